@@ -0,0 +1,53 @@
+package user.dockerfile.ID003
+
+import data.images
+
+__rego_metadata__ := {
+	"id": "ID003",
+	"title": "Disallowed base image registry",
+	"severity": "HIGH",
+	"type": "Dockerfile Custom Check",
+	"description": "Base images must be pulled from the registries allowed by the organization.",
+}
+
+__rego_input__ := {"selector": [{"type": "dockerfile"}]}
+
+deny[res] {
+	from := input.stages[_][_]
+	from.Cmd == "from"
+	image := from.Value[0]
+	image != "scratch"
+	not allowed(normalize(image))
+
+	res := sprintf("Base image '%s' is not from an allowed registry", [image])
+}
+
+allowed(image) {
+	startswith(image, images.allowed_registries[_])
+}
+
+# e.g. alpine:3.16 => docker.io/library/alpine:3.16
+normalize(image) = sprintf("docker.io/library/%s", [image]) {
+	not contains(image, "/")
+}
+
+# e.g. bitnami/nginx => docker.io/bitnami/nginx
+normalize(image) = sprintf("docker.io/%s", [image]) {
+	contains(image, "/")
+	not is_registry(split(image, "/")[0])
+}
+
+normalize(image) = image {
+	contains(image, "/")
+	is_registry(split(image, "/")[0])
+}
+
+is_registry(host) {
+	contains(host, ".")
+}
+
+is_registry(host) {
+	contains(host, ":")
+}
+
+is_registry("localhost")
@@ -0,0 +1,17 @@
+package user.dockerfile.ID003
+
+test_allowed_registry {
+	r := deny with input as {"stages": {"alpine:3.16": [
+		{"Cmd": "from", "Value": ["alpine:3.16"]},
+	]}} with data.images.allowed_registries as ["docker.io/library/"]
+
+	count(r) == 0
+}
+
+test_disallowed_registry {
+	r := deny with input as {"stages": {"quay.io/example/base:1.0": [
+		{"Cmd": "from", "Value": ["quay.io/example/base:1.0"]},
+	]}} with data.images.allowed_registries as ["docker.io/library/"]
+
+	r[_] == "Base image 'quay.io/example/base:1.0' is not from an allowed registry"
+}
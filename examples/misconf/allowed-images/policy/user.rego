@@ -0,0 +1,28 @@
+package user.dockerfile.ID004
+
+import data.images
+
+__rego_metadata__ := {
+	"id": "ID004",
+	"title": "Unapproved user",
+	"severity": "HIGH",
+	"type": "Dockerfile Custom Check",
+	"description": "Containers must run as one of the users approved by the organization.",
+}
+
+__rego_input__ := {"selector": [{"type": "dockerfile"}]}
+
+deny[res] {
+	user := input.stages[_][_]
+	user.Cmd == "user"
+
+	# e.g. USER app:app
+	name := split(user.Value[0], ":")[0]
+	not approved(name)
+
+	res := sprintf("User '%s' is not approved", [name])
+}
+
+approved(name) {
+	images.approved_users[_] == name
+}
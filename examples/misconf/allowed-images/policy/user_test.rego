@@ -0,0 +1,19 @@
+package user.dockerfile.ID004
+
+test_approved_user {
+	r := deny with input as {"stages": {"alpine:3.16": [
+		{"Cmd": "from", "Value": ["alpine:3.16"]},
+		{"Cmd": "user", "Value": ["app:app"]},
+	]}} with data.images.approved_users as ["app"]
+
+	count(r) == 0
+}
+
+test_unapproved_user {
+	r := deny with input as {"stages": {"alpine:3.16": [
+		{"Cmd": "from", "Value": ["alpine:3.16"]},
+		{"Cmd": "user", "Value": ["guest"]},
+	]}} with data.images.approved_users as ["app"]
+
+	r[_] == "User 'guest' is not approved"
+}
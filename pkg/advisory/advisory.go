@@ -0,0 +1,126 @@
+package advisory
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/xerrors"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+// SourceID is the data source of vulnerabilities detected with advisory feeds
+const SourceID dbTypes.SourceID = "advisory-feed"
+
+// Feed is a set of advisories in OSV format maintained by users, e.g. for internal packages
+type Feed struct {
+	// advisories are indexed by ecosystem and normalized package name
+	advisories      map[dbTypes.Ecosystem]map[string][]dbTypes.Advisory
+	vulnerabilities map[string]dbTypes.Vulnerability
+}
+
+// Load loads OSV JSON files under the directory.
+// Entries of unsupported ecosystems are skipped.
+func Load(dir, name string) (*Feed, error) {
+	feed := &Feed{
+		advisories:      map[dbTypes.Ecosystem]map[string][]dbTypes.Advisory{},
+		vulnerabilities: map[string]dbTypes.Vulnerability{},
+	}
+	source := &dbTypes.DataSource{
+		ID:   SourceID,
+		Name: name,
+	}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		} else if d.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return xerrors.Errorf("file read error: %w", err)
+		}
+		var entry osv
+		if err = json.Unmarshal(b, &entry); err != nil {
+			return xerrors.Errorf("OSV decode error (%s): %w", path, err)
+		} else if entry.ID == "" {
+			return xerrors.Errorf("OSV entry without id: %s", path)
+		}
+
+		feed.vulnerabilities[entry.ID] = entry.vulnerability(SourceID)
+		for _, affected := range entry.Affected {
+			ecosystem, ok := ecosystems[affected.Package.Ecosystem]
+			if !ok {
+				log.Logger.Debugf("Unsupported ecosystem in the advisory feed: %s (%s)", affected.Package.Ecosystem, entry.ID)
+				continue
+			}
+			if feed.advisories[ecosystem] == nil {
+				feed.advisories[ecosystem] = map[string][]dbTypes.Advisory{}
+			}
+			pkgName := vulnerability.NormalizePkgName(ecosystem, affected.Package.Name)
+			feed.advisories[ecosystem][pkgName] = append(feed.advisories[ecosystem][pkgName], dbTypes.Advisory{
+				VulnerabilityID:    entry.ID,
+				VulnerableVersions: affected.vulnerableVersions(),
+				DataSource:         source,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("advisory feed error (%s): %w", dir, err)
+	}
+	return feed, nil
+}
+
+var (
+	mu    sync.RWMutex
+	feeds []*Feed
+)
+
+// Register registers the feeds used in detection in addition to the vulnerability DB
+func Register(f ...*Feed) {
+	mu.Lock()
+	defer mu.Unlock()
+	feeds = append(feeds, f...)
+}
+
+// Reset deregisters all the feeds
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	feeds = nil
+}
+
+// Advisories returns the advisories of the package in the registered feeds
+func Advisories(ecosystem dbTypes.Ecosystem, pkgName string) []dbTypes.Advisory {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	pkgName = vulnerability.NormalizePkgName(ecosystem, pkgName)
+	var advisories []dbTypes.Advisory
+	for _, f := range feeds {
+		advisories = append(advisories, f.advisories[ecosystem][pkgName]...)
+	}
+	return advisories
+}
+
+// Vulnerability returns the vulnerability details in the registered feeds.
+// The feed registered later takes precedence.
+func Vulnerability(vulnID string) (dbTypes.Vulnerability, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for i := len(feeds) - 1; i >= 0; i-- {
+		if vuln, ok := feeds[i].vulnerabilities[vulnID]; ok {
+			return vuln, true
+		}
+	}
+	return dbTypes.Vulnerability{}, false
+}
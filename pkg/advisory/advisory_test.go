@@ -0,0 +1,101 @@
+package advisory_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
+	"github.com/aquasecurity/trivy/pkg/advisory"
+)
+
+func TestLoad(t *testing.T) {
+	feed, err := advisory.Load("testdata/feed", "acme")
+	require.NoError(t, err)
+
+	advisory.Register(feed)
+	defer advisory.Reset()
+
+	source := &dbTypes.DataSource{
+		ID:   advisory.SourceID,
+		Name: "acme",
+	}
+
+	tests := []struct {
+		name      string
+		ecosystem dbTypes.Ecosystem
+		pkgName   string
+		want      []dbTypes.Advisory
+	}{
+		{
+			name:      "multiple ranges",
+			ecosystem: vulnerability.Npm,
+			pkgName:   "@acme/utils",
+			want: []dbTypes.Advisory{
+				{
+					VulnerabilityID:    "INTERNAL-2022-0001",
+					VulnerableVersions: []string{"< 1.2.3", ">= 2.0.0, < 2.0.1"},
+					DataSource:         source,
+				},
+			},
+		},
+		{
+			name:      "last affected, versions and a normalized name",
+			ecosystem: vulnerability.Pip,
+			pkgName:   "acme-cli",
+			want: []dbTypes.Advisory{
+				{
+					VulnerabilityID:    "INTERNAL-2022-0002",
+					VulnerableVersions: []string{">= 1.0, <= 1.4", "= 0.9.1"},
+					DataSource:         source,
+				},
+			},
+		},
+		{
+			name:      "unknown package",
+			ecosystem: vulnerability.Npm,
+			pkgName:   "lodash",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := advisory.Advisories(tt.ecosystem, tt.pkgName)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	got, ok := advisory.Vulnerability("INTERNAL-2022-0001")
+	require.True(t, ok)
+	assert.Equal(t, dbTypes.Vulnerability{
+		Title:       "Prototype pollution in @acme/utils",
+		Description: "merge() of @acme/utils allows prototype pollution.",
+		Severity:    "HIGH",
+		CweIDs:      []string{"CWE-1321"},
+		CVSS: dbTypes.VendorCVSS{
+			advisory.SourceID: {V3Vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:H/A:N"},
+		},
+		References:       []string{"https://security.acme.example/INTERNAL-2022-0001"},
+		PublishedDate:    timePtr(time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)),
+		LastModifiedDate: timePtr(time.Date(2022, 6, 2, 0, 0, 0, 0, time.UTC)),
+	}, got)
+
+	got, ok = advisory.Vulnerability("INTERNAL-2022-0002")
+	require.True(t, ok)
+	assert.Equal(t, "UNKNOWN", got.Severity)
+
+	_, ok = advisory.Vulnerability("CVE-2021-23337")
+	assert.False(t, ok)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	_, err := advisory.Load("testdata/invalid", "invalid")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "OSV decode error")
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
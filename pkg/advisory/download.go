@@ -0,0 +1,138 @@
+package advisory
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/oci"
+)
+
+const (
+	// MediaType is the media type of the layer of advisory feeds, which is a tarball of OSV JSON files
+	MediaType = "application/vnd.aquasec.trivy.advisories.layer.v1.tar+gzip"
+
+	ociScheme = "oci://"
+
+	// Feeds are cached under the cache directory, e.g. ~/.cache/trivy/advisories/ghcr.io/org/advisories/latest
+	feedDir      = "advisories"
+	contentDir   = "content"
+	metadataFile = "metadata.json"
+)
+
+// Option holds the options for loading advisory feeds
+type Option struct {
+	CacheDir string
+
+	// SkipUpdate uses the cached feeds without checking the registries
+	SkipUpdate bool
+
+	Insecure bool
+	Quiet    bool
+}
+
+type feedMetadata struct {
+	Digest       string
+	DownloadedAt time.Time
+}
+
+// LoadFeeds loads the advisory feeds from directories or OCI artifacts, e.g. "oci://ghcr.io/org/advisories:latest".
+// OCI artifacts are downloaded into the cache directory again only if the digests are changed.
+func LoadFeeds(ctx context.Context, refs []string, opt Option) ([]*Feed, error) {
+	var feeds []*Feed
+	for _, r := range refs {
+		dir := r
+		if strings.HasPrefix(r, ociScheme) {
+			ref, err := name.ParseReference(strings.TrimPrefix(r, ociScheme))
+			if err != nil {
+				return nil, xerrors.Errorf("advisory feed reference error (%s): %w", r, err)
+			}
+			if dir, err = download(ctx, ref, opt); err != nil {
+				return nil, xerrors.Errorf("advisory feed error (%s): %w", r, err)
+			}
+		}
+
+		feed, err := Load(dir, r)
+		if err != nil {
+			return nil, err
+		}
+		feeds = append(feeds, feed)
+	}
+	return feeds, nil
+}
+
+func download(ctx context.Context, ref name.Reference, opt Option) (string, error) {
+	identifier := strings.ReplaceAll(ref.Identifier(), ":", "-")
+	cacheDir := filepath.Join(opt.CacheDir, feedDir, ref.Context().RegistryStr(), filepath.FromSlash(ref.Context().RepositoryStr()), identifier)
+	dst := filepath.Join(cacheDir, contentDir)
+
+	meta, cached := readMetadata(cacheDir)
+	if cached && opt.SkipUpdate {
+		log.Logger.Debugf("Using the cached advisory feed: %s", ref)
+		return dst, nil
+	}
+
+	art, err := oci.NewArtifact(ref.String(), MediaType, opt.Quiet, opt.Insecure)
+	if err != nil {
+		if cached {
+			log.Logger.Warnf("Using the cached advisory feed as %s is not available: %s", ref, err)
+			return dst, nil
+		}
+		return "", xerrors.Errorf("OCI artifact error: %w", err)
+	}
+
+	digest, err := art.Digest()
+	if err != nil {
+		return "", xerrors.Errorf("digest error: %w", err)
+	}
+	if cached && meta.Digest == digest {
+		log.Logger.Debugf("The advisory feed is up to date: %s", ref)
+		return dst, nil
+	}
+
+	log.Logger.Infof("Downloading the advisory feed from %s...", ref)
+	if err = os.RemoveAll(dst); err != nil {
+		return "", xerrors.Errorf("unable to remove the cached feed: %w", err)
+	}
+	if err = art.Download(ctx, dst); err != nil {
+		return "", xerrors.Errorf("download error: %w", err)
+	}
+
+	if err = writeMetadata(cacheDir, feedMetadata{
+		Digest:       digest,
+		DownloadedAt: time.Now().UTC(),
+	}); err != nil {
+		return "", xerrors.Errorf("metadata error: %w", err)
+	}
+	return dst, nil
+}
+
+func readMetadata(dir string) (feedMetadata, bool) {
+	b, err := os.ReadFile(filepath.Join(dir, metadataFile))
+	if err != nil {
+		return feedMetadata{}, false
+	}
+	var meta feedMetadata
+	if err = json.Unmarshal(b, &meta); err != nil {
+		return feedMetadata{}, false
+	}
+	return meta, true
+}
+
+func writeMetadata(dir string, meta feedMetadata) error {
+	b, err := json.Marshal(meta)
+	if err != nil {
+		return xerrors.Errorf("json error: %w", err)
+	}
+	if err = os.MkdirAll(dir, 0700); err != nil {
+		return xerrors.Errorf("mkdir error: %w", err)
+	}
+	return os.WriteFile(filepath.Join(dir, metadataFile), b, 0600)
+}
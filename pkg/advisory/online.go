@@ -0,0 +1,303 @@
+package advisory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
+	"github.com/aquasecurity/trivy/pkg/httpclient"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const (
+	// OSVSourceID is the data source of vulnerabilities detected with the OSV API
+	OSVSourceID dbTypes.SourceID = "osv"
+
+	// OSVURL is the endpoint of the OSV API
+	OSVURL = "https://api.osv.dev"
+
+	osvTimeout = 30 * time.Second
+
+	// osvBatchSize is the maximum number of queries in a batch request
+	osvBatchSize = 1000
+)
+
+var osvSource = &dbTypes.DataSource{
+	ID:   OSVSourceID,
+	Name: "OSV.dev",
+	URL:  "https://osv.dev",
+}
+
+type onlineOption func(*OnlineClient)
+
+// WithOSVURL takes the endpoint of the OSV API
+func WithOSVURL(u string) onlineOption {
+	return func(c *OnlineClient) {
+		c.url = u
+	}
+}
+
+// WithInsecure allows insecure server connections
+func WithInsecure(insecure bool) onlineOption {
+	return func(c *OnlineClient) {
+		if insecure {
+			c.client.Transport = httpclient.Transport(true)
+		}
+	}
+}
+
+// OnlineClient queries the OSV API for vulnerabilities of language-specific packages
+type OnlineClient struct {
+	url    string
+	client *http.Client
+
+	mu      sync.Mutex
+	entries map[string]osv
+}
+
+// NewOnlineClient is the factory method for OnlineClient
+func NewOnlineClient(opts ...onlineOption) *OnlineClient {
+	c := &OnlineClient{
+		url:     OSVURL,
+		client:  httpclient.Client(osvTimeout, false),
+		entries: map[string]osv{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+var online *OnlineClient
+
+// EnableOnline makes detection query the OSV API in addition to the vulnerability DB and advisory feeds.
+// nil disables it.
+func EnableOnline(c *OnlineClient) {
+	mu.Lock()
+	defer mu.Unlock()
+	online = c
+}
+
+// Augment adds the vulnerabilities of the packages found by the OSV API to the detected vulnerabilities.
+// Advisories already detected are skipped by their IDs and aliases, e.g. GHSA IDs of CVE-IDs in the DB.
+// It returns the vulnerabilities as they are if the online mode is disabled or the API is not available.
+func Augment(ctx context.Context, ecosystem dbTypes.Ecosystem, pkgs []ftypes.Package, vulns []types.DetectedVulnerability) []types.DetectedVulnerability {
+	mu.RLock()
+	c := online
+	mu.RUnlock()
+	if c == nil {
+		return vulns
+	}
+
+	osvEcosystem, ok := osvEcosystems()[ecosystem]
+	if !ok {
+		return vulns
+	}
+
+	found, err := c.query(ctx, osvEcosystem, pkgs)
+	if err != nil {
+		log.Logger.Warnf("Unable to query the OSV API: %s", err)
+		return vulns
+	}
+
+	detected := map[string][]string{}
+	for _, v := range vulns {
+		key := v.PkgName + "@" + v.InstalledVersion
+		detected[key] = append(detected[key], v.VulnerabilityID)
+	}
+
+	for i, ids := range found {
+		pkg := pkgs[i]
+		for _, id := range ids {
+			entry, err := c.entry(ctx, id)
+			if err != nil {
+				log.Logger.Warnf("Unable to get %s from the OSV API: %s", id, err)
+				continue
+			}
+
+			key := pkg.Name + "@" + pkg.Version
+			if slices.Contains(detected[key], entry.ID) || detectedAlias(entry.Aliases, detected[key]) {
+				continue
+			}
+			detected[key] = append(detected[key], entry.ID)
+
+			vulns = append(vulns, types.DetectedVulnerability{
+				VulnerabilityID:  entry.ID,
+				PkgID:            pkg.ID,
+				PkgName:          pkg.Name,
+				PkgPath:          pkg.FilePath,
+				InstalledVersion: pkg.Version,
+				FixedVersion:     entry.fixedVersions(osvEcosystem, ecosystem, pkg.Name),
+				Layer:            pkg.Layer,
+				DataSource:       osvSource,
+			})
+		}
+	}
+	return vulns
+}
+
+// OnlineVulnerability returns the details of the vulnerability fetched from the OSV API
+func OnlineVulnerability(vulnID string) (dbTypes.Vulnerability, bool) {
+	mu.RLock()
+	c := online
+	mu.RUnlock()
+	if c == nil {
+		return dbTypes.Vulnerability{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[vulnID]
+	if !ok {
+		return dbTypes.Vulnerability{}, false
+	}
+	return entry.vulnerability(OSVSourceID), true
+}
+
+type osvQuery struct {
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	} `json:"package"`
+	Version string `json:"version"`
+}
+
+type osvBatchResponse struct {
+	Results []struct {
+		Vulns []struct {
+			ID string `json:"id"`
+		} `json:"vulns"`
+	} `json:"results"`
+}
+
+// query returns the IDs of vulnerabilities affecting each package
+func (c *OnlineClient) query(ctx context.Context, ecosystem string, pkgs []ftypes.Package) ([][]string, error) {
+	found := make([][]string, len(pkgs))
+	for start := 0; start < len(pkgs); start += osvBatchSize {
+		end := start + osvBatchSize
+		if end > len(pkgs) {
+			end = len(pkgs)
+		}
+
+		var queries []osvQuery
+		for _, pkg := range pkgs[start:end] {
+			var q osvQuery
+			q.Package.Ecosystem = ecosystem
+			q.Package.Name = pkg.Name
+			q.Version = pkg.Version
+			queries = append(queries, q)
+		}
+
+		var res osvBatchResponse
+		if err := c.do(ctx, http.MethodPost, "/v1/querybatch", map[string]interface{}{"queries": queries}, &res); err != nil {
+			return nil, xerrors.Errorf("batch query error: %w", err)
+		} else if len(res.Results) != end-start {
+			return nil, xerrors.Errorf("unexpected number of results: %d", len(res.Results))
+		}
+
+		for i, r := range res.Results {
+			for _, v := range r.Vulns {
+				found[start+i] = append(found[start+i], v.ID)
+			}
+		}
+	}
+	return found, nil
+}
+
+// entry returns the OSV entry, which is fetched only once per scan process
+func (c *OnlineClient) entry(ctx context.Context, id string) (osv, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[id]
+	c.mu.Unlock()
+	if ok {
+		return entry, nil
+	}
+
+	if err := c.do(ctx, http.MethodGet, "/v1/vulns/"+url.PathEscape(id), nil, &entry); err != nil {
+		return osv{}, err
+	}
+
+	c.mu.Lock()
+	c.entries[id] = entry
+	c.mu.Unlock()
+	return entry, nil
+}
+
+func (c *OnlineClient) do(ctx context.Context, method, path string, body, v interface{}) error {
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			return xerrors.Errorf("json encode error: %w", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.url, "/")+path, &buf)
+	if err != nil {
+		return xerrors.Errorf("request error: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("HTTP error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return xerrors.Errorf("unexpected status: %s", resp.Status)
+	}
+	if err = json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return xerrors.Errorf("json decode error: %w", err)
+	}
+	return nil
+}
+
+// osvEcosystems returns OSV ecosystems by ecosystems of the vulnerability DB
+func osvEcosystems() map[dbTypes.Ecosystem]string {
+	m := map[dbTypes.Ecosystem]string{}
+	for k, v := range ecosystems {
+		m[v] = k
+	}
+	return m
+}
+
+// detectedAlias reports whether one of the aliases has been detected
+func detectedAlias(aliases, detected []string) bool {
+	for _, a := range aliases {
+		if slices.Contains(detected, a) {
+			return true
+		}
+	}
+	return false
+}
+
+// fixedVersions returns the fixed versions of the package in the entry
+func (o osv) fixedVersions(osvEcosystem string, ecosystem dbTypes.Ecosystem, pkgName string) string {
+	var fixed []string
+	for _, a := range o.Affected {
+		if a.Package.Ecosystem != osvEcosystem ||
+			vulnerability.NormalizePkgName(ecosystem, a.Package.Name) != vulnerability.NormalizePkgName(ecosystem, pkgName) {
+			continue
+		}
+		for _, r := range a.Ranges {
+			for _, e := range r.Events {
+				if e.Fixed != "" && r.Type != "GIT" {
+					fixed = append(fixed, e.Fixed)
+				}
+			}
+		}
+	}
+	return strings.Join(fixed, ", ")
+}
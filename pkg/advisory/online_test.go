@@ -0,0 +1,165 @@
+package advisory_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
+	"github.com/aquasecurity/trivy/pkg/advisory"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func newOSVServer(t *testing.T) *httptest.Server {
+	entries := map[string]string{
+		"GHSA-35jh-r3h4-6jhm": `{"id": "GHSA-35jh-r3h4-6jhm", "aliases": ["CVE-2021-23337"]}`,
+		"GHSA-p6mc-m468-83gw": `{
+  "id": "GHSA-p6mc-m468-83gw",
+  "summary": "Prototype Pollution in lodash",
+  "affected": [
+    {
+      "package": {"ecosystem": "npm", "name": "lodash"},
+      "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "4.17.19"}]}]
+    },
+    {
+      "package": {"ecosystem": "npm", "name": "lodash.pick"},
+      "ranges": [{"type": "SEMVER", "events": [{"introduced": "4.0.0"}, {"fixed": "4.4.1"}]}]
+    }
+  ],
+  "references": [{"url": "https://github.com/lodash/lodash/issues/4744"}],
+  "database_specific": {"severity": "HIGH"}
+}`,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/querybatch", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Queries []struct {
+				Package struct {
+					Ecosystem string `json:"ecosystem"`
+					Name      string `json:"name"`
+				} `json:"package"`
+				Version string `json:"version"`
+			} `json:"queries"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		var results []interface{}
+		for _, q := range req.Queries {
+			var vulns []interface{}
+			if q.Package.Ecosystem == "npm" && q.Package.Name == "lodash" && q.Version == "4.17.15" {
+				vulns = []interface{}{
+					map[string]string{"id": "GHSA-35jh-r3h4-6jhm"},
+					map[string]string{"id": "GHSA-p6mc-m468-83gw"},
+				}
+			}
+			results = append(results, map[string]interface{}{"vulns": vulns})
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{"results": results}))
+	})
+	mux.HandleFunc("/v1/vulns/", func(w http.ResponseWriter, r *http.Request) {
+		entry, ok := entries[r.URL.Path[len("/v1/vulns/"):]]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(entry))
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestAugment(t *testing.T) {
+	ts := newOSVServer(t)
+	defer ts.Close()
+
+	pkgs := []ftypes.Package{
+		{ID: "lodash@4.17.15", Name: "lodash", Version: "4.17.15", FilePath: "node_modules/lodash/package.json"},
+		{ID: "express@4.18.1", Name: "express", Version: "4.18.1"},
+	}
+	dbVulns := []types.DetectedVulnerability{
+		{
+			VulnerabilityID:  "CVE-2021-23337",
+			PkgID:            "lodash@4.17.15",
+			PkgName:          "lodash",
+			InstalledVersion: "4.17.15",
+			FixedVersion:     "4.17.21",
+		},
+	}
+
+	tests := []struct {
+		name      string
+		url       string
+		ecosystem dbTypes.Ecosystem
+		want      []types.DetectedVulnerability
+	}{
+		{
+			name:      "happy path",
+			url:       ts.URL,
+			ecosystem: vulnerability.Npm,
+			want: append(dbVulns, types.DetectedVulnerability{
+				VulnerabilityID:  "GHSA-p6mc-m468-83gw",
+				PkgID:            "lodash@4.17.15",
+				PkgName:          "lodash",
+				PkgPath:          "node_modules/lodash/package.json",
+				InstalledVersion: "4.17.15",
+				FixedVersion:     "4.17.19",
+				DataSource: &dbTypes.DataSource{
+					ID:   advisory.OSVSourceID,
+					Name: "OSV.dev",
+					URL:  "https://osv.dev",
+				},
+			}),
+		},
+		{
+			name:      "unsupported ecosystem",
+			url:       ts.URL,
+			ecosystem: "vcpkg",
+			want:      dbVulns,
+		},
+		{
+			name:      "API unavailable",
+			url:       ts.URL + "/unknown",
+			ecosystem: vulnerability.Npm,
+			want:      dbVulns,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			advisory.EnableOnline(advisory.NewOnlineClient(advisory.WithOSVURL(tt.url)))
+			defer advisory.EnableOnline(nil)
+
+			vulns := append([]types.DetectedVulnerability{}, dbVulns...)
+			got := advisory.Augment(context.Background(), tt.ecosystem, pkgs, vulns)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestOnlineVulnerability(t *testing.T) {
+	ts := newOSVServer(t)
+	defer ts.Close()
+
+	advisory.EnableOnline(advisory.NewOnlineClient(advisory.WithOSVURL(ts.URL)))
+	defer advisory.EnableOnline(nil)
+
+	// Details are available only after detection
+	_, ok := advisory.OnlineVulnerability("GHSA-p6mc-m468-83gw")
+	assert.False(t, ok)
+
+	advisory.Augment(context.Background(), vulnerability.Npm, []ftypes.Package{{Name: "lodash", Version: "4.17.15"}}, nil)
+
+	got, ok := advisory.OnlineVulnerability("GHSA-p6mc-m468-83gw")
+	require.True(t, ok)
+	assert.Equal(t, dbTypes.Vulnerability{
+		Title:      "Prototype Pollution in lodash",
+		Severity:   "HIGH",
+		References: []string{"https://github.com/lodash/lodash/issues/4744"},
+	}, got)
+}
@@ -0,0 +1,150 @@
+package advisory
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
+)
+
+// osv is the subset of the OSV schema used for detection.
+// cf. https://ossf.github.io/osv-schema/
+type osv struct {
+	ID               string           `json:"id"`
+	Aliases          []string         `json:"aliases"`
+	Summary          string           `json:"summary"`
+	Details          string           `json:"details"`
+	Published        *time.Time       `json:"published"`
+	Modified         *time.Time       `json:"modified"`
+	Severity         []osvSeverity    `json:"severity"`
+	Affected         []osvAffected    `json:"affected"`
+	References       []osvReference   `json:"references"`
+	DatabaseSpecific osvDatabaseExtra `json:"database_specific"`
+}
+
+type osvSeverity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+type osvAffected struct {
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	} `json:"package"`
+	Ranges   []osvRange `json:"ranges"`
+	Versions []string   `json:"versions"`
+}
+
+type osvRange struct {
+	Type   string     `json:"type"`
+	Events []osvEvent `json:"events"`
+}
+
+type osvEvent struct {
+	Introduced   string `json:"introduced"`
+	Fixed        string `json:"fixed"`
+	LastAffected string `json:"last_affected"`
+}
+
+type osvReference struct {
+	URL string `json:"url"`
+}
+
+// osvDatabaseExtra holds the severity and CWE IDs in the GitHub Advisory style
+type osvDatabaseExtra struct {
+	Severity string   `json:"severity"`
+	CWEIDs   []string `json:"cwe_ids"`
+}
+
+// ecosystems maps OSV ecosystems to those of the vulnerability DB
+var ecosystems = map[string]dbTypes.Ecosystem{
+	"npm":         vulnerability.Npm,
+	"PyPI":        vulnerability.Pip,
+	"Maven":       vulnerability.Maven,
+	"Go":          vulnerability.Go,
+	"crates.io":   vulnerability.Cargo,
+	"RubyGems":    vulnerability.RubyGems,
+	"NuGet":       vulnerability.NuGet,
+	"Packagist":   vulnerability.Composer,
+	"ConanCenter": vulnerability.Conan,
+	"Pub":         "pub",
+	"SwiftURL":    "swift",
+	"CocoaPods":   "cocoapods",
+}
+
+// vulnerability converts the OSV entry into the vulnerability details with the CVSS vector of the source
+func (o osv) vulnerability(source dbTypes.SourceID) dbTypes.Vulnerability {
+	vuln := dbTypes.Vulnerability{
+		Title:            o.Summary,
+		Description:      o.Details,
+		Severity:         dbTypes.SeverityUnknown.String(),
+		CweIDs:           o.DatabaseSpecific.CWEIDs,
+		PublishedDate:    o.Published,
+		LastModifiedDate: o.Modified,
+	}
+	if s, err := dbTypes.NewSeverity(strings.ToUpper(o.DatabaseSpecific.Severity)); err == nil {
+		vuln.Severity = s.String()
+	}
+	for _, s := range o.Severity {
+		if s.Type == "CVSS_V3" {
+			vuln.CVSS = dbTypes.VendorCVSS{source: {V3Vector: s.Score}}
+		}
+	}
+	for _, r := range o.References {
+		vuln.References = append(vuln.References, r.URL)
+	}
+	return vuln
+}
+
+// vulnerableVersions converts the affected ranges and versions into version constraints, e.g. ">= 1.0.0, < 1.2.3".
+// Fixed versions are taken from the upper bounds of the constraints in detection.
+func (a osvAffected) vulnerableVersions() []string {
+	var constraints []string
+	for _, r := range a.Ranges {
+		// Git commits cannot be compared with package versions
+		if r.Type != "SEMVER" && r.Type != "ECOSYSTEM" {
+			continue
+		}
+
+		var introduced string
+		var open bool
+		for _, e := range r.Events {
+			switch {
+			case e.Introduced != "":
+				introduced, open = e.Introduced, true
+			case e.Fixed != "" && open:
+				constraints = append(constraints, constraint(introduced, "<", e.Fixed))
+				open = false
+			case e.LastAffected != "" && open:
+				constraints = append(constraints, constraint(introduced, "<=", e.LastAffected))
+				open = false
+			}
+		}
+		if open {
+			constraints = append(constraints, constraint(introduced, "", ""))
+		}
+	}
+
+	for _, v := range a.Versions {
+		constraints = append(constraints, "= "+v)
+	}
+	return constraints
+}
+
+func constraint(introduced, op, upper string) string {
+	var cs []string
+	if introduced != "0" {
+		cs = append(cs, ">= "+introduced)
+	}
+	if upper != "" {
+		cs = append(cs, fmt.Sprintf("%s %s", op, upper))
+	}
+	if len(cs) == 0 {
+		// All versions are affected
+		return ">= 0"
+	}
+	return strings.Join(cs, ", ")
+}
@@ -0,0 +1,102 @@
+package buildpack
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/BurntSushi/toml"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/buildpack"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/purl"
+)
+
+const (
+	version = 1
+
+	// TypeBuildpack parses the build metadata of Cloud Native Buildpacks
+	TypeBuildpack = analyzer.Type("buildpack")
+)
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&metadataAnalyzer{})
+}
+
+// metadataAnalyzer detects dependencies contributed by buildpacks in the bill of materials of layers/config/metadata.toml.
+// Dependencies are reported per buildpack with its layer directory, e.g. layers/paketo-buildpacks_npm-install,
+// so that they are attributed to the buildpack with the image label.
+// Dependencies without a PURL of a supported ecosystem, such as runtimes with "pkg:generic", are skipped.
+type metadataAnalyzer struct{}
+
+func (a metadataAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	var m buildpack.Metadata
+	if _, err := toml.NewDecoder(input.Content).Decode(&m); err != nil {
+		return nil, xerrors.Errorf("unable to decode %s: %w", input.FilePath, err)
+	}
+
+	type appKey struct {
+		appType string
+		dir     string
+	}
+	apps := map[appKey][]types.Package{}
+	for _, entry := range m.BOM {
+		if entry.Metadata.PURL == "" {
+			continue
+		}
+		p, err := purl.FromString(entry.Metadata.PURL)
+		if err != nil {
+			log.Logger.Debugf("Invalid PURL of %s: %s", entry.Name, err)
+			continue
+		}
+		appType := p.AppType()
+		if appType == "" {
+			log.Logger.Debugf("Skipping the unsupported dependency of %s: %s", entry.Buildpack.ID, p.ToString())
+			continue
+		}
+
+		dir := buildpack.LayerDir(entry.Buildpack.ID)
+		pkg := *p.Package()
+		pkg.FilePath = dir
+		key := appKey{appType: appType, dir: dir}
+		apps[key] = append(apps[key], pkg)
+	}
+
+	var keys []appKey
+	for key := range apps {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].dir != keys[j].dir {
+			return keys[i].dir < keys[j].dir
+		}
+		return keys[i].appType < keys[j].appType
+	})
+
+	result := &analyzer.AnalysisResult{}
+	for _, key := range keys {
+		result.Applications = append(result.Applications, types.Application{
+			Type:      key.appType,
+			FilePath:  key.dir,
+			Libraries: apps[key],
+		})
+	}
+	return result, nil
+}
+
+func (a metadataAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	return filepath.ToSlash(filePath) == buildpack.MetadataFile
+}
+
+func (a metadataAnalyzer) Type() analyzer.Type {
+	return TypeBuildpack
+}
+
+func (a metadataAnalyzer) Version() int {
+	return version
+}
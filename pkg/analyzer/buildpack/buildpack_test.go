@@ -0,0 +1,78 @@
+package buildpack
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/types"
+)
+
+func Test_metadataAnalyzer_Analyze(t *testing.T) {
+	tests := []struct {
+		name      string
+		inputFile string
+		want      *analyzer.AnalysisResult
+		wantErr   string
+	}{
+		{
+			name:      "happy path",
+			inputFile: "testdata/metadata.toml",
+			want: &analyzer.AnalysisResult{
+				Applications: []types.Application{
+					{
+						Type:     types.Jar,
+						FilePath: "layers/paketo-buildpacks_bellsoft-liberica",
+						Libraries: []types.Package{
+							{
+								Name:     "org.cloudfoundry:jvmkill",
+								Version:  "1.16.0",
+								FilePath: "layers/paketo-buildpacks_bellsoft-liberica",
+							},
+						},
+					},
+					{
+						Type:     types.NodePkg,
+						FilePath: "layers/paketo-buildpacks_yarn",
+						Libraries: []types.Package{
+							{
+								Name:     "yarn",
+								Version:  "1.22.19",
+								FilePath: "layers/paketo-buildpacks_yarn",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "broken",
+			inputFile: "testdata/broken.toml",
+			wantErr:   "unable to decode",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.inputFile)
+			require.NoError(t, err)
+			defer f.Close()
+
+			a := metadataAnalyzer{}
+			got, err := a.Analyze(context.Background(), analyzer.AnalysisInput{
+				FilePath: "layers/config/metadata.toml",
+				Content:  f,
+			})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package codeowners
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/ownership"
+)
+
+const (
+	version = 1
+
+	// TypeCodeowners is disabled unless "--codeowners" is specified
+	TypeCodeowners = analyzer.Type("codeowners")
+)
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&codeownersAnalyzer{})
+}
+
+// codeownersAnalyzer parses CODEOWNERS in the root of the scanned directory or repository,
+// so that findings are assigned to the owners of their files.
+type codeownersAnalyzer struct{}
+
+func (a codeownersAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	content, err := io.ReadAll(input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("read error: %w", err)
+	}
+
+	rules, err := ownership.Parse(content)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to parse %s: %w", input.FilePath, err)
+	}
+
+	return &analyzer.AnalysisResult{
+		CustomResources: []types.CustomResource{ownership.Resource(input.FilePath, rules)},
+	}, nil
+}
+
+func (a codeownersAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	return slices.Contains(ownership.Locations, filepath.ToSlash(filePath))
+}
+
+func (a codeownersAnalyzer) Type() analyzer.Type {
+	return TypeCodeowners
+}
+
+func (a codeownersAnalyzer) Version() int {
+	return version
+}
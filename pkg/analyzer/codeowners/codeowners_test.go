@@ -0,0 +1,54 @@
+package codeowners
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/ownership"
+)
+
+func TestCodeownersAnalyzer_Analyze(t *testing.T) {
+	a := codeownersAnalyzer{}
+	got, err := a.Analyze(context.Background(), analyzer.AnalysisInput{
+		FilePath: ".github/CODEOWNERS",
+		Content:  strings.NewReader("*  @acme/platform\n/apps/payments/  @acme/payments\n"),
+	})
+	require.NoError(t, err)
+
+	want := &analyzer.AnalysisResult{
+		CustomResources: []types.CustomResource{
+			{
+				Type:     ownership.RulesType,
+				FilePath: ".github/CODEOWNERS",
+				Data: ownership.Rules{
+					{Pattern: "*", Owners: []string{"@acme/platform"}},
+					{Pattern: "/apps/payments/", Owners: []string{"@acme/payments"}},
+				},
+			},
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestCodeownersAnalyzer_Required(t *testing.T) {
+	tests := []struct {
+		filePath string
+		want     bool
+	}{
+		{filePath: "CODEOWNERS", want: true},
+		{filePath: ".github/CODEOWNERS", want: true},
+		{filePath: ".gitlab/CODEOWNERS", want: true},
+		{filePath: "apps/payments/CODEOWNERS", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filePath, func(t *testing.T) {
+			assert.Equal(t, tt.want, codeownersAnalyzer{}.Required(tt.filePath, nil))
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package bicep
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/analyzer/config"
+	"github.com/aquasecurity/trivy/pkg/arm"
+)
+
+const (
+	version     = 1
+	requiredExt = ".bicep"
+)
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&configAnalyzer{})
+}
+
+// configAnalyzer passes Bicep files to the misconfiguration post handler.
+// They are compiled to ARM templates before checks are evaluated.
+type configAnalyzer struct{}
+
+func (a configAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	b, err := io.ReadAll(input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read %s: %w", input.FilePath, err)
+	}
+
+	return &analyzer.AnalysisResult{
+		Files: map[types.HandlerType][]types.File{
+			types.MisconfPostHandler: {
+				{
+					Type:    arm.FileTypeBicep,
+					Path:    input.FilePath,
+					Content: b,
+				},
+			},
+		},
+	}, nil
+}
+
+func (a configAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	return filepath.Ext(filePath) == requiredExt
+}
+
+func (a configAnalyzer) Type() analyzer.Type {
+	return config.TypeBicep
+}
+
+func (a configAnalyzer) Version() int {
+	return version
+}
@@ -0,0 +1,60 @@
+package bicep
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/arm"
+)
+
+func Test_configAnalyzer_Analyze(t *testing.T) {
+	content := "param location string = resourceGroup().location\n"
+	got, err := configAnalyzer{}.Analyze(context.Background(), analyzer.AnalysisInput{
+		FilePath: "infra/main.bicep",
+		Content:  strings.NewReader(content),
+	})
+	require.NoError(t, err)
+
+	want := &analyzer.AnalysisResult{
+		Files: map[types.HandlerType][]types.File{
+			types.MisconfPostHandler: {
+				{
+					Type:    arm.FileTypeBicep,
+					Path:    "infra/main.bicep",
+					Content: []byte(content),
+				},
+			},
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func Test_configAnalyzer_Required(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		want     bool
+	}{
+		{
+			name:     "bicep",
+			filePath: "infra/main.bicep",
+			want:     true,
+		},
+		{
+			name:     "parameters",
+			filePath: "infra/main.bicepparam",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, configAnalyzer{}.Required(tt.filePath, nil))
+		})
+	}
+}
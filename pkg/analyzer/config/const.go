@@ -0,0 +1,10 @@
+package config
+
+import "github.com/aquasecurity/fanal/analyzer"
+
+// Analyzers of config files in addition to those of fanal
+const (
+	// TypeBicep passes Bicep files to the misconfiguration post handler.
+	// It is disabled together with the config analyzers of fanal.
+	TypeBicep = analyzer.Type("bicep")
+)
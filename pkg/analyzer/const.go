@@ -0,0 +1,31 @@
+package analyzer
+
+import (
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/trivy/pkg/analyzer/config"
+	tos "github.com/aquasecurity/trivy/pkg/analyzer/os"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Analyzers implemented in Trivy in addition to those of fanal.
+// They must be disabled together with the corresponding analyzers of fanal.
+var (
+	// TypeOSes has OS package analyzers
+	TypeOSes = []analyzer.Type{tos.TypeBottlerocketInventory, tos.TypePortage, tos.TypeDNFModule, tos.TypeWindowsRegistry}
+
+	// TypeLockfiles has lock file analyzers
+	TypeLockfiles = []analyzer.Type{types.Cocoapods, types.Swift, types.Carthage, types.Pub,
+		types.CondaEnv, types.CondaLock, types.BazelMaven, types.BazelModule, types.Pnpm,
+		types.Gradle, types.GradleCatalog, types.Conan, types.Vcpkg,
+	}
+
+	// TypeIndividualPkgs has analyzers for individual packages
+	TypeIndividualPkgs = []analyzer.Type{types.RustBinary, types.CondaPkg, types.YarnPnP}
+
+	// TypeLanguages has all language analyzers.
+	// Analyzers enabled in all the scanning, such as *.deps.json shipped with applications, are only in this list.
+	TypeLanguages = append(append([]analyzer.Type{types.DotNetCore}, TypeLockfiles...), TypeIndividualPkgs...)
+
+	// TypeConfigFiles has config file analyzers
+	TypeConfigFiles = []analyzer.Type{config.TypeBicep}
+)
@@ -0,0 +1,18 @@
+package analyzer
+
+import (
+	"github.com/aquasecurity/fanal/types"
+)
+
+// ErrorType is the type of custom resources holding the errors of files which failed to be analyzed.
+// They are recorded instead of aborting the scan with --continue-on-error.
+const ErrorType = "trivy-analysis-error"
+
+// ErrorResource returns the custom resource recording the error of the file
+func ErrorResource(filePath string, err error) types.CustomResource {
+	return types.CustomResource{
+		Type:     ErrorType,
+		FilePath: filePath,
+		Data:     err.Error(),
+	}
+}
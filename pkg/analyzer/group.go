@@ -0,0 +1,165 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/sync/semaphore"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	aos "github.com/aquasecurity/fanal/analyzer/os"
+	"github.com/aquasecurity/fanal/types"
+	dio "github.com/aquasecurity/go-dep-parser/pkg/io"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+// fileAnalyzer is the same as the analyzer interface of fanal, which is not exported
+type fileAnalyzer interface {
+	Type() analyzer.Type
+	Version() int
+	Analyze(ctx context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error)
+	Required(filePath string, info os.FileInfo) bool
+}
+
+// analyzers are the analyzers implemented in Trivy, which are analyzed by Group
+var analyzers = map[analyzer.Type]fileAnalyzer{}
+
+// RegisterAnalyzer registers the analyzer implemented in Trivy.
+// It is registered to fanal as well so that it overrides the analyzer of fanal of the same type.
+func RegisterAnalyzer(a fileAnalyzer) {
+	analyzer.RegisterAnalyzer(a)
+	analyzers[a.Type()] = a
+}
+
+// Group analyzes files with the analyzers of Trivy by itself and with the rest of the analyzers of fanal,
+// so that files which the analyzers of Trivy fail to analyze, e.g. corrupt jar files, are not skipped silently.
+// fanal logs the errors of analyzers only at the debug level.
+type Group struct {
+	analyzer.AnalyzerGroup
+	analyzers []fileAnalyzer
+
+	// continueOnError records the errors of analyzers in the result, otherwise they are logged as warnings
+	continueOnError bool
+}
+
+// NewGroup returns the analyzer group with the analyzers of the group except for the disabled ones
+func NewGroup(groupName analyzer.Group, disabled []analyzer.Type, continueOnError bool) Group {
+	if groupName == "" {
+		groupName = analyzer.GroupBuiltin
+	}
+
+	// The analyzers of Trivy are disabled in the group of fanal as they are analyzed by Group
+	fanalDisabled := slices.Clone(disabled)
+	var own []fileAnalyzer
+	for t, a := range analyzers {
+		fanalDisabled = append(fanalDisabled, t)
+		if belongToGroup(groupName, t, disabled, a) {
+			own = append(own, a)
+		}
+	}
+
+	return Group{
+		AnalyzerGroup:   analyzer.NewAnalyzerGroup(groupName, fanalDisabled),
+		analyzers:       own,
+		continueOnError: continueOnError,
+	}
+}
+
+// belongToGroup is the same as that of fanal
+func belongToGroup(groupName analyzer.Group, t analyzer.Type, disabled []analyzer.Type, a fileAnalyzer) bool {
+	if slices.Contains(disabled, t) {
+		return false
+	}
+
+	analyzerGroupName := analyzer.GroupBuiltin
+	if cg, ok := a.(analyzer.CustomGroup); ok {
+		analyzerGroupName = cg.Group()
+	}
+	return analyzerGroupName == groupName
+}
+
+// AnalyzerVersions returns the versions of the analyzers of both Trivy and fanal, used for cache keys
+func (g Group) AnalyzerVersions() map[string]int {
+	versions := g.AnalyzerGroup.AnalyzerVersions()
+	for _, a := range g.analyzers {
+		versions[string(a.Type())] = a.Version()
+	}
+	return versions
+}
+
+// AnalyzeFile analyzes the file with the analyzers of fanal, and then with those of Trivy in the same way as fanal,
+// except for the errors of analyzers. Errors opening the file are returned as they are.
+func (g Group) AnalyzeFile(ctx context.Context, wg *sync.WaitGroup, limit *semaphore.Weighted, result *analyzer.AnalysisResult,
+	dir, filePath string, info os.FileInfo, opener analyzer.Opener, disabled []analyzer.Type, opts analyzer.AnalysisOptions) error {
+	if err := g.AnalyzerGroup.AnalyzeFile(ctx, wg, limit, result, dir, filePath, info, opener, disabled, opts); err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return nil
+	}
+
+	for _, a := range g.analyzers {
+		// Skip disabled analyzers
+		if slices.Contains(disabled, a.Type()) {
+			continue
+		}
+
+		// filepath extracted from tar file doesn't have the prefix "/"
+		if !a.Required(strings.TrimLeft(filePath, "/"), info) {
+			continue
+		}
+		rc, err := opener()
+		if errors.Is(err, fs.ErrPermission) {
+			log.Logger.Debugf("Permission error: %s", filePath)
+			break
+		} else if err != nil {
+			return xerrors.Errorf("unable to open %s: %w", filePath, err)
+		}
+
+		if err = limit.Acquire(ctx, 1); err != nil {
+			return xerrors.Errorf("semaphore acquire: %w", err)
+		}
+		wg.Add(1)
+
+		go func(a fileAnalyzer, rc dio.ReadSeekCloserAt) {
+			defer limit.Release(1)
+			defer wg.Done()
+			defer rc.Close()
+
+			ret, err := a.Analyze(ctx, analyzer.AnalysisInput{
+				Dir:      dir,
+				FilePath: filePath,
+				Info:     info,
+				Content:  rc,
+				Options:  opts,
+			})
+			if err != nil && !xerrors.Is(err, aos.AnalyzeOSError) {
+				g.analysisError(result, a.Type(), filePath, err)
+				return
+			}
+			if ret != nil {
+				result.Merge(ret)
+			}
+		}(a, rc)
+	}
+
+	return nil
+}
+
+func (g Group) analysisError(result *analyzer.AnalysisResult, t analyzer.Type, filePath string, err error) {
+	err = xerrors.Errorf("%s analyzer error: %w", t, err)
+	if !g.continueOnError {
+		log.Logger.Warnf("Unable to analyze %s: %s", filePath, err)
+		return
+	}
+	log.Logger.Warnf("Skipping %s due to the error: %s", filePath, err)
+	result.Merge(&analyzer.AnalysisResult{
+		CustomResources: []types.CustomResource{ErrorResource(filePath, err)},
+	})
+}
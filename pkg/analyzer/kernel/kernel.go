@@ -0,0 +1,61 @@
+package kernel
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/kernel"
+)
+
+const (
+	version = 1
+
+	// TypeKernel is disabled unless "kernel" is specified in "--vuln-type"
+	TypeKernel = analyzer.Type("kernel")
+)
+
+// Each installed kernel has its modules in /lib/modules/<release>,
+// e.g. lib/modules/5.10.0-18-amd64/modules.builtin and usr/lib/modules/5.14.0-70.13.1.el9_0.x86_64/modules.builtin
+var modulesRegexp = regexp.MustCompile(`^(usr/)?lib/modules/([^/]+)/modules\.builtin$`)
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&kernelAnalyzer{})
+}
+
+// kernelAnalyzer detects the releases of installed kernels.
+// The running kernel can't be detected from files, and containers share the kernel of the host.
+type kernelAnalyzer struct{}
+
+func (a kernelAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	m := modulesRegexp.FindStringSubmatch(filepath.ToSlash(input.FilePath))
+	if m == nil {
+		return nil, nil
+	}
+
+	return &analyzer.AnalysisResult{
+		CustomResources: []types.CustomResource{
+			{
+				Type:     kernel.ReleaseType,
+				FilePath: input.FilePath,
+				Data:     m[2],
+			},
+		},
+	}, nil
+}
+
+func (a kernelAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	return modulesRegexp.MatchString(filepath.ToSlash(filePath))
+}
+
+func (a kernelAnalyzer) Type() analyzer.Type {
+	return TypeKernel
+}
+
+func (a kernelAnalyzer) Version() int {
+	return version
+}
@@ -0,0 +1,90 @@
+package kernel
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/kernel"
+)
+
+func TestKernelAnalyzer_Analyze(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		want     *analyzer.AnalysisResult
+	}{
+		{
+			name:     "Debian",
+			filePath: "lib/modules/5.10.0-18-amd64/modules.builtin",
+			want: &analyzer.AnalysisResult{
+				CustomResources: []types.CustomResource{
+					{
+						Type:     kernel.ReleaseType,
+						FilePath: "lib/modules/5.10.0-18-amd64/modules.builtin",
+						Data:     "5.10.0-18-amd64",
+					},
+				},
+			},
+		},
+		{
+			name:     "usr merged",
+			filePath: "usr/lib/modules/5.14.0-70.13.1.el9_0.x86_64/modules.builtin",
+			want: &analyzer.AnalysisResult{
+				CustomResources: []types.CustomResource{
+					{
+						Type:     kernel.ReleaseType,
+						FilePath: "usr/lib/modules/5.14.0-70.13.1.el9_0.x86_64/modules.builtin",
+						Data:     "5.14.0-70.13.1.el9_0.x86_64",
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := kernelAnalyzer{}
+			got, err := a.Analyze(context.Background(), analyzer.AnalysisInput{
+				FilePath: tt.filePath,
+				Content:  strings.NewReader("kernel/fs/ext4/ext4.ko\n"),
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestKernelAnalyzer_Required(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		want     bool
+	}{
+		{
+			name:     "modules",
+			filePath: "lib/modules/5.10.0-18-amd64/modules.builtin",
+			want:     true,
+		},
+		{
+			name:     "other file in modules",
+			filePath: "lib/modules/5.10.0-18-amd64/modules.dep",
+			want:     false,
+		},
+		{
+			name:     "nested",
+			filePath: "app/lib/modules/5.10.0-18-amd64/modules.builtin",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := kernelAnalyzer{}
+			assert.Equal(t, tt.want, a.Required(tt.filePath, nil))
+		})
+	}
+}
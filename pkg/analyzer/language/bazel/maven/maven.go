@@ -0,0 +1,176 @@
+package maven
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/analyzer/language"
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const (
+	version = 1
+
+	fileName = "maven_install.json"
+)
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&mavenInstallAnalyzer{})
+}
+
+// lockfile represents maven_install.json of rules_jvm_external.
+// Version 2 has "artifacts" and "dependencies" at the top level,
+// while the older versions have "dependency_tree".
+type lockfile struct {
+	Artifacts map[string]struct {
+		Version string `json:"version"`
+	} `json:"artifacts"`
+	Dependencies   map[string][]string `json:"dependencies"`
+	DependencyTree struct {
+		Dependencies []struct {
+			Coord        string   `json:"coord"`
+			Dependencies []string `json:"dependencies"`
+		} `json:"dependencies"`
+	} `json:"dependency_tree"`
+}
+
+// mavenInstallAnalyzer parses maven_install.json pinned by rules_jvm_external
+type mavenInstallAnalyzer struct{}
+
+func (a mavenInstallAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	libs, deps, err := parse(input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to parse %s: %w", fileName, err)
+	}
+	return language.ToAnalysisResult(types.BazelMaven, input.FilePath, "", libs, deps), nil
+}
+
+func (a mavenInstallAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	return filepath.Base(filePath) == fileName
+}
+
+func (a mavenInstallAnalyzer) Type() analyzer.Type {
+	return types.BazelMaven
+}
+
+func (a mavenInstallAnalyzer) Version() int {
+	return version
+}
+
+func parse(r io.Reader) ([]godeptypes.Library, []godeptypes.Dependency, error) {
+	var lock lockfile
+	if err := json.NewDecoder(r).Decode(&lock); err != nil {
+		return nil, nil, xerrors.Errorf("json decode error: %w", err)
+	}
+
+	// ID => dependency IDs
+	graph := map[string][]string{}
+	if len(lock.Artifacts) > 0 {
+		parseV2(lock, graph)
+	} else {
+		parseV1(lock, graph)
+	}
+
+	var libs []godeptypes.Library
+	var deps []godeptypes.Dependency
+	for _, id := range lo.Keys(graph) {
+		name, ver, _ := strings.Cut(id, "@")
+		libs = append(libs, godeptypes.Library{
+			ID:      id,
+			Name:    name,
+			Version: ver,
+		})
+		if dependsOn := lo.Uniq(graph[id]); len(dependsOn) > 0 {
+			sort.Strings(dependsOn)
+			deps = append(deps, godeptypes.Dependency{
+				ID:        id,
+				DependsOn: dependsOn,
+			})
+		}
+	}
+	sort.Slice(libs, func(i, j int) bool {
+		return libs[i].ID < libs[j].ID
+	})
+	sort.Slice(deps, func(i, j int) bool {
+		return deps[i].ID < deps[j].ID
+	})
+	return libs, deps, nil
+}
+
+// parseV2 parses maven_install.json of version 2.
+// Keys are "group:artifact" or "group:artifact:packaging:classifier" without versions.
+func parseV2(lock lockfile, graph map[string][]string) {
+	versions := map[string]string{}
+	for key, artifact := range lock.Artifacts {
+		name := artifactName(key)
+		versions[name] = artifact.Version
+		graph[pkgID(name, artifact.Version)] = nil
+	}
+
+	for key, dependencies := range lock.Dependencies {
+		name := artifactName(key)
+		ver, ok := versions[name]
+		if !ok {
+			continue
+		}
+		id := pkgID(name, ver)
+		for _, dep := range dependencies {
+			depName := artifactName(dep)
+			if depVer, ok := versions[depName]; ok && depName != name {
+				graph[id] = append(graph[id], pkgID(depName, depVer))
+			}
+		}
+	}
+}
+
+// parseV1 parses maven_install.json of the older versions.
+// Coordinates are "group:artifact:version" or "group:artifact:packaging:classifier:version".
+func parseV1(lock lockfile, graph map[string][]string) {
+	for _, dep := range lock.DependencyTree.Dependencies {
+		id, ok := coordID(dep.Coord)
+		if !ok {
+			continue
+		}
+		if _, ok = graph[id]; !ok {
+			graph[id] = nil
+		}
+		for _, d := range dep.Dependencies {
+			if depID, ok := coordID(d); ok && depID != id {
+				graph[id] = append(graph[id], depID)
+			}
+		}
+	}
+}
+
+// artifactName returns "group:artifact" of the coordinate without the version
+func artifactName(key string) string {
+	parts := strings.Split(key, ":")
+	if len(parts) < 2 {
+		return key
+	}
+	return strings.Join(parts[:2], ":")
+}
+
+func coordID(coord string) (string, bool) {
+	parts := strings.Split(coord, ":")
+	if len(parts) < 3 {
+		return "", false
+	}
+	return pkgID(artifactName(coord), parts[len(parts)-1]), true
+}
+
+func pkgID(name, version string) string {
+	return fmt.Sprintf("%s@%s", name, version)
+}
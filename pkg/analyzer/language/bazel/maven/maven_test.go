@@ -0,0 +1,90 @@
+package maven
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+)
+
+func Test_parse(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		wantLibs []godeptypes.Library
+		wantDeps []godeptypes.Dependency
+	}{
+		{
+			name: "version 2",
+			file: "testdata/maven_install.json",
+			wantLibs: []godeptypes.Library{
+				{
+					ID:      "com.google.code.findbugs:jsr305@3.0.2",
+					Name:    "com.google.code.findbugs:jsr305",
+					Version: "3.0.2",
+				},
+				{
+					ID:      "com.google.guava:failureaccess@1.0.1",
+					Name:    "com.google.guava:failureaccess",
+					Version: "1.0.1",
+				},
+				{
+					ID:      "com.google.guava:guava@31.0.1-jre",
+					Name:    "com.google.guava:guava",
+					Version: "31.0.1-jre",
+				},
+				{
+					ID:      "io.netty:netty-transport-native-epoll@4.1.72.Final",
+					Name:    "io.netty:netty-transport-native-epoll",
+					Version: "4.1.72.Final",
+				},
+			},
+			wantDeps: []godeptypes.Dependency{
+				{
+					ID: "com.google.guava:guava@31.0.1-jre",
+					DependsOn: []string{
+						"com.google.code.findbugs:jsr305@3.0.2",
+						"com.google.guava:failureaccess@1.0.1",
+					},
+				},
+			},
+		},
+		{
+			name: "version 1",
+			file: "testdata/maven_install_v1.json",
+			wantLibs: []godeptypes.Library{
+				{
+					ID:      "com.google.code.findbugs:jsr305@3.0.2",
+					Name:    "com.google.code.findbugs:jsr305",
+					Version: "3.0.2",
+				},
+				{
+					ID:      "com.google.guava:guava@31.0.1-jre",
+					Name:    "com.google.guava:guava",
+					Version: "31.0.1-jre",
+				},
+			},
+			wantDeps: []godeptypes.Dependency{
+				{
+					ID:        "com.google.guava:guava@31.0.1-jre",
+					DependsOn: []string{"com.google.code.findbugs:jsr305@3.0.2"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.file)
+			require.NoError(t, err)
+			defer f.Close()
+
+			gotLibs, gotDeps, err := parse(f)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLibs, gotLibs)
+			assert.Equal(t, tt.wantDeps, gotDeps)
+		})
+	}
+}
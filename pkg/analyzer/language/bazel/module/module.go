@@ -0,0 +1,134 @@
+package module
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/analyzer/language"
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const (
+	version = 1
+
+	fileName = "MODULE.bazel.lock"
+
+	// The attribute of the repositories generated by the maven extension of rules_jvm_external
+	artifactsAttr = "artifacts"
+)
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&moduleLockAnalyzer{})
+}
+
+type artifact struct {
+	Group    string `json:"group"`
+	Artifact string `json:"artifact"`
+	Version  string `json:"version"`
+}
+
+// moduleLockAnalyzer parses MODULE.bazel.lock of Bzlmod.
+// Bazel modules themselves don't have security advisories,
+// so Maven artifacts declared with the maven extension of rules_jvm_external are reported.
+type moduleLockAnalyzer struct{}
+
+func (a moduleLockAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	libs, err := parse(input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to parse %s: %w", fileName, err)
+	}
+	return language.ToAnalysisResult(types.BazelModule, input.FilePath, "", libs, nil), nil
+}
+
+func (a moduleLockAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	return filepath.Base(filePath) == fileName
+}
+
+func (a moduleLockAnalyzer) Type() analyzer.Type {
+	return types.BazelModule
+}
+
+func (a moduleLockAnalyzer) Version() int {
+	return version
+}
+
+// parse returns Maven artifacts in the lock file.
+// The layout of module extensions differs depending on the lock file version,
+// so it looks for the "artifacts" attribute holding JSON-encoded artifacts anywhere, e.g.
+//
+//	"attributes": {
+//	  "artifacts": [
+//	    "{ \"group\": \"com.google.guava\", \"artifact\": \"guava\", \"version\": \"31.1-jre\" }"
+//	  ]
+//	}
+func parse(r io.Reader) ([]godeptypes.Library, error) {
+	var lock interface{}
+	if err := json.NewDecoder(r).Decode(&lock); err != nil {
+		return nil, xerrors.Errorf("json decode error: %w", err)
+	}
+
+	uniqLibs := map[string]godeptypes.Library{}
+	walk(lock, func(a artifact) {
+		name := fmt.Sprintf("%s:%s", a.Group, a.Artifact)
+		id := fmt.Sprintf("%s@%s", name, a.Version)
+		uniqLibs[id] = godeptypes.Library{
+			ID:      id,
+			Name:    name,
+			Version: a.Version,
+		}
+	})
+
+	var libs []godeptypes.Library
+	for _, lib := range uniqLibs {
+		libs = append(libs, lib)
+	}
+	sort.Slice(libs, func(i, j int) bool {
+		return libs[i].ID < libs[j].ID
+	})
+	return libs, nil
+}
+
+func walk(v interface{}, fn func(artifact)) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for key, child := range val {
+			if list, ok := child.([]interface{}); ok && key == artifactsAttr {
+				decodeArtifacts(list, fn)
+				continue
+			}
+			walk(child, fn)
+		}
+	case []interface{}:
+		for _, child := range val {
+			walk(child, fn)
+		}
+	}
+}
+
+func decodeArtifacts(list []interface{}, fn func(artifact)) {
+	for _, item := range list {
+		s, ok := item.(string)
+		if !ok {
+			continue
+		}
+		var a artifact
+		if err := json.Unmarshal([]byte(s), &a); err != nil {
+			continue
+		}
+		// Artifacts without versions are resolved from BOMs and cannot be identified here
+		if a.Group == "" || a.Artifact == "" || a.Version == "" {
+			continue
+		}
+		fn(a)
+	}
+}
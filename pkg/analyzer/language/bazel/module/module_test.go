@@ -0,0 +1,34 @@
+package module
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+)
+
+func Test_parse(t *testing.T) {
+	f, err := os.Open("testdata/MODULE.bazel.lock")
+	require.NoError(t, err)
+	defer f.Close()
+
+	got, err := parse(f)
+	require.NoError(t, err)
+
+	want := []godeptypes.Library{
+		{
+			ID:      "com.google.guava:guava@31.1-jre",
+			Name:    "com.google.guava:guava",
+			Version: "31.1-jre",
+		},
+		{
+			ID:      "org.apache.logging.log4j:log4j-core@2.14.1",
+			Name:    "org.apache.logging.log4j:log4j-core",
+			Version: "2.14.1",
+		},
+	}
+	assert.Equal(t, want, got)
+}
@@ -0,0 +1,242 @@
+package conan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/analyzer/language"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const (
+	version = 1
+
+	fileName = "conan.lock"
+
+	// The root node of Conan 1.x lock files, i.e. the consumer such as conanfile.txt
+	rootNode = "0"
+
+	buildContext = "build"
+)
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&conanLockAnalyzer{})
+}
+
+// lockfile represents conan.lock of both Conan 1.x and 2.x.
+// Conan 1.x has the dependency graph in "graph_lock", while Conan 2.x has only the lists of references.
+type lockfile struct {
+	GraphLock struct {
+		Nodes map[string]node `json:"nodes"`
+	} `json:"graph_lock"`
+
+	Requires      []string `json:"requires"`
+	BuildRequires []string `json:"build_requires"`
+}
+
+type node struct {
+	Ref           string   `json:"ref"`
+	Requires      []string `json:"requires"`
+	BuildRequires []string `json:"build_requires"`
+	Context       string   `json:"context"`
+}
+
+// conanLockAnalyzer parses conan.lock of Conan, the package manager of C and C++
+type conanLockAnalyzer struct{}
+
+func (a conanLockAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	libs, deps, devIDs, err := parse(input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to parse %s: %w", fileName, err)
+	}
+	return language.ToAnalysisResult(types.Conan, input.FilePath, "", libs, deps, devIDs), nil
+}
+
+func (a conanLockAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	return filepath.Base(filePath) == fileName
+}
+
+func (a conanLockAnalyzer) Type() analyzer.Type {
+	return types.Conan
+}
+
+func (a conanLockAnalyzer) Version() int {
+	return version
+}
+
+// parse returns packages, the dependency graph and the IDs of development dependencies.
+// Build requirements, such as cmake, are tools used only in the build, so they are development dependencies.
+func parse(r io.Reader) ([]godeptypes.Library, []godeptypes.Dependency, []string, error) {
+	var lock lockfile
+	if err := json.NewDecoder(r).Decode(&lock); err != nil {
+		return nil, nil, nil, xerrors.Errorf("json decode error: %w", err)
+	}
+
+	if len(lock.GraphLock.Nodes) > 0 {
+		libs, deps, devIDs := parseV1(lock.GraphLock.Nodes)
+		return libs, deps, devIDs, nil
+	}
+	libs, devIDs := parseV2(lock)
+	return libs, nil, devIDs, nil
+}
+
+// parseV1 parses the graph of Conan 1.x.
+// Requirements of the root node are direct dependencies, and packages reachable only via
+// build requirements or in the build context are development dependencies.
+func parseV1(nodes map[string]node) ([]godeptypes.Library, []godeptypes.Dependency, []string) {
+	ids := map[string]string{}
+	libs := map[string]godeptypes.Library{}
+	for key, n := range nodes {
+		if key == rootNode {
+			continue
+		}
+		name, ver, ok := parseRef(n.Ref)
+		if !ok {
+			continue
+		}
+		id := pkgID(name, ver)
+		ids[key] = id
+		libs[id] = godeptypes.Library{
+			ID:       id,
+			Name:     name,
+			Version:  ver,
+			Indirect: true,
+		}
+	}
+
+	for _, key := range nodes[rootNode].Requires {
+		if id, ok := ids[key]; ok {
+			lib := libs[id]
+			lib.Indirect = false
+			libs[id] = lib
+		}
+	}
+
+	// Packages reachable from the root via "requires" in the host context
+	prodIDs := map[string]struct{}{}
+	var visit func(key string)
+	visit = func(key string) {
+		n := nodes[key]
+		if n.Context == buildContext {
+			return
+		}
+		if id, ok := ids[key]; ok {
+			if _, done := prodIDs[id]; done {
+				return
+			}
+			prodIDs[id] = struct{}{}
+		}
+		for _, child := range n.Requires {
+			visit(child)
+		}
+	}
+	visit(rootNode)
+
+	var deps []godeptypes.Dependency
+	for key, n := range nodes {
+		id, ok := ids[key]
+		if !ok {
+			continue
+		}
+		var dependsOn []string
+		for _, child := range lo.Union(n.Requires, n.BuildRequires) {
+			if childID, ok := ids[child]; ok {
+				dependsOn = append(dependsOn, childID)
+			}
+		}
+		if dependsOn = lo.Uniq(dependsOn); len(dependsOn) > 0 {
+			sort.Strings(dependsOn)
+			deps = append(deps, godeptypes.Dependency{
+				ID:        id,
+				DependsOn: dependsOn,
+			})
+		}
+	}
+
+	var devIDs []string
+	for id := range libs {
+		if _, ok := prodIDs[id]; !ok {
+			devIDs = append(devIDs, id)
+		}
+	}
+
+	result := lo.Values(libs)
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	sort.Slice(deps, func(i, j int) bool {
+		return deps[i].ID < deps[j].ID
+	})
+	sort.Strings(devIDs)
+	return result, deps, devIDs
+}
+
+// parseV2 parses the references of Conan 2.x, which doesn't distinguish direct dependencies.
+func parseV2(lock lockfile) ([]godeptypes.Library, []string) {
+	libs := map[string]godeptypes.Library{}
+	prodIDs := map[string]struct{}{}
+	add := func(ref string, dev bool) {
+		name, ver, ok := parseRef(ref)
+		if !ok {
+			return
+		}
+		id := pkgID(name, ver)
+		libs[id] = godeptypes.Library{
+			ID:      id,
+			Name:    name,
+			Version: ver,
+		}
+		if !dev {
+			prodIDs[id] = struct{}{}
+		}
+	}
+	for _, ref := range lock.Requires {
+		add(ref, false)
+	}
+	for _, ref := range lock.BuildRequires {
+		add(ref, true)
+	}
+
+	var devIDs []string
+	for id := range libs {
+		if _, ok := prodIDs[id]; !ok {
+			devIDs = append(devIDs, id)
+		}
+	}
+
+	result := lo.Values(libs)
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	sort.Strings(devIDs)
+	return result, devIDs
+}
+
+// parseRef returns the name and version of the reference.
+// e.g. "openssl/1.1.1q@user/channel#revision%timestamp" => "openssl", "1.1.1q"
+// References without versions, such as "conanfile.txt", are not packages.
+func parseRef(ref string) (string, string, bool) {
+	ref, _, _ = strings.Cut(ref, "#")
+	ref, _, _ = strings.Cut(ref, "@")
+	name, ver, ok := strings.Cut(ref, "/")
+	if !ok || name == "" || ver == "" {
+		return "", "", false
+	}
+	return name, ver, true
+}
+
+func pkgID(name, version string) string {
+	return fmt.Sprintf("%s@%s", name, version)
+}
@@ -0,0 +1,113 @@
+package conan
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+)
+
+func Test_parse(t *testing.T) {
+	tests := []struct {
+		name       string
+		file       string
+		wantLibs   []godeptypes.Library
+		wantDeps   []godeptypes.Dependency
+		wantDevIDs []string
+		wantErr    string
+	}{
+		{
+			name: "Conan 1.x",
+			file: "testdata/conan.lock",
+			wantLibs: []godeptypes.Library{
+				{
+					ID:      "cmake@3.23.2",
+					Name:    "cmake",
+					Version: "3.23.2",
+					// build requirements of the root are not direct dependencies
+					Indirect: true,
+				},
+				{
+					ID:      "fmt@8.1.1",
+					Name:    "fmt",
+					Version: "8.1.1",
+				},
+				{
+					ID:      "openssl@1.1.1q",
+					Name:    "openssl",
+					Version: "1.1.1q",
+				},
+				{
+					ID:       "zlib@1.2.12",
+					Name:     "zlib",
+					Version:  "1.2.12",
+					Indirect: true,
+				},
+			},
+			wantDeps: []godeptypes.Dependency{
+				{
+					ID:        "cmake@3.23.2",
+					DependsOn: []string{"openssl@1.1.1q"},
+				},
+				{
+					ID:        "openssl@1.1.1q",
+					DependsOn: []string{"zlib@1.2.12"},
+				},
+			},
+			wantDevIDs: []string{"cmake@3.23.2"},
+		},
+		{
+			name: "Conan 2.x",
+			file: "testdata/conan2.lock",
+			wantLibs: []godeptypes.Library{
+				{
+					ID:      "cmake@3.27.4",
+					Name:    "cmake",
+					Version: "3.27.4",
+				},
+				{
+					ID:      "fmt@10.1.1",
+					Name:    "fmt",
+					Version: "10.1.1",
+				},
+				{
+					ID:      "openssl@3.1.2",
+					Name:    "openssl",
+					Version: "3.1.2",
+				},
+				{
+					ID:      "zlib@1.2.13",
+					Name:    "zlib",
+					Version: "1.2.13",
+				},
+			},
+			wantDevIDs: []string{"cmake@3.27.4"},
+		},
+		{
+			name:    "broken",
+			file:    "testdata/broken.lock",
+			wantErr: "json decode error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.file)
+			require.NoError(t, err)
+			defer f.Close()
+
+			gotLibs, gotDeps, gotDevIDs, err := parse(f)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLibs, gotLibs)
+			assert.Equal(t, tt.wantDeps, gotDeps)
+			assert.Equal(t, tt.wantDevIDs, gotDevIDs)
+		})
+	}
+}
@@ -0,0 +1,287 @@
+package vcpkg
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/analyzer/language"
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const (
+	version = 1
+
+	manifestFile = "vcpkg.json"
+
+	// The database of packages installed in manifest mode, e.g. build/vcpkg_installed/vcpkg/status
+	statusFile = "vcpkg_installed/vcpkg/status"
+
+	installedStatus = "install ok installed"
+)
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&vcpkgAnalyzer{})
+}
+
+// manifest represents vcpkg.json
+type manifest struct {
+	// Each dependency is a port name or an object with "name"
+	Dependencies []interface{} `json:"dependencies"`
+	Overrides    []struct {
+		Name          string `json:"name"`
+		Version       string `json:"version"`
+		VersionSemver string `json:"version-semver"`
+		VersionDate   string `json:"version-date"`
+		VersionString string `json:"version-string"`
+	} `json:"overrides"`
+}
+
+// vcpkgAnalyzer parses vcpkg.json and the status database of packages installed by vcpkg.
+// vcpkg.json has only the versions pinned by "overrides" since the others are determined by the baseline,
+// so the status database written by "vcpkg install" is parsed for the installed versions.
+type vcpkgAnalyzer struct{}
+
+func (a vcpkgAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	var libs []godeptypes.Library
+	var deps []godeptypes.Dependency
+	var err error
+	if path.Base(filepath.ToSlash(input.FilePath)) == manifestFile {
+		libs, err = parseManifest(input.Content)
+	} else {
+		libs, deps, err = parseStatus(input.Content)
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("unable to parse %s: %w", input.FilePath, err)
+	}
+	return language.ToAnalysisResult(types.Vcpkg, input.FilePath, "", libs, deps), nil
+}
+
+func (a vcpkgAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	filePath = filepath.ToSlash(filePath)
+	return path.Base(filePath) == manifestFile || filePath == statusFile || strings.HasSuffix(filePath, "/"+statusFile)
+}
+
+func (a vcpkgAnalyzer) Type() analyzer.Type {
+	return types.Vcpkg
+}
+
+func (a vcpkgAnalyzer) Version() int {
+	return version
+}
+
+// parseManifest returns ports pinned by "overrides" of vcpkg.json.
+// Ports in "dependencies" are direct dependencies, and the others are indirect ones.
+func parseManifest(r io.Reader) ([]godeptypes.Library, error) {
+	var m manifest
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return nil, xerrors.Errorf("json decode error: %w", err)
+	}
+
+	direct := map[string]struct{}{}
+	for _, dep := range m.Dependencies {
+		switch d := dep.(type) {
+		case string:
+			direct[d] = struct{}{}
+		case map[string]interface{}:
+			if name, ok := d["name"].(string); ok {
+				direct[name] = struct{}{}
+			}
+		}
+	}
+
+	var libs []godeptypes.Library
+	for _, o := range m.Overrides {
+		ver := firstNonEmpty(o.Version, o.VersionSemver, o.VersionDate, o.VersionString)
+		if o.Name == "" || ver == "" {
+			continue
+		}
+		_, ok := direct[o.Name]
+		libs = append(libs, godeptypes.Library{
+			ID:       pkgID(o.Name, ver),
+			Name:     o.Name,
+			Version:  ver,
+			Indirect: !ok,
+		})
+	}
+	sort.Slice(libs, func(i, j int) bool {
+		return libs[i].ID < libs[j].ID
+	})
+	return libs, nil
+}
+
+// paragraph is an entry of the status database, which is the same format as dpkg.
+// e.g.
+//
+//	Package: curl
+//	Version: 7.84.0
+//	Port-Version: 1
+//	Depends: vcpkg-cmake:x64-linux, zlib
+//	Architecture: x64-linux
+//	Status: install ok installed
+//
+// Features have their own paragraphs with "Feature" and their dependencies.
+type paragraph map[string]string
+
+// parseStatus returns the installed ports with the dependency graph.
+// The same port can be installed for multiple triplets, and it is reported once.
+// Later paragraphs of the same port override the earlier ones as vcpkg appends updates.
+func parseStatus(r io.Reader) ([]godeptypes.Library, []godeptypes.Dependency, error) {
+	paragraphs, err := parseParagraphs(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// package:architecture[:feature] => paragraph
+	latest := map[string]paragraph{}
+	var keys []string
+	for _, p := range paragraphs {
+		key := strings.Join([]string{p["Package"], p["Architecture"], p["Feature"]}, ":")
+		if _, ok := latest[key]; !ok {
+			keys = append(keys, key)
+		}
+		latest[key] = p
+	}
+
+	versions := map[string]string{}
+	for _, key := range keys {
+		p := latest[key]
+		if p["Status"] == installedStatus && p["Feature"] == "" && p["Version"] != "" {
+			versions[p["Package"]] = p["Version"]
+		}
+	}
+
+	graph := map[string][]string{}
+	for _, key := range keys {
+		p := latest[key]
+		ver, ok := versions[p["Package"]]
+		if !ok || p["Status"] != installedStatus {
+			continue
+		}
+		id := pkgID(p["Package"], ver)
+		graph[id] = append(graph[id], lo.FilterMap(splitDepends(p["Depends"]), func(dep string, _ int) (string, bool) {
+			name := portName(dep)
+			depVer, ok := versions[name]
+			return pkgID(name, depVer), ok && name != p["Package"]
+		})...)
+	}
+
+	var libs []godeptypes.Library
+	var deps []godeptypes.Dependency
+	for name, ver := range versions {
+		id := pkgID(name, ver)
+		libs = append(libs, godeptypes.Library{
+			ID:      id,
+			Name:    name,
+			Version: ver,
+		})
+		if dependsOn := lo.Uniq(graph[id]); len(dependsOn) > 0 {
+			sort.Strings(dependsOn)
+			deps = append(deps, godeptypes.Dependency{
+				ID:        id,
+				DependsOn: dependsOn,
+			})
+		}
+	}
+	sort.Slice(libs, func(i, j int) bool {
+		return libs[i].ID < libs[j].ID
+	})
+	sort.Slice(deps, func(i, j int) bool {
+		return deps[i].ID < deps[j].ID
+	})
+	return libs, deps, nil
+}
+
+func parseParagraphs(r io.Reader) ([]paragraph, error) {
+	var paragraphs []paragraph
+	p := paragraph{}
+	var lastKey string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.TrimSpace(line) == "":
+			if len(p) > 0 {
+				paragraphs = append(paragraphs, p)
+				p = paragraph{}
+			}
+		case strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t"):
+			// Continuation of the previous field
+			if lastKey != "" {
+				p[lastKey] += " " + strings.TrimSpace(line)
+			}
+		default:
+			key, value, ok := strings.Cut(line, ":")
+			if !ok {
+				return nil, xerrors.Errorf("invalid line: %s", line)
+			}
+			lastKey = key
+			p[key] = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, xerrors.Errorf("scan error: %w", err)
+	}
+	if len(p) > 0 {
+		paragraphs = append(paragraphs, p)
+	}
+	return paragraphs, nil
+}
+
+// splitDepends splits "Depends" by commas outside of features.
+// e.g. "openssl[core,ssl], zlib" => "openssl[core,ssl]", "zlib"
+func splitDepends(depends string) []string {
+	var result []string
+	var depth, start int
+	for i, c := range depends {
+		switch c {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				result = append(result, depends[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(result, depends[start:])
+}
+
+// portName returns the name of the port in "Depends".
+// e.g. "vcpkg-cmake:x64-linux" and "openssl[core,ssl]" => "vcpkg-cmake" and "openssl"
+func portName(dep string) string {
+	dep = strings.TrimSpace(dep)
+	if i := strings.IndexAny(dep, ":["); i != -1 {
+		dep = dep[:i]
+	}
+	return dep
+}
+
+func firstNonEmpty(ss ...string) string {
+	for _, s := range ss {
+		if s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+func pkgID(name, version string) string {
+	return fmt.Sprintf("%s@%s", name, version)
+}
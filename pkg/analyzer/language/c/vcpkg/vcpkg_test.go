@@ -0,0 +1,178 @@
+package vcpkg
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+)
+
+func Test_parseManifest(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		want    []godeptypes.Library
+		wantErr string
+	}{
+		{
+			name: "happy path",
+			file: "testdata/vcpkg.json",
+			want: []godeptypes.Library{
+				{
+					ID:      "curl@7.84.0",
+					Name:    "curl",
+					Version: "7.84.0",
+				},
+				{
+					ID:      "fmt@8.1.1",
+					Name:    "fmt",
+					Version: "8.1.1",
+				},
+				{
+					ID:       "zlib@1.2.12",
+					Name:     "zlib",
+					Version:  "1.2.12",
+					Indirect: true,
+				},
+			},
+		},
+		{
+			name:    "broken",
+			file:    "testdata/broken-status",
+			wantErr: "json decode error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.file)
+			require.NoError(t, err)
+			defer f.Close()
+
+			got, err := parseManifest(f)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_parseStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		wantLibs []godeptypes.Library
+		wantDeps []godeptypes.Dependency
+		wantErr  string
+	}{
+		{
+			name: "happy path",
+			file: "testdata/status",
+			wantLibs: []godeptypes.Library{
+				{
+					ID:      "curl@7.84.0",
+					Name:    "curl",
+					Version: "7.84.0",
+				},
+				{
+					ID:      "openssl@3.0.5",
+					Name:    "openssl",
+					Version: "3.0.5",
+				},
+				{
+					ID:      "vcpkg-cmake@2022-08-18",
+					Name:    "vcpkg-cmake",
+					Version: "2022-08-18",
+				},
+				{
+					ID:      "zlib@1.2.12",
+					Name:    "zlib",
+					Version: "1.2.12",
+				},
+			},
+			wantDeps: []godeptypes.Dependency{
+				{
+					// "openssl" comes from the "ssl" feature
+					ID:        "curl@7.84.0",
+					DependsOn: []string{"openssl@3.0.5", "vcpkg-cmake@2022-08-18", "zlib@1.2.12"},
+				},
+				{
+					ID:        "openssl@3.0.5",
+					DependsOn: []string{"vcpkg-cmake@2022-08-18"},
+				},
+				{
+					ID:        "zlib@1.2.12",
+					DependsOn: []string{"vcpkg-cmake@2022-08-18"},
+				},
+			},
+		},
+		{
+			name:    "broken",
+			file:    "testdata/broken-status",
+			wantErr: "invalid line",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.file)
+			require.NoError(t, err)
+			defer f.Close()
+
+			gotLibs, gotDeps, err := parseStatus(f)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLibs, gotLibs)
+			assert.Equal(t, tt.wantDeps, gotDeps)
+		})
+	}
+}
+
+func Test_vcpkgAnalyzer_Required(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		want     bool
+	}{
+		{
+			name:     "manifest",
+			filePath: "app/vcpkg.json",
+			want:     true,
+		},
+		{
+			name:     "status",
+			filePath: "app/build/vcpkg_installed/vcpkg/status",
+			want:     true,
+		},
+		{
+			name:     "status in the root",
+			filePath: "vcpkg_installed/vcpkg/status",
+			want:     true,
+		},
+		{
+			name:     "lock file",
+			filePath: "app/vcpkg-lock.json",
+			want:     false,
+		},
+		{
+			name:     "dpkg status",
+			filePath: "var/lib/dpkg/status",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := vcpkgAnalyzer{}
+			assert.Equal(t, tt.want, a.Required(tt.filePath, nil))
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/analyzer/language"
+	ftypes "github.com/aquasecurity/fanal/types"
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const version = 1
+
+var requiredFiles = []string{"environment.yml", "environment.yaml"}
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&environmentAnalyzer{})
+}
+
+type environment struct {
+	// Each dependency is a conda match spec or a map with "pip" and a list of pip requirements
+	Dependencies []interface{} `yaml:"dependencies"`
+}
+
+// environmentAnalyzer parses conda environment files.
+// Packages installed by pip in the environment are reported as pip packages.
+type environmentAnalyzer struct{}
+
+func (a environmentAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	condaLibs, pipLibs, err := parse(input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to parse %s: %w", input.FilePath, err)
+	}
+
+	result := &analyzer.AnalysisResult{}
+	if res := language.ToAnalysisResult(types.CondaEnv, input.FilePath, "", condaLibs, nil); res != nil {
+		result.Applications = append(result.Applications, res.Applications...)
+	}
+	if res := language.ToAnalysisResult(ftypes.Pip, input.FilePath, "", pipLibs, nil); res != nil {
+		result.Applications = append(result.Applications, res.Applications...)
+	}
+	if len(result.Applications) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
+
+func (a environmentAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	return slices.Contains(requiredFiles, filepath.Base(filePath))
+}
+
+func (a environmentAnalyzer) Type() analyzer.Type {
+	return types.CondaEnv
+}
+
+func (a environmentAnalyzer) Version() int {
+	return version
+}
+
+// parse returns conda packages and pip packages pinned in the environment file.
+// Dependencies without exact versions are skipped since the installed versions cannot be identified.
+// e.g.
+//
+//	dependencies:
+//	  - python=3.9.7
+//	  - conda-forge::numpy=1.21.2=py39h20f2e39_0
+//	  - pip:
+//	    - requests==2.26.0
+func parse(r io.Reader) ([]godeptypes.Library, []godeptypes.Library, error) {
+	var env environment
+	if err := yaml.NewDecoder(r).Decode(&env); err != nil {
+		return nil, nil, xerrors.Errorf("yaml decode error: %w", err)
+	}
+
+	var condaLibs, pipLibs []godeptypes.Library
+	for _, dep := range env.Dependencies {
+		switch d := dep.(type) {
+		case string:
+			name, ver := parseMatchSpec(d)
+			if ver == "" {
+				log.Logger.Debugf("Skip %q without the exact version", d)
+				continue
+			}
+			condaLibs = append(condaLibs, library(name, ver))
+		case map[string]interface{}:
+			reqs, ok := d["pip"].([]interface{})
+			if !ok {
+				continue
+			}
+			for _, req := range reqs {
+				s, ok := req.(string)
+				if !ok {
+					continue
+				}
+				name, ver := parsePipRequirement(s)
+				if ver == "" {
+					log.Logger.Debugf("Skip %q without the exact version", s)
+					continue
+				}
+				pipLibs = append(pipLibs, library(name, ver))
+			}
+		}
+	}
+	return condaLibs, pipLibs, nil
+}
+
+// parseMatchSpec returns the name and the exact version in the conda match spec.
+// The version is empty if it is not pinned.
+// e.g. "numpy=1.21.2", "numpy==1.21.2", "conda-forge::numpy=1.21.2=py39h20f2e39_0" and "numpy 1.21.2 py39h20f2e39_0"
+func parseMatchSpec(spec string) (string, string) {
+	if _, s, ok := strings.Cut(spec, "::"); ok {
+		spec = s
+	}
+	spec = strings.TrimSpace(spec)
+
+	i := strings.IndexAny(spec, "=<>! ")
+	if i == -1 {
+		return spec, ""
+	}
+	name, constraint := spec[:i], strings.TrimSpace(spec[i:])
+	constraint = strings.TrimPrefix(constraint, "==")
+	constraint = strings.TrimPrefix(constraint, "=")
+
+	// Drop the build string
+	fields := strings.FieldsFunc(constraint, func(r rune) bool {
+		return r == '=' || r == ' '
+	})
+	if len(fields) == 0 || strings.ContainsAny(fields[0], "<>!*,|") {
+		return name, ""
+	}
+	return name, fields[0]
+}
+
+// parsePipRequirement returns the name and the version of the pinned pip requirement.
+// e.g. "requests==2.26.0" and "requests[security]==2.26.0; python_version >= '3.6'"
+func parsePipRequirement(req string) (string, string) {
+	req, _, _ = strings.Cut(req, ";")
+	name, ver, ok := strings.Cut(req, "==")
+	if !ok {
+		return "", ""
+	}
+	name, _, _ = strings.Cut(name, "[")
+	return strings.TrimSpace(name), strings.TrimSpace(ver)
+}
+
+func library(name, ver string) godeptypes.Library {
+	return godeptypes.Library{
+		ID:      fmt.Sprintf("%s@%s", name, ver),
+		Name:    name,
+		Version: ver,
+	}
+}
@@ -0,0 +1,52 @@
+package environment
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+)
+
+func Test_parse(t *testing.T) {
+	f, err := os.Open("testdata/environment.yml")
+	require.NoError(t, err)
+	defer f.Close()
+
+	gotConda, gotPip, err := parse(f)
+	require.NoError(t, err)
+
+	wantConda := []godeptypes.Library{
+		{
+			ID:      "python@3.9.7",
+			Name:    "python",
+			Version: "3.9.7",
+		},
+		{
+			ID:      "numpy@1.21.2",
+			Name:    "numpy",
+			Version: "1.21.2",
+		},
+		{
+			ID:      "pandas@1.3.3",
+			Name:    "pandas",
+			Version: "1.3.3",
+		},
+		{
+			ID:      "openssl@1.1.1l",
+			Name:    "openssl",
+			Version: "1.1.1l",
+		},
+	}
+	wantPip := []godeptypes.Library{
+		{
+			ID:      "requests@2.26.0",
+			Name:    "requests",
+			Version: "2.26.0",
+		},
+	}
+	assert.Equal(t, wantConda, gotConda)
+	assert.Equal(t, wantPip, gotPip)
+}
@@ -0,0 +1,164 @@
+package lock
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/analyzer/language"
+	ftypes "github.com/aquasecurity/fanal/types"
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const (
+	version = 1
+
+	fileName = "conda-lock.yml"
+
+	managerConda = "conda"
+	managerPip   = "pip"
+)
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&condaLockAnalyzer{})
+}
+
+// lockfile represents the unified lock file of conda-lock
+// ref. https://conda.github.io/conda-lock/output/#unified-lockfile
+type lockfile struct {
+	Packages []struct {
+		Name         string            `yaml:"name"`
+		Version      string            `yaml:"version"`
+		Manager      string            `yaml:"manager"`
+		Platform     string            `yaml:"platform"`
+		Dependencies map[string]string `yaml:"dependencies"`
+	} `yaml:"package"`
+}
+
+// condaLockAnalyzer parses conda-lock.yml.
+// Packages installed by pip are reported as pip packages.
+type condaLockAnalyzer struct{}
+
+func (a condaLockAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	pkgs, err := parse(input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to parse %s: %w", fileName, err)
+	}
+
+	result := &analyzer.AnalysisResult{}
+	for _, m := range []struct {
+		manager string
+		appType string
+	}{
+		{managerConda, types.CondaLock},
+		{managerPip, ftypes.Pip},
+	} {
+		p := pkgs[m.manager]
+		if res := language.ToAnalysisResult(m.appType, input.FilePath, "", p.libs, p.deps); res != nil {
+			result.Applications = append(result.Applications, res.Applications...)
+		}
+	}
+	if len(result.Applications) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
+
+func (a condaLockAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	return filepath.Base(filePath) == fileName
+}
+
+func (a condaLockAnalyzer) Type() analyzer.Type {
+	return types.CondaLock
+}
+
+func (a condaLockAnalyzer) Version() int {
+	return version
+}
+
+type packages struct {
+	libs []godeptypes.Library
+	deps []godeptypes.Dependency
+}
+
+// parse returns packages and dependencies per package manager.
+// The lock file has packages for each platform, and they are merged.
+// Dependencies which are not locked, such as virtual packages like "__glibc", are ignored.
+func parse(r io.Reader) (map[string]packages, error) {
+	var lock lockfile
+	if err := yaml.NewDecoder(r).Decode(&lock); err != nil {
+		return nil, xerrors.Errorf("yaml decode error: %w", err)
+	}
+
+	// manager/platform/name => version
+	versions := map[string]string{}
+	for _, pkg := range lock.Packages {
+		versions[key(pkg.Manager, pkg.Platform, pkg.Name)] = pkg.Version
+	}
+
+	libs := map[string]map[string]godeptypes.Library{}
+	dependsOn := map[string]map[string][]string{}
+	for _, pkg := range lock.Packages {
+		if libs[pkg.Manager] == nil {
+			libs[pkg.Manager] = map[string]godeptypes.Library{}
+			dependsOn[pkg.Manager] = map[string][]string{}
+		}
+
+		id := pkgID(pkg.Name, pkg.Version)
+		libs[pkg.Manager][id] = godeptypes.Library{
+			ID:      id,
+			Name:    pkg.Name,
+			Version: pkg.Version,
+		}
+		for depName := range pkg.Dependencies {
+			ver, ok := versions[key(pkg.Manager, pkg.Platform, depName)]
+			if !ok {
+				continue
+			}
+			dependsOn[pkg.Manager][id] = append(dependsOn[pkg.Manager][id], pkgID(depName, ver))
+		}
+	}
+
+	result := map[string]packages{}
+	for manager, m := range libs {
+		var p packages
+		for _, lib := range m {
+			p.libs = append(p.libs, lib)
+		}
+		sort.Slice(p.libs, func(i, j int) bool {
+			return p.libs[i].ID < p.libs[j].ID
+		})
+
+		for id, deps := range dependsOn[manager] {
+			sort.Strings(deps)
+			p.deps = append(p.deps, godeptypes.Dependency{
+				ID:        id,
+				DependsOn: lo.Uniq(deps),
+			})
+		}
+		sort.Slice(p.deps, func(i, j int) bool {
+			return p.deps[i].ID < p.deps[j].ID
+		})
+		result[manager] = p
+	}
+	return result, nil
+}
+
+func key(manager, platform, name string) string {
+	return strings.Join([]string{manager, platform, name}, "/")
+}
+
+func pkgID(name, version string) string {
+	return fmt.Sprintf("%s@%s", name, version)
+}
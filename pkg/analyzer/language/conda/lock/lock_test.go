@@ -0,0 +1,64 @@
+package lock
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+)
+
+func Test_parse(t *testing.T) {
+	f, err := os.Open("testdata/conda-lock.yml")
+	require.NoError(t, err)
+	defer f.Close()
+
+	got, err := parse(f)
+	require.NoError(t, err)
+
+	want := map[string]packages{
+		managerConda: {
+			libs: []godeptypes.Library{
+				{
+					ID:      "openssl@1.1.1l",
+					Name:    "openssl",
+					Version: "1.1.1l",
+				},
+				{
+					ID:      "python@3.9.7",
+					Name:    "python",
+					Version: "3.9.7",
+				},
+			},
+			deps: []godeptypes.Dependency{
+				{
+					ID:        "python@3.9.7",
+					DependsOn: []string{"openssl@1.1.1l"},
+				},
+			},
+		},
+		managerPip: {
+			libs: []godeptypes.Library{
+				{
+					ID:      "requests@2.26.0",
+					Name:    "requests",
+					Version: "2.26.0",
+				},
+				{
+					ID:      "urllib3@1.26.7",
+					Name:    "urllib3",
+					Version: "1.26.7",
+				},
+			},
+			deps: []godeptypes.Dependency{
+				{
+					ID:        "requests@2.26.0",
+					DependsOn: []string{"urllib3@1.26.7"},
+				},
+			},
+		},
+	}
+	assert.Equal(t, want, got)
+}
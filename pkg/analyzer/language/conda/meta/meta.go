@@ -0,0 +1,70 @@
+package meta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/analyzer/language"
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const (
+	version = 1
+
+	metaDir = "conda-meta"
+)
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&condaMetaAnalyzer{})
+}
+
+type packageJSON struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	License string `json:"license"`
+}
+
+// condaMetaAnalyzer detects packages installed in conda environments.
+// conda records each installed package as a JSON file in "conda-meta" of the environment,
+// e.g. /opt/conda/conda-meta/numpy-1.21.2-py39h20f2e39_0.json
+type condaMetaAnalyzer struct{}
+
+func (a condaMetaAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	var pkg packageJSON
+	if err := json.NewDecoder(input.Content).Decode(&pkg); err != nil {
+		return nil, xerrors.Errorf("unable to decode %s: %w", input.FilePath, err)
+	}
+	if pkg.Name == "" || pkg.Version == "" {
+		return nil, nil
+	}
+
+	libs := []godeptypes.Library{
+		{
+			ID:      fmt.Sprintf("%s@%s", pkg.Name, pkg.Version),
+			Name:    pkg.Name,
+			Version: pkg.Version,
+			License: pkg.License,
+		},
+	}
+	return language.ToAnalysisResult(types.CondaPkg, input.FilePath, input.FilePath, libs, nil), nil
+}
+
+func (a condaMetaAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	return filepath.Base(filepath.Dir(filePath)) == metaDir && filepath.Ext(filePath) == ".json"
+}
+
+func (a condaMetaAnalyzer) Type() analyzer.Type {
+	return types.CondaPkg
+}
+
+func (a condaMetaAnalyzer) Version() int {
+	return version
+}
@@ -0,0 +1,73 @@
+package meta
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func Test_condaMetaAnalyzer_Analyze(t *testing.T) {
+	filePath := "testdata/numpy-1.21.2-py39h20f2e39_0.json"
+	f, err := os.Open(filePath)
+	require.NoError(t, err)
+	defer f.Close()
+
+	a := condaMetaAnalyzer{}
+	got, err := a.Analyze(context.Background(), analyzer.AnalysisInput{
+		FilePath: filePath,
+		Content:  f,
+	})
+	require.NoError(t, err)
+
+	want := &analyzer.AnalysisResult{
+		Applications: []ftypes.Application{
+			{
+				Type:     types.CondaPkg,
+				FilePath: filePath,
+				Libraries: []ftypes.Package{
+					{
+						ID:       "numpy@1.21.2",
+						Name:     "numpy",
+						Version:  "1.21.2",
+						License:  "BSD-3-Clause",
+						FilePath: filePath,
+					},
+				},
+			},
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func Test_condaMetaAnalyzer_Required(t *testing.T) {
+	tests := []struct {
+		filePath string
+		want     bool
+	}{
+		{
+			filePath: "opt/conda/conda-meta/numpy-1.21.2-py39h20f2e39_0.json",
+			want:     true,
+		},
+		{
+			filePath: "opt/conda/conda-meta/history",
+			want:     false,
+		},
+		{
+			filePath: "opt/conda/pkgs/numpy-1.21.2-py39h20f2e39_0/info/index.json",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filePath, func(t *testing.T) {
+			a := condaMetaAnalyzer{}
+			assert.Equal(t, tt.want, a.Required(tt.filePath, nil))
+		})
+	}
+}
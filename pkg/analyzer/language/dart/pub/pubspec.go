@@ -0,0 +1,104 @@
+package pub
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/analyzer/language"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const (
+	version = 2
+
+	fileName = "pubspec.lock"
+
+	transitiveDep = "transitive"
+	directDevDep  = "direct dev"
+)
+
+// Sources of packages which are not published on pub.dev, such as the Flutter SDK and local packages
+var skippedSources = map[string]struct{}{
+	"sdk":  {},
+	"path": {},
+}
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&pubSpecLockAnalyzer{})
+}
+
+type lockfile struct {
+	Packages map[string]struct {
+		Dependency string `yaml:"dependency"`
+		Source     string `yaml:"source"`
+		Version    string `yaml:"version"`
+	} `yaml:"packages"`
+}
+
+// pubSpecLockAnalyzer parses pubspec.lock of Dart and Flutter
+type pubSpecLockAnalyzer struct{}
+
+func (a pubSpecLockAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	libs, devIDs, err := parse(input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to parse %s: %w", fileName, err)
+	}
+	return language.ToAnalysisResult(types.Pub, input.FilePath, "", libs, nil, devIDs), nil
+}
+
+func (a pubSpecLockAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	return filepath.Base(filePath) == fileName
+}
+
+func (a pubSpecLockAnalyzer) Type() analyzer.Type {
+	return types.Pub
+}
+
+func (a pubSpecLockAnalyzer) Version() int {
+	return version
+}
+
+// parse parses pubspec.lock.
+// "direct main", "direct dev" and "direct overridden" packages are regarded as direct dependencies.
+// "direct dev" packages are returned as development dependencies.
+// pubspec.lock doesn't have the dependency graph, so transitive dependencies of them cannot be distinguished.
+func parse(r io.Reader) ([]godeptypes.Library, []string, error) {
+	var lock lockfile
+	if err := yaml.NewDecoder(r).Decode(&lock); err != nil {
+		return nil, nil, xerrors.Errorf("yaml decode error: %w", err)
+	}
+
+	var libs []godeptypes.Library
+	var devIDs []string
+	for name, pkg := range lock.Packages {
+		if _, ok := skippedSources[pkg.Source]; ok {
+			continue
+		}
+		id := fmt.Sprintf("%s@%s", name, pkg.Version)
+		if pkg.Dependency == directDevDep {
+			devIDs = append(devIDs, id)
+		}
+		libs = append(libs, godeptypes.Library{
+			ID:       id,
+			Name:     name,
+			Version:  pkg.Version,
+			Indirect: pkg.Dependency == transitiveDep,
+		})
+	}
+
+	sort.Slice(libs, func(i, j int) bool {
+		return libs[i].ID < libs[j].ID
+	})
+	sort.Strings(devIDs)
+	return libs, devIDs, nil
+}
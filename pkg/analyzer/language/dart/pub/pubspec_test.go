@@ -0,0 +1,41 @@
+package pub
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+)
+
+func Test_parse(t *testing.T) {
+	f, err := os.Open("testdata/pubspec.lock")
+	require.NoError(t, err)
+	defer f.Close()
+
+	got, gotDevIDs, err := parse(f)
+	require.NoError(t, err)
+
+	want := []godeptypes.Library{
+		{
+			ID:       "async@2.8.2",
+			Name:     "async",
+			Version:  "2.8.2",
+			Indirect: true,
+		},
+		{
+			ID:      "http@0.13.4",
+			Name:    "http",
+			Version: "0.13.4",
+		},
+		{
+			ID:      "lints@2.0.0",
+			Name:    "lints",
+			Version: "2.0.0",
+		},
+	}
+	assert.Equal(t, want, got)
+	assert.Equal(t, []string{"lints@2.0.0"}, gotDevIDs)
+}
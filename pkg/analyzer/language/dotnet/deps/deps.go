@@ -0,0 +1,146 @@
+package deps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/analyzer/language"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const (
+	version = 1
+
+	depsFileSuffix = ".deps.json"
+
+	typePackage = "package"
+	typeProject = "project"
+)
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&depsAnalyzer{})
+}
+
+type depsFile struct {
+	RuntimeTarget struct {
+		Name string `json:"name"`
+	} `json:"runtimeTarget"`
+	// target framework => "name/version" => target library
+	Targets map[string]map[string]targetLibrary `json:"targets"`
+	// "name/version" => library
+	Libraries map[string]library `json:"libraries"`
+}
+
+type targetLibrary struct {
+	// package name => version
+	Dependencies map[string]string `json:"dependencies"`
+}
+
+type library struct {
+	Type string `json:"type"`
+}
+
+// depsAnalyzer parses *.deps.json generated by "dotnet build" and "dotnet publish".
+// The file is shipped with the application, so packages are detected in images without the project files.
+type depsAnalyzer struct{}
+
+func (a depsAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	libs, deps, err := parse(input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to parse %s: %w", input.FilePath, err)
+	}
+	return language.ToAnalysisResult(types.DotNetCore, input.FilePath, "", libs, deps, nil), nil
+}
+
+func (a depsAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	return strings.HasSuffix(filePath, depsFileSuffix)
+}
+
+func (a depsAnalyzer) Type() analyzer.Type {
+	return types.DotNetCore
+}
+
+func (a depsAnalyzer) Version() int {
+	return version
+}
+
+// parse returns NuGet packages in "libraries" with the dependencies in the runtime target.
+// Projects, i.e. the application itself and referred projects, are not reported,
+// and packages depended on by them are regarded as direct dependencies.
+func parse(r io.Reader) ([]godeptypes.Library, []godeptypes.Dependency, error) {
+	var deps depsFile
+	if err := json.NewDecoder(r).Decode(&deps); err != nil {
+		return nil, nil, xerrors.Errorf("JSON decode error: %w", err)
+	}
+
+	target := deps.Targets[deps.RuntimeTarget.Name]
+	direct := map[string]struct{}{}
+	for nameVer, lib := range deps.Libraries {
+		if lib.Type != typeProject {
+			continue
+		}
+		for name, ver := range target[nameVer].Dependencies {
+			direct[pkgID(name, ver)] = struct{}{}
+		}
+	}
+
+	var libs []godeptypes.Library
+	var graph []godeptypes.Dependency
+	for _, nameVer := range lo.Keys(deps.Libraries) {
+		if deps.Libraries[nameVer].Type != typePackage {
+			continue
+		}
+
+		// e.g. "Newtonsoft.Json/13.0.1"
+		name, ver, ok := strings.Cut(nameVer, "/")
+		if !ok {
+			continue
+		}
+		id := pkgID(name, ver)
+		_, isDirect := direct[id]
+		libs = append(libs, godeptypes.Library{
+			ID:       id,
+			Name:     name,
+			Version:  ver,
+			Indirect: !isDirect,
+		})
+
+		var dependsOn []string
+		for depName, depVer := range target[nameVer].Dependencies {
+			// Dependencies on projects are not reported
+			if deps.Libraries[depName+"/"+depVer].Type != typePackage {
+				continue
+			}
+			dependsOn = append(dependsOn, pkgID(depName, depVer))
+		}
+		if len(dependsOn) > 0 {
+			sort.Strings(dependsOn)
+			graph = append(graph, godeptypes.Dependency{
+				ID:        id,
+				DependsOn: dependsOn,
+			})
+		}
+	}
+	sort.Slice(libs, func(i, j int) bool {
+		return libs[i].ID < libs[j].ID
+	})
+	sort.Slice(graph, func(i, j int) bool {
+		return graph[i].ID < graph[j].ID
+	})
+	return libs, graph, nil
+}
+
+func pkgID(name, version string) string {
+	return fmt.Sprintf("%s@%s", name, version)
+}
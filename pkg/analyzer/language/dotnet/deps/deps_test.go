@@ -0,0 +1,98 @@
+package deps
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+)
+
+func Test_parse(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		wantLibs []godeptypes.Library
+		wantDeps []godeptypes.Dependency
+		wantErr  string
+	}{
+		{
+			name: "happy path",
+			file: "testdata/MyApp.deps.json",
+			wantLibs: []godeptypes.Library{
+				{
+					// Depended on by the referred project
+					ID:      "Newtonsoft.Json@13.0.1",
+					Name:    "Newtonsoft.Json",
+					Version: "13.0.1",
+				},
+				{
+					ID:      "Serilog.Sinks.Console@4.1.0",
+					Name:    "Serilog.Sinks.Console",
+					Version: "4.1.0",
+				},
+				{
+					ID:       "Serilog@2.10.0",
+					Name:     "Serilog",
+					Version:  "2.10.0",
+					Indirect: true,
+				},
+			},
+			wantDeps: []godeptypes.Dependency{
+				{
+					ID:        "Serilog.Sinks.Console@4.1.0",
+					DependsOn: []string{"Serilog@2.10.0"},
+				},
+			},
+		},
+		{
+			name:    "broken",
+			file:    "testdata/broken.deps.json",
+			wantErr: "JSON decode error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.file)
+			require.NoError(t, err)
+			defer f.Close()
+
+			gotLibs, gotDeps, err := parse(f)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLibs, gotLibs)
+			assert.Equal(t, tt.wantDeps, gotDeps)
+		})
+	}
+}
+
+func Test_depsAnalyzer_Required(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		want     bool
+	}{
+		{
+			name:     "happy path",
+			filePath: "app/MyApp.deps.json",
+			want:     true,
+		},
+		{
+			name:     "runtime config",
+			filePath: "app/MyApp.runtimeconfig.json",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := depsAnalyzer{}
+			assert.Equal(t, tt.want, a.Required(tt.filePath, nil))
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package nuget
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/samber/lo"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/go-dep-parser/pkg/nuget/config"
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/analyzer/language"
+
+	// The analyzer of fanal must be registered first so that it is overridden by this one
+	_ "github.com/aquasecurity/fanal/analyzer/language/dotnet/nuget"
+)
+
+const version = 3
+
+const (
+	lockFile   = types.NuGetPkgsLock
+	configFile = types.NuGetPkgsConfig
+
+	typeDirect  = "Direct"
+	typeProject = "Project"
+)
+
+var requiredFiles = []string{lockFile, configFile}
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&nugetLibraryAnalyzer{})
+}
+
+type lockFileContent struct {
+	Version int `json:"version"`
+	// target framework => package name => package
+	Targets map[string]map[string]lockPackage `json:"dependencies"`
+}
+
+type lockPackage struct {
+	Type     string `json:"type"`
+	Resolved string `json:"resolved"`
+	// package name => version range
+	Dependencies map[string]string `json:"dependencies"`
+}
+
+// nugetLibraryAnalyzer parses packages.lock.json and packages.config.
+// Unlike the analyzer of fanal, the dependency graph is built from packages.lock.json.
+type nugetLibraryAnalyzer struct{}
+
+func (a nugetLibraryAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	var libs []godeptypes.Library
+	var deps []godeptypes.Dependency
+	var err error
+	if filepath.Base(input.FilePath) == configFile {
+		libs, deps, err = config.NewParser().Parse(input.Content)
+	} else {
+		libs, deps, err = parseLockFile(input.Content)
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("NuGet analysis error: %w", err)
+	}
+	return language.ToAnalysisResult(types.NuGet, input.FilePath, "", libs, deps, nil), nil
+}
+
+func (a nugetLibraryAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	return slices.Contains(requiredFiles, filepath.Base(filePath))
+}
+
+func (a nugetLibraryAnalyzer) Type() analyzer.Type {
+	return analyzer.TypeNuget
+}
+
+func (a nugetLibraryAnalyzer) Version() int {
+	return version
+}
+
+// parseLockFile parses packages.lock.json.
+// Packages are resolved per target framework, so dependencies are looked up in the same target framework.
+// Projects referred by the project are skipped since they are scanned with their own lock files.
+func parseLockFile(r io.Reader) ([]godeptypes.Library, []godeptypes.Dependency, error) {
+	var lock lockFileContent
+	if err := json.NewDecoder(r).Decode(&lock); err != nil {
+		return nil, nil, xerrors.Errorf("failed to decode %s: %w", lockFile, err)
+	}
+
+	libs := map[string]godeptypes.Library{}
+	graph := map[string][]string{}
+	for _, pkgs := range lock.Targets {
+		for name, pkg := range pkgs {
+			if pkg.Type == typeProject {
+				continue
+			}
+
+			id := pkgID(name, pkg.Resolved)
+			lib, ok := libs[id]
+			libs[id] = godeptypes.Library{
+				ID:      id,
+				Name:    name,
+				Version: pkg.Resolved,
+				// The package can be direct in some target frameworks and transitive in others
+				Indirect: pkg.Type != typeDirect && (!ok || lib.Indirect),
+			}
+
+			for depName := range pkg.Dependencies {
+				dep, ok := pkgs[depName]
+				if !ok || dep.Type == typeProject {
+					continue
+				}
+				graph[id] = append(graph[id], pkgID(depName, dep.Resolved))
+			}
+		}
+	}
+
+	var libraries []godeptypes.Library
+	var deps []godeptypes.Dependency
+	for _, id := range lo.Keys(libs) {
+		libraries = append(libraries, libs[id])
+		if dependsOn := lo.Uniq(graph[id]); len(dependsOn) > 0 {
+			sort.Strings(dependsOn)
+			deps = append(deps, godeptypes.Dependency{
+				ID:        id,
+				DependsOn: dependsOn,
+			})
+		}
+	}
+	sort.Slice(libraries, func(i, j int) bool {
+		return libraries[i].ID < libraries[j].ID
+	})
+	sort.Slice(deps, func(i, j int) bool {
+		return deps[i].ID < deps[j].ID
+	})
+	return libraries, deps, nil
+}
+
+func pkgID(name, version string) string {
+	return fmt.Sprintf("%s@%s", name, version)
+}
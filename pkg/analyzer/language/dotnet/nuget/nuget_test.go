@@ -0,0 +1,107 @@
+package nuget
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/types"
+)
+
+func Test_nugetLibraryAnalyzer_Analyze(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		file     string
+		want     *analyzer.AnalysisResult
+		wantErr  string
+	}{
+		{
+			name:     "packages.lock.json",
+			filePath: "app/packages.lock.json",
+			file:     "testdata/packages.lock.json",
+			want: &analyzer.AnalysisResult{
+				Applications: []types.Application{
+					{
+						Type:     types.NuGet,
+						FilePath: "app/packages.lock.json",
+						Libraries: []types.Package{
+							{
+								ID:      "Newtonsoft.Json@13.0.1",
+								Name:    "Newtonsoft.Json",
+								Version: "13.0.1",
+							},
+							{
+								ID:      "Serilog.Sinks.Console@4.1.0",
+								Name:    "Serilog.Sinks.Console",
+								Version: "4.1.0",
+								DependsOn: []string{
+									"Serilog@2.10.0",
+								},
+							},
+							{
+								// Direct in one of the target frameworks
+								ID:      "Serilog@2.10.0",
+								Name:    "Serilog",
+								Version: "2.10.0",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "packages.config",
+			filePath: "app/packages.config",
+			file:     "testdata/packages.config",
+			want: &analyzer.AnalysisResult{
+				Applications: []types.Application{
+					{
+						Type:     types.NuGet,
+						FilePath: "app/packages.config",
+						Libraries: []types.Package{
+							{
+								Name:    "Microsoft.AspNet.WebApi",
+								Version: "5.2.2",
+							},
+							{
+								Name:    "Newtonsoft.Json",
+								Version: "6.0.4",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "broken",
+			filePath: "packages.lock.json",
+			file:     "testdata/broken.lock.json",
+			wantErr:  "failed to decode",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.file)
+			require.NoError(t, err)
+			defer f.Close()
+
+			a := nugetLibraryAnalyzer{}
+			got, err := a.Analyze(context.Background(), analyzer.AnalysisInput{
+				FilePath: tt.filePath,
+				Content:  f,
+			})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
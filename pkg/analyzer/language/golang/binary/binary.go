@@ -0,0 +1,153 @@
+package binary
+
+import (
+	"context"
+	"debug/buildinfo"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/analyzer/language"
+	"github.com/aquasecurity/fanal/types"
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/log"
+
+	// The analyzer of fanal must be registered first so that it is overridden by this one
+	_ "github.com/aquasecurity/fanal/analyzer/language/golang/binary"
+)
+
+const version = 2
+
+const (
+	// StdlibName is the name of the Go standard library in the vulnerability database
+	StdlibName = "stdlib"
+
+	develVersion = "(devel)"
+)
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&gobinaryLibraryAnalyzer{})
+}
+
+// gobinaryLibraryAnalyzer detects modules embedded in Go binaries.
+// Unlike the analyzer of fanal, it reports the main module and the standard library as well.
+type gobinaryLibraryAnalyzer struct{}
+
+func (a gobinaryLibraryAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	info, err := buildinfo.Read(input.Content)
+	if err != nil {
+		// debug/buildinfo doesn't export the errors
+		if msg := err.Error(); strings.HasSuffix(msg, "unrecognized file format") ||
+			strings.HasSuffix(msg, "not a Go executable") {
+			return nil, nil
+		}
+		return nil, xerrors.Errorf("go binary parse error: %w", err)
+	}
+
+	return language.ToAnalysisResult(types.GoBinary, input.FilePath, "", parseBuildInfo(input.FilePath, info), nil), nil
+}
+
+func (a gobinaryLibraryAnalyzer) Required(_ string, fileInfo os.FileInfo) bool {
+	mode := fileInfo.Mode()
+	if !mode.IsRegular() {
+		return false
+	}
+
+	// Check executable file
+	return mode.Perm()&0111 != 0
+}
+
+func (a gobinaryLibraryAnalyzer) Type() analyzer.Type {
+	return analyzer.TypeGoBinary
+}
+
+func (a gobinaryLibraryAnalyzer) Version() int {
+	return version
+}
+
+// parseBuildInfo returns the main module, the standard library and dependencies in the build info.
+// Dependencies of vendored builds are recorded in the same way, though they don't have checksums.
+func parseBuildInfo(filePath string, info *debug.BuildInfo) []godeptypes.Library {
+	var libs []godeptypes.Library
+
+	// e.g. "go1.18.3", "go1.18.3 X:boringcrypto"
+	// Development versions such as "devel go1.19-a1b2c3d" are not reported.
+	if fields := strings.Fields(info.GoVersion); len(fields) > 0 && strings.HasPrefix(fields[0], "go") {
+		libs = append(libs, godeptypes.Library{
+			Name:    StdlibName,
+			Version: "v" + strings.TrimPrefix(fields[0], "go"),
+		})
+	}
+
+	if info.Main.Path != "" {
+		if ver := mainVersion(info); ver != "" {
+			libs = append(libs, godeptypes.Library{
+				Name:    info.Main.Path,
+				Version: ver,
+			})
+		} else {
+			log.Logger.Debugf("Unable to detect the version of the main module in %s", filePath)
+		}
+	}
+
+	for _, dep := range info.Deps {
+		mod := dep
+		if dep.Replace != nil {
+			// Modules replaced with local directories are not versioned,
+			// and the version of the original module doesn't tell the code actually built.
+			if dep.Replace.Version == "" {
+				log.Logger.Debugf("Skip %s replaced with %s in %s", dep.Path, dep.Replace.Path, filePath)
+				continue
+			}
+			mod = dep.Replace
+		}
+		if mod.Version == "" || mod.Version == develVersion {
+			continue
+		}
+
+		libs = append(libs, godeptypes.Library{
+			Name:    mod.Path,
+			Version: mod.Version,
+		})
+	}
+	return libs
+}
+
+// mainVersion returns the version of the main module.
+// Binaries built by "go install module@version" have the version,
+// while those built in repositories have "(devel)" and VCS information since Go 1.18.
+// The pseudo-version is computed from the VCS information in the latter case.
+func mainVersion(info *debug.BuildInfo) string {
+	if info.Main.Version != "" && info.Main.Version != develVersion {
+		return info.Main.Version
+	}
+
+	var revision, modified string
+	var commitTime time.Time
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			revision = s.Value
+		case "vcs.time":
+			commitTime, _ = time.Parse(time.RFC3339, s.Value)
+		case "vcs.modified":
+			modified = s.Value
+		}
+	}
+	if len(revision) < 12 || commitTime.IsZero() {
+		return ""
+	}
+
+	// e.g. v0.0.0-20220615115521-e411bc995c6d
+	ver := fmt.Sprintf("v0.0.0-%s-%s", commitTime.UTC().Format("20060102150405"), revision[:12])
+	if modified == "true" {
+		ver += "+dirty"
+	}
+	return ver
+}
@@ -0,0 +1,148 @@
+package binary
+
+import (
+	"context"
+	"os"
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+)
+
+func Test_parseBuildInfo(t *testing.T) {
+	tests := []struct {
+		name string
+		info *debug.BuildInfo
+		want []godeptypes.Library
+	}{
+		{
+			name: "go install with replaced modules",
+			info: &debug.BuildInfo{
+				GoVersion: "go1.18.3",
+				Main: debug.Module{
+					Path:    "github.com/aquasecurity/test",
+					Version: "v1.2.3",
+				},
+				Deps: []*debug.Module{
+					{
+						Path:    "github.com/spf13/cobra",
+						Version: "v1.4.0",
+					},
+					{
+						Path:    "github.com/org/original",
+						Version: "v1.0.0",
+						Replace: &debug.Module{
+							Path:    "github.com/org/fork",
+							Version: "v1.0.1",
+						},
+					},
+					{
+						Path:    "github.com/org/local",
+						Version: "v0.1.0",
+						Replace: &debug.Module{
+							Path: "../local",
+						},
+					},
+				},
+			},
+			want: []godeptypes.Library{
+				{
+					Name:    "stdlib",
+					Version: "v1.18.3",
+				},
+				{
+					Name:    "github.com/aquasecurity/test",
+					Version: "v1.2.3",
+				},
+				{
+					Name:    "github.com/spf13/cobra",
+					Version: "v1.4.0",
+				},
+				{
+					Name:    "github.com/org/fork",
+					Version: "v1.0.1",
+				},
+			},
+		},
+		{
+			name: "go build with VCS stamping",
+			info: &debug.BuildInfo{
+				GoVersion: "go1.18.3 X:boringcrypto",
+				Main: debug.Module{
+					Path:    "github.com/aquasecurity/test",
+					Version: "(devel)",
+				},
+				Settings: []debug.BuildSetting{
+					{
+						Key:   "vcs.revision",
+						Value: "e411bc995c6d2d3a9e7e1f5b0c2f3a4d5e6f7a8b",
+					},
+					{
+						Key:   "vcs.time",
+						Value: "2022-06-15T11:55:21Z",
+					},
+					{
+						Key:   "vcs.modified",
+						Value: "true",
+					},
+				},
+			},
+			want: []godeptypes.Library{
+				{
+					Name:    "stdlib",
+					Version: "v1.18.3",
+				},
+				{
+					Name:    "github.com/aquasecurity/test",
+					Version: "v0.0.0-20220615115521-e411bc995c6d+dirty",
+				},
+			},
+		},
+		{
+			name: "go build without VCS stamping",
+			info: &debug.BuildInfo{
+				GoVersion: "devel go1.19-a1b2c3d",
+				Main: debug.Module{
+					Path:    "github.com/aquasecurity/test",
+					Version: "(devel)",
+				},
+				Deps: []*debug.Module{
+					{
+						Path:    "github.com/spf13/cobra",
+						Version: "v1.4.0",
+					},
+				},
+			},
+			want: []godeptypes.Library{
+				{
+					Name:    "github.com/spf13/cobra",
+					Version: "v1.4.0",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseBuildInfo("test", tt.info)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_gobinaryLibraryAnalyzer_Analyze(t *testing.T) {
+	f, err := os.Open("testdata/script.sh")
+	require.NoError(t, err)
+	defer f.Close()
+
+	a := gobinaryLibraryAnalyzer{}
+	got, err := a.Analyze(context.Background(), analyzer.AnalysisInput{
+		FilePath: "testdata/script.sh",
+		Content:  f,
+	})
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
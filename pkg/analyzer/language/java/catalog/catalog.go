@@ -0,0 +1,156 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/samber/lo"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/analyzer/language"
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const (
+	version = 1
+
+	// e.g. gradle/libs.versions.toml
+	fileSuffix = ".versions.toml"
+)
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&versionCatalogAnalyzer{})
+}
+
+// catalog represents a version catalog of Gradle.
+// ref. https://docs.gradle.org/current/userguide/platforms.html#sub:conventional-dependencies-toml
+type catalog struct {
+	// Each value is a version or a rich version, e.g. { strictly = "[3.8, 4.0[", prefer = "3.9" }
+	Versions map[string]interface{} `toml:"versions"`
+
+	// Each value is "group:artifact:version" or a table with "module" or "group" and "name",
+	// and "version" which is a version, a rich version or a reference to [versions] with "ref".
+	Libraries map[string]interface{} `toml:"libraries"`
+}
+
+// versionCatalogAnalyzer parses version catalogs of Gradle, e.g. gradle/libs.versions.toml.
+// They declare the versions requested by the build rather than the resolved ones,
+// so only libraries with exact versions are reported as direct dependencies.
+type versionCatalogAnalyzer struct{}
+
+func (a versionCatalogAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	libs, err := parse(input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to parse %s: %w", input.FilePath, err)
+	}
+	return language.ToAnalysisResult(types.GradleCatalog, input.FilePath, "", libs, nil), nil
+}
+
+func (a versionCatalogAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	return strings.HasSuffix(filepath.Base(filePath), fileSuffix)
+}
+
+func (a versionCatalogAnalyzer) Type() analyzer.Type {
+	return types.GradleCatalog
+}
+
+func (a versionCatalogAnalyzer) Version() int {
+	return version
+}
+
+// parse returns libraries with exact versions.
+// Libraries without versions, e.g. those managed by platforms, and those with version ranges are skipped.
+func parse(r io.Reader) ([]godeptypes.Library, error) {
+	var c catalog
+	if _, err := toml.DecodeReader(r, &c); err != nil {
+		return nil, xerrors.Errorf("toml decode error: %w", err)
+	}
+
+	libs := map[string]godeptypes.Library{}
+	for alias, v := range c.Libraries {
+		var name, ver string
+		switch lib := v.(type) {
+		case string:
+			// e.g. "com.google.guava:guava:31.1-jre"
+			parts := strings.Split(lib, ":")
+			if len(parts) != 3 {
+				continue
+			}
+			name, ver = strings.Join(parts[:2], ":"), exactVersion(parts[2])
+		case map[string]interface{}:
+			name = moduleName(lib)
+			ver = c.version(lib["version"])
+		default:
+			return nil, xerrors.Errorf("invalid library: %s", alias)
+		}
+		if name == "" || ver == "" {
+			continue
+		}
+
+		id := pkgID(name, ver)
+		libs[id] = godeptypes.Library{
+			ID:      id,
+			Name:    name,
+			Version: ver,
+		}
+	}
+
+	result := lo.Values(libs)
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+// moduleName returns "group:artifact" of the library declared with "module" or "group" and "name"
+func moduleName(lib map[string]interface{}) string {
+	if module, ok := lib["module"].(string); ok {
+		return module
+	}
+	group, _ := lib["group"].(string)
+	name, _ := lib["name"].(string)
+	if group == "" || name == "" {
+		return ""
+	}
+	return group + ":" + name
+}
+
+// version returns the exact version of the library, following the reference to [versions].
+// Rich versions are exact if "strictly", "require" or "prefer" is an exact version in this order.
+func (c catalog) version(v interface{}) string {
+	switch ver := v.(type) {
+	case string:
+		return exactVersion(ver)
+	case map[string]interface{}:
+		if ref, ok := ver["ref"].(string); ok {
+			return c.version(c.Versions[ref])
+		}
+		for _, key := range []string{"strictly", "require", "prefer"} {
+			if s, ok := ver[key].(string); ok && exactVersion(s) != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// exactVersion returns the version if it is not a range or a dynamic version, e.g. "[3.8, 4.0[", "1.+" and "latest.release"
+func exactVersion(ver string) string {
+	if ver == "" || strings.ContainsAny(ver, "[](),+") || strings.HasPrefix(ver, "latest.") {
+		return ""
+	}
+	return ver
+}
+
+func pkgID(name, version string) string {
+	return fmt.Sprintf("%s@%s", name, version)
+}
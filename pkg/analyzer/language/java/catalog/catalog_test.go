@@ -0,0 +1,78 @@
+package catalog
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+)
+
+func Test_parse(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		want    []godeptypes.Library
+		wantErr string
+	}{
+		{
+			name: "happy path",
+			file: "testdata/libs.versions.toml",
+			want: []godeptypes.Library{
+				{
+					ID:      "com.google.guava:guava@31.1-jre",
+					Name:    "com.google.guava:guava",
+					Version: "31.1-jre",
+				},
+				{
+					ID:      "junit:junit@4.13.2",
+					Name:    "junit:junit",
+					Version: "4.13.2",
+				},
+				{
+					ID:      "org.apache.commons:commons-lang3@3.9",
+					Name:    "org.apache.commons:commons-lang3",
+					Version: "3.9",
+				},
+				{
+					ID:      "org.codehaus.groovy:groovy-json@3.0.5",
+					Name:    "org.codehaus.groovy:groovy-json",
+					Version: "3.0.5",
+				},
+				{
+					ID:      "org.codehaus.groovy:groovy@3.0.5",
+					Name:    "org.codehaus.groovy:groovy",
+					Version: "3.0.5",
+				},
+				{
+					ID:      "org.springframework:spring-core@5.3.23",
+					Name:    "org.springframework:spring-core",
+					Version: "5.3.23",
+				},
+			},
+		},
+		{
+			name:    "invalid library",
+			file:    "testdata/broken.versions.toml",
+			wantErr: "invalid library: broken",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.file)
+			require.NoError(t, err)
+			defer f.Close()
+
+			got, err := parse(f)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package gradle
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/analyzer/language"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const (
+	version = 1
+
+	fileName = "gradle.lockfile"
+
+	// Configurations without dependencies are listed with this key, e.g. "empty=annotationProcessor"
+	emptyKey = "empty"
+)
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&gradleLockAnalyzer{})
+}
+
+// gradleLockAnalyzer parses gradle.lockfile written by the dependency locking of Gradle 6.8 and later.
+// ref. https://docs.gradle.org/current/userguide/dependency_locking.html
+type gradleLockAnalyzer struct{}
+
+func (a gradleLockAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	libs, devIDs, err := parse(input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to parse %s: %w", fileName, err)
+	}
+	return language.ToAnalysisResult(types.Gradle, input.FilePath, "", libs, nil, devIDs), nil
+}
+
+func (a gradleLockAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	return filepath.Base(filePath) == fileName
+}
+
+func (a gradleLockAnalyzer) Type() analyzer.Type {
+	return types.Gradle
+}
+
+func (a gradleLockAnalyzer) Version() int {
+	return version
+}
+
+// parse returns the locked modules and the IDs of development dependencies.
+// Each line is "group:artifact:version=configurations", and modules locked only for
+// test configurations, e.g. "testRuntimeClasspath", are development dependencies.
+// The lock file doesn't have the dependency graph and doesn't distinguish direct dependencies.
+func parse(r io.Reader) ([]godeptypes.Library, []string, error) {
+	var libs []godeptypes.Library
+	var devIDs []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		coord, configurations, _ := strings.Cut(line, "=")
+		if coord == emptyKey {
+			continue
+		}
+		parts := strings.Split(coord, ":")
+		if len(parts) != 3 {
+			return nil, nil, xerrors.Errorf("invalid dependency: %s", line)
+		}
+
+		name := strings.Join(parts[:2], ":")
+		id := pkgID(name, parts[2])
+		libs = append(libs, godeptypes.Library{
+			ID:      id,
+			Name:    name,
+			Version: parts[2],
+		})
+		if isTestOnly(strings.Split(configurations, ",")) {
+			devIDs = append(devIDs, id)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, xerrors.Errorf("scan error: %w", err)
+	}
+
+	sort.Slice(libs, func(i, j int) bool {
+		return libs[i].ID < libs[j].ID
+	})
+	sort.Strings(devIDs)
+	return libs, devIDs, nil
+}
+
+// isTestOnly returns true if all the configurations are for tests,
+// e.g. "testCompileClasspath", "integrationTestRuntimeClasspath" and "debugAndroidTestRuntimeClasspath".
+func isTestOnly(configurations []string) bool {
+	for _, c := range configurations {
+		c = strings.TrimSpace(c)
+		if !strings.HasPrefix(c, "test") && !strings.Contains(c, "Test") {
+			return false
+		}
+	}
+	return true
+}
+
+func pkgID(name, version string) string {
+	return fmt.Sprintf("%s@%s", name, version)
+}
@@ -0,0 +1,74 @@
+package gradle
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+)
+
+func Test_parse(t *testing.T) {
+	tests := []struct {
+		name       string
+		file       string
+		wantLibs   []godeptypes.Library
+		wantDevIDs []string
+		wantErr    string
+	}{
+		{
+			name: "happy path",
+			file: "testdata/gradle.lockfile",
+			wantLibs: []godeptypes.Library{
+				{
+					ID:      "com.google.code.gson:gson@2.8.9",
+					Name:    "com.google.code.gson:gson",
+					Version: "2.8.9",
+				},
+				{
+					ID:      "com.google.guava:guava@31.0.1-jre",
+					Name:    "com.google.guava:guava",
+					Version: "31.0.1-jre",
+				},
+				{
+					ID:      "junit:junit@4.13.2",
+					Name:    "junit:junit",
+					Version: "4.13.2",
+				},
+				{
+					ID:      "org.hamcrest:hamcrest-core@1.3",
+					Name:    "org.hamcrest:hamcrest-core",
+					Version: "1.3",
+				},
+			},
+			wantDevIDs: []string{
+				"junit:junit@4.13.2",
+				"org.hamcrest:hamcrest-core@1.3",
+			},
+		},
+		{
+			name:    "without version",
+			file:    "testdata/broken.lockfile",
+			wantErr: "invalid dependency",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.file)
+			require.NoError(t, err)
+			defer f.Close()
+
+			gotLibs, gotDevIDs, err := parse(f)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLibs, gotLibs)
+			assert.Equal(t, tt.wantDevIDs, gotDevIDs)
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package jar
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"regexp"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	jacksonGroupPrefix = "com.fasterxml.jackson"
+	nettyGroup         = "io.netty"
+)
+
+var (
+	// e.g. "2.13.3", "4.1.72.Final" and "2.14.0-rc1"
+	versionRegexp = regexp.MustCompile(`^\d+(\.\d+)+([.-][0-9A-Za-z.-]+)?$`)
+
+	// Fingerprints of artifacts which are often bundled in shaded jars.
+	// The paths are matched by suffixes so that relocated packages, e.g. "shaded/com/fasterxml/jackson/...", are also matched.
+	fingerprints = []struct {
+		path    *regexp.Regexp
+		extract func(r io.Reader) ([]properties, error)
+	}{
+		{
+			// e.g. com/fasterxml/jackson/databind/cfg/PackageVersion.class
+			path:    regexp.MustCompile(`(^|/)com/fasterxml/jackson/.+/PackageVersion\.class$`),
+			extract: jacksonPackageVersion,
+		},
+		{
+			// e.g. META-INF/io.netty.versions.properties and META-INF/io.grpc.netty.shaded.io.netty.versions.properties
+			path:    regexp.MustCompile(`(^|/)META-INF/(.+\.)?io\.netty\.versions\.properties$`),
+			extract: nettyVersions,
+		},
+	}
+)
+
+// fingerprint identifies bundled artifacts with their classes and resources.
+// Shaded jars may strip or relocate pom.properties of bundled artifacts,
+// but some artifacts embed their coordinates in the classes or resources.
+func fingerprint(files []*zip.File) ([]properties, error) {
+	var result []properties
+	for _, f := range files {
+		for _, fp := range fingerprints {
+			if !fp.path.MatchString(f.Name) {
+				continue
+			}
+			props, err := extract(f, fp.extract)
+			if err != nil {
+				return nil, xerrors.Errorf("unable to fingerprint %s: %w", f.Name, err)
+			}
+			result = append(result, props...)
+		}
+	}
+	return result, nil
+}
+
+func extract(f *zip.File, fn func(r io.Reader) ([]properties, error)) ([]properties, error) {
+	r, err := f.Open()
+	if err != nil {
+		return nil, xerrors.Errorf("open error: %w", err)
+	}
+	defer r.Close()
+	return fn(r)
+}
+
+// jacksonPackageVersion extracts the coordinates from PackageVersion of Jackson modules,
+// which calls VersionUtil.parseVersion("2.13.3", "com.fasterxml.jackson.core", "jackson-databind").
+func jacksonPackageVersion(r io.Reader) ([]properties, error) {
+	constants, err := classConstants(r)
+	if err != nil {
+		return nil, xerrors.Errorf("class file error: %w", err)
+	}
+
+	var props properties
+	for _, c := range constants {
+		switch {
+		case versionRegexp.MatchString(c):
+			props.version = c
+		case strings.Contains(c, jacksonGroupPrefix) && !strings.Contains(c, "/"):
+			// Relocated string constants have the prefix, e.g. "shaded.com.fasterxml.jackson.core"
+			props.groupID = c[strings.Index(c, jacksonGroupPrefix):]
+		case strings.HasPrefix(c, "jackson-"):
+			props.artifactID = c
+		}
+	}
+	if !props.valid() {
+		return nil, nil
+	}
+	return []properties{props}, nil
+}
+
+// nettyVersions extracts the artifacts from io.netty.versions.properties,
+// which has "<artifactId>.version=<version>" of each Netty artifact.
+func nettyVersions(r io.Reader) ([]properties, error) {
+	var result []properties
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok || strings.HasPrefix(key, "#") || !strings.HasSuffix(key, ".version") {
+			continue
+		}
+		result = append(result, properties{
+			groupID:    nettyGroup,
+			artifactID: strings.TrimSuffix(key, ".version"),
+			version:    value,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, xerrors.Errorf("scan error: %w", err)
+	}
+	return result, nil
+}
+
+// Tags of the constant pool in class files
+// ref. https://docs.oracle.com/javase/specs/jvms/se17/html/jvms-4.html#jvms-4.4
+const (
+	constantUtf8               = 1
+	constantInteger            = 3
+	constantFloat              = 4
+	constantLong               = 5
+	constantDouble             = 6
+	constantClass              = 7
+	constantString             = 8
+	constantFieldref           = 9
+	constantMethodref          = 10
+	constantInterfaceMethodref = 11
+	constantNameAndType        = 12
+	constantMethodHandle       = 15
+	constantMethodType         = 16
+	constantDynamic            = 17
+	constantInvokeDynamic      = 18
+	constantModule             = 19
+	constantPackage            = 20
+
+	classMagic = 0xCAFEBABE
+)
+
+// classConstants returns the UTF-8 constants in the constant pool of the class file
+func classConstants(r io.Reader) ([]string, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, xerrors.Errorf("read error: %w", err)
+	}
+	br := bytes.NewReader(b)
+
+	var header struct {
+		Magic        uint32
+		Minor, Major uint16
+		PoolCount    uint16
+	}
+	if err = binary.Read(br, binary.BigEndian, &header); err != nil {
+		return nil, xerrors.Errorf("header error: %w", err)
+	} else if header.Magic != classMagic {
+		return nil, xerrors.New("invalid magic number")
+	}
+
+	var constants []string
+	for i := 1; i < int(header.PoolCount); i++ {
+		tag, err := br.ReadByte()
+		if err != nil {
+			return nil, xerrors.Errorf("constant pool error: %w", err)
+		}
+
+		var skip int64
+		switch tag {
+		case constantUtf8:
+			var length uint16
+			if err = binary.Read(br, binary.BigEndian, &length); err != nil {
+				return nil, xerrors.Errorf("constant pool error: %w", err)
+			}
+			s := make([]byte, length)
+			if _, err = io.ReadFull(br, s); err != nil {
+				return nil, xerrors.Errorf("constant pool error: %w", err)
+			}
+			constants = append(constants, string(s))
+		case constantClass, constantString, constantMethodType, constantModule, constantPackage:
+			skip = 2
+		case constantMethodHandle:
+			skip = 3
+		case constantInteger, constantFloat, constantFieldref, constantMethodref, constantInterfaceMethodref,
+			constantNameAndType, constantDynamic, constantInvokeDynamic:
+			skip = 4
+		case constantLong, constantDouble:
+			// 8-byte constants take up two entries
+			skip = 8
+			i++
+		default:
+			return nil, xerrors.Errorf("unknown constant pool tag: %d", tag)
+		}
+		if _, err = br.Seek(skip, io.SeekCurrent); err != nil {
+			return nil, xerrors.Errorf("constant pool error: %w", err)
+		}
+	}
+	return constants, nil
+}
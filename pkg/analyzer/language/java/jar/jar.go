@@ -0,0 +1,70 @@
+package jar
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	ftypes "github.com/aquasecurity/fanal/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+
+	// The analyzer of fanal must be registered first so that it is overridden by this one
+	_ "github.com/aquasecurity/fanal/analyzer/language/java/jar"
+)
+
+const version = 2
+
+var requiredExtensions = []string{".jar", ".war", ".ear", ".par"}
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&javaLibraryAnalyzer{})
+}
+
+// javaLibraryAnalyzer analyzes jar/war/ear/par files.
+// Unlike the analyzer of fanal, artifacts bundled in shaded and uber jars are identified with
+// class fingerprints as well as pom.properties, and each artifact is attributed to the innermost jar containing it.
+type javaLibraryAnalyzer struct{}
+
+func (a javaLibraryAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	p := newParser(input.Options.Offline)
+	pkgs, err := p.parseArtifact(input.FilePath, input.Info.Size(), input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("jar/war/ear/par parse error: %w", err)
+	}
+	if len(pkgs) == 0 {
+		return nil, nil
+	}
+	sortPackages(pkgs)
+
+	return &analyzer.AnalysisResult{
+		Applications: []ftypes.Application{
+			{
+				Type:      ftypes.Jar,
+				FilePath:  input.FilePath,
+				Libraries: pkgs,
+			},
+		},
+	}, nil
+}
+
+func (a javaLibraryAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	ext := filepath.Ext(filePath)
+	for _, required := range requiredExtensions {
+		if strings.EqualFold(ext, required) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a javaLibraryAnalyzer) Type() analyzer.Type {
+	return analyzer.TypeJar
+}
+
+func (a javaLibraryAnalyzer) Version() int {
+	return version
+}
@@ -0,0 +1,138 @@
+package jar
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	ftypes "github.com/aquasecurity/fanal/types"
+)
+
+func Test_javaLibraryAnalyzer_Analyze(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want []ftypes.Package
+	}{
+		{
+			name: "shaded jar",
+			file: "testdata/shaded-app-1.0.0.jar",
+			want: []ftypes.Package{
+				{
+					Name:     "com.example:shaded-app",
+					Version:  "1.0.0",
+					FilePath: "testdata/shaded-app-1.0.0.jar",
+				},
+				{
+					Name:     "com.fasterxml.jackson.core:jackson-databind",
+					Version:  "2.13.3",
+					FilePath: "testdata/shaded-app-1.0.0.jar",
+				},
+				{
+					Name:     "com.google.guava:guava",
+					Version:  "29.0-jre",
+					FilePath: "testdata/shaded-app-1.0.0.jar",
+				},
+				{
+					Name:     "io.netty:netty-buffer",
+					Version:  "4.1.72.Final",
+					FilePath: "testdata/shaded-app-1.0.0.jar",
+				},
+				{
+					Name:     "io.netty:netty-codec",
+					Version:  "4.1.72.Final",
+					FilePath: "testdata/shaded-app-1.0.0.jar",
+				},
+			},
+		},
+		{
+			name: "uber jar",
+			file: "testdata/app-1.0.0.jar",
+			want: []ftypes.Package{
+				{
+					Name:     "com.example:app",
+					Version:  "1.0.0",
+					FilePath: "testdata/app-1.0.0.jar",
+				},
+				{
+					Name:     "com.fasterxml.jackson.core:jackson-databind",
+					Version:  "2.13.3",
+					FilePath: "testdata/app-1.0.0.jar/BOOT-INF/lib/jackson-databind-2.13.3.jar",
+				},
+				{
+					Name:     "org.apache.logging.log4j:log4j-core",
+					Version:  "2.14.1",
+					FilePath: "testdata/app-1.0.0.jar/BOOT-INF/lib/log4j-core-2.14.1.jar",
+				},
+			},
+		},
+		{
+			name: "unknown jar",
+			file: "testdata/unknown.jar",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.file)
+			require.NoError(t, err)
+			defer f.Close()
+
+			fi, err := f.Stat()
+			require.NoError(t, err)
+
+			a := javaLibraryAnalyzer{}
+			got, err := a.Analyze(context.Background(), analyzer.AnalysisInput{
+				FilePath: tt.file,
+				Info:     fi,
+				Content:  f,
+				Options:  analyzer.AnalysisOptions{Offline: true},
+			})
+			require.NoError(t, err)
+
+			if tt.want == nil {
+				assert.Nil(t, got)
+				return
+			}
+			require.Len(t, got.Applications, 1)
+			assert.Equal(t, ftypes.Jar, got.Applications[0].Type)
+			assert.Equal(t, tt.want, got.Applications[0].Libraries)
+		})
+	}
+}
+
+func Test_parser_searchBySHA1(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, `1:"25ca63beec6d4da3bd13e4ca08a973496cbbb5c0"`, r.URL.Query().Get("q"))
+		res := apiResponse{}
+		res.Response.NumFound = 1
+		res.Response.Docs = []apiDoc{
+			{
+				ID:         "com.example:unknown:1.2.3",
+				GroupID:    "com.example",
+				ArtifactID: "unknown",
+				Version:    "1.2.3",
+			},
+		}
+		_ = json.NewEncoder(w).Encode(res)
+	}))
+	defer ts.Close()
+
+	f, err := os.Open("testdata/unknown.jar")
+	require.NoError(t, err)
+	defer f.Close()
+
+	p := parser{
+		baseURL:    ts.URL,
+		httpClient: ts.Client(),
+	}
+	got, err := p.searchBySHA1(f)
+	require.NoError(t, err)
+	assert.Equal(t, properties{groupID: "com.example", artifactID: "unknown", version: "1.2.3"}, got)
+}
@@ -0,0 +1,128 @@
+package jar
+
+import (
+	"crypto/sha1" // nolint: gosec
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	baseURL         = "https://search.maven.org/solrsearch/select"
+	idQuery         = `g:"%s" AND a:"%s"`
+	artifactIDQuery = `a:"%s" AND p:"jar"`
+	sha1Query       = `1:"%s"`
+)
+
+var errArtifactNotFound = xerrors.New("no artifact found")
+
+type apiResponse struct {
+	Response struct {
+		NumFound int      `json:"numFound"`
+		Docs     []apiDoc `json:"docs"`
+	} `json:"response"`
+}
+
+type apiDoc struct {
+	ID           string `json:"id"`
+	GroupID      string `json:"g"`
+	ArtifactID   string `json:"a"`
+	Version      string `json:"v"`
+	P            string `json:"p"`
+	VersionCount int    `json:"versionCount"`
+}
+
+// search calls the search API of Maven Central
+func (p parser) search(query string, rows int) (apiResponse, error) {
+	req, err := http.NewRequest(http.MethodGet, p.baseURL, nil)
+	if err != nil {
+		return apiResponse{}, xerrors.Errorf("unable to initialize HTTP client: %w", err)
+	}
+
+	q := req.URL.Query()
+	q.Set("q", query)
+	q.Set("rows", fmt.Sprint(rows))
+	q.Set("wt", "json")
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return apiResponse{}, xerrors.Errorf("http error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return apiResponse{}, xerrors.Errorf("status %s from %s", resp.Status, req.URL.String())
+	}
+
+	var res apiResponse
+	if err = json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return apiResponse{}, xerrors.Errorf("json decode error: %w", err)
+	}
+	return res, nil
+}
+
+func (p parser) exists(props properties) (bool, error) {
+	res, err := p.search(fmt.Sprintf(idQuery, props.groupID, props.artifactID), 1)
+	if err != nil {
+		return false, err
+	}
+	return res.Response.NumFound > 0, nil
+}
+
+func (p parser) searchBySHA1(r io.ReadSeeker) (properties, error) {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return properties{}, xerrors.Errorf("file seek error: %w", err)
+	}
+
+	h := sha1.New() // nolint: gosec
+	if _, err := io.Copy(h, r); err != nil {
+		return properties{}, xerrors.Errorf("unable to calculate SHA-1: %w", err)
+	}
+	digest := hex.EncodeToString(h.Sum(nil))
+
+	res, err := p.search(fmt.Sprintf(sha1Query, digest), 1)
+	if err != nil {
+		return properties{}, xerrors.Errorf("sha1 search error: %w", err)
+	}
+	if len(res.Response.Docs) == 0 {
+		return properties{}, xerrors.Errorf("digest %s: %w", digest, errArtifactNotFound)
+	}
+
+	// Some artifacts might have the same SHA-1 digests.
+	// e.g. "javax.servlet:jstl" and "jstl:jstl"
+	docs := res.Response.Docs
+	sort.Slice(docs, func(i, j int) bool {
+		return docs[i].ID < docs[j].ID
+	})
+	d := docs[0]
+
+	return properties{
+		groupID:    d.GroupID,
+		artifactID: d.ArtifactID,
+		version:    d.Version,
+	}, nil
+}
+
+func (p parser) searchByArtifactID(artifactID string) (string, error) {
+	res, err := p.search(fmt.Sprintf(artifactIDQuery, artifactID), 20)
+	if err != nil {
+		return "", xerrors.Errorf("artifactID search error: %w", err)
+	}
+	if len(res.Response.Docs) == 0 {
+		return "", xerrors.Errorf("artifactID %s: %w", artifactID, errArtifactNotFound)
+	}
+
+	// Some artifacts might have the same artifactId.
+	// e.g. "javax.servlet:jstl" and "jstl:jstl"
+	docs := res.Response.Docs
+	sort.Slice(docs, func(i, j int) bool {
+		return docs[i].VersionCount > docs[j].VersionCount
+	})
+	return docs[0].GroupID, nil
+}
@@ -0,0 +1,399 @@
+package jar
+
+import (
+	"archive/zip"
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+	"golang.org/x/xerrors"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	dio "github.com/aquasecurity/go-dep-parser/pkg/io"
+	"github.com/aquasecurity/trivy/pkg/httpclient"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+const (
+	pomProperties = "pom.properties"
+	manifestFile  = "MANIFEST.MF"
+)
+
+// e.g. spring-core-5.3.4-SNAPSHOT.jar => spring-core, 5.3.4-SNAPSHOT
+var jarFileRegEx = regexp.MustCompile(`^([a-zA-Z0-9\._-]*[^-*])-(\d\S*(?:-SNAPSHOT)?).jar$`)
+
+type parser struct {
+	baseURL    string
+	httpClient *http.Client
+	offline    bool
+}
+
+func newParser(offline bool) parser {
+	// for HTTP retry
+	retryClient := retryablehttp.NewClient()
+	retryClient.Logger = nil
+	retryClient.HTTPClient.Transport = httpclient.Transport(false)
+	retryClient.RetryWaitMin = 20 * time.Second
+	retryClient.RetryWaitMax = 5 * time.Minute
+	retryClient.RetryMax = 5
+
+	// attempt to read the maven central api url from os environment, if it's
+	// not set use the default
+	mavenURL, ok := os.LookupEnv("MAVEN_CENTRAL_URL")
+	if !ok {
+		mavenURL = baseURL
+	}
+
+	return parser{
+		baseURL:    mavenURL,
+		httpClient: retryClient.StandardClient(),
+		offline:    offline,
+	}
+}
+
+// parseArtifact returns the artifacts in the jar/war/ear/par file.
+// Artifacts bundled in the file are identified in the following order,
+// and each artifact has the path of the innermost file containing it, e.g. "app.jar/BOOT-INF/lib/guava-31.1-jre.jar".
+//  1. Nested jar/war/ear files, which are parsed recursively
+//  2. pom.properties kept by the shade and assembly plugins
+//  3. Class fingerprints, for bundled artifacts whose pom.properties are stripped or relocated
+//
+// The file itself is identified with pom.properties, MANIFEST.MF, the SHA-1 digest and the file name as fanal does.
+func (p parser) parseArtifact(filePath string, size int64, r dio.ReadSeekerAt) ([]ftypes.Package, error) {
+	log.Logger.Debugf("Parsing Java artifacts: %s", filePath)
+
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, xerrors.Errorf("zip error: %w", err)
+	}
+
+	// Try to extract artifactId and version from the file name
+	fileProps := parseFileName(path.Base(filepath.ToSlash(filePath)))
+
+	var pkgs, bundled []ftypes.Package
+	var m manifest
+	var foundPomProps bool
+	for _, fileInJar := range zr.File {
+		switch {
+		case path.Base(fileInJar.Name) == pomProperties:
+			props, err := parsePomProperties(fileInJar)
+			if err != nil {
+				return nil, xerrors.Errorf("failed to parse %s: %w", fileInJar.Name, err)
+			}
+			if !props.valid() {
+				continue
+			}
+			bundled = append(bundled, props.pkg(filePath))
+
+			// Check if the pom.properties is for the original JAR/WAR/EAR
+			if fileProps.artifactID == props.artifactID && fileProps.version == props.version {
+				foundPomProps = true
+			}
+		case path.Base(fileInJar.Name) == manifestFile:
+			m, err = parseManifest(fileInJar)
+			if err != nil {
+				return nil, xerrors.Errorf("failed to parse %s: %w", manifestFile, err)
+			}
+		case isArtifact(fileInJar.Name):
+			innerPkgs, err := p.parseInnerJar(filePath, fileInJar)
+			if err != nil {
+				return nil, xerrors.Errorf("failed to parse %s: %w", fileInJar.Name, err)
+			}
+			pkgs = append(pkgs, innerPkgs...)
+		}
+	}
+
+	fingerprinted, err := fingerprint(zr.File)
+	if err != nil {
+		return nil, xerrors.Errorf("fingerprint error: %w", err)
+	}
+	for _, props := range fingerprinted {
+		// pom.properties takes precedence over fingerprints
+		if !containsArtifact(bundled, props) {
+			bundled = append(bundled, props.pkg(filePath))
+		}
+	}
+	pkgs = append(pkgs, bundled...)
+
+	// If pom.properties is found, it should be preferred than MANIFEST.MF.
+	if foundPomProps {
+		return pkgs, nil
+	}
+
+	props, err := p.identify(filePath, r, m, fileProps)
+	if err != nil {
+		return nil, err
+	} else if props.valid() && !containsArtifact(bundled, props) {
+		pkgs = append(pkgs, props.pkg(filePath))
+	}
+	return pkgs, nil
+}
+
+// identify returns the properties of the artifact itself if it has no pom.properties
+func (p parser) identify(filePath string, r io.ReadSeeker, m manifest, fileProps properties) (properties, error) {
+	manifestProps := m.properties()
+	if p.offline {
+		// In offline mode, we will not check if the artifact information is correct.
+		if !manifestProps.valid() {
+			log.Logger.Debugf("Unable to identify POM in offline mode: %s", filePath)
+		}
+		return manifestProps, nil
+	}
+
+	if manifestProps.valid() {
+		// Even if MANIFEST.MF is found, the groupId and artifactId might not be valid.
+		// We have to make sure that the artifact exists actually.
+		if ok, _ := p.exists(manifestProps); ok {
+			return manifestProps, nil
+		}
+	}
+
+	// If groupId and artifactId are not found, call Maven Central's search API with SHA-1 digest.
+	props, err := p.searchBySHA1(r)
+	if err == nil {
+		return props, nil
+	} else if !xerrors.Is(err, errArtifactNotFound) {
+		return properties{}, xerrors.Errorf("failed to search by SHA1: %w", err)
+	}
+
+	log.Logger.Debugf("No such POM in the central repositories: %s", filePath)
+
+	// Return when artifactId or version from the file name are empty
+	if fileProps.artifactID == "" || fileProps.version == "" {
+		return properties{}, nil
+	}
+
+	// Try to search groupId by artifactId via sonatype API
+	// When some artifacts have the same groupIds, it might result in false detection.
+	fileProps.groupID, err = p.searchByArtifactID(fileProps.artifactID)
+	if err == nil {
+		log.Logger.Debugf("POM was determined in a heuristic way: %s => %s", filePath, fileProps)
+		return fileProps, nil
+	} else if !xerrors.Is(err, errArtifactNotFound) {
+		return properties{}, xerrors.Errorf("failed to search by artifact id: %w", err)
+	}
+	return properties{}, nil
+}
+
+func (p parser) parseInnerJar(parentPath string, zf *zip.File) ([]ftypes.Package, error) {
+	fr, err := zf.Open()
+	if err != nil {
+		return nil, xerrors.Errorf("unable to open %s: %w", zf.Name, err)
+	}
+	defer fr.Close()
+
+	f, err := os.CreateTemp("", "inner")
+	if err != nil {
+		return nil, xerrors.Errorf("unable to create a temp file: %w", err)
+	}
+	defer func() {
+		f.Close()
+		os.Remove(f.Name())
+	}()
+
+	// Copy the file content to the temp file
+	if _, err = io.Copy(f, fr); err != nil {
+		return nil, xerrors.Errorf("file copy error: %w", err)
+	}
+
+	// Parse jar/war/ear recursively
+	innerPath := path.Join(filepath.ToSlash(parentPath), zf.Name)
+	return p.parseArtifact(innerPath, int64(zf.UncompressedSize64), f)
+}
+
+func isArtifact(name string) bool {
+	switch path.Ext(name) {
+	case ".jar", ".ear", ".war":
+		return true
+	}
+	return false
+}
+
+func parseFileName(fileName string) properties {
+	packageVersion := jarFileRegEx.FindStringSubmatch(fileName)
+	if len(packageVersion) != 3 {
+		return properties{}
+	}
+
+	return properties{
+		artifactID: packageVersion[1],
+		version:    packageVersion[2],
+	}
+}
+
+type properties struct {
+	groupID    string
+	artifactID string
+	version    string
+}
+
+func parsePomProperties(f *zip.File) (properties, error) {
+	file, err := f.Open()
+	if err != nil {
+		return properties{}, xerrors.Errorf("unable to open %s: %w", pomProperties, err)
+	}
+	defer file.Close()
+
+	var p properties
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "groupId="):
+			p.groupID = strings.TrimPrefix(line, "groupId=")
+		case strings.HasPrefix(line, "artifactId="):
+			p.artifactID = strings.TrimPrefix(line, "artifactId=")
+		case strings.HasPrefix(line, "version="):
+			p.version = strings.TrimPrefix(line, "version=")
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return properties{}, xerrors.Errorf("scan error: %w", err)
+	}
+	return p, nil
+}
+
+func (p properties) name() string {
+	return fmt.Sprintf("%s:%s", p.groupID, p.artifactID)
+}
+
+func (p properties) pkg(filePath string) ftypes.Package {
+	return ftypes.Package{
+		Name:     p.name(),
+		Version:  p.version,
+		FilePath: filepath.ToSlash(filePath),
+	}
+}
+
+func (p properties) valid() bool {
+	return p.groupID != "" && p.artifactID != "" && p.version != ""
+}
+
+func (p properties) String() string {
+	return fmt.Sprintf("%s:%s:%s", p.groupID, p.artifactID, p.version)
+}
+
+// containsArtifact returns true if the artifact is already found regardless of the version,
+// since the version in pom.properties is more reliable than the others.
+func containsArtifact(pkgs []ftypes.Package, props properties) bool {
+	for _, pkg := range pkgs {
+		if pkg.Name == props.name() {
+			return true
+		}
+	}
+	return false
+}
+
+type manifest struct {
+	implementationVersion  string
+	implementationTitle    string
+	implementationVendorID string
+	specificationTitle     string
+	specificationVersion   string
+	bundleName             string
+	bundleVersion          string
+	bundleSymbolicName     string
+}
+
+func parseManifest(f *zip.File) (manifest, error) {
+	file, err := f.Open()
+	if err != nil {
+		return manifest{}, xerrors.Errorf("unable to open %s: %w", manifestFile, err)
+	}
+	defer file.Close()
+
+	var m manifest
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		// Skip variables. e.g. Bundle-Name: %bundleName
+		ss := strings.Fields(line)
+		if len(ss) <= 1 || strings.HasPrefix(ss[1], "%") {
+			continue
+		}
+
+		// It is not determined which fields are present in each application.
+		// In some cases, none of them are included, in which case they cannot be detected.
+		key, value, _ := strings.Cut(line, ":")
+		value = strings.TrimSpace(value)
+		switch key {
+		case "Implementation-Version":
+			m.implementationVersion = value
+		case "Implementation-Title":
+			m.implementationTitle = value
+		case "Implementation-Vendor-Id":
+			m.implementationVendorID = value
+		case "Specification-Version":
+			m.specificationVersion = value
+		case "Specification-Title":
+			m.specificationTitle = value
+		case "Bundle-Version":
+			m.bundleVersion = value
+		case "Bundle-Name":
+			m.bundleName = value
+		case "Bundle-SymbolicName":
+			m.bundleSymbolicName = value
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return manifest{}, xerrors.Errorf("scan error: %w", err)
+	}
+	return m, nil
+}
+
+func (m manifest) properties() properties {
+	return properties{
+		groupID:    m.groupID(),
+		artifactID: firstNonEmpty(m.implementationTitle, m.specificationTitle, m.bundleName),
+		version:    firstNonEmpty(m.implementationVersion, m.specificationVersion, m.bundleVersion),
+	}
+}
+
+func (m manifest) groupID() string {
+	switch {
+	case m.implementationVendorID != "":
+		return m.implementationVendorID
+	case m.bundleSymbolicName != "":
+		// e.g. "com.fasterxml.jackson.core.jackson-databind;singleton:=true" => "com.fasterxml.jackson.core"
+		name, _, _ := strings.Cut(m.bundleSymbolicName, ";")
+		if idx := strings.LastIndex(name, "."); idx > 0 {
+			return name[:idx]
+		}
+		return name
+	}
+	return ""
+}
+
+func firstNonEmpty(ss ...string) string {
+	for _, s := range ss {
+		if s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// sortPackages sorts packages for stable results
+func sortPackages(pkgs []ftypes.Package) {
+	sort.Slice(pkgs, func(i, j int) bool {
+		if pkgs[i].FilePath != pkgs[j].FilePath {
+			return pkgs[i].FilePath < pkgs[j].FilePath
+		}
+		if pkgs[i].Name != pkgs[j].Name {
+			return pkgs[i].Name < pkgs[j].Name
+		}
+		return pkgs[i].Version < pkgs[j].Version
+	})
+}
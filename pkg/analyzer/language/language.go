@@ -0,0 +1,79 @@
+package language
+
+import (
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/analyzer/language"
+	ftypes "github.com/aquasecurity/fanal/types"
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// ToAnalysisResult is the same as that of fanal, but also records the IDs of development dependencies.
+// They are included in the application so that the cached result doesn't depend on "--include-dev-deps".
+func ToAnalysisResult(fileType, filePath, libFilePath string, libs []godeptypes.Library,
+	depGraph []godeptypes.Dependency, devIDs []string) *analyzer.AnalysisResult {
+	result := language.ToAnalysisResult(fileType, filePath, libFilePath, libs, depGraph)
+	if result == nil || len(devIDs) == 0 {
+		return result
+	}
+
+	var devPkgs []ftypes.Package
+	for _, id := range devIDs {
+		devPkgs = append(devPkgs, ftypes.Package{ID: id})
+	}
+	result.Applications = append(result.Applications, ftypes.Application{
+		Type:      types.DevDependencies,
+		FilePath:  filePath,
+		Libraries: devPkgs,
+	})
+	return result
+}
+
+// ExcludeDevDeps removes development dependencies from applications unless includeDevDeps is true.
+func ExcludeDevDeps(apps []ftypes.Application, includeDevDeps bool) []ftypes.Application {
+	// file path => IDs of development dependencies
+	devIDs := map[string]map[string]struct{}{}
+	for _, app := range apps {
+		if app.Type != types.DevDependencies {
+			continue
+		}
+		ids := map[string]struct{}{}
+		for _, pkg := range app.Libraries {
+			ids[pkg.ID] = struct{}{}
+		}
+		devIDs[app.FilePath] = ids
+	}
+
+	var result []ftypes.Application
+	for _, app := range apps {
+		if app.Type == types.DevDependencies {
+			continue
+		}
+		ids, ok := devIDs[app.FilePath]
+		if !ok || includeDevDeps {
+			result = append(result, app)
+			continue
+		}
+
+		var pkgs []ftypes.Package
+		for _, pkg := range app.Libraries {
+			if _, ok := ids[pkg.ID]; ok {
+				continue
+			}
+			var dependsOn []string
+			for _, id := range pkg.DependsOn {
+				if _, ok := ids[id]; !ok {
+					dependsOn = append(dependsOn, id)
+				}
+			}
+			pkg.DependsOn = dependsOn
+			pkgs = append(pkgs, pkg)
+		}
+		if len(pkgs) == 0 {
+			continue
+		}
+		app.Libraries = pkgs
+		result = append(result, app)
+	}
+	return result
+}
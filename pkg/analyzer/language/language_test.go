@@ -0,0 +1,100 @@
+package language
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestExcludeDevDeps(t *testing.T) {
+	apps := []ftypes.Application{
+		{
+			Type:     ftypes.Poetry,
+			FilePath: "poetry.lock",
+			Libraries: []ftypes.Package{
+				{
+					ID:      "iniconfig@1.1.1",
+					Name:    "iniconfig",
+					Version: "1.1.1",
+				},
+				{
+					ID:        "pytest@7.1.3",
+					Name:      "pytest",
+					Version:   "7.1.3",
+					DependsOn: []string{"iniconfig@1.1.1", "packaging@21.3"},
+				},
+				{
+					ID:      "packaging@21.3",
+					Name:    "packaging",
+					Version: "21.3",
+				},
+			},
+		},
+		{
+			Type:     types.DevDependencies,
+			FilePath: "poetry.lock",
+			Libraries: []ftypes.Package{
+				{ID: "iniconfig@1.1.1"},
+				{ID: "pytest@7.1.3"},
+			},
+		},
+		{
+			Type:     ftypes.Pipenv,
+			FilePath: "Pipfile.lock",
+			Libraries: []ftypes.Package{
+				{
+					ID:      "pytest@7.1.3",
+					Name:    "pytest",
+					Version: "7.1.3",
+				},
+			},
+		},
+		{
+			Type:     types.DevDependencies,
+			FilePath: "Pipfile.lock",
+			Libraries: []ftypes.Package{
+				{ID: "pytest@7.1.3"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		includeDevDeps bool
+		want           []ftypes.Application
+	}{
+		{
+			name:           "exclude",
+			includeDevDeps: false,
+			want: []ftypes.Application{
+				{
+					Type:     ftypes.Poetry,
+					FilePath: "poetry.lock",
+					Libraries: []ftypes.Package{
+						{
+							ID:      "packaging@21.3",
+							Name:    "packaging",
+							Version: "21.3",
+						},
+					},
+				},
+			},
+		},
+		{
+			name:           "include",
+			includeDevDeps: true,
+			want: []ftypes.Application{
+				apps[0],
+				apps[2],
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ExcludeDevDeps(apps, tt.includeDevDeps))
+		})
+	}
+}
@@ -0,0 +1,348 @@
+package npm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/types"
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/analyzer/language"
+
+	// The analyzer of fanal must be registered first so that it is overridden by this one
+	_ "github.com/aquasecurity/fanal/analyzer/language/nodejs/npm"
+)
+
+const version = 2
+
+const (
+	nodeModulesDir = "node_modules"
+	packageJSON    = "package.json"
+)
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&npmLibraryAnalyzer{})
+}
+
+type lockFile struct {
+	// lockfileVersion 1 and 2
+	Dependencies map[string]dependency `json:"dependencies"`
+
+	// lockfileVersion 2 and 3
+	// The key is the location of the package, e.g. "", "packages/a", "node_modules/foo" and "packages/a/node_modules/bar".
+	Packages map[string]packageInfo `json:"packages"`
+}
+
+type dependency struct {
+	Version      string                `json:"version"`
+	Dev          bool                  `json:"dev"`
+	Requires     map[string]string     `json:"requires"`
+	Dependencies map[string]dependency `json:"dependencies"`
+}
+
+type packageInfo struct {
+	Name                 string            `json:"name"`
+	Version              string            `json:"version"`
+	Resolved             string            `json:"resolved"`
+	Link                 bool              `json:"link"`
+	Dependencies         map[string]string `json:"dependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+}
+
+// workspace holds packages used by the root project or a workspace
+type workspace struct {
+	// The directory relative to the lock file. It is empty for the root project.
+	dir    string
+	libs   []godeptypes.Library
+	deps   []godeptypes.Dependency
+	devIDs []string
+}
+
+// npmLibraryAnalyzer parses package-lock.json.
+// Unlike the analyzer of fanal, lockfileVersion 3 is supported, and packages are reported per workspace
+// with development dependencies.
+type npmLibraryAnalyzer struct{}
+
+func (a npmLibraryAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	workspaces, err := parse(input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to parse %s: %w", types.NpmPkgLock, err)
+	}
+
+	result := &analyzer.AnalysisResult{}
+	for _, ws := range workspaces {
+		// Packages of workspaces are attributed to package.json of each workspace
+		filePath := input.FilePath
+		if ws.dir != "" {
+			filePath = path.Join(path.Dir(filepath.ToSlash(input.FilePath)), ws.dir, packageJSON)
+		}
+		if res := language.ToAnalysisResult(types.Npm, filePath, "", ws.libs, ws.deps, ws.devIDs); res != nil {
+			result.Applications = append(result.Applications, res.Applications...)
+		}
+	}
+	if len(result.Applications) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
+
+func (a npmLibraryAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	return filepath.Base(filePath) == types.NpmPkgLock
+}
+
+func (a npmLibraryAnalyzer) Type() analyzer.Type {
+	return analyzer.TypeNpmPkgLock
+}
+
+func (a npmLibraryAnalyzer) Version() int {
+	return version
+}
+
+func parse(r io.Reader) ([]workspace, error) {
+	var lock lockFile
+	if err := json.NewDecoder(r).Decode(&lock); err != nil {
+		return nil, xerrors.Errorf("decode error: %w", err)
+	}
+
+	if len(lock.Packages) == 0 {
+		return []workspace{parseV1(lock)}, nil
+	}
+	return parseV2(lock), nil
+}
+
+// parseV2 parses "packages" of lockfileVersion 2 and 3.
+// Packages are resolved in the same way as Node.js, i.e. looking for "node_modules" from the requiring package up to the root,
+// so that each workspace has only the packages it uses.
+// Packages used only via "devDependencies" of the workspace are development dependencies.
+// Other workspaces required by the workspace are linked and not included since they are reported by themselves.
+func parseV2(lock lockFile) []workspace {
+	var dirs []string
+	for loc, pkg := range lock.Packages {
+		if loc == "" || pkg.Link || isNodeModules(loc) {
+			continue
+		}
+		dirs = append(dirs, loc)
+	}
+	sort.Strings(dirs)
+
+	var workspaces []workspace
+	for _, dir := range append([]string{""}, dirs...) {
+		workspaces = append(workspaces, parseWorkspace(lock.Packages, dir))
+	}
+	return workspaces
+}
+
+func parseWorkspace(pkgs map[string]packageInfo, dir string) workspace {
+	root := pkgs[dir]
+	direct := map[string]struct{}{}
+	for _, m := range []map[string]string{root.Dependencies, root.OptionalDependencies, root.PeerDependencies, root.DevDependencies} {
+		for name := range m {
+			if loc, ok := resolve(pkgs, dir, name); ok {
+				direct[loc] = struct{}{}
+			}
+		}
+	}
+
+	prodLocs := walk(pkgs, dir, root.Dependencies, root.OptionalDependencies, root.PeerDependencies)
+	devLocs := walk(pkgs, dir, root.DevDependencies)
+
+	ws := workspace{dir: dir}
+	libs := map[string]godeptypes.Library{}
+	graph := map[string][]string{}
+	prodIDs := map[string]struct{}{}
+	for _, locs := range []map[string]struct{}{prodLocs, devLocs} {
+		for loc := range locs {
+			pkg := pkgs[loc]
+			name := pkgName(loc, pkg)
+			id := pkgID(name, pkg.Version)
+			if _, ok := prodLocs[loc]; ok {
+				prodIDs[id] = struct{}{}
+			}
+
+			_, isDirect := direct[loc]
+			lib := libs[id]
+			libs[id] = godeptypes.Library{
+				ID:      id,
+				Name:    name,
+				Version: pkg.Version,
+				// The same package can be installed in multiple locations
+				Indirect: !isDirect && (lib.ID == "" || lib.Indirect),
+			}
+
+			for _, m := range []map[string]string{pkg.Dependencies, pkg.OptionalDependencies, pkg.PeerDependencies} {
+				for depName := range m {
+					depLoc, ok := resolve(pkgs, loc, depName)
+					if !ok {
+						continue
+					}
+					graph[id] = append(graph[id], pkgID(pkgName(depLoc, pkgs[depLoc]), pkgs[depLoc].Version))
+				}
+			}
+		}
+	}
+
+	for _, id := range lo.Keys(libs) {
+		ws.libs = append(ws.libs, libs[id])
+		if _, ok := prodIDs[id]; !ok {
+			ws.devIDs = append(ws.devIDs, id)
+		}
+		if dependsOn := lo.Uniq(graph[id]); len(dependsOn) > 0 {
+			sort.Strings(dependsOn)
+			ws.deps = append(ws.deps, godeptypes.Dependency{
+				ID:        id,
+				DependsOn: dependsOn,
+			})
+		}
+	}
+	sort.Slice(ws.libs, func(i, j int) bool {
+		return ws.libs[i].ID < ws.libs[j].ID
+	})
+	sort.Slice(ws.deps, func(i, j int) bool {
+		return ws.deps[i].ID < ws.deps[j].ID
+	})
+	sort.Strings(ws.devIDs)
+	return ws
+}
+
+// walk returns the locations of packages reachable from the dependencies of the workspace
+func walk(pkgs map[string]packageInfo, dir string, deps ...map[string]string) map[string]struct{} {
+	visited := map[string]struct{}{}
+	var visit func(from string, deps []map[string]string)
+	visit = func(from string, deps []map[string]string) {
+		for _, m := range deps {
+			for name := range m {
+				loc, ok := resolve(pkgs, from, name)
+				if !ok {
+					continue
+				}
+				if _, ok = visited[loc]; ok {
+					continue
+				}
+				visited[loc] = struct{}{}
+				pkg := pkgs[loc]
+				visit(loc, []map[string]string{pkg.Dependencies, pkg.OptionalDependencies, pkg.PeerDependencies})
+			}
+		}
+	}
+	visit(dir, deps)
+	return visited
+}
+
+// resolve returns the location of the package required from the given location.
+// Linked packages, i.e. workspaces, and packages not installed, e.g. optional dependencies for other platforms, are not resolved.
+func resolve(pkgs map[string]packageInfo, from, name string) (string, bool) {
+	for {
+		loc := path.Join(from, nodeModulesDir, name)
+		if pkg, ok := pkgs[loc]; ok {
+			return loc, !pkg.Link
+		}
+		if from == "" {
+			return "", false
+		}
+
+		// Look up the parent directories as Node.js does.
+		// Locations which cannot exist, e.g. "node_modules/node_modules/foo", are just not found.
+		if from = path.Dir(from); from == "." {
+			from = ""
+		}
+	}
+}
+
+// parseV1 parses "dependencies" of lockfileVersion 1.
+// Workspaces are not supported in this version.
+func parseV1(lock lockFile) workspace {
+	libs := map[string]godeptypes.Library{}
+	graph := map[string][]string{}
+	devIDs := map[string]bool{}
+
+	var visit func(deps map[string]dependency, versions map[string]string)
+	visit = func(deps map[string]dependency, versions map[string]string) {
+		// Nested dependencies take precedence over the higher level ones
+		versions = lo.Assign(versions)
+		for name, dep := range deps {
+			versions[name] = dep.Version
+		}
+
+		for name, dep := range deps {
+			id := pkgID(name, dep.Version)
+			libs[id] = godeptypes.Library{
+				ID:      id,
+				Name:    name,
+				Version: dep.Version,
+				// Direct dependencies cannot be distinguished from hoisted ones
+				Indirect: true,
+			}
+			// A package is a development dependency only if all of the occurrences are
+			if isDev, ok := devIDs[id]; !ok || isDev {
+				devIDs[id] = dep.Dev
+			}
+
+			for depName := range dep.Requires {
+				if nested, ok := dep.Dependencies[depName]; ok {
+					graph[id] = append(graph[id], pkgID(depName, nested.Version))
+				} else if ver, ok := versions[depName]; ok {
+					graph[id] = append(graph[id], pkgID(depName, ver))
+				}
+			}
+			visit(dep.Dependencies, versions)
+		}
+	}
+	visit(lock.Dependencies, map[string]string{})
+
+	var ws workspace
+	for _, id := range lo.Keys(libs) {
+		ws.libs = append(ws.libs, libs[id])
+		if devIDs[id] {
+			ws.devIDs = append(ws.devIDs, id)
+		}
+		if dependsOn := lo.Uniq(graph[id]); len(dependsOn) > 0 {
+			sort.Strings(dependsOn)
+			ws.deps = append(ws.deps, godeptypes.Dependency{
+				ID:        id,
+				DependsOn: dependsOn,
+			})
+		}
+	}
+	sort.Slice(ws.libs, func(i, j int) bool {
+		return ws.libs[i].ID < ws.libs[j].ID
+	})
+	sort.Slice(ws.deps, func(i, j int) bool {
+		return ws.deps[i].ID < ws.deps[j].ID
+	})
+	sort.Strings(ws.devIDs)
+	return ws
+}
+
+func isNodeModules(loc string) bool {
+	return strings.HasPrefix(loc, nodeModulesDir+"/") || strings.Contains(loc, "/"+nodeModulesDir+"/")
+}
+
+// pkgName returns the package name.
+// The name is recorded only for aliases, so it is taken from the location otherwise.
+// e.g. "node_modules/@babel/core" => "@babel/core"
+func pkgName(loc string, pkg packageInfo) string {
+	if pkg.Name != "" {
+		return pkg.Name
+	}
+	if i := strings.LastIndex(loc, nodeModulesDir+"/"); i != -1 {
+		return loc[i+len(nodeModulesDir)+1:]
+	}
+	return loc
+}
+
+func pkgID(name, version string) string {
+	return fmt.Sprintf("%s@%s", name, version)
+}
@@ -0,0 +1,237 @@
+package npm
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/types"
+	ttypes "github.com/aquasecurity/trivy/pkg/types"
+)
+
+func Test_npmLibraryAnalyzer_Analyze(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		file     string
+		want     *analyzer.AnalysisResult
+		wantErr  string
+	}{
+		{
+			name:     "workspaces",
+			filePath: "app/package-lock.json",
+			file:     "testdata/workspaces-package-lock.json",
+			want: &analyzer.AnalysisResult{
+				Applications: []types.Application{
+					{
+						Type:     types.Npm,
+						FilePath: "app/package-lock.json",
+						Libraries: []types.Package{
+							{
+								ID:      "jest-util@1.0.0",
+								Name:    "jest-util",
+								Version: "1.0.0",
+								DependsOn: []string{
+									"ms@2.1.2",
+								},
+							},
+							{
+								ID:      "lodash@4.17.21",
+								Name:    "lodash",
+								Version: "4.17.21",
+							},
+							{
+								ID:       "ms@2.1.2",
+								Name:     "ms",
+								Version:  "2.1.2",
+								Indirect: true,
+							},
+						},
+					},
+					{
+						Type:     ttypes.DevDependencies,
+						FilePath: "app/package-lock.json",
+						Libraries: []types.Package{
+							{ID: "jest-util@1.0.0"},
+							{ID: "ms@2.1.2"},
+						},
+					},
+					{
+						Type:     types.Npm,
+						FilePath: "app/packages/api/package.json",
+						Libraries: []types.Package{
+							{
+								ID:       "debug@2.6.9",
+								Name:     "debug",
+								Version:  "2.6.9",
+								Indirect: true,
+								DependsOn: []string{
+									"ms@2.0.0",
+								},
+							},
+							{
+								ID:      "express@4.18.2",
+								Name:    "express",
+								Version: "4.18.2",
+								DependsOn: []string{
+									"debug@2.6.9",
+								},
+							},
+							{
+								ID:      "fsevents@2.3.2",
+								Name:    "fsevents",
+								Version: "2.3.2",
+							},
+							{
+								ID:      "lodash@4.17.20",
+								Name:    "lodash",
+								Version: "4.17.20",
+							},
+							{
+								ID:       "ms@2.0.0",
+								Name:     "ms",
+								Version:  "2.0.0",
+								Indirect: true,
+							},
+						},
+					},
+					{
+						Type:     types.Npm,
+						FilePath: "app/packages/web/package.json",
+						Libraries: []types.Package{
+							{
+								ID:      "debug@4.3.4",
+								Name:    "debug",
+								Version: "4.3.4",
+								DependsOn: []string{
+									"ms@2.1.2",
+								},
+							},
+							{
+								ID:      "jest-util@1.0.0",
+								Name:    "jest-util",
+								Version: "1.0.0",
+								DependsOn: []string{
+									"ms@2.1.2",
+								},
+							},
+							{
+								ID:       "ms@2.1.2",
+								Name:     "ms",
+								Version:  "2.1.2",
+								Indirect: true,
+							},
+						},
+					},
+					{
+						Type:     ttypes.DevDependencies,
+						FilePath: "app/packages/web/package.json",
+						Libraries: []types.Package{
+							{ID: "jest-util@1.0.0"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "lockfileVersion 1",
+			filePath: "package-lock.json",
+			file:     "testdata/v1-package-lock.json",
+			want: &analyzer.AnalysisResult{
+				Applications: []types.Application{
+					{
+						Type:     types.Npm,
+						FilePath: "package-lock.json",
+						Libraries: []types.Package{
+							{
+								ID:       "debug@2.6.9",
+								Name:     "debug",
+								Version:  "2.6.9",
+								Indirect: true,
+								DependsOn: []string{
+									"ms@2.0.0",
+								},
+							},
+							{
+								ID:       "debug@4.3.4",
+								Name:     "debug",
+								Version:  "4.3.4",
+								Indirect: true,
+								DependsOn: []string{
+									"ms@2.1.2",
+								},
+							},
+							{
+								ID:       "express@4.18.2",
+								Name:     "express",
+								Version:  "4.18.2",
+								Indirect: true,
+								DependsOn: []string{
+									"debug@2.6.9",
+								},
+							},
+							{
+								ID:       "jest-util@1.0.0",
+								Name:     "jest-util",
+								Version:  "1.0.0",
+								Indirect: true,
+								DependsOn: []string{
+									"ms@2.1.2",
+								},
+							},
+							{
+								ID:       "ms@2.0.0",
+								Name:     "ms",
+								Version:  "2.0.0",
+								Indirect: true,
+							},
+							{
+								ID:       "ms@2.1.2",
+								Name:     "ms",
+								Version:  "2.1.2",
+								Indirect: true,
+							},
+						},
+					},
+					{
+						Type:     ttypes.DevDependencies,
+						FilePath: "package-lock.json",
+						Libraries: []types.Package{
+							{ID: "jest-util@1.0.0"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "broken",
+			filePath: "package-lock.json",
+			file:     "testdata/broken-package-lock.json",
+			wantErr:  "decode error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.file)
+			require.NoError(t, err)
+			defer f.Close()
+
+			a := npmLibraryAnalyzer{}
+			got, err := a.Analyze(context.Background(), analyzer.AnalysisInput{
+				FilePath: tt.filePath,
+				Content:  f,
+			})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package pnp
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/analyzer/language"
+	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/go-dep-parser/pkg/nodejs/packagejson"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const version = 1
+
+var (
+	// The project cache and the global cache, e.g. "app/.yarn/cache" and "root/.yarn/berry/cache"
+	cacheDirs = []string{".yarn/cache", ".yarn/berry/cache"}
+
+	// package.json of the package in the archive, e.g. "node_modules/@babel/core/package.json"
+	packageJSONRegexp = regexp.MustCompile(`^node_modules/(@[^/]+/)?[^/]+/package\.json$`)
+)
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&yarnPnPAnalyzer{})
+}
+
+// yarnPnPAnalyzer detects packages installed by Yarn Plug'n'Play.
+// PnP doesn't create node_modules, and packages are loaded from zip archives in the Yarn cache,
+// e.g. app/.yarn/cache/lodash-npm-4.17.21-6382451519-eb835a2e51.zip.
+// They are reported as Node.js packages in the same way as package.json in node_modules.
+type yarnPnPAnalyzer struct{}
+
+func (a yarnPnPAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	zr, err := zip.NewReader(input.Content, input.Info.Size())
+	if err != nil {
+		return nil, xerrors.Errorf("zip reader error: %w", err)
+	}
+
+	for _, f := range zr.File {
+		if !packageJSONRegexp.MatchString(f.Name) {
+			continue
+		}
+
+		b, err := readFile(f)
+		if err != nil {
+			return nil, xerrors.Errorf("unable to read %s in %s: %w", f.Name, input.FilePath, err)
+		}
+		libs, _, err := packagejson.NewParser().Parse(bytes.NewReader(b))
+		if err != nil {
+			return nil, xerrors.Errorf("unable to parse %s in %s: %w", f.Name, input.FilePath, err)
+		}
+		for i, lib := range libs {
+			libs[i].ID = fmt.Sprintf("%s@%s", lib.Name, lib.Version)
+		}
+		return language.ToAnalysisResult(ftypes.NodePkg, input.FilePath, input.FilePath, libs, nil), nil
+	}
+	return nil, nil
+}
+
+func (a yarnPnPAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	if filepath.Ext(filePath) != ".zip" {
+		return false
+	}
+	dir := "/" + path.Dir(filepath.ToSlash(filePath))
+	for _, cacheDir := range cacheDirs {
+		if strings.HasSuffix(dir, "/"+cacheDir) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a yarnPnPAnalyzer) Type() analyzer.Type {
+	return types.YarnPnP
+}
+
+func (a yarnPnPAnalyzer) Version() int {
+	return version
+}
+
+func readFile(f *zip.File) ([]byte, error) {
+	r, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
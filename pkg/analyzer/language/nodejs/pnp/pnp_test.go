@@ -0,0 +1,139 @@
+package pnp
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/types"
+)
+
+func Test_yarnPnPAnalyzer_Analyze(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		file     string
+		want     *analyzer.AnalysisResult
+		wantErr  string
+	}{
+		{
+			name:     "happy path",
+			filePath: "app/.yarn/cache/lodash-npm-4.17.21-6382451519-eb835a2e51.zip",
+			file:     "testdata/lodash-npm-4.17.21-6382451519-eb835a2e51.zip",
+			want: &analyzer.AnalysisResult{
+				Applications: []types.Application{
+					{
+						Type:     types.NodePkg,
+						FilePath: "app/.yarn/cache/lodash-npm-4.17.21-6382451519-eb835a2e51.zip",
+						Libraries: []types.Package{
+							{
+								ID:       "lodash@4.17.21",
+								Name:     "lodash",
+								Version:  "4.17.21",
+								License:  "MIT",
+								FilePath: "app/.yarn/cache/lodash-npm-4.17.21-6382451519-eb835a2e51.zip",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "scoped package",
+			filePath: "root/.yarn/berry/cache/@babel-code-frame-npm-7.18.6-61ecb8a4a4-195e2be314.zip",
+			file:     "testdata/@babel-code-frame-npm-7.18.6-61ecb8a4a4-195e2be314.zip",
+			want: &analyzer.AnalysisResult{
+				Applications: []types.Application{
+					{
+						Type:     types.NodePkg,
+						FilePath: "root/.yarn/berry/cache/@babel-code-frame-npm-7.18.6-61ecb8a4a4-195e2be314.zip",
+						Libraries: []types.Package{
+							{
+								ID:       "@babel/code-frame@7.18.6",
+								Name:     "@babel/code-frame",
+								Version:  "7.18.6",
+								License:  "MIT",
+								FilePath: "root/.yarn/berry/cache/@babel-code-frame-npm-7.18.6-61ecb8a4a4-195e2be314.zip",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "no package.json",
+			filePath: "app/.yarn/cache/no-package-json.zip",
+			file:     "testdata/no-package-json.zip",
+			want:     nil,
+		},
+		{
+			name:     "broken zip",
+			filePath: "app/.yarn/cache/broken.zip",
+			file:     "testdata/broken.zip",
+			wantErr:  "zip reader error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.file)
+			require.NoError(t, err)
+			defer f.Close()
+
+			fi, err := f.Stat()
+			require.NoError(t, err)
+
+			a := yarnPnPAnalyzer{}
+			got, err := a.Analyze(context.Background(), analyzer.AnalysisInput{
+				FilePath: tt.filePath,
+				Info:     fi,
+				Content:  f,
+			})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_yarnPnPAnalyzer_Required(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		want     bool
+	}{
+		{
+			name:     "project cache",
+			filePath: "app/.yarn/cache/lodash-npm-4.17.21-6382451519-eb835a2e51.zip",
+			want:     true,
+		},
+		{
+			name:     "global cache",
+			filePath: "root/.yarn/berry/cache/lodash-npm-4.17.21-6382451519-eb835a2e51.zip",
+			want:     true,
+		},
+		{
+			name:     "not in cache",
+			filePath: "app/dist/lodash-npm-4.17.21-6382451519-eb835a2e51.zip",
+			want:     false,
+		},
+		{
+			name:     "not zip",
+			filePath: "app/.yarn/cache/.gitignore",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := yarnPnPAnalyzer{}
+			assert.Equal(t, tt.want, a.Required(tt.filePath, nil))
+		})
+	}
+}
@@ -0,0 +1,324 @@
+package pnpm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/analyzer/language"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const (
+	version = 1
+
+	fileName    = "pnpm-lock.yaml"
+	packageJSON = "package.json"
+	rootDir     = "."
+)
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&pnpmLockAnalyzer{})
+}
+
+type lockFile struct {
+	LockfileVersion string `yaml:"lockfileVersion"`
+
+	// Dependencies of the project without workspaces in lockfileVersion 5 and 6
+	Dependencies         dependencies `yaml:"dependencies"`
+	OptionalDependencies dependencies `yaml:"optionalDependencies"`
+	DevDependencies      dependencies `yaml:"devDependencies"`
+
+	// The root project and workspaces
+	Importers map[string]importer `yaml:"importers"`
+
+	// Packages with their dependencies in lockfileVersion 5 and 6.
+	// Dependencies are moved to "snapshots" in lockfileVersion 9.
+	Packages  map[string]packageInfo `yaml:"packages"`
+	Snapshots map[string]packageInfo `yaml:"snapshots"`
+}
+
+type importer struct {
+	Dependencies         dependencies `yaml:"dependencies"`
+	OptionalDependencies dependencies `yaml:"optionalDependencies"`
+	DevDependencies      dependencies `yaml:"devDependencies"`
+}
+
+type packageInfo struct {
+	Dependencies         map[string]string `yaml:"dependencies"`
+	OptionalDependencies map[string]string `yaml:"optionalDependencies"`
+}
+
+// dependencies maps package names to the resolved references.
+// The references are strings in lockfileVersion 5, and objects with "specifier" and "version" after that.
+type dependencies map[string]string
+
+func (d *dependencies) UnmarshalYAML(node *yaml.Node) error {
+	var raw map[string]yaml.Node
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+
+	*d = dependencies{}
+	for name, n := range raw {
+		if n.Kind == yaml.ScalarNode {
+			(*d)[name] = n.Value
+			continue
+		}
+		var dep struct {
+			Version string `yaml:"version"`
+		}
+		if err := n.Decode(&dep); err != nil {
+			return err
+		}
+		(*d)[name] = dep.Version
+	}
+	return nil
+}
+
+// workspace holds packages used by the root project or a workspace
+type workspace struct {
+	// The directory relative to the lock file, e.g. "." and "packages/a"
+	dir    string
+	libs   []godeptypes.Library
+	deps   []godeptypes.Dependency
+	devIDs []string
+}
+
+// pnpmLockAnalyzer parses pnpm-lock.yaml.
+// Packages are reported per workspace, i.e. importer, with development dependencies.
+type pnpmLockAnalyzer struct{}
+
+func (a pnpmLockAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	workspaces, err := parse(input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to parse %s: %w", fileName, err)
+	}
+
+	result := &analyzer.AnalysisResult{}
+	for _, ws := range workspaces {
+		// Packages of workspaces are attributed to package.json of each workspace
+		filePath := input.FilePath
+		if ws.dir != rootDir {
+			filePath = path.Join(path.Dir(filepath.ToSlash(input.FilePath)), ws.dir, packageJSON)
+		}
+		if res := language.ToAnalysisResult(types.Pnpm, filePath, "", ws.libs, ws.deps, ws.devIDs); res != nil {
+			result.Applications = append(result.Applications, res.Applications...)
+		}
+	}
+	if len(result.Applications) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
+
+func (a pnpmLockAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	return filepath.Base(filePath) == fileName
+}
+
+func (a pnpmLockAnalyzer) Type() analyzer.Type {
+	return types.Pnpm
+}
+
+func (a pnpmLockAnalyzer) Version() int {
+	return version
+}
+
+func parse(r io.Reader) ([]workspace, error) {
+	var lock lockFile
+	if err := yaml.NewDecoder(r).Decode(&lock); err != nil {
+		return nil, xerrors.Errorf("yaml decode error: %w", err)
+	}
+
+	// e.g. 5.4, '6.0' and '9.0'
+	lockVer, err := strconv.ParseFloat(lock.LockfileVersion, 64)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid lockfileVersion %q: %w", lock.LockfileVersion, err)
+	}
+	p := parser{
+		lockVer:  lockVer,
+		packages: lock.Packages,
+	}
+	if lockVer >= 9 {
+		p.packages = lock.Snapshots
+	}
+
+	importers := lock.Importers
+	if len(importers) == 0 {
+		importers = map[string]importer{
+			rootDir: {
+				Dependencies:         lock.Dependencies,
+				OptionalDependencies: lock.OptionalDependencies,
+				DevDependencies:      lock.DevDependencies,
+			},
+		}
+	}
+
+	dirs := lo.Keys(importers)
+	sort.Strings(dirs)
+
+	var workspaces []workspace
+	for _, dir := range dirs {
+		ws := p.parseImporter(importers[dir])
+		ws.dir = dir
+		workspaces = append(workspaces, ws)
+	}
+	return workspaces, nil
+}
+
+type parser struct {
+	lockVer  float64
+	packages map[string]packageInfo
+}
+
+// parseImporter returns packages reachable from the importer.
+// Packages used only via "devDependencies" are development dependencies.
+// Other workspaces referred by "link:" are not included since they are reported by themselves.
+func (p parser) parseImporter(imp importer) workspace {
+	libs := map[string]godeptypes.Library{}
+	graph := map[string][]string{}
+	prodIDs := map[string]struct{}{}
+
+	var visit func(key string, prod bool)
+	visit = func(key string, prod bool) {
+		name, ver := p.nameVersion(key)
+		id := pkgID(name, ver)
+		if prod {
+			if _, ok := prodIDs[id]; ok {
+				return
+			}
+			prodIDs[id] = struct{}{}
+		} else if _, ok := libs[id]; ok {
+			return
+		}
+
+		if _, ok := libs[id]; !ok {
+			libs[id] = godeptypes.Library{
+				ID:       id,
+				Name:     name,
+				Version:  ver,
+				Indirect: true,
+			}
+		}
+
+		pkg := p.packages[key]
+		for _, m := range []map[string]string{pkg.Dependencies, pkg.OptionalDependencies} {
+			for depName, ref := range m {
+				depKey, ok := p.key(depName, ref)
+				if !ok {
+					continue
+				}
+				depName, depVer := p.nameVersion(depKey)
+				graph[id] = append(graph[id], pkgID(depName, depVer))
+				visit(depKey, prod)
+			}
+		}
+	}
+
+	for _, deps := range []struct {
+		refs dependencies
+		prod bool
+	}{
+		{refs: imp.Dependencies, prod: true},
+		{refs: imp.OptionalDependencies, prod: true},
+		{refs: imp.DevDependencies, prod: false},
+	} {
+		for name, ref := range deps.refs {
+			key, ok := p.key(name, ref)
+			if !ok {
+				continue
+			}
+			visit(key, deps.prod)
+
+			name, ver := p.nameVersion(key)
+			lib := libs[pkgID(name, ver)]
+			lib.Indirect = false
+			libs[lib.ID] = lib
+		}
+	}
+
+	var ws workspace
+	for _, id := range lo.Keys(libs) {
+		ws.libs = append(ws.libs, libs[id])
+		if _, ok := prodIDs[id]; !ok {
+			ws.devIDs = append(ws.devIDs, id)
+		}
+		if dependsOn := lo.Uniq(graph[id]); len(dependsOn) > 0 {
+			sort.Strings(dependsOn)
+			ws.deps = append(ws.deps, godeptypes.Dependency{
+				ID:        id,
+				DependsOn: dependsOn,
+			})
+		}
+	}
+	sort.Slice(ws.libs, func(i, j int) bool {
+		return ws.libs[i].ID < ws.libs[j].ID
+	})
+	sort.Slice(ws.deps, func(i, j int) bool {
+		return ws.deps[i].ID < ws.deps[j].ID
+	})
+	sort.Strings(ws.devIDs)
+	return ws
+}
+
+// key returns the key of the package in "packages" or "snapshots" from the reference of the dependency.
+// e.g. "1.0.0_react@18.2.0" => "/name/1.0.0_react@18.2.0" in lockfileVersion 5,
+// "1.0.0(react@18.2.0)" => "/name@1.0.0(react@18.2.0)" in lockfileVersion 6
+// and "1.0.0(react@18.2.0)" => "name@1.0.0(react@18.2.0)" in lockfileVersion 9.
+// Aliases, e.g. "/real-name/1.0.0" in lockfileVersion 5 and 6 and "real-name@1.0.0" in lockfileVersion 9, are keys as they are.
+// Local packages such as "link:../a" and "file:../b" are not resolved.
+func (p parser) key(name, ref string) (string, bool) {
+	switch {
+	case strings.HasPrefix(ref, "link:"), strings.HasPrefix(ref, "file:"):
+		return "", false
+	case p.lockVer < 6:
+		if !strings.HasPrefix(ref, "/") {
+			ref = fmt.Sprintf("/%s/%s", name, ref)
+		}
+	case p.lockVer < 9:
+		if !strings.HasPrefix(ref, "/") {
+			ref = fmt.Sprintf("/%s@%s", name, ref)
+		}
+	default:
+		if v, _, _ := strings.Cut(ref, "("); strings.LastIndex(v, "@") <= 0 {
+			ref = fmt.Sprintf("%s@%s", name, ref)
+		}
+	}
+	return ref, true
+}
+
+// nameVersion returns the package name and version from the key without peer dependencies.
+// e.g. "/@babel/core/7.20.0_supports-color@8.1.1" => "@babel/core", "7.20.0"
+func (p parser) nameVersion(key string) (string, string) {
+	key = strings.TrimPrefix(key, "/")
+	if p.lockVer < 6 {
+		idx := strings.LastIndex(key, "/")
+		name, ver := key[:idx+1], key[idx+1:]
+		ver, _, _ = strings.Cut(ver, "_")
+		return strings.TrimSuffix(name, "/"), ver
+	}
+
+	key, _, _ = strings.Cut(key, "(")
+	idx := strings.LastIndex(key, "@")
+	if idx <= 0 {
+		return key, ""
+	}
+	return key[:idx], key[idx+1:]
+}
+
+func pkgID(name, version string) string {
+	return fmt.Sprintf("%s@%s", name, version)
+}
@@ -0,0 +1,313 @@
+package pnpm
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func Test_pnpmLockAnalyzer_Analyze(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		file     string
+		want     *analyzer.AnalysisResult
+		wantErr  string
+	}{
+		{
+			name:     "lockfileVersion 5",
+			filePath: "pnpm-lock.yaml",
+			file:     "testdata/v5-pnpm-lock.yaml",
+			want: &analyzer.AnalysisResult{
+				Applications: []ftypes.Application{
+					{
+						Type:     types.Pnpm,
+						FilePath: "pnpm-lock.yaml",
+						Libraries: []ftypes.Package{
+							{
+								ID:      "@babel/helper-plugin-utils@7.20.2",
+								Name:    "@babel/helper-plugin-utils",
+								Version: "7.20.2",
+							},
+							{
+								ID:       "js-tokens@4.0.0",
+								Name:     "js-tokens",
+								Version:  "4.0.0",
+								Indirect: true,
+							},
+							{
+								ID:       "loose-envify@1.4.0",
+								Name:     "loose-envify",
+								Version:  "1.4.0",
+								Indirect: true,
+								DependsOn: []string{
+									"js-tokens@4.0.0",
+								},
+							},
+							{
+								ID:      "react-dom@18.2.0",
+								Name:    "react-dom",
+								Version: "18.2.0",
+								DependsOn: []string{
+									"loose-envify@1.4.0",
+									"react@18.2.0",
+								},
+							},
+							{
+								ID:       "react@18.2.0",
+								Name:     "react",
+								Version:  "18.2.0",
+								Indirect: true,
+								DependsOn: []string{
+									"loose-envify@1.4.0",
+								},
+							},
+							{
+								ID:      "string-width@4.2.3",
+								Name:    "string-width",
+								Version: "4.2.3",
+							},
+							{
+								ID:      "typescript@4.9.3",
+								Name:    "typescript",
+								Version: "4.9.3",
+							},
+						},
+					},
+					{
+						Type:     types.DevDependencies,
+						FilePath: "pnpm-lock.yaml",
+						Libraries: []ftypes.Package{
+							{ID: "typescript@4.9.3"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "lockfileVersion 6 with workspaces",
+			filePath: "app/pnpm-lock.yaml",
+			file:     "testdata/v6-pnpm-lock.yaml",
+			want: &analyzer.AnalysisResult{
+				Applications: []ftypes.Application{
+					{
+						Type:     types.Pnpm,
+						FilePath: "app/pnpm-lock.yaml",
+						Libraries: []ftypes.Package{
+							{
+								ID:      "typescript@5.0.4",
+								Name:    "typescript",
+								Version: "5.0.4",
+							},
+						},
+					},
+					{
+						Type:     types.DevDependencies,
+						FilePath: "app/pnpm-lock.yaml",
+						Libraries: []ftypes.Package{
+							{ID: "typescript@5.0.4"},
+						},
+					},
+					{
+						Type:     types.Pnpm,
+						FilePath: "app/packages/api/package.json",
+						Libraries: []ftypes.Package{
+							{
+								ID:      "debug@4.3.4",
+								Name:    "debug",
+								Version: "4.3.4",
+								DependsOn: []string{
+									"ms@2.1.2",
+									"supports-color@8.1.1",
+								},
+							},
+							{
+								ID:       "has-flag@4.0.0",
+								Name:     "has-flag",
+								Version:  "4.0.0",
+								Indirect: true,
+							},
+							{
+								ID:       "ms@2.1.2",
+								Name:     "ms",
+								Version:  "2.1.2",
+								Indirect: true,
+							},
+							{
+								ID:      "supports-color@8.1.1",
+								Name:    "supports-color",
+								Version: "8.1.1",
+								DependsOn: []string{
+									"has-flag@4.0.0",
+								},
+							},
+						},
+					},
+					{
+						Type:     types.Pnpm,
+						FilePath: "app/packages/shared/package.json",
+						Libraries: []ftypes.Package{
+							{
+								ID:      "debug@4.3.4",
+								Name:    "debug",
+								Version: "4.3.4",
+								DependsOn: []string{
+									"ms@2.1.2",
+									"supports-color@8.1.1",
+								},
+							},
+							{
+								ID:       "has-flag@4.0.0",
+								Name:     "has-flag",
+								Version:  "4.0.0",
+								Indirect: true,
+							},
+							{
+								ID:      "ms@2.1.2",
+								Name:    "ms",
+								Version: "2.1.2",
+							},
+							{
+								ID:       "supports-color@8.1.1",
+								Name:     "supports-color",
+								Version:  "8.1.1",
+								Indirect: true,
+								DependsOn: []string{
+									"has-flag@4.0.0",
+								},
+							},
+						},
+					},
+					{
+						Type:     types.DevDependencies,
+						FilePath: "app/packages/shared/package.json",
+						Libraries: []ftypes.Package{
+							{ID: "debug@4.3.4"},
+							{ID: "has-flag@4.0.0"},
+							{ID: "supports-color@8.1.1"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "lockfileVersion 9 with workspaces",
+			filePath: "pnpm-lock.yaml",
+			file:     "testdata/v9-pnpm-lock.yaml",
+			want: &analyzer.AnalysisResult{
+				Applications: []ftypes.Application{
+					{
+						Type:     types.Pnpm,
+						FilePath: "pnpm-lock.yaml",
+						Libraries: []ftypes.Package{
+							{
+								ID:      "debug@4.3.4",
+								Name:    "debug",
+								Version: "4.3.4",
+								DependsOn: []string{
+									"ms@2.1.2",
+									"supports-color@8.1.1",
+								},
+							},
+							{
+								ID:       "has-flag@4.0.0",
+								Name:     "has-flag",
+								Version:  "4.0.0",
+								Indirect: true,
+							},
+							{
+								ID:       "ms@2.1.2",
+								Name:     "ms",
+								Version:  "2.1.2",
+								Indirect: true,
+							},
+							{
+								ID:      "string-width@4.2.3",
+								Name:    "string-width",
+								Version: "4.2.3",
+							},
+							{
+								// Used by "debug" as well, so it is not a development dependency
+								ID:      "supports-color@8.1.1",
+								Name:    "supports-color",
+								Version: "8.1.1",
+								DependsOn: []string{
+									"has-flag@4.0.0",
+								},
+							},
+						},
+					},
+					{
+						Type:     types.Pnpm,
+						FilePath: "packages/web/package.json",
+						Libraries: []ftypes.Package{
+							{
+								ID:      "ms@2.1.3",
+								Name:    "ms",
+								Version: "2.1.3",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "broken",
+			filePath: "pnpm-lock.yaml",
+			file:     "testdata/broken-pnpm-lock.yaml",
+			wantErr:  "yaml decode error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.file)
+			require.NoError(t, err)
+			defer f.Close()
+
+			a := pnpmLockAnalyzer{}
+			got, err := a.Analyze(context.Background(), analyzer.AnalysisInput{
+				FilePath: tt.filePath,
+				Content:  f,
+			})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_pnpmLockAnalyzer_Required(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		want     bool
+	}{
+		{
+			name:     "happy path",
+			filePath: "app/pnpm-lock.yaml",
+			want:     true,
+		},
+		{
+			name:     "sad path",
+			filePath: "app/package-lock.json",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := pnpmLockAnalyzer{}
+			assert.Equal(t, tt.want, a.Required(tt.filePath, nil))
+		})
+	}
+}
@@ -0,0 +1,312 @@
+package yarn
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/go-dep-parser/pkg/nodejs/yarn"
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/analyzer/language"
+
+	// The analyzer of fanal must be registered first so that it is overridden by this one
+	_ "github.com/aquasecurity/fanal/analyzer/language/nodejs/yarn"
+)
+
+const version = 2
+
+const (
+	metadataKey = "__metadata"
+	packageJSON = "package.json"
+	rootDir     = "."
+
+	protocolNpm       = "npm"
+	protocolPatch     = "patch"
+	protocolWorkspace = "workspace"
+	protocolLink      = "link"
+)
+
+// e.g. "npm:", "workspace:" and "patch:"
+var protocolRegexp = regexp.MustCompile(`^[a-z]+:`)
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&yarnLibraryAnalyzer{})
+}
+
+// entry represents a package in yarn.lock of Yarn 2 and later, i.e. Yarn Berry
+type entry struct {
+	Version      string            `yaml:"version"`
+	Resolution   string            `yaml:"resolution"`
+	Dependencies map[string]string `yaml:"dependencies"`
+}
+
+// workspace holds packages used by the workspace
+type workspace struct {
+	// The directory relative to yarn.lock, e.g. "." and "packages/a"
+	dir  string
+	libs []godeptypes.Library
+	deps []godeptypes.Dependency
+}
+
+// yarnLibraryAnalyzer parses yarn.lock.
+// Unlike the analyzer of fanal, yarn.lock of Yarn Berry is parsed with the dependency graph,
+// and packages are reported per workspace.
+type yarnLibraryAnalyzer struct{}
+
+func (a yarnLibraryAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	content, err := io.ReadAll(input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("read error: %w", err)
+	}
+
+	if !isBerry(content) {
+		libs, deps, err := yarn.NewParser().Parse(bytes.NewReader(content))
+		if err != nil {
+			return nil, xerrors.Errorf("unable to parse %s: %w", types.YarnLock, err)
+		}
+		return language.ToAnalysisResult(types.Yarn, input.FilePath, "", libs, deps, nil), nil
+	}
+
+	workspaces, err := parseBerry(bytes.NewReader(content))
+	if err != nil {
+		return nil, xerrors.Errorf("unable to parse %s: %w", types.YarnLock, err)
+	}
+
+	result := &analyzer.AnalysisResult{}
+	for _, ws := range workspaces {
+		// Packages of workspaces are attributed to package.json of each workspace
+		filePath := input.FilePath
+		if ws.dir != rootDir {
+			filePath = path.Join(path.Dir(filepath.ToSlash(input.FilePath)), ws.dir, packageJSON)
+		}
+		if res := language.ToAnalysisResult(types.Yarn, filePath, "", ws.libs, ws.deps, nil); res != nil {
+			result.Applications = append(result.Applications, res.Applications...)
+		}
+	}
+	if len(result.Applications) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
+
+func (a yarnLibraryAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	return filepath.Base(filePath) == types.YarnLock
+}
+
+func (a yarnLibraryAnalyzer) Type() analyzer.Type {
+	return analyzer.TypeYarn
+}
+
+func (a yarnLibraryAnalyzer) Version() int {
+	return version
+}
+
+// isBerry returns true if yarn.lock is generated by Yarn 2 or later, which has the "__metadata" block.
+func isBerry(content []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), metadataKey+":") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseBerry parses yarn.lock of Yarn Berry, which is YAML.
+// The keys are descriptors resolved to the package, e.g. "lodash@npm:^4.17.20, lodash@npm:^4.17.21",
+// and dependencies are looked up with their descriptors.
+func parseBerry(r io.Reader) ([]workspace, error) {
+	var lock map[string]entry
+	if err := yaml.NewDecoder(r).Decode(&lock); err != nil {
+		return nil, xerrors.Errorf("yaml decode error: %w", err)
+	}
+	delete(lock, metadataKey)
+
+	p := berryParser{
+		entries:     map[string]entry{},
+		descriptors: map[string]string{},
+	}
+	// directory => workspace
+	workspaceEntries := map[string]entry{}
+	for key, e := range lock {
+		p.entries[e.Resolution] = e
+		for _, descriptor := range strings.Split(key, ",") {
+			p.descriptors[strings.TrimSpace(descriptor)] = e.Resolution
+		}
+
+		// e.g. "app@workspace:." and "@app/a@workspace:packages/a"
+		if _, protocol, ref := parseLocator(e.Resolution); protocol == protocolWorkspace {
+			workspaceEntries[ref] = e
+		}
+	}
+
+	dirs := lo.Keys(workspaceEntries)
+	sort.Strings(dirs)
+
+	var workspaces []workspace
+	for _, dir := range dirs {
+		ws := p.parseWorkspace(workspaceEntries[dir])
+		ws.dir = dir
+		workspaces = append(workspaces, ws)
+	}
+	return workspaces, nil
+}
+
+type berryParser struct {
+	// resolution => entry
+	entries map[string]entry
+	// descriptor => resolution
+	descriptors map[string]string
+}
+
+// parseWorkspace returns packages reachable from the workspace.
+// Other workspaces are not traversed since they are reported by themselves.
+// Packages installed via "portal:" are local, but their dependencies are reported as Yarn installs them.
+// Packages linked with "link:" are skipped with their dependencies.
+func (p berryParser) parseWorkspace(ws entry) workspace {
+	libs := map[string]godeptypes.Library{}
+	graph := map[string][]string{}
+	visited := map[string]struct{}{}
+
+	var visit func(resolution string) string
+	visit = func(resolution string) string {
+		e := p.entries[resolution]
+		name, version, ok := p.npmPackage(resolution, e)
+		var id string
+		if ok {
+			id = pkgID(name, version)
+			if _, found := libs[id]; !found {
+				libs[id] = godeptypes.Library{
+					ID:       id,
+					Name:     name,
+					Version:  version,
+					Indirect: true,
+				}
+			}
+		}
+
+		if _, done := visited[resolution]; done {
+			return id
+		}
+		visited[resolution] = struct{}{}
+
+		for _, child := range p.children(e) {
+			childID := visit(child)
+			if id != "" && childID != "" {
+				graph[id] = append(graph[id], childID)
+			}
+		}
+		return id
+	}
+
+	for _, child := range p.children(ws) {
+		if id := visit(child); id != "" {
+			lib := libs[id]
+			lib.Indirect = false
+			libs[id] = lib
+		}
+	}
+
+	var w workspace
+	for _, id := range lo.Keys(libs) {
+		w.libs = append(w.libs, libs[id])
+		if dependsOn := lo.Uniq(graph[id]); len(dependsOn) > 0 {
+			sort.Strings(dependsOn)
+			w.deps = append(w.deps, godeptypes.Dependency{
+				ID:        id,
+				DependsOn: dependsOn,
+			})
+		}
+	}
+	sort.Slice(w.libs, func(i, j int) bool {
+		return w.libs[i].ID < w.libs[j].ID
+	})
+	sort.Slice(w.deps, func(i, j int) bool {
+		return w.deps[i].ID < w.deps[j].ID
+	})
+	return w
+}
+
+// children returns the resolutions of the dependencies to be traversed
+func (p berryParser) children(e entry) []string {
+	var resolutions []string
+	for name, rng := range e.Dependencies {
+		// Ranges without protocols are npm packages
+		if !protocolRegexp.MatchString(rng) {
+			rng = protocolNpm + ":" + rng
+		}
+		resolution, ok := p.descriptors[name+"@"+rng]
+		if !ok {
+			continue
+		}
+		switch _, protocol, _ := parseLocator(resolution); protocol {
+		case protocolWorkspace, protocolLink:
+			continue
+		}
+		resolutions = append(resolutions, resolution)
+	}
+	sort.Strings(resolutions)
+	return resolutions
+}
+
+// npmPackage returns the name and version of the package if it comes from the npm registry.
+// Patched packages, e.g. "resolve@patch:resolve@npm%3A1.22.1#~builtin<compat/resolve>::version=1.22.1&hash=07638b",
+// are reported as the original packages since the patches don't change the vulnerabilities in most cases.
+func (p berryParser) npmPackage(resolution string, e entry) (string, string, bool) {
+	name, protocol, ref := parseLocator(resolution)
+	switch protocol {
+	case protocolNpm:
+		return name, e.Version, true
+	case protocolPatch:
+		// e.g. "resolve@npm%3A1.22.1#~builtin<compat/resolve>::version=1.22.1&hash=07638b" => "resolve@npm:1.22.1"
+		source, _, _ := strings.Cut(ref, "#")
+		source, err := url.QueryUnescape(source)
+		if err != nil {
+			return "", "", false
+		}
+		if _, sourceProtocol, _ := parseLocator(source); sourceProtocol != protocolNpm {
+			return "", "", false
+		}
+		return name, e.Version, true
+	}
+	return "", "", false
+}
+
+// parseLocator splits the locator into the name, protocol and reference.
+// e.g. "@babel/core@npm:7.20.0" => "@babel/core", "npm", "7.20.0"
+func parseLocator(locator string) (string, string, string) {
+	// The scope of the name also starts with "@"
+	idx := strings.Index(strings.TrimPrefix(locator, "@"), "@")
+	if idx == -1 {
+		return locator, "", ""
+	}
+	if strings.HasPrefix(locator, "@") {
+		idx++
+	}
+	name, rest := locator[:idx], locator[idx+1:]
+	protocol, ref, found := strings.Cut(rest, ":")
+	if !found {
+		return name, "", rest
+	}
+	return name, protocol, ref
+}
+
+func pkgID(name, version string) string {
+	return fmt.Sprintf("%s@%s", name, version)
+}
@@ -0,0 +1,155 @@
+package yarn
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/types"
+)
+
+func Test_yarnLibraryAnalyzer_Analyze(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		file     string
+		want     *analyzer.AnalysisResult
+		wantErr  string
+	}{
+		{
+			name:     "Yarn Berry with workspaces",
+			filePath: "app/yarn.lock",
+			file:     "testdata/berry.yarn.lock",
+			want: &analyzer.AnalysisResult{
+				Applications: []types.Application{
+					{
+						Type:     types.Yarn,
+						FilePath: "app/yarn.lock",
+						Libraries: []types.Package{
+							{
+								ID:      "@babel/code-frame@7.18.6",
+								Name:    "@babel/code-frame",
+								Version: "7.18.6",
+								DependsOn: []string{
+									"@babel/highlight@7.18.6",
+								},
+							},
+							{
+								ID:       "@babel/highlight@7.18.6",
+								Name:     "@babel/highlight",
+								Version:  "7.18.6",
+								Indirect: true,
+								DependsOn: []string{
+									"js-tokens@4.0.0",
+								},
+							},
+							{
+								ID:       "js-tokens@4.0.0",
+								Name:     "js-tokens",
+								Version:  "4.0.0",
+								Indirect: true,
+							},
+						},
+					},
+					{
+						Type:     types.Yarn,
+						FilePath: "app/packages/ui/package.json",
+						Libraries: []types.Package{
+							{
+								ID:      "@example/left-pad@1.3.0",
+								Name:    "@example/left-pad",
+								Version: "1.3.0",
+							},
+							{
+								// Installed by the portal package
+								ID:       "ms@2.1.3",
+								Name:     "ms",
+								Version:  "2.1.3",
+								Indirect: true,
+							},
+						},
+					},
+					{
+						Type:     types.Yarn,
+						FilePath: "app/packages/web/package.json",
+						Libraries: []types.Package{
+							{
+								ID:      "lodash@4.17.21",
+								Name:    "lodash",
+								Version: "4.17.21",
+							},
+							{
+								ID:       "path-parse@1.0.7",
+								Name:     "path-parse",
+								Version:  "1.0.7",
+								Indirect: true,
+							},
+							{
+								// Patched by Yarn
+								ID:      "resolve@1.22.1",
+								Name:    "resolve",
+								Version: "1.22.1",
+								DependsOn: []string{
+									"path-parse@1.0.7",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "Yarn Classic",
+			filePath: "yarn.lock",
+			file:     "testdata/classic.yarn.lock",
+			want: &analyzer.AnalysisResult{
+				Applications: []types.Application{
+					{
+						Type:     types.Yarn,
+						FilePath: "yarn.lock",
+						Libraries: []types.Package{
+							{
+								Name:    "js-tokens",
+								Version: "4.0.0",
+							},
+							{
+								Name:    "loose-envify",
+								Version: "1.4.0",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "broken",
+			filePath: "yarn.lock",
+			file:     "testdata/broken.yarn.lock",
+			wantErr:  "yaml decode error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.file)
+			require.NoError(t, err)
+			defer f.Close()
+
+			a := yarnLibraryAnalyzer{}
+			got, err := a.Analyze(context.Background(), analyzer.AnalysisInput{
+				FilePath: tt.filePath,
+				Content:  f,
+			})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package composer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/fanal/walker"
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/analyzer/language"
+
+	// The analyzer of fanal must be registered first so that it is overridden by this one
+	_ "github.com/aquasecurity/fanal/analyzer/language/php/composer"
+)
+
+const version = 3
+
+// installedJSON is the file where Composer records packages installed in the vendor directory.
+// It is shipped in container images even if composer.lock is not.
+const installedJSON = "vendor/composer/installed.json"
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&composerLibraryAnalyzer{})
+
+	// "vendor" is skipped by default, but installed.json is there
+	walker.AppDirs = lo.Reject(walker.AppDirs, func(dir string, _ int) bool {
+		return dir == "vendor"
+	})
+}
+
+type packageInfo struct {
+	Name    string            `json:"name"`
+	Version string            `json:"version"`
+	Require map[string]string `json:"require"`
+	License licenses          `json:"license"`
+}
+
+// licenses is usually an array, but a string is also allowed
+type licenses []string
+
+func (l *licenses) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*l = licenses{s}
+		return nil
+	}
+
+	var ss []string
+	if err := json.Unmarshal(data, &ss); err != nil {
+		return err
+	}
+	*l = ss
+	return nil
+}
+
+type lockFile struct {
+	Packages    []packageInfo `json:"packages"`
+	PackagesDev []packageInfo `json:"packages-dev"`
+}
+
+// installedFile is installed.json of Composer 2.
+// Composer 1 writes only the array of packages.
+type installedFile struct {
+	Packages        []packageInfo `json:"packages"`
+	DevPackageNames []string      `json:"dev-package-names"`
+}
+
+// composerLibraryAnalyzer parses composer.lock and installed.json.
+// Unlike the analyzer of fanal, packages have dependencies and licenses.
+type composerLibraryAnalyzer struct{}
+
+func (a composerLibraryAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	parse := parseLockFile
+	if isInstalledJSON(input.FilePath) {
+		parse = parseInstalledJSON
+	}
+
+	libs, deps, devIDs, err := parse(input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to parse %s: %w", input.FilePath, err)
+	}
+	return language.ToAnalysisResult(types.Composer, input.FilePath, "", libs, deps, devIDs), nil
+}
+
+func (a composerLibraryAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	return filepath.Base(filePath) == types.ComposerLock || isInstalledJSON(filePath)
+}
+
+func (a composerLibraryAnalyzer) Type() analyzer.Type {
+	return analyzer.TypeComposer
+}
+
+func (a composerLibraryAnalyzer) Version() int {
+	return version
+}
+
+func isInstalledJSON(filePath string) bool {
+	return strings.HasSuffix(filepath.ToSlash(filePath), installedJSON)
+}
+
+// parseLockFile parses composer.lock.
+// Packages in "packages-dev" are returned as development dependencies.
+func parseLockFile(r io.Reader) ([]godeptypes.Library, []godeptypes.Dependency, []string, error) {
+	var lock lockFile
+	if err := json.NewDecoder(r).Decode(&lock); err != nil {
+		return nil, nil, nil, xerrors.Errorf("decode error: %w", err)
+	}
+
+	dev := map[string]struct{}{}
+	for _, pkg := range lock.PackagesDev {
+		dev[pkg.Name] = struct{}{}
+	}
+	libs, deps, devIDs := toLibraries(append(lock.Packages, lock.PackagesDev...), dev)
+	return libs, deps, devIDs, nil
+}
+
+// parseInstalledJSON parses installed.json of Composer 1 and 2.
+// Packages in "dev-package-names" are returned as development dependencies.
+// Composer 1 doesn't record them.
+func parseInstalledJSON(r io.Reader) ([]godeptypes.Library, []godeptypes.Dependency, []string, error) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, nil, nil, xerrors.Errorf("decode error: %w", err)
+	}
+
+	var installed installedFile
+	if err := json.Unmarshal(raw, &installed); err != nil {
+		// Composer 1
+		if err = json.Unmarshal(raw, &installed.Packages); err != nil {
+			return nil, nil, nil, xerrors.Errorf("decode error: %w", err)
+		}
+	}
+
+	dev := map[string]struct{}{}
+	for _, name := range installed.DevPackageNames {
+		dev[name] = struct{}{}
+	}
+	libs, deps, devIDs := toLibraries(installed.Packages, dev)
+	return libs, deps, devIDs, nil
+}
+
+// toLibraries converts packages into libraries with the dependency graph.
+// Platform packages such as "php" and "ext-json" are provided by the environment,
+// so they are not included in the dependencies.
+func toLibraries(pkgs []packageInfo, dev map[string]struct{}) ([]godeptypes.Library, []godeptypes.Dependency, []string) {
+	versions := map[string]string{}
+	for _, pkg := range pkgs {
+		versions[pkg.Name] = pkg.Version
+	}
+
+	var libs []godeptypes.Library
+	var deps []godeptypes.Dependency
+	var devIDs []string
+	for _, pkg := range pkgs {
+		id := pkgID(pkg.Name, pkg.Version)
+		if _, ok := dev[pkg.Name]; ok {
+			devIDs = append(devIDs, id)
+		}
+		libs = append(libs, godeptypes.Library{
+			ID:      id,
+			Name:    pkg.Name,
+			Version: pkg.Version,
+			// Multiple licenses mean the package is dual-licensed.
+			// ref. https://getcomposer.org/doc/04-schema.md#license
+			License: strings.Join(pkg.License, " OR "),
+		})
+
+		var dependsOn []string
+		for name := range pkg.Require {
+			if isPlatformPackage(name) {
+				continue
+			}
+			// Packages replaced or provided by others are not installed
+			ver, ok := versions[name]
+			if !ok {
+				continue
+			}
+			dependsOn = append(dependsOn, pkgID(name, ver))
+		}
+		if len(dependsOn) > 0 {
+			sort.Strings(dependsOn)
+			deps = append(deps, godeptypes.Dependency{
+				ID:        id,
+				DependsOn: dependsOn,
+			})
+		}
+	}
+	return libs, deps, devIDs
+}
+
+// isPlatformPackage reports whether the package is a platform package.
+// Platform packages don't have vendor names, e.g. "php", "ext-json", "lib-openssl" and "composer-plugin-api".
+// ref. https://getcomposer.org/doc/01-basic-usage.md#platform-packages
+func isPlatformPackage(name string) bool {
+	return !strings.Contains(name, "/")
+}
+
+func pkgID(name, version string) string {
+	return fmt.Sprintf("%s@%s", name, version)
+}
@@ -0,0 +1,137 @@
+package composer
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+)
+
+func Test_parse(t *testing.T) {
+	guzzleLibs := []godeptypes.Library{
+		{
+			ID:      "guzzlehttp/guzzle@7.4.5",
+			Name:    "guzzlehttp/guzzle",
+			Version: "7.4.5",
+			License: "MIT",
+		},
+		{
+			ID:      "guzzlehttp/promises@1.5.1",
+			Name:    "guzzlehttp/promises",
+			Version: "1.5.1",
+			License: "MIT",
+		},
+	}
+	guzzleDeps := []godeptypes.Dependency{
+		{
+			ID:        "guzzlehttp/guzzle@7.4.5",
+			DependsOn: []string{"guzzlehttp/promises@1.5.1"},
+		},
+	}
+
+	phpunitLib := godeptypes.Library{
+		ID:      "phpunit/phpunit@9.5.21",
+		Name:    "phpunit/phpunit",
+		Version: "9.5.21",
+		License: "BSD-3-Clause",
+	}
+
+	tests := []struct {
+		name       string
+		file       string
+		wantLibs   []godeptypes.Library
+		wantDeps   []godeptypes.Dependency
+		wantDevIDs []string
+		wantErr    string
+	}{
+		{
+			name: "composer.lock",
+			file: "testdata/composer.lock",
+			wantLibs: append(guzzleLibs,
+				godeptypes.Library{
+					ID:      "symfony/polyfill-mbstring@v1.26.0",
+					Name:    "symfony/polyfill-mbstring",
+					Version: "v1.26.0",
+					License: "MIT OR Apache-2.0",
+				},
+				phpunitLib,
+			),
+			wantDeps:   guzzleDeps,
+			wantDevIDs: []string{"phpunit/phpunit@9.5.21"},
+		},
+		{
+			name:       "installed.json of Composer 2",
+			file:       "testdata/composer2/vendor/composer/installed.json",
+			wantLibs:   append(guzzleLibs, phpunitLib),
+			wantDeps:   guzzleDeps,
+			wantDevIDs: []string{"phpunit/phpunit@9.5.21"},
+		},
+		{
+			name:     "installed.json of Composer 1",
+			file:     "testdata/composer1/vendor/composer/installed.json",
+			wantLibs: guzzleLibs,
+			wantDeps: guzzleDeps,
+		},
+		{
+			name:    "broken",
+			file:    "testdata/broken.lock",
+			wantErr: "decode error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.file)
+			require.NoError(t, err)
+			defer f.Close()
+
+			parse := parseLockFile
+			if isInstalledJSON(tt.file) {
+				parse = parseInstalledJSON
+			}
+
+			gotLibs, gotDeps, gotDevIDs, err := parse(f)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLibs, gotLibs)
+			assert.Equal(t, tt.wantDeps, gotDeps)
+			assert.Equal(t, tt.wantDevIDs, gotDevIDs)
+		})
+	}
+}
+
+func Test_composerLibraryAnalyzer_Required(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		want     bool
+	}{
+		{
+			name:     "composer.lock",
+			filePath: "app/composer.lock",
+			want:     true,
+		},
+		{
+			name:     "installed.json",
+			filePath: "var/www/html/vendor/composer/installed.json",
+			want:     true,
+		},
+		{
+			name:     "other installed.json",
+			filePath: "app/installed.json",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := composerLibraryAnalyzer{}
+			assert.Equal(t, tt.want, a.Required(tt.filePath, nil))
+		})
+	}
+}
@@ -0,0 +1,329 @@
+package packaging
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	ftypes "github.com/aquasecurity/fanal/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/types"
+
+	// The analyzer of fanal must be registered first so that it is overridden by this one
+	_ "github.com/aquasecurity/fanal/analyzer/language/python/packaging"
+)
+
+const version = 2
+
+const directURL = ".dist-info/direct_url.json"
+
+var (
+	requiredFiles = []string{
+		// .egg format
+		// https://setuptools.readthedocs.io/en/latest/deprecated/python_eggs.html#eggs-and-their-formats
+		".egg", // zip format
+		"EGG-INFO/PKG-INFO",
+
+		// .egg-info format: .egg-info can be a file or directory
+		// https://setuptools.readthedocs.io/en/latest/deprecated/python_eggs.html#eggs-and-their-formats
+		".egg-info",
+		".egg-info/PKG-INFO",
+
+		// wheel
+		".dist-info/METADATA",
+
+		// The origin of the package installed from a URL
+		// https://packaging.python.org/en/latest/specifications/direct-url/
+		directURL,
+	}
+
+	// e.g. "License :: OSI Approved :: MIT License"
+	licenseClassifier = "License :: "
+
+	nameSeparators = regexp.MustCompile(`[-_.]+`)
+)
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&packagingAnalyzer{})
+}
+
+type directURLInfo struct {
+	URL     string `json:"url"`
+	DirInfo *struct {
+		Editable bool `json:"editable"`
+	} `json:"dir_info"`
+}
+
+// packagingAnalyzer analyzes egg and wheel metadata of installed packages.
+// Unlike the analyzer of fanal, requirements are recorded so that the dependency graph can be built
+// from packages installed in the same directory. See Resolve.
+type packagingAnalyzer struct{}
+
+func (a packagingAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	if strings.HasSuffix(filepath.ToSlash(input.FilePath), directURL) {
+		return a.analyzeDirectURL(input)
+	}
+
+	r := io.Reader(input.Content)
+
+	// .egg file is zip format and PKG-INFO needs to be extracted from the zip file.
+	if strings.HasSuffix(input.FilePath, ".egg") {
+		pkginfoInZip, err := a.analyzeEggZip(input.Content, input.Info.Size())
+		if err != nil {
+			return nil, xerrors.Errorf("egg analysis error: %w", err)
+		}
+
+		// Egg archive may not contain required files, then we will get nil. Skip this archives
+		if pkginfoInZip == nil {
+			return nil, nil
+		}
+
+		r = pkginfoInZip
+	}
+
+	pkg, err := parse(r)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to parse %s: %w", input.FilePath, err)
+	}
+	pkg.FilePath = input.FilePath
+
+	return &analyzer.AnalysisResult{
+		Applications: []ftypes.Application{
+			{
+				Type:      ftypes.PythonPkg,
+				FilePath:  input.FilePath,
+				Libraries: []ftypes.Package{pkg},
+			},
+		},
+	}, nil
+}
+
+// analyzeDirectURL records packages installed from local directories, including editable installs.
+// They are the projects themselves rather than the distributions on PyPI, so they must not be matched against advisories.
+// Packages installed from VCS or archive URLs are still reported.
+func (a packagingAnalyzer) analyzeDirectURL(input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	var info directURLInfo
+	if err := json.NewDecoder(input.Content).Decode(&info); err != nil {
+		return nil, xerrors.Errorf("unable to parse %s: %w", input.FilePath, err)
+	}
+	if info.DirInfo == nil {
+		return nil, nil
+	}
+	return &analyzer.AnalysisResult{
+		Applications: []ftypes.Application{
+			{
+				Type:     types.PythonLocalPkg,
+				FilePath: input.FilePath,
+			},
+		},
+	}, nil
+}
+
+func (a packagingAnalyzer) analyzeEggZip(r io.ReaderAt, size int64) (io.Reader, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, xerrors.Errorf("zip reader error: %w", err)
+	}
+
+	for _, file := range zr.File {
+		if !a.Required(file.Name, nil) {
+			continue
+		}
+
+		f, err := file.Open()
+		if err != nil {
+			return nil, xerrors.Errorf("file %s open error: %w", file.Name, err)
+		}
+		defer f.Close()
+
+		b, err := io.ReadAll(f)
+		if err != nil {
+			return nil, xerrors.Errorf("file %s read error: %w", file.Name, err)
+		}
+		return bytes.NewReader(b), nil
+	}
+
+	return nil, nil
+}
+
+func (a packagingAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	// For Windows
+	filePath = filepath.ToSlash(filePath)
+
+	for _, r := range requiredFiles {
+		if strings.HasSuffix(filePath, r) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a packagingAnalyzer) Type() analyzer.Type {
+	return analyzer.TypePythonPkg
+}
+
+func (a packagingAnalyzer) Version() int {
+	return version
+}
+
+// parse parses egg and wheel metadata, i.e. PKG-INFO and METADATA.
+// DependsOn holds the normalized names of requirements including those of extras,
+// and they are replaced with package IDs by Resolve.
+func parse(r io.Reader) (ftypes.Package, error) {
+	rd := textproto.NewReader(bufio.NewReader(r))
+	h, err := rd.ReadMIMEHeader()
+	if err != nil && err != io.EOF {
+		return ftypes.Package{}, xerrors.Errorf("read MIME error: %w", err)
+	}
+
+	name, ver := h.Get("Name"), h.Get("Version")
+
+	var dependsOn []string
+	for _, req := range h.Values("Requires-Dist") {
+		// The same project can be required with different markers
+		if n := NormalizeName(requirementName(req)); n != "" && !slices.Contains(dependsOn, n) {
+			dependsOn = append(dependsOn, n)
+		}
+	}
+	sort.Strings(dependsOn)
+
+	return ftypes.Package{
+		ID:        pkgID(name, ver),
+		Name:      name,
+		Version:   ver,
+		License:   license(h),
+		DependsOn: dependsOn,
+	}, nil
+}
+
+// license returns "License-Expression" or "License", and falls back to the license classifiers.
+func license(h textproto.MIMEHeader) string {
+	if l := h.Get("License-Expression"); l != "" {
+		return l
+	}
+	if l := h.Get("License"); l != "" && l != "UNKNOWN" {
+		return l
+	}
+
+	var licenses []string
+	for _, c := range h.Values("Classifier") {
+		if !strings.HasPrefix(c, licenseClassifier) {
+			continue
+		}
+		// Use the last part, e.g. "MIT License" of "License :: OSI Approved :: MIT License"
+		parts := strings.Split(c, " :: ")
+		if l := parts[len(parts)-1]; l != "OSI Approved" {
+			licenses = append(licenses, l)
+		}
+	}
+	return strings.Join(licenses, ", ")
+}
+
+// requirementName returns the project name of the requirement.
+// e.g. `PySocks (!=1.5.7,>=1.5.6) ; extra == 'socks'` => "PySocks"
+func requirementName(req string) string {
+	i := strings.IndexAny(req, " ;<>=!~([@")
+	if i == -1 {
+		return strings.TrimSpace(req)
+	}
+	return strings.TrimSpace(req[:i])
+}
+
+// NormalizeName normalizes the project name.
+// ref. https://packaging.python.org/en/latest/specifications/name-normalization/
+func NormalizeName(name string) string {
+	return strings.ToLower(nameSeparators.ReplaceAllString(name, "-"))
+}
+
+// Resolve drops Python packages installed from local directories and
+// replaces the requirement names of Python packages with the IDs of the packages
+// installed in the same directory, e.g. site-packages.
+// Requirements which are not installed, such as those of extras not requested, are dropped.
+func Resolve(apps []ftypes.Application) []ftypes.Application {
+	localPkgs := map[string]struct{}{}
+	for _, app := range apps {
+		if app.Type == types.PythonLocalPkg {
+			localPkgs[path.Dir(filepath.ToSlash(app.FilePath))] = struct{}{}
+		}
+	}
+
+	var result []ftypes.Application
+	for _, app := range apps {
+		switch app.Type {
+		case types.PythonLocalPkg:
+			continue
+		case ftypes.PythonPkg:
+			app.Libraries = resolve(app.Libraries, localPkgs)
+			if len(app.Libraries) == 0 {
+				continue
+			}
+		}
+		result = append(result, app)
+	}
+	return result
+}
+
+func resolve(pkgs []ftypes.Package, localPkgs map[string]struct{}) []ftypes.Package {
+	// site directory/normalized name => package ID
+	ids := map[string]string{}
+	var installed []ftypes.Package
+	for _, pkg := range pkgs {
+		metadataDir := path.Dir(filepath.ToSlash(pkg.FilePath))
+		if _, ok := localPkgs[metadataDir]; ok {
+			continue
+		}
+		ids[siteDir(pkg.FilePath)+"/"+NormalizeName(pkg.Name)] = pkg.ID
+		installed = append(installed, pkg)
+	}
+
+	for i, pkg := range installed {
+		var dependsOn []string
+		for _, name := range pkg.DependsOn {
+			if id, ok := ids[siteDir(pkg.FilePath)+"/"+name]; ok && id != pkg.ID {
+				dependsOn = append(dependsOn, id)
+			}
+		}
+		sort.Strings(dependsOn)
+		installed[i].DependsOn = dependsOn
+	}
+	return installed
+}
+
+// siteDir returns the directory where the package is installed.
+// e.g.
+//
+//	usr/lib/python3.9/site-packages/requests-2.26.0.dist-info/METADATA
+//	usr/lib/python3.9/site-packages/requests-2.26.0-py3.9.egg-info/PKG-INFO
+//	usr/lib/python3.9/site-packages/requests-2.26.0-py3.9.egg-info
+//	usr/lib/python3.9/site-packages/requests-2.26.0-py3.9.egg
+//	usr/lib/python3.9/site-packages/requests-2.26.0-py3.9.egg/EGG-INFO/PKG-INFO
+func siteDir(filePath string) string {
+	filePath = filepath.ToSlash(filePath)
+	switch {
+	case strings.HasSuffix(filePath, "EGG-INFO/PKG-INFO"):
+		return path.Dir(path.Dir(path.Dir(filePath)))
+	case strings.HasSuffix(filePath, "/METADATA"), strings.HasSuffix(filePath, "/PKG-INFO"):
+		return path.Dir(path.Dir(filePath))
+	default:
+		return path.Dir(filePath)
+	}
+}
+
+func pkgID(name, version string) string {
+	return fmt.Sprintf("%s@%s", name, version)
+}
@@ -0,0 +1,225 @@
+package packaging
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func Test_packagingAnalyzer_Analyze(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		want    *analyzer.AnalysisResult
+		wantErr string
+	}{
+		{
+			name: "dist-info with extras",
+			file: "testdata/requests.dist-info/METADATA",
+			want: &analyzer.AnalysisResult{
+				Applications: []ftypes.Application{
+					{
+						Type:     ftypes.PythonPkg,
+						FilePath: "testdata/requests.dist-info/METADATA",
+						Libraries: []ftypes.Package{
+							{
+								ID:      "requests@2.28.1",
+								Name:    "requests",
+								Version: "2.28.1",
+								License: "Apache 2.0",
+								DependsOn: []string{
+									"certifi",
+									"chardet",
+									"charset-normalizer",
+									"idna",
+									"pysocks",
+									"urllib3",
+								},
+								FilePath: "testdata/requests.dist-info/METADATA",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "license classifiers",
+			file: "testdata/classifiers.dist-info/METADATA",
+			want: &analyzer.AnalysisResult{
+				Applications: []ftypes.Application{
+					{
+						Type:     ftypes.PythonPkg,
+						FilePath: "testdata/classifiers.dist-info/METADATA",
+						Libraries: []ftypes.Package{
+							{
+								ID:        "Flask_SQLAlchemy@2.5.1",
+								Name:      "Flask_SQLAlchemy",
+								Version:   "2.5.1",
+								License:   "BSD License",
+								DependsOn: []string{"flask", "sqlalchemy"},
+								FilePath:  "testdata/classifiers.dist-info/METADATA",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "egg-info",
+			file: "testdata/six.egg-info/PKG-INFO",
+			want: &analyzer.AnalysisResult{
+				Applications: []ftypes.Application{
+					{
+						Type:     ftypes.PythonPkg,
+						FilePath: "testdata/six.egg-info/PKG-INFO",
+						Libraries: []ftypes.Package{
+							{
+								ID:       "six@1.16.0",
+								Name:     "six",
+								Version:  "1.16.0",
+								License:  "MIT",
+								FilePath: "testdata/six.egg-info/PKG-INFO",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "editable install",
+			file: "testdata/editable.dist-info/direct_url.json",
+			want: &analyzer.AnalysisResult{
+				Applications: []ftypes.Application{
+					{
+						Type:     types.PythonLocalPkg,
+						FilePath: "testdata/editable.dist-info/direct_url.json",
+					},
+				},
+			},
+		},
+		{
+			name: "VCS install",
+			file: "testdata/vcs.dist-info/direct_url.json",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.file)
+			require.NoError(t, err)
+			defer f.Close()
+
+			a := packagingAnalyzer{}
+			got, err := a.Analyze(context.Background(), analyzer.AnalysisInput{
+				FilePath: tt.file,
+				Content:  f,
+			})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	apps := []ftypes.Application{
+		{
+			Type: ftypes.PythonPkg,
+			Libraries: []ftypes.Package{
+				{
+					ID:        "requests@2.28.1",
+					Name:      "requests",
+					Version:   "2.28.1",
+					DependsOn: []string{"certifi", "pysocks", "urllib3"},
+					FilePath:  "usr/lib/python3.9/site-packages/requests-2.28.1.dist-info/METADATA",
+				},
+				{
+					ID:       "PySocks@1.7.1",
+					Name:     "PySocks",
+					Version:  "1.7.1",
+					FilePath: "usr/lib/python3.9/site-packages/PySocks-1.7.1.dist-info/METADATA",
+				},
+				{
+					ID:       "urllib3@1.26.12",
+					Name:     "urllib3",
+					Version:  "1.26.12",
+					FilePath: "usr/lib/python3.9/site-packages/urllib3-1.26.12-py3.9.egg-info/PKG-INFO",
+				},
+				{
+					// installed in another virtual environment
+					ID:       "certifi@2022.9.24",
+					Name:     "certifi",
+					Version:  "2022.9.24",
+					FilePath: "opt/venv/lib/python3.9/site-packages/certifi-2022.9.24.dist-info/METADATA",
+				},
+				{
+					ID:        "app@0.1.0",
+					Name:      "app",
+					Version:   "0.1.0",
+					DependsOn: []string{"requests"},
+					FilePath:  "usr/lib/python3.9/site-packages/app-0.1.0.dist-info/METADATA",
+				},
+			},
+		},
+		{
+			Type:     types.PythonLocalPkg,
+			FilePath: "usr/lib/python3.9/site-packages/app-0.1.0.dist-info/direct_url.json",
+		},
+		{
+			Type:     ftypes.Pip,
+			FilePath: "app/requirements.txt",
+			Libraries: []ftypes.Package{
+				{
+					Name:    "requests",
+					Version: "2.28.1",
+				},
+			},
+		},
+	}
+
+	want := []ftypes.Application{
+		{
+			Type: ftypes.PythonPkg,
+			Libraries: []ftypes.Package{
+				{
+					ID:        "requests@2.28.1",
+					Name:      "requests",
+					Version:   "2.28.1",
+					DependsOn: []string{"PySocks@1.7.1", "urllib3@1.26.12"},
+					FilePath:  "usr/lib/python3.9/site-packages/requests-2.28.1.dist-info/METADATA",
+				},
+				{
+					ID:       "PySocks@1.7.1",
+					Name:     "PySocks",
+					Version:  "1.7.1",
+					FilePath: "usr/lib/python3.9/site-packages/PySocks-1.7.1.dist-info/METADATA",
+				},
+				{
+					ID:       "urllib3@1.26.12",
+					Name:     "urllib3",
+					Version:  "1.26.12",
+					FilePath: "usr/lib/python3.9/site-packages/urllib3-1.26.12-py3.9.egg-info/PKG-INFO",
+				},
+				{
+					ID:       "certifi@2022.9.24",
+					Name:     "certifi",
+					Version:  "2022.9.24",
+					FilePath: "opt/venv/lib/python3.9/site-packages/certifi-2022.9.24.dist-info/METADATA",
+				},
+			},
+		},
+		apps[2],
+	}
+	assert.Equal(t, want, Resolve(apps))
+}
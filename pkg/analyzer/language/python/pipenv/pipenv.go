@@ -0,0 +1,109 @@
+package pipenv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/types"
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/analyzer/language"
+
+	// The analyzer of fanal must be registered first so that it is overridden by this one
+	_ "github.com/aquasecurity/fanal/analyzer/language/python/pipenv"
+)
+
+const version = 2
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&pipenvLibraryAnalyzer{})
+}
+
+type dependency struct {
+	Version string `json:"version"`
+}
+
+type lockfile struct {
+	Default map[string]dependency `json:"default"`
+	Develop map[string]dependency `json:"develop"`
+}
+
+// pipenvLibraryAnalyzer parses Pipfile.lock.
+// Unlike the analyzer of fanal, packages in "develop" are recorded as development dependencies.
+type pipenvLibraryAnalyzer struct{}
+
+func (a pipenvLibraryAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	libs, devIDs, err := parse(input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to parse %s: %w", types.PipfileLock, err)
+	}
+	return language.ToAnalysisResult(types.Pipenv, input.FilePath, "", libs, nil, devIDs), nil
+}
+
+func (a pipenvLibraryAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	return filepath.Base(filePath) == types.PipfileLock
+}
+
+func (a pipenvLibraryAnalyzer) Type() analyzer.Type {
+	return analyzer.TypePipenv
+}
+
+func (a pipenvLibraryAnalyzer) Version() int {
+	return version
+}
+
+// parse returns libraries and the IDs of development dependencies.
+// Packages in both "default" and "develop" are regarded as main dependencies.
+// Pipfile.lock doesn't have the dependency graph.
+func parse(r io.Reader) ([]godeptypes.Library, []string, error) {
+	var lock lockfile
+	if err := json.NewDecoder(r).Decode(&lock); err != nil {
+		return nil, nil, xerrors.Errorf("json decode error: %w", err)
+	}
+
+	var libs []godeptypes.Library
+	var devIDs []string
+	for _, section := range []struct {
+		deps map[string]dependency
+		dev  bool
+	}{
+		{lock.Default, false},
+		{lock.Develop, true},
+	} {
+		for name, dep := range section.deps {
+			// Packages without versions, such as those installed from VCS, cannot be identified
+			ver := strings.TrimPrefix(dep.Version, "==")
+			if ver == "" {
+				continue
+			}
+			if _, ok := lock.Default[name]; section.dev && ok {
+				continue
+			}
+
+			id := fmt.Sprintf("%s@%s", name, ver)
+			libs = append(libs, godeptypes.Library{
+				ID:      id,
+				Name:    name,
+				Version: ver,
+			})
+			if section.dev {
+				devIDs = append(devIDs, id)
+			}
+		}
+	}
+
+	sort.Slice(libs, func(i, j int) bool {
+		return libs[i].ID < libs[j].ID
+	})
+	sort.Strings(devIDs)
+	return libs, devIDs, nil
+}
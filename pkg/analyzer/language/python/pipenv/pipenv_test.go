@@ -0,0 +1,40 @@
+package pipenv
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+)
+
+func Test_parse(t *testing.T) {
+	f, err := os.Open("testdata/Pipfile.lock")
+	require.NoError(t, err)
+	defer f.Close()
+
+	gotLibs, gotDevIDs, err := parse(f)
+	require.NoError(t, err)
+
+	wantLibs := []godeptypes.Library{
+		{
+			ID:      "certifi@2022.9.24",
+			Name:    "certifi",
+			Version: "2022.9.24",
+		},
+		{
+			ID:      "pytest@7.1.3",
+			Name:    "pytest",
+			Version: "7.1.3",
+		},
+		{
+			ID:      "requests@2.28.1",
+			Name:    "requests",
+			Version: "2.28.1",
+		},
+	}
+	assert.Equal(t, wantLibs, gotLibs)
+	assert.Equal(t, []string{"pytest@7.1.3"}, gotDevIDs)
+}
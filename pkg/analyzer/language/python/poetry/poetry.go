@@ -0,0 +1,118 @@
+package poetry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/BurntSushi/toml"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/types"
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/analyzer/language"
+	"github.com/aquasecurity/trivy/pkg/analyzer/language/python/packaging"
+
+	// The analyzer of fanal must be registered first so that it is overridden by this one
+	_ "github.com/aquasecurity/fanal/analyzer/language/python/poetry"
+)
+
+const version = 2
+
+const devCategory = "dev"
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&poetryLibraryAnalyzer{})
+}
+
+type lockfile struct {
+	Packages []struct {
+		Name     string `toml:"name"`
+		Version  string `toml:"version"`
+		Category string `toml:"category"`
+
+		// Each value is a version constraint, a table with "version" and "markers",
+		// or an array of tables for different markers.
+		Dependencies map[string]interface{} `toml:"dependencies"`
+	} `toml:"package"`
+}
+
+// poetryLibraryAnalyzer parses poetry.lock.
+// Unlike the analyzer of fanal, packages have dependencies, and packages in the "dev" category are
+// recorded as development dependencies.
+type poetryLibraryAnalyzer struct{}
+
+func (a poetryLibraryAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	libs, deps, devIDs, err := parse(input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to parse %s: %w", types.PoetryLock, err)
+	}
+	return language.ToAnalysisResult(types.Poetry, input.FilePath, "", libs, deps, devIDs), nil
+}
+
+func (a poetryLibraryAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	return filepath.Base(filePath) == types.PoetryLock
+}
+
+func (a poetryLibraryAnalyzer) Type() analyzer.Type {
+	return analyzer.TypePoetry
+}
+
+func (a poetryLibraryAnalyzer) Version() int {
+	return version
+}
+
+// parse returns libraries, the dependency graph and the IDs of development dependencies.
+// Poetry 1.5 and later don't write categories, so all the packages are regarded as main dependencies.
+func parse(r io.Reader) ([]godeptypes.Library, []godeptypes.Dependency, []string, error) {
+	var lock lockfile
+	if _, err := toml.DecodeReader(r, &lock); err != nil {
+		return nil, nil, nil, xerrors.Errorf("toml decode error: %w", err)
+	}
+
+	// normalized name => IDs
+	// The same package can be locked with multiple versions for different markers.
+	ids := map[string][]string{}
+	for _, pkg := range lock.Packages {
+		name := packaging.NormalizeName(pkg.Name)
+		ids[name] = append(ids[name], pkgID(pkg.Name, pkg.Version))
+	}
+
+	var libs []godeptypes.Library
+	var deps []godeptypes.Dependency
+	var devIDs []string
+	for _, pkg := range lock.Packages {
+		id := pkgID(pkg.Name, pkg.Version)
+		libs = append(libs, godeptypes.Library{
+			ID:      id,
+			Name:    pkg.Name,
+			Version: pkg.Version,
+		})
+		if pkg.Category == devCategory {
+			devIDs = append(devIDs, id)
+		}
+
+		// Optional dependencies are locked only when the extras are requested
+		var dependsOn []string
+		for name := range pkg.Dependencies {
+			dependsOn = append(dependsOn, ids[packaging.NormalizeName(name)]...)
+		}
+		if len(dependsOn) > 0 {
+			sort.Strings(dependsOn)
+			deps = append(deps, godeptypes.Dependency{
+				ID:        id,
+				DependsOn: dependsOn,
+			})
+		}
+	}
+	return libs, deps, devIDs, nil
+}
+
+func pkgID(name, version string) string {
+	return fmt.Sprintf("%s@%s", name, version)
+}
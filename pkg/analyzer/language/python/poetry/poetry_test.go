@@ -0,0 +1,93 @@
+package poetry
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+)
+
+func Test_parse(t *testing.T) {
+	tests := []struct {
+		name       string
+		file       string
+		wantLibs   []godeptypes.Library
+		wantDeps   []godeptypes.Dependency
+		wantDevIDs []string
+		wantErr    string
+	}{
+		{
+			name: "happy path",
+			file: "testdata/poetry.lock",
+			wantLibs: []godeptypes.Library{
+				{
+					ID:      "certifi@2022.9.24",
+					Name:    "certifi",
+					Version: "2022.9.24",
+				},
+				{
+					ID:      "charset-normalizer@2.1.1",
+					Name:    "charset-normalizer",
+					Version: "2.1.1",
+				},
+				{
+					ID:      "iniconfig@1.1.1",
+					Name:    "iniconfig",
+					Version: "1.1.1",
+				},
+				{
+					ID:      "pytest@7.1.3",
+					Name:    "pytest",
+					Version: "7.1.3",
+				},
+				{
+					ID:      "requests@2.28.1",
+					Name:    "requests",
+					Version: "2.28.1",
+				},
+				{
+					ID:      "urllib3@1.26.12",
+					Name:    "urllib3",
+					Version: "1.26.12",
+				},
+			},
+			wantDeps: []godeptypes.Dependency{
+				{
+					ID:        "pytest@7.1.3",
+					DependsOn: []string{"iniconfig@1.1.1"},
+				},
+				{
+					ID:        "requests@2.28.1",
+					DependsOn: []string{"certifi@2022.9.24", "charset-normalizer@2.1.1", "urllib3@1.26.12"},
+				},
+			},
+			wantDevIDs: []string{"iniconfig@1.1.1", "pytest@7.1.3"},
+		},
+		{
+			name:    "broken",
+			file:    "testdata/broken.lock",
+			wantErr: "toml decode error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.file)
+			require.NoError(t, err)
+			defer f.Close()
+
+			gotLibs, gotDeps, gotDevIDs, err := parse(f)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLibs, gotLibs)
+			assert.Equal(t, tt.wantDeps, gotDeps)
+			assert.Equal(t, tt.wantDevIDs, gotDevIDs)
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package gemspec
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	ftypes "github.com/aquasecurity/fanal/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+
+	// The analyzer of fanal must be registered first so that it is overridden by this one
+	_ "github.com/aquasecurity/fanal/analyzer/language/ruby/gemspec"
+)
+
+const version = 2
+
+const specificationsDir = "/specifications/"
+
+var (
+	// e.g. usr/local/bundle/specifications/rack-2.2.4.gemspec
+	//      usr/local/lib/ruby/gems/3.1.0/specifications/default/json-2.6.1.gemspec
+	fileRegex = regexp.MustCompile(`.*/specifications/.+\.gemspec$`)
+
+	// e.g. Gem::Specification.new do |s|
+	newVarRegexp = regexp.MustCompile(`Gem::Specification\.new\s+do\s+\|(\w+)\|`)
+
+	// e.g. s.name = "rack".freeze
+	//      s.name = %q{rack}
+	attrRegexp = regexp.MustCompile(`^(\w+)\.(name|version|license|licenses)\s*=\s*(.+)$`)
+
+	// e.g. s.add_runtime_dependency(%q<concurrent-ruby>.freeze, ["~> 1.0"])
+	//      s.add_development_dependency(%q<minitest>.freeze, [">= 5.1"])
+	//      s.add_dependency "rack", ">= 1.0"
+	dependencyRegexp = regexp.MustCompile(`^(\w+)\.add_(runtime_|development_)?dependency[\s(]+(%q<[^>]+>|%q\{[^}]+\}|"[^"]+"|'[^']+')`)
+
+	// e.g. "MIT".freeze, %q{BSD-2-Clause}
+	stringRegexp = regexp.MustCompile(`%q<[^>]+>|%q\{[^}]+\}|"[^"]+"|'[^']+'`)
+)
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&gemspecLibraryAnalyzer{})
+}
+
+// gemspecLibraryAnalyzer analyzes specifications of installed gems.
+// Unlike the analyzer of fanal, the old notation of rubygems, e.g. %q{rack}, is supported,
+// and runtime dependencies are recorded so that the dependency graph can be built from gems
+// installed in the same gem home. See Resolve.
+type gemspecLibraryAnalyzer struct{}
+
+func (a gemspecLibraryAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	pkg, err := parse(input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to parse %s: %w", input.FilePath, err)
+	}
+	pkg.FilePath = input.FilePath
+
+	return &analyzer.AnalysisResult{
+		Applications: []ftypes.Application{
+			{
+				Type:      ftypes.GemSpec,
+				FilePath:  input.FilePath,
+				Libraries: []ftypes.Package{pkg},
+			},
+		},
+	}, nil
+}
+
+func (a gemspecLibraryAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	return fileRegex.MatchString(filepath.ToSlash(filePath))
+}
+
+func (a gemspecLibraryAnalyzer) Type() analyzer.Type {
+	return analyzer.TypeGemSpec
+}
+
+func (a gemspecLibraryAnalyzer) Version() int {
+	return version
+}
+
+// parse parses the specification of the installed gem, which is normalized by rubygems.
+// DependsOn holds the names of runtime dependencies, and they are replaced with package IDs by Resolve.
+// Development dependencies are not installed with the gem, so they are excluded.
+// Specifications written by old versions of rubygems also declare all the dependencies including development ones
+// with "add_dependency" as a fallback, so they are used only if no typed dependencies are declared.
+func parse(r io.Reader) (ftypes.Package, error) {
+	var newVar, name, ver string
+	var licenses, runtimeDeps, untypedDeps []string
+	var typed bool
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if m := newVarRegexp.FindStringSubmatch(line); m != nil {
+			newVar = m[1]
+			continue
+		} else if newVar == "" {
+			continue
+		}
+
+		if m := dependencyRegexp.FindStringSubmatch(line); m != nil && m[1] == newVar {
+			dep := unquote(m[3])
+			switch m[2] {
+			case "runtime_":
+				typed = true
+				// Old specifications declare the same dependencies for each version of rubygems
+				if !slices.Contains(runtimeDeps, dep) {
+					runtimeDeps = append(runtimeDeps, dep)
+				}
+			case "development_":
+				typed = true
+			default:
+				if !slices.Contains(untypedDeps, dep) {
+					untypedDeps = append(untypedDeps, dep)
+				}
+			}
+			continue
+		}
+
+		m := attrRegexp.FindStringSubmatch(line)
+		if m == nil || m[1] != newVar {
+			continue
+		}
+		switch m[2] {
+		case "name":
+			name = unquote(m[3])
+		case "version":
+			ver = unquote(m[3])
+		case "license", "licenses":
+			// https://guides.rubygems.org/specification-reference/#licenses=
+			licenses = nil
+			for _, l := range stringRegexp.FindAllString(m[3], -1) {
+				licenses = append(licenses, unquote(l))
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return ftypes.Package{}, xerrors.Errorf("scan error: %w", err)
+	}
+
+	if name == "" || ver == "" {
+		return ftypes.Package{}, xerrors.New("name or version not found")
+	}
+
+	dependsOn := runtimeDeps
+	if !typed {
+		dependsOn = untypedDeps
+	}
+	sort.Strings(dependsOn)
+
+	return ftypes.Package{
+		ID:        pkgID(name, ver),
+		Name:      name,
+		Version:   ver,
+		License:   strings.Join(licenses, ", "),
+		DependsOn: dependsOn,
+	}, nil
+}
+
+// unquote returns the content of the Ruby string literal.
+// e.g. "rack".freeze, 'rack', %q{rack} and %q<rack> => rack
+func unquote(s string) string {
+	s = strings.TrimSuffix(strings.TrimSpace(s), ".freeze")
+	switch {
+	case strings.HasPrefix(s, "%q{"):
+		return strings.TrimSuffix(strings.TrimPrefix(s, "%q{"), "}")
+	case strings.HasPrefix(s, "%q<"):
+		return strings.TrimSuffix(strings.TrimPrefix(s, "%q<"), ">")
+	}
+	return strings.Trim(s, `'"`)
+}
+
+// Resolve replaces the dependency names of installed gems with the IDs of the gems
+// installed in the same gem home, e.g. usr/local/bundle.
+// Multiple versions of a gem can be installed in the same gem home, and the activated one is
+// not known until runtime, so all of them are dependencies.
+// Gems installed both as default gems and regular ones with the same version are reported once.
+func Resolve(apps []ftypes.Application) []ftypes.Application {
+	for i, app := range apps {
+		if app.Type == ftypes.GemSpec {
+			apps[i].Libraries = resolve(app.Libraries)
+		}
+	}
+	return apps
+}
+
+func resolve(pkgs []ftypes.Package) []ftypes.Package {
+	// gem home/name => package IDs
+	ids := map[string][]string{}
+	// gem home/ID
+	seen := map[string]struct{}{}
+	var installed []ftypes.Package
+	for _, pkg := range pkgs {
+		home := gemHome(pkg.FilePath)
+		if _, ok := seen[home+"/"+pkg.ID]; ok {
+			continue
+		}
+		seen[home+"/"+pkg.ID] = struct{}{}
+
+		key := home + "/" + pkg.Name
+		ids[key] = append(ids[key], pkg.ID)
+		installed = append(installed, pkg)
+	}
+
+	for i, pkg := range installed {
+		var dependsOn []string
+		for _, name := range pkg.DependsOn {
+			dependsOn = append(dependsOn, ids[gemHome(pkg.FilePath)+"/"+name]...)
+		}
+		sort.Strings(dependsOn)
+		installed[i].DependsOn = dependsOn
+	}
+	return installed
+}
+
+// gemHome returns the directory where the gem is installed.
+// e.g. usr/local/bundle/specifications/rack-2.2.4.gemspec => usr/local/bundle
+func gemHome(filePath string) string {
+	filePath = filepath.ToSlash(filePath)
+	if i := strings.LastIndex(filePath, specificationsDir); i != -1 {
+		return filePath[:i]
+	}
+	return path.Dir(filePath)
+}
+
+func pkgID(name, version string) string {
+	return fmt.Sprintf("%s@%s", name, version)
+}
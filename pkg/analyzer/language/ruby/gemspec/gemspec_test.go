@@ -0,0 +1,260 @@
+package gemspec
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	ftypes "github.com/aquasecurity/fanal/types"
+)
+
+func Test_gemspecLibraryAnalyzer_Analyze(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		want    *analyzer.AnalysisResult
+		wantErr string
+	}{
+		{
+			name: "runtime dependencies",
+			file: "testdata/rails-7.0.4.gemspec",
+			want: &analyzer.AnalysisResult{
+				Applications: []ftypes.Application{
+					{
+						Type:     ftypes.GemSpec,
+						FilePath: "testdata/rails-7.0.4.gemspec",
+						Libraries: []ftypes.Package{
+							{
+								ID:        "rails@7.0.4",
+								Name:      "rails",
+								Version:   "7.0.4",
+								License:   "MIT",
+								DependsOn: []string{"actionpack", "activesupport", "bundler"},
+								FilePath:  "testdata/rails-7.0.4.gemspec",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "old notation",
+			file: "testdata/rake-0.8.7.gemspec",
+			want: &analyzer.AnalysisResult{
+				Applications: []ftypes.Application{
+					{
+						Type:     ftypes.GemSpec,
+						FilePath: "testdata/rake-0.8.7.gemspec",
+						Libraries: []ftypes.Package{
+							{
+								ID:       "rake@0.8.7",
+								Name:     "rake",
+								Version:  "0.8.7",
+								License:  "MIT",
+								FilePath: "testdata/rake-0.8.7.gemspec",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "untyped dependencies",
+			file: "testdata/sinatra-1.0.gemspec",
+			want: &analyzer.AnalysisResult{
+				Applications: []ftypes.Application{
+					{
+						Type:     ftypes.GemSpec,
+						FilePath: "testdata/sinatra-1.0.gemspec",
+						Libraries: []ftypes.Package{
+							{
+								ID:        "sinatra@1.0",
+								Name:      "sinatra",
+								Version:   "1.0",
+								DependsOn: []string{"rack"},
+								FilePath:  "testdata/sinatra-1.0.gemspec",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "broken",
+			file:    "testdata/broken.gemspec",
+			wantErr: "name or version not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.file)
+			require.NoError(t, err)
+			defer f.Close()
+
+			a := gemspecLibraryAnalyzer{}
+			got, err := a.Analyze(context.Background(), analyzer.AnalysisInput{
+				FilePath: tt.file,
+				Content:  f,
+			})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_gemspecLibraryAnalyzer_Required(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		want     bool
+	}{
+		{
+			name:     "installed gem",
+			filePath: "usr/local/bundle/specifications/rack-2.2.4.gemspec",
+			want:     true,
+		},
+		{
+			name:     "default gem",
+			filePath: "usr/local/lib/ruby/gems/3.1.0/specifications/default/json-2.6.1.gemspec",
+			want:     true,
+		},
+		{
+			name:     "vendored gem",
+			filePath: "app/vendor/bundle/ruby/3.1.0/specifications/rack-2.2.4.gemspec",
+			want:     true,
+		},
+		{
+			name:     "gemspec in the source",
+			filePath: "app/rack.gemspec",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := gemspecLibraryAnalyzer{}
+			assert.Equal(t, tt.want, a.Required(tt.filePath, nil))
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	apps := []ftypes.Application{
+		{
+			Type: ftypes.GemSpec,
+			Libraries: []ftypes.Package{
+				{
+					ID:        "rails@7.0.4",
+					Name:      "rails",
+					Version:   "7.0.4",
+					DependsOn: []string{"actionpack", "activesupport", "bundler"},
+					FilePath:  "usr/local/bundle/specifications/rails-7.0.4.gemspec",
+				},
+				{
+					ID:       "actionpack@7.0.4",
+					Name:     "actionpack",
+					Version:  "7.0.4",
+					FilePath: "usr/local/bundle/specifications/actionpack-7.0.4.gemspec",
+				},
+				{
+					ID:       "activesupport@7.0.4",
+					Name:     "activesupport",
+					Version:  "7.0.4",
+					FilePath: "usr/local/bundle/specifications/activesupport-7.0.4.gemspec",
+				},
+				{
+					ID:       "activesupport@6.1.7",
+					Name:     "activesupport",
+					Version:  "6.1.7",
+					FilePath: "usr/local/bundle/specifications/activesupport-6.1.7.gemspec",
+				},
+				{
+					// default gem
+					ID:       "bundler@2.3.7",
+					Name:     "bundler",
+					Version:  "2.3.7",
+					FilePath: "usr/local/bundle/specifications/default/bundler-2.3.7.gemspec",
+				},
+				{
+					// installed again with the same version
+					ID:       "bundler@2.3.7",
+					Name:     "bundler",
+					Version:  "2.3.7",
+					FilePath: "usr/local/bundle/specifications/bundler-2.3.7.gemspec",
+				},
+				{
+					// installed in another gem home
+					ID:       "actionpack@7.0.3",
+					Name:     "actionpack",
+					Version:  "7.0.3",
+					FilePath: "app/vendor/bundle/ruby/3.1.0/specifications/actionpack-7.0.3.gemspec",
+				},
+			},
+		},
+		{
+			Type:     ftypes.Bundler,
+			FilePath: "app/Gemfile.lock",
+			Libraries: []ftypes.Package{
+				{
+					Name:    "rails",
+					Version: "7.0.4",
+				},
+			},
+		},
+	}
+
+	want := []ftypes.Application{
+		{
+			Type: ftypes.GemSpec,
+			Libraries: []ftypes.Package{
+				{
+					ID:        "rails@7.0.4",
+					Name:      "rails",
+					Version:   "7.0.4",
+					DependsOn: []string{"actionpack@7.0.4", "activesupport@6.1.7", "activesupport@7.0.4", "bundler@2.3.7"},
+					FilePath:  "usr/local/bundle/specifications/rails-7.0.4.gemspec",
+				},
+				{
+					ID:       "actionpack@7.0.4",
+					Name:     "actionpack",
+					Version:  "7.0.4",
+					FilePath: "usr/local/bundle/specifications/actionpack-7.0.4.gemspec",
+				},
+				{
+					ID:       "activesupport@7.0.4",
+					Name:     "activesupport",
+					Version:  "7.0.4",
+					FilePath: "usr/local/bundle/specifications/activesupport-7.0.4.gemspec",
+				},
+				{
+					ID:       "activesupport@6.1.7",
+					Name:     "activesupport",
+					Version:  "6.1.7",
+					FilePath: "usr/local/bundle/specifications/activesupport-6.1.7.gemspec",
+				},
+				{
+					ID:       "bundler@2.3.7",
+					Name:     "bundler",
+					Version:  "2.3.7",
+					FilePath: "usr/local/bundle/specifications/default/bundler-2.3.7.gemspec",
+				},
+				{
+					ID:       "actionpack@7.0.3",
+					Name:     "actionpack",
+					Version:  "7.0.3",
+					FilePath: "app/vendor/bundle/ruby/3.1.0/specifications/actionpack-7.0.3.gemspec",
+				},
+			},
+		},
+		apps[1],
+	}
+	assert.Equal(t, want, Resolve(apps))
+}
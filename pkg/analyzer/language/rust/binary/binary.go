@@ -0,0 +1,177 @@
+package binary
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/analyzer/language"
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const version = 1
+
+const (
+	// The section where cargo-auditable embeds the dependency list
+	// ref. https://github.com/rust-secure-code/cargo-auditable/blob/master/PARSING.md
+	sectionName      = ".dep-v0"
+	machoSectionName = "__dep_v0"
+
+	buildKind = "build"
+)
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&rustBinaryLibraryAnalyzer{})
+}
+
+type versionInfo struct {
+	Packages []struct {
+		Name         string `json:"name"`
+		Version      string `json:"version"`
+		Source       string `json:"source"`
+		Kind         string `json:"kind"`
+		Dependencies []int  `json:"dependencies"`
+		Root         bool   `json:"root"`
+	} `json:"packages"`
+}
+
+// rustBinaryLibraryAnalyzer detects crates embedded in Rust binaries built with cargo-auditable.
+type rustBinaryLibraryAnalyzer struct{}
+
+func (a rustBinaryLibraryAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	data, err := depSection(input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("rust binary parse error: %w", err)
+	} else if data == nil {
+		// Not an executable or built without cargo-auditable
+		return nil, nil
+	}
+
+	libs, deps, err := parseVersionInfo(data)
+	if err != nil {
+		return nil, xerrors.Errorf("rust binary parse error: %w", err)
+	}
+	return language.ToAnalysisResult(types.RustBinary, input.FilePath, "", libs, deps), nil
+}
+
+func (a rustBinaryLibraryAnalyzer) Required(_ string, fileInfo os.FileInfo) bool {
+	mode := fileInfo.Mode()
+	if !mode.IsRegular() {
+		return false
+	}
+
+	// Check executable file
+	return mode.Perm()&0111 != 0
+}
+
+func (a rustBinaryLibraryAnalyzer) Type() analyzer.Type {
+	return types.RustBinary
+}
+
+func (a rustBinaryLibraryAnalyzer) Version() int {
+	return version
+}
+
+// depSection returns the content of the section embedded by cargo-auditable.
+// nil is returned if the file is not an executable or doesn't have the section.
+func depSection(r io.ReaderAt) ([]byte, error) {
+	if f, err := elf.NewFile(r); err == nil {
+		if s := f.Section(sectionName); s != nil {
+			return s.Data()
+		}
+		return nil, nil
+	}
+	if f, err := pe.NewFile(r); err == nil {
+		if s := f.Section(sectionName); s != nil {
+			return s.Data()
+		}
+		return nil, nil
+	}
+	if f, err := macho.NewFile(r); err == nil {
+		if s := f.Section(machoSectionName); s != nil {
+			return s.Data()
+		}
+		return nil, nil
+	}
+	return nil, nil
+}
+
+// parseVersionInfo decodes the zlib-compressed JSON embedded by cargo-auditable.
+// Build dependencies are not linked into the binary, so they are skipped.
+// The root crate and its dependencies are regarded as direct dependencies.
+func parseVersionInfo(data []byte) ([]godeptypes.Library, []godeptypes.Dependency, error) {
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, xerrors.Errorf("zlib error: %w", err)
+	}
+	defer zr.Close()
+
+	var info versionInfo
+	if err = json.NewDecoder(zr).Decode(&info); err != nil {
+		return nil, nil, xerrors.Errorf("json decode error: %w", err)
+	}
+
+	direct := map[int]struct{}{}
+	for i, pkg := range info.Packages {
+		if !pkg.Root {
+			continue
+		}
+		direct[i] = struct{}{}
+		for _, dep := range pkg.Dependencies {
+			direct[dep] = struct{}{}
+		}
+	}
+
+	var libs []godeptypes.Library
+	var deps []godeptypes.Dependency
+	for i, pkg := range info.Packages {
+		if pkg.Kind == buildKind {
+			continue
+		}
+		id := pkgID(pkg.Name, pkg.Version)
+		_, ok := direct[i]
+		libs = append(libs, godeptypes.Library{
+			ID:       id,
+			Name:     pkg.Name,
+			Version:  pkg.Version,
+			Indirect: !ok,
+		})
+
+		var dependsOn []string
+		for _, dep := range pkg.Dependencies {
+			if dep < 0 || dep >= len(info.Packages) {
+				return nil, nil, xerrors.Errorf("invalid dependency index of %s: %d", id, dep)
+			}
+			d := info.Packages[dep]
+			if d.Kind == buildKind {
+				continue
+			}
+			dependsOn = append(dependsOn, pkgID(d.Name, d.Version))
+		}
+		if len(dependsOn) > 0 {
+			sort.Strings(dependsOn)
+			deps = append(deps, godeptypes.Dependency{
+				ID:        id,
+				DependsOn: dependsOn,
+			})
+		}
+	}
+	return libs, deps, nil
+}
+
+func pkgID(name, version string) string {
+	return fmt.Sprintf("%s@%s", name, version)
+}
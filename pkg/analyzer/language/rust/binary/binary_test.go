@@ -0,0 +1,101 @@
+package binary
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+)
+
+func Test_parseVersionInfo(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantLibs []godeptypes.Library
+		wantDeps []godeptypes.Dependency
+		wantErr  string
+	}{
+		{
+			name: "happy path",
+			input: `{"packages":[
+				{"name":"app","version":"0.1.0","source":"local","dependencies":[1,3],"root":true},
+				{"name":"serde","version":"1.0.137","source":"crates.io","dependencies":[2]},
+				{"name":"itoa","version":"0.4.8","source":"crates.io"},
+				{"name":"cc","version":"1.0.73","source":"crates.io","kind":"build"}
+			]}`,
+			wantLibs: []godeptypes.Library{
+				{
+					ID:      "app@0.1.0",
+					Name:    "app",
+					Version: "0.1.0",
+				},
+				{
+					ID:      "serde@1.0.137",
+					Name:    "serde",
+					Version: "1.0.137",
+				},
+				{
+					ID:       "itoa@0.4.8",
+					Name:     "itoa",
+					Version:  "0.4.8",
+					Indirect: true,
+				},
+			},
+			wantDeps: []godeptypes.Dependency{
+				{
+					ID:        "app@0.1.0",
+					DependsOn: []string{"serde@1.0.137"},
+				},
+				{
+					ID:        "serde@1.0.137",
+					DependsOn: []string{"itoa@0.4.8"},
+				},
+			},
+		},
+		{
+			name:    "invalid index",
+			input:   `{"packages":[{"name":"app","version":"0.1.0","dependencies":[1],"root":true}]}`,
+			wantErr: "invalid dependency index of app@0.1.0: 1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := zlib.NewWriter(&buf)
+			_, err := w.Write([]byte(tt.input))
+			require.NoError(t, err)
+			require.NoError(t, w.Close())
+
+			gotLibs, gotDeps, err := parseVersionInfo(buf.Bytes())
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLibs, gotLibs)
+			assert.Equal(t, tt.wantDeps, gotDeps)
+		})
+	}
+}
+
+func Test_rustBinaryLibraryAnalyzer_Analyze(t *testing.T) {
+	f, err := os.Open("testdata/script.sh")
+	require.NoError(t, err)
+	defer f.Close()
+
+	a := rustBinaryLibraryAnalyzer{}
+	got, err := a.Analyze(context.Background(), analyzer.AnalysisInput{
+		FilePath: "testdata/script.sh",
+		Content:  f,
+	})
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
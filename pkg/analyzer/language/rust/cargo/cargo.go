@@ -0,0 +1,146 @@
+package cargo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/samber/lo"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/analyzer/language"
+	"github.com/aquasecurity/fanal/types"
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+
+	// The analyzer of fanal must be registered first so that it is overridden by this one
+	_ "github.com/aquasecurity/fanal/analyzer/language/rust/cargo"
+)
+
+const version = 2
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&cargoLibraryAnalyzer{})
+}
+
+type lockfile struct {
+	Packages []struct {
+		Name         string   `toml:"name"`
+		Version      string   `toml:"version"`
+		Source       string   `toml:"source,omitempty"`
+		Dependencies []string `toml:"dependencies,omitempty"`
+	} `toml:"package"`
+}
+
+// cargoLibraryAnalyzer parses Cargo.lock with the dependency graph.
+// Unlike the analyzer of fanal, packages have IDs and dependencies so that the dependency tree can be rendered.
+type cargoLibraryAnalyzer struct{}
+
+func (a cargoLibraryAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	libs, deps, err := parse(input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("error with Cargo.lock: %w", err)
+	}
+	return language.ToAnalysisResult(types.Cargo, input.FilePath, "", libs, deps), nil
+}
+
+func (a cargoLibraryAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	return filepath.Base(filePath) == types.CargoLock
+}
+
+func (a cargoLibraryAnalyzer) Type() analyzer.Type {
+	return analyzer.TypeCargo
+}
+
+func (a cargoLibraryAnalyzer) Version() int {
+	return version
+}
+
+// parse parses Cargo.lock of all the versions.
+// Local crates, i.e. packages without "source", and their dependencies are regarded as direct dependencies.
+func parse(r io.Reader) ([]godeptypes.Library, []godeptypes.Dependency, error) {
+	var lock lockfile
+	if _, err := toml.NewDecoder(r).Decode(&lock); err != nil {
+		return nil, nil, xerrors.Errorf("decode error: %w", err)
+	}
+
+	// Versions of the same name
+	versions := map[string][]string{}
+	for _, pkg := range lock.Packages {
+		versions[pkg.Name] = append(versions[pkg.Name], pkg.Version)
+	}
+
+	var deps []godeptypes.Dependency
+	direct := map[string]struct{}{}
+	for _, pkg := range lock.Packages {
+		id := pkgID(pkg.Name, pkg.Version)
+
+		var dependsOn []string
+		for _, dep := range pkg.Dependencies {
+			depID, err := resolveDependency(dep, versions)
+			if err != nil {
+				return nil, nil, xerrors.Errorf("dependency error of %s: %w", id, err)
+			}
+			dependsOn = append(dependsOn, depID)
+		}
+
+		if pkg.Source == "" {
+			direct[id] = struct{}{}
+			for _, depID := range dependsOn {
+				direct[depID] = struct{}{}
+			}
+		}
+
+		if len(dependsOn) > 0 {
+			sort.Strings(dependsOn)
+			deps = append(deps, godeptypes.Dependency{
+				ID:        id,
+				DependsOn: lo.Uniq(dependsOn),
+			})
+		}
+	}
+
+	var libs []godeptypes.Library
+	for _, pkg := range lock.Packages {
+		id := pkgID(pkg.Name, pkg.Version)
+		_, ok := direct[id]
+		libs = append(libs, godeptypes.Library{
+			ID:       id,
+			Name:     pkg.Name,
+			Version:  pkg.Version,
+			Indirect: !ok,
+		})
+	}
+	return libs, deps, nil
+}
+
+// resolveDependency returns the ID of the dependency.
+// The version is omitted if there is only one version of the package since Cargo.lock v2,
+// and the source is added in Cargo.lock v1.
+// e.g. "serde", "libc 0.2.126", "libc 0.2.126 (registry+https://github.com/rust-lang/crates.io-index)"
+func resolveDependency(dep string, versions map[string][]string) (string, error) {
+	fields := strings.Fields(dep)
+	if len(fields) == 0 {
+		return "", xerrors.New("empty dependency")
+	}
+
+	name := fields[0]
+	if len(fields) > 1 {
+		return pkgID(name, fields[1]), nil
+	}
+
+	if len(versions[name]) != 1 {
+		return "", xerrors.Errorf("unable to identify the version of %s", name)
+	}
+	return pkgID(name, versions[name][0]), nil
+}
+
+func pkgID(name, version string) string {
+	return fmt.Sprintf("%s@%s", name, version)
+}
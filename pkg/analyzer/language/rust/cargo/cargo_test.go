@@ -0,0 +1,113 @@
+package cargo
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+)
+
+func Test_parse(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		wantLibs []godeptypes.Library
+		wantDeps []godeptypes.Dependency
+		wantErr  string
+	}{
+		{
+			name: "v3 with multiple versions",
+			file: "testdata/Cargo.lock",
+			wantLibs: []godeptypes.Library{
+				{
+					ID:      "app@0.1.0",
+					Name:    "app",
+					Version: "0.1.0",
+				},
+				{
+					ID:       "itoa@0.4.8",
+					Name:     "itoa",
+					Version:  "0.4.8",
+					Indirect: true,
+				},
+				{
+					ID:      "itoa@1.0.2",
+					Name:    "itoa",
+					Version: "1.0.2",
+				},
+				{
+					ID:      "serde@1.0.137",
+					Name:    "serde",
+					Version: "1.0.137",
+				},
+			},
+			wantDeps: []godeptypes.Dependency{
+				{
+					ID:        "app@0.1.0",
+					DependsOn: []string{"itoa@1.0.2", "serde@1.0.137"},
+				},
+				{
+					ID:        "serde@1.0.137",
+					DependsOn: []string{"itoa@0.4.8"},
+				},
+			},
+		},
+		{
+			name: "v1 with sources",
+			file: "testdata/Cargo.lock.v1",
+			wantLibs: []godeptypes.Library{
+				{
+					ID:      "app@0.1.0",
+					Name:    "app",
+					Version: "0.1.0",
+				},
+				{
+					ID:       "itoa@0.4.8",
+					Name:     "itoa",
+					Version:  "0.4.8",
+					Indirect: true,
+				},
+				{
+					ID:      "serde@1.0.137",
+					Name:    "serde",
+					Version: "1.0.137",
+				},
+			},
+			wantDeps: []godeptypes.Dependency{
+				{
+					ID:        "app@0.1.0",
+					DependsOn: []string{"serde@1.0.137"},
+				},
+				{
+					ID:        "serde@1.0.137",
+					DependsOn: []string{"itoa@0.4.8"},
+				},
+			},
+		},
+		{
+			name:    "ambiguous dependency",
+			file:    "testdata/ambiguous.lock",
+			wantErr: "unable to identify the version of itoa",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.file)
+			require.NoError(t, err)
+			defer f.Close()
+
+			gotLibs, gotDeps, err := parse(f)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLibs, gotLibs)
+			assert.Equal(t, tt.wantDeps, gotDeps)
+		})
+	}
+}
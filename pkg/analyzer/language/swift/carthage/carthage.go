@@ -0,0 +1,98 @@
+package carthage
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/analyzer/language"
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/analyzer/language/swift/swift"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const (
+	version = 1
+
+	fileName = "Cartfile.resolved"
+
+	originGitHub = "github"
+	originGit    = "git"
+)
+
+// e.g. github "Alamofire/Alamofire" "5.4.3"
+var dependencyRegexp = regexp.MustCompile(`^(\w+)\s+"([^"]+)"\s+"([^"]+)"`)
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&carthageLibraryAnalyzer{})
+}
+
+// carthageLibraryAnalyzer parses Cartfile.resolved of Carthage.
+// Carthage dependencies are Git repositories, so they are reported as Swift packages.
+type carthageLibraryAnalyzer struct{}
+
+func (a carthageLibraryAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	libs, err := parse(input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to parse %s: %w", fileName, err)
+	}
+	return language.ToAnalysisResult(types.Carthage, input.FilePath, "", libs, nil), nil
+}
+
+func (a carthageLibraryAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	return filepath.Base(filePath) == fileName
+}
+
+func (a carthageLibraryAnalyzer) Type() analyzer.Type {
+	return types.Carthage
+}
+
+func (a carthageLibraryAnalyzer) Version() int {
+	return version
+}
+
+// parse parses Cartfile.resolved.
+// Binary-only frameworks are skipped since they are not identified by repositories.
+func parse(r io.Reader) ([]godeptypes.Library, error) {
+	var libs []godeptypes.Library
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		m := dependencyRegexp.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+
+		switch origin, location, ver := m[1], m[2], m[3]; origin {
+		case originGitHub:
+			// "owner/repo" or the URL of GitHub Enterprise
+			name := "github.com/" + location
+			if strings.Contains(location, "://") {
+				name = swift.PackageName(location)
+			}
+			libs = append(libs, library(name, ver))
+		case originGit:
+			libs = append(libs, library(swift.PackageName(location), ver))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, xerrors.Errorf("scan error: %w", err)
+	}
+	return libs, nil
+}
+
+func library(name, ver string) godeptypes.Library {
+	return godeptypes.Library{
+		ID:      fmt.Sprintf("%s@%s", name, ver),
+		Name:    name,
+		Version: ver,
+	}
+}
@@ -0,0 +1,39 @@
+package carthage
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+)
+
+func Test_parse(t *testing.T) {
+	f, err := os.Open("testdata/Cartfile.resolved")
+	require.NoError(t, err)
+	defer f.Close()
+
+	got, err := parse(f)
+	require.NoError(t, err)
+
+	want := []godeptypes.Library{
+		{
+			ID:      "example.com/org/Framework@1.2.0",
+			Name:    "example.com/org/Framework",
+			Version: "1.2.0",
+		},
+		{
+			ID:      "github.com/Alamofire/Alamofire@5.4.3",
+			Name:    "github.com/Alamofire/Alamofire",
+			Version: "5.4.3",
+		},
+		{
+			ID:      "ghe.example.com/org/Internal@v0.3.1",
+			Name:    "ghe.example.com/org/Internal",
+			Version: "v0.3.1",
+		},
+	}
+	assert.Equal(t, want, got)
+}
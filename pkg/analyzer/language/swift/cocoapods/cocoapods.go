@@ -0,0 +1,160 @@
+package cocoapods
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/analyzer/language"
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const (
+	version = 1
+
+	fileName = "Podfile.lock"
+)
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&cocoaPodsLibraryAnalyzer{})
+}
+
+type lockfile struct {
+	Pods         []interface{} `yaml:"PODS"` // pod name or map of pod name to its dependencies
+	Dependencies []string      `yaml:"DEPENDENCIES"`
+}
+
+// cocoaPodsLibraryAnalyzer parses Podfile.lock of CocoaPods
+type cocoaPodsLibraryAnalyzer struct{}
+
+func (a cocoaPodsLibraryAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	libs, deps, err := parse(input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to parse %s: %w", fileName, err)
+	}
+	return language.ToAnalysisResult(types.Cocoapods, input.FilePath, "", libs, deps), nil
+}
+
+func (a cocoaPodsLibraryAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	return filepath.Base(filePath) == fileName
+}
+
+func (a cocoaPodsLibraryAnalyzer) Type() analyzer.Type {
+	return types.Cocoapods
+}
+
+func (a cocoaPodsLibraryAnalyzer) Version() int {
+	return version
+}
+
+// parse parses Podfile.lock.
+// Pods listed in "DEPENDENCIES" are regarded as direct dependencies.
+// e.g.
+//
+//	PODS:
+//	  - Alamofire (5.4.3)
+//	  - AppCenter/Analytics (4.2.0):
+//	    - AppCenter/Core
+//	  - AppCenter/Core (4.2.0)
+//	DEPENDENCIES:
+//	  - Alamofire (~> 5.4)
+//	  - AppCenter/Analytics
+func parse(r io.Reader) ([]godeptypes.Library, []godeptypes.Dependency, error) {
+	var lock lockfile
+	if err := yaml.NewDecoder(r).Decode(&lock); err != nil {
+		return nil, nil, xerrors.Errorf("yaml decode error: %w", err)
+	}
+
+	// Pod name => version
+	versions := map[string]string{}
+	// Pod name => names of the dependencies
+	dependsOn := map[string][]string{}
+	var names []string
+	for _, pod := range lock.Pods {
+		switch p := pod.(type) {
+		case string:
+			name, ver := parsePod(p)
+			versions[name] = ver
+			names = append(names, name)
+		case map[string]interface{}:
+			for key, value := range p {
+				name, ver := parsePod(key)
+				versions[name] = ver
+				names = append(names, name)
+
+				deps, ok := value.([]interface{})
+				if !ok {
+					return nil, nil, xerrors.Errorf("invalid dependencies of %s", name)
+				}
+				for _, dep := range deps {
+					s, ok := dep.(string)
+					if !ok {
+						return nil, nil, xerrors.Errorf("invalid dependency of %s", name)
+					}
+					depName, _ := parsePod(s)
+					dependsOn[name] = append(dependsOn[name], depName)
+				}
+			}
+		default:
+			return nil, nil, xerrors.Errorf("invalid pod: %v", pod)
+		}
+	}
+
+	direct := map[string]struct{}{}
+	for _, dep := range lock.Dependencies {
+		name, _ := parsePod(dep)
+		direct[name] = struct{}{}
+	}
+
+	var libs []godeptypes.Library
+	var deps []godeptypes.Dependency
+	for _, name := range names {
+		id := pkgID(name, versions[name])
+		_, ok := direct[name]
+		libs = append(libs, godeptypes.Library{
+			ID:       id,
+			Name:     name,
+			Version:  versions[name],
+			Indirect: !ok,
+		})
+
+		var depIDs []string
+		for _, depName := range dependsOn[name] {
+			ver, ok := versions[depName]
+			if !ok {
+				return nil, nil, xerrors.Errorf("unable to identify the version of %s", depName)
+			}
+			depIDs = append(depIDs, pkgID(depName, ver))
+		}
+		if len(depIDs) > 0 {
+			sort.Strings(depIDs)
+			deps = append(deps, godeptypes.Dependency{
+				ID:        id,
+				DependsOn: depIDs,
+			})
+		}
+	}
+	return libs, deps, nil
+}
+
+// parsePod splits a pod into the name and the version or the requirement.
+// e.g. "AppCenter/Core (4.2.0)", "Alamofire (~> 5.4)" and "KeychainAccess"
+func parsePod(s string) (string, string) {
+	name, ver, _ := strings.Cut(s, " ")
+	ver = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(ver), "("), ")")
+	return name, ver
+}
+
+func pkgID(name, version string) string {
+	return fmt.Sprintf("%s@%s", name, version)
+}
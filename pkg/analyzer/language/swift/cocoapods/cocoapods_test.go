@@ -0,0 +1,72 @@
+package cocoapods
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+)
+
+func Test_parse(t *testing.T) {
+	f, err := os.Open("testdata/Podfile.lock")
+	require.NoError(t, err)
+	defer f.Close()
+
+	gotLibs, gotDeps, err := parse(f)
+	require.NoError(t, err)
+
+	wantLibs := []godeptypes.Library{
+		{
+			ID:      "Alamofire@5.4.3",
+			Name:    "Alamofire",
+			Version: "5.4.3",
+		},
+		{
+			ID:      "AppCenter@4.2.0",
+			Name:    "AppCenter",
+			Version: "4.2.0",
+		},
+		{
+			ID:       "AppCenter/Analytics@4.2.0",
+			Name:     "AppCenter/Analytics",
+			Version:  "4.2.0",
+			Indirect: true,
+		},
+		{
+			ID:       "AppCenter/Core@4.2.0",
+			Name:     "AppCenter/Core",
+			Version:  "4.2.0",
+			Indirect: true,
+		},
+		{
+			ID:       "AppCenter/Crashes@4.2.0",
+			Name:     "AppCenter/Crashes",
+			Version:  "4.2.0",
+			Indirect: true,
+		},
+		{
+			ID:      "KeychainAccess@4.2.1",
+			Name:    "KeychainAccess",
+			Version: "4.2.1",
+		},
+	}
+	wantDeps := []godeptypes.Dependency{
+		{
+			ID:        "AppCenter@4.2.0",
+			DependsOn: []string{"AppCenter/Analytics@4.2.0", "AppCenter/Crashes@4.2.0"},
+		},
+		{
+			ID:        "AppCenter/Analytics@4.2.0",
+			DependsOn: []string{"AppCenter/Core@4.2.0"},
+		},
+		{
+			ID:        "AppCenter/Crashes@4.2.0",
+			DependsOn: []string{"AppCenter/Core@4.2.0"},
+		},
+	}
+	assert.Equal(t, wantLibs, gotLibs)
+	assert.Equal(t, wantDeps, gotDeps)
+}
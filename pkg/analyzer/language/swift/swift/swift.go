@@ -0,0 +1,125 @@
+package swift
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/analyzer/language"
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const (
+	version = 1
+
+	fileName = "Package.resolved"
+)
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&swiftLibraryAnalyzer{})
+}
+
+// Package.resolved has a different structure depending on the version.
+// Version 1 has pins in "object", while version 2 and later have them at the top level.
+type lockfile struct {
+	Object struct {
+		Pins []pin `json:"pins"`
+	} `json:"object"`
+	Pins    []pin `json:"pins"`
+	Version int   `json:"version"`
+}
+
+type pin struct {
+	RepositoryURL string `json:"repositoryURL"` // version 1
+	Location      string `json:"location"`      // version 2 or later
+	State         struct {
+		Branch   *string `json:"branch"`
+		Revision string  `json:"revision"`
+		Version  string  `json:"version"`
+	} `json:"state"`
+}
+
+// swiftLibraryAnalyzer parses Package.resolved of Swift Package Manager
+type swiftLibraryAnalyzer struct{}
+
+func (a swiftLibraryAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	libs, err := parse(input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to parse %s: %w", fileName, err)
+	}
+	return language.ToAnalysisResult(types.Swift, input.FilePath, "", libs, nil), nil
+}
+
+func (a swiftLibraryAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	return filepath.Base(filePath) == fileName
+}
+
+func (a swiftLibraryAnalyzer) Type() analyzer.Type {
+	return types.Swift
+}
+
+func (a swiftLibraryAnalyzer) Version() int {
+	return version
+}
+
+// parse parses Package.resolved.
+// Packages pinned to branches or revisions are skipped as they don't have versions.
+func parse(r io.Reader) ([]godeptypes.Library, error) {
+	var lock lockfile
+	if err := json.NewDecoder(r).Decode(&lock); err != nil {
+		return nil, xerrors.Errorf("json decode error: %w", err)
+	}
+
+	pins := lock.Pins
+	if lock.Version == 1 {
+		pins = lock.Object.Pins
+	}
+
+	var libs []godeptypes.Library
+	for _, p := range pins {
+		if p.State.Version == "" {
+			continue
+		}
+
+		url := p.Location
+		if url == "" {
+			url = p.RepositoryURL
+		}
+		name := PackageName(url)
+		libs = append(libs, godeptypes.Library{
+			ID:      fmt.Sprintf("%s@%s", name, p.State.Version),
+			Name:    name,
+			Version: p.State.Version,
+		})
+	}
+	return libs, nil
+}
+
+// PackageName returns the name of a Swift package from the repository URL.
+// Swift packages are identified by their repository URLs without the scheme and the ".git" suffix.
+// e.g. "https://github.com/Alamofire/Alamofire.git" and "git@github.com:Alamofire/Alamofire.git"
+// => "github.com/Alamofire/Alamofire"
+func PackageName(url string) string {
+	name := url
+	if i := strings.Index(name, "://"); i != -1 {
+		name = name[i+len("://"):]
+	} else if i = strings.Index(name, "@"); i != -1 {
+		// SCP-like syntax
+		name = strings.Replace(name[i+1:], ":", "/", 1)
+	}
+
+	// Strip the user info
+	if i := strings.Index(name, "@"); i != -1 && i < strings.Index(name, "/") {
+		name = name[i+1:]
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(name, "/"), ".git")
+}
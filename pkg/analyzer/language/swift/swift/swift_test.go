@@ -0,0 +1,92 @@
+package swift
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+)
+
+func Test_parse(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want []godeptypes.Library
+	}{
+		{
+			name: "version 1",
+			file: "testdata/Package.resolved.v1",
+			want: []godeptypes.Library{
+				{
+					ID:      "github.com/Alamofire/Alamofire@5.4.3",
+					Name:    "github.com/Alamofire/Alamofire",
+					Version: "5.4.3",
+				},
+				{
+					ID:      "github.com/kishikawakatsumi/KeychainAccess@4.2.2",
+					Name:    "github.com/kishikawakatsumi/KeychainAccess",
+					Version: "4.2.2",
+				},
+			},
+		},
+		{
+			name: "version 2",
+			file: "testdata/Package.resolved.v2",
+			want: []godeptypes.Library{
+				{
+					ID:      "github.com/Alamofire/Alamofire@5.6.2",
+					Name:    "github.com/Alamofire/Alamofire",
+					Version: "5.6.2",
+				},
+				{
+					ID:      "github.com/apple/swift-log@1.4.4",
+					Name:    "github.com/apple/swift-log",
+					Version: "1.4.4",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.file)
+			require.NoError(t, err)
+			defer f.Close()
+
+			got, err := parse(f)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPackageName(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{
+			url:  "https://github.com/Alamofire/Alamofire.git",
+			want: "github.com/Alamofire/Alamofire",
+		},
+		{
+			url:  "git@github.com:Alamofire/Alamofire.git",
+			want: "github.com/Alamofire/Alamofire",
+		},
+		{
+			url:  "ssh://git@github.com/Alamofire/Alamofire",
+			want: "github.com/Alamofire/Alamofire",
+		},
+		{
+			url:  "https://gitlab.com/org/package/",
+			want: "gitlab.com/org/package",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			assert.Equal(t, tt.want, PackageName(tt.url))
+		})
+	}
+}
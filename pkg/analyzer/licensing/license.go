@@ -0,0 +1,102 @@
+package licensing
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/licensing"
+)
+
+const (
+	version = 1
+
+	// TypeLicenseFile is disabled unless "--license-full" is specified
+	TypeLicenseFile = analyzer.Type("license-file")
+
+	// License files larger than this are not classified
+	maxLicenseFileSize = 1 << 20
+
+	// Only the beginning of source files is classified as the header
+	headerSize = 4 << 10
+)
+
+var (
+	// e.g. LICENSE, LICENSE.md, LICENSE-MIT, COPYING, NOTICE.txt
+	licenseFileName = regexp.MustCompile(`(?i)^(licen[cs]e|copying|notice|unlicense)([.-].*)?$`)
+
+	sourceExts = []string{
+		".c", ".cc", ".cpp", ".cs", ".go", ".h", ".hpp", ".java", ".js", ".jsx", ".kt",
+		".php", ".py", ".rb", ".rs", ".scala", ".sh", ".swift", ".ts", ".tsx",
+	}
+	skipDirs = []string{".git", "node_modules"}
+)
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&licenseFileAnalyzer{})
+}
+
+// licenseFileAnalyzer classifies license files and headers of source files
+type licenseFileAnalyzer struct{}
+
+func (a licenseFileAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	r := io.Reader(input.Content)
+	if !isLicenseFile(input.FilePath) {
+		r = io.LimitReader(r, headerSize)
+	}
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, xerrors.Errorf("read error %s: %w", input.FilePath, err)
+	}
+
+	findings := licensing.Classify(content)
+	if len(findings) == 0 {
+		return nil, nil
+	}
+
+	return &analyzer.AnalysisResult{
+		CustomResources: []types.CustomResource{
+			{
+				Type:     licensing.FileType,
+				FilePath: input.FilePath,
+				Data:     findings,
+			},
+		},
+	}, nil
+}
+
+func (a licenseFileAnalyzer) Required(filePath string, fi os.FileInfo) bool {
+	dirs := strings.Split(filepath.ToSlash(filepath.Dir(filePath)), "/")
+	for _, skipDir := range skipDirs {
+		if slices.Contains(dirs, skipDir) {
+			return false
+		}
+	}
+
+	if isLicenseFile(filePath) {
+		return fi.Size() <= maxLicenseFileSize
+	}
+	return slices.Contains(sourceExts, filepath.Ext(filePath))
+}
+
+func (a licenseFileAnalyzer) Type() analyzer.Type {
+	return TypeLicenseFile
+}
+
+func (a licenseFileAnalyzer) Version() int {
+	return version
+}
+
+func isLicenseFile(filePath string) bool {
+	return licenseFileName.MatchString(filepath.Base(filePath))
+}
@@ -0,0 +1,128 @@
+package licensing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/licensing"
+)
+
+func TestLicenseFileAnalyzer_Analyze(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		want     *analyzer.AnalysisResult
+	}{
+		{
+			name:     "license file",
+			filePath: "testdata/LICENSE",
+			want: &analyzer.AnalysisResult{
+				CustomResources: []types.CustomResource{
+					{
+						Type:     licensing.FileType,
+						FilePath: "testdata/LICENSE",
+						Data: []licensing.Finding{
+							{
+								Name:       "MIT",
+								Confidence: 0.9,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "source file",
+			filePath: "testdata/main.go",
+			want: &analyzer.AnalysisResult{
+				CustomResources: []types.CustomResource{
+					{
+						Type:     licensing.FileType,
+						FilePath: "testdata/main.go",
+						Data: []licensing.Finding{
+							{
+								Name:       "GPL-3.0-or-later",
+								Confidence: 1,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "no license",
+			filePath: "testdata/README.md",
+			want:     nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.filePath)
+			require.NoError(t, err)
+			defer f.Close()
+
+			a := licenseFileAnalyzer{}
+			got, err := a.Analyze(context.Background(), analyzer.AnalysisInput{
+				FilePath: tt.filePath,
+				Content:  f,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLicenseFileAnalyzer_Required(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		want     bool
+	}{
+		{
+			name:     "license file",
+			filePath: "LICENSE",
+			want:     true,
+		},
+		{
+			name:     "license file with extension",
+			filePath: "docs/COPYING.txt",
+			want:     true,
+		},
+		{
+			name:     "source file",
+			filePath: "src/main.go",
+			want:     true,
+		},
+		{
+			name:     "vendored dependency",
+			filePath: "node_modules/lodash/LICENSE",
+			want:     false,
+		},
+		{
+			name:     "other file",
+			filePath: "README.md",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			f, err := os.Create(filepath.Join(dir, "file"))
+			require.NoError(t, err)
+			defer f.Close()
+
+			fi, err := f.Stat()
+			require.NoError(t, err)
+
+			a := licenseFileAnalyzer{}
+			assert.Equal(t, tt.want, a.Required(tt.filePath, fi))
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package analyzer
+
+import (
+	"sort"
+
+	"github.com/aquasecurity/fanal/analyzer"
+)
+
+// FileSizeLimits holds the maximum size of files passed to each analyzer.
+// Analyzers without limits analyze files of any size.
+type FileSizeLimits map[analyzer.Type]int64
+
+// Exceeded returns the analyzers which don't analyze the file of the size, sorted by the type
+func (l FileSizeLimits) Exceeded(size int64) []analyzer.Type {
+	var exceeded []analyzer.Type
+	for t, limit := range l {
+		if limit > 0 && size > limit {
+			exceeded = append(exceeded, t)
+		}
+	}
+	sort.Slice(exceeded, func(i, j int) bool {
+		return exceeded[i] < exceeded[j]
+	})
+	return exceeded
+}
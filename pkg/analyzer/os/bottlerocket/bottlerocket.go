@@ -0,0 +1,89 @@
+package bottlerocket
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sort"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	tos "github.com/aquasecurity/trivy/pkg/analyzer/os"
+)
+
+const version = 1
+
+var requiredFiles = []string{
+	"usr/share/bottlerocket/application-inventory.json",
+}
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&inventoryAnalyzer{})
+}
+
+// inventory represents the application inventory of Bottlerocket.
+// Bottlerocket is built from RPM packages, but the RPM database is not shipped.
+type inventory struct {
+	Content []struct {
+		Name         string `json:"Name"`
+		Version      string `json:"Version"`
+		Release      string `json:"Release"`
+		Architecture string `json:"Architecture"`
+		License      string `json:"License"`
+	} `json:"Content"`
+}
+
+// inventoryAnalyzer detects the packages installed in Bottlerocket.
+type inventoryAnalyzer struct{}
+
+func (a inventoryAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	var inv inventory
+	if err := json.NewDecoder(input.Content).Decode(&inv); err != nil {
+		return nil, xerrors.Errorf("unable to decode %s: %w", input.FilePath, err)
+	}
+
+	var pkgs []types.Package
+	for _, app := range inv.Content {
+		if app.Name == "" || app.Version == "" {
+			continue
+		}
+		pkgs = append(pkgs, types.Package{
+			Name:    app.Name,
+			Version: app.Version,
+			Release: app.Release,
+			Arch:    app.Architecture,
+			License: app.License,
+		})
+	}
+	if len(pkgs) == 0 {
+		return nil, nil
+	}
+	sort.Slice(pkgs, func(i, j int) bool {
+		return pkgs[i].Name < pkgs[j].Name
+	})
+
+	return &analyzer.AnalysisResult{
+		PackageInfos: []types.PackageInfo{
+			{
+				FilePath: input.FilePath,
+				Packages: pkgs,
+			},
+		},
+	}, nil
+}
+
+func (a inventoryAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	return slices.Contains(requiredFiles, filePath)
+}
+
+func (a inventoryAnalyzer) Type() analyzer.Type {
+	return tos.TypeBottlerocketInventory
+}
+
+func (a inventoryAnalyzer) Version() int {
+	return version
+}
@@ -0,0 +1,75 @@
+package bottlerocket
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/types"
+)
+
+func Test_inventoryAnalyzer_Analyze(t *testing.T) {
+	tests := []struct {
+		name      string
+		inputFile string
+		want      *analyzer.AnalysisResult
+		wantErr   string
+	}{
+		{
+			name:      "happy path",
+			inputFile: "testdata/application-inventory.json",
+			want: &analyzer.AnalysisResult{
+				PackageInfos: []types.PackageInfo{
+					{
+						FilePath: "usr/share/bottlerocket/application-inventory.json",
+						Packages: []types.Package{
+							{
+								Name:    "bash",
+								Version: "5.1.16",
+								Release: "1.1654641093.104f8e0f.br1",
+								Arch:    "x86_64",
+								License: "GPL-3.0-or-later",
+							},
+							{
+								Name:    "openssl",
+								Version: "1.1.1o",
+								Release: "1.1654641093.104f8e0f.br1",
+								Arch:    "x86_64",
+								License: "OpenSSL",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "broken",
+			inputFile: "testdata/broken.json",
+			wantErr:   "unable to decode",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.inputFile)
+			require.NoError(t, err)
+			defer f.Close()
+
+			a := inventoryAnalyzer{}
+			got, err := a.Analyze(context.Background(), analyzer.AnalysisInput{
+				FilePath: "usr/share/bottlerocket/application-inventory.json",
+				Content:  f,
+			})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package os
+
+import "github.com/aquasecurity/fanal/analyzer"
+
+// OS families supported by Trivy in addition to those of fanal
+const (
+	// Wolfi is the undistro for containers, https://github.com/wolfi-dev
+	Wolfi = "wolfi"
+
+	// Chainguard is Chainguard OS used in Chainguard Images
+	Chainguard = "chainguard"
+
+	// Bottlerocket is the container-optimized OS by AWS, https://github.com/bottlerocket-os/bottlerocket
+	Bottlerocket = "bottlerocket"
+
+	// Flatcar is Flatcar Container Linux, https://www.flatcar.org
+	Flatcar = "flatcar"
+
+	// Windows is Windows Server and Windows in container images
+	Windows = "windows"
+)
+
+// WindowsPkg is the package representing the build of Windows, which is updated by cumulative updates
+const WindowsPkg = "windows"
+
+// Analyzers of OS packages in addition to those of fanal
+const (
+	// TypeBottlerocketInventory parses the application inventory of Bottlerocket
+	TypeBottlerocketInventory = analyzer.Type("bottlerocket-inventory")
+
+	// TypePortage parses the Portage package database of Flatcar Container Linux
+	TypePortage = analyzer.Type("portage")
+
+	// TypeDNFModule parses the module streams enabled in DNF for modular packages of Red Hat based distributions
+	TypeDNFModule = analyzer.Type("dnf-module")
+
+	// TypeWindowsRegistry parses the SOFTWARE registry hive of Windows
+	TypeWindowsRegistry = analyzer.Type("windows-registry")
+)
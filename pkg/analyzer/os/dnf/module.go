@@ -0,0 +1,112 @@
+package dnf
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	tos "github.com/aquasecurity/trivy/pkg/analyzer/os"
+	"github.com/aquasecurity/trivy/pkg/dnf"
+)
+
+const (
+	version = 1
+
+	modulesDir = "etc/dnf/modules.d"
+)
+
+// States of enabled modules. Old versions of DNF have "enabled=1" instead.
+var enabledStates = []string{"enabled", "installed"}
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&moduleAnalyzer{})
+}
+
+// moduleAnalyzer detects module streams enabled in DNF, e.g. etc/dnf/modules.d/nodejs.module.
+//
+//	[nodejs]
+//	name=nodejs
+//	stream=18
+//	profiles=
+//	state=enabled
+type moduleAnalyzer struct{}
+
+func (a moduleAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	var modules []dnf.Module
+	var m dnf.Module
+	var enabled bool
+	flush := func() {
+		if enabled && m.Name != "" && m.Stream != "" {
+			modules = append(modules, m)
+		}
+		m, enabled = dnf.Module{}, false
+	}
+
+	scanner := bufio.NewScanner(input.Content)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			flush()
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		switch value = strings.TrimSpace(value); strings.TrimSpace(key) {
+		case "name":
+			m.Name = value
+		case "stream":
+			m.Stream = value
+		case "state":
+			enabled = slices.Contains(enabledStates, value)
+		case "enabled":
+			enabled = value == "1" || value == "true"
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, xerrors.Errorf("scan error %s: %w", input.FilePath, err)
+	}
+	flush()
+
+	if len(modules) == 0 {
+		return nil, nil
+	}
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].Name < modules[j].Name
+	})
+
+	return &analyzer.AnalysisResult{
+		CustomResources: []types.CustomResource{
+			{
+				Type:     dnf.ModuleType,
+				FilePath: input.FilePath,
+				Data:     modules,
+			},
+		},
+	}, nil
+}
+
+func (a moduleAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	filePath = filepath.ToSlash(filePath)
+	return path.Dir(filePath) == modulesDir && path.Ext(filePath) == ".module"
+}
+
+func (a moduleAnalyzer) Type() analyzer.Type {
+	return tos.TypeDNFModule
+}
+
+func (a moduleAnalyzer) Version() int {
+	return version
+}
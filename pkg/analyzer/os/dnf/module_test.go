@@ -0,0 +1,109 @@
+package dnf
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/dnf"
+)
+
+func Test_moduleAnalyzer_Analyze(t *testing.T) {
+	tests := []struct {
+		name      string
+		inputFile string
+		want      *analyzer.AnalysisResult
+	}{
+		{
+			name:      "enabled",
+			inputFile: "testdata/nodejs.module",
+			want: &analyzer.AnalysisResult{
+				CustomResources: []types.CustomResource{
+					{
+						Type:     dnf.ModuleType,
+						FilePath: "testdata/nodejs.module",
+						Data: []dnf.Module{
+							{
+								Name:   "nodejs",
+								Stream: "18",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "enabled=1",
+			inputFile: "testdata/legacy.module",
+			want: &analyzer.AnalysisResult{
+				CustomResources: []types.CustomResource{
+					{
+						Type:     dnf.ModuleType,
+						FilePath: "testdata/legacy.module",
+						Data: []dnf.Module{
+							{
+								Name:   "postgresql",
+								Stream: "13",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "disabled",
+			inputFile: "testdata/disabled.module",
+			want:      nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.inputFile)
+			require.NoError(t, err)
+			defer f.Close()
+
+			a := moduleAnalyzer{}
+			got, err := a.Analyze(context.Background(), analyzer.AnalysisInput{
+				FilePath: tt.inputFile,
+				Content:  f,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_moduleAnalyzer_Required(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		want     bool
+	}{
+		{
+			name:     "module",
+			filePath: "etc/dnf/modules.d/nodejs.module",
+			want:     true,
+		},
+		{
+			name:     "defaults",
+			filePath: "etc/dnf/modules.defaults.d/nodejs.yaml",
+			want:     false,
+		},
+		{
+			name:     "other directory",
+			filePath: "usr/share/nodejs.module",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := moduleAnalyzer{}
+			assert.Equal(t, tt.want, a.Required(tt.filePath, nil))
+		})
+	}
+}
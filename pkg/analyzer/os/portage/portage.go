@@ -0,0 +1,80 @@
+package portage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	tos "github.com/aquasecurity/trivy/pkg/analyzer/os"
+)
+
+const version = 1
+
+var (
+	// Each installed package has its own directory in the database, e.g. var/db/pkg/dev-libs/openssl-3.0.7-r1/PF
+	pfRegexp = regexp.MustCompile(`^var/db/pkg/[^/]+/[^/]+/PF$`)
+
+	// PF is the package name with the version and the revision, e.g. openssl-3.0.7-r1.
+	// The version starts at the last hyphen followed by a digit.
+	versionRegexp = regexp.MustCompile(`^(.+?)-(\d+(?:\.\d+)*[a-z]?(?:_(?:alpha|beta|pre|rc|p)\d*)*)(?:-r(\d+))?$`)
+)
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&portageAnalyzer{})
+}
+
+// portageAnalyzer detects the packages installed in Flatcar Container Linux, which is built with Portage of Gentoo.
+// The database is read from /var/db/pkg, which is not shipped in production images of some releases.
+type portageAnalyzer struct{}
+
+func (a portageAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	b, err := io.ReadAll(input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("read error %s: %w", input.FilePath, err)
+	}
+
+	pf := strings.TrimSpace(string(b))
+	m := versionRegexp.FindStringSubmatch(pf)
+	if m == nil {
+		return nil, xerrors.Errorf("invalid package name %q in %s", pf, input.FilePath)
+	}
+
+	pkg := types.Package{
+		Name:    m[1],
+		Version: m[2],
+	}
+	if m[3] != "" {
+		pkg.Release = "r" + m[3]
+	}
+
+	return &analyzer.AnalysisResult{
+		PackageInfos: []types.PackageInfo{
+			{
+				// The directory of the package, e.g. var/db/pkg/dev-libs/openssl-3.0.7-r1
+				FilePath: path.Dir(filepath.ToSlash(input.FilePath)),
+				Packages: []types.Package{pkg},
+			},
+		},
+	}, nil
+}
+
+func (a portageAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	return pfRegexp.MatchString(filepath.ToSlash(filePath))
+}
+
+func (a portageAnalyzer) Type() analyzer.Type {
+	return tos.TypePortage
+}
+
+func (a portageAnalyzer) Version() int {
+	return version
+}
@@ -0,0 +1,113 @@
+package portage
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/types"
+)
+
+func Test_portageAnalyzer_Analyze(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		content  string
+		want     *analyzer.AnalysisResult
+		wantErr  string
+	}{
+		{
+			name:     "with revision",
+			filePath: "var/db/pkg/dev-libs/openssl-3.0.7-r1/PF",
+			content:  "openssl-3.0.7-r1\n",
+			want: &analyzer.AnalysisResult{
+				PackageInfos: []types.PackageInfo{
+					{
+						FilePath: "var/db/pkg/dev-libs/openssl-3.0.7-r1",
+						Packages: []types.Package{
+							{
+								Name:    "openssl",
+								Version: "3.0.7",
+								Release: "r1",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "hyphen and suffix",
+			filePath: "var/db/pkg/sys-kernel/coreos-firmware-20220509_p1/PF",
+			content:  "coreos-firmware-20220509_p1\n",
+			want: &analyzer.AnalysisResult{
+				PackageInfos: []types.PackageInfo{
+					{
+						FilePath: "var/db/pkg/sys-kernel/coreos-firmware-20220509_p1",
+						Packages: []types.Package{
+							{
+								Name:    "coreos-firmware",
+								Version: "20220509_p1",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "invalid",
+			filePath: "var/db/pkg/dev-libs/openssl/PF",
+			content:  "openssl",
+			wantErr:  "invalid package name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := portageAnalyzer{}
+			got, err := a.Analyze(context.Background(), analyzer.AnalysisInput{
+				FilePath: tt.filePath,
+				Content:  strings.NewReader(tt.content),
+			})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_portageAnalyzer_Required(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		want     bool
+	}{
+		{
+			name:     "PF",
+			filePath: "var/db/pkg/dev-libs/openssl-3.0.7-r1/PF",
+			want:     true,
+		},
+		{
+			name:     "other file",
+			filePath: "var/db/pkg/dev-libs/openssl-3.0.7-r1/CONTENTS",
+			want:     false,
+		},
+		{
+			name:     "other directory",
+			filePath: "usr/share/pkg/dev-libs/openssl-3.0.7-r1/PF",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := portageAnalyzer{}
+			assert.Equal(t, tt.want, a.Required(tt.filePath, nil))
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package release
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	fos "github.com/aquasecurity/fanal/analyzer/os"
+	"github.com/aquasecurity/fanal/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	tos "github.com/aquasecurity/trivy/pkg/analyzer/os"
+
+	// The analyzer of fanal must be registered first so that it is overridden by this one
+	_ "github.com/aquasecurity/fanal/analyzer/os/release"
+)
+
+const version = 2
+
+var requiredFiles = []string{
+	"etc/os-release",
+	"usr/lib/os-release",
+}
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&osReleaseAnalyzer{})
+}
+
+// osReleaseAnalyzer detects the OS with os-release.
+// In addition to the OS families of fanal, Wolfi and Chainguard OS are detected,
+// which are apk-based but don't have /etc/alpine-release.
+// Bottlerocket and Flatcar Container Linux, which have neither apk, dpkg nor rpm, are detected as well.
+type osReleaseAnalyzer struct{}
+
+func (a osReleaseAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	var id, versionID string
+	scanner := bufio.NewScanner(input.Content)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.Trim(strings.TrimSpace(value), `"'`)
+
+		switch key {
+		case "ID":
+			id = value
+		case "VERSION_ID":
+			versionID = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, xerrors.Errorf("scan error %s: %w", input.FilePath, err)
+	}
+
+	var family string
+	switch id {
+	case "alpine":
+		family = fos.Alpine
+	case "opensuse-tumbleweed":
+		family = fos.OpenSUSETumbleweed
+	case "opensuse-leap", "opensuse": // opensuse for leap:42, opensuse-leap for leap:15
+		family = fos.OpenSUSELeap
+	case "sles":
+		family = fos.SLES
+	case "photon":
+		family = fos.Photon
+	case "wolfi":
+		family = tos.Wolfi
+	case "chainguard":
+		family = tos.Chainguard
+	case "bottlerocket":
+		family = tos.Bottlerocket
+	case "flatcar":
+		family = tos.Flatcar
+	}
+
+	if family == "" || versionID == "" {
+		return nil, nil
+	}
+	return &analyzer.AnalysisResult{
+		OS: &types.OS{Family: family, Name: versionID},
+	}, nil
+}
+
+func (a osReleaseAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	return slices.Contains(requiredFiles, filePath)
+}
+
+func (a osReleaseAnalyzer) Type() analyzer.Type {
+	return analyzer.TypeOSRelease
+}
+
+func (a osReleaseAnalyzer) Version() int {
+	return version
+}
@@ -0,0 +1,85 @@
+package release
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	fos "github.com/aquasecurity/fanal/analyzer/os"
+	"github.com/aquasecurity/fanal/types"
+	tos "github.com/aquasecurity/trivy/pkg/analyzer/os"
+)
+
+func Test_osReleaseAnalyzer_Analyze(t *testing.T) {
+	tests := []struct {
+		name      string
+		inputFile string
+		want      *analyzer.AnalysisResult
+	}{
+		{
+			name:      "alpine",
+			inputFile: "testdata/alpine",
+			want: &analyzer.AnalysisResult{
+				OS: &types.OS{Family: fos.Alpine, Name: "3.15.4"},
+			},
+		},
+		{
+			name:      "Wolfi",
+			inputFile: "testdata/wolfi",
+			want: &analyzer.AnalysisResult{
+				OS: &types.OS{Family: tos.Wolfi, Name: "20230201"},
+			},
+		},
+		{
+			name:      "Chainguard",
+			inputFile: "testdata/chainguard",
+			want: &analyzer.AnalysisResult{
+				OS: &types.OS{Family: tos.Chainguard, Name: "20230214"},
+			},
+		},
+		{
+			name:      "Bottlerocket",
+			inputFile: "testdata/bottlerocket",
+			want: &analyzer.AnalysisResult{
+				OS: &types.OS{Family: tos.Bottlerocket, Name: "1.11.1"},
+			},
+		},
+		{
+			name:      "Flatcar",
+			inputFile: "testdata/flatcar",
+			want: &analyzer.AnalysisResult{
+				OS: &types.OS{Family: tos.Flatcar, Name: "3227.2.0"},
+			},
+		},
+		{
+			name:      "Unknown OS",
+			inputFile: "testdata/unknown",
+			want:      nil,
+		},
+		{
+			name:      "No 'VERSION_ID' field",
+			inputFile: "testdata/no-version",
+			want:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.inputFile)
+			require.NoError(t, err)
+			defer f.Close()
+
+			a := osReleaseAnalyzer{}
+			res, err := a.Analyze(context.Background(), analyzer.AnalysisInput{
+				FilePath: "etc/os-release",
+				Content:  f,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, res)
+		})
+	}
+}
@@ -0,0 +1,303 @@
+package windows
+
+import (
+	"encoding/binary"
+	"io"
+	"strings"
+	"unicode/utf16"
+
+	"golang.org/x/xerrors"
+)
+
+// A minimal read-only parser of registry hives, which reads only keys and small values needed by the analyzer.
+// See https://github.com/msuhanov/regf/blob/master/Windows%20registry%20file%20format%20specification.md
+
+const (
+	baseBlockSize = 4096
+
+	// Cells larger than this are not read since keys and values used by the analyzer are small.
+	maxCellSize = 1 << 20
+
+	// Cells are not referenced with this offset
+	nullOffset = 0xffffffff
+
+	keyCompName   = 0x0020
+	valueCompName = 0x0001
+
+	regSZ       = 1
+	regExpandSZ = 2
+	regDWORD    = 4
+)
+
+var errKeyNotFound = xerrors.New("key not found")
+
+type hive struct {
+	r    io.ReaderAt
+	root uint32
+}
+
+type key struct {
+	h             *hive
+	name          string
+	subkeyCount   uint32
+	subkeysOffset uint32
+	valueCount    uint32
+	valuesOffset  uint32
+}
+
+type value struct {
+	name string
+	typ  uint32
+	data []byte
+}
+
+func openHive(r io.ReaderAt) (*hive, error) {
+	base := make([]byte, baseBlockSize)
+	if _, err := r.ReadAt(base, 0); err != nil {
+		return nil, xerrors.Errorf("base block read error: %w", err)
+	}
+	if string(base[:4]) != "regf" {
+		return nil, xerrors.New("invalid signature")
+	}
+	return &hive{
+		r:    r,
+		root: binary.LittleEndian.Uint32(base[0x24:]),
+	}, nil
+}
+
+// cell returns the data of the allocated cell at the offset relative to the hive bins data
+func (h *hive) cell(offset uint32) ([]byte, error) {
+	if offset == nullOffset {
+		return nil, xerrors.New("null offset")
+	}
+	var sizeBuf [4]byte
+	if _, err := h.r.ReadAt(sizeBuf[:], baseBlockSize+int64(offset)); err != nil {
+		return nil, xerrors.Errorf("cell read error: %w", err)
+	}
+
+	// Allocated cells have negative sizes
+	size := -int32(binary.LittleEndian.Uint32(sizeBuf[:]))
+	if size <= 4 || size > maxCellSize {
+		return nil, xerrors.Errorf("invalid cell size at %d: %d", offset, size)
+	}
+
+	data := make([]byte, size-4)
+	if _, err := h.r.ReadAt(data, baseBlockSize+int64(offset)+4); err != nil {
+		return nil, xerrors.Errorf("cell read error: %w", err)
+	}
+	return data, nil
+}
+
+func (h *hive) key(offset uint32) (*key, error) {
+	b, err := h.cell(offset)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < 0x4C || string(b[:2]) != "nk" {
+		return nil, xerrors.Errorf("invalid key node at %d", offset)
+	}
+
+	nameLen := int(binary.LittleEndian.Uint16(b[0x48:]))
+	if 0x4C+nameLen > len(b) {
+		return nil, xerrors.Errorf("invalid key name length at %d", offset)
+	}
+
+	return &key{
+		h:             h,
+		name:          decodeName(b[0x4C:0x4C+nameLen], binary.LittleEndian.Uint16(b[2:])&keyCompName != 0),
+		subkeyCount:   binary.LittleEndian.Uint32(b[0x14:]),
+		subkeysOffset: binary.LittleEndian.Uint32(b[0x1C:]),
+		valueCount:    binary.LittleEndian.Uint32(b[0x24:]),
+		valuesOffset:  binary.LittleEndian.Uint32(b[0x28:]),
+	}, nil
+}
+
+// open returns the key with the path relative to the root key, e.g. Microsoft\Windows NT\CurrentVersion
+func (h *hive) open(path string) (*key, error) {
+	k, err := h.key(h.root)
+	if err != nil {
+		return nil, xerrors.Errorf("root key error: %w", err)
+	}
+	for _, name := range strings.Split(path, `\`) {
+		if k, err = k.subkey(name); err != nil {
+			return nil, err
+		}
+	}
+	return k, nil
+}
+
+func (k *key) subkey(name string) (*key, error) {
+	subkeys, err := k.subkeys()
+	if err != nil {
+		return nil, err
+	}
+	for _, sk := range subkeys {
+		// Key names are case-insensitive
+		if strings.EqualFold(sk.name, name) {
+			return sk, nil
+		}
+	}
+	return nil, xerrors.Errorf("%s: %w", name, errKeyNotFound)
+}
+
+func (k *key) subkeys() ([]*key, error) {
+	if k.subkeyCount == 0 {
+		return nil, nil
+	}
+	offsets, err := k.h.subkeyOffsets(k.subkeysOffset, 0)
+	if err != nil {
+		return nil, xerrors.Errorf("subkey list error in %s: %w", k.name, err)
+	}
+
+	var subkeys []*key
+	for _, offset := range offsets {
+		sk, err := k.h.key(offset)
+		if err != nil {
+			return nil, xerrors.Errorf("subkey error in %s: %w", k.name, err)
+		}
+		subkeys = append(subkeys, sk)
+	}
+	return subkeys, nil
+}
+
+// subkeyOffsets returns offsets of key nodes in the subkey list.
+// "ri" has offsets of other lists, which don't have "ri" in turn.
+func (h *hive) subkeyOffsets(offset uint32, depth int) ([]uint32, error) {
+	b, err := h.cell(offset)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < 4 {
+		return nil, xerrors.Errorf("invalid subkey list at %d", offset)
+	}
+
+	count := int(binary.LittleEndian.Uint16(b[2:]))
+	var offsets []uint32
+	switch sig := string(b[:2]); sig {
+	case "lf", "lh":
+		// Each element has the offset and the hash of the name
+		for i := 0; i < count && 4+8*i+4 <= len(b); i++ {
+			offsets = append(offsets, binary.LittleEndian.Uint32(b[4+8*i:]))
+		}
+	case "li", "ri":
+		for i := 0; i < count && 4+4*i+4 <= len(b); i++ {
+			offsets = append(offsets, binary.LittleEndian.Uint32(b[4+4*i:]))
+		}
+		if sig == "li" {
+			break
+		} else if depth > 0 {
+			return nil, xerrors.Errorf("nested index root at %d", offset)
+		}
+
+		var leaves []uint32
+		for _, o := range offsets {
+			l, err := h.subkeyOffsets(o, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			leaves = append(leaves, l...)
+		}
+		offsets = leaves
+	default:
+		return nil, xerrors.Errorf("unknown subkey list %q at %d", sig, offset)
+	}
+	return offsets, nil
+}
+
+func (k *key) value(name string) (value, bool, error) {
+	if k.valueCount == 0 {
+		return value{}, false, nil
+	}
+	list, err := k.h.cell(k.valuesOffset)
+	if err != nil {
+		return value{}, false, xerrors.Errorf("value list error in %s: %w", k.name, err)
+	}
+
+	for i := 0; i < int(k.valueCount) && 4*i+4 <= len(list); i++ {
+		v, err := k.h.value(binary.LittleEndian.Uint32(list[4*i:]))
+		if err != nil {
+			return value{}, false, xerrors.Errorf("value error in %s: %w", k.name, err)
+		}
+		// Value names are case-insensitive
+		if strings.EqualFold(v.name, name) {
+			return v, true, nil
+		}
+	}
+	return value{}, false, nil
+}
+
+func (h *hive) value(offset uint32) (value, error) {
+	b, err := h.cell(offset)
+	if err != nil {
+		return value{}, err
+	}
+	if len(b) < 0x14 || string(b[:2]) != "vk" {
+		return value{}, xerrors.Errorf("invalid value at %d", offset)
+	}
+
+	nameLen := int(binary.LittleEndian.Uint16(b[2:]))
+	if 0x14+nameLen > len(b) {
+		return value{}, xerrors.Errorf("invalid value name length at %d", offset)
+	}
+	v := value{
+		name: decodeName(b[0x14:0x14+nameLen], binary.LittleEndian.Uint16(b[0x10:])&valueCompName != 0),
+		typ:  binary.LittleEndian.Uint32(b[0xC:]),
+	}
+
+	size := binary.LittleEndian.Uint32(b[4:])
+	if size&0x80000000 != 0 {
+		// Data up to 4 bytes are stored in the offset field
+		size &^= 0x80000000
+		if size > 4 {
+			return value{}, xerrors.Errorf("invalid resident data size at %d", offset)
+		}
+		v.data = b[8 : 8+size]
+		return v, nil
+	}
+
+	data, err := h.cell(binary.LittleEndian.Uint32(b[8:]))
+	if err != nil {
+		return value{}, xerrors.Errorf("value data error: %w", err)
+	} else if int(size) > len(data) {
+		// Big data ("db" cells) isn't supported
+		return value{}, xerrors.Errorf("unsupported data size at %d: %d", offset, size)
+	}
+	v.data = data[:size]
+	return v, nil
+}
+
+// String returns the string of REG_SZ and REG_EXPAND_SZ
+func (v value) String() string {
+	if v.typ != regSZ && v.typ != regExpandSZ {
+		return ""
+	}
+	u := make([]uint16, len(v.data)/2)
+	for i := range u {
+		u[i] = binary.LittleEndian.Uint16(v.data[2*i:])
+	}
+	return strings.TrimRight(string(utf16.Decode(u)), "\x00")
+}
+
+// Uint32 returns the number of REG_DWORD
+func (v value) Uint32() (uint32, bool) {
+	if v.typ != regDWORD || len(v.data) != 4 {
+		return 0, false
+	}
+	return binary.LittleEndian.Uint32(v.data), true
+}
+
+// decodeName decodes names of keys and values, which are in ASCII (Latin-1) when compressed, otherwise in UTF-16LE
+func decodeName(b []byte, compressed bool) string {
+	if compressed {
+		r := make([]rune, len(b))
+		for i, c := range b {
+			r[i] = rune(c)
+		}
+		return string(r)
+	}
+	u := make([]uint16, len(b)/2)
+	for i := range u {
+		u[i] = binary.LittleEndian.Uint16(b[2*i:])
+	}
+	return string(utf16.Decode(u))
+}
@@ -0,0 +1,100 @@
+package windows
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+)
+
+func Test_hive_open(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		wantSubkeys []string
+		wantErr     error
+	}{
+		{
+			name: "subkeys in lf list",
+			path: `Microsoft\ServerManager\ServicingStorage\ServerComponentCache`,
+			wantSubkeys: []string{
+				"NET-Framework-45-Core",
+				"Web-Server",
+			},
+		},
+		{
+			name: "subkeys in ri list",
+			path: `microsoft\windows\currentversion\component based servicing\packages`,
+			wantSubkeys: []string{
+				"Microsoft-Windows-Foundation-Package~31bf3856ad364e35~amd64~~10.0.17763.1",
+				"Package_1_for_KB5017315~31bf3856ad364e35~amd64~~17763.3406.1.5",
+				"Package_for_KB4589208~31bf3856ad364e35~amd64~~17763.1.1.0",
+				"Package_for_KB5005112~31bf3856ad364e35~amd64~~17763.2090.1.2",
+				"Package_for_KB5017315~31bf3856ad364e35~amd64~~17763.3406.1.5",
+				"Package_for_RollupFix~31bf3856ad364e35~amd64~~17763.3406.1.5",
+			},
+		},
+		{
+			name:    "not found",
+			path:    `Microsoft\Windows Defender`,
+			wantErr: errKeyNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open("testdata/SOFTWARE")
+			require.NoError(t, err)
+			defer f.Close()
+
+			h, err := openHive(f)
+			require.NoError(t, err)
+
+			k, err := h.open(tt.path)
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				assert.True(t, xerrors.Is(err, tt.wantErr), err)
+				return
+			}
+			require.NoError(t, err)
+
+			subkeys, err := k.subkeys()
+			require.NoError(t, err)
+
+			var names []string
+			for _, sk := range subkeys {
+				names = append(names, sk.name)
+			}
+			assert.ElementsMatch(t, tt.wantSubkeys, names)
+		})
+	}
+}
+
+func Test_key_value(t *testing.T) {
+	f, err := os.Open("testdata/SOFTWARE")
+	require.NoError(t, err)
+	defer f.Close()
+
+	h, err := openHive(f)
+	require.NoError(t, err)
+
+	k, err := h.open(`Microsoft\Windows NT\CurrentVersion`)
+	require.NoError(t, err)
+
+	v, ok, err := k.value("ProductName")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "Windows Server 2019 Datacenter", v.String())
+
+	v, ok, err = k.value("ubr")
+	require.NoError(t, err)
+	require.True(t, ok)
+	n, ok := v.Uint32()
+	require.True(t, ok)
+	assert.Equal(t, uint32(3406), n)
+
+	_, ok, err = k.value("ReleaseId")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,243 @@
+package windows
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	tos "github.com/aquasecurity/trivy/pkg/analyzer/os"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+const (
+	version = 1
+
+	currentVersionKey = `Microsoft\Windows NT\CurrentVersion`
+	cbsPackagesKey    = `Microsoft\Windows\CurrentVersion\Component Based Servicing\Packages`
+	featuresKey       = `Microsoft\ServerManager\ServicingStorage\ServerComponentCache`
+
+	// CurrentState of CBS packages which are installed
+	cbsInstalled = 0x70
+
+	// InstallState of features which are installed
+	featureInstalled = 1
+)
+
+var (
+	// The SOFTWARE hive in layers of container images has the "Files" prefix, and that in root filesystems doesn't.
+	requiredFiles = []string{
+		"Files/Windows/System32/config/SOFTWARE",
+		"Windows/System32/config/SOFTWARE",
+	}
+
+	// CBS packages of updates, e.g. Package_for_KB5005112~31bf3856ad364e35~amd64~~17763.2090.1.2
+	// and Package_1_for_KB5017315~31bf3856ad364e35~amd64~~17763.3406.1.5
+	kbPackageRegexp = regexp.MustCompile(`^Package_(?:\d+_)?for_(KB\d+)~[^~]*~([^~]*)~[^~]*~(.+)$`)
+)
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&registryAnalyzer{})
+}
+
+// registryAnalyzer detects the build of Windows, installed updates (KBs) and features in the SOFTWARE registry hive.
+// Layers other than the base layer have only differences of hives, which are not parsed,
+// so updates and features installed in the Dockerfile are not detected.
+type registryAnalyzer struct{}
+
+func (a registryAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	h, err := openHive(input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to open the hive %s: %w", input.FilePath, err)
+	}
+
+	osVer, err := osVersion(h)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to detect the Windows version: %w", err)
+	}
+
+	pkgs := []types.Package{
+		{
+			Name:    tos.WindowsPkg,
+			Version: osVer,
+		},
+	}
+
+	kbs, err := installedKBs(h)
+	if err != nil {
+		log.Logger.Debugf("Unable to detect installed updates in %s: %s", input.FilePath, err)
+	}
+	pkgs = append(pkgs, kbs...)
+
+	features, err := installedFeatures(h, osVer)
+	if err != nil {
+		log.Logger.Debugf("Unable to detect installed features in %s: %s", input.FilePath, err)
+	}
+	pkgs = append(pkgs, features...)
+
+	return &analyzer.AnalysisResult{
+		OS: &types.OS{
+			Family: tos.Windows,
+			Name:   osVer,
+		},
+		PackageInfos: []types.PackageInfo{
+			{
+				FilePath: input.FilePath,
+				Packages: pkgs,
+			},
+		},
+	}, nil
+}
+
+// osVersion returns the version with the update build revision, e.g. 10.0.17763.3406
+func osVersion(h *hive) (string, error) {
+	k, err := h.open(currentVersionKey)
+	if err != nil {
+		return "", xerrors.Errorf("unable to open %s: %w", currentVersionKey, err)
+	}
+
+	build, err := stringValue(k, "CurrentBuildNumber")
+	if err != nil {
+		return "", err
+	}
+
+	// Windows 10 and later have the major and minor versions in DWORD, and "CurrentVersion" is always 6.3
+	var ver string
+	major, majorErr := dwordValue(k, "CurrentMajorVersionNumber")
+	minor, minorErr := dwordValue(k, "CurrentMinorVersionNumber")
+	if majorErr == nil && minorErr == nil {
+		ver = fmt.Sprintf("%d.%d.%s", major, minor, build)
+	} else {
+		cur, err := stringValue(k, "CurrentVersion")
+		if err != nil {
+			return "", err
+		}
+		ver = fmt.Sprintf("%s.%s", cur, build)
+	}
+
+	// The update build revision is incremented by cumulative updates
+	if ubr, err := dwordValue(k, "UBR"); err == nil {
+		ver = fmt.Sprintf("%s.%d", ver, ubr)
+	}
+	return ver, nil
+}
+
+// installedKBs returns updates installed with Component Based Servicing.
+// An update may consist of multiple CBS packages, and it is reported once.
+func installedKBs(h *hive) ([]types.Package, error) {
+	k, err := h.open(cbsPackagesKey)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to open %s: %w", cbsPackagesKey, err)
+	}
+	subkeys, err := k.subkeys()
+	if err != nil {
+		return nil, err
+	}
+
+	kbs := map[string]types.Package{}
+	for _, sk := range subkeys {
+		m := kbPackageRegexp.FindStringSubmatch(sk.name)
+		if m == nil {
+			continue
+		}
+		if state, err := dwordValue(sk, "CurrentState"); err != nil || state != cbsInstalled {
+			continue
+		}
+		kbs[m[1]] = types.Package{
+			Name:    m[1],
+			Version: m[3],
+			Arch:    m[2],
+		}
+	}
+	return sortedPkgs(kbs), nil
+}
+
+// installedFeatures returns features installed by Server Manager, such as Web-Server.
+// Features are serviced by cumulative updates, so they have the version of Windows.
+func installedFeatures(h *hive, osVer string) ([]types.Package, error) {
+	k, err := h.open(featuresKey)
+	if xerrors.Is(err, errKeyNotFound) {
+		// e.g. Nano Server
+		return nil, nil
+	} else if err != nil {
+		return nil, xerrors.Errorf("unable to open %s: %w", featuresKey, err)
+	}
+	subkeys, err := k.subkeys()
+	if err != nil {
+		return nil, err
+	}
+
+	features := map[string]types.Package{}
+	for _, sk := range subkeys {
+		if state, err := dwordValue(sk, "InstallState"); err != nil || state != featureInstalled {
+			continue
+		}
+		features[sk.name] = types.Package{
+			Name:    sk.name,
+			Version: osVer,
+		}
+	}
+	return sortedPkgs(features), nil
+}
+
+func stringValue(k *key, name string) (string, error) {
+	v, ok, err := k.value(name)
+	if err != nil {
+		return "", err
+	} else if !ok || v.String() == "" {
+		return "", xerrors.Errorf("%s not found in %s", name, k.name)
+	}
+	return v.String(), nil
+}
+
+func dwordValue(k *key, name string) (uint32, error) {
+	v, ok, err := k.value(name)
+	if err != nil {
+		return 0, err
+	} else if !ok {
+		return 0, xerrors.Errorf("%s not found in %s", name, k.name)
+	}
+	n, ok := v.Uint32()
+	if !ok {
+		return 0, xerrors.Errorf("%s is not DWORD", name)
+	}
+	return n, nil
+}
+
+func sortedPkgs(m map[string]types.Package) []types.Package {
+	var pkgs []types.Package
+	for _, pkg := range m {
+		pkgs = append(pkgs, pkg)
+	}
+	sort.Slice(pkgs, func(i, j int) bool {
+		return pkgs[i].Name < pkgs[j].Name
+	})
+	return pkgs
+}
+
+func (a registryAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	filePath = filepath.ToSlash(filePath)
+	for _, f := range requiredFiles {
+		// Paths are case-insensitive on Windows
+		if strings.EqualFold(filePath, f) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a registryAnalyzer) Type() analyzer.Type {
+	return tos.TypeWindowsRegistry
+}
+
+func (a registryAnalyzer) Version() int {
+	return version
+}
@@ -0,0 +1,118 @@
+package windows
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/types"
+)
+
+func Test_registryAnalyzer_Analyze(t *testing.T) {
+	tests := []struct {
+		name      string
+		inputFile string
+		want      *analyzer.AnalysisResult
+		wantErr   string
+	}{
+		{
+			name:      "Windows Server 2019",
+			inputFile: "testdata/SOFTWARE",
+			want: &analyzer.AnalysisResult{
+				OS: &types.OS{
+					Family: "windows",
+					Name:   "10.0.17763.3406",
+				},
+				PackageInfos: []types.PackageInfo{
+					{
+						FilePath: "Files/Windows/System32/config/SOFTWARE",
+						Packages: []types.Package{
+							{
+								Name:    "windows",
+								Version: "10.0.17763.3406",
+							},
+							{
+								Name:    "KB5005112",
+								Version: "17763.2090.1.2",
+								Arch:    "amd64",
+							},
+							{
+								Name:    "KB5017315",
+								Version: "17763.3406.1.5",
+								Arch:    "amd64",
+							},
+							{
+								Name:    "NET-Framework-45-Core",
+								Version: "10.0.17763.3406",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "broken",
+			inputFile: "testdata/broken",
+			wantErr:   "invalid signature",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.inputFile)
+			require.NoError(t, err)
+			defer f.Close()
+
+			a := registryAnalyzer{}
+			got, err := a.Analyze(context.Background(), analyzer.AnalysisInput{
+				FilePath: "Files/Windows/System32/config/SOFTWARE",
+				Content:  f,
+			})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_registryAnalyzer_Required(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		want     bool
+	}{
+		{
+			name:     "layer",
+			filePath: "Files/Windows/System32/config/SOFTWARE",
+			want:     true,
+		},
+		{
+			name:     "root filesystem",
+			filePath: "Windows/System32/config/SOFTWARE",
+			want:     true,
+		},
+		{
+			name:     "different case",
+			filePath: "Files/Windows/system32/config/software",
+			want:     true,
+		},
+		{
+			name:     "SYSTEM hive",
+			filePath: "Files/Windows/System32/config/SYSTEM",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := registryAnalyzer{}
+			assert.Equal(t, tt.want, a.Required(tt.filePath, nil))
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package sbom
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/sbom"
+)
+
+const (
+	version = 1
+
+	// TypeEmbeddedSBOM is disabled in repository scanning since the directories are in images
+	TypeEmbeddedSBOM = analyzer.Type("embedded-sbom")
+)
+
+// Directories where images ship SBOM files of installed packages,
+// e.g. var/lib/db/sbom/openssl-3.0.7-r0.spdx.json of Wolfi and Chainguard images
+var sbomDirs = []string{
+	"var/lib/db/sbom/",
+	"usr/share/spdx/",
+}
+
+func init() {
+	tanalyzer.RegisterAnalyzer(&sbomAnalyzer{})
+}
+
+// sbomAnalyzer detects packages listed in SBOM files embedded in images.
+// OS packages are stored in a custom resource and merged with those of the package manager when scanning,
+// so that images without the package database, such as distroless images, are also scanned.
+// Language packages are reported as an application with the path of the SBOM file.
+type sbomAnalyzer struct{}
+
+func (a sbomAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	format, err := sbom.DetectFormat(input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to detect the SBOM format of %s: %w", input.FilePath, err)
+	} else if format == sbom.FormatUnknown {
+		log.Logger.Debugf("Skipping the unknown SBOM format: %s", input.FilePath)
+		return nil, nil
+	}
+
+	bom, err := sbom.Decode(input.Content, format, sbom.Option{})
+	if err != nil {
+		return nil, xerrors.Errorf("unable to decode %s: %w", input.FilePath, err)
+	}
+
+	result := &analyzer.AnalysisResult{}
+	var pkgs []types.Package
+	for _, info := range bom.Packages {
+		pkgs = append(pkgs, info.Packages...)
+	}
+	if len(pkgs) > 0 {
+		result.CustomResources = []types.CustomResource{
+			{
+				Type:     sbom.EmbeddedType,
+				FilePath: input.FilePath,
+				Data: sbom.Embedded{
+					OS:       bom.OS,
+					Packages: pkgs,
+				},
+			},
+		}
+	}
+
+	// Applications are reported with the SBOM file so that they are not merged with those detected from lock files
+	for _, app := range bom.Applications {
+		app.FilePath = input.FilePath
+		result.Applications = append(result.Applications, app)
+	}
+
+	return result, nil
+}
+
+func (a sbomAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	filePath = filepath.ToSlash(filePath)
+	for _, dir := range sbomDirs {
+		if strings.HasPrefix(filePath, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a sbomAnalyzer) Type() analyzer.Type {
+	return TypeEmbeddedSBOM
+}
+
+func (a sbomAnalyzer) Version() int {
+	return version
+}
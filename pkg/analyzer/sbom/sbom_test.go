@@ -0,0 +1,112 @@
+package sbom
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/sbom"
+)
+
+func Test_sbomAnalyzer_Analyze(t *testing.T) {
+	tests := []struct {
+		name      string
+		inputFile string
+		want      *analyzer.AnalysisResult
+	}{
+		{
+			name:      "SPDX",
+			inputFile: "testdata/openssl-3.0.7-r0.spdx.json",
+			want: &analyzer.AnalysisResult{
+				Applications: []types.Application{
+					{
+						Type:     "python-pkg",
+						FilePath: "testdata/openssl-3.0.7-r0.spdx.json",
+						Libraries: []types.Package{
+							{
+								Name:    "cryptography",
+								Version: "38.0.3",
+							},
+						},
+					},
+				},
+				CustomResources: []types.CustomResource{
+					{
+						Type:     sbom.EmbeddedType,
+						FilePath: "testdata/openssl-3.0.7-r0.spdx.json",
+						Data: sbom.Embedded{
+							OS: &types.OS{
+								Family: "wolfi",
+								Name:   "20221118",
+							},
+							Packages: []types.Package{
+								{
+									Name:       "libcrypto3",
+									Version:    "3.0.7-r0",
+									Arch:       "x86_64",
+									License:    "Apache-2.0",
+									SrcName:    "libcrypto3",
+									SrcVersion: "3.0.7-r0",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "unknown format",
+			inputFile: "testdata/unknown.txt",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.inputFile)
+			require.NoError(t, err)
+			defer f.Close()
+
+			a := sbomAnalyzer{}
+			got, err := a.Analyze(context.Background(), analyzer.AnalysisInput{
+				FilePath: tt.inputFile,
+				Content:  f,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_sbomAnalyzer_Required(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		want     bool
+	}{
+		{
+			name:     "Wolfi",
+			filePath: "var/lib/db/sbom/openssl-3.0.7-r0.spdx.json",
+			want:     true,
+		},
+		{
+			name:     "spdx directory",
+			filePath: "usr/share/spdx/app.cdx.json",
+			want:     true,
+		},
+		{
+			name:     "sad path",
+			filePath: "app/sbom.spdx.json",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := sbomAnalyzer{}
+			assert.Equal(t, tt.want, a.Required(tt.filePath, nil))
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package secret
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	dio "github.com/aquasecurity/go-dep-parser/pkg/io"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/secret"
+)
+
+const (
+	// Archives larger than this are not scanned
+	maxArchiveSize = 100 << 20
+
+	// Files in archives larger than this are not scanned
+	maxArchiveEntrySize = 10 << 20
+
+	// archiveSeparator separates the archive path and the path in the archive, e.g. app.jar!/config.properties
+	archiveSeparator = "!/"
+)
+
+var (
+	zipExts = []string{".zip", ".jar", ".war", ".ear"}
+	tarExts = []string{".tar", ".tar.gz", ".tgz"}
+)
+
+// archiveEntryFunc is called for each regular file in the archive
+type archiveEntryFunc func(name string, size int64, r io.Reader) error
+
+func isArchive(filePath string) bool {
+	return hasExt(filePath, zipExts) || hasExt(filePath, tarExts)
+}
+
+func hasExt(filePath string, exts []string) bool {
+	filePath = strings.ToLower(filePath)
+	for _, ext := range exts {
+		if strings.HasSuffix(filePath, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// analyzeArchive scans files in the archive.
+// Nested archives are not scanned.
+func (a SecretAnalyzer) analyzeArchive(ctx context.Context, filePath string, r dio.ReadSeekerAt, size int64) (*analyzer.AnalysisResult, error) {
+	result := &analyzer.AnalysisResult{}
+	err := walkArchive(filePath, r, size, func(name string, size int64, r io.Reader) error {
+		entryPath := filePath + archiveSeparator + name
+		if size < 10 || size > maxArchiveEntrySize || isArchive(name) || !a.requiredPath(entryPath) {
+			return nil
+		}
+
+		b, err := io.ReadAll(io.LimitReader(r, maxArchiveEntrySize))
+		if err != nil {
+			return xerrors.Errorf("read error: %w", err)
+		}
+
+		content, err := a.readContent(bytes.NewReader(b), int64(len(b)))
+		if err != nil {
+			return xerrors.Errorf("read error: %w", err)
+		} else if content == nil {
+			return nil
+		}
+
+		if res := a.analyze(ctx, secret.ScanArgs{FilePath: entryPath, Content: content}); res != nil {
+			result.Secrets = append(result.Secrets, res.Secrets...)
+			result.CustomResources = append(result.CustomResources, res.CustomResources...)
+		}
+		return nil
+	})
+	if err != nil {
+		// Broken archives should not fail the whole scan
+		log.Logger.Debugf("Unable to scan the archive for secrets (%s): %s", filePath, err)
+		return nil, nil
+	}
+
+	if len(result.Secrets) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
+
+func walkArchive(filePath string, r dio.ReadSeekerAt, size int64, fn archiveEntryFunc) error {
+	if hasExt(filePath, zipExts) {
+		return walkZip(r, size, fn)
+	}
+	return walkTar(filePath, r, fn)
+}
+
+func walkZip(r io.ReaderAt, size int64, fn archiveEntryFunc) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return xerrors.Errorf("zip error: %w", err)
+	}
+
+	for _, f := range zr.File {
+		if !f.Mode().IsRegular() {
+			continue
+		}
+		if err = walkZipFile(f, fn); err != nil {
+			return xerrors.Errorf("%s: %w", f.Name, err)
+		}
+	}
+	return nil
+}
+
+func walkZipFile(f *zip.File, fn archiveEntryFunc) error {
+	rc, err := f.Open()
+	if err != nil {
+		return xerrors.Errorf("open error: %w", err)
+	}
+	defer rc.Close()
+
+	return fn(path.Clean(f.Name), int64(f.UncompressedSize64), rc)
+}
+
+func walkTar(filePath string, r io.Reader, fn archiveEntryFunc) error {
+	if !strings.HasSuffix(strings.ToLower(filePath), ".tar") {
+		gr, err := gzip.NewReader(r)
+		if err != nil {
+			return xerrors.Errorf("gzip error: %w", err)
+		}
+		defer gr.Close()
+		r = gr
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return xerrors.Errorf("tar error: %w", err)
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err = fn(path.Clean(strings.TrimPrefix(hdr.Name, "/")), hdr.Size, tr); err != nil {
+			return xerrors.Errorf("%s: %w", hdr.Name, err)
+		}
+	}
+}
@@ -0,0 +1,334 @@
+package secret
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/secret"
+	ttypes "github.com/aquasecurity/trivy/pkg/types"
+)
+
+// HistoryType is the type of custom resources holding secrets found in the git history
+const HistoryType = "trivy-secret-history"
+
+// Files larger than this are not scanned in the history
+const maxHistoryFileSize = 10 << 20
+
+type historySecret struct {
+	repoPath  string // relative to the repository root
+	filePath  string // relative to the scan root
+	detection secret.Detection
+	commit    *object.Commit
+}
+
+// ScanHistory scans files changed in the last "depth" commits from HEAD for secrets.
+// All the commits are scanned if depth is not positive.
+// It returns custom resources holding the secrets with the commits introducing them, one per file.
+func ScanHistory(ctx context.Context, opt ScannerOption, rootPath string, depth int) ([]types.CustomResource, error) {
+	a, err := newSecretAnalyzer(opt)
+	if err != nil {
+		return nil, xerrors.Errorf("secret scanner error: %w", err)
+	}
+
+	r, err := git.PlainOpenWithOptions(rootPath, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, xerrors.Errorf("git open error: %w", err)
+	}
+
+	relPath, err := pathFromRepoRoot(r, rootPath)
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := r.Head()
+	if err != nil {
+		return nil, xerrors.Errorf("git HEAD error: %w", err)
+	}
+
+	commits, err := historyCommits(r, head.Hash(), depth)
+	if err != nil {
+		return nil, err
+	}
+	log.Logger.Debugf("Scanning %d commits for secrets...", len(commits))
+
+	// Secrets are keyed by the file, the rule and the secret.
+	// The commits are scanned from the oldest so that the first commit introducing the secret is recorded.
+	var found []*historySecret
+	seen := map[string]struct{}{}
+	for i := len(commits) - 1; i >= 0; i-- {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		c := commits[i]
+		secrets, err := a.scanCommit(c, relPath)
+		if err != nil {
+			return nil, xerrors.Errorf("commit %s: %w", c.Hash, err)
+		}
+		for _, s := range secrets {
+			key := historyKey(s.filePath, s.detection)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			found = append(found, s)
+		}
+	}
+
+	headCommit, err := r.CommitObject(head.Hash())
+	if err != nil {
+		return nil, xerrors.Errorf("unable to get the HEAD commit: %w", err)
+	}
+	headTree, err := headCommit.Tree()
+	if err != nil {
+		return nil, xerrors.Errorf("unable to get the HEAD tree: %w", err)
+	}
+
+	return a.historyResources(ctx, headTree, found), nil
+}
+
+// historyCommits returns up to "depth" commits reachable from the hash, the newest first
+func historyCommits(r *git.Repository, hash plumbing.Hash, depth int) ([]*object.Commit, error) {
+	iter, err := r.Log(&git.LogOptions{From: hash})
+	if err != nil {
+		return nil, xerrors.Errorf("git log error: %w", err)
+	}
+	defer iter.Close()
+
+	var commits []*object.Commit
+	err = iter.ForEach(func(c *object.Commit) error {
+		if depth > 0 && len(commits) >= depth {
+			return storer.ErrStop
+		}
+		commits = append(commits, c)
+		return nil
+	})
+	// The history may be truncated in a shallow clone
+	if err != nil && !errors.Is(err, plumbing.ErrObjectNotFound) {
+		return nil, xerrors.Errorf("git log error: %w", err)
+	}
+	return commits, nil
+}
+
+// scanCommit scans files added or modified in the commit
+func (a SecretAnalyzer) scanCommit(c *object.Commit, relPath string) ([]*historySecret, error) {
+	tree, err := c.Tree()
+	if err != nil {
+		return nil, xerrors.Errorf("tree error: %w", err)
+	}
+
+	// The parent is not available in the first commit or at the boundary of a shallow clone
+	var parentTree *object.Tree
+	if parent, err := c.Parent(0); err == nil {
+		if parentTree, err = parent.Tree(); err != nil {
+			return nil, xerrors.Errorf("parent tree error: %w", err)
+		}
+	}
+
+	changes, err := object.DiffTree(parentTree, tree)
+	if err != nil {
+		return nil, xerrors.Errorf("git diff error: %w", err)
+	}
+
+	var secrets []*historySecret
+	for _, change := range changes {
+		// Deleted files
+		if change.To.Name == "" {
+			continue
+		}
+
+		filePath, ok := pathFromScanRoot(change.To.Name, relPath)
+		if !ok || !a.requiredPath(filePath) {
+			continue
+		}
+
+		content, err := fileContent(tree, change.To.Name)
+		if err != nil {
+			return nil, err
+		} else if content == nil {
+			continue
+		}
+
+		for _, d := range a.scanner.Detect(secret.ScanArgs{FilePath: filePath, Content: content}) {
+			secrets = append(secrets, &historySecret{
+				repoPath:  change.To.Name,
+				filePath:  filePath,
+				detection: d,
+				commit:    c,
+			})
+		}
+	}
+	return secrets, nil
+}
+
+// historyResources checks if the secrets still exist at HEAD and converts them into custom resources
+func (a SecretAnalyzer) historyResources(ctx context.Context, headTree *object.Tree, found []*historySecret) []types.CustomResource {
+	// Detect secrets in the files at HEAD so that the secrets can be compared
+	headDetections := map[string]secret.Detection{}
+	scanned := map[string]struct{}{}
+	for _, s := range found {
+		if _, ok := scanned[s.filePath]; ok {
+			continue
+		}
+		scanned[s.filePath] = struct{}{}
+
+		content, err := fileContent(headTree, s.repoPath)
+		if err != nil || content == nil {
+			// Deleted at HEAD
+			continue
+		}
+		for _, d := range a.scanner.Detect(secret.ScanArgs{FilePath: s.filePath, Content: content}) {
+			headDetections[historyKey(s.filePath, d)] = d
+		}
+	}
+
+	var verifications map[string]ttypes.SecretVerification
+	if a.verifier != nil {
+		verifications = a.verifyHistory(ctx, found)
+	}
+
+	secretsByFile := map[string][]ttypes.DetectedSecret{}
+	for _, s := range found {
+		// Report the location at HEAD if the secret still exists
+		d, existsAtHead := headDetections[historyKey(s.filePath, s.detection)]
+		if !existsAtHead {
+			d = s.detection
+		}
+
+		secretsByFile[s.filePath] = append(secretsByFile[s.filePath], ttypes.DetectedSecret{
+			SecretFinding: d.SecretFinding,
+			Fingerprint:   secret.Fingerprint(s.filePath, s.detection),
+			Verification:  verifications[historyKey(s.filePath, s.detection)],
+			History: &ttypes.SecretHistory{
+				Commit:       s.commit.Hash.String(),
+				Author:       s.commit.Author.Name,
+				Date:         s.commit.Author.When.UTC(),
+				ExistsAtHead: existsAtHead,
+			},
+		})
+	}
+
+	var resources []types.CustomResource
+	for filePath, secrets := range secretsByFile {
+		resources = append(resources, types.CustomResource{
+			Type:     HistoryType,
+			FilePath: filePath,
+			Data:     secrets,
+		})
+	}
+
+	// For consistent cache keys
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].FilePath < resources[j].FilePath
+	})
+	return resources
+}
+
+// verifyHistory verifies the secrets per file as AWS keys are verified in pairs
+func (a SecretAnalyzer) verifyHistory(ctx context.Context, found []*historySecret) map[string]ttypes.SecretVerification {
+	detections := map[string][]secret.Detection{}
+	for _, s := range found {
+		detections[s.filePath] = append(detections[s.filePath], s.detection)
+	}
+
+	verifications := map[string]ttypes.SecretVerification{}
+	for filePath, ds := range detections {
+		for _, v := range a.verifier.Verify(ctx, ds) {
+			for _, d := range ds {
+				if d.RuleID == v.RuleID && d.StartLine == v.StartLine {
+					verifications[historyKey(filePath, d)] = v.Status
+				}
+			}
+		}
+	}
+	return verifications
+}
+
+// fileContent returns the content of the file in the tree.
+// It returns nil if the file doesn't exist or should not be scanned.
+func fileContent(tree *object.Tree, name string) ([]byte, error) {
+	f, err := tree.File(name)
+	if errors.Is(err, object.ErrFileNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, xerrors.Errorf("file error (%s): %w", name, err)
+	}
+
+	// Skip symbolic links, submodules, large files and small files
+	if !f.Mode.IsFile() || f.Size > maxHistoryFileSize || f.Size < 10 {
+		return nil, nil
+	}
+
+	r, err := f.Reader()
+	if err != nil {
+		return nil, xerrors.Errorf("file open error (%s): %w", name, err)
+	}
+	defer r.Close()
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, xerrors.Errorf("file read error (%s): %w", name, err)
+	}
+
+	if binary, err := isBinary(bytes.NewReader(content), int64(len(content))); binary || err != nil {
+		return nil, nil
+	}
+	return content, nil
+}
+
+// pathFromRepoRoot returns the slash-separated path of the scan root relative to the repository root
+func pathFromRepoRoot(r *git.Repository, rootPath string) (string, error) {
+	wt, err := r.Worktree()
+	if err != nil {
+		return "", xerrors.Errorf("git worktree error: %w", err)
+	}
+
+	repoRoot, err := filepath.EvalSymlinks(wt.Filesystem.Root())
+	if err != nil {
+		return "", xerrors.Errorf("unable to resolve the repository root: %w", err)
+	}
+
+	absPath, err := filepath.Abs(rootPath)
+	if err != nil {
+		return "", xerrors.Errorf("absolute path error: %w", err)
+	}
+	absPath, err = filepath.EvalSymlinks(absPath)
+	if err != nil {
+		return "", xerrors.Errorf("unable to resolve %s: %w", rootPath, err)
+	}
+
+	rel, err := filepath.Rel(repoRoot, absPath)
+	if err != nil {
+		return "", xerrors.Errorf("relative path error: %w", err)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// pathFromScanRoot converts the path relative to the repository root into the one relative to the scan root.
+// It returns false if the file is out of the scan root.
+func pathFromScanRoot(name, relPath string) (string, bool) {
+	if relPath == "." {
+		return name, true
+	}
+	if !strings.HasPrefix(name, relPath+"/") {
+		return "", false
+	}
+	return strings.TrimPrefix(name, relPath+"/"), true
+}
+
+func historyKey(filePath string, d secret.Detection) string {
+	return filePath + "\x00" + d.RuleID + "\x00" + d.Secret
+}
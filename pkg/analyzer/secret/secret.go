@@ -0,0 +1,316 @@
+// Package secret is forked from the secret analyzer of fanal, see forkedFanalVersion in pkg/secret.
+package secret
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/samber/lo"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/types"
+	dio "github.com/aquasecurity/go-dep-parser/pkg/io"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/ignore"
+	"github.com/aquasecurity/trivy/pkg/secret"
+)
+
+const (
+	// The options changing the results are mixed into cache keys by the artifacts, see CacheKeyOption
+	version = 4
+
+	// Binaries larger than this are not scanned even if binary scanning is enabled
+	maxBinarySize = 10 << 20
+
+	// Printable strings shorter than this are not extracted from binaries
+	minStringLength = 8
+)
+
+var (
+	skipFiles = []string{
+		"go.mod",
+		"go.sum",
+		"package-lock.json",
+		"yarn.lock",
+		"pnpm-lock.yaml",
+		"Pipfile.lock",
+		"Gemfile.lock",
+		"gradle.lockfile",
+		"conan.lock",
+	}
+	skipDirs = []string{".git", "node_modules", "vendor"}
+	skipExts = []string{
+		".jpg", ".png", ".gif", ".doc", ".pdf", ".bin", ".svg", ".socket", ".deb", ".rpm",
+		".zip", ".gz", ".gzip", ".tar", ".pyc",
+	}
+)
+
+type ScannerOption struct {
+	ConfigPath string
+
+	// Verify makes detected secrets verified against the providers
+	Verify bool
+
+	// ScanArchives makes files in zip, jar and tar archives scanned
+	ScanArchives bool
+
+	// ScanBinaries makes printable strings in small binaries scanned
+	ScanBinaries bool
+
+	// Redaction is how secrets are redacted in findings. Secrets are fully masked by default.
+	Redaction secret.Redaction
+}
+
+// CacheKeyOption returns the options to be mixed into cache keys, or nil if they are the default ones.
+// Results with different options must not be mixed in the cache.
+func CacheKeyOption(opt ScannerOption) *ScannerOption {
+	if opt == (ScannerOption{}) {
+		return nil
+	}
+	return &opt
+}
+
+// SecretAnalyzer is an analyzer for secrets
+type SecretAnalyzer struct {
+	scanner      secret.Scanner
+	verifier     *secret.Verifier
+	configPath   string
+	scanArchives bool
+	scanBinaries bool
+	redaction    secret.Redaction
+}
+
+func RegisterSecretAnalyzer(opt ScannerOption) error {
+	a, err := newSecretAnalyzer(opt)
+	if err != nil {
+		return xerrors.Errorf("secret scanner init error: %w", err)
+	}
+	tanalyzer.RegisterAnalyzer(a)
+	return nil
+}
+
+func newSecretAnalyzer(opt ScannerOption) (SecretAnalyzer, error) {
+	redaction := opt.Redaction
+	if redaction == "" {
+		redaction = secret.RedactionFull
+	}
+
+	s, err := secret.NewScanner(opt.ConfigPath, secret.WithRedaction(redaction))
+	if err != nil {
+		return SecretAnalyzer{}, xerrors.Errorf("secret scanner error: %w", err)
+	}
+
+	var verifier *secret.Verifier
+	if opt.Verify {
+		verifier = secret.NewVerifier()
+	}
+	return SecretAnalyzer{
+		scanner:      s,
+		verifier:     verifier,
+		configPath:   opt.ConfigPath,
+		scanArchives: opt.ScanArchives,
+		scanBinaries: opt.ScanBinaries,
+		redaction:    redaction,
+	}, nil
+}
+
+func (a SecretAnalyzer) Analyze(ctx context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	filePath := input.FilePath
+	// Files extracted from the image have an empty input.Dir.
+	// Also, paths to these files do not have "/" prefix.
+	// We need to add a "/" prefix to properly filter paths from the config file.
+	if input.Dir == "" { // add leading `/` for files extracted from image
+		filePath = fmt.Sprintf("/%s", filePath)
+	}
+
+	if a.scanArchives && isArchive(filePath) {
+		return a.analyzeArchive(ctx, filePath, input.Content, input.Info.Size())
+	}
+
+	content, err := a.readContent(input.Content, input.Info.Size())
+	if err != nil {
+		return nil, xerrors.Errorf("read error %s: %w", input.FilePath, err)
+	} else if content == nil {
+		return nil, nil
+	}
+
+	return a.analyze(ctx, secret.ScanArgs{
+		FilePath: filePath,
+		Content:  content,
+	}), nil
+}
+
+// readContent returns the content to be scanned.
+// Only printable strings are returned for binaries, and nil is returned if the content should not be scanned.
+func (a SecretAnalyzer) readContent(r dio.ReadSeekerAt, size int64) ([]byte, error) {
+	binary, err := isBinary(r, size)
+	if err != nil {
+		// e.g. empty files
+		return nil, nil
+	} else if binary && (!a.scanBinaries || size > maxBinarySize) {
+		// Do not scan binaries
+		return nil, nil
+	}
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if binary {
+		return extractStrings(content), nil
+	}
+	return content, nil
+}
+
+func (a SecretAnalyzer) analyze(ctx context.Context, args secret.ScanArgs) *analyzer.AnalysisResult {
+	detections := a.scanner.Detect(args)
+	if len(detections) == 0 {
+		return nil
+	}
+
+	result := &analyzer.AnalysisResult{
+		Secrets: []types.Secret{
+			{
+				FilePath: args.FilePath,
+				Findings: lo.Map(detections, func(d secret.Detection, _ int) types.SecretFinding {
+					return d.SecretFinding
+				}),
+			},
+		},
+		CustomResources: []types.CustomResource{
+			{
+				Type:     secret.FingerprintType,
+				FilePath: args.FilePath,
+				Data:     secret.Fingerprints(args.FilePath, detections),
+			},
+		},
+	}
+
+	// Inline ignore comments, including "nosec"
+	if rules := ignore.Parse(args.Content, true); len(rules) > 0 {
+		result.CustomResources = append(result.CustomResources, types.CustomResource{
+			Type:     ignore.RuleType,
+			FilePath: args.FilePath,
+			Data:     rules,
+		})
+	}
+
+	if a.verifier == nil {
+		return result
+	}
+
+	if verifications := a.verifier.Verify(ctx, detections); len(verifications) > 0 {
+		result.CustomResources = append(result.CustomResources, types.CustomResource{
+			Type:     secret.VerificationType,
+			FilePath: args.FilePath,
+			Data:     verifications,
+		})
+	}
+	return result
+}
+
+func isBinary(content dio.ReadSeekerAt, fileSize int64) (bool, error) {
+	headSize := int(math.Min(float64(fileSize), 300))
+	head := make([]byte, headSize)
+	if _, err := content.Read(head); err != nil {
+		return false, err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return false, err
+	}
+
+	// cf. https://github.com/file/file/blob/f2a6e7cb7db9b5fd86100403df6b2f830c7f22ba/src/encoding.c#L151-L228
+	for _, b := range head {
+		if b < 7 || b == 11 || (13 < b && b < 27) || (27 < b && b < 0x20) || b == 0x7f {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// extractStrings returns printable ASCII strings in the binary, one per line like strings(1)
+func extractStrings(content []byte) []byte {
+	var buf bytes.Buffer
+	start := -1
+	for i := 0; i <= len(content); i++ {
+		if i < len(content) && (content[i] == '\t' || (0x20 <= content[i] && content[i] < 0x7f)) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 && i-start >= minStringLength {
+			buf.Write(content[start:i])
+			buf.WriteByte('\n')
+		}
+		start = -1
+	}
+	return buf.Bytes()
+}
+
+func (a SecretAnalyzer) Required(filePath string, fi os.FileInfo) bool {
+	// Skip small files
+	if fi.Size() < 10 {
+		return false
+	}
+
+	// Skip large archives
+	if a.scanArchives && isArchive(filePath) && fi.Size() > maxArchiveSize {
+		return false
+	}
+	return a.requiredPath(filePath)
+}
+
+// requiredPath checks if the file should be scanned by the path
+func (a SecretAnalyzer) requiredPath(filePath string) bool {
+	dir, fileName := filepath.Split(filePath)
+	dir = filepath.ToSlash(dir)
+	dirs := strings.Split(dir, "/")
+
+	// Check if the directory should be skipped
+	for _, skipDir := range skipDirs {
+		if slices.Contains(dirs, skipDir) {
+			return false
+		}
+	}
+
+	// Check if the file should be skipped
+	if slices.Contains(skipFiles, fileName) {
+		return false
+	}
+
+	// Skip the config file for secret scanning
+	if filepath.Base(a.configPath) == filePath {
+		return false
+	}
+
+	// Check if the file extension should be skipped
+	ext := filepath.Ext(fileName)
+	if slices.Contains(skipExts, ext) && !(a.scanArchives && isArchive(fileName)) {
+		return false
+	}
+
+	if a.scanner.AllowPath(filePath) {
+		return false
+	}
+
+	return true
+}
+
+func (a SecretAnalyzer) Type() analyzer.Type {
+	return analyzer.TypeSecret
+}
+
+func (a SecretAnalyzer) Version() int {
+	return version
+}
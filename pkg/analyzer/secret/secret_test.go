@@ -0,0 +1,25 @@
+package secret
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	_ "github.com/aquasecurity/fanal/analyzer/secret"
+	"github.com/aquasecurity/trivy/pkg/secret"
+)
+
+func TestRegisterSecretAnalyzer(t *testing.T) {
+	// The secret analyzer of fanal must be overridden
+	require.NoError(t, RegisterSecretAnalyzer(ScannerOption{}))
+	versions := analyzer.NewAnalyzerGroup(analyzer.GroupBuiltin, nil).AnalyzerVersions()
+	assert.Equal(t, version, versions[string(analyzer.TypeSecret)])
+}
+
+func TestCacheKeyOption(t *testing.T) {
+	assert.Nil(t, CacheKeyOption(ScannerOption{}))
+	assert.Equal(t, &ScannerOption{Redaction: secret.RedactionHash},
+		CacheKeyOption(ScannerOption{Redaction: secret.RedactionHash}))
+}
@@ -0,0 +1,152 @@
+package arm
+
+import (
+	"bytes"
+	"encoding/json"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+const (
+	// FileTypeARM is the type of Azure Resource Manager templates
+	FileTypeARM = "azure-arm"
+
+	// FileTypeBicep is the type of Bicep files, which are compiled to ARM templates
+	FileTypeBicep = "bicep"
+
+	// configFile is the Terraform configuration converted from a template
+	configFile = "main.tf"
+
+	// schemaSuffix identifies deployment templates, e.g.
+	// https://schema.management.azure.com/schemas/2019-04-01/deploymentTemplate.json#
+	schemaSuffix = "deploymenttemplate.json#"
+)
+
+// Source is the template which a converted configuration comes from
+type Source struct {
+	FilePath string
+	FileType string
+
+	// Resources maps the Terraform addresses to the ARM resources,
+	// e.g. "azurerm_storage_account.resource0" => "Microsoft.Storage/storageAccounts/mystorage"
+	Resources map[string]string
+}
+
+// Convert replaces ARM templates and Bicep files with Terraform configurations of the equivalent azurerm resources
+// so that Azure checks are evaluated against them.
+// Bicep files are compiled to ARM templates by the bicep CLI, and they are left as they are if it is not installed.
+// Bicep files are read from the directory as they may refer to modules.
+// Like Terraform plans, each template is converted into its own directory, e.g. "azuredeploy.json/main.tf".
+// The converted paths are returned with the templates so that findings are attributed to them by Relabel.
+func Convert(dir string, files []types.File) ([]types.File, map[string]Source) {
+	converted := map[string]Source{}
+	var bicepChecked, bicepFound bool
+	var results []types.File
+	for _, file := range files {
+		content := file.Content
+		fileType := FileTypeARM
+		switch {
+		case file.Type == FileTypeBicep:
+			if !bicepChecked {
+				bicepChecked = true
+				if _, err := exec.LookPath("bicep"); err == nil {
+					bicepFound = true
+				} else {
+					log.Logger.Warn("Bicep files are not scanned as the bicep CLI is not found in PATH")
+				}
+			}
+			if !bicepFound {
+				results = append(results, file)
+				continue
+			}
+
+			b, err := compile(filepath.Join(dir, file.Path))
+			if err != nil {
+				log.Logger.Warnf("Unable to compile the Bicep file (%s): %s", file.Path, err)
+				results = append(results, file)
+				continue
+			}
+			content, fileType = b, FileTypeBicep
+		case file.Type != types.JSON || !isTemplate(file.Content):
+			results = append(results, file)
+			continue
+		}
+
+		config, resources, err := convert(content)
+		if err != nil {
+			log.Logger.Warnf("Unable to convert the ARM template (%s): %s", file.Path, err)
+			results = append(results, file)
+			continue
+		}
+
+		filePath := filepath.Join(file.Path, configFile)
+		converted[filePath] = Source{
+			FilePath:  file.Path,
+			FileType:  fileType,
+			Resources: resources,
+		}
+		results = append(results, types.File{
+			Type:    types.Terraform,
+			Path:    filePath,
+			Content: config,
+		})
+	}
+	return results, converted
+}
+
+// Relabel attributes misconfigurations in the converted configurations to the templates.
+// As the line numbers and the code are of the converted configurations, they are removed,
+// and the ARM resources are reported instead of the Terraform addresses.
+func Relabel(misconfs []types.Misconfiguration, converted map[string]Source) {
+	for i, misconf := range misconfs {
+		source, ok := converted[misconf.FilePath]
+		if !ok {
+			continue
+		}
+		misconfs[i].FilePath = source.FilePath
+		misconfs[i].FileType = source.FileType
+
+		label := "Azure ARM"
+		if source.FileType == FileTypeBicep {
+			label = "Bicep"
+		}
+		for _, results := range []types.MisconfResults{misconf.Successes, misconf.Warnings, misconf.Failures, misconf.Exceptions} {
+			for j := range results {
+				r := &results[j]
+				r.Type = strings.Replace(r.Type, "Terraform", label, 1)
+				if resource, ok := source.Resources[r.Resource]; ok {
+					r.Resource = resource
+				}
+				r.StartLine, r.EndLine = 0, 0
+				r.Code = types.Code{}
+			}
+		}
+	}
+}
+
+func isTemplate(content []byte) bool {
+	var t struct {
+		Schema string `json:"$schema"`
+	}
+	if err := json.Unmarshal(content, &t); err != nil {
+		return false
+	}
+	return strings.HasSuffix(strings.ToLower(t.Schema), schemaSuffix)
+}
+
+func compile(filePath string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("bicep", "build", "--stdout", filePath)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, xerrors.Errorf("bicep build error: %s: %w", strings.TrimSpace(stderr.String()), err)
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,164 @@
+package arm_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/arm"
+)
+
+func TestConvert(t *testing.T) {
+	// The bicep CLI is not used in the test
+	t.Setenv("PATH", "")
+
+	template, err := os.ReadFile("testdata/azuredeploy.json")
+	require.NoError(t, err)
+
+	packageJSON := types.File{
+		Type:    types.JSON,
+		Path:    "package.json",
+		Content: []byte(`{"name": "app"}`),
+	}
+	bicep := types.File{
+		Type:    arm.FileTypeBicep,
+		Path:    "main.bicep",
+		Content: []byte("param location string = resourceGroup().location\n"),
+	}
+
+	got, converted := arm.Convert("testdata", []types.File{
+		{
+			Type:    types.JSON,
+			Path:    "azuredeploy.json",
+			Content: template,
+		},
+		packageJSON,
+		bicep,
+	})
+
+	want := []types.File{
+		{
+			Type: types.Terraform,
+			Path: "azuredeploy.json/main.tf",
+			Content: []byte(`resource "azurerm_storage_account" "resource0" {
+  enable_https_traffic_only = false
+  min_tls_version           = "TLS1_0"
+  network_rules {
+    default_action = "Allow"
+    bypass         = ["AzureServices", "Logging"]
+  }
+}
+
+resource "azurerm_storage_container" "resource1" {
+  container_access_type = "container"
+}
+
+resource "azurerm_network_security_group" "resource2" {
+  security_rule {
+    name                       = "ssh"
+    protocol                   = "Tcp"
+    access                     = "Allow"
+    direction                  = "Inbound"
+    source_address_prefix      = "*"
+    source_port_range          = "*"
+    destination_address_prefix = "*"
+    destination_port_range     = "22"
+  }
+}
+
+resource "azurerm_key_vault" "resource3" {
+  soft_delete_retention_days = 7
+}
+`),
+		},
+		packageJSON,
+		bicep,
+	}
+	assert.Equal(t, want, got)
+
+	wantConverted := map[string]arm.Source{
+		"azuredeploy.json/main.tf": {
+			FilePath: "azuredeploy.json",
+			FileType: arm.FileTypeARM,
+			Resources: map[string]string{
+				"azurerm_storage_account.resource0":        "Microsoft.Storage/storageAccounts/appstorage",
+				"azurerm_storage_container.resource1":      "Microsoft.Storage/storageAccounts/appstorage/blobServices/default/containers/logs",
+				"azurerm_network_security_group.resource2": "Microsoft.Network/networkSecurityGroups/app-nsg",
+				"azurerm_key_vault.resource3":              "Microsoft.KeyVault/vaults/app-vault",
+			},
+		},
+	}
+	assert.Equal(t, wantConverted, converted)
+}
+
+func TestRelabel(t *testing.T) {
+	misconfs := []types.Misconfiguration{
+		{
+			FileType: types.Terraform,
+			FilePath: "main.bicep/main.tf",
+			Failures: types.MisconfResults{
+				{
+					PolicyMetadata: types.PolicyMetadata{
+						ID:   "AVD-AZU-0008",
+						Type: "Terraform Security Check",
+					},
+					CauseMetadata: types.CauseMetadata{
+						Resource:  "azurerm_storage_account.resource0",
+						Provider:  "Azure",
+						StartLine: 2,
+						EndLine:   2,
+						Code: types.Code{
+							Lines: []types.Line{
+								{
+									Number:  2,
+									Content: "  enable_https_traffic_only = false",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			FileType: types.Terraform,
+			FilePath: "main.tf",
+		},
+	}
+
+	arm.Relabel(misconfs, map[string]arm.Source{
+		"main.bicep/main.tf": {
+			FilePath: "main.bicep",
+			FileType: arm.FileTypeBicep,
+			Resources: map[string]string{
+				"azurerm_storage_account.resource0": "Microsoft.Storage/storageAccounts/appstorage",
+			},
+		},
+	})
+
+	want := []types.Misconfiguration{
+		{
+			FileType: arm.FileTypeBicep,
+			FilePath: "main.bicep",
+			Failures: types.MisconfResults{
+				{
+					PolicyMetadata: types.PolicyMetadata{
+						ID:   "AVD-AZU-0008",
+						Type: "Bicep Security Check",
+					},
+					CauseMetadata: types.CauseMetadata{
+						Resource: "Microsoft.Storage/storageAccounts/appstorage",
+						Provider: "Azure",
+					},
+				},
+			},
+		},
+		{
+			FileType: types.Terraform,
+			FilePath: "main.tf",
+		},
+	}
+	assert.Equal(t, want, misconfs)
+}
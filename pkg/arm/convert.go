@@ -0,0 +1,329 @@
+package arm
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	"golang.org/x/xerrors"
+)
+
+// maxDepth limits the nesting of variables referring to others
+const maxDepth = 10
+
+// referenceRegexp matches expressions referring to a parameter or a variable, e.g. [parameters('location')]
+var referenceRegexp = regexp.MustCompile(`^\[\s*(parameters|variables)\(\s*'([^']+)'\s*\)\s*\]$`)
+
+type template struct {
+	Parameters map[string]struct {
+		DefaultValue interface{} `json:"defaultValue"`
+	} `json:"parameters"`
+	Variables map[string]interface{} `json:"variables"`
+
+	// Resources is an array, or an object with symbolic names in language version 2.0
+	Resources json.RawMessage `json:"resources"`
+}
+
+type resource struct {
+	Type       string                 `json:"type"`
+	Name       string                 `json:"name"`
+	Kind       string                 `json:"kind"`
+	Identity   map[string]interface{} `json:"identity"`
+	Properties map[string]interface{} `json:"properties"`
+	Resources  []resource             `json:"resources"`
+}
+
+// converter builds a Terraform configuration of the azurerm resources equivalent to the ARM resources.
+// Only the attributes evaluated by Azure checks are converted.
+// Template expressions are resolved if they refer to parameters with default values or variables,
+// and the attributes with other expressions are omitted.
+type converter struct {
+	tmpl      template
+	file      *hclwrite.File
+	resources map[string]string
+}
+
+func convert(content []byte) ([]byte, map[string]string, error) {
+	var tmpl template
+	if err := json.Unmarshal(content, &tmpl); err != nil {
+		return nil, nil, xerrors.Errorf("json error: %w", err)
+	}
+
+	resources, err := tmpl.resources()
+	if err != nil {
+		return nil, nil, xerrors.Errorf("resource error: %w", err)
+	}
+
+	c := converter{
+		tmpl:      tmpl,
+		file:      hclwrite.NewEmptyFile(),
+		resources: map[string]string{},
+	}
+	for _, r := range resources {
+		c.convertResource("", "", r)
+	}
+	return c.file.Bytes(), c.resources, nil
+}
+
+func (t template) resources() ([]resource, error) {
+	var resources []resource
+	if len(t.Resources) == 0 {
+		return nil, nil
+	} else if err := json.Unmarshal(t.Resources, &resources); err == nil {
+		return resources, nil
+	}
+
+	symbolic := map[string]resource{}
+	if err := json.Unmarshal(t.Resources, &symbolic); err != nil {
+		return nil, xerrors.Errorf("json error: %w", err)
+	}
+	var names []string
+	for name := range symbolic {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		resources = append(resources, symbolic[name])
+	}
+	return resources, nil
+}
+
+// convertResource converts the resource and its child resources.
+// The types and names of child resources are qualified with their parents,
+// e.g. "Microsoft.Storage/storageAccounts/blobServices/containers" and "mystorage/default/logs".
+func (c *converter) convertResource(parentType, parentName string, r resource) {
+	name, ok := c.resolve(r.Name, 0).(string)
+	if !ok {
+		name = r.Name
+	}
+	if parentType != "" {
+		r.Type = parentType + "/" + r.Type
+		name = parentName + "/" + name
+	}
+	props, _ := c.resolve(r.Properties, 0).(map[string]interface{})
+
+	switch strings.ToLower(r.Type) {
+	case "microsoft.storage/storageaccounts":
+		c.storageAccount(r.Type, name, props)
+	case "microsoft.storage/storageaccounts/blobservices/containers":
+		c.storageContainer(r.Type, name, props)
+	case "microsoft.keyvault/vaults":
+		c.keyVault(r.Type, name, props)
+	case "microsoft.network/networksecuritygroups":
+		c.networkSecurityGroup(r.Type, name, props)
+	case "microsoft.web/sites":
+		identity, _ := c.resolve(r.Identity, 0).(map[string]interface{})
+		c.site(r.Type, name, r.Kind, identity, props)
+	}
+
+	for _, child := range r.Resources {
+		c.convertResource(r.Type, name, child)
+	}
+}
+
+func (c *converter) storageAccount(armType, name string, props map[string]interface{}) {
+	body := c.newResource("azurerm_storage_account", armType, name)
+	setAttribute(body, "enable_https_traffic_only", lookup(props, "supportsHttpsTrafficOnly"))
+	setAttribute(body, "min_tls_version", lookup(props, "minimumTlsVersion"))
+
+	if acls, ok := lookup(props, "networkAcls").(map[string]interface{}); ok {
+		rules := body.AppendNewBlock("network_rules", nil).Body()
+		setAttribute(rules, "default_action", acls["defaultAction"])
+		if bypass, ok := acls["bypass"].(string); ok {
+			var values []interface{}
+			for _, b := range strings.Split(bypass, ",") {
+				values = append(values, strings.TrimSpace(b))
+			}
+			setAttribute(rules, "bypass", values)
+		}
+	}
+}
+
+func (c *converter) storageContainer(armType, name string, props map[string]interface{}) {
+	body := c.newResource("azurerm_storage_container", armType, name)
+	if access, ok := lookup(props, "publicAccess").(string); ok {
+		setAttribute(body, "container_access_type", strings.ToLower(access))
+	}
+}
+
+func (c *converter) keyVault(armType, name string, props map[string]interface{}) {
+	body := c.newResource("azurerm_key_vault", armType, name)
+	setAttribute(body, "purge_protection_enabled", lookup(props, "enablePurgeProtection"))
+	setAttribute(body, "soft_delete_retention_days", lookup(props, "softDeleteRetentionInDays"))
+
+	if acls, ok := lookup(props, "networkAcls").(map[string]interface{}); ok {
+		setAttribute(body.AppendNewBlock("network_acls", nil).Body(), "default_action", acls["defaultAction"])
+	}
+}
+
+// securityRuleAttributes maps the properties of security rules to the attributes
+var securityRuleAttributes = []struct {
+	property  string
+	attribute string
+}{
+	{"protocol", "protocol"},
+	{"access", "access"},
+	{"direction", "direction"},
+	{"sourceAddressPrefix", "source_address_prefix"},
+	{"sourceAddressPrefixes", "source_address_prefixes"},
+	{"sourcePortRange", "source_port_range"},
+	{"sourcePortRanges", "source_port_ranges"},
+	{"destinationAddressPrefix", "destination_address_prefix"},
+	{"destinationAddressPrefixes", "destination_address_prefixes"},
+	{"destinationPortRange", "destination_port_range"},
+	{"destinationPortRanges", "destination_port_ranges"},
+}
+
+func (c *converter) networkSecurityGroup(armType, name string, props map[string]interface{}) {
+	body := c.newResource("azurerm_network_security_group", armType, name)
+	rules, _ := lookup(props, "securityRules").([]interface{})
+	for _, r := range rules {
+		ruleProps, ok := lookup(r, "properties").(map[string]interface{})
+		if !ok {
+			continue
+		}
+		rule := body.AppendNewBlock("security_rule", nil).Body()
+		setAttribute(rule, "name", lookup(r, "name"))
+		for _, attr := range securityRuleAttributes {
+			setAttribute(rule, attr.attribute, ruleProps[attr.property])
+		}
+	}
+}
+
+func (c *converter) site(armType, name, kind string, identity, props map[string]interface{}) {
+	if strings.Contains(strings.ToLower(kind), "functionapp") {
+		body := c.newResource("azurerm_function_app", armType, name)
+		setAttribute(body, "https_only", lookup(props, "httpsOnly"))
+		return
+	}
+
+	body := c.newResource("azurerm_app_service", armType, name)
+	setAttribute(body, "https_only", lookup(props, "httpsOnly"))
+	setAttribute(body, "client_cert_enabled", lookup(props, "clientCertEnabled"))
+	if identity != nil {
+		setAttribute(body.AppendNewBlock("identity", nil).Body(), "type", identity["type"])
+	}
+	if siteConfig, ok := lookup(props, "siteConfig").(map[string]interface{}); ok {
+		site := body.AppendNewBlock("site_config", nil).Body()
+		setAttribute(site, "http2_enabled", siteConfig["http20Enabled"])
+		setAttribute(site, "min_tls_version", siteConfig["minTlsVersion"])
+	}
+}
+
+// newResource appends a resource block named after the order of the resources, e.g. "resource0",
+// as the names of ARM resources may not be valid identifiers in Terraform.
+func (c *converter) newResource(tfType, armType, name string) *hclwrite.Body {
+	tfName := fmt.Sprintf("resource%d", len(c.resources))
+	c.resources[tfType+"."+tfName] = resourceID(armType, name)
+
+	body := c.file.Body()
+	if len(body.Blocks()) > 0 {
+		body.AppendNewline()
+	}
+	return body.AppendNewBlock("resource", []string{tfType, tfName}).Body()
+}
+
+// resourceID returns the ID of the resource relative to the resource group,
+// e.g. "Microsoft.Storage/storageAccounts/mystorage/blobServices/default/containers/logs"
+func resourceID(armType, name string) string {
+	types := strings.Split(armType, "/")
+	names := strings.Split(name, "/")
+	if len(types) != len(names)+1 {
+		return armType + "/" + name
+	}
+
+	id := types[0]
+	for i, n := range names {
+		id += "/" + types[i+1] + "/" + n
+	}
+	return id
+}
+
+// resolve evaluates template expressions in the value.
+// Unresolvable expressions are returned as nil, and they are removed from objects and arrays.
+func (c *converter) resolve(value interface{}, depth int) interface{} {
+	if depth > maxDepth {
+		return nil
+	}
+
+	switch v := value.(type) {
+	case string:
+		if strings.HasPrefix(v, "[[") {
+			// Escaped literal
+			return v[1:]
+		} else if !strings.HasPrefix(v, "[") || !strings.HasSuffix(v, "]") {
+			return v
+		}
+
+		m := referenceRegexp.FindStringSubmatch(v)
+		if m == nil {
+			return nil
+		}
+		if m[1] == "parameters" {
+			return c.resolve(c.tmpl.Parameters[m[2]].DefaultValue, depth+1)
+		}
+		return c.resolve(c.tmpl.Variables[m[2]], depth+1)
+	case map[string]interface{}:
+		resolved := map[string]interface{}{}
+		for key, val := range v {
+			if r := c.resolve(val, depth); r != nil {
+				resolved[key] = r
+			}
+		}
+		return resolved
+	case []interface{}:
+		var resolved []interface{}
+		for _, val := range v {
+			if r := c.resolve(val, depth); r != nil {
+				resolved = append(resolved, r)
+			}
+		}
+		return resolved
+	default:
+		return v
+	}
+}
+
+func lookup(value interface{}, key string) interface{} {
+	if m, ok := value.(map[string]interface{}); ok {
+		return m[key]
+	}
+	return nil
+}
+
+// setAttribute sets the value if it is a string, a number, a bool or an array of them
+func setAttribute(body *hclwrite.Body, name string, value interface{}) {
+	if v, ok := toCty(value); ok {
+		body.SetAttributeValue(name, v)
+	}
+}
+
+func toCty(value interface{}) (cty.Value, bool) {
+	switch v := value.(type) {
+	case string:
+		return cty.StringVal(v), true
+	case bool:
+		return cty.BoolVal(v), true
+	case float64:
+		return cty.NumberFloatVal(v), true
+	case []interface{}:
+		var values []cty.Value
+		for _, val := range v {
+			cv, ok := toCty(val)
+			if !ok {
+				return cty.NilVal, false
+			}
+			values = append(values, cv)
+		}
+		if len(values) == 0 {
+			return cty.NilVal, false
+		}
+		return cty.TupleVal(values), true
+	default:
+		return cty.NilVal, false
+	}
+}
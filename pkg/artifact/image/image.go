@@ -0,0 +1,556 @@
+// Package image is forked from the image artifact of fanal, see forkedFanalVersion in pkg/secret.
+package image
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	digest "github.com/opencontainers/go-digest"
+	"golang.org/x/exp/slices"
+	"golang.org/x/sync/semaphore"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/analyzer/config"
+	"github.com/aquasecurity/fanal/artifact"
+	"github.com/aquasecurity/fanal/cache"
+	"github.com/aquasecurity/fanal/handler"
+	"github.com/aquasecurity/fanal/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/analyzer/secret"
+	"github.com/aquasecurity/trivy/pkg/ignore"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/oci"
+	"github.com/aquasecurity/trivy/pkg/progress"
+	"github.com/aquasecurity/trivy/pkg/timeout"
+)
+
+// defaultParallel is the number of layers, and of files in each layer, analyzed concurrently by default.
+// It is smaller than that of filesystems as each layer holds the files being analyzed.
+const defaultParallel = 5
+
+// Option holds the options for image scanning which are not supported in fanal
+type Option struct {
+	// SecretScannerOption is used instead of the one in fanal, which supports only a config path.
+	SecretScannerOption secret.ScannerOption
+
+	// PolicyTimeout limits the evaluation of misconfiguration checks in each layer.
+	PolicyTimeout time.Duration
+
+	// Progress is notified of the progress of analyzing layers.
+	Progress progress.Reporter
+
+	// TmpDir holds the temporary files of large files extracted from layers, os.TempDir() if empty.
+	TmpDir string
+
+	// TmpSizeLimit limits the total size of the temporary files in use if positive.
+	// Files which would exceed it are skipped with a warning.
+	TmpSizeLimit int64
+
+	// FileSizeLimits limits the size of files passed to each analyzer.
+	FileSizeLimits tanalyzer.FileSizeLimits
+
+	// ContinueOnError skips files which fail to be opened or analyzed and records their errors in the result.
+	ContinueOnError bool
+
+	// Parallel is the number of layers, and of files in each layer, analyzed concurrently.
+	// The default is used if it is not positive.
+	Parallel int
+}
+
+type Artifact struct {
+	image          types.Image
+	cache          cache.ArtifactCache
+	walker         layerWalker
+	analyzer       tanalyzer.Group
+	handlerManager handler.Manager
+
+	artifactOption artifact.Option
+	policyTimeout  time.Duration
+	progress       progress.Reporter
+	tmpSizeLimit   int64
+	fileSizeLimits tanalyzer.FileSizeLimits
+	secretOption   secret.ScannerOption
+	parallel       int64
+
+	continueOnError bool
+}
+
+func NewArtifact(img types.Image, c cache.ArtifactCache, opt artifact.Option, imageOpt Option) (artifact.Artifact, error) {
+	misconf := opt.MisconfScannerOption
+	// Register config analyzers
+	if err := config.RegisterConfigAnalyzers(misconf.FilePatterns); err != nil {
+		return nil, xerrors.Errorf("config scanner error: %w", err)
+	}
+
+	// Initialize handlers
+	handlerManager, err := handler.NewManager(opt)
+	if err != nil {
+		return nil, xerrors.Errorf("handler init error: %w", err)
+	}
+
+	// Register secret analyzer
+	if err = secret.RegisterSecretAnalyzer(imageOpt.SecretScannerOption); err != nil {
+		return nil, xerrors.Errorf("secret scanner error: %w", err)
+	}
+
+	if imageOpt.Progress == nil {
+		imageOpt.Progress = progress.Nop()
+	}
+	if imageOpt.Parallel <= 0 {
+		imageOpt.Parallel = defaultParallel
+	}
+
+	return Artifact{
+		image:          img,
+		cache:          c,
+		walker:         newLayerWalker(opt.SkipFiles, opt.SkipDirs, imageOpt),
+		analyzer:       tanalyzer.NewGroup(opt.AnalyzerGroup, opt.DisabledAnalyzers, imageOpt.ContinueOnError),
+		handlerManager: handlerManager,
+
+		artifactOption: opt,
+		policyTimeout:  imageOpt.PolicyTimeout,
+		progress:       imageOpt.Progress,
+		tmpSizeLimit:   imageOpt.TmpSizeLimit,
+		fileSizeLimits: imageOpt.FileSizeLimits,
+		secretOption:   imageOpt.SecretScannerOption,
+		parallel:       int64(imageOpt.Parallel),
+
+		continueOnError: imageOpt.ContinueOnError,
+	}, nil
+}
+
+func (a Artifact) Inspect(ctx context.Context) (types.ArtifactReference, error) {
+	imageID, err := a.image.ID()
+	if err != nil {
+		return types.ArtifactReference{}, xerrors.Errorf("unable to get the image ID: %w", err)
+	}
+
+	diffIDs, err := a.image.LayerIDs()
+	if err != nil {
+		return types.ArtifactReference{}, xerrors.Errorf("unable to get layer IDs: %w", err)
+	}
+
+	configFile, err := a.image.ConfigFile()
+	if err != nil {
+		return types.ArtifactReference{}, xerrors.Errorf("unable to get the image's config file: %w", err)
+	}
+
+	// OCI artifacts such as Helm charts have no root filesystem
+	if configFile.RootFS.Type == "" && len(diffIDs) == 0 {
+		if err = a.inspectNonImage(); err != nil {
+			return types.ArtifactReference{}, err
+		}
+	}
+
+	// Debug
+	log.Logger.Debugf("Image ID: %s", imageID)
+	log.Logger.Debugf("Diff IDs: %v", diffIDs)
+
+	// Try to detect base layers.
+	baseDiffIDs := a.guessBaseLayers(diffIDs, configFile)
+	log.Logger.Debugf("Base Layers: %v", baseDiffIDs)
+
+	// Convert image ID and layer IDs to cache keys
+	imageKey, layerKeys, layerKeyMap, err := a.calcCacheKeys(imageID, diffIDs)
+	if err != nil {
+		return types.ArtifactReference{}, err
+	}
+
+	missingImage, missingLayers, err := a.cache.MissingBlobs(imageKey, layerKeys)
+	if err != nil {
+		return types.ArtifactReference{}, xerrors.Errorf("unable to get missing layers: %w", err)
+	}
+
+	missingImageKey := imageKey
+	if missingImage {
+		log.Logger.Debugf("Missing image ID in cache: %s", imageID)
+	} else {
+		missingImageKey = ""
+	}
+
+	if err = a.inspect(ctx, missingImageKey, missingLayers, baseDiffIDs, layerKeyMap); err != nil {
+		return types.ArtifactReference{}, xerrors.Errorf("analyze error: %w", err)
+	}
+
+	return types.ArtifactReference{
+		Name:    a.image.Name(),
+		Type:    types.ArtifactContainerImage,
+		ID:      imageKey,
+		BlobIDs: layerKeys,
+		ImageMetadata: types.ImageMetadata{
+			ID:          imageID,
+			DiffIDs:     diffIDs,
+			RepoTags:    a.image.RepoTags(),
+			RepoDigests: a.image.RepoDigests(),
+			ConfigFile:  *configFile,
+		},
+	}, nil
+}
+
+func (Artifact) Clean(_ types.ArtifactReference) error {
+	return nil
+}
+
+// inspectNonImage returns oci.NotImageError if the image is actually an OCI artifact of another kind
+func (a Artifact) inspectNonImage() error {
+	manifest, err := a.image.Manifest()
+	if err != nil || manifest == nil {
+		// Images in container engines may have no manifest
+		return nil
+	}
+	if kind := oci.ArtifactKind(manifest); kind != "" {
+		return &oci.NotImageError{
+			Kind:  kind,
+			Image: a.image,
+		}
+	}
+	return nil
+}
+
+func (a Artifact) calcCacheKeys(imageID string, diffIDs []string) (string, []string, map[string]string, error) {
+	// Pass an empty config scanner option so that the cache key can be the same, even when policies are updated.
+	imageKey, err := cache.CalcKey(imageID, a.analyzer.ImageConfigAnalyzerVersions(), nil, artifact.Option{})
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	layerKeyMap := map[string]string{}
+	hookVersions := a.handlerManager.Versions()
+	var layerKeys []string
+	for _, diffID := range diffIDs {
+		layerID, err := a.layerID(diffID)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		blobKey, err := cache.CalcKey(layerID, a.analyzer.AnalyzerVersions(), hookVersions, a.artifactOption)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		layerKeys = append(layerKeys, blobKey)
+		layerKeyMap[blobKey] = diffID
+	}
+	return imageKey, layerKeys, layerKeyMap, nil
+}
+
+// layerID returns the ID of the layer for the cache key.
+// The size limits and --continue-on-error are mixed into it, as files skipped by them change the analysis result.
+// So are the secret scanner options, which fanal doesn't take into account.
+func (a Artifact) layerID(diffID string) (string, error) {
+	secretOption := secret.CacheKeyOption(a.secretOption)
+	if a.tmpSizeLimit <= 0 && len(a.fileSizeLimits) == 0 && !a.continueOnError && secretOption == nil {
+		return diffID, nil
+	}
+
+	h := sha256.New()
+	if err := json.NewEncoder(h).Encode(struct {
+		DiffID          string
+		TmpSizeLimit    int64
+		FileSizeLimits  tanalyzer.FileSizeLimits
+		ContinueOnError bool                  `json:",omitempty"`
+		SecretOption    *secret.ScannerOption `json:",omitempty"`
+	}{diffID, a.tmpSizeLimit, a.fileSizeLimits, a.continueOnError, secretOption}); err != nil {
+		return "", xerrors.Errorf("json error: %w", err)
+	}
+	return digest.NewDigest(digest.SHA256, h).String(), nil
+}
+
+func (a Artifact) inspect(ctx context.Context, missingImage string, layerKeys, baseDiffIDs []string, layerKeyMap map[string]string) error {
+	done := make(chan struct{})
+	errCh := make(chan error)
+
+	if len(layerKeys) > 0 {
+		a.progress.Start(len(layerKeys))
+		defer a.progress.Finish()
+	}
+
+	// Bound the layers analyzed at a time, as each of them holds the files being analyzed
+	layerLimit := semaphore.NewWeighted(a.parallel)
+
+	var osFound types.OS
+	for _, k := range layerKeys {
+		go func(ctx context.Context, layerKey string) {
+			if err := layerLimit.Acquire(ctx, 1); err != nil {
+				errCh <- xerrors.Errorf("semaphore acquire: %w", err)
+				return
+			}
+			defer layerLimit.Release(1)
+
+			diffID := layerKeyMap[layerKey]
+
+			// If it is a base layer, secret scanning should not be performed.
+			var disabledAnalyers []analyzer.Type
+			if slices.Contains(baseDiffIDs, diffID) {
+				disabledAnalyers = append(disabledAnalyers, analyzer.TypeSecret)
+			}
+
+			layerInfo, err := a.inspectLayer(ctx, diffID, disabledAnalyers)
+			if err != nil {
+				errCh <- xerrors.Errorf("failed to analyze layer: %s : %w", diffID, err)
+				return
+			}
+			if err = a.cache.PutBlob(layerKey, layerInfo); err != nil {
+				errCh <- xerrors.Errorf("failed to store layer: %s in cache: %w", layerKey, err)
+				return
+			}
+			if layerInfo.OS != nil {
+				osFound = *layerInfo.OS
+			}
+			done <- struct{}{}
+		}(ctx, k)
+	}
+
+	for range layerKeys {
+		select {
+		case <-done:
+		case err := <-errCh:
+			return err
+		case <-ctx.Done():
+			return xerrors.Errorf("timeout: %w", ctx.Err())
+		}
+	}
+
+	if missingImage != "" {
+		if err := a.inspectConfig(missingImage, osFound); err != nil {
+			return xerrors.Errorf("unable to analyze config: %w", err)
+		}
+	}
+
+	return nil
+
+}
+
+func (a Artifact) inspectLayer(ctx context.Context, diffID string, disabled []analyzer.Type) (types.BlobInfo, error) {
+	log.Logger.Debugf("Missing diff ID in cache: %s", diffID)
+
+	a.progress.LayerStarted(diffID)
+	layerDigest, r, err := a.uncompressedLayer(diffID)
+	if err != nil {
+		return types.BlobInfo{}, xerrors.Errorf("unable to get uncompressed layer %s: %w", diffID, err)
+	}
+	r = progress.Reader(r, a.progress, diffID)
+
+	// Prepare variables
+	var wg sync.WaitGroup
+	opts := analyzer.AnalysisOptions{Offline: a.artifactOption.Offline}
+	result := analyzer.NewAnalysisResult()
+	limit := semaphore.NewWeighted(a.parallel)
+
+	// Walk a tar layer
+	opqDirs, whFiles, err := a.walker.Walk(r, func(filePath string, info os.FileInfo, opener analyzer.Opener) error {
+		var analyzers int
+		fileDisabled := append(a.fileSizeLimits.Exceeded(info.Size()), disabled...)
+		err = a.analyzer.AnalyzeFile(ctx, &wg, limit, result, "", filePath, info, progress.CountOpener(opener, &analyzers), fileDisabled, opts)
+		if errors.Is(err, errTmpSizeLimit) {
+			log.Logger.Warnf("Skipping %s (%d bytes) in %s: temporary files would exceed --tmp-size-limit", filePath, info.Size(), diffID)
+			return nil
+		} else if err != nil && a.continueOnError {
+			log.Logger.Warnf("Skipping %s in %s due to the error: %s", filePath, diffID, err)
+			result.Merge(&analyzer.AnalysisResult{
+				CustomResources: []types.CustomResource{tanalyzer.ErrorResource(filePath, err)},
+			})
+			return nil
+		} else if err != nil {
+			return xerrors.Errorf("failed to analyze %s: %w", filePath, err)
+		}
+		if !info.IsDir() {
+			a.progress.File(diffID, analyzers)
+		}
+		return nil
+	})
+	if err != nil {
+		return types.BlobInfo{}, xerrors.Errorf("walk error: %w", err)
+	}
+
+	// Wait for all the goroutine to finish.
+	wg.Wait()
+	a.progress.LayerFinished(diffID)
+
+	// Sort the analysis result for consistent results
+	result.Sort()
+
+	// Inline ignore rules in config files
+	result.CustomResources = append(result.CustomResources, ignore.Resources(result.Files[types.MisconfPostHandler])...)
+
+	blobInfo := types.BlobInfo{
+		SchemaVersion:   types.BlobJSONSchemaVersion,
+		Digest:          layerDigest,
+		DiffID:          diffID,
+		OS:              result.OS,
+		Repository:      result.Repository,
+		PackageInfos:    result.PackageInfos,
+		Applications:    result.Applications,
+		Secrets:         result.Secrets,
+		OpaqueDirs:      opqDirs,
+		WhiteoutFiles:   whFiles,
+		CustomResources: result.CustomResources,
+
+		// For Red Hat
+		BuildInfo: result.BuildInfo,
+	}
+
+	// Call post handlers to modify blob info
+	err = timeout.Run(ctx, timeout.PhasePolicy, a.policyTimeout, func(ctx context.Context) error {
+		return a.handlerManager.PostHandle(ctx, result, &blobInfo)
+	})
+	if err != nil {
+		return types.BlobInfo{}, xerrors.Errorf("post handler error: %w", err)
+	}
+
+	return blobInfo, nil
+}
+
+func (a Artifact) uncompressedLayer(diffID string) (string, io.Reader, error) {
+	// diffID is a hash of the uncompressed layer
+	h, err := v1.NewHash(diffID)
+	if err != nil {
+		return "", nil, xerrors.Errorf("invalid layer ID (%s): %w", diffID, err)
+	}
+
+	layer, err := a.image.LayerByDiffID(h)
+	if err != nil {
+		return "", nil, xerrors.Errorf("failed to get the layer (%s): %w", diffID, err)
+	}
+
+	// digest is a hash of the compressed layer
+	var digest string
+	if a.isCompressed(layer) {
+		d, err := layer.Digest()
+		if err != nil {
+			return "", nil, xerrors.Errorf("failed to get the digest (%s): %w", diffID, err)
+		}
+		digest = d.String()
+	}
+
+	r, err := layer.Uncompressed()
+	if err != nil {
+		return "", nil, xerrors.Errorf("failed to get the layer content (%s): %w", diffID, err)
+	}
+	return digest, r, nil
+}
+
+// ref. https://github.com/google/go-containerregistry/issues/701
+func (a Artifact) isCompressed(l v1.Layer) bool {
+	_, uncompressed := reflect.TypeOf(l).Elem().FieldByName("UncompressedLayer")
+	return !uncompressed
+}
+
+func (a Artifact) inspectConfig(imageID string, osFound types.OS) error {
+	configBlob, err := a.image.RawConfigFile()
+	if err != nil {
+		return xerrors.Errorf("unable to get config blob: %w", err)
+	}
+
+	pkgs := a.analyzer.AnalyzeImageConfig(osFound, configBlob)
+
+	var s1 v1.ConfigFile
+	if err = json.Unmarshal(configBlob, &s1); err != nil {
+		return xerrors.Errorf("json marshal error: %w", err)
+	}
+
+	info := types.ArtifactInfo{
+		SchemaVersion:   types.ArtifactJSONSchemaVersion,
+		Architecture:    s1.Architecture,
+		Created:         s1.Created.Time,
+		DockerVersion:   s1.DockerVersion,
+		OS:              s1.OS,
+		HistoryPackages: pkgs,
+	}
+
+	if err = a.cache.PutArtifact(imageID, info); err != nil {
+		return xerrors.Errorf("failed to put image info into the cache: %w", err)
+	}
+
+	return nil
+}
+
+// Guess layers in base image (call base layers).
+//
+// e.g. In the following example, we should detect layers in debian:8.
+//
+//	FROM debian:8
+//	RUN apt-get update
+//	COPY mysecret /
+//	ENTRYPOINT ["entrypoint.sh"]
+//	CMD ["somecmd"]
+//
+// debian:8 may be like
+//
+//	ADD file:5d673d25da3a14ce1f6cf66e4c7fd4f4b85a3759a9d93efb3fd9ff852b5b56e4 in /
+//	CMD ["/bin/sh"]
+//
+// In total, it would be like:
+//
+//	ADD file:5d673d25da3a14ce1f6cf66e4c7fd4f4b85a3759a9d93efb3fd9ff852b5b56e4 in /
+//	CMD ["/bin/sh"]              # empty layer (detected)
+//	RUN apt-get update
+//	COPY mysecret /
+//	ENTRYPOINT ["entrypoint.sh"] # empty layer (skipped)
+//	CMD ["somecmd"]              # empty layer (skipped)
+//
+// This method tries to detect CMD in the second line and assume the first line is a base layer.
+//  1. Iterate histories from the bottom.
+//  2. Skip all the empty layers at the bottom. In the above example, "entrypoint.sh" and "somecmd" will be skipped
+//  3. If it finds CMD, it assumes that it is the end of base layers.
+//  4. It gets all the layers as base layers above the CMD found in #3.
+func (a Artifact) guessBaseLayers(diffIDs []string, configFile *v1.ConfigFile) []string {
+	if configFile == nil {
+		return nil
+	}
+
+	var baseImageIndex int
+	var foundNonEmpty bool
+	for i := len(configFile.History) - 1; i >= 0; i-- {
+		h := configFile.History[i]
+
+		// Skip the last CMD, ENTRYPOINT, etc.
+		if !foundNonEmpty {
+			if h.EmptyLayer {
+				continue
+			}
+			foundNonEmpty = true
+		}
+
+		if !h.EmptyLayer {
+			continue
+		}
+
+		// Detect CMD instruction in base image
+		if strings.HasPrefix(h.CreatedBy, "/bin/sh -c #(nop)  CMD") ||
+			strings.HasPrefix(h.CreatedBy, "CMD") { // BuildKit
+			baseImageIndex = i
+			break
+		}
+	}
+
+	// Diff IDs don't include empty layers, so the index is different from histories
+	var diffIDIndex int
+	var baseDiffIDs []string
+	for i, h := range configFile.History {
+		// It is no longer base layer.
+		if i > baseImageIndex {
+			break
+		}
+		// Empty layers are not included in diff IDs.
+		if h.EmptyLayer {
+			continue
+		}
+
+		if diffIDIndex >= len(diffIDs) {
+			// something wrong...
+			return nil
+		}
+		baseDiffIDs = append(baseDiffIDs, diffIDs[diffIDIndex])
+		diffIDIndex++
+	}
+	return baseDiffIDs
+}
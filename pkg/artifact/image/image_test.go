@@ -0,0 +1,354 @@
+package image
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/artifact"
+	"github.com/aquasecurity/fanal/cache"
+	fimage "github.com/aquasecurity/fanal/image"
+	"github.com/aquasecurity/fanal/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/analyzer/secret"
+	"github.com/aquasecurity/trivy/pkg/oci"
+	"github.com/aquasecurity/trivy/pkg/progress"
+)
+
+type fakeImage struct {
+	v1.Image
+}
+
+func (fakeImage) Name() string          { return "fake" }
+func (fakeImage) RepoTags() []string    { return nil }
+func (fakeImage) RepoDigests() []string { return nil }
+
+func (img fakeImage) ID() (string, error) {
+	return fimage.ID(img)
+}
+
+func (img fakeImage) LayerIDs() ([]string, error) {
+	return fimage.LayerIDs(img)
+}
+
+func newLayer(t *testing.T, files map[string]string) v1.Layer {
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+
+	layer, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(buf.Bytes())), nil
+	})
+	require.NoError(t, err)
+	return layer
+}
+
+func TestArtifact_Inspect_Secret(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "trivy-secret.yaml")
+	err := os.WriteFile(configPath, []byte(`rules:
+  - id: internal-token
+    category: Internal
+    title: Internal API token
+    severity: HIGH
+    regex: internal_token=(?P<secret>\S+)
+    secret-group-name: secret
+    entropy: 3
+`), 0600)
+	require.NoError(t, err)
+
+	// Secrets in the first layer are not detected as it is regarded as a base layer.
+	img, err := mutate.AppendLayers(empty.Image,
+		newLayer(t, map[string]string{
+			"etc/config.env": "internal_token=Zp3Kq9Wm2Xv7Rt4Lb8Nc\n",
+		}),
+		newLayer(t, map[string]string{
+			"app/config.env": "internal_token=q8Xz2LmP4vRt7NwK9bYc\nplaceholder internal_token=aaaaaaaaaaaaaaaa\n",
+		}),
+	)
+	require.NoError(t, err)
+
+	c, err := cache.NewFSCache(t.TempDir())
+	require.NoError(t, err)
+	defer c.Close()
+
+	a, err := NewArtifact(fakeImage{Image: img}, c, artifact.Option{}, Option{
+		SecretScannerOption: secret.ScannerOption{ConfigPath: configPath},
+	})
+	require.NoError(t, err)
+
+	ref, err := a.Inspect(context.Background())
+	require.NoError(t, err)
+	require.Len(t, ref.BlobIDs, 2)
+
+	base, err := c.GetBlob(ref.BlobIDs[0])
+	require.NoError(t, err)
+	assert.Empty(t, base.Secrets)
+
+	blob, err := c.GetBlob(ref.BlobIDs[1])
+	require.NoError(t, err)
+
+	want := []types.Secret{
+		{
+			FilePath: "/app/config.env",
+			Findings: []types.SecretFinding{
+				{
+					RuleID:    "internal-token",
+					Category:  "Internal",
+					Title:     "Internal API token",
+					Severity:  "HIGH",
+					StartLine: 1,
+					EndLine:   1,
+					Match:     "internal_token=*****",
+				},
+			},
+		},
+	}
+	assert.Equal(t, want, blob.Secrets)
+}
+
+func TestArtifact_Inspect_Progress(t *testing.T) {
+	img, err := mutate.AppendLayers(empty.Image,
+		newLayer(t, map[string]string{
+			"etc/alpine-release": "3.16.0\n",
+		}),
+		newLayer(t, map[string]string{
+			"app/main.go": "package main\n",
+			"app/go.sum":  "",
+		}),
+	)
+	require.NoError(t, err)
+
+	c, err := cache.NewFSCache(t.TempDir())
+	require.NoError(t, err)
+	defer c.Close()
+
+	buf := &bytes.Buffer{}
+	a, err := NewArtifact(fakeImage{Image: img}, c, artifact.Option{}, Option{
+		Progress: progress.NewEventWriter(buf),
+	})
+	require.NoError(t, err)
+
+	_, err = a.Inspect(context.Background())
+	require.NoError(t, err)
+
+	var finish progress.Event
+	events := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.NoError(t, json.Unmarshal([]byte(events[len(events)-1]), &finish))
+
+	assert.Equal(t, progress.EventFinish, finish.Type)
+	assert.Equal(t, int64(3), finish.Files)
+	assert.Positive(t, finish.Bytes)
+	assert.Equal(t, 2, strings.Count(buf.String(), `"Type":"layer_finished"`))
+}
+
+func TestArtifact_Inspect_FileSizeLimits(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "trivy-secret.yaml")
+	err := os.WriteFile(configPath, []byte(`rules:
+  - id: internal-token
+    category: Internal
+    title: Internal API token
+    severity: HIGH
+    regex: internal_token=(?P<secret>\S+)
+    secret-group-name: secret
+    entropy: 3
+`), 0600)
+	require.NoError(t, err)
+
+	img, err := mutate.AppendLayers(empty.Image,
+		newLayer(t, map[string]string{
+			"etc/alpine-release": "3.16.0\n",
+		}),
+		newLayer(t, map[string]string{
+			"app/config.env": "internal_token=q8Xz2LmP4vRt7NwK9bYc\n",
+		}),
+	)
+	require.NoError(t, err)
+
+	c, err := cache.NewFSCache(t.TempDir())
+	require.NoError(t, err)
+	defer c.Close()
+
+	tests := []struct {
+		name        string
+		limits      tanalyzer.FileSizeLimits
+		wantSecrets int
+	}{
+		{
+			name:        "no limits",
+			wantSecrets: 1,
+		},
+		{
+			name:   "file exceeding the limit of the secret analyzer",
+			limits: tanalyzer.FileSizeLimits{analyzer.TypeSecret: 16},
+		},
+		{
+			name:        "file within the limit of the secret analyzer",
+			limits:      tanalyzer.FileSizeLimits{analyzer.TypeSecret: 1024},
+			wantSecrets: 1,
+		},
+	}
+
+	// Results with different limits must not be shared via the cache
+	blobIDs := map[string]bool{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := NewArtifact(fakeImage{Image: img}, c, artifact.Option{}, Option{
+				SecretScannerOption: secret.ScannerOption{ConfigPath: configPath},
+				FileSizeLimits:      tt.limits,
+			})
+			require.NoError(t, err)
+
+			ref, err := a.Inspect(context.Background())
+			require.NoError(t, err)
+			require.Len(t, ref.BlobIDs, 2)
+			assert.False(t, blobIDs[ref.BlobIDs[1]])
+			blobIDs[ref.BlobIDs[1]] = true
+
+			blob, err := c.GetBlob(ref.BlobIDs[1])
+			require.NoError(t, err)
+			assert.Len(t, blob.Secrets, tt.wantSecrets)
+		})
+	}
+}
+
+func TestArtifact_Inspect_NotImage(t *testing.T) {
+	// Helm charts pushed by "helm push" have no root filesystem
+	img, err := mutate.Append(empty.Image, mutate.Addendum{
+		Layer: static.NewLayer([]byte("chart"), oci.HelmChartLayerMediaType),
+	})
+	require.NoError(t, err)
+	img, err = mutate.ConfigFile(img, &v1.ConfigFile{})
+	require.NoError(t, err)
+	img = mutate.ConfigMediaType(img, oci.HelmConfigMediaType)
+
+	c, err := cache.NewFSCache(t.TempDir())
+	require.NoError(t, err)
+	defer c.Close()
+
+	a, err := NewArtifact(fakeImage{Image: img}, c, artifact.Option{}, Option{})
+	require.NoError(t, err)
+
+	_, err = a.Inspect(context.Background())
+	var notImage *oci.NotImageError
+	require.ErrorAs(t, err, &notImage)
+	assert.Equal(t, oci.KindHelmChart, notImage.Kind)
+}
+
+// concurrencyAnalyzer records the maximum number of files analyzed at a time.
+// It belongs to its own group so that it doesn't run in the other tests.
+type concurrencyAnalyzer struct {
+	group analyzer.Group
+
+	mu      sync.Mutex
+	current int
+	max     int
+}
+
+func (a *concurrencyAnalyzer) Type() analyzer.Type               { return analyzer.Type(a.group) }
+func (a *concurrencyAnalyzer) Version() int                      { return 1 }
+func (a *concurrencyAnalyzer) Group() analyzer.Group             { return a.group }
+func (a *concurrencyAnalyzer) Required(string, os.FileInfo) bool { return true }
+
+func (a *concurrencyAnalyzer) Analyze(context.Context, analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	a.mu.Lock()
+	a.current++
+	if a.current > a.max {
+		a.max = a.current
+	}
+	a.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	a.mu.Lock()
+	a.current--
+	a.mu.Unlock()
+	return nil, nil
+}
+
+func TestArtifact_Inspect_Parallel(t *testing.T) {
+	files := map[string]string{}
+	for i := 0; i < 8; i++ {
+		files[fmt.Sprintf("app/file%d.txt", i)] = fmt.Sprintf("file %d", i)
+	}
+
+	tests := []struct {
+		name     string
+		parallel int
+		layers   int
+		want     int
+	}{
+		{
+			name:     "one by one",
+			parallel: 1,
+			layers:   2,
+			want:     1,
+		},
+		{
+			name:     "files in a layer",
+			parallel: 3,
+			layers:   1,
+			want:     3,
+		},
+		{
+			name:   "default",
+			layers: 1,
+			want:   defaultParallel,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &concurrencyAnalyzer{group: analyzer.Group(fmt.Sprintf("image-parallel-%d", i))}
+			tanalyzer.RegisterAnalyzer(a)
+
+			var layers []v1.Layer
+			for j := 0; j < tt.layers; j++ {
+				layerFiles := map[string]string{fmt.Sprintf("layer%d.txt", j): "layer"}
+				for name, content := range files {
+					layerFiles[name] = content
+				}
+				layers = append(layers, newLayer(t, layerFiles))
+			}
+			img, err := mutate.AppendLayers(empty.Image, layers...)
+			require.NoError(t, err)
+
+			c, err := cache.NewFSCache(t.TempDir())
+			require.NoError(t, err)
+			defer c.Close()
+
+			art, err := NewArtifact(fakeImage{Image: img}, c, artifact.Option{AnalyzerGroup: a.group}, Option{Parallel: tt.parallel})
+			require.NoError(t, err)
+
+			_, err = art.Inspect(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, a.max)
+		})
+	}
+}
@@ -0,0 +1,308 @@
+package image
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/walker"
+	dio "github.com/aquasecurity/go-dep-parser/pkg/io"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+const (
+	opq string = ".wh..wh..opq"
+	wh  string = ".wh."
+
+	// memoryThreshold is the size from which files are extracted to temporary files instead of memory.
+	// It is much smaller than fanal's walker.ThresholdSize, as files in several layers are analyzed concurrently
+	// and multi-GB images such as ML images would otherwise exhaust the memory of small CI runners.
+	memoryThreshold = int64(10) << 20
+)
+
+// errTmpSizeLimit is returned when extracting the file would exceed the size limit of temporary files
+var errTmpSizeLimit = xerrors.New("the size limit of temporary files exceeded")
+
+// layerWalker walks the files in a layer tar in the same way as fanal's walker.LayerTar.
+// The layer is streamed, and only the files required by analyzers are extracted.
+// In addition, it bounds the memory and the temporary files used for the extracted files.
+type layerWalker struct {
+	skipFiles []string
+	skipDirs  []string
+
+	// memoryThreshold is the size from which files are extracted to temporary files
+	memoryThreshold int64
+
+	// tmpDir holds the temporary files, os.TempDir() if empty
+	tmpDir string
+
+	// tmpSpace is shared by the layers analyzed concurrently
+	tmpSpace *tmpSpace
+}
+
+func newLayerWalker(skipFiles, skipDirs []string, opt Option) layerWalker {
+	var cleanSkipFiles, cleanSkipDirs []string
+	for _, skipFile := range skipFiles {
+		skipFile = filepath.Clean(filepath.ToSlash(skipFile))
+		skipFile = strings.TrimLeft(skipFile, "/")
+		cleanSkipFiles = append(cleanSkipFiles, skipFile)
+	}
+
+	for _, skipDir := range append(skipDirs, walker.SystemDirs...) {
+		skipDir = filepath.Clean(filepath.ToSlash(skipDir))
+		skipDir = strings.TrimLeft(skipDir, "/")
+		cleanSkipDirs = append(cleanSkipDirs, skipDir)
+	}
+
+	return layerWalker{
+		skipFiles:       cleanSkipFiles,
+		skipDirs:        cleanSkipDirs,
+		memoryThreshold: memoryThreshold,
+		tmpDir:          opt.TmpDir,
+		tmpSpace:        &tmpSpace{limit: opt.TmpSizeLimit},
+	}
+}
+
+// Walk walks the files in the layer tar, and returns the opaque directories and the whiteout files
+func (w layerWalker) Walk(layer io.Reader, analyzeFn walker.WalkFunc) ([]string, []string, error) {
+	var opqDirs, whFiles, skipDirs []string
+	tr := tar.NewReader(layer)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, nil, xerrors.Errorf("failed to extract the archive: %w", err)
+		}
+
+		filePath := hdr.Name
+		filePath = strings.TrimLeft(filepath.Clean(filePath), "/")
+		fileDir, fileName := filepath.Split(filePath)
+
+		// e.g. etc/.wh..wh..opq
+		if opq == fileName {
+			opqDirs = append(opqDirs, fileDir)
+			continue
+		}
+		// etc/.wh.hostname
+		if strings.HasPrefix(fileName, wh) {
+			name := strings.TrimPrefix(fileName, wh)
+			fpath := filepath.Join(fileDir, name)
+			whFiles = append(whFiles, fpath)
+			continue
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if w.shouldSkipDir(filePath) {
+				skipDirs = append(skipDirs, filePath)
+				continue
+			}
+		case tar.TypeSymlink, tar.TypeLink, tar.TypeReg:
+			if slices.Contains(w.skipFiles, filePath) {
+				continue
+			}
+		default:
+			continue
+		}
+
+		if underSkippedDir(filePath, skipDirs) {
+			continue
+		}
+
+		// A symbolic/hard link or regular file will reach here.
+		if err = w.processFile(filePath, tr, hdr.FileInfo(), analyzeFn); err != nil {
+			return nil, nil, xerrors.Errorf("failed to process the file: %w", err)
+		}
+	}
+	return opqDirs, whFiles, nil
+}
+
+func (w layerWalker) processFile(filePath string, tr *tar.Reader, fi os.FileInfo, analyzeFn walker.WalkFunc) error {
+	tf := &tarFile{
+		size:            fi.Size(),
+		reader:          tr,
+		memoryThreshold: w.memoryThreshold,
+		tmpDir:          w.tmpDir,
+		tmpSpace:        w.tmpSpace,
+	}
+	defer tf.clean()
+
+	if err := analyzeFn(filePath, fi, tf.Open); err != nil {
+		return xerrors.Errorf("failed to analyze file: %w", err)
+	}
+	return nil
+}
+
+func (w layerWalker) shouldSkipDir(dir string) bool {
+	// Skip application dirs (relative path)
+	if slices.Contains(walker.AppDirs, filepath.Base(dir)) {
+		return true
+	}
+	// Skip system dirs and specified dirs (absolute path)
+	return slices.Contains(w.skipDirs, dir)
+}
+
+func underSkippedDir(filePath string, skipDirs []string) bool {
+	for _, skipDir := range skipDirs {
+		rel, err := filepath.Rel(skipDir, filePath)
+		if err != nil {
+			return false
+		}
+		if !strings.HasPrefix(rel, "../") {
+			return true
+		}
+	}
+	return false
+}
+
+// tmpSpace tracks the total size of the temporary files in use
+type tmpSpace struct {
+	mu    sync.Mutex
+	limit int64 // unlimited if not positive
+	used  int64
+}
+
+// reserve reports whether the size can be used without exceeding the limit, and reserves it if so
+func (s *tmpSpace) reserve(size int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.limit > 0 && s.used+size > s.limit {
+		return false
+	}
+	s.used += size
+	return true
+}
+
+func (s *tmpSpace) release(size int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.used -= size
+}
+
+// tarFile represents a file in a layer tar.
+// The content is read from the tar on the first Open, and shared by the analyzers.
+// Small files are held in memory, and large files are extracted to temporary files.
+type tarFile struct {
+	once sync.Once
+	err  error
+
+	size   int64
+	reader io.Reader
+
+	memoryThreshold int64
+	tmpDir          string
+	tmpSpace        *tmpSpace
+
+	content []byte // It will be populated if this file is small
+
+	// The temporary file is removed once the file is walked,
+	// and the reserved space is released once the analyzers close it as well.
+	mu       sync.Mutex
+	filePath string // It will be populated if this file is large
+	reserved bool
+	refs     int
+	cleaned  bool
+}
+
+// Open opens the file in the layer tar.
+// It returns errTmpSizeLimit if the file is too large to be extracted within the size limit.
+func (o *tarFile) Open() (dio.ReadSeekCloserAt, error) {
+	o.once.Do(func() {
+		if o.size < o.memoryThreshold {
+			b, err := io.ReadAll(o.reader)
+			if err != nil {
+				o.err = xerrors.Errorf("unable to read the file: %w", err)
+				return
+			}
+			o.content = b
+			return
+		}
+
+		if !o.tmpSpace.reserve(o.size) {
+			o.err = errTmpSizeLimit
+			return
+		}
+		o.reserved = true
+		o.err = o.extract()
+	})
+	if o.err != nil {
+		return nil, xerrors.Errorf("failed to open: %w", o.err)
+	}
+
+	if o.filePath == "" {
+		return dio.NopCloser(bytes.NewReader(o.content)), nil
+	}
+
+	f, err := os.Open(o.filePath)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to open the temp file: %w", err)
+	}
+	o.mu.Lock()
+	o.refs++
+	o.mu.Unlock()
+	return &tmpFile{File: f, closed: o.unref}, nil
+}
+
+// extract copies the content to a temporary file
+func (o *tarFile) extract() error {
+	f, err := os.CreateTemp(o.tmpDir, "trivy-*")
+	if err != nil {
+		return xerrors.Errorf("failed to create the temp file: %w", err)
+	}
+	defer f.Close()
+
+	// The path is set first so that the file is removed even if the copy fails
+	o.filePath = f.Name()
+	if _, err = io.Copy(f, o.reader); err != nil {
+		return xerrors.Errorf("failed to copy: %w", err)
+	}
+	return nil
+}
+
+// clean removes the temporary file. Analyzers can still read the file opened before.
+func (o *tarFile) clean() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if !o.reserved || o.cleaned {
+		return
+	}
+	o.cleaned = true
+	if o.filePath != "" {
+		if err := os.Remove(o.filePath); err != nil {
+			log.Logger.Debugf("Unable to remove the temp file %s: %s", o.filePath, err)
+		}
+	}
+	if o.refs == 0 {
+		o.tmpSpace.release(o.size)
+	}
+}
+
+func (o *tarFile) unref() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.refs--
+	if o.refs == 0 && o.cleaned {
+		o.tmpSpace.release(o.size)
+	}
+}
+
+// tmpFile notifies the tar file when it is closed
+type tmpFile struct {
+	*os.File
+	closeOnce sync.Once
+	closed    func()
+}
+
+func (f *tmpFile) Close() error {
+	err := f.File.Close()
+	f.closeOnce.Do(f.closed)
+	return err
+}
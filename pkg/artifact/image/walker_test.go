@@ -0,0 +1,181 @@
+package image
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+)
+
+func newLayerTar(t *testing.T, files map[string]string) io.Reader {
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	for _, name := range names {
+		hdr := &tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(files[name])),
+			Typeflag: tar.TypeReg,
+		}
+		if strings.HasSuffix(name, "/") {
+			hdr.Size, hdr.Typeflag = 0, tar.TypeDir
+		}
+		require.NoError(t, tw.WriteHeader(hdr))
+		_, err := tw.Write([]byte(files[name]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return buf
+}
+
+func TestLayerWalker_Walk(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       map[string]string
+		skipDirs    []string
+		opt         Option
+		want        map[string]string
+		wantSkipped []string
+		wantOpqDirs []string
+		wantWhFiles []string
+	}{
+		{
+			name: "small and large files",
+			files: map[string]string{
+				"etc/alpine-release": "3.16",
+				"models/model.bin":   strings.Repeat("x", 64),
+			},
+			want: map[string]string{
+				"etc/alpine-release": "3.16",
+				"models/model.bin":   strings.Repeat("x", 64),
+			},
+		},
+		{
+			name: "size limit of temporary files",
+			files: map[string]string{
+				"etc/alpine-release": "3.16",
+				"models/a.bin":       strings.Repeat("a", 64),
+				"models/b.bin":       strings.Repeat("b", 128),
+			},
+			opt: Option{TmpSizeLimit: 100},
+			want: map[string]string{
+				"etc/alpine-release": "3.16",
+				"models/a.bin":       strings.Repeat("a", 64),
+			},
+			wantSkipped: []string{"models/b.bin"},
+		},
+		{
+			name: "skip dirs and whiteouts",
+			files: map[string]string{
+				"app/":                  "",
+				"app/package.json":      "{}",
+				"etc/.wh..wh..opq":      "",
+				"etc/.wh.hostname":      "",
+				"proc/":                 "",
+				"proc/version":          "Linux",
+				"vendor/":               "",
+				"vendor/modules.txt":    "",
+				"usr/lib/os-release":    "ID=alpine",
+				"usr/skip/":             "",
+				"usr/skip/os-release":   "ID=debian",
+				"usr/skip/nested/":      "",
+				"usr/skip/nested/a.txt": "a",
+			},
+			skipDirs: []string{"/usr/skip"},
+			want: map[string]string{
+				"app/package.json":   "{}",
+				"usr/lib/os-release": "ID=alpine",
+			},
+			wantOpqDirs: []string{"etc/"},
+			wantWhFiles: []string{"etc/hostname"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opt.TmpDir = t.TempDir()
+			w := newLayerWalker(nil, tt.skipDirs, tt.opt)
+			w.memoryThreshold = 16
+
+			got := map[string]string{}
+			var skipped []string
+			opqDirs, whFiles, err := w.Walk(newLayerTar(t, tt.files), func(filePath string, info os.FileInfo, opener analyzer.Opener) error {
+				if info.IsDir() {
+					return nil
+				}
+				r, err := opener()
+				if xerrors.Is(err, errTmpSizeLimit) {
+					skipped = append(skipped, filePath)
+					return nil
+				}
+				require.NoError(t, err)
+				defer r.Close()
+
+				b, err := io.ReadAll(r)
+				require.NoError(t, err)
+				got[filePath] = string(b)
+				return nil
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantSkipped, skipped)
+			assert.Equal(t, tt.wantOpqDirs, opqDirs)
+			assert.Equal(t, tt.wantWhFiles, whFiles)
+
+			// Temporary files are removed and their space is released once the files are walked
+			entries, err := os.ReadDir(tt.opt.TmpDir)
+			require.NoError(t, err)
+			assert.Empty(t, entries)
+			assert.Zero(t, w.tmpSpace.used)
+		})
+	}
+}
+
+func TestTarFile_Open(t *testing.T) {
+	space := &tmpSpace{limit: 100}
+	tf := &tarFile{
+		size:            64,
+		reader:          strings.NewReader(strings.Repeat("x", 64)),
+		memoryThreshold: 16,
+		tmpDir:          t.TempDir(),
+		tmpSpace:        space,
+	}
+
+	// Analyzers share the extracted file
+	r1, err := tf.Open()
+	require.NoError(t, err)
+	r2, err := tf.Open()
+	require.NoError(t, err)
+	assert.Equal(t, int64(64), space.used)
+
+	// The space is kept while the analyzers are reading the file
+	tf.clean()
+	require.NoError(t, r1.Close())
+	assert.Equal(t, int64(64), space.used)
+
+	b, err := io.ReadAll(r2)
+	require.NoError(t, err)
+	assert.Len(t, b, 64)
+
+	require.NoError(t, r2.Close())
+	assert.Zero(t, space.used)
+
+	// Closing twice doesn't release the space twice
+	assert.Error(t, r2.Close())
+	assert.Zero(t, space.used)
+}
@@ -0,0 +1,404 @@
+package local
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/walker"
+	dio "github.com/aquasecurity/go-dep-parser/pkg/io"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+const (
+	// archiveSeparator separates the archive path and the path in the archive,
+	// e.g. mirror/wheels.tar!/requests-2.28.1-py3-none-any.whl!/requests-2.28.1.dist-info/METADATA
+	archiveSeparator = "!/"
+
+	// archiveMemoryThreshold is the size from which files in archives are extracted to temporary files instead of memory
+	archiveMemoryThreshold = int64(10) << 20
+
+	archiveZip   = "zip"
+	archiveTar   = "tar"
+	archiveTarGz = "tar.gz"
+)
+
+var (
+	// Java archives are not walked, as the jar analyzer analyzes them as a whole
+	archiveZipExts   = []string{".zip", ".whl", ".egg"}
+	archiveTarExts   = []string{".tar"}
+	archiveTarGzExts = []string{".tar.gz", ".tgz"}
+)
+
+// errArchiveSizeLimit is returned when extracting the file would exceed the size limit of files extracted from the archive
+var errArchiveSizeLimit = xerrors.New("the size limit of files extracted from the archive exceeded")
+
+// archiveFormat returns the format of the archive walked with --scan-archives, or an empty string if not an archive
+func archiveFormat(filePath string) string {
+	filePath = strings.ToLower(filePath)
+	switch {
+	case hasSuffix(filePath, archiveZipExts):
+		return archiveZip
+	case hasSuffix(filePath, archiveTarExts):
+		return archiveTar
+	case hasSuffix(filePath, archiveTarGzExts):
+		return archiveTarGz
+	}
+	return ""
+}
+
+func hasSuffix(s string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// archiveWalker walks the files in archives found in the filesystem, including nested archives,
+// so that artifact repositories mirrored to disk can be scanned in place.
+// Files are extracted only when analyzers open them. Small files are held in memory,
+// and large files are extracted to temporary files, which are removed by clean.
+type archiveWalker struct {
+	// maxDepth is the maximum nesting depth of archives walked, where files in the archive on disk are at depth 1
+	maxDepth int
+
+	// sizeLimit limits the total size of files extracted from each archive on disk if positive
+	sizeLimit int64
+
+	// tmpDir holds the temporary files, os.TempDir() if empty
+	tmpDir string
+
+	mu       sync.Mutex
+	tmpFiles []string
+}
+
+func newArchiveWalker(opt Option) *archiveWalker {
+	return &archiveWalker{
+		maxDepth:  opt.ArchiveDepth,
+		sizeLimit: opt.ArchiveSizeLimit,
+		tmpDir:    opt.TmpDir,
+	}
+}
+
+// archiveEntryError wraps errors returned by the walk function, which must not be regarded as broken archives
+type archiveEntryError struct {
+	err error
+}
+
+func (e archiveEntryError) Error() string {
+	return e.err.Error()
+}
+
+// Walk calls fn for each regular file in the archive at filePath, with paths such as "app.tar!/app/package-lock.json".
+// Broken archives are skipped with a warning.
+func (w *archiveWalker) Walk(filePath string, opener analyzer.Opener, fn walker.WalkFunc) error {
+	f, err := opener()
+	if err != nil {
+		return xerrors.Errorf("unable to open %s: %w", filePath, err)
+	}
+	defer f.Close()
+
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return xerrors.Errorf("seek error: %w", err)
+	} else if _, err = f.Seek(0, io.SeekStart); err != nil {
+		return xerrors.Errorf("seek error: %w", err)
+	}
+
+	budget := &archiveBudget{limit: w.sizeLimit}
+	err = w.walk(filePath, archiveFormat(filePath), f, size, 1, budget, fn)
+
+	var entryErr archiveEntryError
+	if errors.As(err, &entryErr) {
+		return entryErr.err
+	} else if err != nil {
+		log.Logger.Warnf("Unable to walk the archive %s: %s", filePath, err)
+	}
+	return nil
+}
+
+func (w *archiveWalker) walk(archivePath, format string, r io.Reader, size int64, depth int, budget *archiveBudget, fn walker.WalkFunc) error {
+	return walkArchiveEntries(format, r, size, func(name string, fi os.FileInfo, r io.Reader) error {
+		entryPath := archivePath + archiveSeparator + name
+		entry := &archiveEntry{
+			walker: w,
+			budget: budget,
+			size:   fi.Size(),
+			reader: r,
+		}
+
+		// Analyzers read the entry first, as the content of entries in tar archives can be read only once
+		if err := fn(entryPath, fi, entry.Open); err != nil {
+			return archiveEntryError{err: err}
+		}
+
+		if depth >= w.maxDepth {
+			return nil
+		}
+		nested := archiveFormat(name)
+		if nested == "" {
+			// e.g. blobs/sha256/... in "docker save" tarballs, which are image layers without extensions
+			nested = entry.sniff()
+		}
+		if nested == "" {
+			return nil
+		}
+
+		nr, closeFn, err := entry.nestedReader(nested)
+		if errors.Is(err, errArchiveSizeLimit) {
+			log.Logger.Warnf("Skipping %s (%d bytes): files extracted from the archive would exceed --archive-size-limit", entryPath, fi.Size())
+			return nil
+		} else if err != nil {
+			return xerrors.Errorf("%s: %w", name, err)
+		}
+		defer closeFn()
+
+		if err = w.walk(entryPath, nested, nr, fi.Size(), depth+1, budget, fn); err != nil {
+			var entryErr archiveEntryError
+			if errors.As(err, &entryErr) {
+				return err
+			}
+			// Broken nested archives don't stop walking the parent archive
+			log.Logger.Debugf("Unable to walk the nested archive %s: %s", entryPath, err)
+		}
+		return nil
+	})
+}
+
+func (w *archiveWalker) addTmpFile(filePath string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.tmpFiles = append(w.tmpFiles, filePath)
+}
+
+// clean removes the temporary files. It must be called after analyzers finish reading the files.
+func (w *archiveWalker) clean() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, f := range w.tmpFiles {
+		if err := os.Remove(f); err != nil {
+			log.Logger.Debugf("Unable to remove the temp file %s: %s", f, err)
+		}
+	}
+	w.tmpFiles = nil
+}
+
+// archiveBudget tracks the total size of files extracted from an archive on disk.
+// Archives are walked sequentially, so it is not safe for concurrent use.
+type archiveBudget struct {
+	limit int64 // unlimited if not positive
+	used  int64
+}
+
+func (b *archiveBudget) reserve(size int64) bool {
+	if b.limit > 0 && b.used+size > b.limit {
+		return false
+	}
+	b.used += size
+	return true
+}
+
+// archiveEntry represents a file in an archive.
+// The content is extracted on the first Open, and shared by the analyzers and the nested archive walk.
+type archiveEntry struct {
+	walker *archiveWalker
+	budget *archiveBudget
+
+	size   int64
+	reader io.Reader
+
+	once     sync.Once
+	err      error
+	content  []byte // It will be populated if this file is small
+	filePath string // It will be populated if this file is large
+
+	// buffered is used to sniff the format without consuming the content
+	buffered *bufio.Reader
+}
+
+// Open opens the file in the archive.
+// It returns errArchiveSizeLimit if extracting the file would exceed the size limit.
+func (e *archiveEntry) Open() (dio.ReadSeekCloserAt, error) {
+	e.once.Do(func() {
+		e.err = e.extract()
+	})
+	if e.err != nil {
+		return nil, xerrors.Errorf("failed to open: %w", e.err)
+	}
+
+	if e.filePath == "" {
+		return dio.NopCloser(bytes.NewReader(e.content)), nil
+	}
+	f, err := os.Open(e.filePath)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to open the temp file: %w", err)
+	}
+	return f, nil
+}
+
+func (e *archiveEntry) extract() error {
+	if !e.budget.reserve(e.size) {
+		return errArchiveSizeLimit
+	}
+
+	r := e.stream()
+	if e.size < archiveMemoryThreshold {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return xerrors.Errorf("unable to read the file: %w", err)
+		}
+		e.content = b
+		return nil
+	}
+
+	f, err := os.CreateTemp(e.walker.tmpDir, "trivy-archive-*")
+	if err != nil {
+		return xerrors.Errorf("failed to create the temp file: %w", err)
+	}
+	defer f.Close()
+
+	// The file is registered first so that it is removed even if the copy fails
+	e.walker.addTmpFile(f.Name())
+	e.filePath = f.Name()
+	if _, err = io.Copy(f, r); err != nil {
+		return xerrors.Errorf("failed to copy: %w", err)
+	}
+	return nil
+}
+
+// stream returns the content which has not been extracted
+func (e *archiveEntry) stream() io.Reader {
+	if e.buffered != nil {
+		return e.buffered
+	}
+	return e.reader
+}
+
+// extracted reports whether the content has been extracted to memory or a temporary file
+func (e *archiveEntry) extracted() bool {
+	return e.content != nil || e.filePath != ""
+}
+
+// sniff returns the format of tar archives without extensions
+func (e *archiveEntry) sniff() string {
+	header := make([]byte, 512)
+	switch {
+	case e.content != nil:
+		header = e.content
+	case e.filePath != "":
+		f, err := os.Open(e.filePath)
+		if err != nil {
+			return ""
+		}
+		defer f.Close()
+		n, _ := io.ReadFull(f, header)
+		header = header[:n]
+	case e.err == nil || errors.Is(e.err, errArchiveSizeLimit):
+		// The content is not consumed yet
+		e.buffered = bufio.NewReaderSize(e.reader, len(header))
+		header, _ = e.buffered.Peek(len(header))
+	default:
+		return ""
+	}
+
+	switch {
+	case len(header) >= 2 && header[0] == 0x1f && header[1] == 0x8b:
+		return archiveTarGz
+	case len(header) >= 262 && string(header[257:262]) == "ustar":
+		return archiveTar
+	}
+	return ""
+}
+
+// nestedReader returns the reader of the nested archive and the function to close it.
+// Tar archives are streamed unless they are extracted, and zip archives are extracted for random access.
+func (e *archiveEntry) nestedReader(format string) (io.Reader, func(), error) {
+	if format != archiveZip && !e.extracted() {
+		return e.stream(), func() {}, nil
+	}
+
+	r, err := e.Open()
+	if err != nil {
+		return nil, nil, err
+	}
+	return r, func() { _ = r.Close() }, nil
+}
+
+// walkArchiveEntries calls fn for each regular file in the archive
+func walkArchiveEntries(format string, r io.Reader, size int64, fn func(name string, fi os.FileInfo, r io.Reader) error) error {
+	switch format {
+	case archiveZip:
+		ra, ok := r.(io.ReaderAt)
+		if !ok {
+			return xerrors.New("zip archives must be read at random")
+		}
+		return walkZip(ra, size, fn)
+	case archiveTarGz:
+		gr, err := gzip.NewReader(r)
+		if err != nil {
+			return xerrors.Errorf("gzip error: %w", err)
+		}
+		defer gr.Close()
+		return walkTar(gr, fn)
+	}
+	return walkTar(r, fn)
+}
+
+func walkZip(r io.ReaderAt, size int64, fn func(name string, fi os.FileInfo, r io.Reader) error) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return xerrors.Errorf("zip error: %w", err)
+	}
+
+	for _, f := range zr.File {
+		if !f.Mode().IsRegular() {
+			continue
+		}
+		if err = walkZipFile(f, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func walkZipFile(f *zip.File, fn func(name string, fi os.FileInfo, r io.Reader) error) error {
+	rc, err := f.Open()
+	if err != nil {
+		return xerrors.Errorf("%s: open error: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	return fn(path.Clean(strings.TrimPrefix(f.Name, "/")), f.FileInfo(), rc)
+}
+
+func walkTar(r io.Reader, fn func(name string, fi os.FileInfo, r io.Reader) error) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return xerrors.Errorf("tar error: %w", err)
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err = fn(path.Clean(strings.TrimPrefix(hdr.Name, "/")), hdr.FileInfo(), tr); err != nil {
+			return err
+		}
+	}
+}
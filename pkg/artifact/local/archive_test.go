@@ -0,0 +1,216 @@
+package local
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+)
+
+func newTar(t *testing.T, files map[string][]byte) []byte {
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	for _, name := range names {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(files[name])),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tw.Write(files[name])
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func newZip(t *testing.T, files map[string][]byte) []byte {
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func gzipped(t *testing.T, b []byte) []byte {
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+	_, err := gw.Write(b)
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+	return buf.Bytes()
+}
+
+// newMirror returns a tar archive as below.
+//
+//	requirements.txt
+//	wheels/requests-2.28.1-py3-none-any.whl
+//	  requests-2.28.1.dist-info/METADATA
+//	image.tar ("docker save" tarball)
+//	  manifest.json
+//	  blobs/sha256/0123 (gzipped layer without extensions)
+//	    app/requirements.txt
+func newMirror(t *testing.T) []byte {
+	layer := gzipped(t, newTar(t, map[string][]byte{
+		"app/requirements.txt": []byte("flask==2.0.0\n"),
+	}))
+	return newTar(t, map[string][]byte{
+		"requirements.txt": []byte("django==4.0.0\n"),
+		"wheels/requests-2.28.1-py3-none-any.whl": newZip(t, map[string][]byte{
+			"requests-2.28.1.dist-info/METADATA": []byte("Name: requests\nVersion: 2.28.1\n"),
+		}),
+		"image.tar": newTar(t, map[string][]byte{
+			"manifest.json":     []byte(`[{"Layers":["blobs/sha256/0123"]}]`),
+			"blobs/sha256/0123": layer,
+		}),
+	})
+}
+
+func TestArchiveWalker_Walk(t *testing.T) {
+	tests := []struct {
+		name        string
+		option      Option
+		want        map[string]string
+		wantSkipped []string
+	}{
+		{
+			name:   "nested archives",
+			option: Option{ArchiveDepth: 3},
+			want: map[string]string{
+				"mirror.tar!/requirements.txt": "django==4.0.0\n",
+				"mirror.tar!/wheels/requests-2.28.1-py3-none-any.whl!/requests-2.28.1.dist-info/METADATA": "Name: requests\nVersion: 2.28.1\n",
+				"mirror.tar!/image.tar!/manifest.json":                                                    `[{"Layers":["blobs/sha256/0123"]}]`,
+				"mirror.tar!/image.tar!/blobs/sha256/0123!/app/requirements.txt":                          "flask==2.0.0\n",
+			},
+		},
+		{
+			name:   "depth limit",
+			option: Option{ArchiveDepth: 1},
+			want: map[string]string{
+				"mirror.tar!/requirements.txt": "django==4.0.0\n",
+			},
+		},
+		{
+			// Nested tar archives are streamed without being extracted, but zip archives are extracted
+			name: "size limit",
+			option: Option{
+				ArchiveDepth:     3,
+				ArchiveSizeLimit: 20,
+			},
+			want: map[string]string{
+				"mirror.tar!/image.tar!/blobs/sha256/0123!/app/requirements.txt": "flask==2.0.0\n",
+			},
+			wantSkipped: []string{
+				"mirror.tar!/image.tar!/manifest.json",
+				"mirror.tar!/requirements.txt",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.option.TmpDir = t.TempDir()
+			w := newArchiveWalker(tt.option)
+
+			archivePath := filepath.Join(t.TempDir(), "mirror.tar")
+			require.NoError(t, os.WriteFile(archivePath, newMirror(t), 0644))
+
+			got := map[string]string{}
+			var skipped []string
+			err := w.Walk("mirror.tar", fileOpener(archivePath), func(filePath string, info os.FileInfo, opener analyzer.Opener) error {
+				// Nested archives are opened only if needed
+				if archiveFormat(filePath) != "" || strings.HasPrefix(info.Name(), "0123") {
+					return nil
+				}
+
+				r, err := opener()
+				if xerrors.Is(err, errArchiveSizeLimit) {
+					skipped = append(skipped, filePath)
+					return nil
+				}
+				require.NoError(t, err)
+				defer r.Close()
+
+				b, err := io.ReadAll(r)
+				require.NoError(t, err)
+				assert.Equal(t, int64(len(b)), info.Size())
+				got[filePath] = string(b)
+				return nil
+			})
+			require.NoError(t, err)
+			w.clean()
+
+			sort.Strings(skipped)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantSkipped, skipped)
+
+			entries, err := os.ReadDir(tt.option.TmpDir)
+			require.NoError(t, err)
+			assert.Empty(t, entries)
+		})
+	}
+}
+
+func TestArchiveWalker_Walk_Large(t *testing.T) {
+	// Large files are extracted to temporary files, which are removed by clean
+	large := strings.Repeat("x", int(archiveMemoryThreshold))
+	archivePath := filepath.Join(t.TempDir(), "large.zip")
+	require.NoError(t, os.WriteFile(archivePath, newZip(t, map[string][]byte{"large.txt": []byte(large)}), 0644))
+
+	tmpDir := t.TempDir()
+	w := newArchiveWalker(Option{ArchiveDepth: 1, TmpDir: tmpDir})
+	err := w.Walk("large.zip", fileOpener(archivePath), func(filePath string, info os.FileInfo, opener analyzer.Opener) error {
+		r, err := opener()
+		require.NoError(t, err)
+		defer r.Close()
+
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, large, string(b))
+		return nil
+	})
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(tmpDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	w.clean()
+	entries, err = os.ReadDir(tmpDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestArchiveWalker_Walk_Broken(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "broken.tar.gz")
+	require.NoError(t, os.WriteFile(archivePath, []byte("broken"), 0644))
+
+	// Broken archives don't fail the scan
+	w := newArchiveWalker(Option{ArchiveDepth: 1})
+	err := w.Walk("broken.tar.gz", fileOpener(archivePath), func(string, os.FileInfo, analyzer.Opener) error {
+		return xerrors.New("unexpected call")
+	})
+	assert.NoError(t, err)
+}
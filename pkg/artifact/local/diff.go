@@ -0,0 +1,171 @@
+package local
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+)
+
+// dependencyFileGroups holds manifests and lock files which must be analyzed together.
+// When one of them is changed, the others in the same directory are analyzed as well
+// so that the dependencies can be resolved correctly.
+var dependencyFileGroups = [][]string{
+	{"package.json", "package-lock.json", "yarn.lock", "pnpm-lock.yaml"},
+	{"go.mod", "go.sum"},
+	{"Gemfile", "Gemfile.lock"},
+	{"Pipfile", "Pipfile.lock"},
+	{"pyproject.toml", "poetry.lock"},
+	{"composer.json", "composer.lock"},
+	{"Cargo.toml", "Cargo.lock"},
+	{"Podfile", "Podfile.lock"},
+	{"Package.swift", "Package.resolved"},
+	{"Cartfile", "Cartfile.resolved"},
+	{"pubspec.yaml", "pubspec.lock"},
+	{"environment.yml", "environment.yaml", "conda-lock.yml"},
+	{"MODULE.bazel", "MODULE.bazel.lock"},
+}
+
+// changedFiles returns files changed since the given git revision, including uncommitted changes.
+// The returned paths are slash-separated and relative to rootPath.
+func changedFiles(rootPath, revision string) (map[string]struct{}, error) {
+	r, err := git.PlainOpenWithOptions(rootPath, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, xerrors.Errorf("git open error: %w", err)
+	}
+
+	wt, err := r.Worktree()
+	if err != nil {
+		return nil, xerrors.Errorf("git worktree error: %w", err)
+	}
+
+	baseTree, err := revisionTree(r, plumbing.Revision(revision))
+	if err != nil {
+		return nil, xerrors.Errorf("base revision error: %w", err)
+	}
+
+	headTree, err := revisionTree(r, plumbing.Revision(plumbing.HEAD))
+	if err != nil {
+		return nil, xerrors.Errorf("HEAD error: %w", err)
+	}
+
+	// Committed changes between the base revision and HEAD
+	changes, err := object.DiffTree(baseTree, headTree)
+	if err != nil {
+		return nil, xerrors.Errorf("git diff error: %w", err)
+	}
+
+	var paths []string
+	for _, c := range changes {
+		// Deleted files don't need to be analyzed
+		if c.To.Name != "" {
+			paths = append(paths, c.To.Name)
+		}
+	}
+
+	// Uncommitted changes including untracked files
+	status, err := wt.Status()
+	if err != nil {
+		return nil, xerrors.Errorf("git status error: %w", err)
+	}
+	for p, s := range status {
+		if s.Worktree == git.Deleted || s.Staging == git.Deleted {
+			continue
+		}
+		if s.Worktree != git.Unmodified || s.Staging != git.Unmodified {
+			paths = append(paths, p)
+		}
+	}
+
+	baseDir, err := baseDirectory(rootPath)
+	if err != nil {
+		return nil, err
+	}
+
+	repoRoot, err := filepath.EvalSymlinks(wt.Filesystem.Root())
+	if err != nil {
+		return nil, xerrors.Errorf("unable to resolve the repository root: %w", err)
+	}
+
+	files := map[string]struct{}{}
+	for _, p := range paths {
+		rel, err := filepath.Rel(baseDir, filepath.Join(repoRoot, filepath.FromSlash(p)))
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			// Out of the scan target
+			continue
+		}
+		files[filepath.ToSlash(rel)] = struct{}{}
+	}
+
+	addDependencyFiles(files)
+
+	return files, nil
+}
+
+func revisionTree(r *git.Repository, revision plumbing.Revision) (*object.Tree, error) {
+	hash, err := r.ResolveRevision(revision)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to resolve %s: %w", revision, err)
+	}
+
+	commit, err := r.CommitObject(*hash)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to get the commit %s: %w", hash, err)
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, xerrors.Errorf("unable to get the tree of %s: %w", hash, err)
+	}
+	return tree, nil
+}
+
+// baseDirectory returns the directory that file paths passed to analyzers are relative to.
+func baseDirectory(rootPath string) (string, error) {
+	absPath, err := filepath.Abs(rootPath)
+	if err != nil {
+		return "", xerrors.Errorf("absolute path error: %w", err)
+	}
+
+	absPath, err = filepath.EvalSymlinks(absPath)
+	if err != nil {
+		return "", xerrors.Errorf("unable to resolve %s: %w", rootPath, err)
+	}
+
+	fi, err := os.Stat(absPath)
+	if err != nil {
+		return "", xerrors.Errorf("stat error: %w", err)
+	}
+
+	// When a file is given, file paths are relative to its directory
+	if !fi.IsDir() {
+		return filepath.Dir(absPath), nil
+	}
+	return absPath, nil
+}
+
+// addDependencyFiles adds manifests and lock files related to the changed files.
+func addDependencyFiles(files map[string]struct{}) {
+	var related []string
+	for file := range files {
+		dir, base := path.Split(file)
+		for _, group := range dependencyFileGroups {
+			if !slices.Contains(group, base) {
+				continue
+			}
+			for _, name := range group {
+				related = append(related, dir+name)
+			}
+		}
+	}
+
+	for _, file := range related {
+		files[file] = struct{}{}
+	}
+}
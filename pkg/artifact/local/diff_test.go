@@ -0,0 +1,138 @@
+package local
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/maps"
+)
+
+func writeFile(t *testing.T, dir, name, content string) {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+// setupRepository creates a git repository where the files below are changed after the tag "base".
+//
+//	app/package.json (committed)
+//	api/go.sum       (staged)
+//	new.txt          (untracked)
+func setupRepository(t *testing.T) string {
+	dir := t.TempDir()
+	r, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+
+	w, err := r.Worktree()
+	require.NoError(t, err)
+
+	sig := &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}
+
+	writeFile(t, dir, "README.md", "readme")
+	writeFile(t, dir, "app/package.json", "{}")
+	writeFile(t, dir, "app/package-lock.json", "{}")
+	writeFile(t, dir, "api/go.mod", "module example.com/api")
+	writeFile(t, dir, "api/go.sum", "")
+	writeFile(t, dir, "deleted.txt", "deleted")
+	_, err = w.Add(".")
+	require.NoError(t, err)
+	base, err := w.Commit("base", &git.CommitOptions{Author: sig})
+	require.NoError(t, err)
+	_, err = r.CreateTag("base", base, nil)
+	require.NoError(t, err)
+
+	writeFile(t, dir, "app/package.json", `{"name": "app"}`)
+	_, err = w.Add("app/package.json")
+	require.NoError(t, err)
+	_, err = w.Remove("deleted.txt")
+	require.NoError(t, err)
+	_, err = w.Commit("update", &git.CommitOptions{Author: sig})
+	require.NoError(t, err)
+
+	writeFile(t, dir, "api/go.sum", "golang.org/x/text v0.3.7 h1:xxx")
+	_, err = w.Add("api/go.sum")
+	require.NoError(t, err)
+
+	writeFile(t, dir, "new.txt", "new")
+
+	return dir
+}
+
+func Test_changedFiles(t *testing.T) {
+	repoDir := setupRepository(t)
+
+	tests := []struct {
+		name     string
+		rootPath string
+		revision string
+		want     []string
+		wantErr  string
+	}{
+		{
+			name:     "repository root",
+			rootPath: repoDir,
+			revision: "base",
+			want: []string{
+				"api/go.mod",
+				"api/go.sum",
+				"app/package-lock.json",
+				"app/package.json",
+				"app/pnpm-lock.yaml",
+				"app/yarn.lock",
+				"new.txt",
+			},
+		},
+		{
+			name:     "sub directory",
+			rootPath: filepath.Join(repoDir, "app"),
+			revision: "base",
+			want: []string{
+				"package-lock.json",
+				"package.json",
+				"pnpm-lock.yaml",
+				"yarn.lock",
+			},
+		},
+		{
+			name:     "HEAD",
+			rootPath: repoDir,
+			revision: "HEAD",
+			want: []string{
+				"api/go.mod",
+				"api/go.sum",
+				"new.txt",
+			},
+		},
+		{
+			name:     "unknown revision",
+			rootPath: repoDir,
+			revision: "unknown",
+			wantErr:  "base revision error",
+		},
+		{
+			name:     "not a git repository",
+			rootPath: t.TempDir(),
+			revision: "HEAD",
+			wantErr:  "git open error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := changedFiles(tt.rootPath, tt.revision)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			files := maps.Keys(got)
+			assert.ElementsMatch(t, tt.want, files)
+		})
+	}
+}
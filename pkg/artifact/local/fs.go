@@ -0,0 +1,450 @@
+package local
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	digest "github.com/opencontainers/go-digest"
+	"golang.org/x/sync/semaphore"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/analyzer/config"
+	"github.com/aquasecurity/fanal/artifact"
+	"github.com/aquasecurity/fanal/cache"
+	"github.com/aquasecurity/fanal/handler"
+	"github.com/aquasecurity/fanal/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/analyzer/secret"
+	"github.com/aquasecurity/trivy/pkg/arm"
+	"github.com/aquasecurity/trivy/pkg/cdk"
+	"github.com/aquasecurity/trivy/pkg/cloudformation"
+	"github.com/aquasecurity/trivy/pkg/helm"
+	"github.com/aquasecurity/trivy/pkg/ignore"
+	"github.com/aquasecurity/trivy/pkg/kustomize"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/progress"
+	"github.com/aquasecurity/trivy/pkg/pulumi"
+	"github.com/aquasecurity/trivy/pkg/tfplan"
+	"github.com/aquasecurity/trivy/pkg/timeout"
+)
+
+// defaultParallel is the number of files analyzed concurrently by default.
+// Analysis is a mix of I/O and CPU work, so it is twice the number of CPUs.
+var defaultParallel = 2 * runtime.NumCPU()
+
+// Option holds the options for filesystem scanning which are not supported in fanal
+type Option struct {
+	// DiffBase is a git revision.
+	// If it is specified, only files changed since the revision are analyzed.
+	DiffBase string
+
+	// Files are slash-separated paths relative to the root path.
+	// If it is not empty, only the files and the related manifests and lock files are analyzed.
+	Files []string
+
+	// Rootfs makes symbolic links resolved relative to the root path as chroot does by default,
+	// and system directories such as proc are skipped relative to the root path.
+	Rootfs bool
+
+	// FollowSymlinks is the policy of following symbolic links to files, SymlinksNone or SymlinksRoot in rootfs if empty.
+	// Symbolic links to directories are never followed.
+	FollowSymlinks string
+
+	// IncludeSystemDirs walks the system directories such as proc, sys and dev, which are skipped by default.
+	IncludeSystemDirs bool
+
+	// FileSizeLimits limits the size of files passed to each analyzer.
+	FileSizeLimits tanalyzer.FileSizeLimits
+
+	// ScanArchives walks the files in zip, wheel and tar archives, including nested archives up to ArchiveDepth.
+	// ArchiveSizeLimit limits the total size of files extracted from each archive if positive.
+	ScanArchives     bool
+	ArchiveDepth     int
+	ArchiveSizeLimit int64
+
+	// ArchiveAnalyzers analyze only the files in archives, e.g. the individual package analyzers for wheels in filesystem scanning.
+	ArchiveAnalyzers []analyzer.Type
+
+	// TmpDir holds the temporary files of large files extracted from archives, os.TempDir() if empty.
+	TmpDir string
+
+	// IncludePaths and ExcludePaths are doublestar patterns of paths relative to the root path.
+	// When IncludePaths is not empty, only matched files are analyzed.
+	// Files and directories matched by ExcludePaths are skipped.
+	IncludePaths []string
+	ExcludePaths []string
+
+	// UseGitignore skips files and directories ignored by .gitignore and .git/info/exclude in the root path.
+	UseGitignore bool
+
+	// Parallel is the number of files analyzed concurrently.
+	// The default is used if it is not positive.
+	Parallel int
+
+	// SecretScannerOption is used instead of the one in fanal, which supports only a config path.
+	SecretScannerOption secret.ScannerOption
+
+	// SecretHistory scans the last SecretHistoryDepth commits in the git repository for secrets.
+	// All the commits are scanned if SecretHistoryDepth is not positive.
+	SecretHistory      bool
+	SecretHistoryDepth int
+
+	// HelmOption renders Helm charts with values overrides before Kubernetes checks are evaluated.
+	HelmOption helm.Option
+
+	// CloudFormationParameters are set to CloudFormation templates as parameter values.
+	CloudFormationParameters cloudformation.Parameters
+
+	// PolicyTimeout limits the evaluation of misconfiguration checks.
+	PolicyTimeout time.Duration
+
+	// Progress is notified of the progress of analyzing files.
+	Progress progress.Reporter
+
+	// ContinueOnError skips files which fail to be analyzed, e.g. corrupt archives, and records their errors in the result.
+	ContinueOnError bool
+}
+
+type Artifact struct {
+	rootPath       string
+	cache          cache.ArtifactCache
+	walker         fsWalker
+	analyzer       tanalyzer.Group
+	handlerManager handler.Manager
+	helmRenderer   *helm.Renderer
+
+	artifactOption artifact.Option
+	option         Option
+}
+
+func NewArtifact(rootPath string, c cache.ArtifactCache, artifactOpt artifact.Option, opt Option) (artifact.Artifact, error) {
+	// Register config analyzers
+	if err := config.RegisterConfigAnalyzers(artifactOpt.MisconfScannerOption.FilePatterns); err != nil {
+		return nil, xerrors.Errorf("config analyzer error: %w", err)
+	}
+
+	handlerManager, err := handler.NewManager(artifactOpt)
+	if err != nil {
+		return nil, xerrors.Errorf("handler initialize error: %w", err)
+	}
+
+	// Register secret analyzer
+	if err = secret.RegisterSecretAnalyzer(opt.SecretScannerOption); err != nil {
+		return nil, xerrors.Errorf("secret scanner error: %w", err)
+	}
+
+	var helmRenderer *helm.Renderer
+	if opt.HelmOption.Enabled() {
+		renderer, err := helm.NewRenderer(opt.HelmOption)
+		if err != nil {
+			return nil, xerrors.Errorf("helm renderer error: %w", err)
+		}
+		helmRenderer = &renderer
+	}
+
+	skipFiles := buildAbsPaths(rootPath, artifactOpt.SkipFiles)
+	skipDirs := buildAbsPaths(rootPath, artifactOpt.SkipDirs)
+
+	if opt.Parallel <= 0 {
+		opt.Parallel = defaultParallel
+	}
+
+	if opt.Progress == nil {
+		opt.Progress = progress.Nop()
+	}
+
+	return Artifact{
+		rootPath:       filepath.Clean(rootPath),
+		cache:          c,
+		walker:         newFSWalker(rootPath, skipFiles, skipDirs, opt),
+		analyzer:       tanalyzer.NewGroup(artifactOpt.AnalyzerGroup, artifactOpt.DisabledAnalyzers, opt.ContinueOnError),
+		handlerManager: handlerManager,
+		helmRenderer:   helmRenderer,
+
+		artifactOption: artifactOpt,
+		option:         opt,
+	}, nil
+}
+
+func buildAbsPaths(base string, paths []string) []string {
+	var absPaths []string
+	for _, path := range paths {
+		if filepath.IsAbs(path) {
+			absPaths = append(absPaths, path)
+		} else {
+			absPaths = append(absPaths, filepath.Join(base, path))
+		}
+	}
+	return absPaths
+}
+
+func (a Artifact) Inspect(ctx context.Context) (types.ArtifactReference, error) {
+	var wg sync.WaitGroup
+	result := analyzer.NewAnalysisResult()
+	limit := semaphore.NewWeighted(int64(a.option.Parallel))
+
+	filter, err := a.fileFilter()
+	if err != nil {
+		return types.ArtifactReference{}, xerrors.Errorf("file filter error: %w", err)
+	}
+
+	// Temporary files extracted from archives are removed once the analyzers finish
+	archives := newArchiveWalker(a.option)
+	defer archives.clean()
+
+	analyzeFile := func(directory, filePath string, info os.FileInfo, opener analyzer.Opener, disabled []analyzer.Type) error {
+		var analyzers int
+		opts := analyzer.AnalysisOptions{Offline: a.artifactOption.Offline}
+		// The walker calls this concurrently, so the given slice must not be appended to
+		disabled = append(a.option.FileSizeLimits.Exceeded(info.Size()), disabled...)
+		// Files in archives are not scanned twice for secrets
+		if a.option.ScanArchives && archiveFormat(filePath) != "" {
+			disabled = append(disabled, analyzer.TypeSecret)
+		}
+		err := a.analyzer.AnalyzeFile(ctx, &wg, limit, result, directory, filePath, info, progress.CountOpener(opener, &analyzers), disabled, opts)
+		if errors.Is(err, errArchiveSizeLimit) {
+			log.Logger.Warnf("Skipping %s (%d bytes): files extracted from the archive would exceed --archive-size-limit", filePath, info.Size())
+			return nil
+		} else if err != nil {
+			return a.skipError(result, filePath, xerrors.Errorf("analyze file (%s): %w", filePath, err))
+		}
+		if !info.IsDir() {
+			a.option.Progress.File("", analyzers)
+		}
+		return nil
+	}
+
+	a.option.Progress.Start(0)
+	a.option.Progress.LayerStarted("")
+	err = a.walker.Walk(a.rootPath, func(filePath string, info os.FileInfo, opener analyzer.Opener) error {
+		directory := a.rootPath
+
+		// When the directory is the same as the filePath, a file was given
+		// instead of a directory, rewrite the directory in this case.
+		if a.rootPath == filePath {
+			directory = filepath.Dir(a.rootPath)
+		}
+
+		// For exported rootfs (e.g. images/alpine/etc/alpine-release)
+		filePath, err := filepath.Rel(directory, filePath)
+		if err != nil {
+			return xerrors.Errorf("filepath rel (%s): %w", filePath, err)
+		}
+
+		if !filter(filePath) {
+			return nil
+		}
+
+		if err = analyzeFile(directory, filePath, info, opener, a.option.ArchiveAnalyzers); err != nil {
+			return err
+		}
+
+		if !a.option.ScanArchives || archiveFormat(filePath) == "" {
+			return nil
+		}
+		err = archives.Walk(filePath, opener, func(entryPath string, entryInfo os.FileInfo, entryOpener analyzer.Opener) error {
+			return analyzeFile(directory, entryPath, entryInfo, entryOpener, nil)
+		})
+		if err != nil {
+			return a.skipError(result, filePath, xerrors.Errorf("archive walk (%s): %w", filePath, err))
+		}
+		return nil
+	})
+	if err != nil {
+		a.option.Progress.Finish()
+		return types.ArtifactReference{}, xerrors.Errorf("walk filesystem: %w", err)
+	}
+
+	// Wait for all the goroutine to finish.
+	wg.Wait()
+	a.option.Progress.LayerFinished("")
+	a.option.Progress.Finish()
+
+	// Sort the analysis result for consistent results
+	result.Sort()
+
+	if a.option.SecretHistory {
+		resources, err := secret.ScanHistory(ctx, a.option.SecretScannerOption, a.rootPath, a.option.SecretHistoryDepth)
+		if err != nil {
+			log.Logger.Warnf("Unable to scan the git history for secrets: %s", err)
+		}
+		result.CustomResources = append(result.CustomResources, resources...)
+	}
+
+	// Inline ignore rules are read before config files are converted
+	result.CustomResources = append(result.CustomResources, ignore.Resources(result.Files[types.MisconfPostHandler])...)
+
+	blobInfo := types.BlobInfo{
+		SchemaVersion:   types.BlobJSONSchemaVersion,
+		OS:              result.OS,
+		Repository:      result.Repository,
+		PackageInfos:    result.PackageInfos,
+		Applications:    result.Applications,
+		Secrets:         result.Secrets,
+		CustomResources: result.CustomResources,
+	}
+
+	relabel := a.renderConfigFiles(result)
+
+	err = timeout.Run(ctx, timeout.PhasePolicy, a.option.PolicyTimeout, func(ctx context.Context) error {
+		return a.handlerManager.PostHandle(ctx, result, &blobInfo)
+	})
+	if err != nil {
+		return types.ArtifactReference{}, xerrors.Errorf("failed to call hooks: %w", err)
+	}
+	relabel(blobInfo.Misconfigurations)
+
+	cacheKey, err := a.calcCacheKey(blobInfo)
+	if err != nil {
+		return types.ArtifactReference{}, xerrors.Errorf("failed to calculate a cache key: %w", err)
+	}
+
+	if err = a.cache.PutBlob(cacheKey, blobInfo); err != nil {
+		return types.ArtifactReference{}, xerrors.Errorf("failed to store blob (%s) in cache: %w", cacheKey, err)
+	}
+
+	// get hostname
+	var hostName string
+	b, err := os.ReadFile(filepath.Join(a.rootPath, "etc", "hostname"))
+	if err == nil && string(b) != "" {
+		hostName = strings.TrimSpace(string(b))
+	} else {
+		hostName = a.rootPath
+	}
+
+	return types.ArtifactReference{
+		Name:    hostName,
+		Type:    types.ArtifactFilesystem,
+		ID:      cacheKey, // use a cache key as pseudo artifact ID
+		BlobIDs: []string{cacheKey},
+	}, nil
+}
+
+// renderConfigFiles converts config files so that checks evaluate them as they would be deployed.
+// Kustomizations are built, Helm charts are rendered with the overrides, parameters are set to CloudFormation templates,
+// and Terraform plans, ARM templates, Bicep files and Pulumi previews are converted.
+// CloudFormation templates synthesized by AWS CDK are evaluated as they are, and their findings are attributed to the constructs.
+// The returned function attributes misconfigurations in the converted files back to the original ones.
+func (a Artifact) renderConfigFiles(result *analyzer.AnalysisResult) func([]types.Misconfiguration) {
+	files, ok := result.Files[types.MisconfPostHandler]
+	if !ok {
+		return func([]types.Misconfiguration) {}
+	}
+
+	if a.isDir() {
+		files = kustomize.Build(a.rootPath, files)
+	}
+
+	var rendered map[string]struct{}
+	if a.helmRenderer != nil {
+		files, rendered = a.helmRenderer.Render(files)
+	}
+
+	files = cloudformation.Apply(files, a.option.CloudFormationParameters)
+	files, converted := tfplan.Convert(files)
+	files, templates := arm.Convert(a.dir(), files)
+	files, previews := pulumi.Convert(files)
+	stacks := cdk.Templates(files)
+
+	result.Files[types.MisconfPostHandler] = files
+	return func(misconfs []types.Misconfiguration) {
+		helm.Relabel(misconfs, rendered)
+		tfplan.Relabel(misconfs, converted)
+		arm.Relabel(misconfs, templates)
+		pulumi.Relabel(misconfs, previews)
+		cdk.Relabel(misconfs, stacks)
+	}
+}
+
+func (a Artifact) isDir() bool {
+	fi, err := os.Stat(a.rootPath)
+	return err == nil && fi.IsDir()
+}
+
+// dir returns the directory which file paths are relative to
+func (a Artifact) dir() string {
+	if a.isDir() {
+		return a.rootPath
+	}
+	return filepath.Dir(a.rootPath)
+}
+
+// fileFilter returns a function reporting whether the file should be analyzed.
+// The file path passed to the function is relative to the root path.
+func (a Artifact) fileFilter() (func(string) bool, error) {
+	if len(a.option.Files) > 0 {
+		files := map[string]struct{}{}
+		for _, f := range a.option.Files {
+			files[f] = struct{}{}
+		}
+		addDependencyFiles(files)
+
+		return func(filePath string) bool {
+			_, ok := files[filepath.ToSlash(filePath)]
+			return ok
+		}, nil
+	}
+
+	if a.option.DiffBase == "" {
+		return func(string) bool { return true }, nil
+	}
+
+	changed, err := changedFiles(a.rootPath, a.option.DiffBase)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to get changed files since %s: %w", a.option.DiffBase, err)
+	}
+	log.Logger.Debugf("%d files changed since %s", len(changed), a.option.DiffBase)
+
+	return func(filePath string) bool {
+		_, ok := changed[filepath.ToSlash(filePath)]
+		return ok
+	}, nil
+}
+
+func (a Artifact) Clean(reference types.ArtifactReference) error {
+	return a.cache.DeleteBlobs(reference.BlobIDs)
+}
+
+// skipError records the error of the file in the result and returns nil with --continue-on-error,
+// otherwise it returns the error as is.
+func (a Artifact) skipError(result *analyzer.AnalysisResult, filePath string, err error) error {
+	if !a.option.ContinueOnError {
+		return err
+	}
+	log.Logger.Warnf("Skipping %s due to the error: %s", filePath, err)
+	result.Merge(&analyzer.AnalysisResult{
+		CustomResources: []types.CustomResource{tanalyzer.ErrorResource(filePath, err)},
+	})
+	return nil
+}
+
+func (a Artifact) calcCacheKey(blobInfo types.BlobInfo) (string, error) {
+	// calculate hash of JSON and use it as pseudo artifactID and blobID.
+	// The secret scanner options are mixed into it, as fanal doesn't take them into account.
+	h := sha256.New()
+	if err := json.NewEncoder(h).Encode(blobInfo); err != nil {
+		return "", xerrors.Errorf("json error: %w", err)
+	}
+	if secretOption := secret.CacheKeyOption(a.option.SecretScannerOption); secretOption != nil {
+		if err := json.NewEncoder(h).Encode(secretOption); err != nil {
+			return "", xerrors.Errorf("json error: %w", err)
+		}
+	}
+
+	d := digest.NewDigest(digest.SHA256, h)
+	cacheKey, err := cache.CalcKey(d.String(), a.analyzer.AnalyzerVersions(), a.handlerManager.Versions(), a.artifactOption)
+	if err != nil {
+		return "", xerrors.Errorf("cache key: %w", err)
+	}
+
+	return cacheKey, nil
+}
@@ -0,0 +1,336 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/artifact"
+	"github.com/aquasecurity/fanal/cache"
+	"github.com/aquasecurity/fanal/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+
+	_ "github.com/aquasecurity/fanal/analyzer/language/python/pip"
+	_ "github.com/aquasecurity/fanal/analyzer/os/release"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/language/java/jar"
+)
+
+func TestArtifact_Inspect(t *testing.T) {
+	dir := t.TempDir()
+	r, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	w, err := r.Worktree()
+	require.NoError(t, err)
+
+	writeFile(t, dir, "foo/requirements.txt", "flask==2.0.0\n")
+	writeFile(t, dir, "bar/requirements.txt", "django==3.2.0\n")
+	_, err = w.Add(".")
+	require.NoError(t, err)
+	_, err = w.Commit("base", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+
+	writeFile(t, dir, "bar/requirements.txt", "django==4.0.0\n")
+
+	tests := []struct {
+		name      string
+		option    Option
+		wantFiles []string
+	}{
+		{
+			name:      "all files",
+			wantFiles: []string{"bar/requirements.txt", "foo/requirements.txt"},
+		},
+		{
+			name:      "diff base",
+			option:    Option{DiffBase: "HEAD"},
+			wantFiles: []string{"bar/requirements.txt"},
+		},
+		{
+			name:      "files",
+			option:    Option{Files: []string{"foo/requirements.txt"}},
+			wantFiles: []string{"foo/requirements.txt"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := cache.NewFSCache(t.TempDir())
+			require.NoError(t, err)
+			defer c.Close()
+
+			a, err := NewArtifact(dir, c, artifact.Option{}, tt.option)
+			require.NoError(t, err)
+
+			ref, err := a.Inspect(context.Background())
+			require.NoError(t, err)
+
+			blob, err := c.GetBlob(ref.BlobIDs[0])
+			require.NoError(t, err)
+
+			var gotFiles []string
+			for _, app := range blob.Applications {
+				gotFiles = append(gotFiles, app.FilePath)
+			}
+			assert.Equal(t, tt.wantFiles, gotFiles)
+		})
+	}
+}
+
+func TestArtifact_Inspect_Rootfs(t *testing.T) {
+	// The absolute link must not be resolved on the host
+	root := t.TempDir()
+	writeFile(t, root, "usr/share/os-release", "ID=alpine\nVERSION_ID=3.16.0\n")
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "etc"), 0755))
+	require.NoError(t, os.Symlink("/usr/share/os-release", filepath.Join(root, "etc", "os-release")))
+
+	tests := []struct {
+		name   string
+		option Option
+		want   *types.OS
+	}{
+		{
+			name: "symlinks are not followed",
+		},
+		{
+			name:   "os-release via symlink",
+			option: Option{Rootfs: true},
+			want: &types.OS{
+				Family: "alpine",
+				Name:   "3.16.0",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := cache.NewFSCache(t.TempDir())
+			require.NoError(t, err)
+			defer c.Close()
+
+			a, err := NewArtifact(root, c, artifact.Option{}, tt.option)
+			require.NoError(t, err)
+
+			ref, err := a.Inspect(context.Background())
+			require.NoError(t, err)
+
+			blob, err := c.GetBlob(ref.BlobIDs[0])
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, blob.OS)
+		})
+	}
+}
+
+func TestArtifact_Inspect_Archives(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "requirements.txt", "requests==2.28.1\n")
+	writeFile(t, dir, "mirror.tar", string(newMirror(t)))
+
+	tests := []struct {
+		name      string
+		option    Option
+		wantFiles []string
+	}{
+		{
+			name:      "archives are not walked",
+			wantFiles: []string{"requirements.txt"},
+		},
+		{
+			name: "archives are walked",
+			option: Option{
+				ScanArchives: true,
+				ArchiveDepth: 3,
+			},
+			wantFiles: []string{
+				"mirror.tar!/image.tar!/blobs/sha256/0123!/app/requirements.txt",
+				"mirror.tar!/requirements.txt",
+				"requirements.txt",
+			},
+		},
+		{
+			name: "depth limit",
+			option: Option{
+				ScanArchives: true,
+				ArchiveDepth: 1,
+			},
+			wantFiles: []string{
+				"mirror.tar!/requirements.txt",
+				"requirements.txt",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := cache.NewFSCache(t.TempDir())
+			require.NoError(t, err)
+			defer c.Close()
+
+			a, err := NewArtifact(dir, c, artifact.Option{}, tt.option)
+			require.NoError(t, err)
+
+			ref, err := a.Inspect(context.Background())
+			require.NoError(t, err)
+
+			blob, err := c.GetBlob(ref.BlobIDs[0])
+			require.NoError(t, err)
+
+			var gotFiles []string
+			for _, app := range blob.Applications {
+				gotFiles = append(gotFiles, app.FilePath)
+			}
+			assert.Equal(t, tt.wantFiles, gotFiles)
+		})
+	}
+}
+
+func TestArtifact_Inspect_ContinueOnError(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "requirements.txt", "requests==2.28.1\n")
+	writeFile(t, dir, "broken.jar", "not a zip file")
+	// The entry is truncated, so it fails to be opened
+	archive := newTar(t, map[string][]byte{"requirements.txt": []byte(strings.Repeat("flask==2.0.0\n", 100))})
+	writeFile(t, dir, "truncated.tar", string(archive[:1024]))
+
+	tests := []struct {
+		name          string
+		option        Option
+		wantErrorFile []string
+		wantErr       string
+	}{
+		{
+			// Analyzer errors don't abort the scan as fanal doesn't
+			name: "analyzer error is logged",
+		},
+		{
+			name:          "analyzer error is recorded",
+			option:        Option{ContinueOnError: true},
+			wantErrorFile: []string{"broken.jar"},
+		},
+		{
+			name:    "open error aborts the scan",
+			option:  Option{ScanArchives: true, ArchiveDepth: 1},
+			wantErr: "archive walk (truncated.tar)",
+		},
+		{
+			name:          "open error is recorded",
+			option:        Option{ScanArchives: true, ArchiveDepth: 1, ContinueOnError: true},
+			wantErrorFile: []string{"broken.jar", "truncated.tar!/requirements.txt"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := cache.NewFSCache(t.TempDir())
+			require.NoError(t, err)
+			defer c.Close()
+
+			a, err := NewArtifact(dir, c, artifact.Option{}, tt.option)
+			require.NoError(t, err)
+
+			ref, err := a.Inspect(context.Background())
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			blob, err := c.GetBlob(ref.BlobIDs[0])
+			require.NoError(t, err)
+
+			// The other files are analyzed
+			require.Len(t, blob.Applications, 1)
+			assert.Equal(t, "requirements.txt", blob.Applications[0].FilePath)
+
+			var gotErrorFiles []string
+			for _, r := range blob.CustomResources {
+				if r.Type == tanalyzer.ErrorType {
+					gotErrorFiles = append(gotErrorFiles, r.FilePath)
+				}
+			}
+			sort.Strings(gotErrorFiles)
+			assert.Equal(t, tt.wantErrorFile, gotErrorFiles)
+		})
+	}
+}
+
+// concurrencyAnalyzer records the maximum number of files analyzed at a time.
+// It belongs to its own group so that it doesn't run in the other tests.
+type concurrencyAnalyzer struct {
+	group analyzer.Group
+
+	mu      sync.Mutex
+	current int
+	max     int
+}
+
+func (a *concurrencyAnalyzer) Type() analyzer.Type               { return analyzer.Type(a.group) }
+func (a *concurrencyAnalyzer) Version() int                      { return 1 }
+func (a *concurrencyAnalyzer) Group() analyzer.Group             { return a.group }
+func (a *concurrencyAnalyzer) Required(string, os.FileInfo) bool { return true }
+
+func (a *concurrencyAnalyzer) Analyze(context.Context, analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	a.mu.Lock()
+	a.current++
+	if a.current > a.max {
+		a.max = a.current
+	}
+	a.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	a.mu.Lock()
+	a.current--
+	a.mu.Unlock()
+	return nil, nil
+}
+
+func TestArtifact_Inspect_Parallel(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 8; i++ {
+		writeFile(t, dir, fmt.Sprintf("file%d.txt", i), fmt.Sprintf("file %d", i))
+	}
+
+	tests := []struct {
+		name     string
+		parallel int
+		want     int
+	}{
+		{
+			name:     "one by one",
+			parallel: 1,
+			want:     1,
+		},
+		{
+			name:     "three files",
+			parallel: 3,
+			want:     3,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &concurrencyAnalyzer{group: analyzer.Group(fmt.Sprintf("fs-parallel-%d", i))}
+			tanalyzer.RegisterAnalyzer(a)
+
+			c, err := cache.NewFSCache(t.TempDir())
+			require.NoError(t, err)
+			defer c.Close()
+
+			art, err := NewArtifact(dir, c, artifact.Option{AnalyzerGroup: a.group}, Option{Parallel: tt.parallel})
+			require.NoError(t, err)
+
+			_, err = art.Inspect(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, a.max)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package local
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"golang.org/x/xerrors"
+)
+
+// gitignoreMatcher matches paths against .gitignore files in the file tree.
+// Directories are walked concurrently, so .gitignore files are loaded as directories are visited.
+// A directory is always visited before its children, so the patterns of all the parents are available.
+type gitignoreMatcher struct {
+	root string
+
+	mu sync.RWMutex
+	// slash-separated directory path relative to the root => patterns in the directory
+	patterns map[string][]gitignore.Pattern
+}
+
+// newGitignoreMatcher loads .gitignore and .git/info/exclude in the root directory
+func newGitignoreMatcher(root string) (*gitignoreMatcher, error) {
+	m := &gitignoreMatcher{
+		root:     root,
+		patterns: map[string][]gitignore.Pattern{},
+	}
+
+	exclude, err := readGitignore(filepath.Join(root, ".git", "info", "exclude"), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to read .git/info/exclude: %w", err)
+	}
+
+	if err = m.load("."); err != nil {
+		return nil, err
+	}
+
+	// .gitignore takes precedence over .git/info/exclude
+	m.patterns["."] = append(exclude, m.patterns["."]...)
+	return m, nil
+}
+
+// load loads .gitignore in the directory
+func (m *gitignoreMatcher) load(dir string) error {
+	var domain []string
+	if dir != "." {
+		domain = strings.Split(dir, "/")
+	}
+
+	ps, err := readGitignore(filepath.Join(m.root, filepath.FromSlash(dir), ".gitignore"), domain)
+	if err != nil {
+		return xerrors.Errorf("unable to read .gitignore in %s: %w", dir, err)
+	} else if len(ps) == 0 {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.patterns[dir] = ps
+	return nil
+}
+
+// match reports whether the slash-separated path relative to the root is ignored
+func (m *gitignoreMatcher) match(relPath string, isDir bool) bool {
+	var dirs []string
+	for dir := path.Dir(relPath); dir != "."; dir = path.Dir(dir) {
+		dirs = append(dirs, dir)
+	}
+
+	m.mu.RLock()
+	ps := append([]gitignore.Pattern{}, m.patterns["."]...)
+	// Deeper .gitignore files take precedence
+	for i := len(dirs) - 1; i >= 0; i-- {
+		ps = append(ps, m.patterns[dirs[i]]...)
+	}
+	m.mu.RUnlock()
+
+	return gitignore.NewMatcher(ps).Match(strings.Split(relPath, "/"), isDir)
+}
+
+func readGitignore(filePath string, domain []string) ([]gitignore.Pattern, error) {
+	f, err := os.Open(filePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, xerrors.Errorf("file open error: %w", err)
+	}
+	defer f.Close()
+
+	var ps []gitignore.Pattern
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+		ps = append(ps, gitignore.ParsePattern(line, domain))
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, xerrors.Errorf("file read error: %w", err)
+	}
+	return ps, nil
+}
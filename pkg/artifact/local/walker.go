@@ -0,0 +1,428 @@
+package local
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bmatcuk/doublestar"
+	swalker "github.com/saracen/walker"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/artifact"
+	"github.com/aquasecurity/fanal/walker"
+	dio "github.com/aquasecurity/go-dep-parser/pkg/io"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+// maxSymlinks is the maximum number of symbolic links followed while resolving a path, as Linux does.
+const maxSymlinks = 40
+
+// Policies of following symbolic links
+const (
+	// SymlinksNone doesn't analyze symbolic links
+	SymlinksNone = "none"
+	// SymlinksRoot resolves symbolic links relative to the root as chroot does
+	SymlinksRoot = "root"
+	// SymlinksAll resolves symbolic links as the OS does, even if they point outside the root
+	SymlinksAll = "all"
+)
+
+// Reasons why paths are skipped
+const (
+	SkipReasonSkipFiles   = "--skip-files"
+	SkipReasonSkipDirs    = "--skip-dirs"
+	SkipReasonExcludePath = "--exclude-path"
+	SkipReasonIncludePath = "not matched by --include-path"
+	SkipReasonGitignore   = ".gitignore"
+	SkipReasonAppDir      = "application directory"
+	SkipReasonSystemDir   = "system directory"
+)
+
+// SkippedPath is a path skipped while walking the file tree
+type SkippedPath struct {
+	Path   string // slash-separated and relative to the root
+	Dir    bool
+	Reason string
+}
+
+// SkippedPaths walks the file tree as the artifact does without analyzing files,
+// and returns the paths skipped by the options sorted by the path.
+// The children of skipped directories are not walked, so they are not returned.
+func SkippedPaths(rootPath string, artifactOpt artifact.Option, opt Option) ([]SkippedPath, error) {
+	var mu sync.Mutex
+	var skipped []SkippedPath
+
+	w := newFSWalker(rootPath, buildAbsPaths(rootPath, artifactOpt.SkipFiles), buildAbsPaths(rootPath, artifactOpt.SkipDirs), opt)
+	w.onSkip = func(relPath string, dir bool, reason string) {
+		mu.Lock()
+		defer mu.Unlock()
+		skipped = append(skipped, SkippedPath{
+			Path:   relPath,
+			Dir:    dir,
+			Reason: reason,
+		})
+	}
+
+	err := w.Walk(filepath.Clean(rootPath), func(string, os.FileInfo, analyzer.Opener) error {
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("walk error: %w", err)
+	}
+
+	sort.Slice(skipped, func(i, j int) bool {
+		return skipped[i].Path < skipped[j].Path
+	})
+	return skipped, nil
+}
+
+// Skipper returns a function reporting whether the slash-separated path relative to the root is skipped by the options
+// in the same way as the artifact walks the file tree. .gitignore is not taken into account.
+func Skipper(rootPath string, artifactOpt artifact.Option, opt Option) func(relPath string, dir bool) bool {
+	root := filepath.Clean(rootPath)
+	w := newFSWalker(root, buildAbsPaths(root, artifactOpt.SkipFiles), buildAbsPaths(root, artifactOpt.SkipDirs), opt)
+	return func(relPath string, dir bool) bool {
+		pathname := filepath.Join(root, filepath.FromSlash(relPath))
+
+		// The patterns are validated on start-up
+		if excluded, _ := matchAny(w.excludePaths, relPath); excluded {
+			return true
+		}
+
+		if dir {
+			return w.skipDirReason(pathname) != ""
+		} else if w.shouldSkipFile(pathname) {
+			return true
+		}
+
+		if len(w.includePaths) > 0 {
+			included, _ := matchAny(w.includePaths, relPath)
+			return !included
+		}
+		return false
+	}
+}
+
+// fsWalker walks the file tree in the same way as fanal's walker.FS.
+// In addition, it can resolve symbolic links relative to the root as chroot does,
+// so that links such as "/etc/os-release -> ../usr/lib/os-release" are analyzed in rootfs.
+type fsWalker struct {
+	skipFiles  []string
+	skipDirs   []string
+	systemDirs []string
+
+	// doublestar patterns matched against slash-separated paths relative to the root
+	includePaths []string
+	excludePaths []string
+
+	// symlinks is the policy of following symbolic links
+	symlinks string
+
+	// useGitignore skips files ignored by .gitignore
+	useGitignore bool
+
+	// onSkip is called with the reason for each skipped path if set.
+	// It must be safe for concurrent use.
+	onSkip func(relPath string, dir bool, reason string)
+}
+
+func newFSWalker(root string, skipFiles, skipDirs []string, opt Option) fsWalker {
+	var systemDirs []string
+	if !opt.IncludeSystemDirs {
+		systemDirs = walker.SystemDirs
+		// System directories are under the root in rootfs, e.g. /mnt/rootfs/proc
+		if opt.Rootfs {
+			systemDirs = buildAbsPaths(filepath.Clean(root), systemDirs)
+		}
+	}
+
+	symlinks := opt.FollowSymlinks
+	if symlinks == "" {
+		symlinks = SymlinksNone
+		if opt.Rootfs {
+			symlinks = SymlinksRoot
+		}
+	}
+
+	return fsWalker{
+		skipFiles:    cleanPaths(skipFiles),
+		skipDirs:     cleanPaths(skipDirs),
+		systemDirs:   cleanPaths(systemDirs),
+		includePaths: cleanPatterns(opt.IncludePaths),
+		excludePaths: cleanPatterns(opt.ExcludePaths),
+		symlinks:     symlinks,
+		useGitignore: opt.UseGitignore,
+	}
+}
+
+func cleanPaths(paths []string) []string {
+	var cleaned []string
+	for _, p := range paths {
+		p = filepath.Clean(filepath.ToSlash(p))
+		cleaned = append(cleaned, strings.TrimLeft(p, "/"))
+	}
+	return cleaned
+}
+
+func cleanPatterns(patterns []string) []string {
+	var cleaned []string
+	for _, pattern := range patterns {
+		pattern = strings.TrimLeft(filepath.ToSlash(pattern), "/")
+		cleaned = append(cleaned, path.Clean(pattern))
+	}
+	return cleaned
+}
+
+// Walk walks the file tree rooted at root, calling WalkFunc for each file in the tree.
+// Directories to be ignored will be skipped.
+func (w fsWalker) Walk(root string, fn walker.WalkFunc) error {
+	var ignore *gitignoreMatcher
+	if fi, err := os.Stat(root); err == nil && fi.IsDir() && w.useGitignore {
+		if ignore, err = newGitignoreMatcher(root); err != nil {
+			return xerrors.Errorf("gitignore error: %w", err)
+		}
+	}
+
+	// walk function called for every path found
+	walkFn := func(pathname string, fi os.FileInfo) error {
+		pathname = filepath.Clean(pathname)
+		relPath, err := filepath.Rel(root, pathname)
+		if err != nil {
+			return xerrors.Errorf("filepath rel (%s): %w", pathname, err)
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		excluded, err := matchAny(w.excludePaths, relPath)
+		if err != nil {
+			return xerrors.Errorf("exclude path error: %w", err)
+		}
+		excludeReason := SkipReasonExcludePath
+
+		if !excluded && ignore != nil && relPath != "." && ignore.match(relPath, fi.IsDir()) {
+			excluded, excludeReason = true, SkipReasonGitignore
+		}
+
+		if fi.IsDir() {
+			if reason := w.skipDirReason(pathname); reason != "" {
+				w.skipped(relPath, true, reason)
+				return filepath.SkipDir
+			} else if excluded {
+				w.skipped(relPath, true, excludeReason)
+				return filepath.SkipDir
+			}
+			if ignore != nil && relPath != "." {
+				// Load .gitignore before the children are visited
+				if err = ignore.load(relPath); err != nil {
+					return xerrors.Errorf("gitignore error: %w", err)
+				}
+			}
+			return nil
+		} else if w.shouldSkipFile(pathname) {
+			w.skipped(relPath, false, SkipReasonSkipFiles)
+			return nil
+		} else if excluded {
+			w.skipped(relPath, false, excludeReason)
+			return nil
+		}
+
+		if len(w.includePaths) > 0 {
+			included, err := matchAny(w.includePaths, relPath)
+			if err != nil {
+				return xerrors.Errorf("include path error: %w", err)
+			} else if !included {
+				w.skipped(relPath, false, SkipReasonIncludePath)
+				return nil
+			}
+		}
+
+		realPath := pathname
+		if fi.Mode()&os.ModeSymlink != 0 {
+			switch w.symlinks {
+			case SymlinksRoot:
+				realPath, fi, err = resolveSymlink(root, pathname)
+			case SymlinksAll:
+				realPath, fi, err = resolveHostSymlink(pathname)
+			}
+			if err != nil {
+				// Dangling links are common in rootfs, e.g. links to /proc
+				log.Logger.Debugf("Unable to resolve the symlink %s: %s", pathname, err)
+				return nil
+			}
+		}
+
+		// Devices, sockets, pipes, directories linked and links not followed are not analyzed
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+
+		if err := fn(pathname, fi, fileOpener(realPath)); err != nil {
+			return xerrors.Errorf("failed to analyze file: %w", err)
+		}
+		return nil
+	}
+
+	// error function called for every error encountered
+	errorCallbackOption := swalker.WithErrorCallback(func(pathname string, err error) error {
+		// ignore permission errors
+		if os.IsPermission(err) {
+			return nil
+		}
+		// halt traversal on any other error
+		return xerrors.Errorf("unknown error with %s: %w", pathname, err)
+	})
+
+	// Multiple goroutines stat the filesystem concurrently. The provided
+	// walkFn must be safe for concurrent use.
+	if err := swalker.Walk(root, walkFn, errorCallbackOption); err != nil {
+		return xerrors.Errorf("walk error: %w", err)
+	}
+	return nil
+}
+
+func (w fsWalker) shouldSkipFile(filePath string) bool {
+	filePath = filepath.ToSlash(filePath)
+	filePath = strings.TrimLeft(filePath, "/")
+
+	return slices.Contains(w.skipFiles, filePath)
+}
+
+// skipDirReason returns the reason why the directory is skipped, or an empty string if not skipped
+func (w fsWalker) skipDirReason(dir string) string {
+	dir = filepath.ToSlash(dir)
+	dir = strings.TrimLeft(dir, "/")
+
+	// Skip application dirs (relative path)
+	if slices.Contains(walker.AppDirs, filepath.Base(dir)) {
+		return SkipReasonAppDir
+	}
+
+	// Skip system dirs and specified dirs (absolute path)
+	if slices.Contains(w.systemDirs, dir) {
+		return SkipReasonSystemDir
+	} else if slices.Contains(w.skipDirs, dir) {
+		return SkipReasonSkipDirs
+	}
+	return ""
+}
+
+func (w fsWalker) skipped(relPath string, dir bool, reason string) {
+	if w.onSkip != nil {
+		w.onSkip(relPath, dir, reason)
+	}
+}
+
+// matchAny reports whether the slash-separated path matches any of the doublestar patterns
+func matchAny(patterns []string, pathname string) (bool, error) {
+	for _, pattern := range patterns {
+		matched, err := doublestar.Match(pattern, pathname)
+		if err != nil {
+			return false, xerrors.Errorf("bad pattern %q: %w", pattern, err)
+		} else if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// resolveHostSymlink resolves the symbolic link at pathname as the OS does, so the link may point outside the root.
+// It returns the resolved path and its file info.
+func resolveHostSymlink(pathname string) (string, os.FileInfo, error) {
+	realPath, err := filepath.EvalSymlinks(pathname)
+	if err != nil {
+		return "", nil, xerrors.Errorf("eval symlinks error: %w", err)
+	}
+	fi, err := os.Stat(realPath)
+	if err != nil {
+		return "", nil, xerrors.Errorf("stat error: %w", err)
+	}
+	return realPath, fi, nil
+}
+
+// resolveSymlink resolves the symbolic link at pathname, treating root as "/".
+// Absolute link targets and ".." never escape root.
+// It returns the resolved path on the host and its file info.
+func resolveSymlink(root, pathname string) (string, os.FileInfo, error) {
+	rel, err := filepath.Rel(root, pathname)
+	if err != nil {
+		return "", nil, xerrors.Errorf("filepath rel error: %w", err)
+	}
+
+	resolved, err := evalSymlinksInRoot(root, filepath.ToSlash(rel))
+	if err != nil {
+		return "", nil, err
+	}
+
+	realPath := filepath.Join(root, filepath.FromSlash(resolved))
+	fi, err := os.Lstat(realPath)
+	if err != nil {
+		return "", nil, xerrors.Errorf("lstat error: %w", err)
+	}
+	return realPath, fi, nil
+}
+
+// evalSymlinksInRoot is like filepath.EvalSymlinks, but resolves the slash-separated path within root.
+// The returned path is slash-separated and absolute as seen from root.
+func evalSymlinksInRoot(root, unsafePath string) (string, error) {
+	resolved := "/"
+	remaining := unsafePath
+	var links int
+
+	for remaining != "" {
+		var part string
+		part, remaining, _ = strings.Cut(remaining, "/")
+		if part == "" || part == "." {
+			continue
+		}
+
+		// path.Join never goes above "/"
+		next := path.Join(resolved, part)
+		if part == ".." {
+			resolved = next
+			continue
+		}
+
+		fi, err := os.Lstat(filepath.Join(root, filepath.FromSlash(next)))
+		if err != nil {
+			return "", xerrors.Errorf("lstat error: %w", err)
+		}
+		if fi.Mode()&os.ModeSymlink == 0 {
+			resolved = next
+			continue
+		}
+
+		links++
+		if links > maxSymlinks {
+			return "", xerrors.Errorf("too many links in %s", unsafePath)
+		}
+
+		target, err := os.Readlink(filepath.Join(root, filepath.FromSlash(next)))
+		if err != nil {
+			return "", xerrors.Errorf("readlink error: %w", err)
+		}
+		target = filepath.ToSlash(target)
+
+		// Absolute targets are relative to root
+		if path.IsAbs(target) {
+			resolved = "/"
+		}
+		// The target must not be cleaned lexically before resolving symlinks in it
+		if remaining != "" {
+			target += "/" + remaining
+		}
+		remaining = target
+	}
+	return resolved, nil
+}
+
+// fileOpener returns a function opening a file.
+func fileOpener(pathname string) func() (dio.ReadSeekCloserAt, error) {
+	return func() (dio.ReadSeekCloserAt, error) {
+		return os.Open(pathname)
+	}
+}
@@ -0,0 +1,400 @@
+package local
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/artifact"
+)
+
+// setupRootfs creates a rootfs with symlinks as below.
+//
+//	etc/os-release -> ../usr/lib/os-release
+//	etc/alternatives/python -> /usr/bin/python3.10
+//	usr/bin/python -> /etc/alternatives/python
+//	usr/bin/escape -> ../../../../host
+//	usr/bin/outside -> ../../../host (the host file outside the root)
+//	usr/bin/dangling -> /proc/self/exe
+func setupRootfs(t *testing.T) string {
+	dir := t.TempDir()
+	writeFile(t, dir, "usr/lib/os-release", "ID=alpine\nVERSION_ID=3.16.0\n")
+	writeFile(t, dir, "usr/bin/python3.10", "python")
+	writeFile(t, filepath.Dir(dir), "host", "host")
+
+	symlink := func(oldname, newname string) {
+		newname = filepath.Join(dir, newname)
+		require.NoError(t, os.MkdirAll(filepath.Dir(newname), 0755))
+		require.NoError(t, os.Symlink(oldname, newname))
+	}
+	symlink("../usr/lib/os-release", "etc/os-release")
+	symlink("/usr/bin/python3.10", "etc/alternatives/python")
+	symlink("/etc/alternatives/python", "usr/bin/python")
+	symlink("../../../../host", "usr/bin/escape")
+	symlink("../../../host", "usr/bin/outside")
+	symlink("/proc/self/exe", "usr/bin/dangling")
+
+	return dir
+}
+
+func TestFSWalker_Walk(t *testing.T) {
+	root := setupRootfs(t)
+
+	tests := []struct {
+		name   string
+		option Option
+		want   map[string]string
+	}{
+		{
+			name: "symlinks are skipped",
+			want: map[string]string{
+				"usr/lib/os-release": "ID=alpine\nVERSION_ID=3.16.0\n",
+				"usr/bin/python3.10": "python",
+			},
+		},
+		{
+			name:   "symlinks are resolved in the root",
+			option: Option{Rootfs: true},
+			want: map[string]string{
+				"usr/lib/os-release":      "ID=alpine\nVERSION_ID=3.16.0\n",
+				"usr/bin/python3.10":      "python",
+				"etc/os-release":          "ID=alpine\nVERSION_ID=3.16.0\n",
+				"etc/alternatives/python": "python",
+				"usr/bin/python":          "python",
+			},
+		},
+		{
+			name: "symlinks are not followed in rootfs",
+			option: Option{
+				Rootfs:         true,
+				FollowSymlinks: SymlinksNone,
+			},
+			want: map[string]string{
+				"usr/lib/os-release": "ID=alpine\nVERSION_ID=3.16.0\n",
+				"usr/bin/python3.10": "python",
+			},
+		},
+		{
+			name: "symlinks are resolved in the host",
+			option: Option{
+				FollowSymlinks: SymlinksAll,
+				IncludePaths:   []string{"etc/os-release", "usr/bin/escape", "usr/bin/outside"},
+			},
+			want: map[string]string{
+				"etc/os-release":  "ID=alpine\nVERSION_ID=3.16.0\n",
+				"usr/bin/outside": "host",
+			},
+		},
+		{
+			name: "include paths",
+			option: Option{
+				Rootfs:       true,
+				IncludePaths: []string{"**/os-release"},
+			},
+			want: map[string]string{
+				"usr/lib/os-release": "ID=alpine\nVERSION_ID=3.16.0\n",
+				"etc/os-release":     "ID=alpine\nVERSION_ID=3.16.0\n",
+			},
+		},
+		{
+			name: "exclude paths",
+			option: Option{
+				Rootfs:       true,
+				ExcludePaths: []string{"/etc", "usr/bin/python*"},
+			},
+			want: map[string]string{
+				"usr/lib/os-release": "ID=alpine\nVERSION_ID=3.16.0\n",
+			},
+		},
+		{
+			name: "exclude paths take precedence",
+			option: Option{
+				IncludePaths: []string{"usr/**"},
+				ExcludePaths: []string{"usr/lib/**"},
+			},
+			want: map[string]string{
+				"usr/bin/python3.10": "python",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			got := map[string]string{}
+
+			w := newFSWalker(root, nil, nil, tt.option)
+			err := w.Walk(root, func(filePath string, info os.FileInfo, opener analyzer.Opener) error {
+				rel, err := filepath.Rel(root, filePath)
+				require.NoError(t, err)
+
+				f, err := opener()
+				require.NoError(t, err)
+				defer f.Close()
+
+				b, err := io.ReadAll(f)
+				require.NoError(t, err)
+				assert.Equal(t, int64(len(b)), info.Size())
+
+				mu.Lock()
+				defer mu.Unlock()
+				got[filepath.ToSlash(rel)] = string(b)
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFSWalker_Walk_SystemDirs(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "etc/alpine-release", "3.16.0")
+	writeFile(t, root, "proc/version", "Linux")
+	writeFile(t, root, "sys/kernel/notes", "notes")
+
+	tests := []struct {
+		name        string
+		option      Option
+		want        []string
+		wantSkipped []string
+	}{
+		{
+			name:        "system dirs are skipped in rootfs",
+			option:      Option{Rootfs: true},
+			want:        []string{"etc/alpine-release"},
+			wantSkipped: []string{"proc", "sys"},
+		},
+		{
+			name: "system dirs are included",
+			option: Option{
+				Rootfs:            true,
+				IncludeSystemDirs: true,
+			},
+			want: []string{"etc/alpine-release", "proc/version", "sys/kernel/notes"},
+		},
+		{
+			name: "system dirs of the host only are skipped in filesystem",
+			want: []string{"etc/alpine-release", "proc/version", "sys/kernel/notes"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var got, skipped []string
+
+			w := newFSWalker(root, nil, nil, tt.option)
+			w.onSkip = func(relPath string, _ bool, reason string) {
+				mu.Lock()
+				defer mu.Unlock()
+				assert.Equal(t, SkipReasonSystemDir, reason)
+				skipped = append(skipped, relPath)
+			}
+			err := w.Walk(root, func(filePath string, _ os.FileInfo, _ analyzer.Opener) error {
+				rel, err := filepath.Rel(root, filePath)
+				require.NoError(t, err)
+
+				mu.Lock()
+				defer mu.Unlock()
+				got = append(got, filepath.ToSlash(rel))
+				return nil
+			})
+			require.NoError(t, err)
+
+			sort.Strings(got)
+			sort.Strings(skipped)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantSkipped, skipped)
+		})
+	}
+}
+
+func Test_evalSymlinksInRoot(t *testing.T) {
+	root := setupRootfs(t)
+
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "regular file",
+			path: "usr/lib/os-release",
+			want: "/usr/lib/os-release",
+		},
+		{
+			name: "relative link",
+			path: "etc/os-release",
+			want: "/usr/lib/os-release",
+		},
+		{
+			name: "chained absolute links",
+			path: "usr/bin/python",
+			want: "/usr/bin/python3.10",
+		},
+		{
+			name: "dot dot",
+			path: "../../etc/os-release",
+			want: "/usr/lib/os-release",
+		},
+		{
+			name:    "escape from the root",
+			path:    "usr/bin/escape",
+			wantErr: "lstat error",
+		},
+		{
+			name:    "dangling link",
+			path:    "usr/bin/dangling",
+			wantErr: "lstat error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := evalSymlinksInRoot(root, tt.path)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFSWalker_Walk_Gitignore(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, ".gitignore", "node_modules/\n*.env\n!example.env\n")
+	writeFile(t, root, ".git/info/exclude", "local/\n")
+	writeFile(t, root, "package-lock.json", "{}")
+	writeFile(t, root, "node_modules/lodash/package.json", "{}")
+	writeFile(t, root, "prod.env", "SECRET=foo")
+	writeFile(t, root, "example.env", "SECRET=")
+	writeFile(t, root, "local/notes.txt", "notes")
+	writeFile(t, root, "app/.gitignore", "build\n")
+	writeFile(t, root, "app/go.mod", "module app")
+	writeFile(t, root, "app/build/app.jar", "jar")
+	writeFile(t, root, "build/app.jar", "jar")
+
+	tests := []struct {
+		name   string
+		option Option
+		want   []string
+	}{
+		{
+			name: "gitignore disabled",
+			want: []string{
+				".gitignore",
+				"app/.gitignore",
+				"app/build/app.jar",
+				"app/go.mod",
+				"build/app.jar",
+				"example.env",
+				"local/notes.txt",
+				"node_modules/lodash/package.json",
+				"package-lock.json",
+				"prod.env",
+			},
+		},
+		{
+			name:   "gitignore enabled",
+			option: Option{UseGitignore: true},
+			want: []string{
+				".gitignore",
+				"app/.gitignore",
+				"app/go.mod",
+				"build/app.jar",
+				"example.env",
+				"package-lock.json",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var got []string
+
+			w := newFSWalker(root, nil, nil, tt.option)
+			err := w.Walk(root, func(filePath string, _ os.FileInfo, _ analyzer.Opener) error {
+				rel, err := filepath.Rel(root, filePath)
+				require.NoError(t, err)
+
+				mu.Lock()
+				defer mu.Unlock()
+				got = append(got, filepath.ToSlash(rel))
+				return nil
+			})
+			require.NoError(t, err)
+
+			sort.Strings(got)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSkippedPaths(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, ".gitignore", "*.env\n")
+	writeFile(t, root, ".git/HEAD", "ref: refs/heads/main")
+	writeFile(t, root, "package-lock.json", "{}")
+	writeFile(t, root, "prod.env", "SECRET=foo")
+	writeFile(t, root, "testdata/fixture.json", "{}")
+	writeFile(t, root, "docs/index.md", "docs")
+	writeFile(t, root, "app/secret.pem", "pem")
+	writeFile(t, root, "app/go.mod", "module app")
+
+	got, err := SkippedPaths(root, artifact.Option{
+		SkipFiles: []string{"app/secret.pem"},
+		SkipDirs:  []string{"testdata"},
+	}, Option{
+		ExcludePaths: []string{"docs/**"},
+		UseGitignore: true,
+	})
+	require.NoError(t, err)
+
+	want := []SkippedPath{
+		{Path: ".git", Dir: true, Reason: SkipReasonAppDir},
+		{Path: "app/secret.pem", Reason: SkipReasonSkipFiles},
+		{Path: "docs/index.md", Reason: SkipReasonExcludePath},
+		{Path: "prod.env", Reason: SkipReasonGitignore},
+		{Path: "testdata", Dir: true, Reason: SkipReasonSkipDirs},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestSkipper(t *testing.T) {
+	root := t.TempDir()
+	skip := Skipper(root, artifact.Option{
+		SkipFiles: []string{"app/secret.pem"},
+		SkipDirs:  []string{"testdata"},
+	}, Option{
+		IncludePaths: []string{"**/*.json", "app/**"},
+		ExcludePaths: []string{"docs/**"},
+	})
+
+	tests := []struct {
+		relPath string
+		dir     bool
+		want    bool
+	}{
+		{relPath: "package-lock.json", want: false},
+		{relPath: "README.md", want: true},
+		{relPath: "app/go.mod", want: false},
+		{relPath: "app/secret.pem", want: true},
+		{relPath: "docs/index.json", want: true},
+		{relPath: "testdata", dir: true, want: true},
+		{relPath: "vendor", dir: true, want: true},
+		{relPath: "app", dir: true, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.relPath, func(t *testing.T) {
+			assert.Equal(t, tt.want, skip(tt.relPath, tt.dir))
+		})
+	}
+}
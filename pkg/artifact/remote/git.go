@@ -0,0 +1,293 @@
+package remote
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp/sideband"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/artifact"
+	"github.com/aquasecurity/fanal/cache"
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/artifact/local"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+// commitRef is the reference of the commit fetched by "--commit"
+const commitRef = "refs/heads/trivy-commit"
+
+// scpLikeURL matches SCP-like git URLs such as "git@github.com:aquasecurity/trivy.git"
+var scpLikeURL = regexp.MustCompile(`^[\w.-]+@[\w.-]+:[^/]`)
+
+// Option holds the options for cloning a remote repository
+type Option struct {
+	Branch string
+	Tag    string
+	Commit string
+
+	// Depth is the number of commits to be cloned, and 0 means the full history.
+	// go-git doesn't support partial clones, i.e. blobs are always fetched.
+	Depth int
+
+	// Username and Token authenticate HTTP(S) requests prior to GITHUB_TOKEN and GITLAB_TOKEN
+	Username string
+	Token    string
+}
+
+type Artifact struct {
+	url   string
+	local artifact.Artifact
+}
+
+func NewArtifact(rawurl string, c cache.ArtifactCache, artifactOpt artifact.Option, repoOpt Option,
+	localOpt local.Option) (artifact.Artifact, func(), error) {
+	cleanup := func() {}
+
+	if repoOpt.Branch != "" && repoOpt.Tag != "" {
+		return nil, cleanup, xerrors.New("branch and tag cannot be specified at the same time")
+	} else if repoOpt.Depth < 0 {
+		return nil, cleanup, xerrors.New("depth must not be negative")
+	}
+
+	u, err := newURL(rawurl)
+	if err != nil {
+		return nil, cleanup, err
+	}
+
+	if artifactOpt.Offline {
+		if endpoint, err := transport.NewEndpoint(u); err != nil || endpoint.Protocol != "file" {
+			return nil, cleanup, xerrors.Errorf("%s cannot be cloned with --offline-scan. "+
+				"Clone it beforehand and scan the directory with \"trivy fs\"", rawurl)
+		}
+	}
+
+	tmpDir, err := os.MkdirTemp("", "trivy-remote")
+	if err != nil {
+		return nil, cleanup, err
+	}
+
+	cleanup = func() {
+		_ = os.RemoveAll(tmpDir)
+	}
+
+	// The history is necessary to get the diff and scan past commits
+	fullClone := localOpt.DiffBase != "" || localOpt.SecretHistory
+
+	if err = cloneRepository(tmpDir, u, artifactOpt, repoOpt, fullClone); err != nil {
+		cleanup()
+		return nil, func() {}, err
+	}
+
+	art, err := local.NewArtifact(tmpDir, c, artifactOpt, localOpt)
+	if err != nil {
+		return nil, cleanup, xerrors.Errorf("fs artifact: %w", err)
+	}
+
+	return Artifact{
+		url:   rawurl,
+		local: art,
+	}, cleanup, nil
+}
+
+func cloneRepository(dir, u string, artifactOpt artifact.Option, repoOpt Option, fullClone bool) error {
+	auth, err := gitAuth(u, repoOpt)
+	if err != nil {
+		return xerrors.Errorf("git auth error: %w", err)
+	}
+
+	depth := repoOpt.Depth
+	if fullClone {
+		depth = 0
+	}
+
+	var progress sideband.Progress = os.Stdout
+	// suppress clone output if noProgress
+	if artifactOpt.NoProgress {
+		progress = nil
+	}
+
+	if repoOpt.Commit != "" {
+		return fetchCommit(dir, u, repoOpt.Commit, git.FetchOptions{
+			Auth:            auth,
+			Progress:        progress,
+			Depth:           depth,
+			InsecureSkipTLS: artifactOpt.InsecureSkipTLS,
+		})
+	}
+
+	cloneOptions := git.CloneOptions{
+		URL:             u,
+		Auth:            auth,
+		Progress:        progress,
+		Depth:           depth,
+		InsecureSkipTLS: artifactOpt.InsecureSkipTLS,
+	}
+
+	switch {
+	case repoOpt.Branch != "":
+		cloneOptions.ReferenceName = plumbing.NewBranchReferenceName(repoOpt.Branch)
+		cloneOptions.SingleBranch = true
+	case repoOpt.Tag != "":
+		cloneOptions.ReferenceName = plumbing.NewTagReferenceName(repoOpt.Tag)
+		cloneOptions.SingleBranch = true
+	}
+
+	if _, err = git.PlainClone(dir, false, &cloneOptions); err != nil {
+		return xerrors.Errorf("git error: %w", err)
+	}
+	return nil
+}
+
+// fetchCommit fetches only the commit with the given depth and checks it out.
+// If the server doesn't allow fetching commits which are not advertised, or the commit is not a full hash,
+// the full history is fetched to find the commit.
+func fetchCommit(dir, u, commit string, fetchOptions git.FetchOptions) error {
+	r, err := git.PlainInit(dir, false)
+	if err != nil {
+		return xerrors.Errorf("git init error: %w", err)
+	}
+	if _, err = r.CreateRemote(&config.RemoteConfig{
+		Name: git.DefaultRemoteName,
+		URLs: []string{u},
+	}); err != nil {
+		return xerrors.Errorf("git remote error: %w", err)
+	}
+
+	var fetched bool
+	if plumbing.IsHash(commit) {
+		log.Logger.Debugf("Fetching the commit %s", commit)
+		opts := fetchOptions
+		opts.RefSpecs = []config.RefSpec{config.RefSpec(commit + ":" + commitRef)}
+		if err = r.Fetch(&opts); err != nil {
+			log.Logger.Debugf("Unable to fetch the commit %s: %s", commit, err)
+		}
+		fetched = err == nil
+	}
+	if !fetched {
+		log.Logger.Debugf("Fetching the full history to find the commit %s", commit)
+		opts := fetchOptions
+		opts.Depth = 0
+		opts.Tags = git.AllTags
+		if err = r.Fetch(&opts); err != nil {
+			return xerrors.Errorf("git error: %w", err)
+		}
+	}
+
+	log.Logger.Debugf("Checking out the commit %s", commit)
+	hash, err := r.ResolveRevision(plumbing.Revision(commit))
+	if err != nil {
+		return xerrors.Errorf("unable to resolve the commit %s: %w", commit, err)
+	}
+
+	w, err := r.Worktree()
+	if err != nil {
+		return xerrors.Errorf("git worktree error: %w", err)
+	}
+
+	if err = w.Checkout(&git.CheckoutOptions{Hash: *hash}); err != nil {
+		return xerrors.Errorf("git checkout error: %w", err)
+	}
+	return nil
+}
+
+func (a Artifact) Inspect(ctx context.Context) (types.ArtifactReference, error) {
+	ref, err := a.local.Inspect(ctx)
+	if err != nil {
+		return types.ArtifactReference{}, xerrors.Errorf("remote repository error: %w", err)
+	}
+
+	ref.Name = a.url
+	ref.Type = types.ArtifactRemoteRepository
+
+	return ref, nil
+}
+
+func (Artifact) Clean(_ types.ArtifactReference) error {
+	return nil
+}
+
+func newURL(rawurl string) (string, error) {
+	// SCP-like URLs are handled by go-git as is
+	// e.g. git@github.com:aquasecurity/trivy.git
+	if scpLikeURL.MatchString(rawurl) {
+		return rawurl, nil
+	}
+
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return "", xerrors.Errorf("url parse error: %w", err)
+	}
+	// "https://" can be omitted
+	// e.g. github.com/aquasecurity/trivy
+	if u.Scheme == "" {
+		u.Scheme = "https"
+	}
+
+	return u.String(), nil
+}
+
+// gitAuth returns the authentication method for the given URL.
+// SSH URLs are authenticated via the SSH agent, and HTTP(S) URLs via the token of the options
+// or a GitHub/GitLab token taken from env vars in order to make authenticated requests to access private repos.
+func gitAuth(rawurl string, repoOpt Option) (transport.AuthMethod, error) {
+	endpoint, err := transport.NewEndpoint(rawurl)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid git endpoint: %w", err)
+	}
+
+	switch endpoint.Protocol {
+	case "ssh":
+		user := endpoint.User
+		if user == "" {
+			user = "git"
+		}
+		auth, err := ssh.NewSSHAgentAuth(user)
+		if err != nil {
+			return nil, xerrors.Errorf("ssh agent error: %w", err)
+		}
+		return auth, nil
+	case "http", "https":
+		return tokenAuth(repoOpt), nil
+	}
+
+	// e.g. file://
+	return nil, nil
+}
+
+func tokenAuth(repoOpt Option) transport.AuthMethod {
+	// The username can be anything for HTTPS Git operations, except for some services such as Bitbucket
+	gitUsername := "trivy-aquasecurity-scan"
+	if repoOpt.Username != "" {
+		gitUsername = repoOpt.Username
+	}
+
+	if repoOpt.Token != "" {
+		return &http.BasicAuth{
+			Username: gitUsername,
+			Password: repoOpt.Token,
+		}
+	}
+
+	for _, env := range []string{"GITHUB_TOKEN", "GITLAB_TOKEN"} {
+		if token := strings.TrimSpace(os.Getenv(env)); token != "" {
+			return &http.BasicAuth{
+				Username: gitUsername,
+				Password: token,
+			}
+		}
+	}
+
+	// If no token was provided, we simply return a nil,
+	// which will make the request to be unauthenticated
+	return nil
+}
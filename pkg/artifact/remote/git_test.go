@@ -0,0 +1,274 @@
+package remote
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/artifact"
+	"github.com/aquasecurity/trivy/pkg/artifact/local"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+func TestMain(m *testing.M) {
+	_ = log.InitLogger(false, true)
+	os.Exit(m.Run())
+}
+
+// setupRepository creates a git repository with the following history.
+//
+//	main: "v1" (tag: v0.1.0) -> "v2"
+//	dev:  "v1" -> "dev"
+func setupRepository(t *testing.T) (string, plumbing.Hash) {
+	dir := t.TempDir()
+	r, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+
+	w, err := r.Worktree()
+	require.NoError(t, err)
+
+	commit := func(content string) plumbing.Hash {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "version.txt"), []byte(content), 0644))
+		_, err = w.Add("version.txt")
+		require.NoError(t, err)
+		hash, err := w.Commit(content, &git.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+		})
+		require.NoError(t, err)
+		return hash
+	}
+
+	first := commit("v1")
+	_, err = r.CreateTag("v0.1.0", first, nil)
+	require.NoError(t, err)
+
+	head, err := r.Head()
+	require.NoError(t, err)
+
+	require.NoError(t, w.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("dev"), Create: true}))
+	commit("dev")
+
+	require.NoError(t, w.Checkout(&git.CheckoutOptions{Branch: head.Name()}))
+	commit("v2")
+
+	return dir, first
+}
+
+func TestCloneRepository(t *testing.T) {
+	repoDir, firstCommit := setupRepository(t)
+	repo, err := git.PlainOpen(repoDir)
+	require.NoError(t, err)
+	devCommit, err := repo.ResolveRevision("refs/heads/dev")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		// allowSHA1InWant allows fetching commits which are not advertised, as GitHub and GitLab do
+		allowSHA1InWant bool
+		repoOpt         Option
+		want            string
+		wantShallow     bool
+		wantErr         string
+	}{
+		{
+			name: "default branch",
+			want: "v2",
+		},
+		{
+			name:        "depth",
+			repoOpt:     Option{Depth: 1},
+			want:        "v2",
+			wantShallow: true,
+		},
+		{
+			name:        "branch",
+			repoOpt:     Option{Branch: "dev", Depth: 1},
+			want:        "dev",
+			wantShallow: true,
+		},
+		{
+			name:        "tag",
+			repoOpt:     Option{Tag: "v0.1.0", Depth: 1},
+			want:        "v1",
+			wantShallow: true,
+		},
+		{
+			name:            "commit",
+			allowSHA1InWant: true,
+			repoOpt:         Option{Commit: firstCommit.String()},
+			want:            "v1",
+		},
+		{
+			name:            "commit with depth",
+			allowSHA1InWant: true,
+			repoOpt:         Option{Commit: devCommit.String(), Depth: 1},
+			want:            "dev",
+			wantShallow:     true,
+		},
+		{
+			name:    "commit not allowed to be fetched",
+			repoOpt: Option{Commit: devCommit.String(), Depth: 1},
+			want:    "dev",
+		},
+		{
+			name:    "unknown branch",
+			repoOpt: Option{Branch: "unknown"},
+			wantErr: "git error",
+		},
+		{
+			name:            "unknown commit",
+			allowSHA1InWant: true,
+			repoOpt:         Option{Commit: "0000000000000000000000000000000000000000"},
+			wantErr:         "unable to resolve the commit",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := repo.Config()
+			require.NoError(t, err)
+			cfg.Raw.Section("uploadpack").SetOption("allowReachableSHA1InWant", strconv.FormatBool(tt.allowSHA1InWant))
+			require.NoError(t, repo.SetConfig(cfg))
+
+			dir := t.TempDir()
+			err = cloneRepository(dir, "file://"+repoDir, artifact.Option{NoProgress: true}, tt.repoOpt, false)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			got, err := os.ReadFile(filepath.Join(dir, "version.txt"))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+
+			cloned, err := git.PlainOpen(dir)
+			require.NoError(t, err)
+			shallows, err := cloned.Storer.Shallow()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantShallow, len(shallows) > 0)
+		})
+	}
+}
+
+func Test_tokenAuth(t *testing.T) {
+	tests := []struct {
+		name        string
+		repoOpt     Option
+		githubToken string
+		want        transport.AuthMethod
+	}{
+		{
+			name:    "token",
+			repoOpt: Option{Token: "token"},
+			want:    &http.BasicAuth{Username: "trivy-aquasecurity-scan", Password: "token"},
+		},
+		{
+			name:        "token over GITHUB_TOKEN",
+			repoOpt:     Option{Username: "user", Token: "token"},
+			githubToken: "github-token",
+			want:        &http.BasicAuth{Username: "user", Password: "token"},
+		},
+		{
+			name:        "GITHUB_TOKEN",
+			githubToken: "github-token",
+			want:        &http.BasicAuth{Username: "trivy-aquasecurity-scan", Password: "github-token"},
+		},
+		{
+			name: "unauthenticated",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GITHUB_TOKEN", tt.githubToken)
+			t.Setenv("GITLAB_TOKEN", "")
+			assert.Equal(t, tt.want, tokenAuth(tt.repoOpt))
+		})
+	}
+}
+
+func TestNewArtifact(t *testing.T) {
+	tests := []struct {
+		name        string
+		rawurl      string
+		artifactOpt artifact.Option
+		repoOpt     Option
+		wantErr     string
+	}{
+		{
+			name:    "branch and tag",
+			rawurl:  "github.com/aquasecurity/trivy",
+			repoOpt: Option{Branch: "main", Tag: "v0.1.0"},
+			wantErr: "branch and tag cannot be specified at the same time",
+		},
+		{
+			name:    "negative depth",
+			rawurl:  "github.com/aquasecurity/trivy",
+			repoOpt: Option{Depth: -1},
+			wantErr: "depth must not be negative",
+		},
+		{
+			name:        "remote repository in offline mode",
+			rawurl:      "github.com/aquasecurity/trivy",
+			artifactOpt: artifact.Option{Offline: true},
+			wantErr:     "github.com/aquasecurity/trivy cannot be cloned with --offline-scan",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := NewArtifact(tt.rawurl, nil, tt.artifactOpt, tt.repoOpt, local.Option{})
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func Test_newURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		rawurl  string
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "without scheme",
+			rawurl: "github.com/aquasecurity/trivy",
+			want:   "https://github.com/aquasecurity/trivy",
+		},
+		{
+			name:   "with scheme",
+			rawurl: "http://example.com/org/repo.git",
+			want:   "http://example.com/org/repo.git",
+		},
+		{
+			name:   "scp-like",
+			rawurl: "git@github.com:aquasecurity/trivy.git",
+			want:   "git@github.com:aquasecurity/trivy.git",
+		},
+		{
+			name:    "invalid url",
+			rawurl:  "ht tp://foo.com",
+			wantErr: "url parse error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newURL(tt.rawurl)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package sbom
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	digest "github.com/opencontainers/go-digest"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/artifact"
+	"github.com/aquasecurity/fanal/cache"
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/sbom"
+)
+
+const (
+	ArtifactCycloneDX types.ArtifactType = "cyclonedx"
+	ArtifactSPDX      types.ArtifactType = "spdx"
+)
+
+// Option holds the options specific to SBOM scanning
+type Option struct {
+	// AttestationKey is the path to the public key verifying SBOM attestations
+	AttestationKey string
+
+	// InsecureSkipAttestationVerify accepts SBOM attestations which cannot be verified
+	InsecureSkipAttestationVerify bool
+}
+
+// Artifact treats an SBOM document as an artifact
+type Artifact struct {
+	filePath       string
+	cache          cache.ArtifactCache
+	artifactOption artifact.Option
+	decodeOption   sbom.Option
+}
+
+func NewArtifact(filePath string, c cache.ArtifactCache, opt artifact.Option, sbomOpt Option) (artifact.Artifact, error) {
+	decodeOption := sbom.Option{InsecureSkipVerify: sbomOpt.InsecureSkipAttestationVerify}
+	if sbomOpt.AttestationKey != "" {
+		key, err := sbom.LoadPublicKey(sbomOpt.AttestationKey)
+		if err != nil {
+			return nil, xerrors.Errorf("attestation key error: %w", err)
+		}
+		decodeOption.AttestationKey = key
+	}
+
+	return Artifact{
+		filePath:       filepath.Clean(filePath),
+		cache:          c,
+		artifactOption: opt,
+		decodeOption:   decodeOption,
+	}, nil
+}
+
+func (a Artifact) Inspect(_ context.Context) (types.ArtifactReference, error) {
+	f, err := os.Open(a.filePath)
+	if err != nil {
+		return types.ArtifactReference{}, xerrors.Errorf("failed to open the SBOM file: %w", err)
+	}
+	defer f.Close()
+
+	format, err := sbom.DetectFormat(f)
+	if err != nil {
+		return types.ArtifactReference{}, xerrors.Errorf("failed to detect SBOM format: %w", err)
+	}
+	log.Logger.Infof("Detected SBOM format: %s", format)
+
+	bom, err := sbom.Decode(f, format, a.decodeOption)
+	if err != nil {
+		return types.ArtifactReference{}, xerrors.Errorf("SBOM decode error: %w", err)
+	}
+
+	blobInfo := types.BlobInfo{
+		SchemaVersion: types.BlobJSONSchemaVersion,
+		OS:            bom.OS,
+		PackageInfos:  bom.Packages,
+		Applications:  bom.Applications,
+	}
+
+	cacheKey, err := a.calcCacheKey(blobInfo)
+	if err != nil {
+		return types.ArtifactReference{}, xerrors.Errorf("failed to calculate a cache key: %w", err)
+	}
+
+	if err = a.cache.PutBlob(cacheKey, blobInfo); err != nil {
+		return types.ArtifactReference{}, xerrors.Errorf("failed to store blob (%s) in cache: %w", cacheKey, err)
+	}
+
+	var artifactType types.ArtifactType
+	switch format {
+	case sbom.FormatCycloneDXJSON, sbom.FormatCycloneDXXML, sbom.FormatAttestCycloneDXJSON:
+		artifactType = ArtifactCycloneDX
+	case sbom.FormatSPDXJSON, sbom.FormatSPDXTV, sbom.FormatAttestSPDXJSON:
+		artifactType = ArtifactSPDX
+	}
+
+	return types.ArtifactReference{
+		Name:    a.filePath,
+		Type:    artifactType,
+		ID:      cacheKey, // use a cache key as pseudo artifact ID
+		BlobIDs: []string{cacheKey},
+	}, nil
+}
+
+func (a Artifact) Clean(reference types.ArtifactReference) error {
+	return a.cache.DeleteBlobs(reference.BlobIDs)
+}
+
+func (a Artifact) calcCacheKey(blobInfo types.BlobInfo) (string, error) {
+	// calculate hash of JSON and use it as pseudo artifactID and blobID
+	h := sha256.New()
+	if err := json.NewEncoder(h).Encode(blobInfo); err != nil {
+		return "", xerrors.Errorf("json error: %w", err)
+	}
+
+	d := digest.NewDigest(digest.SHA256, h)
+	cacheKey, err := cache.CalcKey(d.String(), nil, nil, a.artifactOption)
+	if err != nil {
+		return "", xerrors.Errorf("cache key: %w", err)
+	}
+
+	return cacheKey, nil
+}
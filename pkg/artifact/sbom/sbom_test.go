@@ -0,0 +1,73 @@
+package sbom_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/artifact"
+	"github.com/aquasecurity/fanal/cache"
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/artifact/sbom"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+func TestArtifact_Inspect(t *testing.T) {
+	require.NoError(t, log.InitLogger(false, true))
+
+	tests := []struct {
+		name     string
+		filePath string
+		wantType types.ArtifactType
+		wantApps []types.Application
+		wantErr  string
+	}{
+		{
+			name:     "SPDX",
+			filePath: "testdata/bom.spdx",
+			wantType: sbom.ArtifactSPDX,
+			wantApps: []types.Application{
+				{
+					Type: types.GoBinary,
+					Libraries: []types.Package{
+						{
+							Name:    "github.com/gin-gonic/gin",
+							Version: "v1.7.0",
+							License: "MIT",
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "missing file",
+			filePath: "testdata/missing.json",
+			wantErr:  "failed to open the SBOM file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := cache.NewFSCache(t.TempDir())
+			require.NoError(t, err)
+			defer c.Close()
+
+			a, err := sbom.NewArtifact(tt.filePath, c, artifact.Option{}, sbom.Option{})
+			require.NoError(t, err)
+
+			ref, err := a.Inspect(context.Background())
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.filePath, ref.Name)
+			assert.Equal(t, tt.wantType, ref.Type)
+
+			blob, err := c.GetBlob(ref.BlobIDs[0])
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantApps, blob.Applications)
+		})
+	}
+}
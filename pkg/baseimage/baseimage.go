@@ -0,0 +1,160 @@
+package baseimage
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Methods identifying base images
+const (
+	MethodIndex   = "index"
+	MethodHistory = "history"
+)
+
+// Index is the list of known base images, e.g. golden images maintained by a platform team
+type Index struct {
+	Images []KnownImage `json:"images"`
+}
+
+// KnownImage is a base image identified by its layers,
+// which are shown by "docker inspect --format '{{json .RootFS.Layers}}' <image>".
+type KnownImage struct {
+	Name    string   `json:"name"`
+	DiffIDs []string `json:"diff_ids"`
+}
+
+// LoadIndex loads the index of known base images in JSON
+func LoadIndex(filePath string) (*Index, error) {
+	b, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to read the base image index: %w", err)
+	}
+
+	var index Index
+	if err = json.Unmarshal(b, &index); err != nil {
+		return nil, xerrors.Errorf("base image index decode error (%s): %w", filePath, err)
+	}
+	for _, img := range index.Images {
+		if img.Name == "" || len(img.DiffIDs) == 0 {
+			return nil, xerrors.Errorf("base image index error (%s): name and diff_ids are required", filePath)
+		}
+	}
+	return &index, nil
+}
+
+// Detect returns the probable base image of the image, or nil if it is not identified.
+// The known image sharing the most layers from the bottom is chosen from the index.
+// Without matches, the base image is guessed from the history in the image config.
+func Detect(config v1.ConfigFile, diffIDs []string, index *Index) *types.BaseImage {
+	if index != nil {
+		var found *KnownImage
+		for i, img := range index.Images {
+			if !isPrefix(img.DiffIDs, diffIDs) {
+				continue
+			}
+			if found == nil || len(img.DiffIDs) > len(found.DiffIDs) {
+				found = &index.Images[i]
+			}
+		}
+		if found != nil {
+			return &types.BaseImage{
+				Name:    found.Name,
+				DiffIDs: found.DiffIDs,
+				Method:  MethodIndex,
+			}
+		}
+	}
+
+	n := guessBaseLayers(config.History)
+	if n <= 0 || n > len(diffIDs) {
+		return nil
+	}
+	return &types.BaseImage{
+		DiffIDs: diffIDs[:n],
+		Method:  MethodHistory,
+	}
+}
+
+// guessBaseLayers returns the number of layers in the base image.
+// The base image usually ends with CMD as the following, which is followed by the layers added on it.
+//
+//	ADD file:5d673d25da3a14ce1f6cf66e4c7fd4f4b85a3759a9d93efb3fd9ff852b5b56e4 in /
+//	CMD ["/bin/sh"]              # the end of the base image
+//	RUN apk add --no-cache curl
+//	COPY app /app
+//	ENTRYPOINT ["/app"]          # skipped as there are no layers after it
+//	CMD ["--help"]               # skipped as well
+//
+// It returns 0 if the end of the base image is not found.
+func guessBaseLayers(history []v1.History) int {
+	var foundLayer bool
+	for i := len(history) - 1; i >= 0; i-- {
+		h := history[i]
+		if !h.EmptyLayer {
+			foundLayer = true
+			continue
+		}
+		// Instructions after all the layers belong to the image itself
+		if !foundLayer || !isCmd(h.CreatedBy) {
+			continue
+		}
+
+		var n int
+		for _, hh := range history[:i] {
+			if !hh.EmptyLayer {
+				n++
+			}
+		}
+		return n
+	}
+	return 0
+}
+
+func isCmd(createdBy string) bool {
+	return strings.HasPrefix(createdBy, "/bin/sh -c #(nop)  CMD") || // Docker builder
+		strings.HasPrefix(createdBy, "CMD") // BuildKit
+}
+
+func isPrefix(prefix, diffIDs []string) bool {
+	if len(prefix) > len(diffIDs) {
+		return false
+	}
+	return slices.Equal(prefix, diffIDs[:len(prefix)])
+}
+
+// Attribute tells whether each vulnerable package is introduced by the base image or the layers added on it.
+// Vulnerabilities without layers are left as they are.
+func Attribute(results types.Results, base *types.BaseImage) {
+	for i := range results {
+		for j := range results[i].Vulnerabilities {
+			vuln := &results[i].Vulnerabilities[j]
+			if vuln.Layer.DiffID == "" {
+				continue
+			}
+			vuln.IntroducedBy = types.IntroducedByAddedLayers
+			if slices.Contains(base.DiffIDs, vuln.Layer.DiffID) {
+				vuln.IntroducedBy = types.IntroducedByBaseImage
+			}
+		}
+	}
+}
+
+// RemoveVulnerabilities removes vulnerabilities introduced by the base image
+func RemoveVulnerabilities(results types.Results) {
+	for i := range results {
+		var vulns []types.DetectedVulnerability
+		for _, v := range results[i].Vulnerabilities {
+			if v.IntroducedBy != types.IntroducedByBaseImage {
+				vulns = append(vulns, v)
+			}
+		}
+		results[i].Vulnerabilities = vulns
+	}
+}
@@ -0,0 +1,171 @@
+package baseimage_test
+
+import (
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/baseimage"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const (
+	alpineLayer = "sha256:994393dc58e7931862558d06e46aa2bb17487044f670f310dffe1d24e4d1eec7"
+	acmeLayer   = "sha256:5e9ae3bfc4a0e0b3a7ccd7bbcd6e1b8bb2a0af8c5a7b1e0e74e7a0e94c35d2bb"
+	appLayer    = "sha256:0b4a8f4a3f6c8b1e9d2a7c5e3f1b8d6a4c2e0f9b7d5a3c1e8f6b4d2a0c9e7f5b"
+)
+
+func TestLoadIndex(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		want     *baseimage.Index
+		wantErr  string
+	}{
+		{
+			name:     "happy path",
+			filePath: "testdata/index.json",
+			want: &baseimage.Index{
+				Images: []baseimage.KnownImage{
+					{
+						Name:    "alpine:3.16",
+						DiffIDs: []string{alpineLayer},
+					},
+					{
+						Name:    "acme/base:1.0",
+						DiffIDs: []string{alpineLayer, acmeLayer},
+					},
+				},
+			},
+		},
+		{
+			name:     "no diff IDs",
+			filePath: "testdata/invalid.json",
+			wantErr:  "name and diff_ids are required",
+		},
+		{
+			name:     "no such file",
+			filePath: "testdata/missing.json",
+			wantErr:  "unable to read the base image index",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := baseimage.LoadIndex(tt.filePath)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDetect(t *testing.T) {
+	index, err := baseimage.LoadIndex("testdata/index.json")
+	require.NoError(t, err)
+
+	history := []v1.History{
+		{CreatedBy: "/bin/sh -c #(nop) ADD file:5d673d25da3a14ce1f6cf66e4c7fd4f4b85a3759a9d93efb3fd9ff852b5b56e4 in / "},
+		{CreatedBy: `/bin/sh -c #(nop)  CMD ["/bin/sh"]`, EmptyLayer: true},
+		{CreatedBy: "RUN /bin/sh -c apk add --no-cache curl # buildkit"},
+		{CreatedBy: "COPY app /app # buildkit"},
+		{CreatedBy: `ENTRYPOINT ["/app"]`, EmptyLayer: true},
+		{CreatedBy: `CMD ["--help"]`, EmptyLayer: true},
+	}
+
+	tests := []struct {
+		name    string
+		config  v1.ConfigFile
+		diffIDs []string
+		index   *baseimage.Index
+		want    *types.BaseImage
+	}{
+		{
+			name:    "the longest match in the index",
+			diffIDs: []string{alpineLayer, acmeLayer, appLayer},
+			index:   index,
+			want: &types.BaseImage{
+				Name:    "acme/base:1.0",
+				DiffIDs: []string{alpineLayer, acmeLayer},
+				Method:  baseimage.MethodIndex,
+			},
+		},
+		{
+			name:    "history",
+			config:  v1.ConfigFile{History: history},
+			diffIDs: []string{alpineLayer, acmeLayer, appLayer},
+			want: &types.BaseImage{
+				DiffIDs: []string{alpineLayer},
+				Method:  baseimage.MethodHistory,
+			},
+		},
+		{
+			name:    "no match in the index",
+			config:  v1.ConfigFile{History: history},
+			diffIDs: []string{appLayer, alpineLayer, acmeLayer},
+			index:   index,
+			want: &types.BaseImage{
+				DiffIDs: []string{appLayer},
+				Method:  baseimage.MethodHistory,
+			},
+		},
+		{
+			name: "no CMD in the history",
+			config: v1.ConfigFile{History: []v1.History{
+				{CreatedBy: "COPY app /app # buildkit"},
+				{CreatedBy: `CMD ["/app"]`, EmptyLayer: true},
+			}},
+			diffIDs: []string{appLayer},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := baseimage.Detect(tt.config, tt.diffIDs, tt.index)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAttribute(t *testing.T) {
+	results := types.Results{
+		{
+			Target: "alpine:3.16 (alpine 3.16.2)",
+			Vulnerabilities: []types.DetectedVulnerability{
+				{VulnerabilityID: "CVE-2022-37434", Layer: ftypes.Layer{DiffID: alpineLayer}},
+				{VulnerabilityID: "CVE-2022-32221", Layer: ftypes.Layer{DiffID: appLayer}},
+				{VulnerabilityID: "CVE-2022-0001"},
+			},
+		},
+	}
+	baseimage.Attribute(results, &types.BaseImage{DiffIDs: []string{alpineLayer}})
+
+	want := types.Results{
+		{
+			Target: "alpine:3.16 (alpine 3.16.2)",
+			Vulnerabilities: []types.DetectedVulnerability{
+				{
+					VulnerabilityID: "CVE-2022-37434",
+					Layer:           ftypes.Layer{DiffID: alpineLayer},
+					IntroducedBy:    types.IntroducedByBaseImage,
+				},
+				{
+					VulnerabilityID: "CVE-2022-32221",
+					Layer:           ftypes.Layer{DiffID: appLayer},
+					IntroducedBy:    types.IntroducedByAddedLayers,
+				},
+				{VulnerabilityID: "CVE-2022-0001"},
+			},
+		},
+	}
+	assert.Equal(t, want, results)
+
+	baseimage.RemoveVulnerabilities(results)
+	assert.Equal(t, []types.DetectedVulnerability{want[0].Vulnerabilities[1], want[0].Vulnerabilities[2]},
+		results[0].Vulnerabilities)
+}
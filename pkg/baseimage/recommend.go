@@ -0,0 +1,145 @@
+package baseimage
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/go-version/pkg/version"
+	"github.com/aquasecurity/trivy/pkg/image"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// MaxCandidates is the number of the newest tags scanned as candidates of the upgrade
+const MaxCandidates = 5
+
+// tagPattern matches versioned tags with an optional variant, e.g. "3.16.2", "1.19-alpine" and "v2"
+var tagPattern = regexp.MustCompile(`^v?(\d+(?:\.\d+)*)(-.+)?$`)
+
+// Candidates returns the tags newer than the tag of the base image in its repository, the newest first.
+// Only tags with the same variant are chosen, e.g. "1.20-alpine" for "1.19-alpine", but not "1.20".
+func Candidates(baseImage string, insecure bool) ([]name.Tag, error) {
+	ref, err := name.NewTag(baseImage)
+	if err != nil {
+		return nil, xerrors.Errorf("base image name parse error: %w", err)
+	}
+
+	tags, err := remote.List(ref.Context(), image.RemoteOptions(insecure)...)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to list the tags of %s: %w", ref.Context(), err)
+	}
+
+	var candidates []name.Tag
+	for _, tag := range NewerTags(ref.TagStr(), tags) {
+		if len(candidates) == MaxCandidates {
+			break
+		}
+		candidates = append(candidates, ref.Context().Tag(tag))
+	}
+	return candidates, nil
+}
+
+// NewerTags returns the tags newer than the current one with the same variant, the newest first.
+// It returns nothing if the current tag is not versioned, e.g. "latest".
+func NewerTags(current string, tags []string) []string {
+	cur, variant, ok := parseTag(current)
+	if !ok {
+		return nil
+	}
+
+	type versionedTag struct {
+		tag string
+		ver version.Version
+	}
+	var newer []versionedTag
+	for _, tag := range tags {
+		v, vv, ok := parseTag(tag)
+		if !ok || vv != variant || !v.GreaterThan(cur) {
+			continue
+		}
+		newer = append(newer, versionedTag{tag: tag, ver: v})
+	}
+	sort.SliceStable(newer, func(i, j int) bool {
+		return newer[i].ver.GreaterThan(newer[j].ver)
+	})
+
+	var sorted []string
+	for _, t := range newer {
+		sorted = append(sorted, t.tag)
+	}
+	return sorted
+}
+
+func parseTag(tag string) (version.Version, string, bool) {
+	m := tagPattern.FindStringSubmatch(tag)
+	if m == nil {
+		return version.Version{}, "", false
+	}
+	v, err := version.Parse(m[1])
+	if err != nil {
+		return version.Version{}, "", false
+	}
+	return v, m[2], true
+}
+
+// Recommend compares the vulnerabilities in the base image with those in each candidate,
+// and returns the recommendations sorted by the number of remediated vulnerabilities.
+// The candidates are given with their vulnerabilities, the newest first.
+func Recommend(base string, results types.Results, candidates []string, candidateVulns [][]types.DetectedVulnerability) []types.Recommendation {
+	var current []string
+	for _, result := range results {
+		if result.Class != types.ClassOSPkg {
+			continue
+		}
+		for _, v := range result.Vulnerabilities {
+			if v.IntroducedBy == types.IntroducedByBaseImage {
+				current = appendUnique(current, v.VulnerabilityID)
+			}
+		}
+	}
+
+	var recommendations []types.Recommendation
+	for i, candidate := range candidates {
+		var remaining []string
+		for _, v := range candidateVulns[i] {
+			remaining = appendUnique(remaining, v.VulnerabilityID)
+		}
+
+		r := types.Recommendation{
+			BaseImage: base,
+			Candidate: candidate,
+		}
+		for _, id := range current {
+			if !slices.Contains(remaining, id) {
+				r.Remediated = append(r.Remediated, id)
+			}
+		}
+		for _, id := range remaining {
+			if !slices.Contains(current, id) {
+				r.Introduced = append(r.Introduced, id)
+			}
+		}
+		recommendations = append(recommendations, r)
+	}
+
+	// Prefer more remediated vulnerabilities, then fewer new ones, then newer tags
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		ri, rj := recommendations[i], recommendations[j]
+		if len(ri.Remediated) != len(rj.Remediated) {
+			return len(ri.Remediated) > len(rj.Remediated)
+		}
+		return len(ri.Introduced) < len(rj.Introduced)
+	})
+	return recommendations
+}
+
+func appendUnique(ids []string, id string) []string {
+	if slices.Contains(ids, id) {
+		return ids
+	}
+	return append(ids, id)
+}
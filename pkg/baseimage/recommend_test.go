@@ -0,0 +1,139 @@
+package baseimage_test
+
+import (
+	"io"
+	"log"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/baseimage"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestCandidates(t *testing.T) {
+	ts := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	defer ts.Close()
+	u, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+
+	img, err := random.Image(64, 1)
+	require.NoError(t, err)
+	for _, tag := range []string{"3.15", "3.16", "3.16.3", "3.17", "3.17.1", "3.18", "3.19", "3.20", "latest", "edge"} {
+		ref, err := name.ParseReference(u.Host + "/library/alpine:" + tag)
+		require.NoError(t, err)
+		require.NoError(t, remote.Write(ref, img))
+	}
+
+	got, err := baseimage.Candidates(u.Host+"/library/alpine:3.16", false)
+	require.NoError(t, err)
+
+	var tags []string
+	for _, tag := range got {
+		tags = append(tags, tag.TagStr())
+	}
+	assert.Equal(t, []string{"3.20", "3.19", "3.18", "3.17.1", "3.17"}, tags)
+
+	_, err = baseimage.Candidates(u.Host+"/library/missing:3.16", false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to list the tags")
+}
+
+func TestNewerTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		tags    []string
+		want    []string
+	}{
+		{
+			name:    "happy path",
+			current: "3.16",
+			tags:    []string{"3.15", "3.16", "3.16.2", "3.17", "latest", "3.10"},
+			want:    []string{"3.17", "3.16.2"},
+		},
+		{
+			name:    "same variant",
+			current: "1.19-alpine",
+			tags:    []string{"1.19-alpine", "1.20", "1.20-alpine", "1.20-bullseye", "1.21-alpine3.18"},
+			want:    []string{"1.20-alpine"},
+		},
+		{
+			name:    "v prefix",
+			current: "v1.2",
+			tags:    []string{"v1.1", "v1.10", "v1.9"},
+			want:    []string{"v1.10", "v1.9"},
+		},
+		{
+			name:    "not versioned",
+			current: "latest",
+			tags:    []string{"3.16", "3.17"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := baseimage.NewerTags(tt.current, tt.tags)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRecommend(t *testing.T) {
+	results := types.Results{
+		{
+			Target: "acme/app:1.0 (alpine 3.16.2)",
+			Class:  types.ClassOSPkg,
+			Vulnerabilities: []types.DetectedVulnerability{
+				{VulnerabilityID: "CVE-2022-37434", PkgName: "zlib", IntroducedBy: types.IntroducedByBaseImage},
+				{VulnerabilityID: "CVE-2022-43551", PkgName: "curl", IntroducedBy: types.IntroducedByBaseImage},
+				{VulnerabilityID: "CVE-2022-43551", PkgName: "libcurl", IntroducedBy: types.IntroducedByBaseImage},
+				{VulnerabilityID: "CVE-2022-32221", PkgName: "git", IntroducedBy: types.IntroducedByAddedLayers},
+			},
+		},
+		{
+			Target: "app/package-lock.json",
+			Class:  types.ClassLangPkg,
+			Vulnerabilities: []types.DetectedVulnerability{
+				{VulnerabilityID: "CVE-2022-0001", PkgName: "lodash", IntroducedBy: types.IntroducedByBaseImage},
+			},
+		},
+	}
+	candidates := []string{"alpine:3.18", "alpine:3.17", "alpine:3.16.3"}
+	candidateVulns := [][]types.DetectedVulnerability{
+		{
+			{VulnerabilityID: "CVE-2023-0286", PkgName: "openssl"},
+		},
+		{},
+		{
+			{VulnerabilityID: "CVE-2022-43551", PkgName: "curl"},
+		},
+	}
+
+	got := baseimage.Recommend("alpine:3.16", results, candidates, candidateVulns)
+	want := []types.Recommendation{
+		{
+			BaseImage:  "alpine:3.16",
+			Candidate:  "alpine:3.17",
+			Remediated: []string{"CVE-2022-37434", "CVE-2022-43551"},
+		},
+		{
+			BaseImage:  "alpine:3.16",
+			Candidate:  "alpine:3.18",
+			Remediated: []string{"CVE-2022-37434", "CVE-2022-43551"},
+			Introduced: []string{"CVE-2023-0286"},
+		},
+		{
+			BaseImage:  "alpine:3.16",
+			Candidate:  "alpine:3.16.3",
+			Remediated: []string{"CVE-2022-37434"},
+		},
+	}
+	assert.Equal(t, want, got)
+}
@@ -0,0 +1,98 @@
+package buildpack
+
+import (
+	"encoding/json"
+	"path"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const (
+	// BuildMetadataLabel is the label of images built by Cloud Native Buildpacks, which has the same content as MetadataFile
+	BuildMetadataLabel = "io.buildpacks.build.metadata"
+
+	// MetadataFile is written by the lifecycle of Cloud Native Buildpacks
+	MetadataFile = "layers/config/metadata.toml"
+
+	layersDir = "layers"
+)
+
+// Metadata represents the build metadata of Cloud Native Buildpacks.
+// ref. https://github.com/buildpacks/spec/blob/main/platform.md#iobuildpacksbuildmetadata-json
+type Metadata struct {
+	Buildpacks []Buildpack `json:"buildpacks" toml:"buildpacks"`
+	BOM        []BOMEntry  `json:"bom" toml:"bom"`
+}
+
+// Buildpack represents a buildpack which participated in the build
+type Buildpack struct {
+	ID       string `json:"id" toml:"id"`
+	Version  string `json:"version" toml:"version"`
+	Homepage string `json:"homepage" toml:"homepage"`
+}
+
+// BOMEntry represents a dependency contributed by a buildpack
+type BOMEntry struct {
+	Name      string      `json:"name" toml:"name"`
+	Metadata  BOMMetadata `json:"metadata" toml:"metadata"`
+	Buildpack Buildpack   `json:"buildpack" toml:"buildpack"`
+}
+
+// BOMMetadata is defined by each buildpack, and Paketo buildpacks have the version and the PURL
+type BOMMetadata struct {
+	Version string `json:"version" toml:"version"`
+	PURL    string `json:"purl" toml:"purl"`
+}
+
+// ParseLabels returns the build metadata in the image labels.
+// It returns nil if the image is not built by Cloud Native Buildpacks.
+func ParseLabels(labels map[string]string) (*Metadata, error) {
+	label, ok := labels[BuildMetadataLabel]
+	if !ok {
+		return nil, nil
+	}
+	var m Metadata
+	if err := json.Unmarshal([]byte(label), &m); err != nil {
+		return nil, xerrors.Errorf("unable to parse the %s label: %w", BuildMetadataLabel, err)
+	}
+	return &m, nil
+}
+
+// LayerDir returns the directory of layers contributed by the buildpack, e.g. layers/paketo-buildpacks_npm-install
+func LayerDir(id string) string {
+	return path.Join(layersDir, strings.ReplaceAll(id, "/", "_"))
+}
+
+// Attribute fills the buildpack contributing the vulnerable package,
+// which is identified by the path of the package or the target under the layer directory of the buildpack.
+func (m Metadata) Attribute(results types.Results) {
+	for i := range results {
+		for j := range results[i].Vulnerabilities {
+			vuln := &results[i].Vulnerabilities[j]
+			filePath := vuln.PkgPath
+			if filePath == "" {
+				filePath = results[i].Target
+			}
+			if bp := m.find(filePath); bp != nil {
+				vuln.Buildpack = &types.Buildpack{
+					ID:      bp.ID,
+					Version: bp.Version,
+				}
+			}
+		}
+	}
+}
+
+func (m Metadata) find(filePath string) *Buildpack {
+	filePath = strings.TrimPrefix(filePath, "/")
+	for i, bp := range m.Buildpacks {
+		dir := LayerDir(bp.ID)
+		if filePath == dir || strings.HasPrefix(filePath, dir+"/") {
+			return &m.Buildpacks[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package buildpack_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/buildpack"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestParseLabels(t *testing.T) {
+	tests := []struct {
+		name    string
+		labels  map[string]string
+		want    *buildpack.Metadata
+		wantErr string
+	}{
+		{
+			name: "happy path",
+			labels: map[string]string{
+				"io.buildpacks.build.metadata": `{"bom":[{"name":"yarn","metadata":{"purl":"pkg:npm/yarn@1.22.19","version":"1.22.19"},"buildpack":{"id":"paketo-buildpacks/yarn","version":"1.1.1"}}],"buildpacks":[{"id":"paketo-buildpacks/yarn","version":"1.1.1","homepage":"https://github.com/paketo-buildpacks/yarn"}],"launcher":{"version":"0.15.2"}}`,
+			},
+			want: &buildpack.Metadata{
+				Buildpacks: []buildpack.Buildpack{
+					{
+						ID:       "paketo-buildpacks/yarn",
+						Version:  "1.1.1",
+						Homepage: "https://github.com/paketo-buildpacks/yarn",
+					},
+				},
+				BOM: []buildpack.BOMEntry{
+					{
+						Name: "yarn",
+						Metadata: buildpack.BOMMetadata{
+							Version: "1.22.19",
+							PURL:    "pkg:npm/yarn@1.22.19",
+						},
+						Buildpack: buildpack.Buildpack{
+							ID:      "paketo-buildpacks/yarn",
+							Version: "1.1.1",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "not built by buildpacks",
+			labels: map[string]string{
+				"maintainer": "example",
+			},
+		},
+		{
+			name: "broken",
+			labels: map[string]string{
+				"io.buildpacks.build.metadata": `{`,
+			},
+			wantErr: "unable to parse",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildpack.ParseLabels(tt.labels)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMetadata_Attribute(t *testing.T) {
+	m := buildpack.Metadata{
+		Buildpacks: []buildpack.Buildpack{
+			{
+				ID:      "paketo-buildpacks/npm-install",
+				Version: "1.0.0",
+			},
+			{
+				ID:      "paketo-buildpacks/yarn",
+				Version: "1.1.1",
+			},
+		},
+	}
+	results := types.Results{
+		{
+			Target: "Node.js",
+			Vulnerabilities: []types.DetectedVulnerability{
+				{
+					VulnerabilityID: "CVE-2022-24999",
+					PkgName:         "qs",
+					PkgPath:         "layers/paketo-buildpacks_npm-install/launch-modules/node_modules/qs/package.json",
+				},
+				{
+					VulnerabilityID: "CVE-2022-25881",
+					PkgName:         "http-cache-semantics",
+					PkgPath:         "workspace/node_modules/http-cache-semantics/package.json",
+				},
+			},
+		},
+		{
+			Target: "layers/paketo-buildpacks_yarn",
+			Vulnerabilities: []types.DetectedVulnerability{
+				{
+					VulnerabilityID: "CVE-2021-4435",
+					PkgName:         "yarn",
+				},
+			},
+		},
+	}
+	m.Attribute(results)
+
+	assert.Equal(t, &types.Buildpack{ID: "paketo-buildpacks/npm-install", Version: "1.0.0"}, results[0].Vulnerabilities[0].Buildpack)
+	assert.Nil(t, results[0].Vulnerabilities[1].Buildpack)
+	assert.Equal(t, &types.Buildpack{ID: "paketo-buildpacks/yarn", Version: "1.1.1"}, results[1].Vulnerabilities[0].Buildpack)
+}
@@ -2,13 +2,13 @@ package cache
 
 import (
 	"context"
-	"crypto/tls"
 	"net/http"
 
 	"golang.org/x/xerrors"
 
 	"github.com/aquasecurity/fanal/cache"
 	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/httpclient"
 	"github.com/aquasecurity/trivy/pkg/rpc"
 	"github.com/aquasecurity/trivy/pkg/rpc/client"
 	rpcCache "github.com/aquasecurity/trivy/rpc/cache"
@@ -25,12 +25,7 @@ func NewRemoteCache(url string, customHeaders http.Header, insecure bool) cache.
 	ctx := client.WithCustomHeaders(context.Background(), customHeaders)
 
 	httpClient := &http.Client{
-		Transport: &http.Transport{
-			Proxy: http.ProxyFromEnvironment,
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: insecure,
-			},
-		},
+		Transport: httpclient.Transport(insecure),
 	}
 	c := rpcCache.NewCacheProtobufClient(url, httpClient)
 	return &RemoteCache{ctx: ctx, client: c}
@@ -38,7 +33,10 @@ func NewRemoteCache(url string, customHeaders http.Header, insecure bool) cache.
 
 // PutArtifact sends artifact to remote client
 func (c RemoteCache) PutArtifact(imageID string, artifactInfo types.ArtifactInfo) error {
-	_, err := c.client.PutArtifact(c.ctx, rpc.ConvertToRPCArtifactInfo(imageID, artifactInfo))
+	err := rpc.Retry(c.ctx, func() error {
+		_, err := c.client.PutArtifact(c.ctx, rpc.ConvertToRPCArtifactInfo(imageID, artifactInfo))
+		return err
+	})
 	if err != nil {
 		return xerrors.Errorf("unable to store cache on the server: %w", err)
 	}
@@ -47,7 +45,10 @@ func (c RemoteCache) PutArtifact(imageID string, artifactInfo types.ArtifactInfo
 
 // PutBlob sends blobInfo to remote client
 func (c RemoteCache) PutBlob(diffID string, blobInfo types.BlobInfo) error {
-	_, err := c.client.PutBlob(c.ctx, rpc.ConvertToRPCBlobInfo(diffID, blobInfo))
+	err := rpc.Retry(c.ctx, func() error {
+		_, err := c.client.PutBlob(c.ctx, rpc.ConvertToRPCBlobInfo(diffID, blobInfo))
+		return err
+	})
 	if err != nil {
 		return xerrors.Errorf("unable to store cache on the server: %w", err)
 	}
@@ -56,7 +57,12 @@ func (c RemoteCache) PutBlob(diffID string, blobInfo types.BlobInfo) error {
 
 // MissingBlobs fetches missing blobs from RemoteCache
 func (c RemoteCache) MissingBlobs(imageID string, layerIDs []string) (bool, []string, error) {
-	layers, err := c.client.MissingBlobs(c.ctx, rpc.ConvertToMissingBlobsRequest(imageID, layerIDs))
+	var layers *rpcCache.MissingBlobsResponse
+	err := rpc.Retry(c.ctx, func() error {
+		var err error
+		layers, err = c.client.MissingBlobs(c.ctx, rpc.ConvertToMissingBlobsRequest(imageID, layerIDs))
+		return err
+	})
 	if err != nil {
 		return false, nil, xerrors.Errorf("unable to fetch missing layers: %w", err)
 	}
@@ -65,7 +71,10 @@ func (c RemoteCache) MissingBlobs(imageID string, layerIDs []string) (bool, []st
 
 // DeleteBlobs removes blobs by IDs from RemoteCache
 func (c RemoteCache) DeleteBlobs(blobIDs []string) error {
-	_, err := c.client.DeleteBlobs(c.ctx, rpc.ConvertToDeleteBlobsRequest(blobIDs))
+	err := rpc.Retry(c.ctx, func() error {
+		_, err := c.client.DeleteBlobs(c.ctx, rpc.ConvertToDeleteBlobsRequest(blobIDs))
+		return err
+	})
 	if err != nil {
 		return xerrors.Errorf("unable to delete blobs on the server: %w", err)
 	}
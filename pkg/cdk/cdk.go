@@ -0,0 +1,107 @@
+package cdk
+
+import (
+	"encoding/json"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+const (
+	// FileTypeCDK is the type of CloudFormation templates synthesized by AWS CDK
+	FileTypeCDK = "cdk"
+
+	manifestFile = "manifest.json"
+	stackType    = "aws:cloudformation:stack"
+	pathKey      = "aws:cdk:path"
+)
+
+// manifest is the manifest of a cloud assembly, e.g. cdk.out/manifest.json
+type manifest struct {
+	Version   string `json:"version"`
+	Artifacts map[string]struct {
+		Type       string `json:"type"`
+		Properties struct {
+			TemplateFile string `json:"templateFile"`
+		} `json:"properties"`
+	} `json:"artifacts"`
+}
+
+type template struct {
+	Resources map[string]struct {
+		Metadata map[string]interface{} `json:"Metadata"`
+	} `json:"Resources"`
+}
+
+// Templates finds the CloudFormation templates of the stacks in cloud assemblies synthesized by "cdk synth",
+// and returns the construct paths of the resources, e.g. "AppStack/LogsBucket/Resource", by the template paths.
+// The templates are evaluated as CloudFormation, and findings are attributed to the constructs by Relabel.
+func Templates(files []types.File) map[string]map[string]string {
+	contents := map[string][]byte{}
+	for _, file := range files {
+		contents[filepath.ToSlash(file.Path)] = file.Content
+	}
+
+	templates := map[string]map[string]string{}
+	for _, file := range files {
+		filePath := filepath.ToSlash(file.Path)
+		if file.Type != types.JSON || path.Base(filePath) != manifestFile {
+			continue
+		}
+
+		var m manifest
+		if err := json.Unmarshal(file.Content, &m); err != nil || m.Version == "" {
+			continue
+		}
+
+		for name, artifact := range m.Artifacts {
+			if artifact.Type != stackType || artifact.Properties.TemplateFile == "" {
+				continue
+			}
+			templatePath := path.Join(path.Dir(filePath), artifact.Properties.TemplateFile)
+			content, ok := contents[templatePath]
+			if !ok {
+				continue
+			}
+
+			var t template
+			if err := json.Unmarshal(content, &t); err != nil {
+				log.Logger.Debugf("Unable to parse the template of the CDK stack %s (%s): %s", name, templatePath, err)
+				continue
+			}
+
+			constructs := map[string]string{}
+			for logicalID, r := range t.Resources {
+				if p, ok := r.Metadata[pathKey].(string); ok {
+					constructs[logicalID] = p
+				}
+			}
+			templates[filepath.FromSlash(templatePath)] = constructs
+		}
+	}
+	return templates
+}
+
+// Relabel attributes misconfigurations in the stack templates to the constructs
+func Relabel(misconfs []types.Misconfiguration, templates map[string]map[string]string) {
+	for i, misconf := range misconfs {
+		constructs, ok := templates[misconf.FilePath]
+		if !ok {
+			continue
+		}
+		misconfs[i].FileType = FileTypeCDK
+
+		for _, results := range []types.MisconfResults{misconf.Successes, misconf.Warnings, misconf.Failures, misconf.Exceptions} {
+			for j := range results {
+				r := &results[j]
+				r.Type = strings.Replace(r.Type, "CloudFormation", "CDK", 1)
+				if construct, ok := constructs[r.Resource]; ok {
+					r.Resource = construct
+				}
+			}
+		}
+	}
+}
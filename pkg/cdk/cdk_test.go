@@ -0,0 +1,97 @@
+package cdk_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/cdk"
+)
+
+func TestTemplates(t *testing.T) {
+	var files []types.File
+	for _, name := range []string{"manifest.json", "AppStack.template.json"} {
+		b, err := os.ReadFile(filepath.Join("testdata", "cdk.out", name))
+		require.NoError(t, err)
+		files = append(files, types.File{
+			Type:    types.JSON,
+			Path:    filepath.Join("cdk.out", name),
+			Content: b,
+		})
+	}
+	// Not a cloud assembly
+	files = append(files, types.File{
+		Type:    types.JSON,
+		Path:    filepath.Join("app", "manifest.json"),
+		Content: []byte(`{"name": "app"}`),
+	})
+
+	got := cdk.Templates(files)
+	want := map[string]map[string]string{
+		filepath.Join("cdk.out", "AppStack.template.json"): {
+			"LogsBucket9C4D8843": "AppStack/LogsBucket/Resource",
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestRelabel(t *testing.T) {
+	misconfs := []types.Misconfiguration{
+		{
+			FileType: types.CloudFormation,
+			FilePath: "cdk.out/AppStack.template.json",
+			Failures: types.MisconfResults{
+				{
+					PolicyMetadata: types.PolicyMetadata{
+						ID:   "AVD-AWS-0086",
+						Type: "CloudFormation Security Check",
+					},
+					CauseMetadata: types.CauseMetadata{
+						Resource:  "LogsBucket9C4D8843",
+						StartLine: 3,
+						EndLine:   9,
+					},
+				},
+			},
+		},
+		{
+			FileType: types.CloudFormation,
+			FilePath: "template.yaml",
+		},
+	}
+
+	cdk.Relabel(misconfs, map[string]map[string]string{
+		"cdk.out/AppStack.template.json": {
+			"LogsBucket9C4D8843": "AppStack/LogsBucket/Resource",
+		},
+	})
+
+	want := []types.Misconfiguration{
+		{
+			FileType: cdk.FileTypeCDK,
+			FilePath: "cdk.out/AppStack.template.json",
+			Failures: types.MisconfResults{
+				{
+					PolicyMetadata: types.PolicyMetadata{
+						ID:   "AVD-AWS-0086",
+						Type: "CDK Security Check",
+					},
+					CauseMetadata: types.CauseMetadata{
+						Resource:  "AppStack/LogsBucket/Resource",
+						StartLine: 3,
+						EndLine:   9,
+					},
+				},
+			},
+		},
+		{
+			FileType: types.CloudFormation,
+			FilePath: "template.yaml",
+		},
+	}
+	assert.Equal(t, want, misconfs)
+}
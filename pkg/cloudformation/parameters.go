@@ -0,0 +1,206 @@
+package cloudformation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/aquasecurity/defsec/pkg/detection"
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+// Parameters holds the values of stack parameters by their names
+type Parameters map[string]string
+
+// parameter is an element of the file passed to "aws cloudformation create-stack --parameters"
+type parameter struct {
+	ParameterKey   string
+	ParameterValue string
+}
+
+// templateConfiguration is the template configuration file of AWS CodePipeline
+type templateConfiguration struct {
+	Parameters map[string]interface{}
+}
+
+// LoadParameters reads parameter files.
+// Both the format of the AWS CLI, i.e. [{"ParameterKey": "Env", "ParameterValue": "prod"}],
+// and the template configuration of AWS CodePipeline, i.e. {"Parameters": {"Env": "prod"}}, are supported.
+// When a parameter is defined in multiple files, the value in the last file is used.
+func LoadParameters(paths []string) (Parameters, error) {
+	params := Parameters{}
+	for _, p := range paths {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return nil, xerrors.Errorf("unable to read the parameter file (%s): %w", p, err)
+		}
+		if err = params.parse(b); err != nil {
+			return nil, xerrors.Errorf("unable to parse the parameter file (%s): %w", p, err)
+		}
+	}
+	return params, nil
+}
+
+func (params Parameters) parse(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if bytes.HasPrefix(b, []byte("[")) {
+		var list []parameter
+		if err := json.Unmarshal(b, &list); err != nil {
+			return xerrors.Errorf("json error: %w", err)
+		}
+		for _, p := range list {
+			params[p.ParameterKey] = p.ParameterValue
+		}
+		return nil
+	}
+
+	var config templateConfiguration
+	if err := json.Unmarshal(b, &config); err != nil {
+		return xerrors.Errorf("json error: %w", err)
+	}
+	for k, v := range config.Parameters {
+		params[k] = fmt.Sprint(v)
+	}
+	return nil
+}
+
+// Apply sets the parameter values as the defaults of the parameters declared in CloudFormation templates,
+// so that intrinsic functions such as Ref and Fn::Sub are resolved with them.
+// Templates which do not declare the parameters are left as they are.
+func Apply(files []types.File, params Parameters) []types.File {
+	if len(params) == 0 {
+		return files
+	}
+
+	var results []types.File
+	for _, file := range files {
+		if (file.Type != types.YAML && file.Type != types.JSON) ||
+			!detection.IsType(file.Path, bytes.NewReader(file.Content), detection.FileTypeCloudFormation) {
+			results = append(results, file)
+			continue
+		}
+
+		content, err := apply(file, params)
+		if err != nil {
+			log.Logger.Warnf("Unable to set the parameters to the CloudFormation template (%s): %s", file.Path, err)
+		} else if content != nil {
+			file.Content = content
+		}
+		results = append(results, file)
+	}
+	return results
+}
+
+// apply returns the template with the defaults updated, or nil if no parameter is updated
+func apply(file types.File, params Parameters) ([]byte, error) {
+	if file.Type == types.JSON {
+		return applyJSON(file.Content, params)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(file.Content, &doc); err != nil {
+		return nil, xerrors.Errorf("yaml error: %w", err)
+	}
+	if len(doc.Content) == 0 || !setDefaults(doc.Content[0], params) {
+		return nil, nil
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, xerrors.Errorf("yaml encode error: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// setDefaults updates the defaults in the "Parameters" section of the template
+func setDefaults(template *yaml.Node, params Parameters) bool {
+	declared := mappingValue(template, "Parameters")
+	if declared == nil {
+		return false
+	}
+
+	var updated bool
+	for i := 0; i+1 < len(declared.Content); i += 2 {
+		value, ok := params[declared.Content[i].Value]
+		decl := declared.Content[i+1]
+		if !ok || decl.Kind != yaml.MappingNode {
+			continue
+		}
+
+		scalar := &yaml.Node{
+			Kind:  yaml.ScalarNode,
+			Tag:   "!!str",
+			Value: value,
+		}
+		// Numbers are kept as numbers
+		if t := mappingValue(decl, "Type"); t != nil && t.Value == "Number" {
+			scalar.Tag = ""
+		}
+
+		if d := mappingValue(decl, "Default"); d != nil {
+			*d = *scalar
+		} else {
+			decl.Content = append(decl.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "Default"}, scalar)
+		}
+		updated = true
+	}
+	return updated
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func applyJSON(content []byte, params Parameters) ([]byte, error) {
+	var template map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(content))
+	dec.UseNumber()
+	if err := dec.Decode(&template); err != nil {
+		return nil, xerrors.Errorf("json error: %w", err)
+	}
+
+	declared, ok := template["Parameters"].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	var updated bool
+	for name, decl := range declared {
+		value, ok := params[name]
+		d, isMap := decl.(map[string]interface{})
+		if !ok || !isMap {
+			continue
+		}
+		if _, err := strconv.ParseFloat(value, 64); err == nil && d["Type"] == "Number" {
+			d["Default"] = json.Number(value)
+		} else {
+			d["Default"] = value
+		}
+		updated = true
+	}
+	if !updated {
+		return nil, nil
+	}
+
+	b, err := json.MarshalIndent(template, "", "  ")
+	if err != nil {
+		return nil, xerrors.Errorf("json encode error: %w", err)
+	}
+	return b, nil
+}
@@ -0,0 +1,167 @@
+package cloudformation_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/cloudformation"
+)
+
+func TestLoadParameters(t *testing.T) {
+	tests := []struct {
+		name    string
+		paths   []string
+		want    cloudformation.Parameters
+		wantErr string
+	}{
+		{
+			name:  "aws cli",
+			paths: []string{"testdata/params.json"},
+			want: cloudformation.Parameters{
+				"Env":       "prod",
+				"Encrypted": "false",
+			},
+		},
+		{
+			name:  "later files take precedence",
+			paths: []string{"testdata/params.json", "testdata/template-configuration.json"},
+			want: cloudformation.Parameters{
+				"Env":       "staging",
+				"Encrypted": "false",
+				"Port":      "22",
+			},
+		},
+		{
+			name:    "broken",
+			paths:   []string{"testdata/broken.json"},
+			wantErr: "unable to parse the parameter file (testdata/broken.json)",
+		},
+		{
+			name:    "missing",
+			paths:   []string{"testdata/missing.json"},
+			wantErr: "unable to read the parameter file (testdata/missing.json)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cloudformation.LoadParameters(tt.paths)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	params := cloudformation.Parameters{
+		"Env":       "prod",
+		"Encrypted": "false",
+		"Port":      "22",
+	}
+
+	tests := []struct {
+		name string
+		file types.File
+		want string
+	}{
+		{
+			name: "yaml",
+			file: types.File{
+				Type: types.YAML,
+				Path: "template.yaml",
+				Content: []byte(`Parameters:
+  Env:
+    Type: String
+    Default: dev
+  Encrypted:
+    Type: String
+  Port:
+    Type: Number
+    Default: 443
+Resources:
+  Bucket:
+    Type: AWS::S3::Bucket
+    Properties:
+      BucketName: !Sub "${Env}-bucket"
+`),
+			},
+			want: `Parameters:
+  Env:
+    Type: String
+    Default: prod
+  Encrypted:
+    Type: String
+    Default: "false"
+  Port:
+    Type: Number
+    Default: 22
+Resources:
+  Bucket:
+    Type: AWS::S3::Bucket
+    Properties:
+      BucketName: !Sub "${Env}-bucket"
+`,
+		},
+		{
+			name: "json",
+			file: types.File{
+				Type: types.JSON,
+				Path: "template.json",
+				Content: []byte(`{
+  "Parameters": {"Env": {"Type": "String"}, "Port": {"Type": "Number", "Default": 443}},
+  "Resources": {"Bucket": {"Type": "AWS::S3::Bucket"}}
+}`),
+			},
+			want: `{
+  "Parameters": {
+    "Env": {
+      "Default": "prod",
+      "Type": "String"
+    },
+    "Port": {
+      "Default": 22,
+      "Type": "Number"
+    }
+  },
+  "Resources": {
+    "Bucket": {
+      "Type": "AWS::S3::Bucket"
+    }
+  }
+}`,
+		},
+		{
+			name: "no parameters declared",
+			file: types.File{
+				Type:    types.YAML,
+				Path:    "template.yaml",
+				Content: []byte("Resources:\n  Bucket:\n    Type: AWS::S3::Bucket\n"),
+			},
+			want: "Resources:\n  Bucket:\n    Type: AWS::S3::Bucket\n",
+		},
+		{
+			name: "not a template",
+			file: types.File{
+				Type:    types.YAML,
+				Path:    "values.yaml",
+				Content: []byte("Parameters:\n  Env:\n    Type: String\n"),
+			},
+			want: "Parameters:\n  Env:\n    Type: String\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := cloudformation.Apply([]types.File{tt.file}, params)
+			require.Len(t, got, 1)
+			assert.Equal(t, tt.file.Path, got[0].Path)
+			assert.Equal(t, tt.want, string(got[0].Content))
+		})
+	}
+}
@@ -13,14 +13,20 @@ import (
 	"github.com/aquasecurity/trivy-db/pkg/metadata"
 	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
 	"github.com/aquasecurity/trivy/pkg/commands/artifact"
+	"github.com/aquasecurity/trivy/pkg/commands/check"
+	"github.com/aquasecurity/trivy/pkg/commands/fix"
+	"github.com/aquasecurity/trivy/pkg/commands/gate"
+	"github.com/aquasecurity/trivy/pkg/commands/history"
 	"github.com/aquasecurity/trivy/pkg/commands/module"
 	"github.com/aquasecurity/trivy/pkg/commands/option"
 	"github.com/aquasecurity/trivy/pkg/commands/plugin"
 	"github.com/aquasecurity/trivy/pkg/commands/server"
 	k8scommands "github.com/aquasecurity/trivy/pkg/k8s/commands"
 	"github.com/aquasecurity/trivy/pkg/log"
+	tplugin "github.com/aquasecurity/trivy/pkg/plugin"
 	"github.com/aquasecurity/trivy/pkg/report"
 	"github.com/aquasecurity/trivy/pkg/result"
+	"github.com/aquasecurity/trivy/pkg/retry"
 	"github.com/aquasecurity/trivy/pkg/types"
 	"github.com/aquasecurity/trivy/pkg/utils"
 )
@@ -44,7 +50,7 @@ var (
 		Name:    "format",
 		Aliases: []string{"f"},
 		Value:   report.FormatTable,
-		Usage:   "format (table, json, sarif, template, cyclonedx, spdx, spdx-json, github)",
+		Usage:   "format (table, json, sarif, template, cyclonedx, spdx, spdx-json, github, github-annotations)",
 		EnvVars: []string{"TRIVY_FORMAT"},
 	}
 
@@ -56,6 +62,72 @@ var (
 		EnvVars: []string{"TRIVY_INPUT"},
 	}
 
+	platformFlag = cli.StringFlag{
+		Name:    "platform",
+		Usage:   "select an image for the platform (os/arch[/variant]) from multi-arch archives given by --input, e.g. linux/arm64",
+		EnvVars: []string{"TRIVY_PLATFORM"},
+	}
+
+	baseImageIndexFlag = cli.StringFlag{
+		Name:    "base-image-index",
+		Usage:   "specify a JSON file of known base images to attribute vulnerabilities to the base image",
+		EnvVars: []string{"TRIVY_BASE_IMAGE_INDEX"},
+	}
+
+	ignoreBaseImageVulnsFlag = cli.BoolFlag{
+		Name:    "ignore-base-image-vulns",
+		Usage:   "display only vulnerabilities introduced by the layers added on the base image",
+		EnvVars: []string{"TRIVY_IGNORE_BASE_IMAGE_VULNS"},
+	}
+
+	recommendBaseImageFlag = cli.BoolFlag{
+		Name:    "recommend-base-image",
+		Usage:   "scan newer tags of the base image and recommend the upgrade remediating the most OS vulnerabilities",
+		EnvVars: []string{"TRIVY_RECOMMEND_BASE_IMAGE"},
+	}
+
+	verifyProvenanceFlag = cli.BoolFlag{
+		Name:    "verify-provenance",
+		Usage:   "verify SLSA provenance attestations of the image and report policy violations",
+		EnvVars: []string{"TRIVY_VERIFY_PROVENANCE"},
+	}
+
+	provenanceKeyFlag = cli.StringFlag{
+		Name:    "provenance-key",
+		Usage:   "public key file to verify the signatures of provenance attestations",
+		EnvVars: []string{"TRIVY_PROVENANCE_KEY"},
+	}
+
+	provenanceBuilderFlag = cli.StringSliceFlag{
+		Name:    "provenance-builder",
+		Usage:   "allowed builder IDs of provenance (any versions are allowed without \"@\")",
+		EnvVars: []string{"TRIVY_PROVENANCE_BUILDER"},
+	}
+
+	provenanceSourceFlag = cli.StringSliceFlag{
+		Name:    "provenance-source",
+		Usage:   "allowed source repositories of provenance, e.g. github.com/org/repo",
+		EnvVars: []string{"TRIVY_PROVENANCE_SOURCE"},
+	}
+
+	verifySignatureFlag = cli.BoolFlag{
+		Name:    "verify-signature",
+		Usage:   "verify cosign or Notation signatures of the image and report unsigned images or invalid signatures",
+		EnvVars: []string{"TRIVY_VERIFY_SIGNATURE"},
+	}
+
+	signatureKeyFlag = cli.StringFlag{
+		Name:    "signature-key",
+		Usage:   "public key file to verify cosign signatures",
+		EnvVars: []string{"TRIVY_SIGNATURE_KEY"},
+	}
+
+	signatureCertFlag = cli.StringFlag{
+		Name:    "signature-cert",
+		Usage:   "file of trusted root certificates in PEM to verify Notation signatures",
+		EnvVars: []string{"TRIVY_SIGNATURE_CERT"},
+	}
+
 	severityFlag = cli.StringFlag{
 		Name:    "severity",
 		Aliases: []string{"s"},
@@ -78,6 +150,12 @@ var (
 		EnvVars: []string{"TRIVY_EXIT_CODE"},
 	}
 
+	failFastFlag = cli.BoolFlag{
+		Name:    "fail-fast",
+		Usage:   "stop scanning as soon as findings failing the scan are detected, which are reported with --exit-code",
+		EnvVars: []string{"TRIVY_FAIL_FAST"},
+	}
+
 	skipDBUpdateFlag = cli.BoolFlag{
 		Name:    "skip-db-update",
 		Aliases: []string{"skip-update"},
@@ -123,6 +201,12 @@ var (
 		EnvVars: []string{"TRIVY_NO_PROGRESS"},
 	}
 
+	quietProgressFlag = cli.BoolFlag{
+		Name:    "quiet-progress",
+		Usage:   "write progress events of analysis as JSON lines to stderr instead of progress bars",
+		EnvVars: []string{"TRIVY_QUIET_PROGRESS"},
+	}
+
 	ignoreUnfixedFlag = cli.BoolFlag{
 		Name:    "ignore-unfixed",
 		Usage:   "display only fixed vulnerabilities",
@@ -144,15 +228,15 @@ var (
 
 	vulnTypeFlag = cli.StringFlag{
 		Name:    "vuln-type",
-		Value:   strings.Join([]string{types.VulnTypeOS, types.VulnTypeLibrary}, ","),
-		Usage:   "comma-separated list of vulnerability types (os,library)",
+		Value:   strings.Join([]string{types.VulnTypeOS, types.VulnTypeLibrary, types.VulnTypeKernel}, ","),
+		Usage:   "comma-separated list of vulnerability types (os,library,kernel)",
 		EnvVars: []string{"TRIVY_VULN_TYPE"},
 	}
 
 	securityChecksFlag = cli.StringFlag{
 		Name:    "security-checks",
 		Value:   fmt.Sprintf("%s,%s", types.SecurityCheckVulnerability, types.SecurityCheckSecret),
-		Usage:   "comma-separated list of what security issues to detect (vuln,config,secret)",
+		Usage:   "comma-separated list of what security issues to detect (vuln,config,secret,license)",
 		EnvVars: []string{"TRIVY_SECURITY_CHECKS"},
 	}
 
@@ -211,6 +295,42 @@ var (
 		EnvVars: []string{"TRIVY_TIMEOUT"},
 	}
 
+	dbTimeoutFlag = cli.DurationFlag{
+		Name:    "db-timeout",
+		Usage:   "timeout for downloading the vulnerability DB, limited only by --timeout if not specified",
+		EnvVars: []string{"TRIVY_DB_TIMEOUT"},
+	}
+
+	pullTimeoutFlag = cli.DurationFlag{
+		Name:    "pull-timeout",
+		Usage:   "timeout for pulling the image or cloning the repository, limited only by --timeout if not specified",
+		EnvVars: []string{"TRIVY_PULL_TIMEOUT"},
+	}
+
+	analysisTimeoutFlag = cli.DurationFlag{
+		Name:    "analysis-timeout",
+		Usage:   "timeout for analyzing the artifact and detecting issues, limited only by --timeout if not specified",
+		EnvVars: []string{"TRIVY_ANALYSIS_TIMEOUT"},
+	}
+
+	targetTimeoutFlag = cli.DurationFlag{
+		Name:    "target-timeout",
+		Usage:   "timeout for detecting vulnerabilities in each target such as a lock file, limited only by --timeout if not specified",
+		EnvVars: []string{"TRIVY_TARGET_TIMEOUT"},
+	}
+
+	continueOnErrorFlag = cli.BoolFlag{
+		Name:    "continue-on-error",
+		Usage:   "record errors of files and targets failing analysis or detection in the report instead of aborting the scan, which are reported with --exit-code",
+		EnvVars: []string{"TRIVY_CONTINUE_ON_ERROR"},
+	}
+
+	policyTimeoutFlag = cli.DurationFlag{
+		Name:    "policy-timeout",
+		Usage:   "timeout for evaluating misconfiguration checks, per layer for images, limited only by --timeout if not specified",
+		EnvVars: []string{"TRIVY_POLICY_TIMEOUT"},
+	}
+
 	namespaceFlag = cli.StringFlag{
 		Name:    "namespace",
 		Aliases: []string{"n"},
@@ -233,6 +353,13 @@ var (
 		Usage: "specify a report format for the output. (all,summary default: all)",
 	}
 
+	imageReportFlag = cli.StringFlag{
+		Name:    "report",
+		Value:   "all",
+		Usage:   "specify a report view for the table format. \"layers\" groups findings by the layer introducing them (all,layers)",
+		EnvVars: []string{"TRIVY_REPORT"},
+	}
+
 	// TODO: remove this flag after a sufficient deprecation period.
 	lightFlag = cli.BoolFlag{
 		Name:    "light",
@@ -265,6 +392,12 @@ var (
 		EnvVars: []string{"TRIVY_LIST_ALL_PKGS"},
 	}
 
+	includeDevDeps = cli.BoolFlag{
+		Name:    "include-dev-deps",
+		Usage:   "include development dependencies in the report (supported only for some lock files)",
+		EnvVars: []string{"TRIVY_INCLUDE_DEV_DEPS"},
+	}
+
 	skipFiles = cli.StringSliceFlag{
 		Name:    "skip-files",
 		Usage:   "specify the file paths to skip traversal",
@@ -277,9 +410,103 @@ var (
 		EnvVars: []string{"TRIVY_SKIP_DIRS"},
 	}
 
+	enableAnalyzers = cli.StringSliceFlag{
+		Name:    "enable-analyzers",
+		Usage:   "enable only the specified analyzers (see \"trivy analyzers list\")",
+		EnvVars: []string{"TRIVY_ENABLE_ANALYZERS"},
+	}
+
+	disableAnalyzers = cli.StringSliceFlag{
+		Name:    "disable-analyzers",
+		Usage:   "disable the specified analyzers (see \"trivy analyzers list\")",
+		EnvVars: []string{"TRIVY_DISABLE_ANALYZERS"},
+	}
+
+	maxFileSize = cli.StringSliceFlag{
+		Name:    "max-file-size",
+		Usage:   "skip files larger than the size for all analyzers, or for a category or an analyzer as CLASS=SIZE (e.g. 100MB,secret=1MB)",
+		EnvVars: []string{"TRIVY_MAX_FILE_SIZE"},
+	}
+
+	scanArchives = cli.BoolFlag{
+		Name:    "scan-archives",
+		Usage:   "analyze files in zip, wheel and tar archives found in the target, including nested archives and \"docker save\" tarballs",
+		EnvVars: []string{"TRIVY_SCAN_ARCHIVES"},
+	}
+
+	archiveDepth = cli.IntFlag{
+		Name:    "archive-depth",
+		Usage:   "maximum nesting depth of archives walked with --scan-archives",
+		Value:   3,
+		EnvVars: []string{"TRIVY_ARCHIVE_DEPTH"},
+	}
+
+	archiveSizeLimit = cli.StringFlag{
+		Name:    "archive-size-limit",
+		Usage:   "limit the total size of files extracted from each archive with --scan-archives, skipping files beyond it",
+		Value:   "1GiB",
+		EnvVars: []string{"TRIVY_ARCHIVE_SIZE_LIMIT"},
+	}
+
+	followSymlinks = cli.StringFlag{
+		Name:    "follow-symlinks",
+		Usage:   "policy of following symbolic links to files: none, root (resolve relative to the target as chroot does), all (default: root in rootfs, none otherwise)",
+		EnvVars: []string{"TRIVY_FOLLOW_SYMLINKS"},
+	}
+
+	includeSystemDirs = cli.BoolFlag{
+		Name:    "include-system-dirs",
+		Usage:   "walk the system directories proc, sys and dev, which are skipped by default",
+		EnvVars: []string{"TRIVY_INCLUDE_SYSTEM_DIRS"},
+	}
+
+	includePaths = cli.StringSliceFlag{
+		Name:    "include-path",
+		Usage:   "only analyze files matching the glob patterns relative to the target (e.g. 'src/**/*.go')",
+		EnvVars: []string{"TRIVY_INCLUDE_PATH"},
+	}
+
+	excludePaths = cli.StringSliceFlag{
+		Name:    "exclude-path",
+		Usage:   "skip files and directories matching the glob patterns relative to the target (e.g. '**/testdata')",
+		EnvVars: []string{"TRIVY_EXCLUDE_PATH"},
+	}
+
+	excludePathFile = cli.StringFlag{
+		Name:    "exclude-path-file",
+		Usage:   "specify a file listing glob patterns to skip, one per line",
+		EnvVars: []string{"TRIVY_EXCLUDE_PATH_FILE"},
+	}
+
+	useGitignore = cli.BoolFlag{
+		Name:    "use-gitignore",
+		Usage:   "skip files ignored by .gitignore in the target directory",
+		EnvVars: []string{"TRIVY_USE_GITIGNORE"},
+	}
+
+	moduleDirFlag = cli.StringFlag{
+		Name:    "module-dir",
+		Usage:   "specify directory to the wasm modules that will be loaded",
+		Value:   "",
+		EnvVars: []string{"TRIVY_MODULE_DIR"},
+	}
+
+	enableModules = cli.StringSliceFlag{
+		Name:    "enable-modules",
+		Usage:   "[EXPERIMENTAL] load only the specified modules by name, e.g. spring4shell",
+		EnvVars: []string{"TRIVY_ENABLE_MODULES"},
+	}
+
+	pluginIndexURL = cli.StringFlag{
+		Name:    "index-url",
+		Usage:   "URL of the plugin index",
+		Value:   tplugin.DefaultIndexURL,
+		EnvVars: []string{"TRIVY_PLUGIN_INDEX_URL"},
+	}
+
 	offlineScan = cli.BoolFlag{
 		Name:    "offline-scan",
-		Usage:   "do not issue API requests to identify dependencies",
+		Usage:   "do not issue any network requests during the scan, implying --skip-db-update",
 		EnvVars: []string{"TRIVY_OFFLINE_SCAN"},
 	}
 
@@ -324,6 +551,54 @@ var (
 		EnvVars: []string{"TRIVY_POLICY_NAMESPACES"},
 	}
 
+	checksBundle = cli.StringSliceFlag{
+		Name:    "checks-bundle",
+		Usage:   "specify OCI references of custom check bundles (e.g. oci://ghcr.io/org/policies:1.0)",
+		EnvVars: []string{"TRIVY_CHECKS_BUNDLE"},
+	}
+
+	checksBundleKey = cli.StringFlag{
+		Name:    "checks-bundle-key",
+		Usage:   "specify a path to the cosign public key for verifying check bundles",
+		EnvVars: []string{"TRIVY_CHECKS_BUNDLE_KEY"},
+	}
+
+	checkOverrides = cli.StringFlag{
+		Name:    "check-overrides",
+		Usage:   "specify a path to the file overriding severities and metadata of misconfiguration checks",
+		EnvVars: []string{"TRIVY_CHECK_OVERRIDES"},
+	}
+
+	helmValues = cli.StringSliceFlag{
+		Name:    "helm-values",
+		Usage:   "specify paths to values files for rendering Helm charts",
+		EnvVars: []string{"TRIVY_HELM_VALUES"},
+	}
+
+	helmSet = cli.StringSliceFlag{
+		Name:    "helm-set",
+		Usage:   "specify values for rendering Helm charts (e.g. image.tag=1.0)",
+		EnvVars: []string{"TRIVY_HELM_SET"},
+	}
+
+	helmKubeVersion = cli.StringFlag{
+		Name:    "helm-kube-version",
+		Usage:   "specify the Kubernetes version for rendering Helm charts (e.g. 1.24.0)",
+		EnvVars: []string{"TRIVY_HELM_KUBE_VERSION"},
+	}
+
+	helmAPIVersions = cli.StringSliceFlag{
+		Name:    "helm-api-versions",
+		Usage:   "specify the available Kubernetes API versions for rendering Helm charts (e.g. monitoring.coreos.com/v1)",
+		EnvVars: []string{"TRIVY_HELM_API_VERSIONS"},
+	}
+
+	cfParams = cli.StringSliceFlag{
+		Name:    "cf-params",
+		Usage:   "specify paths to parameter files for CloudFormation templates",
+		EnvVars: []string{"TRIVY_CF_PARAMS"},
+	}
+
 	includeNonFailures = cli.BoolFlag{
 		Name:    "include-non-failures",
 		Usage:   "include successes and exceptions",
@@ -345,6 +620,12 @@ var (
 		EnvVars: []string{"TRIVY_INSECURE"},
 	}
 
+	registryToken = cli.StringFlag{
+		Name:    "registry-token",
+		Usage:   "bearer token sent to registries, instead of the credentials in Docker config, credential helpers and cloud keychains",
+		EnvVars: []string{"TRIVY_REGISTRY_TOKEN"},
+	}
+
 	remoteServer = cli.StringFlag{
 		Name:    "server",
 		Usage:   "server address",
@@ -357,6 +638,18 @@ var (
 		EnvVars: []string{"TRIVY_CUSTOM_HEADERS"},
 	}
 
+	advisoryFeed = cli.StringSliceFlag{
+		Name:    "advisory-feed",
+		Usage:   "specify directories or OCI references (e.g. oci://ghcr.io/org/advisories:latest) of additional advisories in OSV format",
+		EnvVars: []string{"TRIVY_ADVISORY_FEED"},
+	}
+
+	osvOnline = cli.BoolFlag{
+		Name:    "osv-online",
+		Usage:   "query the OSV API for vulnerabilities of language-specific packages not in the vulnerability DB",
+		EnvVars: []string{"TRIVY_OSV_ONLINE"},
+	}
+
 	dbRepositoryFlag = cli.StringFlag{
 		Name:    "db-repository",
 		Usage:   "OCI repository to retrieve trivy-db from",
@@ -364,6 +657,24 @@ var (
 		EnvVars: []string{"TRIVY_DB_REPOSITORY"},
 	}
 
+	dbSnapshotFlag = cli.StringFlag{
+		Name:    "db-snapshot",
+		Usage:   "scan with the DB snapshot of the digest (sha256:...) or date (YYYY-MM-DD) instead of the latest DB",
+		EnvVars: []string{"TRIVY_DB_SNAPSHOT"},
+	}
+
+	secretBaseline = cli.StringFlag{
+		Name:    "secret-baseline",
+		Usage:   "specify a path to the secret baseline file to suppress known secrets",
+		EnvVars: []string{"TRIVY_SECRET_BASELINE"},
+	}
+
+	secretBaselineOut = cli.StringFlag{
+		Name:    "secret-baseline-out",
+		Usage:   "write detected secrets to the baseline file",
+		EnvVars: []string{"TRIVY_SECRET_BASELINE_OUT"},
+	}
+
 	secretConfig = cli.StringFlag{
 		Name:    "secret-config",
 		Usage:   "specify a path to config file for secret scanning",
@@ -371,17 +682,290 @@ var (
 		EnvVars: []string{"TRIVY_SECRET_CONFIG"},
 	}
 
+	secretHistory = cli.BoolFlag{
+		Name:    "secret-history",
+		Usage:   "scan past commits in the git repository for secrets (EXPERIMENTAL)",
+		EnvVars: []string{"TRIVY_SECRET_HISTORY"},
+	}
+
+	secretHistoryDepth = cli.IntFlag{
+		Name:    "secret-history-depth",
+		Usage:   "number of commits to scan with --secret-history, 0 for all",
+		Value:   100,
+		EnvVars: []string{"TRIVY_SECRET_HISTORY_DEPTH"},
+	}
+
+	secretRedaction = cli.StringFlag{
+		Name:    "secret-redaction",
+		Usage:   "how to redact secrets in findings (full, partial, hash)",
+		Value:   "full",
+		EnvVars: []string{"TRIVY_SECRET_REDACTION"},
+	}
+
+	secretScanArchives = cli.BoolFlag{
+		Name:    "secret-scan-archives",
+		Usage:   "scan files in zip, jar and tar archives for secrets",
+		EnvVars: []string{"TRIVY_SECRET_SCAN_ARCHIVES"},
+	}
+
+	secretScanBinaries = cli.BoolFlag{
+		Name:    "secret-scan-binaries",
+		Usage:   "scan printable strings in binaries smaller than 10MB for secrets",
+		EnvVars: []string{"TRIVY_SECRET_SCAN_BINARIES"},
+	}
+
+	secretVerify = cli.BoolFlag{
+		Name:    "secret-verify",
+		Usage:   "verify whether detected secrets are live by calling provider APIs (EXPERIMENTAL)",
+		EnvVars: []string{"TRIVY_SECRET_VERIFY"},
+	}
+
+	licenseFull = cli.BoolFlag{
+		Name:    "license-full",
+		Usage:   "classify license files and headers of source files in addition to package licenses",
+		EnvVars: []string{"TRIVY_LICENSE_FULL"},
+	}
+
+	licenseForbidden = cli.StringSliceFlag{
+		Name:    "license-forbidden",
+		Usage:   "licenses classified as forbidden (CRITICAL), e.g. AGPL-3.0",
+		EnvVars: []string{"TRIVY_LICENSE_FORBIDDEN"},
+	}
+
+	licenseRestricted = cli.StringSliceFlag{
+		Name:    "license-restricted",
+		Usage:   "licenses classified as restricted (HIGH), e.g. GPL-3.0",
+		EnvVars: []string{"TRIVY_LICENSE_RESTRICTED"},
+	}
+
+	licenseAllowed = cli.StringSliceFlag{
+		Name:    "license-allowed",
+		Usage:   "licenses classified as permissive (LOW), e.g. MIT",
+		EnvVars: []string{"TRIVY_LICENSE_ALLOWED"},
+	}
+
+	licensePolicy = cli.StringFlag{
+		Name:    "license-policy",
+		Usage:   "specify the Rego file to decide whether each license is allowed, flagged or forbidden",
+		EnvVars: []string{"TRIVY_LICENSE_POLICY"},
+	}
+
 	dependencyTree = cli.BoolFlag{
 		Name:    "dependency-tree",
 		Usage:   "show dependency origin tree (EXPERIMENTAL)",
 		EnvVars: []string{"TRIVY_DEPENDENCY_TREE"},
 	}
 
+	fixAdvice = cli.BoolFlag{
+		Name:    "fix-advice",
+		Usage:   "add the minimal direct dependency upgrades remediating vulnerabilities to the report (EXPERIMENTAL)",
+		EnvVars: []string{"TRIVY_FIX_ADVICE"},
+	}
+
+	riskScore = cli.BoolFlag{
+		Name:    "risk-score",
+		Usage:   "score vulnerabilities with CVSS, EPSS, KEV and reachability, and sort them by the score (EXPERIMENTAL)",
+		EnvVars: []string{"TRIVY_RISK_SCORE"},
+	}
+
+	riskWeights = cli.StringFlag{
+		Name:    "risk-weights",
+		Usage:   "weights of the risk factors (default: cvss=0.4,epss=0.3,kev=0.2,reachability=0.1)",
+		EnvVars: []string{"TRIVY_RISK_WEIGHTS"},
+	}
+
+	epssFile = cli.StringFlag{
+		Name:    "epss-file",
+		Usage:   "EPSS scores in the CSV published by FIRST for --risk-score, optionally gzipped",
+		EnvVars: []string{"TRIVY_EPSS_FILE"},
+	}
+
+	kevFile = cli.StringFlag{
+		Name:    "kev-file",
+		Usage:   "Known Exploited Vulnerabilities catalog in the JSON published by CISA for --risk-score",
+		EnvVars: []string{"TRIVY_KEV_FILE"},
+	}
+
+	reachabilityFile = cli.StringFlag{
+		Name:    "reachability-file",
+		Usage:   "reachable vulnerabilities for --risk-score, one per line as VULN-ID or \"VULN-ID PKG-NAME\"",
+		EnvVars: []string{"TRIVY_REACHABILITY_FILE"},
+	}
+
+	diffBase = cli.StringFlag{
+		Name:    "diff-base",
+		Usage:   "only scan files changed since the specified git revision (e.g. main, HEAD~1)",
+		EnvVars: []string{"TRIVY_DIFF_BASE"},
+	}
+
+	codeownersFlag = cli.BoolFlag{
+		Name:    "codeowners",
+		Usage:   "assign findings to the owners of their files in CODEOWNERS of the scanned directory or repository",
+		EnvVars: []string{"TRIVY_CODEOWNERS"},
+	}
+
+	ownersFile = cli.StringFlag{
+		Name:    "owners-file",
+		Usage:   "specify a file mapping paths to owners in the CODEOWNERS syntax, which takes precedence over CODEOWNERS",
+		EnvVars: []string{"TRIVY_OWNERS_FILE"},
+	}
+
+	tmpDirFlag = cli.StringFlag{
+		Name:    "tmp-dir",
+		Usage:   "directory for temporary files such as large files extracted from image layers and archives (default: the system temp directory)",
+		EnvVars: []string{"TRIVY_TMP_DIR"},
+	}
+
+	tmpSizeLimitFlag = cli.StringFlag{
+		Name:    "tmp-size-limit",
+		Usage:   "limit the total size of the temporary files extracted from image layers, skipping files beyond it (e.g. 10GiB)",
+		EnvVars: []string{"TRIVY_TMP_SIZE_LIMIT"},
+	}
+
+	parallel = cli.IntFlag{
+		Name:    "parallel",
+		Usage:   "number of files analyzed and targets detected concurrently (0 means twice the number of CPUs)",
+		EnvVars: []string{"TRIVY_PARALLEL"},
+	}
+
+	imageParallel = cli.IntFlag{
+		Name:    "parallel",
+		Usage:   "number of layers and files in each layer analyzed and targets detected concurrently (0 means 5 layers and files, and twice the number of CPUs for targets)",
+		EnvVars: []string{"TRIVY_PARALLEL"},
+	}
+
+	saveHistoryFlag = cli.BoolFlag{
+		Name:    "save-history",
+		Usage:   "record the summary of the report in the local history, shown by 'trivy history'",
+		EnvVars: []string{"TRIVY_SAVE_HISTORY"},
+	}
+
+	labelFlag = cli.StringSliceFlag{
+		Name:    "label",
+		Usage:   "attach a label to the report as KEY=VALUE for aggregating reports (e.g. team=payments)",
+		EnvVars: []string{"TRIVY_LABEL"},
+	}
+
+	gatePolicyFlag = cli.StringFlag{
+		Name:    "gate-policy",
+		Usage:   "evaluate the Rego gate policy over the report and exit with --exit-code (1 by default) when denied",
+		EnvVars: []string{"TRIVY_GATE_POLICY"},
+	}
+
+	sbomDetailFlag = cli.BoolFlag{
+		Name:    "sbom-detail",
+		Usage:   "add file evidences, license evidences and hashes of packages to SBOMs (cyclonedx, spdx, spdx-json)",
+		EnvVars: []string{"TRIVY_SBOM_DETAIL"},
+	}
+
+	dryRunFlag = cli.BoolFlag{
+		Name:    "dry-run",
+		Usage:   "print the resolved options, analyzers, DB and skipped paths without scanning",
+		EnvVars: []string{"TRIVY_DRY_RUN"},
+	}
+
+	watchFlag = cli.BoolFlag{
+		Name:    "watch",
+		Usage:   "keep running and rescan changed files, writing the reports as JSON Lines (EXPERIMENTAL)",
+		EnvVars: []string{"TRIVY_WATCH"},
+	}
+
+	watchInterval = cli.DurationFlag{
+		Name:    "watch-interval",
+		Usage:   "interval between checks for changed files in watch mode",
+		Value:   2 * time.Second,
+		EnvVars: []string{"TRIVY_WATCH_INTERVAL"},
+	}
+
+	// For repository scanning
+	repoBranch = cli.StringFlag{
+		Name:    "branch",
+		Usage:   "pass the branch name to be scanned",
+		EnvVars: []string{"TRIVY_BRANCH"},
+	}
+
+	repoTag = cli.StringFlag{
+		Name:    "tag",
+		Usage:   "pass the tag name to be scanned",
+		EnvVars: []string{"TRIVY_TAG"},
+	}
+
+	repoCommit = cli.StringFlag{
+		Name:    "commit",
+		Usage:   "pass the commit hash to be scanned",
+		EnvVars: []string{"TRIVY_COMMIT"},
+	}
+
+	repoDepth = cli.IntFlag{
+		Name:    "depth",
+		Value:   1,
+		Usage:   "number of commits to be cloned, 0 for the full history",
+		EnvVars: []string{"TRIVY_DEPTH"},
+	}
+
+	gitUsername = cli.StringFlag{
+		Name:    "git-username",
+		Usage:   "username for --git-token, required by some services such as Bitbucket",
+		EnvVars: []string{"TRIVY_GIT_USERNAME"},
+	}
+
+	gitToken = cli.StringFlag{
+		Name:    "git-token",
+		Usage:   "token to clone private repositories over HTTP(S), taking precedence over GITHUB_TOKEN and GITLAB_TOKEN",
+		EnvVars: []string{"TRIVY_GIT_TOKEN"},
+	}
+
+	logFormatFlag = cli.StringFlag{
+		Name:    "log-format",
+		Value:   log.FormatConsole,
+		Usage:   "log format (console, json)",
+		EnvVars: []string{"TRIVY_LOG_FORMAT"},
+	}
+
+	configFileFlag = cli.StringFlag{
+		Name:    "config",
+		Value:   option.DefaultConfigFile,
+		Usage:   "config file path",
+		EnvVars: []string{"TRIVY_CONFIG"},
+	}
+
+	profileFlag = cli.StringFlag{
+		Name:    "profile",
+		Usage:   "profile in the config file overriding severities, security checks, ignore file and output format",
+		EnvVars: []string{"TRIVY_PROFILE"},
+	}
+
+	caBundleFlag = cli.StringFlag{
+		Name:    "ca-bundle",
+		Usage:   "PEM file of CA certificates trusted in addition to the system ones for every outbound HTTPS request",
+		EnvVars: []string{"TRIVY_CA_BUNDLE"},
+	}
+
+	retriesFlag = cli.IntFlag{
+		Name:    "retries",
+		Value:   retry.DefaultRetries,
+		Usage:   "number of retries of registry pulls, DB downloads and RPC calls failing with transient network errors (RPC calls are retried 10 times unless specified)",
+		EnvVars: []string{"TRIVY_RETRIES"},
+	}
+
+	retryBackoffFlag = cli.DurationFlag{
+		Name:    "retry-backoff",
+		Value:   retry.DefaultBackoff,
+		Usage:   "initial backoff between retries, doubling on each retry with jitter",
+		EnvVars: []string{"TRIVY_RETRY_BACKOFF"},
+	}
+
 	// Global flags
 	globalFlags = []cli.Flag{
 		&quietFlag,
 		&debugFlag,
 		&cacheDirFlag,
+		&logFormatFlag,
+		&configFileFlag,
+		&profileFlag,
+		&caBundleFlag,
+		&retriesFlag,
+		&retryBackoffFlag,
 	}
 )
 
@@ -423,15 +1007,33 @@ func NewApp(version string) *cli.App {
 		NewConfigCommand(),
 		NewPluginCommand(),
 		NewModuleCommand(),
+		NewCheckCommand(),
+		NewHistoryCommand(),
+		NewAnalyzersCommand(),
 		NewK8sCommand(),
 		NewSbomCommand(),
+		NewLookupCommand(),
+		NewFixCommand(),
+		NewGateCommand(),
+		NewLSPCommand(),
 		NewVersionCommand(),
 	}
+	setConfigFile(app.Commands)
 	app.Commands = append(app.Commands, plugin.LoadCommands()...)
 
 	return app
 }
 
+// setConfigFile makes commands load trivy.yaml before running
+func setConfigFile(commands []*cli.Command) {
+	for _, cmd := range commands {
+		setConfigFile(cmd.Subcommands)
+		if cmd.Action != nil && cmd.Before == nil {
+			cmd.Before = option.ApplyConfigFile
+		}
+	}
+}
+
 func showVersion(cacheDir, outputFormat, version string, outputWriter io.Writer) {
 	var dbMeta *metadata.Metadata
 
@@ -479,35 +1081,96 @@ func NewImageCommand() *cli.Command {
 			&templateFlag,
 			&formatFlag,
 			&inputFlag,
+			&platformFlag,
+			&imageReportFlag,
+			&baseImageIndexFlag,
+			&ignoreBaseImageVulnsFlag,
+			&recommendBaseImageFlag,
+			&verifyProvenanceFlag,
+			&provenanceKeyFlag,
+			stringSliceFlag(provenanceBuilderFlag),
+			stringSliceFlag(provenanceSourceFlag),
+			&verifySignatureFlag,
+			&signatureKeyFlag,
+			&signatureCertFlag,
 			&severityFlag,
 			&outputFlag,
 			&exitCodeFlag,
+			&failFastFlag,
+			&dryRunFlag,
+			&saveHistoryFlag,
+			stringSliceFlag(labelFlag),
+			&gatePolicyFlag,
+			&sbomDetailFlag,
 			&skipDBUpdateFlag,
 			&downloadDBOnlyFlag,
 			&resetFlag,
 			&clearCacheFlag,
 			&noProgressFlag,
+			&quietProgressFlag,
 			&ignoreUnfixedFlag,
 			&removedPkgsFlag,
 			&vulnTypeFlag,
 			&securityChecksFlag,
 			&ignoreFileFlag,
 			&timeoutFlag,
+			&dbTimeoutFlag,
+			&pullTimeoutFlag,
+			&analysisTimeoutFlag,
+			&targetTimeoutFlag,
+			&continueOnErrorFlag,
+			&policyTimeoutFlag,
+			&tmpDirFlag,
+			&tmpSizeLimitFlag,
+			&imageParallel,
 			&lightFlag,
 			&ignorePolicy,
 			&listAllPackages,
+			&includeDevDeps,
 			&cacheBackendFlag,
 			&cacheTTL,
 			&redisBackendCACert,
 			&redisBackendCert,
 			&redisBackendKey,
 			&offlineScan,
+			&moduleDirFlag,
+			stringSliceFlag(enableModules),
 			&insecureFlag,
+			&registryToken,
 			&dbRepositoryFlag,
+			&dbSnapshotFlag,
+			stringSliceFlag(advisoryFeed),
+			&osvOnline,
 			&secretConfig,
+			&secretRedaction,
+			&secretVerify,
+			&secretScanArchives,
+			&secretScanBinaries,
+			&licenseFull,
+			stringSliceFlag(licenseForbidden),
+			stringSliceFlag(licenseRestricted),
+			stringSliceFlag(licenseAllowed),
+			&licensePolicy,
 			&dependencyTree,
+			&fixAdvice,
+			&riskScore,
+			&riskWeights,
+			&epssFile,
+			&kevFile,
+			&reachabilityFile,
 			stringSliceFlag(skipFiles),
 			stringSliceFlag(skipDirs),
+			stringSliceFlag(enableAnalyzers),
+			stringSliceFlag(disableAnalyzers),
+			stringSliceFlag(maxFileSize),
+
+			// for misconfiguration
+			stringSliceFlag(configPolicy),
+			stringSliceFlag(configData),
+			stringSliceFlag(policyNamespaces),
+			stringSliceFlag(checksBundle),
+			&checksBundleKey,
+			&checkOverrides,
 
 			// for client/server
 			&remoteServer,
@@ -532,6 +1195,12 @@ func NewFilesystemCommand() *cli.Command {
 			&severityFlag,
 			&outputFlag,
 			&exitCodeFlag,
+			&failFastFlag,
+			&dryRunFlag,
+			&saveHistoryFlag,
+			stringSliceFlag(labelFlag),
+			&gatePolicyFlag,
+			&sbomDetailFlag,
 			&skipDBUpdateFlag,
 			&skipPolicyUpdateFlag,
 			&insecureFlag,
@@ -546,20 +1215,78 @@ func NewFilesystemCommand() *cli.Command {
 			&redisBackendCert,
 			&redisBackendKey,
 			&timeoutFlag,
+			&dbTimeoutFlag,
+			&analysisTimeoutFlag,
+			&targetTimeoutFlag,
+			&continueOnErrorFlag,
+			&policyTimeoutFlag,
 			&noProgressFlag,
+			&quietProgressFlag,
 			&ignorePolicy,
 			&listAllPackages,
+			&includeDevDeps,
 			&offlineScan,
+			&moduleDirFlag,
+			stringSliceFlag(enableModules),
 			&dbRepositoryFlag,
+			&dbSnapshotFlag,
+			stringSliceFlag(advisoryFeed),
+			&osvOnline,
 			&secretConfig,
+			&secretRedaction,
+			&secretVerify,
+			&secretScanArchives,
+			&secretScanBinaries,
+			&secretBaseline,
+			&secretBaselineOut,
+			&secretHistory,
+			&secretHistoryDepth,
+			&licenseFull,
+			stringSliceFlag(licenseForbidden),
+			stringSliceFlag(licenseRestricted),
+			stringSliceFlag(licenseAllowed),
+			&licensePolicy,
 			&dependencyTree,
+			&fixAdvice,
+			&riskScore,
+			&riskWeights,
+			&epssFile,
+			&kevFile,
+			&reachabilityFile,
+			&diffBase,
+			&codeownersFlag,
+			&ownersFile,
+			&watchFlag,
+			&watchInterval,
+			&parallel,
 			stringSliceFlag(skipFiles),
 			stringSliceFlag(skipDirs),
+			stringSliceFlag(enableAnalyzers),
+			stringSliceFlag(disableAnalyzers),
+			stringSliceFlag(maxFileSize),
+			&scanArchives,
+			&archiveDepth,
+			&archiveSizeLimit,
+			&tmpDirFlag,
+			&followSymlinks,
+			&includeSystemDirs,
+			stringSliceFlag(includePaths),
+			stringSliceFlag(excludePaths),
+			&excludePathFile,
+			&useGitignore,
 
 			// for misconfiguration
 			stringSliceFlag(configPolicy),
 			stringSliceFlag(configData),
 			stringSliceFlag(policyNamespaces),
+			stringSliceFlag(checksBundle),
+			&checksBundleKey,
+			&checkOverrides,
+			stringSliceFlag(helmValues),
+			stringSliceFlag(helmSet),
+			&helmKubeVersion,
+			stringSliceFlag(helmAPIVersions),
+			stringSliceFlag(cfParams),
 
 			// for client/server
 			&remoteServer,
@@ -583,6 +1310,12 @@ func NewRootfsCommand() *cli.Command {
 			&severityFlag,
 			&outputFlag,
 			&exitCodeFlag,
+			&failFastFlag,
+			&dryRunFlag,
+			&saveHistoryFlag,
+			stringSliceFlag(labelFlag),
+			&gatePolicyFlag,
+			&sbomDetailFlag,
 			&skipDBUpdateFlag,
 			&insecureFlag,
 			&skipPolicyUpdateFlag,
@@ -597,18 +1330,63 @@ func NewRootfsCommand() *cli.Command {
 			&redisBackendCert,
 			&redisBackendKey,
 			&timeoutFlag,
+			&dbTimeoutFlag,
+			&analysisTimeoutFlag,
+			&targetTimeoutFlag,
+			&continueOnErrorFlag,
+			&policyTimeoutFlag,
 			&noProgressFlag,
+			&quietProgressFlag,
 			&ignorePolicy,
 			&listAllPackages,
+			&includeDevDeps,
 			&offlineScan,
+			&moduleDirFlag,
+			stringSliceFlag(enableModules),
 			&dbRepositoryFlag,
+			&dbSnapshotFlag,
+			stringSliceFlag(advisoryFeed),
+			&osvOnline,
 			&secretConfig,
+			&secretRedaction,
+			&secretVerify,
+			&secretScanArchives,
+			&secretScanBinaries,
+			&secretBaseline,
+			&secretBaselineOut,
+			&licenseFull,
+			stringSliceFlag(licenseForbidden),
+			stringSliceFlag(licenseRestricted),
+			stringSliceFlag(licenseAllowed),
+			&licensePolicy,
 			&dependencyTree,
+			&fixAdvice,
+			&riskScore,
+			&riskWeights,
+			&epssFile,
+			&kevFile,
+			&reachabilityFile,
+			&parallel,
 			stringSliceFlag(skipFiles),
 			stringSliceFlag(skipDirs),
+			stringSliceFlag(enableAnalyzers),
+			stringSliceFlag(disableAnalyzers),
+			stringSliceFlag(maxFileSize),
+			&scanArchives,
+			&archiveDepth,
+			&archiveSizeLimit,
+			&tmpDirFlag,
+			&followSymlinks,
+			&includeSystemDirs,
+			stringSliceFlag(includePaths),
+			stringSliceFlag(excludePaths),
+			&excludePathFile,
 			stringSliceFlag(configPolicy),
 			stringSliceFlag(configData),
 			stringSliceFlag(policyNamespaces),
+			stringSliceFlag(checksBundle),
+			&checksBundleKey,
+			&checkOverrides,
 		},
 	}
 }
@@ -628,6 +1406,12 @@ func NewRepositoryCommand() *cli.Command {
 			&severityFlag,
 			&outputFlag,
 			&exitCodeFlag,
+			&failFastFlag,
+			&dryRunFlag,
+			&saveHistoryFlag,
+			stringSliceFlag(labelFlag),
+			&gatePolicyFlag,
+			&sbomDetailFlag,
 			&skipDBUpdateFlag,
 			&skipPolicyUpdateFlag,
 			&clearCacheFlag,
@@ -642,17 +1426,85 @@ func NewRepositoryCommand() *cli.Command {
 			&redisBackendCert,
 			&redisBackendKey,
 			&timeoutFlag,
+			&dbTimeoutFlag,
+			&pullTimeoutFlag,
+			&analysisTimeoutFlag,
+			&targetTimeoutFlag,
+			&continueOnErrorFlag,
+			&policyTimeoutFlag,
 			&noProgressFlag,
+			&quietProgressFlag,
 			&quietFlag,
 			&ignorePolicy,
 			&listAllPackages,
+			&includeDevDeps,
 			&offlineScan,
+			&moduleDirFlag,
+			stringSliceFlag(enableModules),
 			&insecureFlag,
 			&dbRepositoryFlag,
+			&dbSnapshotFlag,
+			stringSliceFlag(advisoryFeed),
+			&osvOnline,
 			&secretConfig,
+			&secretRedaction,
+			&secretVerify,
+			&secretScanArchives,
+			&secretScanBinaries,
+			&secretBaseline,
+			&secretBaselineOut,
+			&secretHistory,
+			&secretHistoryDepth,
+			&licenseFull,
+			stringSliceFlag(licenseForbidden),
+			stringSliceFlag(licenseRestricted),
+			stringSliceFlag(licenseAllowed),
+			&licensePolicy,
 			&dependencyTree,
+			&fixAdvice,
+			&riskScore,
+			&riskWeights,
+			&epssFile,
+			&kevFile,
+			&reachabilityFile,
+			&parallel,
 			stringSliceFlag(skipFiles),
 			stringSliceFlag(skipDirs),
+			stringSliceFlag(enableAnalyzers),
+			stringSliceFlag(disableAnalyzers),
+			stringSliceFlag(maxFileSize),
+			&scanArchives,
+			&archiveDepth,
+			&archiveSizeLimit,
+			&tmpDirFlag,
+			stringSliceFlag(includePaths),
+			stringSliceFlag(excludePaths),
+			&excludePathFile,
+			&useGitignore,
+
+			// for misconfiguration
+			stringSliceFlag(configPolicy),
+			stringSliceFlag(configData),
+			stringSliceFlag(policyNamespaces),
+			stringSliceFlag(checksBundle),
+			&checksBundleKey,
+			&checkOverrides,
+			stringSliceFlag(helmValues),
+			stringSliceFlag(helmSet),
+			&helmKubeVersion,
+			stringSliceFlag(helmAPIVersions),
+			stringSliceFlag(cfParams),
+
+			// for repository
+			&diffBase,
+			&codeownersFlag,
+			&ownersFile,
+			&repoBranch,
+			&repoTag,
+			&repoCommit,
+			&repoDepth,
+			&gitUsername,
+			&gitToken,
 		},
 	}
 }
@@ -673,6 +1525,7 @@ func NewClientCommand() *cli.Command {
 			&templateFlag,
 			&formatFlag,
 			&inputFlag,
+			&platformFlag,
 			&severityFlag,
 			&outputFlag,
 			&exitCodeFlag,
@@ -682,16 +1535,43 @@ func NewClientCommand() *cli.Command {
 			&securityChecksFlag,
 			&ignoreFileFlag,
 			&timeoutFlag,
+			&pullTimeoutFlag,
+			&analysisTimeoutFlag,
+			&targetTimeoutFlag,
+			&continueOnErrorFlag,
+			&policyTimeoutFlag,
 			&noProgressFlag,
+			&quietProgressFlag,
 			&ignorePolicy,
 			stringSliceFlag(skipFiles),
 			stringSliceFlag(skipDirs),
+			stringSliceFlag(enableAnalyzers),
+			stringSliceFlag(disableAnalyzers),
 			stringSliceFlag(configPolicy),
+			stringSliceFlag(configData),
+			stringSliceFlag(policyNamespaces),
+			stringSliceFlag(checksBundle),
+			&checksBundleKey,
+			&checkOverrides,
 			&listAllPackages,
+			&includeDevDeps,
 			&offlineScan,
+			&moduleDirFlag,
+			stringSliceFlag(enableModules),
 			&insecureFlag,
+			&registryToken,
 			&secretConfig,
+			&secretRedaction,
+			&secretVerify,
+			&secretScanArchives,
+			&secretScanBinaries,
 			&dependencyTree,
+			&fixAdvice,
+			&riskScore,
+			&riskWeights,
+			&epssFile,
+			&kevFile,
+			&reachabilityFile,
 
 			&token,
 			&tokenHeader,
@@ -726,6 +1606,10 @@ func NewServerCommand() *cli.Command {
 			&redisBackendCert,
 			&redisBackendKey,
 			&dbRepositoryFlag,
+			stringSliceFlag(advisoryFeed),
+			&osvOnline,
+			&moduleDirFlag,
+			stringSliceFlag(enableModules),
 
 			// original flags
 			&token,
@@ -754,17 +1638,43 @@ func NewConfigCommand() *cli.Command {
 			&severityFlag,
 			&outputFlag,
 			&exitCodeFlag,
+			&failFastFlag,
+			&dryRunFlag,
+			&saveHistoryFlag,
+			stringSliceFlag(labelFlag),
+			&gatePolicyFlag,
 			&skipPolicyUpdateFlag,
 			&resetFlag,
 			&clearCacheFlag,
 			&ignoreFileFlag,
 			&timeoutFlag,
+			&analysisTimeoutFlag,
+			&targetTimeoutFlag,
+			&continueOnErrorFlag,
+			&policyTimeoutFlag,
+			&parallel,
 			stringSliceFlag(skipFiles),
 			stringSliceFlag(skipDirs),
+			stringSliceFlag(enableAnalyzers),
+			stringSliceFlag(disableAnalyzers),
+			stringSliceFlag(includePaths),
+			stringSliceFlag(excludePaths),
+			&excludePathFile,
+			&useGitignore,
+			&moduleDirFlag,
+			stringSliceFlag(enableModules),
 			stringSliceFlag(configPolicyAlias),
 			stringSliceFlag(configDataAlias),
 			stringSliceFlag(policyNamespaces),
+			stringSliceFlag(checksBundle),
+			&checksBundleKey,
+			&checkOverrides,
 			stringSliceFlag(filePatterns),
+			stringSliceFlag(helmValues),
+			stringSliceFlag(helmSet),
+			&helmKubeVersion,
+			stringSliceFlag(helmAPIVersions),
+			stringSliceFlag(cfParams),
 			&includeNonFailures,
 			&traceFlag,
 		},
@@ -783,7 +1693,7 @@ func NewPluginCommand() *cli.Command {
 				Name:      "install",
 				Aliases:   []string{"i"},
 				Usage:     "install a plugin",
-				ArgsUsage: "URL | FILE_PATH",
+				ArgsUsage: "NAME | URL | FILE_PATH",
 				Action:    plugin.Install,
 			},
 			{
@@ -812,11 +1722,19 @@ func NewPluginCommand() *cli.Command {
 				ArgsUsage: "PLUGIN_NAME [PLUGIN_OPTIONS]",
 				Action:    plugin.Run,
 			},
+			{
+				Name:      "search",
+				Usage:     "search the plugin index for plugins",
+				ArgsUsage: "[KEYWORD]",
+				Action:    plugin.Search,
+				Flags:     []cli.Flag{&pluginIndexURL},
+			},
 			{
 				Name:      "update",
-				Usage:     "update an existing plugin",
-				ArgsUsage: "PLUGIN_NAME",
+				Usage:     "update the plugin index, or an existing plugin if specified",
+				ArgsUsage: "[PLUGIN_NAME]",
 				Action:    plugin.Update,
+				Flags:     []cli.Flag{&pluginIndexURL},
 			},
 		},
 	}
@@ -835,6 +1753,7 @@ func NewModuleCommand() *cli.Command {
 				Usage:     "install a module",
 				ArgsUsage: "REPOSITORY",
 				Action:    module.Install,
+				Flags:     []cli.Flag{&moduleDirFlag},
 			},
 			{
 				Name:      "uninstall",
@@ -842,6 +1761,14 @@ func NewModuleCommand() *cli.Command {
 				Usage:     "uninstall a module",
 				ArgsUsage: "REPOSITORY",
 				Action:    module.Uninstall,
+				Flags:     []cli.Flag{&moduleDirFlag},
+			},
+			{
+				Name:    "list",
+				Aliases: []string{"l"},
+				Usage:   "list installed modules",
+				Action:  module.List,
+				Flags:   []cli.Flag{&moduleDirFlag},
 			},
 		},
 	}
@@ -879,6 +1806,7 @@ func NewK8sCommand() *cli.Command {
 			&outputFlag,
 			&severityFlag,
 			&exitCodeFlag,
+			&failFastFlag,
 			&skipDBUpdateFlag,
 			&insecureFlag,
 			&skipPolicyUpdateFlag,
@@ -893,19 +1821,40 @@ func NewK8sCommand() *cli.Command {
 			&redisBackendCert,
 			&redisBackendKey,
 			&timeoutFlag,
+			&dbTimeoutFlag,
+			&pullTimeoutFlag,
+			&analysisTimeoutFlag,
+			&targetTimeoutFlag,
+			&continueOnErrorFlag,
+			&policyTimeoutFlag,
 			&noProgressFlag,
 			&ignorePolicy,
 			&listAllPackages,
+			&includeDevDeps,
 			&offlineScan,
+			&moduleDirFlag,
+			stringSliceFlag(enableModules),
 			&dbRepositoryFlag,
+			&dbSnapshotFlag,
+			stringSliceFlag(advisoryFeed),
+			&osvOnline,
 			&secretConfig,
+			&secretRedaction,
+			&secretVerify,
+			&secretScanArchives,
+			&secretScanBinaries,
 			stringSliceFlag(skipFiles),
 			stringSliceFlag(skipDirs),
+			stringSliceFlag(enableAnalyzers),
+			stringSliceFlag(disableAnalyzers),
 
 			// for misconfiguration
 			stringSliceFlag(configPolicy),
 			stringSliceFlag(configData),
 			stringSliceFlag(policyNamespaces),
+			stringSliceFlag(checksBundle),
+			&checksBundleKey,
+			&checkOverrides,
 		},
 	}
 }
@@ -930,8 +1879,115 @@ func NewSbomCommand() *cli.Command {
   - image archive scanning:
       $ trivy sbom --artifact-type archive ./alpine.tar
 
+  - SBOM scanning:
+      $ trivy sbom scan /path/to/bom.cdx.json
+
+  - Merging SBOMs:
+      $ trivy sbom merge --format cyclonedx build.cdx.json runtime.spdx.json
+
 `,
 		Action: artifact.SbomRun,
+		Subcommands: cli.Commands{
+			{
+				Name:        "scan",
+				Usage:       "scan SBOM for vulnerabilities",
+				ArgsUsage:   "SBOM",
+				Description: `SBOM is a CycloneDX (JSON/XML) or SPDX (JSON/tag-value) file. See examples.`,
+				CustomHelpTemplate: cli.CommandHelpTemplate + `EXAMPLES:
+  - CycloneDX scanning:
+      $ trivy sbom scan /path/to/bom.cdx.json
+
+  - SPDX scanning:
+      $ trivy sbom scan /path/to/bom.spdx
+
+  - Scanning an SBOM attestation downloaded by cosign:
+      $ cosign download attestation alpine:3.16 > attestation.json
+      $ trivy sbom scan --attestation-key cosign.pub attestation.json
+
+`,
+				Action: artifact.SbomScanRun,
+				Flags: []cli.Flag{
+					&templateFlag,
+					&formatFlag,
+					&severityFlag,
+					&outputFlag,
+					&exitCodeFlag,
+					&failFastFlag,
+					stringSliceFlag(labelFlag),
+					&gatePolicyFlag,
+					&skipDBUpdateFlag,
+					&clearCacheFlag,
+					&ignoreUnfixedFlag,
+					&vulnTypeFlag,
+					&ignoreFileFlag,
+					&timeoutFlag,
+					&dbTimeoutFlag,
+					&analysisTimeoutFlag,
+					&targetTimeoutFlag,
+					&continueOnErrorFlag,
+					&noProgressFlag,
+					&ignorePolicy,
+					&listAllPackages,
+					&includeDevDeps,
+					&cacheBackendFlag,
+					&cacheTTL,
+					&redisBackendCACert,
+					&redisBackendCert,
+					&redisBackendKey,
+					&offlineScan,
+					&moduleDirFlag,
+					stringSliceFlag(enableModules),
+					&dbRepositoryFlag,
+					&dbSnapshotFlag,
+					stringSliceFlag(advisoryFeed),
+					&osvOnline,
+					&insecureFlag,
+					&riskScore,
+					&riskWeights,
+					&epssFile,
+					&kevFile,
+					&reachabilityFile,
+					stringSliceFlag(skipFiles),
+					stringSliceFlag(skipDirs),
+					stringSliceFlag(enableAnalyzers),
+					stringSliceFlag(disableAnalyzers),
+					&cli.StringFlag{
+						Name:    "attestation-key",
+						Usage:   "public key file to verify the signature of SBOM attestations",
+						EnvVars: []string{"TRIVY_ATTESTATION_KEY"},
+					},
+					&cli.BoolFlag{
+						Name:    "insecure-skip-attestation-verify",
+						Usage:   "accept SBOM attestations without verifying the signature and the subject, e.g. those verified by cosign",
+						EnvVars: []string{"TRIVY_INSECURE_SKIP_ATTESTATION_VERIFY"},
+					},
+
+					// for client/server
+					&remoteServer,
+					&token,
+					&tokenHeader,
+					&customHeaders,
+				},
+			},
+			{
+				Name:      "merge",
+				Usage:     "merge SBOMs into one, deduplicating packages by PURL",
+				ArgsUsage: "SBOM SBOM...",
+				Description: `SBOMs are CycloneDX (JSON/XML) or SPDX (JSON/tag-value) files of the same operating system,
+e.g. SBOMs produced in different build stages. Libraries stay under their applications, such as lock files.`,
+				Action: artifact.SbomMergeRun,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "format",
+						Aliases: []string{"f"},
+						Value:   report.FormatCycloneDX,
+						Usage:   "format (cyclonedx, spdx, spdx-json)",
+						EnvVars: []string{"TRIVY_FORMAT"},
+					},
+					&outputFlag,
+				},
+			},
+		},
 		Flags: []cli.Flag{
 			&outputFlag,
 			&clearCacheFlag,
@@ -963,6 +2019,322 @@ func NewSbomCommand() *cli.Command {
 	}
 }
 
+// NewHistoryCommand is the factory method to add history subcommands
+func NewHistoryCommand() *cli.Command {
+	limitFlag := &cli.IntFlag{
+		Name:    "limit",
+		Value:   10,
+		Usage:   "number of the latest scans to be shown (0 means all)",
+		EnvVars: []string{"TRIVY_LIMIT"},
+	}
+
+	return &cli.Command{
+		Name:  "history",
+		Usage: "show the local history of scans recorded with --save-history",
+		Subcommands: cli.Commands{
+			{
+				Name:      "list",
+				Aliases:   []string{"ls"},
+				Usage:     "list the recorded scans of the artifact, or the latest scan of each artifact",
+				ArgsUsage: "[ARTIFACT]",
+				Action:    history.List,
+				Flags:     []cli.Flag{limitFlag},
+			},
+			{
+				Name:      "diff",
+				Usage:     "show the trend of findings of the artifact and the changes since the previous scan",
+				ArgsUsage: "ARTIFACT",
+				CustomHelpTemplate: cli.CommandHelpTemplate + `EXAMPLES:
+  - Record scans and show the trend:
+      $ trivy image --save-history alpine:3.16
+      $ trivy history diff alpine:3.16
+
+`,
+				Action: history.Diff,
+				Flags:  []cli.Flag{limitFlag},
+			},
+		},
+	}
+}
+
+// NewAnalyzersCommand is the factory method to add analyzers subcommands
+func NewAnalyzersCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "analyzers",
+		Usage: "manage analyzers selected with --enable-analyzers and --disable-analyzers",
+		Subcommands: cli.Commands{
+			{
+				Name:    "list",
+				Aliases: []string{"ls"},
+				Usage:   "list the analyzers",
+				CustomHelpTemplate: cli.CommandHelpTemplate + `EXAMPLES:
+  - Scan only Python dependencies:
+      $ trivy fs --enable-analyzers pip,pipenv,poetry,python-pkg /path/to/project
+
+`,
+				Action: artifact.AnalyzersList,
+			},
+		},
+	}
+}
+
+// NewCheckCommand is the factory method to add check subcommands
+func NewCheckCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "check",
+		Usage: "manage custom misconfiguration checks",
+		Subcommands: cli.Commands{
+			{
+				Name:      "test",
+				Aliases:   []string{"t"},
+				Usage:     "run the tests of custom Rego checks",
+				ArgsUsage: "DIR...",
+				Description: `Rules prefixed with "test_" in Rego files under DIR are run like "opa test".
+Checks annotated with Trivy input schemas, e.g. schema["dockerfile"] and schema["kubernetes"], are type-checked.`,
+				CustomHelpTemplate: cli.CommandHelpTemplate + `EXAMPLES:
+  - Run all the tests:
+      $ trivy check test ./policy
+
+  - Run the tests matching the pattern with traces of failures:
+      $ trivy check test --run 'test_deny_.*' --verbose ./policy
+
+`,
+				Action: check.Test,
+				Flags: []cli.Flag{
+					stringSliceFlag(configDataAlias),
+					&cli.StringFlag{
+						Name:    "run",
+						Aliases: []string{"r"},
+						Usage:   "run only tests matching the regular expression",
+						EnvVars: []string{"TRIVY_RUN"},
+					},
+					&cli.StringFlag{
+						Name:    "format",
+						Aliases: []string{"f"},
+						Value:   "pretty",
+						Usage:   "format (pretty, json)",
+						EnvVars: []string{"TRIVY_FORMAT"},
+					},
+					&cli.BoolFlag{
+						Name:    "verbose",
+						Aliases: []string{"v"},
+						Usage:   "show all the test results and traces of failures",
+						EnvVars: []string{"TRIVY_VERBOSE"},
+					},
+					&timeoutFlag,
+				},
+			},
+			{
+				Name:      "init",
+				Aliases:   []string{"i"},
+				Usage:     "generate a skeleton custom check and its test",
+				ArgsUsage: "[DIR]",
+				Description: `A check and a test are generated in DIR (the current directory by default), e.g. "id001.rego" and "id001_test.rego".
+The check has the metadata, the input selector and the schema annotation of the type, and the test passes as it is.`,
+				CustomHelpTemplate: cli.CommandHelpTemplate + `EXAMPLES:
+  - Generate a Dockerfile check:
+      $ trivy check init --type dockerfile --id ID001 --title "Non-root user" --severity HIGH ./policy
+
+`,
+				Action: check.Init,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "type",
+						Usage:    "input type (dockerfile, json, kubernetes, yaml)",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:     "id",
+						Usage:    "check ID, e.g. ID001",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:  "title",
+						Usage: "check title",
+					},
+					&cli.StringFlag{
+						Name:  "severity",
+						Value: dbTypes.SeverityMedium.String(),
+						Usage: "severity (UNKNOWN, LOW, MEDIUM, HIGH, CRITICAL)",
+					},
+					&cli.StringFlag{
+						Name:  "namespace",
+						Value: "user",
+						Usage: "package prefix, which is passed to --namespaces when scanning",
+					},
+				},
+			},
+		},
+	}
+}
+
+// NewLookupCommand is the factory method to add lookup subcommand
+func NewLookupCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "lookup",
+		ArgsUsage: "PURL",
+		Usage:     "look up vulnerabilities of a package version in the vulnerability DB",
+		Description: `PURL is a Package URL such as "pkg:npm/lodash@4.17.20".
+OS packages need the "distro" qualifier, e.g. "pkg:deb/debian/openssl@1.1.1n-0+deb11u1?distro=debian-11".
+CPE is not supported since the vulnerability DB is indexed by package ecosystems.`,
+		CustomHelpTemplate: cli.CommandHelpTemplate + `EXAMPLES:
+  - Look up an npm package:
+      $ trivy lookup pkg:npm/lodash@4.17.20
+
+  - Look up a Debian package in JSON:
+      $ trivy lookup --format json 'pkg:deb/debian/openssl@1.1.1n-0+deb11u1?distro=debian-11'
+
+`,
+		Action: artifact.LookupRun,
+		Flags: []cli.Flag{
+			&templateFlag,
+			&formatFlag,
+			&severityFlag,
+			&outputFlag,
+			&exitCodeFlag,
+			&skipDBUpdateFlag,
+			&ignoreUnfixedFlag,
+			&ignoreFileFlag,
+			&dbTimeoutFlag,
+			&noProgressFlag,
+			&ignorePolicy,
+			&offlineScan,
+			&dbRepositoryFlag,
+			&dbSnapshotFlag,
+			stringSliceFlag(advisoryFeed),
+			&osvOnline,
+			&insecureFlag,
+		},
+	}
+}
+
+// NewFixCommand is the factory method to add fix subcommand
+func NewFixCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "fix",
+		ArgsUsage:   "REPORT",
+		Usage:       "rewrite lock files to the fixed versions of vulnerabilities (EXPERIMENTAL)",
+		Description: `REPORT is a JSON report generated by scanning a directory with "trivy fs" or "trivy repo".`,
+		CustomHelpTemplate: cli.CommandHelpTemplate + `EXAMPLES:
+  - Fix all the vulnerabilities:
+      $ trivy fs --format json --output result.json /path/to/project
+      $ trivy fix result.json
+
+  - Fix only critical vulnerabilities:
+      $ trivy fix --severity CRITICAL result.json
+
+`,
+		Action: fix.Run,
+		Flags: []cli.Flag{
+			&severityFlag,
+			&cli.StringSliceFlag{
+				Name:    "vuln-id",
+				Usage:   "IDs of vulnerabilities to be fixed, e.g. CVE-2021-44906 (all by default)",
+				EnvVars: []string{"TRIVY_VULN_ID"},
+			},
+			&cli.StringFlag{
+				Name:    "dir",
+				Usage:   "directory containing lock files (the scanned directory in the report by default)",
+				EnvVars: []string{"TRIVY_DIR"},
+			},
+		},
+	}
+}
+
+// NewGateCommand is the factory method to add gate subcommand
+func NewGateCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "gate",
+		ArgsUsage: "REPORT",
+		Usage:     "decide whether a report passes with a Rego policy",
+		Description: `REPORT is a JSON report generated by "trivy --format json".
+The policy defines "deny" and "warn" as sets of messages in the "trivy.gate" package, taking the report as input.
+The gate fails when any message is denied, and warnings are only printed.`,
+		CustomHelpTemplate: cli.CommandHelpTemplate + `EXAMPLES:
+  - Evaluate the gate policy over a report:
+      $ trivy image --format json --output result.json alpine:3.15
+      $ trivy gate --policy gate.rego result.json
+
+  - Evaluate the gate policy right after a scan:
+      $ trivy image --gate-policy gate.rego alpine:3.15
+
+`,
+		Action: gate.Run,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "policy",
+				Usage:    "Rego policy deciding whether the report passes",
+				EnvVars:  []string{"TRIVY_POLICY"},
+				Required: true,
+			},
+			&cli.IntFlag{
+				Name:    "exit-code",
+				Usage:   "Exit code when the gate fails",
+				Value:   1,
+				EnvVars: []string{"TRIVY_EXIT_CODE"},
+			},
+		},
+	}
+}
+
+// NewLSPCommand is the factory method to add lsp subcommand
+func NewLSPCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "lsp",
+		Usage: "run a language server over stdio publishing findings in files as diagnostics (EXPERIMENTAL)",
+		Description: `The language server speaks JSON-RPC over stdin and stdout as defined in the Language Server Protocol.
+Files are scanned when they are opened or saved, and the diagnostics are published with their positions.
+"trivy/scan" scans a file on demand and returns the diagnostics.`,
+		CustomHelpTemplate: cli.CommandHelpTemplate + `EXAMPLES:
+  - Run the language server, which is usually launched by an IDE plugin:
+      $ trivy lsp
+
+  - Detect misconfigurations as well:
+      $ trivy lsp --security-checks vuln,secret,config
+
+`,
+		Action: artifact.LSPRun,
+		Flags: []cli.Flag{
+			&severityFlag,
+			&skipDBUpdateFlag,
+			&skipPolicyUpdateFlag,
+			&insecureFlag,
+			&ignoreUnfixedFlag,
+			&vulnTypeFlag,
+			&securityChecksFlag,
+			&ignoreFileFlag,
+			&cacheBackendFlag,
+			&cacheTTL,
+			&redisBackendCACert,
+			&redisBackendCert,
+			&redisBackendKey,
+			&timeoutFlag,
+			&dbTimeoutFlag,
+			&analysisTimeoutFlag,
+			&targetTimeoutFlag,
+			&continueOnErrorFlag,
+			&policyTimeoutFlag,
+			&noProgressFlag,
+			&ignorePolicy,
+			&includeDevDeps,
+			&offlineScan,
+			&moduleDirFlag,
+			stringSliceFlag(enableModules),
+			&dbRepositoryFlag,
+			&dbSnapshotFlag,
+			stringSliceFlag(advisoryFeed),
+			&osvOnline,
+			&secretConfig,
+			stringSliceFlag(configPolicy),
+			stringSliceFlag(configData),
+			stringSliceFlag(policyNamespaces),
+			stringSliceFlag(checksBundle),
+			&checksBundleKey,
+			&checkOverrides,
+		},
+	}
+}
+
 // NewVersionCommand adds version command
 func NewVersionCommand() *cli.Command {
 	return &cli.Command{
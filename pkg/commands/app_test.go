@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
 )
 
 func Test_showVersion(t *testing.T) {
@@ -129,3 +130,73 @@ func TestNewCommands(t *testing.T) {
 	NewRepositoryCommand()
 	NewServerCommand()
 }
+
+func TestNewSbomCommand(t *testing.T) {
+	type result struct {
+		Action string
+		Format string
+		Target string
+	}
+	tests := []struct {
+		name      string
+		arguments []string
+		want      result
+	}{
+		{
+			name:      "generation by default",
+			arguments: []string{"trivy", "sbom", "alpine:3.15"},
+			want: result{
+				Action: "generate",
+				Format: "cyclonedx",
+				Target: "alpine:3.15",
+			},
+		},
+		{
+			name:      "generation with --format",
+			arguments: []string{"trivy", "sbom", "--format", "spdx-json", "bom.spdx.json"},
+			want: result{
+				Action: "generate",
+				Format: "spdx-json",
+				Target: "bom.spdx.json",
+			},
+		},
+		{
+			name:      "scanning",
+			arguments: []string{"trivy", "sbom", "scan", "--format", "json", "bom.spdx.json"},
+			want: result{
+				Action: "scan",
+				Format: "json",
+				Target: "bom.spdx.json",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got result
+			record := func(action string) cli.ActionFunc {
+				return func(ctx *cli.Context) error {
+					got = result{
+						Action: action,
+						Format: ctx.String("format"),
+						Target: ctx.Args().First(),
+					}
+					return nil
+				}
+			}
+
+			cmd := NewSbomCommand()
+			cmd.Action = record("generate")
+			for _, sub := range cmd.Subcommands {
+				if sub.Name == "scan" {
+					sub.Action = record("scan")
+				}
+			}
+
+			app := cli.NewApp()
+			app.Commands = []*cli.Command{cmd}
+			require.NoError(t, app.Run(tt.arguments))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
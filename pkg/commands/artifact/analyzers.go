@@ -0,0 +1,210 @@
+package artifact
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/docker/go-units"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+// Categories of analyzers shown by "trivy analyzers list"
+const (
+	categoryOS          = "os"
+	categoryLockfile    = "lockfile"
+	categoryPackage     = "package"
+	categoryLanguage    = "language"
+	categoryConfig      = "config"
+	categorySecret      = "secret"
+	categoryImageConfig = "image-config"
+	categoryOther       = "other"
+)
+
+// fileCategories are the categories of analyzers which analyze files
+var fileCategories = []string{categoryOS, categoryLockfile, categoryPackage, categoryLanguage, categoryConfig, categorySecret, categoryOther}
+
+// typeOSOthers has OS-related analyzers not in analyzer.TypeOSes
+var typeOSOthers = []analyzer.Type{analyzer.TypeOSRelease, analyzer.TypeCBLMariner, analyzer.TypeRpmqa, analyzer.TypeApkRepo}
+
+// analyzerInfo describes an analyzer which can be selected with --enable-analyzers and --disable-analyzers
+type analyzerInfo struct {
+	Type     analyzer.Type
+	Category string
+	Version  int
+}
+
+// allAnalyzers returns all the analyzers sorted by the type.
+// Config and secret analyzers are registered when the artifact is created, so they are added by their types.
+func allAnalyzers() []analyzerInfo {
+	group := analyzer.NewAnalyzerGroup(analyzer.GroupBuiltin, nil)
+
+	versions := map[analyzer.Type]int{}
+	for name, v := range group.AnalyzerVersions() {
+		versions[analyzer.Type(name)] = v
+	}
+	for _, t := range append(append([]analyzer.Type{analyzer.TypeHelm, analyzer.TypeSecret}, analyzer.TypeConfigFiles...), tanalyzer.TypeConfigFiles...) {
+		if _, ok := versions[t]; !ok {
+			versions[t] = 0
+		}
+	}
+
+	var analyzers []analyzerInfo
+	for t, v := range versions {
+		analyzers = append(analyzers, analyzerInfo{Type: t, Category: analyzerCategory(t), Version: v})
+	}
+	for name, v := range group.ImageConfigAnalyzerVersions() {
+		analyzers = append(analyzers, analyzerInfo{Type: analyzer.Type(name), Category: categoryImageConfig, Version: v})
+	}
+	sort.Slice(analyzers, func(i, j int) bool {
+		return analyzers[i].Type < analyzers[j].Type
+	})
+	return analyzers
+}
+
+func analyzerCategory(t analyzer.Type) string {
+	switch {
+	case slices.Contains(typeOSOthers, t) || slices.Contains(analyzer.TypeOSes, t) || slices.Contains(tanalyzer.TypeOSes, t):
+		return categoryOS
+	case slices.Contains(analyzer.TypeLockfiles, t) || slices.Contains(tanalyzer.TypeLockfiles, t):
+		return categoryLockfile
+	case slices.Contains(analyzer.TypeIndividualPkgs, t) || slices.Contains(tanalyzer.TypeIndividualPkgs, t):
+		return categoryPackage
+	case slices.Contains(analyzer.TypeLanguages, t) || slices.Contains(tanalyzer.TypeLanguages, t):
+		return categoryLanguage
+	case t == analyzer.TypeHelm || slices.Contains(analyzer.TypeConfigFiles, t) || slices.Contains(tanalyzer.TypeConfigFiles, t):
+		return categoryConfig
+	case t == analyzer.TypeSecret:
+		return categorySecret
+	}
+	return categoryOther
+}
+
+// initAnalyzers validates the analyzers selected with --enable-analyzers and --disable-analyzers
+func (c *Option) initAnalyzers() error {
+	if len(c.EnableAnalyzers) == 0 && len(c.DisableAnalyzers) == 0 {
+		return nil
+	}
+
+	var known []string
+	for _, a := range allAnalyzers() {
+		known = append(known, string(a.Type))
+	}
+	for _, t := range append(slices.Clone(c.EnableAnalyzers), c.DisableAnalyzers...) {
+		if !slices.Contains(known, t) {
+			c.Logger.Errorf(`unknown analyzer: %s (see "trivy analyzers list")`, t)
+			return xerrors.New("arguments error")
+		}
+	}
+	return nil
+}
+
+// initFileSizeLimits resolves the sizes of "--max-file-size" into the limits of each analyzer.
+// A size without a class applies to all the analyzers, and a class is a category or an analyzer.
+// Analyzers take precedence over categories, which take precedence over the size without a class.
+func (c *Option) initFileSizeLimits() error {
+	if len(c.MaxFileSizes) == 0 {
+		return nil
+	}
+
+	analyzers := allAnalyzers()
+	known := map[analyzer.Type]bool{}
+	for _, a := range analyzers {
+		known[a.Type] = true
+	}
+
+	var defaultLimit int64
+	categoryLimits := map[string]int64{}
+	typeLimits := map[analyzer.Type]int64{}
+	for _, s := range c.MaxFileSizes {
+		class, size, found := strings.Cut(s, "=")
+		if !found {
+			class, size = "", s
+		}
+		limit, err := units.RAMInBytes(size)
+		if err != nil || limit <= 0 {
+			c.Logger.Errorf(`invalid "--max-file-size": %s`, s)
+			return xerrors.New("arguments error")
+		}
+
+		switch {
+		case class == "":
+			defaultLimit = limit
+		case slices.Contains(fileCategories, class):
+			categoryLimits[class] = limit
+		case known[analyzer.Type(class)]:
+			typeLimits[analyzer.Type(class)] = limit
+		default:
+			c.Logger.Errorf(`unknown analyzer or category in "--max-file-size": %s (see "trivy analyzers list")`, class)
+			return xerrors.New("arguments error")
+		}
+	}
+
+	c.FileSizeLimits = tanalyzer.FileSizeLimits{}
+	for _, a := range analyzers {
+		limit := defaultLimit
+		if l, ok := categoryLimits[a.Category]; ok {
+			limit = l
+		}
+		if l, ok := typeLimits[a.Type]; ok {
+			limit = l
+		}
+		// Image config analyzers don't analyze files
+		if limit > 0 && a.Category != categoryImageConfig {
+			c.FileSizeLimits[a.Type] = limit
+		}
+	}
+	return nil
+}
+
+// selectedAnalyzers returns the analyzers disabled by --enable-analyzers and --disable-analyzers
+func selectedAnalyzers(opt Option) []analyzer.Type {
+	var disabled []analyzer.Type
+	for _, t := range opt.DisableAnalyzers {
+		disabled = append(disabled, analyzer.Type(t))
+	}
+	if len(opt.EnableAnalyzers) == 0 {
+		return disabled
+	}
+
+	// Only the specified analyzers are enabled
+	for _, a := range allAnalyzers() {
+		if !slices.Contains(opt.EnableAnalyzers, string(a.Type)) {
+			disabled = append(disabled, a.Type)
+		}
+	}
+	return disabled
+}
+
+// warnUnusedAnalyzers warns of the analyzers enabled explicitly but disabled by the scanning mode or the security checks
+func warnUnusedAnalyzers(opt Option, disabled []analyzer.Type) {
+	for _, t := range opt.EnableAnalyzers {
+		if slices.Contains(disabled, analyzer.Type(t)) {
+			log.Logger.Warnf("The %s analyzer is not used in this scan due to the scanning mode or the security checks", t)
+		}
+	}
+}
+
+// AnalyzersList lists the analyzers which can be selected with --enable-analyzers and --disable-analyzers
+func AnalyzersList(c *cli.Context) error {
+	w := tabwriter.NewWriter(c.App.Writer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ANALYZER\tCATEGORY\tVERSION")
+	for _, a := range allAnalyzers() {
+		version := "-"
+		if a.Version > 0 {
+			version = fmt.Sprint(a.Version)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", a.Type, a.Category, version)
+	}
+	if err := w.Flush(); err != nil {
+		return xerrors.Errorf("write error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package artifact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/trivy/pkg/commands/option"
+)
+
+func Test_selectedAnalyzers(t *testing.T) {
+	tests := []struct {
+		name         string
+		enable       []string
+		disable      []string
+		wantDisabled []analyzer.Type
+		wantEnabled  []analyzer.Type
+	}{
+		{
+			name: "no selection",
+		},
+		{
+			name:         "disable analyzers",
+			disable:      []string{"secret", "jar"},
+			wantDisabled: []analyzer.Type{analyzer.TypeSecret, analyzer.TypeJar},
+			wantEnabled:  []analyzer.Type{analyzer.TypePip, analyzer.TypeAlpine},
+		},
+		{
+			name:         "enable analyzers",
+			enable:       []string{"pip", "poetry"},
+			wantDisabled: []analyzer.Type{analyzer.TypeNpmPkgLock, analyzer.TypeAlpine, analyzer.TypeSecret, analyzer.TypeYaml, analyzer.TypeApkCommand},
+			wantEnabled:  []analyzer.Type{analyzer.TypePip, analyzer.TypePoetry},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := selectedAnalyzers(Option{
+				ArtifactOption: option.ArtifactOption{
+					EnableAnalyzers:  tt.enable,
+					DisableAnalyzers: tt.disable,
+				},
+			})
+			if tt.wantDisabled == nil {
+				assert.Empty(t, got)
+			}
+			for _, want := range tt.wantDisabled {
+				assert.Contains(t, got, want)
+			}
+			for _, want := range tt.wantEnabled {
+				assert.NotContains(t, got, want)
+			}
+		})
+	}
+}
+
+func Test_allAnalyzers(t *testing.T) {
+	categories := map[analyzer.Type]string{}
+	for _, a := range allAnalyzers() {
+		categories[a.Type] = a.Category
+	}
+
+	assert.Equal(t, map[analyzer.Type]string{
+		analyzer.TypeAlpine:     categoryOS,
+		analyzer.TypeCBLMariner: categoryOS,
+		analyzer.TypePip:        categoryLockfile,
+		analyzer.TypeJar:        categoryPackage,
+		analyzer.TypeCargo:      categoryLanguage,
+		analyzer.TypeHelm:       categoryConfig,
+		analyzer.TypeSecret:     categorySecret,
+		analyzer.TypeApkCommand: categoryImageConfig,
+	}, filterCategories(categories, analyzer.TypeAlpine, analyzer.TypeCBLMariner, analyzer.TypePip, analyzer.TypeJar,
+		analyzer.TypeCargo, analyzer.TypeHelm, analyzer.TypeSecret, analyzer.TypeApkCommand))
+}
+
+func filterCategories(categories map[analyzer.Type]string, types ...analyzer.Type) map[analyzer.Type]string {
+	filtered := map[analyzer.Type]string{}
+	for t, c := range categories {
+		if slices.Contains(types, t) {
+			filtered[t] = c
+		}
+	}
+	return filtered
+}
+
+func TestOption_initFileSizeLimits(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxFileSizes []string
+		want         map[analyzer.Type]int64
+		wantErr      string
+	}{
+		{
+			name: "no limits",
+		},
+		{
+			name:         "default, category and analyzer",
+			maxFileSizes: []string{"100MB", "lockfile=1MB", "npm=2MB"},
+			want: map[analyzer.Type]int64{
+				analyzer.TypeAlpine:     100 << 20,
+				analyzer.TypeSecret:     100 << 20,
+				analyzer.TypePip:        1 << 20,
+				analyzer.TypeNpmPkgLock: 2 << 20,
+			},
+		},
+		{
+			name:         "category only",
+			maxFileSizes: []string{"secret=512KiB"},
+			want: map[analyzer.Type]int64{
+				analyzer.TypeAlpine: 0,
+				analyzer.TypeSecret: 512 << 10,
+				analyzer.TypePip:    0,
+			},
+		},
+		{
+			name:         "sad: invalid size",
+			maxFileSizes: []string{"secret=big"},
+			wantErr:      "arguments error",
+		},
+		{
+			name:         "sad: unknown class",
+			maxFileSizes: []string{"unknown=1MB"},
+			wantErr:      "arguments error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Option{
+				GlobalOption:   option.GlobalOption{Logger: zap.NewNop().Sugar()},
+				ArtifactOption: option.ArtifactOption{MaxFileSizes: tt.maxFileSizes},
+			}
+			err := c.initFileSizeLimits()
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			if tt.want == nil {
+				assert.Nil(t, c.FileSizeLimits)
+			}
+			for typ, want := range tt.want {
+				assert.Equal(t, want, c.FileSizeLimits[typ], typ)
+			}
+
+			// Image config analyzers don't analyze files
+			assert.NotContains(t, c.FileSizeLimits, analyzer.TypeApkCommand)
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package artifact
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/fanal/artifact"
+	tdb "github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/metadata"
+	"github.com/aquasecurity/trivy/pkg/artifact/local"
+	"github.com/aquasecurity/trivy/pkg/db"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// dryRun prints what would be scanned with the resolved options, without downloading the DB or scanning
+func dryRun(w io.Writer, opt Option, artifactType ArtifactType) error {
+	switch artifactType {
+	case containerImageArtifact, imageArchiveArtifact:
+		opt = imageOption(opt)
+	case filesystemArtifact:
+		opt = filesystemOption(opt)
+	case rootfsArtifact:
+		opt = rootfsOption(opt)
+	case repositoryArtifact:
+		opt = repositoryOption(opt)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	row := func(key string, values ...string) {
+		value := strings.Join(values, ", ")
+		if value == "" {
+			value = "-"
+		}
+		fmt.Fprintf(tw, "%s:\t%s\n", key, value)
+	}
+
+	target := opt.Target
+	if opt.Input != "" {
+		target = opt.Input
+	}
+	row("Target", target)
+	row("Artifact type", string(artifactType))
+	if opt.RemoteAddr != "" {
+		row("Mode", "client/server ("+opt.RemoteAddr+")")
+	} else {
+		row("Mode", "standalone")
+	}
+	row("Security checks", opt.SecurityChecks...)
+	row("Vulnerability types", opt.VulnType...)
+	row("Severities", severityNames(opt)...)
+	row("Ignore unfixed", fmt.Sprint(opt.IgnoreUnfixed))
+	row("Ignore file", ignoreFile(opt.IgnoreFile))
+	row("Ignore policy", opt.IgnorePolicy)
+	row("Format", opt.Format)
+	row("Output", outputName(opt))
+
+	if slices.Contains(opt.SecurityChecks, types.SecurityCheckVulnerability) {
+		row("Vulnerability DB", vulnerabilityDB(opt))
+	}
+	if slices.Contains(opt.SecurityChecks, types.SecurityCheckConfig) {
+		row("Check namespaces", append(opt.PolicyNamespaces, defaultPolicyNamespaces...)...)
+		row("Check paths", opt.PolicyPaths...)
+		row("Check bundles", opt.ChecksBundles...)
+		row("Check data", opt.DataPaths...)
+		row("Config file patterns", opt.FilePatterns...)
+	}
+	if slices.Contains(opt.SecurityChecks, types.SecurityCheckSecret) {
+		row("Secret config", opt.SecretConfigPath)
+	}
+
+	enabled, disabled := analyzers(opt, artifactType)
+	row("Enabled analyzers", enabled...)
+	row("Disabled analyzers", disabled...)
+	row("Skip files", opt.SkipFiles...)
+	row("Skip directories", opt.SkipDirs...)
+	row("Include paths", opt.IncludePaths...)
+	row("Exclude paths", opt.ExcludePaths...)
+
+	if err := tw.Flush(); err != nil {
+		return xerrors.Errorf("write error: %w", err)
+	}
+
+	// The paths can be walked only in local file systems
+	if (artifactType != filesystemArtifact && artifactType != rootfsArtifact) || opt.RemoteAddr != "" {
+		return nil
+	}
+	return writeSkippedPaths(w, opt)
+}
+
+func severityNames(opt Option) []string {
+	var names []string
+	for _, s := range opt.Severities {
+		names = append(names, s.String())
+	}
+	return names
+}
+
+func outputName(opt Option) string {
+	if opt.Context == nil || opt.Context.String("output") == "" {
+		return "stdout"
+	}
+	return opt.Context.String("output")
+}
+
+func ignoreFile(path string) string {
+	if _, err := os.Stat(path); err != nil {
+		return path + " (not found)"
+	}
+	return path
+}
+
+// vulnerabilityDB describes the DB to be used and whether it would be downloaded
+func vulnerabilityDB(opt Option) string {
+	if opt.RemoteAddr != "" {
+		return "the server's DB"
+	}
+
+	if opt.DBSnapshot != "" {
+		return dbSnapshot(opt)
+	}
+
+	desc := tdb.Path(opt.CacheDir)
+	meta, err := metadata.NewClient(opt.CacheDir).Get()
+	if err == nil && meta.Version != 0 {
+		desc += fmt.Sprintf(" (schema %d, updated at %s)", meta.Version, meta.UpdatedAt.UTC().Format("2006-01-02 15:04:05"))
+	} else {
+		desc += " (not downloaded)"
+	}
+
+	client := db.NewClient(opt.CacheDir, true, opt.Insecure, db.WithDBRepository(opt.DBRepository))
+	needsUpdate, err := client.NeedsUpdate(opt.AppVersion, opt.SkipDBUpdate)
+	switch {
+	case err != nil:
+		desc += ", unusable: " + err.Error()
+	case needsUpdate:
+		desc += ", would be downloaded from " + opt.DBRepository
+	}
+	return desc
+}
+
+// dbSnapshot describes the DB snapshot pinned by --db-snapshot
+func dbSnapshot(opt Option) string {
+	digest, err := db.ResolveSnapshot(opt.CacheDir, opt.DBRepository, opt.DBSnapshot)
+	if err != nil {
+		return fmt.Sprintf("snapshot %s, unusable: %s", opt.DBSnapshot, err)
+	}
+
+	dir := db.SnapshotDir(opt.CacheDir, digest)
+	desc := fmt.Sprintf("%s (snapshot %s", tdb.Path(dir), digest)
+	meta, err := metadata.NewClient(dir).Get()
+	switch {
+	case err == nil:
+		desc += fmt.Sprintf(", updated at %s)", meta.UpdatedAt.UTC().Format("2006-01-02 15:04:05"))
+	case opt.SkipDBUpdate:
+		desc += "), unusable: not cached"
+	default:
+		desc += fmt.Sprintf("), would be downloaded from %s@%s", opt.DBRepository, digest)
+	}
+	return desc
+}
+
+// analyzers returns the names of the enabled and disabled analyzers
+func analyzers(opt Option, artifactType ArtifactType) ([]string, []string) {
+	disabledTypes := disabledAnalyzers(opt)
+	group := analyzer.NewAnalyzerGroup(analyzer.GroupBuiltin, disabledTypes)
+
+	var enabled []string
+	for name := range group.AnalyzerVersions() {
+		enabled = append(enabled, name)
+	}
+	// The secret analyzer is registered when the artifact is created
+	if !slices.Contains(disabledTypes, analyzer.TypeSecret) && !slices.Contains(enabled, string(analyzer.TypeSecret)) {
+		enabled = append(enabled, string(analyzer.TypeSecret))
+	}
+	if artifactType == containerImageArtifact || artifactType == imageArchiveArtifact {
+		for name := range group.ImageConfigAnalyzerVersions() {
+			enabled = append(enabled, name)
+		}
+	}
+	sort.Strings(enabled)
+
+	var disabled []string
+	for _, t := range disabledTypes {
+		if !slices.Contains(disabled, string(t)) {
+			disabled = append(disabled, string(t))
+		}
+	}
+	sort.Strings(disabled)
+
+	return enabled, disabled
+}
+
+func writeSkippedPaths(w io.Writer, opt Option) error {
+	artifactOpt := artifact.Option{
+		SkipFiles: opt.SkipFiles,
+		SkipDirs:  opt.SkipDirs,
+	}
+	skipped, err := local.SkippedPaths(opt.Target, artifactOpt, local.Option{
+		Rootfs:            opt.Rootfs,
+		IncludeSystemDirs: opt.IncludeSystemDirs,
+		IncludePaths:      opt.IncludePaths,
+		ExcludePaths:      opt.ExcludePaths,
+		UseGitignore:      opt.UseGitignore,
+	})
+	if err != nil {
+		return xerrors.Errorf("unable to walk %s: %w", opt.Target, err)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Skipped paths:")
+	if len(skipped) == 0 {
+		fmt.Fprintln(w, "  -")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, s := range skipped {
+		p := s.Path
+		if s.Dir {
+			p += "/"
+		}
+		fmt.Fprintf(tw, "  %s\t%s\n", p, s.Reason)
+	}
+	if err = tw.Flush(); err != nil {
+		return xerrors.Errorf("write error: %w", err)
+	}
+	return nil
+}
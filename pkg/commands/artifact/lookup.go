@@ -0,0 +1,57 @@
+package artifact
+
+import (
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/lookup"
+	pkgReport "github.com/aquasecurity/trivy/pkg/report"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// LookupRun looks up the vulnerabilities of the package version in the vulnerability DB
+// without constructing an artifact.
+func LookupRun(ctx *cli.Context) error {
+	opt, err := InitOption(ctx)
+	if err != nil {
+		return xerrors.Errorf("option error: %w", err)
+	}
+	if err = log.InitLogger(opt.Debug, opt.Quiet); err != nil {
+		return xerrors.Errorf("logger error: %w", err)
+	}
+
+	// Only vulnerabilities are looked up
+	opt.SecurityChecks = []string{types.SecurityCheckVulnerability}
+
+	r := &runner{}
+	if err = r.initDB(ctx.Context, opt); err != nil {
+		return xerrors.Errorf("DB error: %w", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Logger.Errorf("failed to close the DB: %s", err)
+		}
+	}()
+
+	result, err := lookup.Lookup(opt.Target)
+	if err != nil {
+		return xerrors.Errorf("lookup error: %w", err)
+	}
+
+	report := types.Report{
+		SchemaVersion: pkgReport.SchemaVersion,
+		ArtifactName:  opt.Target,
+		Results:       types.Results{result},
+	}
+	if report, err = r.Filter(ctx.Context, opt, report); err != nil {
+		return xerrors.Errorf("filter error: %w", err)
+	}
+	if err = r.Report(opt, report); err != nil {
+		return xerrors.Errorf("report error: %w", err)
+	}
+
+	Exit(opt, report.Results.Failed())
+	return nil
+}
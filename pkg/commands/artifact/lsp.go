@@ -0,0 +1,68 @@
+package artifact
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/lsp"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// LSPRun runs a language server over stdio, which scans files on demand for IDEs.
+// The DB and the cache are loaded once and shared by the scans.
+func LSPRun(cliCtx *cli.Context) error {
+	opt, err := NewOption(cliCtx)
+	if err != nil {
+		return xerrors.Errorf("option error: %w", err)
+	}
+
+	// There is no target, so the options for artifacts are not initialized
+	if err = opt.initPreScanOptions(); err != nil {
+		return xerrors.Errorf("option error: %w", err)
+	}
+	if err = opt.initOffline(); err != nil {
+		return xerrors.Errorf("option error: %w", err)
+	}
+
+	r, err := NewRunner(opt)
+	if err != nil {
+		if errors.Is(err, SkipScan) {
+			return nil
+		}
+		return xerrors.Errorf("init error: %w", err)
+	}
+	defer r.Close(cliCtx.Context)
+
+	log.Logger.Info("Language server is listening on stdio")
+	s := lsp.NewServer(os.Stdin, os.Stdout, opt.AppVersion, func(ctx context.Context, filePath string) (types.Results, error) {
+		return lspScan(ctx, r, opt, filePath)
+	})
+	if err = s.Serve(cliCtx.Context); err != nil {
+		return xerrors.Errorf("language server error: %w", err)
+	}
+	return nil
+}
+
+// lspScan scans the file together with the related manifests and lock files in the same directory
+func lspScan(ctx context.Context, r Runner, opt Option, filePath string) (types.Results, error) {
+	ctx, cancel := context.WithTimeout(ctx, opt.Timeout)
+	defer cancel()
+
+	opt.Target = filepath.Dir(filePath)
+	opt.Files = []string{filepath.Base(filePath)}
+
+	report, err := r.ScanFilesystem(ctx, opt)
+	if err != nil {
+		return nil, xerrors.Errorf("filesystem scan error: %w", err)
+	}
+	if err = filterResults(ctx, opt, report.Results); err != nil {
+		return nil, xerrors.Errorf("filter error: %w", err)
+	}
+	return report.Results, nil
+}
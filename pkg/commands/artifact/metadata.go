@@ -0,0 +1,123 @@
+package artifact
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+	"golang.org/x/exp/slices"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	"github.com/aquasecurity/trivy-db/pkg/metadata"
+	"github.com/aquasecurity/trivy/pkg/db"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/policy"
+	"github.com/aquasecurity/trivy/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/utils"
+)
+
+// redactedFlags have credentials, which must not be written to reports
+var redactedFlags = []string{
+	"token",
+	"custom-headers",
+	"git-token",
+}
+
+// scanMetadata returns how the report was produced, so that reports can be reproduced and compared
+func scanMetadata(opt Option, artifactType ArtifactType) *types.ScanMetadata {
+	meta := &types.ScanMetadata{
+		Version:   opt.AppVersion,
+		DB:        dbMetadata(opt),
+		Analyzers: analyzerVersions(opt, artifactType),
+		Flags:     flags(opt.Context),
+	}
+	if slices.Contains(opt.SecurityChecks, types.SecurityCheckConfig) {
+		for _, r := range opt.ChecksBundles {
+			meta.ChecksBundles = append(meta.ChecksBundles, types.ChecksBundle{
+				Reference: r,
+				Digest:    policy.BundleDigest(utils.CacheDir(), r),
+			})
+		}
+	}
+	return meta
+}
+
+// dbMetadata returns the vulnerability DB used for the scan, or nil if the DB is not used
+func dbMetadata(opt Option) *types.DBMetadata {
+	if opt.RemoteAddr != "" || !slices.Contains(opt.SecurityChecks, types.SecurityCheckVulnerability) {
+		return nil
+	}
+
+	dir := opt.CacheDir
+	var digest string
+	if opt.DBSnapshot != "" {
+		var err error
+		if digest, err = db.ResolveSnapshot(opt.CacheDir, opt.DBRepository, opt.DBSnapshot); err != nil {
+			log.Logger.Debugf("DB snapshot error: %s", err)
+			return nil
+		}
+		dir = db.SnapshotDir(opt.CacheDir, digest)
+	}
+
+	meta, err := metadata.NewClient(dir).Get()
+	if err != nil {
+		log.Logger.Debugf("DB metadata error: %s", err)
+		return nil
+	}
+	if digest == "" {
+		digest = db.Digest(opt.CacheDir, opt.DBRepository, meta.UpdatedAt)
+	}
+	return &types.DBMetadata{
+		Repository:    opt.DBRepository,
+		Digest:        digest,
+		Snapshot:      opt.DBSnapshot != "",
+		SchemaVersion: meta.Version,
+		UpdatedAt:     meta.UpdatedAt,
+		DownloadedAt:  meta.DownloadedAt,
+	}
+}
+
+// analyzerVersions returns the versions of the enabled analyzers.
+// It must be called after the scan as some analyzers are registered when the artifact is created.
+func analyzerVersions(opt Option, artifactType ArtifactType) map[string]int {
+	group := analyzer.NewAnalyzerGroup(analyzer.GroupBuiltin, disabledAnalyzers(opt))
+	versions := group.AnalyzerVersions()
+	if artifactType == containerImageArtifact || artifactType == imageArchiveArtifact {
+		for name, v := range group.ImageConfigAnalyzerVersions() {
+			versions[name] = v
+		}
+	}
+	return versions
+}
+
+// flags returns the values of the flags set explicitly, including the global flags
+func flags(ctx *cli.Context) map[string]string {
+	if ctx == nil || ctx.Command == nil {
+		return nil
+	}
+
+	values := map[string]string{}
+	var all []cli.Flag
+	all = append(all, ctx.Command.Flags...)
+	if ctx.App != nil {
+		all = append(all, ctx.App.Flags...)
+	}
+	for _, f := range all {
+		name := f.Names()[0]
+		if _, ok := values[name]; ok || !ctx.IsSet(name) {
+			continue
+		}
+
+		if slices.Contains(redactedFlags, name) {
+			values[name] = "REDACTED"
+		} else if _, ok := f.(*cli.StringSliceFlag); ok {
+			values[name] = strings.Join(ctx.StringSlice(name), ",")
+		} else {
+			values[name] = fmt.Sprint(ctx.Value(name))
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
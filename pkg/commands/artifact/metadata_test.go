@@ -0,0 +1,153 @@
+package artifact
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+
+	"github.com/aquasecurity/trivy-db/pkg/metadata"
+	"github.com/aquasecurity/trivy/pkg/commands/option"
+	"github.com/aquasecurity/trivy/pkg/db"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func Test_flags(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+		want map[string]string
+	}{
+		{
+			name: "happy path",
+			args: []string{"trivy", "--cache-dir", "/tmp/cache", "fs", "--token", "s3cr3t", "--skip-dirs", "a", "--skip-dirs", "b", "--quiet", "."},
+			env: map[string]string{
+				"TRIVY_SEVERITY": "HIGH,CRITICAL",
+			},
+			want: map[string]string{
+				"cache-dir": "/tmp/cache",
+				"quiet":     "true",
+				"severity":  "HIGH,CRITICAL",
+				"skip-dirs": "a,b",
+				"token":     "REDACTED",
+			},
+		},
+		{
+			name: "no flags",
+			args: []string{"trivy", "fs", "."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			var got map[string]string
+			app := &cli.App{
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "cache-dir"},
+				},
+				Commands: []*cli.Command{
+					{
+						Name: "fs",
+						Flags: []cli.Flag{
+							&cli.StringFlag{Name: "severity", Value: "UNKNOWN,LOW,MEDIUM,HIGH,CRITICAL", EnvVars: []string{"TRIVY_SEVERITY"}},
+							&cli.StringFlag{Name: "token"},
+							&cli.StringSliceFlag{Name: "skip-dirs"},
+							&cli.BoolFlag{Name: "quiet", Aliases: []string{"q"}},
+						},
+						Action: func(c *cli.Context) error {
+							got = flags(c)
+							return nil
+						},
+					},
+				},
+			}
+			require.NoError(t, app.Run(tt.args))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_dbMetadata(t *testing.T) {
+	const repository = "ghcr.io/aquasecurity/trivy-db"
+	digest := "sha256:" + strings.Repeat("1", 64)
+	snapshotDigest := "sha256:" + strings.Repeat("2", 64)
+	updatedAt := time.Date(2022, 9, 20, 6, 0, 0, 0, time.UTC)
+	downloadedAt := time.Date(2022, 9, 20, 10, 0, 0, 0, time.UTC)
+
+	cacheDir := t.TempDir()
+	for _, dir := range []string{cacheDir, db.SnapshotDir(cacheDir, snapshotDigest)} {
+		require.NoError(t, metadata.NewClient(dir).Update(metadata.Metadata{
+			Version:      2,
+			UpdatedAt:    updatedAt,
+			DownloadedAt: downloadedAt,
+		}))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(cacheDir, "db-snapshots", "snapshots.json"), []byte(`[
+  {"Repository": "ghcr.io/aquasecurity/trivy-db", "Digest": "`+digest+`", "UpdatedAt": "2022-09-20T06:00:00Z"}
+]`), 0600))
+
+	tests := []struct {
+		name string
+		opt  Option
+		want *types.DBMetadata
+	}{
+		{
+			name: "latest DB",
+			opt: Option{
+				ReportOption: option.ReportOption{SecurityChecks: []string{types.SecurityCheckVulnerability}},
+			},
+			want: &types.DBMetadata{
+				Repository:    repository,
+				Digest:        digest,
+				SchemaVersion: 2,
+				UpdatedAt:     updatedAt,
+				DownloadedAt:  downloadedAt,
+			},
+		},
+		{
+			name: "snapshot",
+			opt: Option{
+				ReportOption: option.ReportOption{SecurityChecks: []string{types.SecurityCheckVulnerability}},
+				DBOption:     option.DBOption{DBSnapshot: snapshotDigest},
+			},
+			want: &types.DBMetadata{
+				Repository:    repository,
+				Digest:        snapshotDigest,
+				Snapshot:      true,
+				SchemaVersion: 2,
+				UpdatedAt:     updatedAt,
+				DownloadedAt:  downloadedAt,
+			},
+		},
+		{
+			name: "client/server mode",
+			opt: Option{
+				ReportOption: option.ReportOption{SecurityChecks: []string{types.SecurityCheckVulnerability}},
+				RemoteOption: option.RemoteOption{RemoteAddr: "http://localhost:4954"},
+			},
+		},
+		{
+			name: "no vulnerability scanning",
+			opt: Option{
+				ReportOption: option.ReportOption{SecurityChecks: []string{types.SecurityCheckSecret}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opt.CacheDir = cacheDir
+			tt.opt.DBRepository = repository
+			got := dbMetadata(tt.opt)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
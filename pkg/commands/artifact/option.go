@@ -2,10 +2,13 @@ package artifact
 
 import (
 	"github.com/urfave/cli/v2"
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
 	"github.com/aquasecurity/fanal/analyzer"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
 	"github.com/aquasecurity/trivy/pkg/commands/option"
+	"github.com/aquasecurity/trivy/pkg/types"
 )
 
 // Option holds the artifact options
@@ -18,14 +21,31 @@ type Option struct {
 	option.CacheOption
 	option.ConfigOption
 	option.RemoteOption
+	option.RepoOption
 	option.SbomOption
 	option.SecretOption
+	option.LicenseOption
+	option.RiskOption
 	option.KubernetesOption
+	option.ModuleOption
 	option.OtherOption
 
 	// We don't want to allow disabled analyzers to be passed by users,
 	// but it differs depending on scanning modes.
 	DisabledAnalyzers []analyzer.Type
+
+	// ArchiveAnalyzers analyze only files in archives with --scan-archives,
+	// e.g. the individual package analyzers for wheels and jars mirrored to disk in filesystem scanning.
+	ArchiveAnalyzers []analyzer.Type
+
+	// Rootfs is enabled in rootfs scanning so that symlinks are resolved relative to the root.
+	Rootfs bool
+
+	// FileSizeLimits is resolved from "--max-file-size" in Init().
+	FileSizeLimits tanalyzer.FileSizeLimits
+
+	// Files limits the analysis to the files relative to the target, which are rescanned in watch mode.
+	Files []string
 }
 
 // NewOption is the factory method to return options
@@ -44,9 +64,13 @@ func NewOption(c *cli.Context) (Option, error) {
 		CacheOption:      option.NewCacheOption(c),
 		ConfigOption:     option.NewConfigOption(c),
 		RemoteOption:     option.NewRemoteOption(c),
+		RepoOption:       option.NewRepoOption(c),
 		SbomOption:       option.NewSbomOption(c),
 		SecretOption:     option.NewSecretOption(c),
+		LicenseOption:    option.NewLicenseOption(c),
+		RiskOption:       option.NewRiskOption(c),
 		KubernetesOption: option.NewKubernetesOption(c),
+		ModuleOption:     option.NewModuleOption(c),
 		OtherOption:      option.NewOtherOption(c),
 	}, nil
 }
@@ -56,6 +80,9 @@ func (c *Option) Init() error {
 	if err := c.initPreScanOptions(); err != nil {
 		return err
 	}
+	if err := c.initOffline(); err != nil {
+		return err
+	}
 
 	// --clear-cache, --download-db-only and --reset don't conduct the scan
 	if c.skipScan() {
@@ -65,6 +92,12 @@ func (c *Option) Init() error {
 	if err := c.ArtifactOption.Init(c.Context, c.Logger); err != nil {
 		return err
 	}
+	if err := c.initAnalyzers(); err != nil {
+		return err
+	}
+	if err := c.initFileSizeLimits(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -81,6 +114,15 @@ func (c *Option) initPreScanOptions() error {
 	if err := c.SbomOption.Init(c.Context, c.Logger); err != nil {
 		return err
 	}
+	if err := c.SecretOption.Init(); err != nil {
+		return err
+	}
+	if err := c.RiskOption.Init(); err != nil {
+		return err
+	}
+	if err := c.ImageOption.Init(); err != nil {
+		return err
+	}
 	c.RemoteOption.Init(c.Logger)
 	return nil
 }
@@ -91,3 +133,75 @@ func (c *Option) skipScan() bool {
 	}
 	return false
 }
+
+// initOffline makes "--offline-scan" network-free and reports the capabilities degraded by it
+func (c *Option) initOffline() error {
+	if !c.OfflineScan {
+		return nil
+	}
+	if c.DownloadDBOnly {
+		return xerrors.New("--offline-scan and --download-db-only options can not be specified both")
+	}
+
+	// The DB must be downloaded beforehand
+	c.SkipDBUpdate = true
+
+	// Check bundles must be downloaded beforehand as well
+	c.SkipPolicyUpdate = true
+
+	for _, d := range c.offlineDegradations() {
+		c.Logger.Warnf("Offline scan: %s", d)
+	}
+
+	// Secret verification calls provider APIs
+	c.SecretVerify = false
+
+	// The online mode calls the OSV API
+	c.OSVOnline = false
+
+	// Attestations, signatures and newer base images are fetched from registries
+	c.VerifyProvenance = false
+	c.VerifySignature = false
+	c.RecommendBaseImage = false
+	return nil
+}
+
+// offlineDegradations returns the capabilities unavailable without network access
+func (c *Option) offlineDegradations() []string {
+	var degradations []string
+	if c.RemoteAddr == "" && slices.Contains(c.SecurityChecks, types.SecurityCheckVulnerability) {
+		degradations = append(degradations, "the vulnerability DB is not updated")
+	}
+	if len(c.ChecksBundles) > 0 {
+		degradations = append(degradations, "check bundles are not updated from registries")
+	}
+	if slices.Contains(c.VulnType, types.VulnTypeLibrary) {
+		degradations = append(degradations,
+			"JAR files are not looked up on Maven Central, so packages without Maven coordinates may be missed",
+			"parent POMs and dependencies not in the local Maven repository are not resolved for pom.xml")
+	}
+	if c.SecretVerify {
+		degradations = append(degradations, "detected secrets are not verified")
+	}
+	if c.OSVOnline {
+		degradations = append(degradations, "the OSV API is not queried")
+	}
+	if c.VerifyProvenance {
+		degradations = append(degradations, "the provenance of images is not verified")
+	}
+	if c.VerifySignature {
+		degradations = append(degradations, "the signatures of images are not verified")
+	}
+	if c.RecommendBaseImage {
+		degradations = append(degradations, "base image upgrades are not recommended")
+	}
+	switch c.Context.Command.Name {
+	case "image":
+		if c.Input == "" {
+			degradations = append(degradations, "images are not pulled from registries")
+		}
+	case "repository":
+		degradations = append(degradations, "only local repositories (file://) can be scanned")
+	}
+	return degradations
+}
@@ -268,6 +268,102 @@ func TestOption_Init(t *testing.T) {
 			args:    []string{"--skip-db-update", "--download-db-only", "alpine:3.10"},
 			wantErr: "--skip-db-update and --download-db-only options can not be specified both",
 		},
+		{
+			name: "offline scan",
+			args: []string{"--offline-scan", "--quiet", "alpine:3.10"},
+			logs: []string{
+				"Offline scan: the vulnerability DB is not updated",
+				"Offline scan: JAR files are not looked up on Maven Central, so packages without Maven coordinates may be missed",
+				"Offline scan: parent POMs and dependencies not in the local Maven repository are not resolved for pom.xml",
+			},
+			want: Option{
+				GlobalOption: option.GlobalOption{
+					Quiet: true,
+				},
+				ArtifactOption: option.ArtifactOption{
+					OfflineScan: true,
+					Target:      "alpine:3.10",
+				},
+				DBOption: option.DBOption{
+					SkipDBUpdate: true,
+				},
+				ConfigOption: option.ConfigOption{
+					SkipPolicyUpdate: true,
+				},
+				ReportOption: option.ReportOption{
+					Severities:     []dbTypes.Severity{dbTypes.SeverityCritical},
+					VulnType:       []string{types.VulnTypeOS, types.VulnTypeLibrary},
+					SecurityChecks: []string{types.SecurityCheckVulnerability},
+					Output:         os.Stdout,
+				},
+			},
+		},
+		{
+			name: "offline scan with check bundles",
+			args: []string{"--offline-scan", "--checks-bundle", "oci://ghcr.io/acme/checks:1.0", "--security-checks", "config", "--quiet", "alpine:3.10"},
+			logs: []string{
+				"Offline scan: check bundles are not updated from registries",
+				"Offline scan: JAR files are not looked up on Maven Central, so packages without Maven coordinates may be missed",
+				"Offline scan: parent POMs and dependencies not in the local Maven repository are not resolved for pom.xml",
+			},
+			want: Option{
+				GlobalOption: option.GlobalOption{
+					Quiet: true,
+				},
+				ArtifactOption: option.ArtifactOption{
+					OfflineScan: true,
+					Target:      "alpine:3.10",
+				},
+				DBOption: option.DBOption{
+					SkipDBUpdate: true,
+				},
+				ConfigOption: option.ConfigOption{
+					SkipPolicyUpdate: true,
+					ChecksBundles:    []string{"oci://ghcr.io/acme/checks:1.0"},
+				},
+				ReportOption: option.ReportOption{
+					Severities:     []dbTypes.Severity{dbTypes.SeverityCritical},
+					VulnType:       []string{types.VulnTypeOS, types.VulnTypeLibrary},
+					SecurityChecks: []string{types.SecurityCheckConfig},
+					Output:         os.Stdout,
+				},
+			},
+		},
+		{
+			name: "offline scan with secret verification",
+			args: []string{"--offline-scan", "--secret-verify", "--security-checks", "secret", "--quiet", "alpine:3.10"},
+			logs: []string{
+				"Offline scan: JAR files are not looked up on Maven Central, so packages without Maven coordinates may be missed",
+				"Offline scan: parent POMs and dependencies not in the local Maven repository are not resolved for pom.xml",
+				"Offline scan: detected secrets are not verified",
+			},
+			want: Option{
+				GlobalOption: option.GlobalOption{
+					Quiet: true,
+				},
+				ArtifactOption: option.ArtifactOption{
+					OfflineScan: true,
+					Target:      "alpine:3.10",
+				},
+				DBOption: option.DBOption{
+					SkipDBUpdate: true,
+				},
+				ConfigOption: option.ConfigOption{
+					SkipPolicyUpdate: true,
+				},
+				ReportOption: option.ReportOption{
+					Severities:     []dbTypes.Severity{dbTypes.SeverityCritical},
+					VulnType:       []string{types.VulnTypeOS, types.VulnTypeLibrary},
+					SecurityChecks: []string{types.SecurityCheckSecret},
+					Output:         os.Stdout,
+				},
+			},
+		},
+		{
+			name:    "sad: offline scan and download db",
+			args:    []string{"--offline-scan", "--download-db-only", "alpine:3.10"},
+			wantErr: "--offline-scan and --download-db-only options can not be specified both",
+		},
 		{
 			name: "sad: multiple image names",
 			args: []string{"centos:7", "alpine:3.10"},
@@ -290,6 +386,8 @@ func TestOption_Init(t *testing.T) {
 			set.Bool("skip-db-update", false, "")
 			set.Bool("download-db-only", false, "")
 			set.Bool("list-all-pkgs", false, "")
+			set.Bool("offline-scan", false, "")
+			set.Bool("secret-verify", false, "")
 			set.String("severity", "CRITICAL", "")
 			set.String("vuln-type", "os,library", "")
 			set.String("security-checks", "vuln", "")
@@ -299,6 +397,7 @@ func TestOption_Init(t *testing.T) {
 			set.String("token", "", "")
 			set.String("token-header", option.DefaultTokenHeader, "")
 			set.Var(&cli.StringSlice{}, "custom-headers", "")
+			set.Var(&cli.StringSlice{}, "checks-bundle", "")
 
 			ctx := cli.NewContext(app, set, nil)
 			_ = set.Parse(tt.args)
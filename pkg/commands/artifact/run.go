@@ -2,6 +2,7 @@ package artifact
 
 import (
 	"context"
+	"crypto"
 	"errors"
 	"fmt"
 	"os"
@@ -9,22 +10,50 @@ import (
 	"github.com/hashicorp/go-multierror"
 	"github.com/urfave/cli/v2"
 	"golang.org/x/exp/slices"
+	"golang.org/x/term"
 	"golang.org/x/xerrors"
 
 	"github.com/aquasecurity/fanal/analyzer"
 	"github.com/aquasecurity/fanal/analyzer/config"
-	"github.com/aquasecurity/fanal/analyzer/secret"
 	"github.com/aquasecurity/fanal/artifact"
 	"github.com/aquasecurity/fanal/cache"
 	"github.com/aquasecurity/trivy-db/pkg/db"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	acodeowners "github.com/aquasecurity/trivy/pkg/analyzer/codeowners"
+	akernel "github.com/aquasecurity/trivy/pkg/analyzer/kernel"
+	alicensing "github.com/aquasecurity/trivy/pkg/analyzer/licensing"
+	asbom "github.com/aquasecurity/trivy/pkg/analyzer/sbom"
+	tsecret "github.com/aquasecurity/trivy/pkg/analyzer/secret"
+	aimage "github.com/aquasecurity/trivy/pkg/artifact/image"
+	"github.com/aquasecurity/trivy/pkg/artifact/local"
+	"github.com/aquasecurity/trivy/pkg/artifact/remote"
+	"github.com/aquasecurity/trivy/pkg/artifact/sbom"
+	"github.com/aquasecurity/trivy/pkg/baseimage"
 	tcache "github.com/aquasecurity/trivy/pkg/cache"
+	"github.com/aquasecurity/trivy/pkg/cloudformation"
 	"github.com/aquasecurity/trivy/pkg/commands/operation"
+	"github.com/aquasecurity/trivy/pkg/gate"
+	"github.com/aquasecurity/trivy/pkg/helm"
+	"github.com/aquasecurity/trivy/pkg/history"
+	"github.com/aquasecurity/trivy/pkg/image"
+	"github.com/aquasecurity/trivy/pkg/licensing"
 	"github.com/aquasecurity/trivy/pkg/log"
 	"github.com/aquasecurity/trivy/pkg/module"
+	"github.com/aquasecurity/trivy/pkg/oci"
+	"github.com/aquasecurity/trivy/pkg/ownership"
+	"github.com/aquasecurity/trivy/pkg/policy"
+	"github.com/aquasecurity/trivy/pkg/progress"
+	"github.com/aquasecurity/trivy/pkg/provenance"
+	"github.com/aquasecurity/trivy/pkg/remediation"
 	pkgReport "github.com/aquasecurity/trivy/pkg/report"
 	"github.com/aquasecurity/trivy/pkg/result"
+	"github.com/aquasecurity/trivy/pkg/risk"
 	"github.com/aquasecurity/trivy/pkg/rpc/client"
+	tsbom "github.com/aquasecurity/trivy/pkg/sbom"
 	"github.com/aquasecurity/trivy/pkg/scanner"
+	pkgSecret "github.com/aquasecurity/trivy/pkg/secret"
+	"github.com/aquasecurity/trivy/pkg/signature"
+	"github.com/aquasecurity/trivy/pkg/timeout"
 	"github.com/aquasecurity/trivy/pkg/types"
 	"github.com/aquasecurity/trivy/pkg/utils"
 )
@@ -37,6 +66,7 @@ const (
 	rootfsArtifact         ArtifactType = "rootfs"
 	repositoryArtifact     ArtifactType = "repo"
 	imageArchiveArtifact   ArtifactType = "archive"
+	sbomArtifact           ArtifactType = "sbom"
 )
 
 var (
@@ -64,6 +94,24 @@ type ScannerConfig struct {
 
 	// Artifact options
 	ArtifactOption artifact.Option
+
+	// Repository options
+	RepoOption remote.Option
+
+	// Filesystem options
+	LocalOption local.Option
+
+	// Container image options
+	ContainerOption image.ContainerOption
+
+	// Image artifact options for container images and image archives
+	ImageOption aimage.Option
+
+	// Image archive options
+	ArchiveOption image.ArchiveOption
+
+	// SBOM options
+	SBOMOption sbom.Option
 }
 
 type Runner interface {
@@ -75,6 +123,8 @@ type Runner interface {
 	ScanRootfs(ctx context.Context, opt Option) (types.Report, error)
 	// ScanRepositroy scans repository
 	ScanRepository(ctx context.Context, opt Option) (types.Report, error)
+	// ScanSBOM scans SBOM
+	ScanSBOM(ctx context.Context, opt Option) (types.Report, error)
 	// Filter filter a report
 	Filter(ctx context.Context, opt Option, report types.Report) (types.Report, error)
 	// Report a writes a report
@@ -117,12 +167,15 @@ func NewRunner(cliOption Option, opts ...runnerOption) (Runner, error) {
 		return nil, xerrors.Errorf("cache error: %w", err)
 	}
 
-	if err = r.initDB(cliOption); err != nil {
+	if err = r.initDB(cliOption.Context.Context, cliOption); err != nil {
 		return nil, xerrors.Errorf("DB error: %w", err)
 	}
 
 	// Initialize WASM modules
-	m, err := module.NewManager(cliOption.Context.Context)
+	m, err := module.NewManager(cliOption.Context.Context, module.Option{
+		Dir:            cliOption.ModuleDir,
+		EnabledModules: cliOption.EnabledModules,
+	})
 	if err != nil {
 		return nil, xerrors.Errorf("WASM module error: %w", err)
 	}
@@ -152,8 +205,15 @@ func (r *runner) Close(ctx context.Context) error {
 }
 
 func (r *runner) ScanImage(ctx context.Context, opt Option) (types.Report, error) {
-	// Disable the lock file scanning
-	opt.DisabledAnalyzers = analyzer.TypeLockfiles
+	artifactOpt := opt
+	opt = imageOption(opt)
+
+	if opt.TmpDir != "" {
+		// Images in container engines are exported to temporary files in os.TempDir() before being analyzed
+		if err := os.Setenv("TMPDIR", opt.TmpDir); err != nil {
+			return types.Report{}, xerrors.Errorf("tmp dir error: %w", err)
+		}
+	}
 
 	var s InitializeScanner
 	switch {
@@ -171,21 +231,99 @@ func (r *runner) ScanImage(ctx context.Context, opt Option) (types.Report, error
 		s = imageRemoteScanner
 	}
 
-	return r.scanArtifact(ctx, opt, s)
+	report, err := r.scanArtifact(ctx, opt, s)
+	var notImage *oci.NotImageError
+	if errors.As(err, &notImage) {
+		// Helm charts and WASM modules stored in registries are scanned as files
+		return r.scanOCIArtifact(ctx, artifactOpt, notImage)
+	} else if err != nil {
+		return types.Report{}, err
+	}
+
+	if err = attributeBaseImage(opt, &report); err != nil {
+		return types.Report{}, xerrors.Errorf("base image error: %w", err)
+	}
+
+	// Other images, attestations and signatures are not pulled once the scan fails
+	if opt.FailFast && failFast(ctx, opt)(report.Results) {
+		log.Logger.Warn("Findings failing the scan were detected. " +
+			"Base image recommendation and verification of provenance and signatures are skipped due to fail-fast.")
+		return report, nil
+	}
+
+	if opt.RecommendBaseImage {
+		recommendations, err := r.recommendBaseImage(ctx, opt, report)
+		if err != nil {
+			return types.Report{}, xerrors.Errorf("base image recommendation error: %w", err)
+		}
+		report.Recommendations = recommendations
+	}
+
+	// Attestations and signatures are stored in the registry, so image tarballs cannot be verified
+	if opt.VerifyProvenance && opt.Input == "" {
+		result, err := verifyProvenance(opt, report)
+		if err != nil {
+			return types.Report{}, xerrors.Errorf("provenance verification error: %w", err)
+		} else if result != nil {
+			report.Results = append(report.Results, *result)
+		}
+	}
+	if opt.VerifySignature && opt.Input == "" {
+		result, err := verifySignature(opt, report)
+		if err != nil {
+			return types.Report{}, xerrors.Errorf("signature verification error: %w", err)
+		} else if result != nil {
+			report.Results = append(report.Results, *result)
+		}
+	}
+	return report, nil
 }
 
-func (r *runner) ScanFilesystem(ctx context.Context, opt Option) (types.Report, error) {
-	// Disable the individual package scanning
-	opt.DisabledAnalyzers = append(opt.DisabledAnalyzers, analyzer.TypeIndividualPkgs...)
+// scanOCIArtifact downloads the files in the OCI artifact and scans them like a filesystem.
+// Base images, provenance and signatures are specific to container images and not checked.
+func (r *runner) scanOCIArtifact(ctx context.Context, opt Option, notImage *oci.NotImageError) (types.Report, error) {
+	log.Logger.Infof("%s is not a container image, but a %s OCI artifact", opt.Target, notImage.Kind)
+
+	dir, err := os.MkdirTemp(opt.TmpDir, "trivy-oci-*")
+	if err != nil {
+		return types.Report{}, xerrors.Errorf("failed to create a temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
 
-	return r.scanFS(ctx, opt)
+	err = timeout.Run(ctx, timeout.PhasePull, opt.PullTimeout, func(context.Context) error {
+		fileNames, err := oci.Pull(notImage.Image, dir)
+		log.Logger.Debugf("OCI artifact files: %v", fileNames)
+		return err
+	})
+	if err != nil {
+		return types.Report{}, xerrors.Errorf("OCI artifact error: %w", err)
+	}
+
+	// Helm charts have no packages, but misconfigurations
+	if notImage.Kind == oci.KindHelmChart && !slices.Contains(opt.SecurityChecks, types.SecurityCheckConfig) &&
+		(opt.Context == nil || !opt.Context.IsSet("security-checks")) {
+		opt.SecurityChecks = append(slices.Clone(opt.SecurityChecks), types.SecurityCheckConfig)
+	}
+
+	target := opt.Target
+	opt.Target = dir
+	report, err := r.scanFS(ctx, filesystemOption(opt))
+	if err != nil {
+		return types.Report{}, err
+	}
+
+	report.ArtifactName = target
+	report.ArtifactType = oci.ArtifactType
+	report.Metadata.ArtifactKind = notImage.Kind
+	return report, nil
 }
 
-func (r *runner) ScanRootfs(ctx context.Context, opt Option) (types.Report, error) {
-	// Disable the lock file scanning
-	opt.DisabledAnalyzers = append(opt.DisabledAnalyzers, analyzer.TypeLockfiles...)
+func (r *runner) ScanFilesystem(ctx context.Context, opt Option) (types.Report, error) {
+	return r.scanFS(ctx, filesystemOption(opt))
+}
 
-	return r.scanFS(ctx, opt)
+func (r *runner) ScanRootfs(ctx context.Context, opt Option) (types.Report, error) {
+	return r.scanFS(ctx, rootfsOption(opt))
 }
 
 func (r *runner) scanFS(ctx context.Context, opt Option) (types.Report, error) {
@@ -202,13 +340,67 @@ func (r *runner) scanFS(ctx context.Context, opt Option) (types.Report, error) {
 }
 
 func (r *runner) ScanRepository(ctx context.Context, opt Option) (types.Report, error) {
+	return r.scanArtifact(ctx, repositoryOption(opt), repositoryStandaloneScanner)
+}
+
+func (r *runner) ScanSBOM(ctx context.Context, opt Option) (types.Report, error) {
+	var s InitializeScanner
+	if opt.RemoteAddr == "" {
+		// Scan SBOM in standalone mode
+		s = sbomStandaloneScanner
+	} else {
+		// Scan SBOM in client/server mode
+		s = sbomRemoteScanner
+	}
+
+	return r.scanArtifact(ctx, opt, s)
+}
+
+// imageOption returns the options for image scanning
+func imageOption(opt Option) Option {
+	// Disable the lock file scanning
+	opt.DisabledAnalyzers = append(analyzer.TypeLockfiles, tanalyzer.TypeLockfiles...)
+	return opt
+}
+
+// filesystemOption returns the options for filesystem scanning
+func filesystemOption(opt Option) Option {
+	// Disable the individual package scanning except in archives
+	return disableIndividualPkgs(opt)
+}
+
+// disableIndividualPkgs disables the individual package analyzers.
+// With --scan-archives, they analyze files in archives such as wheels and jars mirrored to disk.
+func disableIndividualPkgs(opt Option) Option {
+	individualPkgs := append(slices.Clone(analyzer.TypeIndividualPkgs), tanalyzer.TypeIndividualPkgs...)
+	if opt.ScanArchives {
+		opt.ArchiveAnalyzers = append(opt.ArchiveAnalyzers, individualPkgs...)
+	} else {
+		opt.DisabledAnalyzers = append(opt.DisabledAnalyzers, individualPkgs...)
+	}
+	return opt
+}
+
+// rootfsOption returns the options for rootfs scanning
+func rootfsOption(opt Option) Option {
+	// Disable the lock file scanning
+	opt.DisabledAnalyzers = append(opt.DisabledAnalyzers, analyzer.TypeLockfiles...)
+	opt.DisabledAnalyzers = append(opt.DisabledAnalyzers, tanalyzer.TypeLockfiles...)
+
+	// Resolve symlinks relative to the rootfs, not the host
+	opt.Rootfs = true
+	return opt
+}
+
+// repositoryOption returns the options for repository scanning
+func repositoryOption(opt Option) Option {
 	// Do not scan OS packages
 	opt.VulnType = []string{types.VulnTypeLibrary}
 
 	// Disable the OS analyzers and individual package analyzers
-	opt.DisabledAnalyzers = append(analyzer.TypeIndividualPkgs, analyzer.TypeOSes...)
-
-	return r.scanArtifact(ctx, opt, repositoryStandaloneScanner)
+	opt.DisabledAnalyzers = append(slices.Clone(analyzer.TypeOSes), tanalyzer.TypeOSes...)
+	opt.DisabledAnalyzers = append(opt.DisabledAnalyzers, akernel.TypeKernel, asbom.TypeEmbeddedSBOM)
+	return disableIndividualPkgs(opt)
 }
 
 func (r *runner) scanArtifact(ctx context.Context, opt Option, initializeScanner InitializeScanner) (types.Report, error) {
@@ -221,21 +413,119 @@ func (r *runner) scanArtifact(ctx context.Context, opt Option, initializeScanner
 }
 
 func (r *runner) Filter(ctx context.Context, opt Option, report types.Report) (types.Report, error) {
-	results := report.Results
+	if err := filterResults(ctx, opt, report.Results); err != nil {
+		return types.Report{}, err
+	}
+	return report, nil
+}
+
+// filterResults filters the results in place
+func filterResults(ctx context.Context, opt Option, results types.Results) error {
+	var baseline *pkgSecret.Baseline
+	if opt.SecretBaseline != "" {
+		b, err := pkgSecret.ReadBaseline(opt.SecretBaseline)
+		if err != nil {
+			return xerrors.Errorf("unable to read the secret baseline: %w", err)
+		}
+		baseline = b
+	}
+
+	// The license policy is applied before filtering by severity
+	if opt.LicensePolicy != "" {
+		if err := licensing.ApplyPolicy(ctx, opt.LicensePolicy, results); err != nil {
+			return xerrors.Errorf("unable to apply the license policy: %w", err)
+		}
+	}
+
+	// Check overrides are applied before filtering by severity
+	if opt.CheckOverrides != "" {
+		overrides, err := policy.LoadOverrides(opt.CheckOverrides)
+		if err != nil {
+			return xerrors.Errorf("unable to load the check overrides: %w", err)
+		}
+		overrides.Apply(results)
+	}
+
+	if opt.IgnoreBaseImageVulns {
+		baseimage.RemoveVulnerabilities(results)
+	}
+
+	// The owners file takes precedence over CODEOWNERS applied in scanning
+	if opt.OwnersFile != "" {
+		rules, err := ownership.Load(opt.OwnersFile)
+		if err != nil {
+			return xerrors.Errorf("unable to load the owners file: %w", err)
+		}
+		ownership.Assign(results, rules)
+	}
+
+	var riskModel *risk.Model
+	if opt.RiskScore {
+		m, err := risk.Load(opt.RiskWeights, opt.EPSSFile, opt.KEVFile, opt.ReachabilityFile)
+		if err != nil {
+			return xerrors.Errorf("unable to load the risk data: %w", err)
+		}
+		riskModel = &m
+	}
 
 	// Filter results
 	for i := range results {
-		vulns, misconfSummary, misconfs, secrets, err := result.Filter(ctx, results[i].Vulnerabilities, results[i].Misconfigurations, results[i].Secrets,
-			opt.Severities, opt.IgnoreUnfixed, opt.IncludeNonFailures, opt.IgnoreFile, opt.IgnorePolicy)
+		vulns, misconfSummary, misconfs, secrets, licenses, err := result.Filter(ctx, results[i].Vulnerabilities, results[i].Misconfigurations, results[i].Secrets,
+			results[i].Licenses, opt.Severities, opt.IgnoreUnfixed, opt.IncludeNonFailures, opt.IgnoreFile, opt.IgnorePolicy)
 		if err != nil {
-			return types.Report{}, xerrors.Errorf("unable to filter vulnerabilities: %w", err)
+			return xerrors.Errorf("unable to filter vulnerabilities: %w", err)
 		}
 		results[i].Vulnerabilities = vulns
 		results[i].Misconfigurations = misconfs
 		results[i].MisconfSummary = misconfSummary
 		results[i].Secrets = secrets
+		results[i].Licenses = licenses
+
+		// Known secrets in the baseline are suppressed
+		if baseline != nil {
+			results[i].Secrets = baseline.Filter(secrets)
+		}
+
+		// Only the vulnerabilities left after filtering are scored, sorted by the scores
+		if riskModel != nil {
+			riskModel.Apply(&results[i])
+		}
+
+		// Upgrades are computed only for the vulnerabilities left after filtering
+		if opt.FixAdvice {
+			results[i].Remediations = remediation.Advise(results[i])
+		}
 	}
-	return report, nil
+	return nil
+}
+
+// failFast returns a function reporting whether the results have findings failing the scan after filtering.
+// The results are filtered on a copy as they are filtered again after scanning.
+func failFast(ctx context.Context, opt Option) func(types.Results) bool {
+	// Upgrades and risk scores are not needed to decide
+	opt.FixAdvice = false
+	opt.RiskScore = false
+	return func(results types.Results) bool {
+		cloned := cloneResults(results)
+		if err := filterResults(ctx, opt, cloned); err != nil {
+			log.Logger.Debugf("Unable to filter the results for --fail-fast: %s", err)
+			return false
+		}
+		return cloned.Failed()
+	}
+}
+
+// cloneResults copies the findings so that the results can be filtered without modifying the original ones
+func cloneResults(results types.Results) types.Results {
+	cloned := make(types.Results, len(results))
+	for i, r := range results {
+		r.Vulnerabilities = slices.Clone(r.Vulnerabilities)
+		r.Misconfigurations = slices.Clone(r.Misconfigurations)
+		r.Secrets = slices.Clone(r.Secrets)
+		r.Licenses = slices.Clone(r.Licenses)
+		cloned[i] = r
+	}
+	return cloned
 }
 
 func (r *runner) Report(opt Option, report types.Report) error {
@@ -246,8 +536,10 @@ func (r *runner) Report(opt Option, report types.Report) error {
 		Tree:               opt.DependencyTree,
 		Severities:         opt.Severities,
 		OutputTemplate:     opt.Template,
+		SbomDetail:         opt.SbomDetail,
 		IncludeNonFailures: opt.IncludeNonFailures,
 		Trace:              opt.Trace,
+		Report:             opt.ReportView,
 	}); err != nil {
 		return xerrors.Errorf("unable to write results: %w", err)
 	}
@@ -255,7 +547,7 @@ func (r *runner) Report(opt Option, report types.Report) error {
 	return nil
 }
 
-func (r *runner) initDB(c Option) error {
+func (r *runner) initDB(ctx context.Context, c Option) error {
 	// When scanning config files or running as client mode, it doesn't need to download the vulnerability database.
 	if c.RemoteAddr != "" || !slices.Contains(c.SecurityChecks, types.SecurityCheckVulnerability) {
 		return nil
@@ -263,7 +555,17 @@ func (r *runner) initDB(c Option) error {
 
 	// download the database file
 	noProgress := c.Quiet || c.NoProgress
-	if err := operation.DownloadDB(c.AppVersion, c.CacheDir, c.DBRepository, noProgress, c.Insecure, c.SkipDBUpdate); err != nil {
+	dbDir := c.CacheDir
+	err := timeout.Run(ctx, timeout.PhaseDBUpdate, c.DBTimeout, func(ctx context.Context) error {
+		// The pinned snapshot is stored apart from the latest DB
+		if c.DBSnapshot != "" {
+			var err error
+			dbDir, err = operation.DownloadDBSnapshot(ctx, c.CacheDir, c.DBRepository, c.DBSnapshot, noProgress, c.Insecure, c.SkipDBUpdate)
+			return err
+		}
+		return operation.DownloadDB(ctx, c.AppVersion, c.CacheDir, c.DBRepository, noProgress, c.Insecure, c.SkipDBUpdate)
+	})
+	if err != nil {
 		return err
 	}
 
@@ -271,11 +573,16 @@ func (r *runner) initDB(c Option) error {
 		return SkipScan
 	}
 
-	if err := db.Init(c.CacheDir); err != nil {
+	if err = db.Init(dbDir); err != nil {
 		return xerrors.Errorf("error in vulnerability DB initialize: %w", err)
 	}
 	r.dbOpen = true
 
+	if err = operation.InitAdvisoryFeeds(ctx, c.AdvisoryFeeds, c.CacheDir, noProgress, c.Insecure, c.SkipDBUpdate); err != nil {
+		return xerrors.Errorf("advisory feed error: %w", err)
+	}
+	operation.InitOSVOnline(c.OSVOnline, c.Insecure)
+
 	return nil
 }
 
@@ -326,6 +633,10 @@ func Run(cliCtx *cli.Context, artifactType ArtifactType) error {
 		return err
 	}
 
+	if opt.Watch && artifactType == filesystemArtifact {
+		return watch(cliCtx.Context, opt)
+	}
+
 	return run(cliCtx.Context, opt, artifactType)
 }
 
@@ -334,11 +645,20 @@ func run(ctx context.Context, opt Option, artifactType ArtifactType) (err error)
 	defer cancel()
 
 	defer func() {
-		if xerrors.Is(err, context.DeadlineExceeded) {
-			log.Logger.Warn("Increase --timeout value")
-		}
+		WarnTimeout(err)
 	}()
 
+	if opt.DryRun {
+		return dryRun(os.Stdout, opt, artifactType)
+	}
+
+	// The scan ID is attached to the logs and sent to the server for correlation
+	scanID := log.NewScanID()
+	log.SetScanID(scanID)
+	if opt.RemoteAddr != "" {
+		opt.CustomHeaders.Set(log.ScanIDHeader, scanID)
+	}
+
 	r, err := NewRunner(opt)
 	if err != nil {
 		if errors.Is(err, SkipScan) {
@@ -366,6 +686,21 @@ func run(ctx context.Context, opt Option, artifactType ArtifactType) (err error)
 		if report, err = r.ScanRepository(ctx, opt); err != nil {
 			return xerrors.Errorf("repository scan error: %w", err)
 		}
+	case sbomArtifact:
+		if report, err = r.ScanSBOM(ctx, opt); err != nil {
+			return xerrors.Errorf("sbom scan error: %w", err)
+		}
+	}
+
+	report.Trivy = scanMetadata(opt, artifactType)
+	report.Metadata.Labels = opt.Labels
+
+	// The baseline has all the detected secrets regardless of filters
+	if opt.SecretBaselineOut != "" {
+		if err = pkgSecret.NewBaseline(report.Results).Write(opt.SecretBaselineOut); err != nil {
+			return xerrors.Errorf("unable to write the secret baseline: %w", err)
+		}
+		log.Logger.Infof("Secret baseline has been written to %s", opt.SecretBaselineOut)
 	}
 
 	report, err = r.Filter(ctx, opt, report)
@@ -377,11 +712,79 @@ func run(ctx context.Context, opt Option, artifactType ArtifactType) (err error)
 		return xerrors.Errorf("report error: %w", err)
 	}
 
+	if opt.SaveHistory {
+		if err = saveHistory(opt, report); err != nil {
+			return xerrors.Errorf("history error: %w", err)
+		}
+	}
+
+	if opt.GatePolicy != "" {
+		return evaluateGate(ctx, opt, report)
+	}
+
 	Exit(opt, report.Results.Failed())
 
 	return nil
 }
 
+// evaluateGate decides whether the report passes with the gate policy instead of the findings,
+// and exits with --exit-code, or 1 if it is not set, when the gate fails.
+func evaluateGate(ctx context.Context, opt Option, report types.Report) error {
+	result, err := gate.Evaluate(ctx, opt.GatePolicy, report)
+	if err != nil {
+		return xerrors.Errorf("gate error: %w", err)
+	}
+
+	// The report may be written to stdout
+	if err = result.Write(os.Stderr); err != nil {
+		return xerrors.Errorf("gate error: %w", err)
+	}
+
+	if !result.Passed() {
+		exitCode := opt.ExitCode
+		if exitCode == 0 {
+			exitCode = 1
+		}
+		os.Exit(exitCode)
+	}
+	return nil
+}
+
+// saveHistory records the summary of the report in the local history
+func saveHistory(opt Option, report types.Report) error {
+	store, err := history.Open(history.Path(opt.CacheDir))
+	if err != nil {
+		return xerrors.Errorf("history store error: %w", err)
+	}
+	defer store.Close()
+
+	if _, err = store.Record(report); err != nil {
+		return xerrors.Errorf("unable to record the scan: %w", err)
+	}
+	log.Logger.Debugf("The scan of %s has been recorded in the history", report.ArtifactName)
+	return nil
+}
+
+// timeoutFlags are the flags limiting the phases
+var timeoutFlags = map[timeout.Phase]string{
+	timeout.PhaseDBUpdate: "db-timeout",
+	timeout.PhasePull:     "pull-timeout",
+	timeout.PhaseAnalysis: "analysis-timeout",
+	timeout.PhaseTarget:   "target-timeout",
+	timeout.PhasePolicy:   "policy-timeout",
+}
+
+// WarnTimeout suggests the flag to be increased if the error is caused by a timeout
+func WarnTimeout(err error) {
+	var exceeded *timeout.ExceededError
+	switch {
+	case errors.As(err, &exceeded):
+		log.Logger.Warnf("The %s phase exceeded its timeout. Increase --%s value", exceeded.Phase, timeoutFlags[exceeded.Phase])
+	case xerrors.Is(err, context.DeadlineExceeded):
+		log.Logger.Warn("Increase --timeout value")
+	}
+}
+
 func InitOption(ctx *cli.Context) (Option, error) {
 	opt, err := NewOption(ctx)
 	if err != nil {
@@ -401,6 +804,9 @@ func disabledAnalyzers(opt Option) []analyzer.Type {
 	// e.g. The 'image' subcommand should disable the lock file scanning.
 	analyzers := opt.DisabledAnalyzers
 
+	// Analyzers deselected with --enable-analyzers and --disable-analyzers
+	analyzers = append(analyzers, selectedAnalyzers(opt)...)
+
 	// It doesn't analyze apk commands by default.
 	if !opt.ScanRemovedPkgs {
 		analyzers = append(analyzers, analyzer.TypeApkCommand)
@@ -409,6 +815,17 @@ func disabledAnalyzers(opt Option) []analyzer.Type {
 	// Do not analyze programming language packages when not running in 'library' mode
 	if !slices.Contains(opt.VulnType, types.VulnTypeLibrary) {
 		analyzers = append(analyzers, analyzer.TypeLanguages...)
+		analyzers = append(analyzers, tanalyzer.TypeLanguages...)
+	}
+
+	// Do not parse CODEOWNERS unless '--codeowners' is specified.
+	if !opt.Codeowners {
+		analyzers = append(analyzers, acodeowners.TypeCodeowners)
+	}
+
+	// Do not detect installed kernels when not running in 'kernel' mode
+	if !slices.Contains(opt.VulnType, types.VulnTypeKernel) {
+		analyzers = append(analyzers, akernel.TypeKernel)
 	}
 
 	// Do not perform secret scanning when it is not specified.
@@ -419,12 +836,212 @@ func disabledAnalyzers(opt Option) []analyzer.Type {
 	// Do not perform misconfiguration scanning when it is not specified.
 	if !slices.Contains(opt.SecurityChecks, types.SecurityCheckConfig) {
 		analyzers = append(analyzers, analyzer.TypeConfigFiles...)
+		analyzers = append(analyzers, tanalyzer.TypeConfigFiles...)
+	}
+
+	// Do not classify license files unless '--license-full' is specified.
+	if !slices.Contains(opt.SecurityChecks, types.SecurityCheckLicense) || !opt.LicenseFull {
+		analyzers = append(analyzers, alicensing.TypeLicenseFile)
 	}
 
 	return analyzers
 }
 
-func initScannerConfig(opt Option, cacheClient cache.Cache) (ScannerConfig, types.ScanOptions, error) {
+func downloadChecksBundles(ctx context.Context, opt Option) ([]policy.Bundle, error) {
+	if len(opt.ChecksBundles) == 0 {
+		return nil, nil
+	}
+
+	var key crypto.PublicKey
+	if opt.ChecksBundleKey != "" {
+		var err error
+		if key, err = tsbom.LoadPublicKey(opt.ChecksBundleKey); err != nil {
+			return nil, xerrors.Errorf("public key error: %w", err)
+		}
+	}
+
+	return policy.DownloadBundles(ctx, opt.ChecksBundles, policy.BundleOption{
+		CacheDir:   utils.CacheDir(),
+		PublicKey:  key,
+		SkipUpdate: opt.SkipPolicyUpdate,
+		Offline:    opt.OfflineScan,
+		Insecure:   opt.Insecure,
+		Quiet:      opt.Quiet,
+	})
+}
+
+// attributeBaseImage tells whether each vulnerability is introduced by the base image or the layers added on it
+func attributeBaseImage(opt Option, report *types.Report) error {
+	var index *baseimage.Index
+	if opt.BaseImageIndex != "" {
+		var err error
+		if index, err = baseimage.LoadIndex(opt.BaseImageIndex); err != nil {
+			return err
+		}
+	}
+
+	base := baseimage.Detect(report.Metadata.ImageConfig, report.Metadata.DiffIDs, index)
+	if base == nil {
+		if opt.IgnoreBaseImageVulns {
+			log.Logger.Warn("The base image is not identified, so --ignore-base-image-vulns doesn't filter vulnerabilities")
+		}
+		return nil
+	}
+
+	if base.Name != "" {
+		log.Logger.Infof("Detected base image: %s", base.Name)
+	} else {
+		log.Logger.Debugf("The base image is guessed from the history: %d layers", len(base.DiffIDs))
+	}
+	report.Metadata.BaseImage = base
+	baseimage.Attribute(report.Results, base)
+	return nil
+}
+
+// recommendBaseImage scans newer tags of the base image and compares their OS vulnerabilities with those in the base image
+func (r *runner) recommendBaseImage(ctx context.Context, opt Option, report types.Report) ([]types.Recommendation, error) {
+	base := report.Metadata.BaseImage
+	if base == nil || base.Name == "" {
+		log.Logger.Warn("The base image is not found in --base-image-index, so no upgrade is recommended")
+		return nil, nil
+	}
+
+	candidates, err := baseimage.Candidates(base.Name, opt.Insecure)
+	if err != nil {
+		return nil, err
+	} else if len(candidates) == 0 {
+		log.Logger.Infof("No newer tag of %s found", base.Name)
+		return nil, nil
+	}
+
+	// The same filters as the report are applied to compare the vulnerabilities
+	current := make(types.Results, len(report.Results))
+	for i, res := range report.Results {
+		vulns, err := filterVulnerabilities(ctx, opt, res.Vulnerabilities)
+		if err != nil {
+			return nil, err
+		}
+		current[i] = res
+		current[i].Vulnerabilities = vulns
+	}
+
+	s := imageStandaloneScanner
+	if opt.RemoteAddr != "" {
+		s = imageRemoteScanner
+	}
+
+	var names []string
+	var candidateVulns [][]types.DetectedVulnerability
+	for _, candidate := range candidates {
+		log.Logger.Infof("Scanning %s as a candidate of the base image...", candidate)
+		candidateOpt := opt
+		candidateOpt.Target = candidate.String()
+		candidateOpt.Input = ""
+		candidateOpt.SecurityChecks = []string{types.SecurityCheckVulnerability}
+		candidateOpt.VulnType = []string{types.VulnTypeOS}
+
+		candidateReport, err := r.scanArtifact(ctx, candidateOpt, s)
+		if err != nil {
+			// e.g. the tag doesn't support the platform
+			log.Logger.Warnf("Unable to scan %s: %s", candidate, err)
+			continue
+		}
+
+		var vulns []types.DetectedVulnerability
+		for _, res := range candidateReport.Results {
+			if res.Class != types.ClassOSPkg {
+				continue
+			}
+			filtered, err := filterVulnerabilities(ctx, opt, res.Vulnerabilities)
+			if err != nil {
+				return nil, err
+			}
+			vulns = append(vulns, filtered...)
+		}
+		names = append(names, candidate.String())
+		candidateVulns = append(candidateVulns, vulns)
+	}
+	return baseimage.Recommend(base.Name, current, names, candidateVulns), nil
+}
+
+// filterVulnerabilities filters the vulnerabilities by severity and ignore rules without modifying the given slice
+func filterVulnerabilities(ctx context.Context, opt Option, vulns []types.DetectedVulnerability) ([]types.DetectedVulnerability, error) {
+	filtered, _, _, _, _, err := result.Filter(ctx, slices.Clone(vulns), nil, nil, nil, opt.Severities,
+		opt.IgnoreUnfixed, opt.IncludeNonFailures, opt.IgnoreFile, opt.IgnorePolicy)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to filter vulnerabilities: %w", err)
+	}
+	return filtered, nil
+}
+
+// verifyProvenance returns the result with the violations of the provenance policy, or nil if the image complies with it
+func verifyProvenance(opt Option, report types.Report) (*types.Result, error) {
+	var key crypto.PublicKey
+	if opt.ProvenanceKey != "" {
+		var err error
+		if key, err = tsbom.LoadPublicKey(opt.ProvenanceKey); err != nil {
+			return nil, xerrors.Errorf("public key error: %w", err)
+		}
+	} else {
+		log.Logger.Warn("The signatures of provenance attestations are NOT verified. Specify --provenance-key to verify them.")
+	}
+
+	violations, err := provenance.Verify(opt.Target, report.Metadata.RepoDigests, provenance.Option{
+		Policy: provenance.Policy{
+			PublicKey: key,
+			Builders:  opt.ProvenanceBuilders,
+			Sources:   opt.ProvenanceSources,
+		},
+		Insecure: opt.Insecure,
+	})
+	if err != nil {
+		return nil, err
+	} else if len(violations) == 0 {
+		log.Logger.Infof("The provenance of the image has been verified")
+		return nil, nil
+	}
+
+	return &types.Result{
+		Target:               opt.Target,
+		Class:                types.ClassProvenance,
+		ProvenanceViolations: violations,
+	}, nil
+}
+
+// verifySignature returns the result with the unsigned image or invalid signatures, or nil if one of the signatures is valid
+func verifySignature(opt Option, report types.Report) (*types.Result, error) {
+	sigOpt := signature.Option{Insecure: opt.Insecure}
+	if opt.SignatureKey != "" {
+		key, err := tsbom.LoadPublicKey(opt.SignatureKey)
+		if err != nil {
+			return nil, xerrors.Errorf("public key error: %w", err)
+		}
+		sigOpt.PublicKey = key
+	}
+	if opt.SignatureCert != "" {
+		roots, err := signature.LoadCertificates(opt.SignatureCert)
+		if err != nil {
+			return nil, xerrors.Errorf("certificate error: %w", err)
+		}
+		sigOpt.Roots = roots
+	}
+
+	violations, err := signature.Verify(opt.Target, report.Metadata.RepoDigests, sigOpt)
+	if err != nil {
+		return nil, err
+	} else if len(violations) == 0 {
+		log.Logger.Infof("The signature of the image has been verified")
+		return nil, nil
+	}
+
+	return &types.Result{
+		Target:              opt.Target,
+		Class:               types.ClassSignature,
+		SignatureViolations: violations,
+	}, nil
+}
+
+func initScannerConfig(ctx context.Context, opt Option, cacheClient cache.Cache) (ScannerConfig, types.ScanOptions, error) {
 	target := opt.Target
 	if opt.Input != "" {
 		target = opt.Input
@@ -435,6 +1052,14 @@ func initScannerConfig(opt Option, cacheClient cache.Cache) (ScannerConfig, type
 		SecurityChecks:      opt.SecurityChecks,
 		ScanRemovedPackages: opt.ScanRemovedPkgs, // this is valid only for 'image' subcommand
 		ListAllPackages:     opt.ListAllPkgs,
+		IncludeDevDeps:      opt.IncludeDevDeps,
+		Parallel:            opt.Parallel,
+		TargetTimeout:       opt.TargetTimeout,
+		ContinueOnError:     opt.ContinueOnError,
+	}
+
+	if opt.FailFast {
+		scanOptions.FailFast = failFast(ctx, opt)
 	}
 
 	if slices.Contains(opt.SecurityChecks, types.SecurityCheckVulnerability) {
@@ -442,10 +1067,29 @@ func initScannerConfig(opt Option, cacheClient cache.Cache) (ScannerConfig, type
 		log.Logger.Debugf("Vulnerability type:  %s", scanOptions.VulnType)
 	}
 
+	if slices.Contains(opt.SecurityChecks, types.SecurityCheckLicense) {
+		log.Logger.Info("License scanning is enabled")
+		scanOptions.LicenseCategories = licensing.Categories(opt.LicenseForbidden, opt.LicenseRestricted, opt.LicenseAllowed)
+	}
+
 	// ScannerOption is filled only when config scanning is enabled.
 	var configScannerOptions config.ScannerOption
+	var helmOption helm.Option
+	var cfParams cloudformation.Parameters
 	if slices.Contains(opt.SecurityChecks, types.SecurityCheckConfig) {
 		log.Logger.Info("Misconfiguration scanning is enabled")
+
+		// Custom check bundles are evaluated like the local policies
+		bundles, err := downloadChecksBundles(ctx, opt)
+		if err != nil {
+			return ScannerConfig{}, types.ScanOptions{}, xerrors.Errorf("check bundle error: %w", err)
+		}
+		for _, b := range bundles {
+			opt.PolicyPaths = append(opt.PolicyPaths, b.Dir)
+			opt.DataPaths = append(opt.DataPaths, b.Dir)
+			opt.PolicyNamespaces = append(opt.PolicyNamespaces, b.Namespaces...)
+		}
+
 		configScannerOptions = config.ScannerOption{
 			Trace:        opt.Trace,
 			Namespaces:   append(opt.PolicyNamespaces, defaultPolicyNamespaces...),
@@ -453,6 +1097,17 @@ func initScannerConfig(opt Option, cacheClient cache.Cache) (ScannerConfig, type
 			DataPaths:    opt.DataPaths,
 			FilePatterns: opt.FilePatterns,
 		}
+		helmOption = helm.Option{
+			ValueFiles:  opt.HelmValueFiles,
+			Values:      opt.HelmValues,
+			KubeVersion: opt.HelmKubeVersion,
+			APIVersions: opt.HelmAPIVersions,
+		}
+
+		cfParams, err = cloudformation.LoadParameters(opt.CloudFormationParamFiles)
+		if err != nil {
+			return ScannerConfig{}, types.ScanOptions{}, xerrors.Errorf("CloudFormation parameter error: %w", err)
+		}
 	}
 
 	// Do not load config file for secret scanning
@@ -466,7 +1121,26 @@ func initScannerConfig(opt Option, cacheClient cache.Cache) (ScannerConfig, type
 		log.Logger.Infof("Please see also https://aquasecurity.github.io/trivy/%s/docs/secret/scanning/#recommendation for faster secret detection", ver)
 	} else {
 		opt.SecretConfigPath = ""
+		opt.SecretVerify = false
+		opt.SecretHistory = false
+		opt.SecretScanArchives = false
+		opt.SecretScanBinaries = false
+	}
+	secretScannerOption := tsecret.ScannerOption{
+		ConfigPath:   opt.SecretConfigPath,
+		Verify:       opt.SecretVerify,
+		ScanArchives: opt.SecretScanArchives,
+		ScanBinaries: opt.SecretScanBinaries,
+		Redaction:    opt.SecretRedaction,
 	}
+	if opt.SecretVerify {
+		log.Logger.Info("Secret verification is enabled, detected secrets are sent to the provider APIs")
+	}
+
+	reporter := progressReporter(opt)
+
+	disabled := disabledAnalyzers(opt)
+	warnUnusedAnalyzers(opt, disabled)
 
 	return ScannerConfig{
 		Target:             target,
@@ -478,7 +1152,7 @@ func initScannerConfig(opt Option, cacheClient cache.Cache) (ScannerConfig, type
 			Insecure:      opt.Insecure,
 		},
 		ArtifactOption: artifact.Option{
-			DisabledAnalyzers: disabledAnalyzers(opt),
+			DisabledAnalyzers: disabled,
 			SkipFiles:         opt.SkipFiles,
 			SkipDirs:          opt.SkipDirs,
 			InsecureSkipTLS:   opt.Insecure,
@@ -487,30 +1161,109 @@ func initScannerConfig(opt Option, cacheClient cache.Cache) (ScannerConfig, type
 
 			// For misconfiguration scanning
 			MisconfScannerOption: configScannerOptions,
-
-			// For secret scanning
-			SecretScannerOption: secret.ScannerOption{
-				ConfigPath: opt.SecretConfigPath,
-			},
+		},
+		RepoOption: remote.Option{
+			Branch: opt.RepoBranch,
+			Tag:    opt.RepoTag,
+			Commit: opt.RepoCommit,
+			Depth:  opt.RepoDepth,
+
+			Username: opt.GitUsername,
+			Token:    opt.GitToken,
+		},
+		LocalOption: local.Option{
+			DiffBase: opt.DiffBase,
+			Files:    opt.Files,
+			Rootfs:   opt.Rootfs,
+			Parallel: opt.Parallel,
+
+			FollowSymlinks:    opt.FollowSymlinks,
+			IncludeSystemDirs: opt.IncludeSystemDirs,
+			FileSizeLimits:    opt.FileSizeLimits,
+
+			ScanArchives:     opt.ScanArchives,
+			ArchiveDepth:     opt.ArchiveDepth,
+			ArchiveSizeLimit: opt.ArchiveSizeLimit,
+			ArchiveAnalyzers: opt.ArchiveAnalyzers,
+			TmpDir:           opt.TmpDir,
+
+			IncludePaths: opt.IncludePaths,
+			ExcludePaths: opt.ExcludePaths,
+			UseGitignore: opt.UseGitignore,
+
+			SecretScannerOption: secretScannerOption,
+			SecretHistory:       opt.SecretHistory,
+			SecretHistoryDepth:  opt.SecretHistoryDepth,
+
+			HelmOption:               helmOption,
+			CloudFormationParameters: cfParams,
+
+			PolicyTimeout:   opt.PolicyTimeout,
+			Progress:        reporter,
+			ContinueOnError: opt.ContinueOnError,
+		},
+		ContainerOption: image.ContainerOption{
+			Offline:       opt.OfflineScan,
+			RegistryToken: opt.RegistryToken,
+		},
+		ImageOption: aimage.Option{
+			SecretScannerOption: secretScannerOption,
+			PolicyTimeout:       opt.PolicyTimeout,
+			Progress:            reporter,
+			TmpDir:              opt.TmpDir,
+			TmpSizeLimit:        opt.TmpSizeLimit,
+			FileSizeLimits:      opt.FileSizeLimits,
+			ContinueOnError:     opt.ContinueOnError,
+			Parallel:            opt.Parallel,
+		},
+		ArchiveOption: image.ArchiveOption{
+			Platform: opt.Platform,
+		},
+		SBOMOption: sbom.Option{
+			AttestationKey:                opt.AttestationKey,
+			InsecureSkipAttestationVerify: opt.InsecureSkipAttestationVerify,
 		},
 	}, scanOptions, nil
 }
 
+// progressReporter returns the reporter of analysis progress.
+// Progress bars are shown only in terminals so that CI logs are not cluttered.
+func progressReporter(opt Option) progress.Reporter {
+	switch {
+	case opt.QuietProgress:
+		return progress.NewEventWriter(os.Stderr)
+	case opt.Quiet || opt.NoProgress || !term.IsTerminal(int(os.Stderr.Fd())):
+		return progress.Nop()
+	}
+	return progress.NewBar(os.Stderr)
+}
+
 func scan(ctx context.Context, opt Option, initializeScanner InitializeScanner, cacheClient cache.Cache) (
 	types.Report, error) {
 
-	scannerConfig, scanOptions, err := initScannerConfig(opt, cacheClient)
+	scannerConfig, scanOptions, err := initScannerConfig(ctx, opt, cacheClient)
 	if err != nil {
 		return types.Report{}, err
 	}
 
-	s, cleanup, err := initializeScanner(ctx, scannerConfig)
+	// Images are pulled and repositories are cloned when the scanner is initialized
+	var s scanner.Scanner
+	var cleanup func()
+	cancel, err := timeout.RunLazy(ctx, timeout.PhasePull, opt.PullTimeout, func(ctx context.Context) error {
+		s, cleanup, err = initializeScanner(ctx, scannerConfig)
+		return err
+	})
+	defer cancel()
 	if err != nil {
 		return types.Report{}, xerrors.Errorf("unable to initialize a scanner: %w", err)
 	}
 	defer cleanup()
 
-	report, err := s.ScanArtifact(ctx, scanOptions)
+	var report types.Report
+	err = timeout.Run(ctx, timeout.PhaseAnalysis, opt.AnalysisTimeout, func(ctx context.Context) error {
+		report, err = s.ScanArtifact(ctx, scanOptions)
+		return err
+	})
 	if err != nil {
 		return types.Report{}, xerrors.Errorf("image scan failed: %w", err)
 	}
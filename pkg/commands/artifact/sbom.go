@@ -1,14 +1,50 @@
 package artifact
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
 	"github.com/urfave/cli/v2"
 	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
+	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/artifact/sbom"
+	"github.com/aquasecurity/trivy/pkg/commands/option"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/report"
+	tsbom "github.com/aquasecurity/trivy/pkg/sbom"
+	"github.com/aquasecurity/trivy/pkg/scanner"
 	"github.com/aquasecurity/trivy/pkg/types"
 )
 
-// SbomRun runs generates sbom for image and package artifacts
+// mergeFormats is a list of formats available for "trivy sbom merge"
+var mergeFormats = []string{report.FormatCycloneDX, report.FormatSPDX, report.FormatSPDXJSON}
+
+// sbomStandaloneScanner initializes a SBOM scanner in standalone mode
+func sbomStandaloneScanner(ctx context.Context, conf ScannerConfig) (scanner.Scanner, func(), error) {
+	s, cleanup, err := initializeSBOMScanner(ctx, conf.Target, conf.ArtifactCache, conf.LocalArtifactCache, conf.ArtifactOption,
+		conf.SBOMOption)
+	if err != nil {
+		return scanner.Scanner{}, func() {}, xerrors.Errorf("unable to initialize a SBOM scanner: %w", err)
+	}
+	return s, cleanup, nil
+}
+
+// sbomRemoteScanner initializes a SBOM scanner in client/server mode
+func sbomRemoteScanner(ctx context.Context, conf ScannerConfig) (scanner.Scanner, func(), error) {
+	s, cleanup, err := initializeRemoteSBOMScanner(ctx, conf.Target, conf.ArtifactCache, conf.RemoteOption, conf.ArtifactOption,
+		conf.SBOMOption)
+	if err != nil {
+		return scanner.Scanner{}, func() {}, xerrors.Errorf("unable to initialize a SBOM scanner: %w", err)
+	}
+	return s, cleanup, nil
+}
+
+// SbomRun generates SBOM for image and package artifacts
 func SbomRun(ctx *cli.Context) error {
 	opt, err := InitOption(ctx)
 	if err != nil {
@@ -24,10 +60,149 @@ func SbomRun(ctx *cli.Context) error {
 	if artifactType == imageArchiveArtifact {
 		opt.Input = opt.Target
 	}
+	opt.ReportOption.Format = opt.SbomOption.SbomFormat
+	opt.ReportOption.ListAllPkgs = true
 
 	// Scan the relevant dependencies
-	opt.ReportOption.VulnType = []string{types.VulnTypeOS, types.VulnTypeLibrary}
+	opt.ReportOption.VulnType = []string{types.VulnTypeOS, types.VulnTypeLibrary, types.VulnTypeKernel}
 	opt.ReportOption.SecurityChecks = []string{types.SecurityCheckVulnerability}
 
 	return run(ctx.Context, opt, artifactType)
 }
+
+// SbomScanRun scans the given SBOM file for vulnerabilities
+func SbomScanRun(ctx *cli.Context) error {
+	opt, err := InitOption(ctx)
+	if err != nil {
+		return xerrors.Errorf("option error: %w", err)
+	}
+
+	// Scan vulnerabilities
+	opt.ReportOption.VulnType = []string{types.VulnTypeOS, types.VulnTypeLibrary, types.VulnTypeKernel}
+	opt.ReportOption.SecurityChecks = []string{types.SecurityCheckVulnerability}
+
+	return run(ctx.Context, opt, sbomArtifact)
+}
+
+// SbomMergeRun merges the given SBOM files into one SBOM document.
+// Packages are deduplicated by PURL, and libraries stay under their applications.
+func SbomMergeRun(ctx *cli.Context) error {
+	if ctx.NArg() < 2 {
+		cli.ShowSubcommandHelpAndExit(ctx, 1)
+	}
+
+	conf, err := option.NewGlobalOption(ctx)
+	if err != nil {
+		return xerrors.Errorf("option error: %w", err)
+	}
+	if err = log.InitLogger(conf.Debug, conf.Quiet); err != nil {
+		return xerrors.Errorf("failed to initialize a logger: %w", err)
+	}
+
+	format := ctx.String("format")
+	if !slices.Contains(mergeFormats, format) {
+		return xerrors.Errorf(`"--format" must be %q`, mergeFormats)
+	}
+
+	var output io.Writer = os.Stdout
+	if o := ctx.String("output"); o != "" {
+		f, err := os.Create(o)
+		if err != nil {
+			return xerrors.Errorf("failed to create an output file: %w", err)
+		}
+		defer f.Close()
+		output = f
+	}
+
+	paths := ctx.Args().Slice()
+	var boms []tsbom.SBOM
+	var artifactType ftypes.ArtifactType
+	for _, path := range paths {
+		bom, t, err := decodeSBOM(path)
+		if err != nil {
+			return xerrors.Errorf("SBOM error (%s): %w", path, err)
+		}
+		if artifactType == "" {
+			artifactType = t
+		}
+		boms = append(boms, bom)
+	}
+
+	merged, err := tsbom.Merge(boms)
+	if err != nil {
+		return xerrors.Errorf("merge error: %w", err)
+	}
+
+	artifactName := strings.Join(paths, ",")
+	r := types.Report{
+		SchemaVersion: report.SchemaVersion,
+		ArtifactName:  artifactName,
+		ArtifactType:  artifactType,
+		Metadata:      types.Metadata{OS: merged.OS},
+		Results:       sbomResults(artifactName, merged),
+	}
+
+	if err = report.Write(r, report.Option{
+		AppVersion: conf.AppVersion,
+		Format:     format,
+		Output:     output,
+	}); err != nil {
+		return xerrors.Errorf("unable to write the merged SBOM: %w", err)
+	}
+	return nil
+}
+
+func decodeSBOM(path string) (tsbom.SBOM, ftypes.ArtifactType, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return tsbom.SBOM{}, "", xerrors.Errorf("file open error: %w", err)
+	}
+	defer f.Close()
+
+	format, err := tsbom.DetectFormat(f)
+	if err != nil {
+		return tsbom.SBOM{}, "", xerrors.Errorf("failed to detect SBOM format: %w", err)
+	}
+	log.Logger.Debugf("Detected SBOM format of %s: %s", path, format)
+
+	bom, err := tsbom.Decode(f, format, tsbom.Option{})
+	if err != nil {
+		return tsbom.SBOM{}, "", xerrors.Errorf("SBOM decode error: %w", err)
+	}
+
+	artifactType := sbom.ArtifactCycloneDX
+	if format == tsbom.FormatSPDXJSON || format == tsbom.FormatSPDXTV || format == tsbom.FormatAttestSPDXJSON {
+		artifactType = sbom.ArtifactSPDX
+	}
+	return bom, artifactType, nil
+}
+
+// sbomResults converts the SBOM into results listing packages in the same way as scans
+func sbomResults(target string, bom tsbom.SBOM) types.Results {
+	var results types.Results
+	if bom.OS != nil {
+		var pkgs []ftypes.Package
+		for _, pkgInfo := range bom.Packages {
+			pkgs = append(pkgs, pkgInfo.Packages...)
+		}
+		results = append(results, types.Result{
+			Target:   fmt.Sprintf("%s (%s %s)", target, bom.OS.Family, bom.OS.Name),
+			Class:    types.ClassOSPkg,
+			Type:     bom.OS.Family,
+			Packages: pkgs,
+		})
+	}
+	for _, app := range bom.Applications {
+		target := app.FilePath
+		if target == "" {
+			target = app.Type
+		}
+		results = append(results, types.Result{
+			Target:   target,
+			Class:    types.ClassLangPkg,
+			Type:     app.Type,
+			Packages: app.Libraries,
+		})
+	}
+	return results
+}
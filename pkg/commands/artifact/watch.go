@@ -0,0 +1,149 @@
+package artifact
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"time"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/artifact"
+	"github.com/aquasecurity/trivy/pkg/artifact/local"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/types"
+	pkgWatch "github.com/aquasecurity/trivy/pkg/watch"
+)
+
+// watch scans the directory and rescans changed files until interrupted.
+// The report of each scan is written to the output as a line of JSON.
+func watch(ctx context.Context, opt Option) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if fi, err := os.Stat(opt.Target); err != nil {
+		return xerrors.Errorf("stat error: %w", err)
+	} else if !fi.IsDir() {
+		return xerrors.Errorf("--watch requires a directory: %s", opt.Target)
+	}
+
+	r, err := NewRunner(opt)
+	if err != nil {
+		if errors.Is(err, SkipScan) {
+			return nil
+		}
+		return xerrors.Errorf("init error: %w", err)
+	}
+	defer r.Close(ctx)
+
+	// The watcher takes the state of the files before the first scan so that changes during the scan are not missed
+	w, err := pkgWatch.New(opt.Target, pkgWatch.Option{
+		Interval: opt.WatchInterval,
+		Skip: local.Skipper(opt.Target, artifact.Option{
+			SkipFiles: opt.SkipFiles,
+			SkipDirs:  opt.SkipDirs,
+		}, local.Option{
+			IncludeSystemDirs: opt.IncludeSystemDirs,
+			IncludePaths:      opt.IncludePaths,
+			ExcludePaths:      opt.ExcludePaths,
+		}),
+	})
+	if err != nil {
+		return xerrors.Errorf("watch error: %w", err)
+	}
+
+	enc := json.NewEncoder(opt.Output)
+	emit := func(report types.Report, changed []string) error {
+		report.Results = cloneResults(report.Results)
+		if err := filterResults(ctx, opt, report.Results); err != nil {
+			return xerrors.Errorf("filter error: %w", err)
+		}
+		return enc.Encode(pkgWatch.Event{
+			Type:    pkgWatch.EventScan,
+			Time:    time.Now().UTC(),
+			Changed: changed,
+			Report:  &report,
+		})
+	}
+
+	// The first scan must succeed so that the following ones have the results to update
+	report, err := watchScan(ctx, r, opt)
+	if err != nil {
+		WarnTimeout(err)
+		return xerrors.Errorf("filesystem scan error: %w", err)
+	}
+	report.Trivy = scanMetadata(opt, filesystemArtifact)
+	report.Metadata.Labels = opt.Labels
+	if err = emit(report, nil); err != nil {
+		return err
+	}
+
+	log.Logger.Infof("Watching %s for changes...", opt.Target)
+	return w.Run(ctx, func(changed []string) error {
+		log.Logger.Infof("Rescanning %d changed files...", len(changed))
+
+		var files []string
+		for _, f := range changed {
+			if _, err := os.Stat(filepath.Join(opt.Target, filepath.FromSlash(f))); err == nil {
+				files = append(files, f)
+			}
+		}
+
+		var partial types.Report
+		if len(files) > 0 {
+			// Only the changed files are analyzed, and the git history is not rescanned
+			o := opt
+			o.Files = files
+			o.SecretHistory = false
+			if partial, err = watchScan(ctx, r, o); err != nil {
+				// Keep watching, as the files may be fixed by the next change
+				WarnTimeout(err)
+				log.Logger.Errorf("Rescan error: %s", err)
+				return enc.Encode(pkgWatch.Event{
+					Type:    pkgWatch.EventError,
+					Time:    time.Now().UTC(),
+					Changed: changed,
+					Error:   err.Error(),
+				})
+			}
+		}
+
+		report.Results = replaceResults(report.Results, partial.Results, changed)
+		return emit(report, changed)
+	})
+}
+
+// watchScan scans the filesystem within the timeout, which is applied to each scan in watch mode
+func watchScan(ctx context.Context, r Runner, opt Option) (types.Report, error) {
+	ctx, cancel := context.WithTimeout(ctx, opt.Timeout)
+	defer cancel()
+	return r.ScanFilesystem(ctx, opt)
+}
+
+// replaceResults replaces the results of the changed files and the files rescanned with them, e.g. lock files, with the new ones.
+// The results of removed files are dropped.
+func replaceResults(results, newResults types.Results, changed []string) types.Results {
+	rescanned := slices.Clone(changed)
+	for _, result := range newResults {
+		rescanned = append(rescanned, result.Target)
+	}
+
+	var replaced types.Results
+	for _, result := range results {
+		if !slices.Contains(rescanned, result.Target) {
+			replaced = append(replaced, result)
+		}
+	}
+	replaced = append(replaced, newResults...)
+
+	sort.SliceStable(replaced, func(i, j int) bool {
+		return replaced[i].Target < replaced[j].Target
+	})
+	return replaced
+}
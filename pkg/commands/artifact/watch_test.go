@@ -0,0 +1,60 @@
+package artifact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func Test_replaceResults(t *testing.T) {
+	results := types.Results{
+		{Target: "app/requirements.txt", Class: types.ClassLangPkg, Type: ftypes.Pip},
+		{Target: "app/requirements.txt", Class: types.ClassSecret},
+		{Target: "frontend/package-lock.json", Class: types.ClassLangPkg, Type: ftypes.Npm},
+		{Target: "go.mod", Class: types.ClassLangPkg, Type: ftypes.GoModule},
+		{Target: "requirements.txt", Class: types.ClassLangPkg, Type: ftypes.Pip},
+	}
+
+	tests := []struct {
+		name       string
+		newResults types.Results
+		changed    []string
+		want       types.Results
+	}{
+		{
+			name: "modified",
+			newResults: types.Results{
+				{Target: "app/requirements.txt", Class: types.ClassLangPkg, Type: ftypes.Pip},
+			},
+			changed: []string{"app/requirements.txt"},
+			want: types.Results{
+				{Target: "app/requirements.txt", Class: types.ClassLangPkg, Type: ftypes.Pip},
+				{Target: "frontend/package-lock.json", Class: types.ClassLangPkg, Type: ftypes.Npm},
+				{Target: "go.mod", Class: types.ClassLangPkg, Type: ftypes.GoModule},
+				{Target: "requirements.txt", Class: types.ClassLangPkg, Type: ftypes.Pip},
+			},
+		},
+		{
+			name: "lock file rescanned with the manifest",
+			newResults: types.Results{
+				{Target: "frontend/package-lock.json", Class: types.ClassLangPkg, Type: ftypes.Npm},
+			},
+			changed: []string{"frontend/package.json", "go.mod"},
+			want: types.Results{
+				{Target: "app/requirements.txt", Class: types.ClassLangPkg, Type: ftypes.Pip},
+				{Target: "app/requirements.txt", Class: types.ClassSecret},
+				{Target: "frontend/package-lock.json", Class: types.ClassLangPkg, Type: ftypes.Npm},
+				{Target: "requirements.txt", Class: types.ClassLangPkg, Type: ftypes.Pip},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := replaceResults(results, tt.newResults, tt.changed)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
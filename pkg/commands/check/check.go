@@ -0,0 +1,115 @@
+package check
+
+import (
+	"os"
+
+	"github.com/open-policy-agent/opa/tester"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/commands/option"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/policy"
+)
+
+const (
+	formatPretty = "pretty"
+	formatJSON   = "json"
+)
+
+// Test runs the tests of custom checks
+func Test(c *cli.Context) error {
+	if c.NArg() == 0 {
+		cli.ShowSubcommandHelpAndExit(c, 1)
+	}
+
+	if err := initLogger(c); err != nil {
+		return xerrors.Errorf("log initialization error: %w", err)
+	}
+
+	var reporter tester.Reporter
+	switch format := c.String("format"); format {
+	case formatPretty:
+		reporter = tester.PrettyReporter{
+			Output:      os.Stdout,
+			Verbose:     c.Bool("verbose"),
+			FailureLine: true,
+		}
+	case formatJSON:
+		reporter = tester.JSONReporter{Output: os.Stdout}
+	default:
+		return xerrors.Errorf("unknown format: %s", format)
+	}
+
+	results, err := policy.RunTests(c.Context, c.Args().Slice(), policy.TestOption{
+		DataPaths: c.StringSlice("data"),
+		Run:       c.String("run"),
+		Trace:     c.Bool("verbose"),
+		Timeout:   c.Duration("timeout"),
+	})
+	if err != nil {
+		return xerrors.Errorf("test error: %w", err)
+	}
+
+	var failed bool
+	ch := make(chan *tester.Result, len(results))
+	for _, r := range results {
+		failed = failed || !r.Pass() && !r.Skip
+		ch <- r
+	}
+	close(ch)
+
+	if err = reporter.Report(ch); err != nil {
+		return xerrors.Errorf("report error: %w", err)
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// Init generates a skeleton custom check and its test
+func Init(c *cli.Context) error {
+	if c.NArg() > 1 {
+		cli.ShowSubcommandHelpAndExit(c, 1)
+	}
+
+	if err := initLogger(c); err != nil {
+		return xerrors.Errorf("log initialization error: %w", err)
+	}
+
+	dir := c.Args().First()
+	if dir == "" {
+		dir = "."
+	}
+
+	files, err := policy.Scaffold(dir, policy.ScaffoldOption{
+		Type:      c.String("type"),
+		ID:        c.String("id"),
+		Title:     c.String("title"),
+		Severity:  c.String("severity"),
+		Namespace: c.String("namespace"),
+	})
+	if err != nil {
+		return xerrors.Errorf("scaffold error: %w", err)
+	}
+
+	for _, f := range files {
+		log.Logger.Infof("Created %s", f)
+	}
+	log.Logger.Infof("Run the test with 'trivy check test %s'", dir)
+	return nil
+}
+
+func initLogger(ctx *cli.Context) error {
+	conf, err := option.NewGlobalOption(ctx)
+	if err != nil {
+		return xerrors.Errorf("config error: %w", err)
+	}
+
+	if err = log.InitLogger(conf.Debug, conf.Quiet); err != nil {
+		return xerrors.Errorf("failed to initialize a logger: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package fix
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/commands/option"
+	"github.com/aquasecurity/trivy/pkg/fix"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Run rewrites lock files to the fixed versions of the vulnerabilities in the JSON report
+func Run(c *cli.Context) error {
+	if c.NArg() != 1 {
+		cli.ShowSubcommandHelpAndExit(c, 1)
+	}
+
+	if err := initLogger(c); err != nil {
+		return xerrors.Errorf("log initialization error: %w", err)
+	}
+
+	report, err := readReport(c.Args().First())
+	if err != nil {
+		return xerrors.Errorf("report error: %w", err)
+	}
+
+	var severities []dbTypes.Severity
+	for _, s := range strings.Split(c.String("severity"), ",") {
+		severity, err := dbTypes.NewSeverity(s)
+		if err != nil {
+			return xerrors.Errorf("severity error: %w", err)
+		}
+		severities = append(severities, severity)
+	}
+
+	// The scanned directory is recorded as the artifact name
+	dir := c.String("dir")
+	if dir == "" {
+		dir = report.ArtifactName
+	}
+
+	changes, err := fix.Fix(dir, report, fix.Option{
+		Severities:       severities,
+		VulnerabilityIDs: c.StringSlice("vuln-id"),
+	})
+	if err != nil {
+		return xerrors.Errorf("fix error: %w", err)
+	}
+
+	if len(changes) == 0 {
+		log.Logger.Info("No vulnerability to be fixed")
+	}
+	for _, change := range changes {
+		for _, upgrade := range change.Upgrades {
+			log.Logger.Infof("%s: %s %s => %s (%s)", change.FilePath, upgrade.PkgName, upgrade.InstalledVersion,
+				upgrade.FixedVersion, strings.Join(upgrade.VulnerabilityIDs, ", "))
+		}
+	}
+	return nil
+}
+
+func readReport(filePath string) (types.Report, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return types.Report{}, xerrors.Errorf("file open error: %w", err)
+	}
+	defer f.Close()
+
+	var report types.Report
+	if err = json.NewDecoder(f).Decode(&report); err != nil {
+		return types.Report{}, xerrors.Errorf("json decode error: %w", err)
+	}
+	return report, nil
+}
+
+func initLogger(ctx *cli.Context) error {
+	conf, err := option.NewGlobalOption(ctx)
+	if err != nil {
+		return xerrors.Errorf("config error: %w", err)
+	}
+
+	if err = log.InitLogger(conf.Debug, conf.Quiet); err != nil {
+		return xerrors.Errorf("failed to initialize a logger: %w", err)
+	}
+	return nil
+}
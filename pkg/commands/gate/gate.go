@@ -0,0 +1,70 @@
+package gate
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/commands/option"
+	"github.com/aquasecurity/trivy/pkg/gate"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Run evaluates the gate policy over the JSON report, and exits with the exit code when the gate fails
+func Run(c *cli.Context) error {
+	if c.NArg() != 1 {
+		cli.ShowSubcommandHelpAndExit(c, 1)
+	}
+
+	if err := initLogger(c); err != nil {
+		return xerrors.Errorf("log initialization error: %w", err)
+	}
+
+	report, err := readReport(c.Args().First())
+	if err != nil {
+		return xerrors.Errorf("report error: %w", err)
+	}
+
+	result, err := gate.Evaluate(c.Context, c.String("policy"), report)
+	if err != nil {
+		return xerrors.Errorf("gate error: %w", err)
+	}
+
+	if err = result.Write(os.Stdout); err != nil {
+		return xerrors.Errorf("gate error: %w", err)
+	}
+
+	if !result.Passed() {
+		os.Exit(c.Int("exit-code"))
+	}
+	return nil
+}
+
+func readReport(filePath string) (types.Report, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return types.Report{}, xerrors.Errorf("file open error: %w", err)
+	}
+	defer f.Close()
+
+	var report types.Report
+	if err = json.NewDecoder(f).Decode(&report); err != nil {
+		return types.Report{}, xerrors.Errorf("json decode error: %w", err)
+	}
+	return report, nil
+}
+
+func initLogger(ctx *cli.Context) error {
+	conf, err := option.NewGlobalOption(ctx)
+	if err != nil {
+		return xerrors.Errorf("config error: %w", err)
+	}
+
+	if err = log.InitLogger(conf.Debug, conf.Quiet); err != nil {
+		return xerrors.Errorf("failed to initialize a logger: %w", err)
+	}
+	return nil
+}
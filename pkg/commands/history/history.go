@@ -0,0 +1,149 @@
+package history
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/commands/option"
+	"github.com/aquasecurity/trivy/pkg/history"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+const timeFormat = "2006-01-02 15:04:05"
+
+// List lists the recorded scans of the artifact, or the latest scan of each artifact
+func List(c *cli.Context) error {
+	store, err := open(c)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	var scans []history.Scan
+	if c.NArg() == 0 {
+		scans, err = store.Artifacts()
+	} else {
+		scans, err = store.Scans(c.Args().First(), c.Int("limit"))
+	}
+	if err != nil {
+		return xerrors.Errorf("history error: %w", err)
+	}
+
+	if len(scans) == 0 {
+		log.Logger.Info("No scan has been recorded. Scan with '--save-history' to record scans.")
+		return nil
+	}
+	return writeScans(os.Stdout, scans, true)
+}
+
+// Diff shows the trend of findings of the artifact and the changes since the previous scan
+func Diff(c *cli.Context) error {
+	if c.NArg() != 1 {
+		cli.ShowSubcommandHelpAndExit(c, 1)
+	}
+	artifactName := c.Args().First()
+
+	store, err := open(c)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	scans, err := store.Scans(artifactName, c.Int("limit"))
+	if err != nil {
+		return xerrors.Errorf("history error: %w", err)
+	}
+	switch len(scans) {
+	case 0:
+		return xerrors.Errorf("no scan of %s has been recorded", artifactName)
+	case 1:
+		log.Logger.Infof("Only one scan of %s has been recorded", artifactName)
+	}
+
+	w := os.Stdout
+	fmt.Fprintf(w, "%s\n\n", artifactName)
+
+	// Oldest first to show the trend
+	trend := make([]history.Scan, len(scans))
+	for i, scan := range scans {
+		trend[len(scans)-1-i] = scan
+	}
+	if err = writeScans(w, trend, false); err != nil {
+		return err
+	}
+
+	if len(scans) < 2 {
+		return nil
+	}
+
+	from, err := store.Findings(scans[1].ID)
+	if err != nil {
+		return xerrors.Errorf("history error: %w", err)
+	}
+	to, err := store.Findings(scans[0].ID)
+	if err != nil {
+		return xerrors.Errorf("history error: %w", err)
+	}
+	added, resolved := history.Diff(from, to)
+
+	fmt.Fprintf(w, "\nChanges since %s: %d new, %d resolved\n", scans[1].ScannedAt.Local().Format(timeFormat), len(added), len(resolved))
+	writeFindings(w, "+", added)
+	writeFindings(w, "-", resolved)
+	return nil
+}
+
+func open(c *cli.Context) (*history.Store, error) {
+	conf, err := option.NewGlobalOption(c)
+	if err != nil {
+		return nil, xerrors.Errorf("config error: %w", err)
+	}
+	if err = log.InitLogger(conf.Debug, conf.Quiet); err != nil {
+		return nil, xerrors.Errorf("failed to initialize a logger: %w", err)
+	}
+
+	store, err := history.Open(history.Path(conf.CacheDir))
+	if err != nil {
+		return nil, xerrors.Errorf("history store error: %w", err)
+	}
+	return store, nil
+}
+
+func writeScans(w io.Writer, scans []history.Scan, withArtifact bool) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if withArtifact {
+		fmt.Fprint(tw, "ARTIFACT\t")
+	}
+	fmt.Fprintln(tw, "SCANNED AT\tDIGEST\tCRITICAL\tHIGH\tMEDIUM\tLOW\tUNKNOWN\tTOTAL")
+
+	for _, scan := range scans {
+		if withArtifact {
+			fmt.Fprintf(tw, "%s\t", scan.ArtifactName)
+		}
+		digest := scan.Digest
+		if digest == "" {
+			digest = "-"
+		}
+		s := scan.Summary
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%d\t%d\t%d\n", scan.ScannedAt.Local().Format(timeFormat), digest,
+			s.Critical, s.High, s.Medium, s.Low, s.Unknown, s.Total())
+	}
+	if err := tw.Flush(); err != nil {
+		return xerrors.Errorf("write error: %w", err)
+	}
+	return nil
+}
+
+func writeFindings(w io.Writer, sign string, findings []history.Finding) {
+	for _, f := range findings {
+		desc := f.ID
+		if f.Resource != "" {
+			desc += " " + f.Resource
+		}
+		fmt.Fprintf(w, "  %s %s (%s, %s) in %s\n", sign, desc, f.Severity, f.Class, f.Target)
+	}
+}
@@ -1,6 +1,9 @@
 package module
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/urfave/cli/v2"
 	"golang.org/x/xerrors"
 
@@ -20,7 +23,7 @@ func Install(c *cli.Context) error {
 	}
 
 	repo := c.Args().First()
-	if err := module.Install(c.Context, repo, c.Bool("quiet"), c.Bool("insecure")); err != nil {
+	if err := module.Install(c.Context, c.String("module-dir"), repo, c.Bool("quiet"), c.Bool("insecure")); err != nil {
 		return xerrors.Errorf("module installation error: %w", err)
 	}
 
@@ -38,13 +41,29 @@ func Uninstall(c *cli.Context) error {
 	}
 
 	repo := c.Args().First()
-	if err := module.Uninstall(c.Context, repo); err != nil {
+	if err := module.Uninstall(c.Context, c.String("module-dir"), repo); err != nil {
 		return xerrors.Errorf("module uninstall error: %w", err)
 	}
 
 	return nil
 }
 
+// List lists the installed modules
+func List(c *cli.Context) error {
+	if err := initLogger(c); err != nil {
+		return xerrors.Errorf("log initialization error: %w", err)
+	}
+
+	info, err := module.List(c.Context, c.String("module-dir"))
+	if err != nil {
+		return xerrors.Errorf("module list error: %w", err)
+	}
+	if _, err = fmt.Fprint(os.Stdout, info); err != nil {
+		return xerrors.Errorf("print error: %w", err)
+	}
+	return nil
+}
+
 func initLogger(ctx *cli.Context) error {
 	conf, err := option.NewGlobalOption(ctx)
 	if err != nil {
@@ -12,6 +12,7 @@ import (
 
 	"github.com/aquasecurity/fanal/cache"
 	"github.com/aquasecurity/trivy-db/pkg/metadata"
+	"github.com/aquasecurity/trivy/pkg/advisory"
 	"github.com/aquasecurity/trivy/pkg/commands/option"
 	"github.com/aquasecurity/trivy/pkg/db"
 	"github.com/aquasecurity/trivy/pkg/log"
@@ -98,9 +99,8 @@ func (c Cache) ClearArtifacts() error {
 }
 
 // DownloadDB downloads the DB
-func DownloadDB(appVersion, cacheDir, dbRepository string, quiet, insecure, skipUpdate bool) error {
+func DownloadDB(ctx context.Context, appVersion, cacheDir, dbRepository string, quiet, insecure, skipUpdate bool) error {
 	client := db.NewClient(cacheDir, quiet, insecure, db.WithDBRepository(dbRepository))
-	ctx := context.Background()
 	needsUpdate, err := client.NeedsUpdate(appVersion, skipUpdate)
 	if err != nil {
 		return xerrors.Errorf("database error: %w", err)
@@ -122,6 +122,54 @@ func DownloadDB(appVersion, cacheDir, dbRepository string, quiet, insecure, skip
 	return nil
 }
 
+// DownloadDBSnapshot downloads the DB snapshot of the digest or date unless it is cached, and returns the directory of the DB
+func DownloadDBSnapshot(ctx context.Context, cacheDir, dbRepository, snapshot string, quiet, insecure, skipUpdate bool) (string, error) {
+	digest, err := db.ResolveSnapshot(cacheDir, dbRepository, snapshot)
+	if err != nil {
+		return "", xerrors.Errorf("DB snapshot error: %w", err)
+	}
+
+	client := db.NewClient(cacheDir, quiet, insecure, db.WithDBRepository(dbRepository))
+	dir, err := client.DownloadSnapshot(ctx, digest, skipUpdate)
+	if err != nil {
+		return "", xerrors.Errorf("failed to download DB snapshot: %w", err)
+	}
+	log.Logger.Infof("Using DB snapshot %s", digest)
+
+	// for debug
+	if err = showDBInfo(dir); err != nil {
+		return "", xerrors.Errorf("failed to show database info: %w", err)
+	}
+	return dir, nil
+}
+
+// InitAdvisoryFeeds loads the advisory feeds and registers them for detection along with the DB
+func InitAdvisoryFeeds(ctx context.Context, refs []string, cacheDir string, quiet, insecure, skipUpdate bool) error {
+	if len(refs) == 0 {
+		return nil
+	}
+	feeds, err := advisory.LoadFeeds(ctx, refs, advisory.Option{
+		CacheDir:   cacheDir,
+		SkipUpdate: skipUpdate,
+		Insecure:   insecure,
+		Quiet:      quiet,
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to load advisory feeds: %w", err)
+	}
+	advisory.Register(feeds...)
+	return nil
+}
+
+// InitOSVOnline enables the queries to the OSV API in detection
+func InitOSVOnline(enabled, insecure bool) {
+	if !enabled {
+		return
+	}
+	log.Logger.Info("Vulnerabilities are also looked up with the OSV API")
+	advisory.EnableOnline(advisory.NewOnlineClient(advisory.WithInsecure(insecure)))
+}
+
 func showDBInfo(cacheDir string) error {
 	m := metadata.NewClient(cacheDir)
 	meta, err := m.Get()
@@ -1,23 +1,92 @@
 package option
 
 import (
+	"bufio"
 	"os"
+	"path"
+	"strings"
 	"time"
 
+	"github.com/docker/go-units"
 	"github.com/urfave/cli/v2"
 	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 )
 
+// symlinkPolicies are the values of "--follow-symlinks"
+var symlinkPolicies = []string{"none", "root", "all"}
+
 // ArtifactOption holds the options for an artifact scanning
 type ArtifactOption struct {
 	Input      string
 	Timeout    time.Duration
 	ClearCache bool
 
+	// Timeouts of the phases, which are limited only by Timeout if not positive
+	DBTimeout       time.Duration
+	PullTimeout     time.Duration
+	AnalysisTimeout time.Duration
+	TargetTimeout   time.Duration
+	PolicyTimeout   time.Duration
+
+	// ContinueOnError records errors of files and targets in the report instead of aborting the scan
+	ContinueOnError bool
+
+	// QuietProgress writes progress events instead of progress bars
+	QuietProgress bool
+
+	// DryRun prints the resolved options without scanning
+	DryRun bool
+
 	SkipDirs    []string
 	SkipFiles   []string
 	OfflineScan bool
+	DiffBase    string
+	Parallel    int
+
+	// Codeowners assigns findings to the owners of their files in CODEOWNERS of the scanned directory or repository,
+	// and OwnersFile in the CODEOWNERS syntax takes precedence over it
+	Codeowners bool
+	OwnersFile string
+
+	// EnableAnalyzers limits the analyzers to the specified ones if not empty
+	EnableAnalyzers  []string
+	DisableAnalyzers []string
+
+	// TmpDir holds the temporary files such as large files extracted from layers, os.TempDir() if empty
+	TmpDir string
+
+	// TmpSizeLimit limits the total size of the temporary files of extracted files if positive.
+	// It is parsed from "--tmp-size-limit" in Init().
+	TmpSizeLimit int64
+
+	// ScanArchives walks the files in archives found in the filesystem, including nested archives up to ArchiveDepth
+	ScanArchives bool
+	ArchiveDepth int
+
+	// ArchiveSizeLimit limits the total size of files extracted from each archive if positive.
+	// It is parsed from "--archive-size-limit" in Init().
+	ArchiveSizeLimit int64
+
+	// MaxFileSizes limits the size of files passed to analyzers, as "SIZE" for all of them or "CLASS=SIZE".
+	// They are resolved into the limits of each analyzer by the artifact command.
+	MaxFileSizes []string
+
+	// FollowSymlinks is the policy of following symbolic links, which depends on the scanning mode if empty
+	FollowSymlinks string
+
+	// IncludeSystemDirs walks the system directories such as proc, sys and dev
+	IncludeSystemDirs bool
+
+	// Watch keeps rescanning changed files until interrupted
+	Watch         bool
+	WatchInterval time.Duration
+
+	IncludePaths    []string
+	ExcludePaths    []string
+	ExcludePathFile string
+	UseGitignore    bool
 
 	// this field is populated in Init()
 	Target string
@@ -32,6 +101,41 @@ func NewArtifactOption(c *cli.Context) ArtifactOption {
 		SkipFiles:   c.StringSlice("skip-files"),
 		SkipDirs:    c.StringSlice("skip-dirs"),
 		OfflineScan: c.Bool("offline-scan"),
+		DiffBase:    c.String("diff-base"),
+		Parallel:    c.Int("parallel"),
+		TmpDir:      c.String("tmp-dir"),
+
+		Codeowners: c.Bool("codeowners"),
+		OwnersFile: c.String("owners-file"),
+
+		EnableAnalyzers:  c.StringSlice("enable-analyzers"),
+		DisableAnalyzers: c.StringSlice("disable-analyzers"),
+
+		ScanArchives: c.Bool("scan-archives"),
+		ArchiveDepth: c.Int("archive-depth"),
+
+		MaxFileSizes:      c.StringSlice("max-file-size"),
+		FollowSymlinks:    c.String("follow-symlinks"),
+		IncludeSystemDirs: c.Bool("include-system-dirs"),
+
+		Watch:         c.Bool("watch"),
+		WatchInterval: c.Duration("watch-interval"),
+
+		IncludePaths:    c.StringSlice("include-path"),
+		ExcludePaths:    c.StringSlice("exclude-path"),
+		ExcludePathFile: c.String("exclude-path-file"),
+		UseGitignore:    c.Bool("use-gitignore"),
+
+		DBTimeout:       c.Duration("db-timeout"),
+		PullTimeout:     c.Duration("pull-timeout"),
+		AnalysisTimeout: c.Duration("analysis-timeout"),
+		TargetTimeout:   c.Duration("target-timeout"),
+		PolicyTimeout:   c.Duration("policy-timeout"),
+
+		ContinueOnError: c.Bool("continue-on-error"),
+
+		QuietProgress: c.Bool("quiet-progress"),
+		DryRun:        c.Bool("dry-run"),
 	}
 }
 
@@ -46,9 +150,119 @@ func (c *ArtifactOption) Init(ctx *cli.Context, logger *zap.SugaredLogger) (err
 		return xerrors.New("arguments error")
 	}
 
+	if c.Parallel < 0 {
+		logger.Error(`"--parallel" must not be negative`)
+		return xerrors.New("arguments error")
+	}
+
+	for _, t := range c.EnableAnalyzers {
+		if slices.Contains(c.DisableAnalyzers, t) {
+			logger.Errorf(`"%s" cannot be specified in both "--enable-analyzers" and "--disable-analyzers"`, t)
+			return xerrors.New("arguments error")
+		}
+	}
+
+	if size := ctx.String("tmp-size-limit"); size != "" {
+		if c.TmpSizeLimit, err = units.RAMInBytes(size); err != nil {
+			logger.Errorf(`invalid "--tmp-size-limit": %s`, size)
+			return xerrors.Errorf("tmp size limit error: %w", err)
+		}
+	}
+	if c.TmpDir != "" {
+		if fi, err := os.Stat(c.TmpDir); err != nil || !fi.IsDir() {
+			logger.Errorf(`"--tmp-dir" must be an existing directory: %s`, c.TmpDir)
+			return xerrors.New("arguments error")
+		}
+	}
+
+	if c.ScanArchives {
+		if c.ArchiveDepth <= 0 {
+			logger.Error(`"--archive-depth" must be positive`)
+			return xerrors.New("arguments error")
+		}
+		if size := ctx.String("archive-size-limit"); size != "" {
+			if c.ArchiveSizeLimit, err = units.RAMInBytes(size); err != nil {
+				logger.Errorf(`invalid "--archive-size-limit": %s`, size)
+				return xerrors.Errorf("archive size limit error: %w", err)
+			}
+		}
+	}
+
+	if c.FollowSymlinks != "" && !slices.Contains(symlinkPolicies, c.FollowSymlinks) {
+		logger.Errorf(`"--follow-symlinks" must be one of %s: %s`, strings.Join(symlinkPolicies, ", "), c.FollowSymlinks)
+		return xerrors.New("arguments error")
+	}
+
+	if c.Watch {
+		if c.WatchInterval <= 0 {
+			logger.Error(`"--watch-interval" must be positive`)
+			return xerrors.New("arguments error")
+		} else if c.DiffBase != "" {
+			logger.Error(`"--watch" cannot be used with "--diff-base"`)
+			return xerrors.New("arguments error")
+		}
+		if ctx.IsSet("format") && ctx.String("format") != "json" {
+			logger.Warn(`"--watch" always writes the reports as JSON Lines regardless of "--format"`)
+		}
+	}
+
+	if c.ExcludePathFile != "" {
+		patterns, err := readPathPatterns(c.ExcludePathFile)
+		if err != nil {
+			logger.Errorf("unable to read %s: %s", c.ExcludePathFile, err)
+			return xerrors.Errorf("exclude path file error: %w", err)
+		}
+		c.ExcludePaths = append(c.ExcludePaths, patterns...)
+	}
+
+	for _, pattern := range append(c.IncludePaths, c.ExcludePaths...) {
+		if err = validatePathPattern(pattern); err != nil {
+			logger.Errorf("invalid path pattern: %s", pattern)
+			return xerrors.Errorf("path pattern error (%s): %w", pattern, err)
+		}
+	}
+
 	if c.Input == "" {
 		c.Target = ctx.Args().First()
 	}
 
 	return nil
 }
+
+// validatePathPattern validates the doublestar pattern.
+// doublestar.Match stops parsing the pattern once it doesn't match, so each component is validated with path.Match.
+func validatePathPattern(pattern string) error {
+	for _, component := range strings.Split(pattern, "/") {
+		if component == "**" {
+			continue
+		}
+		if _, err := path.Match(component, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readPathPatterns reads path patterns, one per line, from the file in the .trivyignore style.
+// Empty lines and lines starting with "#" are ignored.
+func readPathPatterns(filePath string) ([]string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, xerrors.Errorf("file open error: %w", err)
+	}
+	defer f.Close()
+
+	var patterns []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") || line == "" {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, xerrors.Errorf("file read error: %w", err)
+	}
+	return patterns, nil
+}
@@ -3,6 +3,7 @@ package option_test
 import (
 	"flag"
 	"testing"
+	"time"
 
 	"github.com/aquasecurity/trivy/pkg/commands/option"
 	"github.com/stretchr/testify/assert"
@@ -27,6 +28,129 @@ func TestArtifactOption_Init(t *testing.T) {
 				Target: "alpine:3.10",
 			},
 		},
+		{
+			name: "exclude path file",
+			args: []string{"--exclude-path", "docs/**", "--exclude-path-file", "testdata/exclude-paths", "/path/to/dir"},
+			want: option.ArtifactOption{
+				ExcludePaths:    []string{"docs/**", "**/testdata", "vendor"},
+				ExcludePathFile: "testdata/exclude-paths",
+				Target:          "/path/to/dir",
+			},
+		},
+		{
+			name: "watch",
+			args: []string{"--watch", "--watch-interval", "5s", "--format", "table", "/path/to/dir"},
+			logs: []string{
+				`"--watch" always writes the reports as JSON Lines regardless of "--format"`,
+			},
+			want: option.ArtifactOption{
+				Watch:         true,
+				WatchInterval: 5 * time.Second,
+				Target:        "/path/to/dir",
+			},
+		},
+		{
+			name: "tmp dir",
+			args: []string{"--tmp-dir", "testdata", "--tmp-size-limit", "10GiB", "alpine:3.10"},
+			want: option.ArtifactOption{
+				TmpDir:       "testdata",
+				TmpSizeLimit: 10 << 30,
+				Target:       "alpine:3.10",
+			},
+		},
+		{
+			name: "analyzers",
+			args: []string{"--enable-analyzers", "pip,poetry", "--disable-analyzers", "secret", "/path/to/dir"},
+			want: option.ArtifactOption{
+				EnableAnalyzers:  []string{"pip", "poetry"},
+				DisableAnalyzers: []string{"secret"},
+				Target:           "/path/to/dir",
+			},
+		},
+		{
+			name: "sad: analyzer both enabled and disabled",
+			args: []string{"--enable-analyzers", "pip", "--disable-analyzers", "pip", "/path/to/dir"},
+			logs: []string{
+				`"pip" cannot be specified in both "--enable-analyzers" and "--disable-analyzers"`,
+			},
+			wantErr: "arguments error",
+		},
+		{
+			name: "walker heuristics",
+			args: []string{"--max-file-size", "100MB,secret=1MB", "--follow-symlinks", "all", "--include-system-dirs", "/"},
+			want: option.ArtifactOption{
+				MaxFileSizes:      []string{"100MB", "secret=1MB"},
+				FollowSymlinks:    "all",
+				IncludeSystemDirs: true,
+				Target:            "/",
+			},
+		},
+		{
+			name: "archives",
+			args: []string{"--scan-archives", "--archive-depth", "2", "--archive-size-limit", "512MiB", "/path/to/mirror"},
+			want: option.ArtifactOption{
+				ScanArchives:     true,
+				ArchiveDepth:     2,
+				ArchiveSizeLimit: 512 << 20,
+				Target:           "/path/to/mirror",
+			},
+		},
+		{
+			name: "sad: non-positive archive depth",
+			args: []string{"--scan-archives", "--archive-depth", "0", "/path/to/mirror"},
+			logs: []string{
+				`"--archive-depth" must be positive`,
+			},
+			wantErr: "arguments error",
+		},
+		{
+			name: "sad: invalid archive size limit",
+			args: []string{"--scan-archives", "--archive-depth", "3", "--archive-size-limit", "1XB", "/path/to/mirror"},
+			logs: []string{
+				`invalid "--archive-size-limit": 1XB`,
+			},
+			wantErr: "archive size limit error",
+		},
+		{
+			name: "sad: invalid symlink policy",
+			args: []string{"--follow-symlinks", "host", "/"},
+			logs: []string{
+				`"--follow-symlinks" must be one of none, root, all: host`,
+			},
+			wantErr: "arguments error",
+		},
+		{
+			name: "sad: invalid tmp size limit",
+			args: []string{"--tmp-size-limit", "10XB", "alpine:3.10"},
+			logs: []string{
+				`invalid "--tmp-size-limit": 10XB`,
+			},
+			wantErr: "tmp size limit error",
+		},
+		{
+			name: "sad: missing tmp dir",
+			args: []string{"--tmp-dir", "testdata/missing", "alpine:3.10"},
+			logs: []string{
+				`"--tmp-dir" must be an existing directory: testdata/missing`,
+			},
+			wantErr: "arguments error",
+		},
+		{
+			name: "sad: watch with diff base",
+			args: []string{"--watch", "--watch-interval", "2s", "--diff-base", "main", "/path/to/dir"},
+			logs: []string{
+				`"--watch" cannot be used with "--diff-base"`,
+			},
+			wantErr: "arguments error",
+		},
+		{
+			name: "sad: non-positive watch interval",
+			args: []string{"--watch", "--watch-interval", "0s", "/path/to/dir"},
+			logs: []string{
+				`"--watch-interval" must be positive`,
+			},
+			wantErr: "arguments error",
+		},
 		{
 			name: "sad: multiple image names",
 			args: []string{"centos:7", "alpine:3.10"},
@@ -35,6 +159,30 @@ func TestArtifactOption_Init(t *testing.T) {
 			},
 			wantErr: "arguments error",
 		},
+		{
+			name: "sad: negative parallel",
+			args: []string{"--parallel", "-1", "/path/to/dir"},
+			logs: []string{
+				`"--parallel" must not be negative`,
+			},
+			wantErr: "arguments error",
+		},
+		{
+			name: "sad: invalid path pattern",
+			args: []string{"--include-path", "[a-", "/path/to/dir"},
+			logs: []string{
+				"invalid path pattern: [a-",
+			},
+			wantErr: "path pattern error",
+		},
+		{
+			name: "sad: missing exclude path file",
+			args: []string{"--exclude-path-file", "testdata/missing", "/path/to/dir"},
+			logs: []string{
+				"unable to read testdata/missing: file open error: open testdata/missing: no such file or directory",
+			},
+			wantErr: "exclude path file error",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -43,6 +191,24 @@ func TestArtifactOption_Init(t *testing.T) {
 
 			app := cli.NewApp()
 			set := flag.NewFlagSet("test", 0)
+			set.Int("parallel", 0, "")
+			set.Var(&cli.StringSlice{}, "include-path", "")
+			set.Var(&cli.StringSlice{}, "exclude-path", "")
+			set.String("exclude-path-file", "", "")
+			set.String("diff-base", "", "")
+			set.String("format", "", "")
+			set.Bool("watch", false, "")
+			set.Duration("watch-interval", 0, "")
+			set.String("tmp-dir", "", "")
+			set.String("tmp-size-limit", "", "")
+			set.Var(&cli.StringSlice{}, "enable-analyzers", "")
+			set.Var(&cli.StringSlice{}, "disable-analyzers", "")
+			set.Var(&cli.StringSlice{}, "max-file-size", "")
+			set.String("follow-symlinks", "", "")
+			set.Bool("include-system-dirs", false, "")
+			set.Bool("scan-archives", false, "")
+			set.Int("archive-depth", 0, "")
+			set.String("archive-size-limit", "", "")
 			ctx := cli.NewContext(app, set, nil)
 			_ = set.Parse(tt.args)
 
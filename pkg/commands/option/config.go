@@ -15,6 +15,22 @@ type ConfigOption struct {
 	PolicyPaths      []string
 	DataPaths        []string
 	PolicyNamespaces []string
+
+	// Check bundles
+	ChecksBundles   []string
+	ChecksBundleKey string
+
+	// Severities and metadata of checks defined by organizations
+	CheckOverrides string
+
+	// Helm
+	HelmValueFiles  []string
+	HelmValues      []string
+	HelmKubeVersion string
+	HelmAPIVersions []string
+
+	// CloudFormation
+	CloudFormationParamFiles []string
 }
 
 // NewConfigOption is the factory method to return config scanning options
@@ -27,5 +43,14 @@ func NewConfigOption(c *cli.Context) ConfigOption {
 		PolicyPaths:        c.StringSlice("config-policy"),
 		DataPaths:          c.StringSlice("config-data"),
 		PolicyNamespaces:   c.StringSlice("policy-namespaces"),
+		HelmValueFiles:     c.StringSlice("helm-values"),
+		HelmValues:         c.StringSlice("helm-set"),
+		HelmKubeVersion:    c.String("helm-kube-version"),
+		HelmAPIVersions:    c.StringSlice("helm-api-versions"),
+		ChecksBundles:      c.StringSlice("checks-bundle"),
+		ChecksBundleKey:    c.String("checks-bundle-key"),
+		CheckOverrides:     c.String("check-overrides"),
+
+		CloudFormationParamFiles: c.StringSlice("cf-params"),
 	}
 }
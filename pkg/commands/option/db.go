@@ -15,6 +15,15 @@ type DBOption struct {
 	Light          bool
 	NoProgress     bool
 	DBRepository   string
+
+	// DBSnapshot pins the DB to the digest or date for reproducible scans
+	DBSnapshot string
+
+	// AdvisoryFeeds are directories or OCI artifacts of advisories in OSV format
+	AdvisoryFeeds []string
+
+	// OSVOnline queries the OSV API for vulnerabilities not in the DB
+	OSVOnline bool
 }
 
 // NewDBOption is the factory method to return the DBOption
@@ -26,6 +35,9 @@ func NewDBOption(c *cli.Context) DBOption {
 		Light:          c.Bool("light"),
 		NoProgress:     c.Bool("no-progress"),
 		DBRepository:   c.String("db-repository"),
+		DBSnapshot:     c.String("db-snapshot"),
+		AdvisoryFeeds:  c.StringSlice("advisory-feed"),
+		OSVOnline:      c.Bool("osv-online"),
 	}
 }
 
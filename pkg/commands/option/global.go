@@ -5,7 +5,9 @@ import (
 	"go.uber.org/zap"
 	"golang.org/x/xerrors"
 
+	"github.com/aquasecurity/trivy/pkg/httpclient"
 	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/retry"
 )
 
 // GlobalOption holds the global options for trivy
@@ -17,12 +19,20 @@ type GlobalOption struct {
 	Quiet      bool
 	Debug      bool
 	CacheDir   string
+	LogFormat  string
 }
 
 // NewGlobalOption is the factory method to return GlobalOption
 func NewGlobalOption(c *cli.Context) (GlobalOption, error) {
 	quiet := c.Bool("quiet")
 	debug := c.Bool("debug")
+	logFormat := c.String("log-format")
+	if logFormat != "" {
+		if err := log.SetFormat(logFormat); err != nil {
+			return GlobalOption{}, xerrors.Errorf("log format error: %w", err)
+		}
+	}
+
 	logger, err := log.NewLogger(debug, quiet)
 	if err != nil {
 		return GlobalOption{}, xerrors.New("failed to create a logger")
@@ -36,5 +46,29 @@ func NewGlobalOption(c *cli.Context) (GlobalOption, error) {
 		Quiet:      quiet,
 		Debug:      debug,
 		CacheDir:   c.String("cache-dir"),
+		LogFormat:  logFormat,
 	}, nil
 }
+
+// applyNetworkFlags trusts --ca-bundle and sets the retry policy of --retries and --retry-backoff
+func applyNetworkFlags(c *cli.Context) error {
+	if err := httpclient.SetCABundle(c.String("ca-bundle")); err != nil {
+		return xerrors.Errorf("--ca-bundle error: %w", err)
+	}
+
+	retries, rpcRetries, backoff := retry.DefaultRetries, retry.DefaultRPCRetries, retry.DefaultBackoff
+	if c.IsSet("retries") {
+		retries = c.Int("retries")
+		rpcRetries = retries
+	}
+	if c.IsSet("retry-backoff") {
+		backoff = c.Duration("retry-backoff")
+	}
+	if err := retry.SetPolicy(retries, backoff); err != nil {
+		return xerrors.Errorf("retry policy error: %w", err)
+	}
+	if err := retry.SetRPCRetries(rpcRetries); err != nil {
+		return xerrors.Errorf("retry policy error: %w", err)
+	}
+	return nil
+}
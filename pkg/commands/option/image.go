@@ -2,16 +2,68 @@ package option
 
 import (
 	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
 )
 
 // ImageOption holds the options for scanning images
 type ImageOption struct {
 	ScanRemovedPkgs bool
+	Platform        string
+
+	// ReportView is the view of the table format, e.g. all, layers
+	ReportView string
+
+	// Base image attribution
+	BaseImageIndex       string
+	IgnoreBaseImageVulns bool
+	RecommendBaseImage   bool
+
+	// Provenance verification
+	VerifyProvenance   bool
+	ProvenanceKey      string
+	ProvenanceBuilders []string
+	ProvenanceSources  []string
+
+	// Signature verification
+	VerifySignature bool
+	SignatureKey    string
+	SignatureCert   string
+
+	// RegistryToken is sent to registries as a bearer token
+	RegistryToken string
 }
 
 // NewImageOption is the factory method to return ImageOption
 func NewImageOption(c *cli.Context) ImageOption {
 	return ImageOption{
 		ScanRemovedPkgs: c.Bool("removed-pkgs"),
+		Platform:        c.String("platform"),
+		ReportView:      c.String("report"),
+
+		BaseImageIndex:       c.String("base-image-index"),
+		IgnoreBaseImageVulns: c.Bool("ignore-base-image-vulns"),
+		RecommendBaseImage:   c.Bool("recommend-base-image"),
+
+		VerifyProvenance:   c.Bool("verify-provenance"),
+		ProvenanceKey:      c.String("provenance-key"),
+		ProvenanceBuilders: c.StringSlice("provenance-builder"),
+		ProvenanceSources:  c.StringSlice("provenance-source"),
+
+		VerifySignature: c.Bool("verify-signature"),
+		SignatureKey:    c.String("signature-key"),
+		SignatureCert:   c.String("signature-cert"),
+
+		RegistryToken: c.String("registry-token"),
+	}
+}
+
+// Init validates the image options
+func (c *ImageOption) Init() error {
+	if c.ReportView != "" && c.ReportView != "all" && c.ReportView != "layers" {
+		return xerrors.Errorf("unknown report view: %s (all,layers)", c.ReportView)
+	}
+	if c.VerifySignature && c.SignatureKey == "" && c.SignatureCert == "" {
+		return xerrors.New("--verify-signature requires --signature-key or --signature-cert")
 	}
+	return nil
 }
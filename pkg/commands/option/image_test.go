@@ -0,0 +1,68 @@
+package option_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/commands/option"
+)
+
+func TestImageOption_Init(t *testing.T) {
+	tests := []struct {
+		name    string
+		opt     option.ImageOption
+		wantErr string
+	}{
+		{
+			name: "cosign",
+			opt: option.ImageOption{
+				VerifySignature: true,
+				SignatureKey:    "cosign.pub",
+			},
+		},
+		{
+			name: "notation",
+			opt: option.ImageOption{
+				VerifySignature: true,
+				SignatureCert:   "ca.pem",
+			},
+		},
+		{
+			name: "no verification",
+			opt:  option.ImageOption{},
+		},
+		{
+			name: "sad path",
+			opt: option.ImageOption{
+				VerifySignature: true,
+			},
+			wantErr: "--verify-signature requires --signature-key or --signature-cert",
+		},
+		{
+			name: "layers view",
+			opt: option.ImageOption{
+				ReportView: "layers",
+			},
+		},
+		{
+			name: "unknown view",
+			opt: option.ImageOption{
+				ReportView: "summary",
+			},
+			wantErr: "unknown report view: summary",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opt.Init()
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package option
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+// LicenseOption holds the options for license scanning
+type LicenseOption struct {
+	LicenseFull       bool
+	LicenseForbidden  []string
+	LicenseRestricted []string
+	LicenseAllowed    []string
+	LicensePolicy     string
+}
+
+// NewLicenseOption is the factory method to return license options
+func NewLicenseOption(c *cli.Context) LicenseOption {
+	return LicenseOption{
+		LicenseFull:       c.Bool("license-full"),
+		LicenseForbidden:  c.StringSlice("license-forbidden"),
+		LicenseRestricted: c.StringSlice("license-restricted"),
+		LicenseAllowed:    c.StringSlice("license-allowed"),
+		LicensePolicy:     c.String("license-policy"),
+	}
+}
@@ -0,0 +1,17 @@
+package option
+
+import "github.com/urfave/cli/v2"
+
+// ModuleOption holds the options for WASM modules
+type ModuleOption struct {
+	ModuleDir      string
+	EnabledModules []string
+}
+
+// NewModuleOption is the factory method to return module options
+func NewModuleOption(c *cli.Context) ModuleOption {
+	return ModuleOption{
+		ModuleDir:      c.String("module-dir"),
+		EnabledModules: c.StringSlice("enable-modules"),
+	}
+}
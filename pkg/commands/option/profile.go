@@ -0,0 +1,212 @@
+package option
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/aquasecurity/trivy/pkg/image"
+)
+
+// DefaultConfigFile is loaded if exists when --config is not specified
+const DefaultConfigFile = "trivy.yaml"
+
+// envVarPattern matches ${VAR} in config files
+var envVarPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// ConfigFile represents trivy.yaml.
+// The top-level values apply to every scan, and the profile selected via --profile overrides them, e.g.
+//
+//	severity: [HIGH, CRITICAL]
+//	profiles:
+//	  ci-strict:
+//	    security-checks: [vuln, config, secret]
+//	    format: sarif
+//	    output: trivy.sarif
+//	  local-dev:
+//	    severity: [CRITICAL]
+//	    ignorefile: .trivyignore.local
+//
+// ${VAR} is replaced with the environment variable, and the files listed in "include" are loaded first
+// so that the including file overrides them, e.g. shared org-wide settings with per-repo overrides.
+// The paths of included files are relative to the including file.
+//
+// "registries" holds the mirrors, TLS settings and credentials per registry. See image.RegistryConfig.
+type ConfigFile struct {
+	Include    []string `yaml:"include"`
+	Profile    `yaml:",inline"`
+	Profiles   map[string]Profile              `yaml:"profiles"`
+	Registries map[string]image.RegistryConfig `yaml:"registries"`
+}
+
+// merge overrides the config with the non-empty values of the other config.
+// Registries are overridden as a whole per registry.
+func (c *ConfigFile) merge(other ConfigFile) {
+	c.Profile.merge(other.Profile)
+	for host, registry := range other.Registries {
+		if c.Registries == nil {
+			c.Registries = map[string]image.RegistryConfig{}
+		}
+		c.Registries[host] = registry
+	}
+	for name, profile := range other.Profiles {
+		if c.Profiles == nil {
+			c.Profiles = map[string]Profile{}
+		}
+		p := c.Profiles[name]
+		p.merge(profile)
+		c.Profiles[name] = p
+	}
+}
+
+// Profile holds the values overriding the defaults of flags
+type Profile struct {
+	Severity       []string `yaml:"severity"`
+	SecurityChecks []string `yaml:"security-checks"`
+	IgnoreFile     string   `yaml:"ignorefile"`
+	Format         string   `yaml:"format"`
+	Template       string   `yaml:"template"`
+	Output         string   `yaml:"output"`
+}
+
+// merge overrides the profile with the non-empty values of the other profile
+func (p *Profile) merge(other Profile) {
+	if len(other.Severity) > 0 {
+		p.Severity = other.Severity
+	}
+	if len(other.SecurityChecks) > 0 {
+		p.SecurityChecks = other.SecurityChecks
+	}
+	if other.IgnoreFile != "" {
+		p.IgnoreFile = other.IgnoreFile
+	}
+	if other.Format != "" {
+		p.Format = other.Format
+	}
+	if other.Template != "" {
+		p.Template = other.Template
+	}
+	if other.Output != "" {
+		p.Output = other.Output
+	}
+}
+
+// flags returns the flag values of the profile, keyed by the flag names
+func (p Profile) flags() map[string]string {
+	return map[string]string{
+		"severity":        strings.Join(p.Severity, ","),
+		"security-checks": strings.Join(p.SecurityChecks, ","),
+		"ignorefile":      p.IgnoreFile,
+		"format":          p.Format,
+		"template":        p.Template,
+		"output":          p.Output,
+	}
+}
+
+// ApplyConfigFile sets the flags of the command from the config file and the profile,
+// and applies the network flags before any outbound request.
+// Flags specified on the command line or via environment variables take precedence over the config file.
+func ApplyConfigFile(c *cli.Context) error {
+	if err := applyNetworkFlags(c); err != nil {
+		return err
+	}
+
+	configFile := c.String("config")
+	profileName := c.String("profile")
+
+	if _, err := os.Stat(configFile); errors.Is(err, os.ErrNotExist) && !c.IsSet("config") && profileName == "" {
+		// The default config file is optional
+		return nil
+	}
+
+	config, err := loadConfigFile(filepath.Clean(configFile), nil)
+	if err != nil {
+		return err
+	}
+
+	if err = image.SetRegistries(config.Registries); err != nil {
+		return xerrors.Errorf("registries error in %s: %w", configFile, err)
+	}
+
+	values := config.flags()
+	if profileName != "" {
+		profile, ok := config.Profiles[profileName]
+		if !ok {
+			return xerrors.Errorf("profile %q not found in %s", profileName, configFile)
+		}
+		for name, value := range profile.flags() {
+			if value != "" {
+				values[name] = value
+			}
+		}
+	}
+
+	for name, value := range values {
+		if value == "" || !hasFlag(c.Command, name) || c.IsSet(name) {
+			continue
+		}
+		if err = c.Set(name, value); err != nil {
+			return xerrors.Errorf("unable to set --%s from %s: %w", name, configFile, err)
+		}
+	}
+	return nil
+}
+
+// loadConfigFile loads the config file merged with the included files.
+// "stack" holds the files including this file to detect include cycles.
+func loadConfigFile(path string, stack []string) (ConfigFile, error) {
+	for _, p := range stack {
+		if p == path {
+			return ConfigFile{}, xerrors.Errorf("include cycle: %s", strings.Join(append(stack, path), " -> "))
+		}
+	}
+	stack = append(stack, path)
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return ConfigFile{}, xerrors.Errorf("config file open error: %w", err)
+	}
+	b = envVarPattern.ReplaceAllFunc(b, func(m []byte) []byte {
+		return []byte(os.Getenv(string(envVarPattern.FindSubmatch(m)[1])))
+	})
+
+	var config ConfigFile
+	if err = yaml.Unmarshal(b, &config); err != nil {
+		return ConfigFile{}, xerrors.Errorf("config file (%s) decode error: %w", path, err)
+	}
+
+	var merged ConfigFile
+	for _, include := range config.Include {
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(path), include)
+		}
+		included, err := loadConfigFile(include, stack)
+		if err != nil {
+			return ConfigFile{}, xerrors.Errorf("include error in %s: %w", path, err)
+		}
+		merged.merge(included)
+	}
+	merged.merge(config)
+
+	return merged, nil
+}
+
+func hasFlag(cmd *cli.Command, name string) bool {
+	if cmd == nil {
+		return false
+	}
+	for _, f := range cmd.Flags {
+		for _, n := range f.Names() {
+			if n == name {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package option_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+
+	"github.com/aquasecurity/trivy/pkg/commands/option"
+)
+
+func TestApplyConfigFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		cmdArgs []string
+		env     map[string]string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name: "top-level values",
+			args: []string{"--config", "testdata/config/trivy.yaml"},
+			want: map[string]string{
+				"severity":        "HIGH,CRITICAL",
+				"security-checks": "vuln,secret",
+				"ignorefile":      ".trivyignore",
+				"format":          "json",
+				"output":          "",
+			},
+		},
+		{
+			name: "profile",
+			args: []string{"--config", "testdata/config/trivy.yaml", "--profile", "ci-strict"},
+			want: map[string]string{
+				"severity":        "HIGH,CRITICAL",
+				"security-checks": "vuln,config,secret",
+				"ignorefile":      ".trivyignore",
+				"format":          "sarif",
+				"output":          "trivy.sarif",
+			},
+		},
+		{
+			name:    "flags and envs take precedence",
+			args:    []string{"--config", "testdata/config/trivy.yaml", "--profile", "local-dev"},
+			cmdArgs: []string{"--format", "table"},
+			env: map[string]string{
+				"TEST_SEVERITY": "LOW",
+			},
+			want: map[string]string{
+				"severity":        "LOW",
+				"security-checks": "vuln,secret",
+				"ignorefile":      ".trivyignore.local",
+				"format":          "table",
+				"output":          "",
+			},
+		},
+		{
+			name: "include and interpolation",
+			args: []string{"--config", "testdata/config/repo.yaml", "--profile", "ci-strict"},
+			env: map[string]string{
+				"TEST_ORG_DIR":    "/etc/trivy",
+				"TEST_OUTPUT_DIR": "/tmp/reports",
+			},
+			want: map[string]string{
+				"severity":        "HIGH,CRITICAL",
+				"security-checks": "vuln,secret",
+				"ignorefile":      "/etc/trivy/.trivyignore",
+				"format":          "sarif",
+				"output":          "/tmp/reports/trivy.sarif",
+			},
+		},
+		{
+			name: "missing default config file",
+			args: []string{},
+			want: map[string]string{
+				"severity":        "UNKNOWN,LOW,MEDIUM,HIGH,CRITICAL",
+				"security-checks": "vuln,secret",
+				"ignorefile":      ".trivyignore",
+				"format":          "table",
+				"output":          "",
+			},
+		},
+		{
+			name:    "missing config file",
+			args:    []string{"--config", "testdata/config/missing.yaml"},
+			wantErr: "config file open error",
+		},
+		{
+			name:    "include cycle",
+			args:    []string{"--config", "testdata/config/cycle-a.yaml"},
+			wantErr: "include cycle: testdata/config/cycle-a.yaml -> testdata/config/cycle-b.yaml -> testdata/config/cycle-a.yaml",
+		},
+		{
+			name:    "invalid registry CA",
+			args:    []string{"--config", "testdata/config/registries.yaml"},
+			wantErr: "registries error in testdata/config/registries.yaml: registry registry.internal:5000: CA file error",
+		},
+		{
+			name:    "unknown profile",
+			args:    []string{"--config", "testdata/config/trivy.yaml", "--profile", "unknown"},
+			wantErr: `profile "unknown" not found`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			got := map[string]string{}
+			app := &cli.App{
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "config", Value: option.DefaultConfigFile},
+					&cli.StringFlag{Name: "profile"},
+				},
+				Commands: []*cli.Command{
+					{
+						Name:   "scan",
+						Before: option.ApplyConfigFile,
+						Flags: []cli.Flag{
+							&cli.StringFlag{Name: "severity", Value: "UNKNOWN,LOW,MEDIUM,HIGH,CRITICAL", EnvVars: []string{"TEST_SEVERITY"}},
+							&cli.StringFlag{Name: "security-checks", Value: "vuln,secret"},
+							&cli.StringFlag{Name: "ignorefile", Value: ".trivyignore"},
+							&cli.StringFlag{Name: "format", Value: "table"},
+							&cli.StringFlag{Name: "output"},
+						},
+						Action: func(c *cli.Context) error {
+							for _, name := range []string{"severity", "security-checks", "ignorefile", "format", "output"} {
+								got[name] = c.String(name)
+							}
+							return nil
+						},
+					},
+				},
+			}
+
+			args := append([]string{"trivy"}, tt.args...)
+			args = append(args, "scan")
+			err := app.Run(append(args, tt.cmdArgs...))
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package option
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+// RepoOption holds the options for repository scanning
+type RepoOption struct {
+	RepoBranch string
+	RepoTag    string
+	RepoCommit string
+	RepoDepth  int
+
+	GitUsername string
+	GitToken    string
+}
+
+// NewRepoOption is the factory method to return repository options
+func NewRepoOption(c *cli.Context) RepoOption {
+	return RepoOption{
+		RepoBranch: c.String("branch"),
+		RepoTag:    c.String("tag"),
+		RepoCommit: c.String("commit"),
+		RepoDepth:  c.Int("depth"),
+
+		GitUsername: c.String("git-username"),
+		GitToken:    c.String("git-token"),
+	}
+}
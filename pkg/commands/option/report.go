@@ -19,17 +19,34 @@ type ReportOption struct {
 	Format         string
 	Template       string
 	DependencyTree bool
+	FixAdvice      bool
 
 	IgnoreFile    string
 	IgnoreUnfixed bool
 	ExitCode      int
 	IgnorePolicy  string
 
+	// FailFast stops scanning when findings failing the scan are detected
+	FailFast bool
+
+	// SbomDetail adds file evidences and hashes of packages to SBOMs
+	SbomDetail bool
+
+	// SaveHistory records the summary of the report in the local history
+	SaveHistory bool
+
+	// Labels are attached to the report metadata, populated by Init()
+	Labels map[string]string
+
+	// GatePolicy is a Rego policy deciding whether the report passes
+	GatePolicy string
+
 	// these variables are not exported
 	vulnType       string
 	securityChecks string
 	output         string
 	severities     string
+	labels         []string
 
 	// these variables are populated by Init()
 	VulnType       []string
@@ -37,6 +54,7 @@ type ReportOption struct {
 	Output         io.Writer
 	Severities     []dbTypes.Severity
 	ListAllPkgs    bool
+	IncludeDevDeps bool
 }
 
 // NewReportOption is the factory method to return ReportOption
@@ -45,6 +63,7 @@ func NewReportOption(c *cli.Context) ReportOption {
 		output:         c.String("output"),
 		Format:         c.String("format"),
 		DependencyTree: c.Bool("dependency-tree"),
+		FixAdvice:      c.Bool("fix-advice"),
 		Template:       c.String("template"),
 		IgnorePolicy:   c.String("ignore-policy"),
 
@@ -54,7 +73,13 @@ func NewReportOption(c *cli.Context) ReportOption {
 		IgnoreFile:     c.String("ignorefile"),
 		IgnoreUnfixed:  c.Bool("ignore-unfixed"),
 		ExitCode:       c.Int("exit-code"),
+		FailFast:       c.Bool("fail-fast"),
+		SbomDetail:     c.Bool("sbom-detail"),
+		SaveHistory:    c.Bool("save-history"),
+		labels:         c.StringSlice("label"),
+		GatePolicy:     c.String("gate-policy"),
 		ListAllPkgs:    c.Bool("list-all-pkgs"),
+		IncludeDevDeps: c.Bool("include-dev-deps"),
 	}
 }
 
@@ -97,10 +122,15 @@ func (c *ReportOption) Init(output io.Writer, logger *zap.SugaredLogger) error {
 		return xerrors.Errorf("security checks: %w", err)
 	}
 
+	if err := c.populateLabels(); err != nil {
+		return xerrors.Errorf("label: %w", err)
+	}
+
 	// for testability
 	c.severities = ""
 	c.vulnType = ""
 	c.securityChecks = ""
+	c.labels = nil
 
 	// The output is os.Stdout by default
 	if c.output != "" {
@@ -143,8 +173,23 @@ func (c *ReportOption) populateSecurityChecks() error {
 	return nil
 }
 
+// populateLabels parses labels in the form of KEY=VALUE, where the last value takes precedence for the same key
+func (c *ReportOption) populateLabels() error {
+	for _, l := range c.labels {
+		key, value, ok := strings.Cut(l, "=")
+		if key = strings.TrimSpace(key); !ok || key == "" {
+			return xerrors.Errorf("invalid label (%s), specify it as KEY=VALUE", l)
+		}
+		if c.Labels == nil {
+			c.Labels = map[string]string{}
+		}
+		c.Labels[key] = value
+	}
+	return nil
+}
+
 func (c *ReportOption) forceListAllPkgs(logger *zap.SugaredLogger) bool {
-	if slices.Contains(supportedSbomFormats, c.Format) && !c.ListAllPkgs {
+	if slices.Contains(SupportedSbomFormats, c.Format) && !c.ListAllPkgs {
 		logger.Debugf("'github', 'cyclonedx', 'spdx', and 'spdx-json' automatically enables '--list-all-pkgs'.")
 		return true
 	}
@@ -152,6 +197,10 @@ func (c *ReportOption) forceListAllPkgs(logger *zap.SugaredLogger) bool {
 		logger.Debugf("'--dependency-tree' enables '--list-all-pkgs'.")
 		return true
 	}
+	if c.FixAdvice {
+		logger.Debugf("'--fix-advice' enables '--list-all-pkgs'.")
+		return true
+	}
 	return false
 }
 
@@ -23,6 +23,7 @@ func TestReportReportConfig_Init(t *testing.T) {
 		vulnType       string
 		securityChecks string
 		severities     string
+		labels         []string
 		IgnoreFile     string
 		IgnoreUnfixed  bool
 		listAllPksgs   bool
@@ -136,6 +137,38 @@ func TestReportReportConfig_Init(t *testing.T) {
 				SecurityChecks: []string{types.SecurityCheckVulnerability},
 			},
 		},
+		{
+			name: "happy path with labels",
+			fields: fields{
+				severities:     "CRITICAL",
+				vulnType:       "os",
+				securityChecks: "vuln",
+				labels:         []string{"team=payments", "env=staging", "env=prod", "note=a=b"},
+			},
+			args: []string{"alpine:3.10"},
+			want: ReportOption{
+				Severities:     []dbTypes.Severity{dbTypes.SeverityCritical},
+				VulnType:       []string{types.VulnTypeOS},
+				SecurityChecks: []string{types.SecurityCheckVulnerability},
+				Labels: map[string]string{
+					"team": "payments",
+					"env":  "prod",
+					"note": "a=b",
+				},
+				Output: os.Stdout,
+			},
+		},
+		{
+			name: "sad path with an invalid label",
+			fields: fields{
+				severities:     "CRITICAL",
+				vulnType:       "os",
+				securityChecks: "vuln",
+				labels:         []string{"payments"},
+			},
+			args:    []string{"alpine:3.10"},
+			wantErr: "invalid label (payments)",
+		},
 		{
 			name: "invalid option combination: --template and --format json",
 			fields: fields{
@@ -221,6 +254,7 @@ func TestReportReportConfig_Init(t *testing.T) {
 				vulnType:       tt.fields.vulnType,
 				securityChecks: tt.fields.securityChecks,
 				severities:     tt.fields.severities,
+				labels:         tt.fields.labels,
 				IgnoreFile:     tt.fields.IgnoreFile,
 				IgnoreUnfixed:  tt.fields.IgnoreUnfixed,
 				ExitCode:       tt.fields.ExitCode,
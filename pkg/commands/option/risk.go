@@ -0,0 +1,49 @@
+package option
+
+import (
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/risk"
+)
+
+// RiskOption holds the options for risk scoring of vulnerabilities
+type RiskOption struct {
+	RiskScore        bool
+	EPSSFile         string
+	KEVFile          string
+	ReachabilityFile string
+
+	// these variables are not exported
+	riskWeights string
+
+	// RiskWeights is populated by Init()
+	RiskWeights risk.Weights
+}
+
+// NewRiskOption is the factory method to return risk options
+func NewRiskOption(c *cli.Context) RiskOption {
+	return RiskOption{
+		RiskScore:        c.Bool("risk-score"),
+		EPSSFile:         c.String("epss-file"),
+		KEVFile:          c.String("kev-file"),
+		ReachabilityFile: c.String("reachability-file"),
+		riskWeights:      c.String("risk-weights"),
+	}
+}
+
+// Init parses the weights of the risk factors, which are used only with --risk-score
+func (c *RiskOption) Init() error {
+	if !c.RiskScore {
+		return nil
+	} else if c.riskWeights == "" {
+		c.RiskWeights = risk.DefaultWeights
+		return nil
+	}
+	w, err := risk.ParseWeights(c.riskWeights)
+	if err != nil {
+		return xerrors.Errorf("invalid --risk-weights: %w", err)
+	}
+	c.RiskWeights = w
+	return nil
+}
@@ -0,0 +1,59 @@
+package option
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/risk"
+)
+
+func TestRiskOption_Init(t *testing.T) {
+	tests := []struct {
+		name        string
+		riskScore   bool
+		riskWeights string
+		want        risk.Weights
+		wantErr     string
+	}{
+		{
+			name:      "default",
+			riskScore: true,
+			want:      risk.DefaultWeights,
+		},
+		{
+			name:        "custom weights",
+			riskScore:   true,
+			riskWeights: "cvss=0.5,kev=0.5",
+			want:        risk.Weights{CVSS: 0.5, KEV: 0.5},
+		},
+		{
+			name:        "disabled",
+			riskWeights: "unknown",
+		},
+		{
+			name:        "sad path",
+			riskScore:   true,
+			riskWeights: "cvss=0.5,exploit=0.5",
+			wantErr:     "invalid --risk-weights: unknown risk factor: exploit",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &RiskOption{
+				RiskScore:   tt.riskScore,
+				riskWeights: tt.riskWeights,
+			}
+
+			err := c.Init()
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, c.RiskWeights)
+		})
+	}
+}
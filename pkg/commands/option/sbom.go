@@ -9,19 +9,28 @@ import (
 	"github.com/aquasecurity/trivy/pkg/report"
 )
 
-var supportedSbomFormats = []string{report.FormatCycloneDX, report.FormatSPDX, report.FormatSPDXJSON, report.FormatGitHub}
+// SupportedSbomFormats is a list of formats available for SBOM generation
+var SupportedSbomFormats = []string{report.FormatCycloneDX, report.FormatSPDX, report.FormatSPDXJSON, report.FormatGitHub}
 
-// SbomOption holds the options for SBOM generation
+// SbomOption holds the options for the sbom subcommand
 type SbomOption struct {
+	// ArtifactType and SbomFormat are for SBOM generation
 	ArtifactType string
 	SbomFormat   string
+
+	// AttestationKey is the public key file to verify SBOM attestations in "trivy sbom scan",
+	// and InsecureSkipAttestationVerify accepts attestations without it
+	AttestationKey                string
+	InsecureSkipAttestationVerify bool
 }
 
 // NewSbomOption is the factory method to return SBOM options
 func NewSbomOption(c *cli.Context) SbomOption {
 	return SbomOption{
-		ArtifactType: c.String("artifact-type"),
-		SbomFormat:   c.String("sbom-format"),
+		ArtifactType:                  c.String("artifact-type"),
+		SbomFormat:                    c.String("sbom-format"),
+		AttestationKey:                c.String("attestation-key"),
+		InsecureSkipAttestationVerify: c.Bool("insecure-skip-attestation-verify"),
 	}
 }
 
@@ -31,9 +40,9 @@ func (c *SbomOption) Init(ctx *cli.Context, logger *zap.SugaredLogger) error {
 		return nil
 	}
 
-	if !slices.Contains(supportedSbomFormats, c.SbomFormat) {
-		logger.Errorf(`"--format" must be %q`, supportedSbomFormats)
-		return xerrors.Errorf(`"--format" must be %q`, supportedSbomFormats)
+	if !slices.Contains(SupportedSbomFormats, c.SbomFormat) {
+		logger.Errorf(`"--sbom-format" must be %q`, SupportedSbomFormats)
+		return xerrors.Errorf(`"--sbom-format" must be %q`, SupportedSbomFormats)
 	}
 
 	return nil
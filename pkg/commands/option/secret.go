@@ -2,16 +2,49 @@ package option
 
 import (
 	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/secret"
 )
 
 // SecretOption holds the options for secret scanning
 type SecretOption struct {
-	SecretConfigPath string
+	SecretConfigPath   string
+	SecretVerify       bool
+	SecretHistory      bool
+	SecretHistoryDepth int
+	SecretScanArchives bool
+	SecretScanBinaries bool
+	SecretRedaction    secret.Redaction
+	SecretBaseline     string
+	SecretBaselineOut  string
 }
 
 // NewSecretOption is the factory method to return secret options
 func NewSecretOption(c *cli.Context) SecretOption {
 	return SecretOption{
-		SecretConfigPath: c.String("secret-config"),
+		SecretConfigPath:   c.String("secret-config"),
+		SecretVerify:       c.Bool("secret-verify"),
+		SecretHistory:      c.Bool("secret-history"),
+		SecretHistoryDepth: c.Int("secret-history-depth"),
+		SecretScanArchives: c.Bool("secret-scan-archives"),
+		SecretScanBinaries: c.Bool("secret-scan-binaries"),
+		SecretRedaction:    secret.Redaction(c.String("secret-redaction")),
+		SecretBaseline:     c.String("secret-baseline"),
+		SecretBaselineOut:  c.String("secret-baseline-out"),
+	}
+}
+
+// Init validates the secret options
+func (c *SecretOption) Init() error {
+	// An empty value is allowed for testability
+	if c.SecretRedaction == "" {
+		return nil
+	}
+	r, err := secret.ParseRedaction(string(c.SecretRedaction))
+	if err != nil {
+		return xerrors.Errorf("invalid --secret-redaction: %w", err)
 	}
+	c.SecretRedaction = r
+	return nil
 }
@@ -0,0 +1,57 @@
+package option_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/commands/option"
+	"github.com/aquasecurity/trivy/pkg/secret"
+)
+
+func TestSecretOption_Init(t *testing.T) {
+	tests := []struct {
+		name      string
+		redaction secret.Redaction
+		want      secret.Redaction
+		wantErr   string
+	}{
+		{
+			name:      "partial",
+			redaction: "partial",
+			want:      secret.RedactionPartial,
+		},
+		{
+			name:      "case insensitive",
+			redaction: "HASH",
+			want:      secret.RedactionHash,
+		},
+		{
+			name:      "empty",
+			redaction: "",
+			want:      "",
+		},
+		{
+			name:      "sad path",
+			redaction: "unknown",
+			wantErr:   "unknown secret redaction: unknown",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &option.SecretOption{
+				SecretRedaction: tt.redaction,
+			}
+
+			err := c.Init()
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, c.SecretRedaction)
+		})
+	}
+}
@@ -90,9 +90,31 @@ func List(c *cli.Context) error {
 	return nil
 }
 
-// Update updates an existing plugin
+// Search searches the plugin index for plugins
+func Search(c *cli.Context) error {
+	if c.NArg() > 1 {
+		cli.ShowSubcommandHelpAndExit(c, 1)
+	}
+
+	if err := initLogger(c); err != nil {
+		return xerrors.Errorf("initialize error: %w", err)
+	}
+
+	info, err := plugin.Search(c.Context, c.String("index-url"), c.Args().First())
+	if err != nil {
+		return xerrors.Errorf("plugin search error: %w", err)
+	}
+
+	if _, err = fmt.Fprint(os.Stdout, info); err != nil {
+		return xerrors.Errorf("print error: %w", err)
+	}
+
+	return nil
+}
+
+// Update updates the plugin index, or an existing plugin if the name is given
 func Update(c *cli.Context) error {
-	if c.NArg() != 1 {
+	if c.NArg() > 1 {
 		cli.ShowSubcommandHelpAndExit(c, 1)
 	}
 
@@ -100,6 +122,13 @@ func Update(c *cli.Context) error {
 		return xerrors.Errorf("initialize error: %w", err)
 	}
 
+	if c.NArg() == 0 {
+		if err := plugin.UpdateIndex(c.Context, c.String("index-url")); err != nil {
+			return xerrors.Errorf("plugin index update error: %w", err)
+		}
+		return nil
+	}
+
 	pluginName := c.Args().First()
 	if err := plugin.Update(pluginName); err != nil {
 		return xerrors.Errorf("plugin update error: %w", err)
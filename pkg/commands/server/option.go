@@ -11,6 +11,7 @@ type Option struct {
 	option.GlobalOption
 	option.DBOption
 	option.CacheOption
+	option.ModuleOption
 	option.OtherOption
 
 	Listen      string
@@ -26,6 +27,7 @@ func NewOption(c *cli.Context) Option {
 		GlobalOption: gc,
 		DBOption:     option.NewDBOption(c),
 		CacheOption:  option.NewCacheOption(c),
+		ModuleOption: option.NewModuleOption(c),
 		OtherOption:  option.NewOtherOption(c),
 
 		Listen:      c.String("listen"),
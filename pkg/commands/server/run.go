@@ -1,6 +1,8 @@
 package server
 
 import (
+	"context"
+
 	"github.com/urfave/cli/v2"
 	"golang.org/x/xerrors"
 
@@ -41,7 +43,7 @@ func run(c Option) (err error) {
 	}
 
 	// download the database file
-	if err = operation.DownloadDB(c.AppVersion, c.CacheDir, c.DBRepository, true, c.Insecure, c.SkipDBUpdate); err != nil {
+	if err = operation.DownloadDB(context.Background(), c.AppVersion, c.CacheDir, c.DBRepository, true, c.Insecure, c.SkipDBUpdate); err != nil {
 		return err
 	}
 
@@ -53,8 +55,16 @@ func run(c Option) (err error) {
 		return xerrors.Errorf("error in vulnerability DB initialize: %w", err)
 	}
 
+	if err = operation.InitAdvisoryFeeds(c.Context.Context, c.AdvisoryFeeds, c.CacheDir, true, c.Insecure, c.SkipDBUpdate); err != nil {
+		return xerrors.Errorf("advisory feed error: %w", err)
+	}
+	operation.InitOSVOnline(c.OSVOnline, c.Insecure)
+
 	// Initialize WASM modules
-	m, err := module.NewManager(c.Context.Context)
+	m, err := module.NewManager(c.Context.Context, module.Option{
+		Dir:            c.ModuleDir,
+		EnabledModules: c.EnabledModules,
+	})
 	if err != nil {
 		return xerrors.Errorf("WASM module error: %w", err)
 	}
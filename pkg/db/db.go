@@ -153,6 +153,7 @@ func (c *Client) Download(ctx context.Context, dst string) error {
 		return xerrors.Errorf("OCI artifact error: %w", err)
 	}
 
+	// Requests are retried by the registry transport, so the download is not retried as a whole
 	if err := c.artifact.Download(ctx, db.Dir(dst)); err != nil {
 		return xerrors.Errorf("database download error: %w", err)
 	}
@@ -160,9 +161,26 @@ func (c *Client) Download(ctx context.Context, dst string) error {
 	if err := c.updateDownloadedAt(dst); err != nil {
 		return xerrors.Errorf("failed to update downloaded_at: %w", err)
 	}
+
+	// The digest is recorded so that the DB can be used later with "--db-snapshot <date>"
+	if err := c.recordDownloadedDB(dst); err != nil {
+		log.Logger.Warnf("Unable to record the DB snapshot: %s", err)
+	}
 	return nil
 }
 
+func (c *Client) recordDownloadedDB(dst string) error {
+	digest, err := c.artifact.Digest()
+	if err != nil {
+		return err
+	}
+	meta, err := metadata.NewClient(dst).Get()
+	if err != nil {
+		return xerrors.Errorf("unable to get metadata: %w", err)
+	}
+	return c.recordSnapshot(digest, meta.UpdatedAt)
+}
+
 func (c *Client) updateDownloadedAt(dst string) error {
 	log.Logger.Debug("Updating database metadata...")
 
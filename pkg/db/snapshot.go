@@ -0,0 +1,178 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/metadata"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/oci"
+)
+
+const (
+	snapshotDir     = "db-snapshots"
+	snapshotCatalog = "snapshots.json"
+
+	snapshotDateFormat = "2006-01-02"
+)
+
+// Snapshot is a DB version downloaded before, which is recorded to resolve dates to digests
+type Snapshot struct {
+	Repository string
+	Digest     string
+	UpdatedAt  time.Time
+}
+
+// SnapshotDir returns the cache directory of the DB snapshot, which doesn't change as the digest is immutable
+func SnapshotDir(cacheDir, digest string) string {
+	return filepath.Join(cacheDir, snapshotDir, strings.TrimPrefix(digest, "sha256:"))
+}
+
+// ResolveSnapshot returns the digest of the DB snapshot, which is a digest or a date in YYYY-MM-DD.
+// Registries don't list old DB versions, so a date is resolved to the latest DB updated by the end of the date
+// among the DBs downloaded before.
+func ResolveSnapshot(cacheDir, dbRepository, snapshot string) (string, error) {
+	if strings.HasPrefix(snapshot, "sha256:") {
+		if len(snapshot) != len("sha256:")+64 {
+			return "", xerrors.Errorf("invalid DB snapshot digest: %s", snapshot)
+		}
+		return snapshot, nil
+	}
+
+	date, err := time.Parse(snapshotDateFormat, snapshot)
+	if err != nil {
+		return "", xerrors.Errorf("DB snapshot must be a digest (sha256:...) or a date (YYYY-MM-DD): %s", snapshot)
+	}
+	end := date.Add(24 * time.Hour)
+
+	snapshots, err := loadSnapshots(cacheDir)
+	if err != nil {
+		return "", err
+	}
+
+	var found *Snapshot
+	for i, s := range snapshots {
+		if s.Repository != dbRepository || !s.UpdatedAt.Before(end) {
+			continue
+		}
+		if found == nil || s.UpdatedAt.After(found.UpdatedAt) {
+			found = &snapshots[i]
+		}
+	}
+	if found == nil {
+		return "", xerrors.Errorf("no DB of %s updated by %s has been downloaded. Specify the digest instead", dbRepository, snapshot)
+	}
+	log.Logger.Debugf("DB snapshot %s is resolved to %s (updated at %s)", snapshot, found.Digest, found.UpdatedAt)
+	return found.Digest, nil
+}
+
+// Digest returns the digest of the DB updated at the time, or an empty string if it is not recorded
+func Digest(cacheDir, dbRepository string, updatedAt time.Time) string {
+	snapshots, err := loadSnapshots(cacheDir)
+	if err != nil {
+		return ""
+	}
+	for _, s := range snapshots {
+		if s.Repository == dbRepository && s.UpdatedAt.Equal(updatedAt) {
+			return s.Digest
+		}
+	}
+	return ""
+}
+
+// DownloadSnapshot downloads the DB snapshot of the digest into its cache directory unless it is cached
+func (c *Client) DownloadSnapshot(ctx context.Context, digest string, skipUpdate bool) (string, error) {
+	dir := SnapshotDir(c.cacheDir, digest)
+	if _, err := metadata.NewClient(dir).Get(); err == nil {
+		log.Logger.Debugf("DB snapshot %s is cached", digest)
+		return dir, nil
+	} else if skipUpdate {
+		return "", xerrors.Errorf("DB snapshot %s is not cached, and --skip-db-update is specified", digest)
+	}
+
+	if c.artifact == nil {
+		art, err := oci.NewArtifact(fmt.Sprintf("%s@%s", c.dbRepository, digest), dbMediaType, c.quiet, c.insecureSkipTLSVerify)
+		if err != nil {
+			return "", xerrors.Errorf("OCI artifact error: %w", err)
+		}
+		c.artifact = art
+	}
+
+	log.Logger.Infof("Downloading DB snapshot %s...", digest)
+	if err := c.artifact.Download(ctx, db.Dir(dir)); err != nil {
+		_ = os.RemoveAll(dir)
+		return "", xerrors.Errorf("database download error: %w", err)
+	}
+
+	meta, err := metadata.NewClient(dir).Get()
+	if err != nil {
+		return "", xerrors.Errorf("unable to get metadata: %w", err)
+	} else if meta.Version != db.SchemaVersion {
+		_ = os.RemoveAll(dir)
+		return "", xerrors.Errorf("the schema version of DB snapshot %s (%d) is not supported. Expected: %d",
+			digest, meta.Version, db.SchemaVersion)
+	}
+
+	if err = c.recordSnapshot(digest, meta.UpdatedAt); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// recordSnapshot adds the downloaded DB to the catalog of snapshots
+func (c *Client) recordSnapshot(digest string, updatedAt time.Time) error {
+	snapshots, err := loadSnapshots(c.cacheDir)
+	if err != nil {
+		return err
+	}
+	for _, s := range snapshots {
+		if s.Repository == c.dbRepository && s.Digest == digest {
+			return nil
+		}
+	}
+	snapshots = append(snapshots, Snapshot{
+		Repository: c.dbRepository,
+		Digest:     digest,
+		UpdatedAt:  updatedAt.UTC(),
+	})
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].UpdatedAt.Before(snapshots[j].UpdatedAt)
+	})
+
+	b, err := json.MarshalIndent(snapshots, "", "  ")
+	if err != nil {
+		return xerrors.Errorf("DB snapshot catalog encode error: %w", err)
+	}
+	if err = os.MkdirAll(filepath.Join(c.cacheDir, snapshotDir), 0700); err != nil {
+		return xerrors.Errorf("failed to create the DB snapshot directory: %w", err)
+	}
+	if err = os.WriteFile(filepath.Join(c.cacheDir, snapshotDir, snapshotCatalog), b, 0600); err != nil {
+		return xerrors.Errorf("failed to write the DB snapshot catalog: %w", err)
+	}
+	return nil
+}
+
+func loadSnapshots(cacheDir string) ([]Snapshot, error) {
+	b, err := os.ReadFile(filepath.Join(cacheDir, snapshotDir, snapshotCatalog))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, xerrors.Errorf("unable to read the DB snapshot catalog: %w", err)
+	}
+
+	var snapshots []Snapshot
+	if err = json.Unmarshal(b, &snapshots); err != nil {
+		return nil, xerrors.Errorf("DB snapshot catalog decode error: %w", err)
+	}
+	return snapshots, nil
+}
@@ -0,0 +1,163 @@
+package db_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	fakei "github.com/google/go-containerregistry/pkg/v1/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy-db/pkg/metadata"
+	"github.com/aquasecurity/trivy/pkg/db"
+	"github.com/aquasecurity/trivy/pkg/oci"
+)
+
+const repository = "ghcr.io/aquasecurity/trivy-db"
+
+var (
+	oldDigest = "sha256:" + strings.Repeat("1", 64)
+	newDigest = "sha256:" + strings.Repeat("2", 64)
+)
+
+func TestResolveSnapshot(t *testing.T) {
+	cacheDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(cacheDir, "db-snapshots"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(cacheDir, "db-snapshots", "snapshots.json"), []byte(`[
+  {"Repository": "ghcr.io/aquasecurity/trivy-db", "Digest": "`+oldDigest+`", "UpdatedAt": "2022-09-19T06:00:00Z"},
+  {"Repository": "ghcr.io/aquasecurity/trivy-db", "Digest": "`+newDigest+`", "UpdatedAt": "2022-09-20T18:00:00Z"},
+  {"Repository": "registry.acme.com/trivy-db", "Digest": "sha256:`+strings.Repeat("3", 64)+`", "UpdatedAt": "2022-09-20T00:00:00Z"}
+]`), 0600))
+
+	tests := []struct {
+		name     string
+		snapshot string
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "digest",
+			snapshot: oldDigest,
+			want:     oldDigest,
+		},
+		{
+			name:     "date",
+			snapshot: "2022-09-20",
+			want:     newDigest,
+		},
+		{
+			name:     "earlier date",
+			snapshot: "2022-09-19",
+			want:     oldDigest,
+		},
+		{
+			name:     "no DB by the date",
+			snapshot: "2022-09-18",
+			wantErr:  "no DB of ghcr.io/aquasecurity/trivy-db updated by 2022-09-18 has been downloaded",
+		},
+		{
+			name:     "invalid digest",
+			snapshot: "sha256:1234",
+			wantErr:  "invalid DB snapshot digest",
+		},
+		{
+			name:     "invalid date",
+			snapshot: "2022/09/20",
+			wantErr:  "DB snapshot must be a digest (sha256:...) or a date (YYYY-MM-DD)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := db.ResolveSnapshot(cacheDir, repository, tt.snapshot)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestClient_DownloadSnapshot(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		skipUpdate bool
+		want       metadata.Metadata
+		wantErr    string
+	}{
+		{
+			name:  "happy path",
+			input: "testdata/snapshot.tar.gz",
+			want: metadata.Metadata{
+				Version:    2,
+				NextUpdate: time.Date(2022, 9, 21, 6, 0, 0, 0, time.UTC),
+				UpdatedAt:  time.Date(2022, 9, 20, 6, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:    "old schema",
+			input:   "testdata/db.tar.gz",
+			wantErr: "the schema version of DB snapshot",
+		},
+		{
+			name:       "not cached",
+			input:      "testdata/snapshot.tar.gz",
+			skipUpdate: true,
+			wantErr:    "is not cached, and --skip-db-update is specified",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cacheDir := t.TempDir()
+
+			// Mock image
+			img := new(fakei.FakeImage)
+			img.LayersReturns([]v1.Layer{newFakeLayer(t, tt.input)}, nil)
+			img.ManifestReturns(&v1.Manifest{
+				Layers: []v1.Descriptor{
+					{
+						MediaType: "application/vnd.aquasec.trivy.db.layer.v1.tar+gzip",
+						Annotations: map[string]string{
+							"org.opencontainers.image.title": "db.tar.gz",
+						},
+					},
+				},
+			}, nil)
+			art, err := oci.NewArtifact("db", mediaType, true, false, oci.WithImage(img))
+			require.NoError(t, err)
+
+			client := db.NewClient(cacheDir, true, false, db.WithOCIArtifact(art), db.WithDBRepository(repository))
+			dir, err := client.DownloadSnapshot(context.Background(), newDigest, tt.skipUpdate)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				assert.NoDirExists(t, db.SnapshotDir(cacheDir, newDigest))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, db.SnapshotDir(cacheDir, newDigest), dir)
+
+			got, err := metadata.NewClient(dir).Get()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			// The date is resolved to the downloaded snapshot
+			digest, err := db.ResolveSnapshot(cacheDir, repository, "2022-09-20")
+			require.NoError(t, err)
+			assert.Equal(t, newDigest, digest)
+
+			// The cached snapshot is used without the registry
+			dir, err = db.NewClient(cacheDir, true, false).DownloadSnapshot(context.Background(), newDigest, true)
+			require.NoError(t, err)
+			assert.Equal(t, db.SnapshotDir(cacheDir, newDigest), dir)
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/aquasecurity/trivy-db/pkg/db"
 	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
+	"github.com/aquasecurity/trivy/pkg/advisory"
 	"github.com/aquasecurity/trivy/pkg/detector/library/compare"
 	"github.com/aquasecurity/trivy/pkg/detector/library/compare/npm"
 	"github.com/aquasecurity/trivy/pkg/detector/library/compare/pep440"
@@ -19,6 +20,14 @@ import (
 	"github.com/aquasecurity/trivy/pkg/types"
 )
 
+// Ecosystems which are not defined in trivy-db yet
+const (
+	cocoapods dbTypes.Ecosystem = "cocoapods"
+	swift     dbTypes.Ecosystem = "swift"
+	pub       dbTypes.Ecosystem = "pub"
+	vcpkg     dbTypes.Ecosystem = "vcpkg"
+)
+
 // NewDriver returns a driver according to the library type
 func NewDriver(libType string) (Driver, error) {
 	var ecosystem dbTypes.Ecosystem
@@ -28,27 +37,47 @@ func NewDriver(libType string) (Driver, error) {
 	case ftypes.Bundler, ftypes.GemSpec:
 		ecosystem = vulnerability.RubyGems
 		comparer = rubygems.Comparer{}
-	case ftypes.Cargo:
+	case ftypes.Cargo, types.RustBinary:
 		ecosystem = vulnerability.Cargo
 		comparer = compare.GenericComparer{}
+	case types.Cocoapods:
+		ecosystem = cocoapods
+		comparer = rubygems.Comparer{}
+	case types.Conan:
+		ecosystem = vulnerability.Conan
+		comparer = compare.GenericComparer{}
 	case ftypes.Composer:
 		ecosystem = vulnerability.Composer
 		comparer = compare.GenericComparer{}
 	case ftypes.GoBinary, ftypes.GoModule:
 		ecosystem = vulnerability.Go
 		comparer = compare.GenericComparer{}
-	case ftypes.Jar, ftypes.Pom:
+	case ftypes.Jar, ftypes.Pom, types.BazelMaven, types.BazelModule, types.Gradle, types.GradleCatalog:
 		ecosystem = vulnerability.Maven
 		comparer = maven.Comparer{}
-	case ftypes.Npm, ftypes.Yarn, ftypes.NodePkg, ftypes.JavaScript:
+	case ftypes.Npm, ftypes.Yarn, ftypes.NodePkg, ftypes.JavaScript, types.Pnpm:
 		ecosystem = vulnerability.Npm
 		comparer = npm.Comparer{}
-	case ftypes.NuGet:
+	case ftypes.NuGet, types.DotNetCore:
 		ecosystem = vulnerability.NuGet
 		comparer = compare.GenericComparer{}
 	case ftypes.Pipenv, ftypes.Poetry, ftypes.Pip, ftypes.PythonPkg:
 		ecosystem = vulnerability.Pip
 		comparer = pep440.Comparer{}
+	case types.CondaPkg, types.CondaEnv, types.CondaLock:
+		// Conda packages of Python projects have the same names as PyPI,
+		// while native packages such as openssl don't have advisories in the pip ecosystem.
+		ecosystem = vulnerability.Pip
+		comparer = pep440.Comparer{}
+	case types.Pub:
+		ecosystem = pub
+		comparer = compare.GenericComparer{}
+	case types.Swift, types.Carthage:
+		ecosystem = swift
+		comparer = compare.GenericComparer{}
+	case types.Vcpkg:
+		ecosystem = vcpkg
+		comparer = compare.GenericComparer{}
 	default:
 		return Driver{}, xerrors.Errorf("unsupported type %s", libType)
 	}
@@ -83,6 +112,9 @@ func (d *Driver) DetectVulnerabilities(pkgID, pkgName, pkgVer string) ([]types.D
 		return nil, xerrors.Errorf("failed to get %s advisories: %w", d.ecosystem, err)
 	}
 
+	// Advisories of internal packages maintained by users
+	advisories = append(advisories, advisory.Advisories(d.ecosystem, pkgName)...)
+
 	var vulns []types.DetectedVulnerability
 	for _, adv := range advisories {
 		if !d.comparer.IsVulnerable(pkgVer, adv) {
@@ -10,6 +10,7 @@ import (
 	"github.com/aquasecurity/trivy-db/pkg/db"
 	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
+	"github.com/aquasecurity/trivy/pkg/advisory"
 	"github.com/aquasecurity/trivy/pkg/dbtest"
 	"github.com/aquasecurity/trivy/pkg/detector/library"
 	"github.com/aquasecurity/trivy/pkg/types"
@@ -23,6 +24,7 @@ func TestDriver_Detect(t *testing.T) {
 	tests := []struct {
 		name     string
 		fixtures []string
+		feed     string
 		libType  string
 		args     args
 		want     []types.DetectedVulnerability
@@ -132,6 +134,31 @@ func TestDriver_Detect(t *testing.T) {
 			},
 			wantErr: "failed to unmarshal advisory JSON",
 		},
+		{
+			name: "advisory feed",
+			fixtures: []string{
+				"testdata/fixtures/php.yaml",
+				"testdata/fixtures/data-source.yaml",
+			},
+			feed:    "testdata/feed",
+			libType: ftypes.Composer,
+			args: args{
+				pkgName: "acme/orm",
+				pkgVer:  "1.1.0",
+			},
+			want: []types.DetectedVulnerability{
+				{
+					VulnerabilityID:  "INTERNAL-2022-0003",
+					PkgName:          "acme/orm",
+					InstalledVersion: "1.1.0",
+					FixedVersion:     "1.2.0",
+					DataSource: &dbTypes.DataSource{
+						ID:   advisory.SourceID,
+						Name: "acme",
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -139,6 +166,13 @@ func TestDriver_Detect(t *testing.T) {
 			_ = dbtest.InitDB(t, tt.fixtures)
 			defer db.Close()
 
+			if tt.feed != "" {
+				feed, err := advisory.Load(tt.feed, "acme")
+				require.NoError(t, err)
+				advisory.Register(feed)
+				defer advisory.Reset()
+			}
+
 			driver, err := library.NewDriver(tt.libType)
 			require.NoError(t, err)
 
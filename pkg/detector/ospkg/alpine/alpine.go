@@ -83,9 +83,7 @@ func NewScanner(opts ...option) *Scanner {
 // Detect vulnerabilities in package using Alpine scanner
 func (s *Scanner) Detect(osVer string, repo *ftypes.Repository, pkgs []ftypes.Package) ([]types.DetectedVulnerability, error) {
 	log.Logger.Info("Detecting Alpine vulnerabilities...")
-	if strings.Count(osVer, ".") > 1 {
-		osVer = osVer[:strings.LastIndex(osVer, ".")]
-	}
+	osVer = normalizeVersion(osVer)
 	repoRelease := s.repoRelease(repo)
 
 	log.Logger.Debugf("alpine: os version: %s", osVer)
@@ -168,9 +166,7 @@ func (s *Scanner) isVulnerable(installedVersion version.Version, adv dbTypes.Adv
 
 // IsSupportedVersion checks the OSFamily can be scanned using Alpine scanner
 func (s *Scanner) IsSupportedVersion(osFamily, osVer string) bool {
-	if strings.Count(osVer, ".") > 1 {
-		osVer = osVer[:strings.LastIndex(osVer, ".")]
-	}
+	osVer = normalizeVersion(osVer)
 
 	eol, ok := eolDates[osVer]
 	if !ok {
@@ -181,6 +177,18 @@ func (s *Scanner) IsSupportedVersion(osFamily, osVer string) bool {
 	return s.clock.Now().Before(eol)
 }
 
+// normalizeVersion returns the minor version of the release, e.g. 3.16.2 => 3.16.
+// Alpine edge has the next version with the "_alpha" suffix, e.g. 3.17_alpha20220809, which is regarded as edge.
+func normalizeVersion(osVer string) string {
+	if strings.Contains(osVer, "_alpha") {
+		return "edge"
+	}
+	if strings.Count(osVer, ".") > 1 {
+		osVer = osVer[:strings.LastIndex(osVer, ".")]
+	}
+	return osVer
+}
+
 func (s *Scanner) repoRelease(repo *ftypes.Repository) string {
 	if repo == nil {
 		return ""
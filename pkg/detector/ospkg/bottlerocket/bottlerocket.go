@@ -0,0 +1,67 @@
+package bottlerocket
+
+import (
+	version "github.com/knqyf263/go-rpm-version"
+	"golang.org/x/xerrors"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	tos "github.com/aquasecurity/trivy/pkg/analyzer/os"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/scanner/utils"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Scanner implements the Bottlerocket scanner.
+// Bottlerocket publishes the security advisories for all the variants and releases together,
+// which are stored in trivy-db with "bottlerocket" as the bucket name.
+type Scanner struct {
+	dbc db.Config
+}
+
+// NewScanner is the factory method for Scanner
+func NewScanner() *Scanner {
+	return &Scanner{
+		dbc: db.Config{},
+	}
+}
+
+// Detect vulnerabilities in packages using Bottlerocket Security Advisories
+func (s *Scanner) Detect(osVer string, _ *ftypes.Repository, pkgs []ftypes.Package) ([]types.DetectedVulnerability, error) {
+	log.Logger.Info("Detecting Bottlerocket vulnerabilities...")
+	log.Logger.Debugf("bottlerocket: os version: %s", osVer)
+	log.Logger.Debugf("bottlerocket: the number of packages: %d", len(pkgs))
+
+	var vulns []types.DetectedVulnerability
+	for _, pkg := range pkgs {
+		advisories, err := s.dbc.GetAdvisories(tos.Bottlerocket, pkg.Name)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to get Bottlerocket advisories: %w", err)
+		}
+
+		installed := utils.FormatVersion(pkg)
+		installedVersion := version.NewVersion(installed)
+		for _, adv := range advisories {
+			// Bottlerocket Security Advisories have only fixed vulnerabilities
+			fixedVersion := version.NewVersion(adv.FixedVersion)
+			if !installedVersion.LessThan(fixedVersion) {
+				continue
+			}
+			vulns = append(vulns, types.DetectedVulnerability{
+				VulnerabilityID:  adv.VulnerabilityID,
+				PkgName:          pkg.Name,
+				InstalledVersion: installed,
+				FixedVersion:     adv.FixedVersion,
+				Layer:            pkg.Layer,
+				Custom:           adv.Custom,
+				DataSource:       adv.DataSource,
+			})
+		}
+	}
+	return vulns, nil
+}
+
+// IsSupportedVersion always returns true since Bottlerocket doesn't publish EOL dates of releases
+func (s *Scanner) IsSupportedVersion(_, _ string) bool {
+	return true
+}
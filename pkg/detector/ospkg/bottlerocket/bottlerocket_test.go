@@ -0,0 +1,84 @@
+package bottlerocket_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/dbtest"
+	"github.com/aquasecurity/trivy/pkg/detector/ospkg/bottlerocket"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestScanner_Detect(t *testing.T) {
+	tests := []struct {
+		name     string
+		fixtures []string
+		pkgs     []ftypes.Package
+		want     []types.DetectedVulnerability
+		wantErr  string
+	}{
+		{
+			name:     "happy path",
+			fixtures: []string{"testdata/fixtures/bottlerocket.yaml", "testdata/fixtures/data-source.yaml"},
+			pkgs: []ftypes.Package{
+				{
+					Name:    "openssl",
+					Version: "1.1.1o",
+					Release: "1.1654641093.104f8e0f.br1",
+					Arch:    "x86_64",
+				},
+				{
+					Name:    "bash",
+					Version: "5.1.16",
+					Release: "1.1654641093.104f8e0f.br1",
+					Arch:    "x86_64",
+				},
+			},
+			want: []types.DetectedVulnerability{
+				{
+					VulnerabilityID:  "CVE-2022-2068",
+					PkgName:          "openssl",
+					InstalledVersion: "1.1.1o-1.1654641093.104f8e0f.br1",
+					FixedVersion:     "1.1.1p-1.1656019226.c1f9af1a.br1",
+					DataSource: &dbTypes.DataSource{
+						ID:   "bottlerocket",
+						Name: "Bottlerocket Security Advisories",
+						URL:  "https://advisories.bottlerocket.aws/",
+					},
+				},
+			},
+		},
+		{
+			name:     "invalid bucket",
+			fixtures: []string{"testdata/fixtures/invalid.yaml"},
+			pkgs: []ftypes.Package{
+				{
+					Name:    "openssl",
+					Version: "1.1.1o",
+				},
+			},
+			wantErr: "failed to get Bottlerocket advisories",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = dbtest.InitDB(t, tt.fixtures)
+			defer db.Close()
+
+			s := bottlerocket.NewScanner()
+			got, err := s.Detect("1.11.1", nil, tt.pkgs)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
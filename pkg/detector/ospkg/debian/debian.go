@@ -85,14 +85,15 @@ func (s *Scanner) Detect(osVer string, _ *ftypes.Repository, pkgs []ftypes.Packa
 
 	var vulns []types.DetectedVulnerability
 	for _, pkg := range pkgs {
-		installed := utils.FormatSrcVersion(pkg)
+		// Advisories are per source package, and fixes backported by Debian are in the version of the source package
+		srcName, installed := sourcePackage(pkg)
 		installedVersion, err := version.NewVersion(installed)
 		if err != nil {
 			log.Logger.Debugf("Debian installed package version error: %s", err)
 			continue
 		}
 
-		advisories, err := s.vs.Get(osVer, pkg.SrcName)
+		advisories, err := s.vs.Get(osVer, srcName)
 		if err != nil {
 			return nil, xerrors.Errorf("failed to get debian advisories: %w", err)
 		}
@@ -104,6 +105,7 @@ func (s *Scanner) Detect(osVer string, _ *ftypes.Repository, pkgs []ftypes.Packa
 				PkgName:          pkg.Name,
 				InstalledVersion: installed,
 				FixedVersion:     adv.FixedVersion,
+				Status:           adv.State,
 				Layer:            pkg.Layer,
 				Custom:           adv.Custom,
 				DataSource:       adv.DataSource,
@@ -138,6 +140,19 @@ func (s *Scanner) Detect(osVer string, _ *ftypes.Repository, pkgs []ftypes.Packa
 	return vulns, nil
 }
 
+// sourcePackage returns the name and the version of the source package.
+// The status file of dpkg omits "Source" when the source package has the same name and version as the binary package.
+func sourcePackage(pkg ftypes.Package) (string, string) {
+	name, ver := pkg.SrcName, utils.FormatSrcVersion(pkg)
+	if name == "" {
+		name = pkg.Name
+	}
+	if ver == "" {
+		ver = utils.FormatVersion(pkg)
+	}
+	return name, ver
+}
+
 // IsSupportedVersion checks is OSFamily can be scanned using Debian
 func (s *Scanner) IsSupportedVersion(osFamily, osVer string) bool {
 	if strings.Count(osVer, ".") > 0 {
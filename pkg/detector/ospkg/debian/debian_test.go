@@ -67,6 +67,7 @@ func TestScanner_Detect(t *testing.T) {
 					PkgName:          "htpasswd",
 					VulnerabilityID:  "CVE-2021-31618",
 					InstalledVersion: "2.4.24",
+					Status:           "no-dsa",
 					SeveritySource:   vulnerability.Debian,
 					Vulnerability: dbTypes.Vulnerability{
 						Severity: dbTypes.SeverityMedium.String(),
@@ -82,6 +83,61 @@ func TestScanner_Detect(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:     "without source package",
+			fixtures: []string{"testdata/fixtures/debian.yaml"},
+			args: args{
+				osVer: "9.9",
+				pkgs: []ftypes.Package{
+					{
+						// "Source" is omitted in the status file when it is the same as the binary package
+						Name:    "openssl",
+						Version: "1.1.0k",
+						Release: "1~deb9u1",
+					},
+				},
+			},
+			want: []types.DetectedVulnerability{
+				{
+					PkgName:          "openssl",
+					VulnerabilityID:  "CVE-2019-1551",
+					InstalledVersion: "1.1.0k-1~deb9u1",
+					Status:           "postponed",
+				},
+				{
+					PkgName:          "openssl",
+					VulnerabilityID:  "CVE-2019-1563",
+					InstalledVersion: "1.1.0k-1~deb9u1",
+					FixedVersion:     "1.1.0l-1~deb9u1",
+				},
+			},
+		},
+		{
+			name:     "backported fix",
+			fixtures: []string{"testdata/fixtures/debian.yaml"},
+			args: args{
+				osVer: "9.9",
+				pkgs: []ftypes.Package{
+					{
+						// The binary package has its own version
+						Name:       "libssl1.1",
+						Version:    "1.1.0l",
+						Release:    "1~deb9u1+b1",
+						SrcName:    "openssl",
+						SrcVersion: "1.1.0l",
+						SrcRelease: "1~deb9u1",
+					},
+				},
+			},
+			want: []types.DetectedVulnerability{
+				{
+					PkgName:          "libssl1.1",
+					VulnerabilityID:  "CVE-2019-1551",
+					InstalledVersion: "1.1.0l-1~deb9u1",
+					Status:           "postponed",
+				},
+			},
+		},
 		{
 			name:     "invalid bucket",
 			fixtures: []string{"testdata/fixtures/invalid.yaml", "testdata/fixtures/data-source.yaml"},
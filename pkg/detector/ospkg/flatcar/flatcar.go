@@ -0,0 +1,75 @@
+package flatcar
+
+import (
+	"golang.org/x/xerrors"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	tos "github.com/aquasecurity/trivy/pkg/analyzer/os"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/scanner/utils"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Scanner implements the Flatcar Container Linux scanner.
+// Flatcar publishes the security advisories for all the channels and releases together,
+// which are stored in trivy-db with "flatcar" as the bucket name.
+type Scanner struct {
+	dbc db.Config
+}
+
+// NewScanner is the factory method for Scanner
+func NewScanner() *Scanner {
+	return &Scanner{
+		dbc: db.Config{},
+	}
+}
+
+// Detect vulnerabilities in packages using Flatcar Security Advisories
+func (s *Scanner) Detect(osVer string, _ *ftypes.Repository, pkgs []ftypes.Package) ([]types.DetectedVulnerability, error) {
+	log.Logger.Info("Detecting Flatcar Container Linux vulnerabilities...")
+	log.Logger.Debugf("flatcar: os version: %s", osVer)
+	log.Logger.Debugf("flatcar: the number of packages: %d", len(pkgs))
+
+	var vulns []types.DetectedVulnerability
+	for _, pkg := range pkgs {
+		advisories, err := s.dbc.GetAdvisories(tos.Flatcar, pkg.Name)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to get Flatcar advisories: %w", err)
+		}
+
+		installed := utils.FormatVersion(pkg)
+		installedVersion, err := newVersion(installed)
+		if err != nil {
+			log.Logger.Debugf("failed to parse Flatcar installed package version: %s", err)
+			continue
+		}
+
+		for _, adv := range advisories {
+			// Flatcar Security Advisories have only fixed vulnerabilities
+			fixedVersion, err := newVersion(adv.FixedVersion)
+			if err != nil {
+				log.Logger.Debugf("failed to parse Flatcar fixed version: %s", err)
+				continue
+			}
+			if !installedVersion.LessThan(fixedVersion) {
+				continue
+			}
+			vulns = append(vulns, types.DetectedVulnerability{
+				VulnerabilityID:  adv.VulnerabilityID,
+				PkgName:          pkg.Name,
+				InstalledVersion: installed,
+				FixedVersion:     adv.FixedVersion,
+				Layer:            pkg.Layer,
+				Custom:           adv.Custom,
+				DataSource:       adv.DataSource,
+			})
+		}
+	}
+	return vulns, nil
+}
+
+// IsSupportedVersion always returns true since Flatcar doesn't publish EOL dates of releases
+func (s *Scanner) IsSupportedVersion(_, _ string) bool {
+	return true
+}
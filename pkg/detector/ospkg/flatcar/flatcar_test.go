@@ -0,0 +1,75 @@
+package flatcar_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy/pkg/dbtest"
+	"github.com/aquasecurity/trivy/pkg/detector/ospkg/flatcar"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestScanner_Detect(t *testing.T) {
+	tests := []struct {
+		name     string
+		fixtures []string
+		pkgs     []ftypes.Package
+		want     []types.DetectedVulnerability
+		wantErr  string
+	}{
+		{
+			name:     "happy path",
+			fixtures: []string{"testdata/fixtures/flatcar.yaml"},
+			pkgs: []ftypes.Package{
+				{
+					Name:    "openssl",
+					Version: "3.0.5",
+					Release: "r1",
+				},
+				{
+					Name:    "coreos-firmware",
+					Version: "20220509_p1",
+				},
+			},
+			want: []types.DetectedVulnerability{
+				{
+					VulnerabilityID:  "CVE-2022-3602",
+					PkgName:          "openssl",
+					InstalledVersion: "3.0.5-r1",
+					FixedVersion:     "3.0.7",
+				},
+			},
+		},
+		{
+			name:     "invalid bucket",
+			fixtures: []string{"testdata/fixtures/invalid.yaml"},
+			pkgs: []ftypes.Package{
+				{
+					Name:    "openssl",
+					Version: "3.0.5",
+				},
+			},
+			wantErr: "failed to get Flatcar advisories",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = dbtest.InitDB(t, tt.fixtures)
+			defer db.Close()
+
+			s := flatcar.NewScanner()
+			got, err := s.Detect("3227.2.0", nil, tt.pkgs)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
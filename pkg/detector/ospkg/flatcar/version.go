@@ -0,0 +1,130 @@
+package flatcar
+
+import (
+	"math/big"
+	"regexp"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// Versions of Portage packages, e.g. 1.2.3b_rc1_p2-r3.
+// See https://projects.gentoo.org/pms/8/pms.html#x1-250003.2
+var versionRegexp = regexp.MustCompile(`^(\d+(?:\.\d+)*)([a-z]?)((?:_(?:alpha|beta|pre|rc|p)\d*)*)(?:-r(\d+))?$`)
+
+// The order of suffixes. No suffix is between "_rc" and "_p".
+var suffixOrder = map[string]int{
+	"alpha": 0,
+	"beta":  1,
+	"pre":   2,
+	"rc":    3,
+	"p":     5,
+}
+
+const noSuffix = 4
+
+type suffix struct {
+	order int
+	num   *big.Int
+}
+
+type portageVersion struct {
+	numbers  []string
+	letter   string
+	suffixes []suffix
+	revision *big.Int
+}
+
+func newVersion(s string) (portageVersion, error) {
+	m := versionRegexp.FindStringSubmatch(s)
+	if m == nil {
+		return portageVersion{}, xerrors.Errorf("invalid version: %s", s)
+	}
+
+	v := portageVersion{
+		numbers:  strings.Split(m[1], "."),
+		letter:   m[2],
+		revision: toInt(m[4]),
+	}
+	for _, sf := range strings.Split(m[3], "_")[1:] {
+		name := strings.TrimRight(sf, "0123456789")
+		v.suffixes = append(v.suffixes, suffix{
+			order: suffixOrder[name],
+			num:   toInt(strings.TrimPrefix(sf, name)),
+		})
+	}
+	return v, nil
+}
+
+// Compare returns an integer comparing two versions by the algorithm of the package manager specification.
+func (v portageVersion) Compare(other portageVersion) int {
+	// The first components are compared as integers
+	if c := toInt(v.numbers[0]).Cmp(toInt(other.numbers[0])); c != 0 {
+		return c
+	}
+
+	for i := 1; i < len(v.numbers) && i < len(other.numbers); i++ {
+		if c := compareComponent(v.numbers[i], other.numbers[i]); c != 0 {
+			return c
+		}
+	}
+	if c := len(v.numbers) - len(other.numbers); c != 0 {
+		return sign(c)
+	}
+
+	if c := strings.Compare(v.letter, other.letter); c != 0 {
+		return c
+	}
+
+	for i := 0; i < len(v.suffixes) || i < len(other.suffixes); i++ {
+		s1, s2 := suffixAt(v.suffixes, i), suffixAt(other.suffixes, i)
+		if s1.order != s2.order {
+			return sign(s1.order - s2.order)
+		}
+		if c := s1.num.Cmp(s2.num); c != 0 {
+			return c
+		}
+	}
+
+	return v.revision.Cmp(other.revision)
+}
+
+func (v portageVersion) LessThan(other portageVersion) bool {
+	return v.Compare(other) < 0
+}
+
+// compareComponent compares the components after the first one.
+// Components with a leading zero are compared as strings without trailing zeros, e.g. 1.01 < 1.1 and 1.010 == 1.01.
+func compareComponent(a, b string) int {
+	if strings.HasPrefix(a, "0") || strings.HasPrefix(b, "0") {
+		return strings.Compare(strings.TrimRight(a, "0"), strings.TrimRight(b, "0"))
+	}
+	return toInt(a).Cmp(toInt(b))
+}
+
+func suffixAt(suffixes []suffix, i int) suffix {
+	if i < len(suffixes) {
+		return suffixes[i]
+	}
+	return suffix{order: noSuffix, num: new(big.Int)}
+}
+
+// toInt converts digits, which can be longer than int64 such as dates with times, to an integer.
+// An empty string is zero.
+func toInt(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return new(big.Int)
+	}
+	return n
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
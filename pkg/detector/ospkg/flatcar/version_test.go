@@ -0,0 +1,47 @@
+package flatcar
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_portageVersion_Compare(t *testing.T) {
+	tests := []struct {
+		v1   string
+		v2   string
+		want int
+	}{
+		{v1: "3.0.7", v2: "3.0.7", want: 0},
+		{v1: "3.0.7", v2: "3.0.10", want: -1},
+		{v1: "3.0.7-r1", v2: "3.0.7", want: 1},
+		{v1: "3.0.7-r1", v2: "3.0.7-r2", want: -1},
+		{v1: "1.1.1q", v2: "1.1.1p", want: 1},
+		{v1: "1.2", v2: "1.2.0", want: -1},
+		{v1: "1.01", v2: "1.1", want: -1},
+		{v1: "1.010", v2: "1.01", want: 0},
+		{v1: "2.0_rc1", v2: "2.0", want: -1},
+		{v1: "2.0_alpha2", v2: "2.0_beta1", want: -1},
+		{v1: "2.0_p1", v2: "2.0", want: 1},
+		{v1: "2.0_p1", v2: "2.0_p1_rc1", want: 1},
+		{v1: "20220509_p1", v2: "20220509", want: 1},
+		{v1: "20220509235959", v2: "20220510000000", want: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.v1+" vs "+tt.v2, func(t *testing.T) {
+			v1, err := newVersion(tt.v1)
+			require.NoError(t, err)
+			v2, err := newVersion(tt.v2)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, v1.Compare(v2))
+			assert.Equal(t, -tt.want, v2.Compare(v1))
+		})
+	}
+}
+
+func Test_newVersion(t *testing.T) {
+	_, err := newVersion("3.0.7-beta")
+	assert.ErrorContains(t, err, "invalid version")
+}
@@ -96,12 +96,13 @@ func (s *Scanner) Detect(osVer string, _ *ftypes.Repository, pkgs []ftypes.Packa
 
 	var vulns []types.DetectedVulnerability
 	for _, pkg := range pkgs {
-		advisories, err := s.vs.Get(osVer, pkg.SrcName)
+		// Advisories are per source package, and fixes backported by Ubuntu are in the version of the source package
+		srcName, installed := sourcePackage(pkg)
+		advisories, err := s.vs.Get(osVer, srcName)
 		if err != nil {
 			return nil, xerrors.Errorf("failed to get Ubuntu advisories: %w", err)
 		}
 
-		installed := utils.FormatSrcVersion(pkg)
 		installedVersion, err := version.NewVersion(installed)
 		if err != nil {
 			log.Logger.Debugf("failed to parse Ubuntu installed package version: %w", err)
@@ -138,6 +139,19 @@ func (s *Scanner) Detect(osVer string, _ *ftypes.Repository, pkgs []ftypes.Packa
 	return vulns, nil
 }
 
+// sourcePackage returns the name and the version of the source package.
+// The status file of dpkg omits "Source" when the source package has the same name and version as the binary package.
+func sourcePackage(pkg ftypes.Package) (string, string) {
+	name, ver := pkg.SrcName, utils.FormatSrcVersion(pkg)
+	if name == "" {
+		name = pkg.Name
+	}
+	if ver == "" {
+		ver = utils.FormatVersion(pkg)
+	}
+	return name, ver
+}
+
 // IsSupportedVersion checks is OSFamily can be scanned using Ubuntu scanner
 func (s *Scanner) IsSupportedVersion(osFamily, osVer string) bool {
 	eol, ok := eolDates[osVer]
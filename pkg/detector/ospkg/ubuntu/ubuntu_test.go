@@ -78,6 +78,28 @@ func TestScanner_Detect(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:     "without source package",
+			fixtures: []string{"testdata/fixtures/ubuntu.yaml"},
+			args: args{
+				osVer: "20.04",
+				pkgs: []ftypes.Package{
+					{
+						Name:    "wpa",
+						Epoch:   2,
+						Version: "2.9",
+						Release: "1ubuntu4.3",
+					},
+				},
+			},
+			want: []types.DetectedVulnerability{
+				{
+					PkgName:          "wpa",
+					VulnerabilityID:  "CVE-2019-9243",
+					InstalledVersion: "2:2.9-1ubuntu4.3",
+				},
+			},
+		},
 		{
 			name:     "broken bucket",
 			fixtures: []string{"testdata/fixtures/invalid.yaml", "testdata/fixtures/data-source.yaml"},
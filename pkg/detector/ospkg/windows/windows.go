@@ -0,0 +1,163 @@
+package windows
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+	"k8s.io/utils/clock"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	tos "github.com/aquasecurity/trivy/pkg/analyzer/os"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+var (
+	// End of extended support by build number
+	// cf. https://learn.microsoft.com/en-us/lifecycle/products/
+	eolDates = map[string]time.Time{
+		"14393": time.Date(2027, 1, 12, 23, 59, 59, 0, time.UTC),  // Windows Server 2016
+		"17763": time.Date(2029, 1, 9, 23, 59, 59, 0, time.UTC),   // Windows Server 2019
+		"18362": time.Date(2020, 12, 8, 23, 59, 59, 0, time.UTC),  // Windows Server, version 1903
+		"18363": time.Date(2022, 5, 10, 23, 59, 59, 0, time.UTC),  // Windows Server, version 1909
+		"19041": time.Date(2021, 12, 14, 23, 59, 59, 0, time.UTC), // Windows Server, version 2004
+		"19042": time.Date(2022, 5, 10, 23, 59, 59, 0, time.UTC),  // Windows Server, version 20H2
+		"20348": time.Date(2031, 10, 14, 23, 59, 59, 0, time.UTC), // Windows Server 2022
+		"26100": time.Date(2034, 11, 10, 23, 59, 59, 0, time.UTC), // Windows Server 2025
+	}
+)
+
+type options struct {
+	clock clock.Clock
+}
+
+type option func(*options)
+
+func WithClock(clock clock.Clock) option {
+	return func(opts *options) {
+		opts.clock = clock
+	}
+}
+
+// Scanner implements the scanner of Windows.
+// Advisories converted from the Microsoft Security Response Center (MSRC) are stored in trivy-db
+// with "windows" as the bucket name and the build number as the package name.
+// Each advisory has the OS version fixed by the cumulative update in FixedVersion and the KB IDs of the updates in VendorIDs.
+type Scanner struct {
+	dbc db.Config
+	*options
+}
+
+// NewScanner is the factory method for Scanner
+func NewScanner(opts ...option) *Scanner {
+	o := &options{
+		clock: clock.RealClock{},
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+	return &Scanner{
+		dbc:     db.Config{},
+		options: o,
+	}
+}
+
+// Detect vulnerabilities in the build of Windows using MSRC advisories
+func (s *Scanner) Detect(osVer string, _ *ftypes.Repository, pkgs []ftypes.Package) ([]types.DetectedVulnerability, error) {
+	log.Logger.Info("Detecting Windows vulnerabilities...")
+	log.Logger.Debugf("windows: os version: %s", osVer)
+
+	// Installed updates are reported as packages named after the KB IDs
+	kbs := map[string]struct{}{}
+	var osPkg *ftypes.Package
+	for i, pkg := range pkgs {
+		if pkg.Name == tos.WindowsPkg {
+			osPkg = &pkgs[i]
+			continue
+		}
+		kbs[pkg.Name] = struct{}{}
+	}
+	if osPkg == nil {
+		return nil, nil
+	}
+
+	advisories, err := s.dbc.GetAdvisories(tos.Windows, buildNumber(osPkg.Version))
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get Windows advisories: %w", err)
+	}
+
+	var vulns []types.DetectedVulnerability
+	for _, adv := range advisories {
+		if !isVulnerable(osPkg.Version, kbs, adv) {
+			continue
+		}
+		vulns = append(vulns, types.DetectedVulnerability{
+			VulnerabilityID:  adv.VulnerabilityID,
+			PkgName:          osPkg.Name,
+			InstalledVersion: osPkg.Version,
+			FixedVersion:     adv.FixedVersion,
+			Layer:            osPkg.Layer,
+			Custom:           adv.Custom,
+			DataSource:       adv.DataSource,
+		})
+	}
+	return vulns, nil
+}
+
+// isVulnerable returns true if the build is older than the fixed one and none of the updates fixing the vulnerability are installed.
+// Updates can be installed without changing the update build revision, e.g. servicing stack updates and out-of-band updates.
+func isVulnerable(installed string, kbs map[string]struct{}, adv dbTypes.Advisory) bool {
+	for _, kb := range adv.VendorIDs {
+		if _, ok := kbs[kb]; ok {
+			return false
+		}
+	}
+	if adv.FixedVersion == "" {
+		return true
+	}
+	return compareVersions(installed, adv.FixedVersion) < 0
+}
+
+// IsSupportedVersion checks if the build of Windows is still supported
+func (s *Scanner) IsSupportedVersion(osFamily, osVer string) bool {
+	eol, ok := eolDates[buildNumber(osVer)]
+	if !ok {
+		log.Logger.Infof("This OS version is not on the EOL list: %s %s", osFamily, osVer)
+		return true // may be the latest version
+	}
+	return s.clock.Now().Before(eol)
+}
+
+// buildNumber returns the build number of the version, e.g. 10.0.17763.3406 => 17763
+func buildNumber(ver string) string {
+	if ss := strings.Split(ver, "."); len(ss) >= 3 {
+		return ss[2]
+	}
+	return ver
+}
+
+// compareVersions compares dotted versions numerically, e.g. 10.0.17763.999 < 10.0.17763.3406
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
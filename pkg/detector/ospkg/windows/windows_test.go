@@ -0,0 +1,158 @@
+package windows_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	fake "k8s.io/utils/clock/testing"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/dbtest"
+	"github.com/aquasecurity/trivy/pkg/detector/ospkg/windows"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestScanner_Detect(t *testing.T) {
+	tests := []struct {
+		name     string
+		fixtures []string
+		pkgs     []ftypes.Package
+		want     []types.DetectedVulnerability
+		wantErr  string
+	}{
+		{
+			name:     "Windows Server 2019",
+			fixtures: []string{"testdata/fixtures/windows.yaml", "testdata/fixtures/data-source.yaml"},
+			pkgs: []ftypes.Package{
+				{
+					Name:    "windows",
+					Version: "10.0.17763.3406",
+					Layer: ftypes.Layer{
+						DiffID: "sha256:932da51564135c98a49a34a193d6cd363d8fa4184d957fde16c9d8527b3f3b02",
+					},
+				},
+				{
+					Name:    "KB5017315",
+					Version: "17763.3406.1.5",
+					Arch:    "amd64",
+				},
+				{
+					// The out-of-band update fixes CVE-2022-41073 without changing the build
+					Name:    "KB5020438",
+					Version: "17763.3408.1.1",
+					Arch:    "amd64",
+				},
+				{
+					Name:    "NET-Framework-45-Core",
+					Version: "10.0.17763.3406",
+				},
+			},
+			want: []types.DetectedVulnerability{
+				{
+					VulnerabilityID:  "CVE-2022-41033",
+					PkgName:          "windows",
+					InstalledVersion: "10.0.17763.3406",
+					FixedVersion:     "10.0.17763.3532",
+					Layer: ftypes.Layer{
+						DiffID: "sha256:932da51564135c98a49a34a193d6cd363d8fa4184d957fde16c9d8527b3f3b02",
+					},
+					DataSource: &dbTypes.DataSource{
+						ID:   "windows",
+						Name: "Microsoft Security Response Center",
+						URL:  "https://msrc.microsoft.com/update-guide",
+					},
+				},
+				{
+					VulnerabilityID:  "CVE-2022-41128",
+					PkgName:          "windows",
+					InstalledVersion: "10.0.17763.3406",
+					FixedVersion:     "10.0.17763.3650",
+					Layer: ftypes.Layer{
+						DiffID: "sha256:932da51564135c98a49a34a193d6cd363d8fa4184d957fde16c9d8527b3f3b02",
+					},
+					DataSource: &dbTypes.DataSource{
+						ID:   "windows",
+						Name: "Microsoft Security Response Center",
+						URL:  "https://msrc.microsoft.com/update-guide",
+					},
+				},
+			},
+		},
+		{
+			name:     "no build",
+			fixtures: []string{"testdata/fixtures/windows.yaml"},
+			pkgs: []ftypes.Package{
+				{
+					Name:    "KB5017315",
+					Version: "17763.3406.1.5",
+				},
+			},
+		},
+		{
+			name:     "invalid bucket",
+			fixtures: []string{"testdata/fixtures/invalid.yaml"},
+			pkgs: []ftypes.Package{
+				{
+					Name:    "windows",
+					Version: "10.0.17763.3406",
+				},
+			},
+			wantErr: "failed to get Windows advisories",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = dbtest.InitDB(t, tt.fixtures)
+			defer db.Close()
+
+			s := windows.NewScanner()
+			got, err := s.Detect("10.0.17763.3406", nil, tt.pkgs)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestScanner_IsSupportedVersion(t *testing.T) {
+	tests := []struct {
+		name  string
+		now   time.Time
+		osVer string
+		want  bool
+	}{
+		{
+			name:  "Windows Server 2019",
+			now:   time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC),
+			osVer: "10.0.17763.3406",
+			want:  true,
+		},
+		{
+			name:  "Windows Server, version 2004",
+			now:   time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC),
+			osVer: "10.0.19041.1415",
+			want:  false,
+		},
+		{
+			name:  "unknown",
+			now:   time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC),
+			osVer: "10.0.99999.1",
+			want:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := windows.NewScanner(windows.WithClock(fake.NewFakeClock(tt.now)))
+			got := s.IsSupportedVersion("windows", tt.osVer)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
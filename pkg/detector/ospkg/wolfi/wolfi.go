@@ -0,0 +1,85 @@
+package wolfi
+
+import (
+	version "github.com/knqyf263/go-apk-version"
+	"golang.org/x/xerrors"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/scanner/utils"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Scanner implements the scanner of Wolfi and Chainguard OS.
+// They are rolling distributions based on apk, and publish their own security databases,
+// which are stored in trivy-db with the OS family as the bucket name.
+type Scanner struct {
+	family string
+	dbc    db.Config
+}
+
+// NewScanner is the factory method for Scanner
+func NewScanner(family string) *Scanner {
+	return &Scanner{
+		family: family,
+		dbc:    db.Config{},
+	}
+}
+
+// Detect vulnerabilities in packages using the security database of the distribution
+func (s *Scanner) Detect(_ string, _ *ftypes.Repository, pkgs []ftypes.Package) ([]types.DetectedVulnerability, error) {
+	log.Logger.Infof("Detecting %s vulnerabilities...", s.family)
+	log.Logger.Debugf("%s: the number of packages: %d", s.family, len(pkgs))
+
+	var vulns []types.DetectedVulnerability
+	for _, pkg := range pkgs {
+		srcName := pkg.SrcName
+		if srcName == "" {
+			srcName = pkg.Name
+		}
+		advisories, err := s.dbc.GetAdvisories(s.family, srcName)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to get %s advisories: %w", s.family, err)
+		}
+
+		installed := utils.FormatVersion(pkg)
+		installedVersion, err := version.NewVersion(installed)
+		if err != nil {
+			log.Logger.Debugf("failed to parse %s installed package version: %s", s.family, err)
+			continue
+		}
+
+		for _, adv := range advisories {
+			if !s.isVulnerable(installedVersion, adv) {
+				continue
+			}
+			vulns = append(vulns, types.DetectedVulnerability{
+				VulnerabilityID:  adv.VulnerabilityID,
+				PkgName:          pkg.Name,
+				InstalledVersion: installed,
+				FixedVersion:     adv.FixedVersion,
+				Layer:            pkg.Layer,
+				Custom:           adv.Custom,
+				DataSource:       adv.DataSource,
+			})
+		}
+	}
+	return vulns, nil
+}
+
+func (s *Scanner) isVulnerable(installedVersion version.Version, adv dbTypes.Advisory) bool {
+	// The security databases have only fixed vulnerabilities
+	fixedVersion, err := version.NewVersion(adv.FixedVersion)
+	if err != nil {
+		log.Logger.Debugf("failed to parse %s fixed version: %s", s.family, err)
+		return false
+	}
+	return installedVersion.LessThan(fixedVersion)
+}
+
+// IsSupportedVersion always returns true since they are rolling distributions without EOL
+func (s *Scanner) IsSupportedVersion(_, _ string) bool {
+	return true
+}
@@ -0,0 +1,113 @@
+package wolfi_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	tos "github.com/aquasecurity/trivy/pkg/analyzer/os"
+	"github.com/aquasecurity/trivy/pkg/dbtest"
+	"github.com/aquasecurity/trivy/pkg/detector/ospkg/wolfi"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestScanner_Detect(t *testing.T) {
+	tests := []struct {
+		name     string
+		family   string
+		fixtures []string
+		pkgs     []ftypes.Package
+		want     []types.DetectedVulnerability
+		wantErr  string
+	}{
+		{
+			name:     "Wolfi",
+			family:   tos.Wolfi,
+			fixtures: []string{"testdata/fixtures/wolfi.yaml", "testdata/fixtures/data-source.yaml"},
+			pkgs: []ftypes.Package{
+				{
+					Name:    "libcrypto3",
+					Version: "3.0.6-r0",
+					SrcName: "openssl",
+					Layer: ftypes.Layer{
+						DiffID: "sha256:f3ba08d3bd5b1ec8dd4b4e5a2c5a2bc0e2bb3cde6dc58ad5ec1dd4c5dd2b3a6a",
+					},
+				},
+			},
+			want: []types.DetectedVulnerability{
+				{
+					VulnerabilityID:  "CVE-2022-3602",
+					PkgName:          "libcrypto3",
+					InstalledVersion: "3.0.6-r0",
+					FixedVersion:     "3.0.7-r0",
+					Layer: ftypes.Layer{
+						DiffID: "sha256:f3ba08d3bd5b1ec8dd4b4e5a2c5a2bc0e2bb3cde6dc58ad5ec1dd4c5dd2b3a6a",
+					},
+					DataSource: &dbTypes.DataSource{
+						ID:   "wolfi",
+						Name: "Wolfi Secdb",
+						URL:  "https://packages.wolfi.dev/os/security.json",
+					},
+				},
+			},
+		},
+		{
+			name:     "Chainguard",
+			family:   tos.Chainguard,
+			fixtures: []string{"testdata/fixtures/wolfi.yaml"},
+			pkgs: []ftypes.Package{
+				{
+					Name:    "busybox",
+					Version: "1.35.0-r2",
+				},
+				{
+					// Advisories of Wolfi are not used for Chainguard OS
+					Name:    "libssl3",
+					Version: "3.0.6-r0",
+					SrcName: "openssl",
+				},
+			},
+			want: []types.DetectedVulnerability{
+				{
+					VulnerabilityID:  "CVE-2022-28391",
+					PkgName:          "busybox",
+					InstalledVersion: "1.35.0-r2",
+					FixedVersion:     "1.35.0-r3",
+				},
+			},
+		},
+		{
+			name:     "invalid bucket",
+			family:   tos.Wolfi,
+			fixtures: []string{"testdata/fixtures/invalid.yaml"},
+			pkgs: []ftypes.Package{
+				{
+					Name:    "openssl",
+					Version: "3.0.6-r0",
+					SrcName: "openssl",
+				},
+			},
+			wantErr: "failed to get wolfi advisories",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = dbtest.InitDB(t, tt.fixtures)
+			defer db.Close()
+
+			s := wolfi.NewScanner(tt.family)
+			got, err := s.Detect("20230201", nil, tt.pkgs)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package dnf
+
+import (
+	"fmt"
+	"strings"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+)
+
+// ModuleType is the type of custom resources holding the module streams enabled in DNF
+const ModuleType = "trivy-dnf-module"
+
+// Module represents a module stream enabled in /etc/dnf/modules.d
+type Module struct {
+	Name   string
+	Stream string
+}
+
+// FillModularityLabels fills the modularity labels of modular packages with the enabled module streams.
+// Some packages are built in modules but don't have the label in the RPM header, e.g. packages of AlmaLinux 8.3,
+// and they are matched with advisories of the non-modular package or those of other streams.
+// Such packages are identified by the release, e.g. "1.module+el8.3.0+8021+d6e6d5a4" and "1.module_el8.3.0+2049+8c6c9e0a",
+// and the module is identified by the source package name, e.g. "npm" built from "nodejs" is in the "nodejs" module.
+// The label has neither the version nor the context of the module, which are not in the DNF configuration.
+func FillModularityLabels(pkgs []ftypes.Package, modules []Module) []ftypes.Package {
+	if len(modules) == 0 {
+		return pkgs
+	}
+
+	streams := map[string]string{}
+	for _, m := range modules {
+		streams[m.Name] = m.Stream
+	}
+
+	filled := make([]ftypes.Package, 0, len(pkgs))
+	for _, pkg := range pkgs {
+		if pkg.Modularitylabel == "" && strings.Contains(pkg.Release, ".module") {
+			if name, stream, ok := moduleStream(pkg, streams); ok {
+				// e.g. nodejs:18::, the version and the context are empty
+				pkg.Modularitylabel = fmt.Sprintf("%s:%s::", name, stream)
+			}
+		}
+		filled = append(filled, pkg)
+	}
+	return filled
+}
+
+func moduleStream(pkg ftypes.Package, streams map[string]string) (string, string, bool) {
+	for _, name := range []string{pkg.SrcName, pkg.Name} {
+		if stream, ok := streams[name]; ok && name != "" {
+			return name, stream, true
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,129 @@
+package dnf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+)
+
+func TestFillModularityLabels(t *testing.T) {
+	tests := []struct {
+		name    string
+		pkgs    []ftypes.Package
+		modules []Module
+		want    []ftypes.Package
+	}{
+		{
+			name: "modular package without label",
+			pkgs: []ftypes.Package{
+				{
+					Name:    "npm",
+					Version: "8.19.2",
+					Release: "1.18.12.1.1.module_el8.7.0+3370+a2a2e8b4",
+					SrcName: "nodejs",
+				},
+				{
+					Name:    "nodejs",
+					Version: "18.12.1",
+					Release: "1.module_el8.7.0+3370+a2a2e8b4",
+					SrcName: "nodejs",
+				},
+			},
+			modules: []Module{
+				{
+					Name:   "nodejs",
+					Stream: "18",
+				},
+			},
+			want: []ftypes.Package{
+				{
+					Name:            "npm",
+					Version:         "8.19.2",
+					Release:         "1.18.12.1.1.module_el8.7.0+3370+a2a2e8b4",
+					SrcName:         "nodejs",
+					Modularitylabel: "nodejs:18::",
+				},
+				{
+					Name:            "nodejs",
+					Version:         "18.12.1",
+					Release:         "1.module_el8.7.0+3370+a2a2e8b4",
+					SrcName:         "nodejs",
+					Modularitylabel: "nodejs:18::",
+				},
+			},
+		},
+		{
+			name: "label in the RPM header",
+			pkgs: []ftypes.Package{
+				{
+					Name:            "nodejs",
+					Version:         "16.18.1",
+					Release:         "3.module+el8.7.0+17465+1a1abd74",
+					SrcName:         "nodejs",
+					Modularitylabel: "nodejs:16:8070020221123084300:3c67ad0d",
+				},
+			},
+			modules: []Module{
+				{
+					Name:   "nodejs",
+					Stream: "18",
+				},
+			},
+			want: []ftypes.Package{
+				{
+					Name:            "nodejs",
+					Version:         "16.18.1",
+					Release:         "3.module+el8.7.0+17465+1a1abd74",
+					SrcName:         "nodejs",
+					Modularitylabel: "nodejs:16:8070020221123084300:3c67ad0d",
+				},
+			},
+		},
+		{
+			name: "non-modular package",
+			pkgs: []ftypes.Package{
+				{
+					Name:    "openssl",
+					Version: "1.1.1k",
+					Release: "7.el8_6",
+					SrcName: "openssl",
+				},
+				{
+					// The module is not enabled
+					Name:    "nginx",
+					Version: "1.20.1",
+					Release: "1.module_el8.6.0+2777+a0c5c3a4",
+					SrcName: "nginx",
+				},
+			},
+			modules: []Module{
+				{
+					Name:   "nodejs",
+					Stream: "18",
+				},
+			},
+			want: []ftypes.Package{
+				{
+					Name:    "openssl",
+					Version: "1.1.1k",
+					Release: "7.el8_6",
+					SrcName: "openssl",
+				},
+				{
+					Name:    "nginx",
+					Version: "1.20.1",
+					Release: "1.module_el8.6.0+2777+a0c5c3a4",
+					SrcName: "nginx",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FillModularityLabels(tt.pkgs, tt.modules)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
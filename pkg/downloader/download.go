@@ -6,6 +6,8 @@ import (
 
 	getter "github.com/hashicorp/go-getter"
 	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/httpclient"
 )
 
 // DownloadToTempDir downloads the configured source to a temp dir.
@@ -37,6 +39,14 @@ func Download(ctx context.Context, src, dst, pwd string) error {
 	// Overwrite the file getter so that a file will be copied
 	getter.Getters["file"] = &getter.FileGetter{Copy: true}
 
+	// Overwrite the HTTP getters so that the proxy settings and the CA bundle apply
+	httpGetter := &getter.HttpGetter{
+		Netrc:  true,
+		Client: httpclient.Client(0, false),
+	}
+	getter.Getters["http"] = httpGetter
+	getter.Getters["https"] = httpGetter
+
 	// Build the client
 	client := &getter.Client{
 		Ctx:     ctx,
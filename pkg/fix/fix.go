@@ -0,0 +1,191 @@
+package fix
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/samber/lo"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/remediation"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// fixer rewrites the file content and returns the upgrades actually applied
+type fixer func(content []byte, upgrades []types.DependencyRemediation) ([]byte, []types.DependencyRemediation, error)
+
+var fixers = map[string]fixer{
+	ftypes.Npm:      fixNpm,
+	ftypes.GoModule: fixGoMod,
+	ftypes.Pip:      fixPip,
+}
+
+// Option holds the options to select vulnerabilities to be fixed
+type Option struct {
+	Severities []dbTypes.Severity
+
+	// All the vulnerabilities are fixed if empty
+	VulnerabilityIDs []string
+}
+
+// Change represents the upgrades applied to a file
+type Change struct {
+	// The path relative to the scanned directory, e.g. "app/package-lock.json"
+	FilePath string
+	Upgrades []types.DependencyRemediation
+}
+
+// Fix rewrites lock files in the directory to the minimal fixed versions of the vulnerabilities in the report.
+// The files must be the targets of the report, i.e. the report must be generated by scanning the directory.
+// VCS operations such as commits are left to users.
+func Fix(dir string, report types.Report, opt Option) ([]Change, error) {
+	severities := lo.Map(opt.Severities, func(s dbTypes.Severity, _ int) string {
+		return s.String()
+	})
+
+	// Workspaces share the lock file, so the upgrades are merged per file
+	type target struct {
+		fix      fixer
+		upgrades []types.DependencyRemediation
+	}
+	targets := map[string]*target{}
+	for _, result := range report.Results {
+		f, ok := fixers[result.Type]
+		if !ok {
+			continue
+		}
+
+		result.Vulnerabilities = lo.Filter(result.Vulnerabilities, func(vuln types.DetectedVulnerability, _ int) bool {
+			if len(opt.VulnerabilityIDs) > 0 && !slices.Contains(opt.VulnerabilityIDs, vuln.VulnerabilityID) {
+				return false
+			}
+			return len(severities) == 0 || slices.Contains(severities, vuln.Severity)
+		})
+		upgrades := remediation.Upgrades(result)
+		if len(upgrades) == 0 {
+			continue
+		}
+
+		filePath, err := lockFile(dir, result)
+		if err != nil {
+			return nil, xerrors.Errorf("unable to fix %s: %w", result.Target, err)
+		}
+
+		t, ok := targets[filePath]
+		if !ok {
+			t = &target{fix: f}
+			targets[filePath] = t
+		}
+		for _, upgrade := range upgrades {
+			if !containsUpgrade(t.upgrades, upgrade) {
+				t.upgrades = append(t.upgrades, upgrade)
+			}
+		}
+	}
+
+	var changes []Change
+	for filePath, t := range targets {
+		applied, err := fixFile(filepath.Join(dir, filepath.FromSlash(filePath)), t.fix, t.upgrades)
+		if err != nil {
+			return nil, xerrors.Errorf("unable to fix %s: %w", filePath, err)
+		}
+
+		// Upgrades are reported in the order of packages, not the order in the file
+		upgrades := lo.Filter(t.upgrades, func(upgrade types.DependencyRemediation, _ int) bool {
+			if !containsUpgrade(applied, upgrade) {
+				log.Logger.Warnf("%s@%s is not upgraded in %s", upgrade.PkgName, upgrade.InstalledVersion, filePath)
+				return false
+			}
+			return true
+		})
+		if len(upgrades) == 0 {
+			continue
+		}
+
+		changes = append(changes, Change{
+			FilePath: filePath,
+			Upgrades: upgrades,
+		})
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].FilePath < changes[j].FilePath
+	})
+	return changes, nil
+}
+
+// lockFile returns the path of the lock file to be fixed for the result.
+// Packages of npm workspaces are reported with package.json of the workspaces,
+// so package-lock.json is looked for in the parent directories up to the scanned directory.
+func lockFile(dir string, result types.Result) (string, error) {
+	if result.Type != ftypes.Npm || path.Base(result.Target) != npmManifest {
+		return result.Target, nil
+	}
+
+	for d := path.Dir(result.Target); ; d = path.Dir(d) {
+		target := path.Join(d, npmLockFile)
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(target))); err == nil {
+			return target, nil
+		}
+		if d == "." || d == "/" {
+			return "", xerrors.Errorf("%s not found", npmLockFile)
+		}
+	}
+}
+
+func fixFile(filePath string, f fixer, upgrades []types.DependencyRemediation) ([]types.DependencyRemediation, error) {
+	fi, err := os.Stat(filePath)
+	if err != nil {
+		return nil, xerrors.Errorf("stat error: %w", err)
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, xerrors.Errorf("read error: %w", err)
+	}
+
+	fixed, applied, err := f(content, upgrades)
+	if err != nil {
+		return nil, err
+	} else if len(applied) == 0 {
+		return nil, nil
+	}
+
+	if err = os.WriteFile(filePath, fixed, fi.Mode().Perm()); err != nil {
+		return nil, xerrors.Errorf("write error: %w", err)
+	}
+	return applied, nil
+}
+
+// edit replaces the range of the content with the text
+type edit struct {
+	start, end int
+	text       string
+}
+
+// applyEdits applies non-overlapping edits to the content
+func applyEdits(content []byte, edits []edit) []byte {
+	sort.Slice(edits, func(i, j int) bool {
+		return edits[i].start < edits[j].start
+	})
+
+	var fixed []byte
+	last := 0
+	for _, e := range edits {
+		fixed = append(fixed, content[last:e.start]...)
+		fixed = append(fixed, e.text...)
+		last = e.end
+	}
+	return append(fixed, content[last:]...)
+}
+
+func containsUpgrade(upgrades []types.DependencyRemediation, upgrade types.DependencyRemediation) bool {
+	return lo.ContainsBy(upgrades, func(u types.DependencyRemediation) bool {
+		return u.PkgName == upgrade.PkgName && u.InstalledVersion == upgrade.InstalledVersion
+	})
+}
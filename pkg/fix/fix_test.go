@@ -0,0 +1,312 @@
+package fix_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/fix"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestFix(t *testing.T) {
+	tests := []struct {
+		name      string
+		inputFile string
+		result    types.Result
+		opt       fix.Option
+		want      []fix.Change
+	}{
+		{
+			name:      "package-lock.json",
+			inputFile: "testdata/npm/package-lock.json",
+			result: types.Result{
+				Target: "package-lock.json",
+				Type:   ftypes.Npm,
+				Vulnerabilities: []types.DetectedVulnerability{
+					{
+						VulnerabilityID:  "CVE-2021-23337",
+						PkgID:            "lodash@4.17.15",
+						PkgName:          "lodash",
+						InstalledVersion: "4.17.15",
+						FixedVersion:     "4.17.21",
+					},
+					{
+						VulnerabilityID:  "CVE-2021-44906",
+						PkgID:            "minimist@1.2.0",
+						PkgName:          "minimist",
+						InstalledVersion: "1.2.0",
+						FixedVersion:     "0.2.4, 1.2.6",
+					},
+				},
+			},
+			want: []fix.Change{
+				{
+					FilePath: "package-lock.json",
+					Upgrades: []types.DependencyRemediation{
+						{
+							PkgID:            "lodash@4.17.15",
+							PkgName:          "lodash",
+							InstalledVersion: "4.17.15",
+							FixedVersion:     "4.17.21",
+							VulnerabilityIDs: []string{"CVE-2021-23337"},
+						},
+						{
+							PkgID:            "minimist@1.2.0",
+							PkgName:          "minimist",
+							InstalledVersion: "1.2.0",
+							FixedVersion:     "1.2.6",
+							VulnerabilityIDs: []string{"CVE-2021-44906"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "go.mod",
+			inputFile: "testdata/gomod/go.mod",
+			result: types.Result{
+				Target: "go.mod",
+				Type:   ftypes.GoModule,
+				Vulnerabilities: []types.DetectedVulnerability{
+					{
+						VulnerabilityID:  "CVE-2020-28483",
+						PkgName:          "github.com/gin-gonic/gin",
+						InstalledVersion: "1.6.2",
+						FixedVersion:     "1.7.0",
+					},
+					{
+						VulnerabilityID:  "CVE-2022-0001",
+						PkgName:          "github.com/org/fork",
+						InstalledVersion: "1.0.1",
+						FixedVersion:     "1.0.2",
+					},
+					{
+						VulnerabilityID:  "CVE-2020-14040",
+						PkgName:          "golang.org/x/text",
+						InstalledVersion: "0.3.2",
+						FixedVersion:     "0.3.3",
+					},
+				},
+			},
+			want: []fix.Change{
+				{
+					FilePath: "go.mod",
+					Upgrades: []types.DependencyRemediation{
+						{
+							PkgName:          "github.com/gin-gonic/gin",
+							InstalledVersion: "1.6.2",
+							FixedVersion:     "1.7.0",
+							VulnerabilityIDs: []string{"CVE-2020-28483"},
+						},
+						{
+							PkgName:          "golang.org/x/text",
+							InstalledVersion: "0.3.2",
+							FixedVersion:     "0.3.3",
+							VulnerabilityIDs: []string{"CVE-2020-14040"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "requirements.txt with severities",
+			inputFile: "testdata/pip/requirements.txt",
+			result: types.Result{
+				Target: "requirements.txt",
+				Type:   ftypes.Pip,
+				Vulnerabilities: []types.DetectedVulnerability{
+					{
+						VulnerabilityID:  "CVE-2021-31542",
+						PkgName:          "django",
+						InstalledVersion: "3.2.0",
+						FixedVersion:     "2.2.21, 3.1.9, 3.2.1",
+						Vulnerability: dbTypes.Vulnerability{
+							Severity: "HIGH",
+						},
+					},
+					{
+						VulnerabilityID:  "CVE-2021-33203",
+						PkgName:          "django",
+						InstalledVersion: "3.2.0",
+						FixedVersion:     "2.2.24, 3.1.12, 3.2.4",
+						Vulnerability: dbTypes.Vulnerability{
+							Severity: "MEDIUM",
+						},
+					},
+					{
+						VulnerabilityID:  "CVE-2021-33503",
+						PkgName:          "requests",
+						InstalledVersion: "2.25.0",
+						FixedVersion:     "2.25.1",
+						Vulnerability: dbTypes.Vulnerability{
+							Severity: "HIGH",
+						},
+					},
+					{
+						VulnerabilityID:  "CVE-2020-28493",
+						PkgName:          "jinja2",
+						InstalledVersion: "2.11.2",
+						FixedVersion:     "2.11.3",
+						Vulnerability: dbTypes.Vulnerability{
+							Severity: "LOW",
+						},
+					},
+				},
+			},
+			opt: fix.Option{
+				Severities: []dbTypes.Severity{
+					dbTypes.SeverityMedium,
+					dbTypes.SeverityHigh,
+				},
+			},
+			want: []fix.Change{
+				{
+					FilePath: "requirements.txt",
+					Upgrades: []types.DependencyRemediation{
+						{
+							PkgName:          "django",
+							InstalledVersion: "3.2.0",
+							FixedVersion:     "3.2.4",
+							VulnerabilityIDs: []string{"CVE-2021-31542", "CVE-2021-33203"},
+						},
+						{
+							PkgName:          "requests",
+							InstalledVersion: "2.25.0",
+							FixedVersion:     "2.25.1",
+							VulnerabilityIDs: []string{"CVE-2021-33503"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "no selected vulnerability",
+			inputFile: "testdata/pip/requirements.txt",
+			result: types.Result{
+				Target: "requirements.txt",
+				Type:   ftypes.Pip,
+				Vulnerabilities: []types.DetectedVulnerability{
+					{
+						VulnerabilityID:  "CVE-2020-28493",
+						PkgName:          "jinja2",
+						InstalledVersion: "2.11.2",
+						FixedVersion:     "2.11.3",
+					},
+				},
+			},
+			opt: fix.Option{
+				VulnerabilityIDs: []string{"CVE-2021-33503"},
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			content, err := os.ReadFile(tt.inputFile)
+			require.NoError(t, err)
+
+			filePath := filepath.Join(dir, tt.result.Target)
+			require.NoError(t, os.WriteFile(filePath, content, 0644))
+
+			report := types.Report{
+				Results: types.Results{tt.result},
+			}
+			got, err := fix.Fix(dir, report, tt.opt)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			want := content
+			if len(tt.want) > 0 {
+				want, err = os.ReadFile(tt.inputFile + ".golden")
+				require.NoError(t, err)
+			}
+			fixed, err := os.ReadFile(filePath)
+			require.NoError(t, err)
+			assert.Equal(t, string(want), string(fixed))
+		})
+	}
+}
+
+func TestFix_Workspaces(t *testing.T) {
+	dir := t.TempDir()
+	content, err := os.ReadFile("testdata/npm/package-lock.json")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "package-lock.json"), content, 0644))
+
+	// Packages of the workspace are fixed in package-lock.json of the root project
+	report := types.Report{
+		Results: types.Results{
+			{
+				Target: "package-lock.json",
+				Type:   ftypes.Npm,
+				Vulnerabilities: []types.DetectedVulnerability{
+					{
+						VulnerabilityID:  "CVE-2021-23337",
+						PkgID:            "lodash@4.17.15",
+						PkgName:          "lodash",
+						InstalledVersion: "4.17.15",
+						FixedVersion:     "4.17.21",
+					},
+				},
+			},
+			{
+				Target: "packages/a/package.json",
+				Type:   ftypes.Npm,
+				Vulnerabilities: []types.DetectedVulnerability{
+					{
+						VulnerabilityID:  "CVE-2021-23337",
+						PkgID:            "lodash@4.17.15",
+						PkgName:          "lodash",
+						InstalledVersion: "4.17.15",
+						FixedVersion:     "4.17.21",
+					},
+					{
+						VulnerabilityID:  "CVE-2021-44906",
+						PkgID:            "minimist@1.2.0",
+						PkgName:          "minimist",
+						InstalledVersion: "1.2.0",
+						FixedVersion:     "0.2.4, 1.2.6",
+					},
+				},
+			},
+		},
+	}
+	got, err := fix.Fix(dir, report, fix.Option{})
+	require.NoError(t, err)
+
+	want := []fix.Change{
+		{
+			FilePath: "package-lock.json",
+			Upgrades: []types.DependencyRemediation{
+				{
+					PkgID:            "lodash@4.17.15",
+					PkgName:          "lodash",
+					InstalledVersion: "4.17.15",
+					FixedVersion:     "4.17.21",
+					VulnerabilityIDs: []string{"CVE-2021-23337"},
+				},
+				{
+					PkgID:            "minimist@1.2.0",
+					PkgName:          "minimist",
+					InstalledVersion: "1.2.0",
+					FixedVersion:     "1.2.6",
+					VulnerabilityIDs: []string{"CVE-2021-44906"},
+				},
+			},
+		},
+	}
+	assert.Equal(t, want, got)
+
+	wantContent, err := os.ReadFile("testdata/npm/package-lock.json.golden")
+	require.NoError(t, err)
+	fixed, err := os.ReadFile(filepath.Join(dir, "package-lock.json"))
+	require.NoError(t, err)
+	assert.Equal(t, string(wantContent), string(fixed))
+}
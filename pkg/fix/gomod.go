@@ -0,0 +1,58 @@
+package fix
+
+import (
+	"strings"
+
+	"github.com/samber/lo"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// fixGoMod rewrites require directives of vulnerable modules in go.mod.
+// Indirect modules are upgraded in the same way as direct ones, and "go mod tidy" updates go.sum.
+// Replaced modules are not upgraded as the replacement decides the version.
+func fixGoMod(content []byte, upgrades []types.DependencyRemediation) ([]byte, []types.DependencyRemediation, error) {
+	f, err := modfile.Parse("go.mod", content, nil)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("go.mod parse error: %w", err)
+	}
+
+	var applied []types.DependencyRemediation
+	for _, upgrade := range upgrades {
+		if lo.ContainsBy(f.Replace, func(r *modfile.Replace) bool {
+			return r.Old.Path == upgrade.PkgName
+		}) {
+			log.Logger.Warnf("%s is not upgraded as it is replaced in go.mod", upgrade.PkgName)
+			continue
+		}
+
+		if !lo.ContainsBy(f.Require, func(r *modfile.Require) bool {
+			return r.Mod.Path == upgrade.PkgName && r.Mod.Version == goVersion(upgrade.InstalledVersion)
+		}) {
+			continue
+		}
+
+		if err = f.AddRequire(upgrade.PkgName, goVersion(upgrade.FixedVersion)); err != nil {
+			return nil, nil, xerrors.Errorf("unable to upgrade %s: %w", upgrade.PkgName, err)
+		}
+		applied = append(applied, upgrade)
+	}
+	if len(applied) == 0 {
+		return content, nil, nil
+	}
+
+	f.Cleanup()
+	fixed, err := f.Format()
+	if err != nil {
+		return nil, nil, xerrors.Errorf("go.mod format error: %w", err)
+	}
+	return fixed, applied, nil
+}
+
+// goVersion adds the "v" prefix removed by the parser
+func goVersion(ver string) string {
+	return "v" + strings.TrimPrefix(ver, "v")
+}
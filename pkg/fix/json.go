@@ -0,0 +1,92 @@
+package fix
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"golang.org/x/xerrors"
+)
+
+// jsonString represents a string value in a JSON document with its location.
+// Lock files are rewritten in place so that the formatting and the order of keys are preserved.
+type jsonString struct {
+	path  []string
+	value string
+
+	// The range of the quoted value in the document
+	start, end int
+}
+
+type jsonFrame struct {
+	object  bool
+	key     string
+	wantKey bool
+}
+
+// walkJSON calls the function for each string value in objects
+func walkJSON(content []byte, fn func(s jsonString)) error {
+	dec := json.NewDecoder(bytes.NewReader(content))
+
+	var stack []jsonFrame
+	valueDone := func() {
+		if len(stack) > 0 && stack[len(stack)-1].object {
+			stack[len(stack)-1].wantKey = true
+		}
+	}
+	path := func() []string {
+		var keys []string
+		for _, f := range stack {
+			if f.object {
+				keys = append(keys, f.key)
+			}
+		}
+		return keys
+	}
+
+	for {
+		offset := int(dec.InputOffset())
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return xerrors.Errorf("json decode error: %w", err)
+		}
+
+		if len(stack) > 0 && stack[len(stack)-1].wantKey {
+			if key, ok := tok.(string); ok {
+				stack[len(stack)-1].key = key
+				stack[len(stack)-1].wantKey = false
+				continue
+			}
+		}
+
+		switch t := tok.(type) {
+		case json.Delim:
+			switch t {
+			case '{':
+				stack = append(stack, jsonFrame{object: true, wantKey: true})
+			case '[':
+				stack = append(stack, jsonFrame{})
+			default:
+				stack = stack[:len(stack)-1]
+				valueDone()
+			}
+		case string:
+			// Separators and whitespaces before the value are consumed by Token()
+			start := offset + bytes.IndexByte(content[offset:], '"')
+			if len(stack) > 0 && stack[len(stack)-1].object {
+				fn(jsonString{
+					path:  path(),
+					value: t,
+					start: start,
+					end:   int(dec.InputOffset()),
+				})
+			}
+			valueDone()
+		default:
+			valueDone()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package fix
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const (
+	nodeModulesDir = "node_modules/"
+	npmLockFile    = "package-lock.json"
+	npmManifest    = "package.json"
+)
+
+// npmEntry represents a package in package-lock.json
+type npmEntry struct {
+	name      string
+	version   *jsonString
+	resolved  *jsonString
+	integrity *jsonString
+}
+
+// fixNpm rewrites "version" and "resolved" of vulnerable packages in package-lock.json.
+// "integrity" is removed as it can't be computed without downloading the package,
+// and npm fills it in the next "npm install".
+// Both "packages" (lockfileVersion 2 and 3) and "dependencies" (lockfileVersion 1 and 2) are rewritten.
+func fixNpm(content []byte, upgrades []types.DependencyRemediation) ([]byte, []types.DependencyRemediation, error) {
+	entries := map[string]*npmEntry{}
+	var keys []string
+	err := walkJSON(content, func(s jsonString) {
+		if len(s.path) < 2 {
+			return
+		}
+		entryPath, field := s.path[:len(s.path)-1], s.path[len(s.path)-1]
+		name := npmPkgName(entryPath)
+		if name == "" {
+			return
+		}
+
+		key := strings.Join(entryPath, "\x00")
+		e, ok := entries[key]
+		if !ok {
+			e = &npmEntry{name: name}
+			entries[key] = e
+			keys = append(keys, key)
+		}
+
+		switch field {
+		case "version":
+			e.version = &s
+		case "resolved":
+			e.resolved = &s
+		case "integrity":
+			e.integrity = &s
+		}
+	})
+	if err != nil {
+		return nil, nil, xerrors.Errorf("package-lock.json parse error: %w", err)
+	}
+
+	var edits []edit
+	var applied []types.DependencyRemediation
+	for _, key := range keys {
+		e := entries[key]
+		if e.version == nil {
+			continue
+		}
+		upgrade, ok := lo.Find(upgrades, func(u types.DependencyRemediation) bool {
+			return u.PkgName == e.name && u.InstalledVersion == e.version.value
+		})
+		if !ok {
+			continue
+		}
+
+		entryEdits := []edit{
+			{
+				start: e.version.start,
+				end:   e.version.end,
+				text:  strconv.Quote(upgrade.FixedVersion),
+			},
+		}
+
+		// e.g. https://registry.npmjs.org/lodash/-/lodash-4.17.15.tgz
+		if e.resolved != nil {
+			suffix := fmt.Sprintf("-%s.tgz", upgrade.InstalledVersion)
+			if !strings.HasSuffix(e.resolved.value, suffix) {
+				log.Logger.Debugf("Unable to rewrite the resolved URL of %s: %s", e.name, e.resolved.value)
+				continue
+			}
+			resolved := strings.TrimSuffix(e.resolved.value, suffix) + fmt.Sprintf("-%s.tgz", upgrade.FixedVersion)
+			entryEdits = append(entryEdits, edit{
+				start: e.resolved.start,
+				end:   e.resolved.end,
+				text:  strconv.Quote(resolved),
+			})
+		}
+
+		if e.integrity != nil {
+			entryEdits = append(entryEdits, removeJSONField(content, "integrity", *e.integrity))
+		}
+
+		edits = append(edits, entryEdits...)
+		if !containsUpgrade(applied, upgrade) {
+			applied = append(applied, upgrade)
+		}
+	}
+
+	return applyEdits(content, edits), applied, nil
+}
+
+// npmPkgName returns the package name of the entry in package-lock.json, or empty if it isn't a package.
+// e.g. "@babel/core" for both ["packages", "node_modules/a/node_modules/@babel/core"]
+// and ["dependencies", "a", "dependencies", "@babel/core"]
+func npmPkgName(entryPath []string) string {
+	switch {
+	case len(entryPath) == 2 && entryPath[0] == "packages":
+		// The root package and workspaces are not in node_modules
+		idx := strings.LastIndex(entryPath[1], nodeModulesDir)
+		if idx < 0 {
+			return ""
+		}
+		return entryPath[1][idx+len(nodeModulesDir):]
+	case len(entryPath)%2 == 0:
+		for i := 0; i < len(entryPath); i += 2 {
+			if entryPath[i] != "dependencies" {
+				return ""
+			}
+		}
+		return entryPath[len(entryPath)-1]
+	}
+	return ""
+}
+
+// removeJSONField returns the edit removing the field with the comma separating it
+func removeJSONField(content []byte, key string, value jsonString) edit {
+	start := bytes.LastIndex(content[:value.start], []byte(strconv.Quote(key)))
+	end := value.end
+
+	rest := bytes.TrimLeft(content[end:], " \t\r\n")
+	if len(rest) > 0 && rest[0] == ',' {
+		// Remove the following comma and whitespaces up to the next field
+		rest = bytes.TrimLeft(rest[1:], " \t\r\n")
+		end = len(content) - len(rest)
+	} else if prev := bytes.TrimRight(content[:start], " \t\r\n"); len(prev) > 0 && prev[len(prev)-1] == ',' {
+		// The last field in the object
+		start = len(prev) - 1
+	}
+	return edit{
+		start: start,
+		end:   end,
+	}
+}
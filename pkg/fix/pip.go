@@ -0,0 +1,59 @@
+package fix
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+var (
+	// e.g. "Django==3.2.0", "requests[security] == 2.25.0 ; python_version >= '3.6'"
+	requirementLine = regexp.MustCompile(`^\s*([A-Za-z0-9][A-Za-z0-9._-]*)\s*(?:\[[^\]]*\])?\s*==\s*([^\s;#\\,]+)`)
+
+	pipNameSeparators = regexp.MustCompile(`[-_.]+`)
+)
+
+// fixPip rewrites pinned versions of vulnerable packages in requirements.txt.
+// Extras, environment markers and comments are kept as they are.
+func fixPip(content []byte, upgrades []types.DependencyRemediation) ([]byte, []types.DependencyRemediation, error) {
+	var edits []edit
+	var applied []types.DependencyRemediation
+
+	offset := 0
+	for _, line := range bytes.SplitAfter(content, []byte("\n")) {
+		lineOffset := offset
+		offset += len(line)
+
+		m := requirementLine.FindSubmatchIndex(line)
+		if m == nil {
+			continue
+		}
+		name, ver := string(line[m[2]:m[3]]), string(line[m[4]:m[5]])
+
+		upgrade, ok := lo.Find(upgrades, func(u types.DependencyRemediation) bool {
+			return normalizePipName(u.PkgName) == normalizePipName(name) && u.InstalledVersion == ver
+		})
+		if !ok {
+			continue
+		}
+
+		edits = append(edits, edit{
+			start: lineOffset + m[4],
+			end:   lineOffset + m[5],
+			text:  upgrade.FixedVersion,
+		})
+		if !containsUpgrade(applied, upgrade) {
+			applied = append(applied, upgrade)
+		}
+	}
+	return applyEdits(content, edits), applied, nil
+}
+
+// normalizePipName normalizes the package name as described in PEP 503
+func normalizePipName(name string) string {
+	return pipNameSeparators.ReplaceAllString(strings.ToLower(name), "-")
+}
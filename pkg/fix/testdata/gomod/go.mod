@@ -0,0 +1,16 @@
+module github.com/org/app
+
+go 1.18
+
+require (
+	github.com/gin-gonic/gin v1.6.2
+	github.com/org/replaced v1.0.0
+)
+
+require (
+	// needed by gin
+	golang.org/x/text v0.3.2 // indirect
+	gopkg.in/yaml.v2 v2.2.8 // indirect
+)
+
+replace github.com/org/replaced => github.com/org/fork v1.0.1
@@ -0,0 +1,112 @@
+package gate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/open-policy-agent/opa/rego"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Result is the decision of the gate policy with the reasons
+type Result struct {
+	// Denials are the messages of "deny" failing the gate
+	Denials []string `json:",omitempty"`
+
+	// Warnings are the messages of "warn", which are reported without failing the gate
+	Warnings []string `json:",omitempty"`
+}
+
+// Passed returns whether the gate is passed, i.e. nothing is denied
+func (r Result) Passed() bool {
+	return len(r.Denials) == 0
+}
+
+// Evaluate evaluates "deny" and "warn" in the "trivy.gate" package of the policy file over the whole report,
+// which is given as input in the same structure as the JSON report.
+// Each rule is a set of human-readable messages, e.g. deny[msg] { ... msg := "..." }.
+func Evaluate(ctx context.Context, policyFile string, report types.Report) (Result, error) {
+	policy, err := os.ReadFile(policyFile)
+	if err != nil {
+		return Result{}, xerrors.Errorf("unable to read the gate policy file: %w", err)
+	}
+
+	query, err := rego.New(
+		rego.Query("data.trivy.gate"),
+		rego.Module("gate.rego", string(policy)),
+	).PrepareForEval(ctx)
+	if err != nil {
+		return Result{}, xerrors.Errorf("unable to prepare for eval: %w", err)
+	}
+
+	results, err := query.Eval(ctx, rego.EvalInput(report))
+	if err != nil {
+		return Result{}, xerrors.Errorf("unable to evaluate the gate policy: %w", err)
+	} else if len(results) == 0 {
+		return Result{}, xerrors.New("the gate policy must be in the \"trivy.gate\" package")
+	}
+
+	rules, ok := results[0].Expressions[0].Value.(map[string]interface{})
+	if !ok {
+		return Result{}, xerrors.New("the gate policy must be in the \"trivy.gate\" package")
+	}
+
+	var r Result
+	if r.Denials, err = messages(rules, "deny"); err != nil {
+		return Result{}, err
+	}
+	if r.Warnings, err = messages(rules, "warn"); err != nil {
+		return Result{}, err
+	}
+	return r, nil
+}
+
+// messages returns the sorted messages of the rule, which is undefined when nothing matches
+func messages(rules map[string]interface{}, name string) ([]string, error) {
+	values, ok := rules[name].([]interface{})
+	if !ok {
+		if _, defined := rules[name]; defined {
+			return nil, xerrors.Errorf("%q must be a set of messages", name)
+		}
+		return nil, nil
+	}
+
+	var msgs []string
+	for _, v := range values {
+		msg, ok := v.(string)
+		if !ok {
+			return nil, xerrors.Errorf("%q must be a set of strings, got %v", name, v)
+		}
+		msgs = append(msgs, msg)
+	}
+	sort.Strings(msgs)
+	return msgs, nil
+}
+
+// Write writes the decision and the reasons in a human-readable form
+func (r Result) Write(w io.Writer) error {
+	status := "PASSED"
+	if !r.Passed() {
+		status = "FAILED"
+	}
+
+	if _, err := fmt.Fprintf(w, "Gate: %s (denials: %d, warnings: %d)\n", status, len(r.Denials), len(r.Warnings)); err != nil {
+		return xerrors.Errorf("write error: %w", err)
+	}
+	for _, msg := range r.Denials {
+		if _, err := fmt.Fprintf(w, "  DENY: %s\n", msg); err != nil {
+			return xerrors.Errorf("write error: %w", err)
+		}
+	}
+	for _, msg := range r.Warnings {
+		if _, err := fmt.Fprintf(w, "  WARN: %s\n", msg); err != nil {
+			return xerrors.Errorf("write error: %w", err)
+		}
+	}
+	return nil
+}
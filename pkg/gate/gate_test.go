@@ -0,0 +1,131 @@
+package gate_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/gate"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestEvaluate(t *testing.T) {
+	report := types.Report{
+		ArtifactName: "registry.example.com/payments:1.0",
+		Metadata: types.Metadata{
+			Labels: map[string]string{"env": "prod"},
+		},
+		Results: types.Results{
+			{
+				Target: "app/package-lock.json",
+				Class:  types.ClassLangPkg,
+				Type:   "npm",
+				Vulnerabilities: []types.DetectedVulnerability{
+					{
+						VulnerabilityID:  "CVE-2021-44906",
+						PkgName:          "minimist",
+						InstalledVersion: "1.2.5",
+						FixedVersion:     "1.2.6",
+						Vulnerability:    dbTypes.Vulnerability{Severity: "CRITICAL"},
+					},
+					{
+						VulnerabilityID:  "CVE-2022-0235",
+						PkgName:          "node-fetch",
+						InstalledVersion: "2.6.6",
+						FixedVersion:     "2.6.7",
+						Vulnerability:    dbTypes.Vulnerability{Severity: "HIGH"},
+					},
+				},
+			},
+			{
+				Target: "app/.env",
+				Class:  types.ClassSecret,
+				Secrets: []types.DetectedSecret{
+					{SecretFinding: ftypes.SecretFinding{RuleID: "github-pat", Severity: "CRITICAL"}},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		policyFile string
+		report     types.Report
+		want       gate.Result
+		wantErr    string
+	}{
+		{
+			name:       "failed",
+			policyFile: "testdata/gate.rego",
+			report:     report,
+			want: gate.Result{
+				Denials: []string{
+					"app/.env: 1 secrets are not allowed in production",
+					"app/package-lock.json: CVE-2021-44906 in minimist is fixed in 1.2.6",
+				},
+				Warnings: []string{
+					"app/package-lock.json: CVE-2022-0235 in node-fetch",
+				},
+			},
+		},
+		{
+			name:       "passed",
+			policyFile: "testdata/gate.rego",
+			report: types.Report{
+				ArtifactName: "registry.example.com/payments:1.0",
+				Results:      types.Results{report.Results[1]},
+			},
+			want: gate.Result{},
+		},
+		{
+			name:       "invalid message",
+			policyFile: "testdata/invalid-message.rego",
+			report:     report,
+			wantErr:    `"deny" must be a set of strings`,
+		},
+		{
+			name:       "other package",
+			policyFile: "testdata/other-package.rego",
+			report:     report,
+			wantErr:    `the gate policy must be in the "trivy.gate" package`,
+		},
+		{
+			name:       "missing file",
+			policyFile: "testdata/missing.rego",
+			report:     report,
+			wantErr:    "unable to read the gate policy file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := gate.Evaluate(context.Background(), tt.policyFile, tt.report)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, len(tt.want.Denials) == 0, got.Passed())
+		})
+	}
+}
+
+func TestResult_Write(t *testing.T) {
+	r := gate.Result{
+		Denials:  []string{"app/package-lock.json: CVE-2021-44906 in minimist is fixed in 1.2.6"},
+		Warnings: []string{"app/package-lock.json: CVE-2022-0235 in node-fetch"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, r.Write(&buf))
+	want := `Gate: FAILED (denials: 1, warnings: 1)
+  DENY: app/package-lock.json: CVE-2021-44906 in minimist is fixed in 1.2.6
+  WARN: app/package-lock.json: CVE-2022-0235 in node-fetch
+`
+	assert.Equal(t, want, buf.String())
+}
@@ -0,0 +1,25 @@
+package trivy.gate
+
+# Critical vulnerabilities must be fixed when the fixes are available
+deny[msg] {
+	result := input.Results[_]
+	vuln := result.Vulnerabilities[_]
+	vuln.Severity == "CRITICAL"
+	vuln.FixedVersion != ""
+	msg := sprintf("%s: %s in %s is fixed in %s", [result.Target, vuln.VulnerabilityID, vuln.PkgName, vuln.FixedVersion])
+}
+
+# No secret is allowed in production
+deny[msg] {
+	input.Metadata.Labels.env == "prod"
+	result := input.Results[_]
+	count(result.Secrets) > 0
+	msg := sprintf("%s: %d secrets are not allowed in production", [result.Target, count(result.Secrets)])
+}
+
+warn[msg] {
+	result := input.Results[_]
+	vuln := result.Vulnerabilities[_]
+	vuln.Severity == "HIGH"
+	msg := sprintf("%s: %s in %s", [result.Target, vuln.VulnerabilityID, vuln.PkgName])
+}
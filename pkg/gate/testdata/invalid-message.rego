@@ -0,0 +1,7 @@
+package trivy.gate
+
+deny[msg] {
+	result := input.Results[_]
+	count(result.Vulnerabilities) > 0
+	msg := {"target": result.Target}
+}
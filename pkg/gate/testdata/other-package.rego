@@ -0,0 +1,5 @@
+package trivy
+
+deny[msg] {
+	msg := "denied"
+}
@@ -0,0 +1,317 @@
+package helm
+
+import (
+	"bytes"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+	"golang.org/x/xerrors"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli/values"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/releaseutil"
+
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+const (
+	chartFile = "Chart.yaml"
+
+	// releaseNamespace is the namespace charts are rendered in, as "helm template" does by default.
+	releaseNamespace = "default"
+)
+
+// Option holds the overrides applied when Helm charts are rendered
+type Option struct {
+	// ValueFiles are values files like "helm install --values"
+	ValueFiles []string
+
+	// Values are values like "helm install --set", e.g. "image.tag=1.0"
+	Values []string
+
+	// KubeVersion and APIVersions are the capabilities of the cluster charts are deployed to.
+	// The default of Helm is used if KubeVersion is empty.
+	KubeVersion string
+	APIVersions []string
+}
+
+// Enabled reports whether charts should be rendered by Trivy with the overrides
+func (o Option) Enabled() bool {
+	return len(o.ValueFiles) > 0 || len(o.Values) > 0 || o.KubeVersion != "" || len(o.APIVersions) > 0
+}
+
+// Renderer renders Helm charts in config files into Kubernetes manifests.
+type Renderer struct {
+	option      Option
+	values      map[string]interface{}
+	kubeVersion *chartutil.KubeVersion
+}
+
+// NewRenderer merges the values overrides and parses the Kubernetes version in advance
+// so that invalid options are reported before scanning.
+func NewRenderer(opt Option) (Renderer, error) {
+	valueOpts := values.Options{
+		ValueFiles: opt.ValueFiles,
+		Values:     opt.Values,
+	}
+	vals, err := valueOpts.MergeValues(getter.Providers{})
+	if err != nil {
+		return Renderer{}, xerrors.Errorf("unable to merge Helm values: %w", err)
+	}
+
+	var kubeVersion *chartutil.KubeVersion
+	if opt.KubeVersion != "" {
+		if kubeVersion, err = chartutil.ParseKubeVersion(opt.KubeVersion); err != nil {
+			return Renderer{}, xerrors.Errorf("invalid Kubernetes version (%s): %w", opt.KubeVersion, err)
+		}
+	}
+
+	return Renderer{
+		option:      opt,
+		values:      vals,
+		kubeVersion: kubeVersion,
+	}, nil
+}
+
+// Render replaces files of Helm charts with the manifests rendered from their templates.
+// Rendered manifests have the paths of the templates, e.g. "mychart/templates/deployment.yaml",
+// or "mychart-0.1.0.tgz:templates/deployment.yaml" for chart archives, so that findings are attributed to them.
+// The paths of the rendered manifests are returned as well.
+// Charts which cannot be rendered are left as they are.
+func (r Renderer) Render(files []types.File) ([]types.File, map[string]struct{}) {
+	roots := chartRoots(files)
+
+	chartFiles := map[string][]*loader.BufferedFile{}
+	seen := map[string]struct{}{}
+	var others []types.File
+	for _, file := range files {
+		filePath := filepath.ToSlash(file.Path)
+		root, ok := chartRoot(roots, filePath)
+		if !ok || file.Type != types.Helm {
+			others = append(others, file)
+			continue
+		}
+
+		// The same file can be passed by multiple config analyzers
+		if _, ok = seen[filePath]; ok {
+			continue
+		}
+		seen[filePath] = struct{}{}
+
+		name := strings.TrimPrefix(filePath, root+"/")
+		if root == "." {
+			name = filePath
+		}
+		chartFiles[root] = append(chartFiles[root], &loader.BufferedFile{
+			Name: name,
+			Data: file.Content,
+		})
+	}
+
+	// Templates are also passed by other config analyzers, but they should be evaluated only after rendering
+	others = lo.Filter(others, func(file types.File, _ int) bool {
+		_, ok := seen[filepath.ToSlash(file.Path)]
+		return !ok
+	})
+
+	rendered := map[string]struct{}{}
+	var results []types.File
+	for _, root := range roots {
+		manifests, err := r.renderFiles(chartFiles[root])
+		if err != nil {
+			log.Logger.Warnf("Unable to render the Helm chart (%s): %s", root, err)
+			others = append(others, unrendered(files, root, seen)...)
+			continue
+		}
+		for _, m := range manifests {
+			filePath := filepath.FromSlash(path.Join(root, m.path))
+			rendered[filePath] = struct{}{}
+			results = append(results, types.File{
+				Type:    types.Kubernetes,
+				Path:    filePath,
+				Content: m.content,
+			})
+		}
+	}
+
+	for _, file := range others {
+		if file.Type != types.Helm || !isArchive(file.Path) {
+			results = append(results, file)
+			continue
+		}
+
+		c, err := loader.LoadArchive(bytes.NewReader(file.Content))
+		if err != nil {
+			// Not a Helm chart
+			results = append(results, file)
+			continue
+		}
+		manifests, err := r.render(c)
+		if err != nil {
+			log.Logger.Warnf("Unable to render the Helm chart (%s): %s", file.Path, err)
+			results = append(results, file)
+			continue
+		}
+		for _, m := range manifests {
+			filePath := file.Path + ":" + filepath.FromSlash(m.path)
+			rendered[filePath] = struct{}{}
+			results = append(results, types.File{
+				Type:    types.Kubernetes,
+				Path:    filePath,
+				Content: m.content,
+			})
+		}
+	}
+
+	return results, rendered
+}
+
+// Relabel marks misconfigurations in the rendered manifests as those of Helm charts
+// as they are detected by Kubernetes checks.
+func Relabel(misconfs []types.Misconfiguration, rendered map[string]struct{}) {
+	for i, misconf := range misconfs {
+		if _, ok := rendered[misconf.FilePath]; !ok {
+			continue
+		}
+		misconfs[i].FileType = types.Helm
+		for _, results := range [][]types.MisconfResult{misconf.Successes, misconf.Warnings, misconf.Failures, misconf.Exceptions} {
+			for j := range results {
+				results[j].Type = strings.Replace(results[j].Type, "Kubernetes", "Helm", 1)
+			}
+		}
+	}
+}
+
+type manifest struct {
+	path    string
+	content []byte
+}
+
+func (r Renderer) renderFiles(files []*loader.BufferedFile) ([]manifest, error) {
+	c, err := loader.LoadFiles(files)
+	if err != nil {
+		return nil, xerrors.Errorf("chart load error: %w", err)
+	}
+	return r.render(c)
+}
+
+// render renders the chart as "helm template" does.
+// Documents rendered from the same template are joined, and the paths are relative to the chart root.
+func (r Renderer) render(c *chart.Chart) ([]manifest, error) {
+	client := action.NewInstall(&action.Configuration{})
+	client.DryRun = true     // don't do anything
+	client.Replace = true    // skip name check
+	client.ClientOnly = true // don't try to talk to a cluster
+	client.IncludeCRDs = true
+	client.ReleaseName = c.Name()
+	client.Namespace = releaseNamespace
+	client.KubeVersion = r.kubeVersion
+	client.APIVersions = r.option.APIVersions
+
+	rel, err := client.Run(c, r.values)
+	if err != nil {
+		return nil, xerrors.Errorf("render error: %w", err)
+	}
+
+	split := releaseutil.SplitManifests(rel.Manifest)
+	keys := make([]string, 0, len(split))
+	for k := range split {
+		keys = append(keys, k)
+	}
+	sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+
+	docs := map[string][]string{}
+	var paths []string
+	add := func(source, content string) {
+		// e.g. mychart/templates/deployment.yaml => templates/deployment.yaml
+		_, p, ok := strings.Cut(source, "/")
+		if !ok {
+			return
+		}
+		if _, ok = docs[p]; !ok {
+			paths = append(paths, p)
+		}
+		docs[p] = append(docs[p], strings.TrimSpace(content))
+	}
+
+	for _, k := range keys {
+		content := split[k]
+		line, _, _ := strings.Cut(content, "\n")
+		if !strings.HasPrefix(line, "# Source: ") {
+			continue
+		}
+		add(strings.TrimPrefix(line, "# Source: "), content)
+	}
+	for _, hook := range rel.Hooks {
+		add(hook.Path, hook.Manifest)
+	}
+
+	var manifests []manifest
+	for _, p := range paths {
+		manifests = append(manifests, manifest{
+			path:    p,
+			content: []byte(strings.Join(docs[p], "\n---\n") + "\n"),
+		})
+	}
+	return manifests, nil
+}
+
+// chartRoots returns the directories which have Chart.yaml.
+// Subcharts are rendered as part of the parent, so directories under another chart are excluded.
+func chartRoots(files []types.File) []string {
+	var dirs []string
+	for _, file := range files {
+		filePath := filepath.ToSlash(file.Path)
+		if path.Base(filePath) == chartFile {
+			dirs = append(dirs, path.Dir(filePath))
+		}
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		return len(dirs[i]) < len(dirs[j])
+	})
+
+	var roots []string
+	for _, dir := range dirs {
+		if _, ok := chartRoot(roots, dir+"/"+chartFile); ok {
+			continue
+		}
+		roots = append(roots, dir)
+	}
+	sort.Strings(roots)
+	return roots
+}
+
+func chartRoot(roots []string, filePath string) (string, bool) {
+	for _, root := range roots {
+		if root == "." || strings.HasPrefix(filePath, root+"/") {
+			return root, true
+		}
+	}
+	return "", false
+}
+
+// unrendered returns the files of the chart, which were held back for rendering
+func unrendered(files []types.File, root string, seen map[string]struct{}) []types.File {
+	return lo.Filter(files, func(file types.File, _ int) bool {
+		filePath := filepath.ToSlash(file.Path)
+		_, ok := seen[filePath]
+		_, inChart := chartRoot([]string{root}, filePath)
+		return ok && inChart
+	})
+}
+
+func isArchive(filePath string) bool {
+	for _, ext := range []string{".tar", ".tgz", ".tar.gz"} {
+		if strings.HasSuffix(strings.ToLower(filePath), ext) {
+			return true
+		}
+	}
+	return false
+}
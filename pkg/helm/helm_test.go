@@ -0,0 +1,255 @@
+package helm_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/helm"
+)
+
+func TestRenderer_Render(t *testing.T) {
+	dockerfile := types.File{
+		Type:    types.Dockerfile,
+		Path:    "Dockerfile",
+		Content: []byte("FROM alpine:3.16\n"),
+	}
+
+	tests := []struct {
+		name         string
+		option       helm.Option
+		dir          string
+		want         []types.File
+		wantRendered map[string]struct{}
+	}{
+		{
+			name: "values overrides",
+			option: helm.Option{
+				ValueFiles:  []string{"testdata/values.yaml"},
+				Values:      []string{"image.tag=1.23.0"},
+				KubeVersion: "1.20.0",
+			},
+			dir: "testdata/charts",
+			want: []types.File{
+				{
+					Type: types.Kubernetes,
+					Path: "testchart/templates/pdb.yaml",
+					Content: []byte(`# Source: testchart/templates/pdb.yaml
+apiVersion: policy/v1beta1
+kind: PodDisruptionBudget
+metadata:
+  name: testchart
+spec:
+  minAvailable: 1
+`),
+				},
+				{
+					Type: types.Kubernetes,
+					Path: "testchart/templates/deployment.yaml",
+					Content: []byte(`# Source: testchart/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: testchart
+spec:
+  template:
+    spec:
+      containers:
+        - name: testchart
+          image: "nginx:1.23.0"
+          securityContext:
+            runAsNonRoot: true
+`),
+				},
+				dockerfile,
+			},
+			wantRendered: map[string]struct{}{
+				"testchart/templates/deployment.yaml": {},
+				"testchart/templates/pdb.yaml":        {},
+			},
+		},
+		{
+			name: "kube version",
+			option: helm.Option{
+				KubeVersion: "1.24.0",
+			},
+			dir: "testdata/charts/testchart",
+			want: []types.File{
+				{
+					Type: types.Kubernetes,
+					Path: "templates/pdb.yaml",
+					Content: []byte(`# Source: testchart/templates/pdb.yaml
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: testchart
+spec:
+  minAvailable: 1
+`),
+				},
+				{
+					Type: types.Kubernetes,
+					Path: "templates/deployment.yaml",
+					Content: []byte(`# Source: testchart/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: testchart
+spec:
+  template:
+    spec:
+      containers:
+        - name: testchart
+          image: "nginx:1.16.0"
+          securityContext:
+            {}
+`),
+				},
+				dockerfile,
+			},
+			wantRendered: map[string]struct{}{
+				"templates/deployment.yaml": {},
+				"templates/pdb.yaml":        {},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := helm.NewRenderer(tt.option)
+			require.NoError(t, err)
+
+			files := append(loadFiles(t, tt.dir), dockerfile)
+			got, rendered := r.Render(files)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantRendered, rendered)
+		})
+	}
+}
+
+func TestNewRenderer(t *testing.T) {
+	tests := []struct {
+		name    string
+		option  helm.Option
+		wantErr string
+	}{
+		{
+			name: "invalid value",
+			option: helm.Option{
+				Values: []string{"image.tag"},
+			},
+			wantErr: "unable to merge Helm values",
+		},
+		{
+			name: "missing values file",
+			option: helm.Option{
+				ValueFiles: []string{"testdata/missing.yaml"},
+			},
+			wantErr: "unable to merge Helm values",
+		},
+		{
+			name: "invalid kube version",
+			option: helm.Option{
+				KubeVersion: "foo",
+			},
+			wantErr: "invalid Kubernetes version (foo)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := helm.NewRenderer(tt.option)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestRelabel(t *testing.T) {
+	misconfs := []types.Misconfiguration{
+		{
+			FileType: types.Kubernetes,
+			FilePath: "testchart/templates/deployment.yaml",
+			Failures: types.MisconfResults{
+				{
+					PolicyMetadata: types.PolicyMetadata{
+						ID:   "KSV001",
+						Type: "Kubernetes Security Check",
+					},
+				},
+			},
+		},
+		{
+			FileType: types.Kubernetes,
+			FilePath: "deployment.yaml",
+			Failures: types.MisconfResults{
+				{
+					PolicyMetadata: types.PolicyMetadata{
+						ID:   "KSV001",
+						Type: "Kubernetes Security Check",
+					},
+				},
+			},
+		},
+	}
+
+	helm.Relabel(misconfs, map[string]struct{}{
+		"testchart/templates/deployment.yaml": {},
+	})
+
+	want := []types.Misconfiguration{
+		{
+			FileType: types.Helm,
+			FilePath: "testchart/templates/deployment.yaml",
+			Failures: types.MisconfResults{
+				{
+					PolicyMetadata: types.PolicyMetadata{
+						ID:   "KSV001",
+						Type: "Helm Security Check",
+					},
+				},
+			},
+		},
+		{
+			FileType: types.Kubernetes,
+			FilePath: "deployment.yaml",
+			Failures: types.MisconfResults{
+				{
+					PolicyMetadata: types.PolicyMetadata{
+						ID:   "KSV001",
+						Type: "Kubernetes Security Check",
+					},
+				},
+			},
+		},
+	}
+	assert.Equal(t, want, misconfs)
+}
+
+// loadFiles returns files under the directory as the Helm config analyzer passes them
+func loadFiles(t *testing.T, dir string) []types.File {
+	var files []types.File
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, types.File{
+			Type:    types.Helm,
+			Path:    rel,
+			Content: b,
+		})
+		return nil
+	})
+	require.NoError(t, err)
+	return files
+}
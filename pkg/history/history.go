@@ -0,0 +1,377 @@
+package history
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"golang.org/x/xerrors"
+	_ "modernc.org/sqlite" // sqlite driver
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/clock"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Classes of findings
+const (
+	ClassVulnerability    = "vulnerability"
+	ClassMisconfiguration = "misconfiguration"
+	ClassSecret           = "secret"
+	ClassLicense          = "license"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS scans (
+	id            INTEGER PRIMARY KEY AUTOINCREMENT,
+	artifact_name TEXT NOT NULL,
+	artifact_type TEXT NOT NULL,
+	digest        TEXT NOT NULL,
+	scanned_at    TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS scans_artifact_name ON scans (artifact_name, scanned_at);
+CREATE TABLE IF NOT EXISTS findings (
+	scan_id  INTEGER NOT NULL REFERENCES scans (id) ON DELETE CASCADE,
+	class    TEXT NOT NULL,
+	target   TEXT NOT NULL,
+	id       TEXT NOT NULL,
+	resource TEXT NOT NULL,
+	severity TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS findings_scan_id ON findings (scan_id);
+`
+
+// Path returns the path of the history store in the cache directory
+func Path(cacheDir string) string {
+	return filepath.Join(cacheDir, "history", "history.db")
+}
+
+// Finding is a finding recorded in the history
+type Finding struct {
+	Class  string
+	Target string
+
+	// ID is the vulnerability ID, the misconfiguration ID, the secret rule ID or the license name
+	ID string
+
+	// Resource is the package name of vulnerabilities and licenses, or the file path of licenses in files
+	Resource string
+
+	Severity string
+}
+
+func (f Finding) key() Finding {
+	// Severities may be updated in the DB between scans
+	f.Severity = ""
+	return f
+}
+
+// Summary holds the number of findings by severity
+type Summary struct {
+	Critical int
+	High     int
+	Medium   int
+	Low      int
+	Unknown  int
+}
+
+// Total returns the number of all the findings
+func (s Summary) Total() int {
+	return s.Critical + s.High + s.Medium + s.Low + s.Unknown
+}
+
+func (s *Summary) add(severity string, n int) {
+	switch severity {
+	case dbTypes.SeverityCritical.String():
+		s.Critical += n
+	case dbTypes.SeverityHigh.String():
+		s.High += n
+	case dbTypes.SeverityMedium.String():
+		s.Medium += n
+	case dbTypes.SeverityLow.String():
+		s.Low += n
+	default:
+		s.Unknown += n
+	}
+}
+
+// Scan is a scan recorded in the history
+type Scan struct {
+	ID           int64
+	ArtifactName string
+	ArtifactType string
+
+	// Digest is the repository digest or the image ID of images, and empty for other artifacts
+	Digest    string
+	ScannedAt time.Time
+	Summary   Summary
+}
+
+// Store is the local history of scans backed by SQLite
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens the history store, which is created if not exists
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, xerrors.Errorf("mkdir error: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, xerrors.Errorf("unable to open %s: %w", path, err)
+	}
+	if _, err = db.Exec(schema); err != nil {
+		_ = db.Close()
+		return nil, xerrors.Errorf("unable to create the history schema: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the store
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Record records the summary and findings of the report
+func (s *Store) Record(report types.Report) (Scan, error) {
+	findings := Findings(report.Results)
+	scan := Scan{
+		ArtifactName: report.ArtifactName,
+		ArtifactType: string(report.ArtifactType),
+		Digest:       digest(report.Metadata),
+		ScannedAt:    clock.Now().UTC(),
+		Summary:      summarize(findings),
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return Scan{}, xerrors.Errorf("transaction error: %w", err)
+	}
+	defer tx.Rollback() // nolint: errcheck
+
+	res, err := tx.Exec(`INSERT INTO scans (artifact_name, artifact_type, digest, scanned_at) VALUES (?, ?, ?, ?)`,
+		scan.ArtifactName, scan.ArtifactType, scan.Digest, scan.ScannedAt.Format(time.RFC3339Nano))
+	if err != nil {
+		return Scan{}, xerrors.Errorf("unable to insert the scan: %w", err)
+	}
+	if scan.ID, err = res.LastInsertId(); err != nil {
+		return Scan{}, xerrors.Errorf("unable to get the scan ID: %w", err)
+	}
+
+	stmt, err := tx.Prepare(`INSERT INTO findings (scan_id, class, target, id, resource, severity) VALUES (?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return Scan{}, xerrors.Errorf("prepare error: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, f := range findings {
+		if _, err = stmt.Exec(scan.ID, f.Class, f.Target, f.ID, f.Resource, f.Severity); err != nil {
+			return Scan{}, xerrors.Errorf("unable to insert the finding: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return Scan{}, xerrors.Errorf("commit error: %w", err)
+	}
+	return scan, nil
+}
+
+// Artifacts returns the latest scan of each artifact sorted by the artifact name
+func (s *Store) Artifacts() ([]Scan, error) {
+	return s.scans(`SELECT id, artifact_name, artifact_type, digest, scanned_at FROM scans
+WHERE id IN (SELECT MAX(id) FROM scans GROUP BY artifact_name) ORDER BY artifact_name`)
+}
+
+// Scans returns the latest scans of the artifact, newest first. All the scans are returned if limit is not positive.
+func (s *Store) Scans(artifactName string, limit int) ([]Scan, error) {
+	if limit <= 0 {
+		limit = -1
+	}
+	return s.scans(`SELECT id, artifact_name, artifact_type, digest, scanned_at FROM scans
+WHERE artifact_name = ? ORDER BY id DESC LIMIT ?`, artifactName, limit)
+}
+
+func (s *Store) scans(query string, args ...any) ([]Scan, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, xerrors.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	var scans []Scan
+	for rows.Next() {
+		var scan Scan
+		var scannedAt string
+		if err = rows.Scan(&scan.ID, &scan.ArtifactName, &scan.ArtifactType, &scan.Digest, &scannedAt); err != nil {
+			return nil, xerrors.Errorf("scan error: %w", err)
+		}
+		if scan.ScannedAt, err = time.Parse(time.RFC3339Nano, scannedAt); err != nil {
+			return nil, xerrors.Errorf("time parse error: %w", err)
+		}
+		scans = append(scans, scan)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, xerrors.Errorf("rows error: %w", err)
+	}
+
+	for i := range scans {
+		if scans[i].Summary, err = s.summary(scans[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return scans, nil
+}
+
+func (s *Store) summary(scanID int64) (Summary, error) {
+	rows, err := s.db.Query(`SELECT severity, COUNT(*) FROM findings WHERE scan_id = ? GROUP BY severity`, scanID)
+	if err != nil {
+		return Summary{}, xerrors.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	var summary Summary
+	for rows.Next() {
+		var severity string
+		var n int
+		if err = rows.Scan(&severity, &n); err != nil {
+			return Summary{}, xerrors.Errorf("scan error: %w", err)
+		}
+		summary.add(severity, n)
+	}
+	if err = rows.Err(); err != nil {
+		return Summary{}, xerrors.Errorf("rows error: %w", err)
+	}
+	return summary, nil
+}
+
+// Findings returns the findings of the scan
+func (s *Store) Findings(scanID int64) ([]Finding, error) {
+	rows, err := s.db.Query(`SELECT class, target, id, resource, severity FROM findings WHERE scan_id = ?`, scanID)
+	if err != nil {
+		return nil, xerrors.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	var findings []Finding
+	for rows.Next() {
+		var f Finding
+		if err = rows.Scan(&f.Class, &f.Target, &f.ID, &f.Resource, &f.Severity); err != nil {
+			return nil, xerrors.Errorf("scan error: %w", err)
+		}
+		findings = append(findings, f)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, xerrors.Errorf("rows error: %w", err)
+	}
+	return findings, nil
+}
+
+// Findings returns the findings in the results.
+// Only failed misconfigurations are findings.
+func Findings(results types.Results) []Finding {
+	var findings []Finding
+	for _, result := range results {
+		for _, v := range result.Vulnerabilities {
+			findings = append(findings, Finding{
+				Class:    ClassVulnerability,
+				Target:   result.Target,
+				ID:       v.VulnerabilityID,
+				Resource: v.PkgName,
+				Severity: v.Severity,
+			})
+		}
+		for _, m := range result.Misconfigurations {
+			if m.Status != types.StatusFailure {
+				continue
+			}
+			findings = append(findings, Finding{
+				Class:    ClassMisconfiguration,
+				Target:   result.Target,
+				ID:       m.ID,
+				Severity: m.Severity,
+			})
+		}
+		for _, s := range result.Secrets {
+			findings = append(findings, Finding{
+				Class:    ClassSecret,
+				Target:   result.Target,
+				ID:       s.RuleID,
+				Severity: s.Severity,
+			})
+		}
+		for _, l := range result.Licenses {
+			resource := l.PkgName
+			if resource == "" {
+				resource = l.FilePath
+			}
+			findings = append(findings, Finding{
+				Class:    ClassLicense,
+				Target:   result.Target,
+				ID:       l.Name,
+				Resource: resource,
+				Severity: l.Severity,
+			})
+		}
+	}
+	return findings
+}
+
+// Diff returns the findings added in "to" and resolved since "from", sorted by the severity and the ID.
+// Findings are compared regardless of the severity.
+func Diff(from, to []Finding) (added, resolved []Finding) {
+	added = subtract(to, from)
+	resolved = subtract(from, to)
+	return added, resolved
+}
+
+func subtract(a, b []Finding) []Finding {
+	keys := map[Finding]struct{}{}
+	for _, f := range b {
+		keys[f.key()] = struct{}{}
+	}
+
+	var diff []Finding
+	seen := map[Finding]struct{}{}
+	for _, f := range a {
+		if _, ok := keys[f.key()]; ok {
+			continue
+		} else if _, ok = seen[f.key()]; ok {
+			continue
+		}
+		seen[f.key()] = struct{}{}
+		diff = append(diff, f)
+	}
+
+	sort.Slice(diff, func(i, j int) bool {
+		si, _ := dbTypes.NewSeverity(diff[i].Severity) // nolint: errcheck
+		sj, _ := dbTypes.NewSeverity(diff[j].Severity) // nolint: errcheck
+		if si != sj {
+			return si > sj
+		}
+		if diff[i].ID != diff[j].ID {
+			return diff[i].ID < diff[j].ID
+		}
+		return diff[i].Target+diff[i].Resource < diff[j].Target+diff[j].Resource
+	})
+	return diff
+}
+
+func summarize(findings []Finding) Summary {
+	var summary Summary
+	for _, f := range findings {
+		summary.add(f.Severity, 1)
+	}
+	return summary
+}
+
+// digest returns the repository digest or the image ID of images
+func digest(metadata types.Metadata) string {
+	if len(metadata.RepoDigests) > 0 {
+		return metadata.RepoDigests[0]
+	}
+	return metadata.ImageID
+}
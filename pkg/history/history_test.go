@@ -0,0 +1,123 @@
+package history_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/clock"
+	"github.com/aquasecurity/trivy/pkg/history"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func dbVuln(severity string) dbTypes.Vulnerability {
+	return dbTypes.Vulnerability{Severity: severity}
+}
+
+func report(digest string, vulns []types.DetectedVulnerability, misconfs []types.DetectedMisconfiguration) types.Report {
+	return types.Report{
+		ArtifactName: "alpine:3.16",
+		ArtifactType: ftypes.ArtifactContainerImage,
+		Metadata: types.Metadata{
+			RepoDigests: []string{digest},
+		},
+		Results: types.Results{
+			{
+				Target:          "alpine:3.16 (alpine 3.16.0)",
+				Vulnerabilities: vulns,
+			},
+			{
+				Target:            "Dockerfile",
+				Misconfigurations: misconfs,
+			},
+		},
+	}
+}
+
+func TestStore(t *testing.T) {
+	store, err := history.Open(filepath.Join(t.TempDir(), "history", "history.db"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	// First scan
+	clock.SetFakeTime(t, time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC))
+	_, err = store.Record(report("alpine@sha256:aaa", []types.DetectedVulnerability{
+		{VulnerabilityID: "CVE-2022-0001", PkgName: "openssl", Vulnerability: dbVuln("CRITICAL")},
+		{VulnerabilityID: "CVE-2022-0002", PkgName: "zlib", Vulnerability: dbVuln("LOW")},
+	}, []types.DetectedMisconfiguration{
+		{ID: "DS002", Severity: "HIGH", Status: types.StatusFailure},
+		{ID: "DS001", Severity: "MEDIUM", Status: types.StatusPassed},
+	}))
+	require.NoError(t, err)
+
+	// Second scan
+	clock.SetFakeTime(t, time.Date(2022, 7, 2, 0, 0, 0, 0, time.UTC))
+	second, err := store.Record(report("alpine@sha256:bbb", []types.DetectedVulnerability{
+		{VulnerabilityID: "CVE-2022-0002", PkgName: "zlib", Vulnerability: dbVuln("MEDIUM")},
+		{VulnerabilityID: "CVE-2022-0003", PkgName: "busybox", Vulnerability: dbVuln("HIGH")},
+	}, nil))
+	require.NoError(t, err)
+
+	scans, err := store.Scans("alpine:3.16", 0)
+	require.NoError(t, err)
+	want := []history.Scan{
+		{
+			ID:           second.ID,
+			ArtifactName: "alpine:3.16",
+			ArtifactType: "container_image",
+			Digest:       "alpine@sha256:bbb",
+			ScannedAt:    time.Date(2022, 7, 2, 0, 0, 0, 0, time.UTC),
+			Summary:      history.Summary{High: 1, Medium: 1},
+		},
+		{
+			ID:           second.ID - 1,
+			ArtifactName: "alpine:3.16",
+			ArtifactType: "container_image",
+			Digest:       "alpine@sha256:aaa",
+			ScannedAt:    time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC),
+			Summary:      history.Summary{Critical: 1, High: 1, Low: 1},
+		},
+	}
+	assert.Equal(t, want, scans)
+	assert.Equal(t, 2, scans[0].Summary.Total())
+
+	artifacts, err := store.Artifacts()
+	require.NoError(t, err)
+	assert.Equal(t, want[:1], artifacts)
+
+	from, err := store.Findings(scans[1].ID)
+	require.NoError(t, err)
+	to, err := store.Findings(scans[0].ID)
+	require.NoError(t, err)
+
+	added, resolved := history.Diff(from, to)
+	assert.Equal(t, []history.Finding{
+		{
+			Class:    history.ClassVulnerability,
+			Target:   "alpine:3.16 (alpine 3.16.0)",
+			ID:       "CVE-2022-0003",
+			Resource: "busybox",
+			Severity: "HIGH",
+		},
+	}, added)
+	assert.Equal(t, []history.Finding{
+		{
+			Class:    history.ClassVulnerability,
+			Target:   "alpine:3.16 (alpine 3.16.0)",
+			ID:       "CVE-2022-0001",
+			Resource: "openssl",
+			Severity: "CRITICAL",
+		},
+		{
+			Class:    history.ClassMisconfiguration,
+			Target:   "Dockerfile",
+			ID:       "DS002",
+			Severity: "HIGH",
+		},
+	}, resolved)
+}
@@ -0,0 +1,101 @@
+// Package httpclient provides the transport shared by every outbound HTTP request of Trivy,
+// e.g. DB downloads, registries, policy bundles, Maven Central and the OSV API,
+// so that the proxy settings and --ca-bundle apply to all of them.
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+var (
+	// baseTransport is http.DefaultTransport before SetCABundle replaces it
+	baseTransport = http.DefaultTransport.(*http.Transport).Clone()
+
+	mu sync.RWMutex
+
+	// bundle holds the certificates of the CA bundle, or nil for the system certificates only
+	bundle  []byte
+	rootCAs *x509.CertPool
+)
+
+// SetCABundle trusts the certificates of the PEM file in addition to the system ones in every outbound HTTP request,
+// i.e. the transports of this package and http.DefaultTransport used by third-party libraries such as go-git.
+// An empty path trusts only the system certificates.
+func SetCABundle(path string) error {
+	var (
+		pem  []byte
+		pool *x509.CertPool
+	)
+	if path != "" {
+		var err error
+		if pem, err = os.ReadFile(path); err != nil {
+			return xerrors.Errorf("CA bundle error: %w", err)
+		}
+		if pool, err = certPool(pem); err != nil {
+			return xerrors.Errorf("%s: %w", path, err)
+		}
+	}
+
+	mu.Lock()
+	bundle, rootCAs = pem, pool
+	mu.Unlock()
+
+	http.DefaultTransport = Transport(false)
+	return nil
+}
+
+// Transport returns a new transport honoring HTTP_PROXY, HTTPS_PROXY and NO_PROXY and trusting the CA bundle.
+// insecure skips TLS verification, e.g. --insecure.
+func Transport(insecure bool) *http.Transport {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	t := baseTransport.Clone()
+	t.Proxy = http.ProxyFromEnvironment
+	t.TLSClientConfig = &tls.Config{
+		RootCAs:            rootCAs,
+		InsecureSkipVerify: insecure,
+	}
+	return t
+}
+
+// RootCAs returns a new pool of the system certificates, the CA bundle and the given PEM certificates.
+// It is used by transports with their own TLS settings, e.g. registries with a custom CA.
+func RootCAs(pem []byte) (*x509.CertPool, error) {
+	pool, err := certPool(pem)
+	if err != nil {
+		return nil, err
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	pool.AppendCertsFromPEM(bundle)
+	return pool, nil
+}
+
+func certPool(pem []byte) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, xerrors.New("no certificates found")
+	}
+	return pool, nil
+}
+
+// Client returns a new client with Transport and the timeout
+func Client(timeout time.Duration, insecure bool) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: Transport(insecure),
+	}
+}
@@ -0,0 +1,114 @@
+package httpclient_test
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/httpclient"
+)
+
+func TestSetCABundle(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caFile, caPEM, 0600))
+
+	invalidFile := filepath.Join(t.TempDir(), "invalid.pem")
+	require.NoError(t, os.WriteFile(invalidFile, []byte("invalid"), 0600))
+
+	tests := []struct {
+		name     string
+		caBundle string
+		insecure bool
+		wantErr  string
+		wantTLS  bool
+	}{
+		{
+			name:     "CA bundle",
+			caBundle: caFile,
+			wantTLS:  true,
+		},
+		{
+			name:    "system certificates",
+			wantTLS: false,
+		},
+		{
+			name:     "insecure",
+			insecure: true,
+			wantTLS:  true,
+		},
+		{
+			name:     "no certificates",
+			caBundle: invalidFile,
+			wantErr:  "no certificates found",
+		},
+		{
+			name:     "missing file",
+			caBundle: filepath.Join(t.TempDir(), "missing.pem"),
+			wantErr:  "CA bundle error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer httpclient.SetCABundle("")
+
+			err := httpclient.SetCABundle(tt.caBundle)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			for _, client := range []*http.Client{
+				httpclient.Client(time.Second, tt.insecure),
+				{Transport: http.DefaultTransport, Timeout: time.Second},
+			} {
+				if tt.insecure && client.Transport == http.DefaultTransport {
+					continue
+				}
+				resp, err := client.Get(ts.URL)
+				if !tt.wantTLS {
+					assert.Error(t, err)
+					continue
+				}
+				require.NoError(t, err)
+				resp.Body.Close()
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestRootCAs(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+
+	pool, err := httpclient.RootCAs(caPEM)
+	require.NoError(t, err)
+
+	transport := httpclient.Transport(false)
+	transport.TLSClientConfig.RootCAs = pool
+	resp, err := (&http.Client{Transport: transport}).Get(ts.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	_, err = httpclient.RootCAs([]byte("invalid"))
+	assert.ErrorContains(t, err, "no certificates found")
+}
@@ -0,0 +1,279 @@
+package ignore
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+	"time"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/clock"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// RuleType is the type of custom resources holding inline ignore rules.
+// Files are not available when results are built from the cache, so rules are passed as custom resources.
+const RuleType = "trivy-inline-ignore"
+
+const (
+	marker     = "trivy:ignore"
+	expiryKey  = "exp"
+	dateLayout = "2006-01-02"
+)
+
+var nosecRegexp = regexp.MustCompile(`\bnosec\b`)
+
+// commentPrefixes are the beginnings of comments which may precede the marker
+var commentPrefixes = []string{"#", "//", "/*", "<!--", "--", ";"}
+
+// Rule represents an inline ignore comment, e.g. "#trivy:ignore:AVD-AWS-0086:exp:2026-12-31 accepted by the security team"
+type Rule struct {
+	// ID is empty when all findings are ignored
+	ID string `json:",omitempty"`
+
+	// Lines the rule applies to
+	StartLine int
+	EndLine   int
+
+	Reason    string `json:",omitempty"`
+	ExpiresAt string `json:",omitempty"`
+
+	// NoSec is set to "nosec" comments, which apply only to secrets
+	NoSec bool `json:",omitempty"`
+}
+
+// Parse returns the inline ignore rules in the content.
+// A comment on its own line applies to the next line, and a comment following code applies to that line.
+// When the line opens a block, e.g. a Terraform resource, the rule applies to the whole block,
+// and continued lines, e.g. RUN instructions in Dockerfiles, are included.
+// "nosec" comments are also parsed if nosec is true.
+func Parse(content []byte, nosec bool) []Rule {
+	if !bytes.Contains(content, []byte(marker)) && (!nosec || !nosecRegexp.Match(content)) {
+		return nil
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(content)+1)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	var rules []Rule
+	for i, line := range lines {
+		rule, ok := parseComment(line, nosec)
+		if !ok {
+			continue
+		}
+
+		target := i
+		if isComment(line) {
+			if target = nextCode(lines, i+1); target < 0 {
+				continue
+			}
+		}
+		rule.StartLine = target + 1
+		rule.EndLine = lastLine(lines, target) + 1
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+func parseComment(line string, nosec bool) (Rule, bool) {
+	idx := strings.Index(line, marker)
+	if idx < 0 {
+		if loc := nosecRegexp.FindStringIndex(line); nosec && loc != nil {
+			return Rule{
+				Reason: trimComment(line[loc[1]:]),
+				NoSec:  true,
+			}, true
+		}
+		return Rule{}, false
+	}
+
+	var rule Rule
+	rest := line[idx+len(marker):]
+	for strings.HasPrefix(rest, ":") {
+		token, remaining := nextToken(rest[1:])
+		if token == expiryKey {
+			rule.ExpiresAt, remaining = nextToken(strings.TrimPrefix(remaining, ":"))
+		} else if rule.ID == "" {
+			rule.ID = token
+		}
+		rest = remaining
+	}
+	rule.Reason = trimComment(rest)
+	return rule, true
+}
+
+// nextToken returns the string until the next colon or whitespace
+func nextToken(s string) (string, string) {
+	end := strings.IndexFunc(s, func(r rune) bool {
+		return r == ':' || r == ' ' || r == '\t'
+	})
+	if end < 0 {
+		return s, ""
+	}
+	return s[:end], s[end:]
+}
+
+func trimComment(s string) string {
+	s = strings.TrimSpace(s)
+	s = strings.TrimSuffix(s, "-->")
+	s = strings.TrimSuffix(s, "*/")
+	return strings.TrimSpace(s)
+}
+
+// isComment returns whether the line has only the comment
+func isComment(line string) bool {
+	line = strings.TrimSpace(line)
+	for _, prefix := range commentPrefixes {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextCode returns the index of the next line which is not a comment or blank
+func nextCode(lines []string, start int) int {
+	for i := start; i < len(lines); i++ {
+		if line := strings.TrimSpace(lines[i]); line != "" && !isComment(line) {
+			return i
+		}
+	}
+	return -1
+}
+
+// lastLine returns the index of the last line of the block or the continued line starting at the line
+func lastLine(lines []string, start int) int {
+	line := strings.TrimSpace(lines[start])
+	switch {
+	case strings.HasSuffix(line, "{"), strings.HasSuffix(line, "["):
+		depth := 0
+		for i := start; i < len(lines); i++ {
+			depth += strings.Count(lines[i], "{") + strings.Count(lines[i], "[")
+			depth -= strings.Count(lines[i], "}") + strings.Count(lines[i], "]")
+			if depth <= 0 {
+				return i
+			}
+		}
+		return len(lines) - 1
+	case strings.HasSuffix(line, `\`):
+		i := start
+		for i < len(lines)-1 && strings.HasSuffix(strings.TrimSpace(lines[i]), `\`) {
+			i++
+		}
+		return i
+	}
+	return start
+}
+
+// Resources returns custom resources holding the inline ignore rules in the config files
+func Resources(files []ftypes.File) []ftypes.CustomResource {
+	var resources []ftypes.CustomResource
+	for _, f := range files {
+		if rules := Parse(f.Content, false); len(rules) > 0 {
+			resources = append(resources, ftypes.CustomResource{
+				Type:     RuleType,
+				FilePath: f.Path,
+				Data:     rules,
+			})
+		}
+	}
+	return resources
+}
+
+// Apply removes the misconfigurations and secrets ignored by the inline rules,
+// and records them as suppressions of the results for auditing.
+// Expired rules are not applied.
+func Apply(results types.Results, rules map[string][]Rule) {
+	if len(rules) == 0 {
+		return
+	}
+
+	now := clock.Now()
+	for i := range results {
+		r := &results[i]
+		fileRules := activeRules(r.Target, rules[r.Target], now)
+		if len(fileRules) == 0 {
+			continue
+		}
+
+		var misconfs []types.DetectedMisconfiguration
+		for _, m := range r.Misconfigurations {
+			if m.Status != types.StatusFailure {
+				misconfs = append(misconfs, m)
+				continue
+			}
+			rule, ok := match(fileRules, m.ID, m.CauseMetadata.StartLine, false)
+			if !ok {
+				misconfs = append(misconfs, m)
+				continue
+			}
+			r.Suppressions = append(r.Suppressions, suppression(types.SuppressionMisconfiguration, m.ID, m.CauseMetadata.StartLine, rule))
+		}
+		r.Misconfigurations = misconfs
+
+		var secrets []types.DetectedSecret
+		for _, s := range r.Secrets {
+			rule, ok := match(fileRules, s.RuleID, s.StartLine, true)
+			if !ok {
+				secrets = append(secrets, s)
+				continue
+			}
+			r.Suppressions = append(r.Suppressions, suppression(types.SuppressionSecret, s.RuleID, s.StartLine, rule))
+		}
+		r.Secrets = secrets
+	}
+}
+
+func activeRules(filePath string, rules []Rule, now time.Time) []Rule {
+	var active []Rule
+	for _, rule := range rules {
+		if rule.ExpiresAt != "" {
+			expiry, err := time.Parse(dateLayout, rule.ExpiresAt)
+			if err != nil {
+				log.Logger.Warnf("Invalid expiry of the inline ignore in %s:%d: %s", filePath, rule.StartLine, rule.ExpiresAt)
+				continue
+			}
+			// The rule is valid through the expiry date
+			if now.After(expiry.AddDate(0, 0, 1)) {
+				log.Logger.Warnf("The inline ignore in %s:%d expired on %s", filePath, rule.StartLine, rule.ExpiresAt)
+				continue
+			}
+		}
+		active = append(active, rule)
+	}
+	return active
+}
+
+func match(rules []Rule, id string, line int, secret bool) (Rule, bool) {
+	if line == 0 {
+		return Rule{}, false
+	}
+	for _, rule := range rules {
+		if rule.NoSec && !secret {
+			continue
+		}
+		if rule.ID != "" && !strings.EqualFold(rule.ID, id) {
+			continue
+		}
+		if rule.StartLine <= line && line <= rule.EndLine {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+func suppression(findingType types.SuppressionType, id string, line int, rule Rule) types.Suppression {
+	return types.Suppression{
+		Type:      findingType,
+		ID:        id,
+		StartLine: line,
+		Reason:    rule.Reason,
+		ExpiresAt: rule.ExpiresAt,
+	}
+}
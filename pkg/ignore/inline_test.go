@@ -0,0 +1,239 @@
+package ignore_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/clock"
+	"github.com/aquasecurity/trivy/pkg/ignore"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		nosec   bool
+		want    []ignore.Rule
+	}{
+		{
+			name: "terraform block",
+			content: `#trivy:ignore:AVD-AWS-0092:exp:2026-12-31 public website bucket
+resource "aws_s3_bucket" "site" {
+  bucket = "site"
+  acl    = "public-read"
+}
+`,
+			want: []ignore.Rule{
+				{
+					ID:        "AVD-AWS-0092",
+					StartLine: 2,
+					EndLine:   5,
+					Reason:    "public website bucket",
+					ExpiresAt: "2026-12-31",
+				},
+			},
+		},
+		{
+			name: "trailing comment",
+			content: `resource "aws_s3_bucket" "site" {
+  acl = "public-read" // trivy:ignore:AVD-AWS-0092
+}
+`,
+			want: []ignore.Rule{
+				{
+					ID:        "AVD-AWS-0092",
+					StartLine: 2,
+					EndLine:   2,
+				},
+			},
+		},
+		{
+			name: "dockerfile",
+			content: `FROM alpine:3.16
+# trivy:ignore the image runs as root by design
+# another comment
+
+RUN apk add curl && \
+    rm -rf /var/cache/apk
+USER root
+`,
+			want: []ignore.Rule{
+				{
+					StartLine: 5,
+					EndLine:   6,
+					Reason:    "the image runs as root by design",
+				},
+			},
+		},
+		{
+			name: "expiry without ID",
+			content: `# trivy:ignore:exp:2026-12-31
+USER root
+`,
+			want: []ignore.Rule{
+				{
+					StartLine: 2,
+					EndLine:   2,
+					ExpiresAt: "2026-12-31",
+				},
+			},
+		},
+		{
+			name: "nosec",
+			content: `password = "secret" # nosec test fixture
+`,
+			nosec: true,
+			want: []ignore.Rule{
+				{
+					StartLine: 1,
+					EndLine:   1,
+					Reason:    "test fixture",
+					NoSec:     true,
+				},
+			},
+		},
+		{
+			name: "nosec disabled",
+			content: `password = "secret" # nosec
+`,
+		},
+		{
+			name: "no following line",
+			content: `USER root
+# trivy:ignore
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ignore.Parse([]byte(tt.content), tt.nosec)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	clock.SetFakeTime(t, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC))
+
+	rules := map[string][]ignore.Rule{
+		"main.tf": {
+			{
+				ID:        "AVD-AWS-0092",
+				StartLine: 2,
+				EndLine:   5,
+				Reason:    "public website bucket",
+				ExpiresAt: "2026-12-31",
+			},
+			{
+				ID:        "avd-aws-0086",
+				StartLine: 8,
+				EndLine:   10,
+				ExpiresAt: "2026-01-01",
+			},
+			{
+				StartLine: 12,
+				EndLine:   12,
+				NoSec:     true,
+			},
+		},
+		"creds.env": {
+			{
+				StartLine: 1,
+				EndLine:   1,
+				Reason:    "test fixture",
+				NoSec:     true,
+			},
+		},
+	}
+
+	misconf := func(id string, line int, status types.MisconfStatus) types.DetectedMisconfiguration {
+		return types.DetectedMisconfiguration{
+			ID:     id,
+			Status: status,
+			CauseMetadata: ftypes.CauseMetadata{
+				StartLine: line,
+			},
+		}
+	}
+	secret := func(ruleID string, line int) types.DetectedSecret {
+		return types.DetectedSecret{
+			SecretFinding: ftypes.SecretFinding{
+				RuleID:    ruleID,
+				StartLine: line,
+			},
+		}
+	}
+
+	results := types.Results{
+		{
+			Target: "main.tf",
+			Misconfigurations: []types.DetectedMisconfiguration{
+				misconf("AVD-AWS-0092", 4, types.StatusFailure),
+				misconf("AVD-AWS-0092", 4, types.StatusPassed),
+				misconf("AVD-AWS-0093", 4, types.StatusFailure),
+				misconf("AVD-AWS-0086", 9, types.StatusFailure),  // expired
+				misconf("AVD-AWS-0094", 12, types.StatusFailure), // nosec
+			},
+		},
+		{
+			Target: "creds.env",
+			Secrets: []types.DetectedSecret{
+				secret("aws-access-key-id", 1),
+				secret("aws-access-key-id", 2),
+			},
+		},
+		{
+			Target: "Dockerfile",
+			Misconfigurations: []types.DetectedMisconfiguration{
+				misconf("DS002", 3, types.StatusFailure),
+			},
+		},
+	}
+	ignore.Apply(results, rules)
+
+	want := types.Results{
+		{
+			Target: "main.tf",
+			Misconfigurations: []types.DetectedMisconfiguration{
+				misconf("AVD-AWS-0092", 4, types.StatusPassed),
+				misconf("AVD-AWS-0093", 4, types.StatusFailure),
+				misconf("AVD-AWS-0086", 9, types.StatusFailure),
+				misconf("AVD-AWS-0094", 12, types.StatusFailure),
+			},
+			Suppressions: []types.Suppression{
+				{
+					Type:      types.SuppressionMisconfiguration,
+					ID:        "AVD-AWS-0092",
+					StartLine: 4,
+					Reason:    "public website bucket",
+					ExpiresAt: "2026-12-31",
+				},
+			},
+		},
+		{
+			Target: "creds.env",
+			Secrets: []types.DetectedSecret{
+				secret("aws-access-key-id", 2),
+			},
+			Suppressions: []types.Suppression{
+				{
+					Type:      types.SuppressionSecret,
+					ID:        "aws-access-key-id",
+					StartLine: 1,
+					Reason:    "test fixture",
+				},
+			},
+		},
+		{
+			Target: "Dockerfile",
+			Misconfigurations: []types.DetectedMisconfiguration{
+				misconf("DS002", 3, types.StatusFailure),
+			},
+		},
+	}
+	assert.Equal(t, want, results)
+}
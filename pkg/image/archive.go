@@ -0,0 +1,273 @@
+package image
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	multierror "github.com/hashicorp/go-multierror"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/image"
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/fanal/utils"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+// ArchiveOption holds the options for image archives
+type ArchiveOption struct {
+	// Platform selects an image from multi-arch archives, e.g. "linux/arm64"
+	Platform string
+}
+
+// NewArchiveImage opens a Docker archive created by "docker save" or an OCI layout as an image.
+// When a platform is specified, the image matching the platform is selected from the archive.
+func NewArchiveImage(fileName string, opt ArchiveOption) (types.Image, error) {
+	if opt.Platform == "" {
+		return image.NewArchiveImage(fileName)
+	}
+
+	platform, err := ParsePlatform(opt.Platform)
+	if err != nil {
+		return nil, xerrors.Errorf("platform error: %w", err)
+	}
+
+	var errs error
+
+	// Docker archive
+	img, err := tryDockerArchive(fileName, platform)
+	if err == nil {
+		return archiveImage{name: fileName, Image: img}, nil
+	}
+	errs = multierror.Append(errs, err)
+
+	// OCI layout
+	img, err = tryOCI(fileName, platform)
+	if err == nil {
+		return archiveImage{name: fileName, Image: img}, nil
+	}
+	errs = multierror.Append(errs, err)
+
+	return nil, errs
+}
+
+// ParsePlatform parses a platform string in the format "os/arch[/variant]"
+func ParsePlatform(s string) (v1.Platform, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return v1.Platform{}, xerrors.Errorf("invalid platform %q, it must be in the format os/arch[/variant]", s)
+	}
+
+	p := v1.Platform{
+		OS:           parts[0],
+		Architecture: parts[1],
+	}
+	if len(parts) == 3 {
+		p.Variant = parts[2]
+	}
+	return p, nil
+}
+
+// matchPlatform reports whether the given platform satisfies the required one.
+// The variant is compared only when it is required.
+func matchPlatform(got, want v1.Platform) bool {
+	if got.OS != want.OS || got.Architecture != want.Architecture {
+		return false
+	}
+	return want.Variant == "" || got.Variant == want.Variant
+}
+
+func tryDockerArchive(fileName string, platform v1.Platform) (v1.Image, error) {
+	opener := fileOpener(fileName)
+	manifest, err := tarball.LoadManifest(opener)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to open %s as a Docker image: %w", fileName, err)
+	}
+
+	for _, desc := range manifest {
+		var tag *name.Tag
+		if len(desc.RepoTags) > 0 {
+			t, err := name.NewTag(desc.RepoTags[0])
+			if err != nil {
+				return nil, xerrors.Errorf("invalid tag %q: %w", desc.RepoTags[0], err)
+			}
+			tag = &t
+		} else if len(manifest) > 1 {
+			// Untagged images can't be selected from an archive with multiple images
+			log.Logger.Debugf("Skipping the untagged image %s", desc.Config)
+			continue
+		}
+
+		img, err := tarball.Image(opener, tag)
+		if err != nil {
+			return nil, xerrors.Errorf("unable to open %s as a Docker image: %w", fileName, err)
+		}
+
+		ok, err := imagePlatformMatches(img, platform)
+		if err != nil {
+			return nil, err
+		} else if ok {
+			return img, nil
+		}
+	}
+	return nil, xerrors.Errorf("no image for %s found in the Docker archive", platformString(platform))
+}
+
+func tryOCI(fileName string, platform v1.Platform) (v1.Image, error) {
+	var inputTag string
+
+	// Check if tag is specified in input
+	// e.g. /path/to/oci:0.0.1
+	inputFileName := fileName
+	if strings.Contains(fileName, ":") {
+		splitFileName := strings.SplitN(fileName, ":", 2)
+		inputFileName = splitFileName[0]
+		inputTag = splitFileName[1]
+	}
+
+	lp, err := layout.FromPath(inputFileName)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to open %s as an OCI Image: %w", fileName, err)
+	}
+
+	index, err := lp.ImageIndex()
+	if err != nil {
+		return nil, xerrors.Errorf("unable to retrieve index.json: %w", err)
+	}
+
+	img, err := findOCIImage(index, inputTag, platform)
+	if err != nil {
+		return nil, err
+	} else if img == nil {
+		return nil, xerrors.Errorf("no image for %s found in the OCI layout", platformString(platform))
+	}
+	return img, nil
+}
+
+// findOCIImage walks the image index recursively and returns the first image matching the platform.
+// nil is returned if no image matches.
+func findOCIImage(index v1.ImageIndex, inputTag string, platform v1.Platform) (v1.Image, error) {
+	m, err := index.IndexManifest()
+	if err != nil {
+		return nil, xerrors.Errorf("invalid index manifest: %w", err)
+	}
+
+	for _, manifest := range m.Manifests {
+		// The tag is annotated only in the top-level index
+		if inputTag != "" && manifest.Annotations[ispec.AnnotationRefName] != inputTag {
+			continue
+		}
+
+		h := manifest.Digest
+		if manifest.MediaType.IsIndex() {
+			childIndex, err := index.ImageIndex(h)
+			if err != nil {
+				return nil, xerrors.Errorf("unable to retrieve a child image %q: %w", h.String(), err)
+			}
+			img, err := findOCIImage(childIndex, "", platform)
+			if err != nil {
+				return nil, err
+			} else if img != nil {
+				return img, nil
+			}
+			continue
+		}
+
+		// The platform in the descriptor is optional
+		if manifest.Platform != nil && !matchPlatform(*manifest.Platform, platform) {
+			continue
+		}
+
+		img, err := index.Image(h)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid OCI image: %w", err)
+		}
+
+		ok, err := imagePlatformMatches(img, platform)
+		if err != nil {
+			return nil, err
+		} else if ok {
+			return img, nil
+		}
+	}
+	return nil, nil
+}
+
+// imagePlatformMatches checks the platform in the image config.
+// The variant is not compared as it is not available in the config.
+func imagePlatformMatches(img v1.Image, platform v1.Platform) (bool, error) {
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		return false, xerrors.Errorf("unable to get the config file: %w", err)
+	}
+	return cfg.OS == platform.OS && cfg.Architecture == platform.Architecture, nil
+}
+
+func platformString(p v1.Platform) string {
+	s := p.OS + "/" + p.Architecture
+	if p.Variant != "" {
+		s += "/" + p.Variant
+	}
+	return s
+}
+
+func fileOpener(fileName string) tarball.Opener {
+	return func() (io.ReadCloser, error) {
+		f, err := os.Open(fileName)
+		if err != nil {
+			return nil, xerrors.Errorf("unable to open the file: %w", err)
+		}
+
+		br := bufio.NewReader(f)
+		if !utils.IsGzip(br) {
+			return readCloser{Reader: br, Closer: f}, nil
+		}
+
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			_ = f.Close()
+			return nil, xerrors.Errorf("failed to open gzip: %w", err)
+		}
+		return readCloser{Reader: gr, Closer: f}, nil
+	}
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+type archiveImage struct {
+	v1.Image
+	name string
+}
+
+func (img archiveImage) Name() string {
+	return img.name
+}
+
+func (img archiveImage) ID() (string, error) {
+	return image.ID(img)
+}
+
+// LayerIDs returns a list of uncompressed layer IDs
+func (img archiveImage) LayerIDs() ([]string, error) {
+	return image.LayerIDs(img)
+}
+
+// RepoTags returns empty as an archive doesn't support RepoTags
+func (archiveImage) RepoTags() []string {
+	return nil
+}
+
+// RepoDigests returns empty as an archive doesn't support RepoDigests
+func (archiveImage) RepoDigests() []string {
+	return nil
+}
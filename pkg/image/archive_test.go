@@ -0,0 +1,191 @@
+package image
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/retry"
+)
+
+func TestMain(m *testing.M) {
+	_ = log.InitLogger(false, true)
+	// Unreachable mirrors are retried
+	_ = retry.SetPolicy(retry.DefaultRetries, time.Millisecond)
+	m.Run()
+}
+
+func newImage(t *testing.T, platform v1.Platform) v1.Image {
+	img, err := random.Image(10, 1)
+	require.NoError(t, err)
+
+	cfg, err := img.ConfigFile()
+	require.NoError(t, err)
+	cfg.OS = platform.OS
+	cfg.Architecture = platform.Architecture
+
+	img, err = mutate.ConfigFile(img, cfg)
+	require.NoError(t, err)
+	return img
+}
+
+func imageID(t *testing.T, img v1.Image) string {
+	h, err := img.ConfigName()
+	require.NoError(t, err)
+	return h.String()
+}
+
+func TestNewArchiveImage(t *testing.T) {
+	amd64 := v1.Platform{OS: "linux", Architecture: "amd64"}
+	arm64 := v1.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"}
+	amd64Image := newImage(t, amd64)
+	arm64Image := newImage(t, arm64)
+
+	// docker save -o multi.tar alpine:amd64 alpine:arm64
+	dockerArchive := filepath.Join(t.TempDir(), "multi.tar")
+	amd64Tag, err := name.NewTag("alpine:amd64")
+	require.NoError(t, err)
+	arm64Tag, err := name.NewTag("alpine:arm64")
+	require.NoError(t, err)
+	err = tarball.MultiWriteToFile(dockerArchive, map[name.Tag]v1.Image{
+		amd64Tag: amd64Image,
+		arm64Tag: arm64Image,
+	})
+	require.NoError(t, err)
+
+	// OCI layout with a multi-arch image index
+	ociLayout := t.TempDir()
+	index := mutate.AppendManifests(empty.Index,
+		mutate.IndexAddendum{Add: amd64Image, Descriptor: v1.Descriptor{Platform: &amd64}},
+		mutate.IndexAddendum{Add: arm64Image, Descriptor: v1.Descriptor{Platform: &arm64}},
+	)
+	lp, err := layout.Write(ociLayout, empty.Index)
+	require.NoError(t, err)
+	err = lp.AppendIndex(index, layout.WithAnnotations(map[string]string{
+		"org.opencontainers.image.ref.name": "3.16",
+	}))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		fileName string
+		option   ArchiveOption
+		wantID   string
+		wantErr  string
+	}{
+		{
+			name:     "docker archive amd64",
+			fileName: dockerArchive,
+			option:   ArchiveOption{Platform: "linux/amd64"},
+			wantID:   imageID(t, amd64Image),
+		},
+		{
+			name:     "docker archive arm64",
+			fileName: dockerArchive,
+			option:   ArchiveOption{Platform: "linux/arm64"},
+			wantID:   imageID(t, arm64Image),
+		},
+		{
+			name:     "OCI layout arm64",
+			fileName: ociLayout,
+			option:   ArchiveOption{Platform: "linux/arm64"},
+			wantID:   imageID(t, arm64Image),
+		},
+		{
+			name:     "OCI layout arm64 with variant and tag",
+			fileName: ociLayout + ":3.16",
+			option:   ArchiveOption{Platform: "linux/arm64/v8"},
+			wantID:   imageID(t, arm64Image),
+		},
+		{
+			name:     "OCI layout without platform",
+			fileName: ociLayout,
+			wantID:   imageID(t, amd64Image),
+		},
+		{
+			name:     "OCI layout with unknown variant",
+			fileName: ociLayout,
+			option:   ArchiveOption{Platform: "linux/arm64/v7"},
+			wantErr:  "no image for linux/arm64/v7 found in the OCI layout",
+		},
+		{
+			name:     "unknown platform",
+			fileName: dockerArchive,
+			option:   ArchiveOption{Platform: "linux/s390x"},
+			wantErr:  "no image for linux/s390x found in the Docker archive",
+		},
+		{
+			name:     "invalid platform",
+			fileName: dockerArchive,
+			option:   ArchiveOption{Platform: "linux"},
+			wantErr:  "invalid platform",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := NewArchiveImage(tt.fileName, tt.option)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.fileName, img.Name())
+			id, err := img.ID()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantID, id)
+		})
+	}
+}
+
+func TestParsePlatform(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    v1.Platform
+		wantErr string
+	}{
+		{
+			name:  "os and arch",
+			input: "linux/amd64",
+			want:  v1.Platform{OS: "linux", Architecture: "amd64"},
+		},
+		{
+			name:  "with variant",
+			input: "linux/arm/v7",
+			want:  v1.Platform{OS: "linux", Architecture: "arm", Variant: "v7"},
+		},
+		{
+			name:    "missing arch",
+			input:   "linux/",
+			wantErr: "invalid platform",
+		},
+		{
+			name:    "too many parts",
+			input:   "linux/arm/v7/extra",
+			wantErr: "invalid platform",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePlatform(tt.input)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
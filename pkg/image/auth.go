@@ -0,0 +1,246 @@
+package image
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/config/configfile"
+	dtypes "github.com/docker/cli/cli/config/types"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/image/token"
+	"github.com/aquasecurity/fanal/image/token/azure"
+	"github.com/aquasecurity/fanal/image/token/ecr"
+	"github.com/aquasecurity/fanal/image/token/google"
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/clock"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+// credentialsTTL is how long the resolved credentials are cached, shorter than the lifetime of the tokens of the cloud registries
+const credentialsTTL = 10 * time.Minute
+
+// cloudRegistries return the credentials of the cloud registries such as ECR, GCR/Artifact Registry and ACR.
+// They are created per registry as they hold the state of the registry.
+var cloudRegistries = []func() token.Registry{
+	func() token.Registry { return &ecr.ECR{} },
+	func() token.Registry { return &google.Registry{} },
+	func() token.Registry { return &azure.Registry{} },
+}
+
+// DefaultKeychain resolves the credentials of registries without explicit credentials, i.e. in the following order.
+//  1. The "registries" section of the config file
+//  2. Docker config, i.e. credHelpers, credsStore and auths in $DOCKER_CONFIG/config.json or ~/.docker/config.json
+//  3. Podman and Buildah, i.e. $REGISTRY_AUTH_FILE, $XDG_RUNTIME_DIR/containers/auth.json or ~/.config/containers/auth.json
+//  4. Cloud registries, i.e. ECR, GCR/Artifact Registry and ACR with the credentials of the environment
+//
+// Keychains failing to resolve, e.g. due to a missing credential helper, are skipped with a warning.
+var DefaultKeychain = NewKeychain(types.DockerOption{}, "")
+
+type keychain struct {
+	name  string
+	chain authn.Keychain
+}
+
+type chainKeychain struct {
+	keychains []keychain
+
+	mu    sync.Mutex
+	cache map[string]cachedAuth
+}
+
+type cachedAuth struct {
+	auth      authn.Authenticator
+	expiresAt time.Time
+}
+
+// NewKeychain returns the keychain with the explicit credentials of the Docker options prior to DefaultKeychain,
+// except for the credentials of the registry in the config file.
+// The explicit credentials are sent only to the given registry, e.g. not to its mirrors.
+// The options of ECR and GCR are also passed to the cloud registries.
+func NewKeychain(opt types.DockerOption, registry string) authn.Keychain {
+	keychains := []keychain{
+		{name: "config file", chain: registryConfigKeychain{}},
+	}
+	switch {
+	case opt.UserName != "" || opt.Password != "":
+		keychains = append(keychains, keychain{
+			name: "--username and --password",
+			chain: staticKeychain{
+				registry: registry,
+				auth:     &authn.Basic{Username: opt.UserName, Password: opt.Password},
+			},
+		})
+	case opt.RegistryToken != "":
+		keychains = append(keychains, keychain{
+			name: "--registry-token",
+			chain: staticKeychain{
+				registry: registry,
+				auth:     &authn.Bearer{Token: opt.RegistryToken},
+			},
+		})
+	}
+
+	keychains = append(keychains,
+		keychain{name: "Docker config", chain: configKeychain{load: loadDockerConfig}},
+		keychain{name: "containers auth file", chain: configKeychain{load: loadContainersAuth}},
+		keychain{name: "cloud registry", chain: cloudKeychain{option: opt}},
+	)
+	return &chainKeychain{
+		keychains: keychains,
+		cache:     map[string]cachedAuth{},
+	}
+}
+
+// Resolve returns the first credentials found in the keychains, or anonymous.
+// The credentials are cached per registry for credentialsTTL as the cloud registries issue tokens every time.
+// Anonymous is not cached, nor are the credentials resolved while any keychain fails,
+// so that the credentials are resolved again, e.g. after a credential helper recovers.
+func (k *chainKeychain) Resolve(target authn.Resource) (authn.Authenticator, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	registry := target.RegistryStr()
+	if c, ok := k.cache[registry]; ok && clock.Now().Before(c.expiresAt) {
+		return c.auth, nil
+	}
+	delete(k.cache, registry)
+
+	var failed bool
+	for _, kc := range k.keychains {
+		a, err := kc.chain.Resolve(target)
+		if err != nil {
+			log.Logger.Warnf("Unable to get the credentials of %s from %s: %s", registry, kc.name, err)
+			failed = true
+			continue
+		} else if a == authn.Anonymous {
+			continue
+		}
+		log.Logger.Debugf("Using the credentials of %s from %s", registry, kc.name)
+		if !failed {
+			k.cache[registry] = cachedAuth{
+				auth:      a,
+				expiresAt: clock.Now().Add(credentialsTTL),
+			}
+		}
+		return a, nil
+	}
+	return authn.Anonymous, nil
+}
+
+// staticKeychain resolves the explicit credentials for the registry they are given for
+type staticKeychain struct {
+	registry string
+	auth     authn.Authenticator
+}
+
+func (k staticKeychain) Resolve(target authn.Resource) (authn.Authenticator, error) {
+	if target.RegistryStr() != k.registry {
+		return authn.Anonymous, nil
+	}
+	return k.auth, nil
+}
+
+// configKeychain resolves credentials in a config file of the Docker format, including credential helpers
+type configKeychain struct {
+	load func() (*configfile.ConfigFile, error)
+}
+
+func (k configKeychain) Resolve(target authn.Resource) (authn.Authenticator, error) {
+	cf, err := k.load()
+	if err != nil {
+		return nil, err
+	} else if cf == nil {
+		return authn.Anonymous, nil
+	}
+
+	// Docker Hub is stored with the legacy key
+	key := target.RegistryStr()
+	if key == name.DefaultRegistry {
+		key = authn.DefaultAuthKey
+	}
+
+	cfg, err := cf.GetAuthConfig(key)
+	if err != nil {
+		return nil, xerrors.Errorf("credential error: %w", err)
+	} else if cfg == (dtypes.AuthConfig{}) {
+		return authn.Anonymous, nil
+	}
+	return authn.FromConfig(authn.AuthConfig{
+		Username:      cfg.Username,
+		Password:      cfg.Password,
+		Auth:          cfg.Auth,
+		IdentityToken: cfg.IdentityToken,
+		RegistryToken: cfg.RegistryToken,
+	}), nil
+}
+
+func loadDockerConfig() (*configfile.ConfigFile, error) {
+	dir := os.Getenv("DOCKER_CONFIG")
+	if dir == "" {
+		dir = config.Dir()
+	}
+	if _, err := os.Stat(filepath.Join(dir, config.ConfigFileName)); err != nil {
+		return nil, nil
+	}
+	return config.Load(dir)
+}
+
+func loadContainersAuth() (*configfile.ConfigFile, error) {
+	var paths []string
+	if p := os.Getenv("REGISTRY_AUTH_FILE"); p != "" {
+		paths = append(paths, p)
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		paths = append(paths, filepath.Join(dir, "containers", "auth.json"))
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(home, ".config", "containers", "auth.json"))
+	}
+
+	for _, p := range paths {
+		f, err := os.Open(p)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		cf, err := config.LoadFromReader(f)
+		if err != nil {
+			return nil, xerrors.Errorf("%s: %w", p, err)
+		}
+		return cf, nil
+	}
+	return nil, nil
+}
+
+// cloudKeychain resolves credentials of the cloud registries with the credentials of the environment,
+// e.g. AWS_PROFILE, GOOGLE_APPLICATION_CREDENTIALS and AZURE_CLIENT_ID.
+type cloudKeychain struct {
+	option types.DockerOption
+}
+
+func (k cloudKeychain) Resolve(target authn.Resource) (authn.Authenticator, error) {
+	for _, newRegistry := range cloudRegistries {
+		r := newRegistry()
+		if err := r.CheckOptions(target.RegistryStr(), k.option); err != nil {
+			continue
+		}
+		username, password, err := r.GetCredential(context.Background())
+		if err != nil {
+			return nil, err
+		} else if username == "" && password == "" {
+			return authn.Anonymous, nil
+		}
+		return &authn.Basic{Username: username, Password: password}, nil
+	}
+	return authn.Anonymous, nil
+}
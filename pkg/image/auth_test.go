@@ -0,0 +1,261 @@
+package image
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/image/token"
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/clock"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+type fakeCloudRegistry struct {
+	domain string
+	calls  *int
+}
+
+func (r *fakeCloudRegistry) CheckOptions(domain string, _ types.DockerOption) error {
+	if domain != "123456789012.dkr.ecr.us-east-1.amazonaws.com" {
+		return xerrors.New("unknown registry")
+	}
+	r.domain = domain
+	return nil
+}
+
+func (r *fakeCloudRegistry) GetCredential(context.Context) (string, string, error) {
+	*r.calls++
+	return "AWS", "ecr-token", nil
+}
+
+func TestNewKeychain(t *testing.T) {
+	tests := []struct {
+		name          string
+		option        types.DockerOption
+		dockerConfig  string
+		authFile      string
+		registry      string
+		credsRegistry string
+		want          authn.AuthConfig
+		wantCloudCall int
+	}{
+		{
+			name:     "username and password",
+			option:   types.DockerOption{UserName: "user", Password: "pass"},
+			registry: "ghcr.io",
+			want:     authn.AuthConfig{Username: "user", Password: "pass"},
+		},
+		{
+			name:          "username and password of another registry",
+			option:        types.DockerOption{UserName: "user", Password: "pass"},
+			dockerConfig:  `{"auths":{"ghcr.io":{"auth":"ZG9ja2VyOnBhc3M="}}}`,
+			registry:      "ghcr.io",
+			credsRegistry: "quay.io",
+			want:          authn.AuthConfig{Username: "docker", Password: "pass"},
+		},
+		{
+			name:          "registry token of another registry",
+			option:        types.DockerOption{RegistryToken: "token"},
+			registry:      "ghcr.io",
+			credsRegistry: "quay.io",
+			want:          authn.AuthConfig{},
+		},
+		{
+			name:         "registry token over Docker config",
+			option:       types.DockerOption{RegistryToken: "token"},
+			dockerConfig: `{"auths":{"ghcr.io":{"auth":"ZG9ja2VyOnBhc3M="}}}`,
+			registry:     "ghcr.io",
+			want:         authn.AuthConfig{RegistryToken: "token"},
+		},
+		{
+			name:         "Docker config",
+			dockerConfig: `{"auths":{"ghcr.io":{"auth":"ZG9ja2VyOnBhc3M="}}}`,
+			registry:     "ghcr.io",
+			want:         authn.AuthConfig{Username: "docker", Password: "pass"},
+		},
+		{
+			name:         "Docker Hub",
+			dockerConfig: `{"auths":{"https://index.docker.io/v1/":{"auth":"ZG9ja2VyOnBhc3M="}}}`,
+			registry:     "index.docker.io",
+			want:         authn.AuthConfig{Username: "docker", Password: "pass"},
+		},
+		{
+			name:         "missing credential helper falls back to the containers auth file",
+			dockerConfig: `{"credHelpers":{"quay.io":"missing-helper"}}`,
+			authFile:     `{"auths":{"quay.io":{"auth":"cG9kbWFuOnBhc3M="}}}`,
+			registry:     "quay.io",
+			want:         authn.AuthConfig{Username: "podman", Password: "pass"},
+		},
+		{
+			name:          "cloud registry",
+			dockerConfig:  `{"auths":{"ghcr.io":{"auth":"ZG9ja2VyOnBhc3M="}}}`,
+			registry:      "123456789012.dkr.ecr.us-east-1.amazonaws.com",
+			want:          authn.AuthConfig{Username: "AWS", Password: "ecr-token"},
+			wantCloudCall: 1,
+		},
+		{
+			name:     "anonymous",
+			registry: "ghcr.io",
+			want:     authn.AuthConfig{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			t.Setenv("XDG_RUNTIME_DIR", "")
+			t.Setenv("DOCKER_CONFIG", filepath.Join(home, ".docker"))
+			t.Setenv("REGISTRY_AUTH_FILE", "")
+			t.Setenv("PATH", "")
+
+			if tt.dockerConfig != "" {
+				require.NoError(t, os.MkdirAll(filepath.Join(home, ".docker"), 0700))
+				require.NoError(t, os.WriteFile(filepath.Join(home, ".docker", "config.json"), []byte(tt.dockerConfig), 0600))
+			}
+			if tt.authFile != "" {
+				authFile := filepath.Join(home, "auth.json")
+				require.NoError(t, os.WriteFile(authFile, []byte(tt.authFile), 0600))
+				t.Setenv("REGISTRY_AUTH_FILE", authFile)
+			}
+
+			var calls int
+			defer func(r []func() token.Registry) { cloudRegistries = r }(cloudRegistries)
+			cloudRegistries = []func() token.Registry{
+				func() token.Registry { return &fakeCloudRegistry{calls: &calls} },
+			}
+
+			reg, err := name.NewRegistry(tt.registry)
+			require.NoError(t, err)
+
+			credsRegistry := tt.registry
+			if tt.credsRegistry != "" {
+				credsRegistry = tt.credsRegistry
+			}
+			kc := NewKeychain(tt.option, credsRegistry)
+			for i := 0; i < 2; i++ {
+				auth, err := kc.Resolve(reg)
+				require.NoError(t, err)
+				got, err := auth.Authorization()
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, *got)
+			}
+
+			// Credentials are cached per registry
+			assert.Equal(t, tt.wantCloudCall, calls)
+		})
+	}
+}
+
+type resolveResult struct {
+	auth authn.Authenticator
+	err  error
+}
+
+// fakeKeychain returns the results in order, and the last one after that
+type fakeKeychain struct {
+	results []resolveResult
+	calls   int
+}
+
+func (k *fakeKeychain) Resolve(authn.Resource) (authn.Authenticator, error) {
+	r := k.results[len(k.results)-1]
+	if k.calls < len(k.results) {
+		r = k.results[k.calls]
+	}
+	k.calls++
+	return r.auth, r.err
+}
+
+func TestChainKeychain_Resolve(t *testing.T) {
+	basic := &authn.Basic{Username: "user", Password: "pass"}
+	now := time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	type step struct {
+		elapsed time.Duration
+		want    authn.Authenticator
+	}
+	tests := []struct {
+		name      string
+		results   []resolveResult
+		steps     []step
+		wantCalls int
+	}{
+		{
+			name:    "cached",
+			results: []resolveResult{{auth: basic}},
+			steps: []step{
+				{want: basic},
+				{elapsed: time.Minute, want: basic},
+			},
+			wantCalls: 1,
+		},
+		{
+			name:    "expired",
+			results: []resolveResult{{auth: basic}},
+			steps: []step{
+				{want: basic},
+				{elapsed: credentialsTTL, want: basic},
+			},
+			wantCalls: 2,
+		},
+		{
+			name: "error then success",
+			results: []resolveResult{
+				{err: xerrors.New("credential helper error")},
+				{auth: basic},
+			},
+			steps: []step{
+				{want: authn.Anonymous},
+				{want: basic},
+				{want: basic},
+			},
+			wantCalls: 2,
+		},
+		{
+			name: "anonymous then success",
+			results: []resolveResult{
+				{auth: authn.Anonymous},
+				{auth: basic},
+			},
+			steps: []step{
+				{want: authn.Anonymous},
+				{want: basic},
+				{want: basic},
+			},
+			wantCalls: 2,
+		},
+	}
+
+	require.NoError(t, log.InitLogger(false, true))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeKeychain{results: tt.results}
+			kc := &chainKeychain{
+				keychains: []keychain{{name: "fake", chain: fake}},
+				cache:     map[string]cachedAuth{},
+			}
+
+			reg, err := name.NewRegistry("ghcr.io")
+			require.NoError(t, err)
+
+			for _, s := range tt.steps {
+				now = now.Add(s.elapsed)
+				clock.SetFakeTime(t, now)
+
+				got, err := kc.Resolve(reg)
+				require.NoError(t, err)
+				assert.Equal(t, s.want, got)
+			}
+			assert.Equal(t, tt.wantCalls, fake.calls)
+		})
+	}
+}
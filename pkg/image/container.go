@@ -0,0 +1,85 @@
+package image
+
+import (
+	"context"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/image"
+	"github.com/aquasecurity/fanal/image/daemon"
+	"github.com/aquasecurity/fanal/types"
+)
+
+// ContainerOption holds the options for container images
+type ContainerOption struct {
+	// Offline disables pulling images from registries,
+	// so that only images in the local container engines are available.
+	Offline bool
+
+	// RegistryToken is a bearer token sent to registries, which takes precedence over TRIVY_REGISTRY_TOKEN
+	RegistryToken string
+}
+
+// NewContainerImage returns the image in Docker Engine, Podman, containerd or a registry.
+// Images are pulled from registries with the credentials of NewKeychain.
+func NewContainerImage(ctx context.Context, imageName string, dockerOpt types.DockerOption, opt ContainerOption) (
+	types.Image, func(), error) {
+	if opt.RegistryToken != "" {
+		dockerOpt.RegistryToken = opt.RegistryToken
+	}
+
+	ref, err := parseReference(imageName, dockerOpt.NonSSL)
+	if err != nil {
+		return nil, func() {}, xerrors.Errorf("failed to parse the image name: %w", err)
+	}
+
+	var errs error
+	img, cleanup, err := daemon.DockerImage(ref)
+	if err == nil {
+		return daemonImage{Image: img, name: imageName}, cleanup, nil
+	}
+	errs = multierror.Append(errs, err)
+
+	img, cleanup, err = daemon.PodmanImage(imageName)
+	if err == nil {
+		return daemonImage{Image: img, name: imageName}, cleanup, nil
+	}
+	errs = multierror.Append(errs, err)
+
+	img, cleanup, err = daemon.ContainerdImage(ctx, imageName)
+	if err == nil {
+		return daemonImage{Image: img, name: imageName}, cleanup, nil
+	}
+	errs = multierror.Append(errs, err)
+
+	if opt.Offline {
+		return nil, func() {}, xerrors.Errorf("%s is not found in the local container engines, "+
+			"and pulling images from registries is disabled by --offline-scan: %w", imageName, errs)
+	}
+
+	rimg, err := remoteImage(ctx, imageName, ref, dockerOpt)
+	if err == nil {
+		return rimg, func() {}, nil
+	}
+	errs = multierror.Append(errs, err)
+
+	return nil, func() {}, errs
+}
+
+type daemonImage struct {
+	daemon.Image
+	name string
+}
+
+func (img daemonImage) Name() string {
+	return img.name
+}
+
+func (img daemonImage) ID() (string, error) {
+	return image.ID(img)
+}
+
+func (img daemonImage) LayerIDs() ([]string, error) {
+	return image.LayerIDs(img)
+}
@@ -0,0 +1,164 @@
+package image
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/httpclient"
+	"github.com/aquasecurity/trivy/pkg/retry"
+)
+
+// RegistryConfig holds the settings of a registry in the "registries" section of the config file, e.g.
+//
+//	registries:
+//	  docker.io:
+//	    mirrors: [mirror.internal/dockerhub]
+//	  registry.internal:5000:
+//	    ca: /etc/ssl/certs/internal-ca.pem
+//	    username: ${REGISTRY_USER}
+//	    password: ${REGISTRY_PASSWORD}
+//	  legacy.internal:
+//	    insecure: true
+type RegistryConfig struct {
+	// Mirrors are tried in order before the registry when pulling images.
+	// A mirror may have a path prefix, e.g. mirror.internal/dockerhub.
+	Mirrors []string `yaml:"mirrors"`
+
+	// CA is a PEM file of certificates trusted in addition to the system ones
+	CA string `yaml:"ca"`
+
+	// Insecure skips TLS verification and allows plain HTTP
+	Insecure bool `yaml:"insecure"`
+
+	// Credentials take precedence over any other credentials for the registry
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	Token    string `yaml:"token"`
+}
+
+type registry struct {
+	RegistryConfig
+	transport http.RoundTripper
+}
+
+// registries are keyed by the normalized hosts, e.g. index.docker.io for docker.io
+var registries = map[string]registry{}
+
+// SetRegistries sets the settings of the registries from the config file.
+// The CA files are loaded here so that errors are reported before scanning.
+func SetRegistries(configs map[string]RegistryConfig) error {
+	regs := map[string]registry{}
+	for host, conf := range configs {
+		reg, err := name.NewRegistry(host)
+		if err != nil {
+			return xerrors.Errorf("invalid registry %q: %w", host, err)
+		}
+		for _, mirror := range conf.Mirrors {
+			if _, err = name.NewRepository(mirror + "/repo"); err != nil {
+				return xerrors.Errorf("invalid mirror %q of %s: %w", mirror, host, err)
+			}
+		}
+
+		var transport http.RoundTripper
+		if conf.CA != "" || conf.Insecure {
+			if transport, err = newTransport(conf.CA, conf.Insecure); err != nil {
+				return xerrors.Errorf("registry %s: %w", host, err)
+			}
+		}
+		regs[reg.RegistryStr()] = registry{
+			RegistryConfig: conf,
+			transport:      transport,
+		}
+	}
+	registries = regs
+	return nil
+}
+
+func newTransport(caFile string, insecure bool) (*http.Transport, error) {
+	t := httpclient.Transport(insecure)
+	if insecure {
+		return t, nil
+	}
+
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, xerrors.Errorf("CA file error: %w", err)
+	}
+	if t.TLSClientConfig.RootCAs, err = httpclient.RootCAs(pem); err != nil {
+		return nil, xerrors.Errorf("%s: %w", caFile, err)
+	}
+	return t, nil
+}
+
+// Transport returns the transport to access registries with the TLS settings of each registry.
+// insecure skips TLS verification of every registry, e.g. --insecure.
+// Transient errors are retried with the retry policy.
+func Transport(insecure bool) http.RoundTripper {
+	if insecure {
+		return retry.Transport(httpclient.Transport(true))
+	}
+	if len(registries) == 0 {
+		return retry.Transport(http.DefaultTransport)
+	}
+	return retry.Transport(registryTransport{})
+}
+
+// registryTransport dispatches requests by the host, so that the CA and insecure settings apply only to the registry
+type registryTransport struct{}
+
+func (registryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if reg, ok := registries[req.URL.Host]; ok && reg.transport != nil {
+		return reg.transport.RoundTrip(req)
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// parseReference parses the image name, allowing plain HTTP for insecure registries
+func parseReference(imageName string, nonSSL bool) (name.Reference, error) {
+	ref, err := name.ParseReference(imageName)
+	if err != nil {
+		return nil, err
+	}
+	if nonSSL || registries[ref.Context().RegistryStr()].Insecure {
+		return name.ParseReference(imageName, name.Insecure)
+	}
+	return ref, nil
+}
+
+// mirrorReferences returns the references of the image in the mirrors of the registry, followed by the reference itself
+func mirrorReferences(ref name.Reference, nonSSL bool) []name.Reference {
+	sep := ":"
+	if _, ok := ref.(name.Digest); ok {
+		sep = "@"
+	}
+
+	repo := ref.Context()
+	var refs []name.Reference
+	for _, mirror := range registries[repo.RegistryStr()].Mirrors {
+		mref, err := parseReference(strings.TrimSuffix(mirror, "/")+"/"+repo.RepositoryStr()+sep+ref.Identifier(), nonSSL)
+		if err != nil {
+			continue
+		}
+		refs = append(refs, mref)
+	}
+	return append(refs, ref)
+}
+
+// registryConfigKeychain resolves the credentials in the "registries" section of the config file
+type registryConfigKeychain struct{}
+
+func (registryConfigKeychain) Resolve(target authn.Resource) (authn.Authenticator, error) {
+	reg := registries[target.RegistryStr()]
+	switch {
+	case reg.Username != "" || reg.Password != "":
+		return &authn.Basic{Username: reg.Username, Password: reg.Password}, nil
+	case reg.Token != "":
+		return &authn.Bearer{Token: reg.Token}, nil
+	}
+	return authn.Anonymous, nil
+}
@@ -0,0 +1,250 @@
+package image
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	ggcrregistry "github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/types"
+)
+
+func setRegistries(t *testing.T, configs map[string]RegistryConfig) {
+	t.Cleanup(func() { registries = map[string]registry{} })
+	require.NoError(t, SetRegistries(configs))
+}
+
+func writeCA(t *testing.T, s *httptest.Server) string {
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	b := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caFile, b, 0600))
+	return caFile
+}
+
+func TestSetRegistries(t *testing.T) {
+	notPEM := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(notPEM, []byte("not a certificate"), 0600))
+
+	tests := []struct {
+		name    string
+		configs map[string]RegistryConfig
+		wantErr string
+	}{
+		{
+			name: "happy path",
+			configs: map[string]RegistryConfig{
+				"docker.io":              {Mirrors: []string{"mirror.internal/dockerhub"}},
+				"registry.internal:5000": {Insecure: true},
+			},
+		},
+		{
+			name:    "missing CA",
+			configs: map[string]RegistryConfig{"registry.internal": {CA: "missing.pem"}},
+			wantErr: "CA file error",
+		},
+		{
+			name:    "no certificates",
+			configs: map[string]RegistryConfig{"registry.internal": {CA: notPEM}},
+			wantErr: "no certificates found",
+		},
+		{
+			name:    "invalid mirror",
+			configs: map[string]RegistryConfig{"docker.io": {Mirrors: []string{"Mirror.internal/UPPER"}}},
+			wantErr: `invalid mirror "Mirror.internal/UPPER" of docker.io`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(func() { registries = map[string]registry{} })
+			err := SetRegistries(tt.configs)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			// docker.io is normalized
+			assert.Contains(t, registries, "index.docker.io")
+		})
+	}
+}
+
+func TestTransport(t *testing.T) {
+	s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer s.Close()
+	host := strings.TrimPrefix(s.URL, "https://")
+
+	tests := []struct {
+		name     string
+		configs  map[string]RegistryConfig
+		insecure bool
+		wantErr  bool
+	}{
+		{
+			name:    "unknown CA",
+			wantErr: true,
+		},
+		{
+			name:    "CA of another registry",
+			configs: map[string]RegistryConfig{"registry.internal": {CA: writeCA(t, s)}},
+			wantErr: true,
+		},
+		{
+			name:    "CA",
+			configs: map[string]RegistryConfig{host: {CA: writeCA(t, s)}},
+		},
+		{
+			name:    "insecure registry",
+			configs: map[string]RegistryConfig{host: {Insecure: true}},
+		},
+		{
+			name:     "--insecure",
+			insecure: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRegistries(t, tt.configs)
+
+			client := http.Client{Transport: Transport(tt.insecure)}
+			resp, err := client.Get(s.URL)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			resp.Body.Close()
+		})
+	}
+}
+
+func TestNewContainerImage_Mirrors(t *testing.T) {
+	// The image exists only in the mirror
+	mirror := httptest.NewServer(ggcrregistry.New())
+	defer mirror.Close()
+	mirrorHost := strings.TrimPrefix(mirror.URL, "http://")
+
+	img, err := random.Image(100, 1)
+	require.NoError(t, err)
+	mirrorRef, err := name.ParseReference(mirrorHost + "/dockerhub/trivy-test/mirror:1.0")
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(mirrorRef, img))
+
+	setRegistries(t, map[string]RegistryConfig{
+		"docker.io": {
+			Mirrors: []string{
+				"127.0.0.1:1/unreachable",
+				mirrorHost + "/dockerhub",
+			},
+		},
+		mirrorHost: {Insecure: true},
+	})
+
+	got, cleanup, err := NewContainerImage(context.Background(), "trivy-test/mirror:1.0", types.DockerOption{},
+		ContainerOption{})
+	require.NoError(t, err)
+	defer cleanup()
+
+	digest, err := img.Digest()
+	require.NoError(t, err)
+
+	// The image is reported as the original one
+	assert.Equal(t, "trivy-test/mirror:1.0", got.Name())
+	assert.Equal(t, []string{"trivy-test/mirror:1.0"}, got.RepoTags())
+	assert.Equal(t, []string{"trivy-test/mirror@" + digest.String()}, got.RepoDigests())
+}
+
+func TestNewContainerImage_MirrorCredentials(t *testing.T) {
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+
+	// The mirror asks for credentials, but must not receive those of the original registry
+	var authorized bool
+	handler := ggcrregistry.New()
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			authorized = true
+		}
+		if r.URL.Path == "/v2/" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="mirror"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer mirror.Close()
+	mirrorHost := strings.TrimPrefix(mirror.URL, "http://")
+
+	img, err := random.Image(100, 1)
+	require.NoError(t, err)
+	mirrorRef, err := name.ParseReference(mirrorHost + "/dockerhub/trivy-test/mirror:1.0")
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(mirrorRef, img))
+
+	setRegistries(t, map[string]RegistryConfig{
+		"docker.io": {Mirrors: []string{mirrorHost + "/dockerhub"}},
+		mirrorHost:  {Insecure: true},
+	})
+
+	_, cleanup, err := NewContainerImage(context.Background(), "trivy-test/mirror:1.0",
+		types.DockerOption{UserName: "user", Password: "pass"}, ContainerOption{})
+	require.NoError(t, err)
+	defer cleanup()
+	assert.False(t, authorized, "the mirror received an Authorization header")
+}
+
+func TestNewKeychain_RegistryConfig(t *testing.T) {
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+	setRegistries(t, map[string]RegistryConfig{
+		"registry.internal": {Username: "internal", Password: "pass"},
+		"ghcr.io":           {Token: "token"},
+	})
+
+	tests := []struct {
+		registry string
+		want     authn.AuthConfig
+	}{
+		{
+			// Credentials in the config file take precedence over TRIVY_USERNAME and TRIVY_PASSWORD
+			registry: "registry.internal",
+			want:     authn.AuthConfig{Username: "internal", Password: "pass"},
+		},
+		{
+			registry: "ghcr.io",
+			want:     authn.AuthConfig{RegistryToken: "token"},
+		},
+		{
+			registry: "quay.io",
+			want:     authn.AuthConfig{Username: "user", Password: "global"},
+		},
+		{
+			// TRIVY_USERNAME and TRIVY_PASSWORD are sent only to the registry of the image
+			registry: "docker.io",
+			want:     authn.AuthConfig{},
+		},
+	}
+	kc := NewKeychain(types.DockerOption{UserName: "user", Password: "global"}, "quay.io")
+	for _, tt := range tests {
+		t.Run(tt.registry, func(t *testing.T) {
+			reg, err := name.NewRegistry(tt.registry)
+			require.NoError(t, err)
+			auth, err := kc.Resolve(reg)
+			require.NoError(t, err)
+			got, err := auth.Authorization()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, *got)
+		})
+	}
+}
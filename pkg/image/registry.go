@@ -0,0 +1,34 @@
+package image
+
+import (
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// RepoDigest returns the digest of the image in the repository of the reference,
+// e.g. "sha256:..." for "alpine:3.16" with the repository digest "alpine@sha256:...".
+// It returns an empty string if the image is not in the repository.
+func RepoDigest(ref name.Reference, repoDigests []string) string {
+	if d, ok := ref.(name.Digest); ok {
+		return d.DigestStr()
+	}
+	for _, rd := range repoDigests {
+		d, err := name.NewDigest(rd)
+		if err != nil {
+			continue
+		}
+		if d.Context().Name() == ref.Context().Name() {
+			return d.DigestStr()
+		}
+	}
+	return ""
+}
+
+// RemoteOptions returns the options to access registries with the credentials of DefaultKeychain
+// and the TLS settings of the registries in the config file
+func RemoteOptions(insecure bool) []remote.Option {
+	return []remote.Option{
+		remote.WithAuthFromKeychain(DefaultKeychain),
+		remote.WithTransport(Transport(insecure)),
+	}
+}
@@ -0,0 +1,93 @@
+package image
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	multierror "github.com/hashicorp/go-multierror"
+
+	"github.com/aquasecurity/fanal/image"
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+// remoteImage pulls the image from the registry with the credentials of NewKeychain.
+// The mirrors of the registry in the config file are tried first.
+func remoteImage(ctx context.Context, imageName string, ref name.Reference, dockerOpt types.DockerOption) (
+	types.Image, error) {
+	opts := []remote.Option{
+		remote.WithContext(ctx),
+		remote.WithAuthFromKeychain(NewKeychain(dockerOpt, ref.Context().RegistryStr())),
+		remote.WithTransport(Transport(dockerOpt.InsecureSkipTLSVerify)),
+	}
+
+	var errs error
+	for _, r := range mirrorReferences(ref, dockerOpt.NonSSL) {
+		desc, err := remote.Get(r, opts...)
+		if err != nil {
+			if r != ref {
+				log.Logger.Debugf("Unable to pull %s from the mirror: %s", imageName, err)
+			}
+			errs = multierror.Append(errs, err)
+			continue
+		}
+
+		img, err := desc.Image()
+		if err != nil {
+			return nil, err
+		}
+
+		// The name and the repository are kept even if the image is pulled from a mirror
+		return registryImage{
+			Image:  img,
+			name:   imageName,
+			ref:    ref,
+			digest: desc.Digest,
+		}, nil
+	}
+	return nil, errs
+}
+
+type registryImage struct {
+	v1.Image
+	name   string
+	ref    name.Reference
+	digest v1.Hash
+}
+
+func (img registryImage) Name() string {
+	return img.name
+}
+
+func (img registryImage) ID() (string, error) {
+	return image.ID(img)
+}
+
+func (img registryImage) LayerIDs() ([]string, error) {
+	return image.LayerIDs(img)
+}
+
+func (img registryImage) RepoTags() []string {
+	tag, ok := img.ref.(name.Tag)
+	if !ok {
+		return []string{}
+	}
+	return []string{fmt.Sprintf("%s:%s", img.repositoryName(), tag.TagStr())}
+}
+
+func (img registryImage) RepoDigests() []string {
+	return []string{fmt.Sprintf("%s@%s", img.repositoryName(), img.digest.String())}
+}
+
+// repositoryName returns the repository name without the default registry and namespace, e.g. "alpine"
+func (img registryImage) repositoryName() string {
+	repo := img.ref.Context()
+	if repo.RegistryStr() != name.DefaultRegistry {
+		return fmt.Sprintf("%s/%s", repo.RegistryStr(), repo.RepositoryStr())
+	}
+	return strings.TrimPrefix(repo.RepositoryStr(), "library/")
+}
@@ -0,0 +1,259 @@
+package imageconfig
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const (
+	// Type is the type of results of the image config checks
+	Type = "image-config"
+
+	// baseNameLabel is the OCI annotation of the base image, which is also set as a label by some build tools
+	baseNameLabel = "org.opencontainers.image.base.name"
+)
+
+var (
+	// e.g. ADD https://example.com/app.tar.gz /opt/ # buildkit
+	addURLRegexp = regexp.MustCompile(`(?:^|\s)ADD\s+(?:--\S+\s+)*(https?://\S+)`)
+
+	// Build arguments used in RUN are recorded with the number of them, e.g. RUN |1 TOKEN=abc /bin/sh -c make
+	runArgsRegexp = regexp.MustCompile(`^(?:RUN\s+)?\|(\d+)\s+(.*)$`)
+
+	// e.g. ARG PASSWORD=secret
+	argRegexp = regexp.MustCompile(`(?:^|\s)ARG\s+([^=\s]+)=(\S+)`)
+
+	secretKeywords = []string{"PASSWORD", "PASSWD", "SECRET", "TOKEN", "API_KEY", "APIKEY", "ACCESS_KEY", "PRIVATE_KEY", "CREDENTIAL"}
+)
+
+type check struct {
+	id          string
+	title       string
+	description string
+	resolution  string
+	severity    dbTypes.Severity
+	run         func(cfg v1.ConfigFile) []finding
+}
+
+// finding is a failure of the check
+type finding struct {
+	message string
+	diffID  string
+}
+
+var checks = []check{
+	{
+		id:          "IC001",
+		title:       "Image user should not be 'root'",
+		description: "Running containers as root makes it easier to escalate privileges to the host when the container is compromised.",
+		resolution:  "Add 'USER <non-root user>' to the Dockerfile",
+		severity:    dbTypes.SeverityHigh,
+		run:         checkRootUser,
+	},
+	{
+		id:          "IC002",
+		title:       "ADD should not fetch remote URLs",
+		description: "Files fetched by ADD are not verified, and the content may change between builds.",
+		resolution:  "Download files with a checksum verification in RUN, or use COPY",
+		severity:    dbTypes.SeverityMedium,
+		run:         checkRemoteADD,
+	},
+	{
+		id:          "IC003",
+		title:       "Secrets should not be set in ENV or ARG",
+		description: "Environment variables and build arguments are recorded in the image config and history, and anyone pulling the image can read them.",
+		resolution:  "Pass secrets at runtime or use secret mounts of BuildKit",
+		severity:    dbTypes.SeverityCritical,
+		run:         checkSecrets,
+	},
+	{
+		id:          "IC004",
+		title:       "Base image should not use the 'latest' tag",
+		description: "The base image with the 'latest' tag or without a tag may change between builds.",
+		resolution:  "Pin the base image with a version tag or a digest in FROM",
+		severity:    dbTypes.SeverityMedium,
+		run:         checkLatestTag,
+	},
+	{
+		id:          "IC005",
+		title:       "HEALTHCHECK should be defined",
+		description: "Without HEALTHCHECK, the container engine can't detect that the application is unhealthy.",
+		resolution:  "Add HEALTHCHECK to the Dockerfile",
+		severity:    dbTypes.SeverityLow,
+		run:         checkHealthcheck,
+	},
+}
+
+// Scan evaluates the checks against the image config, including instructions reconstructed from the history.
+// Passed checks are also returned, and they are filtered out later unless "--include-non-failures" is specified.
+func Scan(cfg v1.ConfigFile) []types.DetectedMisconfiguration {
+	var misconfs []types.DetectedMisconfiguration
+	for _, c := range checks {
+		findings := c.run(cfg)
+		if len(findings) == 0 {
+			misconfs = append(misconfs, c.misconf("No issues found", types.StatusPassed, ""))
+			continue
+		}
+		for _, f := range findings {
+			misconfs = append(misconfs, c.misconf(f.message, types.StatusFailure, f.diffID))
+		}
+	}
+	return misconfs
+}
+
+func (c check) misconf(msg string, status types.MisconfStatus, diffID string) types.DetectedMisconfiguration {
+	severity := c.severity
+	if status == types.StatusPassed {
+		severity = dbTypes.SeverityUnknown
+	}
+	return types.DetectedMisconfiguration{
+		Type:        "Image Config Security Check",
+		ID:          c.id,
+		Title:       c.title,
+		Description: c.description,
+		Message:     msg,
+		Resolution:  c.resolution,
+		Severity:    severity.String(),
+		Status:      status,
+		Layer:       ftypes.Layer{DiffID: diffID},
+	}
+}
+
+func checkRootUser(cfg v1.ConfigFile) []finding {
+	user, _, _ := strings.Cut(cfg.Config.User, ":")
+	if user == "" || user == "root" || user == "0" {
+		return []finding{{message: "Specify a non-root user with USER"}}
+	}
+	return nil
+}
+
+func checkRemoteADD(cfg v1.ConfigFile) []finding {
+	var findings []finding
+	for _, h := range histories(cfg) {
+		if m := addURLRegexp.FindStringSubmatch(h.createdBy); m != nil {
+			findings = append(findings, finding{
+				message: fmt.Sprintf("ADD fetches the remote URL '%s'", m[1]),
+				diffID:  h.diffID,
+			})
+		}
+	}
+	return findings
+}
+
+func checkSecrets(cfg v1.ConfigFile) []finding {
+	var findings []finding
+	for _, env := range cfg.Config.Env {
+		name, value, _ := strings.Cut(env, "=")
+		if value != "" && isSecretName(name) {
+			// The value is not shown in the message
+			findings = append(findings, finding{message: fmt.Sprintf("Possible secret in ENV '%s'", name)})
+		}
+	}
+
+	seen := map[string]struct{}{}
+	for _, h := range histories(cfg) {
+		for _, name := range buildArgs(h.createdBy) {
+			if _, ok := seen[name]; ok || !isSecretName(name) {
+				continue
+			}
+			seen[name] = struct{}{}
+			findings = append(findings, finding{
+				message: fmt.Sprintf("Possible secret in ARG '%s'", name),
+				diffID:  h.diffID,
+			})
+		}
+	}
+	return findings
+}
+
+func checkLatestTag(cfg v1.ConfigFile) []finding {
+	// The base image is not recorded in the history, so it is known only if the label is set
+	base, ok := cfg.Config.Labels[baseNameLabel]
+	if !ok || strings.Contains(base, "@") {
+		return nil
+	}
+	// e.g. docker.io/library/alpine:latest, localhost:5000/app
+	tag := ""
+	if i := strings.LastIndex(base, ":"); i > strings.LastIndex(base, "/") {
+		tag = base[i+1:]
+	}
+	if tag == "" || tag == "latest" {
+		return []finding{{message: fmt.Sprintf("Base image '%s' uses the latest tag", base)}}
+	}
+	return nil
+}
+
+func checkHealthcheck(cfg v1.ConfigFile) []finding {
+	hc := cfg.Config.Healthcheck
+	if hc == nil || len(hc.Test) == 0 || hc.Test[0] == "NONE" {
+		return []finding{{message: "Add HEALTHCHECK to the image"}}
+	}
+	return nil
+}
+
+type history struct {
+	createdBy string
+	diffID    string
+}
+
+// histories returns the history with the layer created by each instruction
+func histories(cfg v1.ConfigFile) []history {
+	var hs []history
+	var layerIndex int
+	for _, h := range cfg.History {
+		var diffID string
+		if !h.EmptyLayer {
+			if layerIndex < len(cfg.RootFS.DiffIDs) {
+				diffID = cfg.RootFS.DiffIDs[layerIndex].String()
+			}
+			layerIndex++
+		}
+		hs = append(hs, history{
+			createdBy: strings.TrimPrefix(h.CreatedBy, "/bin/sh -c #(nop) "),
+			diffID:    diffID,
+		})
+	}
+	return hs
+}
+
+// buildArgs returns the names of build arguments with values in the instruction
+func buildArgs(createdBy string) []string {
+	if m := argRegexp.FindStringSubmatch(createdBy); m != nil {
+		return []string{m[1]}
+	}
+
+	m := runArgsRegexp.FindStringSubmatch(createdBy)
+	if m == nil {
+		return nil
+	}
+	n, _ := strconv.Atoi(m[1])
+	fields := strings.Fields(m[2])
+	if n > len(fields) {
+		n = len(fields)
+	}
+	var names []string
+	for _, field := range fields[:n] {
+		if name, value, ok := strings.Cut(field, "="); ok && value != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func isSecretName(name string) bool {
+	name = strings.ToUpper(name)
+	for _, keyword := range secretKeywords {
+		if strings.Contains(name, keyword) {
+			return true
+		}
+	}
+	return false
+}
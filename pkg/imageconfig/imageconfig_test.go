@@ -0,0 +1,140 @@
+package imageconfig_test
+
+import (
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aquasecurity/trivy/pkg/imageconfig"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+type result struct {
+	id      string
+	message string
+	diffID  string
+}
+
+func TestScan(t *testing.T) {
+	diffID := v1.Hash{
+		Algorithm: "sha256",
+		Hex:       "24f0c9c8d4f1f7d66dd18c5a7a7a9d8cf1a7f0f2e2c0e6b3b95ab30ee4b3b8b0",
+	}
+	tests := []struct {
+		name string
+		cfg  v1.ConfigFile
+		want []result
+	}{
+		{
+			name: "all failed",
+			cfg: v1.ConfigFile{
+				Config: v1.Config{
+					User: "0:0",
+					Env: []string{
+						"PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin",
+						"DB_PASSWORD=changeme",
+					},
+					Labels: map[string]string{
+						"org.opencontainers.image.base.name": "docker.io/library/alpine",
+					},
+				},
+				History: []v1.History{
+					{
+						CreatedBy: "/bin/sh -c #(nop) ADD file:0a8b5a3b5d56f3d8b3b4f1b2c5e2e5e8f7b5d3c8a0a1b0e1c1d3f5a7b9c1d3e5 in / ",
+					},
+					{
+						CreatedBy:  "ARG GITHUB_TOKEN=ghp_xxx",
+						Comment:    "buildkit.dockerfile.v0",
+						EmptyLayer: true,
+					},
+					{
+						CreatedBy: "ADD https://example.com/app.tar.gz /opt/ # buildkit",
+						Comment:   "buildkit.dockerfile.v0",
+					},
+					{
+						CreatedBy: "RUN |2 GITHUB_TOKEN=ghp_xxx VERSION=1.0 /bin/sh -c make # buildkit",
+						Comment:   "buildkit.dockerfile.v0",
+					},
+				},
+				RootFS: v1.RootFS{
+					DiffIDs: []v1.Hash{{}, diffID, {}},
+				},
+			},
+			want: []result{
+				{
+					id:      "IC001",
+					message: "Specify a non-root user with USER",
+				},
+				{
+					id:      "IC002",
+					message: "ADD fetches the remote URL 'https://example.com/app.tar.gz'",
+					diffID:  diffID.String(),
+				},
+				{
+					id:      "IC003",
+					message: "Possible secret in ENV 'DB_PASSWORD'",
+				},
+				{
+					id:      "IC003",
+					message: "Possible secret in ARG 'GITHUB_TOKEN'",
+				},
+				{
+					id:      "IC004",
+					message: "Base image 'docker.io/library/alpine' uses the latest tag",
+				},
+				{
+					id:      "IC005",
+					message: "Add HEALTHCHECK to the image",
+				},
+			},
+		},
+		{
+			name: "all passed",
+			cfg: v1.ConfigFile{
+				Config: v1.Config{
+					User: "nobody",
+					Env: []string{
+						"PASSWORD_FILE=",
+					},
+					Labels: map[string]string{
+						"org.opencontainers.image.base.name": "localhost:5000/alpine:3.16",
+					},
+					Healthcheck: &v1.HealthConfig{
+						Test: []string{"CMD", "curl", "-f", "http://localhost/"},
+					},
+				},
+				History: []v1.History{
+					{
+						CreatedBy: "COPY app.tar.gz /opt/ # buildkit",
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			misconfs := imageconfig.Scan(tt.cfg)
+			failures := lo.Filter(misconfs, func(m types.DetectedMisconfiguration, _ int) bool {
+				return m.Status == types.StatusFailure
+			})
+
+			var got []result
+			for _, f := range failures {
+				got = append(got, result{
+					id:      f.ID,
+					message: f.Message,
+					diffID:  f.Layer.DiffID,
+				})
+			}
+			assert.Equal(t, tt.want, got)
+
+			// Each check is reported at least once
+			ids := lo.Uniq(lo.Map(misconfs, func(m types.DetectedMisconfiguration, _ int) string {
+				return m.ID
+			}))
+			assert.Equal(t, []string{"IC001", "IC002", "IC003", "IC004", "IC005"}, ids)
+		})
+	}
+}
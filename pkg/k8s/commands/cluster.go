@@ -4,7 +4,9 @@ import (
 	"github.com/aquasecurity/trivy-kubernetes/pkg/k8s"
 	"github.com/aquasecurity/trivy-kubernetes/pkg/trivyk8s"
 	cmd "github.com/aquasecurity/trivy/pkg/commands/artifact"
+	"github.com/aquasecurity/trivy/pkg/k8s/kbom"
 	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/report"
 
 	"github.com/urfave/cli/v2"
 	"golang.org/x/xerrors"
@@ -12,6 +14,10 @@ import (
 
 // clusterRun runs scan on kubernetes cluster
 func clusterRun(cliCtx *cli.Context, opt cmd.Option, cluster k8s.Cluster) error {
+	if opt.Format == report.FormatCycloneDX {
+		return kbomRun(cliCtx, opt, cluster)
+	}
+
 	if err := validateReportArguments(cliCtx); err != nil {
 		return err
 	}
@@ -23,3 +29,18 @@ func clusterRun(cliCtx *cli.Context, opt cmd.Option, cluster k8s.Cluster) error
 
 	return run(cliCtx.Context, opt, cluster.GetCurrentContext(), artifacts)
 }
+
+// kbomRun generates KBOM (Kubernetes Bill of Materials) of the cluster instead of scanning it
+func kbomRun(cliCtx *cli.Context, opt cmd.Option, cluster k8s.Cluster) error {
+	c, err := kbom.Collect(cliCtx.Context, cluster.GetCurrentContext(), cluster.GetDynamicClient())
+	if err != nil {
+		return xerrors.Errorf("KBOM error: %w", err)
+	}
+	log.Logger.Infof("Collected %d control-plane components, %d nodes, %d addons and %d CRDs",
+		len(c.ControlPlane), len(c.Nodes), len(c.Addons), len(c.CRDs))
+
+	if err = kbom.NewWriter(opt.Output, opt.AppVersion).Write(c); err != nil {
+		return xerrors.Errorf("unable to write KBOM: %w", err)
+	}
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/aquasecurity/trivy/pkg/k8s/report"
 	"github.com/aquasecurity/trivy/pkg/k8s/scanner"
 	"github.com/aquasecurity/trivy/pkg/log"
+	pkgReport "github.com/aquasecurity/trivy/pkg/report"
 
 	"github.com/aquasecurity/trivy-kubernetes/pkg/artifacts"
 	"github.com/aquasecurity/trivy-kubernetes/pkg/k8s"
@@ -28,6 +29,10 @@ func Run(cliCtx *cli.Context) error {
 		return xerrors.Errorf("option error: %w", err)
 	}
 
+	if opt.Format == pkgReport.FormatCycloneDX && cliCtx.Args().Get(0) != clusterArtifact {
+		return xerrors.New(`"--format cyclonedx" is available only for "cluster" to generate KBOM`)
+	}
+
 	cluster, err := k8s.GetCluster(opt.KubernetesOption.ClusterContext)
 	if err != nil {
 		return xerrors.Errorf("failed getting k8s cluster: %w", err)
@@ -49,9 +54,7 @@ func run(ctx context.Context, opt cmd.Option, cluster string, artifacts []*artif
 
 	var err error
 	defer func() {
-		if xerrors.Is(err, context.DeadlineExceeded) {
-			log.Logger.Warn("Increase --timeout value")
-		}
+		cmd.WarnTimeout(err)
 	}()
 
 	runner, err := cmd.NewRunner(opt)
@@ -0,0 +1,407 @@
+package kbom
+
+import (
+	"context"
+	"io"
+	"sort"
+	"strings"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/utils/clock"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/purl"
+	"github.com/aquasecurity/trivy/pkg/report/cyclonedx"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const (
+	systemNamespace = "kube-system"
+
+	// Properties of the KBOM components
+	PropertyImage            = "Image"
+	PropertyNamespace        = "Namespace"
+	PropertyOSImage          = "OSImage"
+	PropertyKernelVersion    = "KernelVersion"
+	PropertyArchitecture     = "Architecture"
+	PropertyContainerRuntime = "ContainerRuntime"
+	PropertyGroup            = "Group"
+	PropertyKind             = "Kind"
+	PropertyStorageVersion   = "StorageVersion"
+
+	// https://json-schema.org/understanding-json-schema/reference/string.html#dates-and-times
+	timeLayout = "2006-01-02T15:04:05+00:00"
+)
+
+var (
+	nodesGVR = schema.GroupVersionResource{Version: "v1", Resource: "nodes"}
+	podsGVR  = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	crdsGVR  = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+	// controlPlaneComponents are the images of control-plane components running as static pods
+	controlPlaneComponents = []string{"kube-apiserver", "kube-controller-manager", "kube-scheduler", "etcd"}
+
+	// goModules maps components to their Go modules, so that the KBOM can be scanned for vulnerabilities by "trivy sbom scan"
+	goModules = map[string]string{
+		"kube-apiserver":          "k8s.io/kubernetes",
+		"kube-controller-manager": "k8s.io/kubernetes",
+		"kube-scheduler":          "k8s.io/kubernetes",
+		"kube-proxy":              "k8s.io/kubernetes",
+		"kubelet":                 "k8s.io/kubernetes",
+		"etcd":                    "go.etcd.io/etcd",
+		"coredns":                 "github.com/coredns/coredns",
+		"containerd":              "github.com/containerd/containerd",
+		"cri-o":                   "github.com/cri-o/cri-o",
+	}
+)
+
+// Cluster holds the components of a Kubernetes cluster
+type Cluster struct {
+	Name string
+
+	// ControlPlane is empty for managed clusters hiding the control plane
+	ControlPlane []Component
+	Nodes        []Node
+	Addons       []Component
+	CRDs         []CRD
+}
+
+// Component is a control-plane component or an addon running in the kube-system namespace
+type Component struct {
+	Name    string
+	Version string
+	Image   string
+}
+
+// Node is a node of the cluster
+type Node struct {
+	Name             string
+	KubeletVersion   string
+	KubeProxyVersion string
+	ContainerRuntime string
+	OSImage          string
+	KernelVersion    string
+	Architecture     string
+}
+
+// CRD is a custom resource definition
+type CRD struct {
+	Name           string
+	Group          string
+	Kind           string
+	StorageVersion string
+}
+
+// Collect collects the components of the cluster
+func Collect(ctx context.Context, name string, client dynamic.Interface) (Cluster, error) {
+	cluster := Cluster{Name: name}
+
+	nodes, err := client.Resource(nodesGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return Cluster{}, xerrors.Errorf("unable to list nodes: %w", err)
+	}
+	for _, item := range nodes.Items {
+		nodeInfo := func(field string) string {
+			v, _, _ := unstructured.NestedString(item.Object, "status", "nodeInfo", field) // nolint: errcheck
+			return v
+		}
+		cluster.Nodes = append(cluster.Nodes, Node{
+			Name:             item.GetName(),
+			KubeletVersion:   nodeInfo("kubeletVersion"),
+			KubeProxyVersion: nodeInfo("kubeProxyVersion"),
+			ContainerRuntime: nodeInfo("containerRuntimeVersion"),
+			OSImage:          nodeInfo("osImage"),
+			KernelVersion:    nodeInfo("kernelVersion"),
+			Architecture:     nodeInfo("architecture"),
+		})
+	}
+
+	pods, err := client.Resource(podsGVR).Namespace(systemNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return Cluster{}, xerrors.Errorf("unable to list pods in %s: %w", systemNamespace, err)
+	}
+	seen := map[string]struct{}{}
+	for _, item := range pods.Items {
+		containers, _, _ := unstructured.NestedSlice(item.Object, "spec", "containers") // nolint: errcheck
+		for _, c := range containers {
+			container, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			image, _, _ := unstructured.NestedString(container, "image") // nolint: errcheck
+			if _, ok = seen[image]; ok || image == "" {
+				continue
+			}
+			seen[image] = struct{}{}
+
+			component := imageComponent(image)
+			if slices.Contains(controlPlaneComponents, component.Name) {
+				cluster.ControlPlane = append(cluster.ControlPlane, component)
+			} else {
+				cluster.Addons = append(cluster.Addons, component)
+			}
+		}
+	}
+
+	crds, err := client.Resource(crdsGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return Cluster{}, xerrors.Errorf("unable to list custom resource definitions: %w", err)
+	}
+	for _, item := range crds.Items {
+		crd := CRD{Name: item.GetName()}
+		crd.Group, _, _ = unstructured.NestedString(item.Object, "spec", "group")        // nolint: errcheck
+		crd.Kind, _, _ = unstructured.NestedString(item.Object, "spec", "names", "kind") // nolint: errcheck
+		versions, _, _ := unstructured.NestedSlice(item.Object, "spec", "versions")      // nolint: errcheck
+		for _, v := range versions {
+			if version, ok := v.(map[string]interface{}); ok && version["storage"] == true {
+				crd.StorageVersion, _ = version["name"].(string)
+			}
+		}
+		cluster.CRDs = append(cluster.CRDs, crd)
+	}
+
+	sortComponents(cluster.ControlPlane)
+	sortComponents(cluster.Addons)
+	sort.Slice(cluster.Nodes, func(i, j int) bool {
+		return cluster.Nodes[i].Name < cluster.Nodes[j].Name
+	})
+	sort.Slice(cluster.CRDs, func(i, j int) bool {
+		return cluster.CRDs[i].Name < cluster.CRDs[j].Name
+	})
+	return cluster, nil
+}
+
+// Writer writes the KBOM in CycloneDX
+type Writer struct {
+	Output  io.Writer
+	Version string
+	Clock   clock.Clock
+	NewUUID func() uuid.UUID
+}
+
+// NewWriter returns a writer of the KBOM
+func NewWriter(output io.Writer, version string) Writer {
+	return Writer{
+		Output:  output,
+		Version: version,
+		Clock:   clock.RealClock{},
+		NewUUID: uuid.New,
+	}
+}
+
+// Write writes the KBOM of the cluster
+func (w Writer) Write(cluster Cluster) error {
+	if err := cdx.NewBOMEncoder(w.Output, cdx.BOMFileFormatJSON).Encode(w.bom(cluster)); err != nil {
+		return xerrors.Errorf("failed to encode KBOM: %w", err)
+	}
+	return nil
+}
+
+func (w Writer) bom(cluster Cluster) *cdx.BOM {
+	bom := cdx.NewBOM()
+	bom.SerialNumber = w.NewUUID().URN()
+	bom.Metadata = &cdx.Metadata{
+		Timestamp: w.Clock.Now().UTC().Format(timeLayout),
+		Tools: &[]cdx.Tool{
+			{
+				Vendor:  "aquasecurity",
+				Name:    "trivy",
+				Version: w.Version,
+			},
+		},
+		Component: &cdx.Component{
+			BOMRef: w.NewUUID().String(),
+			Type:   cdx.ComponentTypeApplication,
+			Name:   cluster.Name,
+		},
+	}
+
+	var components []cdx.Component
+	var dependencies []cdx.Dependency
+	var clusterDependencies []cdx.Dependency
+	modules := map[string]struct{}{}
+
+	// add adds the component depending on the Go modules
+	add := func(c cdx.Component, deps []cdx.Component) {
+		c.BOMRef = w.NewUUID().String()
+		var refs []cdx.Dependency
+		for _, dep := range deps {
+			if _, ok := modules[dep.BOMRef]; !ok {
+				modules[dep.BOMRef] = struct{}{}
+				components = append(components, dep)
+			}
+			// kubelet and kube-proxy are usually built from the same version
+			if !slices.Contains(refs, cdx.Dependency{Ref: dep.BOMRef}) {
+				refs = append(refs, cdx.Dependency{Ref: dep.BOMRef})
+			}
+		}
+		if len(refs) > 0 {
+			// Libraries of the component are scanned as Go binaries
+			c.Properties = prepend(c.Properties, property(cyclonedx.PropertyType, ftypes.GoBinary))
+			dependencies = append(dependencies, cdx.Dependency{Ref: c.BOMRef, Dependencies: &refs})
+		}
+		components = append(components, c)
+		clusterDependencies = append(clusterDependencies, cdx.Dependency{Ref: c.BOMRef})
+	}
+
+	for _, c := range append(cluster.ControlPlane, cluster.Addons...) {
+		var props []cdx.Property
+		props = appendProperty(props, PropertyImage, c.Image)
+		props = appendProperty(props, PropertyNamespace, systemNamespace)
+		add(cdx.Component{
+			Type:       cdx.ComponentTypeApplication,
+			Name:       c.Name,
+			Version:    c.Version,
+			Properties: &props,
+		}, modulesOf(c.Name, c.Version))
+	}
+
+	for _, n := range cluster.Nodes {
+		var props []cdx.Property
+		props = appendProperty(props, PropertyOSImage, n.OSImage)
+		props = appendProperty(props, PropertyKernelVersion, n.KernelVersion)
+		props = appendProperty(props, PropertyArchitecture, n.Architecture)
+		props = appendProperty(props, PropertyContainerRuntime, n.ContainerRuntime)
+
+		runtimeName, runtimeVersion, _ := strings.Cut(n.ContainerRuntime, "://")
+		var deps []cdx.Component
+		deps = append(deps, modulesOf("kubelet", n.KubeletVersion)...)
+		deps = append(deps, modulesOf("kube-proxy", n.KubeProxyVersion)...)
+		deps = append(deps, modulesOf(runtimeName, runtimeVersion)...)
+		add(cdx.Component{
+			Type:       cdx.ComponentTypeDevice,
+			Name:       n.Name,
+			Version:    n.KubeletVersion,
+			Properties: &props,
+		}, deps)
+	}
+
+	for _, crd := range cluster.CRDs {
+		var props []cdx.Property
+		props = appendProperty(props, PropertyGroup, crd.Group)
+		props = appendProperty(props, PropertyKind, crd.Kind)
+		props = appendProperty(props, PropertyStorageVersion, crd.StorageVersion)
+		add(cdx.Component{
+			Type:       cdx.ComponentTypeFramework,
+			Name:       crd.Name,
+			Properties: &props,
+		}, nil)
+	}
+
+	dependencies = append(dependencies, cdx.Dependency{
+		Ref:          bom.Metadata.Component.BOMRef,
+		Dependencies: &clusterDependencies,
+	})
+
+	bom.Components = &components
+	bom.Dependencies = &dependencies
+	return bom
+}
+
+// modulesOf returns the library components of the Go module the component is built from.
+// Nothing is returned for unknown components and unknown versions.
+func modulesOf(name, version string) []cdx.Component {
+	module, ok := goModules[name]
+	if !ok {
+		return nil
+	}
+	version = moduleVersion(version)
+	if version == "" {
+		return nil
+	}
+
+	pkg := ftypes.Package{
+		Name:    module,
+		Version: version,
+	}
+	p, err := purl.NewPackageURL(ftypes.GoBinary, types.Metadata{}, pkg)
+	if err != nil {
+		return nil
+	}
+	return []cdx.Component{
+		{
+			BOMRef:     p.BOMRef(),
+			Type:       cdx.ComponentTypeLibrary,
+			Name:       module,
+			Version:    version,
+			PackageURL: p.ToString(),
+		},
+	}
+}
+
+// moduleVersion converts the version of an image tag or a node into the semantic version of the Go module.
+// e.g. "3.5.3-0" => "v3.5.3", "v1.24.7-eks-fb459a0" => "v1.24.7"
+func moduleVersion(version string) string {
+	version = strings.TrimPrefix(version, "v")
+	version, _, _ = strings.Cut(version, "-")
+	version, _, _ = strings.Cut(version, "+")
+	if version == "" || version[0] < '0' || version[0] > '9' {
+		return ""
+	}
+	return "v" + version
+}
+
+// imageComponent returns the component of the image.
+// e.g. "k8s.gcr.io/kube-apiserver:v1.24.1" => "kube-apiserver" and "v1.24.1"
+func imageComponent(image string) Component {
+	name := image
+	if i := strings.Index(name, "@"); i != -1 {
+		name = name[:i]
+	}
+
+	var version string
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, version = name[:i], name[i+1:]
+	}
+	if i := strings.LastIndex(name, "/"); i != -1 {
+		name = name[i+1:]
+	}
+
+	// e.g. "kube-apiserver-amd64"
+	for _, arch := range []string{"-amd64", "-arm64"} {
+		name = strings.TrimSuffix(name, arch)
+	}
+	return Component{
+		Name:    name,
+		Version: version,
+		Image:   image,
+	}
+}
+
+func sortComponents(components []Component) {
+	sort.Slice(components, func(i, j int) bool {
+		if components[i].Name != components[j].Name {
+			return components[i].Name < components[j].Name
+		}
+		return components[i].Version < components[j].Version
+	})
+}
+
+func appendProperty(props []cdx.Property, key, value string) []cdx.Property {
+	if value == "" {
+		return props
+	}
+	return append(props, property(key, value))
+}
+
+func prepend(props *[]cdx.Property, prop cdx.Property) *[]cdx.Property {
+	ret := []cdx.Property{prop}
+	if props != nil {
+		ret = append(ret, *props...)
+	}
+	return &ret
+}
+
+func property(key, value string) cdx.Property {
+	return cdx.Property{
+		Name:  cyclonedx.Namespace + key,
+		Value: value,
+	}
+}
@@ -0,0 +1,253 @@
+package kbom_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	fakeclock "k8s.io/utils/clock/testing"
+
+	"github.com/aquasecurity/trivy/pkg/k8s/kbom"
+)
+
+func object(apiVersion, kind, namespace, name string, fields map[string]interface{}) *unstructured.Unstructured {
+	obj := map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": namespace,
+		},
+	}
+	for k, v := range fields {
+		obj[k] = v
+	}
+	return &unstructured.Unstructured{Object: obj}
+}
+
+func pod(name string, images ...string) *unstructured.Unstructured {
+	var containers []interface{}
+	for _, image := range images {
+		containers = append(containers, map[string]interface{}{
+			"name":  name,
+			"image": image,
+		})
+	}
+	return object("v1", "Pod", "kube-system", name, map[string]interface{}{
+		"spec": map[string]interface{}{
+			"containers": containers,
+		},
+	})
+}
+
+func TestCollect(t *testing.T) {
+	objects := []runtime.Object{
+		object("v1", "Node", "", "node-1", map[string]interface{}{
+			"status": map[string]interface{}{
+				"nodeInfo": map[string]interface{}{
+					"kubeletVersion":          "v1.24.1",
+					"kubeProxyVersion":        "v1.24.1",
+					"containerRuntimeVersion": "containerd://1.6.4",
+					"osImage":                 "Ubuntu 20.04.4 LTS",
+					"kernelVersion":           "5.10.104-linuxkit",
+					"architecture":            "amd64",
+				},
+			},
+		}),
+		pod("kube-apiserver-node-1", "k8s.gcr.io/kube-apiserver:v1.24.1"),
+		pod("etcd-node-1", "k8s.gcr.io/etcd:3.5.3-0"),
+		pod("coredns-6d4b75cb6d-4x2vn", "k8s.gcr.io/coredns/coredns:v1.8.6"),
+		pod("coredns-6d4b75cb6d-9fkq2", "k8s.gcr.io/coredns/coredns:v1.8.6"),
+		pod("kindnet-8xvqz", "docker.io/kindest/kindnetd@sha256:39494477a3fa001aae716b704a8991f4f62d2ccf1aaaa65692da6c805b18856c"),
+		object("v1", "Pod", "default", "nginx", map[string]interface{}{
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "nginx", "image": "nginx:1.23"},
+				},
+			},
+		}),
+		object("apiextensions.k8s.io/v1", "CustomResourceDefinition", "", "certificates.cert-manager.io", map[string]interface{}{
+			"spec": map[string]interface{}{
+				"group": "cert-manager.io",
+				"names": map[string]interface{}{"kind": "Certificate"},
+				"versions": []interface{}{
+					map[string]interface{}{"name": "v1alpha2", "storage": false},
+					map[string]interface{}{"name": "v1", "storage": true},
+				},
+			},
+		}),
+	}
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		{Version: "v1", Resource: "nodes"}: "NodeList",
+		{Version: "v1", Resource: "pods"}:  "PodList",
+		{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}: "CustomResourceDefinitionList",
+	}, objects...)
+
+	got, err := kbom.Collect(context.Background(), "kind-kind", client)
+	require.NoError(t, err)
+
+	want := kbom.Cluster{
+		Name: "kind-kind",
+		ControlPlane: []kbom.Component{
+			{Name: "etcd", Version: "3.5.3-0", Image: "k8s.gcr.io/etcd:3.5.3-0"},
+			{Name: "kube-apiserver", Version: "v1.24.1", Image: "k8s.gcr.io/kube-apiserver:v1.24.1"},
+		},
+		Nodes: []kbom.Node{
+			{
+				Name:             "node-1",
+				KubeletVersion:   "v1.24.1",
+				KubeProxyVersion: "v1.24.1",
+				ContainerRuntime: "containerd://1.6.4",
+				OSImage:          "Ubuntu 20.04.4 LTS",
+				KernelVersion:    "5.10.104-linuxkit",
+				Architecture:     "amd64",
+			},
+		},
+		Addons: []kbom.Component{
+			{Name: "coredns", Version: "v1.8.6", Image: "k8s.gcr.io/coredns/coredns:v1.8.6"},
+			{Name: "kindnetd", Image: "docker.io/kindest/kindnetd@sha256:39494477a3fa001aae716b704a8991f4f62d2ccf1aaaa65692da6c805b18856c"},
+		},
+		CRDs: []kbom.CRD{
+			{Name: "certificates.cert-manager.io", Group: "cert-manager.io", Kind: "Certificate", StorageVersion: "v1"},
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestWriter_Write(t *testing.T) {
+	cluster := kbom.Cluster{
+		Name: "kind-kind",
+		ControlPlane: []kbom.Component{
+			{Name: "etcd", Version: "3.5.3-0", Image: "k8s.gcr.io/etcd:3.5.3-0"},
+			{Name: "kube-apiserver", Version: "v1.24.1", Image: "k8s.gcr.io/kube-apiserver:v1.24.1"},
+		},
+		Nodes: []kbom.Node{
+			{
+				Name:             "node-1",
+				KubeletVersion:   "v1.24.1",
+				KubeProxyVersion: "v1.24.1",
+				ContainerRuntime: "containerd://1.6.4",
+				OSImage:          "Ubuntu 20.04.4 LTS",
+			},
+		},
+		Addons: []kbom.Component{
+			{Name: "kindnetd", Image: "docker.io/kindest/kindnetd@sha256:39494477"},
+		},
+		CRDs: []kbom.CRD{
+			{Name: "certificates.cert-manager.io", Group: "cert-manager.io", Kind: "Certificate", StorageVersion: "v1"},
+		},
+	}
+
+	var count int
+	output := bytes.NewBuffer(nil)
+	w := kbom.NewWriter(output, "dev")
+	w.Clock = fakeclock.NewFakeClock(time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC))
+	w.NewUUID = func() uuid.UUID {
+		count++
+		return uuid.Must(uuid.Parse(fmt.Sprintf("3ff14136-e09f-4df9-80ea-%012d", count)))
+	}
+	require.NoError(t, w.Write(cluster))
+
+	var got cdx.BOM
+	require.NoError(t, json.NewDecoder(output).Decode(&got))
+
+	ref := func(n int) string {
+		return fmt.Sprintf("3ff14136-e09f-4df9-80ea-%012d", n)
+	}
+	prop := func(key, value string) cdx.Property {
+		return cdx.Property{Name: "aquasecurity:trivy:" + key, Value: value}
+	}
+	library := func(module, version string) cdx.Component {
+		purl := fmt.Sprintf("pkg:golang/%s@%s", module, version)
+		return cdx.Component{
+			BOMRef:     purl,
+			Type:       cdx.ComponentTypeLibrary,
+			Name:       module,
+			Version:    version,
+			PackageURL: purl,
+		}
+	}
+
+	assert.Equal(t, "urn:uuid:"+ref(1), got.SerialNumber)
+	assert.Equal(t, "kind-kind", got.Metadata.Component.Name)
+	assert.Equal(t, []cdx.Component{
+		library("go.etcd.io/etcd", "v3.5.3"),
+		{
+			BOMRef:  ref(3),
+			Type:    cdx.ComponentTypeApplication,
+			Name:    "etcd",
+			Version: "3.5.3-0",
+			Properties: &[]cdx.Property{
+				prop("Type", "gobinary"),
+				prop("Image", "k8s.gcr.io/etcd:3.5.3-0"),
+				prop("Namespace", "kube-system"),
+			},
+		},
+		library("k8s.io/kubernetes", "v1.24.1"),
+		{
+			BOMRef:  ref(4),
+			Type:    cdx.ComponentTypeApplication,
+			Name:    "kube-apiserver",
+			Version: "v1.24.1",
+			Properties: &[]cdx.Property{
+				prop("Type", "gobinary"),
+				prop("Image", "k8s.gcr.io/kube-apiserver:v1.24.1"),
+				prop("Namespace", "kube-system"),
+			},
+		},
+		{
+			BOMRef: ref(5),
+			Type:   cdx.ComponentTypeApplication,
+			Name:   "kindnetd",
+			Properties: &[]cdx.Property{
+				prop("Image", "docker.io/kindest/kindnetd@sha256:39494477"),
+				prop("Namespace", "kube-system"),
+			},
+		},
+		library("github.com/containerd/containerd", "v1.6.4"),
+		{
+			BOMRef:  ref(6),
+			Type:    cdx.ComponentTypeDevice,
+			Name:    "node-1",
+			Version: "v1.24.1",
+			Properties: &[]cdx.Property{
+				prop("Type", "gobinary"),
+				prop("OSImage", "Ubuntu 20.04.4 LTS"),
+				prop("ContainerRuntime", "containerd://1.6.4"),
+			},
+		},
+		{
+			BOMRef: ref(7),
+			Type:   cdx.ComponentTypeFramework,
+			Name:   "certificates.cert-manager.io",
+			Properties: &[]cdx.Property{
+				prop("Group", "cert-manager.io"),
+				prop("Kind", "Certificate"),
+				prop("StorageVersion", "v1"),
+			},
+		},
+	}, *got.Components)
+
+	assert.Equal(t, []cdx.Dependency{
+		{Ref: ref(3), Dependencies: &[]cdx.Dependency{{Ref: "pkg:golang/go.etcd.io/etcd@v3.5.3"}}},
+		{Ref: ref(4), Dependencies: &[]cdx.Dependency{{Ref: "pkg:golang/k8s.io/kubernetes@v1.24.1"}}},
+		{Ref: ref(6), Dependencies: &[]cdx.Dependency{
+			{Ref: "pkg:golang/k8s.io/kubernetes@v1.24.1"},
+			{Ref: "pkg:golang/github.com/containerd/containerd@v1.6.4"},
+		}},
+		{Ref: ref(2), Dependencies: &[]cdx.Dependency{
+			{Ref: ref(3)}, {Ref: ref(4)}, {Ref: ref(5)}, {Ref: ref(6)}, {Ref: ref(7)},
+		}},
+	}, *got.Dependencies)
+}
@@ -34,6 +34,9 @@ func (s *Scanner) Scan(ctx context.Context, artifacts []*artifacts.Artifact) (re
 	}
 	defer bar.Finish()
 
+	// The progress of analyzing each resource is not shown in addition to the progress bar of resources
+	s.opt.NoProgress, s.opt.QuietProgress = true, false
+
 	var vulns, misconfigs []report.Resource
 
 	// disable logs before scanning
@@ -42,6 +45,14 @@ func (s *Scanner) Scan(ctx context.Context, artifacts []*artifacts.Artifact) (re
 		return report.Report{}, xerrors.Errorf("logger error: %w", err)
 	}
 
+	// This is deferred before the logs are enabled below so that the warning is shown
+	var stopped bool
+	defer func() {
+		if stopped {
+			log.Logger.Warn("Findings failing the scan were detected. The remaining resources are skipped due to fail-fast.")
+		}
+	}()
+
 	// enable log, this is done in a defer function,
 	// to enable logs even when the function returns earlier
 	// due to an error
@@ -73,6 +84,11 @@ func (s *Scanner) Scan(ctx context.Context, artifacts []*artifacts.Artifact) (re
 			}
 			misconfigs = append(misconfigs, resource)
 		}
+
+		if s.opt.FailFast && (failed(vulns) || failed(misconfigs)) {
+			stopped = true
+			break
+		}
 	}
 
 	return report.Report{
@@ -83,6 +99,15 @@ func (s *Scanner) Scan(ctx context.Context, artifacts []*artifacts.Artifact) (re
 	}, nil
 }
 
+func failed(resources []report.Resource) bool {
+	for _, r := range resources {
+		if r.Results.Failed() {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *Scanner) scanVulns(ctx context.Context, artifact *artifacts.Artifact) ([]report.Resource, error) {
 	resources := make([]report.Resource, 0, len(artifact.Images))
 
@@ -0,0 +1,55 @@
+package kernel
+
+import (
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+)
+
+// ReleaseType is the type of custom resources holding the release of an installed kernel, e.g. "5.10.0-18-amd64"
+const ReleaseType = "trivy-kernel-release"
+
+var (
+	// Source packages of the kernel, e.g. "linux" of Debian and Photon OS, "linux-lts" of Alpine,
+	// "linux-aws" of Ubuntu, "kernel" of Red Hat, "kernel-default" of SUSE, "kernel-5.15" of Bottlerocket
+	// and "coreos-kernel" of Flatcar
+	kernelSrcs        = []string{"linux", "kernel", "coreos-kernel", "coreos-modules"}
+	kernelSrcPrefixes = []string{"linux-", "kernel-"}
+
+	// Source packages of firmware and CPU microcode, e.g. "firmware-nonfree" of Debian, "linux-firmware",
+	// "intel-microcode" of Debian, "microcode_ctl" of Red Hat, "ucode-intel" of SUSE and "coreos-firmware" of Flatcar
+	firmwareSrcPrefixes = []string{"firmware-", "ucode-", "microcode_ctl", "coreos-firmware"}
+	firmwareSrcSuffixes = []string{"-microcode"}
+
+	// User space packages which have the same prefixes
+	nonKernelSrcs = []string{"linux-atm", "linux-base", "linux-pam", "linux-sound-base", "kernel-srpm-macros"}
+)
+
+// IsKernelPkg returns whether the OS package is built from the source of the kernel or firmware.
+// Packages are distinguished by the source package so that the kernel headers, tools and modules are also included.
+func IsKernelPkg(pkg ftypes.Package) bool {
+	src := pkg.SrcName
+	if src == "" {
+		src = pkg.Name
+	}
+
+	switch {
+	case slices.Contains(nonKernelSrcs, src):
+		return false
+	case slices.Contains(kernelSrcs, src):
+		return true
+	}
+	for _, prefix := range append(kernelSrcPrefixes, firmwareSrcPrefixes...) {
+		if strings.HasPrefix(src, prefix) {
+			return true
+		}
+	}
+	for _, suffix := range firmwareSrcSuffixes {
+		if strings.HasSuffix(src, suffix) {
+			return true
+		}
+	}
+	return false
+}
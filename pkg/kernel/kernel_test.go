@@ -0,0 +1,109 @@
+package kernel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+)
+
+func TestIsKernelPkg(t *testing.T) {
+	tests := []struct {
+		name string
+		pkg  ftypes.Package
+		want bool
+	}{
+		{
+			name: "Debian kernel image",
+			pkg: ftypes.Package{
+				Name:    "linux-image-5.10.0-18-amd64",
+				SrcName: "linux-signed-amd64",
+			},
+			want: true,
+		},
+		{
+			name: "Debian kernel headers",
+			pkg: ftypes.Package{
+				Name:    "linux-libc-dev",
+				SrcName: "linux",
+			},
+			want: true,
+		},
+		{
+			name: "Alpine kernel",
+			pkg: ftypes.Package{
+				Name:    "linux-lts",
+				SrcName: "linux-lts",
+			},
+			want: true,
+		},
+		{
+			name: "Red Hat kernel",
+			pkg: ftypes.Package{
+				Name:    "kernel-core",
+				SrcName: "kernel",
+			},
+			want: true,
+		},
+		{
+			name: "SUSE kernel",
+			pkg: ftypes.Package{
+				Name:    "kernel-default",
+				SrcName: "kernel-default",
+			},
+			want: true,
+		},
+		{
+			name: "Flatcar kernel",
+			pkg: ftypes.Package{
+				Name: "coreos-kernel",
+			},
+			want: true,
+		},
+		{
+			name: "firmware",
+			pkg: ftypes.Package{
+				Name:    "firmware-misc-nonfree",
+				SrcName: "firmware-nonfree",
+			},
+			want: true,
+		},
+		{
+			name: "microcode",
+			pkg: ftypes.Package{
+				Name:    "intel-microcode",
+				SrcName: "intel-microcode",
+			},
+			want: true,
+		},
+		{
+			name: "without source name",
+			pkg: ftypes.Package{
+				Name: "linux-firmware",
+			},
+			want: true,
+		},
+		{
+			name: "PAM",
+			pkg: ftypes.Package{
+				Name:    "linux-pam",
+				SrcName: "linux-pam",
+			},
+			want: false,
+		},
+		{
+			name: "user space package",
+			pkg: ftypes.Package{
+				Name:    "util-linux",
+				SrcName: "util-linux",
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsKernelPkg(tt.pkg))
+		})
+	}
+}
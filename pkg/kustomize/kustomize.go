@@ -0,0 +1,246 @@
+package kustomize
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+	"sigs.k8s.io/kustomize/api/krusty"
+	ktypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/yaml"
+
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+var kustomizationFiles = []string{"kustomization.yaml", "kustomization.yml"}
+
+// kustomization is a kustomization file in the target
+type kustomization struct {
+	filePath string // e.g. overlays/prod/kustomization.yaml
+	dir      string // e.g. overlays/prod
+	content  ktypes.Kustomization
+}
+
+// Build runs "kustomize build" for the kustomizations in the files and returns the files with the build results.
+// The directory is the root of the file paths, and files referenced by the kustomizations are read from it
+// as patches and generators may not be config files.
+//
+// Only the kustomizations which are not referenced by another one, i.e. overlays, are built.
+// The resources of a build are grouped by the files they originate from,
+// and have the paths like "overlays/prod/kustomization.yaml:base/deployment.yaml" so that findings are attributed to them.
+// Generated resources have the path of the kustomization file.
+// The resources and patches consumed by the builds are not evaluated by themselves
+// since they may be modified by overlays.
+// Kustomizations which cannot be built, e.g. ones with remote bases, are left as they are.
+func Build(dir string, files []types.File) []types.File {
+	kustomizations := map[string]kustomization{}
+	for _, file := range files {
+		filePath := filepath.ToSlash(file.Path)
+		if !slices.Contains(kustomizationFiles, path.Base(filePath)) {
+			continue
+		}
+		if _, ok := kustomizations[path.Dir(filePath)]; ok {
+			continue
+		}
+
+		var k ktypes.Kustomization
+		if err := yaml.Unmarshal(file.Content, &k); err != nil {
+			log.Logger.Debugf("Unable to parse the kustomization (%s): %s", file.Path, err)
+			continue
+		}
+		k.FixKustomizationPostUnmarshalling()
+		kustomizations[path.Dir(filePath)] = kustomization{
+			filePath: filePath,
+			dir:      path.Dir(filePath),
+			content:  k,
+		}
+	}
+	if len(kustomizations) == 0 {
+		return files
+	}
+
+	// Kustomizations referenced by another one are built as part of it
+	referenced := map[string]struct{}{}
+	for _, k := range kustomizations {
+		for _, ref := range k.refs() {
+			if _, ok := kustomizations[ref]; ok {
+				referenced[ref] = struct{}{}
+			}
+		}
+	}
+
+	var dirs []string
+	for d := range kustomizations {
+		if _, ok := referenced[d]; !ok {
+			dirs = append(dirs, d)
+		}
+	}
+	sort.Strings(dirs)
+
+	consumed := map[string]struct{}{}
+	var results []types.File
+	for _, d := range dirs {
+		k := kustomizations[d]
+		built, err := build(dir, k)
+		if err != nil {
+			log.Logger.Warnf("Unable to build the kustomization (%s): %s", k.filePath, err)
+			continue
+		}
+		for _, b := range built {
+			consumed[b.source] = struct{}{}
+			filePath := k.filePath
+			if b.source != k.filePath {
+				filePath += ":" + b.source
+			}
+			results = append(results, types.File{
+				Type:    types.Kubernetes,
+				Path:    filepath.FromSlash(filePath),
+				Content: b.content,
+			})
+		}
+		for f := range k.consumed(kustomizations) {
+			consumed[f] = struct{}{}
+		}
+	}
+
+	var others []types.File
+	for _, file := range files {
+		if _, ok := consumed[filepath.ToSlash(file.Path)]; !ok {
+			others = append(others, file)
+		}
+	}
+	return append(others, results...)
+}
+
+// refs returns the paths of resources, components and patches, relative to the root
+func (k kustomization) refs() []string {
+	var refs []string
+	for _, r := range append(k.content.Resources, k.content.Components...) {
+		refs = append(refs, path.Join(k.dir, r))
+	}
+	for _, p := range k.content.PatchesStrategicMerge {
+		// Patches can be inline
+		if !strings.Contains(string(p), "\n") {
+			refs = append(refs, path.Join(k.dir, string(p)))
+		}
+	}
+	for _, p := range k.content.Patches {
+		if p.Path != "" {
+			refs = append(refs, path.Join(k.dir, p.Path))
+		}
+	}
+	return refs
+}
+
+// consumed returns the files used by the kustomization and the kustomizations it references
+func (k kustomization) consumed(kustomizations map[string]kustomization) map[string]struct{} {
+	files := map[string]struct{}{}
+	visited := map[string]struct{}{}
+
+	var walk func(kustomization)
+	walk = func(k kustomization) {
+		if _, ok := visited[k.dir]; ok {
+			return
+		}
+		visited[k.dir] = struct{}{}
+		files[k.filePath] = struct{}{}
+
+		for _, ref := range k.refs() {
+			if referenced, ok := kustomizations[ref]; ok {
+				walk(referenced)
+				continue
+			}
+			files[ref] = struct{}{}
+		}
+	}
+	walk(k)
+	return files
+}
+
+type manifest struct {
+	source  string
+	content []byte
+}
+
+// build runs the kustomization and groups the resources by the files they originate from.
+// Resources without the local origin, e.g. generated ones, are attributed to the kustomization file.
+func build(root string, k kustomization) ([]manifest, error) {
+	disk := filesys.MakeFsOnDisk()
+
+	// kustomize reads files by absolute paths with symbolic links evaluated
+	dir, _, err := disk.CleanedAbs(filepath.Join(root, filepath.FromSlash(k.dir)))
+	if err != nil {
+		return nil, xerrors.Errorf("path error: %w", err)
+	}
+	fs := originFS{
+		FileSystem: disk,
+		target:     dir.Join(path.Base(k.filePath)),
+		content:    k.content,
+	}
+
+	kustomizer := krusty.MakeKustomizer(krusty.MakeDefaultOptions())
+	resMap, err := kustomizer.Run(fs, string(dir))
+	if err != nil {
+		return nil, xerrors.Errorf("kustomize build error: %w", err)
+	}
+
+	docs := map[string][]string{}
+	var sources []string
+	for _, r := range resMap.Resources() {
+		source := k.filePath
+		origin, err := r.GetOrigin()
+		if err != nil {
+			return nil, xerrors.Errorf("origin error: %w", err)
+		}
+		if origin != nil && origin.Repo == "" && origin.Path != "" {
+			source = path.Join(k.dir, origin.Path)
+		}
+
+		// The annotation is added only for attribution
+		if err = r.SetOrigin(nil); err != nil {
+			return nil, xerrors.Errorf("origin error: %w", err)
+		}
+		b, err := r.AsYAML()
+		if err != nil {
+			return nil, xerrors.Errorf("yaml error: %w", err)
+		}
+
+		if _, ok := docs[source]; !ok {
+			sources = append(sources, source)
+		}
+		docs[source] = append(docs[source], string(b))
+	}
+
+	var manifests []manifest
+	for _, source := range sources {
+		manifests = append(manifests, manifest{
+			source:  source,
+			content: []byte(strings.Join(docs[source], "---\n")),
+		})
+	}
+	return manifests, nil
+}
+
+// originFS enables origin annotations of the target kustomization so that resources can be attributed to their files.
+type originFS struct {
+	filesys.FileSystem
+	target  string
+	content ktypes.Kustomization
+}
+
+func (fs originFS) ReadFile(name string) ([]byte, error) {
+	if filepath.Clean(name) != fs.target {
+		return fs.FileSystem.ReadFile(name)
+	}
+
+	k := fs.content
+	if !slices.Contains(k.BuildMetadata, ktypes.OriginAnnotations) {
+		k.BuildMetadata = append(slices.Clone(k.BuildMetadata), ktypes.OriginAnnotations)
+	}
+	return yaml.Marshal(k)
+}
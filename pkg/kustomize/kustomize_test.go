@@ -0,0 +1,100 @@
+package kustomize_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/kustomize"
+)
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		name string
+		dir  string
+		want []types.File
+	}{
+		{
+			name: "overlay",
+			dir:  "testdata/happy",
+			want: []types.File{
+				{
+					Type: types.Kubernetes,
+					Path: "overlays/prod/kustomization.yaml:base/deployment.yaml",
+					Content: []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: prod-app
+spec:
+  template:
+    spec:
+      containers:
+      - image: nginx:1.23.0
+        name: app
+        securityContext:
+          runAsNonRoot: true
+`),
+				},
+				{
+					Type: types.Kubernetes,
+					Path: "overlays/prod/kustomization.yaml",
+					Content: []byte(`apiVersion: v1
+data:
+  LOG_LEVEL: info
+kind: ConfigMap
+metadata:
+  name: prod-app-hf678c7m2b
+`),
+				},
+			},
+		},
+		{
+			name: "broken",
+			dir:  "testdata/broken",
+			want: []types.File{
+				{
+					Type:    types.YAML,
+					Path:    "kustomization.yaml",
+					Content: []byte("resources:\n  - missing.yaml\n"),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := kustomize.Build(tt.dir, loadFiles(t, tt.dir))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// loadFiles returns YAML files under the directory as the config analyzers pass them
+func loadFiles(t *testing.T, dir string) []types.File {
+	var files []types.File
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".yaml") {
+			return err
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, types.File{
+			Type:    types.YAML,
+			Path:    rel,
+			Content: b,
+		})
+		return nil
+	})
+	require.NoError(t, err)
+	return files
+}
@@ -0,0 +1,202 @@
+package licensing
+
+import (
+	"github.com/samber/lo"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Categories are listed from the most restrictive one.
+// A license which appears in multiple categories belongs to the first one.
+var categoryOrder = []types.LicenseCategory{
+	types.CategoryForbidden,
+	types.CategoryRestricted,
+	types.CategoryReciprocal,
+	types.CategoryNotice,
+	types.CategoryPermissive,
+	types.CategoryUnencumbered,
+}
+
+var categorySeverities = map[types.LicenseCategory]dbTypes.Severity{
+	types.CategoryForbidden:    dbTypes.SeverityCritical,
+	types.CategoryRestricted:   dbTypes.SeverityHigh,
+	types.CategoryReciprocal:   dbTypes.SeverityMedium,
+	types.CategoryNotice:       dbTypes.SeverityLow,
+	types.CategoryPermissive:   dbTypes.SeverityLow,
+	types.CategoryUnencumbered: dbTypes.SeverityLow,
+	types.CategoryUnknown:      dbTypes.SeverityUnknown,
+}
+
+// defaultCategories is based on the license classification of Google.
+// cf. https://opensource.google/documentation/reference/thirdparty/licenses
+var defaultCategories = map[types.LicenseCategory][]string{
+	types.CategoryForbidden: {
+		"AGPL-1.0",
+		"AGPL-3.0",
+		"CC-BY-NC-1.0",
+		"CC-BY-NC-2.0",
+		"CC-BY-NC-2.5",
+		"CC-BY-NC-3.0",
+		"CC-BY-NC-4.0",
+		"CC-BY-NC-ND-1.0",
+		"CC-BY-NC-ND-2.0",
+		"CC-BY-NC-ND-2.5",
+		"CC-BY-NC-ND-3.0",
+		"CC-BY-NC-ND-4.0",
+		"CC-BY-NC-SA-1.0",
+		"CC-BY-NC-SA-2.0",
+		"CC-BY-NC-SA-2.5",
+		"CC-BY-NC-SA-3.0",
+		"CC-BY-NC-SA-4.0",
+		"Commons-Clause",
+		"Facebook-2-Clause",
+		"Facebook-3-Clause",
+		"Facebook-Examples",
+		"WTFPL",
+	},
+	types.CategoryRestricted: {
+		"BCL",
+		"CC-BY-ND-1.0",
+		"CC-BY-ND-2.0",
+		"CC-BY-ND-2.5",
+		"CC-BY-ND-3.0",
+		"CC-BY-ND-4.0",
+		"CC-BY-SA-1.0",
+		"CC-BY-SA-2.0",
+		"CC-BY-SA-2.5",
+		"CC-BY-SA-3.0",
+		"CC-BY-SA-4.0",
+		"GPL-1.0",
+		"GPL-2.0",
+		"GPL-2.0-with-autoconf-exception",
+		"GPL-2.0-with-bison-exception",
+		"GPL-2.0-with-classpath-exception",
+		"GPL-2.0-with-font-exception",
+		"GPL-2.0-with-GCC-exception",
+		"GPL-3.0",
+		"GPL-3.0-with-autoconf-exception",
+		"GPL-3.0-with-GCC-exception",
+		"LGPL-2.0",
+		"LGPL-2.1",
+		"LGPL-3.0",
+		"NPL-1.0",
+		"NPL-1.1",
+		"OSL-1.0",
+		"OSL-1.1",
+		"OSL-2.0",
+		"OSL-2.1",
+		"OSL-3.0",
+		"QPL-1.0",
+		"Sleepycat",
+	},
+	types.CategoryReciprocal: {
+		"APSL-1.0",
+		"APSL-1.1",
+		"APSL-1.2",
+		"APSL-2.0",
+		"CDDL-1.0",
+		"CDDL-1.1",
+		"CPL-1.0",
+		"EPL-1.0",
+		"EPL-2.0",
+		"FreeImage",
+		"IPL-1.0",
+		"MPL-1.0",
+		"MPL-1.1",
+		"MPL-2.0",
+		"Ruby",
+	},
+	types.CategoryNotice: {
+		"AFL-1.1",
+		"AFL-1.2",
+		"AFL-2.0",
+		"AFL-2.1",
+		"AFL-3.0",
+		"Apache-1.0",
+		"Apache-1.1",
+		"Apache-2.0",
+		"Artistic-1.0-cl8",
+		"Artistic-1.0-Perl",
+		"Artistic-1.0",
+		"Artistic-2.0",
+		"BSL-1.0",
+		"BSD-2-Clause-FreeBSD",
+		"BSD-2-Clause-NetBSD",
+		"BSD-2-Clause",
+		"BSD-3-Clause-Attribution",
+		"BSD-3-Clause-Clear",
+		"BSD-3-Clause-LBNL",
+		"BSD-3-Clause",
+		"BSD-4-Clause",
+		"BSD-4-Clause-UC",
+		"BSD-Protection",
+		"CC-BY-1.0",
+		"CC-BY-2.0",
+		"CC-BY-2.5",
+		"CC-BY-3.0",
+		"CC-BY-4.0",
+		"FTL",
+		"HPND",
+		"ImageMagick",
+		"ISC",
+		"Libpng",
+		"LPL-1.0",
+		"LPL-1.02",
+		"MS-PL",
+		"MIT",
+		"NCSA",
+		"OpenSSL",
+		"PHP-3.0",
+		"PHP-3.01",
+		"PIL",
+		"Python-2.0",
+		"PostgreSQL",
+		"UPL-1.0",
+		"Unicode-DFS-2015",
+		"Unicode-DFS-2016",
+		"Unicode-TOU",
+		"W3C",
+		"W3C-19980720",
+		"W3C-20150513",
+		"X11",
+		"Xnet",
+		"Zend-2.0",
+		"ZPL-1.1",
+		"ZPL-2.0",
+		"ZPL-2.1",
+		"Zlib",
+		"zlib-acknowledgement",
+	},
+	// Users can classify licenses as permissive with "--license-allowed"
+	types.CategoryPermissive: {},
+	types.CategoryUnencumbered: {
+		"0BSD",
+		"CC0-1.0",
+		"Unlicense",
+	},
+}
+
+// Categories returns the default categories overridden by the given licenses.
+// The given licenses are removed from the other categories.
+func Categories(forbidden, restricted, allowed []string) map[types.LicenseCategory][]string {
+	overrides := map[types.LicenseCategory][]string{
+		types.CategoryForbidden:  forbidden,
+		types.CategoryRestricted: restricted,
+		types.CategoryPermissive: allowed,
+	}
+	overridden := lo.Flatten(lo.Values(overrides))
+
+	categories := map[types.LicenseCategory][]string{}
+	for category, names := range defaultCategories {
+		categories[category] = lo.Filter(names, func(name string, _ int) bool {
+			return !lo.ContainsBy(overridden, func(o string) bool {
+				return normalize(o) == normalize(name)
+			})
+		})
+	}
+	for category, names := range overrides {
+		categories[category] = append(categories[category], names...)
+	}
+	return categories
+}
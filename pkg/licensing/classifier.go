@@ -0,0 +1,192 @@
+package licensing
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// FileType is the type of custom resources holding licenses detected in files.
+// Analysis results can't hold licenses of files, so they are passed as custom resources.
+const FileType = "trivy-license-file"
+
+// textConfidence is the confidence of licenses detected by the license text
+const textConfidence = 0.9
+
+var (
+	spdxIdentifier = regexp.MustCompile(`SPDX-License-Identifier:\s*([^\r\n]+)`)
+
+	// Comment markers and whitespaces are ignored so that headers in any language can be matched
+	textSeparator = regexp.MustCompile(`[\s*/#]+`)
+)
+
+// Finding represents a license detected in a file
+type Finding struct {
+	Name       string
+	Confidence float64
+}
+
+// licenseText holds phrases identifying the license.
+// The license is detected if all the phrases in any of the groups are found.
+type licenseText struct {
+	name   string
+	groups [][]string
+}
+
+// Licenses including the others are listed first, e.g. LGPL before GPL, BSD-3-Clause before BSD-2-Clause.
+// The full text of GNU licenses is listed before the header as the text includes the header as an example.
+var licenseTexts = []licenseText{
+	{
+		name:   "AGPL-3.0-only",
+		groups: [][]string{{"GNU AFFERO GENERAL PUBLIC LICENSE Version 3"}},
+	},
+	{
+		name:   "AGPL-3.0-or-later",
+		groups: [][]string{{"GNU Affero General Public License as published by the Free Software Foundation, either version 3"}},
+	},
+	{
+		name:   "LGPL-3.0-only",
+		groups: [][]string{{"GNU LESSER GENERAL PUBLIC LICENSE Version 3"}},
+	},
+	{
+		name:   "LGPL-3.0-or-later",
+		groups: [][]string{{"GNU Lesser General Public License as published by the Free Software Foundation, either version 3"}},
+	},
+	{
+		name:   "LGPL-2.1-only",
+		groups: [][]string{{"GNU LESSER GENERAL PUBLIC LICENSE Version 2.1"}},
+	},
+	{
+		name:   "LGPL-2.1-or-later",
+		groups: [][]string{{"GNU Lesser General Public License as published by the Free Software Foundation; either version 2.1"}},
+	},
+	{
+		name:   "LGPL-2.0-only",
+		groups: [][]string{{"GNU LIBRARY GENERAL PUBLIC LICENSE Version 2"}},
+	},
+	{
+		name:   "LGPL-2.0-or-later",
+		groups: [][]string{{"GNU Library General Public License as published by the Free Software Foundation; either version 2"}},
+	},
+	{
+		name:   "GPL-3.0-only",
+		groups: [][]string{{"GNU GENERAL PUBLIC LICENSE Version 3"}},
+	},
+	{
+		name:   "GPL-3.0-or-later",
+		groups: [][]string{{"GNU General Public License as published by the Free Software Foundation, either version 3"}},
+	},
+	{
+		name:   "GPL-2.0-only",
+		groups: [][]string{{"GNU GENERAL PUBLIC LICENSE Version 2"}},
+	},
+	{
+		name:   "GPL-2.0-or-later",
+		groups: [][]string{{"GNU General Public License as published by the Free Software Foundation; either version 2"}},
+	},
+	{
+		name: "Apache-2.0",
+		groups: [][]string{
+			{"Apache License Version 2.0"},
+			{"Licensed under the Apache License, Version 2.0"},
+		},
+	},
+	{
+		name: "MPL-2.0",
+		groups: [][]string{
+			{"Mozilla Public License Version 2.0"},
+			{"Mozilla Public License, v. 2.0"},
+		},
+	},
+	{
+		name: "EPL-2.0",
+		groups: [][]string{
+			{"Eclipse Public License - v 2.0"},
+			{"Eclipse Public License v. 2.0"},
+		},
+	},
+	{
+		name: "EPL-1.0",
+		groups: [][]string{
+			{"Eclipse Public License - v 1.0"},
+			{"Eclipse Public License v1.0"},
+		},
+	},
+	{
+		name:   "BSL-1.0",
+		groups: [][]string{{"Boost Software License - Version 1.0"}},
+	},
+	{
+		name:   "CC0-1.0",
+		groups: [][]string{{"CC0 1.0 Universal"}},
+	},
+	{
+		name:   "Unlicense",
+		groups: [][]string{{"This is free and unencumbered software released into the public domain"}},
+	},
+	{
+		name:   "MIT",
+		groups: [][]string{{"Permission is hereby granted, free of charge, to any person obtaining a copy"}},
+	},
+	{
+		name: "ISC",
+		groups: [][]string{
+			{"Permission to use, copy, modify, and/or distribute this software for any purpose with or without fee is hereby granted"},
+			{"Permission to use, copy, modify, and distribute this software for any purpose with or without fee is hereby granted"},
+		},
+	},
+	{
+		name:   "BSD-4-Clause",
+		groups: [][]string{{"Redistribution and use in source and binary forms", "All advertising materials mentioning features"}},
+	},
+	{
+		name:   "BSD-3-Clause",
+		groups: [][]string{{"Redistribution and use in source and binary forms", "Neither the name"}},
+	},
+	{
+		name:   "BSD-2-Clause",
+		groups: [][]string{{"Redistribution and use in source and binary forms"}},
+	},
+	{
+		name: "Zlib",
+		groups: [][]string{
+			{"This software is provided 'as-is', without any express or implied warranty", "Altered source versions must be plainly marked"},
+		},
+	},
+}
+
+// Classify detects licenses declared with SPDX-License-Identifier or by the license text.
+// Licenses declared with SPDX-License-Identifier are normalized in the same way as package metadata.
+// Only the first license is detected by the text as license texts often refer to other licenses.
+func Classify(content []byte) []Finding {
+	var findings []Finding
+	for _, m := range spdxIdentifier.FindAllSubmatch(content, -1) {
+		expr := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(string(m[1])), "*/"))
+		findings = append(findings, Normalize(expr).Licenses...)
+	}
+
+	text := normalizeText(string(content))
+	for _, l := range licenseTexts {
+		if lo.ContainsBy(l.groups, func(phrases []string) bool {
+			return lo.EveryBy(phrases, func(phrase string) bool {
+				return strings.Contains(text, normalizeText(phrase))
+			})
+		}) {
+			findings = append(findings, Finding{
+				Name:       l.name,
+				Confidence: textConfidence,
+			})
+			break
+		}
+	}
+
+	// The same license may be declared by both
+	return lo.UniqBy(findings, func(f Finding) string {
+		return f.Name
+	})
+}
+
+func normalizeText(s string) string {
+	return strings.TrimSpace(textSeparator.ReplaceAllString(strings.ToLower(s), " "))
+}
@@ -0,0 +1,90 @@
+package licensing_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aquasecurity/trivy/pkg/licensing"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []licensing.Finding
+	}{
+		{
+			name:    "SPDX identifier",
+			content: "// SPDX-License-Identifier: MIT OR Apache-2.0\npackage main\n",
+			want: []licensing.Finding{
+				{
+					Name:       "MIT",
+					Confidence: 1,
+				},
+				{
+					Name:       "Apache-2.0",
+					Confidence: 1,
+				},
+			},
+		},
+		{
+			name:    "SPDX identifier with exception",
+			content: "/* SPDX-License-Identifier: GPL-2.0-only WITH Linux-syscall-note */\n",
+			want: []licensing.Finding{
+				{
+					Name:       "GPL-2.0-only",
+					Confidence: 1,
+				},
+			},
+		},
+		{
+			name: "license header",
+			content: `# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+`,
+			want: []licensing.Finding{
+				{
+					Name:       "Apache-2.0",
+					Confidence: 0.9,
+				},
+			},
+		},
+		{
+			name: "license text wrapped in comments",
+			content: `/*
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ */
+`,
+			want: []licensing.Finding{
+				{
+					Name:       "LGPL-3.0-or-later",
+					Confidence: 0.9,
+				},
+			},
+		},
+		{
+			name:    "same license declared by both",
+			content: "SPDX-License-Identifier: MIT\n\nPermission is hereby granted, free of charge, to any person obtaining a copy\n",
+			want: []licensing.Finding{
+				{
+					Name:       "MIT",
+					Confidence: 1,
+				},
+			},
+		},
+		{
+			name:    "no license",
+			content: "package main\n",
+			want:    []licensing.Finding{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, licensing.Classify([]byte(tt.content)))
+		})
+	}
+}
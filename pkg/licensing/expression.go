@@ -0,0 +1,132 @@
+package licensing
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/samber/lo"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// normalizedToken splits normalized expressions, e.g. "(MIT OR Apache-2.0) AND GPL-2.0 WITH Classpath-exception-2.0"
+var normalizedToken = regexp.MustCompile(`\(|\)|\s+(?:AND|OR|WITH)\s+`)
+
+// Comply returns the licenses to comply with in the expression.
+// Any branch of "OR" can be chosen, so the branch with the least severe licenses is chosen,
+// while all the licenses of "AND" are required. Licenses of unknown categories are regarded as the most severe
+// so that they are chosen only when all the branches have them. Exceptions of "WITH" don't change the category.
+func (s Scanner) Comply(expr Expression) []Finding {
+	p := &expressionParser{
+		scanner:  s,
+		tokens:   tokenize(expr.Expression),
+		findings: lo.KeyBy(expr.Licenses, func(f Finding) string { return f.Name }),
+	}
+	b, ok := p.or()
+	if !ok || p.pos != len(p.tokens) {
+		// All the licenses are required if the expression can't be parsed
+		return expr.Licenses
+	}
+	return b.licenses
+}
+
+func tokenize(expr string) []string {
+	var tokens []string
+	last := 0
+	for _, loc := range normalizedToken.FindAllStringIndex(expr, -1) {
+		if term := strings.TrimSpace(expr[last:loc[0]]); term != "" {
+			tokens = append(tokens, term)
+		}
+		tokens = append(tokens, strings.TrimSpace(expr[loc[0]:loc[1]]))
+		last = loc[1]
+	}
+	if term := strings.TrimSpace(expr[last:]); term != "" {
+		tokens = append(tokens, term)
+	}
+	return tokens
+}
+
+// branch is the licenses required by a part of the expression with the most severe rank of them
+type branch struct {
+	licenses []Finding
+	rank     int
+}
+
+// expressionParser evaluates "OR" weaker than "AND", and "AND" weaker than "WITH" as SPDX defines
+type expressionParser struct {
+	scanner  Scanner
+	tokens   []string
+	pos      int
+	findings map[string]Finding
+}
+
+func (p *expressionParser) or() (branch, bool) {
+	b, ok := p.and()
+	for ok && p.next("OR") {
+		var right branch
+		if right, ok = p.and(); ok && right.rank < b.rank {
+			b = right
+		}
+	}
+	return b, ok
+}
+
+func (p *expressionParser) and() (branch, bool) {
+	b, ok := p.term()
+	for ok && p.next("AND") {
+		var right branch
+		if right, ok = p.term(); ok {
+			b = branch{
+				licenses: lo.UniqBy(append(b.licenses, right.licenses...), func(f Finding) string { return f.Name }),
+				rank:     lo.Max([]int{b.rank, right.rank}),
+			}
+		}
+	}
+	return b, ok
+}
+
+func (p *expressionParser) term() (branch, bool) {
+	if p.next("(") {
+		b, ok := p.or()
+		return b, ok && p.next(")")
+	}
+	if p.pos >= len(p.tokens) || isOperator(p.tokens[p.pos]) || p.tokens[p.pos] == ")" {
+		return branch{}, false
+	}
+
+	name := p.tokens[p.pos]
+	p.pos++
+	if p.next("WITH") {
+		if p.pos >= len(p.tokens) {
+			return branch{}, false
+		}
+		p.pos++ // the exception
+	}
+
+	f, ok := p.findings[name]
+	if !ok {
+		f = Finding{Name: name, Confidence: unknownConfidence}
+	}
+	return branch{
+		licenses: []Finding{f},
+		rank:     p.rank(name),
+	}, true
+}
+
+func (p *expressionParser) next(token string) bool {
+	if p.pos < len(p.tokens) && p.tokens[p.pos] == token {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// rank returns the severity of the license category, where unknown ones are the most severe
+func (p *expressionParser) rank(name string) int {
+	category, _ := p.scanner.Scan(name)
+	if category == types.CategoryUnknown {
+		return int(dbTypes.SeverityCritical) + 1
+	}
+	return int(categorySeverities[category])
+}
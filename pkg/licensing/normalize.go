@@ -0,0 +1,319 @@
+package licensing
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+const (
+	// spdxConfidence is the confidence of licenses declared with SPDX identifiers
+	spdxConfidence = 1.0
+
+	// aliasConfidence is the confidence of licenses resolved from common aliases, e.g. "GPLv2+"
+	aliasConfidence = 0.9
+
+	// unknownConfidence is the confidence of licenses which are not resolved to SPDX identifiers
+	unknownConfidence = 0.5
+)
+
+var (
+	// e.g. "MIT, Apache-2.0", "GPLv2+ and LGPLv2+", "(MIT OR Apache-2.0)", "GPL-2.0 WITH Classpath-exception-2.0"
+	expressionToken = regexp.MustCompile(`(?i)\(|\)|,|;|&+|\|+|\s+and\s+|\s+or\s+|\s+with\s+`)
+
+	// e.g. "GNU General Public License v2 or later", "GPL version 3 or any later version"
+	orLater = regexp.MustCompile(`(?i)\s+or\s+(any\s+)?later(\s+version)?`)
+
+	// e.g. "Apache License, Version 2.0"
+	commaVersion = regexp.MustCompile(`(?i),\s*version\s+`)
+
+	whitespaces = regexp.MustCompile(`\s+`)
+)
+
+// extraIDs are SPDX identifiers which are not listed in the default categories
+var extraIDs = []string{
+	"AGPL-1.0-only",
+	"AGPL-1.0-or-later",
+	"AGPL-3.0-only",
+	"AGPL-3.0-or-later",
+	"GPL-1.0-only",
+	"GPL-1.0-or-later",
+	"GPL-2.0-only",
+	"GPL-2.0-or-later",
+	"GPL-3.0-only",
+	"GPL-3.0-or-later",
+	"LGPL-2.0-only",
+	"LGPL-2.0-or-later",
+	"LGPL-2.1-only",
+	"LGPL-2.1-or-later",
+	"LGPL-3.0-only",
+	"LGPL-3.0-or-later",
+	"GFDL-1.1-only",
+	"GFDL-1.2-only",
+	"GFDL-1.3-only",
+	"PSF-2.0",
+}
+
+// spdxIDs maps the lower case of SPDX identifiers to the canonical ones
+var spdxIDs = func() map[string]string {
+	ids := map[string]string{}
+	for _, names := range defaultCategories {
+		for _, name := range names {
+			ids[strings.ToLower(name)] = name
+		}
+	}
+	for _, name := range extraIDs {
+		ids[strings.ToLower(name)] = name
+	}
+	return ids
+}()
+
+// deprecatedIDs maps the deprecated GNU identifiers to the current ones
+var deprecatedIDs = map[string]string{
+	"agpl-1.0": "AGPL-1.0-only",
+	"agpl-3.0": "AGPL-3.0-only",
+	"gpl-1.0":  "GPL-1.0-only",
+	"gpl-2.0":  "GPL-2.0-only",
+	"gpl-3.0":  "GPL-3.0-only",
+	"lgpl-2.0": "LGPL-2.0-only",
+	"lgpl-2.1": "LGPL-2.1-only",
+	"lgpl-3.0": "LGPL-3.0-only",
+}
+
+// aliases maps common license names in package metadata to SPDX identifiers.
+// Keys are cleaned by cleanAlias.
+var aliases = func() map[string]string {
+	m := map[string]string{
+		"apache license":                     "Apache-2.0",
+		"apache software license":            "Apache-2.0",
+		"apache license version 2.0":         "Apache-2.0",
+		"apache software license 2.0":        "Apache-2.0",
+		"asl 2.0":                            "Apache-2.0",
+		"asl-2.0":                            "Apache-2.0",
+		"asl 1.1":                            "Apache-1.1",
+		"mit license":                        "MIT",
+		"mit/x11":                            "MIT",
+		"expat":                              "MIT",
+		"bsd 2-clause":                       "BSD-2-Clause",
+		"2-clause bsd":                       "BSD-2-Clause",
+		"simplified bsd":                     "BSD-2-Clause",
+		"freebsd":                            "BSD-2-Clause-FreeBSD",
+		"bsd 3-clause":                       "BSD-3-Clause",
+		"3-clause bsd":                       "BSD-3-Clause",
+		"new bsd":                            "BSD-3-Clause",
+		"modified bsd":                       "BSD-3-Clause",
+		"revised bsd":                        "BSD-3-Clause",
+		"isc license":                        "ISC",
+		"zlib license":                       "Zlib",
+		"zlib/libpng":                        "Zlib",
+		"boost":                              "BSL-1.0",
+		"boost software license":             "BSL-1.0",
+		"boost software license 1.0":         "BSL-1.0",
+		"artistic 2.0":                       "Artistic-2.0",
+		"artistic license 2.0":               "Artistic-2.0",
+		"ruby license":                       "Ruby",
+		"openssl license":                    "OpenSSL",
+		"php license":                        "PHP-3.01",
+		"psf":                                "PSF-2.0",
+		"psfl":                               "PSF-2.0",
+		"python software foundation":         "PSF-2.0",
+		"python software foundation license": "PSF-2.0",
+		"cc0":                                "CC0-1.0",
+		"unlicense":                          "Unlicense",
+		"wtfpl":                              "WTFPL",
+		"postgresql license":                 "PostgreSQL",
+	}
+
+	// e.g. "GPLv2", "GPL-2", "GNU GPL v2", "GNU General Public License version 2"
+	families := []struct {
+		names    []string
+		id       string
+		versions []string
+		gnu      bool
+	}{
+		{[]string{"gpl", "gnu gpl", "gnu general public license"}, "GPL", []string{"1.0", "2.0", "3.0"}, true},
+		{[]string{"lgpl", "gnu lgpl", "gnu lesser general public license", "gnu library general public license"}, "LGPL", []string{"2.0", "2.1", "3.0"}, true},
+		{[]string{"agpl", "gnu agpl", "gnu affero general public license"}, "AGPL", []string{"3.0"}, true},
+		{[]string{"mpl", "mozilla public license"}, "MPL", []string{"1.0", "1.1", "2.0"}, false},
+		{[]string{"epl", "eclipse public license"}, "EPL", []string{"1.0", "2.0"}, false},
+		{[]string{"cddl"}, "CDDL", []string{"1.0", "1.1"}, false},
+		{[]string{"apache", "apache license", "apache software license"}, "Apache", []string{"1.0", "1.1", "2.0"}, false},
+	}
+	for _, f := range families {
+		for _, v := range f.versions {
+			id := fmt.Sprintf("%s-%s", f.id, v)
+			if f.gnu {
+				id += "-only"
+			}
+			short := strings.TrimSuffix(v, ".0") // e.g. "2.0" => "2"
+			for _, name := range f.names {
+				for _, ver := range lo.Uniq([]string{v, short}) {
+					for _, format := range []string{"%s%s", "%sv%s", "%s-%s", "%s %s", "%s v%s", "%s version %s"} {
+						m[fmt.Sprintf(format, name, ver)] = id
+					}
+				}
+			}
+		}
+	}
+	return m
+}()
+
+// Expression represents a license expression normalized into SPDX identifiers
+type Expression struct {
+	// e.g. "MIT OR Apache-2.0"
+	Expression string
+
+	// Licenses in the expression with the confidence of normalization
+	Licenses []Finding
+}
+
+// Valid reports whether all the licenses in the expression are resolved to SPDX identifiers
+func (e Expression) Valid() bool {
+	return len(e.Licenses) > 0 && lo.EveryBy(e.Licenses, func(f Finding) bool {
+		return f.Confidence >= aliasConfidence
+	})
+}
+
+// Normalize normalizes the license field of package metadata into an SPDX expression.
+// Licenses separated by "," or ";" are regarded as "AND".
+// Unknown licenses are kept as they are with the low confidence.
+func Normalize(license string) Expression {
+	license = orLater.ReplaceAllString(strings.TrimSpace(license), "+")
+	license = commaVersion.ReplaceAllString(license, " version ")
+
+	var tokens []string
+	var licenses []Finding
+	var annotation int // depth of parentheses annotating the previous license, e.g. "GNU General Public License v2 (GPLv2)"
+
+	last := 0
+	for _, loc := range expressionToken.FindAllStringIndex(license, -1) {
+		term, op := strings.TrimSpace(license[last:loc[0]]), operator(license[loc[0]:loc[1]])
+		last = loc[1]
+
+		if annotation > 0 {
+			switch op {
+			case "(":
+				annotation++
+			case ")":
+				annotation--
+			}
+			continue
+		}
+
+		if term != "" {
+			tokens, licenses = appendTerm(tokens, licenses, term)
+		}
+
+		switch {
+		case op == "(" && len(tokens) > 0 && !isOperator(tokens[len(tokens)-1]) && tokens[len(tokens)-1] != "(":
+			annotation++
+		case op == "(":
+			tokens = append(tokens, op)
+		case op == ")":
+			tokens = append(trimOperators(tokens), op)
+		case len(tokens) == 0 || isOperator(tokens[len(tokens)-1]) || tokens[len(tokens)-1] == "(":
+			// Skip operators which don't follow a license, e.g. "MIT, and Apache-2.0"
+		default:
+			tokens = append(tokens, op)
+		}
+	}
+	if term := strings.TrimSpace(license[last:]); term != "" && annotation == 0 {
+		tokens, licenses = appendTerm(tokens, licenses, term)
+	}
+
+	return Expression{
+		Expression: join(trimOperators(tokens)),
+		Licenses: lo.UniqBy(licenses, func(f Finding) string {
+			return f.Name
+		}),
+	}
+}
+
+func appendTerm(tokens []string, licenses []Finding, term string) ([]string, []Finding) {
+	// Exceptions are kept as they are, e.g. "Classpath-exception-2.0"
+	if len(tokens) > 0 && tokens[len(tokens)-1] == "WITH" {
+		return append(tokens, term), licenses
+	}
+	f := normalizeLicense(term)
+	return append(tokens, f.Name), append(licenses, f)
+}
+
+func trimOperators(tokens []string) []string {
+	for len(tokens) > 0 && isOperator(tokens[len(tokens)-1]) {
+		tokens = tokens[:len(tokens)-1]
+	}
+	return tokens
+}
+
+// normalizeLicense resolves the license into the SPDX identifier
+func normalizeLicense(name string) Finding {
+	key := strings.ToLower(name)
+	plus := strings.HasSuffix(key, "+")
+	key = strings.TrimSuffix(key, "+")
+
+	id, confidence := "", 0.0
+	if s, ok := deprecatedIDs[key]; ok {
+		id, confidence = s, spdxConfidence
+	} else if s, ok = spdxIDs[key]; ok {
+		id, confidence = s, spdxConfidence
+	} else if s, ok = aliases[cleanAlias(key)]; ok {
+		id, confidence = s, aliasConfidence
+	} else {
+		return Finding{
+			Name:       name,
+			Confidence: unknownConfidence,
+		}
+	}
+
+	if plus {
+		if strings.HasSuffix(id, "-only") {
+			id = strings.TrimSuffix(id, "-only") + "-or-later"
+		} else if !strings.HasSuffix(id, "-or-later") {
+			id += "+"
+		}
+	}
+	return Finding{
+		Name:       id,
+		Confidence: confidence,
+	}
+}
+
+// cleanAlias removes differences which don't matter in license names
+func cleanAlias(name string) string {
+	name = strings.ReplaceAll(name, "licence", "license")
+	name = strings.TrimPrefix(name, "the ")
+	name = strings.TrimSuffix(name, " only")
+	return whitespaces.ReplaceAllString(strings.TrimSpace(name), " ")
+}
+
+func operator(s string) string {
+	switch op := strings.ToLower(strings.TrimSpace(s)); {
+	case op == "(" || op == ")":
+		return op
+	case op == "or" || strings.HasPrefix(op, "|"):
+		return "OR"
+	case op == "with":
+		return "WITH"
+	default:
+		// ",", ";", "&" and "and"
+		return "AND"
+	}
+}
+
+func isOperator(token string) bool {
+	return token == "AND" || token == "OR" || token == "WITH"
+}
+
+// join builds the expression from tokens, e.g. ["(", "MIT", "OR", "Apache-2.0", ")"] => "(MIT OR Apache-2.0)"
+func join(tokens []string) string {
+	var sb strings.Builder
+	for i, token := range tokens {
+		if i > 0 && token != ")" && tokens[i-1] != "(" {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(token)
+	}
+	return sb.String()
+}
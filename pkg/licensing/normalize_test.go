@@ -0,0 +1,136 @@
+package licensing_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aquasecurity/trivy/pkg/licensing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		license   string
+		want      licensing.Expression
+		wantValid bool
+	}{
+		{
+			license: "MIT",
+			want: licensing.Expression{
+				Expression: "MIT",
+				Licenses:   []licensing.Finding{{Name: "MIT", Confidence: 1}},
+			},
+			wantValid: true,
+		},
+		{
+			license: "mit",
+			want: licensing.Expression{
+				Expression: "MIT",
+				Licenses:   []licensing.Finding{{Name: "MIT", Confidence: 1}},
+			},
+			wantValid: true,
+		},
+		{
+			license: "MIT, Apache-2.0",
+			want: licensing.Expression{
+				Expression: "MIT AND Apache-2.0",
+				Licenses: []licensing.Finding{
+					{Name: "MIT", Confidence: 1},
+					{Name: "Apache-2.0", Confidence: 1},
+				},
+			},
+			wantValid: true,
+		},
+		{
+			license: "GPLv2+ and LGPLv2+",
+			want: licensing.Expression{
+				Expression: "GPL-2.0-or-later AND LGPL-2.0-or-later",
+				Licenses: []licensing.Finding{
+					{Name: "GPL-2.0-or-later", Confidence: 0.9},
+					{Name: "LGPL-2.0-or-later", Confidence: 0.9},
+				},
+			},
+			wantValid: true,
+		},
+		{
+			license: "(MIT OR Apache-2.0)",
+			want: licensing.Expression{
+				Expression: "(MIT OR Apache-2.0)",
+				Licenses: []licensing.Finding{
+					{Name: "MIT", Confidence: 1},
+					{Name: "Apache-2.0", Confidence: 1},
+				},
+			},
+			wantValid: true,
+		},
+		{
+			license: "Artistic-1.0-Perl OR GPL-1.0+",
+			want: licensing.Expression{
+				Expression: "Artistic-1.0-Perl OR GPL-1.0-or-later",
+				Licenses: []licensing.Finding{
+					{Name: "Artistic-1.0-Perl", Confidence: 1},
+					{Name: "GPL-1.0-or-later", Confidence: 1},
+				},
+			},
+			wantValid: true,
+		},
+		{
+			license: "MIT/X11 | Apache 2.0",
+			want: licensing.Expression{
+				Expression: "MIT OR Apache-2.0",
+				Licenses: []licensing.Finding{
+					{Name: "MIT", Confidence: 0.9},
+					{Name: "Apache-2.0", Confidence: 0.9},
+				},
+			},
+			wantValid: true,
+		},
+		{
+			license: "GPL-2.0 WITH Classpath-exception-2.0",
+			want: licensing.Expression{
+				Expression: "GPL-2.0-only WITH Classpath-exception-2.0",
+				Licenses:   []licensing.Finding{{Name: "GPL-2.0-only", Confidence: 1}},
+			},
+			wantValid: true,
+		},
+		{
+			license: "Apache License, Version 2.0",
+			want: licensing.Expression{
+				Expression: "Apache-2.0",
+				Licenses:   []licensing.Finding{{Name: "Apache-2.0", Confidence: 0.9}},
+			},
+			wantValid: true,
+		},
+		{
+			license: "GNU General Public License v2 or later (GPLv2+)",
+			want: licensing.Expression{
+				Expression: "GPL-2.0-or-later",
+				Licenses:   []licensing.Finding{{Name: "GPL-2.0-or-later", Confidence: 0.9}},
+			},
+			wantValid: true,
+		},
+		{
+			license: "MIT, and Proprietary",
+			want: licensing.Expression{
+				Expression: "MIT AND Proprietary",
+				Licenses: []licensing.Finding{
+					{Name: "MIT", Confidence: 1},
+					{Name: "Proprietary", Confidence: 0.5},
+				},
+			},
+			wantValid: false,
+		},
+		{
+			license:   "",
+			want:      licensing.Expression{Licenses: []licensing.Finding{}},
+			wantValid: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.license, func(t *testing.T) {
+			got := licensing.Normalize(tt.license)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantValid, got.Valid())
+		})
+	}
+}
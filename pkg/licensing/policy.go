@@ -0,0 +1,97 @@
+package licensing
+
+import (
+	"context"
+	"os"
+
+	"github.com/open-policy-agent/opa/rego"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Decision is the result of the license policy
+type Decision string
+
+const (
+	DecisionAllowed   Decision = "allowed"
+	DecisionFlagged   Decision = "flagged"
+	DecisionForbidden Decision = "forbidden"
+)
+
+// decisionCategories maps decisions to the same categories as "--license-allowed", "--license-restricted" and "--license-forbidden"
+var decisionCategories = map[Decision]types.LicenseCategory{
+	DecisionAllowed:   types.CategoryPermissive,
+	DecisionFlagged:   types.CategoryRestricted,
+	DecisionForbidden: types.CategoryForbidden,
+}
+
+// policyInput is passed to the license policy per component
+type policyInput struct {
+	types.DetectedLicense
+
+	// e.g. tools/go.mod
+	Target string
+
+	// e.g. gomod
+	Type string `json:",omitempty"`
+}
+
+// ApplyPolicy evaluates "data.trivy.license" in the policy file for each license
+// and overrides the classification with the decision.
+// The default classification is kept when the decision is undefined.
+func ApplyPolicy(ctx context.Context, policyFile string, results types.Results) error {
+	policy, err := os.ReadFile(policyFile)
+	if err != nil {
+		return xerrors.Errorf("unable to read the license policy file: %w", err)
+	}
+
+	query, err := rego.New(
+		rego.Query("data.trivy.license"),
+		rego.Module("license.rego", string(policy)),
+	).PrepareForEval(ctx)
+	if err != nil {
+		return xerrors.Errorf("unable to prepare for eval: %w", err)
+	}
+
+	for i, result := range results {
+		for j, license := range result.Licenses {
+			decision, err := evaluate(ctx, query, policyInput{
+				DetectedLicense: license,
+				Target:          result.Target,
+				Type:            result.Type,
+			})
+			if err != nil {
+				return xerrors.Errorf("license policy error (%s): %w", license.Name, err)
+			} else if decision == "" {
+				continue
+			}
+
+			category := decisionCategories[decision]
+			results[i].Licenses[j].Category = category
+			results[i].Licenses[j].Severity = categorySeverities[category].String()
+		}
+	}
+	return nil
+}
+
+func evaluate(ctx context.Context, query rego.PreparedEvalQuery, input policyInput) (Decision, error) {
+	results, err := query.Eval(ctx, rego.EvalInput(input))
+	if err != nil {
+		return "", xerrors.Errorf("unable to evaluate the policy: %w", err)
+	} else if len(results) == 0 {
+		// Handle undefined result.
+		return "", nil
+	}
+
+	s, ok := results[0].Expressions[0].Value.(string)
+	if !ok {
+		return "", xerrors.New("the policy must return a string")
+	}
+
+	decision := Decision(s)
+	if _, ok = decisionCategories[decision]; !ok {
+		return "", xerrors.Errorf("unknown decision %q, must be one of allowed, flagged and forbidden", s)
+	}
+	return decision, nil
+}
@@ -0,0 +1,128 @@
+package licensing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/licensing"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestApplyPolicy(t *testing.T) {
+	gpl := types.DetectedLicense{
+		Severity:   "HIGH",
+		Category:   types.CategoryRestricted,
+		PkgName:    "foo",
+		Name:       "GPL-3.0",
+		Confidence: 1,
+	}
+	mit := types.DetectedLicense{
+		Severity:   "LOW",
+		Category:   types.CategoryNotice,
+		PkgName:    "bar",
+		Name:       "MIT",
+		Confidence: 1,
+	}
+
+	tests := []struct {
+		name       string
+		policyFile string
+		results    types.Results
+		want       types.Results
+		wantErr    string
+	}{
+		{
+			name:       "happy path",
+			policyFile: "testdata/policy.rego",
+			results: types.Results{
+				{
+					Target:   "tools/go.mod",
+					Class:    types.ClassLicense,
+					Licenses: []types.DetectedLicense{gpl, mit},
+				},
+				{
+					Target: "go.mod",
+					Class:  types.ClassLicense,
+					Licenses: []types.DetectedLicense{
+						gpl,
+						{
+							Severity:   "UNKNOWN",
+							Category:   types.CategoryUnknown,
+							PkgName:    "baz",
+							Name:       "Proprietary",
+							Confidence: 1,
+						},
+					},
+				},
+			},
+			want: types.Results{
+				{
+					Target: "tools/go.mod",
+					Class:  types.ClassLicense,
+					Licenses: []types.DetectedLicense{
+						{
+							Severity:   "LOW",
+							Category:   types.CategoryPermissive,
+							PkgName:    "foo",
+							Name:       "GPL-3.0",
+							Confidence: 1,
+						},
+						mit, // undefined
+					},
+				},
+				{
+					Target: "go.mod",
+					Class:  types.ClassLicense,
+					Licenses: []types.DetectedLicense{
+						{
+							Severity:   "CRITICAL",
+							Category:   types.CategoryForbidden,
+							PkgName:    "foo",
+							Name:       "GPL-3.0",
+							Confidence: 1,
+						},
+						{
+							Severity:   "HIGH",
+							Category:   types.CategoryRestricted,
+							PkgName:    "baz",
+							Name:       "Proprietary",
+							Confidence: 1,
+						},
+					},
+				},
+			},
+		},
+		{
+			name:       "unknown decision",
+			policyFile: "testdata/invalid-decision.rego",
+			results: types.Results{
+				{
+					Target:   "go.mod",
+					Class:    types.ClassLicense,
+					Licenses: []types.DetectedLicense{mit},
+				},
+			},
+			wantErr: `unknown decision "ignored"`,
+		},
+		{
+			name:       "no such file",
+			policyFile: "testdata/unknown.rego",
+			wantErr:    "unable to read the license policy file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := licensing.ApplyPolicy(context.Background(), tt.policyFile, tt.results)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, tt.results)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package licensing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/samber/lo"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Scanner classifies licenses into categories
+type Scanner struct {
+	categories map[types.LicenseCategory][]string
+}
+
+// NewScanner is the factory method for Scanner.
+// The default categories are used if nil is passed.
+func NewScanner(categories map[types.LicenseCategory][]string) Scanner {
+	if categories == nil {
+		categories = defaultCategories
+	}
+	return Scanner{categories: categories}
+}
+
+// Scan returns the category and the severity of the license
+func (s Scanner) Scan(licenseName string) (types.LicenseCategory, string) {
+	name := normalize(licenseName)
+	for _, category := range categoryOrder {
+		if lo.ContainsBy(s.categories[category], func(n string) bool {
+			return normalize(n) == name
+		}) {
+			return category, categorySeverities[category].String()
+		}
+	}
+	return types.CategoryUnknown, dbTypes.SeverityUnknown.String()
+}
+
+// Link returns the URL of the license in the SPDX license list if the license is known
+func Link(licenseName string) string {
+	id, ok := spdxIDs[strings.ToLower(strings.TrimSuffix(licenseName, "+"))]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("https://spdx.org/licenses/%s.html", id)
+}
+
+// normalize removes the differences not affecting the category, e.g. "GPL-2.0+" and "gpl-2.0-only"
+func normalize(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, suffix := range []string{"+", "-or-later", "-only"} {
+		name = strings.TrimSuffix(name, suffix)
+	}
+	return name
+}
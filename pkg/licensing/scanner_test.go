@@ -0,0 +1,131 @@
+package licensing_test
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aquasecurity/trivy/pkg/licensing"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestScanner_Scan(t *testing.T) {
+	tests := []struct {
+		name         string
+		categories   map[types.LicenseCategory][]string
+		licenseName  string
+		wantCategory types.LicenseCategory
+		wantSeverity string
+	}{
+		{
+			name:         "forbidden",
+			licenseName:  "AGPL-3.0",
+			wantCategory: types.CategoryForbidden,
+			wantSeverity: "CRITICAL",
+		},
+		{
+			name:         "restricted with suffix",
+			licenseName:  "GPL-2.0-or-later",
+			wantCategory: types.CategoryRestricted,
+			wantSeverity: "HIGH",
+		},
+		{
+			name:         "notice in lower case",
+			licenseName:  "apache-2.0",
+			wantCategory: types.CategoryNotice,
+			wantSeverity: "LOW",
+		},
+		{
+			name:         "unknown",
+			licenseName:  "Proprietary",
+			wantCategory: types.CategoryUnknown,
+			wantSeverity: "UNKNOWN",
+		},
+		{
+			name:         "forbidden by users",
+			categories:   licensing.Categories([]string{"MIT"}, nil, nil),
+			licenseName:  "MIT",
+			wantCategory: types.CategoryForbidden,
+			wantSeverity: "CRITICAL",
+		},
+		{
+			name:         "allowed by users",
+			categories:   licensing.Categories(nil, nil, []string{"GPL-3.0"}),
+			licenseName:  "GPL-3.0",
+			wantCategory: types.CategoryPermissive,
+			wantSeverity: "LOW",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := licensing.NewScanner(tt.categories)
+			gotCategory, gotSeverity := s.Scan(tt.licenseName)
+			assert.Equal(t, tt.wantCategory, gotCategory)
+			assert.Equal(t, tt.wantSeverity, gotSeverity)
+		})
+	}
+}
+
+func TestScanner_Comply(t *testing.T) {
+	tests := []struct {
+		name       string
+		categories map[types.LicenseCategory][]string
+		license    string
+		want       []string
+	}{
+		{
+			name:    "single",
+			license: "GPL-3.0-only",
+			want:    []string{"GPL-3.0-only"},
+		},
+		{
+			name:    "OR chooses the least severe",
+			license: "MIT OR GPL-3.0-only",
+			want:    []string{"MIT"},
+		},
+		{
+			name:    "OR with the restricted first",
+			license: "GPL-3.0-only OR MIT",
+			want:    []string{"MIT"},
+		},
+		{
+			name:    "AND requires all",
+			license: "MIT AND GPL-3.0-only",
+			want:    []string{"MIT", "GPL-3.0-only"},
+		},
+		{
+			name:    "AND takes precedence over OR",
+			license: "MIT AND GPL-3.0-only OR Apache-2.0",
+			want:    []string{"Apache-2.0"},
+		},
+		{
+			name:    "parentheses",
+			license: "MIT AND (GPL-3.0-only OR Apache-2.0)",
+			want:    []string{"MIT", "Apache-2.0"},
+		},
+		{
+			name:    "WITH",
+			license: "GPL-2.0-only WITH Classpath-exception-2.0 OR AGPL-3.0",
+			want:    []string{"GPL-2.0-only"},
+		},
+		{
+			name:    "unknown is chosen last",
+			license: "Proprietary OR GPL-3.0-only",
+			want:    []string{"GPL-3.0-only"},
+		},
+		{
+			name:       "forbidden by users",
+			categories: licensing.Categories([]string{"MIT"}, nil, nil),
+			license:    "MIT OR GPL-3.0-only",
+			want:       []string{"GPL-3.0-only"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := licensing.NewScanner(tt.categories)
+			got := s.Comply(licensing.Normalize(tt.license))
+			assert.Equal(t, tt.want, lo.Map(got, func(f licensing.Finding, _ int) string { return f.Name }))
+		})
+	}
+}
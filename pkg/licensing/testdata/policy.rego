@@ -0,0 +1,16 @@
+package trivy
+
+# GPL is allowed only in build tools which are not shipped
+license = "allowed" {
+	startswith(input.Name, "GPL")
+	startswith(input.Target, "tools/")
+}
+
+license = "forbidden" {
+	startswith(input.Name, "GPL")
+	not startswith(input.Target, "tools/")
+}
+
+license = "flagged" {
+	input.Category == "unknown"
+}
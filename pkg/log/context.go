@@ -0,0 +1,42 @@
+package log
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ScanIDHeader is the HTTP header propagating scan IDs from clients to the server,
+// so that the server logs can be correlated with the client requests.
+const ScanIDHeader = "Trivy-Scan-Id"
+
+// scanIDKey is the key of scan IDs in structured logs
+const scanIDKey = "ScanID"
+
+type scanIDContextKey struct{}
+
+// NewScanID returns a new scan ID
+func NewScanID() string {
+	return uuid.NewString()
+}
+
+// WithScanID returns a copy of the context carrying the scan ID
+func WithScanID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, scanIDContextKey{}, id)
+}
+
+// ScanID returns the scan ID in the context, or an empty string
+func ScanID(ctx context.Context) string {
+	id, _ := ctx.Value(scanIDContextKey{}).(string)
+	return id
+}
+
+// WithContext returns the logger attaching the scan ID in the context to the logs.
+// The global logger is returned as is if it already has the scan ID, e.g. clients and servers in the same process.
+func WithContext(ctx context.Context) *zap.SugaredLogger {
+	if id := ScanID(ctx); id != "" && id != scanID {
+		return Logger.With(scanIDKey, id)
+	}
+	return Logger
+}
@@ -11,10 +11,18 @@ import (
 	dlog "github.com/aquasecurity/go-dep-parser/pkg/log"
 )
 
+// Log formats
+const (
+	FormatConsole = "console"
+	FormatJSON    = "json"
+)
+
 var (
 	// Logger is the global variable for logging
 	Logger      *zap.SugaredLogger
 	debugOption bool
+	format      = FormatConsole
+	scanID      string
 )
 
 func init() {
@@ -22,6 +30,21 @@ func init() {
 	Logger, _ = NewLogger(false, false) // nolint: errcheck
 }
 
+// SetFormat sets the format of loggers created afterwards
+func SetFormat(f string) error {
+	switch f {
+	case FormatConsole, FormatJSON:
+		format = f
+		return nil
+	}
+	return xerrors.Errorf("unknown log format: %s", f)
+}
+
+// SetScanID sets the scan ID attached to the logs of the logger initialized afterwards by InitLogger
+func SetScanID(id string) {
+	scanID = id
+}
+
 // InitLogger initialize the logger variable
 func InitLogger(debug, disable bool) (err error) {
 	debugOption = debug
@@ -29,6 +52,9 @@ func InitLogger(debug, disable bool) (err error) {
 	if err != nil {
 		return xerrors.Errorf("failed to initialize a logger: %w", err)
 	}
+	if scanID != "" {
+		Logger = Logger.With(scanIDKey, scanID)
+	}
 
 	// Set logger for go-dep-parser
 	dlog.SetLogger(Logger)
@@ -68,6 +94,11 @@ func NewLogger(debug, disable bool) (*zap.SugaredLogger, error) {
 	}
 
 	consoleEncoder := zapcore.NewConsoleEncoder(encoderConfig)
+	if format == FormatJSON {
+		// Colors are not wanted by log collectors
+		encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+		consoleEncoder = zapcore.NewJSONEncoder(encoderConfig)
+	}
 
 	// High-priority output should also go to standard error, and low-priority
 	// output should also go to standard out.
@@ -0,0 +1,77 @@
+package lookup
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy/pkg/detector/library"
+	ospkgDetector "github.com/aquasecurity/trivy/pkg/detector/ospkg"
+	"github.com/aquasecurity/trivy/pkg/purl"
+	"github.com/aquasecurity/trivy/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/vulnerability"
+)
+
+// Lookup returns the vulnerabilities affecting the package identified by the reference.
+// The reference must be a Package URL, e.g. "pkg:npm/lodash@4.17.20".
+// OS packages need the "distro" qualifier, e.g. "pkg:deb/debian/openssl@1.1.1n-0+deb11u1?distro=debian-11".
+// The vulnerability DB must be initialized beforehand.
+func Lookup(ref string) (types.Result, error) {
+	// The vulnerability DB is indexed by package ecosystems, not by CPE
+	if strings.HasPrefix(ref, "cpe:") {
+		return types.Result{}, xerrors.Errorf("CPE is not supported by the vulnerability DB, use a Package URL instead: %s", ref)
+	}
+
+	p, err := purl.FromString(ref)
+	if err != nil {
+		return types.Result{}, xerrors.Errorf("invalid package reference: %w", err)
+	}
+	if p.Version == "" {
+		return types.Result{}, xerrors.Errorf("the package version must be specified: %s", ref)
+	}
+
+	pkg := *p.Package()
+	result := types.Result{
+		Target:   ref,
+		Packages: []ftypes.Package{pkg},
+	}
+
+	var vulns []types.DetectedVulnerability
+	switch {
+	case p.IsOSPkg():
+		fos := p.OS()
+		if fos == nil {
+			return types.Result{}, xerrors.Errorf(`the "distro" qualifier must be specified for OS packages: %s`, ref)
+		}
+		// Advisories of OS packages are stored by source package names
+		if pkg.SrcName == "" {
+			pkg.SrcName = pkg.Name
+			pkg.SrcVersion = pkg.Version
+			pkg.SrcRelease = pkg.Release
+			pkg.SrcEpoch = pkg.Epoch
+		}
+		vulns, _, err = ospkgDetector.Detector{}.Detect("", fos.Family, fos.Name, nil, time.Time{}, []ftypes.Package{pkg})
+		if err != nil {
+			return types.Result{}, xerrors.Errorf("OS package detection error: %w", err)
+		}
+		result.Class = types.ClassOSPkg
+		result.Type = fos.Family
+	case p.AppType() != "":
+		vulns, err = library.Detect(context.Background(), p.AppType(), []ftypes.Package{pkg})
+		if err != nil {
+			return types.Result{}, xerrors.Errorf("library detection error: %w", err)
+		}
+		result.Class = types.ClassLangPkg
+		result.Type = p.AppType()
+	default:
+		return types.Result{}, xerrors.Errorf("unsupported package type: %s", p.Type)
+	}
+
+	vulnerability.NewClient(db.Config{}).FillInfo(vulns)
+	result.Vulnerabilities = vulns
+	return result, nil
+}
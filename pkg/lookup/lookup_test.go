@@ -0,0 +1,131 @@
+package lookup_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/dbtest"
+	"github.com/aquasecurity/trivy/pkg/lookup"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name    string
+		ref     string
+		want    types.Result
+		wantErr string
+	}{
+		{
+			name: "npm",
+			ref:  "pkg:npm/lodash@4.17.20",
+			want: types.Result{
+				Target: "pkg:npm/lodash@4.17.20",
+				Class:  types.ClassLangPkg,
+				Type:   ftypes.NodePkg,
+				Packages: []ftypes.Package{
+					{Name: "lodash", Version: "4.17.20"},
+				},
+				Vulnerabilities: []types.DetectedVulnerability{
+					{
+						VulnerabilityID:  "CVE-2021-23337",
+						PkgName:          "lodash",
+						InstalledVersion: "4.17.20",
+						FixedVersion:     "4.17.21",
+						PrimaryURL:       "https://avd.aquasec.com/nvd/cve-2021-23337",
+						DataSource: &dbTypes.DataSource{
+							ID:   "ghsa",
+							Name: "GitHub Security Advisory npm",
+							URL:  "https://github.com/advisories?query=type%3Areviewed+ecosystem%3Anpm",
+						},
+						Vulnerability: dbTypes.Vulnerability{
+							Title:    "nodejs-lodash: command injection via template",
+							Severity: "HIGH",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "not affected",
+			ref:  "pkg:npm/lodash@4.17.21",
+			want: types.Result{
+				Target: "pkg:npm/lodash@4.17.21",
+				Class:  types.ClassLangPkg,
+				Type:   ftypes.NodePkg,
+				Packages: []ftypes.Package{
+					{Name: "lodash", Version: "4.17.21"},
+				},
+			},
+		},
+		{
+			name: "debian",
+			ref:  "pkg:deb/debian/openssl@1.1.1n-0+deb11u1?distro=debian-11",
+			want: types.Result{
+				Target: "pkg:deb/debian/openssl@1.1.1n-0+deb11u1?distro=debian-11",
+				Class:  types.ClassOSPkg,
+				Type:   "debian",
+				Packages: []ftypes.Package{
+					{Name: "openssl", Version: "1.1.1n-0+deb11u1"},
+				},
+				Vulnerabilities: []types.DetectedVulnerability{
+					{
+						VulnerabilityID:  "CVE-2022-2068",
+						PkgName:          "openssl",
+						InstalledVersion: "1.1.1n-0+deb11u1",
+						FixedVersion:     "1.1.1n-0+deb11u3",
+						PrimaryURL:       "https://avd.aquasec.com/nvd/cve-2022-2068",
+						Vulnerability: dbTypes.Vulnerability{
+							Title:    "openssl: the c_rehash script allows command injection",
+							Severity: "CRITICAL",
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "OS package without distro",
+			ref:     "pkg:deb/debian/openssl@1.1.1n-0+deb11u1",
+			wantErr: `the "distro" qualifier must be specified`,
+		},
+		{
+			name:    "no version",
+			ref:     "pkg:npm/lodash",
+			wantErr: "the package version must be specified",
+		},
+		{
+			name:    "CPE",
+			ref:     "cpe:2.3:a:lodash:lodash:4.17.20:*:*:*:*:node.js:*:*",
+			wantErr: "CPE is not supported",
+		},
+		{
+			name:    "unsupported type",
+			ref:     "pkg:generic/foo@1.0.0",
+			wantErr: "unsupported package type: generic",
+		},
+	}
+
+	_ = dbtest.InitDB(t, []string{
+		"testdata/fixtures/advisories.yaml",
+		"testdata/fixtures/vulnerability.yaml",
+	})
+	defer db.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := lookup.Lookup(tt.ref)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
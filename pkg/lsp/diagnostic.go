@@ -0,0 +1,163 @@
+package lsp
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const source = "trivy"
+
+// Diagnostics converts the findings in the results to diagnostics grouped by the file path.
+// The targets of the results are relative to dir.
+func Diagnostics(dir string, results types.Results) map[string][]Diagnostic {
+	diagnostics := map[string][]Diagnostic{}
+	for _, result := range results {
+		filePath := filepath.Join(dir, filepath.FromSlash(result.Target))
+
+		var lines []string
+		if len(result.Vulnerabilities) > 0 {
+			// Packages don't have positions, so they are looked up in the file
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				log.Logger.Debugf("Unable to read %s: %s", filePath, err)
+			}
+			lines = strings.Split(string(bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))), "\n")
+		}
+
+		for _, vuln := range result.Vulnerabilities {
+			message := fmt.Sprintf("%s@%s: %s", vuln.PkgName, vuln.InstalledVersion, vuln.VulnerabilityID)
+			if vuln.Title != "" {
+				message += " " + vuln.Title
+			}
+			if vuln.FixedVersion != "" {
+				message += fmt.Sprintf(" (fixed version: %s)", vuln.FixedVersion)
+			}
+			diagnostics[filePath] = append(diagnostics[filePath], Diagnostic{
+				Range:           packageRange(lines, vuln.PkgName, vuln.InstalledVersion),
+				Severity:        severity(vuln.Severity),
+				Code:            vuln.VulnerabilityID,
+				CodeDescription: codeDescription(vuln.PrimaryURL),
+				Source:          source,
+				Message:         message,
+			})
+		}
+
+		for _, misconf := range result.Misconfigurations {
+			if misconf.Status != types.StatusFailure {
+				continue
+			}
+			diagnostics[filePath] = append(diagnostics[filePath], Diagnostic{
+				Range:           lineRange(misconf.CauseMetadata.StartLine, misconf.CauseMetadata.EndLine),
+				Severity:        severity(misconf.Severity),
+				Code:            misconf.ID,
+				CodeDescription: codeDescription(misconf.PrimaryURL),
+				Source:          source,
+				Message:         fmt.Sprintf("%s: %s", misconf.Title, misconf.Message),
+			})
+		}
+
+		for _, secret := range result.Secrets {
+			diagnostics[filePath] = append(diagnostics[filePath], Diagnostic{
+				Range:    lineRange(secret.StartLine, secret.EndLine),
+				Severity: severity(secret.Severity),
+				Code:     secret.RuleID,
+				Source:   source,
+				Message:  fmt.Sprintf("Secret: %s", secret.Title),
+			})
+		}
+	}
+	return diagnostics
+}
+
+// packageRange returns the line declaring the package, preferably with the version.
+// Lines only mentioning the package, e.g. download URLs in lock files, are used if no declaration is found.
+// The first line is returned if not found, e.g. the package is a transitive dependency in a manifest.
+func packageRange(lines []string, name, version string) Range {
+	found, rank := -1, 0
+	for i, line := range lines {
+		if !strings.Contains(line, name) {
+			continue
+		}
+		r := 1
+		if declares(line, name) {
+			r += 2
+		}
+		if version != "" && strings.Contains(line, version) {
+			r++
+		}
+		if r > rank {
+			found, rank = i, r
+		}
+	}
+	if found < 0 {
+		return lineRange(0, 0)
+	}
+
+	line := lines[found]
+	start := len(line) - len(strings.TrimLeft(line, " \t"))
+	return Range{
+		Start: Position{Line: found, Character: start},
+		End:   Position{Line: found, Character: len(strings.TrimRight(line, " \t"))},
+	}
+}
+
+// declares reports whether the line declares the package, e.g. "flask==2.2.2", `"lodash": {` and "<artifactId>jackson</artifactId>"
+func declares(line, name string) bool {
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, name) && (len(trimmed) == len(name) || !isNameChar(trimmed[len(name)])) {
+		return true
+	}
+	for _, quote := range []string{`"%s"`, `'%s'`, `>%s<`} {
+		if strings.Contains(line, fmt.Sprintf(quote, name)) {
+			return true
+		}
+	}
+	return false
+}
+
+func isNameChar(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte("-_./", c) >= 0
+}
+
+// lineRange converts one-based lines to the range covering the whole lines.
+// The first line is returned if the lines are unknown.
+func lineRange(startLine, endLine int) Range {
+	if startLine <= 0 {
+		startLine = 1
+	}
+	if endLine < startLine {
+		endLine = startLine
+	}
+	// The end position is exclusive, so it is at the beginning of the next line
+	return Range{
+		Start: Position{Line: startLine - 1},
+		End:   Position{Line: endLine},
+	}
+}
+
+// severity converts the severity of Trivy to the diagnostic severity
+func severity(s string) int {
+	switch s {
+	case "CRITICAL", "HIGH":
+		return SeverityError
+	case "MEDIUM":
+		return SeverityWarning
+	case "LOW":
+		return SeverityInformation
+	default:
+		return SeverityHint
+	}
+}
+
+func codeDescription(url string) *CodeDescription {
+	if url == "" {
+		return nil
+	}
+	return &CodeDescription{Href: url}
+}
@@ -0,0 +1,196 @@
+package lsp_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/lsp"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestDiagnostics(t *testing.T) {
+	dir := "testdata"
+	tests := []struct {
+		name    string
+		results types.Results
+		want    map[string][]lsp.Diagnostic
+	}{
+		{
+			name: "vulnerability with the version on another line",
+			results: types.Results{
+				{
+					Target: "package-lock.json",
+					Vulnerabilities: []types.DetectedVulnerability{
+						{
+							VulnerabilityID:  "CVE-2021-23337",
+							PkgName:          "lodash",
+							InstalledVersion: "4.17.4",
+							FixedVersion:     "4.17.21",
+							PrimaryURL:       "https://avd.aquasec.com/nvd/cve-2021-23337",
+							Vulnerability: dbTypes.Vulnerability{
+								Title:    "nodejs-lodash: command injection via template",
+								Severity: "HIGH",
+							},
+						},
+					},
+				},
+			},
+			want: map[string][]lsp.Diagnostic{
+				filepath.Join(dir, "package-lock.json"): {
+					{
+						Range: lsp.Range{
+							Start: lsp.Position{Line: 9, Character: 4},
+							End:   lsp.Position{Line: 9, Character: 15},
+						},
+						Severity:        lsp.SeverityError,
+						Code:            "CVE-2021-23337",
+						CodeDescription: &lsp.CodeDescription{Href: "https://avd.aquasec.com/nvd/cve-2021-23337"},
+						Source:          "trivy",
+						Message:         "lodash@4.17.4: CVE-2021-23337 nodejs-lodash: command injection via template (fixed version: 4.17.21)",
+					},
+				},
+			},
+		},
+		{
+			name: "vulnerability with the version on the same line",
+			results: types.Results{
+				{
+					Target: "requirements.txt",
+					Vulnerabilities: []types.DetectedVulnerability{
+						{
+							VulnerabilityID:  "CVE-2019-6975",
+							PkgName:          "django",
+							InstalledVersion: "2.0.9",
+							Vulnerability: dbTypes.Vulnerability{
+								Severity: "MEDIUM",
+							},
+						},
+						{
+							VulnerabilityID:  "CVE-2022-0001",
+							PkgName:          "werkzeug",
+							InstalledVersion: "0.15.0",
+						},
+					},
+				},
+			},
+			want: map[string][]lsp.Diagnostic{
+				filepath.Join(dir, "requirements.txt"): {
+					{
+						Range: lsp.Range{
+							Start: lsp.Position{Line: 2, Character: 2},
+							End:   lsp.Position{Line: 2, Character: 15},
+						},
+						Severity: lsp.SeverityWarning,
+						Code:     "CVE-2019-6975",
+						Source:   "trivy",
+						Message:  "django@2.0.9: CVE-2019-6975",
+					},
+					{
+						// Not found in the file
+						Range: lsp.Range{
+							Start: lsp.Position{Line: 0},
+							End:   lsp.Position{Line: 1},
+						},
+						Severity: lsp.SeverityHint,
+						Code:     "CVE-2022-0001",
+						Source:   "trivy",
+						Message:  "werkzeug@0.15.0: CVE-2022-0001",
+					},
+				},
+			},
+		},
+		{
+			name: "misconfigurations and secrets",
+			results: types.Results{
+				{
+					Target: "Dockerfile",
+					Misconfigurations: []types.DetectedMisconfiguration{
+						{
+							ID:       "DS002",
+							Title:    "Image user should not be 'root'",
+							Message:  "Specify at least 1 USER command in Dockerfile with non-root user as argument",
+							Severity: "HIGH",
+							Status:   types.StatusFailure,
+						},
+						{
+							ID:       "DS001",
+							Severity: "MEDIUM",
+							Status:   types.StatusPassed,
+						},
+						{
+							ID:       "DS005",
+							Title:    "ADD instead of COPY",
+							Message:  "Consider using 'COPY . /app' command instead of 'ADD . /app'",
+							Severity: "LOW",
+							Status:   types.StatusFailure,
+							CauseMetadata: ftypes.CauseMetadata{
+								StartLine: 3,
+								EndLine:   4,
+							},
+						},
+					},
+				},
+				{
+					Target: "app/.env",
+					Secrets: []types.DetectedSecret{
+						{
+							SecretFinding: ftypes.SecretFinding{
+								RuleID:    "aws-access-key-id",
+								Title:     "AWS Access Key ID",
+								Severity:  "CRITICAL",
+								StartLine: 2,
+								EndLine:   2,
+							},
+						},
+					},
+				},
+			},
+			want: map[string][]lsp.Diagnostic{
+				filepath.Join(dir, "Dockerfile"): {
+					{
+						Range: lsp.Range{
+							Start: lsp.Position{Line: 0},
+							End:   lsp.Position{Line: 1},
+						},
+						Severity: lsp.SeverityError,
+						Code:     "DS002",
+						Source:   "trivy",
+						Message:  "Image user should not be 'root': Specify at least 1 USER command in Dockerfile with non-root user as argument",
+					},
+					{
+						Range: lsp.Range{
+							Start: lsp.Position{Line: 2},
+							End:   lsp.Position{Line: 4},
+						},
+						Severity: lsp.SeverityInformation,
+						Code:     "DS005",
+						Source:   "trivy",
+						Message:  "ADD instead of COPY: Consider using 'COPY . /app' command instead of 'ADD . /app'",
+					},
+				},
+				filepath.Join(dir, "app", ".env"): {
+					{
+						Range: lsp.Range{
+							Start: lsp.Position{Line: 1},
+							End:   lsp.Position{Line: 2},
+						},
+						Severity: lsp.SeverityError,
+						Code:     "aws-access-key-id",
+						Source:   "trivy",
+						Message:  "Secret: AWS Access Key ID",
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := lsp.Diagnostics(dir, tt.results)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Methods handled by the server
+const (
+	methodInitialize         = "initialize"
+	methodInitialized        = "initialized"
+	methodShutdown           = "shutdown"
+	methodExit               = "exit"
+	methodDidOpen            = "textDocument/didOpen"
+	methodDidSave            = "textDocument/didSave"
+	methodDidClose           = "textDocument/didClose"
+	methodPublishDiagnostics = "textDocument/publishDiagnostics"
+	methodLogMessage         = "window/logMessage"
+
+	// MethodScan scans the file on demand and returns the diagnostics without publishing them
+	MethodScan = "trivy/scan"
+)
+
+// messageTypeError is the type of "window/logMessage" for errors
+const messageTypeError = 1
+
+// ScanFunc scans the file and returns the results whose targets are relative to the directory of the file.
+// The manifests and lock files related to the file may be scanned together.
+type ScanFunc func(ctx context.Context, filePath string) (types.Results, error)
+
+// Server is a language server publishing the findings in files as diagnostics.
+// Messages are handled one by one, so that scans share the DB and the cache of the process without contention.
+type Server struct {
+	in      *bufio.Reader
+	out     io.Writer
+	version string
+	scan    ScanFunc
+
+	shutdown bool
+
+	// published holds the URIs with diagnostics published for each opened document,
+	// so that stale diagnostics are cleared on rescans and when the document is closed.
+	published map[string][]string
+}
+
+// NewServer returns a language server reading messages from in and writing messages to out
+func NewServer(in io.Reader, out io.Writer, version string, scan ScanFunc) *Server {
+	return &Server{
+		in:        bufio.NewReader(in),
+		out:       out,
+		version:   version,
+		scan:      scan,
+		published: map[string][]string{},
+	}
+}
+
+// Serve handles messages until "exit" is received, the input is closed or the context is canceled
+func (s *Server) Serve(ctx context.Context) error {
+	for ctx.Err() == nil {
+		content, err := readMessage(s.in)
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return xerrors.Errorf("read error: %w", err)
+		}
+
+		var req request
+		if err = json.Unmarshal(content, &req); err != nil {
+			if err = s.reply(nil, nil, &responseError{Code: codeParseError, Message: err.Error()}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if req.Method == methodExit {
+			if !s.shutdown {
+				return xerrors.New("exit without shutdown")
+			}
+			return nil
+		}
+
+		result, respErr := s.handle(ctx, req)
+		if req.ID == nil {
+			// Notifications have no response
+			continue
+		}
+		if err = s.reply(req.ID, result, respErr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) handle(ctx context.Context, req request) (interface{}, *responseError) {
+	if s.shutdown {
+		return nil, &responseError{Code: codeInvalidRequest, Message: "server is shut down"}
+	}
+
+	switch req.Method {
+	case methodInitialize:
+		return initializeResult{
+			Capabilities: serverCapabilities{
+				TextDocumentSync: textDocumentSyncOptions{
+					OpenClose: true,
+					Save:      true,
+				},
+			},
+			ServerInfo: serverInfo{
+				Name:    "trivy",
+				Version: s.version,
+			},
+		}, nil
+	case methodInitialized:
+		return nil, nil
+	case methodShutdown:
+		s.shutdown = true
+		return nil, nil
+	case methodDidOpen, methodDidSave:
+		var params textDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &responseError{Code: codeInvalidParams, Message: err.Error()}
+		}
+		if err := s.publish(ctx, params.TextDocument.URI); err != nil {
+			log.Logger.Errorf("Unable to scan %s: %s", params.TextDocument.URI, err)
+			s.notify(methodLogMessage, map[string]interface{}{
+				"type":    messageTypeError,
+				"message": "trivy: " + err.Error(),
+			})
+		}
+		return nil, nil
+	case methodDidClose:
+		var params textDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &responseError{Code: codeInvalidParams, Message: err.Error()}
+		}
+		for _, uri := range s.published[params.TextDocument.URI] {
+			s.notify(methodPublishDiagnostics, PublishDiagnosticsParams{URI: uri, Diagnostics: []Diagnostic{}})
+		}
+		delete(s.published, params.TextDocument.URI)
+		return nil, nil
+	case MethodScan:
+		var params textDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &responseError{Code: codeInvalidParams, Message: err.Error()}
+		}
+		diagnostics, err := s.diagnose(ctx, params.TextDocument.URI)
+		if err != nil {
+			return nil, &responseError{Code: codeInternalError, Message: err.Error()}
+		}
+		return diagnostics, nil
+	}
+
+	return nil, &responseError{Code: codeMethodNotFound, Message: "method not found: " + req.Method}
+}
+
+// publish scans the document and publishes the diagnostics.
+// Diagnostics published by the previous scan of the document are cleared if the findings are gone.
+func (s *Server) publish(ctx context.Context, uri string) error {
+	diagnostics, err := s.diagnose(ctx, uri)
+	if err != nil {
+		return err
+	}
+
+	var uris []string
+	for _, d := range diagnostics {
+		s.notify(methodPublishDiagnostics, d)
+		uris = append(uris, d.URI)
+	}
+	for _, old := range s.published[uri] {
+		if !slices.Contains(uris, old) {
+			s.notify(methodPublishDiagnostics, PublishDiagnosticsParams{URI: old, Diagnostics: []Diagnostic{}})
+		}
+	}
+	s.published[uri] = uris
+	return nil
+}
+
+// diagnose scans the document and returns the diagnostics sorted by the URI.
+// The document itself is always included so that its diagnostics are cleared when no findings are left.
+func (s *Server) diagnose(ctx context.Context, uri string) ([]PublishDiagnosticsParams, error) {
+	filePath, err := uriToPath(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := s.scan(ctx, filePath)
+	if err != nil {
+		return nil, xerrors.Errorf("scan error: %w", err)
+	}
+
+	files := Diagnostics(filepath.Dir(filePath), results)
+	if _, ok := files[filePath]; !ok {
+		files[filePath] = []Diagnostic{}
+	}
+
+	var diagnostics []PublishDiagnosticsParams
+	for p, d := range files {
+		u := uri
+		if p != filePath {
+			u = pathToURI(p)
+		}
+		diagnostics = append(diagnostics, PublishDiagnosticsParams{URI: u, Diagnostics: d})
+	}
+	sort.Slice(diagnostics, func(i, j int) bool {
+		return diagnostics[i].URI < diagnostics[j].URI
+	})
+	return diagnostics, nil
+}
+
+func (s *Server) reply(id *json.RawMessage, result interface{}, respErr *responseError) error {
+	resp := response{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   respErr,
+	}
+	if respErr == nil {
+		b, err := json.Marshal(result)
+		if err != nil {
+			return xerrors.Errorf("json marshal error: %w", err)
+		}
+		raw := json.RawMessage(b)
+		resp.Result = &raw
+	}
+	if err := writeMessage(s.out, resp); err != nil {
+		return xerrors.Errorf("write error: %w", err)
+	}
+	return nil
+}
+
+// notify sends the notification. Errors are logged, as the following write will fail as well.
+func (s *Server) notify(method string, params interface{}) {
+	if err := writeMessage(s.out, notification{JSONRPC: "2.0", Method: method, Params: params}); err != nil {
+		log.Logger.Errorf("Unable to send %s: %s", method, err)
+	}
+}
+
+// uriToPath converts the "file" URI to the file path
+func uriToPath(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", xerrors.Errorf("invalid URI (%s): %w", uri, err)
+	} else if u.Scheme != "file" {
+		return "", xerrors.Errorf("unsupported URI scheme: %s", uri)
+	}
+
+	p := u.Path
+	// "file:///C:/path" on Windows
+	if runtime.GOOS == "windows" {
+		p = strings.TrimPrefix(p, "/")
+	}
+	return filepath.Clean(filepath.FromSlash(p)), nil
+}
+
+// pathToURI converts the absolute file path to the "file" URI
+func pathToURI(p string) string {
+	p = filepath.ToSlash(p)
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	return (&url.URL{Scheme: "file", Path: p}).String()
+}
@@ -0,0 +1,215 @@
+package lsp_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/lsp"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestServer_Serve(t *testing.T) {
+	dir, err := filepath.Abs("testdata")
+	require.NoError(t, err)
+	lockURI := "file://" + filepath.ToSlash(filepath.Join(dir, "package-lock.json"))
+	manifestURI := "file://" + filepath.ToSlash(filepath.Join(dir, "package.json"))
+
+	vuln := types.DetectedVulnerability{
+		VulnerabilityID:  "CVE-2021-23337",
+		PkgName:          "lodash",
+		InstalledVersion: "4.17.4",
+		Vulnerability:    dbTypes.Vulnerability{Severity: "HIGH"},
+	}
+	lodash := map[string]interface{}{
+		"range": map[string]interface{}{
+			"start": map[string]interface{}{"line": 9.0, "character": 4.0},
+			"end":   map[string]interface{}{"line": 9.0, "character": 15.0},
+		},
+		"severity": 1.0,
+		"code":     "CVE-2021-23337",
+		"source":   "trivy",
+		"message":  "lodash@4.17.4: CVE-2021-23337",
+	}
+
+	tests := []struct {
+		name     string
+		messages []string
+		scan     lsp.ScanFunc
+		want     []map[string]interface{}
+		wantErr  string
+	}{
+		{
+			name: "happy path",
+			messages: []string{
+				`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+				`{"jsonrpc":"2.0","method":"initialized","params":{}}`,
+				fmt.Sprintf(`{"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":%q}}}`, manifestURI),
+				fmt.Sprintf(`{"jsonrpc":"2.0","id":2,"method":"trivy/scan","params":{"textDocument":{"uri":%q}}}`, lockURI),
+				fmt.Sprintf(`{"jsonrpc":"2.0","method":"textDocument/didClose","params":{"textDocument":{"uri":%q}}}`, manifestURI),
+				`{"jsonrpc":"2.0","id":3,"method":"shutdown"}`,
+				`{"jsonrpc":"2.0","method":"exit"}`,
+			},
+			scan: func(_ context.Context, filePath string) (types.Results, error) {
+				// The lock file is scanned with the manifest
+				return types.Results{
+					{
+						Target:          "package-lock.json",
+						Vulnerabilities: []types.DetectedVulnerability{vuln},
+					},
+				}, nil
+			},
+			want: []map[string]interface{}{
+				{
+					"jsonrpc": "2.0",
+					"id":      1.0,
+					"result": map[string]interface{}{
+						"capabilities": map[string]interface{}{
+							"textDocumentSync": map[string]interface{}{"openClose": true, "change": 0.0, "save": true},
+						},
+						"serverInfo": map[string]interface{}{"name": "trivy", "version": "dev"},
+					},
+				},
+				{
+					"jsonrpc": "2.0",
+					"method":  "textDocument/publishDiagnostics",
+					"params":  map[string]interface{}{"uri": lockURI, "diagnostics": []interface{}{lodash}},
+				},
+				{
+					"jsonrpc": "2.0",
+					"method":  "textDocument/publishDiagnostics",
+					"params":  map[string]interface{}{"uri": manifestURI, "diagnostics": []interface{}{}},
+				},
+				{
+					"jsonrpc": "2.0",
+					"id":      2.0,
+					"result": []interface{}{
+						map[string]interface{}{"uri": lockURI, "diagnostics": []interface{}{lodash}},
+					},
+				},
+				{
+					"jsonrpc": "2.0",
+					"method":  "textDocument/publishDiagnostics",
+					"params":  map[string]interface{}{"uri": lockURI, "diagnostics": []interface{}{}},
+				},
+				{
+					"jsonrpc": "2.0",
+					"method":  "textDocument/publishDiagnostics",
+					"params":  map[string]interface{}{"uri": manifestURI, "diagnostics": []interface{}{}},
+				},
+				{
+					"jsonrpc": "2.0",
+					"id":      3.0,
+					"result":  nil,
+				},
+			},
+		},
+		{
+			name: "errors",
+			messages: []string{
+				`{"jsonrpc":"2.0","id":1,"method":"textDocument/hover","params":{}}`,
+				`{"jsonrpc":"2.0","method":"$/setTrace","params":{}}`,
+				`{"jsonrpc":"2.0","id":2,"method":"trivy/scan","params":{"textDocument":{"uri":"https://example.com/go.mod"}}}`,
+				fmt.Sprintf(`{"jsonrpc":"2.0","method":"textDocument/didSave","params":{"textDocument":{"uri":%q}}}`, lockURI),
+				`{"jsonrpc":"2.0",`,
+				`{"jsonrpc":"2.0","id":3,"method":"shutdown"}`,
+				`{"jsonrpc":"2.0","id":4,"method":"trivy/scan","params":{}}`,
+				`{"jsonrpc":"2.0","method":"exit"}`,
+			},
+			scan: func(context.Context, string) (types.Results, error) {
+				return nil, xerrors.New("broken lock file")
+			},
+			want: []map[string]interface{}{
+				{
+					"jsonrpc": "2.0",
+					"id":      1.0,
+					"error":   map[string]interface{}{"code": -32601.0, "message": "method not found: textDocument/hover"},
+				},
+				{
+					"jsonrpc": "2.0",
+					"id":      2.0,
+					"error":   map[string]interface{}{"code": -32603.0, "message": "unsupported URI scheme: https://example.com/go.mod"},
+				},
+				{
+					"jsonrpc": "2.0",
+					"method":  "window/logMessage",
+					"params":  map[string]interface{}{"type": 1.0, "message": "trivy: scan error: broken lock file"},
+				},
+				{
+					"jsonrpc": "2.0",
+					"id":      nil,
+					"error":   map[string]interface{}{"code": -32700.0, "message": "unexpected end of JSON input"},
+				},
+				{
+					"jsonrpc": "2.0",
+					"id":      3.0,
+					"result":  nil,
+				},
+				{
+					"jsonrpc": "2.0",
+					"id":      4.0,
+					"error":   map[string]interface{}{"code": -32600.0, "message": "server is shut down"},
+				},
+			},
+		},
+		{
+			name: "exit without shutdown",
+			messages: []string{
+				`{"jsonrpc":"2.0","method":"exit"}`,
+			},
+			wantErr: "exit without shutdown",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := new(bytes.Buffer)
+			for _, m := range tt.messages {
+				fmt.Fprintf(in, "Content-Length: %d\r\n\r\n%s", len(m), m)
+			}
+			out := new(bytes.Buffer)
+
+			err := lsp.NewServer(in, out, "dev", tt.scan).Serve(context.Background())
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, readMessages(t, out))
+		})
+	}
+}
+
+func readMessages(t *testing.T, r io.Reader) []map[string]interface{} {
+	var messages []map[string]interface{}
+	br := bufio.NewReader(r)
+	for {
+		header, err := textproto.NewReader(br).ReadMIMEHeader()
+		if err == io.EOF {
+			return messages
+		}
+		require.NoError(t, err)
+
+		length, err := strconv.Atoi(header.Get("Content-Length"))
+		require.NoError(t, err)
+		content := make([]byte, length)
+		_, err = io.ReadFull(br, content)
+		require.NoError(t, err)
+
+		var m map[string]interface{}
+		require.NoError(t, json.Unmarshal(content, &m))
+		messages = append(messages, m)
+	}
+}
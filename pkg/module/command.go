@@ -2,8 +2,10 @@ package module
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/google/go-containerregistry/pkg/name"
 	"golang.org/x/xerrors"
@@ -15,7 +17,7 @@ import (
 const mediaType = "application/vnd.module.wasm.content.layer.v1+wasm"
 
 // Install installs a module
-func Install(ctx context.Context, repo string, quiet, insecure bool) error {
+func Install(ctx context.Context, moduleDir, repo string, quiet, insecure bool) error {
 	ref, err := name.ParseReference(repo)
 	if err != nil {
 		return xerrors.Errorf("repository parse error: %w", err)
@@ -27,7 +29,7 @@ func Install(ctx context.Context, repo string, quiet, insecure bool) error {
 		return xerrors.Errorf("module initialize error: %w", err)
 	}
 
-	dst := filepath.Join(dir(), ref.Context().Name())
+	dst := filepath.Join(moduleDirOrDefault(moduleDir), ref.Context().Name())
 	log.Logger.Debugf("Installing the module to %s...", dst)
 
 	if err = artifact.Download(ctx, dst); err != nil {
@@ -38,17 +40,52 @@ func Install(ctx context.Context, repo string, quiet, insecure bool) error {
 }
 
 // Uninstall uninstalls a module
-func Uninstall(_ context.Context, repo string) error {
+func Uninstall(_ context.Context, moduleDir, repo string) error {
 	ref, err := name.ParseReference(repo)
 	if err != nil {
 		return xerrors.Errorf("repository parse error: %w", err)
 	}
 
 	log.Logger.Infof("Uninstalling %s ...", repo)
-	dst := filepath.Join(dir(), ref.Context().Name())
+	dst := filepath.Join(moduleDirOrDefault(moduleDir), ref.Context().Name())
 	if err = os.RemoveAll(dst); err != nil {
 		return xerrors.Errorf("remove error: %w", err)
 	}
 
 	return nil
 }
+
+// List returns the installed modules compatible with this version of Trivy
+func List(ctx context.Context, moduleDir string) (string, error) {
+	m, err := NewManager(ctx, Option{Dir: moduleDir})
+	if err != nil {
+		return "", xerrors.Errorf("module load error: %w", err)
+	}
+	defer m.Close(ctx)
+
+	modules := m.Modules()
+	if len(modules) == 0 {
+		return "No Installed Modules\n", nil
+	}
+
+	moduleList := []string{"Installed Modules:"}
+	for _, mod := range modules {
+		var types []string
+		if mod.Analyzer {
+			types = append(types, "analyzer")
+		}
+		if mod.PostScanner {
+			types = append(types, "post-scanner")
+		}
+		moduleList = append(moduleList, fmt.Sprintf("  Name:        %s\n  Version:     %d\n  API Version: %d\n  Type:        %s\n  Path:        %s\n",
+			mod.Name, mod.Version, mod.APIVersion, strings.Join(types, ","), mod.Path))
+	}
+	return strings.Join(moduleList, "\n"), nil
+}
+
+func moduleDirOrDefault(moduleDir string) string {
+	if moduleDir == "" {
+		return dir()
+	}
+	return moduleDir
+}
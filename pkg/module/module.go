@@ -3,6 +3,8 @@ package module
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"io/fs"
 	"os"
@@ -40,6 +42,40 @@ var (
 	RelativeDir = filepath.Join(".trivy", "modules")
 )
 
+// maxMemoryPages limits the linear memory of each module to 512MiB (64KiB per page)
+const maxMemoryPages = 8192
+
+// Option holds the options for loading modules
+type Option struct {
+	// Dir is the directory where modules are installed. The default directory is used if empty.
+	Dir string
+
+	// EnabledModules is a list of module names to be loaded. All modules are loaded if empty.
+	EnabledModules []string
+}
+
+// Info describes a loaded module
+type Info struct {
+	Name        string
+	Version     int
+	APIVersion  int
+	Path        string
+	Analyzer    bool
+	PostScanner bool
+}
+
+// apiVersionError is returned when the module is built against another API version
+type apiVersionError struct {
+	name       string
+	version    int
+	apiVersion int
+}
+
+func (e *apiVersionError) Error() string {
+	return fmt.Sprintf("%s@v%d is built for API version %d, but Trivy supports API version %d",
+		e.name, e.version, e.apiVersion, tapi.Version)
+}
+
 func logDebug(ctx context.Context, m api.Module, offset, size uint32) {
 	buf := readMemory(ctx, m, offset, size)
 	if buf != nil {
@@ -80,10 +116,12 @@ func readMemory(ctx context.Context, m api.Module, offset, size uint32) []byte {
 type Manager struct {
 	runtime wazero.Runtime
 	modules []*wasmModule
+	option  Option
 }
 
-func NewManager(ctx context.Context) (*Manager, error) {
-	m := &Manager{}
+func NewManager(ctx context.Context, opt Option) (*Manager, error) {
+	opt.Dir = moduleDirOrDefault(opt.Dir)
+	m := &Manager{option: opt}
 
 	// Create a new WebAssembly Runtime.
 	m.runtime = wazero.NewRuntime()
@@ -97,7 +135,7 @@ func NewManager(ctx context.Context) (*Manager, error) {
 }
 
 func (m *Manager) loadModules(ctx context.Context) error {
-	moduleDir := dir()
+	moduleDir := m.option.Dir
 	_, err := os.Stat(moduleDir)
 	if os.IsNotExist(err) {
 		return nil
@@ -123,10 +161,20 @@ func (m *Manager) loadModules(ctx context.Context) error {
 		}
 
 		p, err := newWASMPlugin(ctx, m.runtime, wasmCode)
-		if err != nil {
+		var verErr *apiVersionError
+		if errors.As(err, &verErr) {
+			log.Logger.Warnf("Ignore %s: %s", rel, verErr)
+			return nil
+		} else if err != nil {
 			return xerrors.Errorf("WASM module init error %s: %w", rel, err)
 		}
 
+		if len(m.option.EnabledModules) > 0 && !slices.Contains(m.option.EnabledModules, p.name) {
+			log.Logger.Debugf("Skipping %s@v%d as it is not enabled", p.name, p.version)
+			return p.Close(ctx)
+		}
+
+		p.path = rel
 		m.modules = append(m.modules, p)
 
 		return nil
@@ -138,6 +186,22 @@ func (m *Manager) loadModules(ctx context.Context) error {
 	return nil
 }
 
+// Modules returns the loaded modules
+func (m *Manager) Modules() []Info {
+	var infos []Info
+	for _, mod := range m.modules {
+		infos = append(infos, Info{
+			Name:        mod.name,
+			Version:     mod.version,
+			APIVersion:  tapi.Version,
+			Path:        mod.path,
+			Analyzer:    mod.isAnalyzer,
+			PostScanner: mod.isPostScanner,
+		})
+	}
+	return infos
+}
+
 func (m *Manager) Register() {
 	for _, mod := range m.modules {
 		mod.Register()
@@ -230,6 +294,7 @@ type wasmModule struct {
 
 	name          string
 	version       int
+	path          string
 	requiredFiles []*regexp.Regexp
 
 	isAnalyzer    bool
@@ -245,7 +310,8 @@ type wasmModule struct {
 
 func newWASMPlugin(ctx context.Context, r wazero.Runtime, code []byte) (*wasmModule, error) {
 	// Combine the above into our baseline config, overriding defaults (which discard stdout and have no file system).
-	config := wazero.NewModuleConfig().WithStdout(os.Stdout).WithFS(memoryfs.New())
+	// Modules write to stderr so that their output doesn't break the report written to stdout.
+	config := wazero.NewModuleConfig().WithStdout(os.Stderr).WithFS(memoryfs.New())
 
 	// Create an empty namespace so that multiple modules will not conflict
 	ns := r.NewNamespace(ctx)
@@ -264,7 +330,8 @@ func newWASMPlugin(ctx context.Context, r wazero.Runtime, code []byte) (*wasmMod
 	}
 
 	// Compile the WebAssembly module using the default configuration.
-	compiled, err := r.CompileModule(ctx, code, wazero.NewCompileConfig())
+	// The linear memory is capped so that a module cannot exhaust the host memory.
+	compiled, err := r.CompileModule(ctx, code, wazero.NewCompileConfig().WithMemorySizer(memorySizer))
 	if err != nil {
 		return nil, xerrors.Errorf("module compile error: %w", err)
 	}
@@ -299,9 +366,8 @@ func newWASMPlugin(ctx context.Context, r wazero.Runtime, code []byte) (*wasmMod
 	}
 
 	if apiVersion != tapi.Version {
-		log.Logger.Infof("Ignore %s@v%d module due to API version mismatch, got: %d, want: %d",
-			name, version, apiVersion, tapi.Version)
-		return nil, nil
+		_ = mod.Close(ctx)
+		return nil, &apiVersionError{name: name, version: version, apiVersion: apiVersion}
 	}
 
 	isAnalyzer, err := moduleIsAnalyzer(ctx, mod)
@@ -689,6 +755,16 @@ func isType(ctx context.Context, mod api.Module, name string) (bool, error) {
 	return isRes[0] > 0, nil
 }
 
+// memorySizer caps the maximum memory pages of modules.
+// Modules requiring more pages than the limit at start fail to be instantiated.
+func memorySizer(minPages uint32, maxPages *uint32) (min, capacity, max uint32) {
+	max = maxMemoryPages
+	if maxPages != nil && *maxPages < max {
+		max = *maxPages
+	}
+	return minPages, minPages, max
+}
+
 func dir() string {
 	return filepath.Join(utils.HomeDir(), RelativeDir)
 }
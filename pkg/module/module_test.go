@@ -20,6 +20,7 @@ func TestManager_Register(t *testing.T) {
 		name                    string
 		noModuleDir             bool
 		moduleName              string
+		enabledModules          []string
 		wantAnalyzerVersions    map[string]int
 		wantPostScannerVersions map[string]int
 		wantErr                 bool
@@ -50,6 +51,13 @@ func TestManager_Register(t *testing.T) {
 				"scanner": 2,
 			},
 		},
+		{
+			name:                    "not enabled",
+			moduleName:              "happy",
+			enabledModules:          []string{"spring4shell"},
+			wantAnalyzerVersions:    map[string]int{},
+			wantPostScannerVersions: map[string]int{},
+		},
 		{
 			name:                    "no module dir",
 			noModuleDir:             true,
@@ -81,7 +89,7 @@ func TestManager_Register(t *testing.T) {
 				require.NoError(t, err)
 			}
 
-			m, err := module.NewManager(context.Background())
+			m, err := module.NewManager(context.Background(), module.Option{EnabledModules: tt.enabledModules})
 			require.NoError(t, err)
 
 			// Register analyzer and post scanner from WASM module
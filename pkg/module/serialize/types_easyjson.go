@@ -265,21 +265,67 @@ func easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgModuleSerialize2(in *jle
 				in.Delim('[')
 				if out.Secrets == nil {
 					if !in.IsDelim(']') {
-						out.Secrets = make([]types1.SecretFinding, 0, 0)
+						out.Secrets = make([]types.DetectedSecret, 0, 0)
 					} else {
-						out.Secrets = []types1.SecretFinding{}
+						out.Secrets = []types.DetectedSecret{}
 					}
 				} else {
 					out.Secrets = (out.Secrets)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v10 types1.SecretFinding
-					easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes1(in, &v10)
+					var v10 types.DetectedSecret
+					easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgTypes3(in, &v10)
 					out.Secrets = append(out.Secrets, v10)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
+		case "Licenses":
+			if in.IsNull() {
+				in.Skip()
+				out.Licenses = nil
+			} else {
+				in.Delim('[')
+				if out.Licenses == nil {
+					if !in.IsDelim(']') {
+						out.Licenses = make([]types.DetectedLicense, 0, 0)
+					} else {
+						out.Licenses = []types.DetectedLicense{}
+					}
+				} else {
+					out.Licenses = (out.Licenses)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v11 types.DetectedLicense
+					easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgTypes4(in, &v11)
+					out.Licenses = append(out.Licenses, v11)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "Remediations":
+			if in.IsNull() {
+				in.Skip()
+				out.Remediations = nil
+			} else {
+				in.Delim('[')
+				if out.Remediations == nil {
+					if !in.IsDelim(']') {
+						out.Remediations = make([]types.Remediation, 0, 0)
+					} else {
+						out.Remediations = []types.Remediation{}
+					}
+				} else {
+					out.Remediations = (out.Remediations)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v12 types.Remediation
+					easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgTypes5(in, &v12)
+					out.Remediations = append(out.Remediations, v12)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		case "CustomResources":
 			if in.IsNull() {
 				in.Skip()
@@ -296,9 +342,9 @@ func easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgModuleSerialize2(in *jle
 					out.CustomResources = (out.CustomResources)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v11 types1.CustomResource
-					easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes2(in, &v11)
-					out.CustomResources = append(out.CustomResources, v11)
+					var v13 types1.CustomResource
+					easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes1(in, &v13)
+					out.CustomResources = append(out.CustomResources, v13)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -337,11 +383,11 @@ func easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgModuleSerialize2(out *jw
 		out.RawString(prefix)
 		{
 			out.RawByte('[')
-			for v12, v13 := range in.Packages {
-				if v12 > 0 {
+			for v14, v15 := range in.Packages {
+				if v14 > 0 {
 					out.RawByte(',')
 				}
-				easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes(out, v13)
+				easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes(out, v15)
 			}
 			out.RawByte(']')
 		}
@@ -351,11 +397,11 @@ func easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgModuleSerialize2(out *jw
 		out.RawString(prefix)
 		{
 			out.RawByte('[')
-			for v14, v15 := range in.Vulnerabilities {
-				if v14 > 0 {
+			for v16, v17 := range in.Vulnerabilities {
+				if v16 > 0 {
 					out.RawByte(',')
 				}
-				easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgTypes(out, v15)
+				easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgTypes(out, v17)
 			}
 			out.RawByte(']')
 		}
@@ -370,11 +416,11 @@ func easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgModuleSerialize2(out *jw
 		out.RawString(prefix)
 		{
 			out.RawByte('[')
-			for v16, v17 := range in.Misconfigurations {
-				if v16 > 0 {
+			for v18, v19 := range in.Misconfigurations {
+				if v18 > 0 {
 					out.RawByte(',')
 				}
-				easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgTypes2(out, v17)
+				easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgTypes2(out, v19)
 			}
 			out.RawByte(']')
 		}
@@ -384,11 +430,39 @@ func easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgModuleSerialize2(out *jw
 		out.RawString(prefix)
 		{
 			out.RawByte('[')
-			for v18, v19 := range in.Secrets {
-				if v18 > 0 {
+			for v20, v21 := range in.Secrets {
+				if v20 > 0 {
+					out.RawByte(',')
+				}
+				easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgTypes3(out, v21)
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Licenses) != 0 {
+		const prefix string = ",\"Licenses\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v22, v23 := range in.Licenses {
+				if v22 > 0 {
 					out.RawByte(',')
 				}
-				easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes1(out, v19)
+				easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgTypes4(out, v23)
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Remediations) != 0 {
+		const prefix string = ",\"Remediations\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v24, v25 := range in.Remediations {
+				if v24 > 0 {
+					out.RawByte(',')
+				}
+				easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgTypes5(out, v25)
 			}
 			out.RawByte(']')
 		}
@@ -398,11 +472,11 @@ func easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgModuleSerialize2(out *jw
 		out.RawString(prefix)
 		{
 			out.RawByte('[')
-			for v20, v21 := range in.CustomResources {
-				if v20 > 0 {
+			for v26, v27 := range in.CustomResources {
+				if v26 > 0 {
 					out.RawByte(',')
 				}
-				easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes2(out, v21)
+				easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes1(out, v27)
 			}
 			out.RawByte(']')
 		}
@@ -433,7 +507,7 @@ func (v *Result) UnmarshalJSON(data []byte) error {
 func (v *Result) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgModuleSerialize2(l, v)
 }
-func easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes2(in *jlexer.Lexer, out *types1.CustomResource) {
+func easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes1(in *jlexer.Lexer, out *types1.CustomResource) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -457,7 +531,7 @@ func easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes2(in *jlexer.Lexer, ou
 		case "FilePath":
 			out.FilePath = string(in.String())
 		case "Layer":
-			easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes3(in, &out.Layer)
+			easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes2(in, &out.Layer)
 		case "Data":
 			if m, ok := out.Data.(easyjson.Unmarshaler); ok {
 				m.UnmarshalEasyJSON(in)
@@ -476,7 +550,7 @@ func easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes2(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes2(out *jwriter.Writer, in types1.CustomResource) {
+func easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes1(out *jwriter.Writer, in types1.CustomResource) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -493,7 +567,7 @@ func easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes2(out *jwriter.Writer,
 	{
 		const prefix string = ",\"Layer\":"
 		out.RawString(prefix)
-		easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes3(out, in.Layer)
+		easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes2(out, in.Layer)
 	}
 	{
 		const prefix string = ",\"Data\":"
@@ -508,7 +582,7 @@ func easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes2(out *jwriter.Writer,
 	}
 	out.RawByte('}')
 }
-func easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes3(in *jlexer.Lexer, out *types1.Layer) {
+func easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes2(in *jlexer.Lexer, out *types1.Layer) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -541,7 +615,7 @@ func easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes3(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes3(out *jwriter.Writer, in types1.Layer) {
+func easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes2(out *jwriter.Writer, in types1.Layer) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -563,7 +637,258 @@ func easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes3(out *jwriter.Writer,
 	}
 	out.RawByte('}')
 }
-func easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes1(in *jlexer.Lexer, out *types1.SecretFinding) {
+func easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgTypes5(in *jlexer.Lexer, out *types.Remediation) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "PkgID":
+			out.PkgID = string(in.String())
+		case "PkgName":
+			out.PkgName = string(in.String())
+		case "InstalledVersion":
+			out.InstalledVersion = string(in.String())
+		case "FixedVersion":
+			out.FixedVersion = string(in.String())
+		case "VulnerabilityIDs":
+			if in.IsNull() {
+				in.Skip()
+				out.VulnerabilityIDs = nil
+			} else {
+				in.Delim('[')
+				if out.VulnerabilityIDs == nil {
+					if !in.IsDelim(']') {
+						out.VulnerabilityIDs = make([]string, 0, 4)
+					} else {
+						out.VulnerabilityIDs = []string{}
+					}
+				} else {
+					out.VulnerabilityIDs = (out.VulnerabilityIDs)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v28 string
+					v28 = string(in.String())
+					out.VulnerabilityIDs = append(out.VulnerabilityIDs, v28)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "Dependencies":
+			if in.IsNull() {
+				in.Skip()
+				out.Dependencies = nil
+			} else {
+				in.Delim('[')
+				if out.Dependencies == nil {
+					if !in.IsDelim(']') {
+						out.Dependencies = make([]types.DependencyRemediation, 0, 0)
+					} else {
+						out.Dependencies = []types.DependencyRemediation{}
+					}
+				} else {
+					out.Dependencies = (out.Dependencies)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v29 types.DependencyRemediation
+					easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgTypes6(in, &v29)
+					out.Dependencies = append(out.Dependencies, v29)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgTypes5(out *jwriter.Writer, in types.Remediation) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.PkgID != "" {
+		const prefix string = ",\"PkgID\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.PkgID))
+	}
+	{
+		const prefix string = ",\"PkgName\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.PkgName))
+	}
+	{
+		const prefix string = ",\"InstalledVersion\":"
+		out.RawString(prefix)
+		out.String(string(in.InstalledVersion))
+	}
+	if in.FixedVersion != "" {
+		const prefix string = ",\"FixedVersion\":"
+		out.RawString(prefix)
+		out.String(string(in.FixedVersion))
+	}
+	if len(in.VulnerabilityIDs) != 0 {
+		const prefix string = ",\"VulnerabilityIDs\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v30, v31 := range in.VulnerabilityIDs {
+				if v30 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v31))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Dependencies) != 0 {
+		const prefix string = ",\"Dependencies\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v32, v33 := range in.Dependencies {
+				if v32 > 0 {
+					out.RawByte(',')
+				}
+				easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgTypes6(out, v33)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+func easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgTypes6(in *jlexer.Lexer, out *types.DependencyRemediation) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "PkgID":
+			out.PkgID = string(in.String())
+		case "PkgName":
+			out.PkgName = string(in.String())
+		case "InstalledVersion":
+			out.InstalledVersion = string(in.String())
+		case "FixedVersion":
+			out.FixedVersion = string(in.String())
+		case "VulnerabilityIDs":
+			if in.IsNull() {
+				in.Skip()
+				out.VulnerabilityIDs = nil
+			} else {
+				in.Delim('[')
+				if out.VulnerabilityIDs == nil {
+					if !in.IsDelim(']') {
+						out.VulnerabilityIDs = make([]string, 0, 4)
+					} else {
+						out.VulnerabilityIDs = []string{}
+					}
+				} else {
+					out.VulnerabilityIDs = (out.VulnerabilityIDs)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v34 string
+					v34 = string(in.String())
+					out.VulnerabilityIDs = append(out.VulnerabilityIDs, v34)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgTypes6(out *jwriter.Writer, in types.DependencyRemediation) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.PkgID != "" {
+		const prefix string = ",\"PkgID\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.PkgID))
+	}
+	{
+		const prefix string = ",\"PkgName\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.PkgName))
+	}
+	{
+		const prefix string = ",\"InstalledVersion\":"
+		out.RawString(prefix)
+		out.String(string(in.InstalledVersion))
+	}
+	{
+		const prefix string = ",\"FixedVersion\":"
+		out.RawString(prefix)
+		out.String(string(in.FixedVersion))
+	}
+	{
+		const prefix string = ",\"VulnerabilityIDs\":"
+		out.RawString(prefix)
+		if in.VulnerabilityIDs == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v35, v36 := range in.VulnerabilityIDs {
+				if v35 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v36))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+func easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgTypes4(in *jlexer.Lexer, out *types.DetectedLicense) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -582,6 +907,104 @@ func easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes1(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
+		case "Severity":
+			out.Severity = string(in.String())
+		case "Category":
+			out.Category = types.LicenseCategory(in.String())
+		case "PkgName":
+			out.PkgName = string(in.String())
+		case "FilePath":
+			out.FilePath = string(in.String())
+		case "Name":
+			out.Name = string(in.String())
+		case "Confidence":
+			out.Confidence = float64(in.Float64())
+		case "Link":
+			out.Link = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgTypes4(out *jwriter.Writer, in types.DetectedLicense) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"Severity\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Severity))
+	}
+	{
+		const prefix string = ",\"Category\":"
+		out.RawString(prefix)
+		out.String(string(in.Category))
+	}
+	if in.PkgName != "" {
+		const prefix string = ",\"PkgName\":"
+		out.RawString(prefix)
+		out.String(string(in.PkgName))
+	}
+	if in.FilePath != "" {
+		const prefix string = ",\"FilePath\":"
+		out.RawString(prefix)
+		out.String(string(in.FilePath))
+	}
+	{
+		const prefix string = ",\"Name\":"
+		out.RawString(prefix)
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"Confidence\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Confidence))
+	}
+	if in.Link != "" {
+		const prefix string = ",\"Link\":"
+		out.RawString(prefix)
+		out.String(string(in.Link))
+	}
+	out.RawByte('}')
+}
+func easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgTypes3(in *jlexer.Lexer, out *types.DetectedSecret) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "Fingerprint":
+			out.Fingerprint = string(in.String())
+		case "Verification":
+			out.Verification = types.SecretVerification(in.String())
+		case "History":
+			if in.IsNull() {
+				in.Skip()
+				out.History = nil
+			} else {
+				if out.History == nil {
+					out.History = new(types.SecretHistory)
+				}
+				easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgTypes7(in, out.History)
+			}
 		case "RuleID":
 			out.RuleID = string(in.String())
 		case "Category":
@@ -606,44 +1029,140 @@ func easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes1(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes1(out *jwriter.Writer, in types1.SecretFinding) {
+func easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgTypes3(out *jwriter.Writer, in types.DetectedSecret) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Fingerprint != "" {
+		const prefix string = ",\"Fingerprint\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Fingerprint))
+	}
+	if in.Verification != "" {
+		const prefix string = ",\"Verification\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Verification))
+	}
+	if in.History != nil {
+		const prefix string = ",\"History\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgTypes7(out, *in.History)
+	}
+	{
+		const prefix string = ",\"RuleID\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.RuleID))
+	}
+	{
+		const prefix string = ",\"Category\":"
+		out.RawString(prefix)
+		out.String(string(in.Category))
+	}
+	{
+		const prefix string = ",\"Severity\":"
+		out.RawString(prefix)
+		out.String(string(in.Severity))
+	}
+	{
+		const prefix string = ",\"Title\":"
+		out.RawString(prefix)
+		out.String(string(in.Title))
+	}
+	{
+		const prefix string = ",\"StartLine\":"
+		out.RawString(prefix)
+		out.Int(int(in.StartLine))
+	}
+	{
+		const prefix string = ",\"EndLine\":"
+		out.RawString(prefix)
+		out.Int(int(in.EndLine))
+	}
+	{
+		const prefix string = ",\"Match\":"
+		out.RawString(prefix)
+		out.String(string(in.Match))
+	}
+	out.RawByte('}')
+}
+func easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgTypes7(in *jlexer.Lexer, out *types.SecretHistory) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "Commit":
+			out.Commit = string(in.String())
+		case "Author":
+			out.Author = string(in.String())
+		case "Date":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Date).UnmarshalJSON(data))
+			}
+		case "ExistsAtHead":
+			out.ExistsAtHead = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgTypes7(out *jwriter.Writer, in types.SecretHistory) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"RuleID\":"
+		const prefix string = ",\"Commit\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.RuleID))
-	}
-	{
-		const prefix string = ",\"Category\":"
-		out.RawString(prefix)
-		out.String(string(in.Category))
-	}
-	{
-		const prefix string = ",\"Severity\":"
-		out.RawString(prefix)
-		out.String(string(in.Severity))
-	}
-	{
-		const prefix string = ",\"Title\":"
-		out.RawString(prefix)
-		out.String(string(in.Title))
+		out.String(string(in.Commit))
 	}
 	{
-		const prefix string = ",\"StartLine\":"
+		const prefix string = ",\"Author\":"
 		out.RawString(prefix)
-		out.Int(int(in.StartLine))
+		out.String(string(in.Author))
 	}
 	{
-		const prefix string = ",\"EndLine\":"
+		const prefix string = ",\"Date\":"
 		out.RawString(prefix)
-		out.Int(int(in.EndLine))
+		out.Raw((in.Date).MarshalJSON())
 	}
 	{
-		const prefix string = ",\"Match\":"
+		const prefix string = ",\"ExistsAtHead\":"
 		out.RawString(prefix)
-		out.String(string(in.Match))
+		out.Bool(bool(in.ExistsAtHead))
 	}
 	out.RawByte('}')
 }
@@ -702,9 +1221,9 @@ func easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgTypes2(in *jlexer.Lexer,
 					out.References = (out.References)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v22 string
-					v22 = string(in.String())
-					out.References = append(out.References, v22)
+					var v37 string
+					v37 = string(in.String())
+					out.References = append(out.References, v37)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -712,9 +1231,9 @@ func easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgTypes2(in *jlexer.Lexer,
 		case "Status":
 			out.Status = types.MisconfStatus(in.String())
 		case "Layer":
-			easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes3(in, &out.Layer)
+			easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes2(in, &out.Layer)
 		case "CauseMetadata":
-			easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes4(in, &out.CauseMetadata)
+			easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes3(in, &out.CauseMetadata)
 		case "Traces":
 			if in.IsNull() {
 				in.Skip()
@@ -731,9 +1250,9 @@ func easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgTypes2(in *jlexer.Lexer,
 					out.Traces = (out.Traces)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v23 string
-					v23 = string(in.String())
-					out.Traces = append(out.Traces, v23)
+					var v38 string
+					v38 = string(in.String())
+					out.Traces = append(out.Traces, v38)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -858,11 +1377,11 @@ func easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgTypes2(out *jwriter.Writ
 		}
 		{
 			out.RawByte('[')
-			for v24, v25 := range in.References {
-				if v24 > 0 {
+			for v39, v40 := range in.References {
+				if v39 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v25))
+				out.String(string(v40))
 			}
 			out.RawByte(']')
 		}
@@ -885,7 +1404,7 @@ func easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgTypes2(out *jwriter.Writ
 		} else {
 			out.RawString(prefix)
 		}
-		easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes3(out, in.Layer)
+		easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes2(out, in.Layer)
 	}
 	if true {
 		const prefix string = ",\"CauseMetadata\":"
@@ -895,7 +1414,7 @@ func easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgTypes2(out *jwriter.Writ
 		} else {
 			out.RawString(prefix)
 		}
-		easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes4(out, in.CauseMetadata)
+		easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes3(out, in.CauseMetadata)
 	}
 	if len(in.Traces) != 0 {
 		const prefix string = ",\"Traces\":"
@@ -907,18 +1426,18 @@ func easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgTypes2(out *jwriter.Writ
 		}
 		{
 			out.RawByte('[')
-			for v26, v27 := range in.Traces {
-				if v26 > 0 {
+			for v41, v42 := range in.Traces {
+				if v41 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v27))
+				out.String(string(v42))
 			}
 			out.RawByte(']')
 		}
 	}
 	out.RawByte('}')
 }
-func easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes4(in *jlexer.Lexer, out *types1.CauseMetadata) {
+func easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes3(in *jlexer.Lexer, out *types1.CauseMetadata) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -948,7 +1467,7 @@ func easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes4(in *jlexer.Lexer, ou
 		case "EndLine":
 			out.EndLine = int(in.Int())
 		case "Code":
-			easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes5(in, &out.Code)
+			easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes4(in, &out.Code)
 		default:
 			in.SkipRecursive()
 		}
@@ -959,7 +1478,7 @@ func easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes4(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes4(out *jwriter.Writer, in types1.CauseMetadata) {
+func easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes3(out *jwriter.Writer, in types1.CauseMetadata) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -1017,11 +1536,11 @@ func easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes4(out *jwriter.Writer,
 		} else {
 			out.RawString(prefix)
 		}
-		easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes5(out, in.Code)
+		easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes4(out, in.Code)
 	}
 	out.RawByte('}')
 }
-func easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes5(in *jlexer.Lexer, out *types1.Code) {
+func easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes4(in *jlexer.Lexer, out *types1.Code) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1056,9 +1575,9 @@ func easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes5(in *jlexer.Lexer, ou
 					out.Lines = (out.Lines)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v28 types1.Line
-					easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes6(in, &v28)
-					out.Lines = append(out.Lines, v28)
+					var v43 types1.Line
+					easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes5(in, &v43)
+					out.Lines = append(out.Lines, v43)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -1073,7 +1592,7 @@ func easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes5(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes5(out *jwriter.Writer, in types1.Code) {
+func easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes4(out *jwriter.Writer, in types1.Code) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -1084,18 +1603,18 @@ func easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes5(out *jwriter.Writer,
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v29, v30 := range in.Lines {
-				if v29 > 0 {
+			for v44, v45 := range in.Lines {
+				if v44 > 0 {
 					out.RawByte(',')
 				}
-				easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes6(out, v30)
+				easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes5(out, v45)
 			}
 			out.RawByte(']')
 		}
 	}
 	out.RawByte('}')
 }
-func easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes6(in *jlexer.Lexer, out *types1.Line) {
+func easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes5(in *jlexer.Lexer, out *types1.Line) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1140,7 +1659,7 @@ func easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes6(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes6(out *jwriter.Writer, in types1.Line) {
+func easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes5(out *jwriter.Writer, in types1.Line) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -1279,13 +1798,15 @@ func easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgTypes(in *jlexer.Lexer,
 					out.VendorIDs = (out.VendorIDs)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v31 string
-					v31 = string(in.String())
-					out.VendorIDs = append(out.VendorIDs, v31)
+					var v46 string
+					v46 = string(in.String())
+					out.VendorIDs = append(out.VendorIDs, v46)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
+		case "PkgID":
+			out.PkgID = string(in.String())
 		case "PkgName":
 			out.PkgName = string(in.String())
 		case "PkgPath":
@@ -1294,8 +1815,10 @@ func easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgTypes(in *jlexer.Lexer,
 			out.InstalledVersion = string(in.String())
 		case "FixedVersion":
 			out.FixedVersion = string(in.String())
+		case "Status":
+			out.Status = string(in.String())
 		case "Layer":
-			easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes3(in, &out.Layer)
+			easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes2(in, &out.Layer)
 		case "SeveritySource":
 			out.SeveritySource = types2.SourceID(in.String())
 		case "PrimaryURL":
@@ -1310,6 +1833,18 @@ func easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgTypes(in *jlexer.Lexer,
 				}
 				easyjson6601e8cdDecodeGithubComAquasecurityTrivyDbPkgTypes(in, out.DataSource)
 			}
+		case "Buildpack":
+			if in.IsNull() {
+				in.Skip()
+				out.Buildpack = nil
+			} else {
+				if out.Buildpack == nil {
+					out.Buildpack = new(types.Buildpack)
+				}
+				easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgTypes8(in, out.Buildpack)
+			}
+		case "IntroducedBy":
+			out.IntroducedBy = types.IntroducedBy(in.String())
 		case "Custom":
 			if m, ok := out.Custom.(easyjson.Unmarshaler); ok {
 				m.UnmarshalEasyJSON(in)
@@ -1340,9 +1875,9 @@ func easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgTypes(in *jlexer.Lexer,
 					out.CweIDs = (out.CweIDs)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v32 string
-					v32 = string(in.String())
-					out.CweIDs = append(out.CweIDs, v32)
+					var v47 string
+					v47 = string(in.String())
+					out.CweIDs = append(out.CweIDs, v47)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -1360,9 +1895,9 @@ func easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgTypes(in *jlexer.Lexer,
 				for !in.IsDelim('}') {
 					key := types2.SourceID(in.String())
 					in.WantColon()
-					var v33 types2.Severity
-					v33 = types2.Severity(in.Int())
-					(out.VendorSeverity)[key] = v33
+					var v48 types2.Severity
+					v48 = types2.Severity(in.Int())
+					(out.VendorSeverity)[key] = v48
 					in.WantComma()
 				}
 				in.Delim('}')
@@ -1380,9 +1915,9 @@ func easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgTypes(in *jlexer.Lexer,
 				for !in.IsDelim('}') {
 					key := types2.SourceID(in.String())
 					in.WantColon()
-					var v34 types2.CVSS
-					easyjson6601e8cdDecodeGithubComAquasecurityTrivyDbPkgTypes1(in, &v34)
-					(out.CVSS)[key] = v34
+					var v49 types2.CVSS
+					easyjson6601e8cdDecodeGithubComAquasecurityTrivyDbPkgTypes1(in, &v49)
+					(out.CVSS)[key] = v49
 					in.WantComma()
 				}
 				in.Delim('}')
@@ -1403,9 +1938,9 @@ func easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgTypes(in *jlexer.Lexer,
 					out.References = (out.References)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v35 string
-					v35 = string(in.String())
-					out.References = append(out.References, v35)
+					var v50 string
+					v50 = string(in.String())
+					out.References = append(out.References, v50)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -1464,15 +1999,25 @@ func easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgTypes(out *jwriter.Write
 		}
 		{
 			out.RawByte('[')
-			for v36, v37 := range in.VendorIDs {
-				if v36 > 0 {
+			for v51, v52 := range in.VendorIDs {
+				if v51 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v37))
+				out.String(string(v52))
 			}
 			out.RawByte(']')
 		}
 	}
+	if in.PkgID != "" {
+		const prefix string = ",\"PkgID\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.PkgID))
+	}
 	if in.PkgName != "" {
 		const prefix string = ",\"PkgName\":"
 		if first {
@@ -1513,6 +2058,16 @@ func easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgTypes(out *jwriter.Write
 		}
 		out.String(string(in.FixedVersion))
 	}
+	if in.Status != "" {
+		const prefix string = ",\"Status\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Status))
+	}
 	if true {
 		const prefix string = ",\"Layer\":"
 		if first {
@@ -1521,7 +2076,7 @@ func easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgTypes(out *jwriter.Write
 		} else {
 			out.RawString(prefix)
 		}
-		easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes3(out, in.Layer)
+		easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes2(out, in.Layer)
 	}
 	if in.SeveritySource != "" {
 		const prefix string = ",\"SeveritySource\":"
@@ -1553,6 +2108,26 @@ func easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgTypes(out *jwriter.Write
 		}
 		easyjson6601e8cdEncodeGithubComAquasecurityTrivyDbPkgTypes(out, *in.DataSource)
 	}
+	if in.Buildpack != nil {
+		const prefix string = ",\"Buildpack\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgTypes8(out, *in.Buildpack)
+	}
+	if in.IntroducedBy != "" {
+		const prefix string = ",\"IntroducedBy\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.IntroducedBy))
+	}
 	if in.Custom != nil {
 		const prefix string = ",\"Custom\":"
 		if first {
@@ -1609,11 +2184,11 @@ func easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgTypes(out *jwriter.Write
 		}
 		{
 			out.RawByte('[')
-			for v38, v39 := range in.CweIDs {
-				if v38 > 0 {
+			for v53, v54 := range in.CweIDs {
+				if v53 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v39))
+				out.String(string(v54))
 			}
 			out.RawByte(']')
 		}
@@ -1628,16 +2203,16 @@ func easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgTypes(out *jwriter.Write
 		}
 		{
 			out.RawByte('{')
-			v40First := true
-			for v40Name, v40Value := range in.VendorSeverity {
-				if v40First {
-					v40First = false
+			v55First := true
+			for v55Name, v55Value := range in.VendorSeverity {
+				if v55First {
+					v55First = false
 				} else {
 					out.RawByte(',')
 				}
-				out.String(string(v40Name))
+				out.String(string(v55Name))
 				out.RawByte(':')
-				out.Int(int(v40Value))
+				out.Int(int(v55Value))
 			}
 			out.RawByte('}')
 		}
@@ -1652,16 +2227,16 @@ func easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgTypes(out *jwriter.Write
 		}
 		{
 			out.RawByte('{')
-			v41First := true
-			for v41Name, v41Value := range in.CVSS {
-				if v41First {
-					v41First = false
+			v56First := true
+			for v56Name, v56Value := range in.CVSS {
+				if v56First {
+					v56First = false
 				} else {
 					out.RawByte(',')
 				}
-				out.String(string(v41Name))
+				out.String(string(v56Name))
 				out.RawByte(':')
-				easyjson6601e8cdEncodeGithubComAquasecurityTrivyDbPkgTypes1(out, v41Value)
+				easyjson6601e8cdEncodeGithubComAquasecurityTrivyDbPkgTypes1(out, v56Value)
 			}
 			out.RawByte('}')
 		}
@@ -1676,11 +2251,11 @@ func easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgTypes(out *jwriter.Write
 		}
 		{
 			out.RawByte('[')
-			for v42, v43 := range in.References {
-				if v42 > 0 {
+			for v57, v58 := range in.References {
+				if v57 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v43))
+				out.String(string(v58))
 			}
 			out.RawByte(']')
 		}
@@ -1786,6 +2361,61 @@ func easyjson6601e8cdEncodeGithubComAquasecurityTrivyDbPkgTypes1(out *jwriter.Wr
 	}
 	out.RawByte('}')
 }
+func easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgTypes8(in *jlexer.Lexer, out *types.Buildpack) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "ID":
+			out.ID = string(in.String())
+		case "Version":
+			out.Version = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgTypes8(out *jwriter.Writer, in types.Buildpack) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.ID != "" {
+		const prefix string = ",\"ID\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	if in.Version != "" {
+		const prefix string = ",\"Version\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Version))
+	}
+	out.RawByte('}')
+}
 func easyjson6601e8cdDecodeGithubComAquasecurityTrivyDbPkgTypes(in *jlexer.Lexer, out *types2.DataSource) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
@@ -1902,7 +2532,7 @@ func easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes(in *jlexer.Lexer, out
 				if out.BuildInfo == nil {
 					out.BuildInfo = new(types1.BuildInfo)
 				}
-				easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes7(in, out.BuildInfo)
+				easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes6(in, out.BuildInfo)
 			}
 		case "Ref":
 			out.Ref = string(in.String())
@@ -1924,9 +2554,9 @@ func easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes(in *jlexer.Lexer, out
 					out.DependsOn = (out.DependsOn)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v44 string
-					v44 = string(in.String())
-					out.DependsOn = append(out.DependsOn, v44)
+					var v59 string
+					v59 = string(in.String())
+					out.DependsOn = append(out.DependsOn, v59)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -1934,7 +2564,7 @@ func easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes(in *jlexer.Lexer, out
 		case "License":
 			out.License = string(in.String())
 		case "Layer":
-			easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes3(in, &out.Layer)
+			easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes2(in, &out.Layer)
 		case "FilePath":
 			out.FilePath = string(in.String())
 		default:
@@ -2065,7 +2695,7 @@ func easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes(out *jwriter.Writer,
 		} else {
 			out.RawString(prefix)
 		}
-		easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes7(out, *in.BuildInfo)
+		easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes6(out, *in.BuildInfo)
 	}
 	if in.Ref != "" {
 		const prefix string = ",\"Ref\":"
@@ -2097,11 +2727,11 @@ func easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes(out *jwriter.Writer,
 		}
 		{
 			out.RawByte('[')
-			for v45, v46 := range in.DependsOn {
-				if v45 > 0 {
+			for v60, v61 := range in.DependsOn {
+				if v60 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v46))
+				out.String(string(v61))
 			}
 			out.RawByte(']')
 		}
@@ -2124,7 +2754,7 @@ func easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes(out *jwriter.Writer,
 		} else {
 			out.RawString(prefix)
 		}
-		easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes3(out, in.Layer)
+		easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes2(out, in.Layer)
 	}
 	if in.FilePath != "" {
 		const prefix string = ",\"FilePath\":"
@@ -2138,7 +2768,7 @@ func easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes(out *jwriter.Writer,
 	}
 	out.RawByte('}')
 }
-func easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes7(in *jlexer.Lexer, out *types1.BuildInfo) {
+func easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes6(in *jlexer.Lexer, out *types1.BuildInfo) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2173,9 +2803,9 @@ func easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes7(in *jlexer.Lexer, ou
 					out.ContentSets = (out.ContentSets)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v47 string
-					v47 = string(in.String())
-					out.ContentSets = append(out.ContentSets, v47)
+					var v62 string
+					v62 = string(in.String())
+					out.ContentSets = append(out.ContentSets, v62)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -2194,7 +2824,7 @@ func easyjson6601e8cdDecodeGithubComAquasecurityFanalTypes7(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes7(out *jwriter.Writer, in types1.BuildInfo) {
+func easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes6(out *jwriter.Writer, in types1.BuildInfo) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -2204,11 +2834,11 @@ func easyjson6601e8cdEncodeGithubComAquasecurityFanalTypes7(out *jwriter.Writer,
 		out.RawString(prefix[1:])
 		{
 			out.RawByte('[')
-			for v48, v49 := range in.ContentSets {
-				if v48 > 0 {
+			for v63, v64 := range in.ContentSets {
+				if v63 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v49))
+				out.String(string(v64))
 			}
 			out.RawByte(']')
 		}
@@ -2272,9 +2902,9 @@ func easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgModuleSerialize3(in *jle
 					out.IDs = (out.IDs)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v50 string
-					v50 = string(in.String())
-					out.IDs = append(out.IDs, v50)
+					var v65 string
+					v65 = string(in.String())
+					out.IDs = append(out.IDs, v65)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -2305,11 +2935,11 @@ func easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgModuleSerialize3(out *jw
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v51, v52 := range in.IDs {
-				if v51 > 0 {
+			for v66, v67 := range in.IDs {
+				if v66 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v52))
+				out.String(string(v67))
 			}
 			out.RawByte(']')
 		}
@@ -2340,7 +2970,7 @@ func (v *PostScanSpec) UnmarshalJSON(data []byte) error {
 func (v *PostScanSpec) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgModuleSerialize3(l, v)
 }
-func easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgModuleSerialize4(in *jlexer.Lexer, out *AnalysisResult) {
+func easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgModuleSerialize4(in *jlexer.Lexer, out *CustomResource) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2359,28 +2989,17 @@ func easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgModuleSerialize4(in *jle
 			continue
 		}
 		switch key {
-		case "CustomResources":
-			if in.IsNull() {
-				in.Skip()
-				out.CustomResources = nil
+		case "Type":
+			out.Type = string(in.String())
+		case "FilePath":
+			out.FilePath = string(in.String())
+		case "Data":
+			if m, ok := out.Data.(easyjson.Unmarshaler); ok {
+				m.UnmarshalEasyJSON(in)
+			} else if m, ok := out.Data.(json.Unmarshaler); ok {
+				_ = m.UnmarshalJSON(in.Raw())
 			} else {
-				in.Delim('[')
-				if out.CustomResources == nil {
-					if !in.IsDelim(']') {
-						out.CustomResources = make([]CustomResource, 0, 1)
-					} else {
-						out.CustomResources = []CustomResource{}
-					}
-				} else {
-					out.CustomResources = (out.CustomResources)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v53 CustomResource
-					easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgModuleSerialize5(in, &v53)
-					out.CustomResources = append(out.CustomResources, v53)
-					in.WantComma()
-				}
-				in.Delim(']')
+				out.Data = in.Interface()
 			}
 		default:
 			in.SkipRecursive()
@@ -2392,53 +3011,58 @@ func easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgModuleSerialize4(in *jle
 		in.Consumed()
 	}
 }
-func easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgModuleSerialize4(out *jwriter.Writer, in AnalysisResult) {
+func easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgModuleSerialize4(out *jwriter.Writer, in CustomResource) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"CustomResources\":"
+		const prefix string = ",\"Type\":"
 		out.RawString(prefix[1:])
-		if in.CustomResources == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
-			out.RawString("null")
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"FilePath\":"
+		out.RawString(prefix)
+		out.String(string(in.FilePath))
+	}
+	{
+		const prefix string = ",\"Data\":"
+		out.RawString(prefix)
+		if m, ok := in.Data.(easyjson.Marshaler); ok {
+			m.MarshalEasyJSON(out)
+		} else if m, ok := in.Data.(json.Marshaler); ok {
+			out.Raw(m.MarshalJSON())
 		} else {
-			out.RawByte('[')
-			for v54, v55 := range in.CustomResources {
-				if v54 > 0 {
-					out.RawByte(',')
-				}
-				easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgModuleSerialize5(out, v55)
-			}
-			out.RawByte(']')
+			out.Raw(json.Marshal(in.Data))
 		}
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v AnalysisResult) MarshalJSON() ([]byte, error) {
+func (v CustomResource) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgModuleSerialize4(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v AnalysisResult) MarshalEasyJSON(w *jwriter.Writer) {
+func (v CustomResource) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgModuleSerialize4(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *AnalysisResult) UnmarshalJSON(data []byte) error {
+func (v *CustomResource) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgModuleSerialize4(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *AnalysisResult) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *CustomResource) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgModuleSerialize4(l, v)
 }
-func easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgModuleSerialize5(in *jlexer.Lexer, out *CustomResource) {
+func easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgModuleSerialize5(in *jlexer.Lexer, out *AnalysisResult) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2457,17 +3081,28 @@ func easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgModuleSerialize5(in *jle
 			continue
 		}
 		switch key {
-		case "Type":
-			out.Type = string(in.String())
-		case "FilePath":
-			out.FilePath = string(in.String())
-		case "Data":
-			if m, ok := out.Data.(easyjson.Unmarshaler); ok {
-				m.UnmarshalEasyJSON(in)
-			} else if m, ok := out.Data.(json.Unmarshaler); ok {
-				_ = m.UnmarshalJSON(in.Raw())
+		case "CustomResources":
+			if in.IsNull() {
+				in.Skip()
+				out.CustomResources = nil
 			} else {
-				out.Data = in.Interface()
+				in.Delim('[')
+				if out.CustomResources == nil {
+					if !in.IsDelim(']') {
+						out.CustomResources = make([]CustomResource, 0, 1)
+					} else {
+						out.CustomResources = []CustomResource{}
+					}
+				} else {
+					out.CustomResources = (out.CustomResources)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v68 CustomResource
+					(v68).UnmarshalEasyJSON(in)
+					out.CustomResources = append(out.CustomResources, v68)
+					in.WantComma()
+				}
+				in.Delim(']')
 			}
 		default:
 			in.SkipRecursive()
@@ -2479,30 +3114,49 @@ func easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgModuleSerialize5(in *jle
 		in.Consumed()
 	}
 }
-func easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgModuleSerialize5(out *jwriter.Writer, in CustomResource) {
+func easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgModuleSerialize5(out *jwriter.Writer, in AnalysisResult) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"Type\":"
+		const prefix string = ",\"CustomResources\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Type))
-	}
-	{
-		const prefix string = ",\"FilePath\":"
-		out.RawString(prefix)
-		out.String(string(in.FilePath))
-	}
-	{
-		const prefix string = ",\"Data\":"
-		out.RawString(prefix)
-		if m, ok := in.Data.(easyjson.Marshaler); ok {
-			m.MarshalEasyJSON(out)
-		} else if m, ok := in.Data.(json.Marshaler); ok {
-			out.Raw(m.MarshalJSON())
+		if in.CustomResources == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
 		} else {
-			out.Raw(json.Marshal(in.Data))
+			out.RawByte('[')
+			for v69, v70 := range in.CustomResources {
+				if v69 > 0 {
+					out.RawByte(',')
+				}
+				(v70).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
 		}
 	}
 	out.RawByte('}')
 }
+
+// MarshalJSON supports json.Marshaler interface
+func (v AnalysisResult) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgModuleSerialize5(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v AnalysisResult) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson6601e8cdEncodeGithubComAquasecurityTrivyPkgModuleSerialize5(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *AnalysisResult) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgModuleSerialize5(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *AnalysisResult) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson6601e8cdDecodeGithubComAquasecurityTrivyPkgModuleSerialize5(l, v)
+}
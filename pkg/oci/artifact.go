@@ -2,20 +2,18 @@ package oci
 
 import (
 	"context"
-	"crypto/tls"
 	"io"
-	"net/http"
 	"os"
 	"path/filepath"
 
 	"github.com/cheggaaa/pb/v3"
-	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"golang.org/x/xerrors"
 
 	"github.com/aquasecurity/trivy/pkg/downloader"
+	"github.com/aquasecurity/trivy/pkg/image"
 )
 
 const titleAnnotation = "org.opencontainers.image.title"
@@ -56,15 +54,7 @@ func NewArtifact(repo, mediaType string, quiet, insecure bool, opts ...Option) (
 			return nil, xerrors.Errorf("repository name error (%s): %w", repo, err)
 		}
 
-		remoteOpts := []remote.Option{remote.WithAuthFromKeychain(authn.DefaultKeychain)}
-		if insecure {
-			t := &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-			}
-			remoteOpts = append(remoteOpts, remote.WithTransport(t))
-		}
-
-		o.img, err = remote.Image(ref, remoteOpts...)
+		o.img, err = remote.Image(ref, image.RemoteOptions(insecure)...)
 		if err != nil {
 			return nil, xerrors.Errorf("OCI repository error: %w", err)
 		}
@@ -0,0 +1,180 @@
+package oci
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"golang.org/x/xerrors"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+)
+
+// ArtifactType is the type of OCI artifacts other than container images in reports
+const ArtifactType ftypes.ArtifactType = "oci_artifact"
+
+// Kinds of OCI artifacts that are not container images
+const (
+	KindHelmChart = "helm-chart"
+	KindWasm      = "wasm"
+	KindGeneric   = "generic"
+)
+
+const (
+	HelmConfigMediaType     = "application/vnd.cncf.helm.config.v1+json"
+	HelmChartLayerMediaType = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+
+	WasmConfigMediaType       = "application/vnd.wasm.config.v1+json"
+	WasmLayerMediaType        = "application/vnd.wasm.content.layer.v1+wasm"
+	WasmModuleLayerMediaType  = "application/vnd.module.wasm.content.layer.v1+wasm"
+	wasmModuleConfigMediaType = "application/vnd.module.wasm.config.v1+json"
+)
+
+// NotImageError is returned when an OCI artifact pulled from a registry is not a container image.
+// Image holds the artifact so that its files can be downloaded without pulling it again.
+type NotImageError struct {
+	Kind  string
+	Image v1.Image
+}
+
+func (e *NotImageError) Error() string {
+	return fmt.Sprintf("not a container image, but a %s OCI artifact", e.Kind)
+}
+
+// ArtifactKind returns the kind of the OCI artifact described by the manifest.
+// It returns an empty string for container images.
+func ArtifactKind(m *v1.Manifest) string {
+	switch m.Config.MediaType {
+	case types.DockerConfigJSON, types.OCIConfigJSON:
+		return ""
+	case HelmConfigMediaType:
+		return KindHelmChart
+	case WasmConfigMediaType, wasmModuleConfigMediaType:
+		return KindWasm
+	}
+
+	// Some tools push artifacts with an empty config, e.g. "oras push"
+	for _, layer := range m.Layers {
+		switch layer.MediaType {
+		case HelmChartLayerMediaType:
+			return KindHelmChart
+		case WasmLayerMediaType, WasmModuleLayerMediaType:
+			return KindWasm
+		}
+	}
+
+	// Container images with an unknown config media type are still container images
+	// as long as their layers are.
+	for _, layer := range m.Layers {
+		if !isImageLayer(layer.MediaType) {
+			return KindGeneric
+		}
+	}
+	return ""
+}
+
+func isImageLayer(mt types.MediaType) bool {
+	switch mt {
+	case types.DockerLayer, types.DockerUncompressedLayer, types.DockerForeignLayer,
+		types.OCILayer, types.OCIUncompressedLayer, types.OCIRestrictedLayer, types.OCIUncompressedRestrictedLayer:
+		return true
+	}
+	// e.g. application/vnd.oci.image.layer.v1.tar+zstd
+	return strings.HasPrefix(string(mt), "application/vnd.oci.image.layer.")
+}
+
+// Pull writes the blobs of the OCI artifact into dir and returns the file names.
+// The file names are taken from the title annotations if any.
+func Pull(img v1.Image, dir string) ([]string, error) {
+	manifest, err := img.Manifest()
+	if err != nil {
+		return nil, xerrors.Errorf("OCI manifest error: %w", err)
+	}
+
+	// "helm push" doesn't annotate titles, but the chart name is stored in the config
+	var chartName string
+	if manifest.Config.MediaType == HelmConfigMediaType {
+		chartName = helmChartName(img)
+	}
+
+	var fileNames []string
+	for _, desc := range manifest.Layers {
+		fileName := layerFileName(desc, chartName)
+		if err = pullLayer(img, desc.Digest, filepath.Join(dir, fileName)); err != nil {
+			return nil, xerrors.Errorf("unable to pull %s: %w", fileName, err)
+		}
+		fileNames = append(fileNames, fileName)
+	}
+	return fileNames, nil
+}
+
+func pullLayer(img v1.Image, digest v1.Hash, filePath string) error {
+	layer, err := img.LayerByDigest(digest)
+	if err != nil {
+		return xerrors.Errorf("layer error: %w", err)
+	}
+
+	// Artifacts such as Helm charts are stored as is, so they must not be decompressed here.
+	rc, err := layer.Compressed()
+	if err != nil {
+		return xerrors.Errorf("failed to fetch the layer: %w", err)
+	}
+	defer rc.Close()
+
+	if err = os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+		return xerrors.Errorf("mkdir error: %w", err)
+	}
+
+	f, err := os.Create(filePath)
+	if err != nil {
+		return xerrors.Errorf("failed to create a file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err = io.Copy(f, rc); err != nil {
+		return xerrors.Errorf("copy error: %w", err)
+	}
+	return nil
+}
+
+func helmChartName(img v1.Image) string {
+	b, err := img.RawConfigFile()
+	if err != nil {
+		return ""
+	}
+	var chart struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+	if err = json.Unmarshal(b, &chart); err != nil || chart.Name == "" || chart.Version == "" {
+		return ""
+	}
+	return filepath.Base(filepath.Clean("/" + chart.Name + "-" + chart.Version))
+}
+
+// layerFileName returns a relative path that cannot escape the destination directory
+func layerFileName(desc v1.Descriptor, chartName string) string {
+	if title := desc.Annotations[titleAnnotation]; title != "" {
+		title = filepath.Clean("/" + filepath.FromSlash(title))
+		if title != string(filepath.Separator) {
+			return strings.TrimPrefix(title, string(filepath.Separator))
+		}
+	}
+
+	fileName := desc.Digest.Hex
+	switch desc.MediaType {
+	case HelmChartLayerMediaType:
+		if chartName != "" && chartName != string(filepath.Separator) {
+			fileName = chartName
+		}
+		fileName += ".tgz"
+	case WasmLayerMediaType, WasmModuleLayerMediaType:
+		fileName += ".wasm"
+	}
+	return fileName
+}
@@ -0,0 +1,125 @@
+package oci_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/oci"
+)
+
+func TestArtifactKind(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest v1.Manifest
+		want     string
+	}{
+		{
+			name: "container image",
+			manifest: v1.Manifest{
+				Config: v1.Descriptor{MediaType: types.OCIConfigJSON},
+				Layers: []v1.Descriptor{{MediaType: types.OCILayer}},
+			},
+			want: "",
+		},
+		{
+			name: "container image with an unknown config",
+			manifest: v1.Manifest{
+				Config: v1.Descriptor{MediaType: "application/octet-stream"},
+				Layers: []v1.Descriptor{{MediaType: types.DockerLayer}},
+			},
+			want: "",
+		},
+		{
+			name: "helm chart",
+			manifest: v1.Manifest{
+				Config: v1.Descriptor{MediaType: oci.HelmConfigMediaType},
+				Layers: []v1.Descriptor{{MediaType: oci.HelmChartLayerMediaType}},
+			},
+			want: oci.KindHelmChart,
+		},
+		{
+			name: "wasm module pushed with an empty config",
+			manifest: v1.Manifest{
+				Config: v1.Descriptor{MediaType: "application/vnd.unknown.config.v1+json"},
+				Layers: []v1.Descriptor{{MediaType: oci.WasmLayerMediaType}},
+			},
+			want: oci.KindWasm,
+		},
+		{
+			name: "generic",
+			manifest: v1.Manifest{
+				Config: v1.Descriptor{MediaType: "application/vnd.unknown.config.v1+json"},
+				Layers: []v1.Descriptor{{MediaType: "text/plain"}},
+			},
+			want: oci.KindGeneric,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, oci.ArtifactKind(&tt.manifest))
+		})
+	}
+}
+
+// helmImage returns the config written by "helm push"
+type helmImage struct {
+	v1.Image
+}
+
+func (helmImage) RawConfigFile() ([]byte, error) {
+	return []byte(`{"name":"mychart","version":"0.2.0","apiVersion":"v2"}`), nil
+}
+
+func TestPull(t *testing.T) {
+	img, err := mutate.Append(empty.Image,
+		mutate.Addendum{
+			Layer:       static.NewLayer([]byte("chart"), oci.HelmChartLayerMediaType),
+			Annotations: map[string]string{"org.opencontainers.image.title": "../../mychart-0.1.0.tgz"},
+		},
+		mutate.Addendum{
+			Layer: static.NewLayer([]byte("module"), oci.WasmLayerMediaType),
+		},
+	)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	got, err := oci.Pull(img, dir)
+	require.NoError(t, err)
+
+	wasmDigest, err := static.NewLayer([]byte("module"), oci.WasmLayerMediaType).Digest()
+	require.NoError(t, err)
+
+	// Titles cannot escape the directory
+	want := []string{
+		"mychart-0.1.0.tgz",
+		wasmDigest.Hex + ".wasm",
+	}
+	assert.Equal(t, want, got)
+
+	b, err := os.ReadFile(filepath.Join(dir, "mychart-0.1.0.tgz"))
+	require.NoError(t, err)
+	assert.Equal(t, "chart", string(b))
+}
+
+func TestPull_HelmChart(t *testing.T) {
+	img, err := mutate.Append(empty.Image, mutate.Addendum{
+		Layer: static.NewLayer([]byte("chart"), oci.HelmChartLayerMediaType),
+	})
+	require.NoError(t, err)
+	img = mutate.ConfigMediaType(img, oci.HelmConfigMediaType)
+
+	dir := t.TempDir()
+	got, err := oci.Pull(helmImage{Image: img}, dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mychart-0.2.0.tgz"}, got)
+	assert.FileExists(t, filepath.Join(dir, "mychart-0.2.0.tgz"))
+}
@@ -0,0 +1,141 @@
+package ownership
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// RulesType is the type of custom resources holding the rules of CODEOWNERS.
+// Files are not available when results are built from the cache, so rules are passed as custom resources.
+const RulesType = "trivy-codeowners"
+
+// Locations are the paths of CODEOWNERS relative to the root in order of precedence.
+// GitHub looks for .github/, the root and docs/, and GitLab also looks for .gitlab/.
+var Locations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS", ".gitlab/CODEOWNERS"}
+
+// Rule represents a line of CODEOWNERS, e.g. "/apps/payments/ @acme/payments-team alice@example.com"
+type Rule struct {
+	Pattern string
+
+	// Owners is empty when the owners of the files are unset
+	Owners []string `json:",omitempty"`
+}
+
+// Rules represents rules in the order of the file, where the last matching rule takes precedence
+type Rules []Rule
+
+// Parse parses rules in the CODEOWNERS syntax.
+// Patterns follow the .gitignore syntax except negation, and owners are separated by spaces.
+// Sections of GitLab, e.g. "[Documentation]", are ignored.
+func Parse(content []byte) (Rules, error) {
+	var rules Rules
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") ||
+			strings.HasPrefix(fields[0], "[") || strings.HasPrefix(fields[0], "^[") {
+			continue
+		} else if strings.HasPrefix(fields[0], "!") {
+			return nil, xerrors.Errorf("line %d: negated patterns are not supported: %s", lineNo, fields[0])
+		}
+
+		// Escaped spaces are not supported, as in GitHub
+		rules = append(rules, Rule{
+			Pattern: fields[0],
+			Owners:  fields[1:],
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, xerrors.Errorf("unable to read CODEOWNERS: %w", err)
+	}
+	return rules, nil
+}
+
+// Load reads the rules from the file in the CODEOWNERS syntax
+func Load(filePath string) (Rules, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to read %s: %w", filePath, err)
+	}
+
+	rules, err := Parse(content)
+	if err != nil {
+		return nil, xerrors.Errorf("%s: %w", filePath, err)
+	}
+	return rules, nil
+}
+
+// Owners returns the owners of the slash-separated path relative to the root.
+// A directory pattern also matches the files under the directory.
+func (rules Rules) Owners(filePath string) []string {
+	owners, _ := rules.match(filePath)
+	return owners
+}
+
+// match returns the owners of the last matching rule, which may be empty to unset the owners
+func (rules Rules) match(filePath string) ([]string, bool) {
+	elems := strings.Split(path.Clean(strings.TrimPrefix(filePath, "/")), "/")
+	for i := len(rules) - 1; i >= 0; i-- {
+		if gitignore.ParsePattern(rules[i].Pattern, nil).Match(elems, false) == gitignore.Exclude {
+			return rules[i].Owners, true
+		}
+	}
+	return nil, false
+}
+
+// Resource returns the custom resource holding the rules of CODEOWNERS
+func Resource(filePath string, rules Rules) ftypes.CustomResource {
+	return ftypes.CustomResource{
+		Type:     RulesType,
+		FilePath: filePath,
+		Data:     rules,
+	}
+}
+
+// Assign sets the owners of results whose targets are files.
+// Results are left as they are when no rule matches the target, so that rules can be applied on top of others.
+func Assign(results types.Results, rules Rules) {
+	if len(rules) == 0 {
+		return
+	}
+
+	for i := range results {
+		r := &results[i]
+		if !hasFileTarget(*r) {
+			continue
+		}
+
+		if owners, ok := rules.match(r.Target); ok {
+			r.Owners = slices.Clone(owners)
+			log.Logger.Debugf("Owners of %s: %v", r.Target, r.Owners)
+		}
+	}
+}
+
+// hasFileTarget returns whether the target of the result is a file path.
+// The targets of OS packages and image-level results are not files in the artifact.
+func hasFileTarget(r types.Result) bool {
+	switch r.Class {
+	case types.ClassLangPkg, types.ClassConfig, types.ClassSecret, types.ClassLicenseFile:
+		return true
+	case "":
+		// Files which failed to be analyzed with --continue-on-error
+		return r.Error != ""
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package ownership_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/ownership"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const codeowners = `# Default owners
+*       @acme/platform
+
+*.tf    @acme/infra # Terraform
+/apps/payments/ @acme/payments alice@example.com
+docs/
+
+[Documentation]
+/apps/**/README.md @acme/writers
+`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    ownership.Rules
+		wantErr string
+	}{
+		{
+			name:    "happy path",
+			content: codeowners,
+			want: ownership.Rules{
+				{Pattern: "*", Owners: []string{"@acme/platform"}},
+				{Pattern: "*.tf", Owners: []string{"@acme/infra"}},
+				{Pattern: "/apps/payments/", Owners: []string{"@acme/payments", "alice@example.com"}},
+				{Pattern: "docs/", Owners: []string{}},
+				{Pattern: "/apps/**/README.md", Owners: []string{"@acme/writers"}},
+			},
+		},
+		{
+			name:    "negation",
+			content: "*  @acme/platform\n!vendor/\n",
+			wantErr: "line 2: negated patterns are not supported",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ownership.Parse([]byte(tt.content))
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRules_Owners(t *testing.T) {
+	rules, err := ownership.Parse([]byte(codeowners))
+	require.NoError(t, err)
+
+	tests := []struct {
+		filePath string
+		want     []string
+	}{
+		{
+			filePath: "go.mod",
+			want:     []string{"@acme/platform"},
+		},
+		{
+			filePath: "deploy/main.tf",
+			want:     []string{"@acme/infra"},
+		},
+		{
+			filePath: "apps/payments/package-lock.json",
+			want:     []string{"@acme/payments", "alice@example.com"},
+		},
+		{
+			filePath: "apps/payments/README.md",
+			want:     []string{"@acme/writers"},
+		},
+		{
+			filePath: "site/docs/Dockerfile",
+			want:     []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filePath, func(t *testing.T) {
+			assert.Equal(t, tt.want, rules.Owners(tt.filePath))
+		})
+	}
+}
+
+func TestAssign(t *testing.T) {
+	results := types.Results{
+		{Target: "alpine:3.16 (alpine 3.16.2)", Class: types.ClassOSPkg},
+		{Target: "apps/payments/package-lock.json", Class: types.ClassLangPkg},
+		{Target: "deploy/main.tf", Class: types.ClassConfig, Owners: []string{"@acme/platform"}},
+		{Target: "vendor/archive.jar", Error: "zip: not a valid zip file"},
+	}
+
+	ownersFile := filepath.Join(t.TempDir(), "owners")
+	require.NoError(t, os.WriteFile(ownersFile, []byte(`
+/apps/  @acme/apps
+*.jar   @acme/java
+`), 0600))
+	rules, err := ownership.Load(ownersFile)
+	require.NoError(t, err)
+
+	ownership.Assign(results, rules)
+
+	want := types.Results{
+		{Target: "alpine:3.16 (alpine 3.16.2)", Class: types.ClassOSPkg},
+		{Target: "apps/payments/package-lock.json", Class: types.ClassLangPkg, Owners: []string{"@acme/apps"}},
+		{Target: "deploy/main.tf", Class: types.ClassConfig, Owners: []string{"@acme/platform"}},
+		{Target: "vendor/archive.jar", Error: "zip: not a valid zip file", Owners: []string{"@acme/java"}},
+	}
+	assert.Equal(t, want, results)
+}
@@ -0,0 +1,160 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/aquasecurity/trivy/pkg/downloader"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+const (
+	// DefaultIndexURL is the URL of the official plugin index
+	DefaultIndexURL = "https://aquasecurity.github.io/trivy-plugin-index/v1/index.yaml"
+
+	indexFile    = "index.yaml"
+	indexVersion = 1
+)
+
+// Index represents a list of plugins available for installation
+type Index struct {
+	Version int          `yaml:"version"`
+	Plugins []IndexEntry `yaml:"plugins"`
+}
+
+// IndexEntry represents a plugin in the index
+type IndexEntry struct {
+	Name       string `yaml:"name"`
+	Maintainer string `yaml:"maintainer"`
+	Summary    string `yaml:"summary"`
+	Repository string `yaml:"repository"`
+
+	// Checksums pins the checksums of the execution files by URI, e.g. "sha256:ab0c...".
+	// They take precedence over the checksums in plugin.yaml, which is in the same repository as the execution files.
+	Checksums map[string]string `yaml:"checksums,omitempty"`
+}
+
+// UpdateIndex downloads the plugin index from the given URL
+func UpdateIndex(ctx context.Context, indexURL string) error {
+	if indexURL == "" {
+		indexURL = DefaultIndexURL
+	}
+
+	log.Logger.Infof("Updating the plugin index from %s...", indexURL)
+	tempDir, err := downloader.DownloadToTempDir(ctx, indexURL)
+	if err != nil {
+		return xerrors.Errorf("plugin index download error: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// The index is saved with the file name in the URL
+	entries, err := os.ReadDir(tempDir)
+	if err != nil {
+		return xerrors.Errorf("read dir error: %w", err)
+	} else if len(entries) != 1 || entries[0].IsDir() {
+		return xerrors.Errorf("%s is not a file", indexURL)
+	}
+
+	b, err := os.ReadFile(filepath.Join(tempDir, entries[0].Name()))
+	if err != nil {
+		return xerrors.Errorf("plugin index read error: %w", err)
+	}
+
+	// Make sure the downloaded index is valid before replacing the current one.
+	if _, err = decodeIndex(b); err != nil {
+		return xerrors.Errorf("invalid plugin index: %w", err)
+	}
+
+	if err = os.MkdirAll(dir(), 0700); err != nil {
+		return xerrors.Errorf("mkdir error: %w", err)
+	}
+	if err = os.WriteFile(indexPath(), b, 0600); err != nil {
+		return xerrors.Errorf("plugin index write error: %w", err)
+	}
+	return nil
+}
+
+// Search searches the plugin index for plugins whose name or summary contains the keyword.
+// The index is downloaded if it doesn't exist yet.
+func Search(ctx context.Context, indexURL, keyword string) (string, error) {
+	if _, err := os.Stat(indexPath()); os.IsNotExist(err) {
+		if err = UpdateIndex(ctx, indexURL); err != nil {
+			return "", err
+		}
+	}
+
+	index, err := loadIndex()
+	if err != nil {
+		return "", err
+	}
+
+	keyword = strings.ToLower(keyword)
+	var entries []IndexEntry
+	for _, p := range index.Plugins {
+		if strings.Contains(strings.ToLower(p.Name), keyword) || strings.Contains(strings.ToLower(p.Summary), keyword) {
+			entries = append(entries, p)
+		}
+	}
+	if len(entries) == 0 {
+		return "No Plugins Found\n", nil
+	}
+
+	buf := &bytes.Buffer{}
+	w := tabwriter.NewWriter(buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tDESCRIPTION\tMAINTAINER\tREPOSITORY")
+	for _, p := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", p.Name, p.Summary, p.Maintainer, p.Repository)
+	}
+	if err = w.Flush(); err != nil {
+		return "", xerrors.Errorf("flush error: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// lookupIndex returns the plugin in the local index by the name or the repository
+func lookupIndex(nameOrRepo string) (IndexEntry, bool) {
+	index, err := loadIndex()
+	if err != nil {
+		log.Logger.Debugf("Unable to load the plugin index: %s", err)
+		return IndexEntry{}, false
+	}
+	for _, p := range index.Plugins {
+		if p.Name == nameOrRepo || p.Repository == nameOrRepo {
+			return p, true
+		}
+	}
+	return IndexEntry{}, false
+}
+
+func loadIndex() (*Index, error) {
+	b, err := os.ReadFile(indexPath())
+	if os.IsNotExist(err) {
+		return nil, xerrors.New(`the plugin index is not found, run "trivy plugin update" first`)
+	} else if err != nil {
+		return nil, xerrors.Errorf("plugin index read error: %w", err)
+	}
+	return decodeIndex(b)
+}
+
+func decodeIndex(b []byte) (*Index, error) {
+	var index Index
+	if err := yaml.Unmarshal(b, &index); err != nil {
+		return nil, xerrors.Errorf("yaml decode error: %w", err)
+	}
+	if index.Version != indexVersion {
+		return nil, xerrors.Errorf("unsupported index version: %d", index.Version)
+	}
+	return &index, nil
+}
+
+func indexPath() string {
+	return filepath.Join(dir(), indexFile)
+}
@@ -49,6 +49,12 @@ type Platform struct {
 	Selector *Selector
 	URI      string
 	Bin      string
+
+	// Checksum is the checksum of the file at URI, e.g. "sha256:ab0c...".
+	// The downloaded file is verified before installation if specified.
+	// It only detects corrupted downloads, as plugin.yaml comes from the same repository as the file.
+	// Checksums pinned in the plugin index are used instead if any.
+	Checksum string
 }
 
 // Selector represents the environment.
@@ -110,15 +116,32 @@ func (p Plugin) selectPlatform() (Platform, error) {
 	return Platform{}, xerrors.New("platform not found")
 }
 
-func (p Plugin) install(ctx context.Context, dst, pwd string) error {
+// install downloads the execution file. The checksums pinned in the plugin index are required if specified.
+func (p Plugin) install(ctx context.Context, dst, pwd string, pinned map[string]string) error {
 	log.Logger.Debugf("Installing the plugin to %s...", dst)
 	platform, err := p.selectPlatform()
 	if err != nil {
 		return xerrors.Errorf("platform selection error: %w", err)
 	}
 
+	checksum := platform.Checksum
+	if len(pinned) > 0 {
+		var ok bool
+		if checksum, ok = pinned[platform.URI]; !ok {
+			return xerrors.Errorf("the checksum of %s is not pinned in the plugin index", platform.URI)
+		}
+	}
+
+	src := platform.URI
+	if checksum != "" {
+		// go-getter verifies the checksum passed as a query parameter
+		src = withChecksum(src, checksum)
+	} else {
+		log.Logger.Warnf("The checksum of %s is not specified in %s, skipping the integrity verification", platform.URI, configFile)
+	}
+
 	log.Logger.Debugf("Downloading the execution file from %s...", platform.URI)
-	if err = downloader.Download(ctx, platform.URI, dst, pwd); err != nil {
+	if err = downloader.Download(ctx, src, dst, pwd); err != nil {
 		return xerrors.Errorf("unable to download the execution file (%s): %w", platform.URI, err)
 	}
 	return nil
@@ -141,6 +164,13 @@ func Install(ctx context.Context, url string, force bool) (Plugin, error) {
 		url = v
 	}
 
+	// Replace plugin names in the index as well, and use the checksums pinned in the index
+	var pinned map[string]string
+	if entry, ok := lookupIndex(url); ok {
+		url = entry.Repository
+		pinned = entry.Checksums
+	}
+
 	if !force {
 		// If the plugin is already installed, it skips installing the plugin.
 		if p, installed := isInstalled(url); installed {
@@ -166,7 +196,9 @@ func Install(ctx context.Context, url string, force bool) (Plugin, error) {
 		return Plugin{}, xerrors.Errorf("failed to determine the plugin dir: %w", err)
 	}
 
-	if err = plugin.install(ctx, pluginDir, tempDir); err != nil {
+	if err = plugin.install(ctx, pluginDir, tempDir, pinned); err != nil {
+		// Don't leave the unverified file
+		_ = os.RemoveAll(pluginDir)
 		return Plugin{}, xerrors.Errorf("failed to install the plugin: %w", err)
 	}
 
@@ -295,6 +327,14 @@ func loadMetadata(dir string) (Plugin, error) {
 	return plugin, nil
 }
 
+func withChecksum(uri, checksum string) string {
+	sep := "?"
+	if strings.Contains(uri, "?") {
+		sep = "&"
+	}
+	return uri + sep + "checksum=" + checksum
+}
+
 func dir() string {
 	return filepath.Join(utils.HomeDir(), pluginsRelativeDir)
 }
@@ -200,6 +200,36 @@ func TestInstall(t *testing.T) {
 			},
 			wantFile: ".trivy/plugins/test_plugin/test.sh",
 		},
+		{
+			name: "with checksum",
+			url:  "testdata/checksum_plugin",
+			want: plugin.Plugin{
+				Name:        "checksum_plugin",
+				Repository:  "github.com/aquasecurity/trivy-plugin-test",
+				Version:     "0.1.0",
+				Usage:       "test",
+				Description: "test",
+				Platforms: []plugin.Platform{
+					{
+						Selector: &plugin.Selector{
+							OS:   "linux",
+							Arch: "amd64",
+						},
+						URI:      "./test.sh",
+						Bin:      "./test.sh",
+						Checksum: "sha256:9597a858a4fbbca8abb4ac64169a38da1987c5c61644295f84abae550db8913b",
+					},
+				},
+				GOOS:   "linux",
+				GOARCH: "amd64",
+			},
+			wantFile: ".trivy/plugins/checksum_plugin/test.sh",
+		},
+		{
+			name:    "checksum mismatch",
+			url:     "testdata/invalid_checksum",
+			wantErr: "Checksums did not match",
+		},
 		{
 			name: "plugin not found",
 			url:  "testdata/not_found",
@@ -271,6 +301,125 @@ func TestInstall(t *testing.T) {
 	}
 }
 
+func TestInstall_Index(t *testing.T) {
+	tests := []struct {
+		name     string
+		plugin   string
+		wantFile string
+		wantErr  string
+	}{
+		{
+			name:     "pinned checksum",
+			plugin:   "checksum_plugin",
+			wantFile: ".trivy/plugins/checksum_plugin/test.sh",
+		},
+		{
+			name:    "pinned checksum mismatch",
+			plugin:  "test_plugin",
+			wantErr: "Checksums did not match",
+		},
+	}
+
+	log.InitLogger(false, true)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The test plugin will be installed here
+			dst := t.TempDir()
+			t.Setenv("XDG_DATA_HOME", dst)
+
+			err := plugin.UpdateIndex(context.Background(), "testdata/index.yaml")
+			require.NoError(t, err)
+
+			got, err := plugin.Install(context.Background(), tt.plugin, false)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				assert.NoDirExists(t, filepath.Join(dst, ".trivy/plugins", tt.plugin))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.plugin, got.Name)
+			assert.FileExists(t, filepath.Join(dst, tt.wantFile))
+		})
+	}
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		name    string
+		keyword string
+		want    string
+	}{
+		{
+			name:    "all plugins",
+			keyword: "",
+			want: `NAME             DESCRIPTION                   MAINTAINER    REPOSITORY
+test_plugin      A simple test plugin          aquasecurity  testdata/test_plugin
+checksum_plugin  A test plugin with checksums  aquasecurity  testdata/checksum_plugin
+`,
+		},
+		{
+			name:    "match summary",
+			keyword: "CHECKSUM",
+			want: `NAME             DESCRIPTION                   MAINTAINER    REPOSITORY
+checksum_plugin  A test plugin with checksums  aquasecurity  testdata/checksum_plugin
+`,
+		},
+		{
+			name:    "no match",
+			keyword: "kubectl",
+			want:    "No Plugins Found\n",
+		},
+	}
+
+	log.InitLogger(false, true)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+			// The index is downloaded on the first search
+			got, err := plugin.Search(context.Background(), "testdata/index.yaml", tt.keyword)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUpdateIndex(t *testing.T) {
+	tests := []struct {
+		name     string
+		indexURL string
+		wantErr  string
+	}{
+		{
+			name:     "happy path",
+			indexURL: "testdata/index.yaml",
+		},
+		{
+			name:     "invalid index",
+			indexURL: "testdata/test_plugin/plugin.yaml",
+			wantErr:  "invalid plugin index",
+		},
+	}
+
+	log.InitLogger(false, true)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := t.TempDir()
+			t.Setenv("XDG_DATA_HOME", dst)
+
+			err := plugin.UpdateIndex(context.Background(), tt.indexURL)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.NoFileExists(t, filepath.Join(dst, ".trivy", "plugins", "index.yaml"))
+				return
+			}
+			require.NoError(t, err)
+			assert.FileExists(t, filepath.Join(dst, ".trivy", "plugins", "index.yaml"))
+		})
+	}
+}
+
 func TestUninstall(t *testing.T) {
 	pluginName := "test_plugin"
 
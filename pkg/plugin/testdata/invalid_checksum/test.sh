@@ -0,0 +1,3 @@
+#!/bin/sh
+
+echo "foo"
\ No newline at end of file
@@ -0,0 +1,291 @@
+package policy
+
+import (
+	"context"
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/image"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/oci"
+	"github.com/aquasecurity/trivy/pkg/sbom"
+)
+
+const (
+	// BundleMediaType is the media type of the layer of check bundles, which is the same as OPA bundles
+	BundleMediaType = "application/vnd.cncf.openpolicyagent.layer.v1.tar+gzip"
+
+	ociScheme = "oci://"
+
+	// Check bundles are cached under the cache directory, e.g. ~/.cache/trivy/checks/ghcr.io/org/policies/1.0
+	bundleDir    = "checks"
+	contentDir   = "content"
+	metadataFile = "metadata.json"
+
+	// bundleManifest is the manifest of OPA bundles, which has the roots of the packages
+	bundleManifest = ".manifest"
+
+	// Annotation and payload of cosign signatures
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+	cosignSignatureType       = "cosign container image signature"
+)
+
+// BundleOption holds the options for downloading check bundles
+type BundleOption struct {
+	CacheDir string
+
+	// PublicKey verifies the cosign signatures of the bundles if it is specified
+	PublicKey crypto.PublicKey
+
+	// SkipUpdate uses the cached bundles without checking the registries
+	SkipUpdate bool
+
+	// Offline uses only the cached bundles, and fails if they are not cached
+	Offline bool
+
+	Insecure bool
+	Quiet    bool
+}
+
+// Bundle is a check bundle downloaded into the cache directory
+type Bundle struct {
+	// Dir has the Rego checks and the data of the bundle
+	Dir string
+
+	// Namespaces are the roots in the bundle manifest, which should be evaluated
+	Namespaces []string
+}
+
+type bundleMetadata struct {
+	Digest       string
+	Verified     bool
+	DownloadedAt time.Time
+}
+
+// DownloadBundles downloads the check bundles, e.g. "oci://ghcr.io/org/policies:1.0", into the cache directory.
+// Bundles are downloaded again only if the digests are changed.
+// The cached bundles are used if the registries are not available.
+func DownloadBundles(ctx context.Context, refs []string, opt BundleOption) ([]Bundle, error) {
+	var bundles []Bundle
+	for _, r := range refs {
+		if !strings.HasPrefix(r, ociScheme) {
+			return nil, xerrors.Errorf("unsupported check bundle (%s): it must start with %s", r, ociScheme)
+		}
+		ref, err := name.ParseReference(strings.TrimPrefix(r, ociScheme))
+		if err != nil {
+			return nil, xerrors.Errorf("check bundle reference error (%s): %w", r, err)
+		}
+
+		dir, err := downloadBundle(ctx, ref, opt)
+		if err != nil {
+			return nil, xerrors.Errorf("check bundle error (%s): %w", r, err)
+		}
+
+		bundle := Bundle{Dir: dir}
+		if bundle.Namespaces, err = bundleRoots(dir); err != nil {
+			return nil, xerrors.Errorf("check bundle manifest error (%s): %w", r, err)
+		}
+		bundles = append(bundles, bundle)
+	}
+	return bundles, nil
+}
+
+// BundleDigest returns the digest of the cached check bundle, or an empty string if it is not cached
+func BundleDigest(cacheDir, r string) string {
+	ref, err := name.ParseReference(strings.TrimPrefix(r, ociScheme))
+	if err != nil {
+		return ""
+	}
+	meta, cached := readMetadata(bundleCacheDir(cacheDir, ref))
+	if !cached {
+		return ""
+	}
+	return meta.Digest
+}
+
+// bundleCacheDir returns the cache directory of the bundle, e.g. ~/.cache/trivy/checks/ghcr.io/org/policies/1.0
+func bundleCacheDir(cacheDir string, ref name.Reference) string {
+	identifier := strings.ReplaceAll(ref.Identifier(), ":", "-")
+	return filepath.Join(cacheDir, bundleDir, ref.Context().RegistryStr(), filepath.FromSlash(ref.Context().RepositoryStr()), identifier)
+}
+
+func downloadBundle(ctx context.Context, ref name.Reference, opt BundleOption) (string, error) {
+	cacheDir := bundleCacheDir(opt.CacheDir, ref)
+	dst := filepath.Join(cacheDir, contentDir)
+
+	meta, cached := readMetadata(cacheDir)
+	if cached && opt.SkipUpdate && (opt.PublicKey == nil || meta.Verified) {
+		log.Logger.Debugf("Using the cached check bundle: %s", ref)
+		return dst, nil
+	} else if opt.Offline {
+		if cached {
+			return "", xerrors.New("the cached bundle is not verified with the public key; download it without --offline-scan first")
+		}
+		return "", xerrors.New("the bundle is not cached; download it without --offline-scan first")
+	}
+
+	art, err := oci.NewArtifact(ref.String(), BundleMediaType, opt.Quiet, opt.Insecure)
+	if err != nil {
+		if cached && (opt.PublicKey == nil || meta.Verified) {
+			log.Logger.Warnf("Using the cached check bundle as %s is not available: %s", ref, err)
+			return dst, nil
+		}
+		return "", xerrors.Errorf("OCI artifact error: %w", err)
+	}
+
+	digest, err := art.Digest()
+	if err != nil {
+		return "", xerrors.Errorf("digest error: %w", err)
+	}
+	if cached && meta.Digest == digest && (opt.PublicKey == nil || meta.Verified) {
+		log.Logger.Debugf("The check bundle is up to date: %s", ref)
+		return dst, nil
+	}
+
+	if opt.PublicKey != nil {
+		if err = verifyBundle(ref, digest, opt); err != nil {
+			return "", xerrors.Errorf("signature verification error: %w", err)
+		}
+		log.Logger.Infof("Verified the signature of the check bundle: %s", ref)
+	}
+
+	log.Logger.Infof("Downloading the check bundle from %s...", ref)
+	if err = os.RemoveAll(dst); err != nil {
+		return "", xerrors.Errorf("unable to remove the cached bundle: %w", err)
+	}
+	if err = art.Download(ctx, dst); err != nil {
+		return "", xerrors.Errorf("download error: %w", err)
+	}
+
+	err = writeMetadata(cacheDir, bundleMetadata{
+		Digest:       digest,
+		Verified:     opt.PublicKey != nil,
+		DownloadedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return "", xerrors.Errorf("metadata error: %w", err)
+	}
+	return dst, nil
+}
+
+// simpleSigning is the payload signed by cosign
+type simpleSigning struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// verifyBundle verifies the cosign signatures of the bundle, which are stored with the tag "sha256-<digest>.sig".
+// One of the signatures must be valid with the public key and must be for the digest of the bundle.
+func verifyBundle(ref name.Reference, digest string, opt BundleOption) error {
+	sigRef := ref.Context().Tag(strings.ReplaceAll(digest, ":", "-") + ".sig")
+	img, err := remote.Image(sigRef, image.RemoteOptions(opt.Insecure)...)
+	if err != nil {
+		return xerrors.Errorf("unable to get the signatures (%s): %w", sigRef, err)
+	}
+
+	manifest, err := img.Manifest()
+	if err != nil {
+		return xerrors.Errorf("signature manifest error: %w", err)
+	}
+
+	for _, desc := range manifest.Layers {
+		sig, err := base64.StdEncoding.DecodeString(desc.Annotations[cosignSignatureAnnotation])
+		if err != nil || len(sig) == 0 {
+			continue
+		}
+
+		payload, err := layerContent(img, desc.Digest)
+		if err != nil {
+			return xerrors.Errorf("signature payload error: %w", err)
+		}
+		if !sbom.VerifySignature(opt.PublicKey, payload, sig) {
+			continue
+		}
+
+		var s simpleSigning
+		if err = json.Unmarshal(payload, &s); err != nil {
+			continue
+		}
+		if s.Critical.Type == cosignSignatureType && s.Critical.Image.DockerManifestDigest == digest {
+			return nil
+		}
+	}
+	return xerrors.New("no valid signature found")
+}
+
+func layerContent(img v1.Image, digest v1.Hash) ([]byte, error) {
+	layer, err := img.LayerByDigest(digest)
+	if err != nil {
+		return nil, xerrors.Errorf("layer error: %w", err)
+	}
+	rc, err := layer.Compressed()
+	if err != nil {
+		return nil, xerrors.Errorf("layer content error: %w", err)
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// bundleRoots returns the roots in the bundle manifest
+func bundleRoots(dir string) ([]string, error) {
+	b, err := os.ReadFile(filepath.Join(dir, bundleManifest))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, xerrors.Errorf("read error: %w", err)
+	}
+
+	var manifest struct {
+		Roots []string `json:"roots"`
+	}
+	if err = json.Unmarshal(b, &manifest); err != nil {
+		return nil, xerrors.Errorf("json error: %w", err)
+	}
+
+	var namespaces []string
+	for _, root := range manifest.Roots {
+		// Roots are paths, e.g. "acme/kubernetes", while namespaces are packages, e.g. "acme.kubernetes"
+		if ns := strings.ReplaceAll(strings.Trim(root, "/"), "/", "."); ns != "" {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces, nil
+}
+
+func readMetadata(dir string) (bundleMetadata, bool) {
+	b, err := os.ReadFile(filepath.Join(dir, metadataFile))
+	if err != nil {
+		return bundleMetadata{}, false
+	}
+	var meta bundleMetadata
+	if err = json.Unmarshal(b, &meta); err != nil {
+		return bundleMetadata{}, false
+	}
+	return meta, true
+}
+
+func writeMetadata(dir string, meta bundleMetadata) error {
+	b, err := json.Marshal(meta)
+	if err != nil {
+		return xerrors.Errorf("json error: %w", err)
+	}
+	if err = os.MkdirAll(dir, 0700); err != nil {
+		return xerrors.Errorf("mkdir error: %w", err)
+	}
+	return os.WriteFile(filepath.Join(dir, metadataFile), b, 0600)
+}
@@ -0,0 +1,259 @@
+package policy_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/policy"
+	"github.com/aquasecurity/trivy/pkg/retry"
+)
+
+func TestDownloadBundles(t *testing.T) {
+	signer, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	defer ts.Close()
+	u, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+
+	// The bundles are pushed to different repositories as they have the same digest
+	signed := u.Host + "/acme/checks:signed"
+	unsigned := u.Host + "/acme/unsigned:latest"
+	pushBundle(t, signed, signer)
+	pushBundle(t, unsigned, nil)
+
+	tests := []struct {
+		name    string
+		refs    []string
+		key     crypto.PublicKey
+		want    []policy.Bundle
+		wantErr string
+	}{
+		{
+			name: "signed",
+			refs: []string{"oci://" + signed},
+			key:  &signer.PublicKey,
+			want: []policy.Bundle{
+				{
+					Dir:        filepath.Join("checks", u.Host, "acme", "checks", "signed", "content"),
+					Namespaces: []string{"acme.dockerfile"},
+				},
+			},
+		},
+		{
+			name: "no verification",
+			refs: []string{"oci://" + unsigned},
+			want: []policy.Bundle{
+				{
+					Dir:        filepath.Join("checks", u.Host, "acme", "unsigned", "latest", "content"),
+					Namespaces: []string{"acme.dockerfile"},
+				},
+			},
+		},
+		{
+			name:    "wrong key",
+			refs:    []string{"oci://" + signed},
+			key:     &other.PublicKey,
+			wantErr: "no valid signature found",
+		},
+		{
+			name:    "not signed",
+			refs:    []string{"oci://" + unsigned},
+			key:     &signer.PublicKey,
+			wantErr: "unable to get the signatures",
+		},
+		{
+			name:    "unsupported scheme",
+			refs:    []string{signed},
+			wantErr: "it must start with oci://",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cacheDir := t.TempDir()
+			got, err := policy.DownloadBundles(context.Background(), tt.refs, policy.BundleOption{
+				CacheDir:  cacheDir,
+				PublicKey: tt.key,
+				Quiet:     true,
+			})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			for i := range tt.want {
+				tt.want[i].Dir = filepath.Join(cacheDir, tt.want[i].Dir)
+			}
+			assert.Equal(t, tt.want, got)
+			assert.FileExists(t, filepath.Join(got[0].Dir, "user.rego"))
+		})
+	}
+}
+
+func TestDownloadBundles_Cache(t *testing.T) {
+	require.NoError(t, retry.SetPolicy(retry.DefaultRetries, time.Millisecond))
+	defer retry.SetPolicy(retry.DefaultRetries, retry.DefaultBackoff)
+
+	ts := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	u, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+
+	ref := u.Host + "/acme/checks:latest"
+	pushBundle(t, ref, nil)
+
+	cacheDir := t.TempDir()
+	opt := policy.BundleOption{
+		CacheDir: cacheDir,
+		Quiet:    true,
+	}
+	want, err := policy.DownloadBundles(context.Background(), []string{"oci://" + ref}, opt)
+	require.NoError(t, err)
+
+	// The cached bundle is used when the registry is not available
+	ts.Close()
+	got, err := policy.DownloadBundles(context.Background(), []string{"oci://" + ref}, opt)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+	assert.FileExists(t, filepath.Join(got[0].Dir, "user.rego"))
+}
+
+func TestDownloadBundles_Offline(t *testing.T) {
+	var requests int
+	reg := registry.New(registry.Logger(log.New(io.Discard, "", 0)))
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		reg.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+	u, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+
+	ref := "oci://" + u.Host + "/acme/checks:latest"
+	pushBundle(t, strings.TrimPrefix(ref, "oci://"), nil)
+
+	cacheDir := t.TempDir()
+	offline := policy.BundleOption{
+		CacheDir:   cacheDir,
+		SkipUpdate: true,
+		Offline:    true,
+		Quiet:      true,
+	}
+
+	// The registry is not contacted even if the bundle is not cached
+	requests = 0
+	_, err = policy.DownloadBundles(context.Background(), []string{ref}, offline)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "the bundle is not cached; download it without --offline-scan first")
+	assert.Zero(t, requests)
+
+	want, err := policy.DownloadBundles(context.Background(), []string{ref}, policy.BundleOption{
+		CacheDir: cacheDir,
+		Quiet:    true,
+	})
+	require.NoError(t, err)
+
+	requests = 0
+	got, err := policy.DownloadBundles(context.Background(), []string{ref}, offline)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+	assert.Zero(t, requests)
+
+	// The cached bundle is not verified
+	signer, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	offline.PublicKey = &signer.PublicKey
+	_, err = policy.DownloadBundles(context.Background(), []string{ref}, offline)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "the cached bundle is not verified with the public key")
+	assert.Zero(t, requests)
+}
+
+// pushBundle pushes the bundle in testdata/bundle, and its cosign signature if the key is given
+func pushBundle(t *testing.T, ref string, key *ecdsa.PrivateKey) {
+	r, err := name.ParseReference(ref)
+	require.NoError(t, err)
+
+	img, err := mutate.Append(empty.Image, mutate.Addendum{
+		Layer: static.NewLayer(bundleArchive(t), policy.BundleMediaType),
+		Annotations: map[string]string{
+			"org.opencontainers.image.title": "bundle.tar.gz",
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(r, img))
+
+	if key == nil {
+		return
+	}
+
+	digest, err := img.Digest()
+	require.NoError(t, err)
+	payload := fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"%s"},"image":{"docker-manifest-digest":"%s"},"type":"cosign container image signature"},"optional":null}`,
+		r.Context().Name(), digest)
+	h := sha256.Sum256([]byte(payload))
+	sig, err := ecdsa.SignASN1(rand.Reader, key, h[:])
+	require.NoError(t, err)
+
+	sigImg, err := mutate.Append(empty.Image, mutate.Addendum{
+		Layer: static.NewLayer([]byte(payload), "application/vnd.dev.cosign.simplesigning.v1+json"),
+		Annotations: map[string]string{
+			"dev.cosignproject.cosign/signature": base64.StdEncoding.EncodeToString(sig),
+		},
+	})
+	require.NoError(t, err)
+	sigRef := r.Context().Tag(strings.ReplaceAll(digest.String(), ":", "-") + ".sig")
+	require.NoError(t, remote.Write(sigRef, sigImg))
+}
+
+func bundleArchive(t *testing.T) []byte {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for _, f := range []string{".manifest", "user.rego"} {
+		b, err := os.ReadFile(filepath.Join("testdata", "bundle", f))
+		require.NoError(t, err)
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name: f,
+			Mode: 0644,
+			Size: int64(len(b)),
+		}))
+		_, err = tw.Write(b)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	return buf.Bytes()
+}
@@ -0,0 +1,92 @@
+package policy
+
+import (
+	"os"
+	"strings"
+
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Overrides maps check IDs to the metadata overriding the check metadata
+type Overrides map[string]Override
+
+// Override holds the severity and the metadata of a check defined by an organization
+type Override struct {
+	ID          string   `yaml:"id"`
+	Severity    string   `yaml:"severity"`
+	Owner       string   `yaml:"owner"`
+	Remediation string   `yaml:"remediation"`
+	References  []string `yaml:"references"`
+}
+
+// LoadOverrides reads the overrides file, e.g.
+//
+//	overrides:
+//	  - id: AVD-AWS-0086
+//	    severity: LOW
+//	    owner: platform-team
+//	    remediation: Use the "secure-bucket" module
+func LoadOverrides(filePath string) (Overrides, error) {
+	if filePath == "" {
+		return nil, nil
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to open the overrides file (%s): %w", filePath, err)
+	}
+	defer f.Close()
+
+	var file struct {
+		Overrides []Override `yaml:"overrides"`
+	}
+	if err = yaml.NewDecoder(f).Decode(&file); err != nil {
+		return nil, xerrors.Errorf("unable to parse the overrides file (%s): %w", filePath, err)
+	}
+
+	overrides := Overrides{}
+	for _, o := range file.Overrides {
+		if o.ID == "" {
+			return nil, xerrors.New("check ID must be specified in overrides")
+		}
+		if o.Severity != "" {
+			if _, err = dbTypes.NewSeverity(strings.ToUpper(o.Severity)); err != nil {
+				return nil, xerrors.Errorf("invalid severity of %s: %w", o.ID, err)
+			}
+			o.Severity = strings.ToUpper(o.Severity)
+		}
+		overrides[strings.ToUpper(o.ID)] = o
+	}
+	return overrides, nil
+}
+
+// Apply overrides the severities and the metadata of the detected misconfigurations.
+// It should be applied before filtering by severity so that the overridden severities are filtered.
+func (o Overrides) Apply(results types.Results) {
+	if len(o) == 0 {
+		return
+	}
+	for i := range results {
+		for j := range results[i].Misconfigurations {
+			misconf := &results[i].Misconfigurations[j]
+			override, ok := o[strings.ToUpper(misconf.ID)]
+			if !ok {
+				continue
+			}
+			if override.Severity != "" {
+				misconf.Severity = override.Severity
+			}
+			if override.Owner != "" {
+				misconf.Owner = override.Owner
+			}
+			if override.Remediation != "" {
+				misconf.Resolution = override.Remediation
+			}
+			misconf.References = append(misconf.References, override.References...)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package policy_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/policy"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestLoadOverrides(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		want     policy.Overrides
+		wantErr  string
+	}{
+		{
+			name:     "happy path",
+			filePath: "testdata/overrides/overrides.yaml",
+			want: policy.Overrides{
+				"AVD-AWS-0086": {
+					ID:          "AVD-AWS-0086",
+					Severity:    "LOW",
+					Owner:       "platform-team",
+					Remediation: "Use the secure-bucket module",
+					References:  []string{"https://wiki.example.com/s3"},
+				},
+				"DS002": {
+					ID:       "ds002",
+					Severity: "CRITICAL",
+				},
+			},
+		},
+		{
+			name: "no file",
+		},
+		{
+			name:     "invalid severity",
+			filePath: "testdata/overrides/invalid-severity.yaml",
+			wantErr:  "invalid severity of AVD-AWS-0086",
+		},
+		{
+			name:     "no ID",
+			filePath: "testdata/overrides/no-id.yaml",
+			wantErr:  "check ID must be specified",
+		},
+		{
+			name:     "missing file",
+			filePath: "testdata/overrides/missing.yaml",
+			wantErr:  "unable to open the overrides file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := policy.LoadOverrides(tt.filePath)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestOverrides_Apply(t *testing.T) {
+	overrides, err := policy.LoadOverrides("testdata/overrides/overrides.yaml")
+	require.NoError(t, err)
+
+	results := types.Results{
+		{
+			Target: "main.tf",
+			Misconfigurations: []types.DetectedMisconfiguration{
+				{
+					ID:         "AVD-AWS-0086",
+					Severity:   "HIGH",
+					Resolution: "Enable blocking any PUT calls with a public ACL specified",
+					References: []string{"https://avd.aquasec.com/misconfig/avd-aws-0086"},
+				},
+				{
+					ID:       "AVD-AWS-0087",
+					Severity: "HIGH",
+				},
+			},
+		},
+		{
+			Target: "Dockerfile",
+			Misconfigurations: []types.DetectedMisconfiguration{
+				{
+					ID:       "DS002",
+					Severity: "HIGH",
+				},
+			},
+		},
+	}
+	overrides.Apply(results)
+
+	want := types.Results{
+		{
+			Target: "main.tf",
+			Misconfigurations: []types.DetectedMisconfiguration{
+				{
+					ID:         "AVD-AWS-0086",
+					Severity:   "LOW",
+					Owner:      "platform-team",
+					Resolution: "Use the secure-bucket module",
+					References: []string{
+						"https://avd.aquasec.com/misconfig/avd-aws-0086",
+						"https://wiki.example.com/s3",
+					},
+				},
+				{
+					ID:       "AVD-AWS-0087",
+					Severity: "HIGH",
+				},
+			},
+		},
+		{
+			Target: "Dockerfile",
+			Misconfigurations: []types.DetectedMisconfiguration{
+				{
+					ID:       "DS002",
+					Severity: "CRITICAL",
+				},
+			},
+		},
+	}
+	assert.Equal(t, want, results)
+}
@@ -0,0 +1,178 @@
+package policy
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+//go:embed scaffold
+var scaffoldFS embed.FS
+
+var (
+	checkTemplate = template.Must(template.ParseFS(scaffoldFS, "scaffold/check.rego.tmpl"))
+	testTemplate  = template.Must(template.ParseFS(scaffoldFS, "scaffold/check_test.rego.tmpl"))
+
+	idRegexp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+)
+
+// checkTypes has the examples of each input type, which are replaced by authors
+var checkTypes = map[string]struct {
+	name    string
+	schema  string
+	rule    string
+	denied  string
+	allowed string
+}{
+	"dockerfile": {
+		name:   "Dockerfile",
+		schema: "dockerfile",
+		rule: `	cmd := input.Stages[_].Commands[_]
+	cmd.Cmd == "user"
+	cmd.Value[0] == "root"
+	msg := "Specify a non-root user"`,
+		denied:  `{"Stages": [{"Name": "alpine:3.16", "Commands": [{"Cmd": "user", "Value": ["root"]}]}]}`,
+		allowed: `{"Stages": [{"Name": "alpine:3.16", "Commands": [{"Cmd": "user", "Value": ["nobody"]}]}]}`,
+	},
+	"kubernetes": {
+		name:   "Kubernetes",
+		schema: "kubernetes",
+		rule: `	input.kind == "Deployment"
+	not input.metadata.labels.owner
+	msg := sprintf("Deployment '%s' should have the owner label", [input.metadata.name])`,
+		denied:  `{"kind": "Deployment", "metadata": {"name": "app"}}`,
+		allowed: `{"kind": "Deployment", "metadata": {"name": "app", "labels": {"owner": "platform"}}}`,
+	},
+	"yaml": {
+		name: "YAML",
+		rule: `	input.debug == true
+	msg := "Debug mode should be disabled"`,
+		denied:  `{"debug": true}`,
+		allowed: `{"debug": false}`,
+	},
+	"json": {
+		name: "JSON",
+		rule: `	input.debug == true
+	msg := "Debug mode should be disabled"`,
+		denied:  `{"debug": true}`,
+		allowed: `{"debug": false}`,
+	},
+}
+
+// CheckTypes returns the input types supported by Scaffold
+func CheckTypes() []string {
+	types := maps.Keys(checkTypes)
+	slices.Sort(types)
+	return types
+}
+
+// ScaffoldOption holds the options for generating a custom check
+type ScaffoldOption struct {
+	// Type is the input type, e.g. "dockerfile"
+	Type string
+
+	ID       string
+	Title    string
+	Severity string
+
+	// Namespace is the prefix of the package, e.g. "user" for "user.dockerfile.ID001"
+	Namespace string
+}
+
+type scaffold struct {
+	Package  string
+	ID       string
+	Title    string
+	Severity string
+	Type     string
+	TypeName string
+	Schema   string
+	Rule     string
+	Denied   string
+	Allowed  string
+}
+
+// Scaffold generates a skeleton custom check and its test in the directory, e.g. "id001.rego" and "id001_test.rego".
+// The check has the metadata, the input selector and the schema annotation of the type,
+// and the test passes as it is so that authors can start from a working check.
+func Scaffold(dir string, opt ScaffoldOption) ([]string, error) {
+	t, ok := checkTypes[opt.Type]
+	if !ok {
+		return nil, xerrors.Errorf("unsupported type %q: it must be one of %s", opt.Type, strings.Join(CheckTypes(), ", "))
+	}
+	if !idRegexp.MatchString(opt.ID) {
+		return nil, xerrors.Errorf("invalid ID %q: it must start with a letter and contain only letters, digits and underscores", opt.ID)
+	}
+	severity, err := dbTypes.NewSeverity(strings.ToUpper(opt.Severity))
+	if err != nil {
+		return nil, xerrors.Errorf("severity error: %w", err)
+	}
+
+	namespace := opt.Namespace
+	if namespace == "" {
+		namespace = "user"
+	}
+	title := opt.Title
+	if title == "" {
+		title = "TODO: describe what the check detects"
+	}
+
+	data := scaffold{
+		Package:  strings.Join([]string{namespace, opt.Type, opt.ID}, "."),
+		ID:       opt.ID,
+		Title:    strings.ReplaceAll(title, `"`, `\"`),
+		Severity: severity.String(),
+		Type:     opt.Type,
+		TypeName: t.name,
+		Schema:   t.schema,
+		Rule:     t.rule,
+		Denied:   t.denied,
+		Allowed:  t.allowed,
+	}
+
+	name := strings.ToLower(opt.ID)
+	files := []struct {
+		path string
+		tmpl *template.Template
+	}{
+		{filepath.Join(dir, name+".rego"), checkTemplate},
+		{filepath.Join(dir, name+"_test.rego"), testTemplate},
+	}
+
+	// Existing checks are not overwritten
+	for _, f := range files {
+		if _, err = os.Stat(f.path); err == nil {
+			return nil, xerrors.Errorf("%s already exists", f.path)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, xerrors.Errorf("stat error: %w", err)
+		}
+	}
+
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return nil, xerrors.Errorf("mkdir error: %w", err)
+	}
+
+	var written []string
+	for _, f := range files {
+		var buf bytes.Buffer
+		if err = f.tmpl.Execute(&buf, data); err != nil {
+			return nil, xerrors.Errorf("template error: %w", err)
+		}
+		if err = os.WriteFile(f.path, buf.Bytes(), 0644); err != nil {
+			return nil, xerrors.Errorf("write error: %w", err)
+		}
+		written = append(written, f.path)
+	}
+	return written, nil
+}
@@ -0,0 +1,120 @@
+package policy_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/policy"
+)
+
+func TestScaffold(t *testing.T) {
+	tests := []struct {
+		name      string
+		opt       policy.ScaffoldOption
+		existing  string
+		wantFiles []string
+		wantErr   string
+	}{
+		{
+			name: "dockerfile",
+			opt: policy.ScaffoldOption{
+				Type:     "dockerfile",
+				ID:       "ID001",
+				Title:    "Non-root user",
+				Severity: "high",
+			},
+			wantFiles: []string{"id001.rego", "id001_test.rego"},
+		},
+		{
+			name: "kubernetes",
+			opt: policy.ScaffoldOption{
+				Type:      "kubernetes",
+				ID:        "ACME001",
+				Severity:  "LOW",
+				Namespace: "acme",
+			},
+			wantFiles: []string{"acme001.rego", "acme001_test.rego"},
+		},
+		{
+			name: "yaml",
+			opt: policy.ScaffoldOption{
+				Type:     "yaml",
+				ID:       "ID002",
+				Severity: "MEDIUM",
+			},
+			wantFiles: []string{"id002.rego", "id002_test.rego"},
+		},
+		{
+			name: "unsupported type",
+			opt: policy.ScaffoldOption{
+				Type:     "ansible",
+				ID:       "ID001",
+				Severity: "HIGH",
+			},
+			wantErr: `unsupported type "ansible": it must be one of dockerfile, json, kubernetes, yaml`,
+		},
+		{
+			name: "invalid ID",
+			opt: policy.ScaffoldOption{
+				Type:     "dockerfile",
+				ID:       "ID-001",
+				Severity: "HIGH",
+			},
+			wantErr: `invalid ID "ID-001"`,
+		},
+		{
+			name: "invalid severity",
+			opt: policy.ScaffoldOption{
+				Type:     "dockerfile",
+				ID:       "ID001",
+				Severity: "URGENT",
+			},
+			wantErr: "severity error",
+		},
+		{
+			name: "existing check",
+			opt: policy.ScaffoldOption{
+				Type:     "dockerfile",
+				ID:       "ID001",
+				Severity: "HIGH",
+			},
+			existing: "id001.rego",
+			wantErr:  "id001.rego already exists",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.existing != "" {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, tt.existing), nil, 0644))
+			}
+
+			got, err := policy.Scaffold(dir, tt.opt)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var want []string
+			for _, f := range tt.wantFiles {
+				want = append(want, filepath.Join(dir, f))
+			}
+			assert.Equal(t, want, got)
+
+			// The generated tests pass as they are
+			results, err := policy.RunTests(context.Background(), []string{dir}, policy.TestOption{})
+			require.NoError(t, err)
+			require.Len(t, results, 2)
+			for _, r := range results {
+				assert.True(t, r.Pass(), r.String())
+			}
+		})
+	}
+}
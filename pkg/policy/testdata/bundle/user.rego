@@ -0,0 +1,19 @@
+package acme.dockerfile.user
+
+__rego_metadata__ := {
+	"id": "ACME001",
+	"title": "Non-root user",
+	"severity": "HIGH",
+	"type": "Dockerfile Custom Check",
+}
+
+__rego_input__ := {"selector": [{"type": "dockerfile"}]}
+
+deny[msg] {
+	not any_user
+	msg := "Specify a non-root USER"
+}
+
+any_user {
+	input.stages[_][_].Cmd == "user"
+}
@@ -0,0 +1,20 @@
+# METADATA
+# schemas:
+#   - input: schema["dockerfile"]
+package user.dockerfile.ID001
+
+__rego_metadata__ := {
+	"id": "ID001",
+	"title": "Non-root user",
+	"severity": "HIGH",
+	"type": "Dockerfile Custom Check",
+}
+
+deny[msg] {
+	not any_user
+	msg := "Specify a non-root USER"
+}
+
+any_user {
+	input.Stages[_].Commands[_].Cmd == "user"
+}
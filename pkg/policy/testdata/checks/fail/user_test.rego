@@ -0,0 +1,13 @@
+package user.dockerfile.ID001
+
+test_denied {
+	count(deny) == 1 with input as {"Stages": [{"Name": "alpine", "Commands": [{"Cmd": "from", "Value": ["alpine"]}]}]}
+}
+
+test_allowed {
+	count(deny) == 0 with input as {"Stages": [{"Name": "alpine", "Commands": [{"Cmd": "user", "Value": ["nobody"]}]}]}
+}
+
+test_fails {
+	count(deny) == 1 with input as {"Stages": [{"Name": "alpine", "Commands": [{"Cmd": "user", "Value": ["nobody"]}]}]}
+}
@@ -0,0 +1,110 @@
+package policy
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/loader"
+	"github.com/open-policy-agent/opa/storage/inmem"
+	"github.com/open-policy-agent/opa/tester"
+	"golang.org/x/xerrors"
+)
+
+// schemaDir has the JSON schemas of the inputs passed to checks, e.g. "dockerfile.json"
+const schemaDir = "schemas"
+
+//go:embed schemas
+var schemaFS embed.FS
+
+// TestOption holds the options for testing checks
+type TestOption struct {
+	// DataPaths are loaded in addition to the data files next to the checks
+	DataPaths []string
+
+	// Run is a regular expression selecting tests to run
+	Run string
+
+	// Trace records the evaluation of failed tests
+	Trace bool
+
+	Timeout time.Duration
+}
+
+// RunTests runs the tests, i.e. rules prefixed with "test_", in the Rego files under the paths like "opa test".
+// The schemas of Trivy inputs are loaded so that checks annotated with them are type-checked, e.g.
+//
+//	# METADATA
+//	# schemas:
+//	#   - input: schema["dockerfile"]
+func RunTests(ctx context.Context, paths []string, opt TestOption) ([]*tester.Result, error) {
+	schemas, err := Schemas()
+	if err != nil {
+		return nil, xerrors.Errorf("schema error: %w", err)
+	}
+
+	loaded, err := loader.NewFileLoader().WithProcessAnnotation(true).Filtered(append(paths, opt.DataPaths...), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("load error: %w", err)
+	}
+
+	modules := map[string]*ast.Module{}
+	for _, m := range loaded.Modules {
+		modules[m.Name] = m.Parsed
+	}
+
+	compiler := ast.NewCompiler().
+		WithSchemas(schemas).
+		WithEnablePrintStatements(true)
+
+	runner := tester.NewRunner().
+		SetCompiler(compiler).
+		SetStore(inmem.NewFromObject(loaded.Documents)).
+		SetModules(modules).
+		Filter(opt.Run).
+		EnableTracing(opt.Trace).
+		CapturePrintOutput(true)
+	if opt.Timeout > 0 {
+		runner = runner.SetTimeout(opt.Timeout)
+	}
+
+	ch, err := runner.RunTests(ctx, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("compile error: %w", err)
+	}
+
+	var results []*tester.Result
+	for r := range ch {
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// Schemas returns the schemas of Trivy inputs, which are referred as schema["<name>"], e.g. schema["kubernetes"]
+func Schemas() (*ast.SchemaSet, error) {
+	entries, err := schemaFS.ReadDir(schemaDir)
+	if err != nil {
+		return nil, xerrors.Errorf("read dir error: %w", err)
+	}
+
+	schemas := ast.NewSchemaSet()
+	for _, e := range entries {
+		b, err := schemaFS.ReadFile(path.Join(schemaDir, e.Name()))
+		if err != nil {
+			return nil, xerrors.Errorf("read error (%s): %w", e.Name(), err)
+		}
+
+		var schema interface{}
+		if err = json.Unmarshal(b, &schema); err != nil {
+			return nil, xerrors.Errorf("json error (%s): %w", e.Name(), err)
+		}
+
+		name := strings.TrimSuffix(e.Name(), path.Ext(e.Name()))
+		schemas.Put(ast.SchemaRootRef.Append(ast.StringTerm(name)), schema)
+	}
+	return schemas, nil
+}
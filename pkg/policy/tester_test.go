@@ -0,0 +1,76 @@
+package policy_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/policy"
+)
+
+func TestRunTests(t *testing.T) {
+	type result struct {
+		Name string
+		Pass bool
+	}
+	tests := []struct {
+		name    string
+		paths   []string
+		opt     policy.TestOption
+		want    []result
+		wantErr string
+	}{
+		{
+			name:  "pass",
+			paths: []string{"testdata/checks/pass"},
+			want: []result{
+				{Name: "test_denied", Pass: true},
+				{Name: "test_allowed", Pass: true},
+			},
+		},
+		{
+			name:  "fail",
+			paths: []string{"testdata/checks/fail"},
+			want: []result{
+				{Name: "test_denied", Pass: true},
+				{Name: "test_allowed", Pass: true},
+				{Name: "test_fails", Pass: false},
+			},
+		},
+		{
+			name:  "filter",
+			paths: []string{"testdata/checks/fail"},
+			opt: policy.TestOption{
+				Run: "test_(denied|allowed)",
+			},
+			want: []result{
+				{Name: "test_denied", Pass: true},
+				{Name: "test_allowed", Pass: true},
+			},
+		},
+		{
+			name:    "schema violation",
+			paths:   []string{"testdata/checks/typo"},
+			wantErr: "undefined ref: input.Stages[_].Commands[_].Command",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := policy.RunTests(context.Background(), tt.paths, tt.opt)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var got []result
+			for _, r := range results {
+				got = append(got, result{Name: r.Name, Pass: r.Pass()})
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
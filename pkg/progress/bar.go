@@ -0,0 +1,74 @@
+package progress
+
+import (
+	"io"
+
+	"github.com/cheggaaa/pb/v3"
+)
+
+const (
+	statsElement = "analysisStats"
+	statsKey     = "analysisStats"
+
+	layersTemplate = `Analyzing layers {{counters .}} {{bar . "[" "=" ">" " " "]"}} {{` + statsElement + ` .}} {{etime .}}`
+	filesTemplate  = `Analyzing {{` + statsElement + ` .}} {{etime .}}`
+)
+
+func init() {
+	pb.RegisterElement(statsElement, pb.ElementFunc(func(state *pb.State, _ ...string) string {
+		stats, ok := state.Get(statsKey).(*layerStats)
+		if !ok {
+			return ""
+		}
+		return stats.total.snapshot().String()
+	}), false)
+}
+
+// barReporter shows a progress bar of layers with the bytes read and the files analyzed
+type barReporter struct {
+	w     io.Writer
+	bar   *pb.ProgressBar
+	stats *layerStats
+}
+
+// NewBar returns a reporter showing a progress bar in the writer, which should be a terminal
+func NewBar(w io.Writer) Reporter {
+	return &barReporter{
+		w:     w,
+		stats: newLayerStats(),
+	}
+}
+
+func (r *barReporter) Start(layers int) {
+	tmpl := filesTemplate
+	if layers > 0 {
+		tmpl = layersTemplate
+	}
+	r.bar = pb.New(layers).
+		SetTemplateString(tmpl).
+		SetWriter(r.w).
+		Set(statsKey, r.stats).
+		Start()
+}
+
+func (r *barReporter) LayerStarted(string) {}
+
+func (r *barReporter) LayerFinished(string) {
+	if r.bar != nil {
+		r.bar.Increment()
+	}
+}
+
+func (r *barReporter) Read(id string, n int) {
+	r.stats.read(id, n)
+}
+
+func (r *barReporter) File(id string, analyzers int) {
+	r.stats.file(id, analyzers)
+}
+
+func (r *barReporter) Finish() {
+	if r.bar != nil {
+		r.bar.Finish()
+	}
+}
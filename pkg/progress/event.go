@@ -0,0 +1,135 @@
+package progress
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/aquasecurity/trivy/pkg/clock"
+)
+
+// EventType represents the type of progress events
+type EventType string
+
+const (
+	EventStart         EventType = "start"
+	EventLayerStarted  EventType = "layer_started"
+	EventLayerFinished EventType = "layer_finished"
+	EventProgress      EventType = "progress"
+	EventFinish        EventType = "finish"
+)
+
+// defaultInterval is the interval of progress events
+const defaultInterval = time.Second
+
+// Event is written as a JSON line for wrappers of Trivy, e.g.
+//
+//	{"Time":"2022-07-01T00:00:00Z","Type":"layer_finished","Layer":"sha256:...","Bytes":5860352,"Files":482,"AnalyzedFiles":3,"AnalyzerRuns":3}
+type Event struct {
+	Time  time.Time
+	Type  EventType
+	Layer string `json:",omitempty"`
+
+	// Layers is the number of layers to be analyzed in "start" events
+	Layers int `json:",omitempty"`
+
+	// Stats is of the layer in "layer_finished" events and the total in "progress" and "finish" events
+	Stats
+}
+
+// eventReporter writes progress events as JSON lines
+type eventReporter struct {
+	mu       sync.Mutex
+	enc      *json.Encoder
+	stats    *layerStats
+	interval time.Duration
+	done     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewEventWriter returns a reporter writing progress events as JSON lines.
+// "progress" events with the total stats are written periodically while analyzing.
+func NewEventWriter(w io.Writer) Reporter {
+	return newEventWriter(w, defaultInterval)
+}
+
+func newEventWriter(w io.Writer, interval time.Duration) *eventReporter {
+	return &eventReporter{
+		enc:      json.NewEncoder(w),
+		stats:    newLayerStats(),
+		interval: interval,
+		done:     make(chan struct{}),
+	}
+}
+
+func (r *eventReporter) Start(layers int) {
+	r.write(Event{
+		Type:   EventStart,
+		Layers: layers,
+	})
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		var last Stats
+		for {
+			select {
+			case <-ticker.C:
+				// Events are not written while nothing progresses, e.g. waiting for the registry
+				if stats := r.stats.total.snapshot(); stats != last {
+					r.write(Event{
+						Type:  EventProgress,
+						Stats: stats,
+					})
+					last = stats
+				}
+			case <-r.done:
+				return
+			}
+		}
+	}()
+}
+
+func (r *eventReporter) LayerStarted(id string) {
+	r.write(Event{
+		Type:  EventLayerStarted,
+		Layer: id,
+	})
+}
+
+func (r *eventReporter) LayerFinished(id string) {
+	r.write(Event{
+		Type:  EventLayerFinished,
+		Layer: id,
+		Stats: r.stats.layer(id).snapshot(),
+	})
+}
+
+func (r *eventReporter) Read(id string, n int) {
+	r.stats.read(id, n)
+}
+
+func (r *eventReporter) File(id string, analyzers int) {
+	r.stats.file(id, analyzers)
+}
+
+func (r *eventReporter) Finish() {
+	close(r.done)
+	r.wg.Wait()
+	r.write(Event{
+		Type:  EventFinish,
+		Stats: r.stats.total.snapshot(),
+	})
+}
+
+func (r *eventReporter) write(e Event) {
+	e.Time = clock.Now().UTC()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_ = r.enc.Encode(e)
+}
@@ -0,0 +1,85 @@
+package progress
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aquasecurity/trivy/pkg/clock"
+)
+
+func TestEventReporter(t *testing.T) {
+	clock.SetFakeTime(t, time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC))
+
+	buf := &bytes.Buffer{}
+	r := newEventWriter(buf, time.Hour)
+	r.Start(2)
+	r.LayerStarted("sha256:aaa")
+	r.Read("sha256:aaa", 1024)
+	r.File("sha256:aaa", 2)
+	r.File("sha256:aaa", 0)
+	r.LayerFinished("sha256:aaa")
+	r.LayerStarted("sha256:bbb")
+	r.Read("sha256:bbb", 512)
+	r.File("sha256:bbb", 1)
+	r.LayerFinished("sha256:bbb")
+	r.Finish()
+
+	want := []string{
+		`{"Time":"2022-07-01T00:00:00Z","Type":"start","Layers":2}`,
+		`{"Time":"2022-07-01T00:00:00Z","Type":"layer_started","Layer":"sha256:aaa"}`,
+		`{"Time":"2022-07-01T00:00:00Z","Type":"layer_finished","Layer":"sha256:aaa","Bytes":1024,"Files":2,"AnalyzedFiles":1,"AnalyzerRuns":2}`,
+		`{"Time":"2022-07-01T00:00:00Z","Type":"layer_started","Layer":"sha256:bbb"}`,
+		`{"Time":"2022-07-01T00:00:00Z","Type":"layer_finished","Layer":"sha256:bbb","Bytes":512,"Files":1,"AnalyzedFiles":1,"AnalyzerRuns":1}`,
+		`{"Time":"2022-07-01T00:00:00Z","Type":"finish","Bytes":1536,"Files":3,"AnalyzedFiles":2,"AnalyzerRuns":3}`,
+	}
+	assert.Equal(t, want, strings.Split(strings.TrimSpace(buf.String()), "\n"))
+}
+
+func TestEventReporter_Progress(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := newEventWriter(buf, 10*time.Millisecond)
+	r.Start(0)
+	r.File("", 1)
+	time.Sleep(50 * time.Millisecond)
+	r.Finish()
+
+	// Progress events are written only when the stats change
+	assert.Equal(t, 1, strings.Count(buf.String(), `"Type":"progress"`))
+}
+
+func TestStats_String(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats Stats
+		want  string
+	}{
+		{
+			name: "files",
+			stats: Stats{
+				Files:         10,
+				AnalyzedFiles: 2,
+				AnalyzerRuns:  3,
+			},
+			want: "10 files, 2 analyzed (3 analyzer runs)",
+		},
+		{
+			name: "layers",
+			stats: Stats{
+				Bytes:         5 * 1024 * 1024,
+				Files:         482,
+				AnalyzedFiles: 3,
+				AnalyzerRuns:  3,
+			},
+			want: "5.0 MiB read, 482 files, 3 analyzed (3 analyzer runs)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.stats.String())
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/aquasecurity/fanal/analyzer"
+	dio "github.com/aquasecurity/go-dep-parser/pkg/io"
+)
+
+// Reporter is notified of the progress of analysis.
+// The methods may be called concurrently as layers are analyzed in parallel.
+type Reporter interface {
+	// Start is called with the number of layers to be analyzed, which is zero for filesystems
+	Start(layers int)
+
+	// LayerStarted and LayerFinished are called for each layer. The ID is empty for filesystems.
+	LayerStarted(id string)
+	LayerFinished(id string)
+
+	// Read is called with the number of bytes read from the layer
+	Read(id string, n int)
+
+	// File is called for each walked file with the number of analyzers run on the file
+	File(id string, analyzers int)
+
+	// Finish is called when the analysis is done
+	Finish()
+}
+
+// Stats holds the counts of analysis
+type Stats struct {
+	Bytes         int64 `json:",omitempty"`
+	Files         int64 `json:",omitempty"`
+	AnalyzedFiles int64 `json:",omitempty"`
+	AnalyzerRuns  int64 `json:",omitempty"`
+}
+
+func (s *Stats) read(n int) {
+	atomic.AddInt64(&s.Bytes, int64(n))
+}
+
+func (s *Stats) file(analyzers int) {
+	atomic.AddInt64(&s.Files, 1)
+	if analyzers > 0 {
+		atomic.AddInt64(&s.AnalyzedFiles, 1)
+		atomic.AddInt64(&s.AnalyzerRuns, int64(analyzers))
+	}
+}
+
+func (s *Stats) snapshot() Stats {
+	return Stats{
+		Bytes:         atomic.LoadInt64(&s.Bytes),
+		Files:         atomic.LoadInt64(&s.Files),
+		AnalyzedFiles: atomic.LoadInt64(&s.AnalyzedFiles),
+		AnalyzerRuns:  atomic.LoadInt64(&s.AnalyzerRuns),
+	}
+}
+
+func (s Stats) String() string {
+	str := fmt.Sprintf("%d files, %d analyzed (%d analyzer runs)", s.Files, s.AnalyzedFiles, s.AnalyzerRuns)
+	if s.Bytes > 0 {
+		str = formatBytes(s.Bytes) + " read, " + str
+	}
+	return str
+}
+
+// layerStats holds the stats of each layer in addition to the total
+type layerStats struct {
+	mu     sync.Mutex
+	total  Stats
+	layers map[string]*Stats
+}
+
+func newLayerStats() *layerStats {
+	return &layerStats{layers: map[string]*Stats{}}
+}
+
+func (l *layerStats) layer(id string) *Stats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	s, ok := l.layers[id]
+	if !ok {
+		s = &Stats{}
+		l.layers[id] = s
+	}
+	return s
+}
+
+func (l *layerStats) read(id string, n int) {
+	l.total.read(n)
+	l.layer(id).read(n)
+}
+
+func (l *layerStats) file(id string, analyzers int) {
+	l.total.file(analyzers)
+	l.layer(id).file(analyzers)
+}
+
+// Reader returns a reader notifying the reporter of the bytes read from the layer
+func Reader(r io.Reader, reporter Reporter, id string) io.Reader {
+	return &reader{
+		r:        r,
+		reporter: reporter,
+		id:       id,
+	}
+}
+
+type reader struct {
+	r        io.Reader
+	reporter Reporter
+	id       string
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.reporter.Read(r.id, n)
+	}
+	return n, err
+}
+
+// CountOpener returns an opener counting the analyzers opening the file.
+// Analyzers open the file only if they require it.
+func CountOpener(opener analyzer.Opener, count *int) analyzer.Opener {
+	return func() (dio.ReadSeekCloserAt, error) {
+		*count++
+		return opener()
+	}
+}
+
+// Nop returns a reporter which does nothing
+func Nop() Reporter {
+	return nopReporter{}
+}
+
+type nopReporter struct{}
+
+func (nopReporter) Start(int)            {}
+func (nopReporter) LayerStarted(string)  {}
+func (nopReporter) LayerFinished(string) {}
+func (nopReporter) Read(string, int)     {}
+func (nopReporter) File(string, int)     {}
+func (nopReporter) Finish()              {}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
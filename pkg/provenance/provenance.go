@@ -0,0 +1,306 @@
+package provenance
+
+import (
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"golang.org/x/xerrors"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/image"
+	"github.com/aquasecurity/trivy/pkg/sbom"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Predicate types of SLSA provenance
+const (
+	PredicateSLSAv01 = "https://slsa.dev/provenance/v0.1"
+	PredicateSLSAv02 = "https://slsa.dev/provenance/v0.2"
+	PredicateSLSAv1  = "https://slsa.dev/provenance/v1"
+)
+
+// Policy is the requirements for the provenance of images
+type Policy struct {
+	// PublicKey verifies the signatures of attestations if it is specified
+	PublicKey crypto.PublicKey
+
+	// Builders are the allowed builder IDs.
+	// An ID without "@" allows any versions of the builder, e.g. "https://github.com/org/builder.yml".
+	Builders []string
+
+	// Sources are the allowed source repositories, e.g. "github.com/org/repo"
+	Sources []string
+}
+
+// Option holds the options for verifying provenance
+type Option struct {
+	Policy
+	Insecure bool
+}
+
+// Verify fetches the SLSA provenance attestations of the image from the registry and verifies them against the policy.
+// The repository digests are those of the scanned image, which are the subjects of the attestations.
+func Verify(imageName string, repoDigests []string, opt Option) ([]types.ProvenanceViolation, error) {
+	ref, err := name.ParseReference(imageName)
+	if err != nil {
+		return nil, xerrors.Errorf("image name parse error: %w", err)
+	}
+
+	digest := image.RepoDigest(ref, repoDigests)
+	if digest == "" {
+		return []types.ProvenanceViolation{
+			violation(types.RuleAttestation, dbTypes.SeverityHigh, "the digest of the image in the registry is unknown"),
+		}, nil
+	}
+
+	envs, err := fetchAttestations(ref, digest, opt.Insecure)
+	if err != nil {
+		return nil, err
+	}
+	return Evaluate(envs, digest, opt.Policy), nil
+}
+
+// Evaluate returns the violations of the attestations of the image with the digest.
+// The image complies with the policy if one of the SLSA provenance attestations has no violations,
+// otherwise the violations of the attestation closest to the policy are returned.
+func Evaluate(envs []sbom.Envelope, digest string, policy Policy) []types.ProvenanceViolation {
+	var violations []types.ProvenanceViolation
+	var found bool
+	for _, env := range envs {
+		statement, err := env.Statement()
+		if err != nil || !isSLSA(statement.PredicateType) {
+			continue
+		}
+
+		vs := check(env, statement, digest, policy)
+		if len(vs) == 0 {
+			return nil
+		} else if !found || len(vs) < len(violations) {
+			violations = vs
+		}
+		found = true
+	}
+
+	if !found {
+		return []types.ProvenanceViolation{
+			violation(types.RuleAttestation, dbTypes.SeverityHigh, "no SLSA provenance attestation found"),
+		}
+	}
+	return violations
+}
+
+func check(env sbom.Envelope, statement sbom.Statement, digest string, policy Policy) []types.ProvenanceViolation {
+	var violations []types.ProvenanceViolation
+	if err := env.Verify(policy.PublicKey); err != nil {
+		violations = append(violations, violation(types.RuleSignature, dbTypes.SeverityCritical, err.Error()))
+	}
+
+	if !hasSubject(statement.Subject, digest) {
+		violations = append(violations, violation(types.RuleSubject, dbTypes.SeverityCritical,
+			fmt.Sprintf("the attestation is not for %s", digest)))
+	}
+
+	builder, sources, err := parsePredicate(statement.PredicateType, statement.Predicate)
+	if err != nil {
+		return append(violations, violation(types.RuleAttestation, dbTypes.SeverityHigh, err.Error()))
+	}
+
+	if len(policy.Builders) > 0 && !allowedBuilder(builder, policy.Builders) {
+		msg := fmt.Sprintf("builder %q is not allowed", builder)
+		if builder == "" {
+			msg = "no builder identity found"
+		}
+		violations = append(violations, violation(types.RuleBuilder, dbTypes.SeverityHigh, msg))
+	}
+
+	if len(policy.Sources) > 0 && !allowedSource(sources, policy.Sources) {
+		msg := fmt.Sprintf("source %s is not allowed", strings.Join(sources, ", "))
+		if len(sources) == 0 {
+			msg = "no source repository found"
+		}
+		violations = append(violations, violation(types.RuleSource, dbTypes.SeverityHigh, msg))
+	}
+	return violations
+}
+
+// predicateV02 is the subset of SLSA provenance v0.1 and v0.2
+type predicateV02 struct {
+	Builder struct {
+		ID string `json:"id"`
+	} `json:"builder"`
+	Invocation struct {
+		ConfigSource struct {
+			URI string `json:"uri"`
+		} `json:"configSource"`
+	} `json:"invocation"`
+	Materials []struct {
+		URI string `json:"uri"`
+	} `json:"materials"`
+}
+
+// predicateV1 is the subset of SLSA provenance v1
+type predicateV1 struct {
+	BuildDefinition struct {
+		ExternalParameters struct {
+			Workflow struct {
+				Repository string `json:"repository"`
+			} `json:"workflow"`
+		} `json:"externalParameters"`
+		ResolvedDependencies []struct {
+			URI string `json:"uri"`
+		} `json:"resolvedDependencies"`
+	} `json:"buildDefinition"`
+	RunDetails struct {
+		Builder struct {
+			ID string `json:"id"`
+		} `json:"builder"`
+	} `json:"runDetails"`
+}
+
+// parsePredicate returns the builder ID and the source repositories in the provenance
+func parsePredicate(predicateType string, predicate json.RawMessage) (string, []string, error) {
+	var builder string
+	var uris []string
+	if predicateType == PredicateSLSAv1 {
+		var p predicateV1
+		if err := json.Unmarshal(predicate, &p); err != nil {
+			return "", nil, xerrors.Errorf("provenance decode error: %w", err)
+		}
+		builder = p.RunDetails.Builder.ID
+		uris = append(uris, p.BuildDefinition.ExternalParameters.Workflow.Repository)
+		for _, d := range p.BuildDefinition.ResolvedDependencies {
+			uris = append(uris, d.URI)
+		}
+	} else {
+		var p predicateV02
+		if err := json.Unmarshal(predicate, &p); err != nil {
+			return "", nil, xerrors.Errorf("provenance decode error: %w", err)
+		}
+		builder = p.Builder.ID
+		uris = append(uris, p.Invocation.ConfigSource.URI)
+		for _, m := range p.Materials {
+			uris = append(uris, m.URI)
+		}
+	}
+
+	var sources []string
+	for _, uri := range uris {
+		if s := normalizeSource(uri); s != "" {
+			sources = append(sources, s)
+		}
+	}
+	return builder, sources, nil
+}
+
+func isSLSA(predicateType string) bool {
+	switch predicateType {
+	case PredicateSLSAv01, PredicateSLSAv02, PredicateSLSAv1:
+		return true
+	}
+	return false
+}
+
+func hasSubject(subjects []sbom.Subject, digest string) bool {
+	for _, s := range subjects {
+		if s.Digest["sha256"] == strings.TrimPrefix(digest, "sha256:") {
+			return true
+		}
+	}
+	return false
+}
+
+func allowedBuilder(builder string, allowed []string) bool {
+	if builder == "" {
+		return false
+	}
+	for _, a := range allowed {
+		if a == builder {
+			return true
+		}
+		// Any versions of the builder are allowed, e.g. "builder.yml" allows "builder.yml@refs/tags/v1.0.0"
+		if !strings.Contains(a, "@") && strings.SplitN(builder, "@", 2)[0] == a {
+			return true
+		}
+	}
+	return false
+}
+
+func allowedSource(sources, allowed []string) bool {
+	for _, s := range sources {
+		for _, a := range allowed {
+			if normalizeSource(a) == s {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// normalizeSource returns the repository of the URI,
+// e.g. "git+https://github.com/org/repo.git@refs/heads/main" => "github.com/org/repo"
+func normalizeSource(uri string) string {
+	s := strings.TrimPrefix(uri, "git+")
+	if i := strings.Index(s, "://"); i >= 0 {
+		s = s[i+len("://"):]
+	}
+	if i := strings.Index(s, "@"); i >= 0 {
+		s = s[:i]
+	}
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "/"), ".git")
+	return strings.ToLower(s)
+}
+
+// fetchAttestations returns the attestations of the image, which are stored by cosign with the tag "sha256-<digest>.att".
+// Each layer is a DSSE envelope.
+func fetchAttestations(ref name.Reference, digest string, insecure bool) ([]sbom.Envelope, error) {
+	attRef := ref.Context().Tag(strings.ReplaceAll(digest, ":", "-") + ".att")
+	img, err := remote.Image(attRef, image.RemoteOptions(insecure)...)
+	if err != nil {
+		var terr *transport.Error
+		if errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, xerrors.Errorf("unable to get the attestations (%s): %w", attRef, err)
+	}
+
+	layers, err := img.Layers()
+	if err != nil {
+		return nil, xerrors.Errorf("attestation layer error: %w", err)
+	}
+
+	var envs []sbom.Envelope
+	for _, layer := range layers {
+		rc, err := layer.Uncompressed()
+		if err != nil {
+			return nil, xerrors.Errorf("attestation layer error: %w", err)
+		}
+		b, err := io.ReadAll(rc)
+		_ = rc.Close()
+		if err != nil {
+			return nil, xerrors.Errorf("attestation read error: %w", err)
+		}
+
+		var env sbom.Envelope
+		if err = json.Unmarshal(b, &env); err != nil {
+			return nil, xerrors.Errorf("envelope decode error: %w", err)
+		}
+		envs = append(envs, env)
+	}
+	return envs, nil
+}
+
+func violation(rule types.ProvenanceRule, severity dbTypes.Severity, msg string) types.ProvenanceViolation {
+	return types.ProvenanceViolation{
+		Rule:     rule,
+		Severity: severity.String(),
+		Message:  msg,
+	}
+}
@@ -0,0 +1,296 @@
+package provenance_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/provenance"
+	"github.com/aquasecurity/trivy/pkg/sbom"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const (
+	builderID = "https://github.com/slsa-framework/slsa-github-generator/.github/workflows/generator_container_slsa3.yml@refs/tags/v1.4.0"
+	sourceURI = "git+https://github.com/acme/app@refs/heads/main"
+)
+
+func TestVerify(t *testing.T) {
+	signer, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	defer ts.Close()
+	u, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+
+	attested := u.Host + "/acme/app:attested"
+	digest := pushImage(t, attested)
+	pushAttestations(t, attested, digest, envelope(t, signer, provenance.PredicateSLSAv02, digest, fmt.Sprintf(`{
+  "builder": {"id": %q},
+  "invocation": {"configSource": {"uri": %q}}
+}`, builderID, sourceURI)))
+
+	v1 := u.Host + "/acme/v1:latest"
+	v1Digest := pushImage(t, v1)
+	pushAttestations(t, v1, v1Digest, envelope(t, signer, provenance.PredicateSLSAv1, v1Digest, fmt.Sprintf(`{
+  "buildDefinition": {"externalParameters": {"workflow": {"repository": "https://github.com/acme/app"}}},
+  "runDetails": {"builder": {"id": %q}}
+}`, builderID)))
+
+	tests := []struct {
+		name        string
+		image       string
+		repoDigests []string
+		policy      provenance.Policy
+		want        []types.ProvenanceViolation
+		wantErr     string
+	}{
+		{
+			name:        "happy path",
+			image:       attested,
+			repoDigests: []string{u.Host + "/acme/app@" + digest},
+			policy: provenance.Policy{
+				PublicKey: &signer.PublicKey,
+				Builders:  []string{"https://github.com/slsa-framework/slsa-github-generator/.github/workflows/generator_container_slsa3.yml"},
+				Sources:   []string{"github.com/acme/app"},
+			},
+		},
+		{
+			name:  "SLSA v1",
+			image: v1 + "@" + v1Digest,
+			policy: provenance.Policy{
+				Builders: []string{builderID},
+				Sources:  []string{"https://github.com/acme/app.git"},
+			},
+		},
+		{
+			name:        "policy violations",
+			image:       attested,
+			repoDigests: []string{u.Host + "/acme/app@" + digest},
+			policy: provenance.Policy{
+				PublicKey: &other.PublicKey,
+				Builders:  []string{"https://github.com/acme/builder.yml"},
+				Sources:   []string{"github.com/acme/other"},
+			},
+			want: []types.ProvenanceViolation{
+				{
+					Rule:     types.RuleSignature,
+					Severity: "CRITICAL",
+					Message:  "no valid signature found",
+				},
+				{
+					Rule:     types.RuleBuilder,
+					Severity: "HIGH",
+					Message:  fmt.Sprintf("builder %q is not allowed", builderID),
+				},
+				{
+					Rule:     types.RuleSource,
+					Severity: "HIGH",
+					Message:  "source github.com/acme/app is not allowed",
+				},
+			},
+		},
+		{
+			name:  "no attestation",
+			image: v1 + "@" + digest,
+			want: []types.ProvenanceViolation{
+				{
+					Rule:     types.RuleAttestation,
+					Severity: "HIGH",
+					Message:  "no SLSA provenance attestation found",
+				},
+			},
+		},
+		{
+			name:  "unknown digest",
+			image: attested,
+			want: []types.ProvenanceViolation{
+				{
+					Rule:     types.RuleAttestation,
+					Severity: "HIGH",
+					Message:  "the digest of the image in the registry is unknown",
+				},
+			},
+		},
+		{
+			name:    "invalid image name",
+			image:   "INVALID:image:name",
+			wantErr: "image name parse error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := provenance.Verify(tt.image, tt.repoDigests, provenance.Option{Policy: tt.policy})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	signer, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	digest := "sha256:" + strings.Repeat("a", 64)
+	predicate := fmt.Sprintf(`{"builder": {"id": %q}, "materials": [{"uri": %q}]}`, builderID, sourceURI)
+
+	tests := []struct {
+		name   string
+		envs   []sbom.Envelope
+		policy provenance.Policy
+		want   []types.ProvenanceViolation
+	}{
+		{
+			name: "one of the attestations complies",
+			envs: []sbom.Envelope{
+				envelope(t, nil, provenance.PredicateSLSAv02, digest, predicate),
+				envelope(t, signer, provenance.PredicateSLSAv01, digest, predicate),
+			},
+			policy: provenance.Policy{
+				Sources: []string{"github.com/acme/app"},
+			},
+		},
+		{
+			name: "different subject",
+			envs: []sbom.Envelope{
+				envelope(t, signer, provenance.PredicateSLSAv02, "sha256:"+strings.Repeat("b", 64), predicate),
+			},
+			want: []types.ProvenanceViolation{
+				{
+					Rule:     types.RuleSubject,
+					Severity: "CRITICAL",
+					Message:  "the attestation is not for " + digest,
+				},
+			},
+		},
+		{
+			name: "SBOM attestation",
+			envs: []sbom.Envelope{
+				envelope(t, signer, sbom.PredicateCycloneDX, digest, `{}`),
+			},
+			want: []types.ProvenanceViolation{
+				{
+					Rule:     types.RuleAttestation,
+					Severity: "HIGH",
+					Message:  "no SLSA provenance attestation found",
+				},
+			},
+		},
+		{
+			name: "no builder",
+			envs: []sbom.Envelope{
+				envelope(t, signer, provenance.PredicateSLSAv02, digest, `{}`),
+			},
+			policy: provenance.Policy{
+				Builders: []string{builderID},
+				Sources:  []string{"github.com/acme/app"},
+			},
+			want: []types.ProvenanceViolation{
+				{
+					Rule:     types.RuleBuilder,
+					Severity: "HIGH",
+					Message:  "no builder identity found",
+				},
+				{
+					Rule:     types.RuleSource,
+					Severity: "HIGH",
+					Message:  "no source repository found",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := provenance.Evaluate(tt.envs, digest, tt.policy)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// envelope returns the DSSE envelope of the in-toto statement, which is not signed if the signer is nil
+func envelope(t *testing.T, signer *ecdsa.PrivateKey, predicateType, digest, predicate string) sbom.Envelope {
+	payload, err := json.Marshal(sbom.Statement{
+		Type:          "https://in-toto.io/Statement/v0.1",
+		PredicateType: predicateType,
+		Subject: []sbom.Subject{
+			{
+				Name:   "acme/app",
+				Digest: map[string]string{"sha256": strings.TrimPrefix(digest, "sha256:")},
+			},
+		},
+		Predicate: json.RawMessage(predicate),
+	})
+	require.NoError(t, err)
+
+	env := sbom.Envelope{
+		PayloadType: sbom.PayloadTypeInToto,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+	}
+	if signer == nil {
+		return env
+	}
+
+	message := fmt.Sprintf("DSSEv1 %d %s %d %s", len(env.PayloadType), env.PayloadType, len(payload), payload)
+	h := sha256.Sum256([]byte(message))
+	sig, err := signer.Sign(rand.Reader, h[:], crypto.SHA256)
+	require.NoError(t, err)
+	env.Signatures = []sbom.Signature{{Sig: base64.StdEncoding.EncodeToString(sig)}}
+	return env
+}
+
+func pushImage(t *testing.T, image string) string {
+	ref, err := name.ParseReference(image)
+	require.NoError(t, err)
+	img, err := random.Image(64, 1)
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(ref, img))
+
+	digest, err := img.Digest()
+	require.NoError(t, err)
+	return digest.String()
+}
+
+// pushAttestations pushes the attestations as cosign does
+func pushAttestations(t *testing.T, image, digest string, envs ...sbom.Envelope) {
+	ref, err := name.ParseReference(image)
+	require.NoError(t, err)
+
+	img := empty.Image
+	for _, env := range envs {
+		b, err := json.Marshal(env)
+		require.NoError(t, err)
+		img, err = mutate.AppendLayers(img, static.NewLayer(b, "application/vnd.dsse.envelope.v1+json"))
+		require.NoError(t, err)
+	}
+	attRef := ref.Context().Tag(strings.ReplaceAll(digest, ":", "-") + ".att")
+	require.NoError(t, remote.Write(attRef, img))
+}
@@ -0,0 +1,336 @@
+package pulumi
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+const (
+	// FileTypePulumi is the type of the output of "pulumi preview --json"
+	FileTypePulumi = "pulumi"
+
+	// configFile is the Terraform configuration converted from a preview
+	configFile = "main.tf"
+
+	urnPrefix = "urn:pulumi:"
+
+	// unknownValue is the value of outputs which are not known until the update, e.g. IDs of other resources
+	unknownValue = "04da6b54-80e4-46f7-96ec-b56ff0331ba9"
+
+	// secretSig is the key of secret values, e.g. {"4dabf18193072939515e22adb298388d": "1b47061264138c4ac30d75fd1eb44270", "value": "..."}
+	secretSig = "4dabf18193072939515e22adb298388d"
+)
+
+// providers maps the Pulumi providers bridged from Terraform to the prefixes of the resource types
+var providers = map[string]string{
+	"aws":   "aws",
+	"azure": "azurerm",
+	"gcp":   "google",
+}
+
+// resourceTypes has the Terraform resource types which don't follow the naming convention of the bridge
+var resourceTypes = map[string]string{
+	"aws:cloudtrail/trail:Trail":               "aws_cloudtrail",
+	"aws:rds/instance:Instance":                "aws_db_instance",
+	"aws:elasticsearch/domain:Domain":          "aws_elasticsearch_domain",
+	"azure:keyvault/keyVault:KeyVault":         "azurerm_key_vault",
+	"azure:appservice/appService:AppService":   "azurerm_app_service",
+	"azure:appservice/functionApp:FunctionApp": "azurerm_function_app",
+}
+
+// mapAttributes are objects which are maps in Terraform, not blocks
+var mapAttributes = map[string]struct{}{
+	"tags":     {},
+	"tags_all": {},
+	"labels":   {},
+}
+
+// Pulumi doesn't include the module in the resource type for these modules, e.g. "aws:ec2/instance:Instance" => "aws_instance"
+var modulesWithoutPrefix = map[string]struct{}{
+	"aws:ec2": {},
+}
+
+var camelRegexp = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+
+type preview struct {
+	Steps []step `json:"steps"`
+}
+
+type step struct {
+	Op       string `json:"op"`
+	URN      string `json:"urn"`
+	NewState *struct {
+		Type   string                 `json:"type"`
+		Inputs map[string]interface{} `json:"inputs"`
+	} `json:"newState"`
+}
+
+// Source is the preview which a converted configuration comes from
+type Source struct {
+	FilePath string
+
+	// Resources maps the Terraform addresses to the Pulumi resources,
+	// e.g. "aws_s3_bucket.resource0" => "aws:s3/bucket:Bucket::my-bucket"
+	Resources map[string]string
+}
+
+// Convert replaces Pulumi previews, which are the output of "pulumi preview --json",
+// with the Terraform configurations of the resources to be created or updated.
+// Only the resources of the providers bridged from Terraform, i.e. aws, azure and gcp, are converted,
+// and the inputs are evaluated by the checks of the Terraform resources.
+// Like Terraform plans, each preview is converted into its own directory, e.g. "preview.json/main.tf".
+// The converted paths are returned with the previews so that findings are attributed to them by Relabel.
+func Convert(files []types.File) ([]types.File, map[string]Source) {
+	converted := map[string]Source{}
+	var results []types.File
+	for _, file := range files {
+		p, ok := parse(file)
+		if !ok {
+			results = append(results, file)
+			continue
+		}
+
+		content, resources, err := convert(p)
+		if err != nil {
+			log.Logger.Warnf("Unable to convert the Pulumi preview (%s): %s", file.Path, err)
+			results = append(results, file)
+			continue
+		}
+
+		filePath := filepath.Join(file.Path, configFile)
+		converted[filePath] = Source{
+			FilePath:  file.Path,
+			Resources: resources,
+		}
+		results = append(results, types.File{
+			Type:    types.Terraform,
+			Path:    filePath,
+			Content: content,
+		})
+	}
+	return results, converted
+}
+
+// Relabel attributes misconfigurations in the converted configurations to the previews.
+// As the line numbers and the code are of the converted configurations, they are removed,
+// and the Pulumi resources are reported instead of the Terraform addresses.
+func Relabel(misconfs []types.Misconfiguration, converted map[string]Source) {
+	for i, misconf := range misconfs {
+		source, ok := converted[misconf.FilePath]
+		if !ok {
+			continue
+		}
+		misconfs[i].FilePath = source.FilePath
+		misconfs[i].FileType = FileTypePulumi
+
+		for _, results := range []types.MisconfResults{misconf.Successes, misconf.Warnings, misconf.Failures, misconf.Exceptions} {
+			for j := range results {
+				r := &results[j]
+				r.Type = strings.Replace(r.Type, "Terraform", "Pulumi", 1)
+				if resource, ok := source.Resources[r.Resource]; ok {
+					r.Resource = resource
+				}
+				r.StartLine, r.EndLine = 0, 0
+				r.Code = types.Code{}
+			}
+		}
+	}
+}
+
+func parse(file types.File) (preview, bool) {
+	if file.Type != types.JSON {
+		return preview{}, false
+	}
+	var p preview
+	if err := json.Unmarshal(file.Content, &p); err != nil || len(p.Steps) == 0 {
+		return preview{}, false
+	}
+	for _, s := range p.Steps {
+		if !strings.HasPrefix(s.URN, urnPrefix) {
+			return preview{}, false
+		}
+	}
+	return p, true
+}
+
+func convert(p preview) ([]byte, map[string]string, error) {
+	file := hclwrite.NewEmptyFile()
+	resources := map[string]string{}
+	for _, s := range p.Steps {
+		if s.Op == "delete" || s.NewState == nil {
+			continue
+		}
+		tfType, ok := resourceType(s.NewState.Type)
+		if !ok {
+			continue
+		}
+
+		tfName := fmt.Sprintf("resource%d", len(resources))
+		resources[tfType+"."+tfName] = urnName(s.URN)
+
+		body := file.Body()
+		if len(body.Blocks()) > 0 {
+			body.AppendNewline()
+		}
+		if err := setInputs(body.AppendNewBlock("resource", []string{tfType, tfName}).Body(), s.NewState.Inputs); err != nil {
+			return nil, nil, xerrors.Errorf("%s: %w", s.URN, err)
+		}
+	}
+	return file.Bytes(), resources, nil
+}
+
+// resourceType returns the Terraform resource type of the Pulumi type token,
+// e.g. "aws:s3/bucket:Bucket" => "aws_s3_bucket"
+func resourceType(token string) (string, bool) {
+	if t, ok := resourceTypes[token]; ok {
+		return t, true
+	}
+
+	// e.g. "aws", "s3/bucketV2", "BucketV2"
+	parts := strings.Split(token, ":")
+	if len(parts) != 3 {
+		return "", false
+	}
+	prefix, ok := providers[parts[0]]
+	if !ok {
+		return "", false
+	}
+	module, name, ok := strings.Cut(parts[1], "/")
+	if !ok {
+		return "", false
+	}
+
+	// Resources renamed to the V2 have the same resource types, e.g. "aws:s3/bucketV2:BucketV2"
+	name = snakeCase(strings.TrimSuffix(name, "V2"))
+	module = strings.ToLower(module)
+	if _, ok = modulesWithoutPrefix[parts[0]+":"+module]; ok || strings.HasPrefix(name, module+"_") {
+		return prefix + "_" + name, true
+	}
+	return prefix + "_" + module + "_" + name, true
+}
+
+// urnName returns the type and name of the resource,
+// e.g. "urn:pulumi:dev::app::aws:s3/bucket:Bucket::my-bucket" => "aws:s3/bucket:Bucket::my-bucket"
+func urnName(urn string) string {
+	parts := strings.Split(urn, "::")
+	if len(parts) < 4 {
+		return urn
+	}
+	// The type is qualified with the parents, e.g. "my:component:Type$aws:s3/bucket:Bucket"
+	qualified := strings.Split(parts[2], "$")
+	return qualified[len(qualified)-1] + "::" + parts[len(parts)-1]
+}
+
+// setInputs sets the inputs as attributes or blocks.
+// Objects and arrays of objects are blocks as the Terraform bridge flattens single nested blocks.
+// Unknown values, which are computed during the update, are omitted.
+func setInputs(body *hclwrite.Body, inputs map[string]interface{}) error {
+	var keys []string
+	for key := range inputs {
+		// Internal properties, e.g. "__defaults"
+		if !strings.HasPrefix(key, "__") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		name := snakeCase(key)
+		switch v := unwrap(inputs[key]).(type) {
+		case map[string]interface{}:
+			if _, ok := mapAttributes[name]; ok {
+				setAttribute(body, name, v)
+			} else if err := setInputs(body.AppendNewBlock(name, nil).Body(), v); err != nil {
+				return err
+			}
+		case []interface{}:
+			if !isObjects(v) {
+				setAttribute(body, name, v)
+				continue
+			}
+			for _, elem := range v {
+				if err := setInputs(body.AppendNewBlock(name, nil).Body(), unwrap(elem).(map[string]interface{})); err != nil {
+					return err
+				}
+			}
+		default:
+			setAttribute(body, name, v)
+		}
+	}
+	return nil
+}
+
+// unwrap returns the plain value of a secret
+func unwrap(value interface{}) interface{} {
+	if m, ok := value.(map[string]interface{}); ok {
+		if _, ok = m[secretSig]; ok {
+			return m["value"]
+		}
+	}
+	return value
+}
+
+func isObjects(values []interface{}) bool {
+	for _, v := range values {
+		if _, ok := unwrap(v).(map[string]interface{}); !ok {
+			return false
+		}
+	}
+	return len(values) > 0
+}
+
+func setAttribute(body *hclwrite.Body, name string, value interface{}) {
+	if v, ok := toCty(value); ok {
+		body.SetAttributeValue(name, v)
+	}
+}
+
+func toCty(value interface{}) (cty.Value, bool) {
+	switch v := unwrap(value).(type) {
+	case string:
+		if v == unknownValue {
+			return cty.NilVal, false
+		}
+		return cty.StringVal(v), true
+	case bool:
+		return cty.BoolVal(v), true
+	case float64:
+		return cty.NumberFloatVal(v), true
+	case []interface{}:
+		var values []cty.Value
+		for _, val := range v {
+			if cv, ok := toCty(val); ok {
+				values = append(values, cv)
+			}
+		}
+		if len(values) == 0 {
+			return cty.EmptyTupleVal, true
+		}
+		return cty.TupleVal(values), true
+	case map[string]interface{}:
+		values := map[string]cty.Value{}
+		for key, val := range v {
+			if cv, ok := toCty(val); ok {
+				values[key] = cv
+			}
+		}
+		return cty.ObjectVal(values), true
+	default:
+		return cty.NilVal, false
+	}
+}
+
+func snakeCase(s string) string {
+	return strings.ToLower(camelRegexp.ReplaceAllString(s, "${1}_${2}"))
+}
@@ -0,0 +1,146 @@
+package pulumi
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/types"
+)
+
+func TestConvert(t *testing.T) {
+	preview, err := os.ReadFile("testdata/preview.json")
+	require.NoError(t, err)
+
+	packageJSON := types.File{
+		Type:    types.JSON,
+		Path:    "package.json",
+		Content: []byte(`{"name": "app"}`),
+	}
+
+	got, converted := Convert([]types.File{
+		{
+			Type:    types.JSON,
+			Path:    "preview.json",
+			Content: preview,
+		},
+		packageJSON,
+	})
+
+	want := []types.File{
+		{
+			Type: types.Terraform,
+			Path: "preview.json/main.tf",
+			Content: []byte(`resource "aws_security_group" "resource0" {
+  description = "web"
+  ingress {
+    cidr_blocks = ["0.0.0.0/0"]
+    from_port   = 22
+    protocol    = "tcp"
+    self        = false
+    to_port     = 22
+  }
+  name                   = "web-7c3a1f2"
+  revoke_rules_on_delete = false
+  tags = {
+    Name = "web"
+  }
+}
+
+resource "aws_s3_bucket" "resource1" {
+  bucket        = "logs-3f9a1b2"
+  force_destroy = false
+}
+`),
+		},
+		packageJSON,
+	}
+	assert.Equal(t, want, got)
+
+	wantConverted := map[string]Source{
+		"preview.json/main.tf": {
+			FilePath: "preview.json",
+			Resources: map[string]string{
+				"aws_security_group.resource0": "aws:ec2/securityGroup:SecurityGroup::web",
+				"aws_s3_bucket.resource1":      "aws:s3/bucketV2:BucketV2::logs",
+			},
+		},
+	}
+	assert.Equal(t, wantConverted, converted)
+}
+
+func TestRelabel(t *testing.T) {
+	misconfs := []types.Misconfiguration{
+		{
+			FileType: types.Terraform,
+			FilePath: "preview.json/main.tf",
+			Failures: types.MisconfResults{
+				{
+					PolicyMetadata: types.PolicyMetadata{
+						ID:   "AVD-AWS-0107",
+						Type: "Terraform Security Check",
+					},
+					CauseMetadata: types.CauseMetadata{
+						Resource:  "aws_security_group.resource0",
+						StartLine: 4,
+						EndLine:   4,
+					},
+				},
+			},
+		},
+	}
+
+	Relabel(misconfs, map[string]Source{
+		"preview.json/main.tf": {
+			FilePath: "preview.json",
+			Resources: map[string]string{
+				"aws_security_group.resource0": "aws:ec2/securityGroup:SecurityGroup::web",
+			},
+		},
+	})
+
+	want := []types.Misconfiguration{
+		{
+			FileType: FileTypePulumi,
+			FilePath: "preview.json",
+			Failures: types.MisconfResults{
+				{
+					PolicyMetadata: types.PolicyMetadata{
+						ID:   "AVD-AWS-0107",
+						Type: "Pulumi Security Check",
+					},
+					CauseMetadata: types.CauseMetadata{
+						Resource: "aws:ec2/securityGroup:SecurityGroup::web",
+					},
+				},
+			},
+		},
+	}
+	assert.Equal(t, want, misconfs)
+}
+
+func Test_resourceType(t *testing.T) {
+	tests := []struct {
+		token  string
+		want   string
+		wantOK bool
+	}{
+		{token: "aws:s3/bucket:Bucket", want: "aws_s3_bucket", wantOK: true},
+		{token: "aws:s3/bucketPublicAccessBlock:BucketPublicAccessBlock", want: "aws_s3_bucket_public_access_block", wantOK: true},
+		{token: "aws:ec2/instance:Instance", want: "aws_instance", wantOK: true},
+		{token: "aws:rds/instance:Instance", want: "aws_db_instance", wantOK: true},
+		{token: "azure:network/networkSecurityGroup:NetworkSecurityGroup", want: "azurerm_network_security_group", wantOK: true},
+		{token: "gcp:storage/bucket:Bucket", want: "google_storage_bucket", wantOK: true},
+		{token: "pulumi:providers:aws", wantOK: false},
+		{token: "kubernetes:apps/v1:Deployment", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.token, func(t *testing.T) {
+			got, ok := resourceType(tt.token)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package purl
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	cn "github.com/google/go-containerregistry/pkg/name"
@@ -11,12 +12,16 @@ import (
 	"github.com/aquasecurity/fanal/analyzer"
 	"github.com/aquasecurity/fanal/analyzer/os"
 	ftypes "github.com/aquasecurity/fanal/types"
+	tos "github.com/aquasecurity/trivy/pkg/analyzer/os"
 	"github.com/aquasecurity/trivy/pkg/scanner/utils"
 	"github.com/aquasecurity/trivy/pkg/types"
 )
 
 const (
 	TypeOCI = "oci"
+
+	// TODO: replace with packageurl.TypePub once they add it.
+	TypePub = "pub"
 )
 
 type PackageURL struct {
@@ -78,6 +83,8 @@ func NewPackageURL(t string, metadata types.Metadata, pkg ftypes.Package) (Packa
 		namespace, name = parseGolang(name)
 	case packageurl.TypeNPM:
 		namespace, name = parseNpm(name)
+	case packageurl.TypeSwift:
+		namespace, name = parseSwift(name)
 	case packageurl.TypeOCI:
 		purl, err := parseOCI(metadata)
 		if err != nil {
@@ -212,6 +219,12 @@ func parseNpm(pkgName string) (string, string) {
 	return parsePkgName(name)
 }
 
+// ref. https://github.com/package-url/purl-spec/blob/a748c36ad415c8aeffe2b8a4a5d8a50d16d6d85f/PURL-TYPES.rst#swift
+func parseSwift(pkgName string) (string, string) {
+	// e.g. github.com/Alamofire/Alamofire => namespace: github.com/Alamofire, name: Alamofire
+	return parsePkgName(pkgName)
+}
+
 func purlType(t string) string {
 	switch t {
 	case string(analyzer.TypeJar), string(analyzer.TypePom):
@@ -222,15 +235,25 @@ func purlType(t string) string {
 		return packageurl.TypePyPi
 	case string(analyzer.TypeGoBinary), string(analyzer.TypeGoMod):
 		return packageurl.TypeGolang
-	case string(analyzer.TypeNpmPkgLock), string(analyzer.TypeNodePkg), string(analyzer.TypeYarn):
+	case string(analyzer.TypeNpmPkgLock), string(analyzer.TypeNodePkg), string(analyzer.TypeYarn), types.Pnpm:
 		return packageurl.TypeNPM
-	case os.Alpine:
+	case types.RustBinary:
+		return packageurl.TypeCargo
+	case types.Carthage:
+		return packageurl.TypeSwift
+	case types.CondaPkg, types.CondaEnv, types.CondaLock:
+		return packageurl.TypeConda
+	case types.BazelMaven, types.BazelModule, types.Gradle, types.GradleCatalog:
+		return packageurl.TypeMaven
+	case types.DotNetCore:
+		return packageurl.TypeNuget
+	case os.Alpine, tos.Wolfi, tos.Chainguard:
 		return string(analyzer.TypeApk)
 	case os.Debian, os.Ubuntu:
 		return packageurl.TypeDebian
 	case os.RedHat, os.CentOS, os.Rocky, os.Alma,
 		os.Amazon, os.Fedora, os.Oracle, os.OpenSUSE,
-		os.OpenSUSELeap, os.OpenSUSETumbleweed, os.SLES, os.Photon:
+		os.OpenSUSELeap, os.OpenSUSETumbleweed, os.SLES, os.Photon, tos.Bottlerocket:
 		return packageurl.TypeRPM
 	case TypeOCI:
 		return packageurl.TypeOCI
@@ -259,3 +282,134 @@ func parsePkgName(name string) (string, string) {
 	return namespace, name
 
 }
+
+// FromString parses a package URL string
+func FromString(s string) (*PackageURL, error) {
+	p, err := packageurl.FromString(s)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse purl(%s): %w", s, err)
+	}
+
+	return &PackageURL{
+		PackageURL: p,
+	}, nil
+}
+
+// IsOSPkg reports whether the package is installed by an OS package manager
+func (purl PackageURL) IsOSPkg() bool {
+	switch purl.Type {
+	case string(analyzer.TypeApk), "alpine", packageurl.TypeDebian, packageurl.TypeRPM:
+		return true
+	}
+	return false
+}
+
+// AppType returns the application type for language-specific packages.
+// An empty string is returned for OS packages and unsupported types.
+func (purl PackageURL) AppType() string {
+	switch purl.Type {
+	case packageurl.TypeMaven:
+		return ftypes.Jar
+	case packageurl.TypeGem:
+		return ftypes.GemSpec
+	case packageurl.TypePyPi:
+		return ftypes.PythonPkg
+	case packageurl.TypeGolang:
+		return ftypes.GoBinary
+	case packageurl.TypeNPM:
+		return ftypes.NodePkg
+	case packageurl.TypeCargo:
+		return ftypes.Cargo
+	case packageurl.TypeComposer:
+		return ftypes.Composer
+	case packageurl.TypeNuget:
+		return ftypes.NuGet
+	case packageurl.TypeCocoapods:
+		return types.Cocoapods
+	case packageurl.TypeSwift:
+		return types.Swift
+	case TypePub:
+		return types.Pub
+	case packageurl.TypeConda:
+		return types.CondaPkg
+	}
+	return ""
+}
+
+// OS returns the operating system according to the namespace and the "distro" qualifier.
+// nil is returned if the OS cannot be identified.
+func (purl PackageURL) OS() *ftypes.OS {
+	if !purl.IsOSPkg() || purl.Namespace == "" {
+		return nil
+	}
+
+	family := purl.Namespace
+	if family == "sles" {
+		family = os.SLES
+	}
+
+	// e.g. "debian-11", "centos-8.4.2105" and "3.16.0" for Alpine
+	distro := purl.Qualifiers.Map()["distro"]
+	distro = strings.TrimPrefix(distro, purl.Namespace+"-")
+	if distro == "" {
+		return nil
+	}
+
+	return &ftypes.OS{
+		Family: family,
+		Name:   distro,
+	}
+}
+
+// Package converts the PURL into a package, which is the reverse of NewPackageURL
+func (purl PackageURL) Package() *ftypes.Package {
+	pkg := &ftypes.Package{
+		Name:    purl.Name,
+		Version: purl.Version,
+	}
+
+	qualifiers := purl.Qualifiers.Map()
+	pkg.Arch = qualifiers["arch"]
+	pkg.Modularitylabel = qualifiers["modularitylabel"]
+	pkg.FilePath = qualifiers["file_path"]
+
+	switch purl.Type {
+	case packageurl.TypeRPM:
+		// e.g. 1:3.5.2-2.el8
+		pkg.Epoch, pkg.Version, pkg.Release = splitRPMVersion(purl.Version)
+		if epoch, err := strconv.Atoi(qualifiers["epoch"]); err == nil {
+			pkg.Epoch = epoch
+		}
+	case packageurl.TypeMaven:
+		pkg.Name = strings.Join(nonEmpty(purl.Namespace, purl.Name), ":")
+	case packageurl.TypeNPM, packageurl.TypeGolang, packageurl.TypeComposer, packageurl.TypeSwift:
+		pkg.Name = strings.Join(nonEmpty(purl.Namespace, purl.Name), "/")
+	}
+
+	return pkg
+}
+
+func splitRPMVersion(v string) (int, string, string) {
+	var epoch int
+	if e, ver, ok := strings.Cut(v, ":"); ok {
+		if n, err := strconv.Atoi(e); err == nil {
+			epoch, v = n, ver
+		}
+	}
+
+	var release string
+	if i := strings.LastIndex(v, "-"); i != -1 {
+		v, release = v[:i], v[i+1:]
+	}
+	return epoch, v, release
+}
+
+func nonEmpty(ss ...string) []string {
+	var ret []string
+	for _, s := range ss {
+		if s != "" {
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
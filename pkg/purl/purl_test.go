@@ -11,6 +11,7 @@ import (
 	"github.com/aquasecurity/fanal/analyzer"
 	"github.com/aquasecurity/fanal/analyzer/os"
 	ftypes "github.com/aquasecurity/fanal/types"
+	tos "github.com/aquasecurity/trivy/pkg/analyzer/os"
 	"github.com/aquasecurity/trivy/pkg/purl"
 	"github.com/aquasecurity/trivy/pkg/types"
 )
@@ -41,6 +42,22 @@ func TestNewPackageURL(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "carthage package",
+			typ:  types.Carthage,
+			pkg: ftypes.Package{
+				Name:    "github.com/Alamofire/Alamofire",
+				Version: "5.4.3",
+			},
+			want: purl.PackageURL{
+				PackageURL: packageurl.PackageURL{
+					Type:      packageurl.TypeSwift,
+					Namespace: "github.com/Alamofire",
+					Name:      "Alamofire",
+					Version:   "5.4.3",
+				},
+			},
+		},
 		{
 			name: "yarn package",
 			typ:  string(analyzer.TypeYarn),
@@ -160,6 +177,73 @@ func TestNewPackageURL(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "wolfi package",
+			typ:  tos.Wolfi,
+			pkg: ftypes.Package{
+				Name:    "openssl",
+				Version: "3.0.7-r0",
+				Arch:    "x86_64",
+			},
+			metadata: types.Metadata{
+				OS: &ftypes.OS{
+					Family: tos.Wolfi,
+					Name:   "20230201",
+				},
+			},
+			want: purl.PackageURL{
+				PackageURL: packageurl.PackageURL{
+					Type:      string(analyzer.TypeApk),
+					Namespace: "wolfi",
+					Name:      "openssl",
+					Version:   "3.0.7-r0",
+					Qualifiers: packageurl.Qualifiers{
+						{
+							Key:   "arch",
+							Value: "x86_64",
+						},
+						{
+							Key:   "distro",
+							Value: "20230201",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "bottlerocket package",
+			typ:  tos.Bottlerocket,
+			pkg: ftypes.Package{
+				Name:    "openssl",
+				Version: "1.1.1o",
+				Release: "1.1654641093.104f8e0f.br1",
+				Arch:    "x86_64",
+			},
+			metadata: types.Metadata{
+				OS: &ftypes.OS{
+					Family: tos.Bottlerocket,
+					Name:   "1.11.1",
+				},
+			},
+			want: purl.PackageURL{
+				PackageURL: packageurl.PackageURL{
+					Type:      packageurl.TypeRPM,
+					Namespace: "bottlerocket",
+					Name:      "openssl",
+					Version:   "1.1.1o-1.1654641093.104f8e0f.br1",
+					Qualifiers: packageurl.Qualifiers{
+						{
+							Key:   "arch",
+							Value: "x86_64",
+						},
+						{
+							Key:   "distro",
+							Value: "bottlerocket-1.11.1",
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "container",
 			typ:  purl.TypeOCI,
@@ -277,3 +361,86 @@ func TestNewPackageURL(t *testing.T) {
 		})
 	}
 }
+
+func TestPackageURL_Package(t *testing.T) {
+	tests := []struct {
+		name    string
+		purl    string
+		want    *ftypes.Package
+		wantOS  *ftypes.OS
+		appType string
+	}{
+		{
+			name: "rpm package with epoch",
+			purl: "pkg:rpm/redhat/openssl-libs@1:1.1.1k-5.el8_5?arch=x86_64&distro=redhat-8.5",
+			want: &ftypes.Package{
+				Name:    "openssl-libs",
+				Epoch:   1,
+				Version: "1.1.1k",
+				Release: "5.el8_5",
+				Arch:    "x86_64",
+			},
+			wantOS: &ftypes.OS{
+				Family: "redhat",
+				Name:   "8.5",
+			},
+		},
+		{
+			name: "deb package",
+			purl: "pkg:deb/debian/libc6@2.31-13+deb11u3?distro=debian-11",
+			want: &ftypes.Package{
+				Name:    "libc6",
+				Version: "2.31-13+deb11u3",
+			},
+			wantOS: &ftypes.OS{
+				Family: "debian",
+				Name:   "11",
+			},
+		},
+		{
+			name: "maven package",
+			purl: "pkg:maven/org.springframework/spring-core@5.3.14",
+			want: &ftypes.Package{
+				Name:    "org.springframework:spring-core",
+				Version: "5.3.14",
+			},
+			appType: ftypes.Jar,
+		},
+		{
+			name: "npm package with scope",
+			purl: "pkg:npm/%40xtuc/ieee754@1.2.0",
+			want: &ftypes.Package{
+				Name:    "@xtuc/ieee754",
+				Version: "1.2.0",
+			},
+			appType: ftypes.NodePkg,
+		},
+		{
+			name: "swift package",
+			purl: "pkg:swift/github.com/apple/swift-nio@2.0.0",
+			want: &ftypes.Package{
+				Name:    "github.com/apple/swift-nio",
+				Version: "2.0.0",
+			},
+			appType: types.Swift,
+		},
+		{
+			name: "unsupported type",
+			purl: "pkg:hex/phoenix@1.6.0",
+			want: &ftypes.Package{
+				Name:    "phoenix",
+				Version: "1.6.0",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := purl.FromString(tt.purl)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, p.Package())
+			assert.Equal(t, tt.wantOS, p.OS())
+			assert.Equal(t, tt.appType, p.AppType())
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package remediation
+
+import (
+	"sort"
+
+	"github.com/samber/lo"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+)
+
+// ReverseDeps returns IDs of the packages depending on each package
+func ReverseDeps(libs []ftypes.Package) map[string][]string {
+	reversed := make(map[string][]string)
+	for _, lib := range libs {
+		for _, dependOn := range lib.DependsOn {
+			items, ok := reversed[dependOn]
+			if !ok {
+				reversed[dependOn] = []string{lib.ID}
+			} else {
+				reversed[dependOn] = append(items, lib.ID)
+			}
+		}
+	}
+
+	for k, v := range reversed {
+		reversed[k] = lo.Uniq(v)
+	}
+	return reversed
+}
+
+// DirectDeps returns IDs of direct dependencies.
+// Packages without parents are regarded as direct dependencies if the lock file doesn't tell which ones are direct.
+func DirectDeps(libs []ftypes.Package, parents map[string][]string) map[string]struct{} {
+	hasIndirect := lo.ContainsBy(libs, func(lib ftypes.Package) bool {
+		return lib.Indirect
+	})
+
+	direct := map[string]struct{}{}
+	for _, lib := range libs {
+		if hasIndirect && !lib.Indirect {
+			direct[lib.ID] = struct{}{}
+		} else if _, ok := parents[lib.ID]; !hasIndirect && !ok {
+			direct[lib.ID] = struct{}{}
+		}
+	}
+	return direct
+}
+
+// directAncestors returns IDs of the direct dependencies pulling in the package
+func directAncestors(pkgID string, parents map[string][]string, direct map[string]struct{}) []string {
+	var ancestors []string
+	visited := map[string]struct{}{pkgID: {}}
+	queue := []string{pkgID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, parent := range parents[id] {
+			// Dependency graphs may have cycles
+			if _, ok := visited[parent]; ok {
+				continue
+			}
+			visited[parent] = struct{}{}
+
+			if _, ok := direct[parent]; ok {
+				ancestors = append(ancestors, parent)
+				continue
+			}
+			queue = append(queue, parent)
+		}
+	}
+	sort.Strings(ancestors)
+	return ancestors
+}
@@ -0,0 +1,267 @@
+package remediation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+	"golang.org/x/xerrors"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	npm "github.com/aquasecurity/go-npm-version/pkg"
+	pep440 "github.com/aquasecurity/go-pep440-version"
+	"github.com/aquasecurity/go-version/pkg/version"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+type compareVersions func(v1, v2 string) (int, error)
+
+// Only lock files holding dependency graphs or listing all the dependencies are supported
+var comparers = map[string]compareVersions{
+	ftypes.Cargo:     compareGeneric,
+	ftypes.Npm:       compareNpm,
+	ftypes.Yarn:      compareNpm,
+	types.Pnpm:       compareNpm,
+	ftypes.GoModule:  compareGeneric,
+	ftypes.Pip:       comparePep440,
+	ftypes.NuGet:     compareGeneric,
+	types.DotNetCore: compareGeneric,
+}
+
+// Advise computes the minimal set of direct dependency upgrades remediating the vulnerabilities in the result.
+// Vulnerabilities in transitive dependencies are attributed to the nearest direct dependencies pulling them in.
+// Transitive dependencies which can't be attributed, e.g. indirect modules in go.mod, are upgraded by themselves.
+// Vulnerabilities without fixed versions are not remediated.
+func Advise(result types.Result) []types.Remediation {
+	upgrades := Upgrades(result)
+	if len(upgrades) == 0 {
+		return nil
+	}
+
+	parents := ReverseDeps(result.Packages)
+	direct := DirectDeps(result.Packages, parents)
+	pkgs := lo.KeyBy(result.Packages, func(pkg ftypes.Package) string {
+		return pkg.ID
+	})
+
+	remediations := map[string]*types.Remediation{}
+	remediationOf := func(key, pkgID, pkgName, installedVersion string) *types.Remediation {
+		if r, ok := remediations[key]; ok {
+			return r
+		}
+		remediations[key] = &types.Remediation{
+			PkgID:            pkgID,
+			PkgName:          pkgName,
+			InstalledVersion: installedVersion,
+		}
+		return remediations[key]
+	}
+
+	for _, upgrade := range upgrades {
+		var ancestors []string
+		if _, ok := direct[upgrade.PkgID]; !ok && upgrade.PkgID != "" {
+			ancestors = directAncestors(upgrade.PkgID, parents, direct)
+		}
+
+		// Direct dependencies and the dependencies not attributed to direct ones are upgraded by themselves
+		if len(ancestors) == 0 {
+			r := remediationOf(pkgKey(upgrade.PkgID, upgrade.PkgName, upgrade.InstalledVersion),
+				upgrade.PkgID, upgrade.PkgName, upgrade.InstalledVersion)
+			r.FixedVersion = upgrade.FixedVersion
+			r.VulnerabilityIDs = upgrade.VulnerabilityIDs
+			continue
+		}
+
+		for _, ancestor := range ancestors {
+			pkg := pkgs[ancestor]
+			r := remediationOf(ancestor, pkg.ID, pkg.Name, pkg.Version)
+			r.Dependencies = append(r.Dependencies, upgrade)
+		}
+	}
+
+	var results []types.Remediation
+	for _, key := range sortedKeys(remediations) {
+		results = append(results, *remediations[key])
+	}
+	return results
+}
+
+// Upgrades returns the minimal upgrade of each vulnerable package in the result.
+// Packages are sorted by ID and those without fixed versions are not included.
+func Upgrades(result types.Result) []types.DependencyRemediation {
+	compare, ok := comparers[result.Type]
+	if !ok || len(result.Vulnerabilities) == 0 {
+		return nil
+	}
+
+	fixable := lo.Filter(result.Vulnerabilities, func(vuln types.DetectedVulnerability, _ int) bool {
+		return vuln.FixedVersion != ""
+	})
+	vulnsByPkg := lo.GroupBy(fixable, func(vuln types.DetectedVulnerability) string {
+		return pkgKey(vuln.PkgID, vuln.PkgName, vuln.InstalledVersion)
+	})
+
+	var upgrades []types.DependencyRemediation
+	for _, key := range sortedKeys(vulnsByPkg) {
+		vulns := vulnsByPkg[key]
+		fixedVersion, err := minimalFixedVersion(compare, vulns)
+		if err != nil {
+			log.Logger.Debugf("Unable to compute the fixed version of %s: %s", key, err)
+			continue
+		} else if fixedVersion == "" {
+			continue
+		}
+
+		vulnIDs := lo.Uniq(lo.Map(vulns, func(v types.DetectedVulnerability, _ int) string {
+			return v.VulnerabilityID
+		}))
+		sort.Strings(vulnIDs)
+
+		upgrades = append(upgrades, types.DependencyRemediation{
+			PkgID:            vulns[0].PkgID,
+			PkgName:          vulns[0].PkgName,
+			InstalledVersion: vulns[0].InstalledVersion,
+			FixedVersion:     fixedVersion,
+			VulnerabilityIDs: vulnIDs,
+		})
+	}
+	return upgrades
+}
+
+// minimalFixedVersion returns the lowest version fixing all the vulnerabilities of the package.
+// e.g. "2.6.7" for "2.6.7, 3.1.1" if the installed version is 2.6.0, and "3.1.1" if it is 3.0.0
+func minimalFixedVersion(compare compareVersions, vulns []types.DetectedVulnerability) (string, error) {
+	installed := vulns[0].InstalledVersion
+
+	var candidates []string
+	var fixedVersions [][]string
+	for _, vuln := range vulns {
+		var versions []string
+		for _, v := range strings.Split(vuln.FixedVersion, ",") {
+			v = strings.TrimSpace(v)
+			if _, err := compare(v, installed); err != nil {
+				log.Logger.Debugf("Invalid fixed version of %s: %s", vuln.VulnerabilityID, err)
+				continue
+			}
+			versions = append(versions, v)
+		}
+		if len(versions) == 0 {
+			return "", xerrors.Errorf("no valid fixed version in %s", vuln.VulnerabilityID)
+		}
+		if err := sortVersions(compare, versions); err != nil {
+			return "", xerrors.Errorf("sort error: %w", err)
+		}
+		fixedVersions = append(fixedVersions, versions)
+
+		for _, v := range versions {
+			if c, _ := compare(v, installed); c > 0 {
+				candidates = append(candidates, v)
+			}
+		}
+	}
+
+	candidates = lo.Uniq(candidates)
+	if err := sortVersions(compare, candidates); err != nil {
+		return "", xerrors.Errorf("sort error: %w", err)
+	}
+
+	for _, candidate := range candidates {
+		if lo.EveryBy(fixedVersions, func(versions []string) bool {
+			return fixedIn(compare, candidate, versions)
+		}) {
+			return candidate, nil
+		}
+	}
+	return "", nil
+}
+
+// fixedIn reports whether the version is out of the vulnerable ranges.
+// Fixed versions other than the latest one are regarded as backports only to the same minor version,
+// e.g. 2.7.0 is still vulnerable if the vulnerability is fixed in "2.6.7, 3.1.1".
+func fixedIn(compare compareVersions, ver string, fixedVersions []string) bool {
+	latest := fixedVersions[len(fixedVersions)-1]
+	if c, _ := compare(ver, latest); c >= 0 {
+		return true
+	}
+
+	// The highest fixed version lower than or equal to the version
+	for i := len(fixedVersions) - 1; i >= 0; i-- {
+		if c, _ := compare(fixedVersions[i], ver); c <= 0 {
+			return minorVersion(fixedVersions[i]) == minorVersion(ver)
+		}
+	}
+	return false
+}
+
+// minorVersion returns the major and minor versions, e.g. "2.6" for "v2.6.7"
+func minorVersion(ver string) string {
+	ss := strings.SplitN(strings.TrimPrefix(ver, "v"), ".", 3)
+	if len(ss) < 2 {
+		return ss[0]
+	}
+	return fmt.Sprintf("%s.%s", ss[0], ss[1])
+}
+
+func sortVersions(compare compareVersions, versions []string) error {
+	var err error
+	sort.SliceStable(versions, func(i, j int) bool {
+		c, e := compare(versions[i], versions[j])
+		if e != nil {
+			err = e
+		}
+		return c < 0
+	})
+	return err
+}
+
+// pkgKey identifies the package even if the lock file doesn't have IDs
+func pkgKey(pkgID, pkgName, pkgVersion string) string {
+	if pkgID != "" {
+		return pkgID
+	}
+	return fmt.Sprintf("%s@%s", pkgName, pkgVersion)
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := lo.Keys(m)
+	sort.Strings(keys)
+	return keys
+}
+
+func compareNpm(v1, v2 string) (int, error) {
+	ver1, err := npm.NewVersion(v1)
+	if err != nil {
+		return 0, xerrors.Errorf("npm version error (%s): %w", v1, err)
+	}
+	ver2, err := npm.NewVersion(v2)
+	if err != nil {
+		return 0, xerrors.Errorf("npm version error (%s): %w", v2, err)
+	}
+	return ver1.Compare(ver2), nil
+}
+
+func compareGeneric(v1, v2 string) (int, error) {
+	ver1, err := version.Parse(v1)
+	if err != nil {
+		return 0, xerrors.Errorf("version error (%s): %w", v1, err)
+	}
+	ver2, err := version.Parse(v2)
+	if err != nil {
+		return 0, xerrors.Errorf("version error (%s): %w", v2, err)
+	}
+	return ver1.Compare(ver2), nil
+}
+
+func comparePep440(v1, v2 string) (int, error) {
+	ver1, err := pep440.Parse(v1)
+	if err != nil {
+		return 0, xerrors.Errorf("python version error (%s): %w", v1, err)
+	}
+	ver2, err := pep440.Parse(v2)
+	if err != nil {
+		return 0, xerrors.Errorf("python version error (%s): %w", v2, err)
+	}
+	return ver1.Compare(ver2), nil
+}
@@ -0,0 +1,276 @@
+package remediation_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/remediation"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestAdvise(t *testing.T) {
+	npmPackages := []ftypes.Package{
+		{
+			ID:        "app-a@1.0.0",
+			Name:      "app-a",
+			Version:   "1.0.0",
+			DependsOn: []string{"lodash@4.17.15", "minimist@1.2.0"},
+		},
+		{
+			ID:        "app-b@2.0.0",
+			Name:      "app-b",
+			Version:   "2.0.0",
+			DependsOn: []string{"helper@1.0.0"},
+		},
+		{
+			ID:        "helper@1.0.0",
+			Name:      "helper",
+			Version:   "1.0.0",
+			Indirect:  true,
+			DependsOn: []string{"minimist@1.2.0", "cycle@1.0.0"},
+		},
+		{
+			ID:        "cycle@1.0.0",
+			Name:      "cycle",
+			Version:   "1.0.0",
+			Indirect:  true,
+			DependsOn: []string{"helper@1.0.0"},
+		},
+		{
+			ID:       "lodash@4.17.15",
+			Name:     "lodash",
+			Version:  "4.17.15",
+			Indirect: true,
+		},
+		{
+			ID:       "minimist@1.2.0",
+			Name:     "minimist",
+			Version:  "1.2.0",
+			Indirect: true,
+		},
+	}
+
+	tests := []struct {
+		name   string
+		result types.Result
+		want   []types.Remediation
+	}{
+		{
+			name: "npm",
+			result: types.Result{
+				Type:     ftypes.Npm,
+				Packages: npmPackages,
+				Vulnerabilities: []types.DetectedVulnerability{
+					{
+						VulnerabilityID:  "CVE-2020-8203",
+						PkgID:            "lodash@4.17.15",
+						PkgName:          "lodash",
+						InstalledVersion: "4.17.15",
+						FixedVersion:     "4.17.19",
+					},
+					{
+						VulnerabilityID:  "CVE-2021-23337",
+						PkgID:            "lodash@4.17.15",
+						PkgName:          "lodash",
+						InstalledVersion: "4.17.15",
+						FixedVersion:     "4.17.21",
+					},
+					{
+						VulnerabilityID:  "CVE-2020-7598",
+						PkgID:            "minimist@1.2.0",
+						PkgName:          "minimist",
+						InstalledVersion: "1.2.0",
+						FixedVersion:     "0.2.1, 1.2.3",
+					},
+					{
+						VulnerabilityID:  "CVE-2021-44906",
+						PkgID:            "minimist@1.2.0",
+						PkgName:          "minimist",
+						InstalledVersion: "1.2.0",
+						FixedVersion:     "0.2.4, 1.2.6",
+					},
+					{
+						VulnerabilityID:  "CVE-2022-0001",
+						PkgID:            "app-b@2.0.0",
+						PkgName:          "app-b",
+						InstalledVersion: "2.0.0",
+						FixedVersion:     "2.0.3",
+					},
+					{
+						VulnerabilityID:  "CVE-2022-0002",
+						PkgID:            "cycle@1.0.0",
+						PkgName:          "cycle",
+						InstalledVersion: "1.0.0",
+					},
+				},
+			},
+			want: []types.Remediation{
+				{
+					PkgID:            "app-a@1.0.0",
+					PkgName:          "app-a",
+					InstalledVersion: "1.0.0",
+					Dependencies: []types.DependencyRemediation{
+						{
+							PkgID:            "lodash@4.17.15",
+							PkgName:          "lodash",
+							InstalledVersion: "4.17.15",
+							FixedVersion:     "4.17.21",
+							VulnerabilityIDs: []string{"CVE-2020-8203", "CVE-2021-23337"},
+						},
+						{
+							PkgID:            "minimist@1.2.0",
+							PkgName:          "minimist",
+							InstalledVersion: "1.2.0",
+							FixedVersion:     "1.2.6",
+							VulnerabilityIDs: []string{"CVE-2020-7598", "CVE-2021-44906"},
+						},
+					},
+				},
+				{
+					PkgID:            "app-b@2.0.0",
+					PkgName:          "app-b",
+					InstalledVersion: "2.0.0",
+					FixedVersion:     "2.0.3",
+					VulnerabilityIDs: []string{"CVE-2022-0001"},
+					Dependencies: []types.DependencyRemediation{
+						{
+							PkgID:            "minimist@1.2.0",
+							PkgName:          "minimist",
+							InstalledVersion: "1.2.0",
+							FixedVersion:     "1.2.6",
+							VulnerabilityIDs: []string{"CVE-2020-7598", "CVE-2021-44906"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "backported fixes",
+			result: types.Result{
+				Type: ftypes.Yarn,
+				Vulnerabilities: []types.DetectedVulnerability{
+					{
+						VulnerabilityID:  "CVE-2022-0003",
+						PkgID:            "node-fetch@2.6.0",
+						PkgName:          "node-fetch",
+						InstalledVersion: "2.6.0",
+						FixedVersion:     "2.6.1, 3.0.0",
+					},
+					{
+						VulnerabilityID:  "CVE-2022-0004",
+						PkgID:            "node-fetch@2.6.0",
+						PkgName:          "node-fetch",
+						InstalledVersion: "2.6.0",
+						FixedVersion:     "2.6.7, 3.1.1",
+					},
+				},
+			},
+			want: []types.Remediation{
+				{
+					PkgID:            "node-fetch@2.6.0",
+					PkgName:          "node-fetch",
+					InstalledVersion: "2.6.0",
+					FixedVersion:     "2.6.7",
+					VulnerabilityIDs: []string{"CVE-2022-0003", "CVE-2022-0004"},
+				},
+			},
+		},
+		{
+			name: "go.mod",
+			result: types.Result{
+				Type: ftypes.GoModule,
+				Packages: []ftypes.Package{
+					{
+						Name:    "github.com/gin-gonic/gin",
+						Version: "1.6.2",
+					},
+					{
+						Name:     "golang.org/x/text",
+						Version:  "0.3.2",
+						Indirect: true,
+					},
+				},
+				Vulnerabilities: []types.DetectedVulnerability{
+					{
+						VulnerabilityID:  "CVE-2020-28483",
+						PkgName:          "github.com/gin-gonic/gin",
+						InstalledVersion: "1.6.2",
+						FixedVersion:     "1.7.0",
+					},
+					{
+						VulnerabilityID:  "CVE-2020-14040",
+						PkgName:          "golang.org/x/text",
+						InstalledVersion: "0.3.2",
+						FixedVersion:     "0.3.3",
+					},
+				},
+			},
+			want: []types.Remediation{
+				{
+					PkgName:          "github.com/gin-gonic/gin",
+					InstalledVersion: "1.6.2",
+					FixedVersion:     "1.7.0",
+					VulnerabilityIDs: []string{"CVE-2020-28483"},
+				},
+				{
+					PkgName:          "golang.org/x/text",
+					InstalledVersion: "0.3.2",
+					FixedVersion:     "0.3.3",
+					VulnerabilityIDs: []string{"CVE-2020-14040"},
+				},
+			},
+		},
+		{
+			name: "requirements.txt",
+			result: types.Result{
+				Type: ftypes.Pip,
+				Vulnerabilities: []types.DetectedVulnerability{
+					{
+						VulnerabilityID:  "CVE-2021-31542",
+						PkgName:          "django",
+						InstalledVersion: "3.2.0",
+						FixedVersion:     "2.2.21, 3.1.9, 3.2.1",
+					},
+					{
+						VulnerabilityID:  "CVE-2021-33203",
+						PkgName:          "django",
+						InstalledVersion: "3.2.0",
+						FixedVersion:     "2.2.24, 3.1.12, 3.2.4",
+					},
+				},
+			},
+			want: []types.Remediation{
+				{
+					PkgName:          "django",
+					InstalledVersion: "3.2.0",
+					FixedVersion:     "3.2.4",
+					VulnerabilityIDs: []string{"CVE-2021-31542", "CVE-2021-33203"},
+				},
+			},
+		},
+		{
+			name: "unsupported type",
+			result: types.Result{
+				Type: ftypes.Composer,
+				Vulnerabilities: []types.DetectedVulnerability{
+					{
+						VulnerabilityID:  "CVE-2022-0005",
+						PkgID:            "guzzlehttp/guzzle@7.4.0",
+						PkgName:          "guzzlehttp/guzzle",
+						InstalledVersion: "7.4.0",
+						FixedVersion:     "7.4.3",
+					},
+				},
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := remediation.Advise(tt.result)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
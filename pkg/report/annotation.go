@@ -0,0 +1,94 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const (
+	annotationError   = "error"
+	annotationWarning = "warning"
+	annotationNotice  = "notice"
+)
+
+var (
+	// https://github.com/actions/toolkit/blob/main/packages/core/src/command.ts
+	annotationDataEscaper = strings.NewReplacer(
+		"%", "%25",
+		"\r", "%0D",
+		"\n", "%0A",
+	)
+	annotationPropertyEscaper = strings.NewReplacer(
+		"%", "%25",
+		"\r", "%0D",
+		"\n", "%0A",
+		":", "%3A",
+		",", "%2C",
+	)
+)
+
+// GitHubAnnotationWriter emits workflow commands of GitHub Actions, e.g. "::error file=main.tf,line=2::message",
+// so that misconfigurations and secrets are annotated on the lines in pull requests.
+type GitHubAnnotationWriter struct {
+	Output io.Writer
+}
+
+func (w GitHubAnnotationWriter) Write(report types.Report) error {
+	for _, res := range report.Results {
+		for _, misconf := range res.Misconfigurations {
+			if misconf.Status != types.StatusFailure {
+				continue
+			}
+			message := misconf.Message
+			if misconf.PrimaryURL != "" {
+				message += "\nSee " + misconf.PrimaryURL
+			}
+			err := w.annotate(misconf.Severity, res.Target, misconf.CauseMetadata.StartLine, misconf.CauseMetadata.EndLine,
+				fmt.Sprintf("%s: %s", misconf.ID, misconf.Title), message)
+			if err != nil {
+				return err
+			}
+		}
+		for _, secret := range res.Secrets {
+			// Secrets in the past commits are not on the lines of the current files
+			if secret.History != nil {
+				continue
+			}
+			err := w.annotate(secret.Severity, res.Target, secret.StartLine, secret.EndLine,
+				fmt.Sprintf("%s: %s", secret.RuleID, secret.Title), fmt.Sprintf("Secret %s (%s)", secret.Title, secret.Category))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (w GitHubAnnotationWriter) annotate(severity, file string, startLine, endLine int, title, message string) error {
+	properties := []string{"file=" + annotationPropertyEscaper.Replace(toPathUri(file))}
+	if startLine > 0 {
+		properties = append(properties, fmt.Sprintf("line=%d", startLine))
+		if endLine > startLine {
+			properties = append(properties, fmt.Sprintf("endLine=%d", endLine))
+		}
+	}
+	properties = append(properties, "title="+annotationPropertyEscaper.Replace(title))
+
+	_, err := fmt.Fprintf(w.Output, "::%s %s::%s\n", toAnnotationLevel(severity),
+		strings.Join(properties, ","), annotationDataEscaper.Replace(message))
+	return err
+}
+
+func toAnnotationLevel(severity string) string {
+	switch severity {
+	case "CRITICAL", "HIGH":
+		return annotationError
+	case "MEDIUM":
+		return annotationWarning
+	default:
+		return annotationNotice
+	}
+}
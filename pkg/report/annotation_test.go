@@ -0,0 +1,121 @@
+package report_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/report"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestGitHubAnnotationWriter_Write(t *testing.T) {
+	tests := []struct {
+		name  string
+		input types.Results
+		want  string
+	}{
+		{
+			name: "misconfigurations",
+			input: types.Results{
+				{
+					Target: "main.tf",
+					Class:  types.ClassConfig,
+					Misconfigurations: []types.DetectedMisconfiguration{
+						{
+							ID:         "AVD-AWS-0092",
+							Title:      "S3 Buckets not publicly accessible through ACL.",
+							Message:    "Bucket has a public ACL: 'public-read'.",
+							PrimaryURL: "https://avd.aquasec.com/misconfig/avd-aws-0092",
+							Severity:   "HIGH",
+							Status:     types.StatusFailure,
+							CauseMetadata: ftypes.CauseMetadata{
+								StartLine: 3,
+								EndLine:   3,
+							},
+						},
+						{
+							ID:       "AVD-AWS-0086",
+							Title:    "S3 Access block should block public ACL",
+							Message:  "No public access block so not blocking public acls",
+							Severity: "LOW",
+							Status:   types.StatusFailure,
+							CauseMetadata: ftypes.CauseMetadata{
+								StartLine: 1,
+								EndLine:   4,
+							},
+						},
+						{
+							ID:       "AVD-AWS-0088",
+							Title:    "Unencrypted S3 bucket.",
+							Severity: "HIGH",
+							Status:   types.StatusPassed,
+						},
+					},
+				},
+				{
+					Target: "Dockerfile",
+					Class:  types.ClassConfig,
+					Misconfigurations: []types.DetectedMisconfiguration{
+						{
+							ID:       "DS026",
+							Title:    "No HEALTHCHECK defined",
+							Message:  "Add HEALTHCHECK instruction in your Dockerfile",
+							Severity: "LOW",
+							Status:   types.StatusFailure,
+						},
+					},
+				},
+			},
+			want: "::error file=main.tf,line=3,title=AVD-AWS-0092%3A S3 Buckets not publicly accessible through ACL.::Bucket has a public ACL: 'public-read'.%0ASee https://avd.aquasec.com/misconfig/avd-aws-0092\n" +
+				"::notice file=main.tf,line=1,endLine=4,title=AVD-AWS-0086%3A S3 Access block should block public ACL::No public access block so not blocking public acls\n" +
+				"::notice file=Dockerfile,title=DS026%3A No HEALTHCHECK defined::Add HEALTHCHECK instruction in your Dockerfile\n",
+		},
+		{
+			name: "secrets",
+			input: types.Results{
+				{
+					Target: "config/app.yaml",
+					Class:  types.ClassSecret,
+					Secrets: []types.DetectedSecret{
+						{
+							SecretFinding: ftypes.SecretFinding{
+								RuleID:    "aws-access-key-id",
+								Category:  "AWS",
+								Severity:  "CRITICAL",
+								Title:     "AWS Access Key ID",
+								StartLine: 5,
+								EndLine:   5,
+								Match:     "key: ********************",
+							},
+						},
+						{
+							SecretFinding: ftypes.SecretFinding{
+								RuleID:    "github-pat",
+								Category:  "GitHub",
+								Severity:  "CRITICAL",
+								Title:     "GitHub Personal Access Token",
+								StartLine: 8,
+								EndLine:   8,
+							},
+							History: &types.SecretHistory{},
+						},
+					},
+				},
+			},
+			want: "::error file=config/app.yaml,line=5,title=aws-access-key-id%3A AWS Access Key ID::Secret AWS Access Key ID (AWS)\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := bytes.NewBuffer(nil)
+			w := report.GitHubAnnotationWriter{Output: output}
+			err := w.Write(types.Report{Results: tt.input})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, output.String())
+		})
+	}
+}
@@ -15,8 +15,10 @@ import (
 	ftypes "github.com/aquasecurity/fanal/types"
 	dtypes "github.com/aquasecurity/trivy-db/pkg/types"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
+	"github.com/aquasecurity/trivy/pkg/licensing"
 	"github.com/aquasecurity/trivy/pkg/log"
 	"github.com/aquasecurity/trivy/pkg/purl"
+	"github.com/aquasecurity/trivy/pkg/sbom/evidence"
 	"github.com/aquasecurity/trivy/pkg/scanner/utils"
 	"github.com/aquasecurity/trivy/pkg/types"
 )
@@ -28,6 +30,9 @@ const (
 	PropertyType          = "Type"
 	PropertyClass         = "Class"
 
+	// PropertyLabel is followed by the key of the label, e.g. "aquasecurity:trivy:Label:team"
+	PropertyLabel = "Label:"
+
 	// Image properties
 	PropertySize       = "Size"
 	PropertyImageID    = "ImageID"
@@ -44,6 +49,7 @@ const (
 	PropertyFilePath        = "FilePath"
 	PropertyLayerDigest     = "LayerDigest"
 	PropertyLayerDiffID     = "LayerDiffID"
+	PropertyFoundAt         = "FoundAt"
 
 	// https://json-schema.org/understanding-json-schema/reference/string.html#dates-and-times
 	timeLayout = "2006-01-02T15:04:05+00:00"
@@ -62,6 +68,9 @@ type options struct {
 	format  cdx.BOMFileFormat
 	clock   clock.Clock
 	newUUID newUUID
+
+	// detail adds evidences and hashes of package files to components
+	detail bool
 }
 
 type option func(*options)
@@ -84,6 +93,13 @@ func WithNewUUID(newUUID newUUID) option {
 	}
 }
 
+// WithDetail adds the file evidences, license evidences and hashes to components
+func WithDetail(detail bool) option {
+	return func(opts *options) {
+		opts.detail = detail
+	}
+}
+
 func NewWriter(output io.Writer, version string, opts ...option) Writer {
 	o := &options{
 		format:  cdx.BOMFileFormatJSON,
@@ -150,14 +166,19 @@ func (cw *Writer) parseComponents(r types.Report, bomRef string) (*[]cdx.Compone
 	var metadataDependencies []cdx.Dependency
 	libraryUniqMap := map[string]struct{}{}
 	vulnMap := map[string]cdx.Vulnerability{}
+	// Vulnerabilities of the kernel result refer to packages in the result of OS packages
+	bomRefMap := map[string]string{}
+	collector := evidence.NewCollector(r)
 	for _, result := range r.Results {
 		var componentDependencies []cdx.Dependency
-		bomRefMap := map[string]string{}
 		for _, pkg := range result.Packages {
 			pkgComponent, err := cw.pkgToComponent(result.Type, r.Metadata, pkg)
 			if err != nil {
 				return nil, nil, nil, xerrors.Errorf("failed to parse pkg: %w", err)
 			}
+			if cw.detail {
+				addEvidence(&pkgComponent, collector.Collect(result, pkg))
+			}
 			if _, ok := bomRefMap[pkg.Name+utils.FormatVersion(pkg)+pkg.FilePath]; !ok {
 				bomRefMap[pkg.Name+utils.FormatVersion(pkg)+pkg.FilePath] = pkgComponent.BOMRef
 			}
@@ -200,8 +221,14 @@ func (cw *Writer) parseComponents(r types.Report, bomRef string) (*[]cdx.Compone
 			}
 		}
 
+		if result.Class == types.ClassKernelPkg {
+			// Kernel packages are components of the operating system
+			continue
+		}
+
 		if result.Type == ftypes.NodePkg || result.Type == ftypes.PythonPkg || result.Type == ftypes.GoBinary ||
-			result.Type == ftypes.GemSpec || result.Type == ftypes.Jar {
+			result.Type == ftypes.GemSpec || result.Type == ftypes.Jar || result.Type == types.RustBinary ||
+			result.Type == types.CondaPkg {
 			// If a package is language-specific package that isn't associated with a lock file,
 			// it will be a dependency of a component under "metadata".
 			// e.g.
@@ -291,9 +318,7 @@ func (cw *Writer) pkgToComponent(t string, meta types.Metadata, pkg ftypes.Packa
 	}
 
 	if pkg.License != "" {
-		component.Licenses = &cdx.Licenses{
-			cdx.LicenseChoice{Expression: pkg.License},
-		}
+		component.Licenses = &cdx.Licenses{toLicenseChoice(pkg.License)}
 	}
 
 	return component, nil
@@ -327,7 +352,8 @@ func (cw *Writer) reportToComponent(r types.Report) (*cdx.Component, error) {
 			component.BOMRef = p.ToString()
 			component.PackageURL = p.ToString()
 		}
-	case ftypes.ArtifactFilesystem, ftypes.ArtifactRemoteRepository:
+	default:
+		// Filesystems, repositories and SBOM documents
 		component.Type = cdx.ComponentTypeApplication
 		component.BOMRef = cw.newUUID().String()
 	}
@@ -341,6 +367,10 @@ func (cw *Writer) reportToComponent(r types.Report) (*cdx.Component, error) {
 	for _, t := range r.Metadata.RepoTags {
 		properties = appendProperties(properties, PropertyRepoTag, t)
 	}
+	for _, l := range r.Metadata.LabelPairs() {
+		key, value, _ := strings.Cut(l, "=")
+		properties = append(properties, property(PropertyLabel+key, value))
+	}
 
 	component.Properties = &properties
 
@@ -380,6 +410,25 @@ func (cw Writer) resultToComponent(r types.Result, osFound *ftypes.OS) cdx.Compo
 	return component
 }
 
+func addEvidence(component *cdx.Component, e evidence.Evidence) {
+	if e.FoundAt != "" {
+		*component.Properties = append(*component.Properties, property(PropertyFoundAt, e.FoundAt))
+	}
+	if e.SHA256 != "" {
+		component.Hashes = &[]cdx.Hash{
+			{
+				Algorithm: cdx.HashAlgoSHA256,
+				Value:     e.SHA256,
+			},
+		}
+	}
+	if e.License != "" {
+		component.Evidence = &cdx.Evidence{
+			Licenses: &cdx.Licenses{toLicenseChoice(e.License)},
+		}
+	}
+}
+
 func parseProperties(pkg ftypes.Package) []cdx.Property {
 	props := []struct {
 		name  string
@@ -569,3 +618,12 @@ func affects(ref, version string) cdx.Affects {
 		},
 	}
 }
+
+// toLicenseChoice normalizes the license into an SPDX expression.
+// The license is stored as a name if it can't be resolved to SPDX identifiers.
+func toLicenseChoice(license string) cdx.LicenseChoice {
+	if expr := licensing.Normalize(license); expr.Valid() {
+		return cdx.LicenseChoice{Expression: expr.Expression}
+	}
+	return cdx.LicenseChoice{License: &cdx.License{Name: license}}
+}
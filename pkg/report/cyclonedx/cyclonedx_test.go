@@ -28,6 +28,7 @@ func TestWriter_Write(t *testing.T) {
 	testCases := []struct {
 		name        string
 		inputReport types.Report
+		detail      bool
 		wantSBOM    *cdx.BOM
 	}{
 		{
@@ -199,7 +200,7 @@ func TestWriter_Write(t *testing.T) {
 						Name:    "binutils",
 						Version: "2.30-93.el8",
 						Licenses: &cdx.Licenses{
-							cdx.LicenseChoice{Expression: "GPLv3+"},
+							cdx.LicenseChoice{Expression: "GPL-3.0-or-later"},
 						},
 						PackageURL: "pkg:rpm/centos/binutils@2.30-93.el8?arch=aarch64&distro=centos-8.3.2011",
 						Properties: &[]cdx.Property{
@@ -589,7 +590,7 @@ func TestWriter_Write(t *testing.T) {
 						Name:    "acl",
 						Version: "1:2.2.53-1.el8",
 						Licenses: &cdx.Licenses{
-							cdx.LicenseChoice{Expression: "GPLv2+"},
+							cdx.LicenseChoice{Expression: "GPL-2.0-or-later"},
 						},
 						PackageURL: "pkg:rpm/centos/acl@1:2.2.53-1.el8?arch=aarch64&distro=centos-8.3.2011",
 						Properties: &[]cdx.Property{
@@ -940,6 +941,151 @@ func TestWriter_Write(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "happy path with detail",
+			inputReport: types.Report{
+				SchemaVersion: report.SchemaVersion,
+				ArtifactName:  "testdata/fs",
+				ArtifactType:  ftypes.ArtifactFilesystem,
+				Results: types.Results{
+					{
+						Target: "Java",
+						Class:  types.ClassLangPkg,
+						Type:   ftypes.Jar,
+						Packages: []ftypes.Package{
+							{
+								Name:     "org.springframework:spring-core",
+								Version:  "5.3.20",
+								License:  "Apache-2.0",
+								FilePath: "app/spring-core-5.3.20.jar",
+							},
+						},
+					},
+					{
+						Target: "app/package-lock.json",
+						Class:  types.ClassLangPkg,
+						Type:   ftypes.Npm,
+						Packages: []ftypes.Package{
+							{
+								Name:    "lodash",
+								Version: "4.17.21",
+							},
+						},
+					},
+				},
+			},
+			detail: true,
+			wantSBOM: &cdx.BOM{
+				BOMFormat:    "CycloneDX",
+				SpecVersion:  "1.4",
+				SerialNumber: "urn:uuid:3ff14136-e09f-4df9-80ea-000000000001",
+				Version:      1,
+				Metadata: &cdx.Metadata{
+					Timestamp: "2021-08-25T12:20:30+00:00",
+					Tools: &[]cdx.Tool{
+						{
+							Name:    "trivy",
+							Vendor:  "aquasecurity",
+							Version: "dev",
+						},
+					},
+					Component: &cdx.Component{
+						Type:   cdx.ComponentTypeApplication,
+						Name:   "testdata/fs",
+						BOMRef: "3ff14136-e09f-4df9-80ea-000000000002",
+						Properties: &[]cdx.Property{
+							{
+								Name:  "aquasecurity:trivy:SchemaVersion",
+								Value: "2",
+							},
+						},
+					},
+				},
+				Components: &[]cdx.Component{
+					{
+						BOMRef:     "pkg:maven/org.springframework/spring-core@5.3.20?file_path=app%2Fspring-core-5.3.20.jar",
+						Type:       "library",
+						Name:       "org.springframework:spring-core",
+						Version:    "5.3.20",
+						PackageURL: "pkg:maven/org.springframework/spring-core@5.3.20",
+						Hashes: &[]cdx.Hash{
+							{
+								Algorithm: cdx.HashAlgoSHA256,
+								Value:     "3eef80104cad33786401dd10cdfb718ef9c4803372ac5eae0732843699d93742",
+							},
+						},
+						Licenses: &cdx.Licenses{
+							cdx.LicenseChoice{Expression: "Apache-2.0"},
+						},
+						Properties: &[]cdx.Property{
+							{
+								Name:  "aquasecurity:trivy:FilePath",
+								Value: "app/spring-core-5.3.20.jar",
+							},
+							{
+								Name:  "aquasecurity:trivy:FoundAt",
+								Value: "app/spring-core-5.3.20.jar",
+							},
+						},
+						Evidence: &cdx.Evidence{
+							Licenses: &cdx.Licenses{
+								cdx.LicenseChoice{Expression: "Apache-2.0"},
+							},
+						},
+					},
+					{
+						BOMRef:     "pkg:npm/lodash@4.17.21",
+						Type:       "library",
+						Name:       "lodash",
+						Version:    "4.17.21",
+						PackageURL: "pkg:npm/lodash@4.17.21",
+						Properties: &[]cdx.Property{
+							{
+								Name:  "aquasecurity:trivy:FoundAt",
+								Value: "app/package-lock.json",
+							},
+						},
+					},
+					{
+						BOMRef: "3ff14136-e09f-4df9-80ea-000000000003",
+						Type:   cdx.ComponentTypeApplication,
+						Name:   "app/package-lock.json",
+						Properties: &[]cdx.Property{
+							{
+								Name:  "aquasecurity:trivy:Type",
+								Value: "npm",
+							},
+							{
+								Name:  "aquasecurity:trivy:Class",
+								Value: "lang-pkgs",
+							},
+						},
+					},
+				},
+				Vulnerabilities: &[]cdx.Vulnerability{},
+				Dependencies: &[]cdx.Dependency{
+					{
+						Ref: "3ff14136-e09f-4df9-80ea-000000000003",
+						Dependencies: &[]cdx.Dependency{
+							{
+								Ref: "pkg:npm/lodash@4.17.21",
+							},
+						},
+					},
+					{
+						Ref: "3ff14136-e09f-4df9-80ea-000000000002",
+						Dependencies: &[]cdx.Dependency{
+							{
+								Ref: "pkg:maven/org.springframework/spring-core@5.3.20?file_path=app%2Fspring-core-5.3.20.jar",
+							},
+							{
+								Ref: "3ff14136-e09f-4df9-80ea-000000000003",
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "happy path empty",
 			inputReport: types.Report{
@@ -983,6 +1129,63 @@ func TestWriter_Write(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "happy path with labels",
+			inputReport: types.Report{
+				SchemaVersion: report.SchemaVersion,
+				ArtifactName:  "empty/path",
+				ArtifactType:  ftypes.ArtifactFilesystem,
+				Metadata: types.Metadata{
+					Labels: map[string]string{
+						"team": "payments",
+						"env":  "prod",
+					},
+				},
+				Results: types.Results{},
+			},
+
+			wantSBOM: &cdx.BOM{
+				BOMFormat:    "CycloneDX",
+				SpecVersion:  "1.4",
+				SerialNumber: "urn:uuid:3ff14136-e09f-4df9-80ea-000000000001",
+				Version:      1,
+				Metadata: &cdx.Metadata{
+					Timestamp: "2021-08-25T12:20:30+00:00",
+					Tools: &[]cdx.Tool{
+						{
+							Name:    "trivy",
+							Vendor:  "aquasecurity",
+							Version: "dev",
+						},
+					},
+					Component: &cdx.Component{
+						Type:   cdx.ComponentTypeApplication,
+						Name:   "empty/path",
+						BOMRef: "3ff14136-e09f-4df9-80ea-000000000002",
+						Properties: &[]cdx.Property{
+							{
+								Name:  "aquasecurity:trivy:SchemaVersion",
+								Value: "2",
+							},
+							{
+								Name:  "aquasecurity:trivy:Label:env",
+								Value: "prod",
+							},
+							{
+								Name:  "aquasecurity:trivy:Label:team",
+								Value: "payments",
+							},
+						},
+					},
+				},
+				Vulnerabilities: &[]cdx.Vulnerability{},
+				Dependencies: &[]cdx.Dependency{
+					{
+						Ref: "3ff14136-e09f-4df9-80ea-000000000002",
+					},
+				},
+			},
+		},
 	}
 
 	clock := fake.NewFakeClock(time.Date(2021, 8, 25, 12, 20, 30, 5, time.UTC))
@@ -996,7 +1199,8 @@ func TestWriter_Write(t *testing.T) {
 			}
 
 			output := bytes.NewBuffer(nil)
-			writer := cyclonedx.NewWriter(output, "dev", cyclonedx.WithClock(clock), cyclonedx.WithNewUUID(newUUID))
+			writer := cyclonedx.NewWriter(output, "dev", cyclonedx.WithClock(clock), cyclonedx.WithNewUUID(newUUID),
+				cyclonedx.WithDetail(tc.detail))
 
 			err := writer.Write(tc.inputReport)
 			require.NoError(t, err)
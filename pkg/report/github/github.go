@@ -150,6 +150,9 @@ func getMetadata(report types.Report) Metadata {
 	if report.Metadata.RepoDigests != nil {
 		metadata["aquasecurity:trivy:RepoDigest"] = strings.Join(report.Metadata.RepoDigests, ", ")
 	}
+	for k, v := range report.Metadata.Labels {
+		metadata["aquasecurity:trivy:Label:"+k] = v
+	}
 	return metadata
 }
 
@@ -0,0 +1,175 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/aquasecurity/table"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// layerFinding is a finding introduced by a layer
+type layerFinding struct {
+	target   string
+	kind     string
+	id       string
+	severity string
+	detail   string
+}
+
+// layerGroup holds the findings introduced by the layer
+type layerGroup struct {
+	diffID   string
+	command  string
+	findings []layerFinding
+}
+
+// writeLayers writes the findings grouped by the layer introducing them, so that users can tell which build step to change.
+// Findings not bound to layers, such as provenance and signature violations, are written as usual.
+func (tw TableWriter) writeLayers(report types.Report) {
+	groups := groupByLayer(report)
+	for i, g := range groups {
+		if len(g.findings) == 0 {
+			continue
+		}
+		tw.writeLayer(i, g, report.Metadata.BaseImage)
+	}
+
+	for _, result := range report.Results {
+		if result.Class == types.ClassProvenance || result.Class == types.ClassSignature {
+			tw.write(result)
+		}
+	}
+}
+
+// groupByLayer returns the groups in the order of the layers, followed by the group of findings whose layers are unknown
+func groupByLayer(report types.Report) []layerGroup {
+	commands := layerCommands(report.Metadata)
+
+	var groups []layerGroup
+	indices := map[string]int{}
+	for i, diffID := range report.Metadata.DiffIDs {
+		groups = append(groups, layerGroup{
+			diffID:  diffID,
+			command: commands[i],
+		})
+		indices[diffID] = i
+	}
+	groups = append(groups, layerGroup{})
+	unknown := len(groups) - 1
+
+	add := func(diffID string, f layerFinding) {
+		i, ok := indices[diffID]
+		if !ok {
+			i = unknown
+		}
+		groups[i].findings = append(groups[i].findings, f)
+	}
+
+	for _, result := range report.Results {
+		for _, v := range result.Vulnerabilities {
+			add(v.Layer.DiffID, layerFinding{
+				target:   result.Target,
+				kind:     "vulnerability",
+				id:       v.VulnerabilityID,
+				severity: v.Severity,
+				detail:   fmt.Sprintf("%s %s", v.PkgName, v.InstalledVersion),
+			})
+		}
+		for _, m := range result.Misconfigurations {
+			if m.Status != types.StatusFailure {
+				continue
+			}
+			add(m.Layer.DiffID, layerFinding{
+				target:   result.Target,
+				kind:     "misconfiguration",
+				id:       m.ID,
+				severity: m.Severity,
+				detail:   m.Title,
+			})
+		}
+		// Secrets are not bound to layers yet
+		for _, s := range result.Secrets {
+			add("", layerFinding{
+				target:   result.Target,
+				kind:     "secret",
+				id:       s.RuleID,
+				severity: s.Severity,
+				detail:   s.Title,
+			})
+		}
+	}
+	return groups
+}
+
+// layerCommands returns the commands creating the layers in the order of the diff IDs.
+// The history has entries without layers, e.g. ENV, which are skipped.
+func layerCommands(metadata types.Metadata) []string {
+	commands := make([]string, len(metadata.DiffIDs))
+	var i int
+	for _, h := range metadata.ImageConfig.History {
+		if h.EmptyLayer {
+			continue
+		}
+		if i == len(commands) {
+			break
+		}
+		commands[i] = formatCommand(h.CreatedBy)
+		i++
+	}
+	return commands
+}
+
+// formatCommand trims the shell prefix added by the Docker builder and the suffix added by BuildKit
+func formatCommand(createdBy string) string {
+	s := strings.TrimPrefix(createdBy, "/bin/sh -c #(nop) ")
+	s = strings.TrimPrefix(s, "/bin/sh -c ")
+	s = strings.TrimSuffix(s, " # buildkit")
+	return strings.TrimSpace(s)
+}
+
+func (tw TableWriter) writeLayer(index int, group layerGroup, base *types.BaseImage) {
+	title := "Unknown layer"
+	if group.diffID != "" {
+		title = fmt.Sprintf("Layer %d", index+1)
+		if base != nil && slices.Contains(base.DiffIDs, group.diffID) {
+			title += " (base image)"
+		}
+	}
+
+	severityCount := map[string]int{}
+	for _, f := range group.findings {
+		severityCount[f.severity]++
+	}
+	total, summaries := tw.summary(severityCount)
+
+	_, _ = fmt.Fprintf(tw.Output, "\n%s\n%s\n", title, strings.Repeat("=", len(title)))
+	if group.diffID != "" {
+		_, _ = fmt.Fprintf(tw.Output, "DiffID: %s\n", group.diffID)
+		if group.command != "" {
+			_, _ = fmt.Fprintf(tw.Output, "Command: %s\n", group.command)
+		}
+	}
+	_, _ = fmt.Fprintf(tw.Output, "Total: %d (%s)\n\n", total, strings.Join(summaries, ", "))
+
+	tableWriter := table.New(tw.Output)
+	if tw.isOutputToTerminal() {
+		tableWriter.SetHeaderStyle(table.StyleBold)
+		tableWriter.SetLineStyle(table.StyleDim)
+	}
+	tableWriter.SetBorders(true)
+	tableWriter.SetAutoMerge(true)
+	tableWriter.SetRowLines(true)
+	tableWriter.SetHeaders("Target", "Type", "ID", "Severity", "Detail")
+	tableWriter.SetAlignment(table.AlignLeft, table.AlignCenter, table.AlignLeft, table.AlignCenter, table.AlignLeft)
+	for _, f := range group.findings {
+		severity := f.severity
+		if tw.isOutputToTerminal() {
+			severity = ColorizeSeverity(severity, severity)
+		}
+		tableWriter.AddRow(f.target, f.kind, f.id, severity, f.detail)
+	}
+	tableWriter.Render()
+}
@@ -0,0 +1,121 @@
+package report_test
+
+import (
+	"bytes"
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/report"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestReportWriter_TableLayers(t *testing.T) {
+	const (
+		alpineLayer = "sha256:994393dc58e7931862558d06e46aa2bb17487044f670f310dffe1d24e4d1eec7"
+		curlLayer   = "sha256:5e9ae3bfc4a0e0b3a7ccd7bbcd6e1b8bb2a0af8c5a7b1e0e74e7a0e94c35d2bb"
+		appLayer    = "sha256:0b4a8f4a3f6c8b1e9d2a7c5e3f1b8d6a4c2e0f9b7d5a3c1e8f6b4d2a0c9e7f5b"
+	)
+
+	r := types.Report{
+		Metadata: types.Metadata{
+			DiffIDs: []string{alpineLayer, curlLayer, appLayer},
+			ImageConfig: v1.ConfigFile{
+				History: []v1.History{
+					{CreatedBy: "/bin/sh -c #(nop) ADD file:5d673d25da3a14ce1f6cf66e4c7fd4f4b85a3759a9d93efb3fd9ff852b5b56e4 in / "},
+					{CreatedBy: `/bin/sh -c #(nop)  CMD ["/bin/sh"]`, EmptyLayer: true},
+					{CreatedBy: "RUN /bin/sh -c apk add --no-cache curl # buildkit"},
+					{CreatedBy: "COPY app /app # buildkit"},
+				},
+			},
+			BaseImage: &types.BaseImage{
+				DiffIDs: []string{alpineLayer},
+			},
+		},
+		Results: types.Results{
+			{
+				Target: "acme/app:1.0 (alpine 3.16.2)",
+				Class:  types.ClassOSPkg,
+				Vulnerabilities: []types.DetectedVulnerability{
+					{
+						VulnerabilityID:  "CVE-2022-37434",
+						PkgName:          "zlib",
+						InstalledVersion: "1.2.12-r1",
+						Layer:            ftypes.Layer{DiffID: alpineLayer},
+						Vulnerability:    dbTypes.Vulnerability{Severity: "CRITICAL"},
+					},
+					{
+						VulnerabilityID:  "CVE-2022-43551",
+						PkgName:          "curl",
+						InstalledVersion: "7.83.1-r4",
+						Layer:            ftypes.Layer{DiffID: curlLayer},
+						Vulnerability:    dbTypes.Vulnerability{Severity: "HIGH"},
+					},
+				},
+			},
+			{
+				Target: "/app/.env",
+				Class:  types.ClassSecret,
+				Secrets: []types.DetectedSecret{
+					{
+						SecretFinding: ftypes.SecretFinding{
+							RuleID:   "aws-access-key-id",
+							Severity: "CRITICAL",
+							Title:    "AWS Access Key ID",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	buf := bytes.Buffer{}
+	err := report.Write(r, report.Option{
+		Format:     "table",
+		Output:     &buf,
+		Severities: []dbTypes.Severity{dbTypes.SeverityHigh, dbTypes.SeverityCritical},
+		Report:     report.ReportLayers,
+	})
+	require.NoError(t, err)
+
+	want := `
+Layer 1 (base image)
+====================
+DiffID: sha256:994393dc58e7931862558d06e46aa2bb17487044f670f310dffe1d24e4d1eec7
+Command: ADD file:5d673d25da3a14ce1f6cf66e4c7fd4f4b85a3759a9d93efb3fd9ff852b5b56e4 in /
+Total: 1 (HIGH: 0, CRITICAL: 1)
+
+┌──────────────────────────────┬───────────────┬────────────────┬──────────┬────────────────┐
+│            Target            │     Type      │       ID       │ Severity │     Detail     │
+├──────────────────────────────┼───────────────┼────────────────┼──────────┼────────────────┤
+│ acme/app:1.0 (alpine 3.16.2) │ vulnerability │ CVE-2022-37434 │ CRITICAL │ zlib 1.2.12-r1 │
+└──────────────────────────────┴───────────────┴────────────────┴──────────┴────────────────┘
+
+Layer 2
+=======
+DiffID: sha256:5e9ae3bfc4a0e0b3a7ccd7bbcd6e1b8bb2a0af8c5a7b1e0e74e7a0e94c35d2bb
+Command: RUN /bin/sh -c apk add --no-cache curl
+Total: 1 (HIGH: 1, CRITICAL: 0)
+
+┌──────────────────────────────┬───────────────┬────────────────┬──────────┬────────────────┐
+│            Target            │     Type      │       ID       │ Severity │     Detail     │
+├──────────────────────────────┼───────────────┼────────────────┼──────────┼────────────────┤
+│ acme/app:1.0 (alpine 3.16.2) │ vulnerability │ CVE-2022-43551 │   HIGH   │ curl 7.83.1-r4 │
+└──────────────────────────────┴───────────────┴────────────────┴──────────┴────────────────┘
+
+Unknown layer
+=============
+Total: 1 (HIGH: 0, CRITICAL: 1)
+
+┌───────────┬────────┬───────────────────┬──────────┬───────────────────┐
+│  Target   │  Type  │        ID         │ Severity │      Detail       │
+├───────────┼────────┼───────────────────┼──────────┼───────────────────┤
+│ /app/.env │ secret │ aws-access-key-id │ CRITICAL │ AWS Access Key ID │
+└───────────┴────────┴───────────────────┴──────────┴───────────────────┘
+`
+	assert.Equal(t, want, buf.String())
+}
@@ -106,6 +106,11 @@ func (r *misconfigRenderer) renderSummary(misconf types.DetectedMisconfiguration
 	// description
 	r.printf("<dim>%s\r\n", misconf.Description)
 
+	// show the owner defined by the check overrides
+	if misconf.Owner != "" {
+		r.printf("\r\n<dim>Owner: %s\r\n", misconf.Owner)
+	}
+
 	// show link if we have one
 	if misconf.PrimaryURL != "" {
 		r.printf("\r\n<dim>See %s\r\n", misconf.PrimaryURL)
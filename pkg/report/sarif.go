@@ -17,6 +17,7 @@ const (
 	sarifOsPackageVulnerability        = "OsPackageVulnerability"
 	sarifLanguageSpecificVulnerability = "LanguageSpecificPackageVulnerability"
 	sarifConfigFiles                   = "Misconfiguration"
+	sarifLicense                       = "License"
 	sarifUnknownIssue                  = "UnknownIssue"
 
 	sarifError   = "error"
@@ -54,6 +55,7 @@ type sarifData struct {
 	artifactLocation string
 	message          string
 	cvssScore        string
+	risk             *types.Risk
 	startLine        int
 	endLine          int
 }
@@ -100,6 +102,13 @@ func (sw *SarifWriter) addSarifResult(data *sarifData) {
 		WithMessage(sarif.NewTextMessage(data.message)).
 		WithLevel(toSarifErrorLevel(data.severity)).
 		WithLocations([]*sarif.Location{sarif.NewLocation().WithPhysicalLocation(location)})
+
+	// The rank of SARIF is the priority of the result from 0.0 to 100.0 as the risk score
+	if data.risk != nil {
+		rank := float32(data.risk.Score)
+		result.Rank = &rank
+		result.Properties = sarif.Properties{"risk": data.risk}
+	}
 	sw.run.AddResult(result)
 }
 
@@ -122,6 +131,11 @@ func (sw SarifWriter) Write(report types.Report) error {
 	sw.run.Tool.Driver.WithVersion(sw.Version)
 	sw.run.Tool.Driver.WithFullName("Trivy Vulnerability Scanner")
 
+	// Labels are kept in the run so that results can be sliced by them
+	if len(report.Metadata.Labels) > 0 {
+		sw.run.Properties = sarif.Properties{"labels": report.Metadata.Labels}
+	}
+
 	ruleIndexes := map[string]int{}
 	for _, res := range report.Results {
 		for _, vuln := range res.Vulnerabilities {
@@ -138,6 +152,7 @@ func (sw SarifWriter) Write(report types.Report) error {
 				vulnerabilityId:  vuln.VulnerabilityID,
 				severity:         vuln.Severity,
 				cvssScore:        getCVSSScore(vuln),
+				risk:             vuln.Risk,
 				url:              vuln.PrimaryURL,
 				resourceClass:    string(res.Class),
 				artifactLocation: toPathUri(path),
@@ -172,6 +187,29 @@ func (sw SarifWriter) Write(report types.Report) error {
 					res.Target, res.Type, misconf.ID, misconf.Severity, misconf.Message, misconf.ID, misconf.PrimaryURL),
 			})
 		}
+		for _, license := range res.Licenses {
+			path := license.FilePath
+			if path == "" {
+				path = res.Target
+			}
+			sw.addSarifResult(&sarifData{
+				title:            "license",
+				vulnerabilityId:  license.Name,
+				severity:         license.Severity,
+				cvssScore:        severityToScore(license.Severity),
+				url:              license.Link,
+				resourceClass:    string(res.Class),
+				artifactLocation: toPathUri(path),
+				resultIndex:      getRuleIndex(license.Name, ruleIndexes),
+				fullDescription:  html.EscapeString(fmt.Sprintf("License %s is classified as %s", license.Name, license.Category)),
+				helpText: fmt.Sprintf("License %v\nClassification: %v\nSeverity: %v\nLink: [%v](%v)",
+					license.Name, license.Category, license.Severity, license.Name, license.Link),
+				helpMarkdown: fmt.Sprintf("**License %v**\n| Classification | Severity | Link |\n| --- | --- | --- |\n|%v|%v|[%v](%v)|",
+					license.Name, license.Category, license.Severity, license.Name, license.Link),
+				message: fmt.Sprintf("Artifact: %v\nPackage: %v\nLicense: %v\nClassification: %v\nSeverity: %v",
+					path, license.PkgName, license.Name, license.Category, license.Severity),
+			})
+		}
 	}
 	sw.run.ColumnKind = columnKind
 	sw.run.OriginalUriBaseIDs = map[string]*sarif.ArtifactLocation{
@@ -183,12 +221,14 @@ func (sw SarifWriter) Write(report types.Report) error {
 
 func toSarifRuleName(class string) string {
 	switch class {
-	case types.ClassOSPkg:
+	case types.ClassOSPkg, types.ClassKernelPkg:
 		return sarifOsPackageVulnerability
 	case types.ClassLangPkg:
 		return sarifLanguageSpecificVulnerability
 	case types.ClassConfig:
 		return sarifConfigFiles
+	case types.ClassLicense, types.ClassLicenseFile:
+		return sarifLicense
 	default:
 		return sarifUnknownIssue
 	}
@@ -220,6 +220,145 @@ func TestReportWriter_Sarif(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "report with licenses",
+			input: types.Results{
+				{
+					Target: "OS Packages",
+					Class:  types.ClassLicense,
+					Licenses: []types.DetectedLicense{
+						{
+							Severity:   "HIGH",
+							Category:   types.CategoryRestricted,
+							PkgName:    "bash",
+							Name:       "GPL-3.0",
+							Confidence: 1,
+							Link:       "https://spdx.org/licenses/GPL-3.0.html",
+						},
+					},
+				},
+			},
+			wantResults: []*sarif.Result{
+				{
+					RuleID:    toPtr("GPL-3.0"),
+					RuleIndex: toPtr[uint](0),
+					Level:     toPtr("error"),
+					Message:   sarif.Message{Text: toPtr("Artifact: OS Packages\nPackage: bash\nLicense: GPL-3.0\nClassification: restricted\nSeverity: HIGH")},
+					Locations: []*sarif.Location{
+						{
+							PhysicalLocation: &sarif.PhysicalLocation{
+								ArtifactLocation: &sarif.ArtifactLocation{
+									URI:       toPtr("OS Packages"),
+									URIBaseId: toPtr("ROOTPATH"),
+								},
+								Region: &sarif.Region{StartLine: toPtr(1)},
+							},
+						},
+					},
+				},
+			},
+			wantRules: []*sarif.ReportingDescriptor{
+				{
+					ID:               "GPL-3.0",
+					Name:             toPtr("License"),
+					ShortDescription: &sarif.MultiformatMessageString{Text: toPtr("GPL-3.0")},
+					FullDescription:  &sarif.MultiformatMessageString{Text: toPtr("License GPL-3.0 is classified as restricted")},
+					DefaultConfiguration: &sarif.ReportingConfiguration{
+						Level: "error",
+					},
+					HelpURI: toPtr("https://spdx.org/licenses/GPL-3.0.html"),
+					Properties: map[string]interface{}{
+						"tags": []interface{}{
+							"license",
+							"security",
+							"HIGH",
+						},
+						"precision":         "very-high",
+						"security-severity": "8.0",
+					},
+					Help: &sarif.MultiformatMessageString{
+						Text:     toPtr("License GPL-3.0\nClassification: restricted\nSeverity: HIGH\nLink: [GPL-3.0](https://spdx.org/licenses/GPL-3.0.html)"),
+						Markdown: toPtr("**License GPL-3.0**\n| Classification | Severity | Link |\n| --- | --- | --- |\n|restricted|HIGH|[GPL-3.0](https://spdx.org/licenses/GPL-3.0.html)|"),
+					},
+				},
+			},
+		},
+		{
+			name: "report with risk scores",
+			input: types.Results{
+				{
+					Target: "test",
+					Class:  types.ClassOSPkg,
+					Vulnerabilities: []types.DetectedVulnerability{
+						{
+							VulnerabilityID:  "CVE-2020-0001",
+							PkgName:          "foo",
+							InstalledVersion: "1.2.3",
+							FixedVersion:     "3.4.5",
+							Risk:             &types.Risk{Score: 73.5, CVSS: 7.5, EPSS: 0.45, KEV: true},
+							Vulnerability: dbTypes.Vulnerability{
+								Title:    "foobar",
+								Severity: "HIGH",
+							},
+						},
+					},
+				},
+			},
+			wantRules: []*sarif.ReportingDescriptor{
+				{
+					ID:               "CVE-2020-0001",
+					Name:             toPtr("OsPackageVulnerability"),
+					ShortDescription: &sarif.MultiformatMessageString{Text: toPtr("CVE-2020-0001")},
+					FullDescription:  &sarif.MultiformatMessageString{Text: toPtr("foobar")},
+					DefaultConfiguration: &sarif.ReportingConfiguration{
+						Level: "error",
+					},
+					Properties: map[string]interface{}{
+						"tags": []interface{}{
+							"vulnerability",
+							"security",
+							"HIGH",
+						},
+						"precision":         "very-high",
+						"security-severity": "8.0",
+					},
+					Help: &sarif.MultiformatMessageString{
+						Text:     toPtr("Vulnerability CVE-2020-0001\nSeverity: HIGH\nPackage: foo\nFixed Version: 3.4.5\nLink: [CVE-2020-0001]()\n"),
+						Markdown: toPtr("**Vulnerability CVE-2020-0001**\n| Severity | Package | Fixed Version | Link |\n| --- | --- | --- | --- |\n|HIGH|foo|3.4.5|[CVE-2020-0001]()|\n\n"),
+					},
+				},
+			},
+			wantResults: []*sarif.Result{
+				{
+					PropertyBag: sarif.PropertyBag{
+						Properties: sarif.Properties{
+							"risk": map[string]interface{}{
+								"Score": 73.5,
+								"CVSS":  7.5,
+								"EPSS":  0.45,
+								"KEV":   true,
+							},
+						},
+					},
+					RuleID:    toPtr("CVE-2020-0001"),
+					RuleIndex: toPtr[uint](0),
+					Level:     toPtr("error"),
+					Message:   sarif.Message{Text: toPtr("Package: foo\nInstalled Version: 1.2.3\nVulnerability CVE-2020-0001\nSeverity: HIGH\nFixed Version: 3.4.5\nLink: [CVE-2020-0001]()")},
+					Locations: []*sarif.Location{
+						{
+							PhysicalLocation: &sarif.PhysicalLocation{
+								ArtifactLocation: &sarif.ArtifactLocation{
+									URI:       toPtr("test"),
+									URIBaseId: toPtr("ROOTPATH"),
+								},
+								Region: &sarif.Region{StartLine: toPtr(1)},
+							},
+						},
+					},
+					Rank: toPtr[float32](73.5),
+				},
+			},
+		},
 		{
 			name:        "no vulns",
 			wantResults: []*sarif.Result{},
@@ -3,6 +3,7 @@ package spdx
 import (
 	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,6 +15,8 @@ import (
 	"k8s.io/utils/clock"
 
 	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/licensing"
+	"github.com/aquasecurity/trivy/pkg/sbom/evidence"
 	"github.com/aquasecurity/trivy/pkg/types"
 )
 
@@ -39,6 +42,9 @@ type options struct {
 	clock      clock.Clock
 	newUUID    newUUID
 	spdxFormat string
+
+	// detail adds the found-at paths and checksums of package files to packages
+	detail bool
 }
 
 type option func(*options)
@@ -57,6 +63,13 @@ func WithNewUUID(newUUID newUUID) option {
 	}
 }
 
+// WithDetail adds the found-at paths and checksums of package files to packages
+func WithDetail(detail bool) option {
+	return func(opts *options) {
+		opts.detail = detail
+	}
+}
+
 func NewWriter(output io.Writer, version string, spdxFormat string, opts ...option) Writer {
 	o := &options{
 		format:     spdx.Document2_1{},
@@ -97,6 +110,7 @@ func (cw Writer) Write(report types.Report) error {
 
 func (cw *Writer) convertToBom(r types.Report, version string) (*spdx.Document2_2, error) {
 	packages := make(map[spdx.ElementID]*spdx.Package2_2)
+	collector := evidence.NewCollector(r)
 
 	for _, result := range r.Results {
 		for _, pkg := range result.Packages {
@@ -104,6 +118,9 @@ func (cw *Writer) convertToBom(r types.Report, version string) (*spdx.Document2_
 			if err != nil {
 				return nil, xerrors.Errorf("failed to parse pkg: %w", err)
 			}
+			if cw.detail {
+				addEvidence(&spdxPackage, pkg, collector.Collect(result, pkg))
+			}
 			packages[spdxPackage.PackageSPDXIdentifier] = &spdxPackage
 		}
 	}
@@ -118,11 +135,20 @@ func (cw *Writer) convertToBom(r types.Report, version string) (*spdx.Document2_
 			CreatorOrganizations: []string{CreatorOrganization},
 			CreatorTools:         []string{CreatorTool},
 			Created:              cw.clock.Now().UTC().Format(time.RFC3339Nano),
+			DocumentComment:      documentComment(r.Metadata),
 		},
 		Packages: packages,
 	}, nil
 }
 
+// documentComment returns the labels attached to the scan, as SPDX 2.2 has no place for arbitrary metadata
+func documentComment(meta types.Metadata) string {
+	if len(meta.Labels) == 0 {
+		return ""
+	}
+	return "Labels: " + strings.Join(meta.LabelPairs(), ", ")
+}
+
 func pkgToSpdxPackage(t string, meta types.Metadata, pkg ftypes.Package) (spdx.Package2_2, error) {
 	var spdxPackage spdx.Package2_2
 	license := getLicense(pkg)
@@ -145,11 +171,30 @@ func pkgToSpdxPackage(t string, meta types.Metadata, pkg ftypes.Package) (spdx.P
 	return spdxPackage, nil
 }
 
+func addEvidence(spdxPackage *spdx.Package2_2, pkg ftypes.Package, e evidence.Evidence) {
+	spdxPackage.PackageFileName = pkg.FilePath
+	if e.FoundAt != "" {
+		spdxPackage.PackageSourceInfo = "package found in: " + e.FoundAt
+	}
+	if e.SHA256 != "" {
+		spdxPackage.PackageChecksums = map[spdx.ChecksumAlgorithm]spdx.Checksum{
+			spdx.SHA256: {
+				Algorithm: spdx.SHA256,
+				Value:     e.SHA256,
+			},
+		}
+	}
+}
+
 func getLicense(p ftypes.Package) string {
 	if p.License == "" {
 		return "NONE"
 	}
 
+	// Licenses which can't be resolved to SPDX identifiers are kept as they are
+	if expr := licensing.Normalize(p.License); expr.Valid() {
+		return expr.Expression
+	}
 	return p.License
 }
 
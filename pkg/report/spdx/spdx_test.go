@@ -24,6 +24,7 @@ func TestWriter_Write(t *testing.T) {
 	testCases := []struct {
 		name        string
 		inputReport types.Report
+		detail      bool
 		wantSBOM    *spdx.Document2_2
 	}{
 		{
@@ -129,8 +130,8 @@ func TestWriter_Write(t *testing.T) {
 						PackageSPDXIdentifier:     spdx.ElementID("a49b9e67b4e8bc6d"),
 						PackageName:               "binutils",
 						PackageVersion:            "2.30",
-						PackageLicenseConcluded:   "GPLv3+",
-						PackageLicenseDeclared:    "GPLv3+",
+						PackageLicenseConcluded:   "GPL-3.0-or-later",
+						PackageLicenseDeclared:    "GPL-3.0-or-later",
 						IsFilesAnalyzedTagPresent: true,
 					},
 				},
@@ -224,8 +225,8 @@ func TestWriter_Write(t *testing.T) {
 						PackageSPDXIdentifier:     spdx.ElementID("a3a5d111639875c5"),
 						PackageName:               "acl",
 						PackageVersion:            "2.2.53",
-						PackageLicenseConcluded:   "GPLv2+",
-						PackageLicenseDeclared:    "GPLv2+",
+						PackageLicenseConcluded:   "GPL-2.0-or-later",
+						PackageLicenseDeclared:    "GPL-2.0-or-later",
 						IsFilesAnalyzedTagPresent: true,
 					},
 					spdx.ElementID("216407676208fcb1"): {
@@ -345,6 +346,61 @@ func TestWriter_Write(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "happy path with detail",
+			inputReport: types.Report{
+				SchemaVersion: report.SchemaVersion,
+				ArtifactName:  "testdata/fs",
+				ArtifactType:  ftypes.ArtifactFilesystem,
+				Results: types.Results{
+					{
+						Target: "Java",
+						Class:  types.ClassLangPkg,
+						Type:   ftypes.Jar,
+						Packages: []ftypes.Package{
+							{
+								Name:     "org.springframework:spring-core",
+								Version:  "5.3.20",
+								License:  "Apache-2.0",
+								FilePath: "app/spring-core-5.3.20.jar",
+							},
+						},
+					},
+				},
+			},
+			detail: true,
+			wantSBOM: &spdx.Document2_2{
+				CreationInfo: &spdx.CreationInfo2_2{
+					SPDXVersion:                "SPDX-2.2",
+					DataLicense:                "CC0-1.0",
+					SPDXIdentifier:             "DOCUMENT",
+					DocumentName:               "testdata/fs",
+					DocumentNamespace:          "http://aquasecurity.github.io/trivy/filesystem/testdata/fs-3ff14136-e09f-4df9-80ea-000000000001",
+					CreatorOrganizations:       []string{"aquasecurity"},
+					CreatorTools:               []string{"trivy"},
+					Created:                    "2021-08-25T12:20:30.000000005Z",
+					ExternalDocumentReferences: map[string]spdx.ExternalDocumentRef2_2{},
+				},
+				Packages: map[spdx.ElementID]*spdx.Package2_2{
+					spdx.ElementID("8118856eb317f967"): {
+						PackageSPDXIdentifier: spdx.ElementID("8118856eb317f967"),
+						PackageName:           "org.springframework:spring-core",
+						PackageVersion:        "5.3.20",
+						PackageFileName:       "app/spring-core-5.3.20.jar",
+						PackageChecksums: map[spdx.ChecksumAlgorithm]spdx.Checksum{
+							spdx.SHA256: {
+								Algorithm: spdx.SHA256,
+								Value:     "3eef80104cad33786401dd10cdfb718ef9c4803372ac5eae0732843699d93742",
+							},
+						},
+						PackageSourceInfo:         "package found in: app/spring-core-5.3.20.jar",
+						PackageLicenseConcluded:   "Apache-2.0",
+						PackageLicenseDeclared:    "Apache-2.0",
+						IsFilesAnalyzedTagPresent: true,
+					},
+				},
+			},
+		},
 		{
 			name: "happy path empty",
 			inputReport: types.Report{
@@ -381,7 +437,8 @@ func TestWriter_Write(t *testing.T) {
 			}
 
 			output := bytes.NewBuffer(nil)
-			writer := reportSpdx.NewWriter(output, "dev", "spdx-json", reportSpdx.WithClock(clock), reportSpdx.WithNewUUID(newUUID))
+			writer := reportSpdx.NewWriter(output, "dev", "spdx-json", reportSpdx.WithClock(clock), reportSpdx.WithNewUUID(newUUID),
+				reportSpdx.WithDetail(tc.detail))
 
 			err := writer.Write(tc.inputReport)
 			require.NoError(t, err)
@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -14,10 +15,10 @@ import (
 	"github.com/xlab/treeprint"
 	"golang.org/x/exp/slices"
 
-	ftypes "github.com/aquasecurity/fanal/types"
 	"github.com/aquasecurity/table"
 	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
 	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/remediation"
 	"github.com/aquasecurity/trivy/pkg/types"
 )
 
@@ -45,20 +46,128 @@ type TableWriter struct {
 	// For misconfigurations
 	IncludeNonFailures bool
 	Trace              bool
+
+	// Group findings by the layer introducing them
+	Layers bool
 }
 
 // Write writes the result on standard output
 func (tw TableWriter) Write(report types.Report) error {
-	for _, result := range report.Results {
-		// Not display a table of custom resources
-		if result.Class == types.ClassCustom {
-			continue
+	tw.writeLabels(report.Metadata)
+	if tw.Layers {
+		tw.writeLayers(report)
+	} else {
+		for _, result := range report.Results {
+			// Not display a table of custom resources
+			if result.Class == types.ClassCustom {
+				continue
+			}
+			// Targets with errors are listed below
+			if result.Error != "" {
+				continue
+			}
+			tw.write(result)
 		}
-		tw.write(result)
 	}
+	tw.writeErrors(report.Results)
+	tw.writeSuppressions(report.Results)
+	tw.writeRecommendations(report.Recommendations)
 	return nil
 }
 
+// writeLabels writes the labels attached to the scan
+func (tw TableWriter) writeLabels(meta types.Metadata) {
+	if len(meta.Labels) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(tw.Output, "\nLabels: %s\n", strings.Join(meta.LabelPairs(), ", "))
+}
+
+// writeRecommendations writes the upgrades of the base image, where the first one is recommended
+func (tw TableWriter) writeRecommendations(recommendations []types.Recommendation) {
+	if len(recommendations) == 0 {
+		return
+	}
+
+	best := recommendations[0]
+	_, _ = fmt.Fprintf(tw.Output, "\nBase image recommendations\n%s\n", strings.Repeat("=", 26))
+	if len(best.Remediated) == 0 {
+		_, _ = fmt.Fprintf(tw.Output, "No upgrade of %s remediates OS vulnerabilities\n\n", best.BaseImage)
+	} else {
+		_, _ = fmt.Fprintf(tw.Output, "Recommended: %s (%d remediated, %d introduced)\n\n",
+			best.Candidate, len(best.Remediated), len(best.Introduced))
+	}
+
+	tableWriter := table.New(tw.Output)
+	if tw.isOutputToTerminal() {
+		tableWriter.SetHeaderStyle(table.StyleBold)
+		tableWriter.SetLineStyle(table.StyleDim)
+	}
+	tableWriter.SetBorders(true)
+	tableWriter.SetRowLines(true)
+	tableWriter.SetHeaders("Base Image", "Candidate", "Remediated", "Introduced")
+	tableWriter.SetAlignment(table.AlignLeft, table.AlignLeft, table.AlignCenter, table.AlignCenter)
+	for _, r := range recommendations {
+		tableWriter.AddRow(r.BaseImage, r.Candidate, fmt.Sprint(len(r.Remediated)), fmt.Sprint(len(r.Introduced)))
+	}
+	tableWriter.Render()
+}
+
+// writeErrors writes the targets which failed to be analyzed or detected with --continue-on-error
+func (tw TableWriter) writeErrors(results types.Results) {
+	errored := lo.Filter(results, func(r types.Result, _ int) bool {
+		return r.Error != ""
+	})
+	if len(errored) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintf(tw.Output, "\nErrors\n%s\nTotal: %d\n\n", strings.Repeat("=", 6), len(errored))
+
+	tableWriter := table.New(tw.Output)
+	if tw.isOutputToTerminal() {
+		tableWriter.SetHeaderStyle(table.StyleBold)
+		tableWriter.SetLineStyle(table.StyleDim)
+	}
+	tableWriter.SetBorders(true)
+	tableWriter.SetRowLines(true)
+	tableWriter.SetHeaders("Target", "Type", "Error")
+	tableWriter.SetAlignment(table.AlignLeft, table.AlignCenter, table.AlignLeft)
+	for _, r := range errored {
+		tableWriter.AddRow(r.Target, r.Type, r.Error)
+	}
+	tableWriter.Render()
+}
+
+// writeSuppressions writes the findings suppressed by inline ignore comments for auditing
+func (tw TableWriter) writeSuppressions(results types.Results) {
+	var total int
+	for _, result := range results {
+		total += len(result.Suppressions)
+	}
+	if total == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintf(tw.Output, "\nSuppressed findings\n%s\nTotal: %d\n\n", strings.Repeat("=", 19), total)
+
+	tableWriter := table.New(tw.Output)
+	if tw.isOutputToTerminal() {
+		tableWriter.SetHeaderStyle(table.StyleBold)
+		tableWriter.SetLineStyle(table.StyleDim)
+	}
+	tableWriter.SetBorders(true)
+	tableWriter.SetRowLines(true)
+	tableWriter.SetHeaders("Target", "Type", "ID", "Line No", "Reason", "Expires")
+	tableWriter.SetAlignment(table.AlignLeft, table.AlignCenter, table.AlignCenter, table.AlignCenter, table.AlignLeft, table.AlignCenter)
+	for _, result := range results {
+		for _, s := range result.Suppressions {
+			tableWriter.AddRow(result.Target, string(s.Type), s.ID, fmt.Sprint(s.StartLine), s.Reason, s.ExpiresAt)
+		}
+	}
+	tableWriter.Render()
+}
+
 func (tw TableWriter) isOutputToTerminal() bool {
 	if tw.Output != os.Stdout {
 		return false
@@ -87,6 +196,12 @@ func (tw TableWriter) write(result types.Result) {
 		tw.writeVulnerabilities(tableWriter, result.Vulnerabilities)
 	case len(result.Secrets) > 0:
 		tw.writeSecrets(tableWriter, result.Secrets)
+	case len(result.Licenses) > 0:
+		tw.writeLicenses(tableWriter, result.Class, result.Licenses)
+	case len(result.ProvenanceViolations) > 0:
+		tw.writeProvenanceViolations(tableWriter, result.ProvenanceViolations)
+	case len(result.SignatureViolations) > 0:
+		tw.writeSignatureViolations(tableWriter, result.SignatureViolations)
 	}
 
 	total, summaries := tw.summary(severityCount)
@@ -99,6 +214,15 @@ func (tw TableWriter) write(result types.Result) {
 			return
 		}
 		target += " (secrets)"
+	} else if result.Class == types.ClassLicense || result.Class == types.ClassLicenseFile {
+		if len(result.Licenses) == 0 {
+			return
+		}
+		target += " (license)"
+	} else if result.Class == types.ClassProvenance {
+		target += " (provenance)"
+	} else if result.Class == types.ClassSignature {
+		target += " (signature)"
 	} else if result.Class != types.ClassOSPkg {
 		target += fmt.Sprintf(" (%s)", result.Type)
 	}
@@ -110,15 +234,24 @@ func (tw TableWriter) write(result types.Result) {
 		fmt.Printf("\n%s\n", target)
 		fmt.Println(strings.Repeat("=", len(target)))
 	}
+	if len(result.Owners) > 0 {
+		fmt.Printf("Owners: %s\n", strings.Join(result.Owners, ", "))
+	}
 	if result.Class == types.ClassConfig {
 		// for misconfigurations
 		summary := result.MisconfSummary
 		fmt.Printf("Tests: %d (SUCCESSES: %d, FAILURES: %d, EXCEPTIONS: %d)\n",
 			summary.Successes+summary.Failures+summary.Exceptions, summary.Successes, summary.Failures, summary.Exceptions)
 		fmt.Printf("Failures: %d (%s)\n\n", total, strings.Join(summaries, ", "))
+	} else if result.Class == types.ClassProvenance || result.Class == types.ClassSignature {
+		fmt.Printf("Violations: %d (%s)\n\n", total, strings.Join(summaries, ", "))
 	} else {
-		// for vulnerabilities and secrets
-		fmt.Printf("Total: %d (%s)\n\n", total, strings.Join(summaries, ", "))
+		// for vulnerabilities, secrets and licenses
+		fmt.Printf("Total: %d (%s)\n", total, strings.Join(summaries, ", "))
+		if base, added := countIntroducedBy(result.Vulnerabilities); base+added > 0 {
+			fmt.Printf("Introduced by base image: %d, added layers: %d\n", base, added)
+		}
+		fmt.Println()
 	}
 
 	tableWriter.Render()
@@ -162,11 +295,39 @@ func (tw TableWriter) summary(severityCount map[string]int) (int, []string) {
 
 func (tw TableWriter) writeVulnerabilities(tableWriter *table.Table, vulns []types.DetectedVulnerability) {
 	header := []string{"Library", "Vulnerability", "Severity", "Installed Version", "Fixed Version", "Title"}
+	scored := hasRisk(vulns)
+	if scored {
+		header = slices.Insert(header, 3, "Risk")
+	}
 	tableWriter.SetHeaders(header...)
-	tw.setVulnerabilityRows(tableWriter, vulns)
+	tw.setVulnerabilityRows(tableWriter, vulns, scored)
+}
+
+// hasRisk returns whether the vulnerabilities are scored with --risk-score
+func hasRisk(vulns []types.DetectedVulnerability) bool {
+	return slices.IndexFunc(vulns, func(v types.DetectedVulnerability) bool { return v.Risk != nil }) >= 0
 }
 
-func (tw TableWriter) setVulnerabilityRows(tableWriter *table.Table, vulns []types.DetectedVulnerability) {
+// riskCell returns the risk score with the factors raising it, e.g. "99.3 (KEV, reachable)"
+func riskCell(r *types.Risk) string {
+	if r == nil {
+		return ""
+	}
+	var factors []string
+	if r.KEV {
+		factors = append(factors, "KEV")
+	}
+	if r.Reachable {
+		factors = append(factors, "reachable")
+	}
+	cell := strconv.FormatFloat(r.Score, 'f', 1, 64)
+	if len(factors) > 0 {
+		cell += fmt.Sprintf(" (%s)", strings.Join(factors, ", "))
+	}
+	return cell
+}
+
+func (tw TableWriter) setVulnerabilityRows(tableWriter *table.Table, vulns []types.DetectedVulnerability, scored bool) {
 	for _, v := range vulns {
 		lib := v.PkgName
 		if v.PkgPath != "" {
@@ -201,16 +362,24 @@ func (tw TableWriter) setVulnerabilityRows(tableWriter *table.Table, vulns []typ
 		} else {
 			row = []string{lib, v.VulnerabilityID, v.Severity, v.InstalledVersion, v.FixedVersion, strings.TrimSpace(title)}
 		}
+		if scored {
+			row = slices.Insert(row, 3, riskCell(v.Risk))
+		}
 
 		tableWriter.AddRow(row...)
 	}
 }
 func (tw TableWriter) renderDependencyTree(result types.Result) {
+	if len(result.Vulnerabilities) == 0 {
+		return
+	}
+
 	// Get parents of each dependency
-	parents := reverseDeps(result.Packages)
+	parents := remediation.ReverseDeps(result.Packages)
 	if len(parents) == 0 {
 		return
 	}
+	direct := remediation.DirectDeps(result.Packages, parents)
 
 	root := treeprint.NewWithRoot(fmt.Sprintf(`
 Dependency Origin Tree
@@ -245,40 +414,36 @@ Dependency Origin Tree
 
 		seen[vuln.PkgID] = struct{}{}
 		branch := root.AddBranch(topLvlID)
-		addParents(branch, vuln.PkgID, parents)
+		addParents(branch, vuln.PkgID, parents, direct, map[string]struct{}{vuln.PkgID: {}})
 
 	}
 	tw.Println(root.String())
 }
 
-func addParents(topItem treeprint.Tree, pkgID string, parentMap map[string][]string) {
+// addParents adds the paths from direct dependencies to the package.
+// Parents of direct dependencies are not shown as upgrading the direct dependency is what users need to do.
+func addParents(topItem treeprint.Tree, pkgID string, parentMap map[string][]string, direct map[string]struct{},
+	visited map[string]struct{}) {
 	parents, ok := parentMap[pkgID]
 	if !ok {
 		return
 	}
 	for _, parent := range parents {
-		branch := topItem.AddBranch(parent)
-		addParents(branch, parent, parentMap)
-	}
-}
+		// Dependency graphs may have cycles
+		if _, ok = visited[parent]; ok {
+			continue
+		}
 
-func reverseDeps(libs []ftypes.Package) map[string][]string {
-	reversed := make(map[string][]string)
-	for _, lib := range libs {
-		for _, dependOn := range lib.DependsOn {
-			items, ok := reversed[dependOn]
-			if !ok {
-				reversed[dependOn] = []string{lib.ID}
-			} else {
-				reversed[dependOn] = append(items, lib.ID)
-			}
+		if _, ok = direct[parent]; ok {
+			topItem.AddBranch(parent + " (direct)")
+			continue
 		}
-	}
 
-	for k, v := range reversed {
-		reversed[k] = lo.Uniq(v)
+		visited[parent] = struct{}{}
+		branch := topItem.AddBranch(parent)
+		addParents(branch, parent, parentMap, direct, visited)
+		delete(visited, parent)
 	}
-	return reversed
 }
 
 func (tw TableWriter) outputTrace(result types.Result) {
@@ -308,18 +473,36 @@ func (tw TableWriter) outputTrace(result types.Result) {
 	}
 }
 
-func (tw TableWriter) writeSecrets(tableWriter *table.Table, secrets []ftypes.SecretFinding) {
+func (tw TableWriter) writeSecrets(tableWriter *table.Table, secrets []types.DetectedSecret) {
 
 	alignment := []table.Alignment{table.AlignCenter, table.AlignCenter, table.AlignCenter,
 		table.AlignCenter, table.AlignLeft}
 	header := []string{"Category", "Description", "Severity", "Line No", "Match"}
 
+	// Show the verification column only when secrets were verified
+	verified := lo.ContainsBy(secrets, func(s types.DetectedSecret) bool {
+		return s.Verification != ""
+	})
+	if verified {
+		alignment = append(alignment, table.AlignCenter)
+		header = append(header, "Verification")
+	}
+
+	// Show the commit column only when the git history was scanned
+	history := lo.ContainsBy(secrets, func(s types.DetectedSecret) bool {
+		return s.History != nil
+	})
+	if history {
+		alignment = append(alignment, table.AlignCenter)
+		header = append(header, "Commit")
+	}
+
 	tableWriter.SetAlignment(alignment...)
 	tableWriter.SetHeaders(header...)
-	tw.setSecretRows(tableWriter, secrets)
+	tw.setSecretRows(tableWriter, secrets, verified, history)
 }
 
-func (tw TableWriter) setSecretRows(tableWriter *table.Table, secrets []ftypes.SecretFinding) {
+func (tw TableWriter) setSecretRows(tableWriter *table.Table, secrets []types.DetectedSecret, verified, history bool) {
 	for _, secret := range secrets {
 		severity := secret.Severity
 		if tw.isOutputToTerminal() {
@@ -328,11 +511,80 @@ func (tw TableWriter) setSecretRows(tableWriter *table.Table, secrets []ftypes.S
 		row := []string{string(secret.Category), secret.Title, severity,
 			fmt.Sprint(secret.StartLine), // multi-line is not supported for now.
 			secret.Match}
+		if verified {
+			row = append(row, string(secret.Verification))
+		}
+		if history {
+			row = append(row, secretCommit(secret.History))
+		}
 
 		tableWriter.AddRow(row...)
 	}
 }
 
+// secretCommit returns the short hash of the commit introducing the secret
+func secretCommit(h *types.SecretHistory) string {
+	if h == nil {
+		return ""
+	}
+	commit := h.Commit
+	if len(commit) > 7 {
+		commit = commit[:7]
+	}
+	if !h.ExistsAtHead {
+		commit += " (removed)"
+	}
+	return commit
+}
+
+func (tw TableWriter) writeLicenses(tableWriter *table.Table, class types.ResultClass, licenses []types.DetectedLicense) {
+	if class == types.ClassLicenseFile {
+		tableWriter.SetAlignment(table.AlignCenter, table.AlignCenter, table.AlignCenter, table.AlignLeft)
+		tableWriter.SetHeaders("Classification", "Severity", "License", "File Location")
+	} else {
+		tableWriter.SetAlignment(table.AlignCenter, table.AlignCenter, table.AlignCenter, table.AlignCenter)
+		tableWriter.SetHeaders("Package", "License", "Classification", "Severity")
+	}
+
+	for _, license := range licenses {
+		severity := license.Severity
+		if tw.isOutputToTerminal() {
+			severity = ColorizeSeverity(severity, severity)
+		}
+		if class == types.ClassLicenseFile {
+			tableWriter.AddRow(string(license.Category), severity, license.Name, license.FilePath)
+		} else {
+			tableWriter.AddRow(license.PkgName, license.Name, string(license.Category), severity)
+		}
+	}
+}
+
+func (tw TableWriter) writeProvenanceViolations(tableWriter *table.Table, violations []types.ProvenanceViolation) {
+	tableWriter.SetAlignment(table.AlignCenter, table.AlignCenter, table.AlignLeft)
+	tableWriter.SetHeaders("Rule", "Severity", "Message")
+
+	for _, v := range violations {
+		severity := v.Severity
+		if tw.isOutputToTerminal() {
+			severity = ColorizeSeverity(severity, severity)
+		}
+		tableWriter.AddRow(string(v.Rule), severity, v.Message)
+	}
+}
+
+func (tw TableWriter) writeSignatureViolations(tableWriter *table.Table, violations []types.SignatureViolation) {
+	tableWriter.SetAlignment(table.AlignCenter, table.AlignCenter, table.AlignCenter, table.AlignLeft)
+	tableWriter.SetHeaders("Status", "Format", "Severity", "Message")
+
+	for _, v := range violations {
+		severity := v.Severity
+		if tw.isOutputToTerminal() {
+			severity = ColorizeSeverity(severity, severity)
+		}
+		tableWriter.AddRow(string(v.Status), v.Format, severity, v.Message)
+	}
+}
+
 func (tw TableWriter) Println(a ...interface{}) {
 	_, _ = fmt.Fprintln(tw.Output, a...)
 }
@@ -351,9 +603,32 @@ func (tw TableWriter) countSeverities(result types.Result) map[string]int {
 	for _, v := range result.Vulnerabilities {
 		severityCount[v.Severity]++
 	}
+	for _, l := range result.Licenses {
+		severityCount[l.Severity]++
+	}
+	for _, v := range result.ProvenanceViolations {
+		severityCount[v.Severity]++
+	}
+	for _, v := range result.SignatureViolations {
+		severityCount[v.Severity]++
+	}
 	return severityCount
 }
 
+// countIntroducedBy returns the number of vulnerabilities in the base image and the layers added on it
+func countIntroducedBy(vulns []types.DetectedVulnerability) (int, int) {
+	var base, added int
+	for _, v := range vulns {
+		switch v.IntroducedBy {
+		case types.IntroducedByBaseImage:
+			base++
+		case types.IntroducedByAddedLayers:
+			added++
+		}
+	}
+	return base, added
+}
+
 func ColorizeSeverity(value, severity string) string {
 	for i, name := range dbTypes.SeverityNames {
 		if severity == name {
@@ -16,6 +16,7 @@ func TestReportWriter_Table(t *testing.T) {
 	testCases := []struct {
 		name               string
 		results            types.Results
+		recommendations    []types.Recommendation
 		expectedOutput     string
 		includeNonFailures bool
 	}{
@@ -102,6 +103,46 @@ func TestReportWriter_Table(t *testing.T) {
 ├─────────┼───────────────┼──────────┼───────────────────┼───────────────┼────────┤
 │ foo     │ CVE-2020-0001 │ HIGH     │ 1.2.3             │ 3.4.5         │ foobar │
 └─────────┴───────────────┴──────────┴───────────────────┴───────────────┴────────┘
+`,
+		},
+		{
+			name: "happy path with risk scores",
+			results: types.Results{
+				{
+					Target: "test",
+					Vulnerabilities: []types.DetectedVulnerability{
+						{
+							VulnerabilityID:  "CVE-2021-44228",
+							PkgName:          "log4j-core",
+							InstalledVersion: "2.14.1",
+							FixedVersion:     "2.15.0",
+							Risk:             &types.Risk{Score: 99.3, CVSS: 10, EPSS: 0.97565, KEV: true, Reachable: true},
+							Vulnerability: dbTypes.Vulnerability{
+								Title:    "log4j-core: Remote code execution",
+								Severity: "CRITICAL",
+							},
+						},
+						{
+							VulnerabilityID:  "CVE-2020-0001",
+							PkgName:          "foo",
+							InstalledVersion: "1.2.3",
+							FixedVersion:     "3.4.5",
+							Risk:             &types.Risk{Score: 40, CVSS: 7.5},
+							Vulnerability: dbTypes.Vulnerability{
+								Title:    "foobar",
+								Severity: "HIGH",
+							},
+						},
+					},
+				},
+			},
+			expectedOutput: `┌────────────┬────────────────┬──────────┬───────────────────────┬───────────────────┬───────────────┬───────────────────────────────────┐
+│  Library   │ Vulnerability  │ Severity │         Risk          │ Installed Version │ Fixed Version │               Title               │
+├────────────┼────────────────┼──────────┼───────────────────────┼───────────────────┼───────────────┼───────────────────────────────────┤
+│ log4j-core │ CVE-2021-44228 │ CRITICAL │ 99.3 (KEV, reachable) │ 2.14.1            │ 2.15.0        │ log4j-core: Remote code execution │
+├────────────┼────────────────┼──────────┼───────────────────────┼───────────────────┼───────────────┼───────────────────────────────────┤
+│ foo        │ CVE-2020-0001  │ HIGH     │ 40.0                  │ 1.2.3             │ 3.4.5         │ foobar                            │
+└────────────┴────────────────┴──────────┴───────────────────────┴───────────────────┴───────────────┴───────────────────────────────────┘
 `,
 		},
 		{
@@ -217,9 +258,227 @@ package-lock.json
 ├── node-fetch@1.7.3, (MEDIUM: 0, HIGH: 1)
 │   └── isomorphic-fetch@2.2.1
 │       └── fbjs@0.8.18
-│           └── styled-components@3.1.3
+│           └── styled-components@3.1.3 (direct)
 └── sanitize-html@1.20.0, (MEDIUM: 1, HIGH: 0)
 
+`,
+		},
+		{
+			name: "happy path with direct dependencies and cycles",
+			results: types.Results{
+				{
+					Target: "package-lock.json",
+					Class:  "lang-pkgs",
+					Type:   "npm",
+					Packages: []ftypes.Package{
+						{
+							ID:        "a@1.0.0",
+							Name:      "a",
+							Version:   "1.0.0",
+							DependsOn: []string{"b@1.0.0", "c@1.0.0"},
+						},
+						{
+							ID:        "b@1.0.0",
+							Name:      "b",
+							Version:   "1.0.0",
+							Indirect:  true,
+							DependsOn: []string{"c@1.0.0"},
+						},
+						{
+							ID:        "c@1.0.0",
+							Name:      "c",
+							Version:   "1.0.0",
+							Indirect:  true,
+							DependsOn: []string{"b@1.0.0"},
+						},
+						{
+							// Parents of direct dependencies are not shown
+							ID:        "z@1.0.0",
+							Name:      "z",
+							Version:   "1.0.0",
+							Indirect:  true,
+							DependsOn: []string{"a@1.0.0"},
+						},
+					},
+					Vulnerabilities: []types.DetectedVulnerability{
+						{
+							VulnerabilityID: "CVE-2022-0001",
+							PkgID:           "c@1.0.0",
+							PkgName:         "c",
+							Vulnerability: dbTypes.Vulnerability{
+								Title:    "foobar",
+								Severity: "HIGH",
+							},
+							InstalledVersion: "1.0.0",
+							FixedVersion:     "1.0.1",
+						},
+					},
+				},
+			},
+			expectedOutput: `┌─────────┬───────────────┬──────────┬───────────────────┬───────────────┬────────┐
+│ Library │ Vulnerability │ Severity │ Installed Version │ Fixed Version │ Title  │
+├─────────┼───────────────┼──────────┼───────────────────┼───────────────┼────────┤
+│ c       │ CVE-2022-0001 │ HIGH     │ 1.0.0             │ 1.0.1         │ foobar │
+└─────────┴───────────────┴──────────┴───────────────────┴───────────────┴────────┘
+
+Dependency Origin Tree
+======================
+package-lock.json
+└── c@1.0.0, (MEDIUM: 0, HIGH: 1)
+    ├── a@1.0.0 (direct)
+    └── b@1.0.0
+        └── a@1.0.0 (direct)
+
+`,
+		},
+		{
+			name: "happy path with licenses",
+			results: types.Results{
+				{
+					Target: "OS Packages",
+					Class:  types.ClassLicense,
+					Licenses: []types.DetectedLicense{
+						{
+							Severity:   "HIGH",
+							Category:   types.CategoryRestricted,
+							PkgName:    "bash",
+							Name:       "GPL-3.0",
+							Confidence: 1,
+						},
+					},
+				},
+			},
+			expectedOutput: `┌─────────┬─────────┬────────────────┬──────────┐
+│ Package │ License │ Classification │ Severity │
+├─────────┼─────────┼────────────────┼──────────┤
+│  bash   │ GPL-3.0 │   restricted   │   HIGH   │
+└─────────┴─────────┴────────────────┴──────────┘
+`,
+		},
+		{
+			name: "happy path with licenses of files",
+			results: types.Results{
+				{
+					Target: "Loose File License(s)",
+					Class:  types.ClassLicenseFile,
+					Licenses: []types.DetectedLicense{
+						{
+							Severity:   "MEDIUM",
+							Category:   types.CategoryReciprocal,
+							FilePath:   "src/main.c",
+							Name:       "MPL-2.0",
+							Confidence: 1,
+						},
+					},
+				},
+			},
+			expectedOutput: `┌────────────────┬──────────┬─────────┬───────────────┐
+│ Classification │ Severity │ License │ File Location │
+├────────────────┼──────────┼─────────┼───────────────┤
+│   reciprocal   │  MEDIUM  │ MPL-2.0 │ src/main.c    │
+└────────────────┴──────────┴─────────┴───────────────┘
+`,
+		},
+		{
+			name: "happy path with provenance violations",
+			results: types.Results{
+				{
+					Target: "acme/app:1.0",
+					Class:  types.ClassProvenance,
+					ProvenanceViolations: []types.ProvenanceViolation{
+						{
+							Rule:     types.RuleSource,
+							Severity: "HIGH",
+							Message:  "source github.com/acme/fork is not allowed",
+						},
+					},
+				},
+			},
+			expectedOutput: `┌────────┬──────────┬────────────────────────────────────────────┐
+│  Rule  │ Severity │                  Message                   │
+├────────┼──────────┼────────────────────────────────────────────┤
+│ source │   HIGH   │ source github.com/acme/fork is not allowed │
+└────────┴──────────┴────────────────────────────────────────────┘
+`,
+		},
+		{
+			name: "happy path with signature violations",
+			results: types.Results{
+				{
+					Target: "acme/app:1.0",
+					Class:  types.ClassSignature,
+					SignatureViolations: []types.SignatureViolation{
+						{
+							Status:   types.SignatureStatusInvalid,
+							Format:   "cosign",
+							Severity: "CRITICAL",
+							Message:  "the signature is not valid with the public key",
+						},
+					},
+				},
+			},
+			expectedOutput: `┌─────────┬────────┬──────────┬────────────────────────────────────────────────┐
+│ Status  │ Format │ Severity │                    Message                     │
+├─────────┼────────┼──────────┼────────────────────────────────────────────────┤
+│ invalid │ cosign │ CRITICAL │ the signature is not valid with the public key │
+└─────────┴────────┴──────────┴────────────────────────────────────────────────┘
+`,
+		},
+		{
+			name: "happy path with errors",
+			results: types.Results{
+				{
+					Target: "app/Gemfile.lock",
+					Class:  types.ClassLangPkg,
+					Type:   "bundler",
+					Error:  "failed to scan rubygems vulnerabilities",
+				},
+				{
+					Target: "app/broken.jar",
+					Error:  "zip: not a valid zip file",
+				},
+			},
+			expectedOutput: `
+Errors
+======
+Total: 2
+
+┌──────────────────┬─────────┬─────────────────────────────────────────┐
+│      Target      │  Type   │                  Error                  │
+├──────────────────┼─────────┼─────────────────────────────────────────┤
+│ app/Gemfile.lock │ bundler │ failed to scan rubygems vulnerabilities │
+├──────────────────┼─────────┼─────────────────────────────────────────┤
+│ app/broken.jar   │         │ zip: not a valid zip file               │
+└──────────────────┴─────────┴─────────────────────────────────────────┘
+`,
+		},
+		{
+			name: "happy path with base image recommendations",
+			recommendations: []types.Recommendation{
+				{
+					BaseImage:  "alpine:3.16",
+					Candidate:  "index.docker.io/library/alpine:3.17.1",
+					Remediated: []string{"CVE-2022-37434", "CVE-2022-43551"},
+					Introduced: []string{"CVE-2023-0286"},
+				},
+				{
+					BaseImage:  "alpine:3.16",
+					Candidate:  "index.docker.io/library/alpine:3.16.3",
+					Remediated: []string{"CVE-2022-37434"},
+				},
+			},
+			expectedOutput: `
+Base image recommendations
+==========================
+Recommended: index.docker.io/library/alpine:3.17.1 (2 remediated, 1 introduced)
+
+┌─────────────┬───────────────────────────────────────┬────────────┬────────────┐
+│ Base Image  │               Candidate               │ Remediated │ Introduced │
+├─────────────┼───────────────────────────────────────┼────────────┼────────────┤
+│ alpine:3.16 │ index.docker.io/library/alpine:3.17.1 │     2      │     1      │
+├─────────────┼───────────────────────────────────────┼────────────┼────────────┤
+│ alpine:3.16 │ index.docker.io/library/alpine:3.16.3 │     1      │     0      │
+└─────────────┴───────────────────────────────────────┴────────────┴────────────┘
 `,
 		},
 	}
@@ -227,7 +486,7 @@ package-lock.json
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			tableWritten := bytes.Buffer{}
-			err := report.Write(types.Report{Results: tc.results}, report.Option{
+			err := report.Write(types.Report{Results: tc.results, Recommendations: tc.recommendations}, report.Option{
 				Format:             "table",
 				Output:             &tableWritten,
 				Tree:               true,
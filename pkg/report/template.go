@@ -57,6 +57,10 @@ func NewTemplateWriter(output io.Writer, outputTemplate string) (*TemplateWriter
 	templateFuncMap["sourceID"] = func(input string) dbTypes.SourceID {
 		return dbTypes.SourceID(input)
 	}
+	// The labels of the report are bound when the report is written
+	templateFuncMap["labels"] = func() map[string]string {
+		return nil
+	}
 
 	// Overwrite functions
 	for k, v := range CustomTemplateFuncMap {
@@ -72,6 +76,12 @@ func NewTemplateWriter(output io.Writer, outputTemplate string) (*TemplateWriter
 
 // Write writes result
 func (tw TemplateWriter) Write(report types.Report) error {
+	// The template is executed with the results, so labels are given by the function, e.g. {{ (labels).team }}
+	tw.Template.Funcs(template.FuncMap{
+		"labels": func() map[string]string {
+			return report.Metadata.Labels
+		},
+	})
 	err := tw.Template.Execute(tw.Output, report.Results)
 	if err != nil {
 		return xerrors.Errorf("failed to write with template: %w", err)
@@ -18,6 +18,7 @@ func TestReportWriter_Template(t *testing.T) {
 	testCases := []struct {
 		name          string
 		detectedVulns []types.DetectedVulnerability
+		labels        map[string]string
 		template      string
 		expected      string
 	}{
@@ -160,6 +161,13 @@ func TestReportWriter_Template(t *testing.T) {
 			template:      `{{ lower (env "AWS_ACCOUNT_ID") }}`,
 			expected:      `123456789012`,
 		},
+		{
+			name:          "happy path with labels",
+			detectedVulns: []types.DetectedVulnerability{},
+			labels:        map[string]string{"team": "payments"},
+			template:      `{{ range . }}{{ .Target }}: {{ (labels).team }}{{ end }}`,
+			expected:      `foojunit: payments`,
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -168,6 +176,7 @@ func TestReportWriter_Template(t *testing.T) {
 			os.Setenv("AWS_ACCOUNT_ID", "123456789012")
 			got := bytes.Buffer{}
 			inputReport := types.Report{
+				Metadata: types.Metadata{Labels: tc.labels},
 				Results: types.Results{
 					{
 						Target:          "foojunit",
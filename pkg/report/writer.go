@@ -26,6 +26,11 @@ const (
 	FormatSPDX      = "spdx"
 	FormatSPDXJSON  = "spdx-json"
 	FormatGitHub    = "github"
+
+	FormatGitHubAnnotations = "github-annotations"
+
+	ReportAll    = "all"
+	ReportLayers = "layers"
 )
 
 type Option struct {
@@ -37,13 +42,23 @@ type Option struct {
 	Severities     []dbTypes.Severity
 	OutputTemplate string
 
+	// For SBOM formats
+	SbomDetail bool
+
 	// For misconfigurations
 	IncludeNonFailures bool
 	Trace              bool
+
+	// The view of the table format, e.g. all, layers
+	Report string
 }
 
 // Write writes the result to output, format as passed in argument
 func Write(report types.Report, option Option) error {
+	if option.Report == ReportLayers && option.Format != FormatTable {
+		log.Logger.Warnf("--report %s is ignored in the %s format", ReportLayers, option.Format)
+	}
+
 	var writer Writer
 	switch option.Format {
 	case FormatTable:
@@ -54,16 +69,19 @@ func Write(report types.Report, option Option) error {
 			ShowMessageOnce:    &sync.Once{},
 			IncludeNonFailures: option.IncludeNonFailures,
 			Trace:              option.Trace,
+			Layers:             option.Report == ReportLayers,
 		}
 	case FormatJSON:
 		writer = &JSONWriter{Output: option.Output}
 	case FormatGitHub:
 		writer = &github.Writer{Output: option.Output, Version: option.AppVersion}
+	case FormatGitHubAnnotations:
+		writer = GitHubAnnotationWriter{Output: option.Output}
 	case FormatCycloneDX:
 		// TODO: support xml format option with cyclonedx writer
-		writer = cyclonedx.NewWriter(option.Output, option.AppVersion)
+		writer = cyclonedx.NewWriter(option.Output, option.AppVersion, cyclonedx.WithDetail(option.SbomDetail))
 	case FormatSPDX, FormatSPDXJSON:
-		writer = spdx.NewWriter(option.Output, option.AppVersion, option.Format)
+		writer = spdx.NewWriter(option.Output, option.AppVersion, option.Format, spdx.WithDetail(option.SbomDetail))
 	case FormatTemplate:
 		// We keep `sarif.tpl` template working for backward compatibility for a while.
 		if strings.HasPrefix(option.OutputTemplate, "@") && strings.HasSuffix(option.OutputTemplate, "sarif.tpl") {
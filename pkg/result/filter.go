@@ -14,7 +14,6 @@ import (
 	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
-	ftypes "github.com/aquasecurity/fanal/types"
 	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
 	"github.com/aquasecurity/trivy/pkg/log"
 	"github.com/aquasecurity/trivy/pkg/types"
@@ -26,25 +25,26 @@ const (
 )
 
 // Filter filters out the vulnerabilities
-func Filter(ctx context.Context, vulns []types.DetectedVulnerability, misconfs []types.DetectedMisconfiguration, secrets []ftypes.SecretFinding,
-	severities []dbTypes.Severity, ignoreUnfixed, includeNonFailures bool, ignoreFile, policyFile string) (
-	[]types.DetectedVulnerability, *types.MisconfSummary, []types.DetectedMisconfiguration, []ftypes.SecretFinding, error) {
+func Filter(ctx context.Context, vulns []types.DetectedVulnerability, misconfs []types.DetectedMisconfiguration, secrets []types.DetectedSecret,
+	licenses []types.DetectedLicense, severities []dbTypes.Severity, ignoreUnfixed, includeNonFailures bool, ignoreFile, policyFile string) (
+	[]types.DetectedVulnerability, *types.MisconfSummary, []types.DetectedMisconfiguration, []types.DetectedSecret, []types.DetectedLicense, error) {
 	ignoredIDs := getIgnoredIDs(ignoreFile)
 
 	filteredVulns := filterVulnerabilities(vulns, severities, ignoreUnfixed, ignoredIDs)
 	misconfSummary, filteredMisconfs := filterMisconfigurations(misconfs, severities, includeNonFailures, ignoredIDs)
 	filteredSecrets := filterSecrets(secrets, severities)
+	filteredLicenses := filterLicenses(licenses, severities, ignoredIDs)
 
 	if policyFile != "" {
 		var err error
 		filteredVulns, filteredMisconfs, err = applyPolicy(ctx, filteredVulns, filteredMisconfs, policyFile)
 		if err != nil {
-			return nil, nil, nil, nil, xerrors.Errorf("failed to apply the policy: %w", err)
+			return nil, nil, nil, nil, nil, xerrors.Errorf("failed to apply the policy: %w", err)
 		}
 	}
 	sort.Sort(types.BySeverity(filteredVulns))
 
-	return filteredVulns, misconfSummary, filteredMisconfs, filteredSecrets, nil
+	return filteredVulns, misconfSummary, filteredMisconfs, filteredSecrets, filteredLicenses, nil
 }
 
 func filterVulnerabilities(vulns []types.DetectedVulnerability, severities []dbTypes.Severity,
@@ -111,8 +111,8 @@ func filterMisconfigurations(misconfs []types.DetectedMisconfiguration, severiti
 	return summary, filtered
 }
 
-func filterSecrets(secrets []ftypes.SecretFinding, severities []dbTypes.Severity) []ftypes.SecretFinding {
-	var filtered []ftypes.SecretFinding
+func filterSecrets(secrets []types.DetectedSecret, severities []dbTypes.Severity) []types.DetectedSecret {
+	var filtered []types.DetectedSecret
 	for _, secret := range secrets {
 		// Filter secrets by severity
 		for _, s := range severities {
@@ -125,6 +125,24 @@ func filterSecrets(secrets []ftypes.SecretFinding, severities []dbTypes.Severity
 	return filtered
 }
 
+// filterLicenses filters out licenses by severity and license names in the ignore file
+func filterLicenses(licenses []types.DetectedLicense, severities []dbTypes.Severity, ignoredIDs []string) []types.DetectedLicense {
+	var filtered []types.DetectedLicense
+	for _, license := range licenses {
+		if slices.Contains(ignoredIDs, license.Name) {
+			continue
+		}
+		// Filter licenses by severity
+		for _, s := range severities {
+			if s.String() == license.Severity {
+				filtered = append(filtered, license)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
 func summarize(status types.MisconfStatus, summary *types.MisconfSummary) {
 	switch status {
 	case types.StatusFailure:
@@ -17,7 +17,8 @@ func TestClient_Filter(t *testing.T) {
 	type args struct {
 		vulns         []types.DetectedVulnerability
 		misconfs      []types.DetectedMisconfiguration
-		secrets       []ftypes.SecretFinding
+		secrets       []types.DetectedSecret
+		licenses      []types.DetectedLicense
 		severities    []dbTypes.Severity
 		ignoreUnfixed bool
 		ignoreFile    string
@@ -29,7 +30,8 @@ func TestClient_Filter(t *testing.T) {
 		wantVulns          []types.DetectedVulnerability
 		wantMisconfSummary *types.MisconfSummary
 		wantMisconfs       []types.DetectedMisconfiguration
-		wantSecrets        []ftypes.SecretFinding
+		wantSecrets        []types.DetectedSecret
+		wantLicenses       []types.DetectedLicense
 	}{
 		{
 			name: "happy path",
@@ -99,22 +101,42 @@ func TestClient_Filter(t *testing.T) {
 						Status:   types.StatusPassed,
 					},
 				},
-				secrets: []ftypes.SecretFinding{
+				secrets: []types.DetectedSecret{
 					{
-						RuleID:    "generic-critical-rule",
-						Severity:  dbTypes.SeverityCritical.String(),
-						Title:     "Critical Secret should pass filter",
-						StartLine: 1,
-						EndLine:   2,
-						Match:     "*****",
+						SecretFinding: ftypes.SecretFinding{
+							RuleID:    "generic-critical-rule",
+							Severity:  dbTypes.SeverityCritical.String(),
+							Title:     "Critical Secret should pass filter",
+							StartLine: 1,
+							EndLine:   2,
+							Match:     "*****",
+						},
 					},
 					{
-						RuleID:    "generic-low-rule",
-						Severity:  dbTypes.SeverityLow.String(),
-						Title:     "Low Secret should be ignored",
-						StartLine: 3,
-						EndLine:   4,
-						Match:     "*****",
+						SecretFinding: ftypes.SecretFinding{
+							RuleID:    "generic-low-rule",
+							Severity:  dbTypes.SeverityLow.String(),
+							Title:     "Low Secret should be ignored",
+							StartLine: 3,
+							EndLine:   4,
+							Match:     "*****",
+						},
+					},
+				},
+				licenses: []types.DetectedLicense{
+					{
+						Severity:   dbTypes.SeverityHigh.String(),
+						Category:   types.CategoryRestricted,
+						PkgName:    "foo",
+						Name:       "GPL-3.0",
+						Confidence: 1,
+					},
+					{
+						Severity:   dbTypes.SeverityLow.String(),
+						Category:   types.CategoryNotice,
+						PkgName:    "bar",
+						Name:       "MIT",
+						Confidence: 1,
 					},
 				},
 				severities:    []dbTypes.Severity{dbTypes.SeverityCritical, dbTypes.SeverityHigh, dbTypes.SeverityUnknown},
@@ -173,14 +195,25 @@ func TestClient_Filter(t *testing.T) {
 					Status:   types.StatusFailure,
 				},
 			},
-			wantSecrets: []ftypes.SecretFinding{
+			wantSecrets: []types.DetectedSecret{
+				{
+					SecretFinding: ftypes.SecretFinding{
+						RuleID:    "generic-critical-rule",
+						Severity:  dbTypes.SeverityCritical.String(),
+						Title:     "Critical Secret should pass filter",
+						StartLine: 1,
+						EndLine:   2,
+						Match:     "*****",
+					},
+				},
+			},
+			wantLicenses: []types.DetectedLicense{
 				{
-					RuleID:    "generic-critical-rule",
-					Severity:  dbTypes.SeverityCritical.String(),
-					Title:     "Critical Secret should pass filter",
-					StartLine: 1,
-					EndLine:   2,
-					Match:     "*****",
+					Severity:   dbTypes.SeverityHigh.String(),
+					Category:   types.CategoryRestricted,
+					PkgName:    "foo",
+					Name:       "GPL-3.0",
+					Confidence: 1,
 				},
 			},
 		},
@@ -285,6 +318,23 @@ func TestClient_Filter(t *testing.T) {
 						Status:   types.StatusFailure,
 					},
 				},
+				licenses: []types.DetectedLicense{
+					{
+						// this license is ignored
+						Severity:   dbTypes.SeverityLow.String(),
+						Category:   types.CategoryNotice,
+						PkgName:    "foo",
+						Name:       "Apache-2.0",
+						Confidence: 1,
+					},
+					{
+						Severity:   dbTypes.SeverityLow.String(),
+						Category:   types.CategoryNotice,
+						PkgName:    "bar",
+						Name:       "MIT",
+						Confidence: 1,
+					},
+				},
 				severities:    []dbTypes.Severity{dbTypes.SeverityLow},
 				ignoreUnfixed: false,
 				ignoreFile:    "testdata/.trivyignore",
@@ -309,6 +359,15 @@ func TestClient_Filter(t *testing.T) {
 					},
 				},
 			},
+			wantLicenses: []types.DetectedLicense{
+				{
+					Severity:   dbTypes.SeverityLow.String(),
+					Category:   types.CategoryNotice,
+					PkgName:    "bar",
+					Name:       "MIT",
+					Confidence: 1,
+				},
+			},
 		},
 		{
 			name: "happy path with a policy file",
@@ -491,13 +550,14 @@ func TestClient_Filter(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotVulns, gotMisconfSummary, gotMisconfs, gotSecrets, err := result.Filter(context.Background(), tt.args.vulns, tt.args.misconfs, tt.args.secrets,
-				tt.args.severities, tt.args.ignoreUnfixed, false, tt.args.ignoreFile, tt.args.policyFile)
+			gotVulns, gotMisconfSummary, gotMisconfs, gotSecrets, gotLicenses, err := result.Filter(context.Background(), tt.args.vulns, tt.args.misconfs, tt.args.secrets,
+				tt.args.licenses, tt.args.severities, tt.args.ignoreUnfixed, false, tt.args.ignoreFile, tt.args.policyFile)
 			require.NoError(t, err)
 			assert.Equal(t, tt.wantVulns, gotVulns)
 			assert.Equal(t, tt.wantMisconfSummary, gotMisconfSummary)
 			assert.Equal(t, tt.wantMisconfs, gotMisconfs)
 			assert.Equal(t, tt.wantSecrets, gotSecrets)
+			assert.Equal(t, tt.wantLicenses, gotLicenses)
 		})
 	}
 }
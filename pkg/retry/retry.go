@@ -0,0 +1,210 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	"github.com/twitchtv/twirp"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+const (
+	// DefaultRetries is the default of --retries
+	DefaultRetries = 3
+
+	// DefaultRPCRetries is the number of retries of RPC calls unless --retries is specified,
+	// which is kept from before --retries was added
+	DefaultRPCRetries = 10
+
+	// DefaultBackoff is the default of --retry-backoff
+	DefaultBackoff = time.Second
+
+	maxBackoff = time.Minute
+)
+
+var (
+	mu             sync.RWMutex
+	retries        = DefaultRetries
+	rpcRetries     = DefaultRPCRetries
+	initialBackoff = DefaultBackoff
+)
+
+// SetPolicy sets the number of retries and the initial backoff of remote operations, i.e. --retries and --retry-backoff.
+// The backoff doubles on each retry with jitter, up to a minute.
+func SetPolicy(n int, backoff time.Duration) error {
+	if n < 0 {
+		return xerrors.Errorf("negative retries: %d", n)
+	}
+	if backoff <= 0 {
+		return xerrors.Errorf("non-positive backoff: %s", backoff)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	retries, initialBackoff = n, backoff
+	return nil
+}
+
+// SetRPCRetries sets the number of retries of RPC calls to the server
+func SetRPCRetries(n int) error {
+	if n < 0 {
+		return xerrors.Errorf("negative retries: %d", n)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	rpcRetries = n
+	return nil
+}
+
+func newBackOff(ctx context.Context, rpc bool) backoff.BackOff {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	retries := retries
+	if rpc {
+		retries = rpcRetries
+	}
+
+	b := backoff.NewExponentialBackOff()
+	b.InitialInterval = initialBackoff
+	b.MaxInterval = maxBackoff
+	b.MaxElapsedTime = 0 // limited by the number of retries
+	b.Reset()
+	return backoff.WithContext(backoff.WithMaxRetries(b, uint64(retries)), ctx)
+}
+
+// Do runs f, and runs it again with the backoff while it fails with a transient error.
+// Each retry is logged as a warning, and the last error is returned once the retries are exhausted.
+func Do(ctx context.Context, operation string, f func() error) error {
+	return do(ctx, operation, false, f)
+}
+
+// DoRPC is the same as Do, except that it retries with the number of retries of RPC calls
+func DoRPC(ctx context.Context, operation string, f func() error) error {
+	return do(ctx, operation, true, f)
+}
+
+func do(ctx context.Context, operation string, rpc bool, f func() error) error {
+	attempt := 0
+	return backoff.RetryNotify(func() error {
+		err := f()
+		if err != nil && !IsTransient(err) {
+			return backoff.Permanent(err)
+		}
+		return err
+	}, newBackOff(ctx, rpc), func(err error, d time.Duration) {
+		attempt++
+		log.Logger.Warnf("%s failed with a transient error, retrying in %s (%d): %s",
+			operation, d.Round(time.Millisecond), attempt, err)
+	})
+}
+
+// IsTransient returns whether the error may be resolved by retrying,
+// e.g. timeouts, connection resets, 429 Too Many Requests, 5xx from registries and unavailable RPC servers.
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var twerr twirp.Error
+	if errors.As(err, &twerr) && twerr.Code() == twirp.Unavailable {
+		return true
+	}
+
+	var se statusError
+	if errors.As(err, &se) {
+		return true
+	}
+
+	// e.g. *transport.Error of go-containerregistry
+	var temporary interface{ Temporary() bool }
+	if errors.As(err, &temporary) && temporary.Temporary() {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE)
+}
+
+// statusError represents a transient HTTP status
+type statusError struct {
+	status string
+}
+
+func (e statusError) Error() string {
+	return e.status
+}
+
+func transientStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+type transport struct {
+	base http.RoundTripper
+}
+
+// Transport returns a transport retrying requests on transient errors and statuses with the policy.
+// Requests with a body that can't be read again are sent only once.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	return transport{base: base}
+}
+
+func (t transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	var resp *http.Response
+	attempt := 0
+	err := Do(req.Context(), req.Method+" "+req.URL.Redacted(), func() error {
+		r := req
+		if attempt++; attempt > 1 {
+			if resp != nil {
+				_, _ = io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+				resp = nil
+			}
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return xerrors.Errorf("unable to read the request body again: %w", err)
+				}
+				r = req.Clone(req.Context())
+				r.Body = body
+			}
+		}
+
+		var err error
+		if resp, err = t.base.RoundTrip(r); err != nil {
+			return err
+		} else if transientStatus(resp.StatusCode) {
+			return statusError{status: resp.Status}
+		}
+		return nil
+	})
+
+	// The last response is returned as is, so that the caller handles the status
+	if resp != nil {
+		return resp, nil
+	}
+	return nil, err
+}
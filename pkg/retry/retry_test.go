@@ -0,0 +1,184 @@
+package retry_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twitchtv/twirp"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/retry"
+)
+
+func TestDo(t *testing.T) {
+	require.NoError(t, retry.SetPolicy(2, time.Millisecond))
+	defer retry.SetPolicy(retry.DefaultRetries, retry.DefaultBackoff)
+
+	tests := []struct {
+		name      string
+		errs      []error
+		wantCalls int
+		wantErr   string
+	}{
+		{
+			name:      "success",
+			errs:      []error{nil},
+			wantCalls: 1,
+		},
+		{
+			name:      "connection reset",
+			errs:      []error{xerrors.Errorf("read: %w", syscall.ECONNRESET), nil},
+			wantCalls: 2,
+		},
+		{
+			name: "unavailable server",
+			errs: []error{
+				twirp.NewError(twirp.Unavailable, "unavailable"),
+				twirp.InternalErrorWith(syscall.ECONNREFUSED),
+				nil,
+			},
+			wantCalls: 3,
+		},
+		{
+			name: "retries exhausted",
+			errs: []error{
+				syscall.ECONNREFUSED,
+				syscall.ECONNREFUSED,
+				xerrors.Errorf("last: %w", syscall.ECONNREFUSED),
+			},
+			wantCalls: 3,
+			wantErr:   "last",
+		},
+		{
+			name:      "permanent error",
+			errs:      []error{xerrors.New("invalid image name")},
+			wantCalls: 1,
+			wantErr:   "invalid image name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := retry.Do(context.Background(), "test", func() error {
+				err := tt.errs[calls]
+				calls++
+				return err
+			})
+			assert.Equal(t, tt.wantCalls, calls)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestDoRPC(t *testing.T) {
+	// RPC calls are retried with their own number of retries
+	require.NoError(t, retry.SetPolicy(0, time.Millisecond))
+	require.NoError(t, retry.SetRPCRetries(4))
+	defer func() {
+		_ = retry.SetPolicy(retry.DefaultRetries, retry.DefaultBackoff)
+		_ = retry.SetRPCRetries(retry.DefaultRPCRetries)
+	}()
+
+	calls := 0
+	err := retry.DoRPC(context.Background(), "test", func() error {
+		calls++
+		return twirp.NewError(twirp.Unavailable, "unavailable")
+	})
+	require.Error(t, err)
+	assert.Equal(t, 5, calls)
+}
+
+func TestSetPolicy(t *testing.T) {
+	assert.Error(t, retry.SetPolicy(-1, time.Second))
+	assert.Error(t, retry.SetPolicy(1, 0))
+	assert.Error(t, retry.SetRPCRetries(-1))
+}
+
+func TestTransport(t *testing.T) {
+	require.NoError(t, retry.SetPolicy(2, time.Millisecond))
+	defer retry.SetPolicy(retry.DefaultRetries, retry.DefaultBackoff)
+
+	tests := []struct {
+		name       string
+		statuses   []int
+		body       io.Reader
+		wantStatus int
+		wantCalls  int
+	}{
+		{
+			name:       "too many requests",
+			statuses:   []int{http.StatusTooManyRequests, http.StatusOK},
+			wantStatus: http.StatusOK,
+			wantCalls:  2,
+		},
+		{
+			name:       "replayable body",
+			statuses:   []int{http.StatusBadGateway, http.StatusOK},
+			body:       strings.NewReader("body"),
+			wantStatus: http.StatusOK,
+			wantCalls:  2,
+		},
+		{
+			name:       "non-replayable body",
+			statuses:   []int{http.StatusServiceUnavailable},
+			body:       io.MultiReader(strings.NewReader("body")),
+			wantStatus: http.StatusServiceUnavailable,
+			wantCalls:  1,
+		},
+		{
+			name:       "retries exhausted",
+			statuses:   []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable},
+			wantStatus: http.StatusServiceUnavailable,
+			wantCalls:  3,
+		},
+		{
+			name:       "not found",
+			statuses:   []int{http.StatusNotFound},
+			wantStatus: http.StatusNotFound,
+			wantCalls:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.body != nil {
+					b, err := io.ReadAll(r.Body)
+					require.NoError(t, err)
+					assert.Equal(t, "body", string(b))
+				}
+				w.WriteHeader(tt.statuses[calls])
+				calls++
+			}))
+			defer ts.Close()
+
+			method := http.MethodGet
+			if tt.body != nil {
+				method = http.MethodPost
+			}
+			req, err := http.NewRequest(method, ts.URL, tt.body)
+			require.NoError(t, err)
+
+			client := http.Client{Transport: retry.Transport(http.DefaultTransport)}
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.Equal(t, tt.wantCalls, calls)
+		})
+	}
+}
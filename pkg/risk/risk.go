@@ -0,0 +1,307 @@
+package risk
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// DefaultWeights are the weights of the factors unless --risk-weights is specified
+var DefaultWeights = Weights{CVSS: 0.4, EPSS: 0.3, KEV: 0.2, Reachability: 0.1}
+
+// Weights are the relative weights of the factors in the risk score
+type Weights struct {
+	CVSS         float64
+	EPSS         float64
+	KEV          float64
+	Reachability float64
+}
+
+// ParseWeights parses weights in the form of "cvss=0.5,epss=0.3,kev=0.2", where unspecified factors have no weight
+func ParseWeights(s string) (Weights, error) {
+	var w Weights
+	for _, kv := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(kv), "=")
+		if !ok {
+			return Weights{}, xerrors.Errorf("invalid weight (%s), specify it as FACTOR=WEIGHT", kv)
+		}
+		weight, err := strconv.ParseFloat(value, 64)
+		if err != nil || weight < 0 {
+			return Weights{}, xerrors.Errorf("invalid weight of %s (%s), specify a non-negative number", key, value)
+		}
+
+		switch strings.ToLower(key) {
+		case "cvss":
+			w.CVSS = weight
+		case "epss":
+			w.EPSS = weight
+		case "kev":
+			w.KEV = weight
+		case "reachability":
+			w.Reachability = weight
+		default:
+			return Weights{}, xerrors.Errorf("unknown risk factor: %s (cvss, epss, kev or reachability)", key)
+		}
+	}
+	if w.CVSS+w.EPSS+w.KEV+w.Reachability == 0 {
+		return Weights{}, xerrors.New("at least one risk factor must have a positive weight")
+	}
+	return w, nil
+}
+
+// Model computes risk scores with the weights and the data of the factors.
+// The factors without data, i.e. nil maps, are excluded so that they don't lower all the scores.
+type Model struct {
+	Weights Weights
+
+	// EPSS is the probability of exploitation in the next 30 days by CVE ID
+	EPSS map[string]float64
+
+	// KEV is the set of CVE IDs in the Known Exploited Vulnerabilities catalog
+	KEV map[string]struct{}
+
+	// Reachable is the set of reachable findings as "VULN-ID" or "VULN-ID PKG-NAME"
+	Reachable map[string]struct{}
+}
+
+// Load returns the model with the data files, which are optional
+func Load(weights Weights, epssFile, kevFile, reachabilityFile string) (Model, error) {
+	m := Model{Weights: weights}
+
+	var err error
+	if epssFile != "" {
+		if m.EPSS, err = loadEPSS(epssFile); err != nil {
+			return Model{}, xerrors.Errorf("EPSS error: %w", err)
+		}
+	}
+	if kevFile != "" {
+		if m.KEV, err = loadKEV(kevFile); err != nil {
+			return Model{}, xerrors.Errorf("KEV error: %w", err)
+		}
+	}
+	if reachabilityFile != "" {
+		if m.Reachable, err = loadReachable(reachabilityFile); err != nil {
+			return Model{}, xerrors.Errorf("reachability error: %w", err)
+		}
+	}
+	return m, nil
+}
+
+// Apply sets the risk scores of the vulnerabilities, and sorts them in descending order of the scores.
+// Vulnerabilities with the same score are left in the original order.
+func (m Model) Apply(result *types.Result) {
+	for i := range result.Vulnerabilities {
+		result.Vulnerabilities[i].Risk = m.Score(result.Vulnerabilities[i])
+	}
+	sort.SliceStable(result.Vulnerabilities, func(i, j int) bool {
+		return result.Vulnerabilities[i].Risk.Score > result.Vulnerabilities[j].Risk.Score
+	})
+}
+
+// Score returns the weighted average of the factors scaled from 0 to 100, rounded to one decimal place
+func (m Model) Score(vuln types.DetectedVulnerability) *types.Risk {
+	r := &types.Risk{CVSS: cvssScore(vuln)}
+	total := m.Weights.CVSS * r.CVSS / 10
+	weights := m.Weights.CVSS
+
+	if m.EPSS != nil {
+		r.EPSS = m.EPSS[vuln.VulnerabilityID]
+		total += m.Weights.EPSS * r.EPSS
+		weights += m.Weights.EPSS
+	}
+	if m.KEV != nil {
+		_, r.KEV = m.KEV[vuln.VulnerabilityID]
+		total += m.Weights.KEV * boolFactor(r.KEV)
+		weights += m.Weights.KEV
+	}
+	if m.Reachable != nil {
+		_, byID := m.Reachable[vuln.VulnerabilityID]
+		_, byPkg := m.Reachable[vuln.VulnerabilityID+" "+vuln.PkgName]
+		r.Reachable = byID || byPkg
+		total += m.Weights.Reachability * boolFactor(r.Reachable)
+		weights += m.Weights.Reachability
+	}
+
+	if weights > 0 {
+		r.Score = math.Round(total/weights*1000) / 10
+	}
+	return r
+}
+
+func boolFactor(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// severityScores are the lowest CVSS v3 scores of the severities,
+// used when no CVSS score is available for the vulnerability
+var severityScores = map[string]float64{
+	dbTypes.SeverityCritical.String(): 9.0,
+	dbTypes.SeverityHigh.String():     7.0,
+	dbTypes.SeverityMedium.String():   4.0,
+	dbTypes.SeverityLow.String():      0.1,
+}
+
+// cvssScore returns the CVSS score of the severity source, NVD or the highest one of the vendors in order,
+// where v3 takes precedence over v2
+func cvssScore(vuln types.DetectedVulnerability) float64 {
+	for _, source := range []dbTypes.SourceID{vuln.SeveritySource, vulnerability.NVD} {
+		if score := vendorScore(vuln.CVSS[source]); score > 0 {
+			return score
+		}
+	}
+
+	var highest float64
+	for _, cvss := range vuln.CVSS {
+		highest = math.Max(highest, vendorScore(cvss))
+	}
+	if highest > 0 {
+		return highest
+	}
+	return severityScores[vuln.Severity]
+}
+
+func vendorScore(cvss dbTypes.CVSS) float64 {
+	if cvss.V3Score > 0 {
+		return cvss.V3Score
+	}
+	return cvss.V2Score
+}
+
+// loadEPSS reads the CSV of EPSS scores published by FIRST, e.g. epss_scores-current.csv.gz.
+// The file may be gzipped and start with a comment line of the model version.
+func loadEPSS(filePath string) (map[string]float64, error) {
+	content, err := readFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Skip "#model_version:v2022.01.01,score_date:..."
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	var lines []string
+	for scanner.Scan() {
+		if line := scanner.Text(); !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, xerrors.Errorf("unable to read %s: %w", filePath, err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(strings.Join(lines, "\n"))).ReadAll()
+	if err != nil {
+		return nil, xerrors.Errorf("unable to parse %s: %w", filePath, err)
+	} else if len(records) == 0 {
+		return nil, xerrors.Errorf("%s is empty", filePath)
+	}
+
+	cveCol, epssCol := -1, -1
+	for i, name := range records[0] {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "cve":
+			cveCol = i
+		case "epss":
+			epssCol = i
+		}
+	}
+	if cveCol < 0 || epssCol < 0 {
+		return nil, xerrors.Errorf("%s must have the \"cve\" and \"epss\" columns", filePath)
+	}
+
+	scores := map[string]float64{}
+	for _, record := range records[1:] {
+		score, err := strconv.ParseFloat(record[epssCol], 64)
+		if err != nil || score < 0 || score > 1 {
+			return nil, xerrors.Errorf("%s: invalid EPSS score of %s: %s", filePath, record[cveCol], record[epssCol])
+		}
+		scores[record[cveCol]] = score
+	}
+	return scores, nil
+}
+
+// catalog is the Known Exploited Vulnerabilities catalog published by CISA in JSON
+type catalog struct {
+	Vulnerabilities []struct {
+		CveID string `json:"cveID"`
+	} `json:"vulnerabilities"`
+}
+
+func loadKEV(filePath string) (map[string]struct{}, error) {
+	content, err := readFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var c catalog
+	if err = json.Unmarshal(content, &c); err != nil {
+		return nil, xerrors.Errorf("unable to parse %s: %w", filePath, err)
+	}
+
+	kev := map[string]struct{}{}
+	for _, v := range c.Vulnerabilities {
+		kev[v.CveID] = struct{}{}
+	}
+	return kev, nil
+}
+
+// loadReachable reads reachable findings, one per line as "VULN-ID" or "VULN-ID PKG-NAME",
+// e.g. the vulnerabilities whose vulnerable functions are called according to a reachability analysis tool.
+// Lines starting with "#" are comments.
+func loadReachable(filePath string) (map[string]struct{}, error) {
+	content, err := readFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	reachable := map[string]struct{}{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		reachable[strings.Join(fields, " ")] = struct{}{}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, xerrors.Errorf("unable to read %s: %w", filePath, err)
+	}
+	return reachable, nil
+}
+
+// readFile reads the file, decompressing it if gzipped
+func readFile(filePath string) ([]byte, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to read %s: %w", filePath, err)
+	}
+	if !bytes.HasPrefix(content, []byte{0x1f, 0x8b}) {
+		return content, nil
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, xerrors.Errorf("unable to decompress %s: %w", filePath, err)
+	}
+	defer r.Close()
+
+	if content, err = io.ReadAll(r); err != nil {
+		return nil, xerrors.Errorf("unable to decompress %s: %w", filePath, err)
+	}
+	return content, nil
+}
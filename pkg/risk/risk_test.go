@@ -0,0 +1,238 @@
+package risk_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
+	"github.com/aquasecurity/trivy/pkg/risk"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestParseWeights(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    risk.Weights
+		wantErr string
+	}{
+		{
+			name:  "all factors",
+			input: "cvss=0.4,epss=0.3,kev=0.2,reachability=0.1",
+			want:  risk.DefaultWeights,
+		},
+		{
+			name:  "unspecified factors",
+			input: "CVSS=1, kev=2",
+			want:  risk.Weights{CVSS: 1, KEV: 2},
+		},
+		{
+			name:    "unknown factor",
+			input:   "cvss=1,exploitability=1",
+			wantErr: "unknown risk factor: exploitability",
+		},
+		{
+			name:    "negative weight",
+			input:   "cvss=-1",
+			wantErr: "invalid weight of cvss (-1)",
+		},
+		{
+			name:    "no weight",
+			input:   "cvss",
+			wantErr: "invalid weight (cvss)",
+		},
+		{
+			name:    "all zero",
+			input:   "cvss=0,epss=0",
+			wantErr: "at least one risk factor must have a positive weight",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := risk.ParseWeights(tt.input)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name             string
+		epssFile         string
+		kevFile          string
+		reachabilityFile string
+		want             risk.Model
+		wantErr          string
+	}{
+		{
+			name:             "happy path",
+			epssFile:         "testdata/epss.csv",
+			kevFile:          "testdata/kev.json",
+			reachabilityFile: "testdata/reachable.txt",
+			want: risk.Model{
+				Weights: risk.DefaultWeights,
+				EPSS: map[string]float64{
+					"CVE-2021-44228": 0.97565,
+					"CVE-2021-44906": 0.00720,
+					"CVE-2022-0235":  0.00171,
+				},
+				KEV: map[string]struct{}{
+					"CVE-2021-44228": {},
+				},
+				Reachable: map[string]struct{}{
+					"CVE-2021-44228":           {},
+					"CVE-2022-0235 node-fetch": {},
+				},
+			},
+		},
+		{
+			name:     "gzipped EPSS",
+			epssFile: "testdata/epss.csv.gz",
+			want: risk.Model{
+				Weights: risk.DefaultWeights,
+				EPSS: map[string]float64{
+					"CVE-2021-44228": 0.97565,
+					"CVE-2021-44906": 0.00720,
+					"CVE-2022-0235":  0.00171,
+				},
+			},
+		},
+		{
+			name: "no data",
+			want: risk.Model{Weights: risk.DefaultWeights},
+		},
+		{
+			name:     "invalid EPSS",
+			epssFile: "testdata/invalid-epss.csv",
+			wantErr:  "invalid EPSS score of CVE-2021-44228: high",
+		},
+		{
+			name:    "invalid KEV",
+			kevFile: "testdata/epss.csv",
+			wantErr: "KEV error",
+		},
+		{
+			name:             "missing file",
+			reachabilityFile: "testdata/missing.txt",
+			wantErr:          "reachability error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := risk.Load(risk.DefaultWeights, tt.epssFile, tt.kevFile, tt.reachabilityFile)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestModel_Apply(t *testing.T) {
+	vulns := []types.DetectedVulnerability{
+		{
+			VulnerabilityID: "CVE-2021-44906",
+			PkgName:         "minimist",
+			SeveritySource:  vulnerability.GHSA,
+			Vulnerability: dbTypes.Vulnerability{
+				Severity: "CRITICAL",
+				CVSS: dbTypes.VendorCVSS{
+					vulnerability.GHSA: {V3Score: 9.8},
+					vulnerability.NVD:  {V3Score: 5.6},
+				},
+			},
+		},
+		{
+			VulnerabilityID: "CVE-2022-0235",
+			PkgName:         "node-fetch",
+			Vulnerability: dbTypes.Vulnerability{
+				Severity: "HIGH",
+				CVSS: dbTypes.VendorCVSS{
+					vulnerability.NVD: {V2Score: 5.8, V3Score: 8.8},
+				},
+			},
+		},
+		{
+			VulnerabilityID: "CVE-2021-44228",
+			PkgName:         "org.apache.logging.log4j:log4j-core",
+			Vulnerability: dbTypes.Vulnerability{
+				Severity: "CRITICAL",
+				CVSS: dbTypes.VendorCVSS{
+					vulnerability.RedHat: {V3Score: 9.8},
+					vulnerability.GHSA:   {V3Score: 10.0},
+				},
+			},
+		},
+		{
+			VulnerabilityID: "CVE-2022-0001",
+			PkgName:         "node-fetch",
+			Vulnerability: dbTypes.Vulnerability{
+				Severity: "MEDIUM",
+			},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		model risk.Model
+		want  []types.DetectedVulnerability
+	}{
+		{
+			name: "all factors",
+			model: risk.Model{
+				Weights: risk.DefaultWeights,
+				EPSS: map[string]float64{
+					"CVE-2021-44228": 0.97565,
+					"CVE-2021-44906": 0.00720,
+					"CVE-2022-0235":  0.00171,
+				},
+				KEV: map[string]struct{}{"CVE-2021-44228": {}},
+				Reachable: map[string]struct{}{
+					"CVE-2021-44228":           {},
+					"CVE-2022-0235 node-fetch": {},
+					"CVE-2022-0001 minimist":   {},
+				},
+			},
+			want: []types.DetectedVulnerability{
+				withRisk(vulns[2], types.Risk{Score: 99.3, CVSS: 10.0, EPSS: 0.97565, KEV: true, Reachable: true}),
+				withRisk(vulns[1], types.Risk{Score: 45.3, CVSS: 8.8, EPSS: 0.00171, Reachable: true}),
+				withRisk(vulns[0], types.Risk{Score: 39.4, CVSS: 9.8, EPSS: 0.00720}),
+				withRisk(vulns[3], types.Risk{Score: 16, CVSS: 4.0}),
+			},
+		},
+		{
+			name:  "CVSS only",
+			model: risk.Model{Weights: risk.DefaultWeights},
+			want: []types.DetectedVulnerability{
+				withRisk(vulns[2], types.Risk{Score: 100, CVSS: 10.0}),
+				withRisk(vulns[0], types.Risk{Score: 98, CVSS: 9.8}),
+				withRisk(vulns[1], types.Risk{Score: 88, CVSS: 8.8}),
+				withRisk(vulns[3], types.Risk{Score: 40, CVSS: 4.0}),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := types.Result{
+				Vulnerabilities: append([]types.DetectedVulnerability{}, vulns...),
+			}
+			tt.model.Apply(&result)
+			assert.Equal(t, tt.want, result.Vulnerabilities)
+		})
+	}
+}
+
+func withRisk(vuln types.DetectedVulnerability, r types.Risk) types.DetectedVulnerability {
+	vuln.Risk = &r
+	return vuln
+}
@@ -2,12 +2,12 @@ package client
 
 import (
 	"context"
-	"crypto/tls"
 	"net/http"
 
 	"golang.org/x/xerrors"
 
 	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/httpclient"
 	r "github.com/aquasecurity/trivy/pkg/rpc"
 	"github.com/aquasecurity/trivy/pkg/types"
 	rpc "github.com/aquasecurity/trivy/rpc/scanner"
@@ -42,12 +42,7 @@ type Scanner struct {
 // NewScanner is the factory method to return RPC Scanner
 func NewScanner(scannerOptions ScannerOption, opts ...Option) Scanner {
 	httpClient := &http.Client{
-		Transport: &http.Transport{
-			Proxy: http.ProxyFromEnvironment,
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: scannerOptions.Insecure,
-			},
-		},
+		Transport: httpclient.Transport(scannerOptions.Insecure),
 	}
 
 	c := rpc.NewScannerProtobufClient(scannerOptions.RemoteURL, httpClient)
@@ -65,7 +60,7 @@ func (s Scanner) Scan(ctx context.Context, target, artifactKey string, blobKeys
 	ctx = WithCustomHeaders(ctx, s.customHeaders)
 
 	var res *rpc.ScanResponse
-	err := r.Retry(func() error {
+	err := r.Retry(ctx, func() error {
 		var err error
 		res, err = s.client.Scan(ctx, &rpc.ScanRequest{
 			Target:     target,
@@ -75,6 +70,7 @@ func (s Scanner) Scan(ctx context.Context, target, artifactKey string, blobKeys
 				VulnType:        options.VulnType,
 				SecurityChecks:  options.SecurityChecks,
 				ListAllPackages: options.ListAllPackages,
+				IncludeDevDeps:  options.IncludeDevDeps,
 			},
 		})
 		return err
@@ -1,42 +1,12 @@
 package rpc
 
 import (
-	"time"
+	"context"
 
-	"github.com/cenkalti/backoff"
-	"github.com/twitchtv/twirp"
-
-	"github.com/aquasecurity/trivy/pkg/log"
-)
-
-const (
-	maxRetries = 10
+	"github.com/aquasecurity/trivy/pkg/retry"
 )
 
-// Retry executes the function again using backoff until maxRetries or success
-func Retry(f func() error) error {
-	operation := func() error {
-		err := f()
-		if err != nil {
-			twerr, ok := err.(twirp.Error)
-			if !ok {
-				return backoff.Permanent(err)
-			}
-			if twerr.Code() == twirp.Unavailable {
-				return err
-			}
-			return backoff.Permanent(err)
-		}
-		return nil
-	}
-
-	b := backoff.WithMaxRetries(backoff.NewExponentialBackOff(), maxRetries)
-	err := backoff.RetryNotify(operation, b, func(err error, _ time.Duration) {
-		log.Logger.Warn(err)
-		log.Logger.Info("Retrying HTTP request...")
-	})
-	if err != nil {
-		return err
-	}
-	return nil
+// Retry executes the function again with the retry policy while the server is unavailable or the network fails
+func Retry(ctx context.Context, f func() error) error {
+	return retry.DoRPC(ctx, "RPC request", f)
 }
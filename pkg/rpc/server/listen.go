@@ -84,11 +84,11 @@ func newServeMux(serverCache cache.Cache, dbUpdateWg, requestWg *sync.WaitGroup,
 	mux := http.NewServeMux()
 
 	scanServer := rpcScanner.NewScannerServer(initializeScanServer(serverCache), nil)
-	scanHandler := withToken(withWaitGroup(scanServer), token, tokenHeader)
+	scanHandler := withScanID(withToken(withWaitGroup(scanServer), token, tokenHeader))
 	mux.Handle(rpcScanner.ScannerPathPrefix, gziphandler.GzipHandler(scanHandler))
 
 	layerServer := rpcCache.NewCacheServer(NewCacheServer(serverCache), nil)
-	layerHandler := withToken(withWaitGroup(layerServer), token, tokenHeader)
+	layerHandler := withScanID(withToken(withWaitGroup(layerServer), token, tokenHeader))
 	mux.Handle(rpcCache.CachePathPrefix, gziphandler.GzipHandler(layerHandler))
 
 	mux.HandleFunc("/healthz", func(rw http.ResponseWriter, r *http.Request) {
@@ -110,6 +110,22 @@ func withToken(base http.Handler, token, tokenHeader string) http.Handler {
 	})
 }
 
+// withScanID puts the scan ID sent by the client into the request context for logging.
+// A new ID is assigned if the client doesn't send it, and the ID is returned in the response header.
+func withScanID(base http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scanID := r.Header.Get(log.ScanIDHeader)
+		if scanID == "" {
+			scanID = log.NewScanID()
+		}
+		w.Header().Set(log.ScanIDHeader, scanID)
+
+		ctx := log.WithScanID(r.Context(), scanID)
+		log.WithContext(ctx).Debugf("%s %s", r.Method, r.URL.Path)
+		base.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 type dbWorker struct {
 	dbClient dbFile.Operation
 }
@@ -19,6 +19,7 @@ import (
 	"github.com/aquasecurity/trivy-db/pkg/db"
 	"github.com/aquasecurity/trivy-db/pkg/metadata"
 	dbFile "github.com/aquasecurity/trivy/pkg/db"
+	"github.com/aquasecurity/trivy/pkg/log"
 	"github.com/aquasecurity/trivy/pkg/utils"
 	rpcCache "github.com/aquasecurity/trivy/rpc/cache"
 )
@@ -247,3 +248,41 @@ func Test_newServeMux(t *testing.T) {
 		})
 	}
 }
+
+func Test_withScanID(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{
+			name:   "scan ID from the client",
+			header: "3e9f4c5a-5b9e-4a8e-9d7a-0b6a2f1c8d11",
+			want:   "3e9f4c5a-5b9e-4a8e-9d7a-0b6a2f1c8d11",
+		},
+		{
+			name: "new scan ID",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := withScanID(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				got = log.ScanID(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, rpcCache.CachePathPrefix, nil)
+			if tt.header != "" {
+				req.Header.Set(log.ScanIDHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			require.NotEmpty(t, got)
+			if tt.want != "" {
+				assert.Equal(t, tt.want, got)
+			}
+			assert.Equal(t, got, rec.Header().Get(log.ScanIDHeader))
+		})
+	}
+}
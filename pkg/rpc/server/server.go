@@ -8,6 +8,7 @@ import (
 	"golang.org/x/xerrors"
 
 	"github.com/aquasecurity/fanal/cache"
+	"github.com/aquasecurity/trivy/pkg/log"
 	"github.com/aquasecurity/trivy/pkg/rpc"
 	"github.com/aquasecurity/trivy/pkg/scanner"
 	"github.com/aquasecurity/trivy/pkg/scanner/local"
@@ -39,9 +40,13 @@ func (s *ScanServer) Scan(ctx context.Context, in *rpcScanner.ScanRequest) (*rpc
 		VulnType:        in.Options.VulnType,
 		SecurityChecks:  in.Options.SecurityChecks,
 		ListAllPackages: in.Options.ListAllPackages,
+		IncludeDevDeps:  in.Options.IncludeDevDeps,
 	}
+	logger := log.WithContext(ctx)
+	logger.Infof("Scanning %s...", in.Target)
 	results, os, err := s.localScanner.Scan(ctx, in.Target, in.ArtifactId, in.BlobIds, options)
 	if err != nil {
+		logger.Errorf("Failed to scan %s: %s", in.Target, err)
 		return nil, xerrors.Errorf("failed scan, %s: %w", in.Target, err)
 	}
 
@@ -0,0 +1,340 @@
+package sbom
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+	"github.com/samber/lo"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/purl"
+)
+
+const (
+	// PayloadTypeInToto is the DSSE payload type of in-toto statements
+	PayloadTypeInToto = "application/vnd.in-toto+json"
+
+	// Predicate types of SBOM attestations
+	PredicateCycloneDX       = "https://cyclonedx.org/bom"
+	PredicateCycloneDXSchema = "https://cyclonedx.org/schema"
+	PredicateSPDX            = "https://spdx.dev/Document"
+
+	// repoDigestProperty is the property of the repository digests of images in CycloneDX generated by Trivy
+	repoDigestProperty = "aquasecurity:trivy:RepoDigest"
+)
+
+// Envelope is a DSSE envelope, which is output by "cosign download attestation"
+type Envelope struct {
+	PayloadType string      `json:"payloadType"`
+	Payload     string      `json:"payload"`
+	Signatures  []Signature `json:"signatures"`
+}
+
+type Signature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// Statement is an in-toto statement
+type Statement struct {
+	Type          string          `json:"_type"`
+	PredicateType string          `json:"predicateType"`
+	Subject       []Subject       `json:"subject"`
+	Predicate     json.RawMessage `json:"predicate"`
+}
+
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// cosignPredicate is the predicate wrapping arbitrary data generated by older versions of cosign
+type cosignPredicate struct {
+	Data json.RawMessage `json:"Data"`
+}
+
+// detectAttestation returns the SBOM format in the attestation envelopes, or FormatUnknown.
+func detectAttestation(r io.Reader) Format {
+	decoder := json.NewDecoder(r)
+	for {
+		var env Envelope
+		if err := decoder.Decode(&env); err != nil || env.PayloadType != PayloadTypeInToto {
+			return FormatUnknown
+		}
+
+		statement, err := env.Statement()
+		if err != nil {
+			return FormatUnknown
+		}
+		if format := attestationFormat(statement.PredicateType); format != FormatUnknown {
+			return format
+		}
+	}
+}
+
+func attestationFormat(predicateType string) Format {
+	switch predicateType {
+	case PredicateCycloneDX, PredicateCycloneDXSchema:
+		return FormatAttestCycloneDXJSON
+	case PredicateSPDX:
+		return FormatAttestSPDXJSON
+	}
+	return FormatUnknown
+}
+
+// decodeAttestation verifies the envelope and decodes the SBOM in the predicate.
+// "cosign download attestation" prints one envelope per line, and the first one with the SBOM is used.
+func decodeAttestation(r io.Reader, format Format, opt Option) (SBOM, error) {
+	decoder := json.NewDecoder(r)
+	for {
+		var env Envelope
+		if err := decoder.Decode(&env); errors.Is(err, io.EOF) {
+			return SBOM{}, xerrors.Errorf("no %s attestation found", format)
+		} else if err != nil {
+			return SBOM{}, xerrors.Errorf("envelope decode error: %w", err)
+		}
+
+		statement, err := env.Statement()
+		if err != nil {
+			return SBOM{}, err
+		} else if attestationFormat(statement.PredicateType) != format {
+			continue
+		}
+
+		if err = env.Verify(opt.AttestationKey); err != nil {
+			return SBOM{}, xerrors.Errorf("attestation verification error: %w", err)
+		} else if opt.AttestationKey == nil {
+			if !opt.InsecureSkipVerify {
+				return SBOM{}, xerrors.New("the signature of the attestation cannot be verified without --attestation-key. " +
+					"Specify --insecure-skip-attestation-verify if the attestation has been verified in another way")
+			}
+			log.Logger.Warn("The signature of the attestation is NOT verified as --insecure-skip-attestation-verify is specified.")
+		}
+
+		predicate, err := unwrapPredicate(statement.Predicate)
+		if err != nil {
+			return SBOM{}, err
+		}
+
+		if err = checkSubject(statement.Subject, artifactDigests(format, predicate), opt.InsecureSkipVerify); err != nil {
+			return SBOM{}, xerrors.Errorf("attestation subject error: %w", err)
+		}
+
+		if format == FormatAttestCycloneDXJSON {
+			return decodeCycloneDX(bytes.NewReader(predicate), FormatCycloneDXJSON)
+		}
+		return decodeSPDX(bytes.NewReader(predicate), FormatSPDXJSON)
+	}
+}
+
+func (e Envelope) Statement() (Statement, error) {
+	if e.PayloadType != PayloadTypeInToto {
+		return Statement{}, xerrors.Errorf("unsupported payload type: %s", e.PayloadType)
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(e.Payload)
+	if err != nil {
+		return Statement{}, xerrors.Errorf("payload decode error: %w", err)
+	}
+
+	var statement Statement
+	if err = json.Unmarshal(payload, &statement); err != nil {
+		return Statement{}, xerrors.Errorf("in-toto statement decode error: %w", err)
+	}
+	return statement, nil
+}
+
+// Verify verifies the envelope. The envelope must be signed, and one of the signatures must be valid
+// when the public key is given. Without the key, the signatures are NOT verified, and callers must opt out explicitly.
+func (e Envelope) Verify(key crypto.PublicKey) error {
+	if len(e.Signatures) == 0 {
+		return xerrors.New("the envelope is not signed")
+	}
+	if key == nil {
+		return nil
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(e.Payload)
+	if err != nil {
+		return xerrors.Errorf("payload decode error: %w", err)
+	}
+	message := pae(e.PayloadType, payload)
+
+	for _, s := range e.Signatures {
+		sig, err := base64.StdEncoding.DecodeString(s.Sig)
+		if err != nil {
+			continue
+		}
+		if VerifySignature(key, message, sig) {
+			return nil
+		}
+	}
+	return xerrors.New("no valid signature found")
+}
+
+// pae returns the pre-authentication encoding, which is signed in DSSE
+func pae(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
+
+// VerifySignature verifies the signature of the message, which is signed as cosign does
+func VerifySignature(key crypto.PublicKey, message, sig []byte) bool {
+	digest := sha256.Sum256(message)
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(k, digest[:], sig)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig) == nil
+	case ed25519.PublicKey:
+		return ed25519.Verify(k, message, sig)
+	}
+	return false
+}
+
+// checkSubject checks that the statement is about the artifact described in the SBOM.
+// The statement must have a subject, and one of the subjects must have one of the digests of the artifact.
+// SBOM documents without the digests of the artifact, e.g. those of filesystems, cannot be checked,
+// and they are accepted only if skipUnchecked is set.
+func checkSubject(subjects []Subject, digests []string, skipUnchecked bool) error {
+	if len(subjects) == 0 {
+		return xerrors.New("the statement has no subject")
+	} else if len(digests) == 0 {
+		if !skipUnchecked {
+			return xerrors.New("the subject cannot be checked as the SBOM has no digest of the artifact. " +
+				"Specify --insecure-skip-attestation-verify to accept it")
+		}
+		log.Logger.Warn("The attestation subject is NOT checked as the SBOM has no digest of the artifact.")
+		return nil
+	}
+
+	for _, subject := range subjects {
+		for algorithm, value := range subject.Digest {
+			if slices.Contains(digests, algorithm+":"+value) {
+				return nil
+			}
+		}
+	}
+	return xerrors.Errorf("the attestation is not for the artifact in the SBOM (%s)", strings.Join(digests, ", "))
+}
+
+// artifactDigests returns the digests of the artifact described in the SBOM, e.g. the repository digests of images.
+// They are taken from the OCI PURLs and the "RepoDigest" properties of the root component in CycloneDX,
+// and from the OCI PURLs of the packages in SPDX.
+func artifactDigests(format Format, predicate []byte) []string {
+	var purls []string
+	switch format {
+	case FormatAttestCycloneDXJSON:
+		var bom struct {
+			Metadata struct {
+				Component struct {
+					PackageURL string `json:"purl"`
+					Properties []struct {
+						Name  string `json:"name"`
+						Value string `json:"value"`
+					} `json:"properties"`
+				} `json:"component"`
+			} `json:"metadata"`
+		}
+		if err := json.Unmarshal(predicate, &bom); err != nil {
+			return nil
+		}
+		purls = append(purls, bom.Metadata.Component.PackageURL)
+
+		var digests []string
+		for _, p := range bom.Metadata.Component.Properties {
+			if p.Name == repoDigestProperty {
+				if _, d, ok := strings.Cut(p.Value, "@"); ok {
+					digests = append(digests, d)
+				}
+			}
+		}
+		return lo.Uniq(append(digests, ociDigests(purls)...))
+	case FormatAttestSPDXJSON:
+		var doc struct {
+			Packages []struct {
+				ExternalRefs []struct {
+					ReferenceType    string `json:"referenceType"`
+					ReferenceLocator string `json:"referenceLocator"`
+				} `json:"externalRefs"`
+			} `json:"packages"`
+		}
+		if err := json.Unmarshal(predicate, &doc); err != nil {
+			return nil
+		}
+		for _, pkg := range doc.Packages {
+			for _, ref := range pkg.ExternalRefs {
+				if ref.ReferenceType == spdxRefTypePURL {
+					purls = append(purls, ref.ReferenceLocator)
+				}
+			}
+		}
+		return lo.Uniq(ociDigests(purls))
+	}
+	return nil
+}
+
+// ociDigests returns the digests in the OCI PURLs, e.g. "pkg:oci/alpine@sha256:..."
+func ociDigests(purls []string) []string {
+	var digests []string
+	for _, s := range purls {
+		p, err := packageurl.FromString(s)
+		if err != nil || p.Type != purl.TypeOCI || p.Version == "" {
+			continue
+		}
+		digests = append(digests, p.Version)
+	}
+	return digests
+}
+
+// unwrapPredicate returns the SBOM in the predicate.
+// Older versions of cosign store the SBOM in the "Data" field as an object or a string.
+func unwrapPredicate(predicate json.RawMessage) ([]byte, error) {
+	var wrapped cosignPredicate
+	if err := json.Unmarshal(predicate, &wrapped); err != nil {
+		return nil, xerrors.Errorf("predicate decode error: %w", err)
+	}
+	if len(wrapped.Data) == 0 {
+		return predicate, nil
+	}
+
+	var s string
+	if err := json.Unmarshal(wrapped.Data, &s); err == nil {
+		return []byte(s), nil
+	}
+	return wrapped.Data, nil
+}
+
+// LoadPublicKey loads a PEM-encoded public key such as "cosign.pub"
+func LoadPublicKey(filePath string) (crypto.PublicKey, error) {
+	b, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to read the public key: %w", err)
+	}
+
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, xerrors.Errorf("no PEM block found in %s", filePath)
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, xerrors.Errorf("public key parse error: %w", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,148 @@
+package sbom
+
+import (
+	"io"
+	"strconv"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"golang.org/x/xerrors"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/purl"
+	"github.com/aquasecurity/trivy/pkg/report/cyclonedx"
+)
+
+func decodeCycloneDX(r io.Reader, format Format) (SBOM, error) {
+	fileFormat := cdx.BOMFileFormatJSON
+	if format == FormatCycloneDXXML {
+		fileFormat = cdx.BOMFileFormatXML
+	}
+
+	var bom cdx.BOM
+	if err := cdx.NewBOMDecoder(r, fileFormat).Decode(&bom); err != nil {
+		return SBOM{}, xerrors.Errorf("CycloneDX decode error: %w", err)
+	}
+
+	var components []cdx.Component
+	if bom.Components != nil {
+		components = flattenComponents(*bom.Components)
+	}
+
+	refs := map[string]cdx.Component{}
+	for _, c := range components {
+		if c.BOMRef != "" {
+			refs[c.BOMRef] = c
+		}
+	}
+
+	// Trivy lists libraries in lock files as dependencies of an application component,
+	// which has the application type and the file path.
+	parents := map[string]cdx.Component{}
+	if bom.Dependencies != nil {
+		for _, dep := range *bom.Dependencies {
+			parent, ok := refs[dep.Ref]
+			if !ok || parent.Type != cdx.ComponentTypeApplication || dep.Dependencies == nil {
+				continue
+			}
+			for _, d := range *dep.Dependencies {
+				parents[d.Ref] = parent
+			}
+		}
+	}
+
+	b := newBuilder()
+	var osFound *ftypes.OS
+	for _, c := range components {
+		switch {
+		case c.Type == cdx.ComponentTypeOS:
+			osFound = &ftypes.OS{
+				Family: c.Name,
+				Name:   c.Version,
+			}
+		case c.PackageURL != "":
+			p, err := purl.FromString(c.PackageURL)
+			if err != nil {
+				log.Logger.Debugf("Skipping the component %s: %s", c.Name, err)
+				continue
+			}
+
+			var appType, appPath string
+			if parent, ok := parents[c.BOMRef]; ok {
+				appType = property(parent, cyclonedx.PropertyType)
+				appPath = parent.Name
+			}
+			b.add(p, componentToPackage(p, c), appType, appPath)
+		}
+	}
+
+	sbom := b.build()
+	if osFound != nil {
+		sbom.OS = osFound
+	}
+	return sbom, nil
+}
+
+// flattenComponents returns components including nested ones
+func flattenComponents(components []cdx.Component) []cdx.Component {
+	var ret []cdx.Component
+	for _, c := range components {
+		ret = append(ret, c)
+		if c.Components != nil {
+			ret = append(ret, flattenComponents(*c.Components)...)
+		}
+	}
+	return ret
+}
+
+func componentToPackage(p *purl.PackageURL, c cdx.Component) ftypes.Package {
+	pkg := *p.Package()
+
+	if v := property(c, cyclonedx.PropertyFilePath); v != "" {
+		pkg.FilePath = v
+	}
+	pkg.SrcName = property(c, cyclonedx.PropertySrcName)
+	pkg.SrcVersion = property(c, cyclonedx.PropertySrcVersion)
+	pkg.SrcRelease = property(c, cyclonedx.PropertySrcRelease)
+	if epoch, err := strconv.Atoi(property(c, cyclonedx.PropertySrcEpoch)); err == nil {
+		pkg.SrcEpoch = epoch
+	}
+	if v := property(c, cyclonedx.PropertyModularitylabel); v != "" {
+		pkg.Modularitylabel = v
+	}
+	pkg.Layer = ftypes.Layer{
+		Digest: property(c, cyclonedx.PropertyLayerDigest),
+		DiffID: property(c, cyclonedx.PropertyLayerDiffID),
+	}
+
+	if c.Licenses != nil {
+		for _, l := range *c.Licenses {
+			switch {
+			case l.Expression != "":
+				pkg.License = l.Expression
+			case l.License != nil && l.License.ID != "":
+				pkg.License = l.License.ID
+			case l.License != nil:
+				pkg.License = l.License.Name
+			}
+			if pkg.License != "" {
+				break
+			}
+		}
+	}
+
+	return pkg
+}
+
+// property returns the value of the Trivy property
+func property(c cdx.Component, key string) string {
+	if c.Properties == nil {
+		return ""
+	}
+	for _, p := range *c.Properties {
+		if p.Name == cyclonedx.Namespace+key {
+			return p.Value
+		}
+	}
+	return ""
+}
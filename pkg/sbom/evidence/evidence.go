@@ -0,0 +1,97 @@
+package evidence
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Evidence is the evidence of a package included in detailed SBOMs
+type Evidence struct {
+	// FoundAt is the path of the file where the package was found
+	FoundAt string
+
+	// SHA256 is the hex-encoded SHA-256 digest of the package file.
+	// It is empty when the package file is not accessible, e.g. files in container images.
+	SHA256 string
+
+	// License is the license found in the package metadata
+	License string
+}
+
+// Collector collects evidences of packages in the report
+type Collector struct {
+	// root is the directory where package files are accessible
+	root    string
+	digests map[string]string
+}
+
+// NewCollector returns a collector for the report.
+// Package files are hashed only when the scanned artifact is a local filesystem.
+func NewCollector(report types.Report) *Collector {
+	var root string
+	if report.ArtifactType == ftypes.ArtifactFilesystem {
+		root = report.ArtifactName
+	}
+	return &Collector{
+		root:    root,
+		digests: map[string]string{},
+	}
+}
+
+// Collect returns the evidence of the package in the result
+func (c *Collector) Collect(result types.Result, pkg ftypes.Package) Evidence {
+	e := Evidence{
+		FoundAt: pkg.FilePath,
+		License: pkg.License,
+	}
+
+	// Packages in lock files don't have their own files
+	if e.FoundAt == "" && result.Class == types.ClassLangPkg {
+		e.FoundAt = result.Target
+		return e
+	}
+
+	if pkg.FilePath != "" {
+		e.SHA256 = c.digest(pkg.FilePath)
+	}
+	return e
+}
+
+func (c *Collector) digest(filePath string) string {
+	if c.root == "" {
+		return ""
+	}
+	if d, ok := c.digests[filePath]; ok {
+		return d
+	}
+
+	d, err := fileSHA256(filepath.Join(c.root, filepath.FromSlash(filePath)))
+	if err != nil {
+		log.Logger.Debugf("Unable to calculate the digest of %s: %s", filePath, err)
+	}
+	c.digests[filePath] = d
+	return d
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", xerrors.Errorf("file open error: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", xerrors.Errorf("file read error: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,87 @@
+package sbom
+
+import (
+	"golang.org/x/xerrors"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/purl"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Merge merges the SBOMs into one.
+// Packages are deduplicated by PURL in OS packages and in each application,
+// so that the relationships between applications and their libraries are preserved.
+// SBOMs of different operating systems cannot be merged.
+func Merge(boms []SBOM) (SBOM, error) {
+	var merged SBOM
+	for _, bom := range boms {
+		if bom.OS == nil {
+			continue
+		}
+		if merged.OS == nil {
+			merged.OS = bom.OS
+		} else if merged.OS.Family != bom.OS.Family || merged.OS.Name != bom.OS.Name {
+			return SBOM{}, xerrors.Errorf("SBOMs of different operating systems cannot be merged: %s %s and %s %s",
+				merged.OS.Family, merged.OS.Name, bom.OS.Family, bom.OS.Name)
+		}
+	}
+
+	var osFamily string
+	if merged.OS != nil {
+		osFamily = merged.OS.Family
+	}
+	var osPkgs []ftypes.Package
+	osSeen := map[string]struct{}{}
+
+	apps := map[appKey]*ftypes.Application{}
+	var appOrder []appKey
+	libSeen := map[appKey]map[string]struct{}{}
+
+	for _, bom := range boms {
+		for _, pkgInfo := range bom.Packages {
+			for _, pkg := range pkgInfo.Packages {
+				if add(osSeen, osFamily, merged.OS, pkg) {
+					osPkgs = append(osPkgs, pkg)
+				}
+			}
+		}
+
+		for _, app := range bom.Applications {
+			key := appKey{appType: app.Type, filePath: app.FilePath}
+			if _, ok := apps[key]; !ok {
+				apps[key] = &ftypes.Application{
+					Type:     app.Type,
+					FilePath: app.FilePath,
+				}
+				appOrder = append(appOrder, key)
+				libSeen[key] = map[string]struct{}{}
+			}
+			for _, lib := range app.Libraries {
+				if add(libSeen[key], app.Type, nil, lib) {
+					apps[key].Libraries = append(apps[key].Libraries, lib)
+				}
+			}
+		}
+	}
+
+	if len(osPkgs) > 0 {
+		merged.Packages = []ftypes.PackageInfo{{Packages: osPkgs}}
+	}
+	for _, key := range appOrder {
+		merged.Applications = append(merged.Applications, *apps[key])
+	}
+	return merged, nil
+}
+
+// add records the PURL of the package and returns false if it has been already recorded
+func add(seen map[string]struct{}, t string, fos *ftypes.OS, pkg ftypes.Package) bool {
+	key := pkg.Name + "@" + pkg.Version
+	if p, err := purl.NewPackageURL(t, types.Metadata{OS: fos}, pkg); err == nil && p.Type != "" {
+		key = p.ToString()
+	}
+	if _, ok := seen[key]; ok {
+		return false
+	}
+	seen[key] = struct{}{}
+	return true
+}
@@ -0,0 +1,220 @@
+package sbom
+
+import (
+	"bufio"
+	"crypto"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"sort"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/purl"
+)
+
+type Format string
+
+const (
+	FormatCycloneDXJSON Format = "cyclonedx-json"
+	FormatCycloneDXXML  Format = "cyclonedx-xml"
+	FormatSPDXJSON      Format = "spdx-json"
+	FormatSPDXTV        Format = "spdx-tv"
+	FormatUnknown       Format = "unknown"
+
+	// In-toto attestations with SBOM, e.g. "cosign download attestation" output
+	FormatAttestCycloneDXJSON Format = "attest-cyclonedx-json"
+	FormatAttestSPDXJSON      Format = "attest-spdx-json"
+)
+
+// EmbeddedType is the type of custom resources holding OS packages listed in SBOM files embedded in images
+const EmbeddedType = "trivy-embedded-sbom"
+
+// Embedded holds the OS and OS packages listed in an SBOM file embedded in an image
+type Embedded struct {
+	OS       *ftypes.OS
+	Packages []ftypes.Package
+}
+
+// Option holds the options for decoding SBOM
+type Option struct {
+	// AttestationKey is the public key to verify the signature of attestations.
+	// Attestations are rejected without it unless InsecureSkipVerify is set.
+	AttestationKey crypto.PublicKey
+
+	// InsecureSkipVerify accepts attestations without verifying the signature,
+	// and without checking the subject if the SBOM has no digest of the artifact
+	InsecureSkipVerify bool
+}
+
+// SBOM holds the packages listed in an SBOM document
+type SBOM struct {
+	OS           *ftypes.OS
+	Packages     []ftypes.PackageInfo
+	Applications []ftypes.Application
+}
+
+// DetectFormat detects the SBOM format from the content
+func DetectFormat(r io.ReadSeeker) (Format, error) {
+	defer func() {
+		_, _ = r.Seek(0, io.SeekStart)
+	}()
+
+	type cyclonedx struct {
+		// XML specific field
+		XMLName xml.Name `json:"-"`
+
+		// JSON specific field
+		BOMFormat string `json:"bomFormat" xml:"-"`
+	}
+
+	// Try CycloneDX JSON
+	var cdxBOM cyclonedx
+	if err := json.NewDecoder(r).Decode(&cdxBOM); err == nil {
+		if cdxBOM.BOMFormat == "CycloneDX" {
+			return FormatCycloneDXJSON, nil
+		}
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return FormatUnknown, xerrors.Errorf("seek error: %w", err)
+	}
+
+	// Try CycloneDX XML
+	if err := xml.NewDecoder(r).Decode(&cdxBOM); err == nil {
+		if strings.HasPrefix(cdxBOM.XMLName.Space, "http://cyclonedx.org") {
+			return FormatCycloneDXXML, nil
+		}
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return FormatUnknown, xerrors.Errorf("seek error: %w", err)
+	}
+
+	// Try SPDX JSON
+	var spdxBOM struct {
+		SPDXID string `json:"SPDXID"`
+	}
+	if err := json.NewDecoder(r).Decode(&spdxBOM); err == nil {
+		if strings.HasPrefix(spdxBOM.SPDXID, "SPDXRef-") {
+			return FormatSPDXJSON, nil
+		}
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return FormatUnknown, xerrors.Errorf("seek error: %w", err)
+	}
+
+	// Try in-toto attestation
+	if format := detectAttestation(r); format != FormatUnknown {
+		return format, nil
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return FormatUnknown, xerrors.Errorf("seek error: %w", err)
+	}
+
+	// Try SPDX tag-value
+	if scanner := bufio.NewScanner(r); scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), "SPDXVersion:") {
+			return FormatSPDXTV, nil
+		}
+	}
+
+	return FormatUnknown, nil
+}
+
+// Decode decodes the SBOM document in the given format
+func Decode(r io.Reader, format Format, opt Option) (SBOM, error) {
+	var (
+		bom SBOM
+		err error
+	)
+	switch format {
+	case FormatCycloneDXJSON, FormatCycloneDXXML:
+		bom, err = decodeCycloneDX(r, format)
+	case FormatSPDXJSON, FormatSPDXTV:
+		bom, err = decodeSPDX(r, format)
+	case FormatAttestCycloneDXJSON, FormatAttestSPDXJSON:
+		bom, err = decodeAttestation(r, format, opt)
+	default:
+		return SBOM{}, xerrors.Errorf("%s format is not supported", format)
+	}
+	if err != nil {
+		return SBOM{}, xerrors.Errorf("failed to decode %s: %w", format, err)
+	}
+	return bom, nil
+}
+
+// builder aggregates packages identified by PURLs into OS packages and applications
+type builder struct {
+	os       *ftypes.OS
+	osPkgs   []ftypes.Package
+	apps     map[appKey][]ftypes.Package
+	appOrder []appKey
+}
+
+type appKey struct {
+	appType  string
+	filePath string
+}
+
+func newBuilder() *builder {
+	return &builder{apps: map[appKey][]ftypes.Package{}}
+}
+
+// add adds the package identified by the PURL.
+// When appType is empty, it is guessed from the PURL type.
+func (b *builder) add(p *purl.PackageURL, pkg ftypes.Package, appType, appPath string) {
+	if p.IsOSPkg() {
+		if b.os == nil {
+			b.os = p.OS()
+		}
+
+		// The source package is assumed to be the same as the binary package if not specified
+		if pkg.SrcName == "" {
+			pkg.SrcName = pkg.Name
+			pkg.SrcVersion = pkg.Version
+			pkg.SrcRelease = pkg.Release
+			pkg.SrcEpoch = pkg.Epoch
+		}
+		b.osPkgs = append(b.osPkgs, pkg)
+		return
+	}
+
+	if appType == "" {
+		appType = p.AppType()
+	}
+	if appType == "" {
+		log.Logger.Debugf("Skipping the unsupported package: %s", p.ToString())
+		return
+	}
+
+	key := appKey{appType: appType, filePath: appPath}
+	if _, ok := b.apps[key]; !ok {
+		b.appOrder = append(b.appOrder, key)
+	}
+	b.apps[key] = append(b.apps[key], pkg)
+}
+
+func (b *builder) build() SBOM {
+	bom := SBOM{OS: b.os}
+	if len(b.osPkgs) > 0 {
+		bom.Packages = []ftypes.PackageInfo{{Packages: b.osPkgs}}
+	}
+	for _, key := range b.appOrder {
+		libs := b.apps[key]
+		sort.Slice(libs, func(i, j int) bool {
+			return libs[i].Name < libs[j].Name || (libs[i].Name == libs[j].Name && libs[i].Version < libs[j].Version)
+		})
+		bom.Applications = append(bom.Applications, ftypes.Application{
+			Type:      key.appType,
+			FilePath:  key.filePath,
+			Libraries: libs,
+		})
+	}
+	return bom
+}
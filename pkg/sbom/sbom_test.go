@@ -0,0 +1,540 @@
+package sbom_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/sbom"
+)
+
+func TestMain(m *testing.M) {
+	_ = log.InitLogger(false, true)
+	os.Exit(m.Run())
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name       string
+		inputFile  string
+		option     sbom.Option
+		wantFormat sbom.Format
+		want       sbom.SBOM
+		wantErr    string
+	}{
+		{
+			name:       "CycloneDX JSON",
+			inputFile:  "testdata/cyclonedx.json",
+			wantFormat: sbom.FormatCycloneDXJSON,
+			want: sbom.SBOM{
+				OS: &ftypes.OS{
+					Family: "alpine",
+					Name:   "3.16.0",
+				},
+				Packages: []ftypes.PackageInfo{
+					{
+						Packages: []ftypes.Package{
+							{
+								Name:       "musl",
+								Version:    "1.2.3-r0",
+								SrcName:    "musl",
+								SrcVersion: "1.2.3-r0",
+								License:    "MIT",
+							},
+							{
+								Name:       "busybox",
+								Version:    "1.35.0-r13",
+								SrcName:    "busybox",
+								SrcVersion: "1.35.0-r13",
+							},
+						},
+					},
+				},
+				Applications: []ftypes.Application{
+					{
+						Type:     ftypes.Npm,
+						FilePath: "app/package-lock.json",
+						Libraries: []ftypes.Package{
+							{
+								Name:    "lodash",
+								Version: "4.17.20",
+							},
+						},
+					},
+					{
+						Type: ftypes.Jar,
+						Libraries: []ftypes.Package{
+							{
+								Name:     "org.springframework:spring-core",
+								Version:  "5.3.14",
+								FilePath: "app.jar",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:       "SPDX JSON",
+			inputFile:  "testdata/spdx.json",
+			wantFormat: sbom.FormatSPDXJSON,
+			want: sbom.SBOM{
+				OS: &ftypes.OS{
+					Family: "centos",
+					Name:   "8.4.2105",
+				},
+				Packages: []ftypes.PackageInfo{
+					{
+						Packages: []ftypes.Package{
+							{
+								Name:       "openssl-libs",
+								Epoch:      1,
+								Version:    "1.1.1k",
+								Release:    "5.el8_5",
+								Arch:       "x86_64",
+								SrcName:    "openssl-libs",
+								SrcEpoch:   1,
+								SrcVersion: "1.1.1k",
+								SrcRelease: "5.el8_5",
+								License:    "OpenSSL",
+							},
+						},
+					},
+				},
+				Applications: []ftypes.Application{
+					{
+						Type: ftypes.PythonPkg,
+						Libraries: []ftypes.Package{
+							{
+								Name:    "requests",
+								Version: "2.25.0",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:       "SPDX tag-value",
+			inputFile:  "testdata/spdx.txt",
+			wantFormat: sbom.FormatSPDXTV,
+			want: sbom.SBOM{
+				Applications: []ftypes.Application{
+					{
+						Type: ftypes.GoBinary,
+						Libraries: []ftypes.Package{
+							{
+								Name:    "github.com/gin-gonic/gin",
+								Version: "v1.7.0",
+								License: "MIT",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:       "CycloneDX attestation",
+			inputFile:  "testdata/attestation.json",
+			option:     sbom.Option{InsecureSkipVerify: true},
+			wantFormat: sbom.FormatAttestCycloneDXJSON,
+			want: sbom.SBOM{
+				OS: &ftypes.OS{
+					Family: "alpine",
+					Name:   "3.16.0",
+				},
+				Packages: []ftypes.PackageInfo{
+					{
+						Packages: []ftypes.Package{
+							{
+								Name:       "musl",
+								Version:    "1.2.3-r0",
+								SrcName:    "musl",
+								SrcVersion: "1.2.3-r0",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:       "unknown format",
+			inputFile:  "testdata/unknown.json",
+			wantFormat: sbom.FormatUnknown,
+			wantErr:    "unknown format is not supported",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.inputFile)
+			require.NoError(t, err)
+			defer f.Close()
+
+			format, err := sbom.DetectFormat(f)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFormat, format)
+
+			got, err := sbom.Decode(f, format, tt.option)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// signStatement returns the envelope of the statement signed with the key as cosign does
+func signStatement(t *testing.T, key *ecdsa.PrivateKey, statement sbom.Statement) []byte {
+	payload, err := json.Marshal(statement)
+	require.NoError(t, err)
+	b, err := json.Marshal(sbom.Envelope{
+		PayloadType: sbom.PayloadTypeInToto,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures:  []sbom.Signature{{Sig: sign(t, key, payload)}},
+	})
+	require.NoError(t, err)
+	return b
+}
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, payload []byte) string {
+	pae := fmt.Sprintf("DSSEv1 %d %s %d %s", len(sbom.PayloadTypeInToto), sbom.PayloadTypeInToto, len(payload), payload)
+	digest := sha256.Sum256([]byte(pae))
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(sig)
+}
+
+func TestDecode_AttestationSignature(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	statement := sbom.Statement{
+		Type:          "https://in-toto.io/Statement/v0.1",
+		PredicateType: sbom.PredicateCycloneDX,
+		Subject:       []sbom.Subject{{Name: "alpine", Digest: map[string]string{"sha256": "abc"}}},
+		Predicate: json.RawMessage(`{"bomFormat":"CycloneDX","specVersion":"1.4","version":1,"metadata":{"component":{` +
+			`"type":"container","name":"alpine","purl":"pkg:oci/alpine@sha256%3Aabc"}}}`),
+	}
+	payload, err := json.Marshal(statement)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		signatures []sbom.Signature
+		payload    []byte
+		option     sbom.Option
+		wantErr    string
+	}{
+		{
+			name:       "valid signature",
+			signatures: []sbom.Signature{{Sig: sign(t, key, payload)}},
+			payload:    payload,
+			option:     sbom.Option{AttestationKey: key.Public()},
+		},
+		{
+			name:       "no key",
+			signatures: []sbom.Signature{{Sig: "invalid"}},
+			payload:    payload,
+			wantErr:    "the signature of the attestation cannot be verified without --attestation-key",
+		},
+		{
+			name:       "no key with skipping verification",
+			signatures: []sbom.Signature{{Sig: "invalid"}},
+			payload:    payload,
+			option:     sbom.Option{InsecureSkipVerify: true},
+		},
+		{
+			name:       "another key",
+			signatures: []sbom.Signature{{Sig: sign(t, key, payload)}},
+			payload:    payload,
+			option:     sbom.Option{AttestationKey: otherKey.Public()},
+			wantErr:    "no valid signature found",
+		},
+		{
+			name:       "tampered payload",
+			signatures: []sbom.Signature{{Sig: sign(t, key, []byte("{}"))}},
+			payload:    payload,
+			option:     sbom.Option{AttestationKey: key.Public()},
+			wantErr:    "no valid signature found",
+		},
+		{
+			name:    "unsigned",
+			payload: payload,
+			option:  sbom.Option{InsecureSkipVerify: true},
+			wantErr: "the envelope is not signed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(sbom.Envelope{
+				PayloadType: sbom.PayloadTypeInToto,
+				Payload:     base64.StdEncoding.EncodeToString(tt.payload),
+				Signatures:  tt.signatures,
+			})
+			require.NoError(t, err)
+
+			_, err = sbom.Decode(bytes.NewReader(b), sbom.FormatAttestCycloneDXJSON, tt.option)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestDecode_AttestationSubject(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	const (
+		digest      = "sha256:a27fd8080b517143cbbbab9dfb7c8571c40d67d534bbdee55bd6c473f432b177"
+		otherDigest = "sha256:5d0da3dc976460b72c77d94c8a1ad043720b0416bfc16c52c45d4847e53fadb6"
+	)
+	cdxImage := func(d string) string {
+		return `{"bomFormat":"CycloneDX","specVersion":"1.4","version":1,"metadata":{"component":{` +
+			`"type":"container","name":"rails","purl":"pkg:oci/rails@` + strings.Replace(d, ":", "%3A", 1) + `"}}}`
+	}
+	subject := []sbom.Subject{{Name: "index.docker.io/library/rails", Digest: map[string]string{"sha256": strings.TrimPrefix(digest, "sha256:")}}}
+
+	tests := []struct {
+		name          string
+		predicateType string
+		format        sbom.Format
+		subject       []sbom.Subject
+		predicate     string
+		insecure      bool
+		wantErr       string
+	}{
+		{
+			name:          "CycloneDX PURL",
+			predicateType: sbom.PredicateCycloneDX,
+			format:        sbom.FormatAttestCycloneDXJSON,
+			subject:       subject,
+			predicate:     cdxImage(digest),
+		},
+		{
+			name:          "CycloneDX RepoDigest",
+			predicateType: sbom.PredicateCycloneDX,
+			format:        sbom.FormatAttestCycloneDXJSON,
+			subject:       subject,
+			predicate: `{"bomFormat":"CycloneDX","specVersion":"1.4","version":1,"metadata":{"component":{` +
+				`"type":"container","name":"rails","properties":[{"name":"aquasecurity:trivy:RepoDigest","value":"rails@` + digest + `"}]}}}`,
+		},
+		{
+			name:          "CycloneDX of another image",
+			predicateType: sbom.PredicateCycloneDX,
+			format:        sbom.FormatAttestCycloneDXJSON,
+			subject:       subject,
+			predicate:     cdxImage(otherDigest),
+			wantErr:       "the attestation is not for the artifact in the SBOM (" + otherDigest + ")",
+		},
+		{
+			name:          "SPDX of another image",
+			predicateType: sbom.PredicateSPDX,
+			format:        sbom.FormatAttestSPDXJSON,
+			subject:       subject,
+			predicate: `{"SPDXID":"SPDXRef-DOCUMENT","spdxVersion":"SPDX-2.2","packages":[{"SPDXID":"SPDXRef-rails","name":"rails",` +
+				`"externalRefs":[{"referenceCategory":"PACKAGE-MANAGER","referenceType":"purl","referenceLocator":"pkg:oci/rails@` + otherDigest + `"}]}]}`,
+			wantErr: "the attestation is not for the artifact in the SBOM",
+		},
+		{
+			name:          "no digest of the artifact",
+			predicateType: sbom.PredicateCycloneDX,
+			format:        sbom.FormatAttestCycloneDXJSON,
+			subject:       subject,
+			predicate:     `{"bomFormat":"CycloneDX","specVersion":"1.4","version":1}`,
+			wantErr:       "the subject cannot be checked as the SBOM has no digest of the artifact",
+		},
+		{
+			name:          "no digest of the artifact with skipping verification",
+			predicateType: sbom.PredicateCycloneDX,
+			format:        sbom.FormatAttestCycloneDXJSON,
+			subject:       subject,
+			predicate:     `{"bomFormat":"CycloneDX","specVersion":"1.4","version":1}`,
+			insecure:      true,
+		},
+		{
+			name:          "CycloneDX of another image with skipping verification",
+			predicateType: sbom.PredicateCycloneDX,
+			format:        sbom.FormatAttestCycloneDXJSON,
+			subject:       subject,
+			predicate:     cdxImage(otherDigest),
+			insecure:      true,
+			wantErr:       "the attestation is not for the artifact in the SBOM",
+		},
+		{
+			name:          "no subject",
+			predicateType: sbom.PredicateCycloneDX,
+			format:        sbom.FormatAttestCycloneDXJSON,
+			predicate:     cdxImage(digest),
+			wantErr:       "the statement has no subject",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := signStatement(t, key, sbom.Statement{
+				Type:          "https://in-toto.io/Statement/v0.1",
+				PredicateType: tt.predicateType,
+				Subject:       tt.subject,
+				Predicate:     json.RawMessage(tt.predicate),
+			})
+
+			_, err = sbom.Decode(bytes.NewReader(b), tt.format, sbom.Option{
+				AttestationKey:     key.Public(),
+				InsecureSkipVerify: tt.insecure,
+			})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	alpine := &ftypes.OS{
+		Family: "alpine",
+		Name:   "3.16.0",
+	}
+	musl := ftypes.Package{
+		Name:    "musl",
+		Version: "1.2.3",
+		Release: "r0",
+	}
+	busybox := ftypes.Package{
+		Name:    "busybox",
+		Version: "1.35.0",
+		Release: "r13",
+	}
+	lodash := ftypes.Package{
+		Name:    "lodash",
+		Version: "4.17.20",
+	}
+	express := ftypes.Package{
+		Name:    "express",
+		Version: "4.17.3",
+	}
+
+	tests := []struct {
+		name    string
+		boms    []sbom.SBOM
+		want    sbom.SBOM
+		wantErr string
+	}{
+		{
+			name: "happy path",
+			boms: []sbom.SBOM{
+				{
+					OS:       alpine,
+					Packages: []ftypes.PackageInfo{{Packages: []ftypes.Package{musl}}},
+					Applications: []ftypes.Application{
+						{
+							Type:      ftypes.Npm,
+							FilePath:  "app/package-lock.json",
+							Libraries: []ftypes.Package{lodash},
+						},
+					},
+				},
+				{
+					OS:       alpine,
+					Packages: []ftypes.PackageInfo{{Packages: []ftypes.Package{musl, busybox}}},
+					Applications: []ftypes.Application{
+						{
+							Type:      ftypes.Npm,
+							FilePath:  "app/package-lock.json",
+							Libraries: []ftypes.Package{lodash, express},
+						},
+						{
+							Type:      ftypes.Npm,
+							FilePath:  "web/package-lock.json",
+							Libraries: []ftypes.Package{lodash},
+						},
+					},
+				},
+			},
+			want: sbom.SBOM{
+				OS:       alpine,
+				Packages: []ftypes.PackageInfo{{Packages: []ftypes.Package{musl, busybox}}},
+				Applications: []ftypes.Application{
+					{
+						Type:      ftypes.Npm,
+						FilePath:  "app/package-lock.json",
+						Libraries: []ftypes.Package{lodash, express},
+					},
+					{
+						Type:      ftypes.Npm,
+						FilePath:  "web/package-lock.json",
+						Libraries: []ftypes.Package{lodash},
+					},
+				},
+			},
+		},
+		{
+			name: "libraries only",
+			boms: []sbom.SBOM{
+				{OS: alpine},
+				{
+					Applications: []ftypes.Application{
+						{
+							Type:      ftypes.Npm,
+							Libraries: []ftypes.Package{lodash},
+						},
+					},
+				},
+			},
+			want: sbom.SBOM{
+				OS: alpine,
+				Applications: []ftypes.Application{
+					{
+						Type:      ftypes.Npm,
+						Libraries: []ftypes.Package{lodash},
+					},
+				},
+			},
+		},
+		{
+			name: "different operating systems",
+			boms: []sbom.SBOM{
+				{OS: alpine},
+				{
+					OS: &ftypes.OS{
+						Family: "debian",
+						Name:   "11.3",
+					},
+				},
+			},
+			wantErr: "SBOMs of different operating systems cannot be merged: alpine 3.16.0 and debian 11.3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sbom.Merge(tt.boms)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
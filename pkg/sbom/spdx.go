@@ -0,0 +1,74 @@
+package sbom
+
+import (
+	"io"
+	"sort"
+
+	"github.com/spdx/tools-golang/jsonloader"
+	"github.com/spdx/tools-golang/spdx"
+	"github.com/spdx/tools-golang/tvloader"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/purl"
+)
+
+const spdxRefTypePURL = "purl"
+
+// decodeSPDX converts SPDX packages having a PURL in their external references.
+// Other packages are skipped since their ecosystems cannot be identified.
+func decodeSPDX(r io.Reader, format Format) (SBOM, error) {
+	var (
+		doc *spdx.Document2_2
+		err error
+	)
+	if format == FormatSPDXJSON {
+		doc, err = jsonloader.Load2_2(r)
+	} else {
+		doc, err = tvloader.Load2_2(r)
+	}
+	if err != nil {
+		return SBOM{}, xerrors.Errorf("SPDX decode error: %w", err)
+	}
+
+	// Sort packages for consistent results
+	var ids []string
+	for id := range doc.Packages {
+		ids = append(ids, string(id))
+	}
+	sort.Strings(ids)
+
+	b := newBuilder()
+	for _, id := range ids {
+		spdxPkg := doc.Packages[spdx.ElementID(id)]
+		p := packagePURL(spdxPkg)
+		if p == nil {
+			log.Logger.Debugf("Skipping the package without PURL: %s", spdxPkg.PackageName)
+			continue
+		}
+
+		pkg := *p.Package()
+		switch spdxPkg.PackageLicenseConcluded {
+		case "", "NONE", "NOASSERTION":
+		default:
+			pkg.License = spdxPkg.PackageLicenseConcluded
+		}
+		b.add(p, pkg, "", "")
+	}
+	return b.build(), nil
+}
+
+func packagePURL(pkg *spdx.Package2_2) *purl.PackageURL {
+	for _, ref := range pkg.PackageExternalReferences {
+		if ref.RefType != spdxRefTypePURL {
+			continue
+		}
+		p, err := purl.FromString(ref.Locator)
+		if err != nil {
+			log.Logger.Debugf("Invalid PURL in %s: %s", pkg.PackageName, err)
+			continue
+		}
+		return p
+	}
+	return nil
+}
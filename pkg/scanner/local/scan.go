@@ -2,14 +2,19 @@ package local
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"runtime"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/google/wire"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
 	"golang.org/x/exp/slices"
+	"golang.org/x/sync/errgroup"
 	"golang.org/x/xerrors"
 
 	"github.com/aquasecurity/fanal/analyzer"
@@ -18,20 +23,74 @@ import (
 	_ "github.com/aquasecurity/fanal/handler/all"
 	ftypes "github.com/aquasecurity/fanal/types"
 	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	// Some analyzers override those of fanal of the same types. Their versions are larger than those of fanal
+	// so that cached results of fanal are not used.
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/buildpack"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/codeowners"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/config/bicep"
+	"github.com/aquasecurity/trivy/pkg/analyzer/language"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/language/bazel/maven"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/language/bazel/module"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/language/c/conan"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/language/c/vcpkg"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/language/conda/environment"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/language/conda/lock"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/language/conda/meta"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/language/dart/pub"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/language/dotnet/deps"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/language/dotnet/nuget"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/language/golang/binary"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/language/java/catalog"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/language/java/gradle"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/language/java/jar"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/language/nodejs/npm"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/language/nodejs/pnp"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/language/nodejs/pnpm"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/language/nodejs/yarn"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/language/php/composer"
+	"github.com/aquasecurity/trivy/pkg/analyzer/language/python/packaging"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/language/python/pipenv"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/language/python/poetry"
+	"github.com/aquasecurity/trivy/pkg/analyzer/language/ruby/gemspec"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/language/rust/binary"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/language/rust/cargo"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/language/swift/carthage"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/language/swift/cocoapods"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/language/swift/swift"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/os/bottlerocket"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/os/dnf"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/os/portage"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/os/release"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/os/windows"
+	_ "github.com/aquasecurity/trivy/pkg/analyzer/sbom"
+	asecret "github.com/aquasecurity/trivy/pkg/analyzer/secret"
 	"github.com/aquasecurity/trivy/pkg/detector/library"
 	ospkgDetector "github.com/aquasecurity/trivy/pkg/detector/ospkg"
+	"github.com/aquasecurity/trivy/pkg/dnf"
+	"github.com/aquasecurity/trivy/pkg/ignore"
+	"github.com/aquasecurity/trivy/pkg/kernel"
+	"github.com/aquasecurity/trivy/pkg/licensing"
 	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/ownership"
+	"github.com/aquasecurity/trivy/pkg/sbom"
 	"github.com/aquasecurity/trivy/pkg/scanner/post"
+	"github.com/aquasecurity/trivy/pkg/secret"
+	"github.com/aquasecurity/trivy/pkg/timeout"
 	"github.com/aquasecurity/trivy/pkg/types"
 	"github.com/aquasecurity/trivy/pkg/vulnerability"
 )
 
+// defaultParallel is the number of targets detected concurrently by default, the same as that of files analyzed
+var defaultParallel = 2 * runtime.NumCPU()
+
 var (
 	pkgTargets = map[string]string{
 		ftypes.PythonPkg: "Python",
 		ftypes.GemSpec:   "Ruby",
 		ftypes.NodePkg:   "Node.js",
 		ftypes.Jar:       "Java",
+		types.CondaPkg:   "Conda",
 	}
 )
 
@@ -60,6 +119,9 @@ type Scanner struct {
 	applier       Applier
 	ospkgDetector OspkgDetector
 	vulnClient    vulnerability.Client
+
+	// logger attaches the scan ID of the request, set per scan
+	logger *zap.SugaredLogger
 }
 
 // NewScanner is the factory method for Scanner
@@ -72,34 +134,60 @@ func NewScanner(applier Applier, ospkgDetector OspkgDetector, vulnClient vulnera
 
 // Scan scans the artifact and return results.
 func (s Scanner) Scan(ctx context.Context, target, artifactKey string, blobKeys []string, options types.ScanOptions) (types.Results, *ftypes.OS, error) {
+	// Scanner is copied per scan, so the logger is not shared between concurrent requests
+	s.logger = log.WithContext(ctx)
+
 	artifactDetail, err := s.applier.ApplyLayers(artifactKey, blobKeys)
 	switch {
 	case errors.Is(err, analyzer.ErrUnknownOS):
-		log.Logger.Debug("OS is not detected.")
+		s.logger.Debug("OS is not detected.")
 
 		// If OS is not detected and repositories are detected, we'll try to use repositories as OS.
 		if artifactDetail.Repository != nil {
-			log.Logger.Debugf("Package repository: %s %s", artifactDetail.Repository.Family, artifactDetail.Repository.Release)
-			log.Logger.Debugf("Assuming OS is %s %s.", artifactDetail.Repository.Family, artifactDetail.Repository.Release)
+			s.logger.Debugf("Package repository: %s %s", artifactDetail.Repository.Family, artifactDetail.Repository.Release)
+			s.logger.Debugf("Assuming OS is %s %s.", artifactDetail.Repository.Family, artifactDetail.Repository.Release)
 			artifactDetail.OS = &ftypes.OS{
 				Family: artifactDetail.Repository.Family,
 				Name:   artifactDetail.Repository.Release,
 			}
+		} else if embeddedOS, _ := embeddedSBOMs(artifactDetail.CustomResources); embeddedOS != nil {
+			// Images without os-release, e.g. those built by apko, may have the OS in embedded SBOM files
+			s.logger.Debugf("Assuming OS is %s %s from the embedded SBOM.", embeddedOS.Family, embeddedOS.Name)
+			artifactDetail.OS = embeddedOS
 		}
 	case errors.Is(err, analyzer.ErrNoPkgsDetected):
-		log.Logger.Warn("No OS package is detected. Make sure you haven't deleted any files that contain information about the installed packages.")
-		log.Logger.Warn(`e.g. files under "/lib/apk/db/", "/var/lib/dpkg/" and "/var/lib/rpm"`)
+		s.logger.Warn("No OS package is detected. Make sure you haven't deleted any files that contain information about the installed packages.")
+		s.logger.Warn(`e.g. files under "/lib/apk/db/", "/var/lib/dpkg/" and "/var/lib/rpm"`)
 	case err != nil:
 		return nil, nil, xerrors.Errorf("failed to apply layers: %w", err)
 	}
 
+	artifactDetail.Applications = aggregate(artifactDetail.Applications)
+	artifactDetail.Applications = packaging.Resolve(artifactDetail.Applications)
+	artifactDetail.Applications = gemspec.Resolve(artifactDetail.Applications)
+	artifactDetail.Applications = language.ExcludeDevDeps(artifactDetail.Applications, options.IncludeDevDeps)
+
 	var eosl bool
 	var results types.Results
 
+	// Misconfigurations and secrets ignored by inline comments are moved to the suppressions
+	ignoreRules := inlineIgnoreRules(artifactDetail.CustomResources)
+
+	// The remaining checks are skipped once findings failing the scan are detected,
+	// including those detected so far in the current check.
+	var stopped bool
+	failed := func(current ...types.Result) bool {
+		if !stopped && options.FailFast != nil && options.FailFast(append(slices.Clone(results), current...)) {
+			s.logger.Warn("Findings failing the scan were detected. The remaining checks are skipped due to fail-fast.")
+			stopped = true
+		}
+		return stopped
+	}
+
 	// Scan OS packages and language-specific dependencies
 	if slices.Contains(options.SecurityChecks, types.SecurityCheckVulnerability) {
 		var vulnResults types.Results
-		vulnResults, eosl, err = s.checkVulnerabilities(target, artifactDetail, options)
+		vulnResults, eosl, err = s.checkVulnerabilities(ctx, target, artifactDetail, options, failed)
 		if err != nil {
 			return nil, nil, xerrors.Errorf("failed to detect vulnerabilities: %w", err)
 		}
@@ -110,30 +198,49 @@ func (s Scanner) Scan(ctx context.Context, target, artifactKey string, blobKeys
 	}
 
 	// Scan IaC config files
-	if slices.Contains(options.SecurityChecks, types.SecurityCheckConfig) {
+	if slices.Contains(options.SecurityChecks, types.SecurityCheckConfig) && !failed() {
 		configResults := s.misconfsToResults(artifactDetail.Misconfigurations)
+		ignore.Apply(configResults, ignoreRules)
 		results = append(results, configResults...)
 	}
 
 	// Scan secrets
-	if slices.Contains(options.SecurityChecks, types.SecurityCheckSecret) {
-		secretResults := s.secretsToResults(artifactDetail.Secrets)
+	if slices.Contains(options.SecurityChecks, types.SecurityCheckSecret) && !failed() {
+		secretResults := s.secretsToResults(artifactDetail.Secrets, artifactDetail.CustomResources)
+		ignore.Apply(secretResults, ignoreRules)
 		results = append(results, secretResults...)
 	}
 
+	// Scan licenses
+	if slices.Contains(options.SecurityChecks, types.SecurityCheckLicense) && !failed() {
+		licenseResults := s.scanLicenses(artifactDetail, options)
+		results = append(results, licenseResults...)
+	}
+
+	// Fingerprints, verification results and history of secrets are merged into secret findings above.
+	// Licenses of files are reported as license results, kernel releases are shown in the kernel result,
+	// module streams are used for modular packages, inline ignore rules are applied above and CODEOWNERS below.
+	customResources := lo.Filter(artifactDetail.CustomResources, func(r ftypes.CustomResource, _ int) bool {
+		return r.Type != secret.FingerprintType && r.Type != secret.VerificationType && r.Type != asecret.HistoryType &&
+			r.Type != licensing.FileType && r.Type != kernel.ReleaseType && r.Type != dnf.ModuleType &&
+			r.Type != sbom.EmbeddedType && r.Type != ignore.RuleType && r.Type != tanalyzer.ErrorType &&
+			r.Type != ownership.RulesType
+	})
+
+	// Files which failed to be analyzed with --continue-on-error
+	results = append(results, analysisErrors(artifactDetail.CustomResources)...)
+
+	// CODEOWNERS is parsed only with --codeowners
+	ownership.Assign(results, codeowners(artifactDetail.CustomResources))
+
 	// For WASM plugins and custom analyzers
-	if len(artifactDetail.CustomResources) != 0 {
+	if len(customResources) != 0 {
 		results = append(results, types.Result{
 			Class:           types.ClassCustom,
-			CustomResources: artifactDetail.CustomResources,
+			CustomResources: customResources,
 		})
 	}
 
-	for i := range results {
-		// Fill vulnerability details
-		s.vulnClient.FillInfo(results[i].Vulnerabilities)
-	}
-
 	// Post scanning
 	results, err = post.Scan(ctx, results)
 	if err != nil {
@@ -143,44 +250,69 @@ func (s Scanner) Scan(ctx context.Context, target, artifactKey string, blobKeys
 	return results, artifactDetail.OS, nil
 }
 
-func (s Scanner) checkVulnerabilities(target string, detail ftypes.ArtifactDetail, options types.ScanOptions) (
-	types.Results, bool, error) {
+// checkVulnerabilities detects the vulnerabilities of OS packages and then those of language-specific dependencies.
+// The details such as the severity are filled on detection so that failed can decide with them,
+// and the dependencies are not scanned if the OS packages already fail the scan.
+func (s Scanner) checkVulnerabilities(ctx context.Context, target string, detail ftypes.ArtifactDetail, options types.ScanOptions,
+	failed func(...types.Result) bool) (types.Results, bool, error) {
 	var eosl bool
 	var results types.Results
 
-	if slices.Contains(options.VulnType, types.VulnTypeOS) {
-		result, detectedEosl, err := s.scanOSPkgs(target, detail, options)
-		if err != nil {
+	if slices.Contains(options.VulnType, types.VulnTypeOS) || slices.Contains(options.VulnType, types.VulnTypeKernel) {
+		osResults, detectedEosl, err := s.scanOSPkgs(target, detail, options)
+		if err != nil && options.ContinueOnError && ctx.Err() == nil {
+			s.logger.Warnf("Unable to scan OS packages: %s", err)
+			osResults = types.Results{{
+				Target: fmt.Sprintf("%s (%s %s)", target, detail.OS.Family, detail.OS.Name),
+				Class:  types.ClassOSPkg,
+				Type:   detail.OS.Family,
+				Error:  err.Error(),
+			}}
+		} else if err != nil {
 			return nil, false, xerrors.Errorf("unable to scan OS packages: %w", err)
-		} else if result != nil {
-			results = append(results, *result)
 		}
+		s.fillInfo(osResults)
+		results = append(results, osResults...)
 		eosl = detectedEosl
 	}
 
-	if slices.Contains(options.VulnType, types.VulnTypeLibrary) {
-		libResults, err := s.scanLibrary(detail.Applications, options)
+	if slices.Contains(options.VulnType, types.VulnTypeLibrary) && !failed(results...) {
+		libResults, err := s.scanLibrary(ctx, detail.Applications, options)
 		if err != nil {
 			return nil, false, xerrors.Errorf("failed to scan application libraries: %w", err)
 		}
+		s.fillInfo(libResults)
 		results = append(results, libResults...)
 	}
 
 	return results, eosl, nil
 }
 
+// fillInfo fills the vulnerability details such as the severity and the title
+func (s Scanner) fillInfo(results types.Results) {
+	for i := range results {
+		s.vulnClient.FillInfo(results[i].Vulnerabilities)
+	}
+}
+
+// scanOSPkgs returns the result of OS packages and that of the kernel and firmware packages.
+// Kernel packages are detected with the advisories of the distribution in the same way as the other packages,
+// so that backported fixes are taken into account, and reported separately so that they can be filtered with "--vuln-type".
 func (s Scanner) scanOSPkgs(target string, detail ftypes.ArtifactDetail, options types.ScanOptions) (
-	*types.Result, bool, error) {
+	types.Results, bool, error) {
 	if detail.OS == nil {
-		log.Logger.Debug("Detected OS: unknown")
+		s.logger.Debug("Detected OS: unknown")
 		return nil, false, nil
 	}
-	log.Logger.Infof("Detected OS: %s", detail.OS.Family)
+	s.logger.Infof("Detected OS: %s", detail.OS.Family)
 
-	pkgs := detail.Packages
+	// Packages of the package manager have priority over those listed in embedded SBOM files
+	_, embeddedPkgs := embeddedSBOMs(detail.CustomResources)
+	pkgs := mergePkgs(detail.Packages, embeddedPkgs)
 	if options.ScanRemovedPackages {
 		pkgs = mergePkgs(pkgs, detail.HistoryPackages)
 	}
+	pkgs = dnf.FillModularityLabels(pkgs, enabledModules(detail.CustomResources))
 
 	result, eosl, err := s.detectVulnsInOSPkgs(target, detail.OS.Family, detail.OS.Name, detail.Repository, pkgs)
 	if err != nil {
@@ -189,14 +321,140 @@ func (s Scanner) scanOSPkgs(target string, detail ftypes.ArtifactDetail, options
 		return nil, eosl, nil
 	}
 
-	if options.ListAllPackages {
-		sort.Slice(pkgs, func(i, j int) bool {
-			return strings.Compare(pkgs[i].Name, pkgs[j].Name) <= 0
-		})
-		result.Packages = pkgs
+	var results types.Results
+	osVulns, kernelVulns := splitKernelVulns(result.Vulnerabilities, pkgs)
+	if slices.Contains(options.VulnType, types.VulnTypeOS) {
+		result.Vulnerabilities = osVulns
+		if options.ListAllPackages {
+			sort.Slice(pkgs, func(i, j int) bool {
+				return strings.Compare(pkgs[i].Name, pkgs[j].Name) <= 0
+			})
+			result.Packages = pkgs
+		}
+		results = append(results, *result)
 	}
 
-	return result, eosl, nil
+	if slices.Contains(options.VulnType, types.VulnTypeKernel) {
+		if kernelResult := toKernelResult(detail, pkgs, kernelVulns); kernelResult != nil {
+			results = append(results, *kernelResult)
+		}
+	}
+
+	return results, eosl, nil
+}
+
+// splitKernelVulns splits vulnerabilities into those of the kernel and firmware packages and the others
+func splitKernelVulns(vulns []types.DetectedVulnerability, pkgs []ftypes.Package) ([]types.DetectedVulnerability, []types.DetectedVulnerability) {
+	kernelPkgs := map[string]struct{}{}
+	for _, pkg := range pkgs {
+		if kernel.IsKernelPkg(pkg) {
+			kernelPkgs[pkg.Name] = struct{}{}
+		}
+	}
+
+	var osVulns, kernelVulns []types.DetectedVulnerability
+	for _, vuln := range vulns {
+		if _, ok := kernelPkgs[vuln.PkgName]; ok {
+			kernelVulns = append(kernelVulns, vuln)
+		} else {
+			osVulns = append(osVulns, vuln)
+		}
+	}
+	return osVulns, kernelVulns
+}
+
+// toKernelResult returns the result of the kernel and firmware packages.
+// The releases of installed kernels are shown in the target, e.g. "Linux kernel 5.10.0-18-amd64".
+// Packages are listed in the result of OS packages.
+func toKernelResult(detail ftypes.ArtifactDetail, pkgs []ftypes.Package, vulns []types.DetectedVulnerability) *types.Result {
+	releases := installedKernels(detail.CustomResources)
+	if len(releases) == 0 && !lo.ContainsBy(pkgs, kernel.IsKernelPkg) {
+		return nil
+	}
+
+	target := "Linux kernel"
+	if len(releases) > 0 {
+		log.Logger.Infof("Detected kernel: %s", strings.Join(releases, ", "))
+		target += " " + strings.Join(releases, ", ")
+	}
+	return &types.Result{
+		Target:          target,
+		Vulnerabilities: vulns,
+		Class:           types.ClassKernelPkg,
+		Type:            detail.OS.Family,
+	}
+}
+
+// installedKernels returns the releases of installed kernels stored in custom resources
+func installedKernels(customResources []ftypes.CustomResource) []string {
+	var releases []string
+	for _, r := range customResources {
+		if r.Type != kernel.ReleaseType {
+			continue
+		}
+
+		var release string
+		if err := decodeCustomResource(r, &release); err != nil {
+			log.Logger.Debugf("Unable to decode the kernel release: %s", err)
+			continue
+		}
+		releases = append(releases, release)
+	}
+	if releases = lo.Uniq(releases); len(releases) > 0 {
+		sort.Strings(releases)
+	}
+	return releases
+}
+
+// enabledModules returns the module streams enabled in DNF stored in custom resources
+func enabledModules(customResources []ftypes.CustomResource) []dnf.Module {
+	var modules []dnf.Module
+	for _, r := range customResources {
+		if r.Type != dnf.ModuleType {
+			continue
+		}
+
+		var ms []dnf.Module
+		if err := decodeCustomResource(r, &ms); err != nil {
+			log.Logger.Debugf("Unable to decode DNF modules: %s", err)
+			continue
+		}
+		modules = append(modules, ms...)
+	}
+	return modules
+}
+
+// embeddedSBOMs returns the OS and OS packages listed in SBOM files embedded in the image.
+// Packages are deduplicated by name since each installed package may have its own SBOM file.
+func embeddedSBOMs(customResources []ftypes.CustomResource) (*ftypes.OS, []ftypes.Package) {
+	var found *ftypes.OS
+	var pkgs []ftypes.Package
+	uniqPkgs := map[string]struct{}{}
+	for _, r := range customResources {
+		if r.Type != sbom.EmbeddedType {
+			continue
+		}
+
+		var embedded sbom.Embedded
+		if err := decodeCustomResource(r, &embedded); err != nil {
+			log.Logger.Debugf("Unable to decode the embedded SBOM: %s", err)
+			continue
+		}
+		if found == nil {
+			found = embedded.OS
+		}
+		for _, pkg := range embedded.Packages {
+			if _, ok := uniqPkgs[pkg.Name]; ok {
+				continue
+			}
+			uniqPkgs[pkg.Name] = struct{}{}
+			if pkg.Layer.DiffID == "" {
+				pkg.Layer = r.Layer
+			}
+			pkgs = append(pkgs, pkg)
+		}
+	}
+	return found, pkgs
 }
 
 func (s Scanner) detectVulnsInOSPkgs(target, osFamily, osName string, repo *ftypes.Repository, pkgs []ftypes.Package) (*types.Result, bool, error) {
@@ -220,48 +478,80 @@ func (s Scanner) detectVulnsInOSPkgs(target, osFamily, osName string, repo *ftyp
 	return result, eosl, nil
 }
 
-func (s Scanner) scanLibrary(apps []ftypes.Application, options types.ScanOptions) (types.Results, error) {
-	log.Logger.Infof("Number of language-specific files: %d", len(apps))
+// scanLibrary detects vulnerabilities of the applications concurrently,
+// as monorepos may have hundreds of lock files to be detected independently.
+func (s Scanner) scanLibrary(ctx context.Context, apps []ftypes.Application, options types.ScanOptions) (types.Results, error) {
+	s.logger.Infof("Number of language-specific files: %d", len(apps))
 	if len(apps) == 0 {
 		return nil, nil
 	}
 
-	var results types.Results
+	// Prevent the same log messages from being displayed many times for the same type.
 	printedTypes := map[string]struct{}{}
 	for _, app := range apps {
-		if len(app.Libraries) == 0 {
-			continue
-		}
-
-		// Prevent the same log messages from being displayed many times for the same type.
-		if _, ok := printedTypes[app.Type]; !ok {
-			log.Logger.Infof("Detecting %s vulnerabilities...", app.Type)
+		if _, ok := printedTypes[app.Type]; !ok && len(app.Libraries) > 0 {
+			s.logger.Infof("Detecting %s vulnerabilities...", app.Type)
 			printedTypes[app.Type] = struct{}{}
 		}
+	}
 
-		log.Logger.Debugf("Detecting library vulnerabilities, type: %s, path: %s", app.Type, app.FilePath)
-		vulns, err := library.Detect(app.Type, app.Libraries)
-		if err != nil {
-			return nil, xerrors.Errorf("failed vulnerability detection of libraries: %w", err)
-		}
+	parallel := options.Parallel
+	if parallel <= 0 {
+		parallel = defaultParallel
+	}
 
-		target := app.FilePath
-		if t, ok := pkgTargets[app.Type]; ok && target == "" {
-			// When the file path is empty, we will overwrite it with the pre-defined value.
-			target = t
+	results := make(types.Results, len(apps))
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(parallel)
+	for i, app := range apps {
+		if len(app.Libraries) == 0 {
+			continue
 		}
 
-		libReport := types.Result{
-			Target:          target,
-			Vulnerabilities: vulns,
-			Class:           types.ClassLangPkg,
-			Type:            app.Type,
-		}
-		if options.ListAllPackages {
-			libReport.Packages = app.Libraries
-		}
-		results = append(results, libReport)
+		i, app := i, app
+		g.Go(func() error {
+			target := app.FilePath
+			if t, ok := pkgTargets[app.Type]; ok && target == "" {
+				// When the file path is empty, we will overwrite it with the pre-defined value.
+				target = t
+			}
+
+			s.logger.Debugf("Detecting library vulnerabilities, type: %s, path: %s", app.Type, app.FilePath)
+			var vulns []types.DetectedVulnerability
+			err := timeout.Run(ctx, timeout.PhaseTarget, options.TargetTimeout, func(ctx context.Context) error {
+				var err error
+				vulns, err = library.Detect(ctx, app.Type, app.Libraries)
+				return err
+			})
+			if err != nil && (!options.ContinueOnError || ctx.Err() != nil) {
+				return xerrors.Errorf("failed vulnerability detection of libraries in %s: %w", target, err)
+			}
+
+			libReport := types.Result{
+				Target:          target,
+				Vulnerabilities: vulns,
+				Class:           types.ClassLangPkg,
+				Type:            app.Type,
+			}
+			if err != nil {
+				s.logger.Warnf("Failed vulnerability detection of libraries in %s: %s", target, err)
+				libReport.Error = err.Error()
+			}
+			if options.ListAllPackages {
+				libReport.Packages = app.Libraries
+			}
+			results[i] = libReport
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
 	}
+
+	// Applications without libraries have no result
+	results = lo.Filter(results, func(r types.Result, _ int) bool {
+		return r.Class != ""
+	})
 	sort.Slice(results, func(i, j int) bool {
 		return results[i].Target < results[j].Target
 	})
@@ -269,10 +559,10 @@ func (s Scanner) scanLibrary(apps []ftypes.Application, options types.ScanOption
 }
 
 func (s Scanner) misconfsToResults(misconfs []ftypes.Misconfiguration) types.Results {
-	log.Logger.Infof("Detected config files: %d", len(misconfs))
+	s.logger.Infof("Detected config files: %d", len(misconfs))
 	var results types.Results
 	for _, misconf := range misconfs {
-		log.Logger.Debugf("Scanned config file: %s", misconf.FilePath)
+		s.logger.Debugf("Scanned config file: %s", misconf.FilePath)
 
 		var detected []types.DetectedMisconfiguration
 
@@ -304,20 +594,307 @@ func (s Scanner) misconfsToResults(misconfs []ftypes.Misconfiguration) types.Res
 	return results
 }
 
-func (s Scanner) secretsToResults(secrets []ftypes.Secret) types.Results {
+func (s Scanner) secretsToResults(secrets []ftypes.Secret, customResources []ftypes.CustomResource) types.Results {
+	verifications := secretVerifications(customResources)
+	fingerprints := secretFingerprints(customResources)
+	histories := secretHistories(customResources)
+
 	var results types.Results
 	for _, secret := range secrets {
-		log.Logger.Debugf("Secret file: %s", secret.FilePath)
+		s.logger.Debugf("Secret file: %s", secret.FilePath)
+
+		history := histories[secret.FilePath]
+		delete(histories, secret.FilePath)
+
+		var detected []types.DetectedSecret
+		for _, finding := range secret.Findings {
+			key := secretKey(secret.FilePath, finding.RuleID, finding.StartLine)
+			d := types.DetectedSecret{
+				SecretFinding: finding,
+				Fingerprint:   fingerprints[key],
+				Verification:  verifications[key],
+			}
+
+			// Fill in the commit introducing the secret
+			if h, ok := history[key]; ok {
+				d.History = h.History
+				delete(history, key)
+			}
+			detected = append(detected, d)
+		}
+
+		// Secrets which no longer exist
+		detected = append(detected, sortedSecrets(history)...)
 
 		results = append(results, types.Result{
 			Target:  secret.FilePath,
 			Class:   types.ClassSecret,
-			Secrets: secret.Findings,
+			Secrets: detected,
+		})
+	}
+
+	// Files which no longer have secrets
+	filePaths := lo.Keys(histories)
+	sort.Strings(filePaths)
+	for _, filePath := range filePaths {
+		results = append(results, types.Result{
+			Target:  filePath,
+			Class:   types.ClassSecret,
+			Secrets: sortedSecrets(histories[filePath]),
+		})
+	}
+	return results
+}
+
+func (s Scanner) scanLicenses(detail ftypes.ArtifactDetail, options types.ScanOptions) types.Results {
+	scanner := licensing.NewScanner(options.LicenseCategories)
+
+	var results types.Results
+
+	// License - OS packages
+	var osPkgLicenses []types.DetectedLicense
+	for _, pkg := range detail.Packages {
+		osPkgLicenses = append(osPkgLicenses, pkgLicenses(scanner, pkg)...)
+	}
+	if len(osPkgLicenses) != 0 {
+		results = append(results, types.Result{
+			Target:   "OS Packages",
+			Class:    types.ClassLicense,
+			Licenses: osPkgLicenses,
+		})
+	}
+
+	// License - language-specific packages
+	for _, app := range detail.Applications {
+		var langLicenses []types.DetectedLicense
+		for _, lib := range app.Libraries {
+			langLicenses = append(langLicenses, pkgLicenses(scanner, lib)...)
+		}
+		if len(langLicenses) == 0 {
+			continue
+		}
+
+		target := app.FilePath
+		if t, ok := pkgTargets[app.Type]; ok && target == "" {
+			// When the file path is empty, we will overwrite it with the pre-defined value.
+			target = t
+		}
+		results = append(results, types.Result{
+			Target:   target,
+			Class:    types.ClassLicense,
+			Type:     app.Type,
+			Licenses: langLicenses,
+		})
+	}
+
+	// License - files (--license-full)
+	var fileLicenses []types.DetectedLicense
+	for _, r := range detail.CustomResources {
+		if r.Type != licensing.FileType {
+			continue
+		}
+
+		var findings []licensing.Finding
+		if err := decodeCustomResource(r, &findings); err != nil {
+			s.logger.Debugf("Unable to decode licenses of files: %s", err)
+			continue
+		}
+
+		for _, f := range findings {
+			category, severity := scanner.Scan(f.Name)
+			fileLicenses = append(fileLicenses, types.DetectedLicense{
+				Severity:   severity,
+				Category:   category,
+				FilePath:   r.FilePath,
+				Name:       f.Name,
+				Confidence: f.Confidence,
+				Link:       licensing.Link(f.Name),
+			})
+		}
+	}
+	if len(fileLicenses) != 0 {
+		sort.Slice(fileLicenses, func(i, j int) bool {
+			if fileLicenses[i].FilePath != fileLicenses[j].FilePath {
+				return fileLicenses[i].FilePath < fileLicenses[j].FilePath
+			}
+			return fileLicenses[i].Name < fileLicenses[j].Name
+		})
+		results = append(results, types.Result{
+			Target:   "Loose File License(s)",
+			Class:    types.ClassLicenseFile,
+			Licenses: fileLicenses,
+		})
+	}
+	return results
+}
+
+// pkgLicenses classifies licenses declared in the package metadata.
+// Licenses are normalized into SPDX identifiers, and only the least severe branch of "OR" is reported.
+func pkgLicenses(scanner licensing.Scanner, pkg ftypes.Package) []types.DetectedLicense {
+	var licenses []types.DetectedLicense
+	for _, l := range scanner.Comply(licensing.Normalize(pkg.License)) {
+		category, severity := scanner.Scan(l.Name)
+		licenses = append(licenses, types.DetectedLicense{
+			Severity:   severity,
+			Category:   category,
+			PkgName:    pkg.Name,
+			Name:       l.Name,
+			Confidence: l.Confidence,
+			Link:       licensing.Link(l.Name),
+		})
+	}
+	return licenses
+}
+
+// analysisErrors returns the results of files which failed to be analyzed, stored in custom resources
+func analysisErrors(customResources []ftypes.CustomResource) types.Results {
+	var results types.Results
+	for _, r := range customResources {
+		if r.Type != tanalyzer.ErrorType {
+			continue
+		}
+		results = append(results, types.Result{
+			Target: r.FilePath,
+			Error:  fmt.Sprint(r.Data),
 		})
 	}
 	return results
 }
 
+// codeowners returns the rules of CODEOWNERS taking precedence if there are several
+func codeowners(customResources []ftypes.CustomResource) ownership.Rules {
+	var rules ownership.Rules
+	precedence := len(ownership.Locations)
+	for _, r := range customResources {
+		if r.Type != ownership.RulesType {
+			continue
+		}
+
+		i := slices.Index(ownership.Locations, r.FilePath)
+		if i < 0 || i >= precedence {
+			continue
+		}
+		var fileRules ownership.Rules
+		if err := decodeCustomResource(r, &fileRules); err != nil {
+			log.Logger.Debugf("Unable to decode CODEOWNERS: %s", err)
+			continue
+		}
+		rules, precedence = fileRules, i
+	}
+	return rules
+}
+
+// secretVerifications returns verification statuses of secrets stored in custom resources
+func secretVerifications(customResources []ftypes.CustomResource) map[string]types.SecretVerification {
+	statuses := map[string]types.SecretVerification{}
+	for _, r := range customResources {
+		if r.Type != secret.VerificationType {
+			continue
+		}
+
+		var verifications []secret.Verification
+		if err := decodeCustomResource(r, &verifications); err != nil {
+			log.Logger.Debugf("Unable to decode secret verifications: %s", err)
+			continue
+		}
+
+		for _, v := range verifications {
+			statuses[secretKey(r.FilePath, v.RuleID, v.StartLine)] = v.Status
+		}
+	}
+	return statuses
+}
+
+// secretFingerprints returns fingerprints of secrets stored in custom resources
+func secretFingerprints(customResources []ftypes.CustomResource) map[string]string {
+	fingerprints := map[string]string{}
+	for _, r := range customResources {
+		if r.Type != secret.FingerprintType {
+			continue
+		}
+
+		var fs []secret.FindingFingerprint
+		if err := decodeCustomResource(r, &fs); err != nil {
+			log.Logger.Debugf("Unable to decode secret fingerprints: %s", err)
+			continue
+		}
+
+		for _, f := range fs {
+			fingerprints[secretKey(r.FilePath, f.RuleID, f.StartLine)] = f.Fingerprint
+		}
+	}
+	return fingerprints
+}
+
+// secretHistories returns secrets found in the git history per file
+func secretHistories(customResources []ftypes.CustomResource) map[string]map[string]types.DetectedSecret {
+	histories := map[string]map[string]types.DetectedSecret{}
+	for _, r := range customResources {
+		if r.Type != asecret.HistoryType {
+			continue
+		}
+
+		var secrets []types.DetectedSecret
+		if err := decodeCustomResource(r, &secrets); err != nil {
+			log.Logger.Debugf("Unable to decode secrets in the git history: %s", err)
+			continue
+		}
+
+		history := map[string]types.DetectedSecret{}
+		for _, s := range secrets {
+			history[secretKey(r.FilePath, s.RuleID, s.StartLine)] = s
+		}
+		histories[r.FilePath] = history
+	}
+	return histories
+}
+
+// inlineIgnoreRules returns inline ignore rules stored in custom resources per file
+func inlineIgnoreRules(customResources []ftypes.CustomResource) map[string][]ignore.Rule {
+	rules := map[string][]ignore.Rule{}
+	for _, r := range customResources {
+		if r.Type != ignore.RuleType {
+			continue
+		}
+
+		var fileRules []ignore.Rule
+		if err := decodeCustomResource(r, &fileRules); err != nil {
+			log.Logger.Debugf("Unable to decode inline ignore rules: %s", err)
+			continue
+		}
+		rules[r.FilePath] = append(rules[r.FilePath], fileRules...)
+	}
+	return rules
+}
+
+// decodeCustomResource decodes the data of the custom resource.
+// Data is decoded as a generic value when it is loaded from cache.
+func decodeCustomResource(r ftypes.CustomResource, v interface{}) error {
+	b, err := json.Marshal(r.Data)
+	if err != nil {
+		return xerrors.Errorf("json marshal error: %w", err)
+	}
+	if err = json.Unmarshal(b, v); err != nil {
+		return xerrors.Errorf("json unmarshal error: %w", err)
+	}
+	return nil
+}
+
+func sortedSecrets(secrets map[string]types.DetectedSecret) []types.DetectedSecret {
+	sorted := lo.Values(secrets)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].StartLine != sorted[j].StartLine {
+			return sorted[i].StartLine < sorted[j].StartLine
+		}
+		return sorted[i].RuleID < sorted[j].RuleID
+	})
+	return sorted
+}
+
+func secretKey(filePath, ruleID string, line int) string {
+	return fmt.Sprintf("%s:%s:%d", filePath, ruleID, line)
+}
+
 func toDetectedMisconfiguration(res ftypes.MisconfResult, defaultSeverity dbTypes.Severity,
 	status types.MisconfStatus, layer ftypes.Layer) types.DetectedMisconfiguration {
 
@@ -383,3 +960,21 @@ func mergePkgs(pkgs, pkgsFromCommands []ftypes.Package) []ftypes.Package {
 	}
 	return pkgs
 }
+
+// aggregate merges individual packages detected by Trivy analyzers into an application per type.
+// fanal aggregates packages installed by pip, gem, npm and jar in the same way.
+func aggregate(apps []ftypes.Application) []ftypes.Application {
+	var result []ftypes.Application
+	aggregated := ftypes.Application{Type: types.CondaPkg}
+	for _, app := range apps {
+		if app.Type != aggregated.Type {
+			result = append(result, app)
+			continue
+		}
+		aggregated.Libraries = append(aggregated.Libraries, app.Libraries...)
+	}
+	if len(aggregated.Libraries) > 0 {
+		result = append(result, aggregated)
+	}
+	return result
+}
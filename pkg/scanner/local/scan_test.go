@@ -9,14 +9,24 @@ import (
 	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
 
 	"github.com/aquasecurity/fanal/analyzer"
 	fos "github.com/aquasecurity/fanal/analyzer/os"
 	ftypes "github.com/aquasecurity/fanal/types"
 	"github.com/aquasecurity/trivy-db/pkg/db"
 	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	tanalyzer "github.com/aquasecurity/trivy/pkg/analyzer"
+	asecret "github.com/aquasecurity/trivy/pkg/analyzer/secret"
 	"github.com/aquasecurity/trivy/pkg/dbtest"
 	"github.com/aquasecurity/trivy/pkg/detector/ospkg"
+	"github.com/aquasecurity/trivy/pkg/dnf"
+	"github.com/aquasecurity/trivy/pkg/kernel"
+	"github.com/aquasecurity/trivy/pkg/licensing"
+	"github.com/aquasecurity/trivy/pkg/ownership"
+	"github.com/aquasecurity/trivy/pkg/sbom"
+	"github.com/aquasecurity/trivy/pkg/secret"
 	"github.com/aquasecurity/trivy/pkg/types"
 	"github.com/aquasecurity/trivy/pkg/vulnerability"
 )
@@ -143,6 +153,306 @@ func TestScanner_Scan(t *testing.T) {
 				Eosl:   true,
 			},
 		},
+		{
+			name: "happy path with kernel",
+			args: args{
+				target:   "alpine:latest",
+				layerIDs: []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
+				options: types.ScanOptions{
+					VulnType:       []string{types.VulnTypeOS, types.VulnTypeKernel},
+					SecurityChecks: []string{types.SecurityCheckVulnerability},
+				},
+			},
+			fixtures: []string{"testdata/fixtures/happy.yaml"},
+			applyLayersExpectation: ApplierApplyLayersExpectation{
+				Args: ApplierApplyLayersArgs{
+					BlobIDs: []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
+				},
+				Returns: ApplierApplyLayersReturns{
+					Detail: ftypes.ArtifactDetail{
+						OS: &ftypes.OS{
+							Family: fos.Alpine,
+							Name:   "3.11",
+						},
+						Packages: []ftypes.Package{
+							{
+								Name:       "musl",
+								Version:    "1.2.3",
+								SrcName:    "musl",
+								SrcVersion: "1.2.3",
+							},
+							{
+								Name:       "linux-lts",
+								Version:    "5.15.4-r0",
+								SrcName:    "linux-lts",
+								SrcVersion: "5.15.4-r0",
+							},
+						},
+						CustomResources: []ftypes.CustomResource{
+							{
+								Type:     kernel.ReleaseType,
+								FilePath: "lib/modules/5.15.4-0-lts/modules.builtin",
+								Data:     "5.15.4-0-lts",
+							},
+						},
+					},
+				},
+			},
+			wantResults: types.Results{
+				{
+					Target: "alpine:latest (alpine 3.11)",
+					Class:  types.ClassOSPkg,
+					Type:   fos.Alpine,
+					Vulnerabilities: []types.DetectedVulnerability{
+						{
+							VulnerabilityID:  "CVE-2020-9999",
+							PkgName:          "musl",
+							InstalledVersion: "1.2.3",
+							FixedVersion:     "1.2.4",
+							PrimaryURL:       "https://avd.aquasec.com/nvd/cve-2020-9999",
+							Vulnerability: dbTypes.Vulnerability{
+								Title:       "dos",
+								Description: "dos vulnerability",
+								Severity:    "HIGH",
+							},
+						},
+					},
+				},
+				{
+					Target: "Linux kernel 5.15.4-0-lts",
+					Class:  types.ClassKernelPkg,
+					Type:   fos.Alpine,
+					Vulnerabilities: []types.DetectedVulnerability{
+						{
+							VulnerabilityID:  "CVE-2022-0001",
+							PkgName:          "linux-lts",
+							InstalledVersion: "5.15.4-r0",
+							FixedVersion:     "5.15.5-r0",
+							PrimaryURL:       "https://avd.aquasec.com/nvd/cve-2022-0001",
+							Vulnerability: dbTypes.Vulnerability{
+								Title:       "privilege escalation",
+								Description: "privilege escalation vulnerability",
+								Severity:    "HIGH",
+							},
+						},
+					},
+				},
+			},
+			wantOS: &ftypes.OS{
+				Family: "alpine",
+				Name:   "3.11",
+				Eosl:   true,
+			},
+		},
+		{
+			name: "happy path with DNF modules",
+			args: args{
+				target:   "almalinux:8",
+				layerIDs: []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
+				options: types.ScanOptions{
+					VulnType:       []string{types.VulnTypeOS},
+					SecurityChecks: []string{types.SecurityCheckVulnerability},
+				},
+			},
+			fixtures: []string{"testdata/fixtures/modular.yaml"},
+			applyLayersExpectation: ApplierApplyLayersExpectation{
+				Args: ApplierApplyLayersArgs{
+					BlobIDs: []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
+				},
+				Returns: ApplierApplyLayersReturns{
+					Detail: ftypes.ArtifactDetail{
+						OS: &ftypes.OS{
+							Family: fos.Alma,
+							Name:   "8.6",
+						},
+						Packages: []ftypes.Package{
+							{
+								// The modularity label is missing in the RPM header
+								Name:       "nginx",
+								Epoch:      1,
+								Version:    "1.14.1",
+								Release:    "8.module_el8.3.0+2165+af250afe.alma",
+								SrcName:    "nginx",
+								SrcEpoch:   1,
+								SrcVersion: "1.14.1",
+								SrcRelease: "8.module_el8.3.0+2165+af250afe.alma",
+							},
+						},
+						CustomResources: []ftypes.CustomResource{
+							{
+								Type:     dnf.ModuleType,
+								FilePath: "etc/dnf/modules.d/nginx.module",
+								Data: []dnf.Module{
+									{
+										Name:   "nginx",
+										Stream: "1.14",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantResults: types.Results{
+				{
+					Target: "almalinux:8 (alma 8.6)",
+					Class:  types.ClassOSPkg,
+					Type:   fos.Alma,
+					Vulnerabilities: []types.DetectedVulnerability{
+						{
+							VulnerabilityID:  "CVE-2019-9511",
+							PkgName:          "nginx",
+							InstalledVersion: "1:1.14.1-8.module_el8.3.0+2165+af250afe.alma",
+							FixedVersion:     "1:1.14.1-9.module_el8.3.0+2165+af250afe.alma",
+							PrimaryURL:       "https://avd.aquasec.com/nvd/cve-2019-9511",
+							Vulnerability: dbTypes.Vulnerability{
+								Title:       "data dribble",
+								Description: "HTTP/2 data dribble",
+								Severity:    "HIGH",
+							},
+						},
+					},
+				},
+			},
+			wantOS: &ftypes.OS{
+				Family: fos.Alma,
+				Name:   "8.6",
+			},
+		},
+		{
+			name: "happy path with embedded SBOM",
+			args: args{
+				target:   "apko:latest",
+				layerIDs: []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
+				options: types.ScanOptions{
+					VulnType:       []string{types.VulnTypeOS},
+					SecurityChecks: []string{types.SecurityCheckVulnerability},
+				},
+			},
+			fixtures: []string{"testdata/fixtures/happy.yaml"},
+			applyLayersExpectation: ApplierApplyLayersExpectation{
+				Args: ApplierApplyLayersArgs{
+					BlobIDs: []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
+				},
+				Returns: ApplierApplyLayersReturns{
+					Detail: ftypes.ArtifactDetail{
+						CustomResources: []ftypes.CustomResource{
+							{
+								Type:     sbom.EmbeddedType,
+								FilePath: "var/lib/db/sbom/musl-1.2.3.spdx.json",
+								Layer: ftypes.Layer{
+									DiffID: "sha256:ebf12965380b39889c99a9c02e82ba465f887b45975b6e389d42e9e6a3857888",
+								},
+								Data: sbom.Embedded{
+									OS: &ftypes.OS{
+										Family: fos.Alpine,
+										Name:   "3.11",
+									},
+									Packages: []ftypes.Package{
+										{
+											Name:       "musl",
+											Version:    "1.2.3",
+											SrcName:    "musl",
+											SrcVersion: "1.2.3",
+										},
+									},
+								},
+							},
+							{
+								// Packages listed in multiple SBOM files are reported once
+								Type:     sbom.EmbeddedType,
+								FilePath: "var/lib/db/sbom/musl-utils-1.2.3.spdx.json",
+								Data: sbom.Embedded{
+									Packages: []ftypes.Package{
+										{
+											Name:       "musl",
+											Version:    "1.2.3",
+											SrcName:    "musl",
+											SrcVersion: "1.2.3",
+										},
+									},
+								},
+							},
+						},
+					},
+					Err: analyzer.ErrUnknownOS,
+				},
+			},
+			wantResults: types.Results{
+				{
+					Target: "apko:latest (alpine 3.11)",
+					Class:  types.ClassOSPkg,
+					Type:   fos.Alpine,
+					Vulnerabilities: []types.DetectedVulnerability{
+						{
+							VulnerabilityID:  "CVE-2020-9999",
+							PkgName:          "musl",
+							InstalledVersion: "1.2.3",
+							FixedVersion:     "1.2.4",
+							Layer: ftypes.Layer{
+								DiffID: "sha256:ebf12965380b39889c99a9c02e82ba465f887b45975b6e389d42e9e6a3857888",
+							},
+							PrimaryURL: "https://avd.aquasec.com/nvd/cve-2020-9999",
+							Vulnerability: dbTypes.Vulnerability{
+								Title:       "dos",
+								Description: "dos vulnerability",
+								Severity:    "HIGH",
+							},
+						},
+					},
+				},
+			},
+			wantOS: &ftypes.OS{
+				Family: fos.Alpine,
+				Name:   "3.11",
+				Eosl:   true,
+			},
+		},
+		{
+			name: "happy path without kernel",
+			args: args{
+				target:   "alpine:latest",
+				layerIDs: []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
+				options: types.ScanOptions{
+					VulnType:       []string{types.VulnTypeOS},
+					SecurityChecks: []string{types.SecurityCheckVulnerability},
+				},
+			},
+			fixtures: []string{"testdata/fixtures/happy.yaml"},
+			applyLayersExpectation: ApplierApplyLayersExpectation{
+				Args: ApplierApplyLayersArgs{
+					BlobIDs: []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
+				},
+				Returns: ApplierApplyLayersReturns{
+					Detail: ftypes.ArtifactDetail{
+						OS: &ftypes.OS{
+							Family: fos.Alpine,
+							Name:   "3.11",
+						},
+						Packages: []ftypes.Package{
+							{
+								Name:       "linux-lts",
+								Version:    "5.15.4-r0",
+								SrcName:    "linux-lts",
+								SrcVersion: "5.15.4-r0",
+							},
+						},
+					},
+				},
+			},
+			wantResults: types.Results{
+				{
+					Target: "alpine:latest (alpine 3.11)",
+					Class:  types.ClassOSPkg,
+					Type:   fos.Alpine,
+				},
+			},
+			wantOS: &ftypes.OS{
+				Family: "alpine",
+				Name:   "3.11",
+				Eosl:   true,
+			},
+		},
 		{
 			name: "happy path with list all packages",
 			args: args{
@@ -645,12 +955,14 @@ func TestScanner_Scan(t *testing.T) {
 			},
 		},
 		{
-			name: "happy path with misconfigurations",
+			name: "happy path with sequential library detection",
 			args: args{
-				target:   "/app/configs",
+				target:   "alpine:latest",
 				layerIDs: []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
 				options: types.ScanOptions{
-					SecurityChecks: []string{types.SecurityCheckConfig},
+					VulnType:       []string{types.VulnTypeLibrary},
+					SecurityChecks: []string{types.SecurityCheckVulnerability},
+					Parallel:       1,
 				},
 			},
 			fixtures: []string{"testdata/fixtures/happy.yaml"},
@@ -660,22 +972,131 @@ func TestScanner_Scan(t *testing.T) {
 				},
 				Returns: ApplierApplyLayersReturns{
 					Detail: ftypes.ArtifactDetail{
-						Misconfigurations: []ftypes.Misconfiguration{
+						OS: &ftypes.OS{
+							Family: "alpine",
+							Name:   "3.11",
+						},
+						Packages: []ftypes.Package{
 							{
-								FileType: ftypes.Kubernetes,
-								FilePath: "/app/configs/pod.yaml",
-								Warnings: []ftypes.MisconfResult{
+								Name:       "musl",
+								Version:    "1.2.3",
+								SrcName:    "musl",
+								SrcVersion: "1.2.3",
+							},
+						},
+						Applications: []ftypes.Application{
+							{
+								Type:     "composer",
+								FilePath: "/app/composer-lock.json",
+								Libraries: []ftypes.Package{
 									{
-										Namespace: "main.kubernetes.id300",
-										PolicyMetadata: ftypes.PolicyMetadata{
-											ID:       "ID300",
-											Type:     "Kubernetes Security Check",
-											Title:    "Bad Deployment",
-											Severity: "DUMMY",
+										Name:    "laravel/framework",
+										Version: "6.0.0",
+										Layer: ftypes.Layer{
+											DiffID: "sha256:9922bc15eeefe1637b803ef2106f178152ce19a391f24aec838cbe2e48e73303",
 										},
 									},
 								},
-								Exceptions: ftypes.MisconfResults{
+							},
+							{
+								Type:     "bundler",
+								FilePath: "/app/Gemfile.lock",
+								Libraries: []ftypes.Package{
+									{
+										Name:    "rails",
+										Version: "4.0.2",
+										Layer: ftypes.Layer{
+											DiffID: "sha256:5cb2a5009179b1e78ecfef81a19756328bb266456cf9a9dbbcf9af8b83b735f0",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantResults: types.Results{
+				{
+					Target: "/app/Gemfile.lock",
+					Vulnerabilities: []types.DetectedVulnerability{
+						{
+							VulnerabilityID:  "CVE-2014-0081",
+							PkgName:          "rails",
+							InstalledVersion: "4.0.2",
+							FixedVersion:     "4.0.3, 3.2.17",
+							Layer: ftypes.Layer{
+								DiffID: "sha256:5cb2a5009179b1e78ecfef81a19756328bb266456cf9a9dbbcf9af8b83b735f0",
+							},
+							PrimaryURL: "https://avd.aquasec.com/nvd/cve-2014-0081",
+							Vulnerability: dbTypes.Vulnerability{
+								Title:       "xss",
+								Description: "xss vulnerability",
+								Severity:    "MEDIUM",
+								References: []string{
+									"http://example.com",
+								},
+								LastModifiedDate: lo.ToPtr(time.Date(2020, 2, 1, 1, 1, 0, 0, time.UTC)),
+								PublishedDate:    lo.ToPtr(time.Date(2020, 1, 1, 1, 1, 0, 0, time.UTC)),
+							},
+						},
+					},
+					Class: types.ClassLangPkg,
+					Type:  ftypes.Bundler,
+				},
+				{
+					Target: "/app/composer-lock.json",
+					Vulnerabilities: []types.DetectedVulnerability{
+						{
+							VulnerabilityID:  "CVE-2021-21263",
+							PkgName:          "laravel/framework",
+							InstalledVersion: "6.0.0",
+							FixedVersion:     "8.22.1, 7.30.3, 6.20.12",
+							Layer: ftypes.Layer{
+								DiffID: "sha256:9922bc15eeefe1637b803ef2106f178152ce19a391f24aec838cbe2e48e73303",
+							},
+						},
+					},
+					Class: types.ClassLangPkg,
+					Type:  ftypes.Composer,
+				},
+			},
+			wantOS: &ftypes.OS{
+				Family: "alpine",
+				Name:   "3.11",
+			},
+		},
+		{
+			name: "happy path with misconfigurations",
+			args: args{
+				target:   "/app/configs",
+				layerIDs: []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
+				options: types.ScanOptions{
+					SecurityChecks: []string{types.SecurityCheckConfig},
+				},
+			},
+			fixtures: []string{"testdata/fixtures/happy.yaml"},
+			applyLayersExpectation: ApplierApplyLayersExpectation{
+				Args: ApplierApplyLayersArgs{
+					BlobIDs: []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
+				},
+				Returns: ApplierApplyLayersReturns{
+					Detail: ftypes.ArtifactDetail{
+						Misconfigurations: []ftypes.Misconfiguration{
+							{
+								FileType: ftypes.Kubernetes,
+								FilePath: "/app/configs/pod.yaml",
+								Warnings: []ftypes.MisconfResult{
+									{
+										Namespace: "main.kubernetes.id300",
+										PolicyMetadata: ftypes.PolicyMetadata{
+											ID:       "ID300",
+											Type:     "Kubernetes Security Check",
+											Title:    "Bad Deployment",
+											Severity: "DUMMY",
+										},
+									},
+								},
+								Exceptions: ftypes.MisconfResults{
 									{
 										Namespace: "main.kubernetes.id100",
 										PolicyMetadata: ftypes.PolicyMetadata{
@@ -794,13 +1215,14 @@ func TestScanner_Scan(t *testing.T) {
 			},
 		},
 		{
-			name: "sad path: ApplyLayers returns an error",
+			name: "fail fast with library vulnerabilities and severities",
 			args: args{
-				target:   "alpine:latest",
+				target:   "/app",
 				layerIDs: []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
 				options: types.ScanOptions{
-					VulnType:       []string{types.VulnTypeOS, types.VulnTypeLibrary},
-					SecurityChecks: []string{types.SecurityCheckVulnerability},
+					VulnType:       []string{types.VulnTypeLibrary},
+					SecurityChecks: []string{types.SecurityCheckVulnerability, types.SecurityCheckSecret},
+					FailFast:       failOnSeverities(dbTypes.SeverityMedium),
 				},
 			},
 			fixtures: []string{"testdata/fixtures/happy.yaml"},
@@ -809,22 +1231,75 @@ func TestScanner_Scan(t *testing.T) {
 					BlobIDs: []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
 				},
 				Returns: ApplierApplyLayersReturns{
-					Err: errors.New("error"),
+					Detail: ftypes.ArtifactDetail{
+						Applications: []ftypes.Application{
+							{
+								Type:     ftypes.Bundler,
+								FilePath: "/app/Gemfile.lock",
+								Libraries: []ftypes.Package{
+									{
+										Name:    "rails",
+										Version: "4.0.2",
+									},
+								},
+							},
+						},
+						Secrets: []ftypes.Secret{
+							{
+								FilePath: "config.env",
+								Findings: []ftypes.SecretFinding{
+									{
+										RuleID:    "github-pat",
+										Severity:  "CRITICAL",
+										StartLine: 2,
+										EndLine:   2,
+										Match:     "GITHUB_TOKEN=*****",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantResults: types.Results{
+				{
+					Target: "/app/Gemfile.lock",
+					Class:  types.ClassLangPkg,
+					Type:   ftypes.Bundler,
+					Vulnerabilities: []types.DetectedVulnerability{
+						{
+							VulnerabilityID:  "CVE-2014-0081",
+							PkgName:          "rails",
+							InstalledVersion: "4.0.2",
+							FixedVersion:     "4.0.3, 3.2.17",
+							PrimaryURL:       "https://avd.aquasec.com/nvd/cve-2014-0081",
+							Vulnerability: dbTypes.Vulnerability{
+								Title:       "xss",
+								Description: "xss vulnerability",
+								Severity:    "MEDIUM",
+								References: []string{
+									"http://example.com",
+								},
+								LastModifiedDate: lo.ToPtr(time.Date(2020, 2, 1, 1, 1, 0, 0, time.UTC)),
+								PublishedDate:    lo.ToPtr(time.Date(2020, 1, 1, 1, 1, 0, 0, time.UTC)),
+							},
+						},
+					},
 				},
 			},
-			wantErr: "failed to apply layers",
 		},
 		{
-			name: "sad path: library.Detect returns an error",
+			name: "fail fast with OS vulnerabilities skips libraries",
 			args: args{
 				target:   "alpine:latest",
 				layerIDs: []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
 				options: types.ScanOptions{
-					VulnType:       []string{types.VulnTypeLibrary},
+					VulnType:       []string{types.VulnTypeOS, types.VulnTypeLibrary},
 					SecurityChecks: []string{types.SecurityCheckVulnerability},
+					FailFast:       failOnSeverities(dbTypes.SeverityHigh, dbTypes.SeverityCritical),
 				},
 			},
-			fixtures: []string{"testdata/fixtures/sad.yaml"},
+			fixtures: []string{"testdata/fixtures/happy.yaml"},
 			applyLayersExpectation: ApplierApplyLayersExpectation{
 				Args: ApplierApplyLayersArgs{
 					BlobIDs: []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
@@ -832,55 +1307,628 @@ func TestScanner_Scan(t *testing.T) {
 				Returns: ApplierApplyLayersReturns{
 					Detail: ftypes.ArtifactDetail{
 						OS: &ftypes.OS{
-							Family: "alpine",
+							Family: fos.Alpine,
 							Name:   "3.11",
 						},
 						Packages: []ftypes.Package{
 							{
-								Name:    "musl",
-								Version: "1.2.3",
-								Layer: ftypes.Layer{
-									DiffID: "sha256:ebf12965380b39889c99a9c02e82ba465f887b45975b6e389d42e9e6a3857888",
-								},
+								Name:       "musl",
+								Version:    "1.2.3",
+								SrcName:    "musl",
+								SrcVersion: "1.2.3",
 							},
 						},
 						Applications: []ftypes.Application{
 							{
-								Type:     "bundler",
+								Type:     ftypes.Bundler,
 								FilePath: "/app/Gemfile.lock",
 								Libraries: []ftypes.Package{
 									{
 										Name:    "rails",
-										Version: "6.0",
-										Layer: ftypes.Layer{
-											DiffID: "sha256:9bdb2c849099a99c8ab35f6fd7469c623635e8f4479a0a5a3df61e22bae509f6",
+										Version: "4.0.2",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantResults: types.Results{
+				{
+					Target: "alpine:latest (alpine 3.11)",
+					Class:  types.ClassOSPkg,
+					Type:   fos.Alpine,
+					Vulnerabilities: []types.DetectedVulnerability{
+						{
+							VulnerabilityID:  "CVE-2020-9999",
+							PkgName:          "musl",
+							InstalledVersion: "1.2.3",
+							FixedVersion:     "1.2.4",
+							PrimaryURL:       "https://avd.aquasec.com/nvd/cve-2020-9999",
+							Vulnerability: dbTypes.Vulnerability{
+								Title:       "dos",
+								Description: "dos vulnerability",
+								Severity:    "HIGH",
+							},
+						},
+					},
+				},
+			},
+			wantOS: &ftypes.OS{
+				Family: "alpine",
+				Name:   "3.11",
+				Eosl:   true,
+			},
+		},
+		{
+			name: "fail fast",
+			args: args{
+				target:   "/app",
+				layerIDs: []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
+				options: types.ScanOptions{
+					SecurityChecks: []string{types.SecurityCheckConfig, types.SecurityCheckSecret},
+					FailFast: func(results types.Results) bool {
+						return results.Failed()
+					},
+				},
+			},
+			applyLayersExpectation: ApplierApplyLayersExpectation{
+				Args: ApplierApplyLayersArgs{
+					BlobIDs: []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
+				},
+				Returns: ApplierApplyLayersReturns{
+					Detail: ftypes.ArtifactDetail{
+						Misconfigurations: []ftypes.Misconfiguration{
+							{
+								FileType: ftypes.Dockerfile,
+								FilePath: "Dockerfile",
+								Failures: ftypes.MisconfResults{
+									{
+										Namespace: "builtin.dockerfile.DS002",
+										Message:   "Last USER command in Dockerfile should not be 'root'",
+										PolicyMetadata: ftypes.PolicyMetadata{
+											ID:       "DS002",
+											Type:     "Dockerfile Security Check",
+											Title:    "Image user should not be 'root'",
+											Severity: "HIGH",
 										},
 									},
 								},
 							},
 						},
+						Secrets: []ftypes.Secret{
+							{
+								FilePath: "config.env",
+								Findings: []ftypes.SecretFinding{
+									{
+										RuleID:    "github-pat",
+										Severity:  "CRITICAL",
+										StartLine: 2,
+										EndLine:   2,
+										Match:     "GITHUB_TOKEN=*****",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantResults: types.Results{
+				{
+					Target: "Dockerfile",
+					Class:  types.ClassConfig,
+					Type:   ftypes.Dockerfile,
+					Misconfigurations: []types.DetectedMisconfiguration{
+						{
+							Type:       "Dockerfile Security Check",
+							ID:         "DS002",
+							Title:      "Image user should not be 'root'",
+							Message:    "Last USER command in Dockerfile should not be 'root'",
+							Namespace:  "builtin.dockerfile.DS002",
+							Severity:   "HIGH",
+							PrimaryURL: "https://avd.aquasec.com/misconfig/ds002",
+							References: []string{
+								"https://avd.aquasec.com/misconfig/ds002",
+							},
+							Status: types.StatusFailure,
+						},
 					},
 				},
 			},
-			wantErr: "failed to scan application libraries",
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			_ = dbtest.InitDB(t, tt.fixtures)
-			defer db.Close()
-
-			applier := new(MockApplier)
-			applier.ApplyApplyLayersExpectation(tt.applyLayersExpectation)
-
-			s := NewScanner(applier, ospkg.Detector{}, vulnerability.NewClient(db.Config{}))
-			gotResults, gotOS, err := s.Scan(context.Background(), tt.args.target, "", tt.args.layerIDs, tt.args.options)
-			if tt.wantErr != "" {
-				require.NotNil(t, err, tt.name)
-				require.Contains(t, err.Error(), tt.wantErr, tt.name)
-				return
-			}
+		{
+			name: "happy path with secrets",
+			args: args{
+				target:   "/app",
+				layerIDs: []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
+				options: types.ScanOptions{
+					SecurityChecks: []string{types.SecurityCheckSecret},
+				},
+			},
+			applyLayersExpectation: ApplierApplyLayersExpectation{
+				Args: ApplierApplyLayersArgs{
+					BlobIDs: []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
+				},
+				Returns: ApplierApplyLayersReturns{
+					Detail: ftypes.ArtifactDetail{
+						Secrets: []ftypes.Secret{
+							{
+								FilePath: "config.env",
+								Findings: []ftypes.SecretFinding{
+									{
+										RuleID:    "github-pat",
+										Severity:  "CRITICAL",
+										StartLine: 2,
+										EndLine:   2,
+										Match:     "GITHUB_TOKEN=*****",
+									},
+								},
+							},
+						},
+						CustomResources: []ftypes.CustomResource{
+							{
+								Type:     secret.FingerprintType,
+								FilePath: "config.env",
+								Data: []secret.FindingFingerprint{
+									{
+										RuleID:      "github-pat",
+										StartLine:   2,
+										Fingerprint: "sha256:3c9f1b7e2d4a6c8e0f1a3b5d7c9e1f2a4b6d8c0e1f3a5b7d9c1e3f5a7b9d1c3e",
+									},
+								},
+							},
+							{
+								Type:     secret.VerificationType,
+								FilePath: "config.env",
+								Data: []secret.Verification{
+									{
+										RuleID:    "github-pat",
+										StartLine: 2,
+										Status:    types.SecretVerified,
+									},
+								},
+							},
+							{
+								Type:     asecret.HistoryType,
+								FilePath: "config.env",
+								Data: []types.DetectedSecret{
+									{
+										SecretFinding: ftypes.SecretFinding{
+											RuleID:    "github-pat",
+											Severity:  "CRITICAL",
+											StartLine: 2,
+											EndLine:   2,
+											Match:     "GITHUB_TOKEN=*****",
+										},
+										History: &types.SecretHistory{
+											Commit:       "7ea4b2b0b9d2e5b5b6d1c7f8f9a0b1c2d3e4f5a6",
+											ExistsAtHead: true,
+										},
+									},
+								},
+							},
+							{
+								Type:     asecret.HistoryType,
+								FilePath: "old.env",
+								Data: []types.DetectedSecret{
+									{
+										SecretFinding: ftypes.SecretFinding{
+											RuleID:    "github-pat",
+											Severity:  "CRITICAL",
+											StartLine: 1,
+											EndLine:   1,
+											Match:     "GITHUB_TOKEN=*****",
+										},
+										History: &types.SecretHistory{
+											Commit: "1c5a9d3e7f2b4a6c8e0d2f4a6b8c0e2d4f6a8b0c",
+										},
+									},
+								},
+							},
+							{
+								Type:     "wasm",
+								FilePath: "foo.txt",
+								Data:     "bar",
+							},
+						},
+					},
+				},
+			},
+			wantResults: types.Results{
+				{
+					Target: "config.env",
+					Class:  types.ClassSecret,
+					Secrets: []types.DetectedSecret{
+						{
+							SecretFinding: ftypes.SecretFinding{
+								RuleID:    "github-pat",
+								Severity:  "CRITICAL",
+								StartLine: 2,
+								EndLine:   2,
+								Match:     "GITHUB_TOKEN=*****",
+							},
+							Fingerprint:  "sha256:3c9f1b7e2d4a6c8e0f1a3b5d7c9e1f2a4b6d8c0e1f3a5b7d9c1e3f5a7b9d1c3e",
+							Verification: types.SecretVerified,
+							History: &types.SecretHistory{
+								Commit:       "7ea4b2b0b9d2e5b5b6d1c7f8f9a0b1c2d3e4f5a6",
+								ExistsAtHead: true,
+							},
+						},
+					},
+				},
+				{
+					Target: "old.env",
+					Class:  types.ClassSecret,
+					Secrets: []types.DetectedSecret{
+						{
+							SecretFinding: ftypes.SecretFinding{
+								RuleID:    "github-pat",
+								Severity:  "CRITICAL",
+								StartLine: 1,
+								EndLine:   1,
+								Match:     "GITHUB_TOKEN=*****",
+							},
+							History: &types.SecretHistory{
+								Commit: "1c5a9d3e7f2b4a6c8e0d2f4a6b8c0e2d4f6a8b0c",
+							},
+						},
+					},
+				},
+				{
+					Class: types.ClassCustom,
+					CustomResources: []ftypes.CustomResource{
+						{
+							Type:     "wasm",
+							FilePath: "foo.txt",
+							Data:     "bar",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "happy path with licenses",
+			args: args{
+				target:   "/app",
+				layerIDs: []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
+				options: types.ScanOptions{
+					SecurityChecks: []string{types.SecurityCheckLicense},
+				},
+			},
+			applyLayersExpectation: ApplierApplyLayersExpectation{
+				Args: ApplierApplyLayersArgs{
+					BlobIDs: []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
+				},
+				Returns: ApplierApplyLayersReturns{
+					Detail: ftypes.ArtifactDetail{
+						OS: &ftypes.OS{
+							Family: fos.Alpine,
+							Name:   "3.11",
+						},
+						Packages: []ftypes.Package{
+							{
+								Name:    "musl",
+								Version: "1.2.3",
+								License: "MIT",
+							},
+							{
+								Name:    "bash",
+								Version: "5.1.16",
+								License: "GPLv3+",
+							},
+							{
+								Name:    "ca-certificates",
+								Version: "20211220",
+							},
+						},
+						Applications: []ftypes.Application{
+							{
+								Type:     ftypes.Npm,
+								FilePath: "/app/package-lock.json",
+								Libraries: []ftypes.Package{
+									{
+										Name:    "lodash",
+										Version: "4.17.21",
+										License: "MIT OR Apache-2.0",
+									},
+									{
+										Name:    "dual",
+										Version: "1.0.0",
+										License: "GPL-3.0 OR MIT",
+									},
+								},
+							},
+							{
+								Type:     ftypes.Cargo,
+								FilePath: "/app/Cargo.lock",
+								Libraries: []ftypes.Package{
+									{
+										Name:    "serde",
+										Version: "1.0.136",
+									},
+								},
+							},
+						},
+						CustomResources: []ftypes.CustomResource{
+							{
+								Type:     licensing.FileType,
+								FilePath: "LICENSE",
+								Data: []licensing.Finding{
+									{
+										Name:       "AGPL-3.0",
+										Confidence: 0.9,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantResults: types.Results{
+				{
+					Target: "OS Packages",
+					Class:  types.ClassLicense,
+					Licenses: []types.DetectedLicense{
+						{
+							Severity:   "LOW",
+							Category:   types.CategoryNotice,
+							PkgName:    "musl",
+							Name:       "MIT",
+							Confidence: 1,
+							Link:       "https://spdx.org/licenses/MIT.html",
+						},
+						{
+							Severity:   "HIGH",
+							Category:   types.CategoryRestricted,
+							PkgName:    "bash",
+							Name:       "GPL-3.0-or-later",
+							Confidence: 0.9,
+							Link:       "https://spdx.org/licenses/GPL-3.0-or-later.html",
+						},
+					},
+				},
+				{
+					Target: "/app/package-lock.json",
+					Class:  types.ClassLicense,
+					Type:   ftypes.Npm,
+					Licenses: []types.DetectedLicense{
+						{
+							Severity:   "LOW",
+							Category:   types.CategoryNotice,
+							PkgName:    "lodash",
+							Name:       "MIT",
+							Confidence: 1,
+							Link:       "https://spdx.org/licenses/MIT.html",
+						},
+						{
+							Severity:   "LOW",
+							Category:   types.CategoryNotice,
+							PkgName:    "dual",
+							Name:       "MIT",
+							Confidence: 1,
+							Link:       "https://spdx.org/licenses/MIT.html",
+						},
+					},
+				},
+				{
+					Target: "Loose File License(s)",
+					Class:  types.ClassLicenseFile,
+					Licenses: []types.DetectedLicense{
+						{
+							Severity:   "CRITICAL",
+							Category:   types.CategoryForbidden,
+							FilePath:   "LICENSE",
+							Name:       "AGPL-3.0",
+							Confidence: 0.9,
+							Link:       "https://spdx.org/licenses/AGPL-3.0.html",
+						},
+					},
+				},
+			},
+			wantOS: &ftypes.OS{
+				Family: fos.Alpine,
+				Name:   "3.11",
+			},
+		},
+		{
+			name: "sad path: ApplyLayers returns an error",
+			args: args{
+				target:   "alpine:latest",
+				layerIDs: []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
+				options: types.ScanOptions{
+					VulnType:       []string{types.VulnTypeOS, types.VulnTypeLibrary},
+					SecurityChecks: []string{types.SecurityCheckVulnerability},
+				},
+			},
+			fixtures: []string{"testdata/fixtures/happy.yaml"},
+			applyLayersExpectation: ApplierApplyLayersExpectation{
+				Args: ApplierApplyLayersArgs{
+					BlobIDs: []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
+				},
+				Returns: ApplierApplyLayersReturns{
+					Err: errors.New("error"),
+				},
+			},
+			wantErr: "failed to apply layers",
+		},
+		{
+			name: "sad path: library.Detect returns an error",
+			args: args{
+				target:   "alpine:latest",
+				layerIDs: []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
+				options: types.ScanOptions{
+					VulnType:       []string{types.VulnTypeLibrary},
+					SecurityChecks: []string{types.SecurityCheckVulnerability},
+				},
+			},
+			fixtures: []string{"testdata/fixtures/sad.yaml"},
+			applyLayersExpectation: ApplierApplyLayersExpectation{
+				Args: ApplierApplyLayersArgs{
+					BlobIDs: []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
+				},
+				Returns: ApplierApplyLayersReturns{
+					Detail: ftypes.ArtifactDetail{
+						OS: &ftypes.OS{
+							Family: "alpine",
+							Name:   "3.11",
+						},
+						Packages: []ftypes.Package{
+							{
+								Name:    "musl",
+								Version: "1.2.3",
+								Layer: ftypes.Layer{
+									DiffID: "sha256:ebf12965380b39889c99a9c02e82ba465f887b45975b6e389d42e9e6a3857888",
+								},
+							},
+						},
+						Applications: []ftypes.Application{
+							{
+								Type:     "bundler",
+								FilePath: "/app/Gemfile.lock",
+								Libraries: []ftypes.Package{
+									{
+										Name:    "rails",
+										Version: "6.0",
+										Layer: ftypes.Layer{
+											DiffID: "sha256:9bdb2c849099a99c8ab35f6fd7469c623635e8f4479a0a5a3df61e22bae509f6",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: "failed to scan application libraries",
+		},
+		{
+			name: "happy path with CODEOWNERS",
+			args: args{
+				target:   "/app",
+				layerIDs: []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
+				options: types.ScanOptions{
+					SecurityChecks: []string{types.SecurityCheckSecret},
+				},
+			},
+			applyLayersExpectation: ApplierApplyLayersExpectation{
+				Args: ApplierApplyLayersArgs{
+					BlobIDs: []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
+				},
+				Returns: ApplierApplyLayersReturns{
+					Detail: ftypes.ArtifactDetail{
+						Secrets: []ftypes.Secret{
+							{
+								FilePath: "apps/payments/config.env",
+								Findings: []ftypes.SecretFinding{
+									{
+										RuleID:    "github-pat",
+										Severity:  "CRITICAL",
+										StartLine: 2,
+										EndLine:   2,
+										Match:     "GITHUB_TOKEN=*****",
+									},
+								},
+							},
+						},
+						CustomResources: []ftypes.CustomResource{
+							ownership.Resource("docs/CODEOWNERS", ownership.Rules{
+								{Pattern: "*", Owners: []string{"@acme/docs"}},
+							}),
+							ownership.Resource(".github/CODEOWNERS", ownership.Rules{
+								{Pattern: "*", Owners: []string{"@acme/platform"}},
+								{Pattern: "/apps/payments/", Owners: []string{"@acme/payments"}},
+							}),
+						},
+					},
+				},
+			},
+			wantResults: types.Results{
+				{
+					Target: "apps/payments/config.env",
+					Class:  types.ClassSecret,
+					Secrets: []types.DetectedSecret{
+						{
+							SecretFinding: ftypes.SecretFinding{
+								RuleID:    "github-pat",
+								Severity:  "CRITICAL",
+								StartLine: 2,
+								EndLine:   2,
+								Match:     "GITHUB_TOKEN=*****",
+							},
+						},
+					},
+					Owners: []string{"@acme/payments"},
+				},
+			},
+		},
+		{
+			name: "continue on error",
+			args: args{
+				target:   "alpine:latest",
+				layerIDs: []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
+				options: types.ScanOptions{
+					VulnType:        []string{types.VulnTypeLibrary},
+					SecurityChecks:  []string{types.SecurityCheckVulnerability},
+					ContinueOnError: true,
+				},
+			},
+			fixtures: []string{"testdata/fixtures/sad.yaml"},
+			applyLayersExpectation: ApplierApplyLayersExpectation{
+				Args: ApplierApplyLayersArgs{
+					BlobIDs: []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
+				},
+				Returns: ApplierApplyLayersReturns{
+					Detail: ftypes.ArtifactDetail{
+						Applications: []ftypes.Application{
+							{
+								Type:     "bundler",
+								FilePath: "/app/Gemfile.lock",
+								Libraries: []ftypes.Package{
+									{
+										Name:    "rails",
+										Version: "6.0",
+									},
+								},
+							},
+						},
+						CustomResources: []ftypes.CustomResource{
+							tanalyzer.ErrorResource("app/broken.jar", xerrors.New("zip: not a valid zip file")),
+						},
+					},
+				},
+			},
+			wantResults: types.Results{
+				{
+					Target: "/app/Gemfile.lock",
+					Class:  types.ClassLangPkg,
+					Type:   "bundler",
+					Error: "failed to scan rubygems vulnerabilities: failed to detect rubygems vulnerabilities: " +
+						"failed to get rubygems advisories: failed to unmarshal advisory JSON: " +
+						"json: cannot unmarshal string into Go struct field Advisory.PatchedVersions of type []string",
+				},
+				{
+					Target: "app/broken.jar",
+					Error:  "zip: not a valid zip file",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = dbtest.InitDB(t, tt.fixtures)
+			defer db.Close()
+
+			applier := new(MockApplier)
+			applier.ApplyApplyLayersExpectation(tt.applyLayersExpectation)
+
+			s := NewScanner(applier, ospkg.Detector{}, vulnerability.NewClient(db.Config{}))
+			gotResults, gotOS, err := s.Scan(context.Background(), tt.args.target, "", tt.args.layerIDs, tt.args.options)
+			if tt.wantErr != "" {
+				require.NotNil(t, err, tt.name)
+				require.Contains(t, err.Error(), tt.wantErr, tt.name)
+				return
+			}
 
 			require.NoError(t, err, tt.name)
 			assert.Equal(t, tt.wantResults, gotResults)
@@ -890,3 +1938,94 @@ func TestScanner_Scan(t *testing.T) {
 		})
 	}
 }
+
+// failOnSeverities emulates --severity with --fail-fast
+func failOnSeverities(severities ...dbTypes.Severity) func(types.Results) bool {
+	return func(results types.Results) bool {
+		for _, r := range results {
+			for _, v := range r.Vulnerabilities {
+				// Vulnerabilities without details are UNKNOWN
+				if sev, _ := dbTypes.NewSeverity(v.Severity); slices.Contains(severities, sev) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+func Test_aggregate(t *testing.T) {
+	apps := []ftypes.Application{
+		{
+			Type:     ftypes.Pip,
+			FilePath: "app/requirements.txt",
+			Libraries: []ftypes.Package{
+				{
+					Name:    "requests",
+					Version: "2.26.0",
+				},
+			},
+		},
+		{
+			Type:     types.CondaPkg,
+			FilePath: "opt/conda/conda-meta/numpy-1.21.2-py39h20f2e39_0.json",
+			Libraries: []ftypes.Package{
+				{
+					Name:     "numpy",
+					Version:  "1.21.2",
+					FilePath: "opt/conda/conda-meta/numpy-1.21.2-py39h20f2e39_0.json",
+				},
+			},
+		},
+		{
+			Type:     types.CondaPkg,
+			FilePath: "opt/conda/conda-meta/openssl-1.1.1l-h7f8727e_0.json",
+			Libraries: []ftypes.Package{
+				{
+					Name:     "openssl",
+					Version:  "1.1.1l",
+					FilePath: "opt/conda/conda-meta/openssl-1.1.1l-h7f8727e_0.json",
+				},
+			},
+		},
+	}
+	want := []ftypes.Application{
+		apps[0],
+		{
+			Type: types.CondaPkg,
+			Libraries: []ftypes.Package{
+				apps[1].Libraries[0],
+				apps[2].Libraries[0],
+			},
+		},
+	}
+	assert.Equal(t, want, aggregate(apps))
+}
+
+func TestAnalyzers_Registered(t *testing.T) {
+	// The analyzers of trivy imported in scan.go override those of fanal of the same types
+	tests := []struct {
+		analyzerType analyzer.Type
+		wantVersion  int
+	}{
+		{analyzerType: analyzer.TypeGoBinary, wantVersion: 2},
+		{analyzerType: analyzer.TypeCargo, wantVersion: 2},
+		{analyzerType: analyzer.TypeComposer, wantVersion: 3},
+		{analyzerType: analyzer.TypePythonPkg, wantVersion: 2},
+		{analyzerType: analyzer.TypePipenv, wantVersion: 2},
+		{analyzerType: analyzer.TypePoetry, wantVersion: 2},
+		{analyzerType: analyzer.TypeNpmPkgLock, wantVersion: 2},
+		{analyzerType: analyzer.TypeYarn, wantVersion: 2},
+		{analyzerType: analyzer.TypeNuget, wantVersion: 3},
+		{analyzerType: analyzer.TypeGemSpec, wantVersion: 2},
+		{analyzerType: analyzer.TypeJar, wantVersion: 2},
+		{analyzerType: analyzer.TypeOSRelease, wantVersion: 2},
+	}
+
+	versions := analyzer.NewAnalyzerGroup(analyzer.GroupBuiltin, nil).AnalyzerVersions()
+	for _, tt := range tests {
+		t.Run(string(tt.analyzerType), func(t *testing.T) {
+			assert.Equal(t, tt.wantVersion, versions[string(tt.analyzerType)])
+		})
+	}
+}
@@ -4,18 +4,22 @@ import (
 	"context"
 
 	"github.com/google/wire"
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
 	"github.com/aquasecurity/fanal/artifact"
-	aimage "github.com/aquasecurity/fanal/artifact/image"
-	flocal "github.com/aquasecurity/fanal/artifact/local"
-	"github.com/aquasecurity/fanal/artifact/remote"
-	"github.com/aquasecurity/fanal/image"
 	ftypes "github.com/aquasecurity/fanal/types"
+	aimage "github.com/aquasecurity/trivy/pkg/artifact/image"
+	"github.com/aquasecurity/trivy/pkg/artifact/local"
+	"github.com/aquasecurity/trivy/pkg/artifact/remote"
+	"github.com/aquasecurity/trivy/pkg/artifact/sbom"
+	"github.com/aquasecurity/trivy/pkg/buildpack"
+	timage "github.com/aquasecurity/trivy/pkg/image"
+	"github.com/aquasecurity/trivy/pkg/imageconfig"
 	"github.com/aquasecurity/trivy/pkg/log"
 	"github.com/aquasecurity/trivy/pkg/report"
 	"github.com/aquasecurity/trivy/pkg/rpc/client"
-	"github.com/aquasecurity/trivy/pkg/scanner/local"
+	slocal "github.com/aquasecurity/trivy/pkg/scanner/local"
 	"github.com/aquasecurity/trivy/pkg/types"
 )
 
@@ -25,28 +29,28 @@ import (
 
 // StandaloneSuperSet is used in the standalone mode
 var StandaloneSuperSet = wire.NewSet(
-	local.SuperSet,
-	wire.Bind(new(Driver), new(local.Scanner)),
+	slocal.SuperSet,
+	wire.Bind(new(Driver), new(slocal.Scanner)),
 	NewScanner,
 )
 
 // StandaloneDockerSet binds docker dependencies
 var StandaloneDockerSet = wire.NewSet(
-	image.NewContainerImage,
+	timage.NewContainerImage,
 	aimage.NewArtifact,
 	StandaloneSuperSet,
 )
 
 // StandaloneArchiveSet binds archive scan dependencies
 var StandaloneArchiveSet = wire.NewSet(
-	image.NewArchiveImage,
+	timage.NewArchiveImage,
 	aimage.NewArtifact,
 	StandaloneSuperSet,
 )
 
 // StandaloneFilesystemSet binds filesystem dependencies
 var StandaloneFilesystemSet = wire.NewSet(
-	flocal.NewArtifact,
+	local.NewArtifact,
 	StandaloneSuperSet,
 )
 
@@ -56,6 +60,12 @@ var StandaloneRepositorySet = wire.NewSet(
 	StandaloneSuperSet,
 )
 
+// StandaloneSBOMSet binds SBOM dependencies
+var StandaloneSBOMSet = wire.NewSet(
+	sbom.NewArtifact,
+	StandaloneSuperSet,
+)
+
 /////////////////
 // Client/Server
 /////////////////
@@ -70,21 +80,27 @@ var RemoteSuperSet = wire.NewSet(
 
 // RemoteFilesystemSet binds filesystem dependencies for client/server mode
 var RemoteFilesystemSet = wire.NewSet(
-	flocal.NewArtifact,
+	local.NewArtifact,
 	RemoteSuperSet,
 )
 
 // RemoteDockerSet binds remote docker dependencies
 var RemoteDockerSet = wire.NewSet(
 	aimage.NewArtifact,
-	image.NewContainerImage,
+	timage.NewContainerImage,
 	RemoteSuperSet,
 )
 
 // RemoteArchiveSet binds remote archive dependencies
 var RemoteArchiveSet = wire.NewSet(
 	aimage.NewArtifact,
-	image.NewArchiveImage,
+	timage.NewArchiveImage,
+	RemoteSuperSet,
+)
+
+// RemoteSBOMSet binds SBOM dependencies for client/server mode
+var RemoteSBOMSet = wire.NewSet(
+	sbom.NewArtifact,
 	RemoteSuperSet,
 )
 
@@ -127,6 +143,23 @@ func (s Scanner) ScanArtifact(ctx context.Context, options types.ScanOptions) (t
 		log.Logger.Warnf("The vulnerability detection may be insufficient because security updates are not provided")
 	}
 
+	// Dependencies contributed by Cloud Native Buildpacks are attributed with the image label
+	if m, err := buildpack.ParseLabels(artifactInfo.ImageMetadata.ConfigFile.Config.Labels); err != nil {
+		log.Logger.Debugf("Unable to parse the buildpack metadata: %s", err)
+	} else if m != nil {
+		m.Attribute(results)
+	}
+
+	// The image config is checked in addition to config files in layers
+	if artifactInfo.Type == ftypes.ArtifactContainerImage && slices.Contains(options.SecurityChecks, types.SecurityCheckConfig) {
+		results = append(results, types.Result{
+			Target:            artifactInfo.Name,
+			Class:             types.ClassConfig,
+			Type:              imageconfig.Type,
+			Misconfigurations: imageconfig.Scan(artifactInfo.ImageMetadata.ConfigFile),
+		})
+	}
+
 	// Layer makes sense only when scanning container images
 	if artifactInfo.Type != ftypes.ArtifactContainerImage {
 		removeLayer(results)
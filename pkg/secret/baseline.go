@@ -0,0 +1,143 @@
+package secret
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"sort"
+
+	"github.com/samber/lo"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// FingerprintType is the type of custom resources holding fingerprints of secret findings.
+// Secret findings can't hold the fingerprint, so it is passed as a custom resource.
+const FingerprintType = "trivy-secret-fingerprint"
+
+const baselineSchemaVersion = 1
+
+// FindingFingerprint represents the fingerprint of a secret finding
+type FindingFingerprint struct {
+	RuleID      string
+	StartLine   int
+	Fingerprint string
+}
+
+// Fingerprint returns the SHA-256 hash of the file path, the rule ID and the secret.
+// The line is not included so that the fingerprint doesn't change when lines are added above the secret.
+func Fingerprint(filePath string, d Detection) string {
+	h := sha256.Sum256([]byte(filePath + "\x00" + d.RuleID + "\x00" + d.Secret))
+	return "sha256:" + hex.EncodeToString(h[:])
+}
+
+// Fingerprints returns the fingerprints of the secrets detected in a file
+func Fingerprints(filePath string, detections []Detection) []FindingFingerprint {
+	return lo.Map(detections, func(d Detection, _ int) FindingFingerprint {
+		return FindingFingerprint{
+			RuleID:      d.RuleID,
+			StartLine:   d.StartLine,
+			Fingerprint: Fingerprint(filePath, d),
+		}
+	})
+}
+
+// Baseline holds known or accepted secrets which should not be reported
+type Baseline struct {
+	SchemaVersion int
+	Secrets       []BaselineSecret
+
+	fingerprints map[string]struct{}
+}
+
+// BaselineSecret represents a secret in the baseline.
+// Only the fingerprint is used for matching, and the others are informational.
+type BaselineSecret struct {
+	Target      string
+	RuleID      string
+	StartLine   int
+	Fingerprint string
+}
+
+// NewBaseline creates a baseline from the secrets in the results
+func NewBaseline(results types.Results) *Baseline {
+	b := &Baseline{
+		SchemaVersion: baselineSchemaVersion,
+		fingerprints:  map[string]struct{}{},
+	}
+	for _, result := range results {
+		for _, s := range result.Secrets {
+			if _, ok := b.fingerprints[s.Fingerprint]; ok || s.Fingerprint == "" {
+				continue
+			}
+			b.fingerprints[s.Fingerprint] = struct{}{}
+			b.Secrets = append(b.Secrets, BaselineSecret{
+				Target:      result.Target,
+				RuleID:      s.RuleID,
+				StartLine:   s.StartLine,
+				Fingerprint: s.Fingerprint,
+			})
+		}
+	}
+
+	// The timestamp is not included so that the baseline can be committed without noisy diffs
+	sort.Slice(b.Secrets, func(i, j int) bool {
+		switch {
+		case b.Secrets[i].Target != b.Secrets[j].Target:
+			return b.Secrets[i].Target < b.Secrets[j].Target
+		case b.Secrets[i].StartLine != b.Secrets[j].StartLine:
+			return b.Secrets[i].StartLine < b.Secrets[j].StartLine
+		}
+		return b.Secrets[i].RuleID < b.Secrets[j].RuleID
+	})
+	return b
+}
+
+// ReadBaseline reads the baseline file
+func ReadBaseline(filePath string) (*Baseline, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, xerrors.Errorf("file open error: %w", err)
+	}
+	defer f.Close()
+
+	var b Baseline
+	if err = json.NewDecoder(f).Decode(&b); err != nil {
+		return nil, xerrors.Errorf("secret baseline decode error: %w", err)
+	}
+	if b.SchemaVersion != baselineSchemaVersion {
+		return nil, xerrors.Errorf("unsupported secret baseline schema version: %d", b.SchemaVersion)
+	}
+
+	b.fingerprints = map[string]struct{}{}
+	for _, s := range b.Secrets {
+		b.fingerprints[s.Fingerprint] = struct{}{}
+	}
+	return &b, nil
+}
+
+// Write writes the baseline to the file
+func (b *Baseline) Write(filePath string) error {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return xerrors.Errorf("file create error: %w", err)
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err = enc.Encode(b); err != nil {
+		return xerrors.Errorf("secret baseline encode error: %w", err)
+	}
+	return nil
+}
+
+// Filter removes the secrets in the baseline
+func (b *Baseline) Filter(secrets []types.DetectedSecret) []types.DetectedSecret {
+	return lo.Filter(secrets, func(s types.DetectedSecret, _ int) bool {
+		_, ok := b.fingerprints[s.Fingerprint]
+		return !ok
+	})
+}
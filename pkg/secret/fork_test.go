@@ -0,0 +1,21 @@
+package secret
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForkedFanalVersion(t *testing.T) {
+	info, ok := debug.ReadBuildInfo()
+	require.True(t, ok)
+
+	fanal, found := lo.Find(info.Deps, func(m *debug.Module) bool {
+		return m.Path == "github.com/aquasecurity/fanal"
+	})
+	require.True(t, found)
+	assert.Equal(t, forkedFanalVersion, fanal.Version, "port the changes of fanal into the forked packages")
+}
@@ -0,0 +1,67 @@
+package secret
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// Redaction represents how secrets are redacted in findings
+type Redaction string
+
+const (
+	// RedactionFull replaces the secret with "*****"
+	RedactionFull Redaction = "full"
+
+	// RedactionPartial shows only the last 4 characters of the secret
+	RedactionPartial Redaction = "partial"
+
+	// RedactionHash replaces the secret with the truncated SHA-256 hash so that the same secret can be correlated
+	RedactionHash Redaction = "hash"
+)
+
+const (
+	mask = "*****"
+
+	// Short secrets are fully masked even in the partial mode
+	minPartialLength = 12
+	partialLength    = 4
+
+	hashLength = 16
+)
+
+// Redactions is the list of available redaction modes
+var Redactions = []Redaction{
+	RedactionFull,
+	RedactionPartial,
+	RedactionHash,
+}
+
+// Redact returns the redacted secret
+func (r Redaction) Redact(secret string) string {
+	switch r {
+	case RedactionPartial:
+		if len(secret) < minPartialLength {
+			return mask
+		}
+		return mask + secret[len(secret)-partialLength:]
+	case RedactionHash:
+		h := sha256.Sum256([]byte(secret))
+		return fmt.Sprintf("sha256:%s", hex.EncodeToString(h[:])[:hashLength])
+	default:
+		return mask
+	}
+}
+
+// ParseRedaction returns the redaction mode from the string
+func ParseRedaction(s string) (Redaction, error) {
+	for _, r := range Redactions {
+		if strings.EqualFold(string(r), s) {
+			return r, nil
+		}
+	}
+	return "", xerrors.Errorf("unknown secret redaction: %s", s)
+}
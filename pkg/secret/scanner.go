@@ -0,0 +1,596 @@
+package secret
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/samber/lo"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+// forkedFanalVersion is the version of fanal which the secret scanner, its builtin rules,
+// pkg/analyzer/secret and pkg/artifact/image are forked from so that secret rules can be extended.
+// When fanal is updated in go.mod, its changes in them must be ported and this version must be updated together.
+const forkedFanalVersion = "v0.0.0-20220615115521-e411bc995c6d"
+
+var lineSep = []byte{'\n'}
+
+// maxEntropy is the maximum Shannon entropy of bytes
+const maxEntropy = 8
+
+type Scanner struct {
+	*Global
+	redaction Redaction
+}
+
+type Config struct {
+	// Enable only specified built-in rules. If only one ID is specified, all other rules are disabled.
+	// All the built-in rules are enabled if this field is not specified. It doesn't affect custom rules.
+	EnableBuiltinRuleIDs []string `yaml:"enable-builtin-rules"`
+
+	// Disable rules. It is applied to enabled IDs.
+	DisableRuleIDs []string `yaml:"disable-rules"`
+
+	// Disable allow rules.
+	DisableAllowRuleIDs []string `yaml:"disable-allow-rules"`
+
+	CustomRules      []Rule       `yaml:"rules"`
+	CustomAllowRules AllowRules   `yaml:"allow-rules"`
+	ExcludeBlock     ExcludeBlock `yaml:"exclude-block"`
+}
+
+type Global struct {
+	Rules        []Rule
+	AllowRules   AllowRules
+	ExcludeBlock ExcludeBlock
+}
+
+// Allow checks if the match is allowed
+func (g Global) Allow(match string) bool {
+	return g.AllowRules.Allow(match)
+}
+
+// AllowLine checks if the line in the file is allowed
+func (g Global) AllowLine(path, line string) bool {
+	return g.AllowRules.AllowLine(path, line)
+}
+
+// AllowPath checks if the path is allowed
+func (g Global) AllowPath(path string) bool {
+	return g.AllowRules.AllowPath(path)
+}
+
+// Regexp adds unmarshalling from YAML for regexp.Regexp
+type Regexp struct {
+	*regexp.Regexp
+}
+
+func MustCompile(str string) *Regexp {
+	return &Regexp{regexp.MustCompile(str)}
+}
+
+// UnmarshalYAML unmarshals YAML into a regexp.Regexp
+func (r *Regexp) UnmarshalYAML(value *yaml.Node) error {
+	var v string
+	if err := value.Decode(&v); err != nil {
+		return err
+	}
+	regex, err := regexp.Compile(v)
+	if err != nil {
+		return xerrors.Errorf("regexp compile error: %w", err)
+	}
+
+	r.Regexp = regex
+	return nil
+}
+
+type Rule struct {
+	ID              string                   `yaml:"id"`
+	Category        types.SecretRuleCategory `yaml:"category"`
+	Title           string                   `yaml:"title"`
+	Severity        string                   `yaml:"severity"`
+	Regex           *Regexp                  `yaml:"regex"`
+	Keywords        []string                 `yaml:"keywords"`
+	Path            *Regexp                  `yaml:"path"`
+	AllowRules      AllowRules               `yaml:"allow-rules"`
+	ExcludeBlock    ExcludeBlock             `yaml:"exclude-block"`
+	SecretGroupName string                   `yaml:"secret-group-name"`
+
+	// Entropy is the minimum Shannon entropy of the secret in bits per character.
+	// Matches with lower entropy, such as placeholders, are ignored. It is not checked if zero.
+	Entropy float64 `yaml:"entropy"`
+}
+
+// MatchEntropy checks if the secret is random enough
+func (r *Rule) MatchEntropy(secret []byte) bool {
+	return r.Entropy == 0 || shannonEntropy(secret) >= r.Entropy
+}
+
+func (r *Rule) validate() error {
+	if r.ID == "" {
+		return xerrors.New("'id' is required")
+	}
+	if r.Regex == nil {
+		return xerrors.Errorf("%s: 'regex' is required", r.ID)
+	}
+	if r.Entropy < 0 || r.Entropy > maxEntropy {
+		return xerrors.Errorf("%s: 'entropy' must be between 0 and %d", r.ID, maxEntropy)
+	}
+	if r.SecretGroupName != "" && !slices.Contains(r.Regex.SubexpNames(), r.SecretGroupName) {
+		return xerrors.Errorf("%s: the regex doesn't have the group %q", r.ID, r.SecretGroupName)
+	}
+	return nil
+}
+
+func (s *Scanner) FindLocations(r Rule, content []byte) []Location {
+	if r.Regex == nil {
+		return nil
+	}
+
+	if r.SecretGroupName != "" {
+		return s.FindSubmatchLocations(r, content)
+	}
+
+	var locs []Location
+	indices := r.Regex.FindAllIndex(content, -1)
+	for _, index := range indices {
+		loc := Location{
+			Start: index[0],
+			End:   index[1],
+		}
+
+		if s.AllowLocation(r, content, loc) {
+			continue
+		}
+
+		locs = append(locs, loc)
+	}
+	return locs
+}
+
+func (s *Scanner) FindSubmatchLocations(r Rule, content []byte) []Location {
+	var submatchLocations []Location
+	matchsIndices := r.Regex.FindAllSubmatchIndex(content, -1)
+	for _, matchIndices := range matchsIndices {
+		matchLocation := Location{ // first two indexes are always start and end of the whole match
+			Start: matchIndices[0],
+			End:   matchIndices[1],
+		}
+
+		if s.AllowLocation(r, content, matchLocation) {
+			continue
+		}
+
+		matchSubgroupsLocations := r.getMatchSubgroupsLocations(matchIndices)
+		if len(matchSubgroupsLocations) > 0 {
+			submatchLocations = append(submatchLocations, matchSubgroupsLocations...)
+		}
+	}
+	return submatchLocations
+}
+
+func (s *Scanner) AllowLocation(r Rule, content []byte, loc Location) bool {
+	match := string(content[loc.Start:loc.End])
+	return s.Allow(match) || r.Allow(match)
+}
+
+func (r *Rule) getMatchSubgroupsLocations(matchLocs []int) []Location {
+	var locations []Location
+	for i, name := range r.Regex.SubexpNames() {
+		if name == r.SecretGroupName {
+			startLocIndex := 2 * i
+			endLocIndex := startLocIndex + 1
+			locations = append(locations, Location{Start: matchLocs[startLocIndex], End: matchLocs[endLocIndex]})
+		}
+	}
+	return locations
+}
+
+func (r *Rule) MatchPath(path string) bool {
+	return r.Path == nil || r.Path.MatchString(path)
+}
+
+func (r *Rule) MatchKeywords(content []byte) bool {
+	if len(r.Keywords) == 0 {
+		return true
+	}
+
+	for _, kw := range r.Keywords {
+		if bytes.Contains(bytes.ToLower(content), []byte(strings.ToLower(kw))) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *Rule) AllowPath(path string) bool {
+	return r.AllowRules.AllowPath(path)
+}
+
+func (r *Rule) AllowLine(path, line string) bool {
+	return r.AllowRules.AllowLine(path, line)
+}
+
+func (r *Rule) Allow(match string) bool {
+	return r.AllowRules.Allow(match)
+}
+
+type AllowRule struct {
+	ID          string  `yaml:"id"`
+	Description string  `yaml:"description"`
+	Regex       *Regexp `yaml:"regex"`
+	Path        *Regexp `yaml:"path"`
+
+	// Line allows secrets in the lines matching the regex, e.g. lines with a marker comment.
+	// If Path is also specified, only the lines in the matched files are allowed.
+	Line *Regexp `yaml:"line"`
+}
+
+type AllowRules []AllowRule
+
+func (rules AllowRules) AllowPath(path string) bool {
+	for _, rule := range rules {
+		// Path in line rules limits the files where the line rule is applied
+		if rule.Line != nil {
+			continue
+		}
+		if rule.Path != nil && rule.Path.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowLine checks if the line in the file is allowed
+func (rules AllowRules) AllowLine(path, line string) bool {
+	for _, rule := range rules {
+		if rule.Line == nil || (rule.Path != nil && !rule.Path.MatchString(path)) {
+			continue
+		}
+		if rule.Line.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+func (rules AllowRules) Allow(match string) bool {
+	for _, rule := range rules {
+		if rule.Regex != nil && rule.Regex.MatchString(match) {
+			return true
+		}
+	}
+	return false
+}
+
+type ExcludeBlock struct {
+	Description string    `yaml:"description"`
+	Regexes     []*Regexp `yaml:"regexes"`
+}
+
+type Location struct {
+	Start int
+	End   int
+}
+
+func (l Location) Match(loc Location) bool {
+	return l.Start <= loc.Start && loc.End <= l.End
+}
+
+type Blocks struct {
+	content []byte
+	regexes []*Regexp
+	locs    []Location
+	once    *sync.Once
+}
+
+func newBlocks(content []byte, regexes []*Regexp) Blocks {
+	return Blocks{
+		content: content,
+		regexes: regexes,
+		once:    new(sync.Once),
+	}
+}
+
+func (b *Blocks) Match(block Location) bool {
+	b.once.Do(b.find)
+	for _, loc := range b.locs {
+		if loc.Match(block) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Blocks) find() {
+	for _, regex := range b.regexes {
+		results := regex.FindAllIndex(b.content, -1)
+		if len(results) == 0 {
+			continue
+		}
+		for _, r := range results {
+			b.locs = append(b.locs, Location{
+				Start: r[0],
+				End:   r[1],
+			})
+		}
+	}
+}
+
+type scannerOption func(*Scanner)
+
+// WithRedaction takes how secrets are redacted in findings
+func WithRedaction(r Redaction) scannerOption {
+	return func(s *Scanner) {
+		s.redaction = r
+	}
+}
+
+func NewScanner(configPath string, opts ...scannerOption) (Scanner, error) {
+	global, err := loadGlobal(configPath)
+	if err != nil {
+		return Scanner{}, err
+	}
+
+	s := Scanner{
+		Global:    global,
+		redaction: RedactionFull,
+	}
+	for _, opt := range opts {
+		opt(&s)
+	}
+	return s, nil
+}
+
+// loadGlobal loads rules and allow rules from the config file
+func loadGlobal(configPath string) (*Global, error) {
+	// Set default values
+	global := Global{
+		Rules:      builtinRules,
+		AllowRules: builtinAllowRules,
+	}
+
+	// If no config is passed, use built-in rules and allow rules.
+	if configPath == "" {
+		return &global, nil
+	}
+
+	f, err := os.Open(configPath)
+	if errors.Is(err, os.ErrNotExist) {
+		// If the specified file doesn't exist, it just uses built-in rules and allow rules.
+		log.Logger.Debugf("No secret config detected: %s", configPath)
+		return &global, nil
+	} else if err != nil {
+		return nil, xerrors.Errorf("file open error %s: %w", configPath, err)
+	}
+	defer f.Close()
+
+	log.Logger.Infof("Loading %s for secret scanning...", configPath)
+
+	// reset global
+	global = Global{}
+
+	var config Config
+	if err = yaml.NewDecoder(f).Decode(&config); err != nil {
+		return nil, xerrors.Errorf("secrets config decode error: %w", err)
+	}
+
+	enabledRules := builtinRules
+	if len(config.EnableBuiltinRuleIDs) != 0 {
+		// Enable only specified built-in rules
+		enabledRules = lo.Filter(builtinRules, func(v Rule, _ int) bool {
+			return slices.Contains(config.EnableBuiltinRuleIDs, v.ID)
+		})
+	}
+
+	for _, rule := range config.CustomRules {
+		if err = rule.validate(); err != nil {
+			return nil, xerrors.Errorf("invalid custom rule: %w", err)
+		}
+	}
+
+	// Custom rules are enabled regardless of "enable-builtin-rules".
+	enabledRules = append(enabledRules, config.CustomRules...)
+
+	// Disable specified rules
+	global.Rules = lo.Filter(enabledRules, func(v Rule, _ int) bool {
+		return !slices.Contains(config.DisableRuleIDs, v.ID)
+	})
+
+	// Disable specified allow rules
+	allowRules := append(builtinAllowRules, config.CustomAllowRules...)
+	global.AllowRules = lo.Filter(allowRules, func(v AllowRule, _ int) bool {
+		return !slices.Contains(config.DisableAllowRuleIDs, v.ID)
+	})
+
+	global.ExcludeBlock = config.ExcludeBlock
+
+	return &global, nil
+}
+
+type ScanArgs struct {
+	FilePath string
+	Content  []byte
+}
+
+func (s Scanner) Scan(args ScanArgs) types.Secret {
+	// Global allowed paths
+	if s.AllowPath(args.FilePath) {
+		return types.Secret{
+			FilePath: args.FilePath,
+		}
+	}
+
+	detections := s.Detect(args)
+	if len(detections) == 0 {
+		return types.Secret{}
+	}
+
+	return types.Secret{
+		FilePath: args.FilePath,
+		Findings: lo.Map(detections, func(d Detection, _ int) types.SecretFinding {
+			return d.SecretFinding
+		}),
+	}
+}
+
+// Detection holds a secret finding together with the raw secret.
+// The raw secret is used only for verification and must not be stored.
+type Detection struct {
+	types.SecretFinding
+	Secret string
+
+	// Builtin is true if the secret is detected by a built-in rule, which is the only one verified.
+	// Custom rules may reuse the IDs of built-in rules.
+	Builtin bool
+}
+
+// isBuiltin returns whether the rule is a built-in rule rather than a custom rule with the same ID
+func isBuiltin(rule Rule) bool {
+	return slices.IndexFunc(builtinRules, func(r Rule) bool {
+		return r.ID == rule.ID && r.Regex == rule.Regex
+	}) >= 0
+}
+
+// Detect returns the secrets detected in the content
+func (s Scanner) Detect(args ScanArgs) []Detection {
+	// Global allowed paths
+	if s.AllowPath(args.FilePath) {
+		return nil
+	}
+
+	var detections []Detection
+	globalExcludedBlocks := newBlocks(args.Content, s.ExcludeBlock.Regexes)
+	for _, rule := range s.Rules {
+		// Check if the file path should be scanned by this rule
+		if !rule.MatchPath(args.FilePath) {
+			continue
+		}
+
+		// Check if the file path should be allowed
+		if rule.AllowPath(args.FilePath) {
+			continue
+		}
+
+		// Check if the file content contains keywords and should be scanned
+		if !rule.MatchKeywords(args.Content) {
+			continue
+		}
+
+		// Detect secrets
+		locs := s.FindLocations(rule, args.Content)
+		if len(locs) == 0 {
+			continue
+		}
+
+		localExcludedBlocks := newBlocks(args.Content, rule.ExcludeBlock.Regexes)
+		builtin := isBuiltin(rule)
+		for _, loc := range locs {
+			// Skip the secret if it is within excluded blocks.
+			if globalExcludedBlocks.Match(loc) || localExcludedBlocks.Match(loc) {
+				continue
+			}
+
+			// Skip low-entropy secrets such as placeholders
+			if !rule.MatchEntropy(args.Content[loc.Start:loc.End]) {
+				continue
+			}
+
+			// Skip the secret if the line is allowed
+			line := string(args.Content[lineStart(args.Content, loc.Start):lineEnd(args.Content, loc.End)])
+			if s.AllowLine(args.FilePath, line) || rule.AllowLine(args.FilePath, line) {
+				continue
+			}
+
+			detections = append(detections, Detection{
+				SecretFinding: toFinding(rule, loc, args.Content, s.redaction),
+				Secret:        string(args.Content[loc.Start:loc.End]),
+				Builtin:       builtin,
+			})
+		}
+	}
+	return detections
+}
+
+func toFinding(rule Rule, loc Location, content []byte, redaction Redaction) types.SecretFinding {
+	startLine, endLine, matchLine := findLocation(loc.Start, loc.End, content, redaction)
+
+	return types.SecretFinding{
+		RuleID:    rule.ID,
+		Category:  rule.Category,
+		Severity:  lo.Ternary(rule.Severity == "", "UNKNOWN", rule.Severity),
+		Title:     rule.Title,
+		StartLine: startLine,
+		EndLine:   endLine,
+		Match:     matchLine,
+	}
+}
+
+func findLocation(start, end int, content []byte, redaction Redaction) (int, int, string) {
+	startLineNum := bytes.Count(content[:start], lineSep) + 1
+	endLineNum := startLineNum // TODO: support multi lines
+
+	lineStart := lineStart(content, start)
+	lineEnd := lineEnd(content, start)
+
+	match := string(content[start:end])
+	matchLine := string(content[lineStart:lineEnd])
+	if len(matchLine) > 100 {
+		truncatedLineStart := lo.Ternary(start-30 < 0, 0, start-30)
+		truncatedLineEnd := lo.Ternary(end+20 > len(content), len(content), end+20)
+		matchLine = string(content[truncatedLineStart:truncatedLineEnd])
+	}
+
+	// Mask credentials
+	matchLine = strings.TrimSpace(strings.ReplaceAll(matchLine, match, redaction.Redact(match)))
+
+	return startLineNum, endLineNum, matchLine
+}
+
+// lineStart returns the offset of the beginning of the line including the offset
+func lineStart(content []byte, offset int) int {
+	return bytes.LastIndex(content[:offset], lineSep) + 1
+}
+
+// lineEnd returns the offset of the end of the line including the offset
+func lineEnd(content []byte, offset int) int {
+	end := bytes.Index(content[offset:], lineSep)
+	if end == -1 {
+		return len(content)
+	}
+	return offset + end
+}
+
+// shannonEntropy returns the Shannon entropy of the data in bits per byte
+func shannonEntropy(data []byte) float64 {
+	if len(data) == 0 {
+		return 0
+	}
+
+	var counts [256]int
+	for _, b := range data {
+		counts[b]++
+	}
+
+	var entropy float64
+	size := float64(len(data))
+	for _, c := range counts {
+		if c == 0 {
+			continue
+		}
+		p := float64(c) / size
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
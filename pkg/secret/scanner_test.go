@@ -0,0 +1,136 @@
+package secret_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/secret"
+)
+
+func TestMain(m *testing.M) {
+	_ = log.InitLogger(false, true)
+	os.Exit(m.Run())
+}
+
+func TestNewScanner(t *testing.T) {
+	tests := []struct {
+		name       string
+		configPath string
+		wantErr    string
+	}{
+		{
+			name:       "custom rules",
+			configPath: "testdata/custom-rules.yaml",
+		},
+		{
+			name:       "no config",
+			configPath: "testdata/not-found.yaml",
+		},
+		{
+			name:       "invalid entropy",
+			configPath: "testdata/invalid-entropy.yaml",
+			wantErr:    "internal-token: 'entropy' must be between 0 and 8",
+		},
+		{
+			name:       "missing group",
+			configPath: "testdata/invalid-group.yaml",
+			wantErr:    `internal-token: the regex doesn't have the group "secret"`,
+		},
+		{
+			name:       "no regex",
+			configPath: "testdata/no-regex.yaml",
+			wantErr:    "internal-token: 'regex' is required",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := secret.NewScanner(tt.configPath)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestScanner_Scan(t *testing.T) {
+	finding := types.SecretFinding{
+		RuleID:    "internal-token",
+		Category:  "Internal",
+		Title:     "Internal API token",
+		Severity:  "HIGH",
+		StartLine: 1,
+		EndLine:   1,
+		Match:     "internal_token = '*****'",
+	}
+
+	tests := []struct {
+		name     string
+		filePath string
+		content  string
+		want     []types.SecretFinding
+	}{
+		{
+			name:     "random token",
+			filePath: "app/config.py",
+			content:  "internal_token = 'q8Xz2LmP4vRt7NwK9bYc'\n",
+			want:     []types.SecretFinding{finding},
+		},
+		{
+			name:     "low entropy placeholder",
+			filePath: "app/config.py",
+			content:  "internal_token = 'xxxxxxxxxxxxxxxxxxxx'\n",
+		},
+		{
+			name:     "globally allowed line",
+			filePath: "app/config.py",
+			content:  "internal_token = 'q8Xz2LmP4vRt7NwK9bYc' # sample\n",
+		},
+		{
+			name:     "allowed line in the allowed path",
+			filePath: "app/fixtures/config.py",
+			content:  "internal_token = 'q8Xz2LmP4vRt7NwK9bYc' # dummy\n",
+		},
+		{
+			name:     "allowed line in another path",
+			filePath: "app/config.py",
+			content:  "internal_token = 'q8Xz2LmP4vRt7NwK9bYc' # dummy\n",
+			want: []types.SecretFinding{
+				{
+					RuleID:    "internal-token",
+					Category:  "Internal",
+					Title:     "Internal API token",
+					Severity:  "HIGH",
+					StartLine: 1,
+					EndLine:   1,
+					Match:     "internal_token = '*****' # dummy",
+				},
+			},
+		},
+		{
+			name:     "line rules don't allow the whole file",
+			filePath: "app/fixtures/config.py",
+			content:  "internal_token = 'q8Xz2LmP4vRt7NwK9bYc'\n",
+			want:     []types.SecretFinding{finding},
+		},
+	}
+
+	s, err := secret.NewScanner("testdata/custom-rules.yaml")
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.Scan(secret.ScanArgs{
+				FilePath: tt.filePath,
+				Content:  []byte(tt.content),
+			})
+			assert.Equal(t, tt.want, got.Findings)
+		})
+	}
+}
@@ -0,0 +1,280 @@
+package secret
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/samber/lo"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/httpclient"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// VerificationType is the type of custom resources holding verification results.
+// Secret findings can't hold the verification status, so it is passed as a custom resource.
+const VerificationType = "trivy-secret-verification"
+
+const (
+	githubAPIURL     = "https://api.github.com/rate_limit"
+	awsSTSURL        = "https://sts.amazonaws.com/"
+	slackWebhookHost = "hooks.slack.com"
+	awsRegion        = "us-east-1"
+	verifyTimeout    = 10 * time.Second
+	maxAWSKeyPairs   = 10
+)
+
+// Verification represents the verification result of a secret finding
+type Verification struct {
+	RuleID    string
+	StartLine int
+	Status    types.SecretVerification
+}
+
+type verifyFunc func(ctx context.Context, secret string) (types.SecretVerification, error)
+
+type verifierOption func(*Verifier)
+
+// WithHTTPClient takes a custom HTTP client for calling provider APIs
+func WithHTTPClient(client *http.Client) verifierOption {
+	return func(v *Verifier) {
+		v.client = client
+	}
+}
+
+// Verifier checks whether detected secrets are live by calling provider APIs.
+// The calls are read-only and don't change anything on the provider side.
+type Verifier struct {
+	client *http.Client
+
+	mu      sync.Mutex
+	results map[string]types.SecretVerification
+}
+
+// NewVerifier is the factory method for Verifier
+func NewVerifier(opts ...verifierOption) *Verifier {
+	v := &Verifier{
+		client:  httpclient.Client(verifyTimeout, false),
+		results: map[string]types.SecretVerification{},
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// Verify verifies the secrets detected in a file.
+// Secrets which can't be verified, such as unsupported types, are not included in the result.
+// Only secrets detected by built-in rules are sent to providers, as custom rules may match anything.
+func (v *Verifier) Verify(ctx context.Context, detections []Detection) []Verification {
+	detections = lo.Filter(detections, func(d Detection, _ int) bool {
+		return d.Builtin
+	})
+
+	var verifications []Verification
+	for _, d := range detections {
+		var verify verifyFunc
+		switch d.RuleID {
+		case "github-pat", "github-oauth", "github-app-token":
+			verify = v.verifyGitHub
+		case "slack-web-hook":
+			verify = v.verifySlackWebhook
+		default:
+			continue
+		}
+
+		status, err := v.cached(ctx, d.RuleID, d.Secret, verify)
+		if err != nil {
+			log.Logger.Debugf("Unable to verify the secret (%s) at line %d: %s", d.RuleID, d.StartLine, err)
+			continue
+		}
+		verifications = append(verifications, Verification{
+			RuleID:    d.RuleID,
+			StartLine: d.StartLine,
+			Status:    status,
+		})
+	}
+
+	return append(verifications, v.verifyAWSKeys(ctx, detections)...)
+}
+
+// cached calls the verify function only once for the same secret
+func (v *Verifier) cached(ctx context.Context, ruleID, secret string, verify verifyFunc) (types.SecretVerification, error) {
+	key := ruleID + ":" + secret
+	v.mu.Lock()
+	status, ok := v.results[key]
+	v.mu.Unlock()
+	if ok {
+		return status, nil
+	}
+
+	status, err := verify(ctx, secret)
+	if err != nil {
+		return "", err
+	}
+
+	v.mu.Lock()
+	v.results[key] = status
+	v.mu.Unlock()
+	return status, nil
+}
+
+// verifyAWSKeys verifies pairs of an access key ID and a secret access key in the same file.
+// A key without the other half can't be verified.
+func (v *Verifier) verifyAWSKeys(ctx context.Context, detections []Detection) []Verification {
+	keyIDs := lo.Filter(detections, func(d Detection, _ int) bool {
+		return d.RuleID == "aws-access-key-id"
+	})
+	secretKeys := lo.Filter(detections, func(d Detection, _ int) bool {
+		return d.RuleID == "aws-secret-access-key"
+	})
+	if len(keyIDs) == 0 || len(secretKeys) == 0 {
+		return nil
+	}
+	if len(keyIDs)*len(secretKeys) > maxAWSKeyPairs {
+		log.Logger.Debugf("Too many AWS key pairs to verify: %d", len(keyIDs)*len(secretKeys))
+		return nil
+	}
+
+	statuses := map[Detection]types.SecretVerification{}
+	for _, keyID := range keyIDs {
+		for _, secretKey := range secretKeys {
+			status, err := v.cached(ctx, "aws", keyID.Secret+":"+secretKey.Secret,
+				func(ctx context.Context, _ string) (types.SecretVerification, error) {
+					return v.verifyAWS(ctx, keyID.Secret, secretKey.Secret)
+				})
+			if err != nil {
+				log.Logger.Debugf("Unable to verify the AWS access key at line %d: %s", keyID.StartLine, err)
+				continue
+			}
+
+			// A key is verified if it works with any of the other halves
+			for _, d := range []Detection{keyID, secretKey} {
+				if statuses[d] != types.SecretVerified {
+					statuses[d] = status
+				}
+			}
+		}
+	}
+
+	var verifications []Verification
+	for _, d := range append(keyIDs, secretKeys...) {
+		if status, ok := statuses[d]; ok {
+			verifications = append(verifications, Verification{
+				RuleID:    d.RuleID,
+				StartLine: d.StartLine,
+				Status:    status,
+			})
+		}
+	}
+	return verifications
+}
+
+// verifyAWS calls sts:GetCallerIdentity, which requires no permissions
+func (v *Verifier) verifyAWS(ctx context.Context, keyID, secretKey string) (types.SecretVerification, error) {
+	body := strings.NewReader("Action=GetCallerIdentity&Version=2011-06-15")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, awsSTSURL, body)
+	if err != nil {
+		return "", xerrors.Errorf("http request error: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	signer := v4.NewSigner(credentials.NewStaticCredentials(keyID, secretKey, ""))
+	if _, err = signer.Sign(req, body, "sts", awsRegion, time.Now()); err != nil {
+		return "", xerrors.Errorf("aws sign error: %w", err)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return "", xerrors.Errorf("http error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return types.SecretVerified, nil
+	}
+
+	var errResp struct {
+		Code string `xml:"Error>Code"`
+	}
+	if err = xml.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&errResp); err != nil {
+		return "", xerrors.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	switch errResp.Code {
+	case "InvalidClientTokenId", "SignatureDoesNotMatch":
+		return types.SecretUnverified, nil
+	}
+	return "", xerrors.Errorf("unexpected error: %s", errResp.Code)
+}
+
+// verifyGitHub calls the rate limit API, which is available to any valid token
+func (v *Verifier) verifyGitHub(ctx context.Context, token string) (types.SecretVerification, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, githubAPIURL, nil)
+	if err != nil {
+		return "", xerrors.Errorf("http request error: %w", err)
+	}
+	req.Header.Set("Authorization", "token "+token)
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return "", xerrors.Errorf("http error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return types.SecretVerified, nil
+	case http.StatusUnauthorized:
+		return types.SecretUnverified, nil
+	}
+	return "", xerrors.Errorf("unexpected status code: %d", resp.StatusCode)
+}
+
+// verifySlackWebhook posts an empty payload, which is rejected without posting a message.
+// A live webhook responds with "no_text" while a revoked one responds with an error such as "no_team".
+func (v *Verifier) verifySlackWebhook(ctx context.Context, webhookURL string) (types.SecretVerification, error) {
+	// The secret comes from the scanned file, so it must not be sent anywhere else
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return "", xerrors.Errorf("url parse error: %w", err)
+	} else if u.Scheme != "https" || u.Host != slackWebhookHost || u.User != nil ||
+		!strings.HasPrefix(u.Path, "/services/") {
+		return "", xerrors.Errorf("not a Slack webhook URL: %s://%s", u.Scheme, u.Host)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, strings.NewReader("{}"))
+	if err != nil {
+		return "", xerrors.Errorf("http request error: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return "", xerrors.Errorf("http error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return "", xerrors.Errorf("read error: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusBadRequest && strings.TrimSpace(string(body)) == "no_text":
+		return types.SecretVerified, nil
+	case resp.StatusCode == http.StatusForbidden, resp.StatusCode == http.StatusNotFound,
+		resp.StatusCode == http.StatusGone:
+		return types.SecretUnverified, nil
+	}
+	return "", xerrors.Errorf("unexpected response: %d %s", resp.StatusCode, body)
+}
@@ -0,0 +1,85 @@
+package signature
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/sbom"
+)
+
+const (
+	// Annotation and payload of cosign signatures
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+	cosignSignatureType       = "cosign container image signature"
+)
+
+// simpleSigning is the payload signed by cosign
+type simpleSigning struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// cosignSignatures returns the cosign signatures of the image, which are stored with the tag "sha256-<digest>.sig".
+// Each layer is the payload with the signature in the annotation.
+func cosignSignatures(repo name.Repository, digest string, opt Option) ([]signature, error) {
+	desc, err := get(repo.Tag(strings.ReplaceAll(digest, ":", "-")+".sig"), opt)
+	if err != nil || desc == nil {
+		return nil, err
+	}
+
+	img, err := desc.Image()
+	if err != nil {
+		return nil, xerrors.Errorf("signature image error: %w", err)
+	}
+	manifest, err := img.Manifest()
+	if err != nil {
+		return nil, xerrors.Errorf("signature manifest error: %w", err)
+	}
+
+	var sigs []signature
+	for _, layer := range manifest.Layers {
+		encoded, ok := layer.Annotations[cosignSignatureAnnotation]
+		if !ok {
+			continue
+		}
+
+		payload, err := blob(repo, layer.Digest.String(), opt)
+		if err != nil {
+			return nil, xerrors.Errorf("signature payload error: %w", err)
+		}
+		sigs = append(sigs, signature{
+			format: FormatCosign,
+			err:    verifyCosign(payload, encoded, digest, opt),
+		})
+	}
+	return sigs, nil
+}
+
+func verifyCosign(payload []byte, encoded, digest string, opt Option) error {
+	sig, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return xerrors.Errorf("signature decode error: %w", err)
+	}
+	if !sbom.VerifySignature(opt.PublicKey, payload, sig) {
+		return xerrors.New("the signature is not valid with the public key")
+	}
+
+	var s simpleSigning
+	if err = json.Unmarshal(payload, &s); err != nil {
+		return xerrors.Errorf("payload decode error: %w", err)
+	}
+	if s.Critical.Type != cosignSignatureType {
+		return xerrors.Errorf("unexpected signature type: %s", s.Critical.Type)
+	} else if s.Critical.Image.DockerManifestDigest != digest {
+		return xerrors.Errorf("the signature is for %s", s.Critical.Image.DockerManifestDigest)
+	}
+	return nil
+}
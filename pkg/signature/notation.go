@@ -0,0 +1,296 @@
+package signature
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/image"
+)
+
+const (
+	// notationArtifactType is the artifact type of Notation signatures
+	notationArtifactType = "application/vnd.cncf.notary.signature"
+
+	// notationMediaTypeJWS is the media type of JWS signature envelopes.
+	// COSE envelopes are not supported.
+	notationMediaTypeJWS = "application/jose+json"
+
+	notationPayloadType = "application/vnd.cncf.notary.payload.v1+json"
+
+	referrersTimeout = 30 * time.Second
+)
+
+// ociDescriptor is the descriptor in OCI manifests, which has the artifact type unlike v1.Descriptor
+type ociDescriptor struct {
+	MediaType    string `json:"mediaType"`
+	ArtifactType string `json:"artifactType"`
+	Digest       string `json:"digest"`
+}
+
+// ociManifest is either an image index listing referrers or an image manifest of a signature
+type ociManifest struct {
+	ArtifactType string          `json:"artifactType"`
+	Config       ociDescriptor   `json:"config"`
+	Manifests    []ociDescriptor `json:"manifests"`
+	Layers       []ociDescriptor `json:"layers"`
+}
+
+// jws is a JWS envelope in the flattened JSON serialization
+type jws struct {
+	Payload   string `json:"payload"`
+	Protected string `json:"protected"`
+	Header    struct {
+		// X5C is the certificate chain, where the first one is the signing certificate
+		X5C []string `json:"x5c"`
+	} `json:"header"`
+	Signature string `json:"signature"`
+}
+
+type jwsProtected struct {
+	Algorithm   string `json:"alg"`
+	ContentType string `json:"cty"`
+}
+
+type notationPayload struct {
+	TargetArtifact ociDescriptor `json:"targetArtifact"`
+}
+
+// notationSignatures returns the Notation signatures of the image, which are the referrers of the image
+func notationSignatures(repo name.Repository, digest string, opt Option) ([]signature, error) {
+	descs, err := referrers(repo, digest, opt)
+	if err != nil {
+		return nil, xerrors.Errorf("referrers error: %w", err)
+	}
+
+	var sigs []signature
+	for _, desc := range descs {
+		d, err := get(repo.Digest(desc.Digest), opt)
+		if err != nil {
+			return nil, err
+		} else if d == nil {
+			continue
+		}
+
+		var m ociManifest
+		if err = json.Unmarshal(d.Manifest, &m); err != nil {
+			return nil, xerrors.Errorf("signature manifest decode error: %w", err)
+		}
+		if artifactType(m) != notationArtifactType {
+			continue
+		}
+
+		for _, layer := range m.Layers {
+			if layer.MediaType != notationMediaTypeJWS {
+				sigs = append(sigs, signature{
+					format: FormatNotation,
+					err:    xerrors.Errorf("unsupported signature envelope: %s", layer.MediaType),
+				})
+				continue
+			}
+
+			b, err := blob(repo, layer.Digest, opt)
+			if err != nil {
+				return nil, xerrors.Errorf("signature envelope error: %w", err)
+			}
+			sigs = append(sigs, signature{
+				format: FormatNotation,
+				err:    verifyNotation(b, digest, opt),
+			})
+		}
+	}
+	return sigs, nil
+}
+
+// referrers returns the descriptors of the artifacts referring to the digest.
+// The referrers API is used if the registry supports it, otherwise the referrers tag schema "sha256-<digest>".
+func referrers(repo name.Repository, digest string, opt Option) ([]ociDescriptor, error) {
+	b, err := referrersAPI(repo, digest, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	if b == nil {
+		desc, err := get(repo.Tag(strings.ReplaceAll(digest, ":", "-")), opt)
+		if err != nil || desc == nil {
+			return nil, err
+		}
+		b = desc.Manifest
+	}
+
+	var index ociManifest
+	if err = json.Unmarshal(b, &index); err != nil {
+		return nil, xerrors.Errorf("referrers decode error: %w", err)
+	}
+	return index.Manifests, nil
+}
+
+// referrersAPI returns the image index listing the referrers, or nil if the registry doesn't support the API
+func referrersAPI(repo name.Repository, digest string, opt Option) ([]byte, error) {
+	auth, err := image.DefaultKeychain.Resolve(repo)
+	if err != nil {
+		return nil, xerrors.Errorf("auth error: %w", err)
+	}
+
+	rt, err := transport.New(repo.Registry, auth, image.Transport(opt.Insecure), []string{repo.Scope(transport.PullScope)})
+	if err != nil {
+		return nil, xerrors.Errorf("transport error: %w", err)
+	}
+
+	u := url.URL{
+		Scheme: repo.Registry.Scheme(),
+		Host:   repo.RegistryStr(),
+		Path:   fmt.Sprintf("/v2/%s/referrers/%s", repo.RepositoryStr(), digest),
+	}
+	client := &http.Client{Transport: rt, Timeout: referrersTimeout}
+	resp, err := client.Get(u.String())
+	if err != nil {
+		return nil, xerrors.Errorf("HTTP error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	} else if err = transport.CheckError(resp, http.StatusOK); err != nil {
+		return nil, err
+	}
+
+	var index json.RawMessage
+	if err = json.NewDecoder(resp.Body).Decode(&index); err != nil {
+		return nil, xerrors.Errorf("referrers decode error: %w", err)
+	}
+	return index, nil
+}
+
+// artifactType returns the artifact type of the manifest.
+// Older versions of Notation put it in the media type of the config.
+func artifactType(m ociManifest) string {
+	if m.ArtifactType != "" {
+		return m.ArtifactType
+	}
+	return m.Config.MediaType
+}
+
+// verifyNotation verifies the JWS envelope signed with the certificate chained to one of the trusted roots
+func verifyNotation(b []byte, digest string, opt Option) error {
+	var env jws
+	if err := json.Unmarshal(b, &env); err != nil {
+		return xerrors.Errorf("envelope decode error: %w", err)
+	}
+
+	var protected jwsProtected
+	if err := decodeSegment(env.Protected, &protected); err != nil {
+		return xerrors.Errorf("protected header error: %w", err)
+	} else if protected.ContentType != notationPayloadType {
+		return xerrors.Errorf("unsupported payload type: %s", protected.ContentType)
+	}
+
+	certs, err := parseChain(env.Header.X5C)
+	if err != nil {
+		return err
+	}
+	intermediates := x509.NewCertPool()
+	for _, c := range certs[1:] {
+		intermediates.AddCert(c)
+	}
+	if _, err = certs[0].Verify(x509.VerifyOptions{
+		Roots:         opt.Roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}); err != nil {
+		return xerrors.Errorf("certificate verification error: %w", err)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(env.Signature)
+	if err != nil {
+		return xerrors.Errorf("signature decode error: %w", err)
+	}
+	if err = verifyJWS(protected.Algorithm, certs[0].PublicKey, []byte(env.Protected+"."+env.Payload), sig); err != nil {
+		return err
+	}
+
+	var payload notationPayload
+	if err = decodeSegment(env.Payload, &payload); err != nil {
+		return xerrors.Errorf("payload error: %w", err)
+	} else if payload.TargetArtifact.Digest != digest {
+		return xerrors.Errorf("the signature is for %s", payload.TargetArtifact.Digest)
+	}
+	return nil
+}
+
+func decodeSegment(s string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return xerrors.Errorf("base64 decode error: %w", err)
+	}
+	if err = json.Unmarshal(b, v); err != nil {
+		return xerrors.Errorf("json decode error: %w", err)
+	}
+	return nil
+}
+
+func parseChain(x5c []string) ([]*x509.Certificate, error) {
+	if len(x5c) == 0 {
+		return nil, xerrors.New("no certificate found in the envelope")
+	}
+	var certs []*x509.Certificate
+	for _, s := range x5c {
+		der, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, xerrors.Errorf("certificate decode error: %w", err)
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, xerrors.Errorf("certificate parse error: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+// verifyJWS verifies the signature with the algorithms allowed by Notation
+func verifyJWS(alg string, key crypto.PublicKey, message, sig []byte) error {
+	var hash crypto.Hash
+	switch alg {
+	case "PS256", "ES256":
+		hash = crypto.SHA256
+	case "PS384", "ES384":
+		hash = crypto.SHA384
+	case "PS512", "ES512":
+		hash = crypto.SHA512
+	default:
+		return xerrors.Errorf("unsupported signature algorithm: %s", alg)
+	}
+	h := hash.New()
+	h.Write(message)
+	digest := h.Sum(nil)
+
+	var valid bool
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		valid = strings.HasPrefix(alg, "PS") &&
+			rsa.VerifyPSS(k, hash, digest, sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil
+	case *ecdsa.PublicKey:
+		// The JWS signature is the concatenation of R and S
+		size := (k.Curve.Params().BitSize + 7) / 8
+		valid = strings.HasPrefix(alg, "ES") && len(sig) == 2*size &&
+			ecdsa.Verify(k, digest, new(big.Int).SetBytes(sig[:size]), new(big.Int).SetBytes(sig[size:]))
+	}
+	if !valid {
+		return xerrors.New("the signature is not valid with the certificate")
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package signature
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"golang.org/x/xerrors"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/image"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const (
+	FormatCosign   = "cosign"
+	FormatNotation = "notation"
+)
+
+// Option holds the options for verifying image signatures
+type Option struct {
+	// PublicKey verifies cosign signatures if it is specified
+	PublicKey crypto.PublicKey
+
+	// Roots are the trusted root certificates, which verify Notation signatures if they are specified
+	Roots *x509.CertPool
+
+	Insecure bool
+}
+
+// signature is a signature of the image in any format
+type signature struct {
+	format string
+
+	// err is nil if the signature is valid
+	err error
+}
+
+// Verify fetches the signatures of the image from the registry and verifies them.
+// The image complies with the signing policy if one of the signatures is valid.
+// Otherwise, the image is reported as unsigned, or all the invalid signatures are reported.
+func Verify(imageName string, repoDigests []string, opt Option) ([]types.SignatureViolation, error) {
+	if opt.PublicKey == nil && opt.Roots == nil {
+		return nil, xerrors.New("a public key or root certificates must be specified")
+	}
+
+	ref, err := name.ParseReference(imageName)
+	if err != nil {
+		return nil, xerrors.Errorf("image name parse error: %w", err)
+	}
+
+	digest := image.RepoDigest(ref, repoDigests)
+	if digest == "" {
+		return []types.SignatureViolation{
+			unsigned("the digest of the image in the registry is unknown"),
+		}, nil
+	}
+
+	var sigs []signature
+	if opt.PublicKey != nil {
+		s, err := cosignSignatures(ref.Context(), digest, opt)
+		if err != nil {
+			return nil, xerrors.Errorf("cosign error: %w", err)
+		}
+		sigs = append(sigs, s...)
+	}
+	if opt.Roots != nil {
+		s, err := notationSignatures(ref.Context(), digest, opt)
+		if err != nil {
+			return nil, xerrors.Errorf("notation error: %w", err)
+		}
+		sigs = append(sigs, s...)
+	}
+
+	if len(sigs) == 0 {
+		return []types.SignatureViolation{unsigned("no signature found")}, nil
+	}
+
+	var violations []types.SignatureViolation
+	for _, s := range sigs {
+		if s.err == nil {
+			return nil, nil
+		}
+		violations = append(violations, types.SignatureViolation{
+			Status:   types.SignatureStatusInvalid,
+			Format:   s.format,
+			Severity: dbTypes.SeverityCritical.String(),
+			Message:  s.err.Error(),
+		})
+	}
+	return violations, nil
+}
+
+// LoadCertificates loads the PEM-encoded certificates into the pool
+func LoadCertificates(filePath string) (*x509.CertPool, error) {
+	b, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to read the certificates: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	var found bool
+	for block, rest := pem.Decode(b); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, xerrors.Errorf("certificate parse error: %w", err)
+		}
+		pool.AddCert(cert)
+		found = true
+	}
+	if !found {
+		return nil, xerrors.Errorf("no certificate found in %s", filePath)
+	}
+	return pool, nil
+}
+
+func unsigned(msg string) types.SignatureViolation {
+	return types.SignatureViolation{
+		Status:   types.SignatureStatusUnsigned,
+		Severity: dbTypes.SeverityHigh.String(),
+		Message:  msg,
+	}
+}
+
+// get returns the manifest of the reference, or nil if it doesn't exist
+func get(ref name.Reference, opt Option) (*remote.Descriptor, error) {
+	desc, err := remote.Get(ref, image.RemoteOptions(opt.Insecure)...)
+	if err != nil {
+		var terr *transport.Error
+		if errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, xerrors.Errorf("unable to get %s: %w", ref, err)
+	}
+	return desc, nil
+}
+
+// blob returns the content of the blob in the repository
+func blob(repo name.Repository, digest string, opt Option) ([]byte, error) {
+	layer, err := remote.Layer(repo.Digest(digest), image.RemoteOptions(opt.Insecure)...)
+	if err != nil {
+		return nil, xerrors.Errorf("blob error: %w", err)
+	}
+	rc, err := layer.Compressed()
+	if err != nil {
+		return nil, xerrors.Errorf("blob error: %w", err)
+	}
+	defer rc.Close()
+
+	b, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to read %s: %w", digest, err)
+	}
+	return b, nil
+}
@@ -0,0 +1,374 @@
+package signature_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/signature"
+	ttypes "github.com/aquasecurity/trivy/pkg/types"
+)
+
+const notationArtifactType = "application/vnd.cncf.notary.signature"
+
+func TestVerify(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	root, leaf, leafKey := certificates(t, "acme")
+	otherRoot, _, _ := certificates(t, "other")
+
+	// The referrers API is served only for "acme/api"
+	referrersAPI := map[string][]byte{}
+	handler := registry.New(registry.Logger(log.New(io.Discard, "", 0)))
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/v2/acme/api/referrers/") {
+			w.Header().Set("Content-Type", string(types.OCIImageIndex))
+			_, _ = w.Write(referrersAPI[strings.TrimPrefix(r.URL.Path, "/v2/acme/api/referrers/")])
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+	u, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+
+	cosignImage := u.Host + "/acme/cosign:latest"
+	cosignDigest := pushImage(t, cosignImage)
+	pushCosignSignature(t, cosignImage, cosignDigest, key)
+
+	notationImage := u.Host + "/acme/notation:latest"
+	notationDigest := pushImage(t, notationImage)
+	index := pushNotationSignature(t, notationImage, notationDigest, leaf, leafKey)
+	putManifest(t, u.Host+"/acme/notation:"+strings.ReplaceAll(notationDigest, ":", "-"), index, types.OCIImageIndex)
+
+	apiImage := u.Host + "/acme/api:latest"
+	apiDigest := pushImage(t, apiImage)
+	referrersAPI[apiDigest] = pushNotationSignature(t, apiImage, apiDigest, leaf, leafKey)
+
+	unsignedImage := u.Host + "/acme/unsigned:latest"
+	unsignedDigest := pushImage(t, unsignedImage)
+
+	tests := []struct {
+		name    string
+		image   string
+		digests []string
+		key     crypto.PublicKey
+		roots   []*x509.Certificate
+		want    []ttypes.SignatureViolation
+		wantErr string
+	}{
+		{
+			name:    "cosign",
+			image:   cosignImage,
+			digests: []string{u.Host + "/acme/cosign@" + cosignDigest},
+			key:     &key.PublicKey,
+		},
+		{
+			name:  "cosign with wrong key",
+			image: u.Host + "/acme/cosign@" + cosignDigest,
+			key:   &otherKey.PublicKey,
+			want: []ttypes.SignatureViolation{
+				{
+					Status:   ttypes.SignatureStatusInvalid,
+					Format:   signature.FormatCosign,
+					Severity: "CRITICAL",
+					Message:  "the signature is not valid with the public key",
+				},
+			},
+		},
+		{
+			name:  "notation with the referrers tag schema",
+			image: u.Host + "/acme/notation@" + notationDigest,
+			roots: []*x509.Certificate{root},
+		},
+		{
+			name:  "notation with the referrers API",
+			image: u.Host + "/acme/api@" + apiDigest,
+			roots: []*x509.Certificate{root},
+		},
+		{
+			name:  "notation with untrusted root",
+			image: u.Host + "/acme/notation@" + notationDigest,
+			key:   &key.PublicKey,
+			roots: []*x509.Certificate{otherRoot},
+			want: []ttypes.SignatureViolation{
+				{
+					Status:   ttypes.SignatureStatusInvalid,
+					Format:   signature.FormatNotation,
+					Severity: "CRITICAL",
+					Message:  "certificate verification error: x509: certificate signed by unknown authority",
+				},
+			},
+		},
+		{
+			name:  "unsigned",
+			image: u.Host + "/acme/unsigned@" + unsignedDigest,
+			key:   &key.PublicKey,
+			roots: []*x509.Certificate{root},
+			want: []ttypes.SignatureViolation{
+				{
+					Status:   ttypes.SignatureStatusUnsigned,
+					Severity: "HIGH",
+					Message:  "no signature found",
+				},
+			},
+		},
+		{
+			name:  "unknown digest",
+			image: cosignImage,
+			key:   &key.PublicKey,
+			want: []ttypes.SignatureViolation{
+				{
+					Status:   ttypes.SignatureStatusUnsigned,
+					Severity: "HIGH",
+					Message:  "the digest of the image in the registry is unknown",
+				},
+			},
+		},
+		{
+			name:    "no key",
+			image:   cosignImage,
+			wantErr: "a public key or root certificates must be specified",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt := signature.Option{PublicKey: tt.key}
+			if len(tt.roots) > 0 {
+				opt.Roots, err = signature.LoadCertificates(writeCertificates(t, tt.roots))
+				require.NoError(t, err)
+			}
+
+			got, err := signature.Verify(tt.image, tt.digests, opt)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLoadCertificates(t *testing.T) {
+	dir := t.TempDir()
+	noCert := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(noCert, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: []byte("dummy")}), 0600))
+
+	_, err := signature.LoadCertificates(noCert)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no certificate found")
+
+	_, err = signature.LoadCertificates(filepath.Join(dir, "missing.pem"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to read the certificates")
+}
+
+// certificates returns a root CA certificate and a code signing certificate issued by it
+func certificates(t *testing.T, org string) (*x509.Certificate, *x509.Certificate, *ecdsa.PrivateKey) {
+	rootKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	rootTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: org + " root"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, rootTemplate, rootTemplate, &rootKey.PublicKey, rootKey)
+	require.NoError(t, err)
+	root, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err = x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: org + " signer"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}, root, &leafKey.PublicKey, rootKey)
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return root, leaf, leafKey
+}
+
+func writeCertificates(t *testing.T, certs []*x509.Certificate) string {
+	var b []byte
+	for _, c := range certs {
+		b = append(b, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.Raw})...)
+	}
+	filePath := filepath.Join(t.TempDir(), "roots.pem")
+	require.NoError(t, os.WriteFile(filePath, b, 0600))
+	return filePath
+}
+
+func pushImage(t *testing.T, image string) string {
+	ref, err := name.ParseReference(image)
+	require.NoError(t, err)
+	img, err := random.Image(64, 1)
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(ref, img))
+
+	digest, err := img.Digest()
+	require.NoError(t, err)
+	return digest.String()
+}
+
+// pushCosignSignature pushes the signature as "cosign sign" does
+func pushCosignSignature(t *testing.T, image, digest string, key *ecdsa.PrivateKey) {
+	ref, err := name.ParseReference(image)
+	require.NoError(t, err)
+
+	payload := fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"%s"},"image":{"docker-manifest-digest":"%s"},"type":"cosign container image signature"},"optional":null}`,
+		ref.Context().Name(), digest)
+	h := sha256.Sum256([]byte(payload))
+	sig, err := ecdsa.SignASN1(rand.Reader, key, h[:])
+	require.NoError(t, err)
+
+	sigImg, err := mutate.Append(empty.Image, mutate.Addendum{
+		Layer: static.NewLayer([]byte(payload), "application/vnd.dev.cosign.simplesigning.v1+json"),
+		Annotations: map[string]string{
+			"dev.cosignproject.cosign/signature": base64.StdEncoding.EncodeToString(sig),
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(ref.Context().Tag(strings.ReplaceAll(digest, ":", "-")+".sig"), sigImg))
+}
+
+// pushNotationSignature pushes the JWS signature as "notation sign" does,
+// and returns the image index listing the signature as a referrer.
+func pushNotationSignature(t *testing.T, image, digest string, cert *x509.Certificate, key *ecdsa.PrivateKey) []byte {
+	ref, err := name.ParseReference(image)
+	require.NoError(t, err)
+
+	encode := func(v interface{}) string {
+		b, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	protected := encode(map[string]string{
+		"alg": "ES256",
+		"cty": "application/vnd.cncf.notary.payload.v1+json",
+	})
+	payload := encode(map[string]interface{}{
+		"targetArtifact": map[string]interface{}{
+			"mediaType": types.DockerManifestSchema2,
+			"digest":    digest,
+		},
+	})
+	h := sha256.Sum256([]byte(protected + "." + payload))
+	r, s, err := ecdsa.Sign(rand.Reader, key, h[:])
+	require.NoError(t, err)
+	sig := append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+
+	envelope, err := json.Marshal(map[string]interface{}{
+		"payload":   payload,
+		"protected": protected,
+		"header": map[string]interface{}{
+			"x5c": []string{base64.StdEncoding.EncodeToString(cert.Raw)},
+		},
+		"signature": base64.RawURLEncoding.EncodeToString(sig),
+	})
+	require.NoError(t, err)
+
+	layer := static.NewLayer(envelope, "application/jose+json")
+	require.NoError(t, remote.WriteLayer(ref.Context(), layer))
+	layerDigest, err := layer.Digest()
+	require.NoError(t, err)
+
+	manifest, err := json.Marshal(map[string]interface{}{
+		"schemaVersion": 2,
+		"mediaType":     types.OCIManifestSchema1,
+		"artifactType":  notationArtifactType,
+		"config": map[string]interface{}{
+			"mediaType": "application/vnd.oci.empty.v1+json",
+			"digest":    "sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a",
+			"size":      2,
+		},
+		"layers": []interface{}{
+			map[string]interface{}{
+				"mediaType": "application/jose+json",
+				"digest":    layerDigest.String(),
+				"size":      len(envelope),
+			},
+		},
+	})
+	require.NoError(t, err)
+	manifestDigest := fmt.Sprintf("sha256:%x", sha256.Sum256(manifest))
+	putManifest(t, ref.Context().Name()+"@"+manifestDigest, manifest, types.OCIManifestSchema1)
+
+	index, err := json.Marshal(map[string]interface{}{
+		"schemaVersion": 2,
+		"mediaType":     types.OCIImageIndex,
+		"manifests": []interface{}{
+			map[string]interface{}{
+				"mediaType":    types.OCIManifestSchema1,
+				"artifactType": notationArtifactType,
+				"digest":       manifestDigest,
+				"size":         len(manifest),
+			},
+		},
+	})
+	require.NoError(t, err)
+	return index
+}
+
+type rawManifest struct {
+	b         []byte
+	mediaType types.MediaType
+}
+
+func (m rawManifest) RawManifest() ([]byte, error) {
+	return m.b, nil
+}
+
+func (m rawManifest) MediaType() (types.MediaType, error) {
+	return m.mediaType, nil
+}
+
+func putManifest(t *testing.T, image string, b []byte, mediaType types.MediaType) {
+	ref, err := name.ParseReference(image)
+	require.NoError(t, err)
+	require.NoError(t, remote.Put(ref, rawManifest{b: b, mediaType: mediaType}))
+}
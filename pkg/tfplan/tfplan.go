@@ -0,0 +1,76 @@
+package tfplan
+
+import (
+	"bytes"
+	"io/fs"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/defsec/pkg/detection"
+	"github.com/aquasecurity/defsec/pkg/scanners/terraformplan/parser"
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+// configFile is the Terraform configuration converted from a plan
+const configFile = "main.tf"
+
+// Convert replaces plan files, which are the output of "terraform show -json", with the Terraform configurations
+// of the planned values so that checks evaluate the resolved variables, modules and computed attributes.
+// As Terraform configurations are evaluated per directory, each plan is converted into its own directory,
+// e.g. "tfplan.json/main.tf".
+// The converted paths are returned with the plan files so that findings are attributed to the plans by Relabel.
+func Convert(files []types.File) ([]types.File, map[string]string) {
+	converted := map[string]string{}
+	var results []types.File
+	for _, file := range files {
+		if file.Type != types.JSON || !detection.IsType(file.Path, bytes.NewReader(file.Content), detection.FileTypeTerraformPlan) {
+			results = append(results, file)
+			continue
+		}
+
+		content, err := convert(file.Content)
+		if err != nil {
+			log.Logger.Warnf("Unable to convert the Terraform plan (%s): %s", file.Path, err)
+			results = append(results, file)
+			continue
+		}
+
+		filePath := filepath.Join(file.Path, configFile)
+		converted[filePath] = file.Path
+		results = append(results, types.File{
+			Type:    types.Terraform,
+			Path:    filePath,
+			Content: content,
+		})
+	}
+	return results, converted
+}
+
+// Relabel attributes misconfigurations in the converted configurations to the plan files
+func Relabel(misconfs []types.Misconfiguration, converted map[string]string) {
+	for i, misconf := range misconfs {
+		if planFile, ok := converted[misconf.FilePath]; ok {
+			misconfs[i].FilePath = planFile
+		}
+	}
+}
+
+func convert(content []byte) ([]byte, error) {
+	plan, err := parser.New().Parse(bytes.NewReader(content))
+	if err != nil {
+		return nil, xerrors.Errorf("plan parse error: %w", err)
+	}
+
+	planFS, err := plan.ToFS()
+	if err != nil {
+		return nil, xerrors.Errorf("plan conversion error: %w", err)
+	}
+
+	b, err := fs.ReadFile(planFS, configFile)
+	if err != nil {
+		return nil, xerrors.Errorf("read error: %w", err)
+	}
+	return b, nil
+}
@@ -0,0 +1,76 @@
+package tfplan_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/tfplan"
+)
+
+func TestConvert(t *testing.T) {
+	plan, err := os.ReadFile("testdata/tfplan.json")
+	require.NoError(t, err)
+
+	packageJSON := types.File{
+		Type:    types.JSON,
+		Path:    "package.json",
+		Content: []byte(`{"name": "app"}`),
+	}
+	brokenPlan := types.File{
+		Type:    types.JSON,
+		Path:    "broken.json",
+		Content: []byte(`{"format_version": "0.2", "terraform_version": "1.0.3", "planned_values": []}`),
+	}
+
+	got, converted := tfplan.Convert([]types.File{
+		{
+			Type:    types.JSON,
+			Path:    "tfplan.json",
+			Content: plan,
+		},
+		packageJSON,
+		brokenPlan,
+	})
+
+	require.Len(t, got, 3)
+	assert.Equal(t, types.Terraform, got[0].Type)
+	assert.Equal(t, "tfplan.json/main.tf", got[0].Path)
+	assert.Contains(t, string(got[0].Content), `resource "aws_security_group" "sg" {`)
+	assert.Contains(t, string(got[0].Content), `"0.0.0.0/0",`)
+	assert.Contains(t, string(got[0].Content), `bucket = "tfsec-plan-testing"`) // resolved from the variable
+	assert.Equal(t, packageJSON, got[1])
+	assert.Equal(t, brokenPlan, got[2])
+
+	assert.Equal(t, map[string]string{"tfplan.json/main.tf": "tfplan.json"}, converted)
+}
+
+func TestRelabel(t *testing.T) {
+	misconfs := []types.Misconfiguration{
+		{
+			FileType: types.Terraform,
+			FilePath: "tfplan.json/main.tf",
+		},
+		{
+			FileType: types.Terraform,
+			FilePath: "main.tf",
+		},
+	}
+
+	tfplan.Relabel(misconfs, map[string]string{"tfplan.json/main.tf": "tfplan.json"})
+
+	want := []types.Misconfiguration{
+		{
+			FileType: types.Terraform,
+			FilePath: "tfplan.json",
+		},
+		{
+			FileType: types.Terraform,
+			FilePath: "main.tf",
+		},
+	}
+	assert.Equal(t, want, misconfs)
+}
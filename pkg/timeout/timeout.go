@@ -0,0 +1,62 @@
+package timeout
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Phase represents a phase of scanning which has its own timeout
+type Phase string
+
+const (
+	PhaseDBUpdate Phase = "DB update"
+	PhasePull     Phase = "pull"
+	PhaseAnalysis Phase = "analysis"
+	PhasePolicy   Phase = "policy evaluation"
+	PhaseTarget   Phase = "target detection"
+)
+
+// ExceededError is returned when a phase doesn't finish within its timeout
+type ExceededError struct {
+	Phase   Phase
+	Timeout time.Duration
+	Err     error
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("%s exceeded the timeout (%s): %s", e.Phase, e.Timeout, e.Err)
+}
+
+func (e *ExceededError) Unwrap() error {
+	return e.Err
+}
+
+// Run runs the phase with the timeout. The phase is not limited if the timeout is not positive.
+// The context passed to f is canceled once f returns.
+func Run(ctx context.Context, phase Phase, timeout time.Duration, f func(context.Context) error) error {
+	cancel, err := RunLazy(ctx, phase, timeout, f)
+	cancel()
+	return err
+}
+
+// RunLazy is the same as Run, except that the context passed to f is not canceled until the returned function is called,
+// because artifacts may use it lazily after the phase, e.g. images saved from the Docker daemon on demand.
+func RunLazy(ctx context.Context, phase Phase, timeout time.Duration, f func(context.Context) error) (context.CancelFunc, error) {
+	if timeout <= 0 {
+		return func() {}, f(ctx)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	timer := time.AfterFunc(timeout, cancel)
+
+	err := f(ctx)
+	if !timer.Stop() && err != nil {
+		return cancel, &ExceededError{
+			Phase:   phase,
+			Timeout: timeout,
+			Err:     err,
+		}
+	}
+	return cancel, err
+}
@@ -0,0 +1,107 @@
+package timeout_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/timeout"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		f       func(context.Context) error
+		wantErr string
+		want    *timeout.ExceededError
+	}{
+		{
+			name:    "finished within the timeout",
+			timeout: time.Minute,
+			f: func(ctx context.Context) error {
+				return nil
+			},
+		},
+		{
+			name:    "no timeout",
+			timeout: 0,
+			f: func(ctx context.Context) error {
+				time.Sleep(10 * time.Millisecond)
+				return ctx.Err()
+			},
+		},
+		{
+			name:    "exceeded",
+			timeout: 10 * time.Millisecond,
+			f: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			wantErr: "pull exceeded the timeout (10ms): context canceled",
+			want: &timeout.ExceededError{
+				Phase:   timeout.PhasePull,
+				Timeout: 10 * time.Millisecond,
+				Err:     context.Canceled,
+			},
+		},
+		{
+			name:    "other error",
+			timeout: time.Minute,
+			f: func(ctx context.Context) error {
+				return errors.New("unauthorized")
+			},
+			wantErr: "unauthorized",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := timeout.Run(context.Background(), timeout.PhasePull, tt.timeout, tt.f)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.wantErr)
+
+			var exceeded *timeout.ExceededError
+			if tt.want == nil {
+				assert.False(t, errors.As(err, &exceeded))
+				return
+			}
+			require.True(t, errors.As(err, &exceeded))
+			assert.Equal(t, tt.want, exceeded)
+		})
+	}
+}
+
+func TestRun_ContextAfterPhase(t *testing.T) {
+	var phaseCtx context.Context
+	err := timeout.Run(context.Background(), timeout.PhasePolicy, time.Minute, func(ctx context.Context) error {
+		phaseCtx = ctx
+		return nil
+	})
+	require.NoError(t, err)
+
+	// The context is released once the phase finishes
+	assert.ErrorIs(t, phaseCtx.Err(), context.Canceled)
+}
+
+func TestRunLazy(t *testing.T) {
+	var phaseCtx context.Context
+	cancel, err := timeout.RunLazy(context.Background(), timeout.PhasePull, 10*time.Millisecond, func(ctx context.Context) error {
+		phaseCtx = ctx
+		return nil
+	})
+	require.NoError(t, err)
+
+	// The context is still available for lazy use after the phase
+	time.Sleep(20 * time.Millisecond)
+	assert.NoError(t, phaseCtx.Err())
+
+	cancel()
+	assert.ErrorIs(t, phaseCtx.Err(), context.Canceled)
+}
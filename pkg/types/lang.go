@@ -0,0 +1,66 @@
+package types
+
+// Types of language-specific packages which are not supported by fanal.
+// They are used as both the analyzer type and the application type.
+const (
+	// RustBinary is the type of Rust binaries built with cargo-auditable
+	RustBinary = "rustbinary"
+
+	// Cocoapods is the type of Podfile.lock
+	Cocoapods = "cocoapods"
+
+	// Swift is the type of Package.resolved of Swift Package Manager
+	Swift = "swift"
+
+	// Carthage is the type of Cartfile.resolved
+	Carthage = "carthage"
+
+	// Pub is the type of pubspec.lock of Dart and Flutter
+	Pub = "pub"
+
+	// CondaPkg is the type of conda packages installed in environments, i.e. conda-meta/*.json
+	CondaPkg = "conda-pkg"
+
+	// CondaEnv is the type of conda environment files, i.e. environment.yml
+	CondaEnv = "conda-environment"
+
+	// CondaLock is the type of lock files generated by conda-lock
+	CondaLock = "conda-lock"
+
+	// BazelMaven is the type of maven_install.json pinned by rules_jvm_external of Bazel
+	BazelMaven = "bazel-maven"
+
+	// BazelModule is the type of MODULE.bazel.lock of Bazel
+	BazelModule = "bazel-module"
+
+	// Pnpm is the type of pnpm-lock.yaml
+	Pnpm = "pnpm"
+
+	// DotNetCore is the type of *.deps.json of .NET applications
+	DotNetCore = "dotnet-core"
+
+	// Gradle is the type of gradle.lockfile
+	Gradle = "gradle"
+
+	// GradleCatalog is the type of version catalogs of Gradle, e.g. libs.versions.toml
+	GradleCatalog = "gradle-catalog"
+
+	// Conan is the type of conan.lock of Conan
+	Conan = "conan"
+
+	// Vcpkg is the type of vcpkg.json and packages installed by vcpkg
+	Vcpkg = "vcpkg"
+)
+
+// YarnPnP is the type of the analyzer for packages in the Yarn cache used by Plug'n'Play installs.
+// The packages are reported as node-pkg together with those in node_modules.
+const YarnPnP = "yarn-pnp"
+
+// PythonLocalPkg is the type of Python packages installed from local directories, e.g. "pip install -e .".
+// It is recorded by the packaging analyzer and dropped before detection together with the packages.
+const PythonLocalPkg = "python-local-pkg"
+
+// DevDependencies is the type of development dependencies in lock files.
+// It is recorded by analyzers together with the application and excluded before detection
+// unless "--include-dev-deps" is specified.
+const DevDependencies = "dev-dependencies"
@@ -0,0 +1,38 @@
+package types
+
+// LicenseCategory represents how restrictive a license is
+type LicenseCategory string
+
+const (
+	CategoryForbidden    LicenseCategory = "forbidden"
+	CategoryRestricted   LicenseCategory = "restricted"
+	CategoryReciprocal   LicenseCategory = "reciprocal"
+	CategoryNotice       LicenseCategory = "notice"
+	CategoryPermissive   LicenseCategory = "permissive"
+	CategoryUnencumbered LicenseCategory = "unencumbered"
+	CategoryUnknown      LicenseCategory = "unknown"
+)
+
+// DetectedLicense holds a license of a package or a file
+type DetectedLicense struct {
+	// e.g. HIGH
+	Severity string
+
+	// e.g. restricted
+	Category LicenseCategory
+
+	// PkgName is empty for licenses detected in files
+	PkgName string `json:",omitempty"`
+
+	// FilePath is empty for licenses of packages
+	FilePath string `json:",omitempty"`
+
+	// e.g. GPL-3.0
+	Name string
+
+	// Confidence is between 0 and 1
+	Confidence float64
+
+	// e.g. https://spdx.org/licenses/GPL-3.0.html
+	Link string `json:",omitempty"`
+}
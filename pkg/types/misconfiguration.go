@@ -15,6 +15,7 @@ type DetectedMisconfiguration struct {
 	Severity      string               `json:",omitempty"`
 	PrimaryURL    string               `json:",omitempty"`
 	References    []string             `json:",omitempty"`
+	Owner         string               `json:",omitempty"`
 	Status        MisconfStatus        `json:",omitempty"`
 	Layer         ftypes.Layer         `json:",omitempty"`
 	CauseMetadata ftypes.CauseMetadata `json:",omitempty"`
@@ -0,0 +1,24 @@
+package types
+
+// ProvenanceRule represents the check of the provenance policy
+type ProvenanceRule string
+
+const (
+	RuleAttestation ProvenanceRule = "attestation"
+	RuleSignature   ProvenanceRule = "signature"
+	RuleSubject     ProvenanceRule = "subject"
+	RuleBuilder     ProvenanceRule = "builder"
+	RuleSource      ProvenanceRule = "source"
+)
+
+// ProvenanceViolation holds a violation of the provenance policy by the image
+type ProvenanceViolation struct {
+	// e.g. builder
+	Rule ProvenanceRule
+
+	// e.g. HIGH
+	Severity string
+
+	// e.g. builder "https://example.com/builder@v1" is not allowed
+	Message string
+}
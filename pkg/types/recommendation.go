@@ -0,0 +1,13 @@
+package types
+
+// Recommendation represents an upgrade of the base image to a newer tag
+type Recommendation struct {
+	BaseImage string
+	Candidate string
+
+	// Remediated is the OS vulnerabilities in the base image which the candidate doesn't have
+	Remediated []string `json:",omitempty"`
+
+	// Introduced is the OS vulnerabilities in the candidate which the base image doesn't have
+	Introduced []string `json:",omitempty"`
+}
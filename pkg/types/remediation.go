@@ -0,0 +1,29 @@
+package types
+
+// Remediation represents the upgrade of a direct dependency remediating vulnerabilities
+type Remediation struct {
+	// The direct dependency to be upgraded, e.g. express@4.17.1.
+	// PkgID is empty if the lock file doesn't have IDs, e.g. go.mod
+	PkgID            string `json:",omitempty"`
+	PkgName          string
+	InstalledVersion string
+
+	// The minimal version fixing the vulnerabilities of the direct dependency itself.
+	// It is empty if only transitive dependencies are vulnerable.
+	FixedVersion     string   `json:",omitempty"`
+	VulnerabilityIDs []string `json:",omitempty"`
+
+	// Transitive dependencies which must be upgraded through the direct dependency
+	Dependencies []DependencyRemediation `json:",omitempty"`
+}
+
+// DependencyRemediation represents the upgrade of a transitive dependency
+type DependencyRemediation struct {
+	PkgID            string `json:",omitempty"`
+	PkgName          string
+	InstalledVersion string
+
+	// The minimal version fixing all the vulnerabilities of the dependency
+	FixedVersion     string
+	VulnerabilityIDs []string
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"encoding/json"
+	"sort"
 
 	v1 "github.com/google/go-containerregistry/pkg/v1" // nolint: goimports
 
@@ -15,6 +16,11 @@ type Report struct {
 	ArtifactType  ftypes.ArtifactType `json:",omitempty"`
 	Metadata      Metadata            `json:",omitempty"`
 	Results       Results             `json:",omitempty"`
+
+	Recommendations []Recommendation `json:",omitempty"`
+
+	// Trivy records how the report was produced
+	Trivy *ScanMetadata `json:",omitempty"`
 }
 
 // Metadata represents a metadata of artifact
@@ -28,6 +34,40 @@ type Metadata struct {
 	RepoTags    []string      `json:",omitempty"`
 	RepoDigests []string      `json:",omitempty"`
 	ImageConfig v1.ConfigFile `json:",omitempty"`
+	BaseImage   *BaseImage    `json:",omitempty"`
+
+	// OCI artifact other than container images, e.g. helm-chart and wasm
+	ArtifactKind string `json:",omitempty"`
+
+	// Labels attached to the scan with --label, e.g. team=payments
+	Labels map[string]string `json:",omitempty"`
+}
+
+// LabelPairs returns the labels as KEY=VALUE sorted by the key
+func (m Metadata) LabelPairs() []string {
+	var keys []string
+	for k := range m.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+m.Labels[k])
+	}
+	return pairs
+}
+
+// BaseImage represents the probable base image of the container image
+type BaseImage struct {
+	// Name is empty when the base image is guessed from the history
+	Name string `json:",omitempty"`
+
+	// DiffIDs are the layers of the base image
+	DiffIDs []string
+
+	// e.g. index, history
+	Method string
 }
 
 // Results to hold list of Result
@@ -36,11 +76,16 @@ type Results []Result
 type ResultClass string
 
 const (
-	ClassOSPkg   = "os-pkgs"
-	ClassLangPkg = "lang-pkgs"
-	ClassConfig  = "config"
-	ClassSecret  = "secret"
-	ClassCustom  = "custom"
+	ClassOSPkg       = "os-pkgs"
+	ClassLangPkg     = "lang-pkgs"
+	ClassKernelPkg   = "kernel-pkgs"
+	ClassConfig      = "config"
+	ClassSecret      = "secret"
+	ClassLicense     = "license"
+	ClassLicenseFile = "license-file"
+	ClassCustom      = "custom"
+	ClassProvenance  = "provenance"
+	ClassSignature   = "signature"
 )
 
 // Result holds a target and detected vulnerabilities
@@ -52,8 +97,21 @@ type Result struct {
 	Vulnerabilities   []DetectedVulnerability    `json:"Vulnerabilities,omitempty"`
 	MisconfSummary    *MisconfSummary            `json:"MisconfSummary,omitempty"`
 	Misconfigurations []DetectedMisconfiguration `json:"Misconfigurations,omitempty"`
-	Secrets           []ftypes.SecretFinding     `json:"Secrets,omitempty"`
+	Secrets           []DetectedSecret           `json:"Secrets,omitempty"`
+	Licenses          []DetectedLicense          `json:"Licenses,omitempty"`
+	Remediations      []Remediation              `json:"Remediations,omitempty"`
 	CustomResources   []ftypes.CustomResource    `json:"CustomResources,omitempty"`
+	Suppressions      []Suppression              `json:"Suppressions,omitempty"`
+
+	ProvenanceViolations []ProvenanceViolation `json:"ProvenanceViolations,omitempty"`
+	SignatureViolations  []SignatureViolation  `json:"SignatureViolations,omitempty"`
+
+	// Owners are the owners of the target file assigned by CODEOWNERS or --owners-file
+	Owners []string `json:"Owners,omitempty"`
+
+	// Error is the error of the target which failed to be analyzed or detected with --continue-on-error,
+	// in which case the findings of the target may be missing.
+	Error string `json:"Error,omitempty"`
 }
 
 func (r *Result) MarshalJSON() ([]byte, error) {
@@ -89,10 +147,15 @@ func (s MisconfSummary) Empty() bool {
 	return s.Successes == 0 && s.Failures == 0 && s.Exceptions == 0
 }
 
-// Failed returns whether the result includes any vulnerabilities or misconfigurations
+// Failed returns whether the result includes any vulnerabilities, misconfigurations, licenses or violations of
+// provenance and signing policies, or errors of targets whose findings may be missing
 func (results Results) Failed() bool {
 	for _, r := range results {
-		if len(r.Vulnerabilities) > 0 {
+		if r.Error != "" {
+			return true
+		}
+		if len(r.Vulnerabilities) > 0 || len(r.Licenses) > 0 ||
+			len(r.ProvenanceViolations) > 0 || len(r.SignatureViolations) > 0 {
 			return true
 		}
 		for _, m := range r.Misconfigurations {
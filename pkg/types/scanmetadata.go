@@ -0,0 +1,42 @@
+package types
+
+import "time"
+
+// ScanMetadata records how the report was produced, so that two reports can be compared and audited
+type ScanMetadata struct {
+	// Version is the version of Trivy
+	Version string `json:",omitempty"`
+
+	// DB is empty when the vulnerability DB is not used, e.g. in client/server mode
+	DB *DBMetadata `json:",omitempty"`
+
+	// Analyzers are the enabled analyzers and their versions
+	Analyzers map[string]int `json:",omitempty"`
+
+	ChecksBundles []ChecksBundle `json:",omitempty"`
+
+	// Flags are the flags given by the command line, environment variables or the config file.
+	// The values of the flags having credentials are redacted.
+	Flags map[string]string `json:",omitempty"`
+}
+
+// DBMetadata represents the vulnerability DB used for the scan
+type DBMetadata struct {
+	Repository string
+
+	// Digest is empty if the DB was downloaded by an old version of Trivy
+	Digest string `json:",omitempty"`
+
+	// Snapshot is true if the DB is pinned by --db-snapshot
+	Snapshot bool `json:",omitempty"`
+
+	SchemaVersion int
+	UpdatedAt     time.Time
+	DownloadedAt  time.Time
+}
+
+// ChecksBundle represents a bundle of custom misconfiguration checks used for the scan
+type ChecksBundle struct {
+	Reference string
+	Digest    string `json:",omitempty"`
+}
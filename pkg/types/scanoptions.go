@@ -1,9 +1,29 @@
 package types
 
+import "time"
+
 // ScanOptions holds the attributes for scanning vulnerabilities
 type ScanOptions struct {
 	VulnType            []string
 	SecurityChecks      []string
 	ScanRemovedPackages bool
 	ListAllPackages     bool
+	IncludeDevDeps      bool
+	LicenseCategories   map[LicenseCategory][]string
+
+	// Parallel is the number of targets, e.g. lock files, whose vulnerabilities are detected concurrently.
+	// The default is used if it is not positive.
+	Parallel int
+
+	// TargetTimeout limits the vulnerability detection of each target if positive.
+	TargetTimeout time.Duration
+
+	// ContinueOnError records the errors of targets failing vulnerability detection in their results
+	// instead of aborting the scan. It is not passed to the server in client/server mode.
+	ContinueOnError bool
+
+	// FailFast reports whether the results have findings failing the scan.
+	// If it is set, the remaining checks are skipped once it returns true.
+	// It is not passed to the server in client/server mode.
+	FailFast func(Results) bool
 }
@@ -0,0 +1,44 @@
+package types
+
+import (
+	"time"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+)
+
+// DetectedSecret holds a detected secret with information not available in fanal
+type DetectedSecret struct {
+	ftypes.SecretFinding
+
+	// Fingerprint identifies the secret in the file without exposing it.
+	// It doesn't change when the secret moves to another line.
+	Fingerprint string `json:",omitempty"`
+
+	// Verification is empty if the secret was not verified
+	Verification SecretVerification `json:",omitempty"`
+
+	// History is filled only when the git history is scanned
+	History *SecretHistory `json:",omitempty"`
+}
+
+// SecretVerification represents whether a detected secret is live
+type SecretVerification string
+
+const (
+	// SecretVerified represents the secret was accepted by the provider
+	SecretVerified SecretVerification = "VERIFIED"
+
+	// SecretUnverified represents the secret was rejected by the provider
+	SecretUnverified SecretVerification = "UNVERIFIED"
+)
+
+// SecretHistory holds the commit which introduced the secret
+type SecretHistory struct {
+	Commit string
+	Author string
+	Date   time.Time
+
+	// ExistsAtHead is false if the secret was removed after the commit.
+	// Note that the secret still needs to be revoked as it remains in the history.
+	ExistsAtHead bool
+}
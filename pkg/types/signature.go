@@ -0,0 +1,24 @@
+package types
+
+// SignatureStatus represents why the image does not comply with the signing policy
+type SignatureStatus string
+
+const (
+	SignatureStatusUnsigned SignatureStatus = "unsigned"
+	SignatureStatusInvalid  SignatureStatus = "invalid"
+)
+
+// SignatureViolation holds an unsigned image or an invalid signature of the image
+type SignatureViolation struct {
+	// e.g. invalid
+	Status SignatureStatus
+
+	// e.g. cosign, notation. It is empty for unsigned images.
+	Format string `json:",omitempty"`
+
+	// e.g. CRITICAL
+	Severity string
+
+	// e.g. the signature is not valid with the public key
+	Message string
+}
@@ -0,0 +1,18 @@
+package types
+
+// SuppressionType represents the type of suppressed findings
+type SuppressionType string
+
+const (
+	SuppressionMisconfiguration SuppressionType = "misconfiguration"
+	SuppressionSecret           SuppressionType = "secret"
+)
+
+// Suppression represents a finding suppressed by an inline ignore comment
+type Suppression struct {
+	Type      SuppressionType
+	ID        string
+	StartLine int
+	Reason    string `json:",omitempty"`
+	ExpiresAt string `json:",omitempty"`
+}
@@ -16,6 +16,9 @@ const (
 	// VulnTypeLibrary is a vulnerability type of programming language dependencies
 	VulnTypeLibrary = VulnType("library")
 
+	// VulnTypeKernel is a vulnerability type of the Linux kernel and firmware packages
+	VulnTypeKernel = VulnType("kernel")
+
 	// SecurityCheckUnknown is a security check of unknown
 	SecurityCheckUnknown = SecurityCheck("unknown")
 
@@ -27,9 +30,12 @@ const (
 
 	// SecurityCheckSecret is a security check of secrets
 	SecurityCheckSecret = SecurityCheck("secret")
+
+	// SecurityCheckLicense is a security check of licenses
+	SecurityCheckLicense = SecurityCheck("license")
 )
 
 var (
-	VulnTypes      = []string{VulnTypeOS, VulnTypeLibrary}
-	SecurityChecks = []string{SecurityCheckVulnerability, SecurityCheckConfig, SecurityCheckSecret}
+	VulnTypes      = []string{VulnTypeOS, VulnTypeLibrary, VulnTypeKernel}
+	SecurityChecks = []string{SecurityCheckVulnerability, SecurityCheckConfig, SecurityCheckSecret, SecurityCheckLicense}
 )
@@ -14,6 +14,7 @@ type DetectedVulnerability struct {
 	PkgPath          string         `json:",omitempty"` // It will be filled in the case of language-specific packages such as egg/wheel and gemspec
 	InstalledVersion string         `json:",omitempty"`
 	FixedVersion     string         `json:",omitempty"`
+	Status           string         `json:",omitempty"` // The state of the unfixed vulnerability in the distribution, e.g. no-dsa and postponed of Debian
 	Layer            ftypes.Layer   `json:",omitempty"`
 	SeveritySource   types.SourceID `json:",omitempty"`
 	PrimaryURL       string         `json:",omitempty"`
@@ -21,6 +22,15 @@ type DetectedVulnerability struct {
 	// DataSource holds where the advisory comes from
 	DataSource *types.DataSource `json:",omitempty"`
 
+	// Buildpack holds the Cloud Native Buildpack contributing the package
+	Buildpack *Buildpack `json:",omitempty"`
+
+	// IntroducedBy tells whether the package comes from the base image or the layers added on it
+	IntroducedBy IntroducedBy `json:",omitempty"`
+
+	// Risk is the composite risk score for prioritization, computed with --risk-score
+	Risk *Risk `json:",omitempty"`
+
 	// Custom is for extensibility and not supposed to be used in OSS
 	Custom interface{} `json:",omitempty"`
 
@@ -28,6 +38,31 @@ type DetectedVulnerability struct {
 	types.Vulnerability
 }
 
+// Buildpack represents a buildpack of Cloud Native Buildpacks
+type Buildpack struct {
+	ID      string `json:",omitempty"`
+	Version string `json:",omitempty"`
+}
+
+// Risk represents the composite risk score of a vulnerability and the factors of the score
+type Risk struct {
+	// Score is from 0 to 100, where the weights of the factors without data are excluded
+	Score float64
+
+	CVSS      float64 `json:",omitempty"`
+	EPSS      float64 `json:",omitempty"`
+	KEV       bool    `json:",omitempty"`
+	Reachable bool    `json:",omitempty"`
+}
+
+// IntroducedBy represents the part of the image introducing the vulnerable package
+type IntroducedBy string
+
+const (
+	IntroducedByBaseImage   IntroducedBy = "base-image"
+	IntroducedByAddedLayers IntroducedBy = "added-layers"
+)
+
 // BySeverity implements sort.Interface based on the Severity field.
 type BySeverity []DetectedVulnerability
 
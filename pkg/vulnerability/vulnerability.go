@@ -8,6 +8,7 @@ import (
 	"github.com/aquasecurity/trivy-db/pkg/db"
 	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
+	"github.com/aquasecurity/trivy/pkg/advisory"
 	"github.com/aquasecurity/trivy/pkg/log"
 	"github.com/aquasecurity/trivy/pkg/types"
 )
@@ -21,6 +22,8 @@ var (
 		vulnerability.OracleOVAL:       {"http://linux.oracle.com/errata", "https://linux.oracle.com/errata"},
 		vulnerability.NodejsSecurityWg: {"https://www.npmjs.com", "https://hackerone.com"},
 		vulnerability.RubySec:          {"https://groups.google.com"},
+		advisory.SourceID:              {"https://", "http://"}, // the first reference of advisory feeds
+		advisory.OSVSourceID:           {"https://", "http://"},
 	}
 )
 
@@ -44,6 +47,15 @@ func NewClient(dbc db.Config) Client {
 func (c Client) FillInfo(vulns []types.DetectedVulnerability) {
 	for i := range vulns {
 		vulnID := vulns[i].VulnerabilityID
+
+		// Advisory feeds and the OSV API have their own details of vulnerabilities
+		if vuln, source, ok := advisoryVulnerability(vulns[i]); ok {
+			vulns[i].Vulnerability = vuln
+			vulns[i].SeveritySource = source
+			vulns[i].PrimaryURL = c.getPrimaryURL(vulnID, vuln.References, source)
+			continue
+		}
+
 		vuln, err := c.dbc.GetVulnerability(vulnID)
 		if err != nil {
 			log.Logger.Warnf("Error while getting vulnerability details: %s", err)
@@ -76,6 +88,21 @@ func (c Client) FillInfo(vulns []types.DetectedVulnerability) {
 	}
 }
 
+func advisoryVulnerability(v types.DetectedVulnerability) (dbTypes.Vulnerability, dbTypes.SourceID, bool) {
+	if v.DataSource == nil {
+		return dbTypes.Vulnerability{}, "", false
+	}
+	var vuln dbTypes.Vulnerability
+	var ok bool
+	switch v.DataSource.ID {
+	case advisory.SourceID:
+		vuln, ok = advisory.Vulnerability(v.VulnerabilityID)
+	case advisory.OSVSourceID:
+		vuln, ok = advisory.OnlineVulnerability(v.VulnerabilityID)
+	}
+	return vuln, v.DataSource.ID, ok
+}
+
 func (c Client) getVendorSeverity(vuln *dbTypes.Vulnerability, source dbTypes.SourceID) (string, dbTypes.SourceID) {
 	if vs, ok := vuln.VendorSeverity[source]; ok {
 		return vs.String(), source
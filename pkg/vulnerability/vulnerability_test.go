@@ -7,16 +7,19 @@ import (
 	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
 	"github.com/aquasecurity/trivy-db/pkg/utils"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
+	"github.com/aquasecurity/trivy/pkg/advisory"
 	"github.com/aquasecurity/trivy/pkg/dbtest"
 	"github.com/aquasecurity/trivy/pkg/types"
 	vuln "github.com/aquasecurity/trivy/pkg/vulnerability"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestClient_FillInfo(t *testing.T) {
 	tests := []struct {
 		name                    string
 		fixtures                []string
+		feed                    string
 		vulns                   []types.DetectedVulnerability
 		expectedVulnerabilities []types.DetectedVulnerability
 	}{
@@ -221,6 +224,30 @@ func TestClient_FillInfo(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:     "happy path, with advisory feed",
+			fixtures: []string{"testdata/fixtures/vulnerability.yaml"},
+			feed:     "testdata/feed",
+			vulns: []types.DetectedVulnerability{
+				{
+					VulnerabilityID: "INTERNAL-2022-0001",
+					DataSource:      &dbTypes.DataSource{ID: advisory.SourceID, Name: "acme"},
+				},
+			},
+			expectedVulnerabilities: []types.DetectedVulnerability{
+				{
+					VulnerabilityID: "INTERNAL-2022-0001",
+					DataSource:      &dbTypes.DataSource{ID: advisory.SourceID, Name: "acme"},
+					Vulnerability: dbTypes.Vulnerability{
+						Title:      "Prototype pollution in @acme/utils",
+						Severity:   dbTypes.SeverityHigh.String(),
+						References: []string{"https://security.acme.example/INTERNAL-2022-0001"},
+					},
+					SeveritySource: advisory.SourceID,
+					PrimaryURL:     "https://security.acme.example/INTERNAL-2022-0001",
+				},
+			},
+		},
 		{
 			name:     "GetVulnerability returns an error",
 			fixtures: []string{"testdata/fixtures/sad.yaml"},
@@ -238,6 +265,13 @@ func TestClient_FillInfo(t *testing.T) {
 			dbtest.InitDB(t, tt.fixtures)
 			defer db.Close()
 
+			if tt.feed != "" {
+				feed, err := advisory.Load(tt.feed, "acme")
+				require.NoError(t, err)
+				advisory.Register(feed)
+				defer advisory.Reset()
+			}
+
 			c := vuln.NewClient(db.Config{})
 			c.FillInfo(tt.vulns)
 			assert.Equal(t, tt.expectedVulnerabilities, tt.vulns, tt.name)
@@ -0,0 +1,190 @@
+package watch
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Event types
+const (
+	EventScan  = "scan"
+	EventError = "error"
+)
+
+// Event is written as a line of JSON every time the directory is scanned
+type Event struct {
+	Type string
+	Time time.Time
+
+	// Changed are the files changed since the previous scan, which are relative to the root.
+	// It is empty for the first scan.
+	Changed []string `json:",omitempty"`
+
+	// Report has the findings of all the files, not only the changed ones
+	Report *types.Report `json:",omitempty"`
+
+	Error string `json:",omitempty"`
+}
+
+// SkipFunc tells whether the path relative to the root is skipped
+type SkipFunc func(relPath string, dir bool) bool
+
+// Option holds the options of Watcher
+type Option struct {
+	Interval time.Duration
+	Skip     SkipFunc
+}
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watcher detects changes of files in the directory by polling,
+// which works in the same way on any OS and file system, e.g. network file systems and containers.
+type Watcher struct {
+	root     string
+	interval time.Duration
+	skip     SkipFunc
+	files    map[string]fileState
+}
+
+// New returns a watcher with the current state of the directory
+func New(root string, opt Option) (*Watcher, error) {
+	w := &Watcher{
+		root:     root,
+		interval: opt.Interval,
+		skip:     opt.Skip,
+	}
+	files, err := w.walk()
+	if err != nil {
+		return nil, err
+	}
+	w.files = files
+	return w, nil
+}
+
+// Changes returns the files created, modified or removed since the last call, which are relative to the root
+func (w *Watcher) Changes() ([]string, error) {
+	files, err := w.walk()
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for p, s := range files {
+		if old, ok := w.files[p]; !ok || old != s {
+			changed = append(changed, p)
+		}
+	}
+	for p := range w.files {
+		if _, ok := files[p]; !ok {
+			changed = append(changed, p)
+		}
+	}
+	sort.Strings(changed)
+
+	w.files = files
+	return changed, nil
+}
+
+// Run calls the function with the changed files until the context is canceled.
+// Changes are reported once the files stop changing for an interval, so that saving several files at once results in one call.
+func (w *Watcher) Run(ctx context.Context, fn func(changed []string) error) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	var pending []string
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		changed, err := w.Changes()
+		if err != nil {
+			return xerrors.Errorf("watch error: %w", err)
+		}
+		if len(changed) > 0 {
+			log.Logger.Debugf("Changed files: %v", changed)
+			pending = merge(pending, changed)
+			continue
+		}
+		if len(pending) == 0 {
+			continue
+		}
+
+		if err = fn(pending); err != nil {
+			return err
+		}
+		pending = nil
+	}
+}
+
+func (w *Watcher) walk() (map[string]fileState, error) {
+	files := map[string]fileState{}
+	err := filepath.WalkDir(w.root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// The file may be removed during the walk
+			log.Logger.Debugf("Watch error: %s", err)
+			return nil
+		}
+
+		rel, err := filepath.Rel(w.root, p)
+		if err != nil {
+			return xerrors.Errorf("filepath rel error: %w", err)
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == "." {
+			return nil
+		}
+
+		if d.IsDir() {
+			if d.Name() == ".git" || (w.skip != nil && w.skip(rel, true)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || (w.skip != nil && w.skip(rel, false)) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		files[rel] = fileState{
+			modTime: info.ModTime(),
+			size:    info.Size(),
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("walk error: %w", err)
+	}
+	return files, nil
+}
+
+func merge(files, others []string) []string {
+	seen := map[string]struct{}{}
+	for _, f := range files {
+		seen[f] = struct{}{}
+	}
+	for _, f := range others {
+		if _, ok := seen[f]; !ok {
+			files = append(files, f)
+			seen[f] = struct{}{}
+		}
+	}
+	sort.Strings(files)
+	return files
+}
@@ -0,0 +1,113 @@
+package watch_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/watch"
+)
+
+func TestWatcher_Changes(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(t *testing.T, root string)
+		skip   watch.SkipFunc
+		want   []string
+	}{
+		{
+			name:   "no changes",
+			change: func(*testing.T, string) {},
+		},
+		{
+			name: "created",
+			change: func(t *testing.T, root string) {
+				writeFile(t, root, "app/go.mod", "module app")
+			},
+			want: []string{"app/go.mod"},
+		},
+		{
+			name: "modified",
+			change: func(t *testing.T, root string) {
+				writeFile(t, root, "requirements.txt", "flask==2.2.2\ndjango==4.1.0\n")
+			},
+			want: []string{"requirements.txt"},
+		},
+		{
+			name: "removed",
+			change: func(t *testing.T, root string) {
+				require.NoError(t, os.RemoveAll(filepath.Join(root, "frontend")))
+			},
+			want: []string{"frontend/package-lock.json"},
+		},
+		{
+			name: "skipped",
+			change: func(t *testing.T, root string) {
+				writeFile(t, root, "testdata/requirements.txt", "django==4.1.0\n")
+				writeFile(t, root, ".git/HEAD", "ref: refs/heads/feature")
+				writeFile(t, root, "go.sum", "")
+			},
+			skip: func(relPath string, dir bool) bool {
+				return relPath == "testdata" && dir
+			},
+			want: []string{"go.sum"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeFile(t, root, "requirements.txt", "flask==2.2.2\n")
+			writeFile(t, root, "frontend/package-lock.json", "{}")
+			writeFile(t, root, ".git/HEAD", "ref: refs/heads/main")
+
+			w, err := watch.New(root, watch.Option{Skip: tt.skip})
+			require.NoError(t, err)
+
+			tt.change(t, root)
+
+			got, err := w.Changes()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			// The changes are reported once
+			got, err = w.Changes()
+			require.NoError(t, err)
+			assert.Empty(t, got)
+		})
+	}
+}
+
+func TestWatcher_Run(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "requirements.txt", "flask==2.2.2\n")
+
+	w, err := watch.New(root, watch.Option{Interval: 10 * time.Millisecond})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	writeFile(t, root, "requirements.txt", "flask==2.2.2\ndjango==4.1.0\n")
+	writeFile(t, root, "go.mod", "module app")
+
+	var got []string
+	err = w.Run(ctx, func(changed []string) error {
+		got = changed
+		cancel()
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go.mod", "requirements.txt"}, got)
+}
+
+func writeFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	p := filepath.Join(root, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+	require.NoError(t, os.WriteFile(p, []byte(content), 0644))
+}
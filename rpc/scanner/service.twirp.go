@@ -1091,38 +1091,39 @@ func callClientError(ctx context.Context, h *twirp.ClientHooks, err twirp.Error)
 }
 
 var twirpFileDescriptor0 = []byte{
-	// 514 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x53, 0x3d, 0x6f, 0xdb, 0x30,
-	0x10, 0x85, 0x6c, 0xc7, 0x1f, 0xa7, 0xa2, 0x76, 0x88, 0xb6, 0x50, 0x92, 0x7e, 0x18, 0x1e, 0x5a,
-	0xa3, 0x83, 0x0c, 0x2b, 0x43, 0x87, 0x4e, 0x6d, 0x1a, 0x14, 0x19, 0x8a, 0x04, 0x74, 0xd0, 0xa1,
-	0x8b, 0x40, 0x53, 0x8c, 0x43, 0x44, 0x16, 0x15, 0x1e, 0x65, 0xc0, 0x53, 0xff, 0x47, 0xff, 0x5e,
-	0xff, 0x48, 0x20, 0x52, 0x0a, 0x22, 0x07, 0x9e, 0xa4, 0x7b, 0xf7, 0x8e, 0x7c, 0xf7, 0x78, 0x07,
-	0x47, 0x3a, 0xe7, 0x33, 0xe4, 0x2c, 0xcb, 0x84, 0x9e, 0xa1, 0xd0, 0x1b, 0xc9, 0x45, 0x98, 0x6b,
-	0x65, 0x14, 0x19, 0x19, 0x2d, 0x37, 0xdb, 0xb0, 0x4a, 0x86, 0x9b, 0xf9, 0x71, 0x50, 0x92, 0xb9,
-	0x5a, 0xaf, 0x55, 0xd6, 0xe4, 0x4e, 0xfe, 0x79, 0xe0, 0x2f, 0x38, 0xcb, 0xa8, 0xb8, 0x2f, 0x04,
-	0x1a, 0xf2, 0x06, 0xba, 0x86, 0xe9, 0x95, 0x30, 0x81, 0x37, 0xf6, 0xa6, 0x03, 0x5a, 0x45, 0xe4,
-	0x03, 0xf8, 0x4c, 0x1b, 0x79, 0xc3, 0xb8, 0x89, 0x65, 0x12, 0xb4, 0x6c, 0x12, 0x6a, 0xe8, 0x22,
-	0x21, 0x47, 0xd0, 0x5f, 0xa6, 0x6a, 0x19, 0xcb, 0x04, 0x83, 0xf6, 0xb8, 0x3d, 0x1d, 0xd0, 0x5e,
-	0x19, 0x5f, 0x24, 0x48, 0xbe, 0x40, 0x4f, 0xe5, 0x46, 0xaa, 0x0c, 0x83, 0xce, 0xd8, 0x9b, 0xfa,
-	0xd1, 0xbb, 0x70, 0x57, 0x61, 0x58, 0x6a, 0xb8, 0x74, 0x24, 0x5a, 0xb3, 0x27, 0x7f, 0x9d, 0xb6,
-	0x0a, 0x27, 0x27, 0x30, 0xd8, 0x14, 0x69, 0x16, 0x9b, 0x6d, 0x2e, 0x02, 0xcf, 0xde, 0xd1, 0x2f,
-	0x81, 0xeb, 0x6d, 0x2e, 0xc8, 0x27, 0x18, 0xa2, 0xe0, 0x85, 0x96, 0x66, 0x1b, 0xf3, 0x5b, 0xc1,
-	0xef, 0x30, 0x68, 0x59, 0xca, 0xcb, 0x1a, 0x3e, 0xb3, 0x28, 0xf9, 0x0c, 0x87, 0xa9, 0x44, 0x13,
-	0xb3, 0x34, 0x8d, 0x73, 0xc6, 0xef, 0xd8, 0x4a, 0x94, 0x8a, 0xbd, 0x69, 0x9f, 0x0e, 0xcb, 0xc4,
-	0xb7, 0x34, 0xbd, 0xaa, 0xe0, 0x49, 0x02, 0x2f, 0x9c, 0x39, 0x98, 0xab, 0x0c, 0x05, 0x19, 0x43,
-	0x4b, 0xa1, 0x75, 0xc6, 0x8f, 0x46, 0x55, 0x13, 0xce, 0xd6, 0xf0, 0x72, 0x41, 0x5b, 0x0a, 0x49,
-	0x04, 0x3d, 0x2d, 0xb0, 0x48, 0x8d, 0x73, 0xc1, 0x8f, 0x82, 0xe7, 0xbd, 0x52, 0x4b, 0xa0, 0x35,
-	0x71, 0xf2, 0xbf, 0x05, 0x5d, 0x87, 0xed, 0xb5, 0xff, 0x1c, 0x86, 0x65, 0xa7, 0x42, 0xb3, 0xa5,
-	0x4c, 0xa5, 0x91, 0xc2, 0x75, 0xe7, 0x47, 0x27, 0x4d, 0x15, 0xbf, 0x9f, 0x90, 0xb6, 0x74, 0xb7,
-	0x86, 0x5c, 0xc3, 0xe1, 0x5a, 0x22, 0x57, 0xd9, 0x8d, 0x5c, 0x15, 0x9a, 0xd5, 0x6f, 0x52, 0x1e,
-	0xf4, 0xb1, 0x79, 0xd0, 0x0f, 0x61, 0x04, 0x37, 0x22, 0xf9, 0xb5, 0x43, 0xa7, 0xcf, 0x0f, 0x20,
-	0xaf, 0xe0, 0x80, 0xa7, 0x0c, 0x31, 0xe8, 0x5a, 0xcd, 0x2e, 0x20, 0x04, 0x3a, 0xf6, 0xa1, 0xda,
-	0x16, 0xb4, 0xff, 0x64, 0x0e, 0xfd, 0x47, 0xcb, 0x0f, 0xec, 0xb5, 0xaf, 0x9b, 0xd7, 0x56, 0xce,
-	0xd3, 0x47, 0x1a, 0xf9, 0x09, 0x23, 0x5e, 0xa0, 0x51, 0xeb, 0x58, 0x0b, 0x54, 0x85, 0xe6, 0x02,
-	0x83, 0x9e, 0x2d, 0x7d, 0xdb, 0x2c, 0x3d, 0xb3, 0x2c, 0x5a, 0x91, 0xe8, 0x90, 0x37, 0x62, 0x8c,
-	0xae, 0xa0, 0xb7, 0x70, 0x6f, 0x40, 0xce, 0xa1, 0x53, 0xfe, 0x92, 0x3d, 0x73, 0x58, 0xed, 0xc2,
-	0xf1, 0xfb, 0x7d, 0x69, 0x37, 0x0d, 0xdf, 0x4f, 0xff, 0xcc, 0x57, 0xd2, 0xdc, 0x16, 0xcb, 0x52,
-	0xc2, 0x8c, 0xdd, 0x17, 0xac, 0x1e, 0xb5, 0x99, 0x2d, 0x9c, 0x3d, 0x59, 0xd1, 0xaf, 0xd5, 0x77,
-	0xd9, 0xb5, 0x7b, 0x77, 0xfa, 0x10, 0x00, 0x00, 0xff, 0xff, 0xb0, 0x5b, 0x27, 0x32, 0xc0, 0x03,
-	0x00, 0x00,
+	// 537 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x53, 0xcb, 0x6e, 0xdb, 0x30,
+	0x10, 0x84, 0x6c, 0xc7, 0x8f, 0x55, 0x11, 0x3b, 0x44, 0x5b, 0x28, 0x49, 0x1f, 0x86, 0x0f, 0xad,
+	0xd1, 0x83, 0x0c, 0x2b, 0x87, 0x1e, 0x7a, 0x6a, 0xe3, 0xa0, 0xc8, 0xa1, 0x48, 0x40, 0x07, 0x3d,
+	0xf4, 0x22, 0xd0, 0xd4, 0xc6, 0x21, 0x22, 0x8b, 0x0a, 0x49, 0x09, 0xf0, 0xaf, 0xf4, 0x0f, 0xfa,
+	0x5d, 0xfd, 0x91, 0x40, 0x94, 0x14, 0x44, 0x0e, 0x7c, 0x92, 0x76, 0x76, 0x96, 0x1c, 0xce, 0x90,
+	0x70, 0xac, 0x52, 0x3e, 0xd3, 0x9c, 0x25, 0x09, 0xaa, 0x99, 0x46, 0x95, 0x0b, 0x8e, 0x7e, 0xaa,
+	0xa4, 0x91, 0x64, 0x64, 0x94, 0xc8, 0xb7, 0x7e, 0xd5, 0xf4, 0xf3, 0xf9, 0x89, 0x57, 0x90, 0xb9,
+	0xdc, 0x6c, 0x64, 0xd2, 0xe4, 0x4e, 0xfe, 0x3a, 0xe0, 0x2e, 0x39, 0x4b, 0x28, 0x3e, 0x64, 0xa8,
+	0x0d, 0x79, 0x0b, 0x5d, 0xc3, 0xd4, 0x1a, 0x8d, 0xe7, 0x8c, 0x9d, 0xe9, 0x80, 0x56, 0x15, 0xf9,
+	0x08, 0x2e, 0x53, 0x46, 0xdc, 0x32, 0x6e, 0x42, 0x11, 0x79, 0x2d, 0xdb, 0x84, 0x1a, 0xba, 0x8c,
+	0xc8, 0x31, 0xf4, 0x57, 0xb1, 0x5c, 0x85, 0x22, 0xd2, 0x5e, 0x7b, 0xdc, 0x9e, 0x0e, 0x68, 0xaf,
+	0xa8, 0x2f, 0x23, 0x4d, 0xbe, 0x42, 0x4f, 0xa6, 0x46, 0xc8, 0x44, 0x7b, 0x9d, 0xb1, 0x33, 0x75,
+	0x83, 0xf7, 0xfe, 0xae, 0x42, 0xbf, 0xd0, 0x70, 0x55, 0x92, 0x68, 0xcd, 0x9e, 0xfc, 0xab, 0xc4,
+	0x55, 0x0d, 0x72, 0x0a, 0x83, 0x3c, 0x8b, 0x93, 0xd0, 0x6c, 0x53, 0xf4, 0x1c, 0xbb, 0x49, 0xbf,
+	0x00, 0x6e, 0xb6, 0x29, 0x92, 0xcf, 0x30, 0xd4, 0xc8, 0x33, 0x25, 0xcc, 0x36, 0xe4, 0x77, 0xc8,
+	0xef, 0xb5, 0xd7, 0xb2, 0x94, 0xc3, 0x1a, 0x3e, 0xb7, 0x28, 0xf9, 0x02, 0x47, 0xb1, 0xd0, 0x26,
+	0x64, 0x71, 0x1c, 0xa6, 0x8c, 0xdf, 0xb3, 0x35, 0x16, 0x92, 0x9d, 0x69, 0x9f, 0x0e, 0x8b, 0xc6,
+	0xf7, 0x38, 0xbe, 0xae, 0x60, 0x32, 0x85, 0x91, 0x48, 0x78, 0x9c, 0x45, 0x18, 0x46, 0x98, 0x87,
+	0x11, 0xa6, 0xe5, 0x19, 0xfa, 0xf4, 0xb0, 0xc2, 0x17, 0x98, 0x2f, 0x30, 0xd5, 0x93, 0x08, 0x5e,
+	0x95, 0x3e, 0xea, 0x54, 0x26, 0x1a, 0xc9, 0x18, 0x5a, 0x52, 0x5b, 0x13, 0xdd, 0x60, 0x54, 0x9d,
+	0xb7, 0x4c, 0xc0, 0xbf, 0x5a, 0xd2, 0x96, 0xd4, 0x24, 0x80, 0x9e, 0x42, 0x9d, 0xc5, 0xa6, 0x34,
+	0xcc, 0x0d, 0xbc, 0x97, 0xb6, 0x50, 0x4b, 0xa0, 0x35, 0x71, 0xf2, 0xbf, 0x05, 0xdd, 0x12, 0xdb,
+	0x9b, 0xd4, 0x05, 0x0c, 0x0b, 0x4f, 0x50, 0xb1, 0x95, 0x88, 0x85, 0x11, 0x58, 0xfa, 0xe0, 0x06,
+	0xa7, 0x4d, 0x15, 0xbf, 0x9f, 0x91, 0xb6, 0x74, 0x77, 0x86, 0xdc, 0xc0, 0xd1, 0x46, 0x68, 0x2e,
+	0x93, 0x5b, 0xb1, 0xce, 0x14, 0xab, 0xe3, 0x2b, 0x16, 0xfa, 0xd4, 0x5c, 0x68, 0x81, 0x06, 0xb9,
+	0xc1, 0xe8, 0xd7, 0x0e, 0x9d, 0xbe, 0x5c, 0x80, 0xbc, 0x86, 0x03, 0x1e, 0x33, 0xad, 0xbd, 0xae,
+	0xd5, 0x5c, 0x16, 0x84, 0x40, 0xc7, 0x46, 0xda, 0xb6, 0xa0, 0xfd, 0x27, 0x73, 0xe8, 0x3f, 0x85,
+	0x73, 0x60, 0xb7, 0x7d, 0xd3, 0xdc, 0xb6, 0xca, 0x88, 0x3e, 0xd1, 0xc8, 0x4f, 0x18, 0xf1, 0x4c,
+	0x1b, 0xb9, 0x09, 0x15, 0x6a, 0x99, 0x29, 0x8e, 0xda, 0xeb, 0xd9, 0xd1, 0x77, 0xcd, 0xd1, 0x73,
+	0xcb, 0xa2, 0x15, 0x89, 0x0e, 0x79, 0xa3, 0xd6, 0xc1, 0x35, 0xf4, 0x96, 0x65, 0x06, 0xe4, 0x02,
+	0x3a, 0xc5, 0x2f, 0xd9, 0x73, 0x65, 0xab, 0x67, 0x73, 0xf2, 0x61, 0x5f, 0xbb, 0xbc, 0x0d, 0x3f,
+	0xce, 0xfe, 0xcc, 0xd7, 0xc2, 0xdc, 0x65, 0xab, 0x42, 0xc2, 0x8c, 0x3d, 0x64, 0xac, 0xbe, 0x94,
+	0x33, 0x3b, 0x38, 0x7b, 0xf6, 0x9a, 0xbf, 0x55, 0xdf, 0x55, 0xd7, 0x3e, 0xd1, 0xb3, 0xc7, 0x01,
+	0x00, 0x8d, 0x1f, 0xc2, 0x94, 0xeb, 0x03, 0x00, 0x00,
 }